@@ -2,15 +2,27 @@
 package agent
 
 import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/amp"
+	"github.com/cursorworkshop/cursor-gastown/internal/auggie"
+	"github.com/cursorworkshop/cursor-gastown/internal/codex"
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/gemini"
 )
 
 // EnsureSettingsForRole ensures agent settings exist for the given agent preset and role.
 // This is a unified function that delegates to the appropriate agent-specific implementation.
 //
 // For Cursor: Creates .cursor/rules/gastown.mdc with rules and .cursor/hooks.json
-// For other agents: Currently no-op (may be extended in future)
+// For Gemini: Creates GEMINI.md with the same rules and .gemini/settings.json hooks
+// For Codex: Creates AGENTS.md with the same rules and a .codex/config.toml notify program
+// For Amp: Creates AGENTS.md with the same rules (Amp has no hooks)
+// For Auggie: Creates .augment/rules/gastown.md with the same rules (no workspace hooks)
 func EnsureSettingsForRole(workDir, role string, agentName string) error {
 	// If no agent specified, default to cursor
 	if agentName == "" {
@@ -26,19 +38,134 @@ func EnsureSettingsForRole(workDir, role string, agentName string) error {
 	switch preset.Name {
 	case config.AgentCursor:
 		return cursor.EnsureSettingsForRole(workDir, role)
-	case config.AgentGemini, config.AgentCodex, config.AgentAuggie, config.AgentAmp:
-		// These agents don't have a similar settings/rules mechanism yet
-		// They may read AGENTS.md or have their own config
-		return nil
+	case config.AgentGemini:
+		return gemini.EnsureSettingsForRole(workDir, role)
+	case config.AgentCodex:
+		return codex.EnsureSettingsForRole(workDir, role)
+	case config.AgentAmp:
+		return amp.EnsureSettingsForRole(workDir, role)
+	case config.AgentAuggie:
+		return auggie.EnsureSettingsForRole(workDir, role)
 	default:
 		// Unknown preset, use cursor as fallback
 		return cursor.EnsureSettingsForRole(workDir, role)
 	}
 }
 
-// EnsureSettingsForAllAgents ensures settings exist for all supported agents.
-// This is useful during installation to prepare the workspace for any agent.
-func EnsureSettingsForAllAgents(workDir, role string) error {
-	// Ensure Cursor rules and hooks
-	return cursor.EnsureSettingsForRole(workDir, role)
+// GeneratedSettings returns the settings files EnsureSettingsForRole
+// manages for the agent preset in workDir, with the content it would write
+// (see cursor.GeneratedSettings). Unknown agents fall back to cursor.
+func GeneratedSettings(workDir, role, agentName string) ([]cursor.SettingsFile, error) {
+	var name config.AgentPreset
+	if preset := config.GetAgentPresetByName(agentName); preset != nil {
+		name = preset.Name
+	}
+	switch name {
+	case config.AgentGemini:
+		return gemini.GeneratedSettings(workDir, role)
+	case config.AgentCodex:
+		return codex.GeneratedSettings(workDir, role)
+	case config.AgentAmp:
+		return amp.GeneratedSettings(workDir, role)
+	case config.AgentAuggie:
+		return auggie.GeneratedSettings(workDir, role)
+	default:
+		return cursor.GeneratedSettings(workDir, role)
+	}
+}
+
+// PlanSettingsForRole returns what EnsureSettingsForRole would create,
+// update, or remove for the agent preset in workDir, without writing.
+func PlanSettingsForRole(workDir, role, agentName string) (*cursor.SettingsPlan, error) {
+	files, err := GeneratedSettings(workDir, role, agentName)
+	if err != nil {
+		return nil, err
+	}
+	return cursor.NewSettingsPlan(workDir, role, files), nil
+}
+
+// EnsureSettingsForRoleWith is EnsureSettingsForRole returning the plan:
+// the files the sync writes, or with DryRun would write.
+func EnsureSettingsForRoleWith(workDir, role, agentName string, opts cursor.EnsureOptions) (*cursor.SettingsPlan, error) {
+	plan, err := PlanSettingsForRole(workDir, role, agentName)
+	if err != nil || opts.DryRun {
+		return plan, err
+	}
+	return plan, EnsureSettingsForRole(workDir, role, agentName)
+}
+
+// AgentForCommand maps an agent command to the preset whose settings it
+// reads, defaulting to cursor.
+func AgentForCommand(command string) string {
+	switch {
+	case strings.Contains(command, "cursor"):
+		return string(config.AgentCursor)
+	case strings.Contains(command, "gemini"):
+		return string(config.AgentGemini)
+	case strings.Contains(command, "codex"):
+		return string(config.AgentCodex)
+	case strings.Contains(command, "auggie"):
+		return string(config.AgentAuggie)
+	case filepath.Base(command) == "amp":
+		return string(config.AgentAmp)
+	default:
+		return string(config.AgentCursor)
+	}
+}
+
+// EnabledAgents returns the agent presets a town uses: its default agent,
+// its custom agents, and the agents its rigs select, sorted and without
+// duplicates. Custom agents count as the preset their command runs (see
+// AgentForCommand).
+func EnabledAgents(townRoot string) []string {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		settings = config.NewTownSettings()
+	}
+
+	enabled := map[string]bool{}
+	add := func(name string) {
+		if rc := settings.Agents[name]; rc != nil && rc.Command != "" && config.GetAgentPresetByName(name) == nil {
+			name = AgentForCommand(rc.Command)
+		}
+		if preset := config.GetAgentPresetByName(name); preset != nil {
+			enabled[string(preset.Name)] = true
+		}
+	}
+
+	if settings.DefaultAgent != "" {
+		add(settings.DefaultAgent)
+	} else {
+		add(string(config.AgentCursor))
+	}
+	for name := range settings.Agents {
+		add(name)
+	}
+	if rigs, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+		for rigName := range rigs.Rigs {
+			rigSettings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+			if err == nil && rigSettings.Agent != "" {
+				add(rigSettings.Agent)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(enabled))
+	for name := range enabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EnsureSettingsForAllAgents ensures settings exist in workDir for every
+// agent the town has enabled (see EnabledAgents), so the role can run on
+// any of them.
+func EnsureSettingsForAllAgents(townRoot, workDir, role string) error {
+	for _, name := range EnabledAgents(townRoot) {
+		if err := EnsureSettingsForRole(workDir, role, name); err != nil {
+			return fmt.Errorf("%s settings: %w", name, err)
+		}
+	}
+	return nil
 }
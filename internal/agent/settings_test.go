@@ -3,7 +3,10 @@ package agent
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
 )
 
 func TestEnsureSettingsForRole_Cursor(t *testing.T) {
@@ -60,35 +63,163 @@ func TestEnsureSettingsForRole_UnknownAgent(t *testing.T) {
 func TestEnsureSettingsForRole_Gemini(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	// Gemini doesn't have settings yet, should be a no-op
 	err := EnsureSettingsForRole(tmpDir, "polecat", "gemini")
 	if err != nil {
 		t.Fatalf("EnsureSettingsForRole failed: %v", err)
 	}
 
-	// Neither settings should be created for Gemini
+	for _, path := range []string{"GEMINI.md", filepath.Join(".gemini", "settings.json")} {
+		if _, err := os.Stat(filepath.Join(tmpDir, path)); err != nil {
+			t.Errorf("%s not created for Gemini: %v", path, err)
+		}
+	}
+
+	// Cursor settings should not be created for Gemini
 	cursorRules := filepath.Join(tmpDir, ".cursor", "rules", "gastown.mdc")
 	if _, err := os.Stat(cursorRules); !os.IsNotExist(err) {
 		t.Error("Cursor rules should not be created for Gemini")
 	}
 }
 
-func TestEnsureSettingsForAllAgents(t *testing.T) {
+func TestEnsureSettingsForRole_Codex(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	err := EnsureSettingsForAllAgents(tmpDir, "polecat")
-	if err != nil {
+	if err := EnsureSettingsForRole(tmpDir, "crew", "codex"); err != nil {
+		t.Fatalf("EnsureSettingsForRole failed: %v", err)
+	}
+	for _, path := range []string{"AGENTS.md", filepath.Join(".codex", "config.toml")} {
+		if _, err := os.Stat(filepath.Join(tmpDir, path)); err != nil {
+			t.Errorf("%s not created for Codex: %v", path, err)
+		}
+	}
+}
+
+func TestAgentForCommand(t *testing.T) {
+	tests := map[string]string{
+		"cursor-agent":          "cursor",
+		"/usr/local/bin/gemini": "gemini",
+		"codex":                 "codex",
+		"/usr/local/bin/amp":    "amp",
+		"auggie":                "auggie",
+		"aider":                 "cursor",
+	}
+	for command, want := range tests {
+		if got := AgentForCommand(command); got != want {
+			t.Errorf("AgentForCommand(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
+
+func TestEnsureSettingsForRole_AmpAndAuggie(t *testing.T) {
+	tests := map[string]string{
+		"amp":    "AGENTS.md",
+		"auggie": filepath.Join(".augment", "rules", "gastown.md"),
+	}
+	for agentName, file := range tests {
+		tmpDir := t.TempDir()
+		if err := EnsureSettingsForRole(tmpDir, "polecat", agentName); err != nil {
+			t.Fatalf("EnsureSettingsForRole(%s) failed: %v", agentName, err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, file)); err != nil {
+			t.Errorf("%s not created for %s: %v", file, agentName, err)
+		}
+	}
+}
+
+func TestEnabledAgents(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := EnabledAgents(townRoot); len(got) != 1 || got[0] != "cursor" {
+		t.Errorf("EnabledAgents without settings = %v, want [cursor]", got)
+	}
+
+	writeFile(t, filepath.Join(townRoot, "settings", "config.json"), `{
+  "type": "town-settings", "version": 1, "default_agent": "gemini",
+  "agents": {"fast-amp": {"command": "/opt/bin/amp"}}
+}`)
+	writeFile(t, filepath.Join(townRoot, "mayor", "rigs.json"), `{"version": 1, "rigs": {"greenplace": {"git_url": "https://example.com/g.git"}}}`)
+	writeFile(t, filepath.Join(townRoot, "greenplace", "settings", "config.json"), `{"type": "rig-settings", "version": 1, "agent": "auggie"}`)
+
+	got := strings.Join(EnabledAgents(townRoot), ",")
+	if want := "amp,auggie,gemini"; got != want {
+		t.Errorf("EnabledAgents = %s, want %s", got, want)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnsureSettingsForAllAgents(t *testing.T) {
+	townRoot := t.TempDir()
+	writeFile(t, filepath.Join(townRoot, "settings", "config.json"), `{
+  "type": "town-settings", "version": 1,
+  "agents": {"codex": {"command": "codex"}}
+}`)
+	mayorDir := filepath.Join(townRoot, "mayor")
+
+	if err := EnsureSettingsForAllAgents(townRoot, mayorDir, "mayor"); err != nil {
 		t.Fatalf("EnsureSettingsForAllAgents failed: %v", err)
 	}
 
-	// Only Cursor settings should be created (we migrated to Cursor-only)
-	cursorRules := filepath.Join(tmpDir, ".cursor", "rules", "gastown.mdc")
-	if _, err := os.Stat(cursorRules); os.IsNotExist(err) {
-		t.Error("Cursor rules not created")
+	// The default agent (cursor) and the town's codex agent are provisioned
+	for _, path := range []string{
+		filepath.Join(".cursor", "rules", "gastown.mdc"),
+		filepath.Join(".cursor", "hooks.json"),
+		"AGENTS.md",
+		filepath.Join(".codex", "config.toml"),
+	} {
+		if _, err := os.Stat(filepath.Join(mayorDir, path)); err != nil {
+			t.Errorf("%s not created: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(mayorDir, "GEMINI.md")); !os.IsNotExist(err) {
+		t.Error("GEMINI.md should not be created when gemini is not enabled")
 	}
+}
 
-	cursorHooks := filepath.Join(tmpDir, ".cursor", "hooks.json")
-	if _, err := os.Stat(cursorHooks); os.IsNotExist(err) {
-		t.Error("Cursor hooks.json not created")
+func TestEnsureSettingsForRoleWith_DryRun(t *testing.T) {
+	for _, name := range []string{"cursor", "gemini", "codex", "amp", "auggie"} {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			plan, err := EnsureSettingsForRoleWith(tmpDir, "crew", name, cursor.EnsureOptions{DryRun: true})
+			if err != nil {
+				t.Fatalf("dry run: %v", err)
+			}
+			if plan.Empty() {
+				t.Fatal("dry run planned nothing for an empty workspace")
+			}
+			for _, f := range plan.Files {
+				if f.Action() != "create" || !strings.Contains(plan.Diff(3), "+++ ") {
+					t.Errorf("%s: action %s, want create with a diff", f.Path, f.Action())
+				}
+			}
+			if entries, _ := os.ReadDir(tmpDir); len(entries) != 0 {
+				t.Fatalf("dry run wrote %d entries", len(entries))
+			}
+
+			applied, err := EnsureSettingsForRoleWith(tmpDir, "crew", name, cursor.EnsureOptions{})
+			if err != nil {
+				t.Fatalf("sync: %v", err)
+			}
+			if len(applied.Files) != len(plan.Files) {
+				t.Errorf("sync wrote %d files, dry run planned %d", len(applied.Files), len(plan.Files))
+			}
+			for _, f := range applied.Files {
+				if _, err := os.Stat(f.Path); err != nil {
+					t.Errorf("planned file not written: %v", err)
+				}
+			}
+
+			if plan, err := PlanSettingsForRole(tmpDir, "crew", name); err != nil || !plan.Empty() {
+				t.Errorf("after sync: plan = %+v, err = %v; want nothing to do", plan, err)
+			}
+		})
 	}
 }
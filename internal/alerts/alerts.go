@@ -0,0 +1,235 @@
+// Package alerts tracks the lifecycle of metric alerts (cost budgets, anomalies)
+// so that a condition fires once, stays quiet while it persists, and only fires
+// again after the metric recovers or crosses a higher threshold.
+//
+// Alert state is stored in <town>/.runtime/alerts.json.
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// StateFile is the alert state file name within the town .runtime directory.
+const StateFile = "alerts.json"
+
+// ErrNotFound is returned when acknowledging an unknown alert.
+var ErrNotFound = errors.New("alert not found")
+
+// Status is the lifecycle state of an alert.
+type Status string
+
+const (
+	// StatusFiring means the alert is active and has not been acknowledged.
+	StatusFiring Status = "firing"
+	// StatusAcknowledged means an operator has seen the active alert.
+	StatusAcknowledged Status = "acknowledged"
+	// StatusResolved means the metric recovered below the hysteresis band.
+	StatusResolved Status = "resolved"
+)
+
+// Alert is the tracked state of a single alerting metric.
+type Alert struct {
+	ID         string    `json:"id"`
+	Summary    string    `json:"summary"`
+	Status     Status    `json:"status"`
+	Level      int       `json:"level"`     // index of the highest threshold crossed
+	Threshold  float64   `json:"threshold"` // absolute value of that threshold
+	Value      float64   `json:"value"`     // most recently observed value
+	FiredAt    time.Time `json:"fired_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	AckedAt    time.Time `json:"acked_at,omitempty"`
+	AckedBy    string    `json:"acked_by,omitempty"`
+	ResolvedAt time.Time `json:"resolved_at,omitempty"`
+	FireCount  int       `json:"fire_count"`
+}
+
+// Active returns true if the alert is firing or acknowledged.
+func (a *Alert) Active() bool {
+	return a.Status == StatusFiring || a.Status == StatusAcknowledged
+}
+
+// State is the persisted set of alerts.
+type State struct {
+	Alerts map[string]*Alert `json:"alerts"`
+}
+
+// StatePath returns the alert state file path for a town.
+func StatePath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), StateFile)
+}
+
+// Load reads alert state for a town. A missing file yields empty state.
+func Load(townRoot string) (*State, error) {
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{Alerts: make(map[string]*Alert)}, nil
+		}
+		return nil, fmt.Errorf("reading alert state: %w", err)
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing alert state: %w", err)
+	}
+	if s.Alerts == nil {
+		s.Alerts = make(map[string]*Alert)
+	}
+	return &s, nil
+}
+
+// Save writes alert state for a town.
+func (s *State) Save(townRoot string) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	if err := util.AtomicWriteJSON(path, s); err != nil {
+		return fmt.Errorf("writing alert state: %w", err)
+	}
+	return nil
+}
+
+// Observe records a new value for the metric identified by id and reports
+// whether an alert should be emitted.
+//
+// levels are absolute thresholds in ascending order. margin is the hysteresis
+// band as a fraction of the active threshold: an active alert clears only
+// when value drops below threshold*(1-margin). While active, the alert is
+// suppressed unless a higher threshold is crossed.
+func (s *State) Observe(id, summary string, value float64, levels []float64, margin float64, now time.Time) (*Alert, bool) {
+	crossed := highestCrossed(value, levels)
+	a := s.Alerts[id]
+
+	if a != nil && a.Active() {
+		a.Value = value
+		a.UpdatedAt = now
+		a.Summary = summary
+
+		// The thresholds may have been shortened or removed since the alert
+		// fired; with none left, it resolves below.
+		if a.Level > len(levels)-1 {
+			a.Level = len(levels) - 1
+		}
+
+		if crossed > a.Level {
+			// Escalation to a higher threshold re-fires even if acknowledged.
+			a.fire(crossed, levels[crossed], now)
+			return a, true
+		}
+
+		// Step down through thresholds whose recovery band has been cleared.
+		for a.Level >= 0 && value < levels[a.Level]*(1-margin) {
+			a.Level--
+		}
+		if a.Level < 0 {
+			a.Status = StatusResolved
+			a.ResolvedAt = now
+			a.Threshold = 0
+		} else {
+			a.Threshold = levels[a.Level]
+		}
+		return a, false
+	}
+
+	if crossed < 0 {
+		if a != nil {
+			a.Value = value
+			a.UpdatedAt = now
+		}
+		return a, false
+	}
+
+	if a == nil {
+		a = &Alert{ID: id}
+		s.Alerts[id] = a
+	}
+	a.Summary = summary
+	a.Value = value
+	a.UpdatedAt = now
+	a.fire(crossed, levels[crossed], now)
+	return a, true
+}
+
+// ResolveUnobserved resolves the active alerts whose ID starts with prefix
+// but is not in observed: their metric no longer exists (e.g. the cost of a
+// session that has ended), so Observe will never clear them. Returns the
+// alerts resolved.
+func (s *State) ResolveUnobserved(prefix string, observed map[string]bool, now time.Time) []*Alert {
+	var resolved []*Alert
+	for id, a := range s.Alerts {
+		if !strings.HasPrefix(id, prefix) || observed[id] || !a.Active() {
+			continue
+		}
+		a.Status = StatusResolved
+		a.ResolvedAt = now
+		a.UpdatedAt = now
+		a.Threshold = 0
+		resolved = append(resolved, a)
+	}
+	return resolved
+}
+
+// fire transitions the alert to firing at the given level.
+func (a *Alert) fire(level int, threshold float64, now time.Time) {
+	a.Status = StatusFiring
+	a.Level = level
+	a.Threshold = threshold
+	a.FiredAt = now
+	a.AckedAt = time.Time{}
+	a.AckedBy = ""
+	a.ResolvedAt = time.Time{}
+	a.FireCount++
+}
+
+// Ack acknowledges an active alert. Acknowledged alerts stay suppressed until
+// the metric recovers or crosses a higher threshold.
+func (s *State) Ack(id, by string, now time.Time) (*Alert, error) {
+	a, ok := s.Alerts[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if !a.Active() {
+		return nil, fmt.Errorf("alert %s is not active (%s)", id, a.Status)
+	}
+	a.Status = StatusAcknowledged
+	a.AckedAt = now
+	a.AckedBy = by
+	return a, nil
+}
+
+// List returns all alerts sorted with active alerts first, then by ID.
+func (s *State) List() []*Alert {
+	list := make([]*Alert, 0, len(s.Alerts))
+	for _, a := range s.Alerts {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Active() != list[j].Active() {
+			return list[i].Active()
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// highestCrossed returns the index of the highest level value meets, or -1.
+func highestCrossed(value float64, levels []float64) int {
+	crossed := -1
+	for i, l := range levels {
+		if value >= l {
+			crossed = i
+		}
+	}
+	return crossed
+}
@@ -0,0 +1,155 @@
+package alerts
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestObserveHysteresis(t *testing.T) {
+	s := &State{Alerts: make(map[string]*Alert)}
+	levels := []float64{80, 100}
+	now := time.Now()
+
+	steps := []struct {
+		value    float64
+		wantFire bool
+		want     Status
+	}{
+		{50, false, ""},             // below all thresholds
+		{85, true, StatusFiring},    // crosses 80
+		{90, false, StatusFiring},   // still above 80, suppressed
+		{75, false, StatusFiring},   // inside recovery band (72..80), stays active
+		{105, true, StatusFiring},   // crosses 100, escalates
+		{95, false, StatusFiring},   // inside band for 100 (90..100)
+		{85, false, StatusFiring},   // stepped down to level 0, no re-fire
+		{70, false, StatusResolved}, // below 72, recovers
+		{82, true, StatusFiring},    // fires again after recovery
+	}
+
+	for i, step := range steps {
+		a, fired := s.Observe("budget-daily", "daily budget", step.value, levels, 0.1, now)
+		if fired != step.wantFire {
+			t.Fatalf("step %d (value %.0f): fired = %v, want %v", i, step.value, fired, step.wantFire)
+		}
+		if step.want == "" {
+			if a != nil {
+				t.Fatalf("step %d: expected no alert, got %+v", i, a)
+			}
+			continue
+		}
+		if a.Status != step.want {
+			t.Fatalf("step %d (value %.0f): status = %s, want %s", i, step.value, a.Status, step.want)
+		}
+	}
+
+	if got := s.Alerts["budget-daily"].FireCount; got != 3 {
+		t.Errorf("FireCount = %d, want 3", got)
+	}
+}
+
+func TestObserveShrinkingThresholds(t *testing.T) {
+	s := &State{Alerts: make(map[string]*Alert)}
+	now := time.Now()
+
+	if _, fired := s.Observe("budget-daily", "daily budget", 105, []float64{80, 100}, 0.1, now); !fired {
+		t.Fatal("expected alert to fire at level 1")
+	}
+
+	// Thresholds shortened while the alert is active: no panic, no re-fire.
+	a, fired := s.Observe("budget-daily", "daily budget", 105, []float64{100}, 0.1, now)
+	if fired || a.Status != StatusFiring || a.Level != 0 || a.Threshold != 100 {
+		t.Fatalf("after shortening: fired %v, %+v; want level 0 at 100, still firing", fired, a)
+	}
+
+	// Thresholds removed: the alert resolves.
+	a, fired = s.Observe("budget-daily", "daily budget", 105, nil, 0.1, now)
+	if fired || a.Status != StatusResolved {
+		t.Fatalf("after removal: fired %v, status %s; want resolved", fired, a.Status)
+	}
+}
+
+func TestAckSuppressesUntilHigherThreshold(t *testing.T) {
+	s := &State{Alerts: make(map[string]*Alert)}
+	levels := []float64{80, 100}
+	now := time.Now()
+
+	s.Observe("budget-weekly", "weekly budget", 85, levels, 0.1, now)
+	a, err := s.Ack("budget-weekly", "overseer", now)
+	if err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if a.Status != StatusAcknowledged || a.AckedBy != "overseer" {
+		t.Fatalf("unexpected ack state: %+v", a)
+	}
+
+	if _, fired := s.Observe("budget-weekly", "weekly budget", 95, levels, 0.1, now); fired {
+		t.Error("acknowledged alert re-fired without crossing a new threshold")
+	}
+	if s.Alerts["budget-weekly"].Status != StatusAcknowledged {
+		t.Error("acknowledgment lost while metric stayed elevated")
+	}
+
+	a, fired := s.Observe("budget-weekly", "weekly budget", 101, levels, 0.1, now)
+	if !fired || a.Status != StatusFiring {
+		t.Errorf("expected escalation to fire, got fired=%v status=%s", fired, a.Status)
+	}
+}
+
+func TestAckErrors(t *testing.T) {
+	s := &State{Alerts: make(map[string]*Alert)}
+	if _, err := s.Ack("missing", "me", time.Now()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Ack(missing) error = %v, want ErrNotFound", err)
+	}
+
+	s.Observe("x", "x", 10, []float64{5}, 0.1, time.Now())
+	s.Observe("x", "x", 1, []float64{5}, 0.1, time.Now())
+	if _, err := s.Ack("x", "me", time.Now()); err == nil {
+		t.Error("expected error acknowledging resolved alert")
+	}
+}
+
+func TestLoadSaveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load empty: %v", err)
+	}
+	s.Observe("budget-daily", "daily", 12, []float64{10}, 0.1, time.Now())
+	if err := s.Save(dir); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	a := loaded.Alerts["budget-daily"]
+	if a == nil || a.Status != StatusFiring || a.Threshold != 10 {
+		t.Errorf("round trip mismatch: %+v", a)
+	}
+}
+
+func TestResolveUnobserved(t *testing.T) {
+	s := &State{Alerts: make(map[string]*Alert)}
+	now := time.Now()
+	s.Observe("session-gastown-toast", "toast", 12, []float64{10}, 0.1, now)
+	s.Observe("session-gastown-nux", "nux", 12, []float64{10}, 0.1, now)
+	s.Observe("budget-daily", "daily", 12, []float64{10}, 0.1, now)
+	if _, err := s.Ack("session-gastown-nux", "me", now); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only toast's session is still running.
+	resolved := s.ResolveUnobserved("session-", map[string]bool{"session-gastown-toast": true}, now)
+	if len(resolved) != 1 || resolved[0].ID != "session-gastown-nux" {
+		t.Fatalf("resolved = %v, want the ended session's alert", resolved)
+	}
+	if s.Alerts["session-gastown-nux"].Status != StatusResolved {
+		t.Error("ended session's acknowledged alert not resolved")
+	}
+	if !s.Alerts["session-gastown-toast"].Active() || !s.Alerts["budget-daily"].Active() {
+		t.Error("observed or unrelated alerts were resolved")
+	}
+}
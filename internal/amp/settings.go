@@ -0,0 +1,59 @@
+// Package amp provides Amp configuration management: the AGENTS.md
+// instructions equivalent to the Cursor rules.
+package amp
+
+import (
+	"fmt"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+)
+
+// InstructionsFile is the instructions file Amp loads from the workspace
+// and its parents.
+const InstructionsFile = "AGENTS.md"
+
+// EnsureSettingsForRole installs Gas Town settings for Amp in workDir:
+// AGENTS.md with the rules composed for role (see cursor.ComposeRules).
+// Amp has no hooks, so AGENTS.md tells the agent to prime itself and check
+// mail. An AGENTS.md the user wrote or edited is kept.
+func EnsureSettingsForRole(workDir, role string) error {
+	return cursor.EnsureInstructionsFile(workDir, role, InstructionsFile)
+}
+
+// GeneratedSettings returns the Amp settings files in workDir for role with
+// the content EnsureSettingsForRole would write: AGENTS.md, kept if edited.
+func GeneratedSettings(workDir, role string) ([]cursor.SettingsFile, error) {
+	instructions, err := cursor.InstructionsSettingsFile(workDir, role, InstructionsFile)
+	if err != nil {
+		return nil, err
+	}
+	return []cursor.SettingsFile{instructions}, nil
+}
+
+// CheckSettings returns what differs between the Amp settings installed in
+// workDir and those generated for role: a missing or outdated AGENTS.md.
+// AGENTS.md edited by the user is not reported.
+func CheckSettings(workDir, role string) ([]string, error) {
+	status, err := cursor.InstructionsStatus(workDir, role, InstructionsFile)
+	if err != nil {
+		return nil, err
+	}
+	if status == cursor.FileMissing || status == cursor.FileOutdated {
+		return []string{fmt.Sprintf("%s (%s)", InstructionsFile, status)}, nil
+	}
+	return nil, nil
+}
+
+// RenderTemplates renders the Amp config for role with vars, without
+// reading a workspace or town: AGENTS.md from the base and role rules. Used
+// to test templates against synthetic workspaces (gt templates selftest).
+func RenderTemplates(vars templates.ConfigVars, role string) ([]cursor.RenderedFile, error) {
+	rules, err := cursor.ComposeRulesWith(vars, role, nil)
+	if err != nil {
+		return nil, err
+	}
+	return []cursor.RenderedFile{
+		{Name: InstructionsFile, Content: cursor.Instructions(rules)},
+	}, nil
+}
@@ -0,0 +1,28 @@
+package amp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnsureSettingsForRole(t *testing.T) {
+	dir := t.TempDir()
+	if problems, _ := CheckSettings(dir, "crew"); len(problems) != 1 {
+		t.Errorf("CheckSettings before install = %v, want AGENTS.md missing", problems)
+	}
+	if err := EnsureSettingsForRole(dir, "crew"); err != nil {
+		t.Fatalf("EnsureSettingsForRole: %v", err)
+	}
+	instructions, err := os.ReadFile(filepath.Join(dir, InstructionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(instructions), "gt prime") {
+		t.Error("AGENTS.md should tell the agent to prime itself")
+	}
+	if problems, err := CheckSettings(dir, "crew"); err != nil || len(problems) > 0 {
+		t.Errorf("CheckSettings after install = %v, %v", problems, err)
+	}
+}
@@ -0,0 +1,110 @@
+// Package auggie provides Auggie CLI configuration management: an
+// always-applied Augment rule equivalent to the Cursor rules.
+package auggie
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/settingsbackup"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+)
+
+// RulesFile is the Augment rule gt installs, relative to the workspace.
+// Auggie loads rules from .augment/rules/ in the workspace and its parents.
+const RulesFile = ".augment/rules/gastown.md"
+
+// ruleFrontmatter makes Auggie apply the rule to every request.
+const ruleFrontmatter = "type: \"always_apply\"\ndescription: \"Gas Town role instructions\"\n"
+
+// rules returns the rule content generated for composed rules.
+func rules(composed []cursor.RuleFile) []byte {
+	return cursor.InstructionsWithFrontmatter(composed, ruleFrontmatter)
+}
+
+// EnsureSettingsForRole installs Gas Town settings for Auggie in workDir:
+// .augment/rules/gastown.md with the rules composed for role (see
+// cursor.ComposeRules). Auggie has no workspace hooks, so the rule tells the
+// agent to prime itself and check mail. A rule the user edited is kept.
+func EnsureSettingsForRole(workDir, role string) error {
+	// Create workDir first: whether it exists decides the town it belongs to
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return err
+	}
+	composed, err := cursor.ComposeRules(workDir, role)
+	if err != nil {
+		return err
+	}
+	content := rules(composed)
+	path := filepath.Join(workDir, RulesFile)
+	installed, err := os.ReadFile(path) //nolint:gosec // G304: path is within the agent workspace
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", RulesFile, err)
+	}
+	if err == nil && cursor.GeneratedStatus(installed, content) != cursor.FileOutdated {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating rules directory: %w", err)
+	}
+	if err := settingsbackup.WriteFile(path, content, 0644); err != nil { //nolint:gosec // G306: rules are not sensitive
+		return fmt.Errorf("writing %s: %w", RulesFile, err)
+	}
+	return nil
+}
+
+// GeneratedSettings returns the Auggie settings files in workDir for role
+// with the content EnsureSettingsForRole would write: the rule, kept if
+// edited.
+func GeneratedSettings(workDir, role string) ([]cursor.SettingsFile, error) {
+	composed, err := cursor.ComposeRules(workDir, role)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(workDir, RulesFile)
+	installed, err := os.ReadFile(path) //nolint:gosec // G304: path is within the agent workspace
+	if os.IsNotExist(err) {
+		installed, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", RulesFile, err)
+	}
+	generated := rules(composed)
+	if installed != nil && cursor.GeneratedStatus(installed, generated) != cursor.FileOutdated {
+		generated = installed
+	}
+	return []cursor.SettingsFile{{Path: path, Installed: installed, Generated: generated}}, nil
+}
+
+// CheckSettings returns what differs between the Auggie settings installed
+// in workDir and those generated for role: a missing or outdated rule. A
+// rule edited by the user is not reported.
+func CheckSettings(workDir, role string) ([]string, error) {
+	composed, err := cursor.ComposeRules(workDir, role)
+	if err != nil {
+		return nil, err
+	}
+	installed, err := os.ReadFile(filepath.Join(workDir, RulesFile)) //nolint:gosec // G304: path is within the agent workspace
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if status := cursor.GeneratedStatus(installed, rules(composed)); status == cursor.FileMissing || status == cursor.FileOutdated {
+		return []string{fmt.Sprintf("%s (%s)", RulesFile, status)}, nil
+	}
+	return nil, nil
+}
+
+// RenderTemplates renders the Auggie config for role with vars, without
+// reading a workspace or town: the rule from the base and role rules. Used
+// to test templates against synthetic workspaces (gt templates selftest).
+func RenderTemplates(vars templates.ConfigVars, role string) ([]cursor.RenderedFile, error) {
+	composed, err := cursor.ComposeRulesWith(vars, role, nil)
+	if err != nil {
+		return nil, err
+	}
+	return []cursor.RenderedFile{
+		{Name: RulesFile, Content: rules(composed)},
+	}, nil
+}
@@ -0,0 +1,51 @@
+package auggie
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnsureSettingsForRole(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureSettingsForRole(dir, "polecat"); err != nil {
+		t.Fatalf("EnsureSettingsForRole: %v", err)
+	}
+
+	path := filepath.Join(dir, RulesFile)
+	rule, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(rule), "---\ntype: \"always_apply\"\n") {
+		t.Errorf("rule should start with always_apply frontmatter:\n%s", rule)
+	}
+	if !strings.Contains(string(rule), "gt prime") {
+		t.Error("rule should tell the agent to prime itself")
+	}
+	if problems, err := CheckSettings(dir, "polecat"); err != nil || len(problems) > 0 {
+		t.Errorf("CheckSettings after install = %v, %v", problems, err)
+	}
+
+	edited := append(rule, []byte("\nAlways run the linters.\n")...)
+	if err := os.WriteFile(path, edited, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureSettingsForRole(dir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != string(edited) {
+		t.Error("an edited rule should be kept")
+	}
+	if problems, _ := CheckSettings(dir, "polecat"); len(problems) > 0 {
+		t.Errorf("CheckSettings reported an edited rule: %v", problems)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if problems, _ := CheckSettings(dir, "polecat"); len(problems) != 1 {
+		t.Errorf("CheckSettings with the rule missing = %v, want one problem", problems)
+	}
+}
@@ -0,0 +1,105 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+// AnthropicEndpoint is the Anthropic API base URL.
+const AnthropicEndpoint = "https://api.anthropic.com"
+
+// Anthropic is a Reconciler backed by the Anthropic Admin API cost report.
+type Anthropic struct {
+	apiKey string
+
+	// Endpoint is the API base URL (overridden in tests).
+	Endpoint string
+	client   *http.Client
+}
+
+// NewAnthropic creates an Anthropic reconciler with a resolved admin key.
+func NewAnthropic(cfg config.BillingProviderConfig, apiKey string) *Anthropic {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = AnthropicEndpoint
+	}
+	return &Anthropic{
+		apiKey:   apiKey,
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns "anthropic".
+func (a *Anthropic) Name() string { return config.BillingAnthropic }
+
+type anthropicCostReport struct {
+	Data []struct {
+		StartingAt time.Time `json:"starting_at"`
+		Results    []struct {
+			Amount   string  `json:"amount"` // Decimal string, in cents
+			Currency string  `json:"currency"`
+			Model    *string `json:"model"`
+		} `json:"results"`
+	} `json:"data"`
+	HasMore  bool    `json:"has_more"`
+	NextPage *string `json:"next_page"`
+}
+
+// Usage returns the daily token cost per model from the cost report.
+func (a *Anthropic) Usage(ctx context.Context, start, end time.Time) ([]Usage, error) {
+	totals := make(map[string]*Usage)
+	var order []string
+	page := ""
+	for {
+		q := url.Values{}
+		q.Set("starting_at", start.UTC().Format(time.RFC3339))
+		q.Set("ending_at", end.UTC().Format(time.RFC3339))
+		q.Set("bucket_width", "1d")
+		q.Add("group_by[]", "description")
+		if page != "" {
+			q.Set("page", page)
+		}
+		data, err := get(ctx, a.client, a.Name(), a.Endpoint+"/v1/organizations/cost_report?"+q.Encode(), map[string]string{
+			"x-api-key":         a.apiKey,
+			"anthropic-version": "2023-06-01",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var report anthropicCostReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("decoding anthropic cost report: %w", err)
+		}
+		for _, bucket := range report.Data {
+			day := bucket.StartingAt.UTC().Format(DayFormat)
+			for _, r := range bucket.Results {
+				if r.Model == nil || *r.Model == "" {
+					continue
+				}
+				if r.Currency != "" && !strings.EqualFold(r.Currency, "USD") {
+					return nil, fmt.Errorf("anthropic cost report is in %s, only USD is supported", r.Currency)
+				}
+				cents, err := strconv.ParseFloat(r.Amount, 64)
+				if err != nil {
+					return nil, fmt.Errorf("anthropic cost report: invalid amount %q", r.Amount)
+				}
+				addUsage(totals, &order, Usage{Day: day, Model: NormalizeModel(*r.Model), CostUSD: cents / 100, Provider: a.Name()})
+			}
+		}
+		if !report.HasMore || report.NextPage == nil || *report.NextPage == "" {
+			break
+		}
+		page = *report.NextPage
+	}
+	return collect(totals, order), nil
+}
@@ -0,0 +1,145 @@
+// Package billing pulls the usage providers actually billed, so the cost
+// ledger recorded by session hooks can be reconciled against it.
+//
+// A town opts in with a "cost_reconcile" block in settings/config.json
+// listing provider billing APIs and their admin keys. Only per-model token
+// costs are reported; provider charges not tied to a model (web search,
+// code execution, fine-tuning) are skipped, since the ledger never records
+// them.
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/secrets"
+)
+
+// ErrNotConfigured is returned when a town has no billing providers configured.
+var ErrNotConfigured = errors.New("no billing providers configured")
+
+// DayFormat is the layout of Usage.Day.
+const DayFormat = "2006-01-02"
+
+// Usage is the cost a provider billed for one model on one UTC day.
+type Usage struct {
+	Day      string  `json:"day"` // UTC, "2006-01-02"
+	Model    string  `json:"model"`
+	CostUSD  float64 `json:"cost_usd"`
+	Provider string  `json:"provider"`
+}
+
+// Reconciler pulls billed usage from a provider.
+type Reconciler interface {
+	// Name is the provider type, e.g. "anthropic".
+	Name() string
+
+	// Usage returns the billed cost per UTC day and model for days
+	// starting in [start, end). Models are normalized with NormalizeModel.
+	Usage(ctx context.Context, start, end time.Time) ([]Usage, error)
+}
+
+// New returns the reconciler for a provider config, resolving its API key.
+func New(cfg config.BillingProviderConfig) (Reconciler, error) {
+	apiKey, err := secrets.Resolve(cfg.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s api_key: %w", cfg.Type, err)
+	}
+	switch cfg.Type {
+	case config.BillingAnthropic:
+		return NewAnthropic(cfg, apiKey), nil
+	case config.BillingOpenAI:
+		return NewOpenAI(cfg, apiKey), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %q", config.ErrInvalidCostReconcile, cfg.Type)
+	}
+}
+
+// ForTown returns the town's reconcile config and a reconciler for each of
+// its providers, or ErrNotConfigured if it has none.
+func ForTown(townRoot string) (*config.CostReconcileConfig, []Reconciler, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg := settings.CostReconcile
+	if cfg == nil || len(cfg.Providers) == 0 {
+		return nil, nil, ErrNotConfigured
+	}
+	reconcilers := make([]Reconciler, 0, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		r, err := New(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		reconcilers = append(reconcilers, r)
+	}
+	return cfg, reconcilers, nil
+}
+
+// modelDateSuffix matches the snapshot date providers append to model IDs,
+// e.g. "-20250514" or "-2024-08-06".
+var modelDateSuffix = regexp.MustCompile(`-(\d{8}|\d{4}-\d{2}-\d{2})$`)
+
+// NormalizeModel maps a model name to the form ledger and billing rows are
+// matched on: lowercased, without a snapshot date suffix.
+func NormalizeModel(model string) string {
+	model = strings.ToLower(strings.TrimSpace(model))
+	return modelDateSuffix.ReplaceAllString(model, "")
+}
+
+// get fetches url with headers and returns the body of a 200 response.
+func get(ctx context.Context, client *http.Client, provider, url string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request: %w", provider, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s response: %w", provider, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return data, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%s rejected the API key (cost reports need an organization admin key)", provider)
+	default:
+		return nil, fmt.Errorf("%s: %s", provider, resp.Status)
+	}
+}
+
+// addUsage adds cost to the day/model total in totals, preserving first-seen order.
+func addUsage(totals map[string]*Usage, order *[]string, u Usage) {
+	key := u.Day + "\x00" + u.Model
+	if t, ok := totals[key]; ok {
+		t.CostUSD += u.CostUSD
+		return
+	}
+	totals[key] = &u
+	*order = append(*order, key)
+}
+
+// collect flattens totals in order.
+func collect(totals map[string]*Usage, order []string) []Usage {
+	out := make([]Usage, 0, len(order))
+	for _, key := range order {
+		out = append(out, *totals[key])
+	}
+	return out
+}
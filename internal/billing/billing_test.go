@@ -0,0 +1,106 @@
+package billing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+func TestNormalizeModel(t *testing.T) {
+	tests := map[string]string{
+		"claude-sonnet-4-20250514": "claude-sonnet-4",
+		"gpt-4o-2024-08-06":        "gpt-4o",
+		" GPT-4o ":                 "gpt-4o",
+		"o3":                       "o3",
+		"claude-3-5-haiku-latest":  "claude-3-5-haiku-latest",
+	}
+	for in, want := range tests {
+		if got := NormalizeModel(in); got != want {
+			t.Errorf("NormalizeModel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAnthropicUsagePaginates(t *testing.T) {
+	pages := []string{
+		`{"data":[{"starting_at":"2026-10-01T00:00:00Z","ending_at":"2026-10-02T00:00:00Z","results":[
+			{"currency":"USD","amount":"123.5","model":"claude-sonnet-4-20250514","cost_type":"tokens"},
+			{"currency":"USD","amount":"76.5","model":"claude-sonnet-4-20250514","cost_type":"tokens"},
+			{"currency":"USD","amount":"40","model":null,"cost_type":"web_search"}]}],
+		 "has_more":true,"next_page":"p2"}`,
+		`{"data":[{"starting_at":"2026-10-02T00:00:00Z","ending_at":"2026-10-03T00:00:00Z","results":[
+			{"currency":"USD","amount":"50","model":"claude-opus-4-20250514","cost_type":"tokens"}]}],
+		 "has_more":false,"next_page":null}`,
+	}
+	var gotPages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "sk-ant-admin-test" || r.Header.Get("anthropic-version") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v1/organizations/cost_report" || r.URL.Query().Get("bucket_width") != "1d" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		gotPages = append(gotPages, r.URL.Query().Get("page"))
+		_, _ = w.Write([]byte(pages[len(gotPages)-1]))
+	}))
+	defer srv.Close()
+
+	a := NewAnthropic(config.BillingProviderConfig{Type: config.BillingAnthropic}, "sk-ant-admin-test")
+	a.Endpoint = srv.URL
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	got, err := a.Usage(context.Background(), start, start.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	want := []Usage{
+		{Day: "2026-10-01", Model: "claude-sonnet-4", CostUSD: 2, Provider: "anthropic"},
+		{Day: "2026-10-02", Model: "claude-opus-4", CostUSD: 0.5, Provider: "anthropic"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Usage = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(gotPages, []string{"", "p2"}) {
+		t.Errorf("pages requested = %q, want first page then p2", gotPages)
+	}
+}
+
+func TestOpenAIUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk-admin-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v1/organization/costs" || r.URL.Query().Get("group_by") != "line_item" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"object":"page","data":[{"object":"bucket","start_time":1790812800,"end_time":1790899200,"results":[
+			{"object":"organization.costs.result","amount":{"value":1.25,"currency":"usd"},"line_item":"gpt-4o-2024-08-06, input"},
+			{"object":"organization.costs.result","amount":{"value":0.75,"currency":"usd"},"line_item":"gpt-4o-2024-08-06, output"},
+			{"object":"organization.costs.result","amount":{"value":3,"currency":"usd"},"line_item":null}]}],
+			"has_more":false,"next_page":null}`))
+	}))
+	defer srv.Close()
+
+	o := NewOpenAI(config.BillingProviderConfig{Type: config.BillingOpenAI}, "sk-admin-test")
+	o.Endpoint = srv.URL
+	start := time.Unix(1790812800, 0)
+	got, err := o.Usage(context.Background(), start, start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	want := []Usage{{Day: "2026-10-01", Model: "gpt-4o", CostUSD: 2, Provider: "openai"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Usage = %+v, want %+v", got, want)
+	}
+
+	o.apiKey = "wrong"
+	if _, err := o.Usage(context.Background(), start, start.AddDate(0, 0, 1)); err == nil {
+		t.Error("Usage with a rejected key succeeded")
+	}
+}
@@ -0,0 +1,107 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+// OpenAIEndpoint is the OpenAI API base URL.
+const OpenAIEndpoint = "https://api.openai.com"
+
+// OpenAI is a Reconciler backed by the OpenAI organization costs API.
+type OpenAI struct {
+	apiKey string
+
+	// Endpoint is the API base URL (overridden in tests).
+	Endpoint string
+	client   *http.Client
+}
+
+// NewOpenAI creates an OpenAI reconciler with a resolved admin key.
+func NewOpenAI(cfg config.BillingProviderConfig, apiKey string) *OpenAI {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = OpenAIEndpoint
+	}
+	return &OpenAI{
+		apiKey:   apiKey,
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns "openai".
+func (o *OpenAI) Name() string { return config.BillingOpenAI }
+
+type openAICostPage struct {
+	Data []struct {
+		StartTime int64 `json:"start_time"`
+		Results   []struct {
+			Amount struct {
+				Value    float64 `json:"value"`
+				Currency string  `json:"currency"`
+			} `json:"amount"`
+			LineItem *string `json:"line_item"`
+		} `json:"results"`
+	} `json:"data"`
+	HasMore  bool    `json:"has_more"`
+	NextPage *string `json:"next_page"`
+}
+
+// Usage returns the daily cost per model from the costs API. Line items
+// such as "gpt-4o-2024-08-06, input" are summed per model.
+func (o *OpenAI) Usage(ctx context.Context, start, end time.Time) ([]Usage, error) {
+	totals := make(map[string]*Usage)
+	var order []string
+	page := ""
+	for {
+		q := url.Values{}
+		q.Set("start_time", strconv.FormatInt(start.Unix(), 10))
+		q.Set("end_time", strconv.FormatInt(end.Unix(), 10))
+		q.Set("bucket_width", "1d")
+		q.Set("group_by", "line_item")
+		if page != "" {
+			q.Set("page", page)
+		}
+		data, err := get(ctx, o.client, o.Name(), o.Endpoint+"/v1/organization/costs?"+q.Encode(), map[string]string{
+			"Authorization": "Bearer " + o.apiKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var resp openAICostPage
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("decoding openai costs: %w", err)
+		}
+		for _, bucket := range resp.Data {
+			day := time.Unix(bucket.StartTime, 0).UTC().Format(DayFormat)
+			for _, r := range bucket.Results {
+				if r.LineItem == nil {
+					continue
+				}
+				model, _, _ := strings.Cut(*r.LineItem, ",")
+				if model = strings.TrimSpace(model); model == "" {
+					continue
+				}
+				if r.Amount.Currency != "" && !strings.EqualFold(r.Amount.Currency, "usd") {
+					return nil, fmt.Errorf("openai costs are in %s, only USD is supported", r.Amount.Currency)
+				}
+				addUsage(totals, &order, Usage{Day: day, Model: NormalizeModel(model), CostUSD: r.Amount.Value, Provider: o.Name()})
+			}
+		}
+		if !resp.HasMore || resp.NextPage == nil || *resp.NextPage == "" {
+			break
+		}
+		page = *resp.NextPage
+	}
+	return collect(totals, order), nil
+}
@@ -0,0 +1,403 @@
+// Package chargeback closes monthly cost ledgers and bills session spend to
+// cost centers.
+//
+// Closing a month freezes its ledger: the session cost entries are written
+// to <town>/costs/<YYYY-MM>/ledger.json, the session.ended events behind
+// them are archived to events.jsonl, and each cost center gets a summary
+// signed with the town's ed25519 key. Later events dated in a closed month
+// no longer change its figures.
+//
+// The private key lives in <town>/.runtime/costs-signing.key and is created
+// on the first close; the public key is published as <town>/costs/signing.pub
+// so finance can verify summaries without access to the town.
+package chargeback
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+)
+
+// Unassigned is the cost center for spend with no configured tag.
+const Unassigned = "unassigned"
+
+// MonthLayout is the time layout of a ledger month (e.g. "2025-01").
+const MonthLayout = "2006-01"
+
+// File names within the costs directory and a closed month's directory.
+const (
+	DirName       = "costs"
+	LedgerFile    = "ledger.json"
+	EventsFile    = "events.jsonl"
+	PublicKeyFile = "signing.pub"
+	keyFile       = "costs-signing.key"
+	summaryPrefix = "summary-"
+)
+
+var (
+	// ErrClosed is returned when closing a month that is already closed.
+	ErrClosed = errors.New("month already closed")
+
+	// ErrNotClosed is returned when reading a month that has not been closed.
+	ErrNotClosed = errors.New("month not closed")
+
+	// ErrBadSignature is returned when a summary fails verification.
+	ErrBadSignature = errors.New("summary signature invalid")
+)
+
+// Entry is one session's cost in a ledger.
+type Entry struct {
+	SessionID  string    `json:"session_id"`
+	Role       string    `json:"role"`
+	Rig        string    `json:"rig,omitempty"`
+	Worker     string    `json:"worker,omitempty"`
+	CostUSD    float64   `json:"cost_usd"`
+	EndedAt    time.Time `json:"ended_at"`
+	WorkItem   string    `json:"work_item,omitempty"`
+	Model      string    `json:"model,omitempty"`
+	CostCenter string    `json:"cost_center"`
+}
+
+// Ledger is a closed month's frozen cost entries.
+type Ledger struct {
+	Month    string    `json:"month"`
+	ClosedAt time.Time `json:"closed_at"`
+	ClosedBy string    `json:"closed_by,omitempty"`
+	TotalUSD float64   `json:"total_usd"`
+	Entries  []Entry   `json:"entries"`
+}
+
+// Summary is the signed chargeback statement for one cost center.
+type Summary struct {
+	Type         string             `json:"type"` // "cost-center-summary"
+	Month        string             `json:"month"`
+	CostCenter   string             `json:"cost_center"`
+	ClosedAt     time.Time          `json:"closed_at"`
+	Sessions     int                `json:"sessions"`
+	TotalUSD     float64            `json:"total_usd"`
+	ByRig        map[string]float64 `json:"by_rig,omitempty"`
+	ByRole       map[string]float64 `json:"by_role,omitempty"`
+	LedgerSHA256 string             `json:"ledger_sha256"` // binds the summary to ledger.json
+	PublicKey    string             `json:"public_key"`
+	Signature    string             `json:"signature"`
+}
+
+// ParseMonth parses "YYYY-MM" and returns the month's bounds in UTC.
+func ParseMonth(month string) (start, end time.Time, err error) {
+	start, err = time.Parse(MonthLayout, month)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid month %q (want YYYY-MM)", month)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// MonthOf returns the ledger month containing t.
+func MonthOf(t time.Time) string {
+	return t.UTC().Format(MonthLayout)
+}
+
+// Dir returns the town's costs directory.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, DirName)
+}
+
+// MonthDir returns the directory of a closed month.
+func MonthDir(townRoot, month string) string {
+	return filepath.Join(Dir(townRoot), month)
+}
+
+// IsClosed reports whether month has been closed.
+func IsClosed(townRoot, month string) bool {
+	_, err := os.Stat(filepath.Join(MonthDir(townRoot, month), LedgerFile))
+	return err == nil
+}
+
+// ClosedMonths returns the closed months, oldest first.
+func ClosedMonths(townRoot string) ([]string, error) {
+	dirEntries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var months []string
+	for _, de := range dirEntries {
+		if !de.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(MonthLayout, de.Name()); err != nil {
+			continue
+		}
+		if IsClosed(townRoot, de.Name()) {
+			months = append(months, de.Name())
+		}
+	}
+	sort.Strings(months)
+	return months, nil
+}
+
+// LoadLedger reads a closed month's ledger.
+func LoadLedger(townRoot, month string) (*Ledger, error) {
+	data, err := os.ReadFile(filepath.Join(MonthDir(townRoot, month), LedgerFile)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotClosed, month)
+		}
+		return nil, fmt.Errorf("reading ledger: %w", err)
+	}
+	var l Ledger
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("parsing ledger: %w", err)
+	}
+	return &l, nil
+}
+
+// Summarize totals a ledger by cost center, sorted by cost center. The
+// summaries are unsigned.
+func Summarize(l *Ledger) []*Summary {
+	byCenter := make(map[string]*Summary)
+	for _, e := range l.Entries {
+		center := e.CostCenter
+		if center == "" {
+			center = Unassigned
+		}
+		s, ok := byCenter[center]
+		if !ok {
+			s = &Summary{
+				Type:       "cost-center-summary",
+				Month:      l.Month,
+				CostCenter: center,
+				ClosedAt:   l.ClosedAt,
+				ByRig:      make(map[string]float64),
+				ByRole:     make(map[string]float64),
+			}
+			byCenter[center] = s
+		}
+		s.Sessions++
+		s.TotalUSD += e.CostUSD
+		if e.Rig != "" {
+			s.ByRig[e.Rig] += e.CostUSD
+		}
+		s.ByRole[e.Role] += e.CostUSD
+	}
+
+	summaries := make([]*Summary, 0, len(byCenter))
+	for _, s := range byCenter {
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].CostCenter < summaries[j].CostCenter })
+	return summaries
+}
+
+// Close freezes a month: it writes the ledger, the archived events (one
+// JSON value per line), and a signed summary per cost center. The month
+// directory appears atomically, so a failed close leaves the month open.
+func Close(townRoot string, l *Ledger, archived []json.RawMessage) ([]*Summary, error) {
+	if _, _, err := ParseMonth(l.Month); err != nil {
+		return nil, err
+	}
+	final := MonthDir(townRoot, l.Month)
+	if IsClosed(townRoot, l.Month) {
+		return nil, fmt.Errorf("%w: %s", ErrClosed, l.Month)
+	}
+
+	key, err := loadOrCreateKey(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp := final + ".closing"
+	if err := os.RemoveAll(tmp); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return nil, fmt.Errorf("creating ledger directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	ledgerData, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding ledger: %w", err)
+	}
+	ledgerData = append(ledgerData, '\n')
+	if err := os.WriteFile(filepath.Join(tmp, LedgerFile), ledgerData, 0644); err != nil { //nolint:gosec // G306: ledger is not secret
+		return nil, fmt.Errorf("writing ledger: %w", err)
+	}
+
+	var events strings.Builder
+	for _, raw := range archived {
+		events.Write(raw)
+		events.WriteByte('\n')
+	}
+	if err := os.WriteFile(filepath.Join(tmp, EventsFile), []byte(events.String()), 0644); err != nil { //nolint:gosec // G306: events are not secret
+		return nil, fmt.Errorf("writing archived events: %w", err)
+	}
+
+	digest := sha256.Sum256(ledgerData)
+	summaries := Summarize(l)
+	for _, s := range summaries {
+		s.LedgerSHA256 = hex.EncodeToString(digest[:])
+		if err := sign(key, s); err != nil {
+			return nil, err
+		}
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding summary: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(tmp, summaryPrefix+s.CostCenter+".json"), append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: summaries are not secret
+			return nil, fmt.Errorf("writing summary: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmp, final); err != nil {
+		return nil, fmt.Errorf("finalizing ledger: %w", err)
+	}
+	return summaries, nil
+}
+
+// LoadSummaries reads a closed month's cost center summaries.
+func LoadSummaries(townRoot, month string) ([]*Summary, error) {
+	if !IsClosed(townRoot, month) {
+		return nil, fmt.Errorf("%w: %s", ErrNotClosed, month)
+	}
+	paths, err := filepath.Glob(filepath.Join(MonthDir(townRoot, month), summaryPrefix+"*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	summaries := make([]*Summary, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is from a glob of the month directory
+		if err != nil {
+			return nil, fmt.Errorf("reading summary: %w", err)
+		}
+		var s Summary
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+		}
+		summaries = append(summaries, &s)
+	}
+	return summaries, nil
+}
+
+// Verify checks a closed month: every summary must carry a valid signature
+// by the town's published key and match the ledger on disk.
+func Verify(townRoot, month string) ([]*Summary, error) {
+	pub, err := PublicKey(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	ledgerData, err := os.ReadFile(filepath.Join(MonthDir(townRoot, month), LedgerFile)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotClosed, month)
+		}
+		return nil, fmt.Errorf("reading ledger: %w", err)
+	}
+	digest := sha256.Sum256(ledgerData)
+
+	summaries, err := LoadSummaries(townRoot, month)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range summaries {
+		if s.PublicKey != hex.EncodeToString(pub) {
+			return summaries, fmt.Errorf("%w: %s signed by an unknown key", ErrBadSignature, s.CostCenter)
+		}
+		if s.LedgerSHA256 != hex.EncodeToString(digest[:]) {
+			return summaries, fmt.Errorf("%w: %s does not match %s", ErrBadSignature, s.CostCenter, LedgerFile)
+		}
+		sig, err := hex.DecodeString(s.Signature)
+		if err != nil {
+			return summaries, fmt.Errorf("%w: %s", ErrBadSignature, s.CostCenter)
+		}
+		payload, err := signedPayload(s)
+		if err != nil {
+			return summaries, err
+		}
+		if !ed25519.Verify(pub, payload, sig) {
+			return summaries, fmt.Errorf("%w: %s", ErrBadSignature, s.CostCenter)
+		}
+	}
+	return summaries, nil
+}
+
+// PublicKey returns the town's published summary signing key.
+func PublicKey(townRoot string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(filepath.Join(Dir(townRoot), PublicKeyFile)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+	pub, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid signing key in %s", PublicKeyFile)
+	}
+	return ed25519.PublicKey(pub), nil
+}
+
+// sign sets the summary's public key and signature.
+func sign(key ed25519.PrivateKey, s *Summary) error {
+	s.PublicKey = hex.EncodeToString(key.Public().(ed25519.PublicKey))
+	payload, err := signedPayload(s)
+	if err != nil {
+		return err
+	}
+	s.Signature = hex.EncodeToString(ed25519.Sign(key, payload))
+	return nil
+}
+
+// signedPayload is the summary's JSON encoding without its signature.
+func signedPayload(s *Summary) ([]byte, error) {
+	unsigned := *s
+	unsigned.Signature = ""
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encoding summary: %w", err)
+	}
+	return data, nil
+}
+
+// loadOrCreateKey returns the town's signing key, creating it and
+// publishing its public half on first use.
+func loadOrCreateKey(townRoot string) (ed25519.PrivateKey, error) {
+	path := filepath.Join(constants.TownRuntimePath(townRoot), keyFile)
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is constructed internally
+		seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid signing key %s", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating signing key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key.Seed())+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("writing signing key: %w", err)
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(Dir(townRoot), PublicKeyFile), []byte(hex.EncodeToString(pub)+"\n"), 0644); err != nil { //nolint:gosec // G306: public key
+		return nil, fmt.Errorf("writing public key: %w", err)
+	}
+	return key, nil
+}
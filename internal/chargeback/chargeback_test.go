@@ -0,0 +1,121 @@
+package chargeback
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testLedger() *Ledger {
+	ended := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	return &Ledger{
+		Month:    "2025-01",
+		ClosedAt: time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC),
+		ClosedBy: "mayor",
+		TotalUSD: 7.5,
+		Entries: []Entry{
+			{SessionID: "gt-gastown-toast", Role: "polecat", Rig: "gastown", CostUSD: 4, EndedAt: ended, CostCenter: "eng-platform"},
+			{SessionID: "gt-gastown-witness", Role: "witness", Rig: "gastown", CostUSD: 1.5, EndedAt: ended, CostCenter: "eng-platform"},
+			{SessionID: "gt-mayor", Role: "mayor", CostUSD: 2, EndedAt: ended},
+		},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	summaries := Summarize(testLedger())
+	if len(summaries) != 2 {
+		t.Fatalf("got %d summaries, want 2", len(summaries))
+	}
+	eng, shared := summaries[0], summaries[1]
+	if eng.CostCenter != "eng-platform" || eng.Sessions != 2 || eng.TotalUSD != 5.5 || eng.ByRig["gastown"] != 5.5 || eng.ByRole["witness"] != 1.5 {
+		t.Errorf("eng-platform = %+v", eng)
+	}
+	if shared.CostCenter != Unassigned || shared.TotalUSD != 2 || len(shared.ByRig) != 0 {
+		t.Errorf("unassigned = %+v", shared)
+	}
+}
+
+func TestCloseAndVerify(t *testing.T) {
+	townRoot := t.TempDir()
+	archived := []json.RawMessage{json.RawMessage(`{"id":"gt-ev1"}`), json.RawMessage(`{"id":"gt-ev2"}`)}
+
+	if IsClosed(townRoot, "2025-01") {
+		t.Fatal("month should start open")
+	}
+	if _, err := Close(townRoot, testLedger(), archived); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !IsClosed(townRoot, "2025-01") {
+		t.Fatal("month should be closed")
+	}
+	if _, err := Close(townRoot, testLedger(), nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("second close: err = %v, want ErrClosed", err)
+	}
+
+	months, err := ClosedMonths(townRoot)
+	if err != nil || len(months) != 1 || months[0] != "2025-01" {
+		t.Errorf("ClosedMonths = %v, %v", months, err)
+	}
+	ledger, err := LoadLedger(townRoot, "2025-01")
+	if err != nil || len(ledger.Entries) != 3 {
+		t.Fatalf("LoadLedger = %+v, %v", ledger, err)
+	}
+	events, err := os.ReadFile(filepath.Join(MonthDir(townRoot, "2025-01"), EventsFile))
+	if err != nil || string(events) != "{\"id\":\"gt-ev1\"}\n{\"id\":\"gt-ev2\"}\n" {
+		t.Errorf("archived events = %q, %v", events, err)
+	}
+
+	summaries, err := Verify(townRoot, "2025-01")
+	if err != nil || len(summaries) != 2 {
+		t.Fatalf("Verify = %d summaries, %v", len(summaries), err)
+	}
+
+	// Tampering with a summary breaks its signature.
+	path := filepath.Join(MonthDir(townRoot, "2025-01"), "summary-eng-platform.json")
+	var s Summary
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	s.TotalUSD = 0.5
+	data, _ = json.Marshal(&s)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(townRoot, "2025-01"); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("tampered summary: err = %v, want ErrBadSignature", err)
+	}
+}
+
+func TestVerifyDetectsLedgerChange(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := Close(townRoot, testLedger(), nil); err != nil {
+		t.Fatal(err)
+	}
+	ledgerPath := filepath.Join(MonthDir(townRoot, "2025-01"), LedgerFile)
+	data, _ := os.ReadFile(ledgerPath)
+	if err := os.WriteFile(ledgerPath, append(data, ' '), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(townRoot, "2025-01"); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("edited ledger: err = %v, want ErrBadSignature", err)
+	}
+	if _, err := Verify(townRoot, "2024-12"); !errors.Is(err, ErrNotClosed) {
+		t.Errorf("open month: err = %v, want ErrNotClosed", err)
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	start, end, err := ParseMonth("2025-12")
+	if err != nil || !start.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseMonth = %v, %v, %v", start, end, err)
+	}
+	for _, bad := range []string{"2025-13", "2025-1", "Jan 2025", "../x"} {
+		if _, _, err := ParseMonth(bad); err == nil {
+			t.Errorf("ParseMonth(%q) should fail", bad)
+		}
+	}
+}
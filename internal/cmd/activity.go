@@ -23,6 +23,7 @@ var (
 	activityIssue     string
 	activityTo        string
 	activityCount     int
+	activityKey       string
 )
 
 var activityCmd = &cobra.Command{
@@ -59,13 +60,17 @@ Common options:
   --actor    Who is emitting the event (e.g., greenplace/witness)
   --rig      Which rig the event is about
   --message  Human-readable message
+  --idempotency-key
+             Skip the event if one with this key was already emitted
+             (use a stable key so hook retries don't duplicate events)
 
 Examples:
   gt activity emit patrol_started --rig greenplace --count 3
   gt activity emit polecat_checked --rig greenplace --polecat Toast --status working --issue gp-xyz
   gt activity emit polecat_nudged --rig greenplace --polecat Toast --reason "idle for 10 minutes"
   gt activity emit escalation_sent --rig greenplace --target Toast --to mayor --reason "unresponsive"
-  gt activity emit patrol_complete --rig greenplace --count 3 --message "All polecats healthy"`,
+  gt activity emit patrol_complete --rig greenplace --count 3 --message "All polecats healthy"
+  gt activity emit patrol_started --rig greenplace --idempotency-key "patrol:greenplace:$CYCLE_ID"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runActivityEmit,
 }
@@ -82,6 +87,7 @@ func init() {
 	activityEmitCmd.Flags().StringVar(&activityIssue, "issue", "", "Issue ID (for polecat_checked)")
 	activityEmitCmd.Flags().StringVar(&activityTo, "to", "", "Escalation target (for escalation_sent: mayor, deacon)")
 	activityEmitCmd.Flags().IntVar(&activityCount, "count", 0, "Polecat count (for patrol events)")
+	activityEmitCmd.Flags().StringVar(&activityKey, "idempotency-key", "", "Skip the event if one with this key was already emitted")
 
 	activityCmd.AddCommand(activityEmitCmd)
 	rootCmd.AddCommand(activityCmd)
@@ -146,7 +152,7 @@ func runActivityEmit(cmd *cobra.Command, args []string) error {
 			payload["branch"] = activityTarget
 		}
 		if activityReason != "" {
-			payload["reason"] = activityReason
+			payload["reason"] = events.Body(activityReason) // may carry test output
 		}
 
 	default:
@@ -182,9 +188,15 @@ func runActivityEmit(cmd *cobra.Command, args []string) error {
 	}
 
 	// Emit the event
-	if err := events.LogFeed(eventType, actor, payload); err != nil {
+	written, err := events.LogOnce(activityKey, eventType, actor, payload, events.VisibilityFeed)
+	if err != nil {
 		return fmt.Errorf("emitting event: %w", err)
 	}
+	if !written {
+		fmt.Printf("%s %s event with key %q already emitted, skipped\n",
+			style.Dim.Render("[SKIP]"), style.Bold.Render(eventType), activityKey)
+		return nil
+	}
 
 	// Print confirmation
 	payloadJSON, _ := json.Marshal(payload)
@@ -0,0 +1,373 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/alerts"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var (
+	alertsJSON  bool
+	alertsAll   bool
+	alertsQuiet bool
+)
+
+var alertsCmd = &cobra.Command{
+	Use:     "alerts",
+	GroupID: GroupDiag,
+	Short:   "Show and acknowledge cost alerts",
+	Long: `Show budget and anomaly alerts for agent session costs.
+
+Alerts use hysteresis: once an alert fires it stays quiet until the metric
+drops back below the threshold by the configured recovery margin, or crosses
+a higher threshold. Acknowledging an alert keeps it quiet under the same rules.
+
+Budgets are configured in settings/config.json:
+
+  "cost_alerts": {
+    "daily_budget_usd": 50,
+    "weekly_budget_usd": 250,
+    "session_limit_usd": 10,
+    "anomaly_factor": 3,
+    "thresholds": [0.8, 1.0],
+    "recovery_margin": 0.1
+  }
+
+Budget alerts fire at each threshold fraction of a budget. The anomaly
+alert fires when today's spend exceeds anomaly_factor times the average
+daily spend of the previous 7 days. A session's alert resolves when the
+session ends.
+
+Examples:
+  gt alerts                  # Show active alerts
+  gt alerts --all            # Include resolved alerts
+  gt alerts check            # Evaluate budgets and notify on new alerts
+  gt alerts ack budget-daily # Acknowledge an alert`,
+	RunE: runAlertsList,
+}
+
+var alertsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Evaluate cost budgets and notify on new alerts",
+	Long: `Evaluate cost metrics against configured budgets.
+
+Newly fired alerts are mailed to the overseer and logged to the activity feed.
+Alerts that are already active (firing or acknowledged) are not re-sent.
+The daemon runs this on every heartbeat.`,
+	RunE: runAlertsCheck,
+}
+
+var alertsAckCmd = &cobra.Command{
+	Use:   "ack <id>",
+	Short: "Acknowledge an active alert",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAlertsAck,
+}
+
+func init() {
+	alertsCmd.Flags().BoolVar(&alertsJSON, "json", false, "Output as JSON")
+	alertsCmd.Flags().BoolVar(&alertsAll, "all", false, "Include resolved alerts")
+	alertsCheckCmd.Flags().BoolVarP(&alertsQuiet, "quiet", "q", false, "Only print newly fired alerts")
+
+	alertsCmd.AddCommand(alertsCheckCmd)
+	alertsCmd.AddCommand(alertsAckCmd)
+	rootCmd.AddCommand(alertsCmd)
+}
+
+func runAlertsList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	state, err := alerts.Load(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var list []*alerts.Alert
+	for _, a := range state.List() {
+		if alertsAll || a.Active() {
+			list = append(list, a)
+		}
+	}
+
+	if alertsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+
+	if len(list) == 0 {
+		fmt.Println(style.Dim.Render("No active alerts"))
+		return nil
+	}
+
+	for _, a := range list {
+		printAlert(a)
+	}
+	return nil
+}
+
+func printAlert(a *alerts.Alert) {
+	var prefix string
+	switch a.Status {
+	case alerts.StatusFiring:
+		prefix = style.ErrorPrefix
+	case alerts.StatusAcknowledged:
+		prefix = style.WarningPrefix
+	default:
+		prefix = style.SuccessPrefix
+	}
+
+	fmt.Printf("%s %s: %s\n", prefix, style.Bold.Render(a.ID), a.Summary)
+	fmt.Printf("    %s  value $%.2f  threshold $%.2f  fired %s\n",
+		a.Status, a.Value, a.Threshold, a.FiredAt.Local().Format("2006-01-02 15:04"))
+	if a.Status == alerts.StatusAcknowledged {
+		fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("acknowledged by %s at %s",
+			a.AckedBy, a.AckedAt.Local().Format("2006-01-02 15:04"))))
+	}
+}
+
+func runAlertsAck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	state, err := alerts.Load(townRoot)
+	if err != nil {
+		return err
+	}
+
+	by, err := detectAgentIdentity()
+	if err != nil || by == "" {
+		by = "overseer"
+	}
+
+	a, err := state.Ack(args[0], by, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := state.Save(townRoot); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Acknowledged %s (suppressed until recovery or a higher threshold)\n",
+		style.SuccessPrefix, a.ID)
+	return nil
+}
+
+// costMetric is a single observation evaluated against alert thresholds.
+type costMetric struct {
+	id      string
+	summary string
+	value   float64
+	budget  float64   // the budget or limit the levels derive from
+	levels  []float64 // absolute thresholds, ascending
+}
+
+// sessionAlertPrefix starts the IDs of per-session cost alerts.
+const sessionAlertPrefix = "session-"
+
+func runAlertsCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	cfg := settings.CostAlerts
+	if cfg == nil {
+		if !alertsQuiet {
+			fmt.Println(style.Dim.Render("No cost_alerts configured in settings/config.json"))
+		}
+		return nil
+	}
+
+	if err := config.ValidateCostAlerts(cfg); err != nil {
+		return err
+	}
+
+	metrics, sessionsListed, err := collectCostMetrics(cfg)
+	if err != nil {
+		return err
+	}
+
+	state, err := alerts.Load(townRoot)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var fired []*alerts.Alert
+	observed := make(map[string]bool, len(metrics))
+	for _, m := range metrics {
+		observed[m.id] = true
+		if a, ok := state.Observe(m.id, m.summary, m.value, m.levels, cfg.AlertRecoveryMargin(), now); ok {
+			fired = append(fired, a)
+		}
+	}
+	// Sessions that have ended are never observed again
+	if sessionsListed {
+		state.ResolveUnobserved(sessionAlertPrefix, observed, now)
+	}
+
+	if err := state.Save(townRoot); err != nil {
+		return err
+	}
+
+	for _, a := range fired {
+		notifyCostAlert(townRoot, a)
+		printAlert(a)
+	}
+
+	if len(fired) == 0 && !alertsQuiet {
+		fmt.Printf("%s No new alerts (%d metric(s) evaluated)\n", style.SuccessPrefix, len(metrics))
+	}
+	return nil
+}
+
+// collectCostMetrics gathers the cost values that budgets apply to.
+// sessionsListed reports whether the per-session metrics cover every live
+// session (or session limits are off), so alerts for other sessions can be
+// resolved.
+func collectCostMetrics(cfg *config.CostAlertsConfig) (metrics []costMetric, sessionsListed bool, err error) {
+	if cfg.DailyBudgetUSD > 0 || cfg.WeeklyBudgetUSD > 0 || cfg.AnomalyFactor > 0 {
+		entries, err := querySessionEvents()
+		if err != nil {
+			return nil, false, fmt.Errorf("querying session events: %w", err)
+		}
+		metrics = spendMetrics(cfg, entries, time.Now())
+	}
+
+	if cfg.SessionLimitUSD <= 0 {
+		return metrics, true, nil
+	}
+	t := tmux.NewTmux()
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return metrics, false, nil
+	}
+	levels := budgetLevels(cfg, cfg.SessionLimitUSD)
+	for _, session := range sessions {
+		if !strings.HasPrefix(session, constants.SessionPrefix) {
+			continue
+		}
+		content, err := t.CapturePaneAll(session)
+		if err != nil {
+			continue
+		}
+		cost := extractCost(content)
+		metrics = append(metrics, costMetric{
+			id:      sessionAlertPrefix + strings.TrimPrefix(session, constants.SessionPrefix),
+			summary: fmt.Sprintf("Session %s at $%.2f (limit $%.2f)", session, cost, cfg.SessionLimitUSD),
+			value:   cost,
+			budget:  cfg.SessionLimitUSD,
+			levels:  levels,
+		})
+	}
+	return metrics, true, nil
+}
+
+// spendMetrics computes the daily and weekly budget metrics and the daily
+// anomaly metric from ended sessions' costs.
+func spendMetrics(cfg *config.CostAlertsConfig, entries []CostEntry, now time.Time) []costMetric {
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	weekAgo := now.AddDate(0, 0, -7)
+	priorStart := todayStart.AddDate(0, 0, -7)
+	var today, week, prior float64
+	for _, e := range entries {
+		if !e.EndedAt.Before(todayStart) {
+			today += e.CostUSD
+		} else if !e.EndedAt.Before(priorStart) {
+			prior += e.CostUSD
+		}
+		if e.EndedAt.After(weekAgo) {
+			week += e.CostUSD
+		}
+	}
+
+	var metrics []costMetric
+	if cfg.DailyBudgetUSD > 0 {
+		metrics = append(metrics, costMetric{
+			id:      "budget-daily",
+			summary: fmt.Sprintf("Daily spend $%.2f of $%.2f budget", today, cfg.DailyBudgetUSD),
+			value:   today,
+			budget:  cfg.DailyBudgetUSD,
+			levels:  budgetLevels(cfg, cfg.DailyBudgetUSD),
+		})
+	}
+	if cfg.WeeklyBudgetUSD > 0 {
+		metrics = append(metrics, costMetric{
+			id:      "budget-weekly",
+			summary: fmt.Sprintf("Weekly spend $%.2f of $%.2f budget", week, cfg.WeeklyBudgetUSD),
+			value:   week,
+			budget:  cfg.WeeklyBudgetUSD,
+			levels:  budgetLevels(cfg, cfg.WeeklyBudgetUSD),
+		})
+	}
+	// Without any spend in the previous week there is no baseline to compare
+	// against.
+	if average := prior / 7; cfg.AnomalyFactor > 0 && average > 0 {
+		limit := average * cfg.AnomalyFactor
+		metrics = append(metrics, costMetric{
+			id: "anomaly-daily",
+			summary: fmt.Sprintf("Daily spend $%.2f is %.1fx the 7-day average of $%.2f",
+				today, today/average, average),
+			value:  today,
+			budget: limit,
+			levels: []float64{limit},
+		})
+	}
+	return metrics
+}
+
+// budgetLevels returns the absolute alert thresholds for a budget.
+func budgetLevels(cfg *config.CostAlertsConfig, budget float64) []float64 {
+	fractions := cfg.AlertThresholds()
+	levels := make([]float64, 0, len(fractions))
+	for _, frac := range fractions {
+		levels = append(levels, budget*frac)
+	}
+	return levels
+}
+
+// notifyCostAlert mails the overseer and logs a feed event for a fired alert.
+// Notification is best-effort: alert state is already persisted.
+func notifyCostAlert(townRoot string, a *alerts.Alert) {
+	// Higher thresholds (e.g. 100% of budget) warrant higher priority.
+	priority := mail.PriorityNormal
+	if a.Level > 0 {
+		priority = mail.PriorityHigh
+	}
+
+	router := mail.NewRouter(townRoot)
+	msg := &mail.Message{
+		From:     "daemon",
+		To:       "overseer",
+		Subject:  fmt.Sprintf("[COST] %s", a.Summary),
+		Body:     fmt.Sprintf("Alert: %s\nThreshold: $%.2f\nValue: $%.2f\n\nAcknowledge with: gt alerts ack %s", a.ID, a.Threshold, a.Value, a.ID),
+		Priority: priority,
+	}
+	if err := router.Send(msg); err != nil {
+		style.PrintWarning("could not mail cost alert %s: %v", a.ID, err)
+	}
+
+	_ = events.LogFeed(events.TypeCostAlert, "daemon", events.CostAlertPayload(a.ID, a.Summary, a.Value, a.Threshold))
+}
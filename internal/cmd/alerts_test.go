@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+func TestSpendMetrics(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	var entries []CostEntry
+	// $2 a day for the previous week, then $9 so far today
+	for day := 1; day <= 7; day++ {
+		entries = append(entries, CostEntry{CostUSD: 2, EndedAt: now.AddDate(0, 0, -day)})
+	}
+	entries = append(entries, CostEntry{CostUSD: 9, EndedAt: now.Add(-time.Hour)})
+
+	cfg := &config.CostAlertsConfig{DailyBudgetUSD: 10, WeeklyBudgetUSD: 100, AnomalyFactor: 3, Thresholds: []float64{1.0, 0.8}}
+	metrics := make(map[string]costMetric)
+	for _, m := range spendMetrics(cfg, entries, now) {
+		metrics[m.id] = m
+	}
+
+	daily := metrics["budget-daily"]
+	if daily.value != 9 || len(daily.levels) != 2 || daily.levels[0] != 8 || daily.levels[1] != 10 {
+		t.Errorf("budget-daily = %+v, want value 9 at ascending levels [8 10]", daily)
+	}
+	if weekly := metrics["budget-weekly"]; weekly.value != 21 {
+		t.Errorf("budget-weekly value = %v, want 21", weekly.value)
+	}
+	anomaly, ok := metrics["anomaly-daily"]
+	if !ok || anomaly.value != 9 || len(anomaly.levels) != 1 || anomaly.levels[0] != 6 {
+		t.Errorf("anomaly-daily = %+v, want value 9 against 3x the $2 average", anomaly)
+	}
+
+	// No spend in the previous week: no baseline, no anomaly metric
+	for _, m := range spendMetrics(cfg, entries[7:], now) {
+		if m.id == "anomaly-daily" {
+			t.Errorf("anomaly metric without history: %+v", m)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// attachBannerMs is how long the attach banner stays in the status line.
+const attachBannerMs = 10000
+
+// Attach command flags
+var (
+	attachReadOnly bool
+	attachBanner   bool
+)
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+	attachCmd.Flags().BoolVarP(&attachReadOnly, "read-only", "r", false, "Attach as a read-only client (keystrokes are not sent to the agent)")
+	attachCmd.Flags().BoolVar(&attachBanner, "banner", false, "Show the agent's role and hooked work on attach")
+}
+
+var attachCmd = &cobra.Command{
+	Use:     "attach <agent>",
+	GroupID: GroupAgents,
+	Short:   "Attach to an agent's session safely",
+	Long: `Attach the current terminal to any agent's tmux session.
+
+Accepts the same targets as gt nudge: role shortcuts (mayor, deacon,
+witness, refinery, crew), paths (<rig>/<polecat>, <rig>/crew/<name>,
+<rig>/witness) or a session name.
+
+--read-only attaches as a read-only tmux client: you see everything the
+agent does, but keystrokes never reach its prompt. Detach with Ctrl-B D.
+
+--banner shows the agent's role and hooked work in the status line,
+with a reminder not to type over the agent while it is working.
+
+Defaults for both come from "attach" in settings/config.json:
+  {"attach": {"banner": true, "read_only": true}}
+Pass --read-only=false or --banner=false to override them.
+
+Examples:
+  gt attach greenplace/furiosa            # Attach to a polecat
+  gt attach beads/crew/dave --read-only   # Watch without typing into it
+  gt attach mayor --banner`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAttach,
+}
+
+func runAttach(cmd *cobra.Command, args []string) error {
+	sessionName, err := resolveRoleToSession(args[0])
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	exists, err := t.HasSession(sessionName)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("no session %s for %s", sessionName, args[0])
+	}
+
+	townRoot, _ := workspace.FindFromCwd()
+	var attachCfg *config.AttachConfig
+	if townRoot != "" {
+		if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+			attachCfg = settings.Attach
+		}
+	}
+	readOnly := attachCfg.ReadOnlyDefault()
+	if cmd.Flags().Changed("read-only") {
+		readOnly = attachReadOnly
+	}
+	showBanner := attachCfg.ShowBanner()
+	if cmd.Flags().Changed("banner") {
+		showBanner = attachBanner
+	}
+
+	insideTmux := tmux.IsInsideTmux()
+	if insideTmux && isInTmuxSession(sessionName) {
+		fmt.Printf("Already in %s\n", sessionName)
+		return nil
+	}
+	if insideTmux && readOnly {
+		// switch-client -r would leave the user's own client read-only
+		return fmt.Errorf("--read-only needs a terminal outside tmux; use 'gt peek %s' from here", args[0])
+	}
+
+	banner := ""
+	if showBanner {
+		banner = attachBannerFor(sessionName, townRoot, readOnly)
+	}
+
+	tmuxPath, err := exec.LookPath("tmux")
+	if err != nil {
+		return fmt.Errorf("tmux not found: %w", err)
+	}
+	attach := exec.Command(tmuxPath, attachArgs(sessionName, readOnly, banner, insideTmux)...)
+	attach.Stdin = os.Stdin
+	attach.Stdout = os.Stdout
+	attach.Stderr = os.Stderr
+	return attach.Run()
+}
+
+// attachArgs builds the tmux arguments that attach to (or, inside tmux,
+// switch to) sessionName. A non-empty banner is displayed to the client
+// once it is attached.
+func attachArgs(sessionName string, readOnly bool, banner string, insideTmux bool) []string {
+	var args []string
+	if insideTmux {
+		args = []string{"switch-client", "-t", sessionName}
+	} else {
+		args = []string{"attach-session", "-t", sessionName}
+		if readOnly {
+			args = append(args, "-r")
+		}
+	}
+	if banner != "" {
+		// '#' starts a tmux format; double it so titles print literally
+		args = append(args, ";", "display-message", "-d", fmt.Sprint(attachBannerMs),
+			strings.ReplaceAll(banner, "#", "##"))
+	}
+	return args
+}
+
+// attachBannerFor describes the agent in sessionName for the attach
+// banner, looking up its hooked work when the town root is known.
+func attachBannerFor(sessionName, townRoot string, readOnly bool) string {
+	identity, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return formatAttachBanner(sessionName, "", "", readOnly)
+	}
+
+	work := ""
+	if townRoot != "" {
+		beadsDir := townRoot
+		if identity.Rig != "" {
+			beadsDir = filepath.Join(townRoot, identity.Rig, "mayor", "rig")
+		}
+		work = getHookedWork(hookIdentity(identity), 60, beadsDir)
+	}
+	return formatAttachBanner(identity.Address(), string(identity.Role), work, readOnly)
+}
+
+// hookIdentity returns the assignee an agent's hooked beads are filed
+// under, matching the status line.
+func hookIdentity(identity *session.AgentIdentity) string {
+	if identity.Role == session.RolePolecat {
+		return fmt.Sprintf("%s/%s", identity.Rig, identity.Name)
+	}
+	return identity.Address()
+}
+
+// formatAttachBanner renders the one-line attach banner.
+func formatAttachBanner(agent, role, work string, readOnly bool) string {
+	parts := []string{agent}
+	if role != "" {
+		parts[0] = fmt.Sprintf("%s (%s)", agent, role)
+	}
+	if work != "" {
+		parts = append(parts, "hooked: "+work)
+	} else {
+		parts = append(parts, "no hooked work")
+	}
+	if readOnly {
+		parts = append(parts, "read-only, Ctrl-B D to detach")
+	} else {
+		parts = append(parts, "agent may be working: avoid typing over it")
+	}
+	return "[gt] " + strings.Join(parts, " | ")
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+)
+
+func TestAttachArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		readOnly   bool
+		banner     string
+		insideTmux bool
+		want       []string
+	}{
+		{"interactive", false, "", false, []string{"attach-session", "-t", "gt-gp-nux"}},
+		{"read-only", true, "", false, []string{"attach-session", "-t", "gt-gp-nux", "-r"}},
+		{"banner", true, "[gt] fix #12", false, []string{"attach-session", "-t", "gt-gp-nux", "-r", ";", "display-message", "-d", "10000", "[gt] fix ##12"}},
+		{"inside tmux", false, "", true, []string{"switch-client", "-t", "gt-gp-nux"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attachArgs("gt-gp-nux", tt.readOnly, tt.banner, tt.insideTmux); !slices.Equal(got, tt.want) {
+				t.Errorf("attachArgs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachBanner(t *testing.T) {
+	got := formatAttachBanner("gp/crew/dave", "crew", "gp-42: Fix login", false)
+	for _, want := range []string{"gp/crew/dave (crew)", "hooked: gp-42: Fix login", "avoid typing over it"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("banner %q missing %q", got, want)
+		}
+	}
+	if got := formatAttachBanner("gp/witness", "witness", "", true); !strings.Contains(got, "no hooked work") || !strings.Contains(got, "read-only") {
+		t.Errorf("read-only banner = %q", got)
+	}
+
+	polecat := &session.AgentIdentity{Role: session.RolePolecat, Rig: "gp", Name: "nux"}
+	if got := hookIdentity(polecat); got != "gp/nux" {
+		t.Errorf("polecat hook identity = %q, want gp/nux", got)
+	}
+}
@@ -438,7 +438,7 @@ func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, e
 			Source:    "events",
 			Type:      e.Type,
 			Actor:     e.Actor,
-			Summary:   formatFeedSummary(e),
+			Summary:   formatFeedSummary(events.WithBodies(townRoot, e)),
 		})
 	}
 
@@ -475,6 +475,10 @@ func formatFeedSummary(e events.Event) string {
 			return fmt.Sprintf("Sent mail to %s", to)
 		}
 		return "Sent mail"
+	case events.TypeProgress:
+		percent, _ := e.Payload["percent"].(float64)
+		note, _ := e.Payload["note"].(string)
+		return joinNonEmpty(fmt.Sprintf("Progress %d%%", int(percent)), note)
 	default:
 		return e.Type
 	}
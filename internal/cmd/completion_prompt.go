@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// townPromptStaleAfter is how old the daemon's cached summary may be before
+// the prompt marks it stale. The daemon refreshes on every heartbeat (3m).
+const townPromptStaleAfter = 10 * time.Minute
+
+var (
+	townPromptFormat string
+	townPromptASCII  bool
+)
+
+var completionTownPromptCmd = &cobra.Command{
+	Use:   "town-prompt",
+	Short: "Print a compact town summary for shell prompts",
+	Long: `Print a compact town summary suitable for PS1 or starship prompts.
+
+Reads the summary cached by the daemon on each heartbeat, so it never
+queries tmux or beads and adds no noticeable prompt latency. Prints
+nothing outside a Gas Town workspace or before the daemon has run.
+
+Format placeholders:
+  {sessions}  running Gas Town sessions
+  {mail}      unread overseer mail
+  {alerts}    active cost alerts
+  {health}    health marker (✓ ok, ! degraded, ? stale)
+
+Examples:
+  gt completion town-prompt
+  gt completion town-prompt --ascii
+  gt completion town-prompt --format '{sessions}s {mail}m'
+
+Bash:     PS1='$(gt completion town-prompt) '$PS1
+Starship: [custom.gastown]
+          command = "gt completion town-prompt"
+          when = true`,
+	Args: cobra.NoArgs,
+	RunE: runCompletionTownPrompt,
+}
+
+func init() {
+	completionTownPromptCmd.Flags().StringVar(&townPromptFormat, "format", "", "Custom format string with {sessions}, {mail}, {alerts}, {health}")
+	completionTownPromptCmd.Flags().BoolVar(&townPromptASCII, "ascii", false, "Use ASCII markers instead of symbols")
+}
+
+// addCompletionSubcommands attaches gt-specific helpers to cobra's default
+// completion command. Called from root init after the command is created.
+func addCompletionSubcommands() {
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "completion" {
+			c.AddCommand(completionTownPromptCmd)
+			return
+		}
+	}
+}
+
+func runCompletionTownPrompt(cmd *cobra.Command, args []string) error {
+	// Prompt helpers must never fail loudly: any problem renders as empty.
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+
+	summary, err := daemon.LoadPromptSummary(townRoot)
+	if err != nil || summary == nil {
+		return nil
+	}
+
+	fmt.Print(formatTownPrompt(summary, townPromptFormat, townPromptASCII, time.Now()))
+	return nil
+}
+
+// formatTownPrompt renders a prompt summary. With an empty format, a compact
+// default is used that omits zero-valued mail and alert counts.
+func formatTownPrompt(s *daemon.PromptSummary, format string, ascii bool, now time.Time) string {
+	health := "✓"
+	mailMark := "✉"
+	alertMark := "$"
+	if ascii {
+		health = "ok"
+		mailMark = "m"
+	}
+	switch {
+	case now.Sub(s.UpdatedAt) > townPromptStaleAfter:
+		health = "?"
+	case s.Health != daemon.HealthOK:
+		health = "!"
+	}
+
+	if format != "" {
+		return strings.NewReplacer(
+			"{sessions}", strconv.Itoa(s.Sessions),
+			"{mail}", strconv.Itoa(s.UnreadMail),
+			"{alerts}", strconv.Itoa(s.ActiveAlerts),
+			"{health}", health,
+		).Replace(format)
+	}
+
+	parts := []string{"gt:" + strconv.Itoa(s.Sessions)}
+	if s.UnreadMail > 0 {
+		parts = append(parts, mailMark+strconv.Itoa(s.UnreadMail))
+	}
+	if s.ActiveAlerts > 0 {
+		parts = append(parts, alertMark+strconv.Itoa(s.ActiveAlerts))
+	}
+	parts = append(parts, health)
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+)
+
+func TestFormatTownPrompt(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		summary daemon.PromptSummary
+		format  string
+		ascii   bool
+		want    string
+	}{
+		{
+			name:    "healthy idle town",
+			summary: daemon.PromptSummary{UpdatedAt: now, Sessions: 3, Health: daemon.HealthOK},
+			want:    "gt:3 ✓",
+		},
+		{
+			name:    "mail and alerts",
+			summary: daemon.PromptSummary{UpdatedAt: now, Sessions: 2, UnreadMail: 4, ActiveAlerts: 1, Health: daemon.HealthDegraded},
+			want:    "gt:2 ✉4 $1 !",
+		},
+		{
+			name:    "ascii",
+			summary: daemon.PromptSummary{UpdatedAt: now, Sessions: 1, UnreadMail: 2, Health: daemon.HealthOK},
+			ascii:   true,
+			want:    "gt:1 m2 ok",
+		},
+		{
+			name:    "stale cache",
+			summary: daemon.PromptSummary{UpdatedAt: now.Add(-time.Hour), Sessions: 5, Health: daemon.HealthOK},
+			want:    "gt:5 ?",
+		},
+		{
+			name:    "custom format",
+			summary: daemon.PromptSummary{UpdatedAt: now, Sessions: 7, UnreadMail: 0, Health: daemon.HealthOK},
+			format:  "[{sessions}|{mail}|{health}]",
+			want:    "[7|0|✓]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatTownPrompt(&tt.summary, tt.format, tt.ascii, now)
+			if got != tt.want {
+				t.Errorf("formatTownPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -29,7 +29,8 @@ Commands:
   gt config agent get <name>         Show agent configuration
   gt config agent set <name> <cmd>   Set custom agent command
   gt config agent remove <name>      Remove custom agent
-  gt config default-agent [name]     Get or set default agent`,
+  gt config default-agent [name]     Get or set default agent
+  gt config effective                Show settings with this machine's profile`,
 }
 
 // Agent subcommands
@@ -300,7 +301,7 @@ func runConfigAgentSet(cmd *cobra.Command, args []string) error {
 
 	// Load town settings
 	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	townSettings, err := config.LoadOrCreateBaseTownSettings(settingsPath)
 	if err != nil {
 		return fmt.Errorf("loading town settings: %w", err)
 	}
@@ -359,7 +360,7 @@ func runConfigAgentRemove(cmd *cobra.Command, args []string) error {
 
 	// Load town settings
 	settingsPath := config.TownSettingsPath(townRoot)
-	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	townSettings, err := config.LoadOrCreateBaseTownSettings(settingsPath)
 	if err != nil {
 		return fmt.Errorf("loading town settings: %w", err)
 	}
@@ -432,7 +433,11 @@ func runConfigDefaultAgent(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("agent '%s' not found (use 'gt config agent list' to see available agents)", name)
 	}
 
-	// Set default
+	// Set default on the settings file itself, not the machine profile overlay
+	townSettings, err = config.LoadOrCreateBaseTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
 	townSettings.DefaultAgent = name
 
 	// Save settings
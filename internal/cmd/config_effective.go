@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var configEffectiveJSON bool
+
+var configEffectiveCmd = &cobra.Command{
+	Use:   "effective",
+	Short: "Show town settings with this machine's profile applied",
+	Long: `Show the town settings in effect on this machine.
+
+Machine profiles in settings/profiles/<name>.json overlay
+settings/config.json, so one synced town can run differently on a desktop
+and a laptop. The profile is chosen by GASTOWN_PROFILE (set it to "none"
+to disable profiles), otherwise by the first profile named after this
+machine's short hostname or listing it in "hosts":
+
+  {
+    "hosts": ["*-laptop"],
+    "settings": {"rig_defaults": {"max_polecats": 2}}
+  }
+
+Objects in "settings" merge key by key; any other value replaces the
+town's. Commands that change settings (gt config default-agent, ...) edit
+settings/config.json, never the profile.
+
+Examples:
+  gt config effective
+  GASTOWN_PROFILE=laptop gt config effective --json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEffective,
+}
+
+func init() {
+	configEffectiveCmd.Flags().BoolVar(&configEffectiveJSON, "json", false, "Output as JSON")
+	configCmd.AddCommand(configEffectiveCmd)
+}
+
+func runConfigEffective(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	settings, sel, err := config.LoadEffectiveTownSettings(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if configEffectiveJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Host      string               `json:"host"`
+			Profile   string               `json:"profile,omitempty"`
+			Reason    string               `json:"reason,omitempty"`
+			Overrides []string             `json:"overrides,omitempty"`
+			Settings  *config.TownSettings `json:"settings"`
+		}{sel.Host, sel.Name, sel.Reason, sel.Overrides, settings})
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Machine:"), sel.Host)
+	switch {
+	case sel.Name != "":
+		fmt.Printf("%s %s (%s)\n", style.Bold.Render("Profile:"), sel.Name, sel.Reason)
+	case sel.Reason != "":
+		fmt.Printf("%s none (%s)\n", style.Bold.Render("Profile:"), sel.Reason)
+	default:
+		fmt.Printf("%s none %s\n", style.Bold.Render("Profile:"),
+			style.Dim.Render(fmt.Sprintf("(no profile in %s matches)", relToTown(townRoot, config.MachineProfilesDir(townRoot)))))
+	}
+	if sel.Name != "" {
+		if len(sel.Overrides) == 0 {
+			fmt.Printf("  %s\n", style.Dim.Render("profile changes nothing"))
+		}
+		for _, key := range sel.Overrides {
+			fmt.Printf("  %s %s\n", key, style.Dim.Render("← "+relToTown(townRoot, sel.Path)))
+		}
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n%s\n", style.Bold.Render("Effective settings:"), data)
+	return nil
+}
+
+// relToTown shortens path for display when it lies inside the town.
+func relToTown(townRoot, path string) string {
+	if rel, err := filepath.Rel(townRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
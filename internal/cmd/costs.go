@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,8 +14,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
 	"github.com/cursorworkshop/cursor-gastown/internal/style"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
 )
 
 var (
@@ -27,6 +30,7 @@ var (
 	// Record subcommand flags
 	recordSession  string
 	recordWorkItem string
+	recordKey      string
 )
 
 var costsCmd = &cobra.Command{
@@ -43,7 +47,11 @@ Examples:
   gt costs --week       # This week's total
   gt costs --by-role    # Breakdown by role (polecat, witness, etc.)
   gt costs --by-rig     # Breakdown by rig
-  gt costs --json       # Output as JSON`,
+  gt costs --by-cost-center  # Breakdown by chargeback cost center
+  gt costs --json       # Output as JSON
+  gt costs efficiency   # Cost per merge, per completed item, idle spend
+  gt costs close --month 2025-01  # Freeze a month for chargeback
+  gt costs reconcile    # Compare recorded costs with provider billing`,
 	RunE: runCosts,
 }
 
@@ -56,9 +64,14 @@ This command is intended to be called from a Cursor Stop hook.
 It captures the final cost from the tmux session and creates an event
 bead with the cost data.
 
+Each recorded event carries a unique event ID. Pass --idempotency-key with a
+stable value (e.g. the agent session ID) when the hook may be retried;
+'gt costs' counts events with the same key once.
+
 Examples:
   gt costs record --session gt-gastown-toast
-  gt costs record --session gt-gastown-toast --work-item gt-abc123`,
+  gt costs record --session gt-gastown-toast --work-item gt-abc123
+  gt costs record --idempotency-key "session_end:$CURSOR_SESSION_ID"`,
 	RunE: runCostsRecord,
 }
 
@@ -74,6 +87,7 @@ func init() {
 	costsCmd.AddCommand(costsRecordCmd)
 	costsRecordCmd.Flags().StringVar(&recordSession, "session", "", "Tmux session name to record")
 	costsRecordCmd.Flags().StringVar(&recordWorkItem, "work-item", "", "Work item ID (bead) for attribution")
+	costsRecordCmd.Flags().StringVar(&recordKey, "idempotency-key", "", "Stable key identifying this recording; duplicates are counted once")
 }
 
 // SessionCost represents cost info for a single session.
@@ -96,15 +110,20 @@ type CostEntry struct {
 	StartedAt time.Time `json:"started_at"`
 	EndedAt   time.Time `json:"ended_at"`
 	WorkItem  string    `json:"work_item,omitempty"`
+	Model     string    `json:"model,omitempty"`
+
+	// CostCenter is set for entries from closed months, frozen at close.
+	CostCenter string `json:"cost_center,omitempty"`
 }
 
 // CostsOutput is the JSON output structure.
 type CostsOutput struct {
-	Sessions []SessionCost      `json:"sessions,omitempty"`
-	Total    float64            `json:"total_usd"`
-	ByRole   map[string]float64 `json:"by_role,omitempty"`
-	ByRig    map[string]float64 `json:"by_rig,omitempty"`
-	Period   string             `json:"period,omitempty"`
+	Sessions     []SessionCost      `json:"sessions,omitempty"`
+	Total        float64            `json:"total_usd"`
+	ByRole       map[string]float64 `json:"by_role,omitempty"`
+	ByRig        map[string]float64 `json:"by_rig,omitempty"`
+	ByCostCenter map[string]float64 `json:"by_cost_center,omitempty"`
+	Period       string             `json:"period,omitempty"`
 }
 
 // costRegex matches cost patterns like "$1.23" or "$12.34"
@@ -112,7 +131,7 @@ var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
 func runCosts(cmd *cobra.Command, args []string) error {
 	// If querying ledger, use ledger functions
-	if costsToday || costsWeek || costsByRole || costsByRig {
+	if costsToday || costsWeek || costsByRole || costsByRig || costsByCostCenter {
 		return runCostsFromLedger()
 	}
 
@@ -192,32 +211,21 @@ func runCostsFromLedger() error {
 	}
 
 	// Filter entries by time period
-	var filtered []CostEntry
-	now := time.Now()
-
-	for _, entry := range entries {
-		if costsToday {
-			// Today: same day
-			if entry.EndedAt.Year() == now.Year() &&
-				entry.EndedAt.YearDay() == now.YearDay() {
-				filtered = append(filtered, entry)
-			}
-		} else if costsWeek {
-			// This week: within 7 days
-			weekAgo := now.AddDate(0, 0, -7)
-			if entry.EndedAt.After(weekAgo) {
-				filtered = append(filtered, entry)
-			}
-		} else {
-			// No time filter
-			filtered = append(filtered, entry)
-		}
-	}
+	filtered := filterCostPeriod(entries, time.Now())
 
 	// Calculate totals
 	var total float64
 	byRole := make(map[string]float64)
 	byRig := make(map[string]float64)
+	byCostCenter := make(map[string]float64)
+	var costCenter func(rig string) string
+	if costsByCostCenter {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		costCenter = costCenterResolver(townRoot)
+	}
 
 	for _, entry := range filtered {
 		total += entry.CostUSD
@@ -225,6 +233,13 @@ func runCostsFromLedger() error {
 		if entry.Rig != "" {
 			byRig[entry.Rig] += entry.CostUSD
 		}
+		if costCenter != nil {
+			center := entry.CostCenter
+			if center == "" {
+				center = costCenter(entry.Rig)
+			}
+			byCostCenter[center] += entry.CostUSD
+		}
 	}
 
 	// Build output
@@ -238,14 +253,12 @@ func runCostsFromLedger() error {
 	if costsByRig {
 		output.ByRig = byRig
 	}
-
-	// Set period label
-	if costsToday {
-		output.Period = "today"
-	} else if costsWeek {
-		output.Period = "this week"
+	if costsByCostCenter {
+		output.ByCostCenter = byCostCenter
 	}
 
+	output.Period = costPeriodLabel()
+
 	if costsJSON {
 		return outputCostsJSON(output)
 	}
@@ -253,6 +266,41 @@ func runCostsFromLedger() error {
 	return outputLedgerHuman(output, filtered)
 }
 
+// filterCostPeriod keeps the entries in the --today or --week period.
+func filterCostPeriod(entries []CostEntry, now time.Time) []CostEntry {
+	var filtered []CostEntry
+	for _, entry := range entries {
+		if inCostPeriod(entry, now) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// inCostPeriod reports whether an entry is in the --today or --week period.
+func inCostPeriod(entry CostEntry, now time.Time) bool {
+	if costsToday {
+		// Today: same day
+		return entry.EndedAt.Year() == now.Year() &&
+			entry.EndedAt.YearDay() == now.YearDay()
+	} else if costsWeek {
+		// This week: within 7 days
+		return entry.EndedAt.After(now.AddDate(0, 0, -7))
+	}
+	// No time filter
+	return true
+}
+
+// costPeriodLabel describes the --today or --week period ("" for all time).
+func costPeriodLabel() string {
+	if costsToday {
+		return "today"
+	} else if costsWeek {
+		return "this week"
+	}
+	return ""
+}
+
 // SessionEvent represents a session.ended event from beads.
 type SessionEvent struct {
 	ID        string    `json:"id"`
@@ -267,12 +315,15 @@ type SessionEvent struct {
 
 // SessionPayload represents the JSON payload of a session event.
 type SessionPayload struct {
-	CostUSD   float64 `json:"cost_usd"`
-	SessionID string  `json:"session_id"`
-	Role      string  `json:"role"`
-	Rig       string  `json:"rig"`
-	Worker    string  `json:"worker"`
-	EndedAt   string  `json:"ended_at"`
+	CostUSD        float64 `json:"cost_usd"`
+	SessionID      string  `json:"session_id"`
+	Role           string  `json:"role"`
+	Rig            string  `json:"rig"`
+	Worker         string  `json:"worker"`
+	EndedAt        string  `json:"ended_at"`
+	EventID        string  `json:"event_id,omitempty"`
+	IdempotencyKey string  `json:"idempotency_key,omitempty"`
+	Model          string  `json:"model,omitempty"`
 }
 
 // EventListItem represents an event from bd list (minimal fields).
@@ -280,8 +331,32 @@ type EventListItem struct {
 	ID string `json:"id"`
 }
 
-// querySessionEvents queries beads for session.ended events and converts them to CostEntry.
+// errNoEventLedger means bd could not list events (e.g. no beads database).
+var errNoEventLedger = errors.New("no event ledger")
+
+// querySessionEvents queries beads for session.ended events and converts
+// them to CostEntry. Entries in closed months come from their frozen
+// ledgers instead (see gt costs close).
 func querySessionEvents() ([]CostEntry, error) {
+	events, err := querySessionEventBeads()
+	if errors.Is(err, errNoEventLedger) {
+		events, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := sessionCostEntries(events)
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return entries, nil
+	}
+	return withClosedLedgers(townRoot, entries)
+}
+
+// querySessionEventBeads returns every event bead with its full details.
+// It returns errNoEventLedger if bd cannot list events.
+func querySessionEventBeads() ([]SessionEvent, error) {
 	// Step 1: Get list of event IDs
 	listArgs := []string{
 		"list",
@@ -294,8 +369,7 @@ func querySessionEvents() ([]CostEntry, error) {
 	listCmd := exec.Command("bd", listArgs...)
 	listOutput, err := listCmd.Output()
 	if err != nil {
-		// If bd fails (e.g., no beads database), return empty list
-		return nil, nil
+		return nil, errNoEventLedger
 	}
 
 	var listItems []EventListItem
@@ -325,7 +399,15 @@ func querySessionEvents() ([]CostEntry, error) {
 		return nil, fmt.Errorf("parsing event details: %w", err)
 	}
 
+	return events, nil
+}
+
+// sessionCostEntries converts session.ended events to cost entries.
+// Retried recordings of the same session end (same idempotency key or event
+// ID) are counted once.
+func sessionCostEntries(events []SessionEvent) []CostEntry {
 	var entries []CostEntry
+	seen := make(map[string]bool)
 	for _, event := range events {
 		// Filter for session.ended events only
 		if event.EventKind != "session.ended" {
@@ -340,13 +422,17 @@ func querySessionEvents() ([]CostEntry, error) {
 			}
 		}
 
-		// Parse ended_at from payload, fall back to created_at
-		endedAt := event.CreatedAt
-		if payload.EndedAt != "" {
-			if parsed, err := time.Parse(time.RFC3339, payload.EndedAt); err == nil {
-				endedAt = parsed
-			}
+		key := payload.IdempotencyKey
+		if key == "" {
+			key = payload.EventID
+		}
+		if key == "" {
+			key = event.ID
+		}
+		if seen[key] {
+			continue
 		}
+		seen[key] = true
 
 		entries = append(entries, CostEntry{
 			SessionID: payload.SessionID,
@@ -354,12 +440,13 @@ func querySessionEvents() ([]CostEntry, error) {
 			Rig:       payload.Rig,
 			Worker:    payload.Worker,
 			CostUSD:   payload.CostUSD,
-			EndedAt:   endedAt,
+			EndedAt:   sessionEventEndedAt(event),
 			WorkItem:  event.Target,
+			Model:     payload.Model,
 		})
 	}
 
-	return entries, nil
+	return entries
 }
 
 // parseSessionName extracts role, rig, and worker from a session name.
@@ -506,6 +593,14 @@ func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 		}
 	}
 
+	// By cost center breakdown
+	if len(output.ByCostCenter) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("By Cost Center:"))
+		for center, cost := range output.ByCostCenter {
+			fmt.Printf("  %-24s $%.2f\n", center, cost)
+		}
+	}
+
 	// Session count
 	fmt.Printf("\n%s %d sessions\n", style.Dim.Render("Entries:"), len(entries))
 
@@ -550,6 +645,10 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	// Build agent path for actor field
 	agentPath := buildAgentPath(role, rig, worker)
 
+	// The Stop hook runs this at the end of every agent turn; note it so
+	// gt doctor --fix can tell an idle session from one mid-task.
+	_ = events.LogAudit(events.TypeAgentStop, agentPath, events.AgentStopPayload(session))
+
 	// Build event title
 	title := fmt.Sprintf("Session ended: %s", session)
 	if recordWorkItem != "" {
@@ -562,6 +661,10 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		"session_id": session,
 		"role":       role,
 		"ended_at":   time.Now().Format(time.RFC3339),
+		"event_id":   events.NewID(),
+	}
+	if recordKey != "" {
+		payload["idempotency_key"] = recordKey
 	}
 	if rig != "" {
 		payload["rig"] = rig
@@ -569,6 +672,9 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	if worker != "" {
 		payload["worker"] = worker
 	}
+	if model := os.Getenv("CURSOR_MODEL"); model != "" {
+		payload["model"] = model
+	}
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshaling payload: %w", err)
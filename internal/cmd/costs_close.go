@@ -0,0 +1,435 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/chargeback"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var (
+	costsByCostCenter bool
+	costsCloseMonth   string
+	costsCloseDryRun  bool
+	costsVerifyMonth  string
+	costsTagDefault   bool
+	costsTagClear     bool
+)
+
+var costsCloseCmd = &cobra.Command{
+	Use:   "close",
+	Short: "Close a month: freeze its ledger and sign per-cost-center summaries",
+	Long: `Close a finished month for chargeback.
+
+Closing a month:
+  1. Freezes its ledger: every session cost that ended in the month (UTC)
+     is written to costs/<month>/ledger.json with its cost center.
+  2. Writes a summary per cost center (costs/<month>/summary-<center>.json),
+     signed with the town's ed25519 key. The public key is published in
+     costs/signing.pub; check summaries with 'gt costs verify'.
+  3. Archives the month's session.ended event beads to
+     costs/<month>/events.jsonl and removes them from beads.
+
+After a close, 'gt costs' reads the month from its frozen ledger, so late or
+retried recordings no longer change it. A month can only be closed once, and
+only after it has ended.
+
+Cost centers come from each rig's cost_center setting, falling back to the
+town's; see 'gt costs tag'. Untagged spend is billed to "unassigned".
+
+Examples:
+  gt costs close --month 2025-01
+  gt costs close --month 2025-01 --dry-run   # Show the summaries, change nothing`,
+	Args: cobra.NoArgs,
+	RunE: runCostsClose,
+}
+
+var costsVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify a closed month's signed summaries",
+	Long: `Check that every cost center summary of a closed month is signed by the
+town's key (costs/signing.pub) and matches the frozen ledger.
+
+Examples:
+  gt costs verify --month 2025-01`,
+	Args: cobra.NoArgs,
+	RunE: runCostsVerify,
+}
+
+var costsTagCmd = &cobra.Command{
+	Use:   "tag [rig] [cost-center]",
+	Short: "Show or set the cost center a rig's spend is charged to",
+	Long: `Show or set chargeback cost centers.
+
+A rig's cost center is stored in <rig>/settings/config.json (cost_center).
+The town default, in settings/config.json, covers the mayor, the deacon, and
+untagged rigs. Cost centers are plain names: letters, digits, '.', '_', '-'.
+
+Examples:
+  gt costs tag                           # Show all tags
+  gt costs tag gastown eng-platform      # Charge gastown to eng-platform
+  gt costs tag gastown --clear           # Use the town default again
+  gt costs tag --default shared-infra    # Set the town default`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runCostsTag,
+}
+
+func init() {
+	costsCmd.Flags().BoolVar(&costsByCostCenter, "by-cost-center", false, "Show breakdown by cost center")
+
+	costsCmd.AddCommand(costsCloseCmd)
+	costsCloseCmd.Flags().StringVar(&costsCloseMonth, "month", "", "Month to close (YYYY-MM)")
+	costsCloseCmd.Flags().BoolVar(&costsCloseDryRun, "dry-run", false, "Show what would be closed without changing anything")
+	_ = costsCloseCmd.MarkFlagRequired("month")
+
+	costsCmd.AddCommand(costsVerifyCmd)
+	costsVerifyCmd.Flags().StringVar(&costsVerifyMonth, "month", "", "Closed month to verify (YYYY-MM)")
+	_ = costsVerifyCmd.MarkFlagRequired("month")
+
+	costsCmd.AddCommand(costsTagCmd)
+	costsTagCmd.Flags().BoolVar(&costsTagDefault, "default", false, "Set the town default instead of a rig's tag")
+	costsTagCmd.Flags().BoolVar(&costsTagClear, "clear", false, "Remove the tag")
+}
+
+func runCostsClose(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	start, end, err := chargeback.ParseMonth(costsCloseMonth)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if now.Before(end) {
+		return fmt.Errorf("%s has not ended yet; months can be closed from %s", costsCloseMonth, end.Format("2006-01-02"))
+	}
+	if chargeback.IsClosed(townRoot, costsCloseMonth) {
+		return fmt.Errorf("%w: %s (see %s)", chargeback.ErrClosed, costsCloseMonth, chargeback.MonthDir(townRoot, costsCloseMonth))
+	}
+
+	// Unlike reporting, closing must not mistake an unreadable ledger for
+	// an empty month.
+	beadEvents, err := querySessionEventBeads()
+	if errors.Is(err, errNoEventLedger) {
+		return fmt.Errorf("cannot read session events from beads (is bd installed and the town initialized?)")
+	}
+	if err != nil {
+		return fmt.Errorf("querying session events: %w", err)
+	}
+
+	monthEvents := sessionEventsInMonth(beadEvents, start, end)
+	entries := sessionCostEntries(monthEvents)
+	costCenter := costCenterResolver(townRoot)
+
+	closedBy, err := detectAgentIdentity()
+	if err != nil || closedBy == "" {
+		closedBy = "overseer"
+	}
+	ledger := &chargeback.Ledger{
+		Month:    costsCloseMonth,
+		ClosedAt: now.UTC().Truncate(time.Second),
+		ClosedBy: closedBy,
+		Entries:  make([]chargeback.Entry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		ledger.Entries = append(ledger.Entries, chargeback.Entry{
+			SessionID:  e.SessionID,
+			Role:       e.Role,
+			Rig:        e.Rig,
+			Worker:     e.Worker,
+			CostUSD:    e.CostUSD,
+			EndedAt:    e.EndedAt.UTC(),
+			WorkItem:   e.WorkItem,
+			Model:      e.Model,
+			CostCenter: costCenter(e.Rig),
+		})
+		ledger.TotalUSD += e.CostUSD
+	}
+	sort.SliceStable(ledger.Entries, func(i, j int) bool { return ledger.Entries[i].EndedAt.Before(ledger.Entries[j].EndedAt) })
+
+	if costsCloseDryRun {
+		fmt.Printf("%s Would close %s: %d session(s), %d event bead(s) to archive\n\n",
+			style.Bold.Render("[dry-run]"), costsCloseMonth, len(ledger.Entries), len(monthEvents))
+		printCostCenterSummaries(chargeback.Summarize(ledger), ledger.TotalUSD)
+		return nil
+	}
+
+	archived := make([]json.RawMessage, 0, len(monthEvents))
+	ids := make([]string, 0, len(monthEvents))
+	for _, ev := range monthEvents {
+		raw, err := json.Marshal(ev)
+		if err != nil {
+			return fmt.Errorf("encoding event %s: %w", ev.ID, err)
+		}
+		archived = append(archived, raw)
+		ids = append(ids, ev.ID)
+	}
+
+	summaries, err := chargeback.Close(townRoot, ledger, archived)
+	if err != nil {
+		return fmt.Errorf("closing %s: %w", costsCloseMonth, err)
+	}
+
+	fmt.Printf("%s Closed %s: %d session(s), $%.2f\n\n", style.SuccessPrefix, costsCloseMonth, len(ledger.Entries), ledger.TotalUSD)
+	printCostCenterSummaries(summaries, ledger.TotalUSD)
+	fmt.Printf("\n%s %s\n", style.Dim.Render("Ledger:"), chargeback.MonthDir(townRoot, costsCloseMonth))
+
+	// The frozen ledger already supersedes these beads, so a failed removal
+	// only leaves duplicates that reporting ignores.
+	if len(ids) > 0 {
+		deleteArgs := append([]string{"delete"}, ids...)
+		deleteArgs = append(deleteArgs, "--hard", "--force")
+		if out, err := exec.Command("bd", deleteArgs...).CombinedOutput(); err != nil { //nolint:gosec // G204: ids come from bd
+			fmt.Fprintf(os.Stderr, "warning: archived events were not removed from beads: %v\n%s", err, out)
+		} else {
+			fmt.Printf("%s %d event bead(s) archived\n", style.Dim.Render("Events:"), len(ids))
+		}
+	}
+	return nil
+}
+
+func runCostsVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if _, _, err := chargeback.ParseMonth(costsVerifyMonth); err != nil {
+		return err
+	}
+
+	summaries, err := chargeback.Verify(townRoot, costsVerifyMonth)
+	if err != nil {
+		return err
+	}
+	var total float64
+	for _, s := range summaries {
+		total += s.TotalUSD
+	}
+	fmt.Printf("%s %s: %d cost center summary(ies) verified\n\n", style.SuccessPrefix, costsVerifyMonth, len(summaries))
+	printCostCenterSummaries(summaries, total)
+	return nil
+}
+
+func runCostsTag(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if costsTagDefault {
+		if len(args) > 1 || (len(args) == 0) != costsTagClear {
+			return fmt.Errorf("usage: gt costs tag --default <cost-center> | --default --clear")
+		}
+		path := config.TownSettingsPath(townRoot)
+		settings, err := config.LoadOrCreateBaseTownSettings(path)
+		if err != nil {
+			return fmt.Errorf("loading town settings: %w", err)
+		}
+		settings.CostCenter = ""
+		if len(args) == 1 {
+			settings.CostCenter = args[0]
+		}
+		if err := config.SaveTownSettings(path, settings); err != nil {
+			return err
+		}
+		fmt.Printf("%s Town default cost center: %s\n", style.SuccessPrefix, costCenterLabel(settings.CostCenter))
+		return nil
+	}
+
+	switch {
+	case len(args) == 0:
+		if costsTagClear {
+			return fmt.Errorf("--clear needs a rig (or --default)")
+		}
+		return printCostCenterTags(townRoot)
+	case len(args) == 1 && !costsTagClear:
+		return fmt.Errorf("missing cost center for %s (or pass --clear)", args[0])
+	case len(args) == 2 && costsTagClear:
+		return fmt.Errorf("--clear does not take a cost center")
+	}
+
+	rigName := args[0]
+	if _, _, err := getRig(rigName); err != nil {
+		return err
+	}
+	path := config.RigSettingsPath(filepath.Join(townRoot, rigName))
+	settings, err := config.LoadRigSettings(path)
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			return fmt.Errorf("loading rig settings: %w", err)
+		}
+		settings = config.NewRigSettings()
+	}
+	settings.CostCenter = ""
+	if len(args) == 2 {
+		settings.CostCenter = args[1]
+	}
+	if err := config.SaveRigSettings(path, settings); err != nil {
+		return err
+	}
+
+	if settings.CostCenter == "" {
+		fmt.Printf("%s %s uses the town default cost center\n", style.SuccessPrefix, rigName)
+	} else {
+		fmt.Printf("%s %s is charged to %s\n", style.SuccessPrefix, rigName, settings.CostCenter)
+	}
+	return nil
+}
+
+// printCostCenterTags lists the town default and every rig's cost center.
+func printCostCenterTags(townRoot string) error {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	fmt.Printf("%-20s %s\n", "Town default", costCenterLabel(settings.CostCenter))
+
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil // No rigs yet
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	costCenter := costCenterResolver(townRoot)
+	for _, name := range names {
+		center := costCenter(name)
+		if rs, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, name))); err != nil || rs.CostCenter == "" {
+			center += style.Dim.Render(" (town default)")
+		}
+		fmt.Printf("%-20s %s\n", name, center)
+	}
+	return nil
+}
+
+// costCenterLabel renders an empty cost center as unassigned.
+func costCenterLabel(center string) string {
+	if center == "" {
+		return chargeback.Unassigned
+	}
+	return center
+}
+
+// costCenterResolver returns a function mapping a rig name ("" for
+// town-level agents) to the cost center its spend is billed to.
+func costCenterResolver(townRoot string) func(rig string) string {
+	townDefault := ""
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		townDefault = settings.CostCenter
+	}
+	cache := make(map[string]string)
+	return func(rig string) string {
+		if center, ok := cache[rig]; ok {
+			return center
+		}
+		center := townDefault
+		if rig != "" {
+			if rs, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rig))); err == nil && rs.CostCenter != "" {
+				center = rs.CostCenter
+			}
+		}
+		center = costCenterLabel(center)
+		cache[rig] = center
+		return center
+	}
+}
+
+// sessionEventsInMonth returns the session.ended events whose session ended
+// in [start, end), including retried duplicates so all are archived.
+func sessionEventsInMonth(evs []SessionEvent, start, end time.Time) []SessionEvent {
+	var inMonth []SessionEvent
+	for _, ev := range evs {
+		if ev.EventKind != "session.ended" {
+			continue
+		}
+		endedAt := sessionEventEndedAt(ev)
+		if !endedAt.Before(start) && endedAt.Before(end) {
+			inMonth = append(inMonth, ev)
+		}
+	}
+	return inMonth
+}
+
+// sessionEventEndedAt returns when an event's session ended: the payload's
+// ended_at, falling back to the bead's creation time.
+func sessionEventEndedAt(ev SessionEvent) time.Time {
+	var payload SessionPayload
+	if ev.Payload != "" && json.Unmarshal([]byte(ev.Payload), &payload) == nil && payload.EndedAt != "" {
+		if parsed, err := time.Parse(time.RFC3339, payload.EndedAt); err == nil {
+			return parsed
+		}
+	}
+	return ev.CreatedAt
+}
+
+// withClosedLedgers replaces entries in closed months with the months'
+// frozen ledgers.
+func withClosedLedgers(townRoot string, entries []CostEntry) ([]CostEntry, error) {
+	months, err := chargeback.ClosedMonths(townRoot)
+	if err != nil || len(months) == 0 {
+		return entries, nil
+	}
+	closed := make(map[string]bool, len(months))
+	for _, m := range months {
+		closed[m] = true
+	}
+
+	var result []CostEntry
+	for _, e := range entries {
+		if !closed[chargeback.MonthOf(e.EndedAt)] {
+			result = append(result, e)
+		}
+	}
+	for _, m := range months {
+		ledger, err := chargeback.LoadLedger(townRoot, m)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range ledger.Entries {
+			result = append(result, CostEntry{
+				SessionID:  e.SessionID,
+				Role:       e.Role,
+				Rig:        e.Rig,
+				Worker:     e.Worker,
+				CostUSD:    e.CostUSD,
+				EndedAt:    e.EndedAt,
+				WorkItem:   e.WorkItem,
+				Model:      e.Model,
+				CostCenter: e.CostCenter,
+			})
+		}
+	}
+	return result, nil
+}
+
+// printCostCenterSummaries prints one line per cost center.
+func printCostCenterSummaries(summaries []*chargeback.Summary, total float64) {
+	fmt.Printf("%-24s %8s %12s  %s\n", "Cost Center", "Sessions", "Cost", "Rigs")
+	fmt.Println(strings.Repeat("─", 70))
+	for _, s := range summaries {
+		rigs := make([]string, 0, len(s.ByRig))
+		for rig := range s.ByRig {
+			rigs = append(rigs, rig)
+		}
+		sort.Strings(rigs)
+		fmt.Printf("%-24s %8d %12s  %s\n", s.CostCenter, s.Sessions, fmt.Sprintf("$%.2f", s.TotalUSD), strings.Join(rigs, ", "))
+	}
+	fmt.Println(strings.Repeat("─", 70))
+	fmt.Printf("%-24s %8s %12s\n", "Total", "", fmt.Sprintf("$%.2f", total))
+}
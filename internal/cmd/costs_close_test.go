@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/chargeback"
+)
+
+func TestSessionEventsInMonth(t *testing.T) {
+	created := time.Date(2025, 2, 1, 0, 5, 0, 0, time.UTC)
+	evs := []SessionEvent{
+		{ID: "e1", EventKind: "session.ended", CreatedAt: created, Payload: `{"ended_at":"2025-01-31T23:59:00Z","idempotency_key":"k"}`},
+		{ID: "e2", EventKind: "session.ended", CreatedAt: created, Payload: `{"ended_at":"2025-01-31T23:59:00Z","idempotency_key":"k"}`},
+		{ID: "e3", EventKind: "session.ended", CreatedAt: created},
+		{ID: "e4", EventKind: "merged", CreatedAt: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	start, end, _ := chargeback.ParseMonth("2025-01")
+	got := sessionEventsInMonth(evs, start, end)
+	if len(got) != 2 || got[0].ID != "e1" || got[1].ID != "e2" {
+		t.Errorf("in month = %+v, want e1 and its retry e2", got)
+	}
+	if entries := sessionCostEntries(got); len(entries) != 1 {
+		t.Errorf("entries = %d, want the retry counted once", len(entries))
+	}
+}
+
+func TestWithClosedLedgers(t *testing.T) {
+	townRoot := t.TempDir()
+	jan := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+	ledger := &chargeback.Ledger{
+		Month:   "2025-01",
+		Entries: []chargeback.Entry{{SessionID: "gt-gastown-toast", Role: "polecat", Rig: "gastown", CostUSD: 3, EndedAt: jan, CostCenter: "eng"}},
+	}
+	if _, err := chargeback.Close(townRoot, ledger, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	live := []CostEntry{
+		{SessionID: "late-retry", Role: "polecat", CostUSD: 9, EndedAt: jan},
+		{SessionID: "gt-gastown-nux", Role: "polecat", CostUSD: 1, EndedAt: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	got, err := withClosedLedgers(townRoot, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]CostEntry{}
+	for _, e := range got {
+		ids[e.SessionID] = e
+	}
+	if len(got) != 2 || ids["gt-gastown-toast"].CostCenter != "eng" || ids["gt-gastown-nux"].CostUSD != 1 {
+		t.Errorf("entries = %+v, want the frozen January entry and February's live one", got)
+	}
+}
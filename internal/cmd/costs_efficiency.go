@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var costsByModel bool
+
+var costsEfficiencyCmd = &cobra.Command{
+	Use:   "efficiency",
+	Short: "Show cost per merge, per completed work item, and idle spend",
+	Long: `Show which roles (or models) earn their keep.
+
+Each recorded session (see 'gt costs record') is matched against the activity
+log (.events.jsonl) for the period since the agent's previous session ended:
+
+  Merges      merged events by the agent, or authored by it (polecats)
+  Done        work items the agent completed with 'gt done'
+  Idle        sessions with no work at all: no merge, done, sling, or hook
+              events and no work item, only patrols and heartbeats
+
+Cost per merge and cost per completed item divide the group's total cost,
+idle sessions included, by its merges or completed items. Score averages
+the operator ratings of the group's sessions (see 'gt feedback').
+
+Examples:
+  gt costs efficiency            # By role, all recorded sessions
+  gt costs efficiency --week     # This week only
+  gt costs efficiency --by-model # By model (sessions record $CURSOR_MODEL)
+  gt costs efficiency --json     # Output as JSON`,
+	RunE: runCostsEfficiency,
+}
+
+func init() {
+	costsCmd.AddCommand(costsEfficiencyCmd)
+	costsEfficiencyCmd.Flags().BoolVar(&costsJSON, "json", false, "Output as JSON")
+	costsEfficiencyCmd.Flags().BoolVar(&costsToday, "today", false, "Only sessions that ended today")
+	costsEfficiencyCmd.Flags().BoolVar(&costsWeek, "week", false, "Only sessions that ended this week")
+	costsEfficiencyCmd.Flags().BoolVar(&costsByModel, "by-model", false, "Group by model instead of role")
+}
+
+// EfficiencyRow holds the efficiency metrics for one role or model.
+type EfficiencyRow struct {
+	Group            string   `json:"group"`
+	Sessions         int      `json:"sessions"`
+	CostUSD          float64  `json:"cost_usd"`
+	Merges           int      `json:"merges"`
+	Completed        int      `json:"completed"`
+	IdleSessions     int      `json:"idle_sessions"`
+	IdleCostUSD      float64  `json:"idle_cost_usd"`
+	CostPerMerge     *float64 `json:"cost_per_merge_usd,omitempty"`
+	CostPerCompleted *float64 `json:"cost_per_completed_usd,omitempty"`
+	Rated            int      `json:"rated"` // Sessions with a gt feedback rating
+	AvgScore         *float64 `json:"avg_score,omitempty"`
+
+	scoreSum float64
+}
+
+// EfficiencyOutput is the JSON output of gt costs efficiency.
+type EfficiencyOutput struct {
+	Period  string          `json:"period,omitempty"`
+	GroupBy string          `json:"group_by"` // role or model
+	Rows    []EfficiencyRow `json:"rows"`
+	Total   EfficiencyRow   `json:"total"`
+}
+
+// workEventTypes are the events that show an agent working rather than
+// patrolling or idling.
+var workEventTypes = map[string]bool{
+	events.TypeSling:        true,
+	events.TypeHook:         true,
+	events.TypeDone:         true,
+	events.TypeMergeStarted: true,
+	events.TypeMerged:       true,
+	events.TypeMergeFailed:  true,
+}
+
+// timedEvent is an activity event with its parsed timestamp.
+type timedEvent struct {
+	events.Event
+	at time.Time
+}
+
+func runCostsEfficiency(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	entries, err := querySessionEvents()
+	if err != nil {
+		return fmt.Errorf("querying session events: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println(style.Dim.Render("No session events found. Costs are recorded when sessions end."))
+		return nil
+	}
+
+	activity, err := readActivityEvents(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading activity log: %w", err)
+	}
+
+	// Attribute against every session so each window starts where the
+	// agent's previous session ended, then keep the period's sessions.
+	now := time.Now()
+	inPeriod := func(e CostEntry) bool { return inCostPeriod(e, now) }
+	output := computeEfficiency(entries, activity, costsByModel, inPeriod)
+	output.Period = costPeriodLabel()
+
+	if costsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(output)
+	}
+	return outputEfficiencyHuman(output)
+}
+
+// readActivityEvents reads the town's raw activity log, skipping malformed lines.
+func readActivityEvents(townRoot string) ([]timedEvent, error) {
+	raw, err := events.ReadEvents(filepath.Join(townRoot, events.EventsFile), nil)
+	if err != nil {
+		return nil, err
+	}
+	evs := make([]timedEvent, 0, len(raw))
+	for _, e := range raw {
+		if at, ok := e.Time(); ok {
+			evs = append(evs, timedEvent{Event: events.WithBodies(townRoot, e), at: at})
+		}
+	}
+	return evs, nil
+}
+
+// computeEfficiency attributes merges and completed work to sessions and
+// aggregates the sessions that are inPeriod by role (or model). all is every
+// recorded session; it determines where each session's window starts.
+func computeEfficiency(all []CostEntry, activity []timedEvent, byModel bool, inPeriod func(CostEntry) bool) EfficiencyOutput {
+	sessions := append([]CostEntry(nil), all...)
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].EndedAt.Before(sessions[j].EndedAt) })
+
+	output := EfficiencyOutput{GroupBy: "role", Total: EfficiencyRow{Group: "total"}}
+	if byModel {
+		output.GroupBy = "model"
+	}
+	rows := make(map[string]*EfficiencyRow)
+	merges := make(map[int]bool)    // activity indices counted in the total
+	completed := make(map[int]bool) // activity indices counted in the total
+	lastEnd := make(map[string]time.Time)
+	starts := make([]time.Time, len(sessions))
+	for i, s := range sessions {
+		agent := agentKey(buildAgentPath(s.Role, s.Rig, s.Worker))
+		starts[i] = s.StartedAt
+		if starts[i].IsZero() {
+			starts[i] = lastEnd[agent]
+		}
+		lastEnd[agent] = s.EndedAt
+	}
+	scores := sessionScores(sessions, starts, activity)
+
+	for si, s := range sessions {
+		if !inPeriod(s) {
+			continue
+		}
+		agent := agentKey(buildAgentPath(s.Role, s.Rig, s.Worker))
+		start := starts[si]
+
+		var sessionMerges, sessionCompleted int
+		working := s.WorkItem != ""
+		for i, ev := range activity {
+			if !ev.at.After(start) || ev.at.After(s.EndedAt) {
+				continue
+			}
+			own := agentKey(ev.Actor) == agent
+			switch {
+			case ev.Type == events.TypeMerged && (own || authoredMerge(s, ev.Event)):
+				sessionMerges++
+				merges[i] = true
+			case ev.Type == events.TypeDone && own:
+				sessionCompleted++
+				completed[i] = true
+			}
+			if own && workEventTypes[ev.Type] {
+				working = true
+			}
+		}
+		idle := !working && sessionMerges == 0 && sessionCompleted == 0
+
+		group := s.Role
+		if byModel {
+			group = s.Model
+		}
+		if group == "" {
+			group = "unknown"
+		}
+		row := rows[group]
+		if row == nil {
+			row = &EfficiencyRow{Group: group}
+			rows[group] = row
+		}
+		for _, r := range []*EfficiencyRow{row, &output.Total} {
+			r.Sessions++
+			r.CostUSD += s.CostUSD
+			if idle {
+				r.IdleSessions++
+				r.IdleCostUSD += s.CostUSD
+			}
+			if score, ok := scores[si]; ok {
+				r.Rated++
+				r.scoreSum += score
+			}
+		}
+		row.Merges += sessionMerges
+		row.Completed += sessionCompleted
+	}
+	// Merges are credited to both the author and the refinery, so the
+	// total counts each merge once.
+	output.Total.Merges = len(merges)
+	output.Total.Completed = len(completed)
+
+	for _, row := range rows {
+		output.Rows = append(output.Rows, *row)
+	}
+	sort.Slice(output.Rows, func(i, j int) bool {
+		if output.Rows[i].CostUSD != output.Rows[j].CostUSD {
+			return output.Rows[i].CostUSD > output.Rows[j].CostUSD
+		}
+		return output.Rows[i].Group < output.Rows[j].Group
+	})
+	for i := range output.Rows {
+		output.Rows[i].setRatios()
+	}
+	output.Total.setRatios()
+	return output
+}
+
+// sessionScores assigns each feedback rating in activity to a session: the
+// one with the rated session name running when the rating was given, else
+// the last one that ended before it. sessions are sorted by end time and
+// starts holds each session's window start. A later rating of the same
+// session replaces an earlier one. Returns scores by session index.
+func sessionScores(sessions []CostEntry, starts []time.Time, activity []timedEvent) map[int]float64 {
+	scores := make(map[int]float64)
+	for _, ev := range activity {
+		if ev.Type != events.TypeFeedback {
+			continue
+		}
+		name, _ := ev.Payload["session"].(string)
+		score, ok := ev.Payload["score"].(float64)
+		if name == "" || !ok {
+			continue
+		}
+		rated := -1
+		for i, s := range sessions {
+			if s.SessionID != name {
+				continue
+			}
+			if s.EndedAt.Before(ev.at) {
+				rated = i
+				continue
+			}
+			if starts[i].IsZero() || starts[i].Before(ev.at) {
+				rated = i
+			}
+			break
+		}
+		if rated >= 0 {
+			scores[rated] = score
+		}
+	}
+	return scores
+}
+
+// setRatios computes cost per merge, per completed item, and the average
+// score when defined.
+func (r *EfficiencyRow) setRatios() {
+	if r.Merges > 0 {
+		v := r.CostUSD / float64(r.Merges)
+		r.CostPerMerge = &v
+	}
+	if r.Completed > 0 {
+		v := r.CostUSD / float64(r.Completed)
+		r.CostPerCompleted = &v
+	}
+	if r.Rated > 0 {
+		v := r.scoreSum / float64(r.Rated)
+		r.AvgScore = &v
+	}
+}
+
+// authoredMerge reports whether a merged event is for a polecat session's
+// work: the refinery of the session's rig merged the session's branch.
+func authoredMerge(s CostEntry, ev events.Event) bool {
+	if s.Role != constants.RolePolecat || s.Worker == "" {
+		return false
+	}
+	worker, _ := ev.Payload["worker"].(string)
+	rig, _, _ := strings.Cut(ev.Actor, "/")
+	return strings.EqualFold(worker, s.Worker) && rig == s.Rig
+}
+
+// agentKey normalizes an agent address for comparison: actors are logged as
+// "gastown/Toast", "gastown/polecats/toast", or "mayor/" depending on origin.
+func agentKey(addr string) string {
+	key := strings.ToLower(strings.Trim(addr, "/"))
+	return strings.Replace(key, "/polecats/", "/", 1)
+}
+
+func outputEfficiencyHuman(output EfficiencyOutput) error {
+	periodStr := ""
+	if output.Period != "" {
+		periodStr = fmt.Sprintf(" (%s)", output.Period)
+	}
+	fmt.Printf("\n%s Cost Efficiency%s\n\n", style.Bold.Render("📈"), periodStr)
+
+	header := "Role"
+	if output.GroupBy == "model" {
+		header = "Model"
+	}
+	fmt.Printf("%-14s %8s %10s %7s %9s %6s %9s %10s %6s %7s\n",
+		header, "Sessions", "Cost", "Merges", "$/Merge", "Done", "$/Done", "Idle $", "Idle%", "Score")
+	fmt.Println(strings.Repeat("─", 95))
+	for _, row := range output.Rows {
+		printEfficiencyRow(row)
+	}
+	fmt.Println(strings.Repeat("─", 95))
+	printEfficiencyRow(output.Total)
+
+	if output.Total.IdleSessions > 0 {
+		fmt.Printf("\n%s %d idle session(s) did no work (patrols and heartbeats only)\n",
+			style.Dim.Render("Idle:"), output.Total.IdleSessions)
+	}
+	return nil
+}
+
+func printEfficiencyRow(r EfficiencyRow) {
+	idlePct := 0.0
+	if r.CostUSD > 0 {
+		idlePct = 100 * r.IdleCostUSD / r.CostUSD
+	}
+	score := "-"
+	if r.AvgScore != nil {
+		score = fmt.Sprintf("%.1f (%d)", *r.AvgScore, r.Rated)
+	}
+	fmt.Printf("%-14s %8d %10s %7d %9s %6d %9s %10s %5.0f%% %7s\n",
+		r.Group, r.Sessions, fmt.Sprintf("$%.2f", r.CostUSD),
+		r.Merges, formatOptionalCost(r.CostPerMerge),
+		r.Completed, formatOptionalCost(r.CostPerCompleted),
+		fmt.Sprintf("$%.2f", r.IdleCostUSD), idlePct, score)
+}
+
+// formatOptionalCost formats a ratio, or "-" when it is undefined.
+func formatOptionalCost(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("$%.2f", *v)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestComputeEfficiency(t *testing.T) {
+	base := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+	ev := func(h int, typ, actor string, payload map[string]interface{}) timedEvent {
+		return timedEvent{Event: events.Event{Type: typ, Actor: actor, Payload: payload}, at: at(h)}
+	}
+
+	sessions := []CostEntry{
+		{Role: "polecat", Rig: "gp", Worker: "toast", CostUSD: 4, EndedAt: at(2), Model: "opus"},
+		{Role: "polecat", Rig: "gp", Worker: "toast", CostUSD: 2, EndedAt: at(6), Model: "opus"},
+		{Role: "refinery", Rig: "gp", CostUSD: 1, EndedAt: at(5), Model: "sonnet"},
+		{Role: "witness", Rig: "gp", CostUSD: 3, EndedAt: at(5), Model: "sonnet"},
+	}
+	activity := []timedEvent{
+		ev(1, events.TypeDone, "gp/Toast", events.DonePayload("gp-1", "polecat/toast")),
+		// Merged after the first polecat session ended: credited to the
+		// second one (its window) and to the refinery.
+		ev(3, events.TypeMerged, "gp/refinery", events.MergePayload("mr-1", "Toast", "polecat/toast", "")),
+		ev(4, events.TypePatrolStarted, "gp/witness", nil),
+		ev(4, events.TypeNudge, "gp/witness", nil),
+	}
+
+	out := computeEfficiency(sessions, activity, false, func(CostEntry) bool { return true })
+	rows := make(map[string]EfficiencyRow)
+	for _, r := range out.Rows {
+		rows[r.Group] = r
+	}
+
+	polecat := rows["polecat"]
+	if polecat.Sessions != 2 || polecat.CostUSD != 6 || polecat.Merges != 1 || polecat.Completed != 1 {
+		t.Errorf("polecat = %+v", polecat)
+	}
+	if polecat.CostPerMerge == nil || *polecat.CostPerMerge != 6 {
+		t.Errorf("polecat cost per merge = %v, want 6", polecat.CostPerMerge)
+	}
+	if rows["refinery"].Merges != 1 || rows["refinery"].IdleSessions != 0 {
+		t.Errorf("refinery = %+v", rows["refinery"])
+	}
+	witness := rows["witness"]
+	if witness.IdleSessions != 1 || witness.IdleCostUSD != 3 || witness.CostPerMerge != nil {
+		t.Errorf("patrol-only witness should be idle: %+v", witness)
+	}
+
+	// The merge is credited to author and refinery but counted once in total.
+	if out.Total.Merges != 1 || out.Total.Completed != 1 || out.Total.CostUSD != 10 || out.Total.IdleSessions != 1 {
+		t.Errorf("total = %+v", out.Total)
+	}
+	if out.Rows[0].Group != "polecat" {
+		t.Errorf("rows should be sorted by cost, got %q first", out.Rows[0].Group)
+	}
+
+	// Sessions outside the period still bound the next session's window.
+	recent := computeEfficiency(sessions, activity, true, func(e CostEntry) bool { return e.EndedAt.After(at(2)) })
+	if recent.Total.Sessions != 3 || recent.Total.Completed != 0 || recent.GroupBy != "model" {
+		t.Errorf("period total = %+v", recent.Total)
+	}
+}
+
+func TestAgentKey(t *testing.T) {
+	tests := map[string]string{
+		"mayor/":                 "mayor",
+		"gastown/Toast":          "gastown/toast",
+		"gastown/polecats/toast": "gastown/toast",
+		"gastown/crew/joe":       "gastown/crew/joe",
+	}
+	for in, want := range tests {
+		if got := agentKey(in); got != want {
+			t.Errorf("agentKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestComputeEfficiencyScores(t *testing.T) {
+	base := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+	rate := func(h int, session string, score float64) timedEvent {
+		// Scores read back from the log are JSON numbers.
+		payload := map[string]interface{}{"session": session, "score": score}
+		return timedEvent{Event: events.Event{Type: events.TypeFeedback, Actor: "overseer", Payload: payload}, at: at(h)}
+	}
+
+	sessions := []CostEntry{
+		{SessionID: "gt-gp-toast", Role: "polecat", Rig: "gp", Worker: "toast", CostUSD: 4, EndedAt: at(2)},
+		{SessionID: "gt-gp-toast", Role: "polecat", Rig: "gp", Worker: "toast", CostUSD: 2, EndedAt: at(6)},
+		{SessionID: "gt-gp-nux", Role: "polecat", Rig: "gp", Worker: "nux", CostUSD: 1, EndedAt: at(5)},
+	}
+	activity := []timedEvent{
+		rate(3, "gt-gp-toast", 2), // While the second toast session runs
+		rate(4, "gt-gp-toast", 4), // Re-rated: replaces the 2
+		rate(7, "gt-gp-nux", 5),   // After nux's last session ended
+		rate(7, "gt-gp-gone", 1),  // No such session
+	}
+
+	out := computeEfficiency(sessions, activity, false, func(CostEntry) bool { return true })
+	if out.Total.Rated != 2 || out.Total.AvgScore == nil || *out.Total.AvgScore != 4.5 {
+		t.Errorf("total = %+v, want 2 rated averaging 4.5", out.Total)
+	}
+
+	scores := sessionScores(sessions, []time.Time{{}, at(2), {}}, activity)
+	if len(scores) != 2 || scores[1] != 4 || scores[2] != 5 {
+		t.Errorf("scores = %v, want second toast session 4 and nux 5", scores)
+	}
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/billing"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var costsReconcileDays int
+
+var costsReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Compare recorded costs with what providers billed",
+	Long: `Compare the cost ledger with the usage providers actually billed.
+
+Session costs are recorded by hooks from the agent's own estimates (see
+'gt costs record'). Reconcile pulls billed cost per day and model from the
+providers' billing APIs and reports, for each UTC day and model:
+
+  Recorded   cost of sessions that ended that day, from the ledger
+  Billed     cost the provider billed for the model that day
+  Drift      recorded minus billed, as a percentage of billed
+
+Rows drifting more than drift_threshold_pct (default 10) are flagged. The
+per-model totals include a billed/recorded factor you can use to calibrate
+the ledger. Only complete UTC days are compared, and billed cost includes
+any other use of the same provider organization.
+
+Ledger models are matched to billed models after lowercasing and dropping
+snapshot date suffixes ("claude-sonnet-4-20250514" is "claude-sonnet-4").
+Map other names with model_aliases. Recorded models no configured provider
+billed are listed separately.
+
+Example settings/config.json:
+  "cost_reconcile": {
+    "providers": [
+      {"type": "anthropic", "api_key": "secretRef:keychain:gastown/anthropic-admin"},
+      {"type": "openai", "api_key": "secretRef:env:OPENAI_ADMIN_KEY"}
+    ],
+    "drift_threshold_pct": 10,
+    "model_aliases": {"sonnet-4": "claude-sonnet-4"}
+  }
+
+Billing APIs need an organization admin key.
+
+Examples:
+  gt costs reconcile            # Last 7 complete days
+  gt costs reconcile --days 30  # Last 30 complete days
+  gt costs reconcile --json     # Output as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runCostsReconcile,
+}
+
+func init() {
+	costsCmd.AddCommand(costsReconcileCmd)
+	costsReconcileCmd.Flags().IntVar(&costsReconcileDays, "days", 7, "Number of complete UTC days to compare")
+	costsReconcileCmd.Flags().BoolVar(&costsJSON, "json", false, "Output as JSON")
+}
+
+// ReconcileRow compares recorded and billed cost for one day and model.
+type ReconcileRow struct {
+	Day       string   `json:"day"`
+	Model     string   `json:"model"`
+	LedgerUSD float64  `json:"ledger_usd"`
+	BilledUSD float64  `json:"billed_usd"`
+	DriftUSD  float64  `json:"drift_usd"`
+	DriftPct  *float64 `json:"drift_pct,omitempty"` // Unset when nothing was billed
+	Flagged   bool     `json:"flagged"`
+}
+
+// ReconcileModel totals one model over the period.
+type ReconcileModel struct {
+	Model     string  `json:"model"`
+	LedgerUSD float64 `json:"ledger_usd"`
+	BilledUSD float64 `json:"billed_usd"`
+
+	// Calibration is billed/recorded: multiply recorded costs by it to
+	// match billing. Unset when nothing was recorded.
+	Calibration *float64 `json:"calibration,omitempty"`
+}
+
+// ReconcileOutput is the JSON output of gt costs reconcile.
+type ReconcileOutput struct {
+	From         string           `json:"from"` // First day, inclusive
+	To           string           `json:"to"`   // Last day, inclusive
+	Providers    []string         `json:"providers"`
+	ThresholdPct float64          `json:"threshold_pct"`
+	Rows         []ReconcileRow   `json:"rows"`
+	Models       []ReconcileModel `json:"models"`
+	Unmatched    []ReconcileModel `json:"unmatched,omitempty"` // Recorded, but billed by no provider
+	Flagged      int              `json:"flagged"`
+	Errors       []string         `json:"errors,omitempty"`
+}
+
+func runCostsReconcile(cmd *cobra.Command, args []string) error {
+	if costsReconcileDays < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, reconcilers, err := billing.ForTown(townRoot)
+	if errors.Is(err, billing.ErrNotConfigured) {
+		fmt.Println(style.Dim.Render("No cost_reconcile providers configured in settings/config.json"))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	start := end.AddDate(0, 0, -costsReconcileDays)
+
+	var billed []billing.Usage
+	var providers, failures []string
+	ctx := context.Background()
+	for _, r := range reconcilers {
+		usage, err := r.Usage(ctx, start, end)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		providers = append(providers, r.Name())
+		billed = append(billed, usage...)
+	}
+	if len(providers) == 0 {
+		return fmt.Errorf("pulling billed usage: %s", strings.Join(failures, "; "))
+	}
+
+	entries, err := querySessionEvents()
+	if err != nil {
+		return fmt.Errorf("querying session events: %w", err)
+	}
+
+	output := computeReconciliation(entries, billed, cfg.ModelAliases, cfg.DriftThreshold(), start, end)
+	output.Providers = providers
+	output.Errors = failures
+
+	if costsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(output)
+	}
+	outputReconcileHuman(output)
+	return nil
+}
+
+// computeReconciliation compares ledger entries that ended in [start, end)
+// with billed usage per UTC day and model. Recorded models are mapped
+// through aliases and normalized; those no provider billed in the period
+// are reported as unmatched rather than as drift.
+func computeReconciliation(entries []CostEntry, billed []billing.Usage, aliases map[string]string, thresholdPct float64, start, end time.Time) ReconcileOutput {
+	output := ReconcileOutput{
+		From:         start.UTC().Format(billing.DayFormat),
+		To:           end.UTC().AddDate(0, 0, -1).Format(billing.DayFormat),
+		ThresholdPct: thresholdPct,
+	}
+
+	type key struct{ day, model string }
+	rows := make(map[key]*ReconcileRow)
+	row := func(day, model string) *ReconcileRow {
+		k := key{day, model}
+		if rows[k] == nil {
+			rows[k] = &ReconcileRow{Day: day, Model: model}
+		}
+		return rows[k]
+	}
+
+	billedModels := make(map[string]bool)
+	for _, u := range billed {
+		billedModels[u.Model] = true
+		row(u.Day, u.Model).BilledUSD += u.CostUSD
+	}
+
+	unmatched := make(map[string]float64)
+	for _, e := range entries {
+		if e.EndedAt.Before(start) || !e.EndedAt.Before(end) {
+			continue
+		}
+		model := ledgerModel(e.Model, aliases)
+		if !billedModels[model] {
+			if model == "" {
+				model = "unknown"
+			}
+			unmatched[model] += e.CostUSD
+			continue
+		}
+		row(e.EndedAt.UTC().Format(billing.DayFormat), model).LedgerUSD += e.CostUSD
+	}
+
+	models := make(map[string]*ReconcileModel)
+	for _, r := range rows {
+		r.DriftUSD = r.LedgerUSD - r.BilledUSD
+		if r.BilledUSD > 0 {
+			pct := 100 * r.DriftUSD / r.BilledUSD
+			r.DriftPct = &pct
+			r.Flagged = math.Abs(pct) > thresholdPct
+		} else {
+			r.Flagged = r.LedgerUSD > 0
+		}
+		if r.Flagged {
+			output.Flagged++
+		}
+		output.Rows = append(output.Rows, *r)
+
+		m := models[r.Model]
+		if m == nil {
+			m = &ReconcileModel{Model: r.Model}
+			models[r.Model] = m
+		}
+		m.LedgerUSD += r.LedgerUSD
+		m.BilledUSD += r.BilledUSD
+	}
+	sort.Slice(output.Rows, func(i, j int) bool {
+		if output.Rows[i].Day != output.Rows[j].Day {
+			return output.Rows[i].Day < output.Rows[j].Day
+		}
+		return output.Rows[i].Model < output.Rows[j].Model
+	})
+
+	for _, m := range models {
+		if m.LedgerUSD > 0 {
+			f := m.BilledUSD / m.LedgerUSD
+			m.Calibration = &f
+		}
+		output.Models = append(output.Models, *m)
+	}
+	sort.Slice(output.Models, func(i, j int) bool { return output.Models[i].Model < output.Models[j].Model })
+
+	for model, cost := range unmatched {
+		output.Unmatched = append(output.Unmatched, ReconcileModel{Model: model, LedgerUSD: cost})
+	}
+	sort.Slice(output.Unmatched, func(i, j int) bool { return output.Unmatched[i].Model < output.Unmatched[j].Model })
+	return output
+}
+
+// ledgerModel maps a recorded model name to the billed model it matches.
+func ledgerModel(model string, aliases map[string]string) string {
+	if alias, ok := aliases[model]; ok {
+		model = alias
+	} else if alias, ok := aliases[strings.ToLower(model)]; ok {
+		model = alias
+	}
+	return billing.NormalizeModel(model)
+}
+
+func outputReconcileHuman(output ReconcileOutput) {
+	for _, e := range output.Errors {
+		style.PrintWarning("%s", e)
+	}
+	fmt.Printf("\n%s Cost Reconciliation (%s to %s UTC, %s)\n\n", style.Bold.Render("🧾"),
+		output.From, output.To, strings.Join(output.Providers, ", "))
+
+	if len(output.Rows) == 0 {
+		fmt.Println(style.Dim.Render("Nothing was billed for the period."))
+	} else {
+		fmt.Printf("%-10s  %-28s %10s %10s %10s %8s\n", "Day", "Model", "Recorded", "Billed", "Drift", "Drift%")
+		fmt.Println(strings.Repeat("─", 83))
+		for _, r := range output.Rows {
+			pct := "-"
+			if r.DriftPct != nil {
+				pct = fmt.Sprintf("%+.1f%%", *r.DriftPct)
+			}
+			line := fmt.Sprintf("%-10s  %-28s %10s %10s %10s %8s", r.Day, r.Model,
+				fmt.Sprintf("$%.2f", r.LedgerUSD), fmt.Sprintf("$%.2f", r.BilledUSD),
+				fmt.Sprintf("%+.2f", r.DriftUSD), pct)
+			if r.Flagged {
+				line = style.Warning.Render(line)
+			}
+			fmt.Println(line)
+		}
+
+		fmt.Printf("\n%s\n", style.Bold.Render("By model"))
+		for _, m := range output.Models {
+			factor := "-"
+			if m.Calibration != nil {
+				factor = fmt.Sprintf("%.2f", *m.Calibration)
+			}
+			fmt.Printf("  %-28s recorded %10s  billed %10s  billed/recorded %s\n", m.Model,
+				fmt.Sprintf("$%.2f", m.LedgerUSD), fmt.Sprintf("$%.2f", m.BilledUSD), factor)
+		}
+	}
+
+	if len(output.Unmatched) > 0 {
+		fmt.Printf("\n%s\n", style.Dim.Render("Recorded but not billed by a configured provider (see model_aliases):"))
+		for _, m := range output.Unmatched {
+			fmt.Printf("  %-28s %10s\n", m.Model, fmt.Sprintf("$%.2f", m.LedgerUSD))
+		}
+	}
+
+	fmt.Println()
+	if output.Flagged > 0 {
+		fmt.Printf("%s %d of %d day/model rows drift more than %.0f%%\n",
+			style.WarningPrefix, output.Flagged, len(output.Rows), output.ThresholdPct)
+	} else if len(output.Rows) > 0 {
+		fmt.Printf("%s Recorded costs are within %.0f%% of billing\n", style.SuccessPrefix, output.ThresholdPct)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/billing"
+)
+
+func TestComputeReconciliation(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+	at := func(day, hour int) time.Time { return start.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour) }
+
+	entries := []CostEntry{
+		{Model: "claude-sonnet-4-20250514", CostUSD: 6, EndedAt: at(0, 3)},
+		{Model: "sonnet-4", CostUSD: 4, EndedAt: at(0, 9)},          // Aliased
+		{Model: "Claude-Sonnet-4", CostUSD: 4.5, EndedAt: at(1, 1)}, // Case-insensitive
+		{Model: "gpt-4o", CostUSD: 3, EndedAt: at(1, 2)},            // Billed on the other day only
+		{Model: "auto", CostUSD: 2, EndedAt: at(1, 5)},              // No provider bills it
+		{Model: "", CostUSD: 1, EndedAt: at(1, 6)},
+		{Model: "claude-sonnet-4", CostUSD: 100, EndedAt: at(2, 0)}, // After the period
+	}
+	billed := []billing.Usage{
+		{Day: "2026-10-01", Model: "claude-sonnet-4", CostUSD: 10.5, Provider: "anthropic"},
+		{Day: "2026-10-02", Model: "claude-sonnet-4", CostUSD: 6, Provider: "anthropic"},
+		{Day: "2026-10-01", Model: "gpt-4o", CostUSD: 2, Provider: "openai"},
+	}
+
+	out := computeReconciliation(entries, billed, map[string]string{"sonnet-4": "claude-sonnet-4"}, 10, start, end)
+	if out.From != "2026-10-01" || out.To != "2026-10-02" {
+		t.Errorf("period = %s..%s, want 2026-10-01..2026-10-02", out.From, out.To)
+	}
+
+	want := []struct {
+		day, model      string
+		ledger, billed  float64
+		flagged, hasPct bool
+	}{
+		{"2026-10-01", "claude-sonnet-4", 10, 10.5, false, true}, // -4.8%
+		{"2026-10-01", "gpt-4o", 0, 2, true, true},               // Billed, never recorded
+		{"2026-10-02", "claude-sonnet-4", 4.5, 6, true, true},    // -25%
+		{"2026-10-02", "gpt-4o", 3, 0, true, false},              // Recorded, not billed
+	}
+	if len(out.Rows) != len(want) {
+		t.Fatalf("rows = %+v, want %d", out.Rows, len(want))
+	}
+	for i, w := range want {
+		r := out.Rows[i]
+		if r.Day != w.day || r.Model != w.model || r.LedgerUSD != w.ledger || r.BilledUSD != w.billed ||
+			r.Flagged != w.flagged || (r.DriftPct != nil) != w.hasPct {
+			t.Errorf("row %d = %+v, want %+v", i, r, w)
+		}
+	}
+	if out.Flagged != 3 {
+		t.Errorf("Flagged = %d, want 3", out.Flagged)
+	}
+
+	if len(out.Models) != 2 || out.Models[0].Model != "claude-sonnet-4" || out.Models[0].Calibration == nil {
+		t.Fatalf("models = %+v", out.Models)
+	}
+	if got := *out.Models[0].Calibration; got < 1.1379 || got > 1.1380 {
+		t.Errorf("claude-sonnet-4 calibration = %v, want 16.5/14.5", got)
+	}
+
+	if len(out.Unmatched) != 2 || out.Unmatched[0].Model != "auto" || out.Unmatched[1].Model != "unknown" {
+		t.Errorf("unmatched = %+v, want auto and unknown", out.Unmatched)
+	}
+}
@@ -98,3 +98,27 @@ func TestDeriveSessionName(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionCostEntriesDeduplicates(t *testing.T) {
+	evs := []SessionEvent{
+		{ID: "gt-1", EventKind: "session.ended", Payload: `{"cost_usd":1.5,"session_id":"gt-a","idempotency_key":"session_end:a","event_id":"01A"}`},
+		// Retried hook: new bead and event ID, same idempotency key.
+		{ID: "gt-2", EventKind: "session.ended", Payload: `{"cost_usd":1.5,"session_id":"gt-a","idempotency_key":"session_end:a","event_id":"01B"}`},
+		// Same event copied twice (same event ID, no key).
+		{ID: "gt-3", EventKind: "session.ended", Payload: `{"cost_usd":2,"session_id":"gt-b","event_id":"01C"}`},
+		{ID: "gt-4", EventKind: "session.ended", Payload: `{"cost_usd":2,"session_id":"gt-b","event_id":"01C"}`},
+		// Legacy events without IDs fall back to the bead ID.
+		{ID: "gt-5", EventKind: "session.ended", Payload: `{"cost_usd":3,"session_id":"gt-c"}`},
+		{ID: "gt-6", EventKind: "session.ended", Payload: `{"cost_usd":3,"session_id":"gt-c"}`},
+		{ID: "gt-7", EventKind: "other", Payload: `{"cost_usd":9}`},
+	}
+
+	entries := sessionCostEntries(evs)
+	var total float64
+	for _, e := range entries {
+		total += e.CostUSD
+	}
+	if len(entries) != 4 || total != 9.5 {
+		t.Errorf("got %d entries totaling $%.2f, want 4 totaling $9.50", len(entries), total)
+	}
+}
@@ -608,7 +608,8 @@ func getKnownRoles(config *council.Config) []string {
 }
 
 func runCouncilTemplates(cmd *cobra.Command, args []string) error {
-	tmpl, err := templates.New()
+	townRoot, _ := workspace.FindFromCwd()
+	tmpl, err := templates.NewForTown(townRoot)
 	if err != nil {
 		return fmt.Errorf("loading templates: %w", err)
 	}
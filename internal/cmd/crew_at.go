@@ -7,6 +7,8 @@ import (
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
 	"github.com/cursorworkshop/cursor-gastown/internal/crew"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/style"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
 	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
@@ -115,6 +117,16 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 	}
 
 	if !hasSession {
+		if err := preflight.BeforeSpawn(preflight.Spec{
+			Session:  sessionID,
+			Role:     session.RoleCrew,
+			TownRoot: townRoot,
+			RigPath:  r.Path,
+			Agent:    crewAgentOverride,
+		}); err != nil {
+			return err
+		}
+
 		// Create new session
 		if err := t.NewSession(sessionID, worker.ClonePath); err != nil {
 			return fmt.Errorf("creating session: %w", err)
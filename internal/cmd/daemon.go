@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -33,7 +34,20 @@ var daemonStartCmd = &cobra.Command{
 	Short: "Start the daemon",
 	Long: `Start the Gas Town daemon in the background.
 
-The daemon will run until stopped with 'gt daemon stop'.`,
+The daemon will run until stopped with 'gt daemon stop'.
+
+With --web, the daemon also serves a read-only web UI (status, sessions,
+recent events, cost charts, and the last doctor run) so teammates can
+glance at the town from a browser without shell access. The page is
+rendered from state the daemon caches each heartbeat. Every request needs
+the access token: --web-token, or a random token generated on first use
+and kept in daemon/web.token. Open the URL printed by 'gt daemon status'.
+--web-token can also be set with GT_WEB_TOKEN.
+
+Examples:
+  gt daemon start
+  gt daemon start --web :8080
+  gt daemon start --web 127.0.0.1:8080 --web-token "$GT_WEB_TOKEN"`,
 	RunE: runDaemonStart,
 }
 
@@ -66,8 +80,10 @@ var daemonRunCmd = &cobra.Command{
 }
 
 var (
-	daemonLogLines int
+	daemonLogLines  int
 	daemonLogFollow bool
+	daemonWebAddr   string
+	daemonWebToken  string
 )
 
 func init() {
@@ -80,6 +96,11 @@ func init() {
 	daemonLogsCmd.Flags().IntVarP(&daemonLogLines, "lines", "n", 50, "Number of lines to show")
 	daemonLogsCmd.Flags().BoolVarP(&daemonLogFollow, "follow", "f", false, "Follow log output")
 
+	for _, c := range []*cobra.Command{daemonStartCmd, daemonRunCmd} {
+		c.Flags().StringVar(&daemonWebAddr, "web", "", "Serve the read-only web UI on this address (e.g. :8080)")
+		c.Flags().StringVar(&daemonWebToken, "web-token", "", "Web UI access token (default: generated, in daemon/web.token)")
+	}
+
 	rootCmd.AddCommand(daemonCmd)
 }
 
@@ -105,8 +126,16 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding executable: %w", err)
 	}
 
-	daemonCmd := exec.Command(gtPath, "daemon", "run")
+	runArgs := []string{"daemon", "run"}
+	if daemonWebAddr != "" {
+		runArgs = append(runArgs, "--web", daemonWebAddr)
+	}
+	daemonCmd := exec.Command(gtPath, runArgs...) //nolint:gosec // G204: args are our own flags
 	daemonCmd.Dir = townRoot
+	if daemonWebToken != "" {
+		// Pass the token by environment so it does not show up in ps.
+		daemonCmd.Env = append(os.Environ(), "GT_WEB_TOKEN="+daemonWebToken)
+	}
 
 	// Detach from terminal
 	daemonCmd.Stdin = nil
@@ -139,6 +168,7 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Daemon started (PID %d)\n", style.Bold.Render("OK"), pid)
+	printDaemonWebURL(townRoot)
 	return nil
 }
 
@@ -190,6 +220,7 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 					state.LastHeartbeat.Format("15:04:05"),
 					state.HeartbeatCount)
 			}
+			printDaemonWebURL(townRoot)
 
 			// Check if binary is newer than process
 			if binaryModTime, err := getBinaryModTime(); err == nil {
@@ -258,6 +289,12 @@ func runDaemonRun(cmd *cobra.Command, args []string) error {
 	}
 
 	config := daemon.DefaultConfig(townRoot)
+	config.Version = Version
+	config.WebAddr = daemonWebAddr
+	config.WebToken = daemonWebToken
+	if config.WebToken == "" {
+		config.WebToken = os.Getenv("GT_WEB_TOKEN")
+	}
 	d, err := daemon.New(config)
 	if err != nil {
 		return fmt.Errorf("creating daemon: %w", err)
@@ -265,3 +302,24 @@ func runDaemonRun(cmd *cobra.Command, args []string) error {
 
 	return d.Run()
 }
+
+// printDaemonWebURL prints the web UI address, including the access token
+// from daemon/web.token, when the running daemon serves one.
+func printDaemonWebURL(townRoot string) {
+	state, err := daemon.LoadState(townRoot)
+	if err != nil || state.WebAddr == "" {
+		return
+	}
+	host, port, err := net.SplitHostPort(state.WebAddr)
+	if err != nil {
+		return
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	url := "http://" + net.JoinHostPort(host, port) + "/"
+	if token := daemon.LoadWebToken(townRoot); token != "" {
+		url += "?token=" + token
+	}
+	fmt.Printf("  Web UI: %s\n", url)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
 	"github.com/cursorworkshop/cursor-gastown/internal/deacon"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
 	"github.com/cursorworkshop/cursor-gastown/internal/polecat"
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/style"
@@ -312,6 +313,16 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 		style.PrintWarning("Could not create deacon settings: %v", err)
 	}
 
+	if err := preflight.BeforeSpawn(preflight.Spec{
+		Session:     sessionName,
+		Role:        session.RoleDeacon,
+		TownRoot:    townRoot,
+		Agent:       agentOverride,
+		SettingsDir: deaconDir,
+	}); err != nil {
+		return err
+	}
+
 	// Create session in deacon directory
 	fmt.Println("Starting Deacon session...")
 	if err := t.NewSession(sessionName, deaconDir); err != nil {
@@ -1,12 +1,26 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/cursorworkshop/cursor-gastown/internal/doctor"
+	"github.com/cursorworkshop/cursor-gastown/internal/lock"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
 	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+	"golang.org/x/term"
 )
 
 var (
@@ -14,6 +28,17 @@ var (
 	doctorVerbose         bool
 	doctorRig             string
 	doctorRestartSessions bool
+	doctorChangedOnly     bool
+	doctorFormat          string
+	doctorOut             string
+	doctorAllTowns        bool
+	doctorRollbackList    bool
+	doctorHistoryLimit    int
+	doctorHistoryJSON     bool
+	doctorProfile         string
+	doctorCheckTimeout    time.Duration
+	doctorIncludeBase     bool
+	doctorWait            time.Duration
 )
 
 var doctorCmd = &cobra.Command{
@@ -28,23 +53,36 @@ and other problems that could affect workspace operation.
 Workspace checks:
   - town-config-exists       Check mayor/town.json exists
   - town-config-valid        Check mayor/town.json is valid
+  - config-schema            Validate town and rig config files against their schemas
   - rigs-registry-exists     Check mayor/rigs.json exists (fixable)
   - rigs-registry-valid      Check registered rigs exist (fixable)
   - mayor-exists             Check mayor/ directory structure
 
 Infrastructure checks:
-  - daemon                   Check if daemon is running (fixable)
+  - tmux                     Check tmux is installed and its server responds
+  - tmux-env                 Check tmux version, server socket, and options gt relies on
+  - daemon                   Check daemon is running, responsive, and current (fixable)
+  - gt-update                Check for a newer gt release on the configured channel
   - repo-fingerprint         Check database has valid repo fingerprint (fixable)
   - boot-health              Check Boot watchdog health (vet mode)
+  - events-integrity         Check .events.jsonl for corrupt lines (fixable)
+  - event-timestamps         Check .events.jsonl for future-dated or out-of-order timestamps
+  - cursor-cli               Check cursor-agent is installed, on tmux PATH, logged in, and supported
+  - session-names            Detect colliding or shadowed agent tmux session names
 
 Cleanup checks (fixable):
   - orphan-sessions          Detect orphaned tmux sessions
   - orphan-processes         Detect orphaned agent processes
   - wisp-gc                  Detect and clean abandoned wisps (>1h)
+  - stale-polecat-dirs       Salvage and remove polecat dirs never started (>24h)
+  - reclaimable-polecats     Archive and remove polecat dirs whose branch merged or work is done
+  - broken-links             Detect broken symlinks, dangling worktree links, and missing config paths
 
 Clone divergence checks:
   - persistent-role-branches Detect crew/witness/refinery not on main
   - clone-divergence         Detect clones significantly behind origin/main
+  - stale-git-locks          Detect abandoned git lock files in rig clones (fixable)
+  - unmerged-polecat-branches Surface finished polecat work with no merge request
 
 Crew workspace checks:
   - crew-state               Validate crew worker state.json files (fixable)
@@ -66,6 +104,18 @@ Routing checks (fixable):
 Session hook checks:
   - session-hooks            Check settings.json use session-start.sh
   - cursor-settings          Check Cursor settings.json match templates (fixable)
+  - layout-migration         Move files from older town layouts into place (fixable)
+  - generated-gitignore      Check rig repos' .gitignore excludes .cursor/ and gt files (fixable)
+  - template-drift           Check agent hooks match this gt version's templates (fixable)
+  - hook-version             Check agent hooks were generated by this gt version (fixable)
+  - mcp-config               Check agents' .cursor/mcp.json match the configured MCP servers (fixable)
+  - cursor-rules             Check agents' .cursor/rules match the composed rule set (fixable)
+  - codex-settings           Check Codex agents' AGENTS.md and notify program are current (fixable)
+  - settings-perms           Check hooks and state files are not writable by other users (fixable)
+  - context-budget           Check agent rules and context stay within per-role token budgets
+
+Mail checks:
+  - mail-backlog             Detect inboxes with stale (>24h) or piled-up (>20) unread mail
 
 Patrol checks:
   - patrol-molecules-exist   Verify patrol molecules exist
@@ -75,34 +125,291 @@ Patrol checks:
   - patrol-roles-have-prompts Verify role prompts exist
 
 Use --fix to attempt automatic fixes for issues that support it.
-Use --rig to check a specific rig instead of the entire workspace.`,
+Use --rig to check a specific rig instead of the entire workspace.
+Use --changed-only to skip file-based checks whose inputs are unchanged
+since the last run (results are cached in .runtime/doctor-cache.json).
+
+Use --profile to run a named subset of checks. Built-in profiles are
+"quick" (skips git-heavy and slow external checks) and "full" (every
+check); define more in the town settings/config.json:
+
+  "doctor_profiles": {
+    "pre-demo": {"description": "Before a demo", "checks": ["daemon", "patrol-*", "cursor-cli"]}
+  }
+
+Entries are check names or glob patterns; "skip" removes checks after
+"checks" selects them. List profiles with 'gt doctor profiles'.
+
+Use --all-towns to run checks in every town on this machine and print a
+combined report. Towns are recorded in a registry (~/.config/gastown/towns.json,
+or $GT_TOWN_REGISTRY) by 'gt install' and whenever 'gt doctor' runs in them.
+With --fix, registry entries for deleted towns are removed.
+
+Use --format sarif to emit a SARIF 2.1.0 log for code-scanning dashboards
+(e.g. GitHub code scanning). Each check is a rule; warnings and errors are
+results, located at the files they mention.
+
+Use --format json for machine-readable results. Each result lists its
+suggested fixes as actions (command, args, dir, destructive) that the
+daemon, a TUI, or an agent can run directly instead of parsing hint text.
+
+Use --format html --out report.html to write a self-contained page with
+every check, its details and fixes, and recent fix history, for sharing
+with teammates who cannot run gt doctor on the box.
+
+Every run is recorded in the town's .events.jsonl as a doctor_run event
+(summary counts), plus doctor_finding events for warnings, errors, and
+timeouts and doctor_fix events for fixed checks, so tooling and the daemon
+can track town health over time.
+
+While fixing, progress is shown for fixes that touch many sessions or files.
+Press ctrl-C to stop cleanly after the current item; the report shows how
+far each interrupted fix got (press ctrl-C again to abort immediately).
+
+Fixes that restart sessions first check whether the agent is mid-task
+(pane output in the last two minutes and no agent stop since). A busy
+session is left running: on a terminal you are asked whether to restart
+it anyway, otherwise the restart is deferred to a later --fix run.
+
+Before --fix deletes or overwrites files, they are copied into
+.runtime/doctor-backups/<run-id>/. Undo a fix run with 'gt doctor rollback'.
+Every fix action (file deleted, created, or modified; session or process
+killed) is recorded in the fix journal: review it with 'gt doctor history'
+and reverse a single file action with 'gt doctor undo <id>'.
+
+Only one --fix, rollback, or undo runs in a town at a time. They hold a
+town operation lock (.runtime/operation.lock) recording the operation,
+who started it, PID, and host. A second run refuses with the holder's
+details; use --wait 5m to queue behind it instead. A lock left by a process
+that died, or not refreshed for two minutes, is taken over with a warning.
+
+Each check, and each fix, is limited to --check-timeout (default 60s; 0
+disables) so a hung git or bd subprocess cannot stall the run. At the limit
+the check's git, bd, and tmux subprocesses are killed. A check that overruns
+is reported as timed out ([?]) and left to finish in the background, its
+result discarded; with --fix, no further fixes are attempted in that run,
+since it may still be running. A fix that overruns is reported as failed.
+
+Some checks depend on others (for example, tmux-env on tmux). When a
+prerequisite check fails, its dependents are reported as blocked ([-])
+instead of being run, so one root cause is not repeated as many failures.
+
+Use 'gt doctor baseline save' in a town that already fails checks to
+acknowledge its current findings. Later runs report checks whose findings
+are all baselined as [~] and only fail on new findings. Use
+--include-baselined to report every finding.
+
+Exit codes:
+  0  All checks passed
+  1  Errors found
+  2  Warnings only
+  3  Fixes applied (--fix) but warnings or errors remain
+  4  No errors, but a check timed out`,
 	RunE: runDoctor,
 }
 
+var doctorRollbackCmd = &cobra.Command{
+	Use:   "rollback [run-id]",
+	Short: "Restore files changed by a 'gt doctor --fix' run",
+	Long: `Restore every file and directory a 'gt doctor --fix' run deleted or
+overwrote, from the backup taken before the fix was applied.
+
+Without a run ID, the most recent fix run that has not already been rolled
+back is restored. Files that the fix run created are removed.
+
+Examples:
+  gt doctor rollback                    # Undo the most recent fix run
+  gt doctor rollback 20260101-120000    # Undo a specific run
+  gt doctor rollback --list             # Show recorded fix runs`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoctorRollback,
+}
+
+var doctorHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show actions taken by 'gt doctor --fix' runs",
+	Long: `Show the fix journal: every file deleted, created, or modified and every
+session or process killed by 'gt doctor --fix', newest first.
+
+File actions can be reversed one at a time with 'gt doctor undo <id>'.
+
+Examples:
+  gt doctor history             # Recent fix actions
+  gt doctor history -n 0        # All fix actions
+  gt doctor history --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorHistory,
+}
+
+var doctorProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List doctor check profiles",
+	Long: `List the check profiles available to 'gt doctor --profile': the built-in
+"quick" and "full" profiles plus any defined under doctor_profiles in the
+town settings/config.json.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorProfiles,
+}
+
+var doctorUndoCmd = &cobra.Command{
+	Use:   "undo <id>",
+	Short: "Reverse a single action from the fix journal",
+	Long: `Reverse one file action recorded by 'gt doctor --fix', using the backup
+taken before the fix ran. Deleted and modified files are restored; files the
+fix created are removed.
+
+Killed sessions and processes cannot be undone; sessions are restarted by
+the daemon or 'gt up'. Use 'gt doctor rollback' to undo a whole fix run.
+
+Examples:
+  gt doctor history    # Find the entry ID
+  gt doctor undo 42`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDoctorUndo,
+}
+
 func init() {
 	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Attempt to automatically fix issues")
 	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
 	doctorCmd.Flags().StringVar(&doctorRig, "rig", "", "Check specific rig only")
 	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
+	doctorCmd.Flags().BoolVar(&doctorChangedOnly, "changed-only", false, "Skip checks whose inputs are unchanged since the last run")
+	doctorCmd.Flags().StringVar(&doctorFormat, "format", "text", "Output format: text, json, sarif, or html")
+	doctorCmd.Flags().StringVarP(&doctorOut, "out", "o", "", "Write the json, sarif, or html report to a file instead of stdout")
+	doctorCmd.Flags().BoolVar(&doctorAllTowns, "all-towns", false, "Run checks in every registered town on this machine")
+	doctorCmd.Flags().DurationVar(&doctorCheckTimeout, "check-timeout", doctor.DefaultCheckTimeout, "Per-check time limit (0 disables)")
+	doctorCmd.Flags().StringVar(&doctorProfile, "profile", "", "Run only the checks in a named profile (see 'gt doctor profiles')")
+	doctorCmd.Flags().BoolVar(&doctorIncludeBase, "include-baselined", false, "Ignore the doctor baseline and report every finding")
+	doctorCmd.Flags().DurationVar(&doctorWait, "wait", 0, "With --fix, wait this long for another fix run in the town to finish")
+	doctorRollbackCmd.Flags().BoolVar(&doctorRollbackList, "list", false, "List recorded fix runs instead of rolling back")
+	doctorRollbackCmd.Flags().DurationVar(&doctorWait, "wait", 0, "Wait this long for a fix run in the town to finish")
+	doctorUndoCmd.Flags().DurationVar(&doctorWait, "wait", 0, "Wait this long for a fix run in the town to finish")
+	doctorHistoryCmd.Flags().IntVarP(&doctorHistoryLimit, "limit", "n", 20, "Entries to show (0 for all)")
+	doctorHistoryCmd.Flags().BoolVar(&doctorHistoryJSON, "json", false, "Output as JSON")
+	doctorCmd.AddCommand(doctorRollbackCmd)
+	doctorCmd.AddCommand(doctorHistoryCmd)
+	doctorCmd.AddCommand(doctorProfilesCmd)
+	doctorCmd.AddCommand(doctorUndoCmd)
 	rootCmd.AddCommand(doctorCmd)
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
+	switch doctorFormat {
+	case "text", "json", "sarif", "html":
+	default:
+		return fmt.Errorf("invalid --format %q: must be text, json, sarif, or html", doctorFormat)
+	}
+	if doctorOut != "" && doctorFormat == "text" {
+		return fmt.Errorf("--out requires --format json, sarif, or html")
+	}
+	if doctorAllTowns {
+		if doctorRig != "" {
+			return fmt.Errorf("--rig cannot be combined with --all-towns")
+		}
+		return runDoctorAllTowns()
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	// Create check context
-	ctx := &doctor.CheckContext{
-		TownRoot:        townRoot,
-		RigName:         doctorRig,
-		Verbose:         doctorVerbose,
-		RestartSessions: doctorRestartSessions,
+	// Keep the machine-wide registry current (best-effort)
+	_ = workspace.RegisterTown(townRoot)
+
+	var interrupt <-chan struct{}
+	if doctorFix {
+		var stop func()
+		interrupt, stop = notifyDoctorInterrupt()
+		defer stop()
+	}
+
+	d, ctx, report, err := runDoctorTown(townRoot, doctorRig, interrupt)
+	if err != nil {
+		return err
+	}
+
+	// Print report
+	if doctorFormat == "text" {
+		printDoctorReport(report, ctx)
+	} else if err := writeDoctorReport(func(w io.Writer) error {
+		switch doctorFormat {
+		case "sarif":
+			return report.WriteSARIF(w, d.Checks(), townRoot, Version)
+		case "html":
+			history, _ := doctor.ReadJournal(townRoot)
+			return report.WriteHTML(w, townRoot, history, Version)
+		default:
+			return report.WriteJSON(w, townRoot)
+		}
+	}); err != nil {
+		return err
+	}
+	if n := ctx.Backup.Len(); n > 0 && doctorFormat != "text" {
+		fmt.Fprintf(os.Stderr, "Backed up %d path(s) before fixing. Undo with: gt doctor rollback %s\n", n, ctx.Backup.RunID())
+	}
+
+	// Exit with a code that distinguishes warnings, errors, and partial fixes
+	if code := report.ExitCode(); code != doctor.ExitOK {
+		return NewSilentExit(code)
+	}
+
+	return nil
+}
+
+// acquireOperationLock takes the town operation lock so concurrent fix and
+// sync runs (two operators, an operator and an agent, or the daemon's
+// template resync) cannot race on the same files and sessions. With a
+// non-zero wait (--wait) it queues behind the current holder.
+func acquireOperationLock(townRoot, operation string, wait time.Duration) (*lock.OperationLock, error) {
+	opLock, err := lock.AcquireOperationWait(townRoot, operation, detectSender(), wait, func(holder *lock.OperationInfo) {
+		fmt.Fprintf(os.Stderr, "%s Waiting for %s to finish...\n", style.WarningPrefix, holder)
+	})
+	var busy *lock.OperationBusyError
+	if errors.As(err, &busy) {
+		if wait > 0 {
+			return nil, fmt.Errorf("gave up after %s: %w", wait, err)
+		}
+		return nil, fmt.Errorf("%w\n  Wait for it to finish, or rerun with --wait 5m to queue behind it", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("acquiring town operation lock: %w", err)
+	}
+	if opLock.Recovered != nil {
+		style.PrintWarning("recovered stale operation lock (%s)", opLock.Recovered)
+	}
+	return opLock, nil
+}
+
+// writeDoctorReport writes a json, sarif, or html report to --out, or to
+// stdout when --out is not set.
+func writeDoctorReport(write func(io.Writer) error) error {
+	if doctorOut == "" {
+		if err := write(os.Stdout); err != nil {
+			return fmt.Errorf("writing %s report: %w", doctorFormat, err)
+		}
+		return nil
 	}
 
-	// Create doctor and register checks
+	f, err := os.Create(doctorOut)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", doctorOut, err)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %s report: %w", doctorFormat, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", doctorOut, err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s report to %s\n", doctorFormat, doctorOut)
+	return nil
+}
+
+// newTownDoctor creates a doctor with every check registered for a town.
+// Rig checks are added when rigName is set.
+func newTownDoctor(townRoot, rigName string) *doctor.Doctor {
 	d := doctor.NewDoctor()
 
 	// Register workspace-level checks first (fundamental)
@@ -110,7 +417,10 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	// Register built-in checks
 	d.Register(doctor.NewTownGitCheck())
+	d.Register(doctor.NewTmuxCheck())
+	d.Register(doctor.NewTmuxEnvCheck())
 	d.Register(doctor.NewDaemonCheck())
+	d.Register(doctor.NewSelfUpdateCheck())
 	d.Register(doctor.NewRepoFingerprintCheck())
 	d.Register(doctor.NewBootHealthCheck())
 	d.Register(doctor.NewBeadsDatabaseCheck())
@@ -124,9 +434,18 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewBranchCheck())
 	d.Register(doctor.NewBeadsSyncOrphanCheck())
 	d.Register(doctor.NewCloneDivergenceCheck())
+	d.Register(doctor.NewGitLockCheck())
+	d.Register(doctor.NewBrokenLinkCheck())
 	d.Register(doctor.NewIdentityCollisionCheck())
 	d.Register(doctor.NewLinkedPaneCheck())
+	d.Register(doctor.NewStalePolecatCheck())
+	d.Register(doctor.NewReclaimablePolecatCheck())
+	d.Register(doctor.NewUnmergedPolecatCheck())
+	d.Register(doctor.NewSessionNameCheck())
 	d.Register(doctor.NewThemeCheck())
+	d.Register(doctor.NewEventsIntegrityCheck())
+	d.Register(doctor.NewEventTimestampCheck())
+	d.Register(doctor.NewCursorCLICheck())
 
 	// Patrol system checks
 	d.Register(doctor.NewPatrolMoleculesExistCheck())
@@ -140,10 +459,20 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	// Config architecture checks
 	d.Register(doctor.NewSettingsCheck())
+	d.Register(doctor.NewConfigSchemaCheck())
 	d.Register(doctor.NewSessionHookCheck())
 	d.Register(doctor.NewRuntimeGitignoreCheck())
 	d.Register(doctor.NewLegacyGastownCheck())
 	d.Register(doctor.NewCursorSettingsCheck())
+	d.Register(doctor.NewLayoutMigrationCheck())
+	d.Register(doctor.NewGeneratedGitignoreCheck())
+	d.Register(doctor.NewTemplateDriftCheck())
+	d.Register(doctor.NewHookVersionCheck())
+	d.Register(doctor.NewMCPConfigCheck())
+	d.Register(doctor.NewRulesCheck())
+	d.Register(doctor.NewCodexSettingsCheck())
+	d.Register(doctor.NewSettingsPermsCheck())
+	d.Register(doctor.NewContextBudgetCheck())
 
 	// Crew workspace checks
 	d.Register(doctor.NewCrewStateCheck())
@@ -158,26 +487,652 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewHookSingletonCheck())
 	d.Register(doctor.NewOrphanedAttachmentsCheck())
 
-	// Rig-specific checks (only when --rig is specified)
-	if doctorRig != "" {
+	// Mail health checks
+	d.Register(doctor.NewMailBacklogCheck())
+
+	// Rig-specific checks (only when --rig is specified).
+	// Mirror rigs are read-only references with no agent structure to check.
+	if rigName != "" && !isMirrorRig(townRoot, rigName) {
 		d.RegisterAll(doctor.RigChecks()...)
 	}
 
+	return d
+}
+
+// confirmBusyRestart asks whether to restart a session whose agent is
+// mid-task.
+func confirmBusyRestart(sess, reason string) bool {
+	return promptYesNo(fmt.Sprintf("Session %s is busy (%s). Restart it anyway?", sess, reason))
+}
+
+// runDoctorTown runs (or, with --fix, fixes) every check for one town.
+func runDoctorTown(townRoot, rigName string, interrupt <-chan struct{}) (*doctor.Doctor, *doctor.CheckContext, *doctor.Report, error) {
+	// Only one fix run may change the town at a time
+	if doctorFix {
+		opLock, err := acquireOperationLock(townRoot, "doctor --fix", doctorWait)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		defer func() { _ = opLock.Release() }()
+	}
+
+	// Create check context
+	ctx := &doctor.CheckContext{
+		TownRoot:        townRoot,
+		RigName:         rigName,
+		Verbose:         doctorVerbose,
+		RestartSessions: doctorRestartSessions,
+		GTVersion:       Version,
+		OtherTowns:      otherTownRoots(townRoot),
+	}
+
+	d := newTownDoctor(townRoot, rigName)
+	if doctorProfile != "" {
+		if err := applyDoctorProfile(d, townRoot); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	// Attach result cache (always recorded, reused only with --changed-only)
+	cache := doctor.LoadResultCache(townRoot, Version)
+	d.SetCache(cache, doctorChangedOnly)
+	d.SetTimeout(doctorCheckTimeout)
+
+	// Acknowledged findings do not fail the run (see 'gt doctor baseline')
+	if !doctorIncludeBase {
+		baseline, err := doctor.LoadBaseline(townRoot)
+		if err != nil {
+			style.PrintWarning("ignoring doctor baseline: %v", err)
+		}
+		d.SetBaseline(baseline)
+	}
+
+	// Text output streams results as checks finish
+	tty := term.IsTerminal(int(os.Stderr.Fd()))
+	var live *doctorLiveOutput
+	if doctorFormat == "text" {
+		live = newDoctorLiveOutput(os.Stdout, os.Stderr, tty, doctorVerbose)
+		d.SetCheckProgress(live.onCheck)
+	}
+
 	// Run checks
 	var report *doctor.Report
 	if doctorFix {
+		ctx.Backup = doctor.NewFixBackup(townRoot, time.Now())
+		ctx.Journal = doctor.OpenFixJournal(townRoot, ctx.Backup.RunID())
+		ctx.Progress = newFixProgressPrinter(os.Stderr, tty)
+		if live != nil {
+			ctx.Progress = live.onFix
+		}
+		ctx.Interrupt = interrupt
+		if doctorFormat == "text" && term.IsTerminal(int(os.Stdin.Fd())) {
+			ctx.ConfirmRestart = confirmBusyRestart
+		}
 		report = d.Fix(ctx)
 	} else {
 		report = d.Run(ctx)
 	}
+	live.Close()
 
-	// Print report
-	report.Print(os.Stdout, doctorVerbose)
+	if err := cache.Save(townRoot); err != nil && doctorVerbose {
+		fmt.Fprintf(os.Stderr, "warning: could not save doctor cache: %v\n", err)
+	}
 
-	// Exit with error code if there are errors
-	if report.HasErrors() {
-		return fmt.Errorf("doctor found %d error(s)", report.Summary.Errors)
+	// Record the run in the town's events log for health trends
+	report.LogEvents(ctx, detectSender())
+
+	return d, ctx, report, nil
+}
+
+// applyDoctorProfile narrows d to the checks in the --profile profile.
+func applyDoctorProfile(d *doctor.Doctor, townRoot string) error {
+	p, err := doctor.LookupProfile(townRoot, doctorProfile)
+	if err != nil {
+		return err
+	}
+	unmatched, err := d.ApplyProfile(p)
+	if err != nil {
+		return fmt.Errorf("doctor profile %q: %w", doctorProfile, err)
 	}
+	if len(unmatched) > 0 && doctorFormat == "text" {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf(
+			"Profile %s: no registered check matches %s", doctorProfile, strings.Join(unmatched, ", "))))
+	}
+	return nil
+}
 
+// otherTownRoots returns the registered town roots other than townRoot.
+func otherTownRoots(townRoot string) []string {
+	towns, err := workspace.KnownTowns()
+	if err != nil {
+		return nil
+	}
+	var roots []string
+	for _, town := range towns {
+		if town.Root != townRoot {
+			roots = append(roots, town.Root)
+		}
+	}
+	return roots
+}
+
+// printDoctorReport prints a town's summary with fix backup and interrupt
+// notes. Check results were already streamed as the checks finished.
+func printDoctorReport(report *doctor.Report, ctx *doctor.CheckContext) {
+	fmt.Println()
+	report.PrintSummary(os.Stdout)
+
+	if n := ctx.Backup.Len(); n > 0 {
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf(
+			"Backed up %d path(s) before fixing. Undo with: gt doctor rollback %s", n, ctx.Backup.RunID())))
+		fmt.Printf("%s\n", style.Dim.Render("Review individual fix actions with: gt doctor history"))
+	}
+	if err := ctx.Journal.Err(); err != nil {
+		style.PrintWarning("could not update fix journal: %v", err)
+	}
+	if report.Interrupted && len(report.NotRun) > 0 {
+		fmt.Printf("%s\n", style.Dim.Render("Not run: "+strings.Join(report.NotRun, ", ")))
+	}
+}
+
+// runDoctorAllTowns runs doctor in every town in the machine-wide registry
+// and prints a combined report. With --fix, registry entries whose town
+// root no longer exists are removed.
+func runDoctorAllTowns() error {
+	towns, err := workspace.KnownTowns()
+	if err != nil {
+		return fmt.Errorf("loading town registry: %w", err)
+	}
+	if len(towns) == 0 {
+		path, _ := workspace.RegistryPath()
+		return fmt.Errorf("no towns registered in %s (towns are registered by 'gt install' and 'gt doctor')", path)
+	}
+
+	var interrupt <-chan struct{}
+	if doctorFix {
+		var stop func()
+		interrupt, stop = notifyDoctorInterrupt()
+		defer stop()
+	}
+
+	// combined aggregates every town's results for the exit code
+	combined := doctor.NewReport()
+	var sarifTowns []doctor.SARIFTown
+	var jsonTowns []doctor.JSONTown
+	var htmlTowns []doctor.HTMLTown
+	var healthy, warned, failed, missing int
+
+	for i, town := range towns {
+		label := town.Root
+		if town.Name != "" {
+			label = fmt.Sprintf("%s (%s)", town.Name, town.Root)
+		}
+		if doctorFormat == "text" {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s\n", style.Bold.Render("━━ "+label))
+		}
+
+		if ok, _ := workspace.IsWorkspace(town.Root); !ok {
+			missing++
+			result := &doctor.CheckResult{
+				Name:    "town-registry",
+				Status:  doctor.StatusWarning,
+				Message: "town root no longer exists: " + town.Root,
+				Actions: []doctor.FixAction{{Command: "gt", Args: []string{"doctor", "--all-towns", "--fix"}, Description: "remove it from the registry"}},
+			}
+			if doctorFix {
+				if _, err := workspace.UnregisterTown(town.Root); err == nil {
+					result.Status = doctor.StatusOK
+					result.Message += " (removed from registry)"
+					result.Fixed = true
+				}
+			}
+			combined.Add(result)
+			switch doctorFormat {
+			case "text":
+				fmt.Printf("%s %s: %s\n", style.WarningPrefix, result.Name, result.Message)
+			case "json", "html":
+				missingReport := doctor.NewReport()
+				missingReport.Add(result)
+				jsonTowns = append(jsonTowns, doctor.JSONTown{Report: missingReport, TownRoot: town.Root})
+				htmlTowns = append(htmlTowns, doctor.HTMLTown{Report: missingReport, TownRoot: town.Root})
+			}
+			continue
+		}
+
+		if combined.Interrupted {
+			combined.NotRun = append(combined.NotRun, label)
+			if doctorFormat == "text" {
+				fmt.Println(style.Dim.Render("Not run (interrupted)"))
+			}
+			continue
+		}
+
+		d, ctx, report, err := runDoctorTown(town.Root, "", interrupt)
+		if err != nil {
+			failed++
+			name := "doctor-profile"
+			if errors.Is(err, lock.ErrOperationInProgress) {
+				name = "operation-lock"
+			}
+			combined.Add(&doctor.CheckResult{Name: name, Status: doctor.StatusError, Message: err.Error()})
+			if doctorFormat == "text" {
+				fmt.Printf("%s %v\n", style.ErrorPrefix, err)
+			}
+			continue
+		}
+		for _, result := range report.Checks {
+			combined.Add(result)
+		}
+		combined.Interrupted = combined.Interrupted || report.Interrupted
+
+		switch {
+		case report.HasErrors():
+			failed++
+		case report.HasWarnings():
+			warned++
+		default:
+			healthy++
+		}
+
+		switch doctorFormat {
+		case "sarif":
+			sarifTowns = append(sarifTowns, doctor.SARIFTown{Report: report, Checks: d.Checks(), TownRoot: town.Root})
+		case "json":
+			jsonTowns = append(jsonTowns, doctor.JSONTown{Report: report, TownRoot: town.Root})
+		case "html":
+			history, _ := doctor.ReadJournal(town.Root)
+			htmlTowns = append(htmlTowns, doctor.HTMLTown{Report: report, TownRoot: town.Root, History: history})
+		default:
+			printDoctorReport(report, ctx)
+		}
+	}
+
+	switch doctorFormat {
+	case "sarif":
+		if err := writeDoctorReport(func(w io.Writer) error { return doctor.WriteSARIFTowns(w, sarifTowns, Version) }); err != nil {
+			return err
+		}
+	case "json":
+		if err := writeDoctorReport(func(w io.Writer) error { return doctor.WriteJSONTowns(w, jsonTowns) }); err != nil {
+			return err
+		}
+	case "html":
+		if err := writeDoctorReport(func(w io.Writer) error { return doctor.WriteHTMLTowns(w, htmlTowns, Version) }); err != nil {
+			return err
+		}
+	default:
+		parts := []string{fmt.Sprintf("%d towns", len(towns))}
+		if healthy > 0 {
+			parts = append(parts, style.Success.Render(fmt.Sprintf("%d healthy", healthy)))
+		}
+		if warned > 0 {
+			parts = append(parts, style.Warning.Render(fmt.Sprintf("%d with warnings", warned)))
+		}
+		if failed > 0 {
+			parts = append(parts, style.Error.Render(fmt.Sprintf("%d with errors", failed)))
+		}
+		if missing > 0 {
+			parts = append(parts, style.Dim.Render(fmt.Sprintf("%d missing", missing)))
+		}
+		if len(combined.NotRun) > 0 {
+			parts = append(parts, style.Warning.Render(fmt.Sprintf("%d not run", len(combined.NotRun))))
+		}
+		fmt.Printf("\n%s\n", style.Bold.Render(strings.Join(parts, ", ")))
+	}
+
+	if code := combined.ExitCode(); code != doctor.ExitOK {
+		return NewSilentExit(code)
+	}
+	return nil
+}
+
+// notifyDoctorInterrupt returns a channel closed on the first ctrl-C or
+// SIGTERM so fixes stop between items. Later signals get the default
+// behavior (immediate exit). Call stop when the run is done.
+func notifyDoctorInterrupt() (<-chan struct{}, func()) {
+	interrupt := make(chan struct{})
+	sigCh := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigCh:
+			signal.Stop(sigCh)
+			fmt.Fprintf(os.Stderr, "\n%s Interrupted: stopping after the current item (ctrl-C again to abort)\n", style.WarningPrefix)
+			close(interrupt)
+		case <-done:
+		}
+	}()
+	return interrupt, func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
+
+// newFixProgressPrinter returns a ProgressFunc that renders fix progress.
+// On a terminal it redraws a single progress bar line; otherwise it prints
+// one line per item.
+func newFixProgressPrinter(w io.Writer, tty bool) doctor.ProgressFunc {
+	return func(p doctor.FixProgress) {
+		if p.Total <= 1 {
+			return
+		}
+		if !tty {
+			if p.Done < p.Total {
+				fmt.Fprintf(w, "  fixing %s: %d/%d %s\n", p.Check, p.Done+1, p.Total, p.Item)
+			}
+			return
+		}
+		if p.Done >= p.Total {
+			fmt.Fprint(w, "\r\033[K")
+			return
+		}
+		const width = 20
+		filled := width * p.Done / p.Total
+		bar := strings.Repeat("#", filled) + strings.Repeat(".", width-filled)
+		fmt.Fprintf(w, "\r\033[K  %s [%s] %d/%d %s", p.Check, bar, p.Done, p.Total, style.Dim.Render(p.Item))
+	}
+}
+
+// spinnerFrames animate the running-check line.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// doctorLiveOutput streams check results to out as each check finishes. On
+// a terminal it also keeps a status line on status showing a spinner, the
+// running check, its elapsed time, and any fix progress; elsewhere fix
+// progress is printed one line per item.
+type doctorLiveOutput struct {
+	out, status io.Writer
+	tty         bool
+	verbose     bool
+	fixLines    doctor.ProgressFunc // fix progress off a terminal
+
+	mu      sync.Mutex
+	check   string    // running check, "" between checks
+	started time.Time // when check started
+	fix     string    // fix progress for the status line
+	frame   int
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func newDoctorLiveOutput(out, status io.Writer, tty, verbose bool) *doctorLiveOutput {
+	l := &doctorLiveOutput{
+		out:      out,
+		status:   status,
+		tty:      tty,
+		verbose:  verbose,
+		fixLines: newFixProgressPrinter(status, false),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if !tty {
+		close(l.done)
+		return l
+	}
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				l.mu.Lock()
+				l.frame++
+				l.draw()
+				l.mu.Unlock()
+			case <-l.stop:
+				return
+			}
+		}
+	}()
+	return l
+}
+
+// onCheck shows a starting check on the status line and prints a finished
+// check's result.
+func (l *doctorLiveOutput) onCheck(p doctor.CheckProgress) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if p.Result == nil {
+		l.check, l.started, l.fix = p.Check, time.Now(), ""
+		l.draw()
+		return
+	}
+	l.check, l.fix = "", ""
+	l.clear()
+	doctor.PrintCheck(l.out, p.Result, l.verbose)
+}
+
+// onFix shows fix progress on the status line, or one line per item off a
+// terminal.
+func (l *doctorLiveOutput) onFix(p doctor.FixProgress) {
+	if !l.tty {
+		l.fixLines(p)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fix = ""
+	if p.Total > 1 && p.Done < p.Total {
+		const width = 20
+		filled := width * p.Done / p.Total
+		l.fix = fmt.Sprintf("fixing [%s] %d/%d %s",
+			strings.Repeat("#", filled)+strings.Repeat(".", width-filled), p.Done, p.Total, p.Item)
+	}
+	l.draw()
+}
+
+// draw redraws the status line. Callers hold l.mu.
+func (l *doctorLiveOutput) draw() {
+	if !l.tty || l.check == "" {
+		return
+	}
+	line := fmt.Sprintf("%s %s %s", spinnerFrames[l.frame%len(spinnerFrames)], l.check,
+		style.Dim.Render(fmt.Sprintf("%.1fs", time.Since(l.started).Seconds())))
+	if l.fix != "" {
+		line += " " + style.Dim.Render(l.fix)
+	}
+	fmt.Fprintf(l.status, "\r\033[K%s", line)
+}
+
+// clear erases the status line. Callers hold l.mu.
+func (l *doctorLiveOutput) clear() {
+	if l.tty {
+		fmt.Fprint(l.status, "\r\033[K")
+	}
+}
+
+// Close stops the spinner and erases the status line. Safe on nil.
+func (l *doctorLiveOutput) Close() {
+	if l == nil {
+		return
+	}
+	if l.tty {
+		close(l.stop)
+	}
+	<-l.done
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.check = ""
+	l.clear()
+}
+
+func runDoctorRollback(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if doctorRollbackList {
+		manifests, err := doctor.ListBackups(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing backups: %w", err)
+		}
+		if len(manifests) == 0 {
+			fmt.Println("No fix runs recorded.")
+			return nil
+		}
+		for _, m := range manifests {
+			note := ""
+			if m.RolledBackAt != nil {
+				note = style.Dim.Render(" (rolled back)")
+			}
+			fmt.Printf("  %s  %s  %d path(s)%s\n", style.Bold.Render(m.RunID),
+				style.Dim.Render(m.CreatedAt.Local().Format("2006-01-02 15:04:05")), len(m.Entries), note)
+		}
+		return nil
+	}
+
+	var runID string
+	if len(args) > 0 {
+		runID = args[0]
+	}
+
+	opLock, err := acquireOperationLock(townRoot, "doctor rollback", doctorWait)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = opLock.Release() }()
+
+	m, err := doctor.Rollback(townRoot, runID)
+	if errors.Is(err, doctor.ErrNoBackups) {
+		fmt.Println("No fix runs to roll back.")
+		return nil
+	}
+	if m == nil {
+		return err
+	}
+	for _, e := range m.Entries {
+		action := "restored"
+		if e.Stored == "" {
+			action = "removed"
+		}
+		fmt.Printf("  %s %s\n", style.Dim.Render(action), e.Path)
+	}
+	if err != nil {
+		return fmt.Errorf("rollback of %s incomplete: %w", m.RunID, err)
+	}
+	fmt.Printf("%s Rolled back fix run %s (%d path(s))\n", style.SuccessPrefix, m.RunID, len(m.Entries))
+	return nil
+}
+
+func runDoctorHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	entries, err := doctor.ReadJournal(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading fix journal: %w", err)
+	}
+
+	// Newest first, limited
+	recent := make([]doctor.JournalEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		if doctorHistoryLimit > 0 && len(recent) == doctorHistoryLimit {
+			break
+		}
+		recent = append(recent, entries[i])
+	}
+
+	if doctorHistoryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(recent)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No fix actions recorded.")
+		return nil
+	}
+	for _, e := range recent {
+		target := e.Target
+		if rel, err := filepath.Rel(townRoot, e.Target); err == nil && filepath.IsAbs(e.Target) && !strings.HasPrefix(rel, "..") {
+			target = rel
+		}
+		note := ""
+		switch {
+		case e.UndoneAt != nil:
+			note = style.Dim.Render(" (undone)")
+		case !e.Reversible():
+			note = style.Dim.Render(" (not reversible)")
+		}
+		fmt.Printf("  %4d  %s  %-15s %s %s%s\n", e.ID,
+			style.Dim.Render(e.Time.Local().Format("2006-01-02 15:04:05")),
+			e.Action, target, style.Dim.Render("["+e.Check+"]"), note)
+	}
+	if len(recent) < len(entries) {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("(%d of %d entries; use -n 0 for all)", len(recent), len(entries))))
+	}
+	return nil
+}
+
+func runDoctorUndo(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid entry ID %q (see 'gt doctor history')", args[0])
+	}
+
+	opLock, err := acquireOperationLock(townRoot, "doctor undo", doctorWait)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = opLock.Release() }()
+
+	e, err := doctor.Undo(townRoot, id)
+	if errors.Is(err, doctor.ErrNotReversible) {
+		return fmt.Errorf("%w (sessions are restarted by the daemon or 'gt up')", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	action := "Restored"
+	if e.Action == doctor.ActionFileCreated {
+		action = "Removed"
+	}
+	fmt.Printf("%s %s %s (undid entry %d from fix run %s)\n", style.SuccessPrefix, action, e.Target, e.ID, e.RunID)
+	return nil
+}
+
+func runDoctorProfiles(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	profiles, err := doctor.Profiles(townRoot)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := profiles[name]
+		fmt.Printf("  %s", style.Bold.Render(name))
+		if p.Description != "" {
+			fmt.Printf("  %s", style.Dim.Render(p.Description))
+		}
+		fmt.Println()
+		if len(p.Checks) > 0 {
+			fmt.Printf("      checks: %s\n", strings.Join(p.Checks, ", "))
+		}
+		if len(p.Skip) > 0 {
+			fmt.Printf("      skip:   %s\n", strings.Join(p.Skip, ", "))
+		}
+	}
 	return nil
 }
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/doctor"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var doctorBaselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Acknowledge existing doctor findings so only new ones fail",
+	Long: `Manage the town's doctor baseline (settings/doctor-baseline.json).
+
+A large existing town can fail many checks at once. Saving a baseline
+records the current warnings and errors as acknowledged: later 'gt doctor'
+runs report checks whose findings are all baselined as [~] and exit 0 for
+them, so only new findings fail. A baselined warning that becomes an error
+counts as new. Run 'gt doctor --include-baselined' to see everything.
+
+Each detail line of a failing check is a separate finding, so a check that
+gains a new detail fails again and shows only the new details.
+
+Examples:
+  gt doctor baseline save     # Acknowledge everything failing now
+  gt doctor baseline show     # List acknowledged findings
+  gt doctor baseline clear    # Report every finding again`,
+	RunE: requireSubcommand,
+}
+
+var doctorBaselineSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Run all checks and acknowledge their current findings",
+	Long: `Run the doctor checks (without fixing) and record every warning and
+error they report in settings/doctor-baseline.json, replacing any previous
+baseline. Use --rig to include a rig's checks.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorBaselineSave,
+}
+
+var doctorBaselineShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "List acknowledged doctor findings",
+	Args:  cobra.NoArgs,
+	RunE:  runDoctorBaselineShow,
+}
+
+var doctorBaselineClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the doctor baseline",
+	Args:  cobra.NoArgs,
+	RunE:  runDoctorBaselineClear,
+}
+
+func init() {
+	doctorBaselineSaveCmd.Flags().StringVar(&doctorRig, "rig", "", "Also run and baseline this rig's checks")
+	doctorBaselineCmd.AddCommand(doctorBaselineSaveCmd)
+	doctorBaselineCmd.AddCommand(doctorBaselineShowCmd)
+	doctorBaselineCmd.AddCommand(doctorBaselineClearCmd)
+	doctorCmd.AddCommand(doctorBaselineCmd)
+}
+
+func runDoctorBaselineSave(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	// Record raw findings, not ones already filtered by the old baseline
+	doctorIncludeBase = true
+	_, _, report, err := runDoctorTown(townRoot, doctorRig, nil)
+	if err != nil {
+		return err
+	}
+
+	baseline := doctor.NewBaseline(report, Version)
+	if err := doctor.SaveBaseline(townRoot, baseline); err != nil {
+		return fmt.Errorf("saving baseline: %w", err)
+	}
+	fmt.Println()
+	if len(baseline.Findings) == 0 {
+		fmt.Printf("%s No findings to acknowledge; saved an empty baseline\n", style.SuccessPrefix)
+		return nil
+	}
+	fmt.Printf("%s Baselined %d finding(s) from %d check(s) in %s\n", style.SuccessPrefix,
+		len(baseline.Findings), report.Summary.Warnings+report.Summary.Errors, doctor.BaselineFile)
+	fmt.Printf("%s\n", style.Dim.Render("Later runs only fail on new findings. See everything with: gt doctor --include-baselined"))
+	return nil
+}
+
+func runDoctorBaselineShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	baseline, err := doctor.LoadBaseline(townRoot)
+	if err != nil {
+		return err
+	}
+	if baseline == nil {
+		fmt.Println("No doctor baseline. Create one with: gt doctor baseline save")
+		return nil
+	}
+
+	fmt.Printf("%s %d finding(s), saved %s\n", style.Bold.Render("Doctor baseline:"),
+		len(baseline.Findings), baseline.SavedAt.Local().Format("2006-01-02 15:04"))
+	check := ""
+	for _, f := range baseline.Findings {
+		if f.Check != check {
+			check = f.Check
+			fmt.Printf("\n  %s\n", style.Bold.Render(check))
+		}
+		fmt.Printf("    %s %s\n", style.Dim.Render("["+f.Status+"]"), f.Finding)
+	}
+	return nil
+}
+
+func runDoctorBaselineClear(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if err := os.Remove(doctor.BaselinePath(townRoot)); err != nil {
+		if os.IsNotExist(err) {
+			fmt.Println("No doctor baseline to clear")
+			return nil
+		}
+		return err
+	}
+	fmt.Printf("%s Removed %s; every finding is reported again\n", style.SuccessPrefix, doctor.BaselineFile)
+	return nil
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/doctor"
+)
+
+func TestDoctorLiveOutput(t *testing.T) {
+	for _, tty := range []bool{false, true} {
+		var out, status bytes.Buffer
+		live := newDoctorLiveOutput(&out, &status, tty, false)
+		live.onCheck(doctor.CheckProgress{Check: "slow-check", Total: 1})
+		live.onFix(doctor.FixProgress{Check: "slow-check", Done: 1, Total: 3, Item: "item-b"})
+		live.onCheck(doctor.CheckProgress{Check: "slow-check", Total: 1, Result: &doctor.CheckResult{
+			Name: "slow-check", Status: doctor.StatusWarning, Message: "one left",
+		}})
+		live.Close()
+
+		if !strings.Contains(out.String(), "slow-check: one left") {
+			t.Errorf("tty=%v: result not streamed to out: %q", tty, out.String())
+		}
+		if !strings.Contains(status.String(), "item-b") {
+			t.Errorf("tty=%v: fix progress missing from status: %q", tty, status.String())
+		}
+		if spinner := strings.Contains(status.String(), spinnerFrames[0]); spinner != tty {
+			t.Errorf("tty=%v: spinner shown = %v: %q", tty, spinner, status.String())
+		}
+	}
+}
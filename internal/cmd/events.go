@@ -0,0 +1,409 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// Events command flags
+var (
+	eventsMergeDryRun bool
+	eventsDiffJSON    bool
+	eventsDiffLimit   int
+
+	eventsConsumeCursor  string
+	eventsConsumeLimit   int
+	eventsConsumeFromEnd bool
+	eventsConsumePeek    bool
+	eventsCursorsJSON    bool
+	eventsCursorsDelete  string
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Inspect and reconcile the raw events log",
+	Long: `Inspect and reconcile the raw events log (~/gt/.events.jsonl).
+
+When a town is synced between machines (e.g., laptop and desktop), each
+machine keeps appending to its own copy of the events log and the copies
+diverge. Use 'gt events diff' to see how they differ, then 'gt events merge'
+to fold the other copy into this town's log.
+
+Events are matched by ID when present, otherwise by their full content, so
+merging is idempotent: re-merging the same file adds nothing.
+
+External consumers (forwarders, indexers, the dashboard) read new events
+with 'gt events consume --cursor NAME', which keeps a named checkpoint in
+.runtime/event-cursors/ and returns only events appended since the last call.
+
+Bulky or sensitive bodies (nudge messages, merge failure output) are kept
+out of the log: they are stored in .runtime/event-bodies/, named by their
+SHA-256, and the event's "bodies" field maps each payload key to its
+reference. Forwarding the log forwards metadata only; print a body with
+'gt events body REF'.
+
+Subcommands:
+  diff     Show events present in only one of the two logs
+  merge    Merge another events log into this town's log
+  consume  Print events appended since a named cursor and advance it
+  cursors  List or delete named cursors
+  body     Print an event body by reference`,
+}
+
+var eventsDiffCmd = &cobra.Command{
+	Use:   "diff <other.jsonl>",
+	Short: "Show divergence between this town's events log and another",
+	Long: `Compare this town's events log with another copy.
+
+Reports how many events are shared, which exist only locally, which exist
+only in the other file, and when the logs first diverged.
+
+Examples:
+  gt events diff ~/Dropbox/gt-desktop/.events.jsonl
+  gt events diff other.jsonl --limit 50
+  gt events diff other.jsonl --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEventsDiff,
+}
+
+var eventsMergeCmd = &cobra.Command{
+	Use:   "merge <other.jsonl>",
+	Short: "Merge another events log into this town's log",
+	Long: `Merge another events log into this town's events log.
+
+The result is the deduplicated union of both logs, ordered by timestamp.
+The local log is replaced atomically while holding its lock, so events
+written during the merge are kept. Lines of the other log that are not
+valid JSON are skipped and reported; a local log with such lines is not
+merged into until 'gt doctor --fix' has quarantined them.
+
+Examples:
+  gt events merge ~/Dropbox/gt-desktop/.events.jsonl
+  gt events merge other.jsonl --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEventsMerge,
+}
+
+var eventsConsumeCmd = &cobra.Command{
+	Use:   "consume --cursor <name>",
+	Short: "Print events appended since a named cursor and advance it",
+	Long: `Print the events appended since the named cursor's checkpoint, one raw
+JSON line per event, and advance the cursor past them.
+
+A cursor that does not exist yet starts at the beginning of the log (or at
+the end with --from-end). The cursor is locked while reading and only moves
+once the events have been written to stdout, so concurrent consumers sharing
+a cursor never get the same events, and a consumer killed mid-read gets them
+again next time.
+
+If the log is rewritten (gt events merge, quarantine repair), the cursor
+resumes after its last event wherever that event now sits. If that event is
+gone, the cursor replays the log from the start.
+
+Examples:
+  gt events consume --cursor mybot
+  gt events consume --cursor indexer --limit 500
+  gt events consume --cursor forwarder --from-end
+  gt events consume --cursor mybot --peek`,
+	Args: cobra.NoArgs,
+	RunE: runEventsConsume,
+}
+
+var eventsCursorsCmd = &cobra.Command{
+	Use:   "cursors",
+	Short: "List or delete named event cursors",
+	Long: `List the town's event cursors with their position and how many events
+each still has to consume.
+
+Deleting a cursor makes its next consume start over.
+
+Examples:
+  gt events cursors
+  gt events cursors --json
+  gt events cursors --delete mybot`,
+	Args: cobra.NoArgs,
+	RunE: runEventsCursors,
+}
+
+var eventsBodyCmd = &cobra.Command{
+	Use:   "body <ref>",
+	Short: "Print an event body by reference",
+	Long: `Print a body stored outside the events log, by the reference in an
+event's "bodies" field.
+
+Examples:
+  gt events body sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEventsBody,
+}
+
+func init() {
+	eventsDiffCmd.Flags().BoolVar(&eventsDiffJSON, "json", false, "Output as JSON")
+	eventsDiffCmd.Flags().IntVarP(&eventsDiffLimit, "limit", "n", 20, "Maximum events to list per side (0 for all)")
+
+	eventsMergeCmd.Flags().BoolVarP(&eventsMergeDryRun, "dry-run", "n", false, "Show what would be merged without writing")
+
+	eventsConsumeCmd.Flags().StringVar(&eventsConsumeCursor, "cursor", "", "Cursor name (required)")
+	eventsConsumeCmd.Flags().IntVarP(&eventsConsumeLimit, "limit", "n", 0, "Maximum events to return (0 for all)")
+	eventsConsumeCmd.Flags().BoolVar(&eventsConsumeFromEnd, "from-end", false, "Start a new cursor at the end of the log instead of the beginning")
+	eventsConsumeCmd.Flags().BoolVar(&eventsConsumePeek, "peek", false, "Print new events without advancing the cursor")
+	_ = eventsConsumeCmd.MarkFlagRequired("cursor")
+
+	eventsCursorsCmd.Flags().BoolVar(&eventsCursorsJSON, "json", false, "Output as JSON")
+	eventsCursorsCmd.Flags().StringVar(&eventsCursorsDelete, "delete", "", "Delete the named cursor")
+
+	eventsCmd.AddCommand(eventsDiffCmd)
+	eventsCmd.AddCommand(eventsMergeCmd)
+	eventsCmd.AddCommand(eventsConsumeCmd)
+	eventsCmd.AddCommand(eventsCursorsCmd)
+	eventsCmd.AddCommand(eventsBodyCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+// loadEventLogs reads the local events log and the other log to compare.
+func loadEventLogs(otherPath string) (localPath string, local, other []events.LogEntry, err error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	localPath = filepath.Join(townRoot, events.EventsFile)
+
+	if _, err := os.Stat(otherPath); err != nil {
+		return "", nil, nil, fmt.Errorf("reading %s: %w", otherPath, err)
+	}
+
+	local, skipped, err := events.ReadLog(localPath)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("reading local events: %w", err)
+	}
+	if skipped > 0 {
+		style.PrintWarning("skipped %d malformed line(s) in %s", skipped, localPath)
+	}
+
+	other, skipped, err = events.ReadLog(otherPath)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("reading %s: %w", otherPath, err)
+	}
+	if skipped > 0 {
+		style.PrintWarning("skipped %d malformed line(s) in %s", skipped, otherPath)
+	}
+
+	return localPath, local, other, nil
+}
+
+// EventsDiffOutput is the JSON form of 'gt events diff'.
+type EventsDiffOutput struct {
+	Common     int               `json:"common"`
+	OnlyLocal  []json.RawMessage `json:"only_local"`
+	OnlyOther  []json.RawMessage `json:"only_other"`
+	DivergedAt string            `json:"diverged_at,omitempty"`
+}
+
+func runEventsDiff(cmd *cobra.Command, args []string) error {
+	_, local, other, err := loadEventLogs(args[0])
+	if err != nil {
+		return err
+	}
+
+	diff := events.DiffLogs(local, other)
+
+	if eventsDiffJSON {
+		out := EventsDiffOutput{
+			Common:    diff.Common,
+			OnlyLocal: rawEntries(diff.OnlyLocal),
+			OnlyOther: rawEntries(diff.OnlyOther),
+		}
+		if t := diff.DivergedAt(); !t.IsZero() {
+			out.DivergedAt = t.Format(time.RFC3339)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(diff.OnlyLocal) == 0 && len(diff.OnlyOther) == 0 {
+		fmt.Printf("%s Logs are identical (%d events)\n", style.SuccessPrefix, diff.Common)
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Events log divergence"))
+	fmt.Printf("  Shared:      %d\n", diff.Common)
+	fmt.Printf("  Only local:  %d\n", len(diff.OnlyLocal))
+	fmt.Printf("  Only other:  %d\n", len(diff.OnlyOther))
+	if t := diff.DivergedAt(); !t.IsZero() {
+		fmt.Printf("  Diverged at: %s\n", t.Local().Format("2006-01-02 15:04:05"))
+	}
+
+	printEventEntries("Only local", diff.OnlyLocal)
+	printEventEntries("Only other", diff.OnlyOther)
+
+	fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Run 'gt events merge %s' to merge.", args[0])))
+	return nil
+}
+
+func runEventsMerge(cmd *cobra.Command, args []string) error {
+	localPath, local, other, err := loadEventLogs(args[0])
+	if err != nil {
+		return err
+	}
+
+	diff := events.DiffLogs(local, other)
+	if len(diff.OnlyOther) == 0 {
+		fmt.Printf("%s Nothing to merge: all %d events from %s are already present\n",
+			style.SuccessPrefix, len(other), args[0])
+		return nil
+	}
+
+	merged := events.MergeLogs(local, other)
+
+	if eventsMergeDryRun {
+		fmt.Printf("Would add %d event(s) to %s (%d → %d)\n",
+			len(diff.OnlyOther), localPath, len(local), len(merged))
+		printEventEntries("Would add", diff.OnlyOther)
+		return nil
+	}
+
+	// Merge against the log as it is under the lock, not the copy read
+	// above: events may have been appended since.
+	result, err := events.MergeLog(localPath, other)
+	if err != nil {
+		return fmt.Errorf("merging events: %w", err)
+	}
+
+	fmt.Printf("%s Merged %d event(s) from %s (%d → %d)\n",
+		style.SuccessPrefix, len(result.Added), args[0], result.Before, result.After)
+	return nil
+}
+
+func runEventsConsume(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	opts := events.ConsumeOptions{
+		Limit:   eventsConsumeLimit,
+		FromEnd: eventsConsumeFromEnd,
+		Peek:    eventsConsumePeek,
+	}
+	_, err = events.Consume(townRoot, eventsConsumeCursor, opts, func(batch []events.LogEntry) error {
+		w := bufio.NewWriter(os.Stdout)
+		for _, e := range batch {
+			_, _ = w.Write(e.Raw)
+			_ = w.WriteByte('\n')
+		}
+		// A failed write (closed pipe) must not advance the cursor
+		return w.Flush()
+	})
+	return err
+}
+
+// EventCursorOutput is the JSON form of one cursor in 'gt events cursors'.
+type EventCursorOutput struct {
+	*events.Cursor
+	PendingBytes int64 `json:"pending_bytes"`
+}
+
+func runEventsCursors(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if eventsCursorsDelete != "" {
+		if err := events.DeleteCursor(townRoot, eventsCursorsDelete); err != nil {
+			return err
+		}
+		fmt.Printf("%s Deleted cursor %s\n", style.SuccessPrefix, eventsCursorsDelete)
+		return nil
+	}
+
+	cursors, err := events.ListCursors(townRoot)
+	if err != nil {
+		return err
+	}
+	var logSize int64
+	if info, err := os.Stat(filepath.Join(townRoot, events.EventsFile)); err == nil {
+		logSize = info.Size()
+	}
+	out := make([]EventCursorOutput, 0, len(cursors))
+	for _, c := range cursors {
+		out = append(out, EventCursorOutput{Cursor: c, PendingBytes: max(logSize-c.Offset, 0)})
+	}
+
+	if eventsCursorsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(out) == 0 {
+		fmt.Println(style.Dim.Render("No event cursors. Create one with 'gt events consume --cursor NAME'."))
+		return nil
+	}
+	for _, c := range out {
+		status := style.Dim.Render("caught up")
+		if c.PendingBytes > 0 {
+			status = fmt.Sprintf("%d bytes behind", c.PendingBytes)
+		}
+		fmt.Printf("  %-20s %8d consumed  %s  %s\n", c.Name, c.Consumed,
+			c.UpdatedAt.Local().Format("2006-01-02 15:04:05"), status)
+	}
+	return nil
+}
+
+// printEventEntries prints a compact one-line summary per event, honoring --limit.
+func printEventEntries(title string, entries []events.LogEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", style.Bold.Render(title))
+	for i, e := range entries {
+		if eventsDiffLimit > 0 && i >= eventsDiffLimit {
+			fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("... and %d more", len(entries)-i)))
+			break
+		}
+		var ev events.Event
+		_ = json.Unmarshal(e.Raw, &ev)
+		ts := ev.Timestamp
+		if !e.Timestamp.IsZero() {
+			ts = e.Timestamp.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("  %s  %-16s %s\n", style.Dim.Render(ts), ev.Type, ev.Actor)
+	}
+}
+
+// rawEntries returns the verbatim JSON of each entry.
+func rawEntries(entries []events.LogEntry) []json.RawMessage {
+	out := make([]json.RawMessage, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.Raw)
+	}
+	return out
+}
+
+func runEventsBody(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	body, err := events.LoadBody(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(body)
+	if body != "" && body[len(body)-1] != '\n' {
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// Feedback command flags
+var (
+	feedbackScore int
+	feedbackNote  string
+	feedbackBead  string
+)
+
+var feedbackCmd = &cobra.Command{
+	Use:     "feedback <session|agent>",
+	GroupID: GroupDiag,
+	Short:   "Rate an agent session or polecat result",
+	Long: `Rate the work of an agent session from 1 (poor) to 5 (excellent).
+
+The rating is logged as a feedback event and joined into
+'gt costs efficiency', so cost per merge can be weighed against how good the
+work was for each role or model. A rating applies to the session running
+when it is given, or else to the agent's last recorded session; rating the
+same session again replaces the earlier score.
+
+The target is a tmux session name or an agent address.
+
+Examples:
+  gt feedback gt-gastown-Toast --score 4 --note "missed edge cases"
+  gt feedback gastown/Toast --score 2 --bead gt-abc
+  gt feedback mayor --score 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFeedback,
+}
+
+func init() {
+	feedbackCmd.Flags().IntVar(&feedbackScore, "score", 0, "Rating from 1 (poor) to 5 (excellent) (required)")
+	feedbackCmd.Flags().StringVar(&feedbackNote, "note", "", "What was good or bad about the work")
+	feedbackCmd.Flags().StringVar(&feedbackBead, "bead", "", "Work item the rating is about")
+	_ = feedbackCmd.MarkFlagRequired("score")
+
+	rootCmd.AddCommand(feedbackCmd)
+}
+
+func runFeedback(cmd *cobra.Command, args []string) error {
+	if feedbackScore < 1 || feedbackScore > 5 {
+		return fmt.Errorf("--score must be between 1 and 5, got %d", feedbackScore)
+	}
+	sess, err := feedbackSession(args[0])
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	payload := events.FeedbackPayload(sess, feedbackScore, feedbackNote, feedbackBead)
+	if err := events.LogIn(townRoot, events.TypeFeedback, detectSender(), payload, events.VisibilityFeed); err != nil {
+		return fmt.Errorf("logging feedback: %w", err)
+	}
+	fmt.Printf("%s Rated %s %d/5\n", style.SuccessPrefix, sess, feedbackScore)
+	return nil
+}
+
+// feedbackSession resolves a feedback target to the tmux session it names.
+// Targets are session names or agent addresses: "mayor", "deacon",
+// "<rig>/witness", "<rig>/refinery", "<rig>/crew/<name>", and
+// "<rig>/<polecat>" or "<rig>/polecats/<polecat>".
+func feedbackSession(target string) (string, error) {
+	if _, err := session.ParseSessionName(target); err == nil {
+		return target, nil
+	}
+	addr := strings.Trim(target, "/")
+	switch addr {
+	case "mayor":
+		return session.MayorSessionName(), nil
+	case "deacon":
+		return session.DeaconSessionName(), nil
+	}
+	parts := strings.Split(addr, "/")
+	switch {
+	case len(parts) == 2 && parts[1] == "witness":
+		return session.WitnessSessionName(parts[0]), nil
+	case len(parts) == 2 && parts[1] == "refinery":
+		return session.RefinerySessionName(parts[0]), nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return session.PolecatSessionName(parts[0], parts[1]), nil
+	case len(parts) == 3 && parts[1] == "polecats" && parts[2] != "":
+		return session.PolecatSessionName(parts[0], parts[2]), nil
+	case len(parts) == 3 && parts[1] == "crew" && parts[2] != "":
+		return session.CrewSessionName(parts[0], parts[2]), nil
+	}
+	return "", fmt.Errorf("unknown feedback target %q: want a session name or an agent address like gastown/Toast", target)
+}
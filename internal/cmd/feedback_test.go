@@ -0,0 +1,27 @@
+package cmd
+
+import "testing"
+
+func TestFeedbackSession(t *testing.T) {
+	tests := map[string]string{
+		"gt-gastown-Toast":       "gt-gastown-Toast",
+		"mayor":                  "hq-mayor",
+		"deacon/":                "hq-deacon",
+		"gastown/witness":        "gt-gastown-witness",
+		"gastown/refinery":       "gt-gastown-refinery",
+		"gastown/Toast":          "gt-gastown-Toast",
+		"gastown/polecats/Toast": "gt-gastown-Toast",
+		"gastown/crew/joe":       "gt-gastown-crew-joe",
+	}
+	for target, want := range tests {
+		got, err := feedbackSession(target)
+		if err != nil || got != want {
+			t.Errorf("feedbackSession(%q) = %q, %v; want %q", target, got, err, want)
+		}
+	}
+	for _, target := range []string{"Toast", "gastown/", "a/b/c/d"} {
+		if _, err := feedbackSession(target); err == nil {
+			t.Errorf("feedbackSession(%q) should fail", target)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/checkpoint"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// Grep command flags
+var (
+	grepRole    string
+	grepRig     string
+	grepSources []string
+	grepSince   string
+	grepRegex   bool
+	grepLimit   int
+	grepJSON    bool
+)
+
+// grepSourceNames are the artifact kinds gt grep searches, in output order.
+var grepSourceNames = []string{"handoff", "mail", "checkpoint", "events"}
+
+var grepCmd = &cobra.Command{
+	Use:     "grep <pattern>",
+	GroupID: GroupDiag,
+	Short:   "Search agent artifacts across the town",
+	Long: `Search what agents have written, without knowing where each artifact lives.
+
+Sources (all by default, narrow with --source):
+  handoff     Handoff mail (HANDOFF in the subject)
+  mail        All other mail, read or unread
+  checkpoint  Agent checkpoints: session notes, current step, modified files
+  events      The town events log (.events.jsonl), limited by --since
+
+Repo code is not searched; use git grep for that. The pattern is a
+case-insensitive literal unless --regex is given. Mail needs bd; if it is
+unavailable the other sources are still searched.
+
+Filters apply to the agent an artifact belongs to: the sender or recipient
+of mail, the owner of a checkpoint, the actor of an event.
+
+Examples:
+  gt grep "flaky auth test"                    # Which agent mentioned it?
+  gt grep auth --role polecat --rig greenplace # Only greenplace polecats
+  gt grep "merge conflict" --source handoff,mail
+  gt grep 'timeout after \d+s' --regex --since 3d`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGrep,
+}
+
+func init() {
+	grepCmd.Flags().StringVar(&grepRole, "role", "", "Only artifacts of this role (mayor, deacon, witness, refinery, crew, polecat)")
+	grepCmd.Flags().StringVar(&grepRig, "rig", "", "Only artifacts of agents in this rig")
+	grepCmd.Flags().StringSliceVar(&grepSources, "source", nil, "Sources to search: handoff, mail, checkpoint, events (default: all)")
+	grepCmd.Flags().StringVar(&grepSince, "since", "7d", "Search events since duration (e.g., 24h, 7d)")
+	grepCmd.Flags().BoolVarP(&grepRegex, "regex", "E", false, "Treat the pattern as a regular expression")
+	grepCmd.Flags().IntVarP(&grepLimit, "limit", "n", 50, "Maximum number of matches to show (newest first)")
+	grepCmd.Flags().BoolVar(&grepJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(grepCmd)
+}
+
+// GrepMatch is one artifact matching a gt grep pattern.
+type GrepMatch struct {
+	Source    string    `json:"source"` // "handoff", "mail", "checkpoint", "events"
+	Timestamp time.Time `json:"timestamp"`
+	Agent     string    `json:"agent"`         // Agent the artifact belongs to
+	Ref       string    `json:"ref,omitempty"` // Message ID, checkpoint path, or event ID
+	Title     string    `json:"title,omitempty"`
+	Line      string    `json:"line"` // First matching line
+}
+
+// grepFilter selects which agents' artifacts are searched.
+type grepFilter struct {
+	role string
+	rig  string
+}
+
+// matches reports whether any of the agent addresses pass the filter.
+func (f grepFilter) matches(addrs ...string) bool {
+	if f.role == "" && f.rig == "" {
+		return true
+	}
+	for _, addr := range addrs {
+		role, rig := grepAgentRoleRig(addr)
+		if (f.role == "" || role == f.role) && (f.rig == "" || strings.EqualFold(rig, f.rig)) {
+			return true
+		}
+	}
+	return false
+}
+
+func runGrep(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	re, err := compileGrepPattern(args[0], grepRegex)
+	if err != nil {
+		return err
+	}
+	sources, err := resolveGrepSources(grepSources)
+	if err != nil {
+		return err
+	}
+	window, err := parseDuration(grepSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since duration: %w", err)
+	}
+	filter := grepFilter{role: strings.ToLower(grepRole), rig: grepRig}
+
+	var matches []GrepMatch
+	if sources["mail"] || sources["handoff"] {
+		messages, err := mail.ListTown(townRoot)
+		if err != nil {
+			style.PrintWarning("skipping mail: %v", err)
+		} else {
+			router := mail.NewRouter(townRoot)
+			for _, msg := range messages {
+				_ = router.Decrypt(msg) // Encrypted mail is searched as ciphertext
+			}
+			matches = append(matches, grepMail(messages, re, filter, sources)...)
+		}
+	}
+	if sources["checkpoint"] {
+		matches = append(matches, grepCheckpoints(townRoot, discoverRigs(townRoot), re, filter)...)
+	}
+	if sources["events"] {
+		activity, err := readActivityEvents(townRoot)
+		if err != nil {
+			return fmt.Errorf("reading events: %w", err)
+		}
+		matches = append(matches, grepEvents(activity, re, filter, time.Now().Add(-window))...)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Timestamp.After(matches[j].Timestamp) })
+	total := len(matches)
+	if grepLimit > 0 && len(matches) > grepLimit {
+		matches = matches[:grepLimit]
+	}
+
+	if grepJSON {
+		if matches == nil {
+			matches = []GrepMatch{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(matches)
+	}
+	return outputGrepText(matches, total, time.Now())
+}
+
+// compileGrepPattern compiles the search pattern, case-insensitively. Unless
+// regex is set the pattern is matched literally.
+func compileGrepPattern(pattern string, regex bool) (*regexp.Regexp, error) {
+	if !regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
+// resolveGrepSources validates --source values; none means every source.
+func resolveGrepSources(names []string) (map[string]bool, error) {
+	sources := make(map[string]bool)
+	if len(names) == 0 {
+		names = grepSourceNames
+	}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		valid := false
+		for _, known := range grepSourceNames {
+			valid = valid || name == known
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown source %q (want %s)", name, strings.Join(grepSourceNames, ", "))
+		}
+		sources[name] = true
+	}
+	return sources, nil
+}
+
+// grepAgentRoleRig returns the role and rig of an agent address such as
+// "mayor/", "greenplace/witness", "greenplace/crew/max",
+// "greenplace/polecats/Toast" or the short polecat form "greenplace/Toast".
+func grepAgentRoleRig(addr string) (role, rig string) {
+	parts := strings.Split(strings.Trim(addr, "/"), "/")
+	switch {
+	case parts[0] == "mayor" || parts[0] == "deacon":
+		return parts[0], ""
+	case len(parts) == 1:
+		return "", ""
+	case parts[1] == "witness" || parts[1] == "refinery":
+		return parts[1], parts[0]
+	case parts[1] == "crew":
+		return "crew", parts[0]
+	default:
+		return "polecat", parts[0]
+	}
+}
+
+// grepFirstLine returns the first line of text matching re, trimmed, or ""
+// if nothing matches.
+func grepFirstLine(re *regexp.Regexp, text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if re.MatchString(line) {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
+
+// grepMail searches message subjects and bodies. Handoff mail is reported
+// as its own source.
+func grepMail(messages []*mail.Message, re *regexp.Regexp, filter grepFilter, sources map[string]bool) []GrepMatch {
+	var matches []GrepMatch
+	for _, msg := range messages {
+		source := "mail"
+		if containsHandoff(msg.Subject) {
+			source = "handoff"
+		}
+		if !sources[source] || !filter.matches(msg.From, msg.To) {
+			continue
+		}
+		line := grepFirstLine(re, msg.Subject)
+		if line == "" {
+			line = grepFirstLine(re, msg.Body)
+		}
+		if line == "" {
+			continue
+		}
+		matches = append(matches, GrepMatch{
+			Source:    source,
+			Timestamp: msg.Timestamp,
+			Agent:     msg.From + " → " + msg.To,
+			Ref:       msg.ID,
+			Title:     msg.Subject,
+			Line:      line,
+		})
+	}
+	return matches
+}
+
+// grepCheckpoints searches the checkpoints of every agent workdir in the
+// town: notes, the current step, branch, hooked bead and modified files.
+func grepCheckpoints(townRoot string, rigs []string, re *regexp.Regexp, filter grepFilter) []GrepMatch {
+	type agentDir struct{ addr, dir string }
+	dirs := []agentDir{
+		{"mayor/", filepath.Join(townRoot, "mayor")},
+		{"deacon/", filepath.Join(townRoot, "deacon")},
+	}
+	for _, rigName := range rigs {
+		rigPath := filepath.Join(townRoot, rigName)
+		dirs = append(dirs,
+			agentDir{rigName + "/witness", filepath.Join(rigPath, "witness")},
+			agentDir{rigName + "/refinery", filepath.Join(rigPath, "refinery", "rig")},
+		)
+		for _, group := range []string{"crew", "polecats"} {
+			entries, _ := os.ReadDir(filepath.Join(rigPath, group))
+			for _, e := range entries {
+				if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+					dirs = append(dirs, agentDir{rigName + "/" + group + "/" + e.Name(), filepath.Join(rigPath, group, e.Name())})
+				}
+			}
+		}
+	}
+
+	var matches []GrepMatch
+	for _, d := range dirs {
+		if !filter.matches(d.addr) {
+			continue
+		}
+		cp, err := checkpoint.Read(d.dir)
+		if err != nil || cp == nil {
+			continue
+		}
+		text := strings.Join(append([]string{cp.Notes, cp.StepTitle, cp.Branch, cp.HookedBead}, cp.ModifiedFiles...), "\n")
+		line := grepFirstLine(re, text)
+		if line == "" {
+			continue
+		}
+		matches = append(matches, GrepMatch{
+			Source:    "checkpoint",
+			Timestamp: cp.Timestamp,
+			Agent:     d.addr,
+			Ref:       checkpoint.Path(d.dir),
+			Title:     cp.StepTitle,
+			Line:      line,
+		})
+	}
+	return matches
+}
+
+// grepEvents searches event types and payloads at or after since.
+func grepEvents(activity []timedEvent, re *regexp.Regexp, filter grepFilter, since time.Time) []GrepMatch {
+	var matches []GrepMatch
+	for _, ev := range activity {
+		if ev.at.Before(since) || !filter.matches(ev.Actor) {
+			continue
+		}
+		summary := formatTimelineEvent(ev.Event)
+		payload, _ := json.Marshal(ev.Payload)
+		if !re.MatchString(ev.Type) && !re.MatchString(summary) && !re.Match(payload) {
+			continue
+		}
+		matches = append(matches, GrepMatch{
+			Source:    "events",
+			Timestamp: ev.at,
+			Agent:     ev.Actor,
+			Ref:       ev.ID,
+			Title:     ev.Type,
+			Line:      summary,
+		})
+	}
+	return matches
+}
+
+func outputGrepText(matches []GrepMatch, total int, now time.Time) error {
+	if len(matches) == 0 {
+		fmt.Println(style.Dim.Render("No matches"))
+		return nil
+	}
+	for _, m := range matches {
+		header := fmt.Sprintf("%-10s %s", m.Source, m.Agent)
+		if m.Title != "" && m.Title != m.Line {
+			header += style.Dim.Render(" · " + m.Title)
+		}
+		fmt.Printf("%s %s\n", style.Dim.Render(fmt.Sprintf("%-10s", formatRelative(m.Timestamp, now))), header)
+		fmt.Printf("           %s\n", m.Line)
+		if m.Ref != "" {
+			fmt.Printf("           %s\n", style.Dim.Render(m.Ref))
+		}
+	}
+	if total > len(matches) {
+		fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Showing %d of %d matches (use -n to see more)", len(matches), total)))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/checkpoint"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+)
+
+func TestGrepAgentRoleRig(t *testing.T) {
+	tests := []struct {
+		addr, role, rig string
+	}{
+		{"mayor/", "mayor", ""},
+		{"deacon", "deacon", ""},
+		{"gp/witness", "witness", "gp"},
+		{"gp/refinery", "refinery", "gp"},
+		{"gp/crew/max", "crew", "gp"},
+		{"gp/polecats/Toast", "polecat", "gp"},
+		{"gp/Toast", "polecat", "gp"},
+		{"overseer", "", ""},
+	}
+	for _, tt := range tests {
+		role, rig := grepAgentRoleRig(tt.addr)
+		if role != tt.role || rig != tt.rig {
+			t.Errorf("grepAgentRoleRig(%q) = (%q, %q), want (%q, %q)", tt.addr, role, rig, tt.role, tt.rig)
+		}
+	}
+}
+
+func TestCompileGrepPattern(t *testing.T) {
+	re, err := compileGrepPattern("auth (flaky)", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("the AUTH (flaky) test") || re.MatchString("auth flaky") {
+		t.Error("literal pattern should match case-insensitively and not as a regex")
+	}
+	if _, err := compileGrepPattern("auth (", true); err == nil {
+		t.Error("invalid regex should fail")
+	}
+	if _, err := resolveGrepSources([]string{"mail", "code"}); err == nil {
+		t.Error("unknown source should fail")
+	}
+}
+
+func TestGrepMail(t *testing.T) {
+	re, _ := compileGrepPattern("flaky auth", false)
+	messages := []*mail.Message{
+		{ID: "hq-1", From: "gp/polecats/Toast", To: "gp/witness", Subject: "🤝 HANDOFF: auth work", Body: "Done so far.\nThe flaky auth test fails on CI.\n"},
+		{ID: "hq-2", From: "gp/crew/max", To: "mayor/", Subject: "Flaky auth again?"},
+		{ID: "hq-3", From: "gp/witness", To: "mayor/", Subject: "Patrol", Body: "all quiet"},
+	}
+	all := map[string]bool{"mail": true, "handoff": true}
+
+	matches := grepMail(messages, re, grepFilter{}, all)
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2: %+v", len(matches), matches)
+	}
+	if matches[0].Source != "handoff" || matches[0].Line != "The flaky auth test fails on CI." {
+		t.Errorf("handoff match = %+v", matches[0])
+	}
+	if matches[1].Source != "mail" || matches[1].Ref != "hq-2" {
+		t.Errorf("mail match = %+v", matches[1])
+	}
+
+	if got := grepMail(messages, re, grepFilter{role: "polecat"}, all); len(got) != 1 || got[0].Ref != "hq-1" {
+		t.Errorf("--role polecat matched %+v", got)
+	}
+	if got := grepMail(messages, re, grepFilter{}, map[string]bool{"mail": true}); len(got) != 1 || got[0].Ref != "hq-2" {
+		t.Errorf("--source mail matched %+v", got)
+	}
+}
+
+func TestGrepCheckpoints(t *testing.T) {
+	townRoot := t.TempDir()
+	toast := filepath.Join(townRoot, "gp", "polecats", "Toast")
+	maxDir := filepath.Join(townRoot, "gp", "crew", "max")
+	for _, dir := range []string{toast, maxDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := checkpoint.Write(toast, &checkpoint.Checkpoint{Notes: "Retrying the flaky auth test", StepTitle: "Fix login"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkpoint.Write(maxDir, &checkpoint.Checkpoint{Notes: "Docs pass"}); err != nil {
+		t.Fatal(err)
+	}
+
+	re, _ := compileGrepPattern("flaky", false)
+	matches := grepCheckpoints(townRoot, []string{"gp"}, re, grepFilter{rig: "gp"})
+	if len(matches) != 1 || matches[0].Agent != "gp/polecats/Toast" || matches[0].Title != "Fix login" {
+		t.Errorf("matches = %+v, want Toast's checkpoint", matches)
+	}
+	if got := grepCheckpoints(townRoot, []string{"gp"}, re, grepFilter{role: "crew"}); len(got) != 0 {
+		t.Errorf("--role crew matched %+v", got)
+	}
+}
+
+func TestGrepEvents(t *testing.T) {
+	now := time.Now()
+	activity := []timedEvent{
+		{Event: events.Event{Type: events.TypeHandoff, Actor: "gp/polecats/Toast", Payload: map[string]interface{}{"subject": "flaky auth test"}}, at: now.Add(-time.Hour)},
+		{Event: events.Event{Type: events.TypeHandoff, Actor: "gp/polecats/Nux", Payload: map[string]interface{}{"subject": "flaky auth test"}}, at: now.Add(-48 * time.Hour)},
+		{Event: events.Event{Type: events.TypeHook, Actor: "gp/polecats/Toast", Payload: map[string]interface{}{"bead": "gp-1"}}, at: now.Add(-time.Minute)},
+	}
+	re, _ := compileGrepPattern("flaky auth", false)
+	matches := grepEvents(activity, re, grepFilter{}, now.Add(-24*time.Hour))
+	if len(matches) != 1 || matches[0].Agent != "gp/polecats/Toast" || matches[0].Line != "Handed off: flaky auth test" {
+		t.Errorf("matches = %+v, want Toast's recent handoff", matches)
+	}
+}
@@ -45,7 +45,17 @@ in-progress items) and includes it in the handoff mail. This provides context
 for the next session without manual summarization.
 
 Any molecule on the hook will be auto-continued by the new session.
-The SessionStart hook runs 'gt prime' to restore context.`,
+The SessionStart hook runs 'gt prime' to restore context.
+
+Warm handover (--warm) is for patrol roles (witness, refinery, deacon), so
+patrol coverage has no gap during a restart. The successor starts in a
+second window of the same session while this one keeps working; it gets the
+handoff mail (state is collected unless -m is given) and runs
+'gt handoff --ack' once it has taken over. Only then is this window
+retired. A successor that does not acknowledge within 5 minutes is stopped
+and this session stays on duty.
+
+  gt handoff --warm -s "Witness patrol" -m "Toast stalled, nudged at 14:02"`,
 	RunE: runHandoff,
 }
 
@@ -55,6 +65,9 @@ var (
 	handoffSubject string
 	handoffMessage string
 	handoffCollect bool
+	handoffWarm    bool
+	handoffAck     bool
+	handoffAwait   string
 )
 
 func init() {
@@ -63,10 +76,21 @@ func init() {
 	handoffCmd.Flags().StringVarP(&handoffSubject, "subject", "s", "", "Subject for handoff mail (optional)")
 	handoffCmd.Flags().StringVarP(&handoffMessage, "message", "m", "", "Message body for handoff mail (optional)")
 	handoffCmd.Flags().BoolVarP(&handoffCollect, "collect", "c", false, "Auto-collect state (status, inbox, beads) into handoff message")
+	handoffCmd.Flags().BoolVar(&handoffWarm, "warm", false, "Start the successor alongside this session and retire this one once it acknowledges (patrol roles)")
+	handoffCmd.Flags().BoolVar(&handoffAck, "ack", false, "Acknowledge the warm handover this session was started for")
+	handoffCmd.Flags().StringVar(&handoffAwait, "await", "", "Watch a warm handover until it completes (internal)")
+	_ = handoffCmd.Flags().MarkHidden("await")
 	rootCmd.AddCommand(handoffCmd)
 }
 
 func runHandoff(cmd *cobra.Command, args []string) error {
+	switch {
+	case handoffAck:
+		return runHandoffAck()
+	case handoffAwait != "":
+		return runHandoffAwait(handoffAwait)
+	}
+
 	// Check if we're a polecat - polecats use gt done instead
 	// GT_POLECAT is set by the session manager when starting polecat sessions
 	if polecatName := os.Getenv("GT_POLECAT"); polecatName != "" {
@@ -111,6 +135,13 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting session name: %w", err)
 	}
 
+	if handoffWarm {
+		if len(args) > 0 {
+			return fmt.Errorf("--warm hands off the current session and takes no arguments")
+		}
+		return runWarmHandoff(t, currentSession, pane)
+	}
+
 	// Determine target session and check for bead hook
 	targetSession := currentSession
 	if len(args) > 0 {
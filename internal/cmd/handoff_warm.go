@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// warmHandoverTimeout is how long a successor has to acknowledge a warm
+// handover. A successor that misses it is stopped and the predecessor stays
+// on duty.
+const warmHandoverTimeout = 5 * time.Minute
+
+// warmHandoverPoll is how often the handover watcher checks for the ack.
+const warmHandoverPoll = 2 * time.Second
+
+// warmHandoverEnv carries the handover ID into the successor's environment.
+const warmHandoverEnv = "GT_HANDOVER"
+
+// warmHandover is a warm handover in progress: the successor runs in a
+// second window of the session while the predecessor keeps patrolling.
+// Stored in <town>/.runtime/handovers/<id>.json until it completes or fails.
+type warmHandover struct {
+	ID          string    `json:"id"`
+	Session     string    `json:"session"`
+	Agent       string    `json:"agent"`
+	Predecessor string    `json:"predecessor_window"`
+	Successor   string    `json:"successor_window"`
+	Mail        string    `json:"mail,omitempty"` // Handoff mail bead
+	StartedAt   time.Time `json:"started_at"`
+}
+
+func warmHandoverPath(townRoot, id string) string {
+	return filepath.Join(townRoot, ".runtime", "handovers", id+".json")
+}
+
+func saveWarmHandover(townRoot string, h *warmHandover) error {
+	path := warmHandoverPath(townRoot, h.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: handover state is not sensitive
+}
+
+func loadWarmHandover(townRoot, id string) (*warmHandover, error) {
+	data, err := os.ReadFile(warmHandoverPath(townRoot, id)) //nolint:gosec // G304: path is within the town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no warm handover %s in progress (already completed or failed)", id)
+		}
+		return nil, err
+	}
+	var h warmHandover
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("reading warm handover %s: %w", id, err)
+	}
+	return &h, nil
+}
+
+// pendingWarmHandover returns the handover in progress for a session, or nil.
+// Records older than the ack timeout are left over from a watcher that died
+// and are ignored.
+func pendingWarmHandover(townRoot, sessionName string) *warmHandover {
+	matches, _ := filepath.Glob(filepath.Join(townRoot, ".runtime", "handovers", "*.json"))
+	for _, path := range matches {
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town root
+		if err != nil {
+			continue
+		}
+		var h warmHandover
+		if json.Unmarshal(data, &h) == nil && h.Session == sessionName && time.Since(h.StartedAt) < warmHandoverTimeout+time.Minute {
+			return &h
+		}
+	}
+	return nil
+}
+
+// warmHandoverAllowed reports whether a session runs a continuous patrol
+// role, the roles warm handover is for.
+func warmHandoverAllowed(sessionName string) error {
+	identity, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return err
+	}
+	switch identity.Role {
+	case session.RoleWitness, session.RoleRefinery, session.RoleDeacon:
+		return nil
+	}
+	return fmt.Errorf("warm handover is for patrol roles (witness, refinery, deacon), not %s; use gt handoff", identity.Role)
+}
+
+// handoverAcked reports whether evs contain the successor's ack of handover id.
+func handoverAcked(evs []events.Event, id string) bool {
+	for _, e := range evs {
+		if e.Type == events.TypeHandoverAck {
+			if h, _ := e.Payload["handover"].(string); h == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runWarmHandoff starts a warm handover of the current session: the
+// successor starts in a background window of the same session and a watcher
+// retires this window once the successor acknowledges. Returns right away so
+// the predecessor keeps patrolling while the successor starts.
+func runWarmHandoff(t *tmux.Tmux, currentSession, pane string) error {
+	if err := warmHandoverAllowed(currentSession); err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if h := pendingWarmHandover(townRoot, currentSession); h != nil {
+		return fmt.Errorf("warm handover %s of %s is already in progress (started %s)",
+			h.ID, currentSession, h.StartedAt.Local().Format("15:04:05"))
+	}
+
+	restartCmd, err := buildRestartCommand(currentSession)
+	if err != nil {
+		return err
+	}
+	workDir, err := sessionWorkDir(currentSession, townRoot)
+	if err != nil {
+		return err
+	}
+	agent := sessionToGTRole(currentSession)
+	h := &warmHandover{
+		ID:        events.NewID(),
+		Session:   currentSession,
+		Agent:     agent,
+		StartedAt: time.Now().UTC(),
+	}
+	successorCmd := fmt.Sprintf("export %s=%s && %s", warmHandoverEnv, h.ID, restartCmd)
+
+	if handoffDryRun {
+		fmt.Printf("Would send handoff mail: subject=%q (auto-hooked)\n", handoffSubject)
+		fmt.Printf("Would execute: tmux new-window -d -t %s: -c %s %s\n", currentSession, workDir, successorCmd)
+		fmt.Printf("Would retire window of %s once the successor runs gt handoff --ack (timeout %s)\n", pane, warmHandoverTimeout)
+		return nil
+	}
+
+	// The successor starts from the predecessor's live state
+	if handoffMessage == "" {
+		handoffMessage = collectHandoffState()
+	}
+	if handoffSubject == "" {
+		handoffSubject = "Warm handover"
+	}
+	if h.Mail, err = sendHandoffMail(handoffSubject, handoffMessage); err != nil {
+		return fmt.Errorf("sending handoff mail: %w", err)
+	}
+	fmt.Printf("%s Sent handoff mail %s (auto-hooked)\n", style.Bold.Render("📬"), h.Mail)
+
+	if h.Predecessor, err = t.WindowID(pane); err != nil {
+		return fmt.Errorf("finding this session's window: %w", err)
+	}
+	if h.Successor, err = t.NewWindow(currentSession, string(roleOfSession(currentSession)), workDir, successorCmd); err != nil {
+		return fmt.Errorf("starting successor: %w", err)
+	}
+	if err := saveWarmHandover(townRoot, h); err != nil {
+		_ = t.KillWindow(h.Successor)
+		return fmt.Errorf("saving handover state: %w", err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "gt"
+	}
+	watcher := fmt.Sprintf("cd '%s' && '%s' handoff --await %s", townRoot, exe, h.ID)
+	if err := t.RunShellBackground(watcher); err != nil {
+		_ = t.KillWindow(h.Successor)
+		_ = os.Remove(warmHandoverPath(townRoot, h.ID))
+		return fmt.Errorf("starting handover watcher: %w", err)
+	}
+
+	_ = LogHandoff(townRoot, agent, handoffSubject)
+	_ = events.LogIn(townRoot, events.TypeHandoverStarted, agent, events.HandoverPayload(h.ID, currentSession, ""), events.VisibilityFeed)
+
+	fmt.Printf("%s Warm handover %s: successor starting in window %s\n", style.Bold.Render("🤝"), h.ID, h.Successor)
+	fmt.Printf("  Keep patrolling. This session is retired once the successor acknowledges (timeout %s).\n", warmHandoverTimeout)
+	return nil
+}
+
+// roleOfSession returns the role a session runs, for naming its windows.
+func roleOfSession(sessionName string) session.Role {
+	if identity, err := session.ParseSessionName(sessionName); err == nil {
+		return identity.Role
+	}
+	return ""
+}
+
+// runHandoffAck acknowledges the warm handover the current session was
+// started for. The watcher retires the predecessor when it sees the ack.
+func runHandoffAck() error {
+	id := os.Getenv(warmHandoverEnv)
+	if id == "" {
+		return fmt.Errorf("no warm handover to acknowledge (%s is not set in this session)", warmHandoverEnv)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	h, err := loadWarmHandover(townRoot, id)
+	if err != nil {
+		return err
+	}
+	if err := events.LogIn(townRoot, events.TypeHandoverAck, h.Agent, events.HandoverPayload(h.ID, h.Session, ""), events.VisibilityFeed); err != nil {
+		return fmt.Errorf("logging ack: %w", err)
+	}
+	fmt.Printf("%s Acknowledged warm handover %s; the predecessor is retired shortly. You are on duty.\n", style.SuccessPrefix, h.ID)
+	return nil
+}
+
+// runHandoffAwait watches a warm handover from the tmux server: it injects
+// the handover into the successor, waits for the successor's ack event, and
+// then retires the predecessor's window. If the successor does not ack in
+// time, or its window goes away, the successor is stopped instead and the
+// predecessor is told it is still on duty.
+func runHandoffAwait(id string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	h, err := loadWarmHandover(townRoot, id)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(warmHandoverPath(townRoot, id)) }()
+
+	t := tmux.NewTmux()
+	_ = t.WaitForCursorReady(h.Successor, constants.CursorStartTimeout) // Non-fatal
+	_ = t.AcceptBypassPermissionsWarning(h.Successor)
+	_ = t.NudgeSession(h.Successor, fmt.Sprintf(
+		"[WARM HANDOVER %s] You are replacing a %s session that is still running. Read its handoff mail (%s), then run `gt handoff --ack` to take over; the predecessor is retired once you acknowledge.",
+		h.ID, h.Agent, h.Mail))
+
+	eventsPath := filepath.Join(townRoot, events.EventsFile)
+	deadline := h.StartedAt.Add(warmHandoverTimeout)
+	reason := fmt.Sprintf("successor did not acknowledge within %s", warmHandoverTimeout)
+	for time.Now().Before(deadline) {
+		evs, _ := events.ReadEvents(eventsPath, func(e events.Event) bool { return e.Type == events.TypeHandoverAck })
+		if handoverAcked(evs, id) {
+			if err := t.KillWindow(h.Predecessor); err != nil {
+				return fmt.Errorf("retiring predecessor window %s: %w", h.Predecessor, err)
+			}
+			return nil
+		}
+		if windows, err := t.ListWindowIDs(h.Session); err == nil && !slices.Contains(windows, h.Successor) {
+			reason = "successor window exited"
+			break
+		}
+		time.Sleep(warmHandoverPoll)
+	}
+
+	_ = t.KillWindow(h.Successor)
+	_ = events.LogIn(townRoot, events.TypeHandoverFailed, h.Agent, events.HandoverPayload(h.ID, h.Session, reason), events.VisibilityFeed)
+	_ = t.NudgeSession(h.Predecessor, fmt.Sprintf(
+		"[WARM HANDOVER %s] Failed: %s. The successor was stopped; you are still on duty. Retry with `gt handoff --warm`, or cycle with `gt handoff`.",
+		h.ID, reason))
+	return fmt.Errorf("warm handover %s failed: %s", h.ID, reason)
+}
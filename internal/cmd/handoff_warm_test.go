@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestWarmHandoverAllowed(t *testing.T) {
+	for _, s := range []string{"gt-gastown-witness", "gt-gastown-refinery", "hq-deacon"} {
+		if err := warmHandoverAllowed(s); err != nil {
+			t.Errorf("warmHandoverAllowed(%q) = %v, want nil", s, err)
+		}
+	}
+	for _, s := range []string{"hq-mayor", "gt-gastown-crew-max", "gt-gastown-Toast"} {
+		if err := warmHandoverAllowed(s); err == nil {
+			t.Errorf("warmHandoverAllowed(%q) should fail", s)
+		}
+	}
+}
+
+func TestWarmHandoverState(t *testing.T) {
+	townRoot := t.TempDir()
+	h := &warmHandover{ID: "h1", Session: "gt-gastown-witness", Predecessor: "@1", Successor: "@2", StartedAt: time.Now().UTC()}
+	if err := saveWarmHandover(townRoot, h); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadWarmHandover(townRoot, "h1")
+	if err != nil || got.Successor != "@2" {
+		t.Fatalf("loadWarmHandover = %+v, %v", got, err)
+	}
+	if p := pendingWarmHandover(townRoot, "gt-gastown-witness"); p == nil || p.ID != "h1" {
+		t.Errorf("pendingWarmHandover = %+v, want h1", p)
+	}
+	if p := pendingWarmHandover(townRoot, "gt-gastown-refinery"); p != nil {
+		t.Errorf("pendingWarmHandover for another session = %+v, want nil", p)
+	}
+
+	// A record the watcher never cleaned up does not block new handovers
+	h.ID, h.StartedAt = "h2", time.Now().Add(-time.Hour)
+	h.Session = "gt-gastown-refinery"
+	if err := saveWarmHandover(townRoot, h); err != nil {
+		t.Fatal(err)
+	}
+	if p := pendingWarmHandover(townRoot, "gt-gastown-refinery"); p != nil {
+		t.Errorf("expired handover should not be pending: %+v", p)
+	}
+	if _, err := loadWarmHandover(townRoot, "missing"); err == nil {
+		t.Error("loading a missing handover should fail")
+	}
+}
+
+func TestHandoverAcked(t *testing.T) {
+	evs := []events.Event{
+		{Type: events.TypeHandoverStarted, Payload: events.HandoverPayload("h1", "gt-gastown-witness", "")},
+		{Type: events.TypeHandoverAck, Payload: map[string]interface{}{"handover": "h0"}},
+	}
+	if handoverAcked(evs, "h1") {
+		t.Error("h1 is not acknowledged yet")
+	}
+	evs = append(evs, events.Event{Type: events.TypeHandoverAck, Payload: events.HandoverPayload("h1", "gt-gastown-witness", "")})
+	if !handoverAcked(evs, "h1") {
+		t.Error("h1 should be acknowledged")
+	}
+}
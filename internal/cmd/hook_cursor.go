@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/replay"
+)
+
+// Cursor hook handlers. The generated hooks.json runs these directly
+// (gt cursor-hook <event>) rather than through shell scripts, so there are
+// no scripts to drift, lose their execute bit, or miss gt on PATH. They live
+// under their own hidden parent rather than gt hook, whose subcommands
+// users type. Each reads the hook's JSON payload from stdin and always
+// exits 0: a failing hook must never block the agent.
+
+// hookCostsEvery is how many shell commands pass between cost recordings in
+// CLI mode, where the stop hook does not fire after each turn.
+const hookCostsEvery = 10
+
+// hookCompactMessage reminds the agent to restore its context after
+// compaction.
+const hookCompactMessage = "[Gas Town] Context compacting. Run `gt prime` after compaction to restore role context and check for mail."
+
+var cursorHookCmd = &cobra.Command{
+	Use:    "cursor-hook",
+	Short:  "Cursor hook handlers run by the generated .cursor/hooks.json",
+	Hidden: true,
+	RunE:   requireSubcommand,
+}
+
+var hookSessionStartCmd = &cobra.Command{
+	Use:   "session-start",
+	Short: "Cursor sessionStart hook: inject mail and the session ID",
+	Args:  cobra.NoArgs,
+	RunE:  runHookSessionStart,
+}
+
+var hookBeforeSubmitCmd = &cobra.Command{
+	Use:   "before-submit",
+	Short: "Cursor beforeSubmitPrompt hook: check mail in the background",
+	Args:  cobra.NoArgs,
+	RunE:  runHookBeforeSubmit,
+}
+
+var hookPreCompactCmd = &cobra.Command{
+	Use:   "pre-compact",
+	Short: "Cursor preCompact hook: remind the agent to re-prime",
+	Args:  cobra.NoArgs,
+	RunE:  runHookPreCompact,
+}
+
+var hookStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Cursor stop hook: record costs and sync beads",
+	Args:  cobra.NoArgs,
+	RunE:  runHookStop,
+}
+
+var hookSessionEndCmd = &cobra.Command{
+	Use:   "session-end",
+	Short: "Cursor sessionEnd hook: record costs once per session and sync beads",
+	Args:  cobra.NoArgs,
+	RunE:  runHookSessionEnd,
+}
+
+var hookBeforeShellCmd = &cobra.Command{
+	Use:   "before-shell",
+	Short: "Cursor beforeShellExecution hook: check mail on the first command",
+	Args:  cobra.NoArgs,
+	RunE:  runHookBeforeShell,
+}
+
+var hookAfterShellCmd = &cobra.Command{
+	Use:   "after-shell",
+	Short: "Cursor afterShellExecution hook: capture the command and record costs periodically",
+	Args:  cobra.NoArgs,
+	RunE:  runHookAfterShell,
+}
+
+var hookAfterEditCmd = &cobra.Command{
+	Use:   "after-edit",
+	Short: "Cursor afterFileEdit hook: capture the edit for gt replay",
+	Args:  cobra.NoArgs,
+	RunE:  runHookCapture,
+}
+
+var hookAfterMCPCmd = &cobra.Command{
+	Use:   "after-mcp",
+	Short: "Cursor afterMCPExecution hook: capture the tool call for gt replay",
+	Args:  cobra.NoArgs,
+	RunE:  runHookCapture,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{
+		hookSessionStartCmd, hookBeforeSubmitCmd, hookPreCompactCmd, hookStopCmd,
+		hookSessionEndCmd, hookBeforeShellCmd, hookAfterShellCmd, hookAfterEditCmd, hookAfterMCPCmd,
+	} {
+		cursorHookCmd.AddCommand(c)
+	}
+	rootCmd.AddCommand(cursorHookCmd)
+}
+
+// hookPayload is the subset of Cursor hook input the handlers use.
+type hookPayload struct {
+	SessionID  string `json:"session_id"`
+	Status     string `json:"status"`
+	Reason     string `json:"reason"`
+	DurationMS *int64 `json:"duration_ms"`
+	Trigger    string `json:"trigger"`
+	Usage      *int   `json:"context_usage_percent"`
+	Command    string `json:"command"`
+	Duration   *int64 `json:"duration"`
+}
+
+// readHookInput reads the hook's stdin, which Cursor requires be consumed,
+// and parses what it can of it.
+func readHookInput() ([]byte, hookPayload) {
+	data, _ := io.ReadAll(os.Stdin)
+	var p hookPayload
+	_ = json.Unmarshal(data, &p)
+	return data, p
+}
+
+// inGasTown reports whether the hook runs in a Gas Town agent session.
+func inGasTown() bool {
+	return os.Getenv("GT_ROLE") != ""
+}
+
+// writeHookOutput prints the hook's JSON response.
+func writeHookOutput(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// hookDebugLog appends a line to the hook debug log when GT_DEBUG is set.
+func hookDebugLog(file, format string, args ...any) {
+	if os.Getenv("GT_DEBUG") == "" {
+		return
+	}
+	f, err := os.OpenFile(filepath.Join(os.TempDir(), file), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // G304: fixed name in the temp dir
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "[%s] %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// runGT runs this gt binary with args and returns its stdout. Hooks run gt
+// as a child so that a failing subcommand cannot fail the hook.
+func runGT(args ...string) ([]byte, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "gt"
+	}
+	return exec.Command(exe, args...).Output() //nolint:gosec // G204: runs gt itself
+}
+
+// startGT starts this gt binary with args in the background without
+// waiting for it, for work that must not delay the hook.
+func startGT(args ...string) {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "gt"
+	}
+	cmd := exec.Command(exe, args...) //nolint:gosec // G204: runs gt itself
+	if err := cmd.Start(); err == nil {
+		_ = cmd.Process.Release()
+	}
+}
+
+// syncBeads runs bd sync when bd is installed.
+func syncBeads() {
+	if _, err := exec.LookPath("bd"); err == nil {
+		_ = exec.Command("bd", "sync").Run()
+	}
+}
+
+// hookStateDir holds per-session hook state: whether mail was checked and
+// how many shell commands ran.
+func hookStateDir() string {
+	id := os.Getenv("GT_SESSION_ID")
+	if id == "" {
+		id = strconv.Itoa(os.Getppid())
+	}
+	return filepath.Join(os.TempDir(), "gastown-session-"+id)
+}
+
+func runHookSessionStart(cmd *cobra.Command, args []string) error {
+	_, in := readHookInput()
+
+	var mail string
+	if out, err := runGT("mail", "check", "--inject"); err == nil {
+		mail = strings.TrimSpace(string(out))
+	}
+
+	out := struct {
+		Continue          bool              `json:"continue"`
+		Env               map[string]string `json:"env,omitempty"`
+		AdditionalContext string            `json:"additional_context"`
+	}{Continue: true, AdditionalContext: mail}
+	if in.SessionID != "" {
+		out.Env = map[string]string{"GT_SESSION_ID": in.SessionID, "CURSOR_SESSION_ID": in.SessionID}
+	}
+	return writeHookOutput(out)
+}
+
+func runHookBeforeSubmit(cmd *cobra.Command, args []string) error {
+	_, _ = readHookInput()
+	if inGasTown() {
+		// Context injection happens at sessionStart; this only prompts
+		// the mail check without blocking the prompt
+		startGT("mail", "check", "--inject")
+	}
+	return writeHookOutput(map[string]bool{"continue": true})
+}
+
+func runHookPreCompact(cmd *cobra.Command, args []string) error {
+	_, in := readHookInput()
+	usage := "?"
+	if in.Usage != nil {
+		usage = strconv.Itoa(*in.Usage)
+	}
+	hookDebugLog("gastown-hooks.log", "preCompact: trigger=%s usage=%s%%", in.Trigger, usage)
+	return writeHookOutput(map[string]string{"user_message": hookCompactMessage})
+}
+
+func runHookStop(cmd *cobra.Command, args []string) error {
+	_, in := readHookInput()
+	hookDebugLog("gastown-hooks.log", "stop: status=%s", in.Status)
+	if inGasTown() {
+		_, _ = runGT("costs", "record")
+		syncBeads()
+	}
+	// No followup_message: don't auto-continue
+	return writeHookOutput(struct{}{})
+}
+
+func runHookSessionEnd(cmd *cobra.Command, args []string) error {
+	_, in := readHookInput()
+	duration := "?"
+	if in.DurationMS != nil {
+		duration = strconv.FormatInt(*in.DurationMS, 10)
+	}
+	hookDebugLog("gastown-hooks.log", "sessionEnd: reason=%s duration=%sms", in.Reason, duration)
+	if inGasTown() {
+		// Keyed by session so a retried hook is counted once
+		costArgs := []string{"costs", "record"}
+		if in.SessionID != "" {
+			costArgs = append(costArgs, "--idempotency-key", "session_end:"+in.SessionID)
+		}
+		_, _ = runGT(costArgs...)
+		syncBeads()
+	}
+	return nil
+}
+
+func runHookBeforeShell(cmd *cobra.Command, args []string) error {
+	_, in := readHookInput()
+	hookDebugLog("gastown-hooks.log", "beforeShell: %s", in.Command)
+	if inGasTown() {
+		// CLI mode checks mail on the first command (the IDE uses
+		// beforeSubmitPrompt instead)
+		dir := hookStateDir()
+		marker := filepath.Join(dir, "mail-checked")
+		if _, err := os.Stat(marker); os.IsNotExist(err) {
+			if err := os.MkdirAll(dir, 0700); err == nil {
+				_ = os.WriteFile(marker, nil, 0600)
+			}
+			startGT("mail", "check", "--inject")
+		}
+	}
+	return writeHookOutput(map[string]string{"permission": "allow"})
+}
+
+func runHookAfterShell(cmd *cobra.Command, args []string) error {
+	data, in := readHookInput()
+	duration := "?"
+	if in.Duration != nil {
+		duration = strconv.FormatInt(*in.Duration, 10)
+	}
+	hookDebugLog("gastown-hooks.log", "afterShell: %s (%sms)", in.Command, duration)
+	if !inGasTown() {
+		return nil
+	}
+	hookDebugLog("gastown-audit.log", "%s", data)
+	_ = captureReplayStep(replay.KindShell, data)
+
+	// CLI mode records costs periodically (the IDE uses the stop hook)
+	dir := hookStateDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil
+	}
+	countFile := filepath.Join(dir, "cmd-count")
+	raw, _ := os.ReadFile(countFile) //nolint:gosec // G304: path is in the session state dir
+	count, _ := strconv.Atoi(strings.TrimSpace(string(raw)))
+	count++
+	_ = os.WriteFile(countFile, []byte(strconv.Itoa(count)), 0600)
+	if count%hookCostsEvery == 0 {
+		startGT("costs", "record")
+	}
+	return nil
+}
+
+// runHookCapture records the edit or MCP call for gt replay.
+func runHookCapture(cmd *cobra.Command, args []string) error {
+	data, _ := readHookInput()
+	if !inGasTown() {
+		return nil
+	}
+	kind := replay.KindEdit
+	if cmd.Name() == "after-mcp" {
+		kind = replay.KindMCP
+	}
+	_ = captureReplayStep(kind, data)
+	return nil
+}
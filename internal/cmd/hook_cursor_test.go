@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// runHookWithInput runs a hook handler with input on stdin and returns what
+// it printed.
+func runHookWithInput(t *testing.T, run func(*cobra.Command, []string) error, c *cobra.Command, input string) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WriteString(input); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+	old := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() { os.Stdin = old })
+
+	var runErr error
+	out := captureStdout(t, func() { runErr = run(c, nil) })
+	if runErr != nil {
+		t.Fatalf("%s: %v", c.Name(), runErr)
+	}
+	return strings.TrimSpace(out)
+}
+
+func TestHookOutputs(t *testing.T) {
+	t.Setenv("GT_ROLE", "")
+
+	tests := []struct {
+		cmd   *cobra.Command
+		run   func(*cobra.Command, []string) error
+		input string
+		want  string
+	}{
+		{hookBeforeSubmitCmd, runHookBeforeSubmit, `{"prompt":"hi"}`, `{"continue":true}`},
+		{hookPreCompactCmd, runHookPreCompact, `{"trigger":"auto"}`, `{"user_message":"[Gas Town] Context compacting. Run ` + "`gt prime`" + ` after compaction to restore role context and check for mail."}`},
+		{hookStopCmd, runHookStop, `{"status":"completed"}`, `{}`},
+		{hookSessionEndCmd, runHookSessionEnd, `{"reason":"user_close"}`, ``},
+		{hookBeforeShellCmd, runHookBeforeShell, `{"command":"ls"}`, `{"permission":"allow"}`},
+		{hookAfterShellCmd, runHookAfterShell, `not json`, ``},
+	}
+	for _, tt := range tests {
+		if got := runHookWithInput(t, tt.run, tt.cmd, tt.input); got != tt.want {
+			t.Errorf("%s: output = %q, want %q", tt.cmd.Name(), got, tt.want)
+		}
+	}
+}
+
+func TestHookAfterShellCountsCommands(t *testing.T) {
+	t.Setenv("GT_ROLE", "polecat")
+	t.Setenv("GT_SESSION_ID", "test-"+filepath.Base(t.TempDir()))
+	t.Chdir(t.TempDir()) // Not in a town: nothing is captured
+	t.Cleanup(func() { _ = os.RemoveAll(hookStateDir()) })
+
+	for i := 0; i < 3; i++ {
+		runHookWithInput(t, runHookAfterShell, hookAfterShellCmd, `{"command":"ls","duration":5}`)
+	}
+	data, err := os.ReadFile(filepath.Join(hookStateDir(), "cmd-count"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "3" {
+		t.Errorf("cmd-count = %q, want 3", data)
+	}
+}
+
+func TestCursorHooksOutsideHookCommand(t *testing.T) {
+	// Prefix matching must resolve gt hook st to status; Cursor's stop and
+	// session-start handlers live under gt cursor-hook
+	cmd, _, err := rootCmd.Find([]string{"hook", "st"})
+	if err != nil || cmd != hookStatusCmd {
+		t.Errorf("gt hook st resolved to %v (err %v), want hook status", cmd.CommandPath(), err)
+	}
+	cmd, _, err = rootCmd.Find([]string{"cursor-hook", "stop"})
+	if err != nil || cmd != hookStopCmd {
+		t.Errorf("gt cursor-hook stop resolved to %v (err %v)", cmd.CommandPath(), err)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
 	"github.com/cursorworkshop/cursor-gastown/internal/style"
 	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
 )
@@ -40,8 +42,24 @@ Examples:
 	RunE: runHooks,
 }
 
+var hooksSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Re-sync agent hooks with this gt's templates",
+	Long: `Rewrite agent hooks that differ from the templates embedded in this gt
+binary. Run after upgrading gt ('gt self-update' does this for you).
+
+Hooks you added to hooks.json are kept. Gas Town hooks you edited are kept
+too and reported, rather than overwritten.
+
+Running sessions are not cycled; agents pick up the new hooks on their next
+start, or during the maintenance window with template_resync.auto_resync.`,
+	Args: cobra.NoArgs,
+	RunE: runHooksSync,
+}
+
 func init() {
 	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hooksSyncCmd)
 	hooksCmd.Flags().BoolVar(&hooksJSON, "json", false, "Output as JSON")
 	hooksCmd.Flags().BoolVarP(&hooksVerbose, "verbose", "v", false, "Show hook commands")
 }
@@ -100,6 +118,42 @@ func runHooks(cmd *cobra.Command, args []string) error {
 	return outputHooksHuman(townRoot, hooks)
 }
 
+// runHooksSync rewrites drifted hooks from the embedded templates.
+func runHooksSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	drifted := daemon.FindTemplateDrift(townRoot, discoverRigs(townRoot))
+	if len(drifted) == 0 {
+		fmt.Printf("%s All agent hooks match current templates\n", style.Success.Render("[OK]"))
+		return nil
+	}
+	var failed int
+	for _, t := range drifted {
+		version := cursor.InstalledSettingsVersion(t.WorkDir)
+		if err := cursor.EnsureHooksForRole(t.WorkDir, t.Role); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: re-syncing %s: %v\n", t.Agent, err)
+			failed++
+			continue
+		}
+		if version >= 0 && version < cursor.SettingsVersion {
+			fmt.Printf("  Re-synced %s %s\n", t.Agent, style.Dim.Render(fmt.Sprintf("(migrated settings v%d → v%d)", version, cursor.SettingsVersion)))
+		} else {
+			fmt.Printf("  Re-synced %s\n", t.Agent)
+		}
+		for _, conflict := range cursor.HookConflicts(t.WorkDir, t.Role) {
+			fmt.Printf("    %s kept edited hook %s\n", style.WarningPrefix, conflict)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d agent workspace(s) could not be re-synced", failed, len(drifted))
+	}
+	fmt.Printf("%s Re-synced hooks for %d agent workspace(s)\n", style.Success.Render("[OK]"), len(drifted))
+	return nil
+}
+
 // discoverHooks finds all Cursor hooks in the workspace.
 func discoverHooks(townRoot string) ([]HookInfo, error) {
 	var hooks []HookInfo
@@ -12,7 +12,10 @@ import (
 	"github.com/cursorworkshop/cursor-gastown/internal/style"
 )
 
-var initForce bool
+var (
+	initForce   bool
+	initProfile string
+)
 
 var initCmd = &cobra.Command{
 	Use:     "init",
@@ -24,16 +27,29 @@ This creates the standard agent directories (polecats/, witness/, refinery/,
 mayor/) and updates .git/info/exclude to ignore them.
 
 The current directory must be a git repository. Use --force to reinitialize
-an existing rig structure.`,
+an existing rig structure.
+
+With --from-profile, the current directory is instead created as a Gas Town
+HQ from a town profile (see 'gt town export-profile'), so every engineer can
+stamp out an identical town:
+
+  mkdir ~/gt && cd ~/gt && gt init --from-profile team.yaml`,
 	RunE: runInit,
 }
 
 func init() {
 	initCmd.Flags().BoolVarP(&initForce, "force", "f", false, "Reinitialize existing structure")
+	initCmd.Flags().StringVar(&initProfile, "from-profile", "", "Create a town here from a profile instead of a rig")
 	rootCmd.AddCommand(initCmd)
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
+	if initProfile != "" {
+		installProfile = initProfile
+		installForce = initForce
+		return runInstall(cmd, nil)
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("getting current directory: %w", err)
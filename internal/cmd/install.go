@@ -10,9 +10,9 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/agent"
 	"github.com/cursorworkshop/cursor-gastown/internal/beads"
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
-	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
 	"github.com/cursorworkshop/cursor-gastown/internal/deps"
 	"github.com/cursorworkshop/cursor-gastown/internal/formula"
 	"github.com/cursorworkshop/cursor-gastown/internal/style"
@@ -29,6 +29,7 @@ var (
 	installGit        bool
 	installGitHub     string
 	installPublic     bool
+	installProfile    string
 )
 
 var installCmd = &cobra.Command{
@@ -53,7 +54,11 @@ Examples:
   gt install ~/gt --no-beads                   # Skip .beads/ initialization
   gt install ~/gt --git                        # Also init git with .gitignore
   gt install ~/gt --github=user/repo           # Create private GitHub repo (default)
-  gt install ~/gt --github=user/repo --public  # Create public GitHub repo`,
+  gt install ~/gt --github=user/repo --public  # Create public GitHub repo
+  gt install ~/gt --from-profile team.yaml     # Stamp out a town from a profile
+
+A profile (see 'gt town export-profile') applies a reference town's settings,
+policies, agents, slash commands, and rigs to the new HQ.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInstall,
 }
@@ -67,6 +72,7 @@ func init() {
 	installCmd.Flags().BoolVar(&installGit, "git", false, "Initialize git with .gitignore")
 	installCmd.Flags().StringVar(&installGitHub, "github", "", "Create GitHub repo (format: owner/repo, private by default)")
 	installCmd.Flags().BoolVar(&installPublic, "public", false, "Make GitHub repo public (use with --github)")
+	installCmd.Flags().StringVar(&installProfile, "from-profile", "", "Apply a town profile exported with 'gt town export-profile'")
 	rootCmd.AddCommand(installCmd)
 }
 
@@ -102,6 +108,15 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("directory is already a Gas Town HQ (use --force to reinitialize)")
 	}
 
+	// Load the profile before creating anything so a bad one fails fast
+	var profile *config.TownProfile
+	if installProfile != "" {
+		profile, err = config.LoadTownProfile(installProfile)
+		if err != nil {
+			return fmt.Errorf("loading town profile: %w", err)
+		}
+	}
+
 	// Check if inside an existing workspace
 	if existingRoot, _ := workspace.Find(absPath); existingRoot != "" && existingRoot != absPath {
 		style.PrintWarning("Creating HQ inside existing workspace at %s", existingRoot)
@@ -177,20 +192,20 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	// mayorDir already defined above
 	if err := os.MkdirAll(mayorDir, 0755); err != nil {
 		fmt.Printf("   %s Could not create mayor directory: %v\n", style.Dim.Render("WARN"), err)
-	} else if err := cursor.EnsureSettingsForRole(mayorDir, "mayor"); err != nil {
+	} else if err := agent.EnsureSettingsForAllAgents(absPath, mayorDir, "mayor"); err != nil {
 		fmt.Printf("   %s Could not create mayor settings: %v\n", style.Dim.Render("WARN"), err)
 	} else {
-		fmt.Printf("   OK Created mayor/.cursor/ settings\n")
+		fmt.Printf("   OK Created mayor/ agent settings\n")
 	}
 
 	// Create deacon directory and settings (deacon runs from ~/gt/deacon/)
 	deaconDir := filepath.Join(absPath, "deacon")
 	if err := os.MkdirAll(deaconDir, 0755); err != nil {
 		fmt.Printf("   %s Could not create deacon directory: %v\n", style.Dim.Render("WARN"), err)
-	} else if err := cursor.EnsureSettingsForRole(deaconDir, "deacon"); err != nil {
+	} else if err := agent.EnsureSettingsForAllAgents(absPath, deaconDir, "deacon"); err != nil {
 		fmt.Printf("   %s Could not create deacon settings: %v\n", style.Dim.Render("WARN"), err)
 	} else {
-		fmt.Printf("   OK Created deacon/.cursor/ settings\n")
+		fmt.Printf("   OK Created deacon/ agent settings\n")
 	}
 
 	// Initialize git BEFORE beads so that bd can compute repository fingerprint.
@@ -248,6 +263,17 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   OK Created .cursor/commands/ (slash commands for all agents)\n")
 	}
 
+	// Record the town in the machine-wide registry (gt doctor --all-towns)
+	if err := workspace.RegisterTown(absPath); err != nil {
+		fmt.Printf("   %s Could not register town: %v\n", style.Dim.Render("WARN"), err)
+	}
+
+	if profile != nil {
+		if err := applyTownProfile(absPath, profile); err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("\n%s HQ created successfully!\n", style.Bold.Render("OK"))
 	fmt.Println()
 	fmt.Println("Next steps:")
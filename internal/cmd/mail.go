@@ -99,7 +99,8 @@ COMMANDS:
   inbox     View your inbox
   send      Send a message
   read      Read a specific message
-  mark      Mark messages read/unread`,
+  mark      Mark messages read/unread
+  away      Vacation mode: auto-reply and divert urgent mail`,
 }
 
 var mailSendCmd = &cobra.Command{
@@ -705,6 +706,11 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting message: %w", err)
 	}
 
+	// Overseer mail may be encrypted at rest; decrypt if we hold the key
+	if err := router.Decrypt(msg); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v (showing ciphertext)\n", style.Warning.Render("[!] Warning:"), err)
+	}
+
 	// Note: We intentionally do NOT mark as read/ack on read.
 	// User must explicitly delete/ack the message.
 	// This preserves handoff messages for reference.
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var (
+	mailAwayMessage  string
+	mailAwayUntil    string
+	mailAwayFallback string
+)
+
+var mailAwayCmd = &cobra.Command{
+	Use:   "away",
+	Short: "Manage vacation mode (auto-reply) for inboxes",
+	Long: `Put an inbox in vacation mode so senders don't silently wait on it.
+
+While an agent is away:
+  - Each sender gets an auto-reply with the status and expected return
+    (at most once per sender per day)
+  - Urgent mail (--urgent / priority 0) is diverted to the fallback recipient
+  - Other mail is still delivered and waits in the inbox
+
+An entry can name a single agent (gastown/crew/joe) or a whole rig (gastown).
+Agents in a parked or docked rig are treated as away automatically; add a rig
+entry to give them a message and fallback.
+
+Entries are stored in config/messaging.json.
+
+Examples:
+  gt mail away                                   # List away inboxes
+  gt mail away set gastown/crew/joe -m "On leave" --until 2026-03-01 --fallback gastown/crew/max
+  gt mail away set gastown --fallback mayor/     # Fallback for the whole rig
+  gt mail away clear gastown/crew/joe`,
+	Args: cobra.NoArgs,
+	RunE: runMailAwayList,
+}
+
+var mailAwaySetCmd = &cobra.Command{
+	Use:   "set <address|rig>",
+	Short: "Mark an inbox as away",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMailAwaySet,
+}
+
+var mailAwayClearCmd = &cobra.Command{
+	Use:   "clear <address|rig>",
+	Short: "Take an inbox out of vacation mode",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMailAwayClear,
+}
+
+func init() {
+	mailAwaySetCmd.Flags().StringVarP(&mailAwayMessage, "message", "m", "", "Status included in auto-replies")
+	mailAwaySetCmd.Flags().StringVar(&mailAwayUntil, "until", "", "Expected return (2006-01-02, RFC3339, or a duration like 3d)")
+	mailAwaySetCmd.Flags().StringVar(&mailAwayFallback, "fallback", "", "Recipient for urgent mail while away")
+
+	mailAwayCmd.AddCommand(mailAwaySetCmd)
+	mailAwayCmd.AddCommand(mailAwayClearCmd)
+	mailCmd.AddCommand(mailAwayCmd)
+}
+
+// loadMailAwayConfig loads the town messaging config for editing.
+func loadMailAwayConfig() (string, *config.MessagingConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	path := config.MessagingConfigPath(townRoot)
+	cfg, err := config.LoadOrCreateMessagingConfig(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("loading messaging config: %w", err)
+	}
+	if cfg.Away == nil {
+		cfg.Away = make(map[string]config.AwayConfig)
+	}
+	return path, cfg, nil
+}
+
+func runMailAwaySet(cmd *cobra.Command, args []string) error {
+	path, cfg, err := loadMailAwayConfig()
+	if err != nil {
+		return err
+	}
+
+	away := config.AwayConfig{
+		Message:  mailAwayMessage,
+		Fallback: mailAwayFallback,
+	}
+	if mailAwayUntil != "" {
+		until, err := parseAwayUntil(mailAwayUntil, time.Now())
+		if err != nil {
+			return err
+		}
+		away.Until = &until
+	}
+
+	cfg.Away[args[0]] = away
+	if err := config.SaveMessagingConfig(path, cfg); err != nil {
+		return fmt.Errorf("saving messaging config: %w", err)
+	}
+
+	fmt.Printf("%s %s is away\n", style.SuccessPrefix, args[0])
+	printMailAway(away)
+	return nil
+}
+
+func runMailAwayClear(cmd *cobra.Command, args []string) error {
+	path, cfg, err := loadMailAwayConfig()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := cfg.Away[args[0]]; !ok {
+		return fmt.Errorf("%s is not marked away", args[0])
+	}
+	delete(cfg.Away, args[0])
+	if err := config.SaveMessagingConfig(path, cfg); err != nil {
+		return fmt.Errorf("saving messaging config: %w", err)
+	}
+
+	fmt.Printf("%s %s is back\n", style.SuccessPrefix, args[0])
+	return nil
+}
+
+func runMailAwayList(cmd *cobra.Command, args []string) error {
+	_, cfg, err := loadMailAwayConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Away) == 0 {
+		fmt.Println("No inboxes marked away.")
+		fmt.Println(style.Dim.Render("Agents in parked or docked rigs auto-reply without an entry."))
+		return nil
+	}
+
+	addresses := make([]string, 0, len(cfg.Away))
+	for address := range cfg.Away {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	for _, address := range addresses {
+		fmt.Printf("%s\n", style.Bold.Render(address))
+		printMailAway(cfg.Away[address])
+	}
+	return nil
+}
+
+func printMailAway(away config.AwayConfig) {
+	if away.Message != "" {
+		fmt.Printf("  Message:  %s\n", away.Message)
+	}
+	if away.Until != nil {
+		note := ""
+		if time.Now().After(*away.Until) {
+			note = style.Dim.Render(" (lapsed)")
+		}
+		fmt.Printf("  Until:    %s%s\n", away.Until.Local().Format("2006-01-02 15:04"), note)
+	}
+	if away.Fallback != "" {
+		fmt.Printf("  Fallback: %s\n", away.Fallback)
+	}
+}
+
+// parseAwayUntil parses an expected return as a date, an RFC3339 time, or a
+// duration from now (supports days, e.g. "3d").
+func parseAwayUntil(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if d, err := parseDuration(s); err == nil {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --until %q: use 2006-01-02, RFC3339, or a duration like 3d", s)
+}
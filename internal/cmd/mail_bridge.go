@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/emailbridge"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var mailBridgeQuiet bool
+
+var mailBridgeCmd = &cobra.Command{
+	Use:   "bridge",
+	Short: "Handle escalations from your real email",
+	Long: `Bridge the overseer inbox to your real email.
+
+When email_bridge is set in settings/config.json, each sync:
+  - emails new escalations in the overseer inbox to the operator address
+    (all overseer mail with forward_all)
+  - reads replies from the dedicated reply mailbox or label over IMAP and
+    delivers each one to the escalating agent as mail from the overseer,
+    marking the escalation read
+
+Only replies from the operator address to a forwarded escalation are
+accepted. Escalations older than a day when the bridge first sees them are
+not forwarded, and encrypted bodies are never sent by email.
+
+The daemon syncs on every heartbeat; 'gt mail bridge sync' syncs now.
+
+Example settings/config.json:
+  "email_bridge": {
+    "operator": "me@example.com",
+    "from": "gastown@example.com",
+    "reply_to": "gastown+replies@example.com",
+    "mailbox": "gastown-replies",
+    "smtp": {"host": "smtp.example.com", "username": "gastown@example.com",
+             "password": "secretRef:keychain:gastown/email"},
+    "imap": {"host": "imap.example.com", "username": "gastown@example.com",
+             "password": "secretRef:keychain:gastown/email"}
+  }
+
+Examples:
+  gt mail bridge          # Show bridge status
+  gt mail bridge test     # Send a test email and check the reply mailbox
+  gt mail bridge sync     # Forward escalations and ingest replies now`,
+	Args: cobra.NoArgs,
+	RunE: runMailBridgeStatus,
+}
+
+var mailBridgeSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Forward escalations and ingest replies now",
+	Args:  cobra.NoArgs,
+	RunE:  runMailBridgeSync,
+}
+
+var mailBridgeTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a test email and log in to the reply mailbox",
+	Args:  cobra.NoArgs,
+	RunE:  runMailBridgeTest,
+}
+
+func init() {
+	mailBridgeSyncCmd.Flags().BoolVarP(&mailBridgeQuiet, "quiet", "q", false, "Only print mail that was forwarded or delivered")
+
+	mailBridgeCmd.AddCommand(mailBridgeSyncCmd)
+	mailBridgeCmd.AddCommand(mailBridgeTestCmd)
+	mailCmd.AddCommand(mailBridgeCmd)
+}
+
+// loadMailBridge returns the town's email bridge. ok is false when the
+// bridge is not configured.
+func loadMailBridge() (townRoot string, bridge *emailbridge.Bridge, ok bool, err error) {
+	townRoot, err = workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, false, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bridge, err = emailbridge.ForTown(townRoot)
+	if errors.Is(err, emailbridge.ErrNotConfigured) {
+		return townRoot, nil, false, nil
+	}
+	if err != nil {
+		return "", nil, false, err
+	}
+	return townRoot, bridge, true, nil
+}
+
+func runMailBridgeStatus(cmd *cobra.Command, args []string) error {
+	townRoot, _, ok, err := loadMailBridge()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println(style.Dim.Render("No email_bridge configured in settings/config.json"))
+		return nil
+	}
+	state, err := emailbridge.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render("Email bridge"))
+	if state.LastSync.IsZero() {
+		fmt.Printf("  Last sync: %s\n", style.Dim.Render("never"))
+	} else {
+		fmt.Printf("  Last sync: %s ago\n", time.Since(state.LastSync).Round(time.Second))
+	}
+	replied := 0
+	for _, f := range state.Forwarded {
+		if len(f.Replies) > 0 {
+			replied++
+		}
+	}
+	fmt.Printf("  Forwarded: %d (%d answered by email)\n", len(state.Forwarded), replied)
+	return nil
+}
+
+func runMailBridgeSync(cmd *cobra.Command, args []string) error {
+	_, bridge, ok, err := loadMailBridge()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if !mailBridgeQuiet {
+			fmt.Println(style.Dim.Render("No email_bridge configured in settings/config.json"))
+		}
+		return nil
+	}
+
+	result, syncErr := bridge.Sync(context.Background())
+	if result != nil {
+		for _, msg := range result.Forwarded {
+			fmt.Printf("%s Forwarded %s: %s\n", style.SuccessPrefix, msg.ID, msg.Subject)
+		}
+		for _, msg := range result.Delivered {
+			fmt.Printf("%s Delivered reply to %s: %s\n", style.SuccessPrefix, msg.To, msg.Subject)
+		}
+		if !mailBridgeQuiet {
+			for _, reason := range result.Rejected {
+				fmt.Printf("%s Skipped %s\n", style.WarningPrefix, reason)
+			}
+			if len(result.Forwarded)+len(result.Delivered) == 0 && syncErr == nil {
+				fmt.Printf("%s Nothing to forward or deliver\n", style.SuccessPrefix)
+			}
+		}
+	}
+	return syncErr
+}
+
+func runMailBridgeTest(cmd *cobra.Command, args []string) error {
+	_, bridge, ok, err := loadMailBridge()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return emailbridge.ErrNotConfigured
+	}
+	if err := bridge.Test(context.Background()); err != nil {
+		return err
+	}
+	fmt.Printf("%s Test email sent and reply mailbox reachable\n", style.SuccessPrefix)
+	return nil
+}
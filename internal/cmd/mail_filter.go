@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+)
+
+var (
+	mailFilterTypes    []string
+	mailFilterSubjects []string
+	mailFilterFrom     []string
+)
+
+var mailFilterCmd = &cobra.Command{
+	Use:   "filter",
+	Short: "Manage per-role inbound mail filters",
+	Long: `Restrict the mail each role accepts, keeping agent inboxes on-protocol.
+
+A filter lists what a role accepts: message types (--type), subject
+keywords (--subject, case-insensitive), and senders (--from, wildcards
+allowed). A message matching any rule is delivered. Anything else is
+bounced to the sender with the reason and is never delivered; 'gt mail send'
+reports the rejection.
+
+Mail from the overseer, self-mail (handoffs), lifecycle messages
+(POLECAT_DONE, NUDGE, ...), bounces, and auto-replies always pass.
+
+Roles: polecat, crew, witness, refinery, mayor, deacon.
+Filters are stored in config/messaging.json.
+
+Examples:
+  gt mail filter                                             # List filters
+  gt mail filter set polecat --type task --type reply --from '*/witness'
+  gt mail filter set witness --subject escalation --subject merge --from mayor/
+  gt mail filter clear polecat`,
+	Args: cobra.NoArgs,
+	RunE: runMailFilterList,
+}
+
+var mailFilterSetCmd = &cobra.Command{
+	Use:   "set <role>",
+	Short: "Set the mail filter for a role",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMailFilterSet,
+}
+
+var mailFilterClearCmd = &cobra.Command{
+	Use:   "clear <role>",
+	Short: "Remove the mail filter for a role",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMailFilterClear,
+}
+
+func init() {
+	mailFilterSetCmd.Flags().StringArrayVar(&mailFilterTypes, "type", nil, "Accepted message type (task, scavenge, notification, reply; repeatable)")
+	mailFilterSetCmd.Flags().StringArrayVar(&mailFilterSubjects, "subject", nil, "Accepted subject keyword (repeatable)")
+	mailFilterSetCmd.Flags().StringArrayVar(&mailFilterFrom, "from", nil, "Sender always accepted, e.g. mayor/ or '*/witness' (repeatable)")
+
+	mailFilterCmd.AddCommand(mailFilterSetCmd)
+	mailFilterCmd.AddCommand(mailFilterClearCmd)
+	mailCmd.AddCommand(mailFilterCmd)
+}
+
+func runMailFilterSet(cmd *cobra.Command, args []string) error {
+	path, cfg, err := loadMailAwayConfig()
+	if err != nil {
+		return err
+	}
+	if len(mailFilterTypes) == 0 && len(mailFilterSubjects) == 0 && len(mailFilterFrom) == 0 {
+		return fmt.Errorf("a filter needs at least one --type, --subject, or --from rule")
+	}
+
+	filter := config.MailFilterConfig{
+		Types:    mailFilterTypes,
+		Subjects: mailFilterSubjects,
+		From:     mailFilterFrom,
+	}
+	if cfg.Filters == nil {
+		cfg.Filters = make(map[string]config.MailFilterConfig)
+	}
+	cfg.Filters[args[0]] = filter
+	if err := config.SaveMessagingConfig(path, cfg); err != nil {
+		return fmt.Errorf("saving messaging config: %w", err)
+	}
+
+	fmt.Printf("%s %s inboxes are filtered\n", style.SuccessPrefix, args[0])
+	printMailFilter(filter)
+	return nil
+}
+
+func runMailFilterClear(cmd *cobra.Command, args []string) error {
+	path, cfg, err := loadMailAwayConfig()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := cfg.Filters[args[0]]; !ok {
+		return fmt.Errorf("%s has no mail filter", args[0])
+	}
+	delete(cfg.Filters, args[0])
+	if err := config.SaveMessagingConfig(path, cfg); err != nil {
+		return fmt.Errorf("saving messaging config: %w", err)
+	}
+
+	fmt.Printf("%s %s inboxes accept all mail\n", style.SuccessPrefix, args[0])
+	return nil
+}
+
+func runMailFilterList(cmd *cobra.Command, args []string) error {
+	_, cfg, err := loadMailAwayConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Filters) == 0 {
+		fmt.Println("No mail filters; every role accepts all mail.")
+		return nil
+	}
+
+	roles := make([]string, 0, len(cfg.Filters))
+	for role := range cfg.Filters {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	for _, role := range roles {
+		fmt.Printf("%s\n", style.Bold.Render(role))
+		printMailFilter(cfg.Filters[role])
+	}
+	return nil
+}
+
+func printMailFilter(filter config.MailFilterConfig) {
+	if len(filter.Types) > 0 {
+		fmt.Printf("  Types:    %s\n", strings.Join(filter.Types, ", "))
+	}
+	if len(filter.Subjects) > 0 {
+		fmt.Printf("  Subjects: %s\n", strings.Join(filter.Subjects, ", "))
+	}
+	if len(filter.From) > 0 {
+		fmt.Printf("  From:     %s\n", strings.Join(filter.From, ", "))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
 )
@@ -381,3 +382,20 @@ func TestAnnounceMessageParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAwayUntil(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if got, err := parseAwayUntil("3d", now); err != nil || !got.Equal(now.Add(72*time.Hour)) {
+		t.Errorf("parseAwayUntil(3d) = %v, %v", got, err)
+	}
+	if got, err := parseAwayUntil("2026-03-01T09:00:00Z", now); err != nil || got.Day() != 1 || got.Month() != time.March {
+		t.Errorf("parseAwayUntil(RFC3339) = %v, %v", got, err)
+	}
+	if got, err := parseAwayUntil("2026-03-01", now); err != nil || got.Year() != 2026 || got.Month() != time.March {
+		t.Errorf("parseAwayUntil(date) = %v, %v", got, err)
+	}
+	if _, err := parseAwayUntil("next week", now); err == nil {
+		t.Error("expected error for unparseable --until")
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/mcp"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:     "mcp",
+	GroupID: GroupServices,
+	Short:   "Gas Town MCP server for agents",
+	RunE:    requireSubcommand,
+}
+
+var mcpServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve Gas Town tools to an agent over MCP (stdio)",
+	Long: `Run the Gas Town MCP server on stdin/stdout. Agents' MCP clients start it;
+it is not meant to be run by hand.
+
+Tools:
+  search   Search code across every rig's checkout (see gt search)
+
+Add it to every agent's .cursor/mcp.json through the town settings
+(settings/config.json):
+
+  "mcp": {"servers": {"gastown": {"command": "gt", "args": ["mcp", "serve"]}}}
+
+The server finds the town from its working directory, which is the agent's
+workspace.`,
+	Args: cobra.NoArgs,
+	RunE: runMCPServe,
+}
+
+func init() {
+	mcpCmd.AddCommand(mcpServeCmd)
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCPServe(cmd *cobra.Command, args []string) error {
+	server := mcp.NewServer("gastown", Version)
+	server.AddTool(mcp.Tool{
+		Name: "search",
+		Description: "Search code across every rig (repository) in the Gas Town workspace. " +
+			"Use it to find where a symbol shared between rigs is defined or used. " +
+			"Returns matches as rig/path:line: text.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"pattern":     map[string]any{"type": "string", "description": "Regular expression to search for"},
+				"rigs":        map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Only search these rigs (default: all)"},
+				"glob":        map[string]any{"type": "string", "description": "Only search files matching this glob, e.g. *.go"},
+				"ignore_case": map[string]any{"type": "boolean", "description": "Match case-insensitively"},
+				"fixed":       map[string]any{"type": "boolean", "description": "Treat pattern as literal text"},
+				"limit":       map[string]any{"type": "integer", "description": fmt.Sprintf("Maximum matches (default %d)", rig.DefaultSearchLimit)},
+			},
+			"required": []string{"pattern"},
+		},
+		Handler: mcpSearch,
+	})
+	return server.Serve(os.Stdin, os.Stdout)
+}
+
+// mcpSearch runs the search tool.
+func mcpSearch(args json.RawMessage) (string, error) {
+	var in struct {
+		Pattern    string   `json:"pattern"`
+		Rigs       []string `json:"rigs"`
+		Glob       string   `json:"glob"`
+		IgnoreCase bool     `json:"ignore_case"`
+		Fixed      bool     `json:"fixed"`
+		Limit      int      `json:"limit"`
+	}
+	if err := json.Unmarshal(args, &in); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if in.Pattern == "" {
+		return "", errors.New("pattern is required")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	result, err := searchTown(townRoot, rig.SearchOptions{
+		Pattern:    in.Pattern,
+		Rigs:       in.Rigs,
+		Glob:       in.Glob,
+		IgnoreCase: in.IgnoreCase,
+		Fixed:      in.Fixed,
+		Limit:      in.Limit,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(result.Matches) == 0 {
+		return "No matches.", nil
+	}
+	text := formatSearchMatches(result)
+	if result.Truncated {
+		text += fmt.Sprintf("(first %d matches shown; narrow with rigs or glob, or raise limit)\n", len(result.Matches))
+	}
+	return text, nil
+}
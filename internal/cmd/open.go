@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/checkpoint"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// Open command flags
+var (
+	openEditor string
+	openFile   bool
+	openPrint  bool
+)
+
+var openCmd = &cobra.Command{
+	Use:     "open <agent>",
+	GroupID: GroupWorkspace,
+	Short:   "Open an agent's workdir, mail, or handoff in your editor",
+	Long: `Open an agent's working directory in your editor.
+
+The agent can be given as an address (mayor, deacon, <rig>/witness,
+<rig>/refinery, <rig>/crew/<name>, <rig>/polecats/<name>, <rig>/<polecat>)
+or as a tmux session name (gt-<rig>-<name>).
+
+With --file, also opens the file the agent was last working on, taken from
+its session checkpoint (the first modified file that still exists).
+
+The editor is chosen in this order: --editor, cursor, code, $VISUAL, $EDITOR.
+
+Examples:
+  gt open greenplace/Toast              # Open a polecat's worktree
+  gt open beads/crew/dave --file        # Worktree plus the file in progress
+  gt open gt-greenplace-witness         # By session name
+  gt open mayor --editor vim            # Use a specific editor
+  gt open greenplace/Toast --print      # Just print the path
+  gt open mail hq-abc                   # Open a mail message
+  gt open handoff beads/crew/dave       # Open the agent's latest handoff`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOpen,
+}
+
+var openMailCmd = &cobra.Command{
+	Use:   "mail <message-id>",
+	Short: "Open a mail message in your editor",
+	Long: `Render a mail message to a markdown file and open it in your editor.
+
+The file is written to <town>/.runtime/open/ and is a read-only copy:
+editing it does not change the message.
+
+Encrypted messages are never written into the town: the decrypted copy goes
+to a private temp file that is removed when the editor exits (cursor and
+code are run with --wait). --print is refused for them; use 'gt mail read'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOpenMail,
+}
+
+var openHandoffCmd = &cobra.Command{
+	Use:   "handoff <agent>",
+	Short: "Open an agent's latest handoff message in your editor",
+	Long: `Find the newest handoff mail (HANDOFF in the subject) in an agent's inbox
+and open it in your editor.
+
+The agent is an address or session name, as for 'gt open'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOpenHandoff,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{openCmd, openMailCmd, openHandoffCmd} {
+		c.Flags().StringVarP(&openEditor, "editor", "e", "", "Editor command to use (default: cursor, code, $VISUAL, $EDITOR)")
+		c.Flags().BoolVar(&openPrint, "print", false, "Print the path instead of opening it")
+	}
+	openCmd.Flags().BoolVarP(&openFile, "file", "f", false, "Also open the agent's current file from its checkpoint")
+
+	openCmd.AddCommand(openMailCmd)
+	openCmd.AddCommand(openHandoffCmd)
+	rootCmd.AddCommand(openCmd)
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	id, err := resolveOpenAgent(args[0])
+	if err != nil {
+		return err
+	}
+	workDir, err := agentWorkDir(townRoot, id)
+	if err != nil {
+		return err
+	}
+
+	paths := []string{workDir}
+	if openFile {
+		file, err := checkpointCurrentFile(workDir)
+		if err != nil {
+			return err
+		}
+		if file == "" {
+			style.PrintWarning("no current file recorded for %s; opening workdir only", id.Address())
+		} else {
+			paths = append(paths, file)
+		}
+	}
+	return openPaths(paths, false)
+}
+
+func runOpenMail(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	router := mail.NewRouter(townRoot)
+	mailbox, err := router.GetMailbox(detectSender())
+	if err != nil {
+		return fmt.Errorf("getting mailbox: %w", err)
+	}
+	msg, err := mailbox.Get(args[0])
+	if err != nil {
+		return fmt.Errorf("getting message: %w", err)
+	}
+	return openMessage(townRoot, router, msg)
+}
+
+func runOpenHandoff(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	id, err := resolveOpenAgent(args[0])
+	if err != nil {
+		return err
+	}
+	router := mail.NewRouter(townRoot)
+	mailbox, err := router.GetMailbox(id.Address())
+	if err != nil {
+		return fmt.Errorf("getting mailbox: %w", err)
+	}
+	messages, err := mailbox.List()
+	if err != nil {
+		return fmt.Errorf("listing mail: %w", err)
+	}
+
+	msg := latestHandoff(messages)
+	if msg == nil {
+		return fmt.Errorf("no handoff message for %s (handoff mail has HANDOFF in the subject)", id.Address())
+	}
+	return openMessage(townRoot, router, msg)
+}
+
+// resolveOpenAgent resolves an agent address, role shortcut, or session name
+// to an agent identity.
+func resolveOpenAgent(target string) (*session.AgentIdentity, error) {
+	sessionName, err := resolveRoleToSession(target)
+	if err != nil {
+		return nil, err
+	}
+	id, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return nil, fmt.Errorf("unknown agent %q: %w", target, err)
+	}
+	return id, nil
+}
+
+// agentWorkDir returns the directory an agent's session runs in.
+func agentWorkDir(townRoot string, id *session.AgentIdentity) (string, error) {
+	rigPath := filepath.Join(townRoot, id.Rig)
+	var candidates []string
+	switch id.Role {
+	case session.RoleMayor:
+		candidates = []string{filepath.Join(townRoot, "mayor")}
+	case session.RoleDeacon:
+		candidates = []string{filepath.Join(townRoot, "deacon")}
+	case session.RoleWitness:
+		candidates = []string{filepath.Join(rigPath, "witness", "rig"), filepath.Join(rigPath, "witness")}
+	case session.RoleRefinery:
+		candidates = []string{filepath.Join(rigPath, "refinery", "rig"), filepath.Join(rigPath, "refinery")}
+	case session.RoleCrew:
+		candidates = []string{filepath.Join(rigPath, "crew", id.Name)}
+	case session.RolePolecat:
+		candidates = []string{findDirFold(filepath.Join(rigPath, "polecats"), id.Name)}
+	}
+
+	for _, dir := range candidates {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("no workdir for %s (looked in %s)", id.Address(), strings.Join(candidates, ", "))
+}
+
+// findDirFold returns parent/name, matching name case-insensitively when
+// there is no exact match. Session names lowercase polecat names, while
+// polecat directories keep their original case.
+func findDirFold(parent, name string) string {
+	exact := filepath.Join(parent, name)
+	if _, err := os.Stat(exact); err == nil {
+		return exact
+	}
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return exact
+	}
+	for _, e := range entries {
+		if e.IsDir() && strings.EqualFold(e.Name(), name) {
+			return filepath.Join(parent, e.Name())
+		}
+	}
+	return exact
+}
+
+// checkpointCurrentFile returns the first modified file recorded in the
+// agent's checkpoint that still exists, or "" if there is none.
+func checkpointCurrentFile(workDir string) (string, error) {
+	cp, err := checkpoint.Read(workDir)
+	if err != nil || cp == nil {
+		return "", err
+	}
+	for _, f := range cp.ModifiedFiles {
+		// Renames are recorded as "old -> new".
+		if _, renamed, ok := strings.Cut(f, " -> "); ok {
+			f = renamed
+		}
+		path := filepath.Join(workDir, strings.Trim(f, `"`))
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// latestHandoff returns the newest message with HANDOFF in its subject.
+func latestHandoff(messages []*mail.Message) *mail.Message {
+	var latest *mail.Message
+	for _, msg := range messages {
+		if !containsHandoff(msg.Subject) {
+			continue
+		}
+		if latest == nil || msg.Timestamp.After(latest.Timestamp) {
+			latest = msg
+		}
+	}
+	return latest
+}
+
+// openMessage renders a message and opens it in the editor. Plaintext mail
+// is written under <town>/.runtime/open/. Decrypted mail is written to a
+// private temp directory outside the town instead, and removed once the
+// editor exits, so encryption at rest is not undone by opening a message.
+func openMessage(townRoot string, router *mail.Router, msg *mail.Message) error {
+	encrypted := mail.IsEncrypted(msg.Body)
+	if encrypted {
+		if err := router.Decrypt(msg); err != nil {
+			// The ciphertext is safe to write where plaintext mail goes.
+			style.PrintWarning("%v; opening ciphertext", err)
+			encrypted = false
+		}
+	}
+
+	if !encrypted {
+		path, err := writeOpenMessage(filepath.Join(townRoot, ".runtime", "open"), msg, 0644)
+		if err != nil {
+			return err
+		}
+		return openPaths([]string{path}, false)
+	}
+
+	if openPrint {
+		return fmt.Errorf("message %s is encrypted and is not written to disk; read it with 'gt mail read %s'", msg.ID, msg.ID)
+	}
+	dir, err := os.MkdirTemp("", "gt-open-*")
+	if err != nil {
+		return fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path, err := writeOpenMessage(dir, msg, 0600)
+	if err != nil {
+		return err
+	}
+	return openPaths([]string{path}, true)
+}
+
+// writeOpenMessage renders a message as markdown in dir with the given file
+// mode and returns the file path.
+func writeOpenMessage(dir string, msg *mail.Message, perm os.FileMode) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", dir, err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", msg.Subject)
+	fmt.Fprintf(&b, "- From: %s\n", msg.From)
+	fmt.Fprintf(&b, "- To: %s\n", msg.To)
+	if len(msg.CC) > 0 {
+		fmt.Fprintf(&b, "- CC: %s\n", strings.Join(msg.CC, ", "))
+	}
+	fmt.Fprintf(&b, "- Date: %s\n", msg.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "- ID: %s\n", msg.ID)
+	if msg.ThreadID != "" {
+		fmt.Fprintf(&b, "- Thread: %s\n", msg.ThreadID)
+	}
+	if msg.Body != "" {
+		fmt.Fprintf(&b, "\n%s\n", strings.TrimRight(msg.Body, "\n"))
+	}
+
+	path := filepath.Join(dir, msg.ID+".md")
+	if err := os.WriteFile(path, []byte(b.String()), perm); err != nil {
+		return "", fmt.Errorf("writing %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// resolveEditor returns the editor command to run: the --editor flag, then
+// cursor or code if installed, then $VISUAL or $EDITOR.
+func resolveEditor(flag string) ([]string, error) {
+	if fields := strings.Fields(flag); len(fields) > 0 {
+		return fields, nil
+	}
+	for _, bin := range []string{"cursor", "code"} {
+		if _, err := exec.LookPath(bin); err == nil {
+			return []string{bin}, nil
+		}
+	}
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields, nil
+		}
+	}
+	return nil, fmt.Errorf("no editor found: install cursor or code, set $EDITOR, or pass --editor")
+}
+
+// openPaths opens paths in the editor, or prints them with --print.
+// Terminal editors run in the foreground; GUI editors return immediately
+// unless wait is set, which runs cursor and code with --wait.
+func openPaths(paths []string, wait bool) error {
+	if openPrint {
+		for _, p := range paths {
+			fmt.Println(p)
+		}
+		return nil
+	}
+
+	editor, err := resolveEditor(openEditor)
+	if err != nil {
+		return err
+	}
+	if wait && editorSupportsWait(editor[0]) && !slices.Contains(editor[1:], "--wait") {
+		editor = append(editor, "--wait")
+	}
+	c := exec.Command(editor[0], append(editor[1:], paths...)...) //nolint:gosec // G204: editor is chosen by the user
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("running %s: %w", editor[0], err)
+	}
+	return nil
+}
+
+// editorSupportsWait reports whether the editor is a GUI editor that takes
+// --wait to block until the file is closed.
+func editorSupportsWait(bin string) bool {
+	name := strings.TrimSuffix(filepath.Base(bin), filepath.Ext(bin))
+	return name == "cursor" || name == "code"
+}
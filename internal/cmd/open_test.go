@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/checkpoint"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+)
+
+func TestAgentWorkDir(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, dir := range []string{"mayor", "gp/witness", "gp/refinery/rig", "gp/crew/dave", "gp/polecats/Toast"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"mayor", "mayor"},
+		{"gp/witness", "gp/witness"},
+		{"gp/refinery", "gp/refinery/rig"},
+		{"gp/crew/dave", "gp/crew/dave"},
+		{"gp/Toast", "gp/polecats/Toast"},
+		{"gt-gp-toast", "gp/polecats/Toast"},
+	}
+	for _, tt := range tests {
+		id, err := resolveOpenAgent(tt.target)
+		if err != nil {
+			t.Fatalf("resolveOpenAgent(%q): %v", tt.target, err)
+		}
+		got, err := agentWorkDir(townRoot, id)
+		if err != nil {
+			t.Fatalf("agentWorkDir(%q): %v", tt.target, err)
+		}
+		if want := filepath.Join(townRoot, tt.want); got != want {
+			t.Errorf("agentWorkDir(%q) = %s, want %s", tt.target, got, want)
+		}
+	}
+
+	id, _ := resolveOpenAgent("gp/polecats/Nux")
+	if _, err := agentWorkDir(townRoot, id); err == nil {
+		t.Error("expected error for missing polecat")
+	}
+}
+
+func TestCheckpointCurrentFile(t *testing.T) {
+	workDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workDir, "new.go"), []byte("package x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cp := &checkpoint.Checkpoint{ModifiedFiles: []string{"deleted.go", "old.go -> new.go"}}
+	if err := checkpoint.Write(workDir, cp); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := checkpointCurrentFile(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(workDir, "new.go"); got != want {
+		t.Errorf("current file = %q, want %q", got, want)
+	}
+}
+
+func TestLatestHandoffAndRender(t *testing.T) {
+	now := time.Now()
+	messages := []*mail.Message{
+		{ID: "hq-1", Subject: "🤝 HANDOFF: old", Timestamp: now.Add(-time.Hour)},
+		{ID: "hq-2", Subject: "🤝 HANDOFF: new", Body: "Continue with step 3.", Timestamp: now},
+		{ID: "hq-3", Subject: "status update", Timestamp: now.Add(time.Hour)},
+	}
+	msg := latestHandoff(messages)
+	if msg == nil || msg.ID != "hq-2" {
+		t.Fatalf("latestHandoff = %+v, want hq-2", msg)
+	}
+
+	dir := t.TempDir()
+	path, err := writeOpenMessage(dir, msg, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# 🤝 HANDOFF: new\n") || !strings.Contains(string(data), "Continue with step 3.") {
+		t.Errorf("rendered message = %q", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("rendered message mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestResolveEditor(t *testing.T) {
+	got, err := resolveEditor("vim -R")
+	if err != nil || strings.Join(got, " ") != "vim -R" {
+		t.Errorf("flag editor = %v, %v", got, err)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano")
+	if got, err := resolveEditor(""); err != nil || got[0] != "nano" {
+		t.Errorf("$EDITOR fallback = %v, %v", got, err)
+	}
+	t.Setenv("EDITOR", "")
+	if _, err := resolveEditor(""); err == nil {
+		t.Error("expected error with no editor available")
+	}
+}
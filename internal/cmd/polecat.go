@@ -14,6 +14,7 @@ import (
 	"github.com/cursorworkshop/cursor-gastown/internal/beads"
 	"github.com/cursorworkshop/cursor-gastown/internal/git"
 	"github.com/cursorworkshop/cursor-gastown/internal/polecat"
+	"github.com/cursorworkshop/cursor-gastown/internal/progress"
 	"github.com/cursorworkshop/cursor-gastown/internal/rig"
 	"github.com/cursorworkshop/cursor-gastown/internal/style"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
@@ -316,6 +317,9 @@ type PolecatListItem struct {
 	State          polecat.State `json:"state"`
 	Issue          string        `json:"issue,omitempty"`
 	SessionRunning bool          `json:"session_running"`
+
+	// Progress is the polecat's latest 'gt progress report', if any.
+	Progress *progress.Report `json:"progress,omitempty"`
 }
 
 // getPolecatManager creates a polecat manager for the given rig.
@@ -370,12 +374,14 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 
 		for _, p := range polecats {
 			running, _ := polecatMgr.IsRunning(p.Name)
+			report, _ := progress.Read(filepath.Join(r.Path, "polecats", p.Name))
 			allPolecats = append(allPolecats, PolecatListItem{
 				Rig:            r.Name,
 				Name:           p.Name,
 				State:          p.State,
 				Issue:          p.Issue,
 				SessionRunning: running,
+				Progress:       report,
 			})
 		}
 	}
@@ -420,6 +426,14 @@ func runPolecatList(cmd *cobra.Command, args []string) error {
 		if p.Issue != "" {
 			fmt.Printf("    %s\n", style.Dim.Render(p.Issue))
 		}
+		if p.Progress != nil {
+			now := time.Now()
+			age := style.Dim.Render(formatRelative(p.Progress.ReportedAt, now))
+			if p.SessionRunning && p.Progress.Stalled(now, progress.DefaultStallAfter) {
+				age = style.Warning.Render("stalled, " + formatRelative(p.Progress.ReportedAt, now))
+			}
+			fmt.Printf("    %s %s\n", style.Dim.Render(formatProgress(p.Progress)+" ·"), age)
+		}
 	}
 
 	return nil
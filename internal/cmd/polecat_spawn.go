@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
@@ -39,6 +40,15 @@ type SlingSpawnOptions struct {
 	Create   bool   // Create polecat if it doesn't exist (currently always true for sling)
 	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	Agent    string // Agent override for this spawn (e.g., "gemini", "codex", "cursor-haiku")
+
+	// Timebox is the wall-clock limit for the polecat session. When zero, the
+	// hook bead's "timebox:" description field is used, if present.
+	Timebox time.Duration
+
+	// Cleanroom runs the polecat's agent in a container built from the rig's
+	// cleanroom_image. When false, the hook bead's "cleanroom:" description
+	// field is used, if present.
+	Cleanroom bool
 }
 
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
@@ -115,6 +125,33 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("getting polecat after creation: %w", err)
 	}
 
+	// Set up cleanroom mode before the session starts so it runs in the container
+	if resolveSpawnCleanroom(opts) {
+		cleanroom, err := polecat.NewCleanroom(
+			r.GetStringConfig(polecat.CleanroomRuntimeKey),
+			r.GetStringConfig(polecat.CleanroomImageKey),
+			fmt.Sprintf("gt-%s-%s", rigName, polecatName),
+			polecatObj.ClonePath)
+		if err != nil {
+			return nil, fmt.Errorf("cleanroom mode: %w", err)
+		}
+		agentCmd, err := config.GetRuntimeCommandWithAgentOverride(r.Path, opts.Agent)
+		if err != nil {
+			return nil, err
+		}
+		tools := []string{"bd"}
+		if fields := strings.Fields(agentCmd); len(fields) > 0 {
+			tools = append(tools, fields[0])
+		}
+		if err := cleanroom.CheckImage(tools...); err != nil {
+			return nil, fmt.Errorf("cleanroom mode: %w", err)
+		}
+		if err := polecat.WriteCleanroom(polecatObj.ClonePath, cleanroom); err != nil {
+			return nil, fmt.Errorf("cleanroom mode: %w", err)
+		}
+		fmt.Printf("Cleanroom: %s container from %s\n", cleanroom.Runtime, cleanroom.Image)
+	}
+
 	// Handle naked mode (no-tmux)
 	if opts.Naked {
 		fmt.Println()
@@ -127,7 +164,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		if err != nil {
 			return nil, err
 		}
-		fmt.Printf("  %s\n\n", agentCmd)
+		fmt.Printf("  %s\n\n", polecat.CleanroomCommand(polecatObj.ClonePath, agentCmd))
 		fmt.Printf("Agent will discover work via gt prime on startup.\n")
 
 		return &SpawnedPolecatInfo{
@@ -181,6 +218,23 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 
 	fmt.Printf("%s Polecat %s spawned\n", style.Bold.Render("OK"), polecatName)
 
+	// Apply time box (daemon nudges at 80%, stops and hands off at 100%)
+	if timebox := resolveSpawnTimebox(opts); timebox > 0 {
+		tb := &polecat.Timebox{
+			Rig:       rigName,
+			Polecat:   polecatName,
+			HookBead:  opts.HookBead,
+			StartedAt: time.Now().UTC(),
+			Duration:  timebox,
+		}
+		if err := polecat.WriteTimebox(polecatObj.ClonePath, tb); err != nil {
+			style.PrintWarning("could not set timebox: %v", err)
+		} else {
+			fmt.Printf("Timebox: %s (wrap-up nudge at %s)\n", timebox,
+				tb.StartedAt.Add(time.Duration(float64(timebox)*polecat.TimeboxWrapUpFraction)).Local().Format("15:04"))
+		}
+	}
+
 	// Log spawn event to activity feed
 	_ = events.LogFeed(events.TypeSpawn, "gt", events.SpawnPayload(rigName, polecatName))
 
@@ -193,6 +247,38 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	}, nil
 }
 
+// resolveSpawnTimebox returns the time box for a spawn: the explicit option,
+// or the hook bead's "timebox:" field.
+func resolveSpawnTimebox(opts SlingSpawnOptions) time.Duration {
+	if opts.Timebox > 0 {
+		return opts.Timebox
+	}
+	if opts.HookBead == "" {
+		return 0
+	}
+	info, err := getBeadInfo(opts.HookBead)
+	if err != nil {
+		return 0
+	}
+	return polecat.ParseTimeboxField(info.Description)
+}
+
+// resolveSpawnCleanroom reports whether a spawn runs in cleanroom mode: the
+// explicit option, or the hook bead's "cleanroom:" field.
+func resolveSpawnCleanroom(opts SlingSpawnOptions) bool {
+	if opts.Cleanroom {
+		return true
+	}
+	if opts.HookBead == "" {
+		return false
+	}
+	info, err := getBeadInfo(opts.HookBead)
+	if err != nil {
+		return false
+	}
+	return polecat.ParseCleanroomField(info.Description)
+}
+
 // IsRigName checks if a target string is a rig name (not a role or path).
 // Returns the rig name and true if it's a valid rig.
 func IsRigName(target string) (string, bool) {
@@ -60,12 +60,19 @@ HOOK MODE (--hook):
   This reads session metadata from stdin and persists it for the session.
 
   Cursor integration (in .cursor/hooks.json):
-    "beforeSubmitPrompt": [{"command": ".cursor/hooks/gastown-prompt.sh"}]
+    "sessionStart": [{"command": "gt prime --hook"}]
 
   Cursor sends JSON on stdin:
     {"session_id": "uuid", "transcript_path": "/path", "source": "startup|resume"}
 
-  Other agents can set GT_SESSION_ID environment variable instead.`,
+  Other agents can set GT_SESSION_ID environment variable instead.
+
+CONTEXT PRIMING:
+  When a session starts (hook source "startup") with work on its hook, the
+  output ends with a Work Item Context block: the issue text, files a quick
+  repo search finds for it, and prior sessions on the same item. Roles and
+  token budgets are set in settings/config.json:
+    "context_priming": {"roles": {"polecat": 3000, "crew": 2000}}`,
 	RunE: runPrime,
 }
 
@@ -95,8 +102,10 @@ func runPrime(cmd *cobra.Command, args []string) error {
 	}
 
 	// Handle hook mode: read session ID from stdin and persist it
+	var source string
 	if primeHookMode {
-		sessionID, source := readHookSessionID()
+		var sessionID string
+		sessionID, source = readHookSessionID()
 		persistSessionID(townRoot, sessionID)
 		if cwd != townRoot {
 			persistSessionID(cwd, sessionID)
@@ -156,8 +165,11 @@ func runPrime(cmd *cobra.Command, args []string) error {
 	// Agent liveness is observable from tmux - no need to record it in bead.
 	// "Discover, don't track" principle: reality is truth, state is derived.
 
+	// Work on the hook drives autonomous mode and is the session's topic
+	hooked := findHookedWork(ctx)
+
 	// Emit session_start event for seance discovery
-	emitSessionEvent(ctx)
+	emitSessionEvent(ctx, hooked)
 
 	// Output session metadata for seance discovery
 	outputSessionMetadata(ctx)
@@ -175,7 +187,12 @@ func runPrime(cmd *cobra.Command, args []string) error {
 
 	// Check for slung work on hook (from gt sling)
 	// If found, we're in autonomous mode - skip normal startup directive
-	hasSlungWork := checkSlungWork(ctx)
+	hasSlungWork := checkSlungWork(ctx, hooked)
+
+	// Prime a freshly spawned session with context for its work item
+	if hasSlungWork && primeHookMode && (source == "" || source == "startup") {
+		outputWorkPriming(ctx, hooked)
+	}
 
 	// Output molecule context if working on a molecule step
 	outputMoleculeContext(ctx)
@@ -282,8 +299,8 @@ func detectRole(cwd, townRoot string) RoleInfo {
 }
 
 func outputPrimeContext(ctx RoleContext) error {
-	// Try to use templates first
-	tmpl, err := templates.New()
+	// Try to use templates first (town overrides take precedence)
+	tmpl, err := templates.NewForTown(ctx.TownRoot)
 	if err != nil {
 		// Fall back to hardcoded output if templates fail
 		return outputPrimeContextFallback(ctx)
@@ -332,6 +349,7 @@ func outputPrimeContext(ctx RoleContext) error {
 		Polecat:       ctx.Polecat,
 		MayorSession:  session.MayorSessionName(),
 		DeaconSession: session.DeaconSessionName(),
+		GTBin:         templates.GTBinary(),
 	}
 
 	// Render and output
@@ -976,14 +994,12 @@ func outputRefineryPatrolContext(ctx RoleContext) {
 	outputPatrolContext(cfg)
 }
 
-// checkSlungWork checks for hooked work on the agent's hook.
-// If found, displays AUTONOMOUS WORK MODE and tells the agent to execute immediately.
-// Returns true if hooked work was found (caller should skip normal startup directive).
-func checkSlungWork(ctx RoleContext) bool {
+// findHookedWork returns the work on the agent's hook, or nil.
+func findHookedWork(ctx RoleContext) *beads.Issue {
 	// Determine agent identity
 	agentID := getAgentIdentity(ctx)
 	if agentID == "" {
-		return false
+		return nil
 	}
 
 	// Check for hooked beads (work on the agent's hook)
@@ -994,7 +1010,7 @@ func checkSlungWork(ctx RoleContext) bool {
 		Priority: -1,
 	})
 	if err != nil {
-		return false
+		return nil
 	}
 
 	// If no hooked beads found, also check in_progress beads assigned to this agent.
@@ -1007,13 +1023,22 @@ func checkSlungWork(ctx RoleContext) bool {
 			Priority: -1,
 		})
 		if err != nil || len(inProgressBeads) == 0 {
-			return false
+			return nil
 		}
 		hookedBeads = inProgressBeads
 	}
 
 	// Use the first hooked bead (agents typically have one)
-	hookedBead := hookedBeads[0]
+	return hookedBeads[0]
+}
+
+// checkSlungWork checks for hooked work on the agent's hook.
+// If found, displays AUTONOMOUS WORK MODE and tells the agent to execute immediately.
+// Returns true if hooked work was found (caller should skip normal startup directive).
+func checkSlungWork(ctx RoleContext, hookedBead *beads.Issue) bool {
+	if hookedBead == nil {
+		return false
+	}
 
 	// Build the role announcement string
 	roleAnnounce := buildRoleAnnouncement(ctx)
@@ -1411,7 +1436,7 @@ func outputCheckpointContext(ctx RoleContext) {
 // emitSessionEvent emits a session_start event for seance discovery.
 // The event is written to ~/gt/.events.jsonl and can be queried via gt seance.
 // Session ID resolution order: GT_SESSION_ID, CURSOR_SESSION_ID, persisted file, fallback.
-func emitSessionEvent(ctx RoleContext) {
+func emitSessionEvent(ctx RoleContext, hooked *beads.Issue) {
 	if ctx.Role == RoleUnknown {
 		return
 	}
@@ -1429,11 +1454,14 @@ func emitSessionEvent(ctx RoleContext) {
 	topic := ""
 	if ctx.Role == RoleWitness || ctx.Role == RoleRefinery || ctx.Role == RoleDeacon {
 		topic = "patrol"
+	} else if hooked != nil {
+		topic = hooked.ID
 	}
 
-	// Emit the event
+	// Emit the event. Keyed by session so a retried SessionStart hook
+	// (or a re-prime of the same session) records the session once.
 	payload := events.SessionPayload(sessionID, actor, topic, ctx.WorkDir)
-	_ = events.LogFeed(events.TypeSessionStart, actor, payload)
+	_, _ = events.LogOnce("session_start:"+sessionID, events.TypeSessionStart, actor, payload, events.VisibilityFeed)
 }
 
 // outputSessionMetadata prints a structured metadata line for seance discovery.
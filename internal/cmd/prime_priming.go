@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+// primingSearchTimeout bounds the repo search so a huge checkout cannot
+// stall session startup.
+const primingSearchTimeout = 5 * time.Second
+
+// primingMaxKeywords caps how many issue keywords are searched for.
+const primingMaxKeywords = 8
+
+// primingSession is a prior session that worked on the same item.
+type primingSession struct {
+	SessionID string
+	Actor     string
+	Started   string
+	Why       string // "topic" or the event that touched the item (e.g. "done")
+}
+
+// workPriming holds the parts of a priming block before budgeting.
+type workPriming struct {
+	IssueID     string
+	Title       string
+	Description string
+	Files       []string
+	Sessions    []primingSession
+}
+
+// outputWorkPriming prints the opening context for a freshly spawned
+// session with work on its hook: the issue text, files a quick repo search
+// finds for it, and prior sessions on the same item, within the role's
+// token budget (settings/config.json context_priming).
+func outputWorkPriming(ctx RoleContext, hooked *beads.Issue) {
+	if hooked == nil {
+		return
+	}
+	var cfg *config.ContextPrimingConfig
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot)); err == nil {
+		cfg = settings.ContextPriming
+	}
+	budget := cfg.Budget(string(ctx.Role))
+	if budget <= 0 {
+		return
+	}
+
+	p := workPriming{
+		IssueID:     hooked.ID,
+		Title:       hooked.Title,
+		Description: hooked.Description,
+	}
+	keywords := primingKeywords(hooked.Title + "\n" + hooked.Description)
+	p.Files = searchRelevantFiles(ctx.WorkDir, keywords, cfg.FileLimit())
+	p.Sessions = relatedSessions(ctx.TownRoot, hooked.ID, resolveSessionIDForPrime(getAgentIdentity(ctx)), cfg.SessionLimit())
+
+	fmt.Print(renderWorkPriming(p, budget))
+}
+
+// renderWorkPriming renders the priming block within budget tokens. The
+// file list and prior sessions are kept whole when possible; the issue
+// description takes whatever budget is left and is cut at a line boundary.
+func renderWorkPriming(p workPriming, budget int) string {
+	var head strings.Builder
+	head.WriteString("\n## Work Item Context\n\n")
+	head.WriteString("Assembled at spawn from your hooked work. Verify before relying on it.\n\n")
+	fmt.Fprintf(&head, "### %s: %s\n\n", p.IssueID, p.Title)
+
+	var tail strings.Builder
+	if len(p.Files) > 0 {
+		tail.WriteString("### Possibly relevant files\n\n")
+		for _, f := range p.Files {
+			fmt.Fprintf(&tail, "- %s\n", f)
+		}
+		tail.WriteString("\n")
+	}
+	if len(p.Sessions) > 0 {
+		tail.WriteString("### Prior sessions on this item\n\n")
+		for _, s := range p.Sessions {
+			fmt.Fprintf(&tail, "- %s %s (session %s, %s)\n", formatEventTime(s.Started), s.Actor, s.SessionID, s.Why)
+		}
+		tail.WriteString("\n")
+	}
+
+	maxBytes := budget * 4 // Four bytes per token, as in gt doctor's budgets
+	remaining := maxBytes - head.Len() - tail.Len()
+	if remaining < 0 {
+		// Not even the lists fit: keep the header and trim the lists.
+		trimmed := truncateAtLine(tail.String(), maxBytes-head.Len())
+		return head.String() + trimmed
+	}
+
+	const truncated = "[... truncated to fit the priming budget]"
+	desc := strings.TrimSpace(p.Description)
+	if desc != "" {
+		if len(desc)+2 > remaining {
+			desc = truncateAtLine(desc, remaining-len(truncated)-2) + truncated
+		}
+		desc += "\n\n"
+	}
+	return head.String() + desc + tail.String()
+}
+
+// truncateAtLine returns the longest prefix of s within n bytes that ends
+// at a line boundary.
+func truncateAtLine(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	cut := s[:n]
+	if i := strings.LastIndexByte(cut, '\n'); i >= 0 {
+		return cut[:i+1]
+	}
+	return ""
+}
+
+// primingKeywordRe matches identifier- and path-like words.
+var primingKeywordRe = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_./-]*[A-Za-z0-9_]`)
+
+// primingStopwords are common issue words too generic to search for.
+var primingStopwords = map[string]bool{
+	"about": true, "after": true, "also": true, "because": true, "before": true,
+	"being": true, "both": true, "change": true, "could": true, "does": true,
+	"each": true, "every": true, "from": true, "have": true, "into": true,
+	"make": true, "more": true, "must": true, "need": true, "only": true,
+	"other": true, "should": true, "some": true, "such": true, "than": true,
+	"that": true, "their": true, "them": true, "then": true, "there": true,
+	"these": true, "they": true, "this": true, "when": true, "where": true,
+	"which": true, "while": true, "will": true, "with": true, "without": true,
+	"would": true, "your": true, "work": true, "works": true, "using": true,
+	"used": true, "instead": true, "still": true, "already": true,
+	"update": true, "support": true, "currently": true,
+}
+
+// primingKeywords picks the words of an issue worth searching the repo
+// for. Code-like words (paths, dotted or snake_case or camelCase names)
+// come first since they pinpoint files; plain words fill the rest.
+func primingKeywords(text string) []string {
+	seen := make(map[string]bool)
+	var codeLike, plain []string
+	for _, word := range primingKeywordRe.FindAllString(text, -1) {
+		word = strings.Trim(word, "./-")
+		lower := strings.ToLower(word)
+		if len(word) < 4 || seen[lower] || primingStopwords[lower] {
+			continue
+		}
+		seen[lower] = true
+		if strings.ContainsAny(word, "./_") || strings.ContainsAny(word[1:], "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+			codeLike = append(codeLike, word)
+		} else {
+			plain = append(plain, lower)
+		}
+	}
+	keywords := append(codeLike, plain...)
+	if len(keywords) > primingMaxKeywords {
+		keywords = keywords[:primingMaxKeywords]
+	}
+	return keywords
+}
+
+// searchRelevantFiles ranks the tracked files in workDir by how many
+// keywords they mention, counting a keyword in the path double. It runs
+// git grep once per keyword under a shared timeout; a slow or failing
+// search just yields fewer files.
+func searchRelevantFiles(workDir string, keywords []string, limit int) []string {
+	if len(keywords) == 0 || limit <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), primingSearchTimeout)
+	defer cancel()
+
+	scores := make(map[string]int)
+	if out, err := exec.CommandContext(ctx, "git", "-C", workDir, "ls-files").Output(); err == nil {
+		for _, path := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			lower := strings.ToLower(path)
+			for _, kw := range keywords {
+				if path != "" && strings.Contains(lower, strings.ToLower(kw)) {
+					scores[path] += 2
+				}
+			}
+		}
+	}
+	for _, kw := range keywords {
+		out, err := exec.CommandContext(ctx, "git", "-C", workDir, "grep", "-l", "-i", "-F", "-I", "-e", kw).Output() //nolint:gosec // G204: keyword is passed as a pattern argument
+		if err != nil {
+			continue // No match (exit 1) or timeout
+		}
+		for _, path := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if path != "" {
+				scores[path]++
+			}
+		}
+	}
+
+	files := make([]string, 0, len(scores))
+	for path := range scores {
+		files = append(files, path)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if scores[files[i]] != scores[files[j]] {
+			return scores[files[i]] > scores[files[j]]
+		}
+		return files[i] < files[j]
+	})
+	if len(files) > limit {
+		files = files[:limit]
+	}
+	return files
+}
+
+// relatedSessions finds prior sessions on an item from the events log:
+// sessions whose topic is the item, and the session each actor was in when
+// it slung, hooked, or finished the item. Most recent first, excluding the
+// current session.
+func relatedSessions(townRoot, issueID, currentSession string, limit int) []primingSession {
+	file, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	// lastStart tracks each actor's most recent session as the log is read
+	// in order, so a touching event maps to the session it happened in.
+	lastStart := make(map[string]sessionEvent)
+	found := make(map[string]primingSession)
+	add := func(s sessionEvent, why string) {
+		id := getPayloadString(s.Payload, "session_id")
+		if id == "" || id == currentSession {
+			return
+		}
+		if _, ok := found[id]; !ok {
+			found[id] = primingSession{SessionID: id, Actor: s.Actor, Started: s.Timestamp, Why: why}
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e sessionEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		switch e.Type {
+		case events.TypeSessionStart:
+			lastStart[e.Actor] = e
+			if getPayloadString(e.Payload, "topic") == issueID {
+				add(e, "topic")
+			}
+		case events.TypeSling, events.TypeHook, events.TypeDone:
+			if getPayloadString(e.Payload, "bead") != issueID {
+				continue
+			}
+			if s, ok := lastStart[e.Actor]; ok {
+				add(s, e.Type)
+			}
+		}
+	}
+
+	sessions := make([]primingSession, 0, len(found))
+	for _, s := range found {
+		sessions = append(sessions, s)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Started > sessions[j].Started })
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestPrimingKeywords(t *testing.T) {
+	got := primingKeywords("Fix token refresh in auth/session.go\n\nThe refreshToken helper should retry when the session_store returns stale tokens. Also update the token docs.")
+	want := []string{"auth/session.go", "refreshToken", "session_store", "token", "refresh", "helper", "retry", "returns"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("primingKeywords = %v, want %v", got, want)
+	}
+}
+
+func TestSearchRelevantFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"auth/session.go": "package auth\n\nfunc refreshToken() {}\n",
+		"auth/login.go":   "package auth\n\n// uses refreshToken\n",
+		"docs/tokens.md":  "Tokens expire.\n",
+		"main.go":         "package main\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "."}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+
+	// A keyword in the path counts double: session.go scores 4, tokens.md 3.
+	got := searchRelevantFiles(dir, []string{"refreshToken", "session", "token"}, 3)
+	want := []string{"auth/session.go", "docs/tokens.md", "auth/login.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("searchRelevantFiles = %v, want %v", got, want)
+	}
+}
+
+func TestRelatedSessions(t *testing.T) {
+	townRoot := t.TempDir()
+	log := strings.Join([]string{
+		`{"ts":"2025-01-01T10:00:00Z","type":"session_start","actor":"gastown/polecats/Toast","payload":{"session_id":"s1"}}`,
+		`{"ts":"2025-01-01T10:05:00Z","type":"hook","actor":"gastown/polecats/Toast","payload":{"bead":"gt-abc"}}`,
+		`{"ts":"2025-01-02T09:00:00Z","type":"session_start","actor":"gastown/polecats/Nux","payload":{"session_id":"s2","topic":"gt-abc"}}`,
+		`{"ts":"2025-01-02T09:30:00Z","type":"session_start","actor":"gastown/crew/max","payload":{"session_id":"s3","topic":"gt-other"}}`,
+		`{"ts":"2025-01-03T09:00:00Z","type":"session_start","actor":"gastown/polecats/Nux","payload":{"session_id":"current","topic":"gt-abc"}}`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := relatedSessions(townRoot, "gt-abc", "current", 3)
+	if len(got) != 2 || got[0].SessionID != "s2" || got[0].Why != "topic" || got[1].SessionID != "s1" || got[1].Why != "hook" {
+		t.Errorf("relatedSessions = %+v, want s2 (topic) then s1 (hook)", got)
+	}
+	if got := relatedSessions(townRoot, "gt-abc", "current", 1); len(got) != 1 {
+		t.Errorf("limit 1: got %d sessions", len(got))
+	}
+}
+
+func TestRenderWorkPrimingBudget(t *testing.T) {
+	p := workPriming{
+		IssueID:     "gt-abc",
+		Title:       "Fix token refresh",
+		Description: strings.Repeat("A line of issue text that goes on.\n", 200),
+		Files:       []string{"auth/session.go"},
+		Sessions:    []primingSession{{SessionID: "s1", Actor: "gastown/polecats/Toast", Started: "2025-01-01T10:00:00Z", Why: "hook"}},
+	}
+
+	out := renderWorkPriming(p, 300)
+	if len(out) > 300*4 {
+		t.Errorf("rendered %d bytes, over the 1200-byte budget", len(out))
+	}
+	for _, want := range []string{"### gt-abc: Fix token refresh", "truncated to fit", "- auth/session.go", "session s1, hook"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	p.Description = "Short."
+	if out := renderWorkPriming(p, 300); strings.Contains(out, "truncated") || !strings.Contains(out, "Short.") {
+		t.Errorf("short description should be kept whole:\n%s", out)
+	}
+}
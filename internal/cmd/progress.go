@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/progress"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// Progress command flags
+var (
+	progressPercent int
+	progressNote    string
+	progressBead    string
+	progressAll     bool
+	progressJSON    bool
+)
+
+var progressCmd = &cobra.Command{
+	Use:     "progress",
+	GroupID: GroupWork,
+	Short:   "Report and view progress on hooked work",
+	Long: `Report and view agent progress on hooked work.
+
+Agents report progress as they work instead of leaving it to be guessed
+from pane output. The latest report is kept in the agent's
+.runtime/progress.json and logged as a progress event. Reports feed
+'gt polecat list', 'gt progress list', and stall detection: the daemon
+tells the witness when a running polecat has not reported on unfinished
+work for 30 minutes.`,
+	RunE: requireSubcommand,
+}
+
+var progressReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report progress on your hooked work",
+	Long: `Record how far along your hooked work is.
+
+Call this after each meaningful step. The bead defaults to your hooked
+work; reporting on a different bead starts a new report. Omitting
+--percent keeps the last percentage, so a note alone is a heartbeat.
+
+Examples:
+  gt progress report --percent 20 --note "reproduced the bug"
+  gt progress report --percent 60 --note "fix in place, writing tests"
+  gt progress report --note "still running the integration suite"`,
+	Args: cobra.NoArgs,
+	RunE: runProgressReport,
+}
+
+var progressListCmd = &cobra.Command{
+	Use:   "list [rig...]",
+	Short: "Show the latest progress reports of polecats and crew",
+	Long: `Show the latest progress report of each polecat and crew worker.
+
+Reports on unfinished work older than 30 minutes are marked stalled.
+If no rig is given, infers it from the current directory.
+
+Examples:
+  gt progress list greenplace
+  gt progress list --all
+  gt progress list --all --json`,
+	RunE: runProgressList,
+}
+
+func init() {
+	progressReportCmd.Flags().IntVar(&progressPercent, "percent", -1, "Percent complete (0-100); keeps the last value if omitted")
+	progressReportCmd.Flags().StringVar(&progressNote, "note", "", "What you just did or are doing now")
+	progressReportCmd.Flags().StringVar(&progressBead, "bead", "", "Bead the report is about (default: your hooked work)")
+
+	progressListCmd.Flags().BoolVar(&progressAll, "all", false, "Show all rigs")
+	progressListCmd.Flags().BoolVar(&progressJSON, "json", false, "Output as JSON")
+
+	progressCmd.AddCommand(progressReportCmd)
+	progressCmd.AddCommand(progressListCmd)
+	rootCmd.AddCommand(progressCmd)
+}
+
+func runProgressReport(cmd *cobra.Command, args []string) error {
+	if progressPercent < 0 && progressNote == "" {
+		return fmt.Errorf("nothing to report: pass --percent and/or --note")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+	roleInfo, err := GetRoleWithContext(cwd, townRoot)
+	if err != nil {
+		return fmt.Errorf("detecting role: %w", err)
+	}
+	if roleInfo.Home == "" {
+		return fmt.Errorf("cannot determine agent home for role %s", roleInfo.Role)
+	}
+
+	bead := progressBead
+	if bead == "" {
+		bead = detectHookedBead(cwd, roleInfo)
+	}
+
+	prev, _ := progress.Read(roleInfo.Home) // Unreadable report: start over
+	report, err := progress.Next(prev, roleInfo.ActorString(), bead, progressPercent, progressNote, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := progress.Write(roleInfo.Home, report); err != nil {
+		return fmt.Errorf("writing progress: %w", err)
+	}
+	_ = events.LogFeed(events.TypeProgress, report.Agent, events.ProgressPayload(report.Bead, report.Percent, report.Note))
+
+	fmt.Printf("%s Progress recorded: %s\n", style.Bold.Render("✓"), formatProgress(report))
+	return nil
+}
+
+// ProgressListItem is one agent's latest report in gt progress list.
+type ProgressListItem struct {
+	Agent   string           `json:"agent"`
+	Stalled bool             `json:"stalled"`
+	Report  *progress.Report `json:"report"`
+}
+
+func runProgressList(cmd *cobra.Command, args []string) error {
+	var rigs []*rig.Rig
+	switch {
+	case progressAll:
+		all, _, err := getAllRigs()
+		if err != nil {
+			return err
+		}
+		rigs = all
+	case len(args) > 0:
+		for _, name := range args {
+			_, r, err := getRig(name)
+			if err != nil {
+				return err
+			}
+			rigs = append(rigs, r)
+		}
+	default:
+		roleInfo, err := GetRole()
+		if err != nil || roleInfo.Rig == "" {
+			return fmt.Errorf("rig name required (or use --all)")
+		}
+		_, r, err := getRig(roleInfo.Rig)
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	}
+
+	now := time.Now()
+	items := []ProgressListItem{}
+	for _, r := range rigs {
+		for _, agent := range rigProgressHomes(r) {
+			report, err := progress.Read(agent.home)
+			if err != nil || report == nil {
+				continue
+			}
+			items = append(items, ProgressListItem{
+				Agent:   agent.address,
+				Stalled: report.Stalled(now, progress.DefaultStallAfter),
+				Report:  report,
+			})
+		}
+	}
+
+	if progressJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Println("No progress reports.")
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("Progress"))
+	for _, item := range items {
+		age := style.Dim.Render(formatRelative(item.Report.ReportedAt, now))
+		if item.Stalled {
+			age = style.Warning.Render("stalled, " + formatRelative(item.Report.ReportedAt, now))
+		}
+		fmt.Printf("  %s  %s  %s\n", item.Agent, formatProgress(item.Report), age)
+	}
+	return nil
+}
+
+// progressHome is an agent whose progress report can be listed.
+type progressHome struct {
+	address string
+	home    string
+}
+
+// rigProgressHomes lists a rig's polecats and crew workers.
+func rigProgressHomes(r *rig.Rig) []progressHome {
+	var homes []progressHome
+	for _, name := range r.Polecats {
+		homes = append(homes, progressHome{r.Name + "/polecats/" + name, filepath.Join(r.Path, "polecats", name)})
+	}
+	for _, name := range r.Crew {
+		homes = append(homes, progressHome{r.Name + "/crew/" + name, filepath.Join(r.Path, "crew", name)})
+	}
+	return homes
+}
+
+// formatProgress renders a report as "60% gp-abc: note".
+func formatProgress(r *progress.Report) string {
+	s := fmt.Sprintf("%d%%", r.Percent)
+	if r.Bead != "" {
+		s += " " + r.Bead
+	}
+	if r.Note != "" {
+		s += ": " + r.Note
+	}
+	return s
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/replay"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var (
+	replayStep  bool
+	replayKind  string
+	replayJSON  bool
+	replayFull  bool
+	replayLimit int
+)
+
+// replayOutputLines is how many lines of command output or tool results are
+// shown per step without --full.
+const replayOutputLines = 10
+
+var replayCmd = &cobra.Command{
+	Use:     "replay [session-id]",
+	GroupID: GroupDiag,
+	Short:   "Step through the tool calls an agent made in a session",
+	Long: `Replay what an agent did during a session: shell commands with their
+output, file edits as diffs, and MCP tool calls, in the order they happened.
+
+Steps are captured by the Gas Town Cursor hooks (afterShellExecution,
+afterFileEdit, afterMCPExecution) into .runtime/replay/<session-id>.jsonl.
+Session IDs can be abbreviated to any unique prefix; find them with
+'gt replay' (no arguments) or 'gt seance'.
+
+Examples:
+  gt replay                      # List captured sessions
+  gt replay 7f3a                 # Print every step of a session
+  gt replay 7f3a --step          # Pause after each step (Enter/q)
+  gt replay 7f3a --kind edit     # Only file edits
+  gt replay 7f3a --json          # Raw steps for tooling`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReplay,
+}
+
+var replayCaptureCmd = &cobra.Command{
+	Use:    "capture <shell|edit|mcp>",
+	Short:  "Record a hook payload from stdin (called by Cursor hooks)",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE:   runReplayCapture,
+}
+
+func init() {
+	replayCmd.Flags().BoolVarP(&replayStep, "step", "s", false, "Pause after each step")
+	replayCmd.Flags().StringVar(&replayKind, "kind", "", "Only show steps of this kind (shell, edit, mcp)")
+	replayCmd.Flags().BoolVar(&replayJSON, "json", false, "Output as JSON")
+	replayCmd.Flags().BoolVar(&replayFull, "full", false, "Show complete output and results")
+	replayCmd.Flags().IntVarP(&replayLimit, "limit", "n", 20, "Sessions to list when no session ID is given")
+	replayCmd.AddCommand(replayCaptureCmd)
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if len(args) == 0 {
+		return runReplayList(townRoot)
+	}
+
+	sessionID, err := replay.Resolve(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	steps, err := replay.Load(townRoot, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if replayKind != "" {
+		var filtered []replay.Step
+		for _, s := range steps {
+			if s.Kind == replayKind {
+				filtered = append(filtered, s)
+			}
+		}
+		steps = filtered
+	}
+
+	if replayJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(steps)
+	}
+
+	if len(steps) == 0 {
+		fmt.Printf("No steps captured for session %s.\n", sessionID)
+		return nil
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Session"), sessionID)
+	if actor := steps[0].Actor; actor != "" {
+		fmt.Printf("%s\n", style.Dim.Render(actor))
+	}
+
+	input := bufio.NewReader(os.Stdin)
+	start := steps[0].Time
+	for i, s := range steps {
+		fmt.Println()
+		printReplayStep(os.Stdout, i+1, len(steps), s, start)
+
+		if replayStep && i < len(steps)-1 {
+			fmt.Print(style.Dim.Render("[Enter] next, [q] quit: "))
+			line, err := input.ReadString('\n')
+			if err != nil || strings.TrimSpace(strings.ToLower(line)) == "q" {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func runReplayList(townRoot string) error {
+	summaries, err := replay.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing captured sessions: %w", err)
+	}
+	if replayLimit > 0 && len(summaries) > replayLimit {
+		summaries = summaries[:replayLimit]
+	}
+
+	if replayJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summaries)
+	}
+
+	if len(summaries) == 0 {
+		fmt.Println("No captured sessions.")
+		fmt.Println(style.Dim.Render("Steps are captured by Cursor hooks; run 'gt doctor --fix' if hooks are out of date"))
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Captured Sessions"))
+	for _, s := range summaries {
+		actor := s.Actor
+		if actor == "" {
+			actor = "-"
+		}
+		fmt.Printf("  %-36s  %-26s  %4d steps  %s\n", s.SessionID, actor, s.Steps,
+			style.Dim.Render(s.Last.Local().Format("2006-01-02 15:04")))
+	}
+	return nil
+}
+
+// printReplayStep renders one step with its offset from the session start.
+func printReplayStep(w io.Writer, n, total int, s replay.Step, start time.Time) {
+	header := fmt.Sprintf("[%d/%d] %s +%s", n, total,
+		s.Time.Local().Format("15:04:05"), s.Time.Sub(start).Round(time.Second))
+	if s.DurationMS > 0 {
+		header += fmt.Sprintf(" (%s)", (time.Duration(s.DurationMS) * time.Millisecond).Round(time.Millisecond))
+	}
+	fmt.Fprintln(w, style.Dim.Render(header))
+
+	switch s.Kind {
+	case replay.KindShell:
+		fmt.Fprintf(w, "%s %s\n", style.Bold.Render("$"), s.Command)
+		if s.Cwd != "" {
+			fmt.Fprintf(w, "  %s\n", style.Dim.Render("in "+s.Cwd))
+		}
+		printReplayBlock(w, s.Output)
+	case replay.KindEdit:
+		fmt.Fprintf(w, "%s %s\n", style.Bold.Render("edit"), s.File)
+		if len(s.Edits) == 0 {
+			fmt.Fprintf(w, "  %s\n", style.Dim.Render("(no diff captured)"))
+		}
+		for i, e := range s.Edits {
+			if i > 0 {
+				fmt.Fprintf(w, "  %s\n", style.Dim.Render("..."))
+			}
+			for _, line := range replay.DiffLines(e) {
+				switch line[0] {
+				case '-':
+					line = style.Error.Render(line)
+				case '+':
+					line = style.Success.Render(line)
+				}
+				fmt.Fprintf(w, "  %s\n", line)
+			}
+		}
+	case replay.KindMCP:
+		fmt.Fprintf(w, "%s %s\n", style.Bold.Render("tool"), s.Tool)
+		if s.Input != "" {
+			fmt.Fprintf(w, "  %s %s\n", style.Dim.Render("input:"), s.Input)
+		}
+		printReplayBlock(w, s.Result)
+	default:
+		fmt.Fprintf(w, "%s\n", s.Kind)
+	}
+}
+
+// printReplayBlock prints indented output, truncated unless --full.
+func printReplayBlock(w io.Writer, text string) {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return
+	}
+	lines := strings.Split(text, "\n")
+	hidden := 0
+	if !replayFull && len(lines) > replayOutputLines {
+		hidden = len(lines) - replayOutputLines
+		lines = lines[:replayOutputLines]
+	}
+	for _, line := range lines {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	if hidden > 0 {
+		fmt.Fprintf(w, "  %s\n", style.Dim.Render(fmt.Sprintf("... %d more line(s), use --full", hidden)))
+	}
+}
+
+func runReplayCapture(cmd *cobra.Command, args []string) error {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("reading hook input: %w", err)
+	}
+	return captureReplayStep(args[0], data)
+}
+
+// captureReplayStep records the tool call in a hook payload as a replay
+// step of kind (see replay.ParseHookInput).
+func captureReplayStep(kind string, data []byte) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		// Not in a Gas Town workspace - nothing to record
+		return nil
+	}
+
+	step, sessionID, err := replay.ParseHookInput(kind, data, time.Now())
+	if err != nil {
+		return err
+	}
+	if sessionID == "" {
+		sessionID = os.Getenv("GT_SESSION_ID")
+	}
+	if sessionID == "" {
+		return nil
+	}
+	step.Actor = detectSender()
+
+	return replay.Record(townRoot, sessionID, step)
+}
@@ -60,13 +60,39 @@ The command also:
   - Creates ~/gt/plugins/ (town-level) if it doesn't exist
   - Creates <rig>/plugins/ (rig-level)
 
+With --mirror, creates a reference-only rig instead: a single read-only
+checkout at <name>/repo/ that agents may browse for context (vendor docs,
+upstream dependencies). Mirror rigs have no beads, no patrol roles, no agent
+settings, and are skipped by the daemon, 'gt up', and agent-oriented doctor
+checks. Refresh a mirror with 'gt rig sync <name>'.
+
 Example:
   gt rig add gastown https://github.com/cursorworkshop/cursor-gastown
-  gt rig add my-project git@github.com:user/repo.git --prefix mp`,
+  gt rig add my-project git@github.com:user/repo.git --prefix mp
+  gt rig add cobra_upstream https://github.com/spf13/cobra --mirror`,
 	Args: cobra.ExactArgs(2),
 	RunE: runRigAdd,
 }
 
+var rigSyncCmd = &cobra.Command{
+	Use:   "sync <rig>",
+	Short: "Update a mirror from upstream, or refresh a rig's rule packs",
+	Long: `For a mirror rig, fast-forward its read-only checkout to its upstream branch.
+
+For a regular rig, re-detect the languages in the repo (go.mod, package.json,
+pyproject.toml, Cargo.toml, ...) from the mayor's clone, record them as
+rule_packs in <rig>/config.json, and install the matching rule packs
+(.cursor/rules/gastown-<pack>.mdc) for the rig's agents. Packs for languages
+the repo no longer uses are removed. Rule packs are first detected by
+'gt rig add'.
+
+Examples:
+  gt rig sync cobra_upstream   # Update a mirror
+  gt rig sync gastown          # Refresh language rule packs`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigSync,
+}
+
 var rigListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all rigs in the workspace",
@@ -256,6 +282,7 @@ var (
 	rigAddPrefix       string
 	rigAddLocalRepo    string
 	rigAddBranch       string
+	rigAddMirror       bool
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -282,10 +309,12 @@ func init() {
 	rigCmd.AddCommand(rigStartCmd)
 	rigCmd.AddCommand(rigStatusCmd)
 	rigCmd.AddCommand(rigStopCmd)
+	rigCmd.AddCommand(rigSyncCmd)
 
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
 	rigAddCmd.Flags().StringVar(&rigAddLocalRepo, "local-repo", "", "Local repo path to share git objects (optional)")
 	rigAddCmd.Flags().StringVar(&rigAddBranch, "branch", "", "Default branch name (default: auto-detected from remote)")
+	rigAddCmd.Flags().BoolVar(&rigAddMirror, "mirror", false, "Create a read-only reference rig (no agents, no beads)")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -309,6 +338,10 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	name := args[0]
 	gitURL := args[1]
 
+	if rigAddMirror {
+		return runRigAddMirror(name, gitURL)
+	}
+
 	// Ensure beads (bd) is available before proceeding
 	if err := deps.EnsureBeads(true); err != nil {
 		return fmt.Errorf("beads dependency check failed: %w", err)
@@ -433,6 +466,84 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runRigAddMirror creates a reference-only mirror rig.
+func runRigAddMirror(name, gitURL string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		rigsConfig = &config.RigsConfig{
+			Version: 1,
+			Rigs:    make(map[string]config.RigEntry),
+		}
+	}
+
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+
+	fmt.Printf("Creating mirror rig %s...\n", style.Bold.Render(name))
+	fmt.Printf("  Repository: %s\n", gitURL)
+
+	if _, err := mgr.AddMirror(rig.AddMirrorOptions{
+		Name:   name,
+		GitURL: gitURL,
+		Branch: rigAddBranch,
+	}); err != nil {
+		return fmt.Errorf("adding mirror rig: %w", err)
+	}
+
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return fmt.Errorf("saving rigs config: %w", err)
+	}
+
+	fmt.Printf("\n%s Mirror rig created\n", style.Success.Render("[OK]"))
+	fmt.Printf("  Agents can read: %s\n", rig.MirrorCheckoutPath(filepath.Join(townRoot, name)))
+	fmt.Printf("  Refresh with:    %s\n", style.Dim.Render("gt rig sync "+name))
+	return nil
+}
+
+func runRigSync(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	if entry, ok := rigsConfig.Rigs[name]; ok && !entry.Mirror {
+		packs, changed, err := mgr.RefreshRulePacks(name)
+		if err != nil {
+			return fmt.Errorf("syncing %s: %w", name, err)
+		}
+		detected := "none"
+		if len(packs) > 0 {
+			detected = strings.Join(packs, ", ")
+		}
+		note := ""
+		if changed {
+			note = style.Dim.Render(" (updated)")
+		}
+		fmt.Printf("%s Rule packs for %s: %s%s\n", style.Success.Render("[OK]"), name, detected, note)
+		return nil
+	}
+
+	if err := mgr.SyncMirror(name); err != nil {
+		return fmt.Errorf("syncing %s: %w", name, err)
+	}
+
+	fmt.Printf("%s Mirror %s is up to date\n", style.Success.Render("[OK]"), name)
+	return nil
+}
+
 func runRigList(cmd *cobra.Command, args []string) error {
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
@@ -467,6 +578,12 @@ func runRigList(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		if r.Mirror {
+			fmt.Printf("  %s %s\n", style.Bold.Render(name), style.Dim.Render("(read-only mirror)"))
+			fmt.Printf("    Checkout: %s\n\n", rig.MirrorCheckoutPath(r.Path))
+			continue
+		}
+
 		summary := r.Summary()
 		fmt.Printf("  %s\n", style.Bold.Render(name))
 		fmt.Printf("    Polecats: %d  Crew: %d\n", summary.PolecatCount, summary.CrewCount)
@@ -31,6 +31,18 @@ func getRig(rigName string) (string, *rig.Rig, error) {
 	if err != nil {
 		return "", nil, fmt.Errorf("rig '%s' not found", rigName)
 	}
+	if r.Mirror {
+		return "", nil, fmt.Errorf("rig '%s' is a read-only mirror with no agents", rigName)
+	}
 
 	return townRoot, r, nil
 }
+
+// isMirrorRig reports whether rigName is registered as a read-only mirror rig.
+func isMirrorRig(townRoot, rigName string) bool {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return false
+	}
+	return rigsConfig.Rigs[rigName].Mirror
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/mrqueue"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+)
+
+// Rig protected command flags
+var (
+	rigProtectedJSON   bool
+	rigProtectedReason string
+)
+
+var rigProtectedCmd = &cobra.Command{
+	Use:   "protected",
+	Short: "Enforce a rig's protected paths",
+	RunE:  requireSubcommand,
+	Long: `Keep agents from changing a rig's protected paths (infrastructure,
+migrations, release config) without an approval.
+
+Declare the paths in <rig>/settings/config.json:
+
+  "protected_paths": {
+    "paths": ["infra/", "db/migrations/", "*.tf", "deploy/release.yaml"],
+    "approver": "mayor/"
+  }
+
+A pattern ending in "/" protects a directory; other patterns are globs
+matched against the whole path, or against the file name when they contain
+no "/". approver (optional, default "mayor/") is who is asked to approve.
+
+The paths are listed in the rules of the rig's agents. The refinery holds a
+branch that changes them: it opens an approval task, mails the approver, and
+blocks the merge request until 'gt rig protected approve' records an
+approval. Approvals cover the approved files at the approved commit; later
+commits touching other protected files need another approval.`,
+}
+
+var rigProtectedScanCmd = &cobra.Command{
+	Use:   "scan <rig> [branch...]",
+	Short: "List agent branches that change protected paths",
+	Long: `Compare agent branches with the rig's default branch and list the
+protected files they change, and whether each change is approved. Without
+branches, every polecat branch is scanned. Exits 1 if any change is
+unapproved.
+
+Examples:
+  gt rig protected scan greenplace
+  gt rig protected scan greenplace polecat/Toast --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRigProtectedScan,
+}
+
+var rigProtectedApproveCmd = &cobra.Command{
+	Use:   "approve <rig> <branch>",
+	Short: "Approve a branch's changes to protected paths",
+	Long: `Record an approval for the protected files a branch changes at its
+current commit, and release merge requests of the branch the refinery is
+holding for approval.
+
+Examples:
+  gt rig protected approve greenplace polecat/Toast --reason "Reviewed migration"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRigProtectedApprove,
+}
+
+func init() {
+	rigProtectedScanCmd.Flags().BoolVar(&rigProtectedJSON, "json", false, "Output as JSON")
+	rigProtectedApproveCmd.Flags().StringVarP(&rigProtectedReason, "reason", "r", "", "Why the changes are approved")
+
+	rigProtectedCmd.AddCommand(rigProtectedScanCmd)
+	rigProtectedCmd.AddCommand(rigProtectedApproveCmd)
+	rigCmd.AddCommand(rigProtectedCmd)
+}
+
+// loadProtectedPaths returns a rig's protected path settings, or an error
+// when it declares none.
+func loadProtectedPaths(r *rig.Rig) (*config.ProtectedPathsConfig, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil || settings.ProtectedPaths == nil || len(settings.ProtectedPaths.Paths) == 0 {
+		return nil, fmt.Errorf("rig '%s' has no protected paths (set protected_paths in %s)", r.Name, config.RigSettingsPath(r.Path))
+	}
+	return settings.ProtectedPaths, nil
+}
+
+func runRigProtectedScan(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := loadProtectedPaths(r)
+	if err != nil {
+		return err
+	}
+	g, base, err := rig.ProtectedRepo(r)
+	if err != nil {
+		return err
+	}
+
+	branches := args[1:]
+	if len(branches) == 0 {
+		if branches, err = g.ListBranches("polecat/*"); err != nil {
+			return fmt.Errorf("listing branches: %w", err)
+		}
+	}
+	scans, err := rig.ScanProtected(r, g, base, cfg, branches)
+	if err != nil {
+		return err
+	}
+
+	unapproved := 0
+	for _, s := range scans {
+		unapproved += len(s.Unapproved())
+	}
+	if rigProtectedJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if scans == nil {
+			scans = []rig.ProtectedScan{}
+		}
+		if err := enc.Encode(scans); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("%s Protected paths in %s: %s\n\n", style.Bold.Render("[protected]"), r.Name, strings.Join(cfg.Paths, ", "))
+		if len(scans) == 0 {
+			fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("No changes to protected paths in %d branch(es)", len(branches))))
+		}
+		for _, s := range scans {
+			fmt.Printf("  %s\n", style.Bold.Render(s.Branch))
+			for _, c := range s.Changes {
+				status := style.Error.Render("[UNAPPROVED]")
+				if c.Approved {
+					status = style.Success.Render("[APPROVED]  ")
+				}
+				fmt.Printf("    %s %s %s\n", status, c.File, style.Dim.Render("("+c.Pattern+")"))
+			}
+		}
+		if unapproved > 0 {
+			fmt.Printf("\n%s %d unapproved change(s). Approve with: gt rig protected approve %s <branch>\n",
+				style.WarningPrefix, unapproved, r.Name)
+		}
+	}
+	if unapproved > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runRigProtectedApprove(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	branch := args[1]
+	cfg, err := loadProtectedPaths(r)
+	if err != nil {
+		return err
+	}
+	g, base, err := rig.ProtectedRepo(r)
+	if err != nil {
+		return err
+	}
+	scans, err := rig.ScanProtected(r, g, base, cfg, []string{branch})
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	if len(scans) > 0 {
+		files = scans[0].Unapproved()
+	}
+	if len(files) == 0 {
+		fmt.Printf("%s %s has no unapproved changes to protected paths\n", style.SuccessPrefix, branch)
+	} else {
+		head, err := g.Rev(branch)
+		if err != nil {
+			return err
+		}
+		approval := rig.ProtectedApproval{
+			Branch:   branch,
+			Commit:   head,
+			Files:    files,
+			Approver: detectSender(),
+			Reason:   rigProtectedReason,
+		}
+		if err := rig.RecordProtectedApproval(r.Path, approval); err != nil {
+			return fmt.Errorf("recording approval: %w", err)
+		}
+		fmt.Printf("%s Approved %d protected change(s) on %s at %s\n", style.SuccessPrefix, len(files), branch, head[:8])
+		for _, f := range files {
+			fmt.Printf("  • %s\n", f)
+		}
+	}
+
+	released, err := releaseProtectedHolds(r, branch)
+	if err != nil {
+		return err
+	}
+	for _, id := range released {
+		fmt.Printf("%s Released merge request %s\n", style.SuccessPrefix, id)
+	}
+	return nil
+}
+
+// releaseProtectedHolds closes the approval tasks blocking a branch's
+// queued merge requests, so they re-enter the merge queue. Returns the
+// released MR IDs.
+func releaseProtectedHolds(r *rig.Rig, branch string) ([]string, error) {
+	queue := mrqueue.New(r.Path)
+	mrs, err := queue.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing merge queue: %w", err)
+	}
+	bd := beads.New(r.Path)
+	var released []string
+	for _, mr := range mrs {
+		if mr.Branch != branch || mr.BlockedBy == "" {
+			continue
+		}
+		task, err := bd.Show(mr.BlockedBy)
+		if err != nil || !strings.HasPrefix(task.Title, rig.ProtectedApprovalTitlePrefix) {
+			continue // Blocked on something else, e.g. a conflict task
+		}
+		if task.Status != "closed" {
+			if err := bd.CloseWithReason("Protected path changes approved", task.ID); err != nil {
+				return released, fmt.Errorf("closing approval task %s: %w", task.ID, err)
+			}
+		}
+		if err := queue.ClearBlockedBy(mr.ID); err != nil {
+			return released, err
+		}
+		released = append(released, mr.ID)
+	}
+	return released, nil
+}
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/git"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// Rig upstream command flags
+var (
+	rigUpstreamDue    bool
+	rigUpstreamDryRun bool
+	rigUpstreamQuiet  bool
+)
+
+var rigUpstreamCmd = &cobra.Command{
+	Use:   "upstream",
+	Short: "Keep forked rigs in sync with their upstream",
+	RunE:  requireSubcommand,
+	Long: `Keep a rig whose repo is a fork in sync with the repo it was forked from.
+
+Configure the upstream in <rig>/settings/config.json:
+
+  "upstream": {
+    "url": "https://github.com/original/project.git",
+    "branch": "main",
+    "interval": "6h"
+  }
+
+branch (optional) defaults to the rig's default branch; interval (optional,
+default 6h) is how often the daemon syncs, "0" for manual syncs only.
+
+Each sync fetches upstream into the rig's shared repo and then:
+  - fast-forwards the fork's default branch on origin when the fork has
+    no commits of its own
+  - opens a sync task bead when both sides have commits (one at a time)
+  - mails the witness when incoming upstream changes touch files that
+    in-flight polecat branches also change`,
+}
+
+var rigUpstreamSyncCmd = &cobra.Command{
+	Use:   "sync [rig...]",
+	Short: "Sync forked rigs with their upstream",
+	Long: `Fetch each rig's upstream and fast-forward, or open a sync task if the
+fork has diverged. Without arguments, every rig with an upstream is synced.
+
+The daemon runs 'gt rig upstream sync --due --quiet' on each heartbeat, which
+only syncs rigs whose interval has elapsed.
+
+Examples:
+  gt rig upstream sync greenplace
+  gt rig upstream sync --dry-run     # Fetch and compare, change nothing`,
+	RunE: runRigUpstreamSync,
+}
+
+var rigUpstreamStatusCmd = &cobra.Command{
+	Use:   "status [rig...]",
+	Short: "Show upstream sync status",
+	Long: `Show each forked rig's upstream, when it last synced, and any open sync
+task. Nothing is fetched; use 'gt rig upstream sync --dry-run' to compare now.`,
+	RunE: runRigUpstreamStatus,
+}
+
+func init() {
+	rigUpstreamSyncCmd.Flags().BoolVar(&rigUpstreamDue, "due", false, "Only sync rigs whose sync interval has elapsed")
+	rigUpstreamSyncCmd.Flags().BoolVar(&rigUpstreamDryRun, "dry-run", false, "Fetch and compare without pushing, opening tasks, or notifying")
+	rigUpstreamSyncCmd.Flags().BoolVarP(&rigUpstreamQuiet, "quiet", "q", false, "Only print errors")
+
+	rigUpstreamCmd.AddCommand(rigUpstreamSyncCmd)
+	rigUpstreamCmd.AddCommand(rigUpstreamStatusCmd)
+	rigCmd.AddCommand(rigUpstreamCmd)
+}
+
+// upstreamRig is a rig with an upstream configured.
+type upstreamRig struct {
+	rig      *rig.Rig
+	upstream *config.UpstreamConfig
+}
+
+// loadUpstreamRigs returns the named rigs, or every rig with an upstream
+// when names is empty.
+func loadUpstreamRigs(names []string) (string, []upstreamRig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return "", nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+
+	var rigs []*rig.Rig
+	if len(names) == 0 {
+		if rigs, err = mgr.DiscoverRigs(); err != nil {
+			return "", nil, err
+		}
+		sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+	} else {
+		for _, name := range names {
+			r, err := mgr.GetRig(name)
+			if err != nil {
+				return "", nil, fmt.Errorf("rig '%s' not found", name)
+			}
+			rigs = append(rigs, r)
+		}
+	}
+
+	var result []upstreamRig
+	for _, r := range rigs {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+		if err != nil || settings.Upstream == nil {
+			if len(names) > 0 {
+				return "", nil, fmt.Errorf("rig '%s' has no upstream configured (set \"upstream\" in settings/config.json)", r.Name)
+			}
+			continue
+		}
+		result = append(result, upstreamRig{rig: r, upstream: settings.Upstream})
+	}
+	return townRoot, result, nil
+}
+
+func runRigUpstreamSync(cmd *cobra.Command, args []string) error {
+	townRoot, rigs, err := loadUpstreamRigs(args)
+	if err != nil {
+		return err
+	}
+	if len(rigs) == 0 && !rigUpstreamQuiet {
+		fmt.Println("No rigs have an upstream configured.")
+	}
+
+	var failed []string
+	now := time.Now()
+	for _, ur := range rigs {
+		state, err := rig.LoadUpstreamState(ur.rig.Path)
+		if err != nil {
+			state = &rig.UpstreamState{}
+		}
+		if rigUpstreamDue && !state.Due(ur.upstream, now) {
+			continue
+		}
+		if err := syncRigUpstream(townRoot, ur, state, now); err != nil {
+			failed = append(failed, ur.rig.Name)
+			fmt.Printf("%s %s: %v\n", style.ErrorPrefix, ur.rig.Name, err)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("upstream sync failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// syncRigUpstream syncs one rig and follows up on the outcome: an event for
+// fast-forwards, a task bead for divergence, and mail to the witness for
+// overlapping in-flight work. State is saved unless this is a dry run.
+func syncRigUpstream(townRoot string, ur upstreamRig, state *rig.UpstreamState, now time.Time) error {
+	r := ur.rig
+	result, err := rig.SyncUpstream(r, ur.upstream, rigUpstreamDryRun)
+	if rigUpstreamDryRun {
+		if err == nil {
+			printUpstreamResult(r.Name, result, "")
+		}
+		return err
+	}
+
+	state.LastSync = now
+	if err != nil {
+		state.LastError = err.Error()
+		_ = rig.SaveUpstreamState(r.Path, state)
+		return err
+	}
+	state.LastOutcome, state.LastError = result.Outcome, ""
+
+	var task string
+	switch result.Outcome {
+	case rig.UpstreamFastForwarded:
+		_ = events.LogFeed(events.TypeUpstreamSync, "daemon", events.UpstreamSyncPayload(r.Name, result.Outcome, result.UpstreamSHA, result.Incoming))
+	case rig.UpstreamDiverged:
+		bd := beads.New(r.Path)
+		if state.SyncTaskBead != "" {
+			if issue, err := bd.Show(state.SyncTaskBead); err == nil && issue.Status != "closed" {
+				task = state.SyncTaskBead
+			}
+		}
+		if task == "" {
+			issue, err := bd.Create(beads.CreateOptions{
+				Title:       fmt.Sprintf("Sync %s with upstream %s", r.Name, result.UpstreamBranch),
+				Type:        "task",
+				Priority:    2,
+				Description: upstreamTaskDescription(result),
+			})
+			if err != nil {
+				style.PrintWarning("could not open sync task for %s: %v", r.Name, err)
+			} else {
+				task = issue.ID
+				_ = events.LogFeed(events.TypeUpstreamSync, "daemon", events.UpstreamSyncPayload(r.Name, result.Outcome, result.UpstreamSHA, result.Incoming))
+			}
+		}
+		state.SyncTaskBead = task
+	case rig.UpstreamUpToDate:
+		state.SyncTaskBead = ""
+	}
+
+	if len(result.Overlaps) > 0 && result.UpstreamSHA != state.NotifiedSHA {
+		if err := notifyWitnessOfUpstream(townRoot, r.Name, result); err != nil {
+			style.PrintWarning("could not notify %s/witness: %v", r.Name, err)
+		} else {
+			state.NotifiedSHA = result.UpstreamSHA
+		}
+	}
+
+	if err := rig.SaveUpstreamState(r.Path, state); err != nil {
+		style.PrintWarning("could not save upstream state for %s: %v", r.Name, err)
+	}
+	printUpstreamResult(r.Name, result, task)
+	return nil
+}
+
+// upstreamTaskDescription explains how to resolve a diverged fork.
+func upstreamTaskDescription(result *rig.UpstreamSyncResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The fork's %s has %d commit(s) upstream does not, and upstream %s has %d the fork does not.\n\n",
+		result.DefaultBranch, result.Local, result.UpstreamBranch, result.Incoming)
+	fmt.Fprintf(&b, "Merge %s/%s into %s, resolve conflicts, and push.\n", rig.UpstreamRemote, result.UpstreamBranch, result.DefaultBranch)
+	fmt.Fprintf(&b, "The rig's shared repo already has the remote: git fetch %s\n", rig.UpstreamRemote)
+	if len(result.Files) > 0 {
+		fmt.Fprintf(&b, "\nFiles changed upstream:\n")
+		for _, f := range result.Files {
+			fmt.Fprintf(&b, "  %s\n", f)
+		}
+	}
+	return b.String()
+}
+
+// notifyWitnessOfUpstream tells the witness which polecats' branches touch
+// files that just changed upstream, so it can have them rebase early.
+func notifyWitnessOfUpstream(townRoot, rigName string, result *rig.UpstreamSyncResult) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Upstream %s has %d new commit(s) (%s, %s).\n", result.UpstreamBranch, result.Incoming, shortSHA(result.UpstreamSHA), result.Outcome)
+	fmt.Fprintf(&b, "In-flight polecat branches change the same files:\n\n")
+	for _, o := range result.Overlaps {
+		fmt.Fprintf(&b, "%s (%s)\n", o.Polecat, o.Branch)
+		for _, f := range o.Files {
+			fmt.Fprintf(&b, "  %s\n", f)
+		}
+	}
+	if result.Outcome == rig.UpstreamFastForwarded {
+		fmt.Fprintf(&b, "\n%s now includes these changes; ask the polecats to rebase before submitting.\n", result.DefaultBranch)
+	} else {
+		fmt.Fprintf(&b, "\nThe fork has diverged; expect conflicts when upstream is merged.\n")
+	}
+
+	router := mail.NewRouter(townRoot)
+	return router.Send(&mail.Message{
+		From:    "daemon",
+		To:      rigName + "/witness",
+		Subject: fmt.Sprintf("UPSTREAM_CHANGES: %d polecat(s) touch files changed upstream", len(result.Overlaps)),
+		Body:    b.String(),
+	})
+}
+
+// shortSHA abbreviates a commit hash for display.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+func printUpstreamResult(rigName string, result *rig.UpstreamSyncResult, task string) {
+	if rigUpstreamQuiet {
+		return
+	}
+	switch result.Outcome {
+	case rig.UpstreamUpToDate:
+		fmt.Printf("%s %s is up to date with upstream %s\n", style.SuccessPrefix, rigName, result.UpstreamBranch)
+	case rig.UpstreamFastForwarded:
+		fmt.Printf("%s %s: fast-forwarded %s by %d upstream commit(s)\n", style.SuccessPrefix, rigName, result.DefaultBranch, result.Incoming)
+	case rig.UpstreamBehind:
+		fmt.Printf("%s %s: would fast-forward %s by %d upstream commit(s)\n", style.Dim.Render("[dry-run]"), rigName, result.DefaultBranch, result.Incoming)
+	case rig.UpstreamDiverged:
+		fmt.Printf("%s %s has diverged: %d upstream, %d local commit(s)\n", style.WarningPrefix, rigName, result.Incoming, result.Local)
+		if task != "" {
+			fmt.Printf("    %s sync task: %s\n", style.ArrowPrefix, task)
+		}
+	}
+	for _, o := range result.Overlaps {
+		fmt.Printf("    %s %s touches %d file(s) changed upstream\n", style.Dim.Render("·"), o.Polecat, len(o.Files))
+	}
+}
+
+func runRigUpstreamStatus(cmd *cobra.Command, args []string) error {
+	_, rigs, err := loadUpstreamRigs(args)
+	if err != nil {
+		return err
+	}
+	if len(rigs) == 0 {
+		fmt.Println("No rigs have an upstream configured.")
+		return nil
+	}
+
+	now := time.Now()
+	for _, ur := range rigs {
+		state, err := rig.LoadUpstreamState(ur.rig.Path)
+		if err != nil {
+			state = &rig.UpstreamState{}
+		}
+		branch := ur.upstream.Branch
+		if branch == "" {
+			branch = ur.rig.DefaultBranch()
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render(ur.rig.Name), style.Dim.Render(ur.upstream.URL+" "+branch))
+
+		interval := "manual only"
+		if d := ur.upstream.SyncInterval(); d > 0 {
+			interval = "every " + d.String()
+		}
+		last := "never"
+		if !state.LastSync.IsZero() {
+			last = formatRelative(state.LastSync, now)
+		}
+		fmt.Printf("  Sync:  %s, last %s\n", interval, last)
+		switch {
+		case state.LastError != "":
+			fmt.Printf("  State: %s\n", style.Error.Render("failed: "+state.LastError))
+		case state.LastOutcome != "":
+			fmt.Printf("  State: %s\n", state.LastOutcome)
+		}
+		if state.SyncTaskBead != "" {
+			fmt.Printf("  Task:  %s\n", state.SyncTaskBead)
+		}
+	}
+	return nil
+}
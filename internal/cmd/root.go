@@ -19,22 +19,39 @@ across distributed teams of AI agents working on shared codebases.`,
 	PersistentPreRunE: checkBeadsDependency,
 }
 
-// Commands that don't require beads to be installed/checked.
+// Commands that don't require beads to be installed/checked, keyed by full
+// command path (see buildCommandPath) so a leaf name shared with another
+// parent, like gt session capture, is not exempted by accident.
 // These are basic utility commands that should work without beads.
 var beadsExemptCommands = map[string]bool{
-	"version":    true,
-	"help":       true,
-	"completion": true,
+	"gt version":                true,
+	"gt help":                   true,
+	"gt completion":             true,
+	"gt completion bash":        true,
+	"gt completion fish":        true,
+	"gt completion powershell":  true,
+	"gt completion zsh":         true,
+	"gt completion town-prompt": true, // runs on every shell prompt; must stay fast
+	"gt replay":                 true,
+	"gt replay capture":         true, // runs on every agent tool call
+	"gt secret resolve":         true, // runs in every agent startup command
+	"gt self-update":            true, // must work to fix an install whose beads check fails
+	"gt templates selftest":     true, // needs no workspace (CI)
+	"gt snapshot":               true, // captures diagnostics even when bd is missing or too old
+	"gt mcp serve":              true, // started by agents' MCP clients
 }
 
 // checkBeadsDependency verifies beads meets minimum version requirements.
 // Skips check for exempt commands (version, help, completion).
 func checkBeadsDependency(cmd *cobra.Command, args []string) error {
-	// Get the root command name being run
-	cmdName := cmd.Name()
-
 	// Skip check for exempt commands
-	if beadsExemptCommands[cmdName] {
+	if beadsExemptCommands[buildCommandPath(cmd)] {
+		return nil
+	}
+
+	// Cursor hook handlers (gt cursor-hook <event>) run on every agent event
+	// and must never fail
+	if parent := cmd.Parent(); parent != nil && parent == cursorHookCmd {
 		return nil
 	}
 
@@ -86,6 +103,11 @@ func init() {
 	rootCmd.SetHelpCommandGroupID(GroupDiag)
 	rootCmd.SetCompletionCommandGroupID(GroupConfig)
 
+	// Materialize the default completion command now (cobra normally creates
+	// it lazily in Execute) so gt-specific helpers can hang off it.
+	rootCmd.InitDefaultCompletionCmd()
+	addCompletionSubcommands()
+
 	// Global flags can be added here
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file")
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBeadsExemptCommandsResolve(t *testing.T) {
+	rootCmd.InitDefaultHelpCmd()
+	for path := range beadsExemptCommands {
+		args := strings.Fields(path)[1:]
+		cmd, _, err := rootCmd.Find(args)
+		if err != nil {
+			t.Errorf("%q: %v", path, err)
+			continue
+		}
+		if got := buildCommandPath(cmd); got != path {
+			t.Errorf("%q resolves to %q; exemptions must name full command paths", path, got)
+		}
+	}
+}
+
+func TestBeadsExemptCommandsMatchFullPath(t *testing.T) {
+	// gt replay capture is exempt; gt session capture shares its leaf name
+	// but needs beads
+	cmd, _, err := rootCmd.Find([]string{"session", "capture"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if beadsExemptCommands[buildCommandPath(cmd)] {
+		t.Errorf("%s is exempt from the beads check", buildCommandPath(cmd))
+	}
+	if beadsExemptCommands[cmd.Name()] {
+		t.Errorf("exemptions are keyed by leaf name %q", cmd.Name())
+	}
+}
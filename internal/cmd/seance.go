@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -21,6 +20,8 @@ var (
 	seanceRig    string
 	seanceRecent int
 	seanceJSON   bool
+	seanceStats  bool
+	seanceDays   int
 )
 
 var seanceCmd = &cobra.Command{
@@ -37,6 +38,10 @@ DISCOVERY:
   gt seance --rig gastown       # Filter by rig
   gt seance --recent 10         # Last N sessions
 
+STATS:
+  gt seance --stats             # Sessions started per day, by role
+  gt seance --stats --days 30   # Over the last 30 days
+
 Sessions are discovered from:
   1. Events emitted by SessionStart hooks (~/gt/.events.jsonl)
   2. The [GAS TOWN] beacon makes sessions searchable in /resume`,
@@ -48,12 +53,15 @@ func init() {
 	seanceCmd.Flags().StringVar(&seanceRig, "rig", "", "Filter by rig name")
 	seanceCmd.Flags().IntVarP(&seanceRecent, "recent", "n", 20, "Number of recent sessions to show")
 	seanceCmd.Flags().BoolVar(&seanceJSON, "json", false, "Output as JSON")
+	seanceCmd.Flags().BoolVar(&seanceStats, "stats", false, "Show sessions started per day by role")
+	seanceCmd.Flags().IntVar(&seanceDays, "days", 7, "Days covered by --stats")
 
 	rootCmd.AddCommand(seanceCmd)
 }
 
 // sessionEvent represents a session_start event from our event stream.
 type sessionEvent struct {
+	ID        string                 `json:"id"`
 	Timestamp string                 `json:"ts"`
 	Type      string                 `json:"type"`
 	Actor     string                 `json:"actor"`
@@ -61,6 +69,9 @@ type sessionEvent struct {
 }
 
 func runSeance(cmd *cobra.Command, args []string) error {
+	if seanceStats {
+		return runSeanceStats()
+	}
 	// Otherwise, list discoverable sessions
 	return runSeanceList()
 }
@@ -80,19 +91,9 @@ func runSeanceList() error {
 	// Apply filters
 	var filtered []sessionEvent
 	for _, s := range sessions {
-		if seanceRole != "" {
-			actor := strings.ToLower(s.Actor)
-			if !strings.Contains(actor, strings.ToLower(seanceRole)) {
-				continue
-			}
-		}
-		if seanceRig != "" {
-			actor := strings.ToLower(s.Actor)
-			if !strings.Contains(actor, strings.ToLower(seanceRig)) {
-				continue
-			}
+		if seanceMatches(s.Actor) {
+			filtered = append(filtered, s)
 		}
-		filtered = append(filtered, s)
 	}
 
 	// Apply limit
@@ -162,33 +163,16 @@ func runSeanceList() error {
 
 // discoverSessions reads session_start events from our event stream.
 func discoverSessions(townRoot string) ([]sessionEvent, error) {
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-
-	file, err := os.Open(eventsPath)
+	evs, err := events.ReadEvents(filepath.Join(townRoot, events.EventsFile), func(e events.Event) bool {
+		return e.Type == events.TypeSessionStart
+	})
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
 		return nil, err
 	}
-	defer file.Close()
 
-	var sessions []sessionEvent
-	scanner := bufio.NewScanner(file)
-
-	// Increase buffer for large lines
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		var event sessionEvent
-		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
-			continue
-		}
-
-		if event.Type == events.TypeSessionStart {
-			sessions = append(sessions, event)
-		}
+	sessions := make([]sessionEvent, 0, len(evs))
+	for _, e := range evs {
+		sessions = append(sessions, sessionEvent{ID: e.ID, Timestamp: e.Timestamp, Type: e.Type, Actor: e.Actor, Payload: e.Payload})
 	}
 
 	// Sort by timestamp descending (most recent first)
@@ -196,7 +180,97 @@ func discoverSessions(townRoot string) ([]sessionEvent, error) {
 		return sessions[i].Timestamp > sessions[j].Timestamp
 	})
 
-	return sessions, scanner.Err()
+	return sessions, nil
+}
+
+// seanceMatches applies the --role and --rig filters to a session's actor.
+func seanceMatches(actor string) bool {
+	actor = strings.ToLower(actor)
+	if seanceRole != "" && !strings.Contains(actor, strings.ToLower(seanceRole)) {
+		return false
+	}
+	return seanceRig == "" || strings.Contains(actor, strings.ToLower(seanceRig))
+}
+
+// seanceStatsRow is one day of session starts for --stats.
+type seanceStatsRow struct {
+	Date   string         `json:"date"`
+	Total  int            `json:"total"`
+	ByRole map[string]int `json:"by_role"`
+}
+
+// runSeanceStats prints how many sessions started each day, by role.
+func runSeanceStats() error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+	if seanceDays < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	rows, err := events.AggregateFile(filepath.Join(townRoot, events.EventsFile), events.Query{
+		Types:    []string{events.TypeSessionStart},
+		Since:    today.AddDate(0, 0, 1-seanceDays),
+		Bucket:   24 * time.Hour,
+		Location: time.Local,
+		By:       []string{"payload.role"},
+		Match:    func(e events.Event) bool { return seanceMatches(e.Actor) },
+	})
+	if err != nil {
+		return fmt.Errorf("reading session events: %w", err)
+	}
+	days := seanceStatsByDay(rows)
+
+	if seanceJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(days)
+	}
+	if len(days) == 0 {
+		fmt.Printf("No sessions started in the last %d day(s).\n", seanceDays)
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Sessions started, last %d day(s)", seanceDays)))
+	total := 0
+	for _, d := range days {
+		roles := make([]string, 0, len(d.ByRole))
+		for role := range d.ByRole {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+		parts := make([]string, 0, len(roles))
+		for _, role := range roles {
+			parts = append(parts, fmt.Sprintf("%s %d", role, d.ByRole[role]))
+		}
+		fmt.Printf("  %s  %4d  %s\n", d.Date, d.Total, style.Dim.Render(strings.Join(parts, ", ")))
+		total += d.Total
+	}
+	fmt.Printf("\n  Total: %d\n", total)
+	return nil
+}
+
+// seanceStatsByDay folds per-day, per-role aggregate rows into one row per
+// day, oldest first.
+func seanceStatsByDay(rows []events.Row) []seanceStatsRow {
+	var days []seanceStatsRow
+	for _, row := range rows {
+		date := row.Start.Format("2006-01-02")
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, seanceStatsRow{Date: date, ByRole: make(map[string]int)})
+		}
+		role := row.Labels["payload.role"]
+		if role == "" {
+			role = "unknown"
+		}
+		d := &days[len(days)-1]
+		d.ByRole[role] += row.Count
+		d.Total += row.Count
+	}
+	return days
 }
 
 func getPayloadString(payload map[string]interface{}, key string) string {
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestSeanceStatsByDay(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	rows := []events.Row{
+		{Start: day(1), Labels: map[string]string{"payload.role": "crew"}, Count: 2},
+		{Start: day(1), Labels: map[string]string{"payload.role": "mayor"}, Count: 1},
+		{Start: day(3), Labels: map[string]string{"payload.role": ""}, Count: 4},
+	}
+	days := seanceStatsByDay(rows)
+	if len(days) != 2 || days[0].Date != "2026-01-01" || days[0].Total != 3 || days[0].ByRole["crew"] != 2 {
+		t.Fatalf("days = %+v", days)
+	}
+	if days[1].ByRole["unknown"] != 4 {
+		t.Errorf("roleless sessions = %+v, want them counted as unknown", days[1])
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/git"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var (
+	searchRigs       []string
+	searchGlob       string
+	searchIgnoreCase bool
+	searchFixed      bool
+	searchLimit      int
+	searchJSON       bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:     "search <pattern>",
+	GroupID: GroupWork,
+	Short:   "Search code across every rig's checkout",
+	Long: `Search the code of every rig in the town for a regular expression, so an
+agent in one rig can find where a shared symbol is defined in another
+without wandering the filesystem.
+
+Each rig's canonical checkout is searched: the mayor's clone (mayor/rig),
+or the read-only checkout of a mirror rig. Searches use ripgrep when it is
+installed (honoring .gitignore) and a built-in searcher otherwise.
+Matches are printed as rig/path:line: text, in rig then path order.
+
+The same search is available to agents as the "search" tool of the Gas Town
+MCP server (gt mcp serve).
+
+Examples:
+  gt search 'func ParseConfig\('              # Where is ParseConfig defined?
+  gt search -F 'config.Load(' --glob '*.go'   # Literal text in Go files
+  gt search -i retrypolicy --rig api --rig web
+  gt search TODO --limit 20 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringSliceVar(&searchRigs, "rig", nil, "Only search these rigs (repeatable)")
+	searchCmd.Flags().StringVar(&searchGlob, "glob", "", "Only search files matching this glob (e.g. '*.go')")
+	searchCmd.Flags().BoolVarP(&searchIgnoreCase, "ignore-case", "i", false, "Match case-insensitively")
+	searchCmd.Flags().BoolVarP(&searchFixed, "fixed-strings", "F", false, "Treat the pattern as literal text")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", rig.DefaultSearchLimit, "Maximum matches to show")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	result, err := searchTown(townRoot, rig.SearchOptions{
+		Pattern:    args[0],
+		Rigs:       searchRigs,
+		Glob:       searchGlob,
+		IgnoreCase: searchIgnoreCase,
+		Fixed:      searchFixed,
+		Limit:      searchLimit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if searchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	if len(result.Matches) == 0 {
+		fmt.Println(style.Dim.Render("No matches"))
+	}
+	fmt.Print(formatSearchMatches(result))
+	if result.Truncated {
+		fmt.Println(style.Dim.Render(fmt.Sprintf("(first %d matches shown; narrow with --rig or --glob, or raise --limit)", len(result.Matches))))
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Printf("%s no checkout to search in: %s\n", style.Warning.Render("!"), strings.Join(result.Skipped, ", "))
+	}
+	return nil
+}
+
+// searchTown searches the checkouts of the town's rigs, mirrors included.
+// Unknown rig names in opts.Rigs are an error.
+func searchTown(townRoot string, opts rig.SearchOptions) (*rig.SearchResult, error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	for _, name := range opts.Rigs {
+		if !mgr.RigExists(name) {
+			return nil, fmt.Errorf("rig '%s' not found", name)
+		}
+	}
+
+	rigs, err := mgr.DiscoverRigs()
+	if err != nil {
+		return nil, err
+	}
+	mirrors, err := mgr.DiscoverMirrors()
+	if err != nil {
+		return nil, err
+	}
+	return rig.Search(append(rigs, mirrors...), opts)
+}
+
+// formatSearchMatches renders matches one per line as rig/path:line: text.
+func formatSearchMatches(result *rig.SearchResult) string {
+	var b strings.Builder
+	for _, m := range result.Matches {
+		fmt.Fprintf(&b, "%s/%s:%d: %s\n", m.Rig, m.Path, m.Line, m.Text)
+	}
+	return b.String()
+}
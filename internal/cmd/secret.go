@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/secrets"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var secretCmd = &cobra.Command{
+	Use:     "secret",
+	GroupID: GroupConfig,
+	Short:   "Resolve and check secret references in settings env",
+	Long: `Manage secret references used for agent credentials.
+
+The "env" map in settings/config.json (town) and <rig>/settings/config.json
+(rig) sets environment variables for agent sessions and the daemon. Values
+may be secret references, so API keys never sit in plaintext config:
+
+  secretRef:env:NAME                  Environment variable
+  secretRef:file:~/.config/gt/key     File contents
+  secretRef:keychain:service/account  OS keychain (macOS security, Linux secret-tool)
+  secretRef:op:op://vault/item/field  1Password CLI
+  secretRef:vault:path#field          HashiCorp Vault CLI
+
+References are resolved when an agent session spawns and when the daemon
+starts. Rig env overrides town env.
+
+Examples:
+  gt secret check                                   # Verify all references resolve
+  gt secret resolve secretRef:env:ANTHROPIC_API_KEY # Print one secret`,
+	RunE: requireSubcommand,
+}
+
+var secretResolveCmd = &cobra.Command{
+	Use:   "resolve <secretRef:provider:ref>",
+	Short: "Print the value of a secret reference",
+	Long: `Print the value of a secret reference to stdout.
+
+Used by agent startup commands to resolve secrets inside the session.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretResolve,
+}
+
+var secretCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Verify every secret reference in settings env resolves",
+	Long: `Resolve every secret reference in town and rig settings env.
+
+Prints variable names and status only; secret values are never shown.`,
+	Args: cobra.NoArgs,
+	RunE: runSecretCheck,
+}
+
+func init() {
+	secretCmd.AddCommand(secretResolveCmd)
+	secretCmd.AddCommand(secretCheckCmd)
+	rootCmd.AddCommand(secretCmd)
+}
+
+func runSecretResolve(cmd *cobra.Command, args []string) error {
+	if !secrets.IsRef(args[0]) {
+		return fmt.Errorf("not a secret reference: %q (want %s<provider>:<ref>)", args[0], secrets.RefPrefix)
+	}
+	value, err := secrets.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(value)
+	return nil
+}
+
+// secretScope is a settings env to check: the town's or one rig's.
+type secretScope struct {
+	name string
+	env  map[string]string
+}
+
+func runSecretCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	scopes := []secretScope{{"town", config.ResolveSessionEnv(townRoot, "")}}
+
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err == nil {
+		rigNames := make([]string, 0, len(rigsConfig.Rigs))
+		for name := range rigsConfig.Rigs {
+			rigNames = append(rigNames, name)
+		}
+		sort.Strings(rigNames)
+		for _, name := range rigNames {
+			rs, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, name)))
+			if err != nil || len(rs.Env) == 0 {
+				continue
+			}
+			scopes = append(scopes, secretScope{name, rs.Env})
+		}
+	}
+
+	refs, failed := 0, 0
+	for _, scope := range scopes {
+		keys := make([]string, 0, len(scope.env))
+		for k, v := range scope.env {
+			if secrets.IsRef(v) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			refs++
+			if _, err := secrets.Resolve(scope.env[k]); err != nil {
+				failed++
+				fmt.Printf("%s %s %s: %v\n", style.Error.Render("✗"), style.Dim.Render(scope.name), k, err)
+				continue
+			}
+			fmt.Printf("%s %s %s\n", style.Success.Render("✓"), style.Dim.Render(scope.name), k)
+		}
+	}
+
+	if refs == 0 {
+		fmt.Println("No secret references in settings env.")
+		return nil
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d of %d secret references failed to resolve\n", failed, refs)
+		return NewSilentExit(1)
+	}
+	fmt.Printf("\n%s %d secret references resolve\n", style.SuccessPrefix, refs)
+	return nil
+}
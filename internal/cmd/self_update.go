@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/selfupdate"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var (
+	selfUpdateCheck   bool
+	selfUpdateChannel string
+	selfUpdateVersion string
+	selfUpdateForce   bool
+)
+
+var selfUpdateCmd = &cobra.Command{
+	Use:     "self-update",
+	GroupID: GroupDiag,
+	Short:   "Upgrade gt to the latest release",
+	Long: `Download the latest gt release, verify it, and replace this binary.
+
+The release archive is checked against the release's checksums.txt, and
+the checksums against the release signing key built into this gt. The new
+binary is staged next to the current one and renamed into place, so a
+failed update leaves gt as it was. Inside a town, agent hooks are then
+re-synced with the new templates ('gt hooks sync').
+
+The channel comes from settings/config.json:
+
+  "self_update": {"channel": "prerelease"}
+
+"stable" (default) follows full releases; "prerelease" also takes release
+candidates. Set "releases_url" to use a mirror of the GitHub releases API;
+gt only installs from a mirror when it was built with a signing key, since
+the mirror's own checksums prove nothing.
+
+Examples:
+  gt self-update                  # Upgrade to the latest release
+  gt self-update --check          # Only report whether one is available
+  gt self-update --version 0.3.1  # Install a specific release`,
+	Args: cobra.NoArgs,
+	RunE: runSelfUpdate,
+}
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateCheck, "check", false, "Report whether an update is available without installing it")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateChannel, "channel", "", "Release channel: stable or prerelease (default from town settings)")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateVersion, "version", "", "Install this release instead of the latest")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "Reinstall even if already on that version")
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	// The town is optional: outside one, the default channel is used and
+	// there are no hooks to re-sync.
+	townRoot, _ := workspace.FindFromCwd()
+	var cfg *config.SelfUpdateConfig
+	if townRoot != "" {
+		if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+			cfg = settings.SelfUpdate
+		}
+	}
+	channel := cfg.UpdateChannel()
+	if selfUpdateChannel != "" {
+		channel = selfUpdateChannel
+	}
+	if channel != selfupdate.ChannelStable && channel != selfupdate.ChannelPrerelease {
+		return fmt.Errorf("unknown channel %q (use %s or %s)", channel, selfupdate.ChannelStable, selfupdate.ChannelPrerelease)
+	}
+
+	ctx := context.Background()
+	client := selfupdate.NewClient(cfg.URL())
+	var rel *selfupdate.Release
+	var err error
+	if selfUpdateVersion != "" {
+		rel, err = client.Tagged(ctx, selfUpdateVersion)
+	} else {
+		rel, err = client.Latest(ctx, channel)
+	}
+	if err != nil {
+		return fmt.Errorf("looking up release: %w", err)
+	}
+
+	newer := selfupdate.CompareVersions(rel.Version(), Version) > 0
+	if !newer && selfUpdateVersion == "" && !selfUpdateForce {
+		fmt.Printf("%s gt %s is the latest %s release\n", style.Success.Render("[OK]"), Version, channel)
+		return nil
+	}
+	if rel.Version() == Version && !selfUpdateForce {
+		fmt.Printf("%s Already on gt %s (use --force to reinstall)\n", style.Success.Render("[OK]"), Version)
+		return nil
+	}
+	if selfUpdateCheck {
+		fmt.Printf("gt %s is available (running %s)\n", rel.Version(), Version)
+		if rel.PageURL != "" {
+			fmt.Printf("  %s\n", style.Dim.Render(rel.PageURL))
+		}
+		fmt.Printf("  Install with: gt self-update\n")
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating gt binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	fmt.Printf("Downloading gt %s for %s/%s...\n", rel.Version(), runtime.GOOS, runtime.GOARCH)
+	binary, err := client.Fetch(ctx, rel, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return fmt.Errorf("fetching gt %s: %w", rel.Version(), err)
+	}
+	verified := "checksum verified"
+	if selfupdate.SigningKey != "" {
+		verified = "signature and checksum verified"
+	}
+	if err := selfupdate.Install(exe, binary); err != nil {
+		return fmt.Errorf("installing %s: %w", exe, err)
+	}
+	fmt.Printf("%s Installed gt %s at %s (%s)\n", style.Success.Render("[OK]"), rel.Version(), exe, verified)
+
+	if townRoot == "" {
+		return nil
+	}
+
+	// The new binary carries the new templates, so it does the re-sync.
+	sync := exec.Command(exe, "hooks", "sync") //nolint:gosec // G204: exe is this gt binary
+	sync.Dir = townRoot
+	sync.Stdout, sync.Stderr = os.Stdout, os.Stderr
+	if err := sync.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: hook re-sync failed: %v (run 'gt hooks sync')\n", err)
+	}
+	fmt.Printf("  %s\n", style.Dim.Render("Restart the daemon to run the new version: gt doctor --fix"))
+	return nil
+}
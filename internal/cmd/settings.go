@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/agent"
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+	"github.com/cursorworkshop/cursor-gastown/internal/doctor"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// Settings command flags
+var (
+	settingsDiffContext  int
+	settingsDiffExitCode bool
+	settingsSyncRig      string
+	settingsSyncRole     string
+	settingsSyncRules    bool
+	settingsSyncRestart  bool
+	settingsSyncWait     time.Duration
+)
+
+var settingsCmd = &cobra.Command{
+	Use:     "settings",
+	GroupID: GroupConfig,
+	Short:   "Inspect and sync agents' generated settings",
+	RunE:    requireSubcommand,
+}
+
+var settingsDiffCmd = &cobra.Command{
+	Use:   "diff [role|agent|path]",
+	Short: "Show what a settings sync would change",
+	Long: `Show a unified diff between each agent's installed Cursor settings
+(.cursor/hooks.json, rules) and what the current templates
+would generate, so you can see exactly what 'gt settings sync',
+'gt hooks sync', or 'gt doctor --fix' would change before running them.
+Settings for the town's other enabled agents (GEMINI.md and .gemini/,
+AGENTS.md and .codex/, .augment/rules/) are included where provisioned.
+
+Generated hooks.json is migrated and merged with hooks you added, just as
+a sync would. The rules file is only written when missing, so its
+differences are shown for reference unless you sync with --rules.
+
+Each changed file is labeled from the template hash gt records in it:
+user-modified (edited since gt wrote it), outdated template (generated from
+older templates), or both.
+
+Limit the diff to a role (witness), an agent (gastown/refinery), or a
+workspace path. Workspaces that were never provisioned are skipped.
+
+Examples:
+  gt settings diff
+  gt settings diff refinery
+  gt settings diff gastown/crew
+  gt settings diff ~/gt/gastown/witness --exit-code`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSettingsDiff,
+}
+
+var settingsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Regenerate agents' Cursor settings from templates",
+	Long: `Regenerate hooks.json, rules, and rule packs for the
+selected agents from the current templates in one step, without running
+the doctor checks. Settings provisioned for other enabled agents (Gemini,
+Codex, Amp, Auggie) are regenerated too. Use it to roll out template changes ('gt settings diff'
+shows what would change).
+
+Hooks you added to hooks.json are kept, and edited Gas Town hooks are kept
+and reported, as with 'gt hooks sync'. A missing rules file is restored,
+but an existing one is only overwritten with --rules. Files a sync
+replaces or removes are backed up first ('gt settings backups').
+
+Running sessions keep their old settings until restarted. With
+--restart-sessions, patrol sessions (witness, refinery, deacon) whose
+settings changed are cycled so they restart with the new ones; agents
+that look mid-task are left running. Crew and polecats pick up the new
+settings on their next start.
+
+A sync holds the town operation lock, like 'gt doctor --fix', so it never
+overlaps a fix run or the daemon's template resync; pass --wait to queue
+behind the current holder.
+
+Examples:
+  gt settings sync
+  gt settings sync --rig gastown --role refinery
+  gt settings sync --role witness --restart-sessions
+  gt settings sync --rules`,
+	Args: cobra.NoArgs,
+	RunE: runSettingsSync,
+}
+
+func init() {
+	settingsDiffCmd.Flags().IntVarP(&settingsDiffContext, "context", "U", 3, "Lines of context around each change")
+	settingsDiffCmd.Flags().BoolVar(&settingsDiffExitCode, "exit-code", false, "Exit 1 if a sync would change anything")
+	settingsSyncCmd.Flags().StringVar(&settingsSyncRig, "rig", "", "Only sync agents in this rig")
+	settingsSyncCmd.Flags().StringVar(&settingsSyncRole, "role", "", "Only sync agents with this role (mayor, deacon, witness, refinery, crew, polecat)")
+	settingsSyncCmd.Flags().BoolVar(&settingsSyncRules, "rules", false, "Also overwrite rules files that differ from the template")
+	settingsSyncCmd.Flags().BoolVar(&settingsSyncRestart, "restart-sessions", false, "Cycle idle patrol sessions whose settings changed")
+	settingsSyncCmd.Flags().DurationVar(&settingsSyncWait, "wait", 0, "Wait this long for a fix or sync run in the town to finish")
+	settingsCmd.AddCommand(settingsDiffCmd)
+	settingsCmd.AddCommand(settingsSyncCmd)
+	rootCmd.AddCommand(settingsCmd)
+}
+
+func runSettingsDiff(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	targets := daemon.TemplateTargets(townRoot, discoverRigs(townRoot))
+	if len(args) > 0 {
+		if targets = filterSettingsTargets(targets, args[0]); len(targets) == 0 {
+			return fmt.Errorf("no agent workspace matches %q (use a role, an agent like gastown/witness, or a workspace path)", args[0])
+		}
+	}
+
+	agents := agent.EnabledAgents(townRoot)
+	var pending, keptOnly int
+	for _, t := range targets {
+		printed := false
+		for _, f := range targetSettings(t, agents) {
+			if !f.Changed() {
+				continue
+			}
+			if !printed {
+				fmt.Printf("%s %s\n", style.Bold.Render(t.Agent), style.Dim.Render("("+t.Role+")"))
+				printed = true
+			}
+			rel := relToTown(townRoot, f.Path)
+			if f.Installed != nil && f.Generated != nil {
+				fmt.Println(style.Dim.Render("# " + rel + ": " + string(f.Status())))
+			}
+			if f.Pending() {
+				pending++
+			} else {
+				fmt.Println(style.Dim.Render("# " + rel + " is kept by a sync; reset it with gt settings sync --rules"))
+				keptOnly++
+			}
+			printColoredDiff(f.Diff(rel, settingsDiffContext))
+		}
+		if printed {
+			fmt.Println()
+		}
+	}
+
+	switch {
+	case pending == 0 && keptOnly == 0:
+		fmt.Printf("%s Installed settings match current templates\n", style.SuccessPrefix)
+	case pending == 0:
+		fmt.Printf("%s A sync would change nothing (%d kept rules file(s) differ)\n", style.SuccessPrefix, keptOnly)
+	default:
+		fmt.Printf("%d file(s) would change. Apply with: gt settings sync\n", pending)
+	}
+	if settingsDiffExitCode && pending > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runSettingsSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	targets := selectSyncTargets(daemon.TemplateTargets(townRoot, discoverRigs(townRoot)), settingsSyncRig, settingsSyncRole)
+	if len(targets) == 0 {
+		return fmt.Errorf("no agent workspace matches --rig %q --role %q", settingsSyncRig, settingsSyncRole)
+	}
+
+	opLock, err := acquireOperationLock(townRoot, "settings sync", settingsSyncWait)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = opLock.Release() }()
+
+	t := tmux.NewTmux()
+	agents := agent.EnabledAgents(townRoot)
+	var synced, current, failed int
+	for _, target := range targets {
+		changed, provisioned, err := syncTargetSettings(target, agents, settingsSyncRules)
+		if err != nil {
+			fmt.Printf("%s %s: %v\n", style.WarningPrefix, target.Agent, err)
+			failed++
+			continue
+		}
+		if !provisioned {
+			continue
+		}
+		if changed == 0 {
+			current++
+			continue
+		}
+		synced++
+		fmt.Printf("  Synced %s %s\n", target.Agent, style.Dim.Render(fmt.Sprintf("(%d file(s))", changed)))
+		for _, conflict := range cursor.HookConflicts(target.WorkDir, target.Role) {
+			fmt.Printf("    %s kept edited hook %s\n", style.WarningPrefix, conflict)
+		}
+
+		cycled := false
+		if settingsSyncRestart && target.Session != "" {
+			if running, _ := t.HasSession(target.Session); running {
+				if busy, reason := doctor.SessionBusy(townRoot, target.Session); busy {
+					fmt.Printf("    Left %s running: agent is busy (%s); it picks up the settings on its next start\n", target.Session, reason)
+				} else if err := t.KillSession(target.Session); err != nil {
+					fmt.Printf("    %s cycling %s: %v\n", style.WarningPrefix, target.Session, err)
+				} else {
+					cycled = true
+					fmt.Printf("    Cycled %s\n", target.Session)
+				}
+			}
+		}
+		_ = events.LogAudit(events.TypeTemplateResync, "gt", events.TemplateResyncPayload(target.Agent, cycled, "gt settings sync"))
+	}
+
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%d agent workspace(s) could not be synced", failed)
+	case synced == 0:
+		fmt.Printf("%s Settings for %d agent workspace(s) already match current templates\n", style.SuccessPrefix, current)
+	default:
+		fmt.Printf("%s Synced settings for %d agent workspace(s)\n", style.SuccessPrefix, synced)
+	}
+	return nil
+}
+
+// targetSettings returns the settings files of every enabled agent that is
+// provisioned in a target's workspace, each file once (Codex and Amp share
+// AGENTS.md). Agents whose settings cannot be generated are reported and
+// skipped.
+func targetSettings(target daemon.TemplateTarget, agents []string) []cursor.SettingsFile {
+	var files []cursor.SettingsFile
+	seen := make(map[string]bool)
+	for _, name := range agents {
+		agentFiles, err := agent.GeneratedSettings(target.WorkDir, target.Role, name)
+		if err != nil {
+			fmt.Printf("%s %s (%s): %v\n", style.WarningPrefix, target.Agent, name, err)
+			continue
+		}
+		if !settingsProvisioned(agentFiles) {
+			continue
+		}
+		for _, f := range agentFiles {
+			if !seen[f.Path] {
+				seen[f.Path] = true
+				files = append(files, f)
+			}
+		}
+	}
+	return files
+}
+
+// syncTargetSettings syncs the settings of every enabled agent provisioned
+// in a target's workspace, returning how many files changed and whether any
+// agent was provisioned there. With rules, an edited rules file is reset.
+func syncTargetSettings(target daemon.TemplateTarget, agents []string, rules bool) (changed int, provisioned bool, err error) {
+	for _, name := range agents {
+		files, err := agent.GeneratedSettings(target.WorkDir, target.Role, name)
+		if err != nil {
+			return changed, provisioned, fmt.Errorf("%s settings: %w", name, err)
+		}
+		if !settingsProvisioned(files) {
+			continue
+		}
+		provisioned = true
+		n, resetRules := syncChanges(files, rules)
+		if n == 0 {
+			continue
+		}
+		if err := agent.EnsureSettingsForRole(target.WorkDir, target.Role, name); err != nil {
+			return changed, provisioned, fmt.Errorf("%s settings: %w", name, err)
+		}
+		if resetRules {
+			if err := cursor.ResetRulesForRole(target.WorkDir, target.Role); err != nil {
+				return changed, provisioned, err
+			}
+		}
+		changed += n
+	}
+	return changed, provisioned, nil
+}
+
+// selectSyncTargets keeps targets in rig (town-level agents have none) with
+// role; empty filters match everything.
+func selectSyncTargets(targets []daemon.TemplateTarget, rig, role string) []daemon.TemplateTarget {
+	var selected []daemon.TemplateTarget
+	for _, t := range targets {
+		if rig != "" && !strings.HasPrefix(t.Agent, rig+"/") {
+			continue
+		}
+		if role != "" && t.Role != role {
+			continue
+		}
+		selected = append(selected, t)
+	}
+	return selected
+}
+
+// syncChanges counts the files a sync would write. Existing rules files
+// only count when resetting rules; resetRules reports whether one differs.
+func syncChanges(files []cursor.SettingsFile, rules bool) (changed int, resetRules bool) {
+	for _, f := range files {
+		if !f.Changed() {
+			continue
+		}
+		if f.KeptIfPresent && f.Installed != nil {
+			if !rules {
+				continue
+			}
+			resetRules = true
+		}
+		changed++
+	}
+	return changed, resetRules
+}
+
+// filterSettingsTargets keeps targets matching a role, an agent address, or
+// a path inside their workspace.
+func filterSettingsTargets(targets []daemon.TemplateTarget, arg string) []daemon.TemplateTarget {
+	abs, _ := filepath.Abs(arg)
+	var matched []daemon.TemplateTarget
+	for _, t := range targets {
+		rel, err := filepath.Rel(t.WorkDir, abs)
+		inside := err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+		if t.Role == arg || t.Agent == arg || inside {
+			matched = append(matched, t)
+		}
+	}
+	return matched
+}
+
+// settingsProvisioned reports whether any managed file is installed.
+func settingsProvisioned(files []cursor.SettingsFile) bool {
+	for _, f := range files {
+		if f.Installed != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// printColoredDiff prints a unified diff with added and removed lines
+// colored.
+func printColoredDiff(diff string) {
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case i < 2: // --- and +++ file headers
+			line = style.Bold.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			line = style.Info.Render(line)
+		case strings.HasPrefix(line, "+"):
+			line = style.Success.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = style.Error.Render(line)
+		}
+		fmt.Println(line)
+	}
+}
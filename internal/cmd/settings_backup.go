@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/settingsbackup"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	settingsBackupsJSON bool
+	settingsRestoreDiff bool
+)
+
+var settingsBackupsCmd = &cobra.Command{
+	Use:   "backups [path]",
+	Short: "List backups of settings files a sync replaced",
+	Long: `List the previous versions of agent settings files (hooks.json, rules,
+AGENTS.md and other instructions files, mcp.json) that 'gt settings sync',
+'gt hooks sync', session starts, or 'gt doctor --fix' replaced or removed.
+
+Backups are kept in .runtime/settings-backups/, the newest 10 per file.
+Limit the list to files under a path (a workspace or a single file).
+
+Examples:
+  gt settings backups
+  gt settings backups gastown/crew/max
+  gt settings restore 20261017-142530`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSettingsBackups,
+}
+
+var settingsRestoreCmd = &cobra.Command{
+	Use:   "restore <backup-id>",
+	Short: "Restore a settings file from a backup",
+	Long: `Write a backup listed by 'gt settings backups' back to its original
+path. The file it replaces is backed up first, so a restore can be undone
+the same way.
+
+A later settings sync rewrites gt-owned content again (hooks you added to
+hooks.json and edited rules files are kept).
+
+Examples:
+  gt settings restore 20261017-142530
+  gt settings restore 20261017-142530 --diff  # Show the change without restoring`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSettingsRestore,
+}
+
+func init() {
+	settingsBackupsCmd.Flags().BoolVar(&settingsBackupsJSON, "json", false, "Output as JSON")
+	settingsRestoreCmd.Flags().BoolVar(&settingsRestoreDiff, "diff", false, "Show what the restore would change without restoring")
+	settingsCmd.AddCommand(settingsBackupsCmd)
+	settingsCmd.AddCommand(settingsRestoreCmd)
+}
+
+func runSettingsBackups(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	backups, err := settingsbackup.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing backups: %w", err)
+	}
+	if len(args) > 0 {
+		backups = filterBackups(backups, townRoot, args[0])
+	}
+
+	if settingsBackupsJSON {
+		if backups == nil {
+			backups = []settingsbackup.Backup{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(backups)
+	}
+	if len(backups) == 0 {
+		fmt.Println(style.Dim.Render("No settings backups"))
+		return nil
+	}
+	for _, b := range backups {
+		fmt.Printf("%-20s %s  %s\n", b.ID, style.Dim.Render(fmt.Sprintf("%-16s", formatAge(b.CreatedAt))), townRelative(townRoot, b.Path))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Restore with: gt settings restore <id>"))
+	return nil
+}
+
+func runSettingsRestore(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if settingsRestoreDiff {
+		content, b, err := settingsbackup.Content(townRoot, args[0])
+		if err != nil {
+			return err
+		}
+		current, err := os.ReadFile(b.Path) //nolint:gosec // G304: path is a backed-up settings file
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		name := townRelative(townRoot, b.Path)
+		from := name
+		if current == nil {
+			from = "/dev/null"
+		}
+		diff := util.UnifiedDiff(from, name+" (backup "+b.ID+")", current, content, 3)
+		if diff == "" {
+			fmt.Printf("%s %s already matches the backup\n", style.SuccessPrefix, name)
+			return nil
+		}
+		fmt.Print(diff)
+		return nil
+	}
+
+	b, err := settingsbackup.Restore(townRoot, args[0])
+	if errors.Is(err, settingsbackup.ErrNotFound) {
+		return fmt.Errorf("%w (see 'gt settings backups')", err)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Restored %s from %s\n", style.SuccessPrefix, townRelative(townRoot, b.Path), b.ID)
+	return nil
+}
+
+// filterBackups keeps the backups of files at or under filter, a path
+// absolute or relative to the town root.
+func filterBackups(backups []settingsbackup.Backup, townRoot, filter string) []settingsbackup.Backup {
+	if !filepath.IsAbs(filter) {
+		filter = filepath.Join(townRoot, filter)
+	}
+	filter = filepath.Clean(filter)
+	var out []settingsbackup.Backup
+	for _, b := range backups {
+		if b.Path == filter || strings.HasPrefix(b.Path, filter+string(filepath.Separator)) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// townRelative shows path relative to the town root when it is inside it.
+func townRelative(townRoot, path string) string {
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+)
+
+func TestFilterSettingsTargets(t *testing.T) {
+	townRoot := t.TempDir()
+	targets := daemon.TemplateTargets(townRoot, []string{"gp", "web"})
+
+	tests := []struct {
+		arg  string
+		want []string
+	}{
+		{"refinery", []string{"gp/refinery", "web/refinery"}},
+		{"web/crew", []string{"web/crew"}},
+		{filepath.Join(townRoot, "gp", "witness", ".cursor"), []string{"gp/witness"}},
+		{filepath.Join(townRoot, "mayor"), []string{"mayor"}},
+		{"nobody", nil},
+	}
+	for _, tt := range tests {
+		if got := targetAgents(filterSettingsTargets(targets, tt.arg)); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestSelectSyncTargets(t *testing.T) {
+	targets := daemon.TemplateTargets(t.TempDir(), []string{"gp", "web"})
+	tests := []struct {
+		rig, role string
+		want      []string
+	}{
+		{"gp", "", []string{"gp/polecats", "gp/crew", "gp/refinery", "gp/witness"}},
+		{"", "deacon", []string{"deacon"}},
+		{"web", "witness", []string{"web/witness"}},
+		{"gp", "mayor", nil},
+	}
+	for _, tt := range tests {
+		if got := targetAgents(selectSyncTargets(targets, tt.rig, tt.role)); !slices.Equal(got, tt.want) {
+			t.Errorf("--rig %q --role %q: got %v, want %v", tt.rig, tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestSyncChanges(t *testing.T) {
+	files := []cursor.SettingsFile{
+		{Path: "hooks.json", Installed: []byte("old"), Generated: []byte("new")},
+		{Path: "gastown-stop.sh", Installed: []byte("same"), Generated: []byte("same")},
+		{Path: "gastown.mdc", Installed: []byte("edited"), Generated: []byte("rules"), KeptIfPresent: true},
+	}
+	if changed, reset := syncChanges(files, false); changed != 1 || reset {
+		t.Errorf("without --rules: changed %d, reset %v; want 1, false", changed, reset)
+	}
+	if changed, reset := syncChanges(files, true); changed != 2 || !reset {
+		t.Errorf("with --rules: changed %d, reset %v; want 2, true", changed, reset)
+	}
+
+	// A missing rules file is restored either way
+	files[2].Installed = nil
+	if changed, reset := syncChanges(files, false); changed != 2 || reset {
+		t.Errorf("missing rules: changed %d, reset %v; want 2, false", changed, reset)
+	}
+}
+
+func targetAgents(ts []daemon.TemplateTarget) []string {
+	var out []string
+	for _, t := range ts {
+		out = append(out, t.Agent)
+	}
+	return out
+}
@@ -140,6 +140,9 @@ var (
 	slingAccount  string // --account: Cursor account handle to use
 	slingAgent    string // --agent: override runtime agent for this sling/spawn
 	slingNoConvoy bool   // --no-convoy: skip auto-convoy creation
+
+	slingTimebox   time.Duration // --timebox: wall-clock limit for spawned polecats
+	slingCleanroom bool          // --cleanroom: run spawned polecats in a container
 )
 
 func init() {
@@ -157,6 +160,8 @@ func init() {
 	slingCmd.Flags().StringVar(&slingAccount, "account", "", "Cursor account handle to use")
 slingCmd.Flags().StringVar(&slingAgent, "agent", "", "Override agent/runtime for this sling (e.g., cursor, gemini, codex, or custom alias)")
 	slingCmd.Flags().BoolVar(&slingNoConvoy, "no-convoy", false, "Skip auto-convoy creation for single-issue sling")
+	slingCmd.Flags().DurationVar(&slingTimebox, "timebox", 0, "Wall-clock limit for spawned polecats (e.g., 2h); overrides the bead's 'timebox:' field")
+	slingCmd.Flags().BoolVar(&slingCleanroom, "cleanroom", false, "Run spawned polecats in a container built from the rig's cleanroom_image (also set by a 'cleanroom: true' bead field)")
 
 	rootCmd.AddCommand(slingCmd)
 }
@@ -274,12 +279,14 @@ func runSling(cmd *cobra.Command, args []string) error {
 				// Spawn a fresh polecat in the rig
 				fmt.Printf("Target is rig '%s', spawning fresh polecat...\n", rigName)
 				spawnOpts := SlingSpawnOptions{
-					Force:    slingForce,
-					Naked:    slingNaked,
-					Account:  slingAccount,
-					Create:   slingCreate,
-					HookBead: beadID, // Set atomically at spawn time
-					Agent:    slingAgent,
+					Force:     slingForce,
+					Naked:     slingNaked,
+					Account:   slingAccount,
+					Create:    slingCreate,
+					HookBead:  beadID, // Set atomically at spawn time
+					Agent:     slingAgent,
+					Timebox:   slingTimebox,
+					Cleanroom: slingCleanroom,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -735,9 +742,10 @@ func verifyBeadExists(beadID string) error {
 
 // beadInfo holds status and assignee for a bead.
 type beadInfo struct {
-	Title    string `json:"title"`
-	Status   string `json:"status"`
-	Assignee string `json:"assignee"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Assignee    string `json:"assignee"`
+	Description string `json:"description"`
 }
 
 // getBeadInfo returns status and assignee for a bead.
@@ -895,11 +903,13 @@ func runSlingFormula(args []string) error {
 				// Spawn a fresh polecat in the rig
 				fmt.Printf("Target is rig '%s', spawning fresh polecat...\n", rigName)
 				spawnOpts := SlingSpawnOptions{
-					Force:   slingForce,
-					Naked:   slingNaked,
-					Account: slingAccount,
-					Create:  slingCreate,
-					Agent:   slingAgent,
+					Force:     slingForce,
+					Naked:     slingNaked,
+					Account:   slingAccount,
+					Create:    slingCreate,
+					Agent:     slingAgent,
+					Timebox:   slingTimebox,
+					Cleanroom: slingCleanroom,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -1438,12 +1448,14 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
-			Force:    slingForce,
-			Naked:    slingNaked,
-			Account:  slingAccount,
-			Create:   slingCreate,
-			HookBead: beadID, // Set atomically at spawn time
-			Agent:    slingAgent,
+			Force:     slingForce,
+			Naked:     slingNaked,
+			Account:   slingAccount,
+			Create:    slingCreate,
+			HookBead:  beadID, // Set atomically at spawn time
+			Agent:     slingAgent,
+			Timebox:   slingTimebox,
+			Cleanroom: slingCleanroom,
 		}
 		spawnInfo, err := SpawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -0,0 +1,408 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/doctor"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/secrets"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// SnapshotSchemaVersion is the schema version of a snapshot's manifest.json.
+const SnapshotSchemaVersion = 1
+
+// snapshotToolTimeout bounds each external tool version lookup.
+const snapshotToolTimeout = 5 * time.Second
+
+var (
+	snapshotOut    string
+	snapshotEvents int
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:     "snapshot",
+	GroupID: GroupDiag,
+	Short:   "Capture a sanitized diagnostic archive for bug reports",
+	Long: `Capture the town's current state in one archive to attach to a bug report.
+
+The archive (gt-snapshot-<town>-<time>.tar.gz) contains:
+
+  manifest.json  When and by which gt it was taken, and what it contains
+  versions.json  gt, Go, OS, and tmux, git, bd, and cursor-agent versions
+  doctor.json    A read-only 'gt doctor --format json' run (nothing is fixed)
+  state.json     'gt state dump --fast': config, rigs, agents, sessions, and
+                 work queue (no mail or pane contents)
+  events.jsonl   The most recent events from .events.jsonl
+
+Secrets are redacted throughout: passwords, tokens, API keys, env and
+header values, and credentials in URLs. Secret references
+(secretRef:...) are kept, since they name where a secret lives, not its
+value. Parts that cannot be collected are listed in manifest.json instead of
+failing the snapshot. Review the archive before sharing it: it still names
+your town, rigs, agents, and paths.
+
+Examples:
+  gt snapshot                       # Write to the current directory
+  gt snapshot -o /tmp/town.tar.gz   # Choose the output file
+  gt snapshot --events 2000         # Include more event history`,
+	Args: cobra.NoArgs,
+	RunE: runSnapshot,
+}
+
+func init() {
+	snapshotCmd.Flags().StringVarP(&snapshotOut, "out", "o", "", "Output file (default gt-snapshot-<town>-<time>.tar.gz)")
+	snapshotCmd.Flags().IntVar(&snapshotEvents, "events", 500, "Most recent events to include (0 for none)")
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+// SnapshotManifest describes a snapshot archive.
+type SnapshotManifest struct {
+	SchemaVersion int       `json:"schema_version"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	GTVersion     string    `json:"gt_version"`
+	TownName      string    `json:"town_name,omitempty"`
+	Files         []string  `json:"files"`
+	Errors        []string  `json:"errors,omitempty"`
+}
+
+// SnapshotVersions are the versions of gt and the tools it drives.
+type SnapshotVersions struct {
+	GT     SnapshotGTVersion `json:"gt"`
+	Go     string            `json:"go"`
+	OS     string            `json:"os"`
+	Arch   string            `json:"arch"`
+	Tools  map[string]string `json:"tools"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// SnapshotGTVersion identifies the gt binary that took a snapshot.
+type SnapshotGTVersion struct {
+	Version string `json:"version"`
+	Build   string `json:"build"`
+	Commit  string `json:"commit,omitempty"`
+	Branch  string `json:"branch,omitempty"`
+}
+
+// snapshotFile is one file in a snapshot archive.
+type snapshotFile struct {
+	name string
+	data []byte
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	now := time.Now().UTC()
+	townName, _ := workspace.GetTownName(townRoot)
+	out := snapshotOut
+	if out == "" {
+		label := townName
+		if label == "" {
+			label = filepath.Base(townRoot)
+		}
+		out = fmt.Sprintf("gt-snapshot-%s-%s.tar.gz", label, now.Format("20060102-150405"))
+	}
+
+	manifest := SnapshotManifest{
+		SchemaVersion: SnapshotSchemaVersion,
+		GeneratedAt:   now,
+		GTVersion:     Version,
+		TownName:      townName,
+	}
+	addErr := func(part string, err error) {
+		manifest.Errors = append(manifest.Errors, fmt.Sprintf("%s: %v", part, err))
+	}
+	var files []snapshotFile
+	addJSON := func(name string, v interface{}) {
+		data, err := redactedJSON(v)
+		if err != nil {
+			addErr(name, err)
+			return
+		}
+		files = append(files, snapshotFile{name: name, data: data})
+	}
+
+	fmt.Fprintf(os.Stderr, "%s Collecting versions...\n", style.Bold.Render("→"))
+	addJSON("versions.json", collectSnapshotVersions())
+
+	fmt.Fprintf(os.Stderr, "%s Running doctor checks...\n", style.Bold.Render("→"))
+	var report bytes.Buffer
+	if err := snapshotDoctorReport(&report, townRoot); err != nil {
+		addErr("doctor.json", err)
+	} else {
+		var doc interface{}
+		if err := json.Unmarshal(report.Bytes(), &doc); err != nil {
+			addErr("doctor.json", err)
+		} else {
+			addJSON("doctor.json", doc)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%s Collecting town state...\n", style.Bold.Render("→"))
+	if dump, err := buildStateDump(townRoot, true); err != nil {
+		addErr("state.json", err)
+	} else {
+		addJSON("state.json", dump)
+	}
+
+	if snapshotEvents > 0 {
+		data, err := snapshotEventsLog(townRoot, snapshotEvents)
+		if err != nil {
+			addErr("events.jsonl", err)
+		} else {
+			files = append(files, snapshotFile{name: "events.jsonl", data: data})
+		}
+	}
+
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.name)
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	files = append([]snapshotFile{{name: "manifest.json", data: append(manifestData, '\n')}}, files...)
+
+	f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) //nolint:gosec // G304: output path is chosen by the user
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", out, err)
+	}
+	dir := strings.TrimSuffix(filepath.Base(out), ".tar.gz")
+	if err := writeSnapshotArchive(f, dir, now, files); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %s: %w", out, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", out, err)
+	}
+
+	fmt.Printf("%s Wrote snapshot to %s\n", style.SuccessPrefix, out)
+	for _, e := range manifest.Errors {
+		fmt.Printf("  %s %s\n", style.WarningPrefix, e)
+	}
+	fmt.Println(style.Dim.Render("  Secrets are redacted, but review it before sharing: it names your town, rigs, agents, and paths."))
+	return nil
+}
+
+// collectSnapshotVersions returns the versions of gt and the tools it runs.
+func collectSnapshotVersions() SnapshotVersions {
+	v := SnapshotVersions{
+		GT: SnapshotGTVersion{
+			Version: Version,
+			Build:   Build,
+			Commit:  resolveCommitHash(),
+			Branch:  resolveBranch(),
+		},
+		Go:    runtime.Version(),
+		OS:    runtime.GOOS,
+		Arch:  runtime.GOARCH,
+		Tools: make(map[string]string),
+	}
+	for _, tool := range [][]string{
+		{"tmux", "-V"},
+		{"git", "--version"},
+		{"bd", "--version"},
+		{"cursor-agent", "--version"},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), snapshotToolTimeout)
+		out, err := exec.CommandContext(ctx, tool[0], tool[1:]...).Output() //nolint:gosec // G204: fixed tool names
+		cancel()
+		if err != nil {
+			if v.Errors == nil {
+				v.Errors = make(map[string]string)
+			}
+			v.Errors[tool[0]] = err.Error()
+			continue
+		}
+		line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+		v.Tools[tool[0]] = line
+	}
+	return v
+}
+
+// snapshotDoctorReport runs every town check without fixing anything, and
+// without recording the run in the events log or result cache.
+func snapshotDoctorReport(w io.Writer, townRoot string) error {
+	ctx := &doctor.CheckContext{
+		TownRoot:   townRoot,
+		GTVersion:  Version,
+		OtherTowns: otherTownRoots(townRoot),
+	}
+	d := newTownDoctor(townRoot, "")
+	d.SetTimeout(doctor.DefaultCheckTimeout)
+	return d.Run(ctx).WriteJSON(w, townRoot)
+}
+
+// snapshotEventsLog returns the last n events of the town's events log as
+// JSON lines, redacted.
+func snapshotEventsLog(townRoot string, n int) ([]byte, error) {
+	evs, err := events.ReadEvents(filepath.Join(townRoot, events.EventsFile), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(evs) > n {
+		evs = evs[len(evs)-n:]
+	}
+	var buf bytes.Buffer
+	for _, e := range evs {
+		generic, err := toGenericJSON(e)
+		if err != nil {
+			return nil, err
+		}
+		if err := encodeSnapshotJSON(&buf, redactSnapshot(generic), ""); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeSnapshotArchive writes files as a gzipped tar under dir/.
+func writeSnapshotArchive(w io.Writer, dir string, modTime time.Time, files []snapshotFile) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    dir + "/" + f.name,
+			Mode:    0600,
+			Size:    int64(len(f.data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// redactedJSON returns v as indented JSON with secrets redacted (see
+// redactSnapshot).
+func redactedJSON(v interface{}) ([]byte, error) {
+	generic, err := toGenericJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := encodeSnapshotJSON(&buf, redactSnapshot(generic), "  "); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeSnapshotJSON writes v as one line of JSON (or indented), keeping
+// "<redacted>" readable instead of HTML-escaping it.
+func encodeSnapshotJSON(w io.Writer, v interface{}, indent string) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	return enc.Encode(v)
+}
+
+// toGenericJSON converts v to maps, slices, and scalars via JSON.
+func toGenericJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// snapshotSecretKeys are substrings of keys whose values are redacted from
+// snapshots, matched against the lowercased key without "_" and "-".
+var snapshotSecretKeys = []string{"password", "passwd", "secret", "token", "apikey", "credential", "privatekey", "authorization", "cookie"}
+
+// snapshotRedactAllKeys are keys whose nested values are all redacted:
+// maps of environment variables and HTTP headers.
+var snapshotRedactAllKeys = map[string]bool{"env": true, "headers": true}
+
+// urlUserinfoRe matches the credentials in a URL ("https://user:pass@").
+var urlUserinfoRe = regexp.MustCompile(`(://)[^/@\s"]+@`)
+
+// redactSnapshot redacts secrets in a generic JSON value: string values of
+// secret-looking keys, every value under env and headers, and credentials
+// embedded in URLs. Secret references and ${env:...} placeholders are kept.
+func redactSnapshot(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if snapshotRedactAllKeys[strings.ToLower(k)] || isSnapshotSecretKey(k) {
+				v[k] = redactSnapshotValues(val)
+			} else {
+				v[k] = redactSnapshot(val)
+			}
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactSnapshot(v[i])
+		}
+		return v
+	case string:
+		return urlUserinfoRe.ReplaceAllString(v, "${1}"+redactedValue+"@")
+	default:
+		return v
+	}
+}
+
+// redactSnapshotValues replaces every string in v that is not a secret
+// reference or placeholder. Numbers and booleans (e.g. token budgets) are
+// kept.
+func redactSnapshotValues(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			v[k] = redactSnapshotValues(val)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactSnapshotValues(v[i])
+		}
+		return v
+	case string:
+		if v == "" || secrets.IsRef(v) || (strings.HasPrefix(v, "${") && strings.HasSuffix(v, "}")) {
+			return v
+		}
+		return redactedValue
+	default:
+		return v
+	}
+}
+
+func isSnapshotSecretKey(key string) bool {
+	k := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, s := range snapshotSecretKeys {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/cursorworkshop/cursor-gastown/internal/git"
 	"github.com/cursorworkshop/cursor-gastown/internal/mayor"
 	"github.com/cursorworkshop/cursor-gastown/internal/polecat"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
+	"github.com/cursorworkshop/cursor-gastown/internal/refinery"
 	"github.com/cursorworkshop/cursor-gastown/internal/rig"
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/style"
@@ -350,6 +352,15 @@ func ensureRefinerySession(rigName string, r *rig.Rig) (bool, error) {
 		return false, fmt.Errorf("ensuring Cursor settings: %w", err)
 	}
 
+	if err := preflight.BeforeSpawn(preflight.Spec{
+		Session:     sessionName,
+		Role:        session.RoleRefinery,
+		RigPath:     r.Path,
+		SettingsDir: refineryParentDir,
+	}); err != nil {
+		return false, err
+	}
+
 	// Create new tmux session
 	if err := t.NewSession(sessionName, refineryRigDir); err != nil {
 		return false, fmt.Errorf("creating session: %w", err)
@@ -395,7 +406,7 @@ func ensureRefinerySession(rigName string, r *rig.Rig) (bool, error) {
 	// Send the propulsion nudge to trigger autonomous patrol execution.
 	// Wait for beacon to be fully processed (needs to be separate prompt)
 	time.Sleep(2 * time.Second)
-	_ = t.NudgeSession(sessionName, session.PropulsionNudgeForRole("refinery", refineryRigDir)) // Non-fatal
+	_ = t.NudgeSession(sessionName, session.PropulsionNudgeWithState("refinery", refineryRigDir, refinery.NewManager(r).StartupState())) // Non-fatal
 
 	return true, nil
 }
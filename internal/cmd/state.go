@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/alerts"
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+	"github.com/cursorworkshop/cursor-gastown/internal/secrets"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// StateDumpSchemaVersion is the schema version of 'gt state dump' output.
+// It is bumped only for breaking changes (removed or renamed fields, changed
+// types); new fields may be added without a bump.
+const StateDumpSchemaVersion = 1
+
+// redactedValue replaces literal settings env values in the dump.
+const redactedValue = "<redacted>"
+
+var stateDumpFast bool
+
+var stateCmd = &cobra.Command{
+	Use:     "state",
+	GroupID: GroupDiag,
+	Short:   "Export resolved town state",
+	RunE:    requireSubcommand,
+}
+
+var stateDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Print the entire resolved town state as one JSON document",
+	Long: `Print the resolved town state as a single versioned JSON document.
+
+This is the stable integration surface for dashboards and scripts: one
+call instead of scraping several commands. The document contains:
+
+  config      Town config, town/rig settings, and the rig registry
+  status      Rigs, agents, hooks, and merge queues (as 'gt status --json')
+  sessions    Gas Town tmux sessions with metadata
+  work_queue  Ready work per rig
+  budgets     Cost budgets, current spend, and active cost alerts
+  health      Daemon and bd daemon health
+
+"schema_version" changes only on breaking changes; new fields may appear
+at any time. Literal settings env values are redacted (secret references
+are shown as configured). Parts that cannot be collected are listed in
+"errors" rather than failing the dump.
+
+Examples:
+  gt state dump | jq '.status.summary'
+  gt state dump --fast > state.json`,
+	Args: cobra.NoArgs,
+	RunE: runStateDump,
+}
+
+func init() {
+	stateDumpCmd.Flags().BoolVar(&stateDumpFast, "fast", false, "Skip mail lookups and pane captures for faster execution")
+	stateCmd.AddCommand(stateDumpCmd)
+	rootCmd.AddCommand(stateCmd)
+}
+
+// StateDump is the document emitted by 'gt state dump'.
+type StateDump struct {
+	SchemaVersion int             `json:"schema_version"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+	GTVersion     string          `json:"gt_version"`
+	TownRoot      string          `json:"town_root"`
+	Config        StateConfig     `json:"config"`
+	Status        TownStatus      `json:"status"`
+	Sessions      []StateSession  `json:"sessions"`
+	WorkQueue     []StateWorkItem `json:"work_queue"`
+	Budgets       StateBudgets    `json:"budgets"`
+	Health        StateHealth     `json:"health"`
+	Errors        []string        `json:"errors,omitempty"`
+}
+
+// StateConfig is the resolved town configuration.
+type StateConfig struct {
+	Town        *config.TownConfig             `json:"town,omitempty"`
+	Settings    *config.TownSettings           `json:"settings,omitempty"`
+	Rigs        *config.RigsConfig             `json:"rigs,omitempty"`
+	RigSettings map[string]*config.RigSettings `json:"rig_settings,omitempty"`
+}
+
+// StateSession is a Gas Town tmux session.
+type StateSession struct {
+	Name         string `json:"name"`
+	Windows      int    `json:"windows"`
+	Created      string `json:"created,omitempty"`
+	Attached     bool   `json:"attached"`
+	Activity     string `json:"activity,omitempty"`
+	LastAttached string `json:"last_attached,omitempty"`
+}
+
+// StateWorkItem is a ready (unblocked, open) issue in a rig.
+type StateWorkItem struct {
+	Rig      string `json:"rig"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Type     string `json:"type,omitempty"`
+	Priority int    `json:"priority"`
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// StateBudgets is the cost budget configuration and current spend.
+type StateBudgets struct {
+	Config  *config.CostAlertsConfig `json:"config,omitempty"`
+	Metrics []StateBudgetMetric      `json:"metrics,omitempty"`
+	Alerts  []*alerts.Alert          `json:"active_alerts,omitempty"`
+}
+
+// StateBudgetMetric is one budgeted cost value.
+type StateBudgetMetric struct {
+	ID      string  `json:"id"`
+	Summary string  `json:"summary"`
+	Value   float64 `json:"value"`
+	Budget  float64 `json:"budget"`
+}
+
+// StateHealth summarizes background service health.
+type StateHealth struct {
+	Daemon    StateDaemon `json:"daemon"`
+	BdWarning string      `json:"bd_warning,omitempty"`
+}
+
+// StateDaemon is the Gas Town daemon's state.
+type StateDaemon struct {
+	Running        bool      `json:"running"`
+	PID            int       `json:"pid,omitempty"`
+	Version        string    `json:"version,omitempty"`
+	StartedAt      time.Time `json:"started_at,omitempty"`
+	LastHeartbeat  time.Time `json:"last_heartbeat,omitempty"`
+	HeartbeatStale bool      `json:"heartbeat_stale"`
+}
+
+func runStateDump(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	dump, err := buildStateDump(townRoot, stateDumpFast)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(dump)
+}
+
+// buildStateDump collects the full town state. Failures in optional
+// sections are recorded in Errors instead of aborting the dump.
+func buildStateDump(townRoot string, fast bool) (*StateDump, error) {
+	dump := &StateDump{
+		SchemaVersion: StateDumpSchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		GTVersion:     Version,
+		TownRoot:      townRoot,
+		Sessions:      []StateSession{},
+		WorkQueue:     []StateWorkItem{},
+	}
+	addErr := func(section string, err error) {
+		dump.Errors = append(dump.Errors, fmt.Sprintf("%s: %v", section, err))
+	}
+
+	// Config
+	if town, err := config.LoadTownConfig(constants.MayorTownPath(townRoot)); err == nil {
+		dump.Config.Town = town
+	} else {
+		addErr("config.town", err)
+	}
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		settings.Env = redactEnv(settings.Env)
+		dump.Config.Settings = settings
+	} else {
+		addErr("config.settings", err)
+	}
+	if rigs, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		dump.Config.Rigs = rigs
+		dump.Config.RigSettings = make(map[string]*config.RigSettings)
+		for name := range rigs.Rigs {
+			rs, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, name)))
+			if err != nil {
+				continue // rigs without settings use town defaults
+			}
+			rs.Env = redactEnv(rs.Env)
+			dump.Config.RigSettings[name] = rs
+		}
+	} else {
+		addErr("config.rigs", err)
+	}
+
+	// Status (rigs, agents, hooks, merge queues)
+	status, bdWarning, err := gatherTownStatus(townRoot, fast)
+	if err != nil {
+		return nil, err
+	}
+	dump.Status = status
+	dump.Health.BdWarning = bdWarning
+
+	// Sessions
+	t := tmux.NewTmux()
+	if names, err := t.ListSessions(); err == nil {
+		sort.Strings(names)
+		for _, name := range names {
+			if !strings.HasPrefix(name, session.Prefix) && !strings.HasPrefix(name, session.HQPrefix) {
+				continue
+			}
+			s := StateSession{Name: name}
+			if info, err := t.GetSessionInfo(name); err == nil {
+				s.Windows = info.Windows
+				s.Created = info.Created
+				s.Attached = info.Attached
+				s.Activity = info.Activity
+				s.LastAttached = info.LastAttached
+			}
+			dump.Sessions = append(dump.Sessions, s)
+		}
+	} else {
+		addErr("sessions", err)
+	}
+
+	// Work queue
+	for _, rs := range status.Rigs {
+		ready, err := beads.New(filepath.Join(townRoot, rs.Name, "mayor", "rig")).Ready()
+		if err != nil {
+			addErr("work_queue."+rs.Name, err)
+			continue
+		}
+		for _, issue := range ready {
+			dump.WorkQueue = append(dump.WorkQueue, StateWorkItem{
+				Rig:      rs.Name,
+				ID:       issue.ID,
+				Title:    issue.Title,
+				Type:     issue.Type,
+				Priority: issue.Priority,
+				Assignee: issue.Assignee,
+			})
+		}
+	}
+
+	// Budgets
+	if dump.Config.Settings != nil && dump.Config.Settings.CostAlerts != nil {
+		cfg := dump.Config.Settings.CostAlerts
+		dump.Budgets.Config = cfg
+		if !fast {
+			metrics, _, err := collectCostMetrics(cfg)
+			if err != nil {
+				addErr("budgets", err)
+			}
+			for _, m := range metrics {
+				dump.Budgets.Metrics = append(dump.Budgets.Metrics, StateBudgetMetric{
+					ID: m.id, Summary: m.summary, Value: m.value, Budget: m.budget,
+				})
+			}
+		}
+	}
+	if state, err := alerts.Load(townRoot); err == nil {
+		for _, a := range state.List() {
+			if a.Active() {
+				dump.Budgets.Alerts = append(dump.Budgets.Alerts, a)
+			}
+		}
+	} else {
+		addErr("budgets.alerts", err)
+	}
+
+	// Health
+	running, pid, _ := daemon.IsRunning(townRoot)
+	dump.Health.Daemon.Running = running
+	if running {
+		dump.Health.Daemon.PID = pid
+		if state, err := daemon.LoadState(townRoot); err == nil {
+			dump.Health.Daemon.Version = state.Version
+			dump.Health.Daemon.StartedAt = state.StartedAt
+			dump.Health.Daemon.LastHeartbeat = state.LastHeartbeat
+			dump.Health.Daemon.HeartbeatStale = state.HeartbeatStale(time.Now())
+		}
+	}
+
+	return dump, nil
+}
+
+// redactEnv hides literal settings env values, which may be credentials.
+// Secret references are kept: they name where a secret lives, not its value.
+func redactEnv(env map[string]string) map[string]string {
+	if len(env) == 0 {
+		return env
+	}
+	redacted := make(map[string]string, len(env))
+	for k, v := range env {
+		if secrets.IsRef(v) {
+			redacted[k] = v
+		} else {
+			redacted[k] = redactedValue
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import "testing"
+
+func TestRedactEnv(t *testing.T) {
+	got := redactEnv(map[string]string{
+		"ANTHROPIC_API_KEY": "secretRef:op:op://Dev/Anthropic/credential",
+		"OPENAI_API_KEY":    "sk-plaintext",
+	})
+	if got["ANTHROPIC_API_KEY"] != "secretRef:op:op://Dev/Anthropic/credential" {
+		t.Errorf("secret reference should be kept, got %q", got["ANTHROPIC_API_KEY"])
+	}
+	if got["OPENAI_API_KEY"] != redactedValue {
+		t.Errorf("literal value should be redacted, got %q", got["OPENAI_API_KEY"])
+	}
+	if redactEnv(nil) != nil {
+		t.Error("redactEnv(nil) should be nil")
+	}
+}
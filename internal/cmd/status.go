@@ -62,6 +62,9 @@ type TownStatus struct {
 	Agents   []AgentRuntime `json:"agents"`             // Global agents (Mayor, Deacon)
 	Rigs     []RigStatus    `json:"rigs"`
 	Summary  StatusSum      `json:"summary"`
+	// TmuxError is set when tmux could not be queried; agent running
+	// states are then unknown rather than stopped.
+	TmuxError string `json:"tmux_error,omitempty"`
 }
 
 // OverseerInfo represents the human operator's identity and status.
@@ -80,6 +83,7 @@ type AgentRuntime struct {
 	Session      string `json:"session"`                 // tmux session name
 	Role         string `json:"role"`                    // Role type
 	Running      bool   `json:"running"`                 // Is tmux session running?
+	Unknown      bool   `json:"unknown,omitempty"`       // tmux unreachable, Running is not known
 	HasWork      bool   `json:"has_work"`                // Has pinned work?
 	WorkTitle    string `json:"work_title,omitempty"`    // Title of pinned work
 	HookBead     string `json:"hook_bead,omitempty"`     // Pinned bead ID from agent bead
@@ -189,6 +193,32 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	status, bdWarning, err := gatherTownStatus(townRoot, statusFast)
+	if err != nil {
+		return err
+	}
+
+	// Output
+	if statusJSON {
+		return outputStatusJSON(status)
+	}
+	if err := outputStatusText(status); err != nil {
+		return err
+	}
+
+	// Show bd daemon warning at the end if there were issues
+	if bdWarning != "" {
+		fmt.Printf("%s %s\n", style.Warning.Render("WARN"), bdWarning)
+		fmt.Printf("  Run 'bd daemon killall && bd daemon --start' to restart daemons\n")
+	}
+
+	return nil
+}
+
+// gatherTownStatus collects the runtime status of a town: global agents,
+// rigs, their agents and hooks, and merge queues. With fast set, mail
+// lookups are skipped. Also returns any bd daemon health warning.
+func gatherTownStatus(townRoot string, fast bool) (TownStatus, string, error) {
 	// Check bd daemon health and attempt restart if needed
 	// This is non-blocking - if daemons can't be started, we show a warning but continue
 	bdWarning := beads.EnsureBdDaemonHealth(townRoot)
@@ -218,16 +248,15 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 
 	// Pre-fetch all tmux sessions for O(1) lookup
 	allSessions := make(map[string]bool)
-	if sessions, err := t.ListSessions(); err == nil {
-		for _, s := range sessions {
-			allSessions[s] = true
-		}
+	sessions, tmuxErr := t.ListSessions()
+	for _, s := range sessions {
+		allSessions[s] = true
 	}
 
 	// Discover rigs
 	rigs, err := mgr.DiscoverRigs()
 	if err != nil {
-		return fmt.Errorf("discovering rigs: %w", err)
+		return TownStatus{}, "", fmt.Errorf("discovering rigs: %w", err)
 	}
 
 	// Pre-fetch agent beads across all rig-specific beads DBs.
@@ -332,7 +361,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		status.Agents = discoverGlobalAgents(allSessions, allAgentBeads, allHookBeads, mailRouter, statusFast)
+		status.Agents = discoverGlobalAgents(allSessions, allAgentBeads, allHookBeads, mailRouter, fast)
 	}()
 
 	// Process all rigs in parallel
@@ -371,7 +400,7 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 			rigActiveHooks[idx] = activeHooks
 
 			// Discover runtime state for all agents in this rig
-			rs.Agents = discoverRigAgents(allSessions, r, rs.Crews, allAgentBeads, allHookBeads, mailRouter, statusFast)
+			rs.Agents = discoverRigAgents(allSessions, r, rs.Crews, allAgentBeads, allHookBeads, mailRouter, fast)
 
 			// Get MQ summary if rig has a refinery
 			rs.MQ = getMQSummary(r)
@@ -396,21 +425,26 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 	}
 	status.Summary.RigCount = len(rigs)
 
-	// Output
-	if statusJSON {
-		return outputStatusJSON(status)
-	}
-	if err := outputStatusText(status); err != nil {
-		return err
+	if tmuxErr != nil {
+		markSessionsUnknown(&status, tmuxErr)
 	}
 
-	// Show bd daemon warning at the end if there were issues
-	if bdWarning != "" {
-		fmt.Printf("%s %s\n", style.Warning.Render("WARN"), bdWarning)
-		fmt.Printf("  Run 'bd daemon killall && bd daemon --start' to restart daemons\n")
-	}
+	return status, bdWarning, nil
+}
 
-	return nil
+// markSessionsUnknown records that tmux could not be queried, so no agent's
+// session state is known.
+func markSessionsUnknown(status *TownStatus, err error) {
+	status.TmuxError = err.Error()
+	mark := func(agents []AgentRuntime) {
+		for i := range agents {
+			agents[i].Unknown = true
+		}
+	}
+	mark(status.Agents)
+	for i := range status.Rigs {
+		mark(status.Rigs[i].Agents)
+	}
 }
 
 func outputStatusJSON(status TownStatus) error {
@@ -424,6 +458,11 @@ func outputStatusText(status TownStatus) error {
 	fmt.Printf("%s %s\n", style.Bold.Render("Town:"), status.Name)
 	fmt.Printf("%s\n\n", style.Dim.Render(status.Location))
 
+	if status.TmuxError != "" {
+		fmt.Printf("%s tmux unavailable: %s\n", style.WarningPrefix, status.TmuxError)
+		fmt.Printf("    %s\n\n", style.Dim.Render("Agent sessions are shown as unknown. Run 'gt doctor' for details."))
+	}
+
 	// Overseer info
 	if status.Overseer != nil {
 		overseerDisplay := status.Overseer.Name
@@ -593,7 +632,9 @@ func renderAgentDetails(agent AgentRuntime, indent string, hooks []AgentHookInfo
 	var statusStr string
 	var stateInfo string
 
-	if sessionExists {
+	if agent.Unknown {
+		statusStr = style.Warning.Render("unknown")
+	} else if sessionExists {
 		statusStr = style.Success.Render("running")
 	} else {
 		statusStr = style.Error.Render("stopped")
@@ -821,7 +862,9 @@ func buildStatusIndicator(agent AgentRuntime) string {
 
 	// Base indicator from tmux state
 	var indicator string
-	if sessionExists {
+	if agent.Unknown {
+		indicator = style.Warning.Render("?")
+	} else if sessionExists {
 		indicator = style.Success.Render("●")
 	} else {
 		indicator = style.Error.Render("○")
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
@@ -96,6 +97,24 @@ func TestRenderAgentDetails_UsesRigPrefix(t *testing.T) {
 	}
 }
 
+func TestMarkSessionsUnknown(t *testing.T) {
+	status := TownStatus{
+		Agents: []AgentRuntime{{Name: "mayor"}},
+		Rigs:   []RigStatus{{Name: "beads", Agents: []AgentRuntime{{Name: "witness"}}}},
+	}
+	markSessionsUnknown(&status, errors.New("tmux is not installed"))
+
+	if status.TmuxError != "tmux is not installed" {
+		t.Errorf("TmuxError = %q", status.TmuxError)
+	}
+	if !status.Agents[0].Unknown || !status.Rigs[0].Agents[0].Unknown {
+		t.Error("all agents should be marked unknown")
+	}
+	if !strings.Contains(buildStatusIndicator(status.Agents[0]), "?") {
+		t.Error("unknown agents should show a ? indicator")
+	}
+}
+
 func TestRunStatusWatch_RejectsZeroInterval(t *testing.T) {
 	oldInterval := statusInterval
 	oldWatch := statusWatch
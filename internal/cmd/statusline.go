@@ -176,8 +176,10 @@ func runMayorStatusLine(t *tmux.Tmux) error {
 	if townRoot != "" {
 		rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
 		if rigsConfig, err := config.LoadRigsConfig(rigsConfigPath); err == nil {
-			for rigName := range rigsConfig.Rigs {
-				registeredRigs[rigName] = true
+			for rigName, entry := range rigsConfig.Rigs {
+				if !entry.Mirror {
+					registeredRigs[rigName] = true
+				}
 			}
 		}
 	}
@@ -298,8 +300,10 @@ func runDeaconStatusLine(t *tmux.Tmux) error {
 	if townRoot != "" {
 		rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
 		if rigsConfig, err := config.LoadRigsConfig(rigsConfigPath); err == nil {
-			for rigName := range rigsConfig.Rigs {
-				registeredRigs[rigName] = true
+			for rigName, entry := range rigsConfig.Rigs {
+				if !entry.Mirror {
+					registeredRigs[rigName] = true
+				}
 			}
 		}
 	}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates/selftest"
+	"github.com/spf13/cobra"
+)
+
+// Templates command flags
+var (
+	templatesSelftestJSON    bool
+	templatesSelftestVerbose bool
+	templatesSelftestOut     string
+)
+
+var templatesCmd = &cobra.Command{
+	Use:     "templates",
+	GroupID: GroupDiag,
+	Short:   "Check the templates gt generates agent config from",
+	RunE:    requireSubcommand,
+}
+
+var templatesSelftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Render every template for every role, agent, and OS, and validate it",
+	Long: `Render the embedded agent config templates (Cursor rules and
+hooks.json; GEMINI.md, Gemini settings.json and hook scripts;
+AGENTS.md, Codex config.toml and notify program; the Auggie rule) and the
+role context templates for every role, agent, and OS, using synthetic
+workspaces, and validate the output:
+
+  - nothing renders as <no value> and no file is empty
+  - JSON and TOML files parse, and every hook script they run is generated
+  - rules files have frontmatter Cursor can read
+  - hook scripts start with a bash shebang and pass 'bash -n'
+
+Town template overrides are not used, so this checks what gt itself ships.
+Exits 1 if any combination fails. Use --out to write the rendered files
+for inspection.
+
+Examples:
+  gt templates selftest
+  gt templates selftest --verbose
+  gt templates selftest --out /tmp/rendered`,
+	Args: cobra.NoArgs,
+	RunE: runTemplatesSelftest,
+}
+
+func init() {
+	templatesSelftestCmd.Flags().BoolVar(&templatesSelftestJSON, "json", false, "Output results as JSON")
+	templatesSelftestCmd.Flags().BoolVarP(&templatesSelftestVerbose, "verbose", "v", false, "List every combination, not just failures")
+	templatesSelftestCmd.Flags().StringVar(&templatesSelftestOut, "out", "", "Write rendered files to <dir>/<os>/<agent>/<role>/")
+
+	templatesCmd.AddCommand(templatesSelftestCmd)
+	rootCmd.AddCommand(templatesCmd)
+}
+
+// templatesSelftestResult is the JSON form of a selftest result.
+type templatesSelftestResult struct {
+	OS     string   `json:"os"`
+	Agent  string   `json:"agent"`
+	Role   string   `json:"role"`
+	Files  []string `json:"files"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+func runTemplatesSelftest(cmd *cobra.Command, args []string) error {
+	fixtures, err := selftest.LoadFixtures()
+	if err != nil {
+		return err
+	}
+	results := fixtures.Run()
+
+	failed := 0
+	files := 0
+	for _, r := range results {
+		files += len(r.Files)
+		if !r.OK() {
+			failed++
+		}
+		if templatesSelftestOut != "" {
+			dir := filepath.Join(templatesSelftestOut, r.Platform.OS, r.Agent, r.Role)
+			for _, f := range r.Files {
+				path := filepath.Join(dir, filepath.FromSlash(f.Name))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					return err
+				}
+				if err := os.WriteFile(path, f.Content, 0644); err != nil { //nolint:gosec // G306: rendered templates are not sensitive
+					return err
+				}
+			}
+		}
+	}
+
+	if templatesSelftestJSON {
+		out := make([]templatesSelftestResult, 0, len(results))
+		for _, r := range results {
+			jr := templatesSelftestResult{OS: r.Platform.OS, Agent: r.Agent, Role: r.Role, Files: []string{}, Errors: r.Errors}
+			for _, f := range r.Files {
+				jr.Files = append(jr.Files, f.Name)
+			}
+			out = append(out, jr)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			if r.OK() {
+				if templatesSelftestVerbose {
+					fmt.Printf("%s %s %s\n", style.SuccessPrefix, r, style.Dim.Render(fmt.Sprintf("(%d files)", len(r.Files))))
+				}
+				continue
+			}
+			fmt.Printf("%s %s\n", style.ErrorPrefix, r)
+			for _, problem := range r.Errors {
+				fmt.Printf("    %s\n", problem)
+			}
+		}
+		if failed == 0 {
+			fmt.Printf("%s Rendered %d files for %d combinations; all valid\n", style.SuccessPrefix, files, len(results))
+		} else {
+			fmt.Printf("%s %d of %d combinations failed\n", style.ErrorPrefix, failed, len(results))
+		}
+		if templatesSelftestOut != "" {
+			fmt.Printf("Rendered files written to %s\n", templatesSelftestOut)
+		}
+	}
+
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
@@ -0,0 +1,349 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/git"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/testledger"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// defaultGreenWindow is how recent a green run must be for 'gt tests gate'
+// when neither --within nor merge_queue.require_green_run is set.
+const defaultGreenWindow = 24 * time.Hour
+
+var (
+	testsRig string
+
+	testsRecordSuite    string
+	testsRecordResult   string
+	testsRecordDuration time.Duration
+	testsRecordRetries  int
+	testsRecordFailed   []string
+
+	testsHistorySince  string
+	testsHistorySuite  string
+	testsHistoryBranch string
+	testsHistoryLimit  int
+	testsHistoryJSON   bool
+
+	testsGateWithin time.Duration
+)
+
+var testsCmd = &cobra.Command{
+	Use:     "tests",
+	GroupID: GroupWork,
+	Short:   "Record and review test runs in the rig's shared test ledger",
+	RunE:    requireSubcommand,
+	Long: `Record test run outcomes in a ledger shared by every agent in a rig.
+
+Each run records its suite, pass/fail, duration, flaky retries, and the
+branch and commit it tested, as one JSON line in <rig>/.runtime/test-runs.jsonl.
+The witness adds flaky runs to its patrol reports, and the refinery can
+require a recent green run before merging a branch
+(merge_queue.require_green_run).`,
+}
+
+var testsRecordCmd = &cobra.Command{
+	Use:   "record [-- <command>...]",
+	Short: "Record a test run (or run a test command and record it)",
+	Long: `Record a test run in the rig's test ledger.
+
+With a command after --, runs it from the current directory, retrying up to
+--retries times on failure, and records the outcome and duration. A command
+that passes after a retry is recorded as flaky. Without a command, records
+a run you made yourself: pass --result.
+
+The branch and commit are taken from the current directory's git checkout.
+
+Examples:
+  gt tests record --suite unit -- go test ./...
+  gt tests record --suite integration --retries 2 -- make integration
+  gt tests record --suite e2e --result fail --duration 4m --failed-test TestLogin`,
+	RunE: runTestsRecord,
+}
+
+var testsHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show a rig's test runs aggregated per suite",
+	Long: `Show a rig's recorded test runs: per-suite pass rates, flaky counts and
+average durations, the tests that passed only on retry, and recent runs.
+
+If --rig is not specified, infers it from the current directory.
+
+Examples:
+  gt tests history
+  gt tests history --rig greenplace --since 30d
+  gt tests history --branch polecat/Toast --json`,
+	Args: cobra.NoArgs,
+	RunE: runTestsHistory,
+}
+
+var testsGateCmd = &cobra.Command{
+	Use:   "gate <branch>",
+	Short: "Check a branch has a recent green test run (exit 1 if not)",
+	Long: `Check the rig's test ledger for a recent green run of a branch.
+
+Passes when the branch's latest recorded run passed within --within
+(default: merge_queue.require_green_run, or 24h). The refinery runs this
+before merging.
+
+Examples:
+  gt tests gate polecat/Toast
+  gt tests gate polecat/Toast --within 2h`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTestsGate,
+}
+
+func init() {
+	testsCmd.PersistentFlags().StringVar(&testsRig, "rig", "", "Rig whose ledger to use (inferred from cwd if not set)")
+
+	testsRecordCmd.Flags().StringVar(&testsRecordSuite, "suite", "default", "Test suite name (e.g. unit, integration)")
+	testsRecordCmd.Flags().StringVar(&testsRecordResult, "result", "", "Outcome of a run made without a command: pass or fail")
+	testsRecordCmd.Flags().DurationVar(&testsRecordDuration, "duration", 0, "How long the run took (without a command)")
+	testsRecordCmd.Flags().IntVar(&testsRecordRetries, "retries", 0, "Retries on failure (with a command), or failed attempts retried (without)")
+	testsRecordCmd.Flags().StringArrayVar(&testsRecordFailed, "failed-test", nil, "Test that failed on any attempt (repeatable)")
+
+	testsHistoryCmd.Flags().StringVar(&testsHistorySince, "since", "7d", "Only runs newer than this (e.g. 24h, 30d; empty for all)")
+	testsHistoryCmd.Flags().StringVar(&testsHistorySuite, "suite", "", "Only this suite")
+	testsHistoryCmd.Flags().StringVar(&testsHistoryBranch, "branch", "", "Only runs of this branch")
+	testsHistoryCmd.Flags().IntVarP(&testsHistoryLimit, "limit", "n", 10, "Recent runs to list (0 for none)")
+	testsHistoryCmd.Flags().BoolVar(&testsHistoryJSON, "json", false, "Output as JSON")
+
+	testsGateCmd.Flags().DurationVar(&testsGateWithin, "within", 0, "How recent the green run must be")
+
+	testsCmd.AddCommand(testsRecordCmd)
+	testsCmd.AddCommand(testsHistoryCmd)
+	testsCmd.AddCommand(testsGateCmd)
+	rootCmd.AddCommand(testsCmd)
+}
+
+// resolveTestsRig returns the rig named by --rig or containing the current
+// directory.
+func resolveTestsRig() (*rig.Rig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := testsRig
+	if rigName == "" {
+		if rigName, err = inferRigFromCwd(townRoot); err != nil {
+			return nil, fmt.Errorf("could not determine rig: %w\nUse --rig <rig>", err)
+		}
+	}
+	_, r, err := getRig(rigName)
+	return r, err
+}
+
+func runTestsRecord(cmd *cobra.Command, args []string) error {
+	r, err := resolveTestsRig()
+	if err != nil {
+		return err
+	}
+
+	run := testledger.Run{
+		Suite:       testsRecordSuite,
+		FailedTests: testsRecordFailed,
+		Actor:       detectSender(),
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		g := git.NewGit(cwd)
+		run.Branch, _ = g.CurrentBranch()
+		run.Commit, _ = g.Rev("HEAD")
+	}
+
+	var runErr error
+	if len(args) > 0 {
+		if testsRecordResult != "" {
+			return fmt.Errorf("--result is only for runs recorded without a command")
+		}
+		runErr = runRecordedTests(&run, args, testsRecordRetries)
+	} else {
+		switch testsRecordResult {
+		case "pass":
+			run.Passed = true
+		case "fail":
+		default:
+			return fmt.Errorf("--result must be pass or fail when no command is given")
+		}
+		run.DurationMS = testsRecordDuration.Milliseconds()
+		run.Retries = testsRecordRetries
+	}
+
+	if err := testledger.Record(r.Path, run); err != nil {
+		return err
+	}
+	status := style.Success.Render("[PASS]")
+	if !run.Passed {
+		status = style.Error.Render("[FAIL]")
+	} else if run.Flaky() {
+		status = style.Warning.Render("[FLAKY]")
+	}
+	fmt.Printf("%s Recorded %s run for %s\n", status, run.Suite, r.Name)
+	if runErr != nil {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// runRecordedTests runs a test command, retrying up to retries times on
+// failure, and fills in run's outcome. Returns the last error when every
+// attempt failed.
+func runRecordedTests(run *testledger.Run, args []string, retries int) error {
+	start := time.Now()
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			fmt.Printf("%s Retrying (attempt %d/%d)...\n", style.Dim.Render("[RETRY]"), attempt+1, retries+1)
+		}
+		c := exec.Command(args[0], args[1:]...) //nolint:gosec // G204: the caller's own test command
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err = c.Run(); err == nil {
+			run.Passed = true
+			run.Retries = attempt
+			break
+		}
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			// Could not start the command at all; retrying will not help
+			run.Retries = attempt
+			break
+		}
+		run.Retries = attempt
+	}
+	run.DurationMS = time.Since(start).Milliseconds()
+	return err
+}
+
+// testsHistoryOutput is the JSON output of 'gt tests history'.
+type testsHistoryOutput struct {
+	Rig        string                  `json:"rig"`
+	Suites     []testledger.SuiteStats `json:"suites"`
+	FlakyTests []string                `json:"flaky_tests,omitempty"`
+	Recent     []testledger.Run        `json:"recent,omitempty"`
+}
+
+func runTestsHistory(cmd *cobra.Command, args []string) error {
+	r, err := resolveTestsRig()
+	if err != nil {
+		return err
+	}
+	runs, err := testledger.Load(r.Path)
+	if err != nil {
+		return fmt.Errorf("reading test ledger: %w", err)
+	}
+
+	if testsHistorySince != "" {
+		d, err := parseDuration(testsHistorySince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		runs = testledger.Since(runs, time.Now().Add(-d))
+	}
+	var filtered []testledger.Run
+	for _, run := range runs {
+		if (testsHistorySuite == "" || run.Suite == testsHistorySuite) &&
+			(testsHistoryBranch == "" || run.Branch == testsHistoryBranch) {
+			filtered = append(filtered, run)
+		}
+	}
+
+	// Newest first, limited
+	var recent []testledger.Run
+	for i := len(filtered) - 1; i >= 0 && len(recent) < testsHistoryLimit; i-- {
+		recent = append(recent, filtered[i])
+	}
+
+	out := testsHistoryOutput{
+		Rig:        r.Name,
+		Suites:     testledger.History(filtered),
+		FlakyTests: testledger.FlakyTests(filtered),
+		Recent:     recent,
+	}
+	if testsHistoryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	fmt.Printf("%s Test runs: %s\n\n", style.Bold.Render("[tests]"), r.Name)
+	if len(filtered) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No test runs recorded (agents record them with 'gt tests record')"))
+		return nil
+	}
+
+	now := time.Now()
+	fmt.Printf("  %-16s  %4s  %5s  %5s  %8s  %s\n", "SUITE", "RUNS", "PASS%", "FLAKY", "AVG", "LAST")
+	for _, s := range out.Suites {
+		last := "pass"
+		if !s.Last.Passed {
+			last = style.Error.Render("fail")
+		}
+		avg := "-"
+		if s.AvgDurationMS > 0 {
+			avg = formatDuration(time.Duration(s.AvgDurationMS) * time.Millisecond)
+		}
+		fmt.Printf("  %-16s  %4d  %4.0f%%  %5d  %8s  %s %s\n",
+			s.Suite, s.Runs, s.PassRate()*100, s.Flaky, avg, last, style.Dim.Render(formatRelative(s.Last.Time, now)))
+	}
+
+	if len(out.FlakyTests) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Passed only on retry:"))
+		for _, test := range out.FlakyTests {
+			fmt.Printf("  • %s\n", test)
+		}
+	}
+
+	if len(recent) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Recent runs:"))
+		for _, run := range recent {
+			status := style.Success.Render("pass ")
+			if !run.Passed {
+				status = style.Error.Render("fail ")
+			} else if run.Flaky() {
+				status = style.Warning.Render("flaky")
+			}
+			fmt.Printf("  %s  %-16s  %-12s  %s  %s\n", status, run.Suite, formatRelative(run.Time, now), run.Branch, style.Dim.Render(run.Actor))
+		}
+	}
+	return nil
+}
+
+func runTestsGate(cmd *cobra.Command, args []string) error {
+	r, err := resolveTestsRig()
+	if err != nil {
+		return err
+	}
+	within := testsGateWithin
+	if within == 0 {
+		within = defaultGreenWindow
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path)); err == nil &&
+			settings.MergeQueue != nil && settings.MergeQueue.RequireGreenRun != "" {
+			if d, err := time.ParseDuration(settings.MergeQueue.RequireGreenRun); err == nil {
+				within = d
+			}
+		}
+	}
+
+	runs, err := testledger.Load(r.Path)
+	if err != nil {
+		return fmt.Errorf("reading test ledger: %w", err)
+	}
+	if err := testledger.RequireGreen(runs, args[0], within, time.Now()); err != nil {
+		fmt.Printf("%s %v\n", style.Error.Render("[BLOCKED]"), err)
+		return NewSilentExit(1)
+	}
+	fmt.Printf("%s %s has a green test run within %s\n", style.Success.Render("[OK]"), args[0], within)
+	return nil
+}
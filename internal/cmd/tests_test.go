@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/testledger"
+)
+
+func TestRunRecordedTests(t *testing.T) {
+	var run testledger.Run
+	if err := runRecordedTests(&run, []string{"true"}, 2); err != nil {
+		t.Fatal(err)
+	}
+	if !run.Passed || run.Retries != 0 || run.Flaky() {
+		t.Errorf("passing command: %+v", run)
+	}
+
+	run = testledger.Run{}
+	if err := runRecordedTests(&run, []string{"false"}, 1); err == nil {
+		t.Error("failing command should return an error")
+	}
+	if run.Passed || run.Retries != 1 {
+		t.Errorf("failing command with one retry: %+v", run)
+	}
+
+	// Fails once, then passes: flaky
+	marker := filepath.Join(t.TempDir(), "ran")
+	script := "if [ -f " + marker + " ]; then exit 0; fi; touch " + marker + "; exit 1"
+	run = testledger.Run{}
+	if err := runRecordedTests(&run, []string{"sh", "-c", script}, 2); err != nil {
+		t.Fatal(err)
+	}
+	if !run.Flaky() || run.Retries != 1 {
+		t.Errorf("command passing on retry: %+v", run)
+	}
+}
+
+func TestAppendLedgerFlaky(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gp")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := appendLedgerFlaky(townRoot, "gp", []string{"TestA"}); !slices.Equal(got, []string{"TestA"}) {
+		t.Errorf("without a ledger = %v, want the reported tests unchanged", got)
+	}
+
+	now := time.Now()
+	for _, run := range []testledger.Run{
+		{Time: now.Add(-time.Hour), Suite: "unit", Passed: true, Retries: 1, FailedTests: []string{"TestA", "TestSync"}},
+		{Time: now, Suite: "unit", Passed: false, FailedTests: []string{"TestBroken"}},
+	} {
+		if err := testledger.Record(rigPath, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"TestA", "TestSync"}
+	if got := appendLedgerFlaky(townRoot, "gp", []string{"TestA"}); !slices.Equal(got, want) {
+		t.Errorf("appendLedgerFlaky = %v, want %v", got, want)
+	}
+}
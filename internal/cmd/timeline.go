@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/townlog"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// Timeline command flags
+var (
+	timelineSince   string
+	timelineLimit   int
+	timelineJSON    bool
+	timelineNoCosts bool
+)
+
+var timelineCmd = &cobra.Command{
+	Use:     "timeline <agent>",
+	GroupID: GroupDiag,
+	Short:   "Show what one agent has been doing",
+	Long: `Show a chronological timeline of one agent's activity: sessions, hooks,
+mail, nudges, handoffs, merges, and recorded session costs.
+
+The agent is a tmux session name (gt-greenplace-witness) or an address
+(greenplace/witness, greenplace/polecats/Toast, mayor). Events the agent
+received, such as nudges and mail sent to it, are included and marked ←.
+
+Sources: the town events log (.events.jsonl), the town log (gt log), and
+session cost events in beads (skip with --no-costs).
+
+Examples:
+  gt timeline gt-greenplace-witness             # Last 24 hours
+  gt timeline greenplace/polecats/Toast --since 2h
+  gt timeline mayor --since 7d -n 100
+  gt timeline gt-greenplace-Toast --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTimeline,
+}
+
+func init() {
+	timelineCmd.Flags().StringVar(&timelineSince, "since", "24h", "Show activity since duration (e.g., 1h, 24h, 7d)")
+	timelineCmd.Flags().IntVarP(&timelineLimit, "limit", "n", 50, "Maximum number of entries to show (most recent)")
+	timelineCmd.Flags().BoolVar(&timelineJSON, "json", false, "Output as JSON")
+	timelineCmd.Flags().BoolVar(&timelineNoCosts, "no-costs", false, "Skip session costs (avoids querying beads)")
+
+	rootCmd.AddCommand(timelineCmd)
+}
+
+// TimelineEntry is one item in an agent's timeline.
+type TimelineEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"` // "events", "townlog", "costs"
+	Type      string    `json:"type"`
+	Summary   string    `json:"summary"`
+	CostUSD   float64   `json:"cost_usd,omitempty"` // Session cost, for costs entries
+	Received  bool      `json:"received,omitempty"` // Another actor did this to the agent
+	From      string    `json:"from,omitempty"`     // Actor, for received entries
+}
+
+// TimelineOutput is the JSON output of gt timeline.
+type TimelineOutput struct {
+	Agent   string          `json:"agent"`
+	Session string          `json:"session,omitempty"`
+	Since   time.Time       `json:"since"`
+	Entries []TimelineEntry `json:"entries"`
+}
+
+func runTimeline(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	window, err := parseDuration(timelineSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since duration: %w", err)
+	}
+	since := time.Now().Add(-window)
+
+	address, sessionName := timelineAgent(args[0])
+
+	activity, err := readActivityEvents(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	townEvents, _ := townlog.ReadEvents(townRoot) // Town log is optional
+	var costs []CostEntry
+	if !timelineNoCosts {
+		costs, _ = querySessionEvents() // Best-effort: needs bd
+	}
+
+	entries := buildTimeline(agentKey(address), activity, townEvents, costs, since)
+	if timelineLimit > 0 && len(entries) > timelineLimit {
+		entries = entries[len(entries)-timelineLimit:]
+	}
+
+	if timelineJSON {
+		if entries == nil {
+			entries = []TimelineEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(TimelineOutput{Agent: address, Session: sessionName, Since: since, Entries: entries})
+	}
+	return outputTimelineText(address, entries, time.Now())
+}
+
+// timelineAgent resolves the argument to an agent address and, when it is
+// a session name, the session.
+func timelineAgent(arg string) (address, sessionName string) {
+	if id, err := session.ParseSessionName(arg); err == nil {
+		if addr := id.Address(); addr != "" {
+			return addr, arg
+		}
+	}
+	return strings.TrimSuffix(arg, "/"), ""
+}
+
+// buildTimeline collects the entries for the agent with the given key (see
+// agentKey) at or after since, oldest first.
+func buildTimeline(key string, activity []timedEvent, townEvents []townlog.Event, costs []CostEntry, since time.Time) []TimelineEntry {
+	var entries []TimelineEntry
+
+	for _, ev := range activity {
+		if ev.at.Before(since) {
+			continue
+		}
+		if agentKey(ev.Actor) == key {
+			entries = append(entries, TimelineEntry{Timestamp: ev.at, Source: "events", Type: ev.Type, Summary: formatTimelineEvent(ev.Event)})
+		} else if timelineTargets(ev.Event, key) {
+			entries = append(entries, TimelineEntry{Timestamp: ev.at, Source: "events", Type: ev.Type, Summary: formatTimelineEvent(ev.Event), Received: true, From: ev.Actor})
+		}
+	}
+
+	for _, e := range townEvents {
+		if e.Timestamp.Before(since) || agentKey(e.Agent) != key {
+			continue
+		}
+		entries = append(entries, TimelineEntry{Timestamp: e.Timestamp, Source: "townlog", Type: string(e.Type), Summary: formatTownlogSummary(e)})
+	}
+
+	for _, c := range costs {
+		if c.EndedAt.Before(since) || agentKey(buildAgentPath(c.Role, c.Rig, c.Worker)) != key {
+			continue
+		}
+		summary := fmt.Sprintf("Session ended, cost $%.2f", c.CostUSD)
+		if c.WorkItem != "" {
+			summary += " (" + c.WorkItem + ")"
+		}
+		entries = append(entries, TimelineEntry{Timestamp: c.EndedAt, Source: "costs", Type: "session_cost", Summary: summary, CostUSD: c.CostUSD})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+	return entries
+}
+
+// timelineTargets reports whether another actor's event was aimed at the
+// agent: nudges, kills, and escalations name a target; mail names a
+// recipient. Targets may be addresses, session names, or bare polecat
+// names qualified by the payload's rig.
+func timelineTargets(ev events.Event, key string) bool {
+	rig, _ := ev.Payload["rig"].(string)
+	for _, field := range []string{"target", "to"} {
+		target, _ := ev.Payload[field].(string)
+		if target == "" {
+			continue
+		}
+		if id, err := session.ParseSessionName(target); err == nil && id.Address() != "" {
+			target = id.Address()
+		} else if rig != "" && !strings.Contains(target, "/") {
+			target = rig + "/" + target
+		}
+		if agentKey(target) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// formatTimelineEvent summarizes an events log entry, adding the detail
+// gt audit's feed summaries leave out.
+func formatTimelineEvent(e events.Event) string {
+	str := func(k string) string {
+		s, _ := e.Payload[k].(string)
+		return s
+	}
+	switch e.Type {
+	case events.TypeHook:
+		return "Hooked " + str("bead")
+	case events.TypeUnhook:
+		return "Unhooked " + str("bead")
+	case events.TypeSessionStart:
+		if topic := str("topic"); topic != "" {
+			return "Session started: " + topic
+		}
+		return "Session started"
+	case events.TypeSessionEnd:
+		return "Session ended"
+	case events.TypeNudge, events.TypePolecatNudged:
+		return joinNonEmpty("Nudged "+str("target"), str("reason"))
+	case events.TypeKill:
+		return joinNonEmpty("Killed "+str("target"), str("reason"))
+	case events.TypeEscalationSent:
+		return joinNonEmpty(fmt.Sprintf("Escalated %s to %s", str("target"), str("to")), str("reason"))
+	case events.TypeSpawn:
+		return "Spawned " + str("polecat")
+	case events.TypeMail:
+		return joinNonEmpty("Mail to "+str("to"), str("subject"))
+	case events.TypeHandoff:
+		return joinNonEmpty("Handed off", str("subject"))
+	}
+	return formatFeedSummary(e)
+}
+
+// joinNonEmpty joins a summary and an optional detail with ": ".
+func joinNonEmpty(summary, detail string) string {
+	if detail == "" {
+		return summary
+	}
+	return summary + ": " + detail
+}
+
+// formatRelative formats how long before now t was, compactly.
+func formatRelative(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm ago", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+func outputTimelineText(address string, entries []TimelineEntry, now time.Time) error {
+	fmt.Printf("%s %s\n", style.Bold.Render("Timeline:"), address)
+	if len(entries) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No activity in this period"))
+		return nil
+	}
+
+	var total float64
+	var currentDate string
+	for _, e := range entries {
+		total += e.CostUSD
+		local := e.Timestamp.Local()
+		if date := local.Format("2006-01-02"); date != currentDate {
+			fmt.Printf("\n%s\n", style.Bold.Render("─── "+date+" ───"))
+			currentDate = date
+		}
+		summary := e.Summary
+		if e.Received {
+			summary = style.Dim.Render("← "+e.From+" ") + summary
+		}
+		fmt.Printf("  %s %s %s\n",
+			style.Dim.Render(local.Format("15:04")),
+			style.Dim.Render(fmt.Sprintf("%-10s", formatRelative(e.Timestamp, now))),
+			summary)
+	}
+	if total > 0 {
+		fmt.Printf("\n%s $%.2f\n", style.Dim.Render("Session costs in period:"), total)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/townlog"
+)
+
+func TestTimelineAgent(t *testing.T) {
+	addr, sess := timelineAgent("gt-gp-witness")
+	if addr != "gp/witness" || sess != "gt-gp-witness" {
+		t.Errorf("session name: got (%q, %q)", addr, sess)
+	}
+	addr, sess = timelineAgent("gp/polecats/Toast/")
+	if addr != "gp/polecats/Toast" || sess != "" {
+		t.Errorf("address: got (%q, %q)", addr, sess)
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-time.Hour)
+	at := func(ago time.Duration) time.Time { return now.Add(-ago) }
+	ev := func(ago time.Duration, typ, actor string, payload map[string]interface{}) timedEvent {
+		return timedEvent{Event: events.Event{Type: typ, Actor: actor, Payload: payload}, at: at(ago)}
+	}
+
+	activity := []timedEvent{
+		ev(2*time.Hour, events.TypeHook, "gp/polecats/Toast", map[string]interface{}{"bead": "gp-old"}),
+		ev(40*time.Minute, events.TypeHook, "gp/polecats/Toast", map[string]interface{}{"bead": "gp-1"}),
+		ev(30*time.Minute, events.TypeNudge, "gp/witness", map[string]interface{}{"rig": "gp", "target": "Toast", "reason": "idle"}),
+		ev(20*time.Minute, events.TypeNudge, "gp/witness", map[string]interface{}{"target": "gt-gp-Toast"}),
+		ev(15*time.Minute, events.TypeNudge, "gp/witness", map[string]interface{}{"rig": "gp", "target": "Nux"}),
+		ev(10*time.Minute, events.TypeHook, "gp/polecats/Nux", map[string]interface{}{"bead": "gp-2"}),
+	}
+	townEvents := []townlog.Event{
+		{Timestamp: at(5 * time.Minute), Type: townlog.EventDone, Agent: "gp/polecats/Toast", Context: "gp-1"},
+		{Timestamp: at(3 * time.Hour), Type: townlog.EventSpawn, Agent: "gp/polecats/Toast"},
+	}
+	costs := []CostEntry{
+		{Role: "polecat", Rig: "gp", Worker: "Toast", CostUSD: 1.25, EndedAt: at(time.Minute)},
+		{Role: "polecat", Rig: "gp", Worker: "Nux", CostUSD: 9, EndedAt: at(time.Minute)},
+	}
+
+	entries := buildTimeline(agentKey("gp/polecats/Toast"), activity, townEvents, costs, since)
+	if len(entries) != 5 {
+		t.Fatalf("got %d entries, want 5: %+v", len(entries), entries)
+	}
+	for i := 1; i < len(entries); i++ {
+		if entries[i].Timestamp.Before(entries[i-1].Timestamp) {
+			t.Errorf("entries not oldest first at %d", i)
+		}
+	}
+	if entries[0].Summary != "Hooked gp-1" || entries[0].Received {
+		t.Errorf("entry 0 = %+v", entries[0])
+	}
+	if !entries[1].Received || entries[1].From != "gp/witness" || entries[1].Summary != "Nudged Toast: idle" {
+		t.Errorf("bare-name nudge = %+v", entries[1])
+	}
+	if !entries[2].Received {
+		t.Errorf("session-name nudge = %+v", entries[2])
+	}
+	if entries[3].Source != "townlog" {
+		t.Errorf("entry 3 = %+v", entries[3])
+	}
+	if entries[4].Source != "costs" || entries[4].CostUSD != 1.25 {
+		t.Errorf("cost entry = %+v", entries[4])
+	}
+}
+
+func TestFormatRelative(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{2*time.Hour + 7*time.Minute, "2h07m ago"},
+		{50 * time.Hour, "2d ago"},
+	}
+	for _, tt := range tests {
+		if got := formatRelative(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("formatRelative(-%v) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var townExportProfileCmd = &cobra.Command{
+	Use:   "export-profile <file>",
+	Short: "Export this town's configuration as a reusable profile",
+	Long: `Export the town's configuration as a YAML profile that can stamp out
+identical towns with 'gt init --from-profile' or 'gt install --from-profile'.
+
+The profile captures:
+  - Town settings and policies (settings/config.json: agents, env, cost
+    alerts, doctor profiles, context budgets, mail encryption, ...)
+  - Custom agent definitions (settings/agents.json)
+  - Mayor, daemon patrol, and messaging config
+  - Customized slash commands (.cursor/commands/; stock ones are skipped)
+  - Rigs: git URL, beads prefix, default branch, mirror flag, and settings
+
+It does not capture data: beads, mail, sessions, clones, and machine-local
+paths stay behind. Plaintext secrets are replaced with secretRef:env
+references and reported, so the profile is safe to share.
+
+Examples:
+  gt town export-profile team.yaml
+  gt init --from-profile team.yaml     # In a new directory`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownExportProfile,
+}
+
+func init() {
+	townCmd.AddCommand(townExportProfileCmd)
+}
+
+func runTownExportProfile(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	profile, warnings, err := config.ExportTownProfile(townRoot)
+	if err != nil {
+		return err
+	}
+	for name, content := range profile.Commands {
+		if templates.IsBuiltinCommand(name, []byte(content)) {
+			delete(profile.Commands, name)
+		}
+	}
+
+	if err := config.SaveTownProfile(args[0], profile); err != nil {
+		return err
+	}
+
+	for _, w := range warnings {
+		fmt.Printf("%s %s\n", style.WarningPrefix, w)
+	}
+	fmt.Printf("%s Exported town profile to %s (%d rig(s), %d custom command(s))\n",
+		style.SuccessPrefix, args[0], len(profile.Rigs), len(profile.Commands))
+	return nil
+}
+
+// applyTownProfile stamps a profile onto a freshly installed town: config
+// files and commands first, then each rig via 'gt rig add' followed by its
+// settings. A rig that fails to add is reported and skipped, so one
+// unreachable remote does not abort the whole town.
+func applyTownProfile(townRoot string, profile *config.TownProfile) error {
+	if err := config.ApplyTownProfile(townRoot, profile); err != nil {
+		return fmt.Errorf("applying profile: %w", err)
+	}
+	fmt.Printf("   OK Applied profile config and commands\n")
+
+	if len(profile.Rigs) == 0 {
+		return nil
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding gt executable: %w", err)
+	}
+	for _, rp := range profile.Rigs {
+		rigPath := filepath.Join(townRoot, rp.Name)
+		if _, err := os.Stat(rigPath); os.IsNotExist(err) {
+			args := []string{"rig", "add", rp.Name, rp.GitURL}
+			if rp.Prefix != "" {
+				args = append(args, "--prefix", rp.Prefix)
+			}
+			if rp.Branch != "" {
+				args = append(args, "--branch", rp.Branch)
+			}
+			if rp.Mirror {
+				args = append(args, "--mirror")
+			}
+			fmt.Printf("\n%s Adding rig %s from profile\n", style.Bold.Render("[CFG]"), rp.Name)
+			addCmd := exec.Command(gtPath, args...) //nolint:gosec // G204: args come from the profile
+			addCmd.Dir = townRoot
+			addCmd.Stdout = os.Stdout
+			addCmd.Stderr = os.Stderr
+			if err := addCmd.Run(); err != nil {
+				fmt.Printf("   %s Could not add rig %s: %v\n", style.Dim.Render("WARN"), rp.Name, err)
+				continue
+			}
+		}
+		if err := config.ApplyRigProfileSettings(rigPath, rp); err != nil {
+			fmt.Printf("   %s Could not apply settings for rig %s: %v\n", style.Dim.Render("WARN"), rp.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/tracker"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// Tracker command flags
+var (
+	trackerRig          string
+	trackerImportDryRun bool
+	trackerCloseMessage string
+)
+
+var trackerCmd = &cobra.Command{
+	Use:     "tracker",
+	GroupID: GroupWork,
+	Short:   "Sync work with an external issue tracker (Linear)",
+	RunE:    requireSubcommand,
+	Long: `Sync a rig's work with an external issue tracker.
+
+Configure the tracker in <rig>/settings/config.json:
+
+  "tracker": {
+    "type": "linear",
+    "team": "ENG",
+    "api_key": "secretRef:op:op://Dev/Linear/credential",
+    "label": "gastown",
+    "close_state": "Done"
+  }
+
+label (optional) imports only issues with that label; close_state
+(optional) is the workflow state issues move to on merge (default: the
+team's first completed state).
+
+Imported issues become beads labeled "linear:<key>". When the refinery
+merges work for such a bead, it comments on the Linear issue and closes it.`,
+}
+
+var trackerImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import open tracker issues as beads",
+	Long: `Import the tracker's open issues as beads in the rig.
+
+Issues already linked to a bead are skipped, so import is safe to re-run.
+
+Examples:
+  gt tracker import --rig greenplace
+  gt tracker import --rig greenplace --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runTrackerImport,
+}
+
+var trackerStatusCmd = &cobra.Command{
+	Use:   "status <bead-or-issue> <message>",
+	Short: "Post a status comment to a tracker issue",
+	Long: `Post a status comment to the tracker issue a bead was imported from.
+
+The first argument is a bead ID linked to a tracker issue, or a tracker
+issue key (e.g. ENG-123).
+
+Examples:
+  gt tracker status gp-abc "Polecat Toast started work"
+  gt tracker status ENG-123 "Blocked on API review"`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runTrackerStatus,
+}
+
+var trackerCloseCmd = &cobra.Command{
+	Use:   "close <bead-or-issue>",
+	Short: "Close a tracker issue",
+	Long: `Close the tracker issue a bead was imported from.
+
+The refinery does this automatically when work merges; use this to close
+issues resolved outside the merge queue.
+
+Examples:
+  gt tracker close gp-abc
+  gt tracker close ENG-123 --message "Fixed by config change"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrackerClose,
+}
+
+func init() {
+	trackerCmd.PersistentFlags().StringVar(&trackerRig, "rig", "", "Rig whose tracker to use (inferred from cwd if not set)")
+	trackerImportCmd.Flags().BoolVar(&trackerImportDryRun, "dry-run", false, "Show issues that would be imported")
+	trackerCloseCmd.Flags().StringVarP(&trackerCloseMessage, "message", "m", "", "Comment to add when closing")
+
+	trackerCmd.AddCommand(trackerImportCmd)
+	trackerCmd.AddCommand(trackerStatusCmd)
+	trackerCmd.AddCommand(trackerCloseCmd)
+	rootCmd.AddCommand(trackerCmd)
+}
+
+// loadRigTracker returns the tracker and beads for the selected rig.
+func loadRigTracker() (tracker.Tracker, *beads.Beads, string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName := trackerRig
+	if rigName == "" {
+		rigName, err = inferRigFromCwd(townRoot)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("could not determine rig: %w\nUse --rig <rig>", err)
+		}
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	t, err := tracker.ForRig(r.Path)
+	if errors.Is(err, tracker.ErrNotConfigured) {
+		return nil, nil, "", fmt.Errorf("rig %s has no tracker configured (add \"tracker\" to %s)", rigName, config.RigSettingsPath(r.Path))
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return t, beads.New(r.Path), rigName, nil
+}
+
+// resolveTrackerKey maps a bead ID to its linked tracker issue key. Anything
+// that is not a linked bead is treated as an issue key.
+func resolveTrackerKey(t tracker.Tracker, bd *beads.Beads, arg string) string {
+	if issue, err := bd.Show(arg); err == nil {
+		if key, ok := tracker.LinkedKey(t, issue.Labels); ok {
+			return key
+		}
+	}
+	return arg
+}
+
+func runTrackerImport(cmd *cobra.Command, args []string) error {
+	t, bd, rigName, err := loadRigTracker()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	issues, err := t.OpenIssues(ctx)
+	if err != nil {
+		return fmt.Errorf("listing %s issues: %w", t.Name(), err)
+	}
+	existing, err := bd.List(beads.ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("listing beads: %w", err)
+	}
+	pending := tracker.Unimported(t, issues, existing)
+
+	if len(pending) == 0 {
+		fmt.Printf("%s No new %s issues for %s (%d open, all imported)\n", style.SuccessPrefix, t.Name(), rigName, len(issues))
+		return nil
+	}
+
+	imported := 0
+	for _, issue := range pending {
+		if trackerImportDryRun {
+			fmt.Printf("  %s %s %s\n", style.Dim.Render("would import"), style.Bold.Render(issue.Key), issue.Title)
+			continue
+		}
+		bead, err := bd.Create(beads.CreateOptions{
+			Title:       issue.Title,
+			Type:        "task",
+			Priority:    issue.Priority,
+			Description: tracker.BeadDescription(t, issue),
+		})
+		if err != nil {
+			style.PrintWarning("could not import %s: %v", issue.Key, err)
+			continue
+		}
+		if err := bd.Update(bead.ID, beads.UpdateOptions{AddLabels: []string{tracker.Label(t, issue.Key)}}); err != nil {
+			style.PrintWarning("imported %s as %s but could not link it: %v", issue.Key, bead.ID, err)
+			continue
+		}
+		imported++
+		fmt.Printf("  %s %s → %s %s\n", style.Success.Render("✓"), issue.Key, bead.ID, style.Dim.Render(issue.Title))
+	}
+
+	if trackerImportDryRun {
+		fmt.Printf("\n%d issue(s) would be imported into %s\n", len(pending), rigName)
+		return nil
+	}
+	fmt.Printf("\n%s Imported %d of %d new %s issue(s) into %s\n", style.SuccessPrefix, imported, len(pending), t.Name(), rigName)
+	if imported < len(pending) {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runTrackerStatus(cmd *cobra.Command, args []string) error {
+	t, bd, _, err := loadRigTracker()
+	if err != nil {
+		return err
+	}
+	key := resolveTrackerKey(t, bd, args[0])
+	message := strings.Join(args[1:], " ")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := t.PostStatus(ctx, key, message); err != nil {
+		return fmt.Errorf("posting status to %s: %w", key, err)
+	}
+	fmt.Printf("%s Posted status to %s issue %s\n", style.SuccessPrefix, t.Name(), key)
+	return nil
+}
+
+func runTrackerClose(cmd *cobra.Command, args []string) error {
+	t, bd, _, err := loadRigTracker()
+	if err != nil {
+		return err
+	}
+	key := resolveTrackerKey(t, bd, args[0])
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := t.Close(ctx, key, trackerCloseMessage); err != nil {
+		return fmt.Errorf("closing %s: %w", key, err)
+	}
+	fmt.Printf("%s Closed %s issue %s\n", style.SuccessPrefix, t.Name(), key)
+	return nil
+}
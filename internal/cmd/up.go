@@ -256,7 +256,10 @@ func discoverRigs(townRoot string) []string {
 	// Try rigs.json first
 	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
 	if rigsConfig, err := config.LoadRigsConfig(rigsConfigPath); err == nil {
-		for name := range rigsConfig.Rigs {
+		for name, entry := range rigsConfig.Rigs {
+			if entry.Mirror {
+				continue
+			}
 			rigs = append(rigs, name)
 		}
 		return rigs
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
 )
 
 // Version information - set at build time via ldflags
@@ -39,6 +40,9 @@ var versionCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+
+	// Stamp generated hooks with this binary's version
+	cursor.GeneratorVersion = Version
 }
 
 func resolveCommitHash() string {
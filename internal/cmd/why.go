@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+var (
+	whyAgent string
+	whyRole  string
+	whyJSON  bool
+)
+
+var whyCmd = &cobra.Command{
+	Use:     "why",
+	GroupID: GroupDiag,
+	Short:   "Explain how gt reached a decision",
+	Long: `Explain how gt chose a value or why it refuses an action.
+
+Each subcommand prints the rules consulted, in order, with the config file
+each one reads and what it found. The rule that decided is marked with →.
+
+Examples:
+  gt why agent greenplace          # Which agent greenplace sessions run, and why
+  gt why agent --agent gemini      # What an --agent override resolves to
+  gt why spawn greenplace          # Would a polecat spawn be allowed?
+  gt why spawn --role mayor        # Preflight for a town-level agent`,
+}
+
+var whyAgentCmd = &cobra.Command{
+	Use:   "agent [rig]",
+	Short: "Explain which agent a rig's sessions run",
+	Long: `Trace agent resolution for a rig (or town-level agents without one):
+the --agent override, the deprecated rig runtime, the rig's agent, the
+town's default_agent, then custom agents and built-in presets.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWhyAgent,
+}
+
+var whySpawnCmd = &cobra.Command{
+	Use:   "spawn [rig]",
+	Short: "Explain whether preflight would allow a session to spawn",
+	Long: `Run every spawn preflight check for a role and show each outcome:
+passed, refused (with the fix), or not applicable (with the reason).
+Nothing is spawned.
+
+Roles: polecat (default), witness, refinery, crew need a rig;
+mayor and deacon do not.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWhySpawn,
+}
+
+func init() {
+	whyCmd.PersistentFlags().StringVar(&whyAgent, "agent", "", "Explain with this --agent override")
+	whyCmd.PersistentFlags().BoolVar(&whyJSON, "json", false, "Output as JSON")
+	whySpawnCmd.Flags().StringVar(&whyRole, "role", string(session.RolePolecat), "Role to check: polecat, witness, refinery, crew, mayor, deacon")
+
+	whyCmd.AddCommand(whyAgentCmd)
+	whyCmd.AddCommand(whySpawnCmd)
+	rootCmd.AddCommand(whyCmd)
+}
+
+// WhyAgentOutput is the JSON output of 'gt why agent'.
+type WhyAgentOutput struct {
+	Rig     string                  `json:"rig,omitempty"`
+	Agent   string                  `json:"agent,omitempty"`
+	Command string                  `json:"command,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+	Steps   []config.ResolutionStep `json:"steps"`
+}
+
+// WhySpawnCheck is one preflight check in 'gt why spawn' JSON output.
+type WhySpawnCheck struct {
+	Check   string `json:"check"`
+	Outcome string `json:"outcome"` // pass, fail, or skip
+	Reason  string `json:"reason,omitempty"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// WhySpawnOutput is the JSON output of 'gt why spawn'.
+type WhySpawnOutput struct {
+	Rig      string          `json:"rig,omitempty"`
+	Role     string          `json:"role"`
+	Allowed  bool            `json:"allowed"`
+	Bypassed bool            `json:"bypassed,omitempty"` // GT_SKIP_PREFLIGHT=1 is set
+	Checks   []WhySpawnCheck `json:"checks"`
+}
+
+// whyTarget resolves the town and optional rig for a why subcommand.
+func whyTarget(args []string) (string, *rig.Rig, error) {
+	if len(args) == 0 {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		return townRoot, nil, nil
+	}
+	return getRig(args[0])
+}
+
+func runWhyAgent(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := whyTarget(args)
+	if err != nil {
+		return err
+	}
+	rigPath := ""
+	out := WhyAgentOutput{}
+	if r != nil {
+		rigPath, out.Rig = r.Path, r.Name
+	}
+
+	rc, agentName, steps, resolveErr := config.ExplainAgentConfig(townRoot, rigPath, whyAgent)
+	out.Steps, out.Agent = steps, agentName
+	if rc != nil {
+		out.Command = rc.Command
+	}
+	if resolveErr != nil {
+		out.Error = resolveErr.Error()
+	}
+
+	if whyJSON {
+		return printWhyJSON(out)
+	}
+
+	subject := "Town-level agents"
+	if out.Rig != "" {
+		subject = "Rig " + out.Rig
+	}
+	switch {
+	case resolveErr != nil:
+		fmt.Printf("%s: %s\n\n", style.Bold.Render(subject), style.Error.Render(resolveErr.Error()))
+	case agentName == "":
+		fmt.Printf("%s run %s\n\n", style.Bold.Render(subject), out.Command)
+	default:
+		fmt.Printf("%s run agent %q (%s)\n\n", style.Bold.Render(subject), agentName, out.Command)
+	}
+	for _, s := range steps {
+		marker := style.Dim.Render("·")
+		if s.Decided {
+			marker = style.Success.Render("→")
+		}
+		fmt.Printf("  %s %s: %s\n", marker, s.Rule, s.Result)
+		if s.Source != "" {
+			fmt.Printf("      %s\n", style.Dim.Render(whySource(townRoot, s.Source)))
+		}
+	}
+	return nil
+}
+
+// whySpawnSpec builds the preflight spec a new session of role would get.
+// r is nil for town-level roles.
+func whySpawnSpec(townRoot string, r *rig.Rig, role session.Role) (preflight.Spec, error) {
+	spec := preflight.Spec{Role: role, TownRoot: townRoot, Agent: whyAgent}
+	switch role {
+	case session.RoleMayor, session.RoleDeacon:
+		if r != nil {
+			return spec, fmt.Errorf("%s is a town-level role; omit the rig", role)
+		}
+		spec.SettingsDir = filepath.Join(townRoot, string(role))
+		if role == session.RoleMayor {
+			spec.Session = session.MayorSessionName()
+		} else {
+			spec.Session = session.DeaconSessionName()
+		}
+		return spec, nil
+	case session.RolePolecat, session.RoleWitness, session.RoleRefinery, session.RoleCrew:
+	default:
+		return spec, fmt.Errorf("unknown role %q", role)
+	}
+	if r == nil {
+		return spec, fmt.Errorf("%s sessions belong to a rig; name one", role)
+	}
+
+	spec.RigPath = r.Path
+	switch role {
+	case session.RolePolecat:
+		spec.Session = session.PolecatSessionName(r.Name, "new")
+		spec.SettingsDir = filepath.Join(r.Path, "polecats")
+		spec.MaxPolecats = r.GetIntConfig("max_polecats")
+	case session.RoleWitness:
+		spec.Session = session.WitnessSessionName(r.Name)
+		spec.SettingsDir = filepath.Join(r.Path, "witness")
+	case session.RoleRefinery:
+		spec.Session = session.RefinerySessionName(r.Name)
+		spec.SettingsDir = filepath.Join(r.Path, "refinery")
+	case session.RoleCrew:
+		spec.Session = session.CrewSessionName(r.Name, "new")
+		spec.SettingsDir = filepath.Join(r.Path, "crew")
+	}
+	return spec, nil
+}
+
+// whySpawnChecks converts preflight decisions for output.
+func whySpawnChecks(decisions []preflight.Decision) ([]WhySpawnCheck, bool) {
+	allowed := true
+	checks := make([]WhySpawnCheck, 0, len(decisions))
+	for _, d := range decisions {
+		c := WhySpawnCheck{Check: d.Check}
+		switch {
+		case d.Problem != nil:
+			c.Outcome, c.Reason, c.Fix = "fail", d.Problem.Message, d.Problem.Fix
+			allowed = false
+		case d.Skipped != "":
+			c.Outcome, c.Reason = "skip", d.Skipped
+		default:
+			c.Outcome = "pass"
+		}
+		checks = append(checks, c)
+	}
+	return checks, allowed
+}
+
+func runWhySpawn(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := whyTarget(args)
+	if err != nil {
+		return err
+	}
+	role := session.Role(strings.ToLower(whyRole))
+	spec, err := whySpawnSpec(townRoot, r, role)
+	if err != nil {
+		return err
+	}
+
+	out := WhySpawnOutput{Role: string(role), Bypassed: os.Getenv(preflight.SkipEnv) == "1"}
+	if r != nil {
+		out.Rig = r.Name
+	}
+	out.Checks, out.Allowed = whySpawnChecks(preflight.Explain(spec))
+
+	if whyJSON {
+		return printWhyJSON(out)
+	}
+
+	subject := string(role)
+	if out.Rig != "" {
+		subject = out.Rig + " " + subject
+	}
+	verdict := style.Success.Render("allowed")
+	if !out.Allowed {
+		verdict = style.Error.Render("refused")
+	}
+	fmt.Printf("%s spawn would be %s\n\n", style.Bold.Render(subject), verdict)
+	for _, c := range out.Checks {
+		switch c.Outcome {
+		case "pass":
+			fmt.Printf("  %s %s\n", style.Success.Render("✓"), c.Check)
+		case "fail":
+			fmt.Printf("  %s %s: %s\n", style.Error.Render("✗"), c.Check, c.Reason)
+			if c.Fix != "" {
+				fmt.Printf("      %s\n", style.Dim.Render("fix: "+c.Fix))
+			}
+		default:
+			fmt.Printf("  %s %s: %s\n", style.Dim.Render("-"), c.Check, style.Dim.Render("n/a, "+c.Reason))
+		}
+	}
+	if out.Bypassed {
+		fmt.Printf("\n%s %s=1 is set: preflight is bypassed and the spawn would proceed anyway\n", style.WarningPrefix, preflight.SkipEnv)
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Agent choice: gt why agent"+whyRigArg(out.Rig)))
+	return nil
+}
+
+// whyRigArg returns " <rig>" for hints, or "" for town-level agents.
+func whyRigArg(rigName string) string {
+	if rigName == "" {
+		return ""
+	}
+	return " " + rigName
+}
+
+// whySource shows config paths relative to the town root.
+func whySource(townRoot, source string) string {
+	if rel, err := filepath.Rel(townRoot, source); err == nil && filepath.IsAbs(source) && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return source
+}
+
+func printWhyJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
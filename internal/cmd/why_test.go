@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+)
+
+func TestWhySpawnSpec(t *testing.T) {
+	townRoot := t.TempDir()
+	r := &rig.Rig{Name: "gp", Path: filepath.Join(townRoot, "gp")}
+
+	spec, err := whySpawnSpec(townRoot, r, session.RoleWitness)
+	if err != nil {
+		t.Fatalf("witness: %v", err)
+	}
+	if spec.RigPath != r.Path || spec.SettingsDir != filepath.Join(r.Path, "witness") || spec.Session != session.WitnessSessionName("gp") {
+		t.Errorf("witness spec = %+v", spec)
+	}
+
+	spec, err = whySpawnSpec(townRoot, nil, session.RoleMayor)
+	if err != nil {
+		t.Fatalf("mayor: %v", err)
+	}
+	if spec.RigPath != "" || spec.SettingsDir != filepath.Join(townRoot, "mayor") {
+		t.Errorf("mayor spec = %+v", spec)
+	}
+
+	if _, err := whySpawnSpec(townRoot, nil, session.RolePolecat); err == nil {
+		t.Error("polecat without a rig should error")
+	}
+	if _, err := whySpawnSpec(townRoot, r, session.RoleDeacon); err == nil {
+		t.Error("deacon with a rig should error")
+	}
+	if _, err := whySpawnSpec(townRoot, r, session.Role("janitor")); err == nil {
+		t.Error("unknown role should error")
+	}
+}
+
+func TestWhySpawnChecks(t *testing.T) {
+	checks, allowed := whySpawnChecks([]preflight.Decision{
+		{Check: "agent"},
+		{Check: "budget", Problem: &preflight.Problem{Check: "budget", Message: "daily budget exhausted", Fix: "raise it"}},
+		{Check: "slot", Skipped: "only polecats take slots"},
+	})
+	if allowed {
+		t.Error("a failed check should refuse the spawn")
+	}
+	want := []WhySpawnCheck{
+		{Check: "agent", Outcome: "pass"},
+		{Check: "budget", Outcome: "fail", Reason: "daily budget exhausted", Fix: "raise it"},
+		{Check: "slot", Outcome: "skip", Reason: "only polecats take slots"},
+	}
+	for i := range want {
+		if checks[i] != want[i] {
+			t.Errorf("checks[%d] = %+v, want %+v", i, checks[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+	"github.com/cursorworkshop/cursor-gastown/internal/style"
+	"github.com/cursorworkshop/cursor-gastown/internal/testledger"
+	"github.com/cursorworkshop/cursor-gastown/internal/witness"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// Witness report flags
+var (
+	witnessReportRig         string
+	witnessReportCycle       string
+	witnessReportCommits     int
+	witnessReportIssues      []string
+	witnessReportFlaky       []string
+	witnessReportPolecats    int
+	witnessReportNudges      int
+	witnessReportEscalations int
+	witnessReportNotes       string
+	witnessReportNotify      string
+
+	witnessReportsRig    string
+	witnessReportsLimit  int
+	witnessReportsWindow int
+	witnessReportsJSON   bool
+)
+
+var witnessReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Record a patrol cycle report (called by the witness patrol)",
+	Long: `Record the structured outcome of a witness patrol cycle.
+
+The report is written as a patrol_report event and mailed (low priority)
+so patrol output is accountable rather than ephemeral chat. Tests that
+passed only on retry in the rig's test ledger (gt tests) since the last
+report are added to the flaky tests. Use
+'gt witness reports' to see history and trends.
+
+Pass --cycle with the patrol wisp ID: re-running the report for the same
+cycle (e.g. after a crash or retry) records it only once.
+
+Examples:
+  gt witness report --rig greenplace --cycle gp-wisp-x1 --commits 4 --polecats 3
+  gt witness report --rig greenplace --issue gp-abc --issue "refinery queue stalled" \
+    --flaky TestSyncRace --nudges 1`,
+	Args: cobra.NoArgs,
+	RunE: runWitnessReport,
+}
+
+var witnessReportsCmd = &cobra.Command{
+	Use:   "reports",
+	Short: "Show witness patrol report history and trends",
+	Long: `Show a rig's witness patrol reports with trends.
+
+Lists recent reports (newest first), then compares the latest report with
+the average of the reports before it and lists flaky tests seen in more
+than one report.
+
+If --rig is not specified, infers it from the current directory.
+
+Examples:
+  gt witness reports --rig greenplace
+  gt witness reports --rig greenplace --limit 50 --window 20
+  gt witness reports --rig greenplace --json`,
+	Args: cobra.NoArgs,
+	RunE: runWitnessReports,
+}
+
+func init() {
+	witnessReportCmd.Flags().StringVar(&witnessReportRig, "rig", "", "Rig the patrol covered (inferred from cwd if not set)")
+	witnessReportCmd.Flags().StringVar(&witnessReportCycle, "cycle", "", "Patrol cycle ID (wisp ID); the report is recorded once per cycle")
+	witnessReportCmd.Flags().IntVar(&witnessReportCommits, "commits", 0, "New commits reviewed this cycle")
+	witnessReportCmd.Flags().StringArrayVar(&witnessReportIssues, "issue", nil, "Issue found (bead ID or short description; repeatable)")
+	witnessReportCmd.Flags().StringArrayVar(&witnessReportFlaky, "flaky", nil, "Flaky test observed (repeatable)")
+	witnessReportCmd.Flags().IntVar(&witnessReportPolecats, "polecats", 0, "Polecats checked")
+	witnessReportCmd.Flags().IntVar(&witnessReportNudges, "nudges", 0, "Polecats nudged")
+	witnessReportCmd.Flags().IntVar(&witnessReportEscalations, "escalations", 0, "Escalations sent")
+	witnessReportCmd.Flags().StringVar(&witnessReportNotes, "notes", "", "Free-form notes")
+	witnessReportCmd.Flags().StringVar(&witnessReportNotify, "notify", "mayor/", "Mail the report to this address (empty to skip)")
+
+	witnessReportsCmd.Flags().StringVar(&witnessReportsRig, "rig", "", "Rig to show reports for (inferred from cwd if not set)")
+	witnessReportsCmd.Flags().IntVarP(&witnessReportsLimit, "limit", "n", 10, "Reports to list (0 for all)")
+	witnessReportsCmd.Flags().IntVar(&witnessReportsWindow, "window", 10, "Earlier reports to average for the trend (0 for all)")
+	witnessReportsCmd.Flags().BoolVar(&witnessReportsJSON, "json", false, "Output as JSON")
+
+	witnessCmd.AddCommand(witnessReportCmd)
+	witnessCmd.AddCommand(witnessReportsCmd)
+}
+
+// resolveWitnessReportRig returns the rig from a flag or the current directory.
+func resolveWitnessReportRig(townRoot, flag string) (string, error) {
+	rigName := flag
+	if rigName == "" {
+		var err error
+		rigName, err = inferRigFromCwd(townRoot)
+		if err != nil {
+			return "", fmt.Errorf("could not determine rig: %w\nUse --rig <rig>", err)
+		}
+	}
+	if _, _, err := getRig(rigName); err != nil {
+		return "", err
+	}
+	return rigName, nil
+}
+
+func runWitnessReport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName, err := resolveWitnessReportRig(townRoot, witnessReportRig)
+	if err != nil {
+		return err
+	}
+
+	report := &witness.PatrolReport{
+		Rig:             rigName,
+		Cycle:           witnessReportCycle,
+		CommitsReviewed: witnessReportCommits,
+		Issues:          witnessReportIssues,
+		FlakyTests:      witnessReportFlaky,
+		PolecatsChecked: witnessReportPolecats,
+		Nudges:          witnessReportNudges,
+		Escalations:     witnessReportEscalations,
+		Notes:           witnessReportNotes,
+	}
+	report.FlakyTests = appendLedgerFlaky(townRoot, rigName, report.FlakyTests)
+
+	actor := rigName + "/witness"
+	key := ""
+	if report.Cycle != "" {
+		key = fmt.Sprintf("%s:%s:%s", events.TypePatrolReport, rigName, report.Cycle)
+	}
+	written, err := events.LogOnce(key, events.TypePatrolReport, actor, report.Payload(), events.VisibilityFeed)
+	if err != nil {
+		return fmt.Errorf("recording report: %w", err)
+	}
+	if !written {
+		fmt.Printf("%s Report for cycle %s already recorded\n", style.Dim.Render("[SKIP]"), report.Cycle)
+		return nil
+	}
+
+	if witnessReportNotify != "" {
+		router := mail.NewRouter(townRoot)
+		msg := &mail.Message{
+			From:     actor,
+			To:       witnessReportNotify,
+			Subject:  fmt.Sprintf("PATROL_REPORT %s: %s", rigName, report.Summary()),
+			Body:     report.Body(),
+			Priority: mail.PriorityLow,
+		}
+		if err := router.Send(msg); err != nil {
+			style.PrintWarning("could not mail patrol report: %v", err)
+		}
+	}
+
+	fmt.Printf("%s Recorded patrol report for %s: %s\n", style.Success.Render("[OK]"), rigName, report.Summary())
+	return nil
+}
+
+// appendLedgerFlaky adds the tests that passed only on retry in the rig's
+// test ledger since the previous patrol report to flaky, skipping ones
+// already listed.
+func appendLedgerFlaky(townRoot, rigName string, flaky []string) []string {
+	runs, err := testledger.Load(filepath.Join(townRoot, rigName))
+	if err != nil || len(runs) == 0 {
+		return flaky
+	}
+	if reports, err := witness.LoadReports(townRoot, rigName); err == nil && len(reports) > 0 {
+		runs = testledger.Since(runs, reports[len(reports)-1].Timestamp)
+	}
+	for _, test := range testledger.FlakyTests(runs) {
+		if !slices.Contains(flaky, test) {
+			flaky = append(flaky, test)
+		}
+	}
+	return flaky
+}
+
+// witnessReportsOutput is the JSON output of 'gt witness reports'.
+type witnessReportsOutput struct {
+	Rig     string                 `json:"rig"`
+	Total   int                    `json:"total"`
+	Reports []witness.PatrolReport `json:"reports"`
+	Trend   *witness.ReportTrend   `json:"trend,omitempty"`
+}
+
+func runWitnessReports(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName, err := resolveWitnessReportRig(townRoot, witnessReportsRig)
+	if err != nil {
+		return err
+	}
+
+	reports, err := witness.LoadReports(townRoot, rigName)
+	if err != nil {
+		return fmt.Errorf("reading reports: %w", err)
+	}
+	trend := witness.Trend(reports, witnessReportsWindow)
+
+	// Newest first, limited
+	recent := make([]witness.PatrolReport, 0, len(reports))
+	for i := len(reports) - 1; i >= 0; i-- {
+		if witnessReportsLimit > 0 && len(recent) == witnessReportsLimit {
+			break
+		}
+		recent = append(recent, reports[i])
+	}
+
+	if witnessReportsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(witnessReportsOutput{Rig: rigName, Total: len(reports), Reports: recent, Trend: trend})
+	}
+
+	fmt.Printf("%s Witness patrol reports: %s\n\n", style.Bold.Render(AgentTypeIcons[AgentWitness]), rigName)
+	if len(reports) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No patrol reports yet (recorded by 'gt witness report' at the end of each patrol)"))
+		return nil
+	}
+
+	fmt.Printf("  %-16s  %7s  %6s  %5s  %8s  %s\n", "TIME", "COMMITS", "ISSUES", "FLAKY", "POLECATS", "ESCALATIONS")
+	for _, r := range recent {
+		fmt.Printf("  %-16s  %7d  %6d  %5d  %8d  %d\n",
+			r.Timestamp.Local().Format("2006-01-02 15:04"),
+			r.CommitsReviewed, len(r.Issues), len(r.FlakyTests), r.PolecatsChecked, r.Escalations)
+	}
+	if len(recent) < len(reports) {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("(%d of %d reports; use --limit 0 for all)", len(recent), len(reports))))
+	}
+
+	printWitnessTrend(trend)
+	return nil
+}
+
+func printWitnessTrend(t *witness.ReportTrend) {
+	fmt.Printf("\n%s\n", style.Bold.Render("Latest vs. previous:"))
+	if t.Baseline == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("Only one report so far; no trend yet"))
+	} else {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("(average of %d earlier report(s))", t.Baseline)))
+		printTrendLine("Commits reviewed", float64(t.Latest.CommitsReviewed), t.AvgCommitsReviewed, false)
+		printTrendLine("Issues found", float64(len(t.Latest.Issues)), t.AvgIssues, true)
+		printTrendLine("Flaky tests", float64(len(t.Latest.FlakyTests)), t.AvgFlakyTests, true)
+		printTrendLine("Escalations", float64(t.Latest.Escalations), t.AvgEscalations, true)
+	}
+
+	if len(t.Latest.Issues) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Latest issues:"))
+		for _, issue := range t.Latest.Issues {
+			fmt.Printf("  • %s\n", issue)
+		}
+	}
+
+	if len(t.RecurringFlaky) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Recurring flaky tests:"))
+		for _, f := range t.RecurringFlaky {
+			fmt.Printf("  %s %s\n", f.Test, style.Dim.Render(fmt.Sprintf("(%d reports)", f.Reports)))
+		}
+	}
+}
+
+// printTrendLine prints a latest-vs-average comparison. When higherIsWorse,
+// increases are highlighted as warnings.
+func printTrendLine(label string, latest, avg float64, higherIsWorse bool) {
+	arrow := "→"
+	switch {
+	case latest > avg:
+		arrow = "↑"
+		if higherIsWorse {
+			arrow = style.Warning.Render(arrow)
+		}
+	case latest < avg:
+		arrow = "↓"
+		if higherIsWorse {
+			arrow = style.Success.Render(arrow)
+		}
+	}
+	fmt.Printf("  %-18s %.0f %s %s\n", label+":", latest, arrow,
+		style.Dim.Render(fmt.Sprintf("avg %.1f", avg)))
+}
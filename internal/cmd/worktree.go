@@ -226,9 +226,9 @@ func runWorktreeList(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Cross-rig worktrees for %s/crew/%s:\n\n", sourceRig, crewName)
 
 	found := false
-	for rigName := range rigsConfig.Rigs {
-		// Skip our own rig - worktrees are for cross-rig work
-		if rigName == sourceRig {
+	for rigName, entry := range rigsConfig.Rigs {
+		// Skip our own rig (worktrees are for cross-rig work) and read-only mirrors
+		if rigName == sourceRig || entry.Mirror {
 			continue
 		}
 
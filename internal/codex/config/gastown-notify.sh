@@ -0,0 +1,38 @@
+#!/bin/bash
+# Gas Town notify program for Codex CLI
+#
+# Codex runs its notify program after each agent turn, passing the event
+# as a JSON argument. Codex has no session hooks, so this records costs,
+# syncs beads and checks mail after every turn.
+#
+# Input:  $1 = {"type": "agent-turn-complete", "thread-id": "...", "turn-id": "...", ...}
+# Output: (fire-and-forget, no output expected)
+
+event="${1:-}"
+
+# Export PATH to ensure gt/bd are available
+export PATH={{with .GTBinDir}}{{shellquote .}}:{{end}}"$HOME/go/bin:$HOME/bin:$HOME/.local/bin:$PATH"
+
+type=$(echo "$event" | grep -o '"type":"[^"]*"' | cut -d'"' -f4 2>/dev/null)
+
+if [ -n "$GT_DEBUG" ]; then
+    echo "[$(date '+%Y-%m-%d %H:%M:%S')] codex notify: type=${type:-unknown}" >> /tmp/gastown-hooks.log
+fi
+
+# Only act on completed turns in a Gas Town context
+if [ "$type" != "agent-turn-complete" ] || [ -z "$GT_ROLE" ]; then
+    exit 0
+fi
+
+# Record session costs (suppress all output)
+gt costs record >/dev/null 2>&1 || true
+
+# Sync beads if bd is available (suppress all output)
+if command -v bd &>/dev/null; then
+    bd sync >/dev/null 2>&1 || true
+fi
+
+# Deliver mail that arrived during the turn
+gt mail check --inject >/dev/null 2>&1 &
+
+exit 0
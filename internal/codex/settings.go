@@ -0,0 +1,275 @@
+// Package codex provides Codex CLI configuration management: the AGENTS.md
+// instructions and the .codex/config.toml notify program equivalent to the
+// Cursor rules and hooks.
+package codex
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/settingsbackup"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+)
+
+//go:embed config/gastown-notify.sh
+var configFS embed.FS
+
+// InstructionsFile is the instructions file Codex CLI loads from the
+// workspace and its parents.
+const InstructionsFile = "AGENTS.md"
+
+// notifyScript is the Gas Town notify program installed into .codex/hooks/.
+const notifyScript = "gastown-notify.sh"
+
+// notifyLinePattern matches a top-level notify setting written on one line.
+var notifyLinePattern = regexp.MustCompile(`(?m)^notify\s*=.*\n?`)
+
+// configPath returns where the Codex config lives under workDir.
+func configPath(workDir string) string {
+	return filepath.Join(workDir, ".codex", "config.toml")
+}
+
+// scriptPath returns where the notify program is installed under workDir.
+func scriptPath(workDir string) string {
+	return filepath.Join(workDir, ".codex", "hooks", notifyScript)
+}
+
+// Installed reports whether gt has set up Codex settings in workDir.
+func Installed(workDir string) bool {
+	_, err := os.Stat(scriptPath(workDir))
+	return err == nil
+}
+
+// EnsureSettingsForRole installs Gas Town settings for Codex CLI in
+// workDir: AGENTS.md with the rules composed for role (see
+// cursor.ComposeRules), and .codex/config.toml with a notify program that
+// records costs, syncs beads, and checks mail after each turn. Codex has no
+// session start hook, so AGENTS.md tells the agent to prime itself. A
+// notify program the user configured is kept (see NotifyConflict).
+func EnsureSettingsForRole(workDir, role string) error {
+	if err := cursor.EnsureInstructionsFile(workDir, role, InstructionsFile); err != nil {
+		return err
+	}
+
+	script, err := renderScript(templates.ConfigVars{WorkDir: workDir, Role: role, GTBin: templates.GTBinary()})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(scriptPath(workDir)), 0755); err != nil {
+		return fmt.Errorf("creating hooks directory: %w", err)
+	}
+	if err := os.WriteFile(scriptPath(workDir), script, 0755); err != nil { //nolint:gosec // G306: the notify program must be executable
+		return fmt.Errorf("writing %s: %w", notifyScript, err)
+	}
+
+	path := configPath(workDir)
+	installed, err := os.ReadFile(path) //nolint:gosec // G304: path is within the agent workspace
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading config.toml: %w", err)
+	}
+	content, err := mergeConfig(installed, workDir)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(content, installed) {
+		return nil
+	}
+	if err := settingsbackup.WriteFile(path, content, 0644); err != nil { //nolint:gosec // G306: config is not sensitive
+		return fmt.Errorf("writing config.toml: %w", err)
+	}
+	return nil
+}
+
+// GeneratedSettings returns the Codex CLI settings files in workDir for
+// role with the content EnsureSettingsForRole would write: AGENTS.md (kept
+// if edited), the notify program, and config.toml with the notify setting
+// merged in.
+func GeneratedSettings(workDir, role string) ([]cursor.SettingsFile, error) {
+	instructions, err := cursor.InstructionsSettingsFile(workDir, role, InstructionsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	script, err := renderScript(templates.ConfigVars{WorkDir: workDir, Role: role, GTBin: templates.GTBinary()})
+	if err != nil {
+		return nil, err
+	}
+	installedScript, err := readIfExists(scriptPath(workDir))
+	if err != nil {
+		return nil, err
+	}
+
+	installedConfig, err := readIfExists(configPath(workDir))
+	if err != nil {
+		return nil, err
+	}
+	config, err := mergeConfig(installedConfig, workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return []cursor.SettingsFile{
+		instructions,
+		{Path: scriptPath(workDir), Installed: installedScript, Generated: script},
+		{Path: configPath(workDir), Installed: installedConfig, Generated: config},
+	}, nil
+}
+
+// readIfExists reads path, returning nil content if it does not exist.
+func readIfExists(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the agent workspace
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// renderScript renders the notify program with vars.
+func renderScript(vars templates.ConfigVars) ([]byte, error) {
+	raw, err := configFS.ReadFile("config/" + notifyScript)
+	if err != nil {
+		return nil, err
+	}
+	return templates.RenderConfig(notifyScript, raw, vars)
+}
+
+// notifyLine returns the notify setting that runs workDir's notify
+// program. Codex appends the event JSON as the last argument.
+func notifyLine(workDir string) string {
+	return "notify = [\"bash\", " + strconv.Quote(scriptPath(workDir)) + "]\n"
+}
+
+// configNotify returns the top-level notify program set in config, or nil.
+func configNotify(config []byte) ([]string, error) {
+	var cfg struct {
+		Notify []string `toml:"notify"`
+	}
+	if _, err := toml.Decode(string(config), &cfg); err != nil {
+		return nil, fmt.Errorf("existing config.toml is not valid TOML (fix or remove it): %w", err)
+	}
+	return cfg.Notify, nil
+}
+
+// isGastownNotify reports whether a notify program runs a Gas Town script.
+func isGastownNotify(notify []string) bool {
+	for _, arg := range notify {
+		if strings.HasSuffix(arg, "/.codex/hooks/"+notifyScript) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeConfig sets the Gas Town notify program in an installed
+// config.toml, keeping the rest of the file as written. A notify program
+// the user set is kept, since Codex runs only one. Returns installed
+// unchanged when the notify program is already current.
+func mergeConfig(installed []byte, workDir string) ([]byte, error) {
+	line := notifyLine(workDir)
+	notify, err := configNotify(installed)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case notify == nil:
+		// Top-level keys must precede the first table
+		return append([]byte(line), installed...), nil
+	case !isGastownNotify(notify):
+		return installed, nil
+	}
+	loc := notifyLinePattern.FindIndex(installed)
+	if loc == nil {
+		// A multi-line notify array gt did not write; leave it alone
+		return installed, nil
+	}
+	merged := append(append([]byte{}, installed[:loc[0]]...), line...)
+	return append(merged, installed[loc[1]:]...), nil
+}
+
+// NotifyConflict returns the notify program the user configured in
+// workDir's config.toml in place of Gas Town's, or "" if there is none.
+func NotifyConflict(workDir string) string {
+	data, err := os.ReadFile(configPath(workDir)) //nolint:gosec // G304: path is within the agent workspace
+	if err != nil {
+		return ""
+	}
+	notify, err := configNotify(data)
+	if err != nil || notify == nil || isGastownNotify(notify) {
+		return ""
+	}
+	return strings.Join(notify, " ")
+}
+
+// CheckSettings returns what differs between the Codex settings installed
+// in workDir and those generated for role: a missing or outdated AGENTS.md
+// or notify program, a config.toml that does not run the notify program,
+// or invalid TOML. AGENTS.md edited by the user is not reported.
+func CheckSettings(workDir, role string) ([]string, error) {
+	var problems []string
+	status, err := cursor.InstructionsStatus(workDir, role, InstructionsFile)
+	if err != nil {
+		return nil, err
+	}
+	if status == cursor.FileMissing || status == cursor.FileOutdated {
+		problems = append(problems, fmt.Sprintf("%s (%s)", InstructionsFile, status))
+	}
+
+	want, err := renderScript(templates.ConfigVars{WorkDir: workDir, Role: role, GTBin: templates.GTBinary()})
+	if err != nil {
+		return nil, err
+	}
+	got, err := os.ReadFile(scriptPath(workDir)) //nolint:gosec // G304: path is within the agent workspace
+	switch {
+	case os.IsNotExist(err):
+		problems = append(problems, notifyScript+" (missing)")
+	case err != nil:
+		return nil, err
+	case !bytes.Equal(got, want):
+		problems = append(problems, notifyScript+" (outdated)")
+	}
+
+	config, err := os.ReadFile(configPath(workDir)) //nolint:gosec // G304: path is within the agent workspace
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	notify, err := configNotify(config)
+	if err != nil {
+		return append(problems, "config.toml: "+err.Error()), nil
+	}
+	if isGastownNotify(notify) {
+		if merged, _ := mergeConfig(config, workDir); !bytes.Equal(merged, config) {
+			problems = append(problems, "config.toml notify (outdated)")
+		}
+	} else if notify == nil {
+		problems = append(problems, "config.toml notify (missing)")
+	}
+	return problems, nil
+}
+
+// RenderTemplates renders the Codex CLI config for role with vars, without
+// reading a workspace or town: AGENTS.md from the base and role rules,
+// config.toml and the notify program. Used to test templates against
+// synthetic workspaces (gt templates selftest).
+func RenderTemplates(vars templates.ConfigVars, role string) ([]cursor.RenderedFile, error) {
+	rules, err := cursor.ComposeRulesWith(vars, role, nil)
+	if err != nil {
+		return nil, err
+	}
+	script, err := renderScript(vars)
+	if err != nil {
+		return nil, err
+	}
+	return []cursor.RenderedFile{
+		{Name: InstructionsFile, Content: cursor.Instructions(rules)},
+		{Name: ".codex/config.toml", Content: []byte(notifyLine(vars.WorkDir))},
+		{Name: ".codex/hooks/" + notifyScript, Content: script},
+	}, nil
+}
@@ -0,0 +1,93 @@
+package codex
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnsureSettingsForRole(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureSettingsForRole(dir, "polecat"); err != nil {
+		t.Fatalf("EnsureSettingsForRole: %v", err)
+	}
+
+	instructions, err := os.ReadFile(filepath.Join(dir, InstructionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(instructions), "gt prime") {
+		t.Error("AGENTS.md should tell the agent to prime itself")
+	}
+	if !Installed(dir) {
+		t.Error("notify program not installed")
+	}
+	config, err := os.ReadFile(configPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(config) != notifyLine(dir) {
+		t.Errorf("config.toml = %q, want %q", config, notifyLine(dir))
+	}
+
+	problems, err := CheckSettings(dir, "polecat")
+	if err != nil || len(problems) > 0 {
+		t.Errorf("CheckSettings after install = %v, %v", problems, err)
+	}
+}
+
+func TestMergeConfig(t *testing.T) {
+	dir := "/town/rig/polecats"
+	line := notifyLine(dir)
+
+	user := "model = \"o3\"\n\n[tui]\nnotifications = true\n"
+	merged, err := mergeConfig([]byte(user), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(merged) != line+user {
+		t.Errorf("merge into user config = %q, want notify first and the rest kept", merged)
+	}
+
+	stale := "model = \"o3\"\nnotify = [\"bash\", \"/old/path/.codex/hooks/gastown-notify.sh\"]\n"
+	merged, err = mergeConfig([]byte(stale), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "model = \"o3\"\n" + line; string(merged) != want {
+		t.Errorf("stale notify = %q, want %q", merged, want)
+	}
+	if again, _ := mergeConfig(merged, dir); string(again) != string(merged) {
+		t.Errorf("merge is not idempotent: %q", again)
+	}
+
+	own := "notify = [\"terminal-notifier\", \"-title\", \"Codex\"]\n"
+	merged, err = mergeConfig([]byte(own), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(merged) != own {
+		t.Errorf("user notify program was replaced: %q", merged)
+	}
+
+	if _, err := mergeConfig([]byte("notify = ["), dir); err == nil {
+		t.Error("invalid config.toml should be an error")
+	}
+}
+
+func TestNotifyConflict(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureSettingsForRole(dir, "crew"); err != nil {
+		t.Fatal(err)
+	}
+	if got := NotifyConflict(dir); got != "" {
+		t.Errorf("NotifyConflict with gt's notify = %q, want none", got)
+	}
+	if err := os.WriteFile(configPath(dir), []byte("notify = [\"say\", \"done\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := NotifyConflict(dir); got != "say done" {
+		t.Errorf("NotifyConflict = %q, want the user's program", got)
+	}
+}
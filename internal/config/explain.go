@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ResolutionStep is one rule consulted while resolving a config value, in
+// the order the rules are tried. Used by 'gt why' to show how a value was
+// chosen.
+type ResolutionStep struct {
+	Rule    string `json:"rule"`              // What was consulted, e.g. "rig agent"
+	Source  string `json:"source,omitempty"`  // File or flag the rule reads
+	Result  string `json:"result"`            // What the rule found
+	Decided bool   `json:"decided,omitempty"` // This rule produced the value
+}
+
+// ExplainAgentConfig resolves the agent for a rig like
+// ResolveAgentConfigWithOverride and also returns every rule it consulted.
+// rigPath may be empty for town-level agents (mayor, deacon).
+func ExplainAgentConfig(townRoot, rigPath, agentOverride string) (*RuntimeConfig, string, []ResolutionStep, error) {
+	var steps []ResolutionStep
+	step := func(rule, source, result string, decided bool) {
+		steps = append(steps, ResolutionStep{Rule: rule, Source: source, Result: result, Decided: decided})
+	}
+
+	overrideResult := "not set"
+	if agentOverride != "" {
+		overrideResult = fmt.Sprintf("%q", agentOverride)
+	}
+	step("--agent override", "command line", overrideResult, false)
+
+	// Load rig settings
+	var rigSettings *RigSettings
+	rigSource := ""
+	if rigPath != "" {
+		rigSource = RigSettingsPath(rigPath)
+		var err error
+		if rigSettings, err = LoadRigSettings(rigSource); err != nil {
+			rigSettings = nil
+			if !errors.Is(err, ErrNotFound) {
+				step("rig settings", rigSource, "unreadable, ignored: "+err.Error(), false)
+			}
+		}
+	}
+
+	// Backwards compatibility: if Runtime is set directly, use it
+	notSet := "not set"
+	if rigPath == "" {
+		notSet = "no rig"
+	}
+	switch {
+	case rigSettings == nil || rigSettings.Runtime == nil:
+		step("rig runtime (deprecated)", rigSource, notSet, false)
+	case agentOverride != "":
+		step("rig runtime (deprecated)", rigSource, "set, but the override wins", false)
+	default:
+		step("rig runtime (deprecated)", rigSource, fmt.Sprintf("command %q", rigSettings.Runtime.Command), true)
+		return fillRuntimeDefaults(rigSettings.Runtime), "", steps, nil
+	}
+
+	// Load town settings for agent lookup
+	townSource := TownSettingsPath(townRoot)
+	townSettings, err := LoadOrCreateTownSettings(townSource)
+	if err != nil {
+		step("town settings", townSource, "unreadable, using defaults: "+err.Error(), false)
+		townSettings = NewTownSettings()
+	}
+
+	// Load custom agent registry if it exists
+	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+
+	// Determine which agent name to use
+	agentName := ""
+	rigAgent := notSet
+	if rigSettings != nil && rigSettings.Agent != "" {
+		rigAgent = fmt.Sprintf("%q", rigSettings.Agent)
+	}
+	townDefault := "not set"
+	if townSettings.DefaultAgent != "" {
+		townDefault = fmt.Sprintf("%q", townSettings.DefaultAgent)
+	}
+	switch {
+	case agentOverride != "":
+		agentName = agentOverride
+		steps[0].Decided = true
+		step("rig agent", rigSource, rigAgent, false)
+		step("town default_agent", townSource, townDefault, false)
+	case rigSettings != nil && rigSettings.Agent != "":
+		agentName = rigSettings.Agent
+		step("rig agent", rigSource, rigAgent, true)
+	case townSettings.DefaultAgent != "":
+		agentName = townSettings.DefaultAgent
+		step("rig agent", rigSource, rigAgent, false)
+		step("town default_agent", townSource, townDefault, true)
+	default:
+		agentName = "cursor" // ultimate fallback
+		step("rig agent", rigSource, rigAgent, false)
+		step("town default_agent", townSource, townDefault, false)
+		step("built-in default", "", `"cursor"`, true)
+	}
+
+	// Look up the agent configuration: town custom agents, then presets
+	if townSettings.Agents != nil {
+		if custom, ok := townSettings.Agents[agentName]; ok && custom != nil {
+			rc := fillRuntimeDefaults(custom)
+			step("town custom agent", townSource, fmt.Sprintf("%q runs %q", agentName, rc.Command), true)
+			return rc, agentName, steps, nil
+		}
+	}
+	step("town custom agent", townSource, fmt.Sprintf("no entry for %q", agentName), false)
+
+	presetSource := DefaultAgentRegistryPath(townRoot) // extends the built-in presets
+	if preset := GetAgentPresetByName(agentName); preset != nil {
+		rc := RuntimeConfigFromPreset(AgentPreset(agentName))
+		step("agent preset", presetSource, fmt.Sprintf("%q runs %q", agentName, rc.Command), true)
+		return rc, agentName, steps, nil
+	}
+	step("agent preset", presetSource, fmt.Sprintf("no preset %q", agentName), false)
+
+	// An explicit override must exist; otherwise fall back to cursor defaults
+	if agentOverride != "" {
+		return nil, "", steps, fmt.Errorf("agent '%s' not found", agentName)
+	}
+	rc := DefaultRuntimeConfig()
+	step("cursor defaults", "", fmt.Sprintf("runs %q", rc.Command), true)
+	return rc, agentName, steps, nil
+}
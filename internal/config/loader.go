@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/mail"
 	"os"
 	"path/filepath"
 	"sort"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/secrets"
 )
 
 var (
@@ -211,6 +213,159 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Tracker != nil {
+		if err := validateTrackerConfig(c.Tracker); err != nil {
+			return err
+		}
+	}
+	if c.Upstream != nil {
+		if err := validateUpstreamConfig(c.Upstream); err != nil {
+			return err
+		}
+	}
+	return ValidateCostCenter(c.CostCenter)
+}
+
+// ErrInvalidCostCenter indicates a malformed cost center tag.
+var ErrInvalidCostCenter = errors.New("invalid cost center")
+
+// ValidateCostCenter checks that a cost center tag is empty or a plain name
+// (letters, digits, '.', '_', '-'), since it names files in closed ledgers.
+func ValidateCostCenter(name string) error {
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case i > 0 && (r == '.' || r == '_' || r == '-'):
+		default:
+			return fmt.Errorf("%w: %q (use letters, digits, '.', '_', '-')", ErrInvalidCostCenter, name)
+		}
+	}
+	return nil
+}
+
+// ErrInvalidUpdateChannel indicates an unknown self_update.channel.
+var ErrInvalidUpdateChannel = errors.New("invalid update channel")
+
+// ErrInvalidUpstream indicates an invalid upstream configuration.
+var ErrInvalidUpstream = errors.New("invalid upstream config")
+
+// validateUpstreamConfig validates an UpstreamConfig.
+func validateUpstreamConfig(c *UpstreamConfig) error {
+	if c.URL == "" {
+		return fmt.Errorf("%w: url is required", ErrInvalidUpstream)
+	}
+	if c.Interval != "" {
+		if d, err := time.ParseDuration(c.Interval); err != nil || d < 0 {
+			return fmt.Errorf("%w: invalid interval '%s'", ErrInvalidUpstream, c.Interval)
+		}
+	}
+	return nil
+}
+
+// ErrInvalidTracker indicates an invalid issue tracker configuration.
+var ErrInvalidTracker = errors.New("invalid tracker config")
+
+// validateTrackerConfig validates a TrackerConfig.
+func validateTrackerConfig(c *TrackerConfig) error {
+	if c.Type != TrackerLinear {
+		return fmt.Errorf("%w: unsupported type '%s', want '%s'", ErrInvalidTracker, c.Type, TrackerLinear)
+	}
+	if c.Team == "" {
+		return fmt.Errorf("%w: team is required", ErrInvalidTracker)
+	}
+	if c.APIKey == "" {
+		return fmt.Errorf("%w: api_key is required", ErrInvalidTracker)
+	}
+	return nil
+}
+
+// ErrInvalidEmailBridge indicates an invalid email bridge configuration.
+var ErrInvalidEmailBridge = errors.New("invalid email_bridge config")
+
+// validateEmailBridgeConfig validates an EmailBridgeConfig.
+func validateEmailBridgeConfig(c *EmailBridgeConfig) error {
+	if c.Operator == "" {
+		return fmt.Errorf("%w: operator is required", ErrInvalidEmailBridge)
+	}
+	if c.From == "" {
+		return fmt.Errorf("%w: from is required", ErrInvalidEmailBridge)
+	}
+	for _, addr := range []string{c.Operator, c.From, c.ReplyTo} {
+		if addr == "" {
+			continue
+		}
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("%w: invalid address %q", ErrInvalidEmailBridge, addr)
+		}
+	}
+	for _, server := range []struct {
+		name string
+		cfg  EmailServerConfig
+	}{{"smtp", c.SMTP}, {"imap", c.IMAP}} {
+		if server.cfg.Host == "" {
+			return fmt.Errorf("%w: %s.host is required", ErrInvalidEmailBridge, server.name)
+		}
+		if server.cfg.Port < 0 || server.cfg.Port > 65535 {
+			return fmt.Errorf("%w: %s.port %d out of range", ErrInvalidEmailBridge, server.name, server.cfg.Port)
+		}
+	}
+	return nil
+}
+
+// ErrInvalidCostReconcile indicates an invalid cost_reconcile configuration.
+var ErrInvalidCostReconcile = errors.New("invalid cost_reconcile config")
+
+// validateCostReconcileConfig validates a CostReconcileConfig.
+func validateCostReconcileConfig(c *CostReconcileConfig) error {
+	if c.DriftThresholdPct < 0 {
+		return fmt.Errorf("%w: drift_threshold_pct must not be negative", ErrInvalidCostReconcile)
+	}
+	seen := make(map[string]bool)
+	for i, p := range c.Providers {
+		if p.Type != BillingAnthropic && p.Type != BillingOpenAI {
+			return fmt.Errorf("%w: providers[%d]: unsupported type '%s', want '%s' or '%s'",
+				ErrInvalidCostReconcile, i, p.Type, BillingAnthropic, BillingOpenAI)
+		}
+		if seen[p.Type] {
+			return fmt.Errorf("%w: providers[%d]: duplicate provider '%s'", ErrInvalidCostReconcile, i, p.Type)
+		}
+		seen[p.Type] = true
+		if p.APIKey == "" {
+			return fmt.Errorf("%w: providers[%d]: api_key is required", ErrInvalidCostReconcile, i)
+		}
+	}
+	return nil
+}
+
+// ErrInvalidCostAlerts indicates an invalid cost_alerts section in town settings.
+var ErrInvalidCostAlerts = errors.New("invalid cost_alerts config")
+
+// ValidateCostAlerts validates a CostAlertsConfig. Thresholds may be listed
+// in any order (AlertThresholds sorts them) but must be positive and
+// distinct. Safe on nil.
+func ValidateCostAlerts(c *CostAlertsConfig) error {
+	if c == nil {
+		return nil
+	}
+	if c.DailyBudgetUSD < 0 || c.WeeklyBudgetUSD < 0 || c.SessionLimitUSD < 0 || c.AnomalyFactor < 0 {
+		return fmt.Errorf("%w: budgets, session_limit_usd and anomaly_factor must not be negative", ErrInvalidCostAlerts)
+	}
+	if c.AnomalyFactor > 0 && c.AnomalyFactor <= 1 {
+		return fmt.Errorf("%w: anomaly_factor must be greater than 1, got %g", ErrInvalidCostAlerts, c.AnomalyFactor)
+	}
+	seen := make(map[float64]bool)
+	for i, t := range c.Thresholds {
+		if t <= 0 {
+			return fmt.Errorf("%w: thresholds[%d]: must be positive, got %g", ErrInvalidCostAlerts, i, t)
+		}
+		if seen[t] {
+			return fmt.Errorf("%w: thresholds[%d]: duplicate threshold %g", ErrInvalidCostAlerts, i, t)
+		}
+		seen[t] = true
+	}
+	if c.RecoveryMargin < 0 || c.RecoveryMargin >= 1 {
+		return fmt.Errorf("%w: recovery_margin must be at least 0 and below 1, got %g", ErrInvalidCostAlerts, c.RecoveryMargin)
+	}
 	return nil
 }
 
@@ -232,6 +387,13 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 		}
 	}
 
+	// Validate require_green_run if specified
+	if c.RequireGreenRun != "" {
+		if _, err := time.ParseDuration(c.RequireGreenRun); err != nil {
+			return fmt.Errorf("invalid require_green_run: %w", err)
+		}
+	}
+
 	// Validate non-negative values
 	if c.RetryFlakyTests < 0 {
 		return fmt.Errorf("%w: retry_flaky_tests must be non-negative", ErrMissingField)
@@ -654,6 +816,12 @@ func validateMessagingConfig(c *MessagingConfig) error {
 	if c.NudgeChannels == nil {
 		c.NudgeChannels = make(map[string][]string)
 	}
+	if c.Away == nil {
+		c.Away = make(map[string]AwayConfig)
+	}
+	if c.Filters == nil {
+		c.Filters = make(map[string]MailFilterConfig)
+	}
 
 	// Validate lists have at least one recipient
 	for name, recipients := range c.Lists {
@@ -692,9 +860,42 @@ func validateMessagingConfig(c *MessagingConfig) error {
 		}
 	}
 
+	// Validate away entries don't divert urgent mail back to themselves
+	for address, away := range c.Away {
+		if address == "" {
+			return fmt.Errorf("%w: away address cannot be empty", ErrMissingField)
+		}
+		if away.Fallback != "" && strings.TrimSuffix(away.Fallback, "/") == strings.TrimSuffix(address, "/") {
+			return fmt.Errorf("%w: away '%s' fallback is itself", ErrMissingField, address)
+		}
+	}
+
+	// Validate filters name a role and known message types
+	for role, filter := range c.Filters {
+		if !mailFilterRoles[role] {
+			return fmt.Errorf("%w: unknown role '%s' (want polecat, crew, witness, refinery, mayor, or deacon)", ErrInvalidMailFilter, role)
+		}
+		for _, t := range filter.Types {
+			if !mailFilterTypes[t] {
+				return fmt.Errorf("%w: role '%s' type '%s' (want task, scavenge, notification, or reply)", ErrInvalidMailFilter, role, t)
+			}
+		}
+	}
+
 	return nil
 }
 
+// ErrInvalidMailFilter indicates a messaging filter names an unknown role or type.
+var ErrInvalidMailFilter = errors.New("invalid mail filter")
+
+var mailFilterRoles = map[string]bool{
+	"polecat": true, "crew": true, "witness": true, "refinery": true, "mayor": true, "deacon": true,
+}
+
+var mailFilterTypes = map[string]bool{
+	"task": true, "scavenge": true, "notification": true, "reply": true,
+}
+
 // MessagingConfigPath returns the standard path for messaging config in a town.
 func MessagingConfigPath(townRoot string) string {
 	return filepath.Join(townRoot, "config", "messaging.json")
@@ -748,7 +949,23 @@ func RigSettingsPath(rigPath string) string {
 }
 
 // LoadOrCreateTownSettings loads town settings or creates defaults if missing.
+// The machine profile selected for this machine (see SelectMachineProfile)
+// is merged over the file; commands that edit and save settings load them
+// with LoadOrCreateBaseTownSettings instead, so the overlay is not saved.
 func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
+	sel, err := SelectMachineProfile(filepath.Dir(filepath.Dir(path)))
+	if err != nil {
+		return nil, err
+	}
+	if sel.Path != "" {
+		return loadTownSettingsWithProfile(path, sel)
+	}
+	return LoadOrCreateBaseTownSettings(path)
+}
+
+// LoadOrCreateBaseTownSettings loads town settings as written in the file,
+// without any machine profile, or creates defaults if missing.
+func LoadOrCreateBaseTownSettings(path string) (*TownSettings, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -766,11 +983,8 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 
 // SaveTownSettings saves town settings to a file.
 func SaveTownSettings(path string, settings *TownSettings) error {
-	if settings.Type != "town-settings" && settings.Type != "" {
-		return fmt.Errorf("%w: expected type 'town-settings', got '%s'", ErrInvalidType, settings.Type)
-	}
-	if settings.Version > CurrentTownSettingsVersion {
-		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, settings.Version, CurrentTownSettingsVersion)
+	if err := validateTownSettings(settings); err != nil {
+		return err
 	}
 
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
@@ -789,6 +1003,32 @@ func SaveTownSettings(path string, settings *TownSettings) error {
 	return nil
 }
 
+func validateTownSettings(c *TownSettings) error {
+	if c.Type != "town-settings" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'town-settings', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentTownSettingsVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentTownSettingsVersion)
+	}
+	if ch := c.SelfUpdate.UpdateChannel(); ch != "stable" && ch != "prerelease" {
+		return fmt.Errorf("%w: %q (use \"stable\" or \"prerelease\")", ErrInvalidUpdateChannel, ch)
+	}
+	if c.EmailBridge != nil {
+		if err := validateEmailBridgeConfig(c.EmailBridge); err != nil {
+			return err
+		}
+	}
+	if c.CostReconcile != nil {
+		if err := validateCostReconcileConfig(c.CostReconcile); err != nil {
+			return err
+		}
+	}
+	if err := ValidateCostAlerts(c.CostAlerts); err != nil {
+		return err
+	}
+	return ValidateCostCenter(c.CostCenter)
+}
+
 // ResolveAgentConfig resolves the agent configuration for a rig.
 // It looks up the agent by name in town settings (custom agents) and built-in presets.
 //
@@ -843,54 +1083,8 @@ func ResolveAgentConfig(townRoot, rigPath string) *RuntimeConfig {
 // Returns the resolved RuntimeConfig, the selected agent name, and an error if the override name
 // does not exist in town custom agents or built-in presets.
 func ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride string) (*RuntimeConfig, string, error) {
-	// Load rig settings
-	rigSettings, err := LoadRigSettings(RigSettingsPath(rigPath))
-	if err != nil {
-		rigSettings = nil
-	}
-
-	// Backwards compatibility: if Runtime is set directly, use it (but still report agentOverride if present)
-	if rigSettings != nil && rigSettings.Runtime != nil && agentOverride == "" {
-		rc := rigSettings.Runtime
-		return fillRuntimeDefaults(rc), "", nil
-	}
-
-	// Load town settings for agent lookup
-	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
-	if err != nil {
-		townSettings = NewTownSettings()
-	}
-
-	// Load custom agent registry if it exists
-	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
-
-	// Determine which agent name to use
-	agentName := ""
-	if agentOverride != "" {
-		agentName = agentOverride
-	} else if rigSettings != nil && rigSettings.Agent != "" {
-		agentName = rigSettings.Agent
-	} else if townSettings.DefaultAgent != "" {
-		agentName = townSettings.DefaultAgent
-	} else {
-		agentName = "cursor" // ultimate fallback
-	}
-
-	// If an override is requested, validate it exists.
-	if agentOverride != "" {
-		if townSettings.Agents != nil {
-			if custom, ok := townSettings.Agents[agentName]; ok && custom != nil {
-				return fillRuntimeDefaults(custom), agentName, nil
-			}
-		}
-		if preset := GetAgentPresetByName(agentName); preset != nil {
-			return RuntimeConfigFromPreset(AgentPreset(agentName)), agentName, nil
-		}
-		return nil, "", fmt.Errorf("agent '%s' not found", agentName)
-	}
-
-	// Normal lookup path (no override)
-	return lookupAgentConfig(agentName, townSettings), agentName, nil
+	rc, agentName, _, err := ExplainAgentConfig(townRoot, rigPath, agentOverride)
+	return rc, agentName, err
 }
 
 // lookupAgentConfig looks up an agent by name.
@@ -1044,13 +1238,15 @@ func findTownRootFromCwd() (string, error) {
 // prompt is optional - if provided, appended as the initial prompt.
 func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) string {
 	var rc *RuntimeConfig
+	var townRoot string
 	if rigPath != "" {
 		// Derive town root from rig path
-		townRoot := filepath.Dir(rigPath)
+		townRoot = filepath.Dir(rigPath)
 		rc = ResolveAgentConfig(townRoot, rigPath)
 	} else {
 		// Try to detect town root from cwd for town-level agents (mayor, deacon)
-		townRoot, err := findTownRootFromCwd()
+		var err error
+		townRoot, err = findTownRootFromCwd()
 		if err != nil {
 			rc = DefaultRuntimeConfig()
 		} else {
@@ -1059,13 +1255,7 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	}
 
 	// Build environment export prefix
-	var exports []string
-	for k, v := range envVars {
-		exports = append(exports, fmt.Sprintf("%s=%s", k, v))
-	}
-
-	// Sort for deterministic output
-	sort.Strings(exports)
+	exports := buildEnvExports(envVars, townRoot, rigPath)
 
 	var cmd string
 	if len(exports) > 0 {
@@ -1082,20 +1272,75 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	return cmd
 }
 
+// ResolveSessionEnv returns the settings env for agent sessions: town
+// settings env overlaid with rig settings env. Values are returned as
+// configured; secret references are not resolved.
+func ResolveSessionEnv(townRoot, rigPath string) map[string]string {
+	env := make(map[string]string)
+	if townRoot != "" {
+		if ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot)); err == nil {
+			for k, v := range ts.Env {
+				env[k] = v
+			}
+		}
+	}
+	if rigPath != "" {
+		if rs, err := LoadRigSettings(RigSettingsPath(rigPath)); err == nil {
+			for k, v := range rs.Env {
+				env[k] = v
+			}
+		}
+	}
+	return env
+}
+
+// buildEnvExports returns sorted NAME=value export assignments for a startup
+// command. Settings env is included beneath envVars, which always win.
+// Secret references are emitted as a "gt secret resolve" substitution so the
+// secret is resolved inside the session at spawn and never appears in the
+// command line or pane scrollback.
+func buildEnvExports(envVars map[string]string, townRoot, rigPath string) []string {
+	var exports []string
+	for k, v := range ResolveSessionEnv(townRoot, rigPath) {
+		if _, ok := envVars[k]; ok {
+			continue
+		}
+		if secrets.IsRef(v) {
+			exports = append(exports, fmt.Sprintf("%s=\"$(gt secret resolve %s)\"", k, shellQuote(v)))
+		} else {
+			exports = append(exports, fmt.Sprintf("%s=%s", k, shellQuote(v)))
+		}
+	}
+	for k, v := range envVars {
+		exports = append(exports, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	// Sort for deterministic output
+	sort.Strings(exports)
+	return exports
+}
+
+// shellQuote single-quotes s for safe use in a POSIX shell command.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // BuildStartupCommandWithAgentOverride builds a startup command like BuildStartupCommand,
 // but uses agentOverride if non-empty.
 func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, prompt, agentOverride string) (string, error) {
 	var rc *RuntimeConfig
+	var townRoot string
 
 	if rigPath != "" {
-		townRoot := filepath.Dir(rigPath)
+		townRoot = filepath.Dir(rigPath)
 		var err error
 		rc, _, err = ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride)
 		if err != nil {
 			return "", err
 		}
 	} else {
-		townRoot, err := findTownRootFromCwd()
+		var err error
+		townRoot, err = findTownRootFromCwd()
 		if err != nil {
 			rc = DefaultRuntimeConfig()
 		} else {
@@ -1108,11 +1353,7 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 	}
 
 	// Build environment export prefix
-	var exports []string
-	for k, v := range envVars {
-		exports = append(exports, fmt.Sprintf("%s=%s", k, v))
-	}
-	sort.Strings(exports)
+	exports := buildEnvExports(envVars, townRoot, rigPath)
 
 	var cmd string
 	if len(exports) > 0 {
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -310,6 +312,89 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid require_green_run",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				MergeQueue: &MergeQueueConfig{
+					RequireGreenRun: "a day",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid linear tracker",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Tracker: &TrackerConfig{Type: TrackerLinear, Team: "ENG", APIKey: "secretRef:env:LINEAR_API_KEY"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid cost center",
+			settings: &RigSettings{
+				Type:       "rig-settings",
+				Version:    1,
+				CostCenter: "eng-platform.2025",
+			},
+			wantErr: false,
+		},
+		{
+			name: "cost center with a path separator",
+			settings: &RigSettings{
+				Type:       "rig-settings",
+				Version:    1,
+				CostCenter: "eng/platform",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported tracker type",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Tracker: &TrackerConfig{Type: "jira", Team: "ENG", APIKey: "key"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tracker without api key",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Tracker: &TrackerConfig{Type: TrackerLinear, Team: "ENG"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid upstream",
+			settings: &RigSettings{
+				Type:     "rig-settings",
+				Version:  1,
+				Upstream: &UpstreamConfig{URL: "https://github.com/original/project.git", Interval: "2h"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "upstream without url",
+			settings: &RigSettings{
+				Type:     "rig-settings",
+				Version:  1,
+				Upstream: &UpstreamConfig{Branch: "main"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "upstream with bad interval",
+			settings: &RigSettings{
+				Type:     "rig-settings",
+				Version:  1,
+				Upstream: &UpstreamConfig{URL: "u", Interval: "daily"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -703,6 +788,36 @@ func TestMessagingConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid config with filters",
+			config: &MessagingConfig{
+				Version: 1,
+				Filters: map[string]MailFilterConfig{
+					"polecat": {Types: []string{"task", "reply"}, From: []string{"*/witness"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "filter for unknown role",
+			config: &MessagingConfig{
+				Version: 1,
+				Filters: map[string]MailFilterConfig{
+					"polecats": {Types: []string{"task"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "filter with unknown type",
+			config: &MessagingConfig{
+				Version: 1,
+				Filters: map[string]MailFilterConfig{
+					"polecat": {Types: []string{"assignment"}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1143,6 +1258,41 @@ func TestBuildStartupCommand_UsesRigAgentWhenRigPathProvided(t *testing.T) {
 	}
 }
 
+func TestBuildStartupCommand_SettingsEnv(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	townSettings := NewTownSettings()
+	townSettings.Env = map[string]string{
+		"ANTHROPIC_API_KEY": "secretRef:op:op://Dev/Anthropic/credential",
+		"REGION":            "us-east-1",
+		"GT_ROLE":           "ignored",
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	rigSettings := NewRigSettings()
+	rigSettings.Env = map[string]string{"REGION": "eu-west-1"}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": "witness"}, rigPath, "")
+	for _, want := range []string{
+		`ANTHROPIC_API_KEY="$(gt secret resolve 'secretRef:op:op://Dev/Anthropic/credential')"`,
+		"REGION='eu-west-1'",
+		"GT_ROLE=witness",
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("expected %q in command: %q", want, cmd)
+		}
+	}
+	if strings.Contains(cmd, "ignored") {
+		t.Errorf("caller env should override settings env: %q", cmd)
+	}
+}
+
 func TestGetRuntimeCommand_UsesRigAgentWhenRigPathProvided(t *testing.T) {
 	townRoot := t.TempDir()
 	rigPath := filepath.Join(townRoot, "testrig")
@@ -1558,3 +1708,78 @@ func TestSaveTownSettings(t *testing.T) {
 		}
 	})
 }
+
+func TestExplainAgentConfig(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	townSettings := NewTownSettings()
+	townSettings.DefaultAgent = "gemini"
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	decided := func(steps []ResolutionStep) []string {
+		var rules []string
+		for _, s := range steps {
+			if s.Decided {
+				rules = append(rules, s.Rule)
+			}
+		}
+		return rules
+	}
+
+	t.Run("town default without rig settings", func(t *testing.T) {
+		rc, name, steps, err := ExplainAgentConfig(townRoot, rigPath, "")
+		if err != nil {
+			t.Fatalf("ExplainAgentConfig: %v", err)
+		}
+		if name != "gemini" || rc.Command != "gemini" {
+			t.Fatalf("got %q running %q, want gemini", name, rc.Command)
+		}
+		got := decided(steps)
+		if len(got) != 2 || got[0] != "town default_agent" || got[1] != "agent preset" {
+			t.Errorf("decided rules = %v, want town default_agent then agent preset", got)
+		}
+		for _, s := range steps {
+			if s.Rule == "town default_agent" && s.Source != TownSettingsPath(townRoot) {
+				t.Errorf("town default_agent source = %q", s.Source)
+			}
+		}
+	})
+
+	t.Run("unknown override records the failed lookups", func(t *testing.T) {
+		_, _, steps, err := ExplainAgentConfig(townRoot, rigPath, "nope-not-an-agent")
+		if err == nil {
+			t.Fatal("expected error for unknown agent override")
+		}
+		if last := steps[len(steps)-1]; last.Rule != "agent preset" || last.Decided {
+			t.Errorf("last step = %+v, want undecided agent preset lookup", last)
+		}
+	})
+}
+
+func TestValidateCostAlerts(t *testing.T) {
+	valid := &CostAlertsConfig{DailyBudgetUSD: 50, AnomalyFactor: 3, Thresholds: []float64{1.0, 0.5, 0.8}, RecoveryMargin: 0.1}
+	if err := ValidateCostAlerts(valid); err != nil {
+		t.Errorf("ValidateCostAlerts(valid) = %v", err)
+	}
+	if got := valid.AlertThresholds(); !slices.Equal(got, []float64{0.5, 0.8, 1.0}) {
+		t.Errorf("AlertThresholds() = %v, want ascending", got)
+	}
+	if valid.Thresholds[0] != 1.0 {
+		t.Error("AlertThresholds() reordered the configured thresholds")
+	}
+
+	for _, c := range []*CostAlertsConfig{
+		{DailyBudgetUSD: -1},
+		{AnomalyFactor: 0.5},
+		{Thresholds: []float64{0.8, 0}},
+		{Thresholds: []float64{0.8, 0.8}},
+		{RecoveryMargin: 1},
+	} {
+		if err := ValidateCostAlerts(c); !errors.Is(err, ErrInvalidCostAlerts) {
+			t.Errorf("ValidateCostAlerts(%+v) = %v, want ErrInvalidCostAlerts", c, err)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ProfileEnv names the environment variable that selects a machine profile,
+// overriding hostname matching. "none" disables profiles.
+const ProfileEnv = "GASTOWN_PROFILE"
+
+// ErrProfileNotFound is returned when GASTOWN_PROFILE names a profile that
+// does not exist.
+var ErrProfileNotFound = errors.New("machine profile not found")
+
+// hostname is swapped out in tests.
+var hostname = os.Hostname
+
+// MachineProfile is a machine-level overlay on town settings, stored in
+// settings/profiles/<name>.json. It lets one synced town run with higher
+// limits on a desktop and conservative ones on a laptop.
+type MachineProfile struct {
+	// Hosts are hostnames (or globs such as "*-laptop") this profile is
+	// selected on. A profile named after the short hostname always matches.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// Settings is merged over settings/config.json: objects merge key by
+	// key, any other value replaces the town's.
+	// Example: {"rig_defaults": {"max_polecats": 20}}
+	Settings json.RawMessage `json:"settings,omitempty"`
+}
+
+// ProfileSelection records which machine profile applies and why.
+type ProfileSelection struct {
+	Name   string // profile name, empty if none applies
+	Path   string // profile file
+	Reason string // e.g. "GASTOWN_PROFILE=laptop" or "hostname studio"
+	Host   string // short hostname of this machine
+
+	// Overrides lists the settings the profile changes, as dotted keys.
+	Overrides []string
+}
+
+// MachineProfilesDir returns the directory holding a town's machine profiles.
+func MachineProfilesDir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "profiles")
+}
+
+// SelectMachineProfile picks the machine profile for this machine:
+// GASTOWN_PROFILE if set, otherwise the first profile (by name) matching
+// the short hostname. Returns a selection with an empty Name when none
+// applies.
+func SelectMachineProfile(townRoot string) (*ProfileSelection, error) {
+	sel := &ProfileSelection{}
+	if h, err := hostname(); err == nil {
+		sel.Host, _, _ = strings.Cut(h, ".")
+	}
+
+	dir := MachineProfilesDir(townRoot)
+	if name := os.Getenv(ProfileEnv); name != "" {
+		if name == "none" {
+			sel.Reason = ProfileEnv + "=none"
+			return sel, nil
+		}
+		path := filepath.Join(dir, name+".json")
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("%w: %s=%s (no %s)", ErrProfileNotFound, ProfileEnv, name, path)
+		}
+		sel.Name, sel.Path, sel.Reason = name, path, ProfileEnv+"="+name
+		return sel, nil
+	}
+
+	if sel.Host == "" {
+		return sel, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return sel, nil
+		}
+		return nil, err
+	}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		profile, err := LoadMachineProfile(path)
+		if err != nil {
+			return nil, err
+		}
+		if name == sel.Host || profile.matchesHost(sel.Host) {
+			sel.Name, sel.Path, sel.Reason = name, path, "hostname "+sel.Host
+			return sel, nil
+		}
+	}
+	return sel, nil
+}
+
+// LoadMachineProfile loads a machine profile file.
+func LoadMachineProfile(path string) (*MachineProfile, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town settings
+	if err != nil {
+		return nil, err
+	}
+	var profile MachineProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("machine profile %s: %w", path, err)
+	}
+	return &profile, nil
+}
+
+func (p *MachineProfile) matchesHost(host string) bool {
+	return slices.ContainsFunc(p.Hosts, func(pattern string) bool {
+		ok, err := filepath.Match(pattern, host)
+		return err == nil && ok
+	})
+}
+
+// LoadEffectiveTownSettings loads a town's settings with its machine
+// profile applied, and reports which profile that was.
+func LoadEffectiveTownSettings(townRoot string) (*TownSettings, *ProfileSelection, error) {
+	sel, err := SelectMachineProfile(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	settings, err := loadTownSettingsWithProfile(TownSettingsPath(townRoot), sel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return settings, sel, nil
+}
+
+// loadTownSettingsWithProfile loads town settings from path and merges the
+// selected profile over them, filling sel.Overrides.
+func loadTownSettingsWithProfile(path string, sel *ProfileSelection) (*TownSettings, error) {
+	base := map[string]any{}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &base); err != nil {
+			return nil, err
+		}
+	case os.IsNotExist(err):
+		if data, err = json.Marshal(NewTownSettings()); err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(data, &base)
+	default:
+		return nil, err
+	}
+
+	if sel != nil && sel.Path != "" {
+		profile, err := LoadMachineProfile(sel.Path)
+		if err != nil {
+			return nil, err
+		}
+		if len(profile.Settings) > 0 {
+			var overlay map[string]any
+			if err := json.Unmarshal(profile.Settings, &overlay); err != nil {
+				return nil, fmt.Errorf("machine profile %s: settings: %w", sel.Path, err)
+			}
+			sel.Overrides = mergeSettings(base, overlay, "")
+			slices.Sort(sel.Overrides)
+		}
+	}
+
+	merged, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+	var settings TownSettings
+	if err := json.Unmarshal(merged, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// mergeSettings merges overlay into base, recursing into objects present
+// in both, and returns the dotted keys it set.
+func mergeSettings(base, overlay map[string]any, prefix string) []string {
+	var changed []string
+	for key, val := range overlay {
+		if sub, ok := val.(map[string]any); ok {
+			if existing, ok := base[key].(map[string]any); ok {
+				changed = append(changed, mergeSettings(existing, sub, prefix+key+".")...)
+				continue
+			}
+		}
+		base[key] = val
+		changed = append(changed, prefix+key)
+	}
+	return changed
+}
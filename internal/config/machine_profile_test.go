@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMachineProfiles(t *testing.T) {
+	townRoot := t.TempDir()
+	hostname = func() (string, error) { return "dev-laptop.local", nil }
+	t.Cleanup(func() { hostname = os.Hostname })
+	t.Setenv(ProfileEnv, "")
+
+	path := TownSettingsPath(townRoot)
+	writeTestFile(t, path, `{"type": "town-settings", "version": 1, "default_agent": "cursor", "cost_center": "eng", "rig_defaults": {"max_polecats": 8, "dnd": false}}`)
+	profiles := MachineProfilesDir(townRoot)
+	writeTestFile(t, filepath.Join(profiles, "desktop.json"), `{"hosts": ["studio"], "settings": {"rig_defaults": {"max_polecats": 20}}}`)
+	writeTestFile(t, filepath.Join(profiles, "laptop.json"), `{"hosts": ["*-laptop"], "settings": {"rig_defaults": {"max_polecats": 2}, "default_agent": "codex"}}`)
+
+	// Selected by hostname glob
+	settings, sel, err := LoadEffectiveTownSettings(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sel.Name != "laptop" || sel.Host != "dev-laptop" {
+		t.Fatalf("selection = %+v, want laptop on dev-laptop", sel)
+	}
+	if want := []string{"default_agent", "rig_defaults.max_polecats"}; !slices.Equal(sel.Overrides, want) {
+		t.Errorf("overrides = %v, want %v", sel.Overrides, want)
+	}
+	if settings.DefaultAgent != "codex" || settings.CostCenter != "eng" {
+		t.Errorf("settings = %+v", settings)
+	}
+	if settings.RigDefaults["max_polecats"] != 2.0 || settings.RigDefaults["dnd"] != false {
+		t.Errorf("rig defaults = %v, want the profile merged over the town's", settings.RigDefaults)
+	}
+	if loaded, err := LoadOrCreateTownSettings(path); err != nil || loaded.DefaultAgent != "codex" {
+		t.Errorf("LoadOrCreateTownSettings should apply the profile: %+v, %v", loaded, err)
+	}
+	if base, err := LoadOrCreateBaseTownSettings(path); err != nil || base.DefaultAgent != "cursor" {
+		t.Errorf("LoadOrCreateBaseTownSettings should not: %+v, %v", base, err)
+	}
+
+	// GASTOWN_PROFILE wins over the hostname
+	t.Setenv(ProfileEnv, "desktop")
+	if settings, sel, err = LoadEffectiveTownSettings(townRoot); err != nil || sel.Name != "desktop" || settings.RigDefaults["max_polecats"] != 20.0 {
+		t.Errorf("GASTOWN_PROFILE=desktop: %+v, %v", sel, err)
+	}
+	t.Setenv(ProfileEnv, "none")
+	if settings, sel, err = LoadEffectiveTownSettings(townRoot); err != nil || sel.Name != "" || settings.DefaultAgent != "cursor" {
+		t.Errorf("GASTOWN_PROFILE=none: %+v, %v", sel, err)
+	}
+	t.Setenv(ProfileEnv, "missing")
+	if _, _, err := LoadEffectiveTownSettings(townRoot); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("unknown profile: err = %v, want ErrProfileNotFound", err)
+	}
+
+	// No match leaves the town settings alone
+	t.Setenv(ProfileEnv, "")
+	hostname = func() (string, error) { return "ci-runner", nil }
+	if _, sel, err = LoadEffectiveTownSettings(townRoot); err != nil || sel.Name != "" {
+		t.Errorf("unmatched host: %+v, %v", sel, err)
+	}
+}
@@ -0,0 +1,398 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema used to validate Gas Town config
+// files. Schemas are generated from the config types, so they always match
+// what the loaders understand.
+type Schema struct {
+	Type                 []string           `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"` // false or *Schema
+	Items                *Schema            `json:"items,omitempty"`
+}
+
+// Kinds of schema violations.
+const (
+	ViolationUnknownKey = "unknown-key" // key the config type does not define (ignored by loaders)
+	ViolationMissingKey = "missing-key" // required key is absent
+	ViolationType       = "type"        // value has the wrong JSON type
+	ViolationValue      = "value"       // value has the right type but is not allowed
+)
+
+// SchemaViolation is a config value that does not match its schema.
+type SchemaViolation struct {
+	Kind    string // one of the Violation* kinds
+	Path    string // dotted JSON path, e.g. "merge_queue.on_conflict" ("" for the root)
+	Line    int    // 1-based line of the offending key or value
+	Column  int    // 1-based byte column
+	Message string
+}
+
+func (v SchemaViolation) String() string {
+	if v.Path == "" {
+		return fmt.Sprintf("line %d:%d: %s", v.Line, v.Column, v.Message)
+	}
+	return fmt.Sprintf("line %d:%d: %s: %s", v.Line, v.Column, v.Path, v.Message)
+}
+
+// TownConfigSchema returns the schema for mayor/town.json.
+func TownConfigSchema() *Schema {
+	return rootSchema(TownConfig{}, "town", "type", "version", "name")
+}
+
+// TownSettingsSchema returns the schema for the town settings/config.json.
+func TownSettingsSchema() *Schema {
+	return rootSchema(TownSettings{}, "town-settings", "type", "version")
+}
+
+// RigConfigSchema returns the schema for <rig>/config.json.
+func RigConfigSchema() *Schema {
+	return rootSchema(RigConfig{}, "rig", "type", "version", "name")
+}
+
+// RigSettingsSchema returns the schema for <rig>/settings/config.json.
+func RigSettingsSchema() *Schema {
+	return rootSchema(RigSettings{}, "rig-settings", "type", "version")
+}
+
+// rootSchema generates the schema for a config file type, pinning its
+// "type" discriminator and required fields.
+func rootSchema(v interface{}, typeName string, required ...string) *Schema {
+	s := schemaForType(reflect.TypeOf(v))
+	if p, ok := s.Properties["type"]; ok {
+		p.Enum = []string{typeName}
+	}
+	s.Required = required
+	return s
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaForType derives a schema from a Go type the way encoding/json maps it.
+func schemaForType(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := schemaForType(t.Elem())
+		if len(s.Type) > 0 {
+			s.Type = append(s.Type, "null")
+		}
+		return s
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: []string{"string"}, Format: "date-time"}
+		}
+		s := &Schema{
+			Type:                 []string{"object"},
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: false,
+		}
+		addStructProperties(s, t)
+		return s
+	case reflect.Map:
+		return &Schema{Type: []string{"object", "null"}, AdditionalProperties: schemaForType(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: []string{"array", "null"}, Items: schemaForType(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: []string{"string"}}
+	case reflect.Bool:
+		return &Schema{Type: []string{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: []string{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: []string{"number"}}
+	default:
+		return &Schema{} // interface{} and friends accept anything
+	}
+}
+
+// addStructProperties adds a struct's JSON fields to s, flattening
+// embedded structs as encoding/json does.
+func addStructProperties(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addStructProperties(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = schemaForType(f.Type)
+	}
+}
+
+// Validate checks a JSON document against the schema. Violations carry the
+// line and column of the offending key or value. A document that is not
+// valid JSON returns an error that includes the line of the syntax error.
+func (s *Schema) Validate(data []byte) ([]SchemaViolation, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	w := &schemaWalker{data: data, dec: dec}
+	if err := w.value(s, ""); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, col := lineCol(data, syntaxErr.Offset)
+			return nil, fmt.Errorf("line %d:%d: %w", line, col, err)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("unexpected end of JSON input")
+		}
+		return nil, err
+	}
+	return w.violations, nil
+}
+
+// schemaWalker validates a token stream against a schema.
+type schemaWalker struct {
+	data       []byte
+	dec        *json.Decoder
+	violations []SchemaViolation
+}
+
+func (w *schemaWalker) report(kind string, offset int64, path, format string, args ...interface{}) {
+	line, col := lineCol(w.data, offset)
+	w.violations = append(w.violations, SchemaViolation{
+		Kind:    kind,
+		Path:    path,
+		Line:    line,
+		Column:  col,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// next returns the offset of the next token's first byte.
+func (w *schemaWalker) next() int64 {
+	off := w.dec.InputOffset()
+	for off < int64(len(w.data)) {
+		switch w.data[off] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			off++
+		default:
+			return off
+		}
+	}
+	return off
+}
+
+// value validates the next JSON value. A nil schema accepts anything.
+func (w *schemaWalker) value(s *Schema, path string) error {
+	start := w.next()
+	tok, err := w.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	var kind string
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			kind = "object"
+		} else {
+			kind = "array"
+		}
+	case string:
+		kind = "string"
+	case json.Number:
+		kind = "number"
+		if _, err := t.Int64(); err == nil {
+			kind = "integer"
+		}
+	case bool:
+		kind = "boolean"
+	case nil:
+		kind = "null"
+	}
+
+	typeOK := s == nil || len(s.Type) == 0 || s.allowsType(kind)
+	if !typeOK {
+		w.report(ViolationType, start, path, "expected %s, got %s", strings.Join(s.Type, " or "), kind)
+	}
+
+	switch kind {
+	case "object":
+		if !typeOK {
+			s = nil // don't pile up errors inside a value of the wrong type
+		}
+		return w.object(s, path, start)
+	case "array":
+		var items *Schema
+		if typeOK && s != nil {
+			items = s.Items
+		}
+		for i := 0; w.dec.More(); i++ {
+			if err := w.value(items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		_, err := w.dec.Token() // ]
+		return err
+	case "string":
+		if typeOK && s != nil {
+			str := tok.(string)
+			if len(s.Enum) > 0 && !containsString(s.Enum, str) {
+				w.report(ViolationValue, start, path, "must be %s, got %q", quoteJoin(s.Enum), str)
+			}
+			if s.Format == "date-time" {
+				if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+					w.report(ViolationValue, start, path, "expected an RFC 3339 timestamp, got %q", str)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (w *schemaWalker) object(s *Schema, path string, start int64) error {
+	seen := make(map[string]bool)
+	for w.dec.More() {
+		keyOffset := w.next()
+		tok, err := w.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		seen[key] = true
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+
+		var child *Schema
+		if s != nil {
+			if p, ok := s.Properties[key]; ok {
+				child = p
+			} else {
+				switch ap := s.AdditionalProperties.(type) {
+				case *Schema:
+					child = ap
+				case bool:
+					if !ap {
+						w.report(ViolationUnknownKey, keyOffset, childPath, "unknown key%s", s.suggest(key))
+					}
+				}
+			}
+		}
+		if err := w.value(child, childPath); err != nil {
+			return err
+		}
+	}
+	if _, err := w.dec.Token(); err != nil { // }
+		return err
+	}
+
+	if s != nil {
+		for _, req := range s.Required {
+			if !seen[req] {
+				w.report(ViolationMissingKey, start, path, "missing required key %q", req)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) allowsType(kind string) bool {
+	for _, t := range s.Type {
+		if t == kind || (t == "number" && kind == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns a " (did you mean ...?)" hint for a misspelled key.
+func (s *Schema) suggest(key string) string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestDist := "", 3 // only suggest close matches
+	for _, name := range names {
+		if d := editDistance(strings.ToLower(key), name); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// lineCol converts a byte offset into a 1-based line and column.
+func lineCol(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteJoin(list []string) string {
+	quoted := make([]string, len(list))
+	for i, v := range list {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, " or ")
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchemaAcceptsSavedConfigs(t *testing.T) {
+	dir := t.TempDir()
+
+	townPath := filepath.Join(dir, "town.json")
+	if err := SaveTownConfig(townPath, &TownConfig{Type: "town", Version: CurrentTownVersion, Name: "hq", CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	rigSettings := NewRigSettings()
+	rigSettings.MergeQueue = DefaultMergeQueueConfig()
+	rigSettings.Env = map[string]string{"FOO": "bar"}
+	rigSettingsPath := filepath.Join(dir, "rig-settings.json")
+	if err := SaveRigSettings(rigSettingsPath, rigSettings); err != nil {
+		t.Fatal(err)
+	}
+	townSettingsPath := filepath.Join(dir, "town-settings.json")
+	if err := SaveTownSettings(townSettingsPath, NewTownSettings()); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, schema := range map[string]*Schema{
+		townPath:         TownConfigSchema(),
+		rigSettingsPath:  RigSettingsSchema(),
+		townSettingsPath: TownSettingsSchema(),
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		violations, err := schema.Validate(data)
+		if err != nil {
+			t.Fatalf("%s: %v", filepath.Base(path), err)
+		}
+		for _, v := range violations {
+			t.Errorf("%s: unexpected violation: %s", filepath.Base(path), v)
+		}
+	}
+}
+
+func TestSchemaViolations(t *testing.T) {
+	data := []byte(`{
+  "type": "rig",
+  "version": "1",
+  "git_ulr": "https://example.com/repo.git",
+  "created_at": "yesterday",
+  "beads": {
+    "prefix": 7
+  }
+}`)
+
+	violations, err := RigConfigSchema().Validate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		kind string
+		line int
+		text string
+	}{
+		{ViolationType, 3, "version: expected integer, got string"},
+		{ViolationUnknownKey, 4, `git_ulr: unknown key (did you mean "git_url"?)`},
+		{ViolationValue, 5, "created_at: expected an RFC 3339 timestamp"},
+		{ViolationType, 7, "beads.prefix: expected string, got integer"},
+		{ViolationMissingKey, 1, `missing required key "name"`},
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("got %d violations, want %d: %v", len(violations), len(want), violations)
+	}
+	for i, w := range want {
+		v := violations[i]
+		if v.Kind != w.kind || v.Line != w.line || !strings.Contains(v.String(), w.text) {
+			t.Errorf("violation %d = %+v (%s), want kind %s line %d containing %q", i, v, v, w.kind, w.line, w.text)
+		}
+	}
+}
+
+func TestSchemaValidateSyntaxError(t *testing.T) {
+	_, err := RigConfigSchema().Validate([]byte("{\n  \"type\": \"rig\",\n  oops\n}"))
+	if err == nil || !strings.Contains(err.Error(), "line 3:") {
+		t.Errorf("Validate() error = %v, want syntax error on line 3", err)
+	}
+}
+
+func TestSchemaMarshalsAsJSONSchema(t *testing.T) {
+	data, err := json.Marshal(RigConfigSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["additionalProperties"] != false {
+		t.Errorf("additionalProperties = %v, want false", doc["additionalProperties"])
+	}
+}
@@ -0,0 +1,415 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/secrets"
+)
+
+// CurrentTownProfileVersion is the current schema version for TownProfile.
+const CurrentTownProfileVersion = 1
+
+// TownProfile is a portable description of a town: its settings, policies,
+// agent definitions, slash commands, and rigs, without any work data (beads,
+// mail, sessions, clones). Organizations export one from a reference town
+// and stamp out identical towns with `gt init --from-profile`.
+//
+// Config sections hold the JSON config files verbatim, so their keys match
+// the files they come from.
+type TownProfile struct {
+	Type       string    `yaml:"type"`                  // "town-profile"
+	Version    int       `yaml:"version"`               // schema version
+	Name       string    `yaml:"name,omitempty"`        // town the profile was exported from
+	ExportedAt time.Time `yaml:"exported_at,omitempty"` // when the profile was exported
+
+	Settings  map[string]interface{} `yaml:"settings,omitempty"`  // settings/config.json
+	Agents    map[string]interface{} `yaml:"agents,omitempty"`    // settings/agents.json
+	Mayor     map[string]interface{} `yaml:"mayor,omitempty"`     // mayor/config.json
+	Daemon    map[string]interface{} `yaml:"daemon,omitempty"`    // mayor/daemon.json
+	Messaging map[string]interface{} `yaml:"messaging,omitempty"` // config/messaging.json
+
+	// Commands maps .cursor/commands/ file names to their content.
+	Commands map[string]string `yaml:"commands,omitempty"`
+
+	Rigs []RigProfile `yaml:"rigs,omitempty"`
+}
+
+// RigProfile describes a rig to add when a town is created from a profile.
+type RigProfile struct {
+	Name     string                 `yaml:"name"`
+	GitURL   string                 `yaml:"git_url"`
+	Prefix   string                 `yaml:"prefix,omitempty"`   // beads issue prefix
+	Branch   string                 `yaml:"branch,omitempty"`   // default branch
+	Mirror   bool                   `yaml:"mirror,omitempty"`   // read-only reference rig
+	Settings map[string]interface{} `yaml:"settings,omitempty"` // <rig>/settings/config.json
+}
+
+// profileSection ties a config section of a TownProfile to its file.
+type profileSection struct {
+	name     string
+	rel      string // path relative to the town root
+	data     *map[string]interface{}
+	validate func(data []byte) error
+}
+
+func (p *TownProfile) sections() []profileSection {
+	return []profileSection{
+		{"settings", filepath.Join("settings", "config.json"), &p.Settings, func(data []byte) error {
+			var c TownSettings
+			if err := json.Unmarshal(data, &c); err != nil {
+				return err
+			}
+			return validateTownSettings(&c)
+		}},
+		{"agents", filepath.Join("settings", "agents.json"), &p.Agents, func(data []byte) error {
+			var c AgentRegistry
+			return json.Unmarshal(data, &c)
+		}},
+		{"mayor", filepath.Join("mayor", "config.json"), &p.Mayor, func(data []byte) error {
+			var c MayorConfig
+			if err := json.Unmarshal(data, &c); err != nil {
+				return err
+			}
+			return validateMayorConfig(&c)
+		}},
+		{"daemon", filepath.Join("mayor", DaemonPatrolConfigFileName), &p.Daemon, func(data []byte) error {
+			var c DaemonPatrolConfig
+			if err := json.Unmarshal(data, &c); err != nil {
+				return err
+			}
+			return validateDaemonPatrolConfig(&c)
+		}},
+		{"messaging", filepath.Join("config", "messaging.json"), &p.Messaging, func(data []byte) error {
+			var c MessagingConfig
+			if err := json.Unmarshal(data, &c); err != nil {
+				return err
+			}
+			return validateMessagingConfig(&c)
+		}},
+	}
+}
+
+// ExportTownProfile captures the profile of the town at townRoot.
+//
+// Plaintext secrets are not exported: they are replaced with secretRef:env
+// references (see stripProfileSecrets) and each replacement is returned as
+// a warning.
+func ExportTownProfile(townRoot string) (*TownProfile, []string, error) {
+	p := &TownProfile{
+		Type:       "town-profile",
+		Version:    CurrentTownProfileVersion,
+		ExportedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if town, err := LoadTownConfig(filepath.Join(townRoot, "mayor", "town.json")); err == nil {
+		p.Name = town.Name
+	}
+
+	var warnings []string
+	for _, s := range p.sections() {
+		section, err := readProfileSection(filepath.Join(townRoot, s.rel))
+		if err != nil {
+			return nil, nil, err
+		}
+		warnings = append(warnings, stripProfileSecrets(s.name, section)...)
+		*s.data = section
+	}
+
+	commandsDir := filepath.Join(townRoot, ".cursor", "commands")
+	if entries, err := os.ReadDir(commandsDir); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(commandsDir, e.Name())) //nolint:gosec // G304: path is within the town
+			if err != nil {
+				return nil, nil, fmt.Errorf("reading command %s: %w", e.Name(), err)
+			}
+			if p.Commands == nil {
+				p.Commands = make(map[string]string)
+			}
+			p.Commands[e.Name()] = string(content)
+		}
+	}
+
+	rigsConfig, err := LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, nil, err
+	}
+	if rigsConfig != nil {
+		names := make([]string, 0, len(rigsConfig.Rigs))
+		for name := range rigsConfig.Rigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			entry := rigsConfig.Rigs[name]
+			rp := RigProfile{Name: name, GitURL: entry.GitURL, Mirror: entry.Mirror}
+			if entry.BeadsConfig != nil {
+				rp.Prefix = entry.BeadsConfig.Prefix
+			}
+			rigPath := filepath.Join(townRoot, name)
+			if rigConfig, err := LoadRigConfig(filepath.Join(rigPath, "config.json")); err == nil {
+				rp.Branch = rigConfig.DefaultBranch
+			}
+			rp.Settings, err = readProfileSection(RigSettingsPath(rigPath))
+			if err != nil {
+				return nil, nil, err
+			}
+			warnings = append(warnings, stripProfileSecrets("rigs."+name+".settings", rp.Settings)...)
+			p.Rigs = append(p.Rigs, rp)
+		}
+	}
+
+	return p, warnings, nil
+}
+
+// Validate checks the profile and every config section it carries, so a
+// bad profile is rejected before any file is written.
+func (p *TownProfile) Validate() error {
+	if p.Type != "town-profile" {
+		return fmt.Errorf("%w: expected type 'town-profile', got '%s'", ErrInvalidType, p.Type)
+	}
+	if p.Version > CurrentTownProfileVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, p.Version, CurrentTownProfileVersion)
+	}
+	for _, s := range p.sections() {
+		if *s.data == nil {
+			continue
+		}
+		data, err := json.Marshal(*s.data)
+		if err != nil {
+			return fmt.Errorf("profile section %s: %w", s.name, err)
+		}
+		if err := s.validate(data); err != nil {
+			return fmt.Errorf("profile section %s: %w", s.name, err)
+		}
+	}
+	for name := range p.Commands {
+		if name != filepath.Base(name) || name == "." || name == ".." {
+			return fmt.Errorf("profile command %q: name must be a plain file name", name)
+		}
+	}
+	for _, rp := range p.Rigs {
+		if rp.Name == "" || rp.GitURL == "" {
+			return fmt.Errorf("profile rig %q: %w: name and git_url are required", rp.Name, ErrMissingField)
+		}
+		if rp.Name != filepath.Base(rp.Name) || rp.Name == "." || rp.Name == ".." {
+			return fmt.Errorf("profile rig %q: name must be a plain directory name", rp.Name)
+		}
+		if rp.Settings == nil {
+			continue
+		}
+		var settings RigSettings
+		data, err := json.Marshal(rp.Settings)
+		if err == nil {
+			err = json.Unmarshal(data, &settings)
+		}
+		if err == nil {
+			err = validateRigSettings(&settings)
+		}
+		if err != nil {
+			return fmt.Errorf("profile rig %s settings: %w", rp.Name, err)
+		}
+	}
+	return nil
+}
+
+// ApplyTownProfile writes the profile's town-level config files and slash
+// commands into townRoot, replacing what is there. Rigs are not added here;
+// see ApplyRigProfileSettings.
+func ApplyTownProfile(townRoot string, p *TownProfile) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	for _, s := range p.sections() {
+		if *s.data == nil {
+			continue
+		}
+		if err := writeProfileSection(filepath.Join(townRoot, s.rel), *s.data); err != nil {
+			return err
+		}
+	}
+
+	if len(p.Commands) > 0 {
+		commandsDir := filepath.Join(townRoot, ".cursor", "commands")
+		if err := os.MkdirAll(commandsDir, 0755); err != nil {
+			return fmt.Errorf("creating commands directory: %w", err)
+		}
+		for name, content := range p.Commands {
+			if err := os.WriteFile(filepath.Join(commandsDir, name), []byte(content), 0644); err != nil { //nolint:gosec // G306: commands are non-sensitive
+				return fmt.Errorf("writing command %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyRigProfileSettings writes a rig profile's settings into an added rig.
+func ApplyRigProfileSettings(rigPath string, rp RigProfile) error {
+	if rp.Settings == nil {
+		return nil
+	}
+	return writeProfileSection(RigSettingsPath(rigPath), rp.Settings)
+}
+
+// LoadTownProfile reads and validates a YAML town profile.
+func LoadTownProfile(path string) (*TownProfile, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is provided by the user
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading town profile: %w", err)
+	}
+
+	var p TownProfile
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing town profile: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SaveTownProfile writes a town profile as YAML.
+func SaveTownProfile(path string, p *TownProfile) error {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("encoding town profile: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: secrets are stripped on export
+		return fmt.Errorf("writing town profile: %w", err)
+	}
+	return nil
+}
+
+// readProfileSection reads a JSON config file as a generic map.
+// A missing file yields a nil section.
+func readProfileSection(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var section map[string]interface{}
+	if err := json.Unmarshal(data, &section); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return section, nil
+}
+
+func writeProfileSection(path string, section map[string]interface{}) error {
+	data, err := json.MarshalIndent(section, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// profileSecretKeys are credential fields whose plaintext values are never
+// exported (e.g. TrackerConfig.APIKey).
+var profileSecretKeys = map[string]bool{
+	"api_key":  true,
+	"token":    true,
+	"password": true,
+	"secret":   true,
+}
+
+// profileSecretEnvWords mark environment variable names that hold secrets.
+var profileSecretEnvWords = []string{"TOKEN", "KEY", "SECRET", "PASSWORD", "CREDENTIAL"}
+
+// stripProfileSecrets replaces plaintext secrets in a config section, in
+// place, with secretRef:env references: credential fields, and env values
+// whose names look secret. Each town created from the profile then reads
+// the secret from its own environment. It returns one warning per
+// replaced value, naming its dotted path and the variable to set.
+func stripProfileSecrets(path string, v interface{}) []string {
+	var warnings []string
+	replace := func(m map[string]interface{}, key, keyPath, envName string) {
+		m[key] = secrets.RefPrefix + "env:" + envName
+		warnings = append(warnings, fmt.Sprintf("%s: plaintext secret not exported; set $%s or edit the profile to reference your secret store", keyPath, envName))
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			keyPath := path + "." + key
+			value := v[key]
+			if env, ok := value.(map[string]interface{}); ok && key == "env" {
+				for _, name := range sortedKeys(env) {
+					if s, ok := env[name].(string); ok && isSecretEnvName(name) && !secrets.IsRef(s) {
+						replace(env, name, keyPath+"."+name, name)
+					}
+				}
+				continue
+			}
+			if s, ok := value.(string); ok && profileSecretKeys[key] && s != "" && !secrets.IsRef(s) {
+				replace(v, key, keyPath, profileEnvName(keyPath))
+				continue
+			}
+			warnings = append(warnings, stripProfileSecrets(keyPath, value)...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			warnings = append(warnings, stripProfileSecrets(fmt.Sprintf("%s[%d]", path, i), item)...)
+		}
+	}
+	return warnings
+}
+
+func isSecretEnvName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, word := range profileSecretEnvWords {
+		if strings.Contains(upper, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// profileEnvName derives an environment variable name from a dotted config
+// path: "rigs.gastown.settings.tracker.api_key" -> "GT_GASTOWN_TRACKER_API_KEY".
+func profileEnvName(path string) string {
+	var parts []string
+	for _, part := range strings.Split(path, ".") {
+		switch part {
+		case "rigs", "settings":
+			continue
+		}
+		parts = append(parts, part)
+	}
+	name := strings.ToUpper(strings.Join(append([]string{"gt"}, parts...), "_"))
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
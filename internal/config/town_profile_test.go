@@ -0,0 +1,159 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProfileTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTownProfileRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	writeProfileTestFile(t, filepath.Join(src, "mayor", "town.json"), `{"type":"town","version":2,"name":"reference"}`)
+	writeProfileTestFile(t, filepath.Join(src, "settings", "config.json"), `{
+  "type": "town-settings",
+  "version": 1,
+  "default_agent": "cursor",
+  "env": {"TEAM": "platform", "GITHUB_TOKEN": "secretRef:env:GH_TOKEN", "NPM_TOKEN": "npm_abc123"},
+  "cost_alerts": {"daily_budget_usd": 50}
+}`)
+	writeProfileTestFile(t, filepath.Join(src, "mayor", "daemon.json"), `{"type":"daemon-patrol-config","version":1,"heartbeat":{"enabled":true,"interval":"3m"}}`)
+	writeProfileTestFile(t, filepath.Join(src, ".cursor", "commands", "review.md"), "Review the diff.")
+	writeProfileTestFile(t, filepath.Join(src, "mayor", "rigs.json"), `{"version":1,"rigs":{
+  "gastown": {"git_url":"https://example.com/gastown.git","local_repo":"/home/alice/src/gastown","added_at":"2026-01-01T00:00:00Z","beads":{"repo":"","prefix":"gt"}},
+  "docs": {"git_url":"https://example.com/docs.git","added_at":"2026-01-01T00:00:00Z","mirror":true}
+}}`)
+	writeProfileTestFile(t, filepath.Join(src, "gastown", "config.json"), `{"type":"rig","version":1,"name":"gastown","git_url":"https://example.com/gastown.git","default_branch":"develop"}`)
+	writeProfileTestFile(t, filepath.Join(src, "gastown", "settings", "config.json"), `{
+  "type": "rig-settings",
+  "version": 1,
+  "agent": "codex",
+  "tracker": {"type": "linear", "team": "ENG", "api_key": "lin_plaintext"}
+}`)
+
+	profile, warnings, err := ExportTownProfile(src)
+	if err != nil {
+		t.Fatalf("ExportTownProfile: %v", err)
+	}
+	if profile.Name != "reference" {
+		t.Errorf("Name = %q, want reference", profile.Name)
+	}
+	wantWarnings := []string{"settings.env.NPM_TOKEN", "rigs.gastown.settings.tracker.api_key"}
+	if len(warnings) != len(wantWarnings) {
+		t.Fatalf("warnings = %v, want %d", warnings, len(wantWarnings))
+	}
+	for i, want := range wantWarnings {
+		if !strings.HasPrefix(warnings[i], want+":") {
+			t.Errorf("warnings[%d] = %q, want %s", i, warnings[i], want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "profile.yaml")
+	if err := SaveTownProfile(path, profile); err != nil {
+		t.Fatalf("SaveTownProfile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"npm_abc123", "lin_plaintext", "/home/alice"} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("profile leaks %q:\n%s", leaked, data)
+		}
+	}
+
+	loaded, err := LoadTownProfile(path)
+	if err != nil {
+		t.Fatalf("LoadTownProfile: %v", err)
+	}
+	if len(loaded.Rigs) != 2 || loaded.Rigs[0].Name != "docs" || !loaded.Rigs[0].Mirror {
+		t.Fatalf("rigs = %+v, want docs (mirror) then gastown", loaded.Rigs)
+	}
+	if rp := loaded.Rigs[1]; rp.Prefix != "gt" || rp.Branch != "develop" {
+		t.Errorf("gastown profile = %+v", rp)
+	}
+
+	dst := t.TempDir()
+	if err := ApplyTownProfile(dst, loaded); err != nil {
+		t.Fatalf("ApplyTownProfile: %v", err)
+	}
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.DefaultAgent != "cursor" || settings.CostAlerts == nil || settings.CostAlerts.DailyBudgetUSD != 50 {
+		t.Errorf("applied settings = %+v", settings)
+	}
+	wantEnv := map[string]string{
+		"TEAM":         "platform",
+		"GITHUB_TOKEN": "secretRef:env:GH_TOKEN",
+		"NPM_TOKEN":    "secretRef:env:NPM_TOKEN",
+	}
+	for name, want := range wantEnv {
+		if settings.Env[name] != want {
+			t.Errorf("applied env %s = %q, want %q", name, settings.Env[name], want)
+		}
+	}
+	patrol, err := LoadDaemonPatrolConfig(DaemonPatrolConfigPath(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patrol.Heartbeat == nil || patrol.Heartbeat.Interval != "3m" {
+		t.Errorf("applied daemon config = %+v", patrol)
+	}
+	if got, err := os.ReadFile(filepath.Join(dst, ".cursor", "commands", "review.md")); err != nil || string(got) != "Review the diff." {
+		t.Errorf("applied command = %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "config", "messaging.json")); !os.IsNotExist(err) {
+		t.Errorf("sections absent from the profile must not be written")
+	}
+
+	rigPath := filepath.Join(dst, "gastown")
+	if err := ApplyRigProfileSettings(rigPath, loaded.Rigs[1]); err != nil {
+		t.Fatal(err)
+	}
+	rigSettings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rigSettings.Agent != "codex" || rigSettings.Tracker.APIKey != "secretRef:env:GT_GASTOWN_TRACKER_API_KEY" {
+		t.Errorf("applied rig settings = %+v", rigSettings)
+	}
+}
+
+func TestTownProfileValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile TownProfile
+		wantErr error
+	}{
+		{"wrong type", TownProfile{Type: "town"}, ErrInvalidType},
+		{"future version", TownProfile{Type: "town-profile", Version: 99}, ErrInvalidVersion},
+		{"bad section", TownProfile{Type: "town-profile", Daemon: map[string]interface{}{"type": "rig"}}, ErrInvalidType},
+		{"rig without url", TownProfile{Type: "town-profile", Rigs: []RigProfile{{Name: "gastown"}}}, ErrMissingField},
+		{"command path", TownProfile{Type: "town-profile", Commands: map[string]string{"../evil.md": ""}}, nil},
+		{"rig path", TownProfile{Type: "town-profile", Rigs: []RigProfile{{Name: "../x", GitURL: "u"}}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Validate()
+			if err == nil {
+				t.Fatal("Validate() = nil, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package config
 
 import (
 	"os"
+	"path"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 )
@@ -48,6 +51,514 @@ type TownSettings struct {
 	// Values override or extend the built-in presets.
 	// Example: {"gemini": {"command": "/custom/path/to/gemini"}}
 	Agents map[string]*RuntimeConfig `json:"agents,omitempty"`
+
+	// CostAlerts configures budget alerting for agent session costs.
+	// When nil, no cost alerts are evaluated.
+	CostAlerts *CostAlertsConfig `json:"cost_alerts,omitempty"`
+
+	// TemplateResync configures automatic re-sync of agent hook templates
+	// after a gt upgrade. When nil or disabled, drift is only reported by
+	// 'gt doctor'.
+	TemplateResync *TemplateResyncConfig `json:"template_resync,omitempty"`
+
+	// SelfUpdate configures where 'gt self-update' and gt doctor look for
+	// new gt releases. When nil, the stable GitHub channel is used.
+	SelfUpdate *SelfUpdateConfig `json:"self_update,omitempty"`
+
+	// Env sets extra environment variables for agent sessions and the daemon.
+	// Values may be secret references ("secretRef:<provider>:<ref>") so API
+	// keys are resolved at spawn instead of stored here in plaintext.
+	// Example: {"ANTHROPIC_API_KEY": "secretRef:op:op://Dev/Anthropic/credential"}
+	Env map[string]string `json:"env,omitempty"`
+
+	// DoctorProfiles defines named check subsets for 'gt doctor --profile'.
+	// A profile named "quick" or "full" replaces the built-in one.
+	// Example: {"pre-demo": {"checks": ["daemon", "patrol-*", "cursor-cli"]}}
+	DoctorProfiles map[string]*DoctorProfile `json:"doctor_profiles,omitempty"`
+
+	// MailEncryption encrypts mail to the overseer at rest with age.
+	// When nil, overseer mail is stored in plaintext like all other mail.
+	MailEncryption *MailEncryptionConfig `json:"mail_encryption,omitempty"`
+
+	// EmailBridge forwards escalations in the overseer inbox to the
+	// operator's email and brings their replies back as town mail (see gt
+	// mail bridge). When nil, the bridge is off.
+	EmailBridge *EmailBridgeConfig `json:"email_bridge,omitempty"`
+
+	// ContextBudgets sets per-role token budgets for the instructions every
+	// agent carries in context, checked by 'gt doctor'. When nil, defaults apply.
+	ContextBudgets *ContextBudgetsConfig `json:"context_budgets,omitempty"`
+
+	// ContextPriming configures the work-item priming block gt prime adds
+	// when a session is spawned with work on its hook. When nil, defaults
+	// apply (polecats and crew are primed).
+	ContextPriming *ContextPrimingConfig `json:"context_priming,omitempty"`
+
+	// CostCenter is the chargeback tag for spend that no rig claims: the
+	// mayor, the deacon, and rigs without their own cost_center.
+	// Example: "eng-platform"
+	CostCenter string `json:"cost_center,omitempty"`
+
+	// CostReconcile lists the provider billing APIs 'gt costs reconcile'
+	// compares the hook-recorded cost ledger against. When nil, there is
+	// nothing to reconcile against.
+	CostReconcile *CostReconcileConfig `json:"cost_reconcile,omitempty"`
+
+	// CursorHooks turns optional Cursor hooks on or off per role in the
+	// generated hooks.json. When nil, each role gets its default hooks.
+	CursorHooks *CursorHooksConfig `json:"cursor_hooks,omitempty"`
+
+	// RigDefaults are town-wide defaults for rig operational config such as
+	// max_polecats, consulted after a rig's own wisp and bead layers ('gt
+	// rig config show'). Usually set per machine in a machine profile.
+	// Example: {"max_polecats": 4}
+	RigDefaults map[string]interface{} `json:"rig_defaults,omitempty"`
+
+	// Attach configures 'gt attach'. When nil, attaches are interactive and
+	// show no banner.
+	Attach *AttachConfig `json:"attach,omitempty"`
+
+	// MCP defines the MCP servers generated into each agent's
+	// .cursor/mcp.json. When nil, gt generates no servers.
+	MCP *MCPSettings `json:"mcp,omitempty"`
+}
+
+// MCPSettings defines MCP servers for agents' .cursor/mcp.json and which
+// roles get them. Town settings define servers for every rig; rig settings
+// add repo-specific ones, replacing a town server of the same name.
+type MCPSettings struct {
+	// Servers maps server names to Cursor mcp.json server entries.
+	// Example: {"github": {"command": "github-mcp-server", "args": ["stdio"],
+	// "env": {"GITHUB_TOKEN": "${env:GITHUB_TOKEN}"}}}
+	Servers map[string]map[string]interface{} `json:"servers,omitempty"`
+
+	// Roles maps role names to the servers they get; "*" applies to every
+	// role. When empty, every role gets every server.
+	// Example: {"refinery": ["github"], "*": ["docs"]}
+	Roles map[string][]string `json:"roles,omitempty"`
+}
+
+// ServersFor returns the names of the defined servers role gets, sorted.
+func (c *MCPSettings) ServersFor(role string) []string {
+	if c == nil {
+		return nil
+	}
+	var names []string
+	for name := range c.Servers {
+		if len(c.Roles) == 0 || slices.Contains(c.Roles[role], name) || slices.Contains(c.Roles["*"], name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CursorHooksConfig configures which optional Cursor hooks (afterFileEdit
+// for change tracking, beforeShellExecution for command auditing) each
+// role's hooks.json includes. Enabled hooks are also required by 'gt doctor'.
+type CursorHooksConfig struct {
+	// Roles maps role names (mayor, deacon, witness, refinery, polecat,
+	// crew) to the optional hooks enabled or disabled for them.
+	// Example: {"witness": {"enable": ["afterFileEdit"]}, "crew": {"disable": ["beforeShellExecution"]}}
+	Roles map[string]*RoleHooksConfig `json:"roles,omitempty"`
+}
+
+// RoleHooksConfig lists the optional hooks enabled or disabled for a role.
+// A hook listed in both is enabled.
+type RoleHooksConfig struct {
+	Enable  []string `json:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty"`
+}
+
+// Role returns the hook settings for role, or nil if it has none.
+func (c *CursorHooksConfig) Role(role string) *RoleHooksConfig {
+	if c == nil {
+		return nil
+	}
+	return c.Roles[role]
+}
+
+// AttachConfig sets the defaults for humans attaching to agent sessions
+// with 'gt attach'. The --banner and --read-only flags override them.
+type AttachConfig struct {
+	// Banner shows the agent's role and hooked work in the tmux status
+	// line on attach, with a reminder not to type over the agent.
+	Banner bool `json:"banner,omitempty"`
+
+	// ReadOnly attaches as a read-only tmux client, so stray keystrokes
+	// never reach the agent's prompt.
+	ReadOnly bool `json:"read_only,omitempty"`
+}
+
+// ShowBanner reports whether attaches show the safety banner by default.
+func (c *AttachConfig) ShowBanner() bool {
+	return c != nil && c.Banner
+}
+
+// ReadOnlyDefault reports whether attaches are read-only by default.
+func (c *AttachConfig) ReadOnlyDefault() bool {
+	return c != nil && c.ReadOnly
+}
+
+// ContextBudgetsConfig sets token budgets for each agent's assembled
+// instructions: its rules files, the repo's AGENTS.md, and the role context
+// printed by gt prime.
+type ContextBudgetsConfig struct {
+	// ContextWindow is the model context window in tokens, used to report
+	// the fraction instructions take up. Default: 200000.
+	ContextWindow int `json:"context_window,omitempty"`
+
+	// Default is the budget for roles not listed in Roles. Default: 20000.
+	Default int `json:"default,omitempty"`
+
+	// Roles maps role names (mayor, deacon, witness, refinery, polecat,
+	// crew) to their budget in tokens.
+	// Example: {"polecat": 12000, "mayor": 30000}
+	Roles map[string]int `json:"roles,omitempty"`
+}
+
+// Context budget defaults.
+const (
+	DefaultContextWindowTokens = 200000
+	DefaultContextBudgetTokens = 20000
+)
+
+// Window returns the configured context window or the default.
+func (c *ContextBudgetsConfig) Window() int {
+	if c == nil || c.ContextWindow <= 0 {
+		return DefaultContextWindowTokens
+	}
+	return c.ContextWindow
+}
+
+// Budget returns the instruction token budget for role.
+func (c *ContextBudgetsConfig) Budget(role string) int {
+	if c == nil {
+		return DefaultContextBudgetTokens
+	}
+	if b := c.Roles[role]; b > 0 {
+		return b
+	}
+	if c.Default > 0 {
+		return c.Default
+	}
+	return DefaultContextBudgetTokens
+}
+
+// ContextPrimingConfig configures automatic context priming: when a session
+// is spawned with work on its hook, gt prime opens it with the issue text,
+// files a quick repo search turns up for it, and prior sessions on the same
+// work, capped at the role's token budget.
+type ContextPrimingConfig struct {
+	// Roles maps role names to their priming budget in tokens. Only listed
+	// roles with a positive budget are primed.
+	// Default: {"polecat": 3000, "crew": 3000}
+	Roles map[string]int `json:"roles,omitempty"`
+
+	// MaxFiles caps the relevant-file list. Default: 10.
+	MaxFiles int `json:"max_files,omitempty"`
+
+	// MaxSessions caps the related prior sessions listed. Default: 3.
+	MaxSessions int `json:"max_sessions,omitempty"`
+}
+
+// Context priming defaults.
+const (
+	DefaultPrimingBudgetTokens = 3000
+	DefaultPrimingMaxFiles     = 10
+	DefaultPrimingMaxSessions  = 3
+)
+
+// Budget returns the priming token budget for role, or 0 if the role is
+// not primed.
+func (c *ContextPrimingConfig) Budget(role string) int {
+	if c == nil || len(c.Roles) == 0 {
+		if role == "polecat" || role == "crew" {
+			return DefaultPrimingBudgetTokens
+		}
+		return 0
+	}
+	if b := c.Roles[role]; b > 0 {
+		return b
+	}
+	return 0
+}
+
+// FileLimit returns the configured relevant-file cap or the default.
+func (c *ContextPrimingConfig) FileLimit() int {
+	if c == nil || c.MaxFiles <= 0 {
+		return DefaultPrimingMaxFiles
+	}
+	return c.MaxFiles
+}
+
+// SessionLimit returns the configured prior-session cap or the default.
+func (c *ContextPrimingConfig) SessionLimit() int {
+	if c == nil || c.MaxSessions <= 0 {
+		return DefaultPrimingMaxSessions
+	}
+	return c.MaxSessions
+}
+
+// MailEncryptionConfig configures age encryption of the overseer inbox.
+// Message bodies are encrypted; subjects stay plaintext so the inbox can be
+// listed without the key.
+type MailEncryptionConfig struct {
+	// Recipient is the age public key (age1...) that overseer mail bodies are
+	// encrypted to. Sending fails rather than storing plaintext if the age
+	// CLI is unavailable.
+	Recipient string `json:"recipient"`
+
+	// Identity is the age identity used by 'gt mail read' to decrypt: either
+	// a path to an age identity file or a secret reference resolving to the
+	// identity (e.g. "secretRef:keychain:gastown/age-identity"). When unset
+	// or unavailable, encrypted bodies are shown as ciphertext.
+	Identity string `json:"identity,omitempty"`
+}
+
+// Billing providers supported by CostReconcileConfig.
+const (
+	BillingAnthropic = "anthropic"
+	BillingOpenAI    = "openai"
+)
+
+// DefaultDriftThresholdPct is how far, in percent, a day's recorded cost for
+// a model may drift from the billed cost before it is flagged.
+const DefaultDriftThresholdPct = 10.0
+
+// CostReconcileConfig configures reconciliation of the cost ledger against
+// the usage providers actually billed (see gt costs reconcile).
+type CostReconcileConfig struct {
+	// Providers are the billing APIs to pull usage from.
+	Providers []BillingProviderConfig `json:"providers"`
+
+	// DriftThresholdPct flags day/model rows whose recorded cost differs
+	// from the billed cost by more than this percentage.
+	// Default: 10.
+	DriftThresholdPct float64 `json:"drift_threshold_pct,omitempty"`
+
+	// ModelAliases maps model names recorded in the ledger (from
+	// $CURSOR_MODEL) to the model the provider bills them as.
+	// Example: {"sonnet-4": "claude-sonnet-4"}
+	ModelAliases map[string]string `json:"model_aliases,omitempty"`
+}
+
+// DriftThreshold returns the drift threshold in percent, applying the default.
+func (c *CostReconcileConfig) DriftThreshold() float64 {
+	if c == nil || c.DriftThresholdPct <= 0 {
+		return DefaultDriftThresholdPct
+	}
+	return c.DriftThresholdPct
+}
+
+// BillingProviderConfig is one provider billing API.
+type BillingProviderConfig struct {
+	// Type is the provider: "anthropic" or "openai".
+	Type string `json:"type"`
+
+	// APIKey is an organization admin key with access to cost reports. Use
+	// a secret reference (e.g. "secretRef:keychain:gastown/anthropic-admin").
+	APIKey string `json:"api_key"`
+
+	// Endpoint overrides the provider's API base URL (e.g. for a proxy).
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// EmailBridgeConfig connects the overseer inbox to the operator's email.
+// Escalations are sent to Operator over SMTP; replies sent to ReplyTo are
+// read over IMAP from Mailbox and delivered to the escalating agent.
+type EmailBridgeConfig struct {
+	// Operator is the operator's email address. Escalations are sent here,
+	// and only replies from this address are accepted.
+	Operator string `json:"operator"`
+
+	// From is the address the bridge sends as.
+	From string `json:"from"`
+
+	// ReplyTo is the dedicated address operator replies go to, e.g.
+	// "gastown+replies@example.com". Default: From.
+	ReplyTo string `json:"reply_to,omitempty"`
+
+	// SMTP is the outgoing mail server. Port 465 uses implicit TLS; other
+	// ports use STARTTLS. Default port: 587.
+	SMTP EmailServerConfig `json:"smtp"`
+
+	// IMAP is the server replies are read from, over TLS. Default port: 993.
+	IMAP EmailServerConfig `json:"imap"`
+
+	// Mailbox is the IMAP mailbox (or Gmail label) replies are read from.
+	// Default: "INBOX".
+	Mailbox string `json:"mailbox,omitempty"`
+
+	// ForwardAll forwards all overseer mail, not only escalations.
+	ForwardAll bool `json:"forward_all,omitempty"`
+}
+
+// EmailServerConfig is a mail server the email bridge logs in to.
+type EmailServerConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username"`
+
+	// Password authenticates Username. Use a secret reference (e.g.
+	// "secretRef:keychain:gastown/smtp") rather than plaintext.
+	Password string `json:"password"`
+}
+
+// Default email bridge settings.
+const (
+	DefaultSMTPPort         = 587
+	DefaultIMAPPort         = 993
+	DefaultEmailBridgeInbox = "INBOX"
+)
+
+// ReplyAddress returns the address operator replies are sent to.
+func (c *EmailBridgeConfig) ReplyAddress() string {
+	if c.ReplyTo != "" {
+		return c.ReplyTo
+	}
+	return c.From
+}
+
+// ReplyMailbox returns the IMAP mailbox replies are read from.
+func (c *EmailBridgeConfig) ReplyMailbox() string {
+	if c.Mailbox != "" {
+		return c.Mailbox
+	}
+	return DefaultEmailBridgeInbox
+}
+
+// DoctorProfile selects a subset of doctor checks. Entries are check names
+// or glob patterns (e.g. "patrol-*").
+type DoctorProfile struct {
+	// Description is shown by 'gt doctor profiles'.
+	Description string `json:"description,omitempty"`
+
+	// Checks lists the checks to run. Empty runs every check.
+	Checks []string `json:"checks,omitempty"`
+
+	// Skip lists checks to leave out, applied after Checks.
+	Skip []string `json:"skip,omitempty"`
+}
+
+// TemplateResyncConfig configures daemon-driven re-sync of agent templates.
+type TemplateResyncConfig struct {
+	// AutoResync opts in to daemon re-sync when embedded templates change.
+	AutoResync bool `json:"auto_resync"`
+
+	// MaintenanceWindow is the local time range ("HH:MM-HH:MM") during which
+	// running patrol agents may be cycled to pick up new templates. Ranges
+	// may wrap midnight. Default: "02:00-05:00".
+	MaintenanceWindow string `json:"maintenance_window,omitempty"`
+}
+
+// SelfUpdateConfig configures the gt release channel.
+type SelfUpdateConfig struct {
+	// Channel is "stable" (default) or "prerelease".
+	Channel string `json:"channel,omitempty"`
+
+	// ReleasesURL overrides the GitHub releases API endpoint, e.g. for an
+	// internal mirror with the same JSON shape.
+	ReleasesURL string `json:"releases_url,omitempty"`
+}
+
+// UpdateChannel returns the configured release channel or "stable".
+func (c *SelfUpdateConfig) UpdateChannel() string {
+	if c == nil || c.Channel == "" {
+		return "stable"
+	}
+	return c.Channel
+}
+
+// URL returns the configured releases endpoint (empty for the default).
+func (c *SelfUpdateConfig) URL() string {
+	if c == nil {
+		return ""
+	}
+	return c.ReleasesURL
+}
+
+// DefaultMaintenanceWindow is used when no maintenance window is configured.
+const DefaultMaintenanceWindow = "02:00-05:00"
+
+// Window returns the configured maintenance window or the default.
+func (c *TemplateResyncConfig) Window() string {
+	if c == nil || c.MaintenanceWindow == "" {
+		return DefaultMaintenanceWindow
+	}
+	return c.MaintenanceWindow
+}
+
+// InMaintenanceWindow reports whether now falls inside the configured
+// maintenance window. An unparseable window never matches.
+func (c *TemplateResyncConfig) InMaintenanceWindow(now time.Time) bool {
+	startStr, endStr, ok := strings.Cut(c.Window(), "-")
+	if !ok {
+		return false
+	}
+	start, err1 := time.Parse("15:04", strings.TrimSpace(startStr))
+	end, err2 := time.Parse("15:04", strings.TrimSpace(endStr))
+	if err1 != nil || err2 != nil {
+		return false
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+	if from <= to {
+		return minute >= from && minute < to
+	}
+	return minute >= from || minute < to // wraps midnight
+}
+
+// CostAlertsConfig configures budget and anomaly alerts for session costs.
+type CostAlertsConfig struct {
+	// DailyBudgetUSD is the spend budget for the current day (0 disables).
+	DailyBudgetUSD float64 `json:"daily_budget_usd,omitempty"`
+
+	// WeeklyBudgetUSD is the spend budget for the trailing 7 days (0 disables).
+	WeeklyBudgetUSD float64 `json:"weekly_budget_usd,omitempty"`
+
+	// SessionLimitUSD flags any single live session whose cost exceeds it (0 disables).
+	SessionLimitUSD float64 `json:"session_limit_usd,omitempty"`
+
+	// AnomalyFactor flags a day whose spend exceeds this multiple of the
+	// average daily spend over the previous 7 days (0 disables).
+	AnomalyFactor float64 `json:"anomaly_factor,omitempty"`
+
+	// Thresholds are budget fractions that trigger alerts, in any order.
+	// Default: [0.8, 1.0] (warn at 80%, alert at 100%).
+	Thresholds []float64 `json:"thresholds,omitempty"`
+
+	// RecoveryMargin is the hysteresis band below a crossed threshold that the
+	// metric must fall under before the alert clears and may fire again.
+	// Expressed as a fraction of the threshold value. Default: 0.1.
+	RecoveryMargin float64 `json:"recovery_margin,omitempty"`
+}
+
+// DefaultCostAlertThresholds are the budget fractions used when none are configured.
+var DefaultCostAlertThresholds = []float64{0.8, 1.0}
+
+// DefaultCostAlertRecoveryMargin is the hysteresis band used when none is configured.
+const DefaultCostAlertRecoveryMargin = 0.1
+
+// AlertThresholds returns the configured thresholds in ascending order, or
+// the defaults.
+func (c *CostAlertsConfig) AlertThresholds() []float64 {
+	if c == nil || len(c.Thresholds) == 0 {
+		return DefaultCostAlertThresholds
+	}
+	thresholds := slices.Clone(c.Thresholds)
+	slices.Sort(thresholds)
+	return thresholds
+}
+
+// AlertRecoveryMargin returns the configured recovery margin or the default.
+func (c *CostAlertsConfig) AlertRecoveryMargin() float64 {
+	if c == nil || c.RecoveryMargin <= 0 {
+		return DefaultCostAlertRecoveryMargin
+	}
+	return c.RecoveryMargin
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -146,6 +657,10 @@ type RigEntry struct {
 	LocalRepo   string       `json:"local_repo,omitempty"`
 	AddedAt     time.Time    `json:"added_at"`
 	BeadsConfig *BeadsConfig `json:"beads,omitempty"`
+
+	// Mirror marks a reference-only rig: a read-only checkout agents may
+	// browse, with no beads, patrol roles, or agent sessions.
+	Mirror bool `json:"mirror,omitempty"`
 }
 
 // BeadsConfig represents beads configuration for a rig.
@@ -178,6 +693,7 @@ type RigConfig struct {
 	DefaultBranch string       `json:"default_branch,omitempty"` // default branch (defaults to "main")
 	CreatedAt     time.Time    `json:"created_at"`               // when the rig was created
 	Beads         *BeadsConfig `json:"beads,omitempty"`
+	RulePacks     []string     `json:"rule_packs,omitempty"` // language rule packs detected in the repo
 }
 
 // RigSettings represents per-rig behavioral configuration (settings/config.json).
@@ -196,6 +712,146 @@ type RigSettings struct {
 	// If empty, uses the town's default_agent setting (cursor).
 	// Takes precedence over Runtime if both are set.
 	Agent string `json:"agent,omitempty"`
+
+	// Env sets extra environment variables for this rig's agent sessions,
+	// overriding town settings env. Values may be secret references.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Tracker connects the rig to an external issue tracker (see gt tracker).
+	Tracker *TrackerConfig `json:"tracker,omitempty"`
+
+	// Upstream is the repository the rig's repo was forked from. The daemon
+	// keeps the fork in sync with it (see gt rig upstream).
+	Upstream *UpstreamConfig `json:"upstream,omitempty"`
+
+	// CostCenter is the chargeback tag the rig's session costs are billed
+	// to (see gt costs close). Empty uses the town's cost_center.
+	CostCenter string `json:"cost_center,omitempty"`
+
+	// MCP adds repo-specific MCP servers to the rig's agents'
+	// .cursor/mcp.json, on top of the town's (see TownSettings.MCP).
+	MCP *MCPSettings `json:"mcp,omitempty"`
+
+	// ProtectedPaths lists repo paths agents must not change without an
+	// approval (see gt rig protected).
+	ProtectedPaths *ProtectedPathsConfig `json:"protected_paths,omitempty"`
+}
+
+// Supported external issue tracker types.
+const (
+	TrackerLinear = "linear"
+)
+
+// TrackerConfig connects a rig to an external issue tracker. Issues are
+// imported as beads, agents post status to them, and the refinery closes
+// them when the work merges.
+type TrackerConfig struct {
+	// Type is the tracker: "linear".
+	Type string `json:"type"`
+
+	// Team is the tracker team key issues are imported from (e.g. "ENG").
+	Team string `json:"team"`
+
+	// APIKey authenticates with the tracker. Use a secret reference
+	// (e.g. "secretRef:op:op://Dev/Linear/credential") rather than plaintext.
+	APIKey string `json:"api_key"`
+
+	// Label, if set, imports only issues carrying this label.
+	Label string `json:"label,omitempty"`
+
+	// CloseState is the workflow state issues move to on merge.
+	// Default: the team's first completed state (usually "Done").
+	CloseState string `json:"close_state,omitempty"`
+}
+
+// DefaultUpstreamSyncInterval is how often the daemon syncs a fork with its
+// upstream when UpstreamConfig.Interval is not set.
+const DefaultUpstreamSyncInterval = 6 * time.Hour
+
+// UpstreamConfig tracks the upstream of a forked rig repo. Each sync fetches
+// upstream, fast-forwards the fork's default branch when it has no commits
+// of its own, and otherwise opens a sync task. The witness is told about
+// upstream changes to files that in-flight polecat branches also touch.
+type UpstreamConfig struct {
+	// URL is the upstream repository.
+	URL string `json:"url"`
+
+	// Branch is the upstream branch to follow.
+	// Default: the rig's default branch.
+	Branch string `json:"branch,omitempty"`
+
+	// Interval is how often the daemon syncs (e.g. "1h"). "0" disables
+	// scheduled syncs; 'gt rig upstream sync' still works.
+	// Default: 6h.
+	Interval string `json:"interval,omitempty"`
+}
+
+// SyncInterval returns the scheduled sync interval; zero means manual only.
+func (c *UpstreamConfig) SyncInterval() time.Duration {
+	if c.Interval == "" {
+		return DefaultUpstreamSyncInterval
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d < 0 {
+		return DefaultUpstreamSyncInterval
+	}
+	return d
+}
+
+// DefaultProtectedPathsApprover is who approves changes to protected paths
+// when the rig does not name an approver.
+const DefaultProtectedPathsApprover = "mayor/"
+
+// ProtectedPathsConfig declares repo paths (infrastructure, migrations,
+// release config) agents must not change without an approval. Agents' rules
+// list them, and the refinery holds merges of branches that change them
+// until the changes are approved.
+type ProtectedPathsConfig struct {
+	// Paths are patterns relative to the repo root. A pattern ending in "/"
+	// protects everything under that directory ("infra/"); other patterns
+	// are path.Match globs matched against the whole path, or against the
+	// file name when the pattern has no "/" ("*.tf", "release.yaml").
+	Paths []string `json:"paths"`
+
+	// Approver is the mail address asked to approve changes.
+	// Default: "mayor/".
+	Approver string `json:"approver,omitempty"`
+}
+
+// Match returns the first pattern protecting file (a slash-separated path
+// relative to the repo root), or "" if file is not protected.
+func (c *ProtectedPathsConfig) Match(file string) string {
+	if c == nil {
+		return ""
+	}
+	for _, pattern := range c.Paths {
+		pattern = strings.TrimPrefix(pattern, "/")
+		switch {
+		case pattern == "":
+			continue
+		case strings.HasSuffix(pattern, "/"):
+			if strings.HasPrefix(file, pattern) {
+				return pattern
+			}
+		case strings.Contains(pattern, "/"):
+			if ok, _ := path.Match(pattern, file); ok {
+				return pattern
+			}
+		default:
+			if ok, _ := path.Match(pattern, path.Base(file)); ok {
+				return pattern
+			}
+		}
+	}
+	return ""
+}
+
+// ApproverAddress returns the address asked to approve protected changes.
+func (c *ProtectedPathsConfig) ApproverAddress() string {
+	if c == nil || c.Approver == "" {
+		return DefaultProtectedPathsApprover
+	}
+	return c.Approver
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -354,6 +1010,11 @@ type MergeQueueConfig struct {
 	// RetryFlakyTests is the number of times to retry flaky tests.
 	RetryFlakyTests int `json:"retry_flaky_tests"`
 
+	// RequireGreenRun gates merges on the rig's test ledger (e.g., "24h"):
+	// a branch merges only if its latest recorded test run passed within
+	// this window. Empty disables the gate.
+	RequireGreenRun string `json:"require_green_run,omitempty"`
+
 	// PollInterval is how often to poll for new merge requests (e.g., "30s").
 	PollInterval string `json:"poll_interval"`
 
@@ -455,6 +1116,46 @@ type MessagingConfig struct {
 	// Like mailing lists but for tmux send-keys instead of durable mail.
 	// Example: {"workers": ["gastown/polecats/*", "gastown/crew/*"], "witnesses": ["*/witness"]}
 	NudgeChannels map[string][]string `json:"nudge_channels,omitempty"`
+
+	// Away puts inboxes in vacation mode, keyed by agent address or rig name
+	// (every agent in the rig). Senders get an auto-reply with the status, and
+	// urgent mail is diverted to the fallback recipient.
+	// Example: {"gastown/crew/joe": {"message": "On leave", "fallback": "gastown/crew/max"}}
+	Away map[string]AwayConfig `json:"away,omitempty"`
+
+	// Filters restrict the mail each role accepts, keyed by role (polecat,
+	// crew, witness, refinery, mayor, deacon). Mail a filter rejects is
+	// bounced to the sender with the reason instead of being delivered.
+	// Example: {"polecat": {"types": ["task", "reply"], "from": ["*/witness"]}}
+	Filters map[string]MailFilterConfig `json:"filters,omitempty"`
+}
+
+// MailFilterConfig lists the mail a role accepts. A message is accepted if
+// it matches any rule; a filter with no rules accepts everything. Mail from
+// the overseer, self-mail, lifecycle messages, and bounces always pass.
+type MailFilterConfig struct {
+	// Types are accepted message types (task, scavenge, notification, reply).
+	Types []string `json:"types,omitempty"`
+
+	// Subjects are accepted subject keywords, matched case-insensitively
+	// anywhere in the subject (e.g. "escalation").
+	Subjects []string `json:"subjects,omitempty"`
+
+	// From are senders whose mail is always accepted.
+	// Supports wildcards: "*/witness" matches every rig's witness.
+	From []string `json:"from,omitempty"`
+}
+
+// AwayConfig describes an unattended inbox.
+type AwayConfig struct {
+	// Message is the status included in auto-replies.
+	Message string `json:"message,omitempty"`
+
+	// Until is the expected return time (nil = unknown).
+	Until *time.Time `json:"until,omitempty"`
+
+	// Fallback receives urgent mail instead of the away inbox (empty = no diversion).
+	Fallback string `json:"fallback,omitempty"`
 }
 
 // QueueConfig represents a work queue configuration.
@@ -489,5 +1190,7 @@ func NewMessagingConfig() *MessagingConfig {
 		Queues:        make(map[string]QueueConfig),
 		Announces:     make(map[string]AnnounceConfig),
 		NudgeChannels: make(map[string][]string),
+		Away:          make(map[string]AwayConfig),
+		Filters:       make(map[string]MailFilterConfig),
 	}
 }
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTemplateResyncInMaintenanceWindow(t *testing.T) {
+	at := func(hh, mm int) time.Time {
+		return time.Date(2026, 1, 1, hh, mm, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name   string
+		cfg    *TemplateResyncConfig
+		now    time.Time
+		expect bool
+	}{
+		{"default inside", nil, at(3, 0), true},
+		{"default outside", nil, at(12, 0), false},
+		{"end exclusive", &TemplateResyncConfig{MaintenanceWindow: "01:00-02:00"}, at(2, 0), false},
+		{"wraps midnight late", &TemplateResyncConfig{MaintenanceWindow: "23:00-01:30"}, at(23, 30), true},
+		{"wraps midnight early", &TemplateResyncConfig{MaintenanceWindow: "23:00-01:30"}, at(1, 0), true},
+		{"wraps midnight outside", &TemplateResyncConfig{MaintenanceWindow: "23:00-01:30"}, at(12, 0), false},
+		{"invalid", &TemplateResyncConfig{MaintenanceWindow: "soon"}, at(3, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.InMaintenanceWindow(tt.now); got != tt.expect {
+				t.Errorf("InMaintenanceWindow(%s) = %v, want %v", tt.now.Format("15:04"), got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestProtectedPathsMatch(t *testing.T) {
+	cfg := &ProtectedPathsConfig{Paths: []string{"infra/", "/db/migrations/", "deploy/*.yaml", "*.tf"}}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"infra/main.go", "infra/"},
+		{"infra/k8s/app.yaml", "infra/"},
+		{"infrastructure.md", ""},
+		{"db/migrations/001_init.sql", "db/migrations/"},
+		{"deploy/prod.yaml", "deploy/*.yaml"},
+		{"deploy/prod/app.yaml", ""},
+		{"modules/vpc/main.tf", "*.tf"},
+		{"src/main.go", ""},
+	}
+	for _, tt := range tests {
+		if got := cfg.Match(tt.file); got != tt.want {
+			t.Errorf("Match(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+
+	var none *ProtectedPathsConfig
+	if got := none.Match("infra/main.go"); got != "" {
+		t.Errorf("nil config Match = %q, want none", got)
+	}
+	if got := none.ApproverAddress(); got != DefaultProtectedPathsApprover {
+		t.Errorf("nil config ApproverAddress = %q", got)
+	}
+}
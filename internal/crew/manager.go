@@ -10,11 +10,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cursorworkshop/cursor-gastown/internal/agent"
 	"github.com/cursorworkshop/cursor-gastown/internal/beads"
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
-	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
 	"github.com/cursorworkshop/cursor-gastown/internal/git"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
 	"github.com/cursorworkshop/cursor-gastown/internal/rig"
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
@@ -464,12 +465,26 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		}
 	}
 
-	// Ensure Claude settings exist in crew/ (not crew/<name>/) so we don't
-	// write into the source repo. Cursor walks up the tree to find settings.
+	// Ensure agent settings exist in crew/ (not crew/<name>/) so we don't
+	// write into the source repo. Agents walk up the tree to find settings.
 	// All crew members share the same settings file.
 	crewBaseDir := filepath.Join(m.rig.Path, "crew")
-	if err := cursor.EnsureSettingsForRole(crewBaseDir, "crew"); err != nil {
-		return fmt.Errorf("ensuring Cursor settings: %w", err)
+	agentName := "cursor"
+	if rc := config.ResolveAgentConfig(filepath.Dir(m.rig.Path), m.rig.Path); rc != nil && rc.Command != "" {
+		agentName = agent.AgentForCommand(rc.Command)
+	}
+	if err := agent.EnsureSettingsForRole(crewBaseDir, "crew", agentName); err != nil {
+		return fmt.Errorf("ensuring agent settings: %w", err)
+	}
+
+	// Crew checkouts are not checked: a human may be mid-rebase on purpose
+	if err := preflight.BeforeSpawn(preflight.Spec{
+		Session:     sessionID,
+		Role:        session.RoleCrew,
+		RigPath:     m.rig.Path,
+		SettingsDir: crewBaseDir,
+	}); err != nil {
+		return err
 	}
 
 	// Create tmux session
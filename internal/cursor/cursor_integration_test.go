@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -51,17 +52,14 @@ func TestCursorConfigurationSetup(t *testing.T) {
 			t.Error("hooks.json not created")
 		}
 
-		// Verify hook scripts
-		scripts := []string{"gastown-prompt.sh", "gastown-stop.sh", "gastown-shell.sh"}
-		for _, script := range scripts {
-			scriptPath := filepath.Join(workDir, ".cursor", "hooks", script)
-			info, err := os.Stat(scriptPath)
-			if os.IsNotExist(err) {
-				t.Errorf("hook script %s not created", script)
-				continue
-			}
-			if info.Mode()&0111 == 0 {
-				t.Errorf("hook script %s is not executable", script)
+		// Verify hooks run gt cursor-hook subcommands
+		data, err := os.ReadFile(hooksPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, sub := range []string{"cursor-hook before-submit", "cursor-hook stop", "cursor-hook before-shell"} {
+			if !strings.Contains(string(data), sub) {
+				t.Errorf("hooks.json does not run gt %s", sub)
 			}
 		}
 	})
@@ -0,0 +1,214 @@
+package cursor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+)
+
+// baseRequiredHooks are the hooks every role needs: mail delivery on each
+// prompt and cost recording when the agent loop ends.
+var baseRequiredHooks = []string{"beforeSubmitPrompt", "stop"}
+
+// roleRequiredHooks lists the hooks each role needs beyond baseRequiredHooks.
+// Doctor reports a role's hooks.json as stale when any is missing.
+var roleRequiredHooks = map[string][]string{
+	// Interactive roles get mail and role context injected at session start
+	"mayor": {"sessionStart"},
+	"crew":  {"sessionStart"},
+
+	// Polecats run long sessions that must re-prime after compaction, and
+	// their edits are captured for review with gt replay
+	"polecat": {"sessionStart", "preCompact", "afterFileEdit"},
+
+	// Conflict resolution edits made during merges are captured for review
+	"refinery": {"preCompact", "afterFileEdit"},
+
+	// Patrol loops run indefinitely and must re-prime after compaction
+	"witness": {"preCompact"},
+	"deacon":  {"preCompact"},
+}
+
+// roleOmittedHooks lists hooks.json events left out of a role's generated
+// hooks. Witness and deacon patrol without editing code, so capturing file
+// edits would only add noise to their replays.
+var roleOmittedHooks = map[string][]string{
+	"witness": {"afterFileEdit"},
+	"deacon":  {"afterFileEdit"},
+}
+
+// OptionalHooks are the hooks.json events a town can turn on or off per role
+// with cursor_hooks in settings/config.json: afterFileEdit tracks changes for
+// gt replay, beforeShellExecution audits commands before they run.
+var OptionalHooks = []string{"afterFileEdit", "beforeShellExecution"}
+
+// RequiredHooks returns the hooks.json events a role cannot work without:
+// its defaults plus the optional hooks enabled for it in hooks, less those
+// disabled. Unknown roles get the base requirements.
+func RequiredHooks(role string, hooks *config.CursorHooksConfig) []string {
+	enable, disable := optionalHookOverrides(role, hooks)
+	required := append(slices.Clone(baseRequiredHooks), roleRequiredHooks[role]...)
+	required = slices.DeleteFunc(required, func(e string) bool {
+		return slices.Contains(disable, e)
+	})
+	for _, e := range enable {
+		if !slices.Contains(required, e) {
+			required = append(required, e)
+		}
+	}
+	return required
+}
+
+// HookEvents returns the hooks.json events generated for role, in template
+// order. An empty role gets every event in the template.
+func HookEvents(role string, hooks *config.CursorHooksConfig) ([]string, error) {
+	template, err := hooksFS.ReadFile("config/hooks.json")
+	if err != nil {
+		return nil, err
+	}
+	if template, err = templates.RenderConfig("hooks.json", template, templates.ConfigVars{}); err != nil {
+		return nil, err
+	}
+	events, _, err := templateHookEvents(template)
+	if err != nil {
+		return nil, err
+	}
+	omit := omittedHooks(role, hooks)
+	return slices.DeleteFunc(events, func(e string) bool {
+		return slices.Contains(omit, e)
+	}), nil
+}
+
+// UnknownOptionalHooks returns the hook names in hooks that are not
+// OptionalHooks, as "role: name". They are ignored when generating hooks.
+func UnknownOptionalHooks(hooks *config.CursorHooksConfig) []string {
+	if hooks == nil {
+		return nil
+	}
+	var unknown []string
+	for role, rc := range hooks.Roles {
+		if rc == nil {
+			continue
+		}
+		for _, name := range append(slices.Clone(rc.Enable), rc.Disable...) {
+			if !slices.Contains(OptionalHooks, name) {
+				unknown = append(unknown, fmt.Sprintf("%s: %s", role, name))
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return slices.Compact(unknown)
+}
+
+// omittedHooks returns the events left out of role's generated hooks.json:
+// roleOmittedHooks less the optional hooks enabled in hooks, plus those
+// disabled.
+func omittedHooks(role string, hooks *config.CursorHooksConfig) []string {
+	enable, disable := optionalHookOverrides(role, hooks)
+	omit := slices.DeleteFunc(slices.Clone(roleOmittedHooks[role]), func(e string) bool {
+		return slices.Contains(enable, e)
+	})
+	for _, e := range disable {
+		if !slices.Contains(omit, e) {
+			omit = append(omit, e)
+		}
+	}
+	return omit
+}
+
+// optionalHookOverrides returns the OptionalHooks enabled and disabled for
+// role. Unknown names are dropped; a hook both enabled and disabled is
+// enabled.
+func optionalHookOverrides(role string, hooks *config.CursorHooksConfig) (enable, disable []string) {
+	rc := hooks.Role(role)
+	if rc == nil {
+		return nil, nil
+	}
+	for _, e := range rc.Enable {
+		if slices.Contains(OptionalHooks, e) {
+			enable = append(enable, e)
+		}
+	}
+	for _, e := range rc.Disable {
+		if slices.Contains(OptionalHooks, e) && !slices.Contains(enable, e) {
+			disable = append(disable, e)
+		}
+	}
+	return enable, disable
+}
+
+// templateHookEvents returns the events of a hooks.json template in file
+// order, with each event's raw hook list.
+func templateHookEvents(template []byte) ([]string, map[string]json.RawMessage, error) {
+	var doc struct {
+		Hooks json.RawMessage `json:"hooks"`
+	}
+	if err := json.Unmarshal(template, &doc); err != nil {
+		return nil, nil, fmt.Errorf("parsing hooks.json template: %w", err)
+	}
+	var hooks map[string]json.RawMessage
+	if err := json.Unmarshal(doc.Hooks, &hooks); err != nil {
+		return nil, nil, fmt.Errorf("parsing hooks.json template: %w", err)
+	}
+
+	// Object keys come back unordered; recover file order from the tokens.
+	dec := json.NewDecoder(bytes.NewReader(doc.Hooks))
+	var order []string
+	if _, err := dec.Token(); err != nil { // Opening brace
+		return nil, nil, err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		order = append(order, tok.(string))
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, nil, err
+		}
+	}
+	return order, hooks, nil
+}
+
+// filterHooksTemplate rewrites a hooks.json template without the events in
+// omit, keeping the template's layout for the events that remain.
+func filterHooksTemplate(template []byte, omit []string) ([]byte, error) {
+	order, hooks, err := templateHookEvents(template)
+	if err != nil {
+		return nil, err
+	}
+	var version struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(template, &version); err != nil {
+		return nil, err
+	}
+
+	var kept []string
+	for _, event := range order {
+		if !slices.Contains(omit, event) {
+			kept = append(kept, event)
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "{\n  \"version\": %d,\n  \"hooks\": {\n", version.Version)
+	for i, event := range kept {
+		fmt.Fprintf(&out, "    %q: ", event)
+		if err := json.Indent(&out, hooks[event], "    ", "  "); err != nil {
+			return nil, err
+		}
+		if i < len(kept)-1 {
+			out.WriteString(",")
+		}
+		out.WriteString("\n")
+	}
+	out.WriteString("  }\n}\n")
+	return out.Bytes(), nil
+}
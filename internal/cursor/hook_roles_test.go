@@ -0,0 +1,131 @@
+package cursor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+func TestRoleHooksCoverRequirements(t *testing.T) {
+	for _, role := range []string{"mayor", "deacon", "witness", "refinery", "crew", "polecat", ""} {
+		events, err := HookEvents(role, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, hook := range RequiredHooks(role, nil) {
+			if !slices.Contains(events, hook) {
+				t.Errorf("role %q requires %s but it is not generated", role, hook)
+			}
+		}
+	}
+
+	if slices.Contains(RequiredHooks("witness", nil), "afterFileEdit") || !slices.Contains(RequiredHooks("refinery", nil), "afterFileEdit") {
+		t.Error("afterFileEdit should be required of the refinery only")
+	}
+	if !slices.Contains(RequiredHooks("mayor", nil), "sessionStart") {
+		t.Error("mayor should require sessionStart")
+	}
+}
+
+func TestOptionalHooksPerRole(t *testing.T) {
+	hooks := &config.CursorHooksConfig{Roles: map[string]*config.RoleHooksConfig{
+		"witness": {Enable: []string{"afterFileEdit", "beforeShellExecution"}},
+		"polecat": {Disable: []string{"afterFileEdit", "stop"}},
+		"crew":    {Enable: []string{"beforeShellExecution"}, Disable: []string{"beforeShellExecution"}},
+		"mayor":   {Disable: []string{"beforeShellExecution"}},
+	}}
+
+	for _, role := range []string{"mayor", "deacon", "witness", "refinery", "crew", "polecat"} {
+		events, err := HookEvents(role, hooks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, hook := range RequiredHooks(role, hooks) {
+			if !slices.Contains(events, hook) {
+				t.Errorf("role %q requires %s but it is not generated", role, hook)
+			}
+		}
+	}
+
+	witness, _ := HookEvents("witness", hooks)
+	if !slices.Contains(witness, "afterFileEdit") || !slices.Contains(RequiredHooks("witness", hooks), "beforeShellExecution") {
+		t.Errorf("witness should generate and require its enabled hooks, got %v", witness)
+	}
+	polecat, _ := HookEvents("polecat", hooks)
+	if slices.Contains(polecat, "afterFileEdit") || slices.Contains(RequiredHooks("polecat", hooks), "afterFileEdit") {
+		t.Error("polecat afterFileEdit should be disabled")
+	}
+	if !slices.Contains(polecat, "stop") || !slices.Contains(RequiredHooks("polecat", hooks), "stop") {
+		t.Error("stop is not optional and cannot be disabled")
+	}
+	if crew, _ := HookEvents("crew", hooks); !slices.Contains(crew, "beforeShellExecution") {
+		t.Error("a hook both enabled and disabled should be enabled")
+	}
+	if mayor, _ := HookEvents("mayor", hooks); slices.Contains(mayor, "beforeShellExecution") {
+		t.Error("mayor beforeShellExecution should be disabled")
+	}
+
+	want := []string{"polecat: stop"}
+	if got := UnknownOptionalHooks(hooks); !slices.Equal(got, want) {
+		t.Errorf("UnknownOptionalHooks = %v, want %v", got, want)
+	}
+}
+
+func TestFilterHooksTemplateKeepsLayout(t *testing.T) {
+	template, err := configTemplates{}.read(hooksFS, "hooks.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := filterHooksTemplate(template, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, template) {
+		t.Errorf("unfiltered template changed layout:\n%s", got)
+	}
+}
+
+func TestEnsureHooksForRole(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureHooksForRole(dir, "witness"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".cursor", "hooks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg HooksConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("generated hooks.json is invalid: %v\n%s", err, data)
+	}
+	if cfg.GTRole != "witness" {
+		t.Errorf("gt_role = %q, want witness", cfg.GTRole)
+	}
+	if _, ok := cfg.Hooks["afterFileEdit"]; ok {
+		t.Error("witness hooks include afterFileEdit")
+	}
+	if _, ok := cfg.Hooks["afterShellExecution"]; !ok {
+		t.Error("witness hooks lost afterShellExecution")
+	}
+
+	if !HooksCurrent(dir) {
+		t.Error("HooksCurrent = false right after install")
+	}
+	if HooksCurrentForRole(dir, "refinery") {
+		t.Error("witness hooks count as current for the refinery")
+	}
+
+	// Re-syncing without a role keeps the recorded one
+	if err := EnsureHooks(dir); err != nil {
+		t.Fatal(err)
+	}
+	if role := InstalledHooksRole(dir); role != "witness" {
+		t.Errorf("role after EnsureHooks = %q, want witness", role)
+	}
+}
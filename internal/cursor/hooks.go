@@ -1,19 +1,31 @@
 package cursor
 
 import (
+	"bytes"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/settingsbackup"
 )
 
-//go:embed config/hooks.json config/gastown-session-start.sh config/gastown-prompt.sh config/gastown-precompact.sh config/gastown-stop.sh config/gastown-session-end.sh config/gastown-shell.sh
+//go:embed config/hooks.json
 var hooksFS embed.FS
 
+// GeneratorVersion is the gt version stamped into generated hooks.json. The
+// gt command sets it at startup; doctor compares installed markers against
+// it to find agents running hooks from an older gt.
+var GeneratorVersion = "dev"
+
 // HooksConfig represents the structure of Cursor's hooks.json
 type HooksConfig struct {
-	Version int                    `json:"version"`
-	Hooks   map[string][]HookEntry `json:"hooks"`
+	Version           int                    `json:"version"`
+	GTVersion         string                 `json:"gt_version,omitempty"`
+	GTRole            string                 `json:"gt_role,omitempty"`
+	GTSettingsVersion int                    `json:"gt_settings_version,omitempty"` // see SettingsVersion
+	Hooks             map[string][]HookEntry `json:"hooks"`
 }
 
 // HookEntry represents a single hook configuration
@@ -21,53 +33,89 @@ type HookEntry struct {
 	Command string `json:"command"`
 }
 
-// EnsureHooks ensures Gas Town hooks are installed in the workspace.
-// This creates .cursor/hooks.json and .cursor/hooks/ directory with hook scripts.
+// legacyHookScripts are the hook scripts older gt versions installed into
+// .cursor/hooks/, in bash (.sh) and PowerShell (.ps1) flavors. hooks.json
+// now runs gt cursor-hook subcommands directly, so a sync removes them.
+var legacyHookScripts = []string{
+	"gastown-session-start",
+	"gastown-prompt",
+	"gastown-precompact",
+	"gastown-stop",
+	"gastown-session-end",
+	"gastown-shell",
+	"gastown-capture",
+}
+
+// EnsureHooks ensures Gas Town hooks are installed in the workspace. This
+// creates .cursor/hooks.json, whose hooks run gt cursor-hook subcommands.
+// The role recorded in an existing hooks.json is kept.
 func EnsureHooks(workDir string) error {
-	cursorDir := filepath.Join(workDir, ".cursor")
-	hooksDir := filepath.Join(cursorDir, "hooks")
+	return EnsureHooksForRole(workDir, InstalledHooksRole(workDir))
+}
 
-	// Create .cursor/hooks directory
-	if err := os.MkdirAll(hooksDir, 0755); err != nil {
-		return fmt.Errorf("creating hooks directory: %w", err)
+// EnsureHooksForRole installs Gas Town hooks generated for role (see
+// HookEvents). An empty role installs every hook in the template. An
+// existing hooks.json is first migrated to the current SettingsVersion; hooks
+// the user added to it are kept, and edited Gas Town hooks are kept too and
+// reported by HookConflicts.
+func EnsureHooksForRole(workDir, role string) error {
+	cursorDir := filepath.Join(workDir, ".cursor")
+	if err := os.MkdirAll(cursorDir, 0755); err != nil {
+		return fmt.Errorf("creating .cursor directory: %w", err)
 	}
 
-	// Always install/update hooks.json to ensure latest hooks are configured
+	// Always install/update hooks.json to ensure latest hooks are configured,
+	// keeping hooks the user added (see mergeHooksConfig)
+	tmpl := templatesFor(workDir)
 	hooksJsonPath := filepath.Join(cursorDir, "hooks.json")
-	content, err := hooksFS.ReadFile("config/hooks.json")
+	content, err := tmpl.renderHookFile("hooks.json", GeneratorVersion, role)
 	if err != nil {
 		return fmt.Errorf("reading hooks.json template: %w", err)
 	}
-	if err := os.WriteFile(hooksJsonPath, content, 0644); err != nil {
+	installed, err := os.ReadFile(hooksJsonPath) //nolint:gosec // G304: path is within the agent workspace
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading hooks.json: %w", err)
+	}
+	if installed != nil {
+		// Upgrade older formats in place before merging (see SettingsVersion)
+		if installed, _, err = MigrateHooksConfig(installed); err != nil {
+			return err
+		}
+	}
+	if content, _, err = mergeHooksConfig(installed, content); err != nil {
+		return err
+	}
+	if err := settingsbackup.WriteFile(hooksJsonPath, content, 0644); err != nil {
 		return fmt.Errorf("writing hooks.json: %w", err)
 	}
 
-	// Install hook scripts
-	hookScripts := []string{
-		"gastown-session-start.sh",
-		"gastown-prompt.sh",
-		"gastown-precompact.sh",
-		"gastown-stop.sh",
-		"gastown-session-end.sh",
-		"gastown-shell.sh",
-	}
+	return removeLegacyHookScripts(workDir)
+}
 
-	for _, script := range hookScripts {
-		scriptPath := filepath.Join(hooksDir, script)
-		
-		// Always overwrite hook scripts to ensure latest version
-		content, err := hooksFS.ReadFile("config/" + script)
-		if err != nil {
-			return fmt.Errorf("reading %s template: %w", script, err)
-		}
-		if err := os.WriteFile(scriptPath, content, 0755); err != nil {
-			return fmt.Errorf("writing %s: %w", script, err)
+// removeLegacyHookScripts removes the hook scripts older gt versions
+// installed (see legacyHookScripts), and .cursor/hooks/ itself once it is
+// empty. Scripts of the user's own are kept.
+func removeLegacyHookScripts(workDir string) error {
+	for _, path := range legacyHookScriptPaths(workDir) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing %s: %w", filepath.Base(path), err)
 		}
 	}
-
+	_ = os.Remove(filepath.Join(workDir, ".cursor", "hooks")) // Fails while the user's scripts remain
 	return nil
 }
 
+// legacyHookScriptPaths returns where older gt versions installed their
+// hook scripts in workDir, in both flavors.
+func legacyHookScriptPaths(workDir string) []string {
+	hooksDir := filepath.Join(workDir, ".cursor", "hooks")
+	paths := make([]string, 0, 2*len(legacyHookScripts))
+	for _, name := range legacyHookScripts {
+		paths = append(paths, filepath.Join(hooksDir, name+".sh"), filepath.Join(hooksDir, name+".ps1"))
+	}
+	return paths
+}
+
 // HooksInstalled checks if Gas Town hooks are installed in the workspace.
 func HooksInstalled(workDir string) bool {
 	hooksJsonPath := filepath.Join(workDir, ".cursor", "hooks.json")
@@ -75,18 +123,169 @@ func HooksInstalled(workDir string) bool {
 	return err == nil
 }
 
+// HooksCurrent reports whether the installed hooks.json in workDir matches
+// the templates embedded in this gt binary (or the town's overrides of
+// them), ignoring version markers. Returns false if any file is missing or
+// its content differs (template drift after a gt upgrade). Hooks are
+// compared against the templates for the role recorded in hooks.json; hooks
+// the user added to hooks.json do not count as drift.
+func HooksCurrent(workDir string) bool {
+	return HooksCurrentForRole(workDir, InstalledHooksRole(workDir))
+}
+
+// HooksCurrentForRole is HooksCurrent against the hooks generated for role,
+// so hooks installed for another role (or before roles were recorded)
+// count as drifted.
+func HooksCurrentForRole(workDir, role string) bool {
+	tmpl := templatesFor(workDir)
+	for _, name := range hookFiles() {
+		got, err := os.ReadFile(installedHookPath(workDir, name)) //nolint:gosec // G304: path is within the agent workspace
+		if err != nil {
+			return false
+		}
+		want, err := tmpl.renderHookFile(name, hookFileVersion(got), role)
+		if err == nil && name == "hooks.json" {
+			want, _, err = mergeHooksConfig(got, want)
+		}
+		if err != nil || !bytes.Equal(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// HookFileStatuses returns how each installed hook file in workDir that is
+// not current differs from the hooks generated for role (see
+// GeneratedStatus): edited by the user, generated from older templates, or
+// both. Hooks the user added to hooks.json are kept by a sync and are not
+// reported on their own.
+func HookFileStatuses(workDir, role string) map[string]FileStatus {
+	tmpl := templatesFor(workDir)
+	statuses := make(map[string]FileStatus)
+	for _, name := range hookFiles() {
+		got, err := readIfExists(installedHookPath(workDir, name))
+		if err != nil {
+			continue
+		}
+		want, err := tmpl.renderHookFile(name, GeneratorVersion, role)
+		if err == nil && name == "hooks.json" && got != nil {
+			want, _, err = mergeHooksConfig(got, want)
+		}
+		if err != nil {
+			continue
+		}
+		if status := GeneratedStatus(got, want); status != FileCurrent {
+			statuses[name] = status
+		}
+	}
+	return statuses
+}
+
+// StaleHookVersions returns the installed hook files in workDir whose gt
+// version marker differs from GeneratorVersion, mapped to the version they
+// were generated by ("" for files predating version markers). Missing files
+// are not reported.
+func StaleHookVersions(workDir string) map[string]string {
+	stale := make(map[string]string)
+	for _, name := range hookFiles() {
+		data, err := os.ReadFile(installedHookPath(workDir, name)) //nolint:gosec // G304: path is within the agent workspace
+		if err != nil {
+			continue
+		}
+		if v := hookFileVersion(data); v != GeneratorVersion {
+			stale[name] = v
+		}
+	}
+	return stale
+}
+
+// InstalledHooksRole returns the role recorded in workDir's hooks.json, or
+// "" if none is recorded.
+func InstalledHooksRole(workDir string) string {
+	data, err := os.ReadFile(installedHookPath(workDir, "hooks.json")) //nolint:gosec // G304: path is within the agent workspace
+	if err != nil {
+		return ""
+	}
+	var cfg HooksConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return ""
+	}
+	return cfg.GTRole
+}
+
+// hookFiles lists the generated hook files. Hooks run gt cursor-hook
+// subcommands rather than scripts, so hooks.json is the only one.
+func hookFiles() []string {
+	return []string{"hooks.json"}
+}
+
+// installedHookPath returns where a hook file is installed under workDir.
+func installedHookPath(workDir, name string) string {
+	return filepath.Join(workDir, ".cursor", name)
+}
+
+// renderHookFile returns a hook template stamped with gt version and
+// template hash markers (see TemplateHash): "gt_version" and
+// "gt_template_hash" fields in hooks.json. hooks.json is generated for role
+// (its events filtered per HookEvents, with the town's optional hook
+// settings) and records the role in a "gt_role" field and its format in
+// "gt_settings_version". An empty version or role is not stamped. Town
+// overrides take the place of embedded templates.
+func (t configTemplates) renderHookFile(name, version, role string) ([]byte, error) {
+	content, err := t.forRole(role).read(hooksFS, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if omit := omittedHooks(role, t.hooks); len(omit) > 0 {
+		if content, err = filterHooksTemplate(content, omit); err != nil {
+			return nil, err
+		}
+	}
+	var stamps []byte
+	fields := []struct {
+		key   string
+		value any
+	}{{"gt_version", version}, {"gt_role", role}, {"gt_settings_version", SettingsVersion}}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		quoted, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		stamps = append(stamps, fmt.Sprintf("  %q: %s,\n", field.key, quoted)...)
+	}
+	body, ok := bytes.CutPrefix(content, []byte("{\n"))
+	if !ok {
+		return content, nil
+	}
+	stamped := append(append([]byte("{\n"), stamps...), body...)
+	hash := fmt.Sprintf("  %q: %q,\n", hooksHashField, TemplateHash(stamped))
+	return append(append([]byte("{\n"), stamps...), append([]byte(hash), body...)...), nil
+}
+
+// hookFileVersion extracts the gt version recorded in installed hooks.json.
+func hookFileVersion(data []byte) string {
+	var cfg HooksConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return ""
+	}
+	return cfg.GTVersion
+}
+
 // RemoveHooks removes Gas Town hooks from the workspace.
 func RemoveHooks(workDir string) error {
-	hooksDir := filepath.Join(workDir, ".cursor", "hooks")
 	hooksJsonPath := filepath.Join(workDir, ".cursor", "hooks.json")
 
-	// Remove hooks directory
-	if err := os.RemoveAll(hooksDir); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("removing hooks directory: %w", err)
+	// Remove scripts left by older gt versions
+	if err := removeLegacyHookScripts(workDir); err != nil {
+		return err
 	}
 
 	// Remove hooks.json
-	if err := os.Remove(hooksJsonPath); err != nil && !os.IsNotExist(err) {
+	if err := settingsbackup.Remove(hooksJsonPath); err != nil {
 		return fmt.Errorf("removing hooks.json: %w", err)
 	}
 
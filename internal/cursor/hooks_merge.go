@@ -0,0 +1,292 @@
+package cursor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// gastownHookPattern matches a hooks.json command running a gt cursor-hook
+// subcommand, capturing the subcommand and its arguments. The gt binary may
+// be named by any path, quoted or not. Entries running one of these are
+// owned by gt; every other entry belongs to the user and survives
+// regeneration.
+var gastownHookPattern = regexp.MustCompile(`^(?:"[^"]*[/\\]gt(?:\.exe)?"|(?:\S*[/\\])?gt(?:\.exe)?) cursor-hook ([a-z][a-z-]*)(.*)$`)
+
+// legacyScriptPattern matches the Gas Town hook script a hooks.json command
+// from an older gt runs (see legacyHookScripts). Such entries are gt's too,
+// and are dropped since no generated entry runs a script.
+var legacyScriptPattern = regexp.MustCompile(`\.cursor/hooks/(gastown-[A-Za-z0-9_-]+\.(?:sh|ps1))`)
+
+// generatedTopLevelKeys are the hooks.json fields gt writes itself.
+var generatedTopLevelKeys = []string{"gt_version", "gt_role", "gt_settings_version", "gt_template_hash", "version", "hooks"}
+
+// HookConflict is a Gas Town hook the user edited in hooks.json. Merges keep
+// the user's entry instead of the generated one and report it, so a
+// customization is never silently overwritten.
+type HookConflict struct {
+	Event   string // hooks.json event, e.g. "stop"
+	Command string // the installed (user-edited) command
+	Want    string // the command gt would generate
+}
+
+// String formats the conflict for doctor and gt hooks sync output.
+func (c HookConflict) String() string {
+	return fmt.Sprintf("%s: %q (generated: %q)", c.Event, c.Command, c.Want)
+}
+
+// HookConflicts returns the user edits to Gas Town hooks in workDir's
+// hooks.json that a merge with the hooks generated for role would keep.
+// Returns nil if hooks.json is missing or unreadable.
+func HookConflicts(workDir, role string) []HookConflict {
+	installed, err := os.ReadFile(installedHookPath(workDir, "hooks.json")) //nolint:gosec // G304: path is within the agent workspace
+	if err != nil {
+		return nil
+	}
+	generated, err := templatesFor(workDir).renderHookFile("hooks.json", GeneratorVersion, role)
+	if err != nil {
+		return nil
+	}
+	_, conflicts, err := mergeHooksConfig(installed, generated)
+	if err != nil {
+		return nil
+	}
+	return conflicts
+}
+
+// mergeHooksConfig merges an installed hooks.json into freshly generated
+// content. Gas Town entries come from generated; user entries (commands not
+// running gt cursor-hook or a Gas Town script), user-only events and unknown
+// top-level fields are kept from installed. An installed Gas Town entry that
+// differs from the generated one for the same event and subcommand is kept
+// and reported as a conflict; one differing only in the path of the gt
+// binary (gt moved) is not. Gas Town entries the generated hooks no longer
+// have are dropped.
+//
+// When installed has nothing of the user's, generated is returned unchanged.
+// An installed file that is not valid JSON is an error rather than being
+// overwritten.
+func mergeHooksConfig(installed, generated []byte) ([]byte, []HookConflict, error) {
+	if installed == nil {
+		return generated, nil, nil
+	}
+
+	var instTop map[string]json.RawMessage
+	if err := json.Unmarshal(installed, &instTop); err != nil {
+		return nil, nil, fmt.Errorf("existing hooks.json is not valid JSON (fix or remove it): %w", err)
+	}
+	var instOrder []string
+	instHooks := map[string]json.RawMessage{}
+	if _, ok := instTop["hooks"]; ok {
+		var err error
+		if instOrder, instHooks, err = templateHookEvents(installed); err != nil {
+			return nil, nil, fmt.Errorf("existing hooks.json: %w", err)
+		}
+	}
+	genOrder, genHooks, err := templateHookEvents(generated)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conflicts []HookConflict
+	changed := false
+	merged := make(map[string][]json.RawMessage)
+	var order []string
+
+	for _, event := range genOrder {
+		genEntries, err := hookEntries(genHooks[event])
+		if err != nil {
+			return nil, nil, err
+		}
+		instEntries, err := hookEntries(instHooks[event])
+		if err != nil {
+			return nil, nil, fmt.Errorf("existing hooks.json: %w", err)
+		}
+
+		var entries []json.RawMessage
+		for _, g := range genEntries {
+			entry := g
+			id := hookEntryID(g)
+			for _, inst := range instEntries {
+				if id == "" || hookEntryID(inst) != id {
+					continue
+				}
+				if !jsonEqual(normalizeHookEntry(inst), normalizeHookEntry(g)) {
+					entry = inst
+					conflicts = append(conflicts, HookConflict{Event: event, Command: hookEntryCommand(inst), Want: hookEntryCommand(g)})
+				}
+				break
+			}
+			entries = append(entries, entry)
+		}
+		for _, inst := range instEntries {
+			// Entries of a town template that are not gt's come from
+			// generated already
+			if hookEntryID(inst) == "" && !slices.ContainsFunc(genEntries, func(g json.RawMessage) bool { return jsonEqual(g, inst) }) {
+				entries = append(entries, inst)
+				changed = true
+			}
+		}
+		merged[event] = entries
+		order = append(order, event)
+	}
+
+	// Events only the user hooks into
+	for _, event := range instOrder {
+		if _, ok := genHooks[event]; ok {
+			continue
+		}
+		instEntries, err := hookEntries(instHooks[event])
+		if err != nil {
+			return nil, nil, fmt.Errorf("existing hooks.json: %w", err)
+		}
+		var entries []json.RawMessage
+		for _, inst := range instEntries {
+			if hookEntryID(inst) == "" {
+				entries = append(entries, inst)
+			}
+		}
+		if len(entries) > 0 {
+			merged[event] = entries
+			order = append(order, event)
+			changed = true
+		}
+	}
+
+	var extraKeys []string
+	for key := range instTop {
+		if !slices.Contains(generatedTopLevelKeys, key) {
+			extraKeys = append(extraKeys, key)
+		}
+	}
+	sort.Strings(extraKeys)
+
+	if !changed && len(conflicts) == 0 && len(extraKeys) == 0 {
+		return generated, nil, nil
+	}
+
+	var genTop map[string]json.RawMessage
+	if err := json.Unmarshal(generated, &genTop); err != nil {
+		return nil, nil, err
+	}
+	var out bytes.Buffer
+	out.WriteString("{\n")
+	writeField := func(key string, value json.RawMessage) error {
+		fmt.Fprintf(&out, "  %q: ", key)
+		if err := json.Indent(&out, value, "  ", "  "); err != nil {
+			return err
+		}
+		out.WriteString(",\n")
+		return nil
+	}
+	for _, key := range []string{"gt_version", "gt_role", "gt_settings_version", "gt_template_hash", "version"} {
+		if value, ok := genTop[key]; ok {
+			if err := writeField(key, value); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	for _, key := range extraKeys {
+		if err := writeField(key, instTop[key]); err != nil {
+			return nil, nil, err
+		}
+	}
+	out.WriteString("  \"hooks\": {\n")
+	for i, event := range order {
+		fmt.Fprintf(&out, "    %q: ", event)
+		list := append([]byte("["), bytes.Join(compactEntries(merged[event]), []byte(","))...)
+		if err := json.Indent(&out, append(list, ']'), "    ", "  "); err != nil {
+			return nil, nil, err
+		}
+		if i < len(order)-1 {
+			out.WriteString(",")
+		}
+		out.WriteString("\n")
+	}
+	out.WriteString("  }\n}\n")
+	return out.Bytes(), conflicts, nil
+}
+
+// hookEntries splits an event's raw hook list into its entries.
+func hookEntries(raw json.RawMessage) ([]json.RawMessage, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var entries []json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("parsing hook list: %w", err)
+	}
+	return entries, nil
+}
+
+// hookEntryCommand returns an entry's command, or "" if it has none.
+func hookEntryCommand(entry json.RawMessage) string {
+	var e HookEntry
+	_ = json.Unmarshal(entry, &e)
+	return e.Command
+}
+
+// hookEntryID identifies the Gas Town hook an entry runs: "cursor-hook
+// <name>" for a gt cursor-hook subcommand, the script name for a legacy
+// script, or "" for a user entry.
+func hookEntryID(entry json.RawMessage) string {
+	cmd := hookEntryCommand(entry)
+	if m := gastownHookPattern.FindStringSubmatch(cmd); m != nil {
+		return "cursor-hook " + m[1]
+	}
+	if m := legacyScriptPattern.FindStringSubmatch(cmd); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// normalizeHookEntry returns entry with the gt binary in a gt cursor-hook
+// command replaced by plain "gt", so entries compare equal across gt
+// locations.
+func normalizeHookEntry(entry json.RawMessage) json.RawMessage {
+	m := gastownHookPattern.FindStringSubmatch(hookEntryCommand(entry))
+	if m == nil {
+		return entry
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(entry, &fields); err != nil {
+		return entry
+	}
+	quoted, err := json.Marshal("gt cursor-hook " + m[1] + m[2])
+	if err != nil {
+		return entry
+	}
+	fields["command"] = quoted
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return entry
+	}
+	return normalized
+}
+
+// compactEntries returns entries with insignificant whitespace removed.
+func compactEntries(entries []json.RawMessage) [][]byte {
+	out := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, entry); err != nil {
+			out = append(out, entry)
+			continue
+		}
+		out = append(out, buf.Bytes())
+	}
+	return out
+}
+
+// jsonEqual reports whether two JSON values are equal ignoring whitespace.
+func jsonEqual(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
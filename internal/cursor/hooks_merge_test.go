@@ -0,0 +1,183 @@
+package cursor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnsureHooks_KeepsUserHooks(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := EnsureHooksForRole(tmpDir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+
+	// User adds a hook to a generated event, a user-only event and a field
+	hooksPath := filepath.Join(tmpDir, ".cursor", "hooks.json")
+	var doc map[string]any
+	data, err := os.ReadFile(hooksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	hooks := doc["hooks"].(map[string]any)
+	hooks["stop"] = append(hooks["stop"].([]any), map[string]any{"command": "notify-send done", "timeout": 5})
+	hooks["afterAgentResponse"] = []any{map[string]any{"command": "./log-response.sh"}}
+	doc["team"] = "platform"
+	data, err = json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hooksPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnsureHooksForRole(tmpDir, "polecat"); err != nil {
+		t.Fatalf("EnsureHooksForRole failed: %v", err)
+	}
+	merged, err := os.ReadFile(hooksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg struct {
+		GTRole string                       `json:"gt_role"`
+		Team   string                       `json:"team"`
+		Hooks  map[string][]json.RawMessage `json:"hooks"`
+	}
+	if err := json.Unmarshal(merged, &cfg); err != nil {
+		t.Fatalf("merged hooks.json is invalid: %v", err)
+	}
+	if cfg.GTRole != "polecat" || cfg.Team != "platform" {
+		t.Errorf("top-level fields = %q/%q, want polecat/platform", cfg.GTRole, cfg.Team)
+	}
+	if len(cfg.Hooks["stop"]) != 2 || !strings.Contains(string(cfg.Hooks["stop"][0]), "gt cursor-hook stop") ||
+		!strings.Contains(string(cfg.Hooks["stop"][1]), `"timeout": 5`) {
+		t.Errorf("stop hooks = %s, want generated hook then user hook with its fields", cfg.Hooks["stop"])
+	}
+	if len(cfg.Hooks["afterAgentResponse"]) != 1 {
+		t.Error("user-only event was dropped")
+	}
+
+	if !HooksCurrentForRole(tmpDir, "polecat") {
+		t.Error("user hooks should not count as template drift")
+	}
+	if conflicts := HookConflicts(tmpDir, "polecat"); len(conflicts) != 0 {
+		t.Errorf("HookConflicts = %v, want none", conflicts)
+	}
+
+	// Merging again is stable
+	if err := EnsureHooksForRole(tmpDir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+	again, err := os.ReadFile(hooksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(merged) {
+		t.Errorf("second merge changed hooks.json:\n%s\nwant:\n%s", again, merged)
+	}
+}
+
+func TestEnsureHooks_ReportsEditedGastownHook(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := EnsureHooksForRole(tmpDir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+
+	hooksPath := filepath.Join(tmpDir, ".cursor", "hooks.json")
+	data, err := os.ReadFile(hooksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data),
+		`"gt cursor-hook stop"`,
+		`"gt cursor-hook stop --quiet"`, 1)
+	if edited == string(data) {
+		t.Fatal("stop hook not found in generated hooks.json")
+	}
+	if err := os.WriteFile(hooksPath, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnsureHooksForRole(tmpDir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(hooksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "gt cursor-hook stop --quiet") {
+		t.Error("edited Gas Town hook was overwritten")
+	}
+	if strings.Count(string(data), "hook stop") != 1 {
+		t.Error("generated stop hook should not be added next to the edited one")
+	}
+
+	conflicts := HookConflicts(tmpDir, "polecat")
+	if len(conflicts) != 1 || conflicts[0].Event != "stop" || !strings.Contains(conflicts[0].Command, "--quiet") {
+		t.Errorf("HookConflicts = %v, want the edited stop hook", conflicts)
+	}
+}
+
+func TestMergeHooksConfig_GTMoved(t *testing.T) {
+	installed := []byte(`{"version": 1, "hooks": {"stop": [{"command": "/old/bin/gt cursor-hook stop"}, {"command": "./notify.sh"}]}}`)
+	generated := []byte(`{"version": 1, "hooks": {"stop": [{"command": "\"/opt/my tools/gt\" cursor-hook stop"}]}}`)
+
+	merged, conflicts, err := mergeHooksConfig(installed, generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("conflicts = %v, want none for a moved gt binary", conflicts)
+	}
+	var cfg HooksConfig
+	if err := json.Unmarshal(merged, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := []HookEntry{{Command: `"/opt/my tools/gt" cursor-hook stop`}, {Command: "./notify.sh"}}
+	if got := cfg.Hooks["stop"]; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("stop hooks = %+v, want %+v", got, want)
+	}
+}
+
+func TestMergeHooksConfig_DropsLegacyScripts(t *testing.T) {
+	installed := []byte(`{"version": 1, "hooks": {"stop": [{"command": "bash -lc '.cursor/hooks/gastown-stop.sh'"}, {"command": "./notify.sh"}]}}`)
+	generated := []byte(`{"version": 1, "hooks": {"stop": [{"command": "gt cursor-hook stop"}]}}`)
+
+	merged, conflicts, err := mergeHooksConfig(installed, generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("conflicts = %v, want none", conflicts)
+	}
+	if strings.Contains(string(merged), "gastown-stop.sh") {
+		t.Errorf("legacy script entry kept:\n%s", merged)
+	}
+}
+
+func TestEnsureHooks_InvalidHooksJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	hooksPath := filepath.Join(tmpDir, ".cursor", "hooks.json")
+	if err := os.MkdirAll(filepath.Dir(hooksPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hooksPath, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnsureHooksForRole(tmpDir, "polecat"); err == nil {
+		t.Error("EnsureHooksForRole should refuse to overwrite invalid hooks.json")
+	}
+	data, err := os.ReadFile(hooksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "{not json" {
+		t.Error("invalid hooks.json was overwritten")
+	}
+}
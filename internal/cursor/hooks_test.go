@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
 )
 
 func TestEnsureHooks(t *testing.T) {
@@ -51,6 +54,8 @@ func TestEnsureHooks(t *testing.T) {
 		"sessionEnd",
 		"beforeShellExecution",
 		"afterShellExecution",
+		"afterFileEdit",
+		"afterMCPExecution",
 	}
 	for _, hook := range requiredHooks {
 		if _, ok := config.Hooks[hook]; !ok {
@@ -59,7 +64,7 @@ func TestEnsureHooks(t *testing.T) {
 	}
 }
 
-func TestEnsureHooks_ScriptsCreated(t *testing.T) {
+func TestEnsureHooks_RunsHookSubcommands(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	err := EnsureHooks(tmpDir)
@@ -67,29 +72,61 @@ func TestEnsureHooks_ScriptsCreated(t *testing.T) {
 		t.Fatalf("EnsureHooks failed: %v", err)
 	}
 
-	// Check hook scripts were created
-	scripts := []string{
-		"gastown-session-start.sh",
-		"gastown-prompt.sh",
-		"gastown-precompact.sh",
-		"gastown-stop.sh",
-		"gastown-session-end.sh",
-		"gastown-shell.sh",
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".cursor", "hooks.json"))
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	for _, script := range scripts {
-		scriptPath := filepath.Join(tmpDir, ".cursor", "hooks", script)
-		info, err := os.Stat(scriptPath)
-		if os.IsNotExist(err) {
-			t.Errorf("hook script %s not created", script)
-			continue
+	var config HooksConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	for event, entries := range config.Hooks {
+		for _, entry := range entries {
+			if !gastownHookPattern.MatchString(entry.Command) {
+				t.Errorf("%s hook %q does not run a gt cursor-hook subcommand", event, entry.Command)
+			}
 		}
+	}
+
+	// No scripts are installed
+	if _, err := os.Stat(filepath.Join(tmpDir, ".cursor", "hooks")); !os.IsNotExist(err) {
+		t.Error("hooks directory should not be created")
+	}
+}
 
-		// Check script is executable
-		if info.Mode()&0111 == 0 {
-			t.Errorf("hook script %s is not executable", script)
+func TestEnsureHooks_RemovesLegacyScripts(t *testing.T) {
+	tmpDir := t.TempDir()
+	hooksDir := filepath.Join(tmpDir, ".cursor", "hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"gastown-stop.sh", "gastown-shell.ps1", "my-hook.sh"} {
+		if err := os.WriteFile(filepath.Join(hooksDir, name), []byte("#!/bin/bash\n"), 0755); err != nil {
+			t.Fatal(err)
 		}
 	}
+
+	if err := EnsureHooks(tmpDir); err != nil {
+		t.Fatalf("EnsureHooks failed: %v", err)
+	}
+	entries, err := os.ReadDir(hooksDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "my-hook.sh" {
+		t.Errorf("hooks directory = %v, want only the user's script", entries)
+	}
+
+	// Without user scripts the directory goes too
+	if err := os.Remove(filepath.Join(hooksDir, "my-hook.sh")); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureHooks(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(hooksDir); !os.IsNotExist(err) {
+		t.Error("empty hooks directory should be removed")
+	}
 }
 
 func TestEnsureHooks_Idempotent(t *testing.T) {
@@ -129,6 +166,67 @@ func TestHooksInstalled(t *testing.T) {
 	}
 }
 
+func TestHooksCurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if HooksCurrent(tmpDir) {
+		t.Error("HooksCurrent should return false before installation")
+	}
+
+	if err := EnsureHooks(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	if !HooksCurrent(tmpDir) {
+		t.Error("HooksCurrent should return true right after installation")
+	}
+
+	// Simulate hooks.json generated by an older gt version
+	stale := `{"version": 1, "hooks": {"stop": [{"command": "bash -lc '.cursor/hooks/gastown-stop.sh'"}]}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".cursor", "hooks.json"), []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if HooksCurrent(tmpDir) {
+		t.Error("HooksCurrent should detect drifted hooks")
+	}
+}
+
+func TestHookVersionMarkers(t *testing.T) {
+	orig := GeneratorVersion
+	t.Cleanup(func() { GeneratorVersion = orig })
+
+	tmpDir := t.TempDir()
+	GeneratorVersion = "0.1.0"
+	if err := EnsureHooks(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, ".cursor", "hooks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config HooksConfig
+	if err := json.Unmarshal(content, &config); err != nil {
+		t.Fatalf("stamped hooks.json is not valid JSON: %v", err)
+	}
+	if config.GTVersion != "0.1.0" {
+		t.Errorf("gt_version = %q, want 0.1.0", config.GTVersion)
+	}
+
+	if stale := StaleHookVersions(tmpDir); len(stale) != 0 {
+		t.Errorf("same version: stale = %v, want none", stale)
+	}
+
+	// Upgrading gt: templates unchanged, so no drift, but hooks.json is stale.
+	GeneratorVersion = "0.2.0"
+	if !HooksCurrent(tmpDir) {
+		t.Error("HooksCurrent should ignore version markers")
+	}
+	stale := StaleHookVersions(tmpDir)
+	if len(stale) != 1 || stale["hooks.json"] != "0.1.0" {
+		t.Errorf("after upgrade: stale = %v, want hooks.json at 0.1.0", stale)
+	}
+}
+
 func TestRemoveHooks(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -173,3 +271,96 @@ func TestEnsureSettings_InstallsHooks(t *testing.T) {
 		t.Error("EnsureSettings should install rules")
 	}
 }
+
+func TestEnsureHooks_TownOverride(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	overrideDir := filepath.Join(townRoot, "templates", "cursor")
+	if err := os.MkdirAll(overrideDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	override := `{
+  "version": 1,
+  "hooks": {
+    "stop": [
+      {
+        "command": {{printf "%s cursor-hook stop" .GTCommand | json}}
+      },
+      {
+        "command": "./team-stop.sh"
+      }
+    ]
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(overrideDir, "hooks.json"), []byte(override), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	workDir := filepath.Join(townRoot, "myrig", "polecats", "toast")
+	if err := EnsureHooksForRole(workDir, "polecat"); err != nil {
+		t.Fatalf("EnsureHooksForRole failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(workDir, ".cursor", "hooks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "./team-stop.sh") {
+		t.Errorf("hooks.json = %s, want town override", content)
+	}
+	if !HooksCurrentForRole(workDir, "polecat") {
+		t.Error("hooks installed from overrides should be current")
+	}
+
+	// Removing the override makes the installed hooks drift from the default
+	if err := os.Remove(filepath.Join(overrideDir, "hooks.json")); err != nil {
+		t.Fatal(err)
+	}
+	if HooksCurrentForRole(workDir, "polecat") {
+		t.Error("HooksCurrentForRole should detect drift from the embedded template")
+	}
+}
+
+func TestEnsureHooks_TownHookSettings(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	settings.CursorHooks = &config.CursorHooksConfig{Roles: map[string]*config.RoleHooksConfig{
+		"witness": {Enable: []string{"afterFileEdit"}, Disable: []string{"beforeShellExecution"}},
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	workDir := filepath.Join(townRoot, "myrig", "witness")
+	if err := EnsureHooksForRole(workDir, "witness"); err != nil {
+		t.Fatalf("EnsureHooksForRole failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(workDir, ".cursor", "hooks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg HooksConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Hooks["afterFileEdit"]; !ok {
+		t.Error("enabled afterFileEdit hook not generated for the witness")
+	}
+	if _, ok := cfg.Hooks["beforeShellExecution"]; ok {
+		t.Error("disabled beforeShellExecution hook generated for the witness")
+	}
+	if !HooksCurrentForRole(workDir, "witness") {
+		t.Error("hooks generated with town settings should be current")
+	}
+}
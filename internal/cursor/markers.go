@@ -0,0 +1,145 @@
+package cursor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+)
+
+// Generated files carry a marker with the template hash: the hash of the
+// file's content without its markers at the time gt wrote it. Comparing it
+// with the hash of the installed content tells user edits apart, and
+// comparing it with the hash of what gt generates now tells template changes
+// apart. Markers by file type:
+//
+//	hooks.json "gt_template_hash": "<hash>" (next to "gt_version")
+//	rules      <!-- generated by gt <version>, template hash <hash> -->
+//	           (after the frontmatter)
+const (
+	hooksHashField     = "gt_template_hash"
+	ruleMarkerFormat   = "<!-- generated by gt %s, template hash %s -->\n"
+	templateHashLength = 12
+)
+
+// markerLinePattern matches the version and template hash marker lines of
+// every generated file type, and of the hook scripts older gt versions
+// generated.
+var markerLinePattern = regexp.MustCompile(`(?m)^(?:  "gt_version": .*,|  "gt_template_hash": .*,|# gt-version: .*|# gt-template-hash: .*|<!-- generated by gt .*, template hash [0-9a-f]+ -->)\n`)
+
+// recordedHashPattern captures the template hash recorded in a marker.
+var recordedHashPattern = regexp.MustCompile(`(?m)^(?:  "gt_template_hash": "([0-9a-f]+)",|# gt-template-hash: ([0-9a-f]+)|<!-- generated by gt .*, template hash ([0-9a-f]+) -->)$`)
+
+// stripMarkers returns content without its version and template hash
+// marker lines.
+func stripMarkers(content []byte) []byte {
+	return markerLinePattern.ReplaceAll(content, nil)
+}
+
+// TemplateHash returns the hash of content ignoring its markers, as recorded
+// in the template hash marker when gt generates it.
+func TemplateHash(content []byte) string {
+	sum := sha256.Sum256(stripMarkers(content))
+	return hex.EncodeToString(sum[:])[:templateHashLength]
+}
+
+// RecordedHash returns the template hash recorded in content's marker, or ""
+// if it has none (files generated before markers, or not by gt).
+func RecordedHash(content []byte) string {
+	m := recordedHashPattern.FindSubmatch(content)
+	if m == nil {
+		return ""
+	}
+	for _, group := range m[1:] {
+		if len(group) > 0 {
+			return string(group)
+		}
+	}
+	return ""
+}
+
+// sameGenerated reports whether two generated files have the same content
+// apart from their markers, e.g. files written by different gt versions from
+// the same templates.
+func sameGenerated(a, b []byte) bool {
+	return bytes.Equal(stripMarkers(a), stripMarkers(b))
+}
+
+// stampRule inserts the generation marker after a rules file's frontmatter.
+// Content without frontmatter is returned unchanged, so it stays invalid
+// rather than gaining a marker line before its first "---".
+func stampRule(content []byte, version string) []byte {
+	frontmatter, body, ok := bytes.Cut(content, []byte("\n---\n"))
+	if !ok || !bytes.HasPrefix(content, []byte("---\n")) {
+		return content
+	}
+	marker := fmt.Sprintf(ruleMarkerFormat, version, TemplateHash(content))
+	stamped := append(append([]byte{}, frontmatter...), "\n---\n"...)
+	stamped = append(stamped, marker...)
+	return append(stamped, body...)
+}
+
+// StampMarkdown prepends the generation marker to a markdown file without
+// frontmatter, such as another agent's instructions file.
+func StampMarkdown(content []byte, version string) []byte {
+	marker := fmt.Sprintf(ruleMarkerFormat, version, TemplateHash(content))
+	return append([]byte(marker), content...)
+}
+
+// FileStatus describes an installed generated file against what gt
+// generates from the current templates.
+type FileStatus string
+
+const (
+	// FileCurrent matches the generated content (markers aside).
+	FileCurrent FileStatus = "current"
+
+	// FileMissing is not installed.
+	FileMissing FileStatus = "missing"
+
+	// FileOutdated is unedited but generated from older templates.
+	FileOutdated FileStatus = "outdated template"
+
+	// FileUserModified was edited after gt wrote it; the templates have
+	// not changed since.
+	FileUserModified FileStatus = "user-modified"
+
+	// FileModifiedOutdated was edited, and the templates changed too.
+	FileModifiedOutdated FileStatus = "user-modified, outdated template"
+
+	// FileUnmarked differs but records no template hash (written before
+	// markers), so the cause is unknown.
+	FileUnmarked FileStatus = "no generation marker"
+)
+
+// GeneratedStatus classifies installed against generated using the
+// template hashes in their markers. generated's own marker is preferred over
+// its hash, since merged files (hooks.json with user hooks) record the hash
+// of the template output rather than of the merged content.
+func GeneratedStatus(installed, generated []byte) FileStatus {
+	switch {
+	case installed == nil:
+		return FileMissing
+	case sameGenerated(installed, generated):
+		return FileCurrent
+	}
+	recorded := RecordedHash(installed)
+	if recorded == "" {
+		return FileUnmarked
+	}
+	want := RecordedHash(generated)
+	if want == "" {
+		want = TemplateHash(generated)
+	}
+	modified := TemplateHash(installed) != recorded
+	outdated := recorded != want
+	switch {
+	case modified && outdated:
+		return FileModifiedOutdated
+	case modified:
+		return FileUserModified
+	default:
+		return FileOutdated
+	}
+}
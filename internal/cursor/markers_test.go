@@ -0,0 +1,53 @@
+package cursor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGeneratedStatus(t *testing.T) {
+	v1 := stampRule([]byte("---\ndescription: x\n---\n\nUse tabs.\n"), "0.1.0")
+	v2 := stampRule([]byte("---\ndescription: x\n---\n\nUse spaces.\n"), "0.2.0")
+	if !bytes.Contains(v1, []byte("---\n<!-- generated by gt 0.1.0, template hash ")) {
+		t.Fatalf("stampRule should add the marker after the frontmatter:\n%s", v1)
+	}
+	if RecordedHash(v1) != TemplateHash(v1) {
+		t.Errorf("recorded hash %q should match the content hash %q", RecordedHash(v1), TemplateHash(v1))
+	}
+	edited := append(append([]byte{}, v1...), "Local note.\n"...)
+
+	tests := []struct {
+		name                string
+		installed, generate []byte
+		want                FileStatus
+	}{
+		{"missing", nil, v1, FileMissing},
+		{"same template, other gt version", v1, stampRule([]byte("---\ndescription: x\n---\n\nUse tabs.\n"), "0.2.0"), FileCurrent},
+		{"template changed", v1, v2, FileOutdated},
+		{"edited", edited, v1, FileUserModified},
+		{"edited and template changed", edited, v2, FileModifiedOutdated},
+		{"no marker", []byte("---\ndescription: x\n---\n\nOld.\n"), v1, FileUnmarked},
+	}
+	for _, tt := range tests {
+		if got := GeneratedStatus(tt.installed, tt.generate); got != tt.want {
+			t.Errorf("%s: GeneratedStatus = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got := stampRule([]byte("no frontmatter\n"), "0.1.0"); string(got) != "no frontmatter\n" {
+		t.Errorf("stampRule without frontmatter = %q, want unchanged", got)
+	}
+}
+
+func TestHookFileMarkers(t *testing.T) {
+	tmpl := configTemplates{}
+	for _, name := range hookFiles() {
+		content, err := tmpl.renderHookFile(name, "0.1.0", "witness")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h := RecordedHash(content); h == "" || h != TemplateHash(content) {
+			t.Errorf("%s: recorded hash %q, content hash %q", name, h, TemplateHash(content))
+		}
+	}
+}
@@ -1,15 +1,25 @@
 package cursor
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/settingsbackup"
 )
 
 // MCPConfig represents the structure of a Cursor mcp.json file.
 // See: https://cursor.com/docs/context/mcp
 type MCPConfig struct {
+	// GTServers lists the servers gt generated from the town and rig
+	// settings (see EnsureMCPForRole).
+	GTServers []string `json:"gt_servers,omitempty"`
+
 	// McpServers maps server names to their configurations.
 	McpServers map[string]MCPServer `json:"mcpServers"`
 }
@@ -137,9 +147,9 @@ func RemoveMCPServer(workDir, name string) error {
 }
 
 // EnsureGasTownMCPServers ensures Gas Town MCP servers are configured.
-// Currently a no-op as Gas Town does not yet have an MCP server.
+// Currently a no-op: the Gas Town server (gt mcp serve) is opt-in, added
+// through the town's mcp settings like any other server.
 func EnsureGasTownMCPServers(workDir string) error {
-	// Future: Add Gas Town MCP server configuration here when available.
 	return nil
 }
 
@@ -219,3 +229,139 @@ func MergeMCPConfigs(configs ...*MCPConfig) *MCPConfig {
 	return result
 }
 
+// mcpManagedKey is the mcp.json field listing the servers gt generated, so
+// servers dropped from the settings are removed while servers the user
+// added by hand are kept.
+const mcpManagedKey = "gt_servers"
+
+// EnsureMCPForRole writes the MCP servers configured for role (town and
+// rig mcp settings) into workDir's .cursor/mcp.json, keeping servers the
+// user added. Nothing is written when no servers are configured and gt has
+// not generated any before.
+func EnsureMCPForRole(workDir, role string) error {
+	installed, generated, managed, err := mcpFileForRole(workDir, role)
+	if err != nil || !managed || mcpEqual(installed, generated) {
+		return err
+	}
+	path := MCPConfigPath(workDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating .cursor directory: %w", err)
+	}
+	if err := settingsbackup.WriteFile(path, generated, 0644); err != nil {
+		return fmt.Errorf("writing mcp.json: %w", err)
+	}
+	return nil
+}
+
+// MCPCurrentForRole reports whether workDir's .cursor/mcp.json has the MCP
+// servers configured for role. It is true when gt manages no servers there.
+func MCPCurrentForRole(workDir, role string) (bool, error) {
+	installed, generated, managed, err := mcpFileForRole(workDir, role)
+	if err != nil {
+		return false, err
+	}
+	return !managed || mcpEqual(installed, generated), nil
+}
+
+// mcpEqual reports whether two mcp.json files hold the same JSON value,
+// ignoring formatting and key order (SaveMCPConfig and EnsureMCPForRole
+// order keys differently).
+func mcpEqual(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// mcpFileForRole returns the installed mcp.json (nil if missing) and the
+// content EnsureMCPForRole would write. managed is false when gt has no
+// servers to write there and has not written any before.
+func mcpFileForRole(workDir, role string) (installed, generated []byte, managed bool, err error) {
+	if installed, err = readIfExists(MCPConfigPath(workDir)); err != nil {
+		return nil, nil, false, err
+	}
+	servers, err := templatesFor(workDir).mcpServers(role)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	generated, managed, err = mergeMCPConfig(installed, servers)
+	return installed, generated, managed, err
+}
+
+// mcpServers returns the MCP server entries role gets from the town and
+// rig settings, rig servers replacing town servers of the same name.
+func (t configTemplates) mcpServers(role string) (map[string]json.RawMessage, error) {
+	servers := make(map[string]json.RawMessage)
+	for _, settings := range []*config.MCPSettings{t.mcp, t.rigMCP} {
+		for _, name := range settings.ServersFor(role) {
+			entry, err := json.Marshal(settings.Servers[name])
+			if err != nil {
+				return nil, fmt.Errorf("mcp server %s: %w", name, err)
+			}
+			servers[name] = entry
+		}
+	}
+	return servers, nil
+}
+
+// mergeMCPConfig sets servers in an installed mcp.json (nil if missing),
+// removing servers gt generated before that are no longer configured and
+// keeping everything else. managed is false when there is nothing for gt
+// to do: no servers and none generated before.
+func mergeMCPConfig(installed []byte, servers map[string]json.RawMessage) ([]byte, bool, error) {
+	top := make(map[string]json.RawMessage)
+	existing := make(map[string]json.RawMessage)
+	var previous []string
+	if installed != nil {
+		if err := json.Unmarshal(installed, &top); err != nil {
+			if len(servers) == 0 {
+				return installed, false, nil // Not ours to fix
+			}
+			return nil, false, fmt.Errorf("parsing mcp.json: %w", err)
+		}
+		if raw, ok := top["mcpServers"]; ok {
+			if err := json.Unmarshal(raw, &existing); err != nil {
+				return nil, false, fmt.Errorf("parsing mcp.json mcpServers: %w", err)
+			}
+		}
+		if raw, ok := top[mcpManagedKey]; ok {
+			_ = json.Unmarshal(raw, &previous)
+		}
+	}
+	if len(servers) == 0 && len(previous) == 0 {
+		return installed, false, nil
+	}
+
+	for _, name := range previous {
+		if _, ok := servers[name]; !ok {
+			delete(existing, name)
+		}
+	}
+	names := make([]string, 0, len(servers))
+	for name, entry := range servers {
+		existing[name] = entry
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	delete(top, mcpManagedKey)
+	if len(names) > 0 {
+		managed, _ := json.Marshal(names)
+		top[mcpManagedKey] = managed
+	}
+	serversJSON, err := json.Marshal(existing)
+	if err != nil {
+		return nil, false, err
+	}
+	top["mcpServers"] = serversJSON
+
+	data, err := json.MarshalIndent(top, "", "  ")
+	if err != nil {
+		return nil, false, err
+	}
+	return append(data, '\n'), true, nil
+}
@@ -3,6 +3,8 @@ package cursor
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"testing"
 )
 
@@ -347,3 +349,99 @@ func TestMergeMCPConfigs_NilHandling(t *testing.T) {
 		t.Errorf("expected 1 server, got %d", len(result.McpServers))
 	}
 }
+
+// writeMCPTown creates a town whose settings define MCP servers: github
+// for the refinery only, docs for every role, and a rig-specific tracker.
+func writeMCPTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	files := map[string]string{
+		"mayor/town.json": `{"type":"town","name":"ai"}`,
+		"settings/config.json": `{"type":"town-settings","version":1,"mcp":{
+			"servers":{"github":{"command":"github-mcp"},"docs":{"url":"https://docs.example/mcp"}},
+			"roles":{"refinery":["github"],"*":["docs"]}}}`,
+		"myrig/settings/config.json": `{"type":"rig-settings","version":1,"mcp":{
+			"servers":{"tracker":{"command":"tracker-mcp"}}}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(townRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return townRoot
+}
+
+func TestEnsureMCPForRole(t *testing.T) {
+	townRoot := writeMCPTown(t)
+	refinery := filepath.Join(townRoot, "myrig", "refinery")
+	witness := filepath.Join(townRoot, "myrig", "witness")
+	mayor := filepath.Join(townRoot, "mayor")
+
+	for dir, role := range map[string]string{refinery: "refinery", witness: "witness", mayor: "mayor"} {
+		if err := EnsureSettingsForRole(dir, role); err != nil {
+			t.Fatal(err)
+		}
+	}
+	servers := func(dir string) []string {
+		names, err := ListMCPServers(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(names)
+		return names
+	}
+	if got := servers(refinery); !slices.Equal(got, []string{"docs", "github", "tracker"}) {
+		t.Errorf("refinery servers = %v", got)
+	}
+	if got := servers(witness); !slices.Equal(got, []string{"docs", "tracker"}) {
+		t.Errorf("witness servers = %v", got)
+	}
+	if got := servers(mayor); !slices.Equal(got, []string{"docs"}) {
+		t.Errorf("mayor servers = %v, want no rig servers", got)
+	}
+
+	// Servers the user adds survive; servers dropped from settings go
+	if err := AddMCPServer(witness, "mine", MCPServer{Command: "my-mcp"}); err != nil {
+		t.Fatal(err)
+	}
+	if current, err := MCPCurrentForRole(witness, "witness"); err != nil || !current {
+		t.Errorf("user server made mcp.json stale: %v, %v", current, err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "myrig", "settings", "config.json"), []byte(`{"type":"rig-settings","version":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if current, _ := MCPCurrentForRole(witness, "witness"); current {
+		t.Error("removing a rig server should leave mcp.json stale")
+	}
+	if err := EnsureMCPForRole(witness, "witness"); err != nil {
+		t.Fatal(err)
+	}
+	if got := servers(witness); !slices.Equal(got, []string{"docs", "mine"}) {
+		t.Errorf("witness servers after removal = %v", got)
+	}
+}
+
+func TestEnsureMCPForRole_NoServers(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureMCPForRole(dir, "crew"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(MCPConfigPath(dir)); !os.IsNotExist(err) {
+		t.Errorf("mcp.json written with no servers configured (err = %v)", err)
+	}
+
+	// A user's own (even broken) mcp.json is left alone
+	if err := os.MkdirAll(filepath.Dir(MCPConfigPath(dir)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(MCPConfigPath(dir), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if current, err := MCPCurrentForRole(dir, "crew"); err != nil || !current {
+		t.Errorf("unmanaged mcp.json: current %v, err %v", current, err)
+	}
+}
@@ -0,0 +1,99 @@
+package cursor
+
+import (
+	"embed"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// configTemplates reads the Cursor config templates (rules, hooks.json) for
+// one workspace. A file in the owning town's
+// templates/cursor/ directory replaces the embedded template of the same
+// name, so teams can customize rules and hooks without forking gt. The
+// town's cursor_hooks settings pick the optional hooks each role gets.
+// Templates are rendered with text/template so they can reference the
+// workspace's town, rig, role, session, and gt binary (see
+// templates.ConfigVars).
+type configTemplates struct {
+	overrideDir  string                    // "" when workDir is not inside a town
+	hooks        *config.CursorHooksConfig // nil for role defaults
+	mcp          *config.MCPSettings       // town MCP servers, nil for none
+	rigMCP       *config.MCPSettings       // owning rig's MCP servers, nil for none
+	rigRulesDir  string                    // owning rig's rules fragments, "" for none
+	repoRulesDir string                    // owning rig repository's rules fragments, "" for none
+	vars         templates.ConfigVars
+}
+
+// templatesFor returns the config templates for the town owning workDir.
+func templatesFor(workDir string) configTemplates {
+	tmpl := configTemplates{vars: templates.ConfigVars{WorkDir: workDir, GTBin: templates.GTBinary()}}
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return tmpl
+	}
+	tmpl.overrideDir = filepath.Join(templates.OverrideDir(townRoot), "cursor")
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		tmpl.hooks = settings.CursorHooks
+		tmpl.mcp = settings.MCP
+	}
+	tmpl.vars.TownRoot = townRoot
+	tmpl.vars.TownName, _ = workspace.GetTownName(townRoot)
+	if rel, err := filepath.Rel(townRoot, workDir); err == nil {
+		first, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		if first != "." && first != ".." && first != "mayor" && first != "deacon" {
+			tmpl.vars.RigName = first
+			tmpl.rigRulesDir = filepath.Join(townRoot, first, "settings", "rules")
+			tmpl.repoRulesDir = filepath.Join(townRoot, first, "mayor", "rig", filepath.FromSlash(RepoRulesDir))
+			if settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, first))); err == nil {
+				tmpl.rigMCP = settings.MCP
+				if settings.ProtectedPaths != nil {
+					tmpl.vars.ProtectedPaths = settings.ProtectedPaths.Paths
+				}
+			}
+		}
+	}
+	return tmpl
+}
+
+// forRole returns the templates with the role and its session filled in.
+func (t configTemplates) forRole(role string) configTemplates {
+	t.vars.Role = role
+	switch role {
+	case "mayor":
+		t.vars.Session = session.MayorSessionName()
+	case "deacon":
+		t.vars.Session = session.DeaconSessionName()
+	case "witness":
+		if t.vars.RigName != "" {
+			t.vars.Session = session.WitnessSessionName(t.vars.RigName)
+		}
+	case "refinery":
+		if t.vars.RigName != "" {
+			t.vars.Session = session.RefinerySessionName(t.vars.RigName)
+		}
+	}
+	return t
+}
+
+// read returns the town override for name if there is one, otherwise the
+// embedded config/<name> from fsys, rendered with the workspace's vars.
+func (t configTemplates) read(fsys embed.FS, name string) ([]byte, error) {
+	if t.overrideDir != "" {
+		if content, err := os.ReadFile(filepath.Join(t.overrideDir, name)); err == nil { //nolint:gosec // G304: path is within the town's templates directory
+			return templates.RenderConfig(name, content, t.vars)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	content, err := fsys.ReadFile("config/" + name)
+	if err != nil {
+		return nil, err
+	}
+	return templates.RenderConfig(name, content, t.vars)
+}
@@ -0,0 +1,38 @@
+package cursor
+
+import (
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+)
+
+// RenderedFile is a config file rendered from the embedded templates, named
+// by its path relative to the agent workspace.
+type RenderedFile struct {
+	Name    string
+	Content []byte
+}
+
+// RenderTemplates renders every embedded Cursor template for role with
+// vars, without reading a workspace or town: the base and role rules, every
+// rule pack and hooks.json. Used to test templates
+// against synthetic workspaces (gt templates selftest).
+func RenderTemplates(vars templates.ConfigVars, role string) ([]RenderedFile, error) {
+	packs := make([]string, 0, len(RulePacks))
+	for _, p := range RulePacks {
+		packs = append(packs, p.Name)
+	}
+	rules, err := ComposeRulesWith(vars, role, packs)
+	if err != nil {
+		return nil, err
+	}
+	var files []RenderedFile
+	for _, r := range rules {
+		files = append(files, RenderedFile{Name: ".cursor/rules/" + r.Name, Content: r.Content})
+	}
+
+	tmpl := configTemplates{vars: vars}
+	content, err := tmpl.renderHookFile("hooks.json", GeneratorVersion, role)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, RenderedFile{Name: ".cursor/hooks.json", Content: content}), nil
+}
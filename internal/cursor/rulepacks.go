@@ -0,0 +1,96 @@
+package cursor
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+//go:embed config/packs/*.mdc
+var packsFS embed.FS
+
+// RulePack is a language-specific rules fragment installed alongside the
+// Gas Town rules when a rig's repo uses that language.
+type RulePack struct {
+	Name    string   // Pack name, e.g. "go"
+	Markers []string // Files at the repo root that select the pack
+}
+
+// RulePacks lists the available packs in detection order.
+var RulePacks = []RulePack{
+	{Name: "go", Markers: []string{"go.mod"}},
+	{Name: "node", Markers: []string{"package.json"}},
+	{Name: "python", Markers: []string{"pyproject.toml", "setup.py", "requirements.txt"}},
+	{Name: "rust", Markers: []string{"Cargo.toml"}},
+}
+
+// DetectRulePacks returns the names of the packs whose marker files exist
+// at the root of repoDir.
+func DetectRulePacks(repoDir string) []string {
+	var packs []string
+	for _, p := range RulePacks {
+		for _, marker := range p.Markers {
+			if _, err := os.Stat(filepath.Join(repoDir, marker)); err == nil {
+				packs = append(packs, p.Name)
+				break
+			}
+		}
+	}
+	return packs
+}
+
+// rulePackFile returns the rules file a pack is installed as.
+func rulePackFile(workDir, name string) string {
+	return filepath.Join(workDir, ".cursor", "rules", "gastown-"+name+".mdc")
+}
+
+// EnsureRulePacks installs the named packs into workDir's .cursor/rules and
+// removes installed packs that are no longer selected. Pack files are owned
+// by gt and are rewritten when the embedded version changes (not when only
+// their generation marker would).
+func EnsureRulePacks(workDir string, packs []string) error {
+	selected := make(map[string]bool, len(packs))
+	for _, name := range packs {
+		selected[name] = true
+	}
+
+	for _, p := range RulePacks {
+		path := rulePackFile(workDir, p.Name)
+		if !selected[p.Name] {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("removing %s rule pack: %w", p.Name, err)
+			}
+			continue
+		}
+
+		content, err := packsFS.ReadFile("config/packs/" + p.Name + ".mdc")
+		if err != nil {
+			return fmt.Errorf("reading %s rule pack: %w", p.Name, err)
+		}
+		content = stampRule(content, GeneratorVersion)
+		if existing, err := os.ReadFile(path); err == nil && sameGenerated(existing, content) { //nolint:gosec // G304: path is a gt-owned rules file
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("creating .cursor/rules directory: %w", err)
+		}
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			return fmt.Errorf("writing %s rule pack: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// rigRulePacks returns the packs recorded in the config of the rig that
+// owns a settings directory (<rig>/polecats, <rig>/crew, <rig>/witness,
+// <rig>/refinery), or nil when workDir is not directly inside a rig.
+func rigRulePacks(workDir string) []string {
+	cfg, err := config.LoadRigConfig(filepath.Join(filepath.Dir(workDir), "config.json"))
+	if err != nil {
+		return nil
+	}
+	return cfg.RulePacks
+}
@@ -0,0 +1,68 @@
+package cursor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectRulePacks(t *testing.T) {
+	repo := t.TempDir()
+	if got := DetectRulePacks(repo); len(got) != 0 {
+		t.Errorf("empty repo packs = %v", got)
+	}
+	for _, f := range []string{"go.mod", "package.json", "requirements.txt"} {
+		if err := os.WriteFile(filepath.Join(repo, f), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := DetectRulePacks(repo), []string{"go", "node", "python"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectRulePacks = %v, want %v", got, want)
+	}
+}
+
+func TestEnsureRulePacks(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureRulePacks(dir, []string{"go", "node"}); err != nil {
+		t.Fatalf("EnsureRulePacks: %v", err)
+	}
+	for _, name := range []string{"go", "node"} {
+		if _, err := os.Stat(rulePackFile(dir, name)); err != nil {
+			t.Errorf("%s pack not installed: %v", name, err)
+		}
+	}
+
+	// Dropping a language removes its pack; stale content is rewritten.
+	if err := os.WriteFile(rulePackFile(dir, "go"), []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureRulePacks(dir, []string{"go"}); err != nil {
+		t.Fatalf("EnsureRulePacks: %v", err)
+	}
+	if _, err := os.Stat(rulePackFile(dir, "node")); !os.IsNotExist(err) {
+		t.Error("node pack should be removed")
+	}
+	if content, _ := os.ReadFile(rulePackFile(dir, "go")); string(content) == "old" {
+		t.Error("go pack should be rewritten from the embedded version")
+	}
+}
+
+func TestEnsureSettings_RigRulePacks(t *testing.T) {
+	rigPath := t.TempDir()
+	cfg := `{"type":"rig","version":1,"name":"gastown","rule_packs":["rust"]}`
+	if err := os.WriteFile(filepath.Join(rigPath, "config.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	polecats := filepath.Join(rigPath, "polecats")
+
+	if err := EnsureSettings(polecats, Autonomous); err != nil {
+		t.Fatalf("EnsureSettings: %v", err)
+	}
+	if _, err := os.Stat(rulePackFile(polecats, "rust")); err != nil {
+		t.Errorf("rig rule pack not installed: %v", err)
+	}
+	if _, err := os.Stat(rulePackFile(polecats, "go")); !os.IsNotExist(err) {
+		t.Error("unselected pack should not be installed")
+	}
+}
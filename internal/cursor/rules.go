@@ -0,0 +1,389 @@
+package cursor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/settingsbackup"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+)
+
+// Rule layers, in composition order.
+const (
+	RuleLayerBase = "base" // gastown.mdc, from rules-autonomous.mdc or rules-interactive.mdc
+	RuleLayerPack = "pack" // gastown-<pack>.mdc, language packs detected for the rig
+	RuleLayerRole = "role" // gastown-role-<role>.mdc, from rules-role-<role>.mdc
+	RuleLayerRepo = "repo" // gastown-repo-<name>.mdc, from the rig repo's .gastown/rules.d/<name>.mdc
+	RuleLayerRig  = "rig"  // gastown-rig-<name>.mdc, from <rig>/settings/rules/<name>.mdc
+)
+
+// Name prefixes of the gt-owned fragments a sync replaces and removes.
+const (
+	roleRulePrefix = "gastown-role-"
+	repoRulePrefix = "gastown-repo-"
+	rigRulePrefix  = "gastown-rig-"
+)
+
+// RepoRulesDir is where a rig's repository keeps rules for the agents that
+// work in it (crew and polecats), relative to the repository root.
+const RepoRulesDir = ".gastown/rules.d"
+
+// RuleFile is one file of an agent's composed rules.
+type RuleFile struct {
+	Layer   string
+	Name    string // File name in .cursor/rules/
+	Source  string // Template or file the rules are generated from
+	Content []byte
+}
+
+// rulesDir returns the directory holding a workspace's rules.
+func rulesDir(workDir string) string {
+	return filepath.Join(workDir, ".cursor", "rules")
+}
+
+// ComposeRules returns the rules files for role in workDir in composition
+// order: the base rules for the role type, the rig's language packs, the
+// role's fragment, the repository's fragments (crew and polecats only),
+// then the rig's own fragments, each sorted by name. The role fragment is
+// left out when role is empty. Each file is stamped with a generation
+// marker (see stampRule).
+func ComposeRules(workDir, role string) ([]RuleFile, error) {
+	return composeRules(workDir, RoleTypeFor(role), role)
+}
+
+func composeRules(workDir string, roleType RoleType, role string) ([]RuleFile, error) {
+	return templatesFor(workDir).forRole(role).composeRules(roleType, role, rigRulePacks(workDir))
+}
+
+// ComposeRulesWith returns the rules for role rendered with vars instead of
+// a workspace's: the base rules, the given packs and the role fragment,
+// without town overrides or rig fragments.
+func ComposeRulesWith(vars templates.ConfigVars, role string, packs []string) ([]RuleFile, error) {
+	return configTemplates{vars: vars}.forRole(role).composeRules(RoleTypeFor(role), role, packs)
+}
+
+// composeRules composes the rules for role from t (see ComposeRules).
+func (t configTemplates) composeRules(roleType RoleType, role string, packs []string) ([]RuleFile, error) {
+	base := rulesTemplate(roleType)
+	content, err := t.read(configFS, base)
+	if err != nil {
+		return nil, fmt.Errorf("reading template %s: %w", base, err)
+	}
+	rules := []RuleFile{{Layer: RuleLayerBase, Name: "gastown.mdc", Source: base, Content: content}}
+
+	for _, name := range packs {
+		content, err := packsFS.ReadFile("config/packs/" + name + ".mdc")
+		if err != nil {
+			return nil, fmt.Errorf("reading %s rule pack: %w", name, err)
+		}
+		rules = append(rules, RuleFile{Layer: RuleLayerPack, Name: filepath.Base(rulePackFile("", name)), Source: "packs/" + name + ".mdc", Content: content})
+	}
+
+	if role != "" {
+		source := "rules-role-" + role + ".mdc"
+		content, err := t.read(configFS, source)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading template %s: %w", source, err)
+		}
+		if err == nil {
+			rules = append(rules, RuleFile{Layer: RuleLayerRole, Name: roleRulePrefix + role + ".mdc", Source: source, Content: content})
+		}
+	}
+
+	// Repository fragments are project files, not gt templates: they are
+	// copied as written, since they may quote template syntax of their own
+	if t.repoRulesDir != "" && (role == "crew" || role == "polecat") {
+		repoRules, err := ruleFragments(t.repoRulesDir, RuleLayerRepo, repoRulePrefix, nil)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, repoRules...)
+	}
+
+	if t.rigRulesDir != "" {
+		rigRules, err := ruleFragments(t.rigRulesDir, RuleLayerRig, rigRulePrefix, func(name string, raw []byte) ([]byte, error) {
+			return templates.RenderConfig(name, raw, t.vars)
+		})
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rigRules...)
+	}
+	for i := range rules {
+		rules[i].Content = stampRule(rules[i].Content, GeneratorVersion)
+	}
+	return rules, nil
+}
+
+// ruleFragments returns the *.mdc files in dir, sorted by name, as layer
+// fragments named prefix+<name>. Only regular files are read, so a symlink
+// cannot pull in content from outside dir. render, if set, transforms each
+// file's content.
+func ruleFragments(dir, layer, prefix string, render func(name string, raw []byte) ([]byte, error)) ([]RuleFile, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.mdc"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	var rules []RuleFile
+	for _, path := range matches {
+		if info, err := os.Lstat(path); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		content, err := os.ReadFile(path) //nolint:gosec // G304: path is in the rig's settings or repository rules directory
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(path)
+		if render != nil {
+			if content, err = render(name, content); err != nil {
+				return nil, err
+			}
+		}
+		rules = append(rules, RuleFile{Layer: layer, Name: prefix + name, Source: path, Content: content})
+	}
+	return rules, nil
+}
+
+// JoinRules joins rules files into a single markdown document, in order
+// and without frontmatter or markers, for agents that read one
+// instructions file instead of .cursor/rules/.
+func JoinRules(rules []RuleFile) []byte {
+	var sections [][]byte
+	for _, r := range rules {
+		content := stripMarkers(r.Content)
+		if _, body, ok := bytes.Cut(content, []byte("\n---\n")); ok && bytes.HasPrefix(content, []byte("---\n")) {
+			content = body
+		}
+		if content = bytes.TrimSpace(content); len(content) > 0 {
+			sections = append(sections, content)
+		}
+	}
+	return append(bytes.Join(sections, []byte("\n\n")), '\n')
+}
+
+// Instructions returns rules joined into an instructions file (see
+// JoinRules), stamped with a generation marker.
+func Instructions(rules []RuleFile) []byte {
+	return StampMarkdown(JoinRules(rules), GeneratorVersion)
+}
+
+// InstructionsWithFrontmatter returns rules joined into a single rules
+// file under frontmatter (YAML lines without the "---" delimiters), stamped
+// after it, for agents whose rules need their own frontmatter (Auggie).
+func InstructionsWithFrontmatter(rules []RuleFile, frontmatter string) []byte {
+	content := append([]byte("---\n"+frontmatter+"---\n\n"), JoinRules(rules)...)
+	return stampRule(content, GeneratorVersion)
+}
+
+// InstructionsStatus returns how the instructions file name in workDir
+// differs from the one generated for role (see GeneratedStatus).
+func InstructionsStatus(workDir, role, name string) (FileStatus, error) {
+	rules, err := ComposeRules(workDir, role)
+	if err != nil {
+		return "", err
+	}
+	installed, err := readIfExists(filepath.Join(workDir, name))
+	if err != nil {
+		return "", err
+	}
+	return GeneratedStatus(installed, Instructions(rules)), nil
+}
+
+// InstructionsSettingsFile returns the instructions file name in workDir
+// with the content EnsureInstructionsFile would write. A file it keeps
+// (edited by the user, or current) is returned unchanged.
+func InstructionsSettingsFile(workDir, role, name string) (SettingsFile, error) {
+	path := filepath.Join(workDir, name)
+	rules, err := ComposeRules(workDir, role)
+	if err != nil {
+		return SettingsFile{}, err
+	}
+	installed, err := readIfExists(path)
+	if err != nil {
+		return SettingsFile{}, fmt.Errorf("reading %s: %w", name, err)
+	}
+	generated := Instructions(rules)
+	if installed != nil && GeneratedStatus(installed, generated) != FileOutdated {
+		generated = installed
+	}
+	return SettingsFile{Path: path, Installed: installed, Generated: generated}, nil
+}
+
+// EnsureInstructionsFile writes the rules composed for role to the
+// instructions file name in workDir (GEMINI.md, AGENTS.md). A file gt did
+// not write, or that was edited after gt wrote it, is kept.
+func EnsureInstructionsFile(workDir, role, name string) error {
+	// Create workDir first: whether it exists decides the town it belongs to
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return err
+	}
+	rules, err := ComposeRules(workDir, role)
+	if err != nil {
+		return err
+	}
+	content := Instructions(rules)
+	path := filepath.Join(workDir, name)
+	installed, err := readIfExists(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	if installed != nil && GeneratedStatus(installed, content) != FileOutdated {
+		return nil
+	}
+	if err := settingsbackup.WriteFile(path, content, 0644); err != nil { //nolint:gosec // G306: instructions are not sensitive
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// EnsureRulesForRole installs the composed rules for role in workDir
+// without touching hooks or MCP servers.
+func EnsureRulesForRole(workDir, role string) error {
+	if err := EnsureRulePacks(workDir, rigRulePacks(workDir)); err != nil {
+		return err
+	}
+	return ensureRules(workDir, RoleTypeFor(role), role)
+}
+
+// ensureRules writes the composed rules into workDir. The base rules are
+// only written when missing, so local edits survive; the other fragments
+// are owned by gt and rewritten when they change (markers aside). Role,
+// repository, and rig fragments that are no longer composed are removed
+// (EnsureRulePacks removes packs).
+func ensureRules(workDir string, roleType RoleType, role string) error {
+	rules, err := composeRules(workDir, roleType, role)
+	if err != nil {
+		return err
+	}
+	dir := rulesDir(workDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating .cursor/rules directory: %w", err)
+	}
+
+	for _, r := range rules {
+		path := filepath.Join(dir, r.Name)
+		installed, err := readIfExists(path)
+		if err != nil {
+			return err
+		}
+		if installed != nil && (r.Layer == RuleLayerBase || sameGenerated(installed, r.Content)) {
+			continue
+		}
+		if err := settingsbackup.WriteFile(path, r.Content, 0600); err != nil {
+			return fmt.Errorf("writing %s: %w", r.Name, err)
+		}
+	}
+
+	for _, name := range staleRuleFragments(workDir, rules) {
+		if err := settingsbackup.Remove(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("removing %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// staleRuleFragments returns the installed role, repository, and rig
+// fragments that are not among rules, sorted.
+func staleRuleFragments(workDir string, rules []RuleFile) []string {
+	composed := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		composed[r.Name] = true
+	}
+	entries, err := os.ReadDir(rulesDir(workDir))
+	if err != nil {
+		return nil
+	}
+	var stale []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() && !composed[name] && strings.HasSuffix(name, ".mdc") &&
+			(strings.HasPrefix(name, roleRulePrefix) || strings.HasPrefix(name, repoRulePrefix) ||
+				strings.HasPrefix(name, rigRulePrefix)) {
+			stale = append(stale, name)
+		}
+	}
+	return stale
+}
+
+// RulesReport is the state of a workspace's installed rules against the
+// composed set.
+type RulesReport struct {
+	Missing []string              // Composed files that are not installed
+	Stale   []string              // Installed gt-owned fragments that differ from the composed ones
+	Status  map[string]FileStatus // Why each Stale file differs
+	Extra   []string              // Installed role, repository, or rig fragments that are no longer composed
+	Invalid []string              // Files whose frontmatter Cursor cannot read, with the reason
+}
+
+// OK reports whether the installed rules match the composed set.
+func (r RulesReport) OK() bool {
+	return len(r.Missing)+len(r.Stale)+len(r.Extra)+len(r.Invalid) == 0
+}
+
+// CheckRules compares the rules installed in workDir with the set composed
+// for role, and validates each file's frontmatter. The base rules may be
+// edited locally, so only their presence and frontmatter are checked.
+func CheckRules(workDir, role string) (RulesReport, error) {
+	var report RulesReport
+	rules, err := ComposeRules(workDir, role)
+	if err != nil {
+		return report, err
+	}
+	for _, r := range rules {
+		if err := ValidateRuleFrontmatter(r.Content); err != nil {
+			report.Invalid = append(report.Invalid, fmt.Sprintf("%s (from %s): %v", r.Name, r.Source, err))
+		}
+		installed, err := readIfExists(filepath.Join(rulesDir(workDir), r.Name))
+		if err != nil {
+			return report, err
+		}
+		switch {
+		case installed == nil:
+			report.Missing = append(report.Missing, r.Name)
+		case r.Layer == RuleLayerBase:
+			if err := ValidateRuleFrontmatter(installed); err != nil {
+				report.Invalid = append(report.Invalid, fmt.Sprintf("%s: %v", r.Name, err))
+			}
+		case !sameGenerated(installed, r.Content):
+			report.Stale = append(report.Stale, r.Name)
+			if report.Status == nil {
+				report.Status = make(map[string]FileStatus)
+			}
+			report.Status[r.Name] = GeneratedStatus(installed, r.Content)
+		}
+	}
+	report.Extra = staleRuleFragments(workDir, rules)
+	return report, nil
+}
+
+// ruleFrontmatterKeys are the fields Cursor reads from a rules file.
+var ruleFrontmatterKeys = map[string]bool{"description": true, "globs": true, "alwaysApply": true}
+
+// ValidateRuleFrontmatter checks that content starts with a "---" delimited
+// frontmatter block of Cursor rule fields.
+func ValidateRuleFrontmatter(content []byte) error {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	rest, ok := strings.CutPrefix(text, "---\n")
+	if !ok {
+		return fmt.Errorf("missing frontmatter (file must start with ---)")
+	}
+	block, _, ok := strings.Cut(rest, "\n---")
+	if !ok {
+		return fmt.Errorf("frontmatter is not closed with ---")
+	}
+	for _, line := range strings.Split(block, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, _, ok := strings.Cut(line, ":")
+		if !ok || !ruleFrontmatterKeys[strings.TrimSpace(key)] {
+			return fmt.Errorf("unknown frontmatter line %q (want description, globs, or alwaysApply)", line)
+		}
+	}
+	return nil
+}
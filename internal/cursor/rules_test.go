@@ -0,0 +1,162 @@
+package cursor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestComposeRules(t *testing.T) {
+	townRoot := t.TempDir()
+	files := map[string]string{
+		"mayor/town.json":                    `{"type":"town","name":"ai"}`,
+		"myrig/config.json":                  `{"type":"rig","version":1,"name":"myrig","rule_packs":["go"]}`,
+		"myrig/settings/rules/zz-deploy.mdc": "---\ndescription: Deploys\nalwaysApply: true\n---\n\nDeploy {{.RigName}} with make deploy.\n",
+		"myrig/settings/rules/api.mdc":       "---\ndescription: API\nalwaysApply: true\n---\n\nKeep the API stable.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(townRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	workDir := filepath.Join(townRoot, "myrig", "polecats")
+
+	rules, err := ComposeRules(workDir, "polecat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, r := range rules {
+		names = append(names, r.Name)
+	}
+	want := []string{"gastown.mdc", "gastown-go.mdc", "gastown-role-polecat.mdc", "gastown-rig-api.mdc", "gastown-rig-zz-deploy.mdc"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("composed %v, want %v", names, want)
+	}
+	if !strings.Contains(string(rules[4].Content), "Deploy myrig with make deploy.") {
+		t.Errorf("rig fragment not rendered:\n%s", rules[4].Content)
+	}
+
+	if err := EnsureSettingsForRole(workDir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+	if report, err := CheckRules(workDir, "polecat"); err != nil || !report.OK() {
+		t.Fatalf("after install: report = %+v, err = %v", report, err)
+	}
+
+	// Edited base rules are kept; removed rig fragments and edited gt-owned
+	// fragments are reported and then fixed by a sync
+	rulesDir := filepath.Join(workDir, ".cursor", "rules")
+	if err := os.WriteFile(filepath.Join(rulesDir, "gastown.mdc"), []byte("---\ndescription: mine\n---\nedited\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rulesDir, "gastown-role-polecat.mdc"), []byte("edited"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(townRoot, "myrig", "settings", "rules", "api.mdc")); err != nil {
+		t.Fatal(err)
+	}
+	report, err := CheckRules(workDir, "polecat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(report.Stale, []string{"gastown-role-polecat.mdc"}) || !slices.Equal(report.Extra, []string{"gastown-rig-api.mdc"}) ||
+		len(report.Missing) != 0 || len(report.Invalid) != 0 {
+		t.Fatalf("report = %+v, want the edited role fragment stale and the api fragment extra", report)
+	}
+	if err := EnsureSettingsForRole(workDir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+	if report, err := CheckRules(workDir, "polecat"); err != nil || !report.OK() {
+		t.Errorf("after sync: report = %+v, err = %v", report, err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(rulesDir, "gastown.mdc")); !strings.Contains(string(content), "edited") {
+		t.Error("sync replaced the edited base rules")
+	}
+}
+
+func TestComposeRulesRepoFragments(t *testing.T) {
+	townRoot := t.TempDir()
+	repoRules := filepath.Join(townRoot, "myrig", "mayor", "rig", ".gastown", "rules.d")
+	files := map[string]string{
+		"mayor/town.json":                            `{"type":"town","name":"ai"}`,
+		"myrig/settings/rules/deploy.mdc":            "---\ndescription: Deploys\nalwaysApply: true\n---\n\nDeploy with make deploy.\n",
+		"myrig/mayor/rig/.gastown/rules.d/style.mdc": "---\ndescription: Style\nalwaysApply: true\n---\n\nTemplates use {{.Name}} literally.\n",
+		"outside.mdc":                                "---\ndescription: Outside\n---\n\nNot part of the repo.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(townRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(townRoot, "outside.mdc"), filepath.Join(repoRules, "link.mdc")); err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(role string) []string {
+		rules, err := ComposeRules(filepath.Join(townRoot, "myrig", role+"s"), role)
+		if err != nil {
+			t.Fatalf("ComposeRules(%s): %v", role, err)
+		}
+		var names []string
+		for _, r := range rules {
+			names = append(names, r.Name)
+			if r.Layer == RuleLayerRepo && !strings.Contains(string(r.Content), "{{.Name}} literally") {
+				t.Errorf("repo fragment was rendered as a template:\n%s", r.Content)
+			}
+		}
+		return names
+	}
+	want := []string{"gastown.mdc", "gastown-role-polecat.mdc", "gastown-repo-style.mdc", "gastown-rig-deploy.mdc"}
+	if got := names("polecat"); !slices.Equal(got, want) {
+		t.Errorf("polecat rules = %v, want %v (symlinked fragment skipped)", got, want)
+	}
+	if got := names("witness"); slices.Contains(got, "gastown-repo-style.mdc") {
+		t.Errorf("witness rules = %v, want no repository fragments", got)
+	}
+
+	// A fragment removed from the repository is removed by the next sync
+	workDir := filepath.Join(townRoot, "myrig", "polecats")
+	if err := EnsureSettingsForRole(workDir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(repoRules, "style.mdc")); err != nil {
+		t.Fatal(err)
+	}
+	if report, _ := CheckRules(workDir, "polecat"); !slices.Equal(report.Extra, []string{"gastown-repo-style.mdc"}) {
+		t.Fatalf("report = %+v, want the removed repository fragment extra", report)
+	}
+	if err := EnsureSettingsForRole(workDir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+	if report, err := CheckRules(workDir, "polecat"); err != nil || !report.OK() {
+		t.Errorf("after sync: report = %+v, err = %v", report, err)
+	}
+}
+
+func TestValidateRuleFrontmatter(t *testing.T) {
+	tests := []struct {
+		content string
+		valid   bool
+	}{
+		{"---\ndescription: x\nglobs: \nalwaysApply: true\n---\n\nbody\n", true},
+		{"# no frontmatter\n", false},
+		{"---\ndescription: x\n", false},
+		{"---\ntitle: x\n---\n", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateRuleFrontmatter([]byte(tt.content)); (err == nil) != tt.valid {
+			t.Errorf("ValidateRuleFrontmatter(%q) = %v, want valid %v", tt.content, err, tt.valid)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/settingsbackup"
 )
 
 //go:embed config/*.mdc
@@ -34,50 +36,84 @@ func RoleTypeFor(role string) RoleType {
 }
 
 // EnsureSettings ensures .cursor/rules directory exists with Gas Town rules,
-// and installs Gas Town hooks for Cursor CLI.
+// and installs Gas Town hooks and configured MCP servers for Cursor CLI.
 // For worktrees, we use sparse checkout to exclude source repo's .cursor/ directory,
 // so our rules are the only ones Cursor sees.
 func EnsureSettings(workDir string, roleType RoleType) error {
-	cursorDir := filepath.Join(workDir, ".cursor", "rules")
-	rulesFile := filepath.Join(cursorDir, "gastown.mdc")
+	return ensureSettings(workDir, roleType, "")
+}
 
-	// Create .cursor/rules directory if needed
-	if err := os.MkdirAll(cursorDir, 0755); err != nil {
-		return fmt.Errorf("creating .cursor/rules directory: %w", err)
+// ensureSettings installs rules for roleType and hooks generated for role
+// (keeping the installed hooks' role when role is empty).
+func ensureSettings(workDir string, roleType RoleType, role string) error {
+	hooksRole := role
+	if role == "" {
+		role = InstalledHooksRole(workDir)
+	}
+
+	// Install language rule packs detected for the owning rig
+	if err := EnsureRulePacks(workDir, rigRulePacks(workDir)); err != nil {
+		return err
 	}
 
-	// Install rules file if it doesn't exist
-	if _, err := os.Stat(rulesFile); os.IsNotExist(err) {
-		// Select template based on role type
-		var templateName string
-		switch roleType {
-		case Autonomous:
-			templateName = "config/rules-autonomous.mdc"
-		default:
-			templateName = "config/rules-interactive.mdc"
-		}
-
-		// Read template
-		content, err := configFS.ReadFile(templateName)
-		if err != nil {
-			return fmt.Errorf("reading template %s: %w", templateName, err)
-		}
-
-		// Write rules file
-		if err := os.WriteFile(rulesFile, content, 0600); err != nil {
-			return fmt.Errorf("writing rules: %w", err)
-		}
+	// Compose the rules: base rules (kept if present), packs, and the role
+	// and rig fragments
+	if err := ensureRules(workDir, roleType, role); err != nil {
+		return err
 	}
 
 	// Install Gas Town hooks for Cursor CLI
-	if err := EnsureHooks(workDir); err != nil {
+	installHooks := EnsureHooks
+	if hooksRole != "" {
+		installHooks = func(dir string) error { return EnsureHooksForRole(dir, hooksRole) }
+	}
+	if err := installHooks(workDir); err != nil {
 		return fmt.Errorf("installing hooks: %w", err)
 	}
 
+	// Wire the role's MCP servers into .cursor/mcp.json
+	if err := EnsureMCPForRole(workDir, role); err != nil {
+		return fmt.Errorf("installing MCP servers: %w", err)
+	}
+
 	return nil
 }
 
-// EnsureSettingsForRole is a convenience function that combines RoleTypeFor and EnsureSettings.
+// EnsureSettingsForRole is a convenience function that combines RoleTypeFor and
+// EnsureSettings, generating hooks for the role's hook requirements.
 func EnsureSettingsForRole(workDir, role string) error {
-	return EnsureSettings(workDir, RoleTypeFor(role))
+	return ensureSettings(workDir, RoleTypeFor(role), role)
+}
+
+// ResetRulesForRole overwrites the Gas Town rules file with the template
+// for role. Rules are otherwise only written when missing, so local edits
+// survive a settings sync.
+func ResetRulesForRole(workDir, role string) error {
+	if err := os.MkdirAll(filepath.Join(workDir, ".cursor", "rules"), 0755); err != nil {
+		return fmt.Errorf("creating .cursor/rules directory: %w", err)
+	}
+	return writeRules(workDir, RoleTypeFor(role), role)
+}
+
+// rulesTemplate returns the rules template for a role type.
+func rulesTemplate(roleType RoleType) string {
+	if roleType == Autonomous {
+		return "rules-autonomous.mdc"
+	}
+	return "rules-interactive.mdc"
+}
+
+// writeRules writes the rules file for roleType, rendered for role and
+// preferring the town's template override.
+func writeRules(workDir string, roleType RoleType, role string) error {
+	templateName := rulesTemplate(roleType)
+	content, err := templatesFor(workDir).forRole(role).read(configFS, templateName)
+	if err != nil {
+		return fmt.Errorf("reading template %s: %w", templateName, err)
+	}
+	content = stampRule(content, GeneratorVersion)
+	if err := settingsbackup.WriteFile(filepath.Join(workDir, ".cursor", "rules", "gastown.mdc"), content, 0600); err != nil {
+		return fmt.Errorf("writing rules: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,232 @@
+package cursor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// SettingsFile is a gt-managed Cursor config file in an agent workspace,
+// with the content a sync would give it.
+type SettingsFile struct {
+	// Path is where the file is installed.
+	Path string
+
+	// Installed is the current content, nil if the file is missing.
+	Installed []byte
+
+	// Generated is what gt would write from the current templates, nil
+	// for a rules fragment a sync removes.
+	Generated []byte
+
+	// KeptIfPresent is true for files gt only writes when missing (the
+	// rules file), so a sync leaves differences alone unless asked to
+	// reset them (see ResetRulesForRole).
+	KeptIfPresent bool
+}
+
+// Status explains how a changed file differs (see GeneratedStatus): edited
+// by the user, generated from older templates, or both.
+func (f SettingsFile) Status() FileStatus {
+	return GeneratedStatus(f.Installed, f.Generated)
+}
+
+// Changed reports whether the installed file differs from the generated one.
+func (f SettingsFile) Changed() bool {
+	return f.Installed == nil || !bytes.Equal(f.Installed, f.Generated)
+}
+
+// Pending reports whether a sync would write or remove the file: it
+// changed, and is not a kept file that is already installed.
+func (f SettingsFile) Pending() bool {
+	return f.Changed() && !(f.KeptIfPresent && f.Installed != nil)
+}
+
+// Action is what a sync does to the file: "create", "remove", or "update".
+func (f SettingsFile) Action() string {
+	switch {
+	case f.Installed == nil:
+		return "create"
+	case f.Generated == nil:
+		return "remove"
+	default:
+		return "update"
+	}
+}
+
+// Diff returns a unified diff from the installed file to the generated
+// one, labelled name, with context lines around each change. Returns ""
+// when they are equal.
+func (f SettingsFile) Diff(name string, context int) string {
+	from := name
+	if f.Installed == nil {
+		from = "/dev/null"
+	}
+	return util.UnifiedDiff(from, name+" (generated)", f.Installed, f.Generated, context)
+}
+
+// SettingsPlan is what a settings sync would do in one workspace: the
+// files it would create, update, or remove, with their content.
+type SettingsPlan struct {
+	WorkDir string
+	Role    string
+	Files   []SettingsFile // Only files a sync would write (see SettingsFile.Pending)
+}
+
+// NewSettingsPlan returns the plan for syncing files in workDir.
+func NewSettingsPlan(workDir, role string, files []SettingsFile) *SettingsPlan {
+	plan := &SettingsPlan{WorkDir: workDir, Role: role}
+	for _, f := range files {
+		if f.Pending() {
+			plan.Files = append(plan.Files, f)
+		}
+	}
+	return plan
+}
+
+// Empty reports whether a sync would change nothing.
+func (p *SettingsPlan) Empty() bool {
+	return p == nil || len(p.Files) == 0
+}
+
+// Diff returns the unified diffs of every planned file, labelled by path
+// relative to the workspace.
+func (p *SettingsPlan) Diff(context int) string {
+	if p == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, f := range p.Files {
+		name, err := filepath.Rel(p.WorkDir, f.Path)
+		if err != nil {
+			name = f.Path
+		}
+		b.WriteString(f.Diff(filepath.ToSlash(name), context))
+	}
+	return b.String()
+}
+
+// EnsureOptions changes how settings are ensured.
+type EnsureOptions struct {
+	// DryRun plans the sync without writing anything.
+	DryRun bool
+}
+
+// EnsureSettingsForRoleWith is EnsureSettingsForRole returning the plan:
+// the files the sync writes, or with DryRun would write.
+func EnsureSettingsForRoleWith(workDir, role string, opts EnsureOptions) (*SettingsPlan, error) {
+	plan, err := PlanSettingsForRole(workDir, role)
+	if err != nil || opts.DryRun {
+		return plan, err
+	}
+	return plan, EnsureSettingsForRole(workDir, role)
+}
+
+// PlanSettingsForRole returns what EnsureSettingsForRole would change in
+// workDir, without writing (see GeneratedSettings).
+func PlanSettingsForRole(workDir, role string) (*SettingsPlan, error) {
+	files, err := GeneratedSettings(workDir, role)
+	if err != nil {
+		return nil, err
+	}
+	return NewSettingsPlan(workDir, role, files), nil
+}
+
+// GeneratedSettings returns the gt-managed settings files in workDir for
+// role with the content EnsureSettingsForRole would write: hooks.json
+// migrated and merged with the user's hooks, the legacy hook scripts it
+// removes, mcp.json when MCP servers are configured, and the composed rules
+// files (see ComposeRules). Hooks that are current apart from their version markers
+// are not rewritten by a sync, so they are returned unchanged.
+func GeneratedSettings(workDir, role string) ([]SettingsFile, error) {
+	tmpl := templatesFor(workDir).forRole(role)
+	current := HooksCurrentForRole(workDir, role)
+	var files []SettingsFile
+
+	for _, name := range hookFiles() {
+		path := installedHookPath(workDir, name)
+		installed, err := readIfExists(path)
+		if err != nil {
+			return nil, err
+		}
+		if current {
+			// Only the version markers differ, which a sync leaves alone
+			files = append(files, SettingsFile{Path: path, Installed: installed, Generated: installed})
+			continue
+		}
+		generated, err := tmpl.renderHookFile(name, GeneratorVersion, role)
+		if err != nil {
+			return nil, err
+		}
+		if name == "hooks.json" {
+			base := installed
+			if base != nil {
+				if base, _, err = MigrateHooksConfig(base); err != nil {
+					return nil, err
+				}
+			}
+			if generated, _, err = mergeHooksConfig(base, generated); err != nil {
+				return nil, err
+			}
+		}
+		files = append(files, SettingsFile{Path: path, Installed: installed, Generated: generated})
+	}
+	for _, path := range legacyHookScriptPaths(workDir) {
+		installed, err := readIfExists(path)
+		if err != nil {
+			return nil, err
+		}
+		if installed != nil {
+			files = append(files, SettingsFile{Path: path, Installed: installed})
+		}
+	}
+
+	installedMCP, generatedMCP, managed, err := mcpFileForRole(workDir, role)
+	if err != nil {
+		return nil, err
+	}
+	if managed {
+		if mcpEqual(installedMCP, generatedMCP) {
+			generatedMCP = installedMCP // Only formatting differs, which a sync leaves alone
+		}
+		files = append(files, SettingsFile{Path: MCPConfigPath(workDir), Installed: installedMCP, Generated: generatedMCP})
+	}
+
+	rules, err := ComposeRules(workDir, role)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		path := filepath.Join(rulesDir(workDir), r.Name)
+		installed, err := readIfExists(path)
+		if err != nil {
+			return nil, err
+		}
+		generated := r.Content
+		if installed != nil && sameGenerated(installed, generated) {
+			generated = installed // Only the generation marker differs, which a sync leaves alone
+		}
+		files = append(files, SettingsFile{Path: path, Installed: installed, Generated: generated, KeptIfPresent: r.Layer == RuleLayerBase})
+	}
+	for _, name := range staleRuleFragments(workDir, rules) {
+		path := filepath.Join(rulesDir(workDir), name)
+		installed, err := readIfExists(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, SettingsFile{Path: path, Installed: installed})
+	}
+	return files, nil
+}
+
+// readIfExists reads path, returning nil content if it does not exist.
+func readIfExists(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the agent workspace
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
@@ -0,0 +1,128 @@
+package cursor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedSettings(t *testing.T) {
+	dir := t.TempDir()
+
+	// Nothing installed: every file would be created
+	files, err := GeneratedSettings(dir, "witness")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("got %d files, want hooks.json, the rules and the role's rules", len(files))
+	}
+	for _, f := range files {
+		if f.Installed != nil || !f.Changed() {
+			t.Errorf("%s: installed %v, changed %v", f.Path, f.Installed != nil, f.Changed())
+		}
+	}
+
+	// Freshly synced: nothing would change
+	if err := EnsureSettingsForRole(dir, "witness"); err != nil {
+		t.Fatal(err)
+	}
+	if files, err = GeneratedSettings(dir, "witness"); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if f.Changed() {
+			t.Errorf("%s changed right after a sync", f.Path)
+		}
+	}
+
+	// Outdated hooks and edited rules both show up; only the rules are kept
+	hooks := filepath.Join(dir, ".cursor", "hooks.json")
+	stale := `{"version": 1, "hooks": {"stop": [{"command": "bash -lc '.cursor/hooks/gastown-stop.sh'"}]}}`
+	if err := os.WriteFile(hooks, []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules := filepath.Join(dir, ".cursor", "rules", "gastown.mdc")
+	if err := os.WriteFile(rules, []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if files, err = GeneratedSettings(dir, "witness"); err != nil {
+		t.Fatal(err)
+	}
+	changed := map[string]SettingsFile{}
+	for _, f := range files {
+		if f.Changed() {
+			changed[filepath.Base(f.Path)] = f
+		}
+	}
+	if len(changed) != 2 {
+		t.Fatalf("changed = %v, want hooks.json and the rules", changed)
+	}
+	if f := changed["hooks.json"]; f.KeptIfPresent || !strings.Contains(string(f.Generated), "gt cursor-hook stop") {
+		t.Errorf("hooks.json: %+v", f)
+	}
+	if !changed["gastown.mdc"].KeptIfPresent {
+		t.Error("rules file should be kept if present")
+	}
+
+	// Scripts left by older gt versions are removed
+	script := filepath.Join(dir, ".cursor", "hooks", "gastown-stop.sh")
+	if err := os.MkdirAll(filepath.Dir(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/bash\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if files, err = GeneratedSettings(dir, "witness"); err != nil {
+		t.Fatal(err)
+	}
+	var removed []string
+	for _, f := range files {
+		if f.Installed != nil && f.Generated == nil {
+			removed = append(removed, f.Path)
+		}
+	}
+	if len(removed) != 1 || removed[0] != script {
+		t.Errorf("removed = %v, want the legacy script", removed)
+	}
+}
+
+func TestResetRulesForRole(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureSettingsForRole(dir, "crew"); err != nil {
+		t.Fatal(err)
+	}
+	rules := filepath.Join(dir, ".cursor", "rules", "gastown.mdc")
+	if err := os.WriteFile(rules, []byte("edited\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A sync keeps the edited rules; a reset restores the template
+	if err := EnsureSettingsForRole(dir, "crew"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(rules); string(data) != "edited\n" {
+		t.Fatalf("sync overwrote edited rules: %q", data)
+	}
+	if err := ResetRulesForRole(dir, "crew"); err != nil {
+		t.Fatal(err)
+	}
+	// The edited rules are backed up (next to the file, outside a town)
+	baks, _ := filepath.Glob(rules + ".*.bak")
+	if len(baks) != 1 {
+		t.Fatalf("backups of the edited rules = %v, want one", baks)
+	}
+	if data, _ := os.ReadFile(baks[0]); string(data) != "edited\n" {
+		t.Errorf("backup = %q, want the edited rules", data)
+	}
+	files, err := GeneratedSettings(dir, "crew")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if f.Changed() {
+			t.Errorf("%s still differs after reset", f.Path)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package cursor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// SettingsVersion is the format version of generated hooks.json files,
+// recorded in their "gt_settings_version" field. Bump it and register a
+// migration in settingsMigrations whenever the format changes, so existing
+// files are upgraded in place instead of being deleted and regenerated.
+const SettingsVersion = 3
+
+// settingsMigration upgrades a hooks.json document from version from to
+// from+1.
+type settingsMigration struct {
+	from        int
+	description string
+	apply       func(doc *hooksDoc) error
+}
+
+// settingsMigrations are applied in order to files older than
+// SettingsVersion. Files without a gt_settings_version are version 0.
+var settingsMigrations = []settingsMigration{
+	{0, "rename Claude-style hook events to Cursor events", migrateClaudeEvents},
+	{1, "run Gas Town hook scripts through a login shell", migrateLoginShell},
+	{2, "run Gas Town hooks as gt cursor-hook subcommands", migrateHookSubcommands},
+}
+
+// hooksDoc is a hooks.json document with its event order kept.
+type hooksDoc struct {
+	top    map[string]json.RawMessage
+	order  []string
+	events map[string][]json.RawMessage
+}
+
+// InstalledSettingsVersion returns the settings version recorded in
+// workDir's hooks.json: 0 for files predating versioning, -1 if hooks.json
+// is missing or unreadable.
+func InstalledSettingsVersion(workDir string) int {
+	data, err := os.ReadFile(installedHookPath(workDir, "hooks.json")) //nolint:gosec // G304: path is within the agent workspace
+	if err != nil {
+		return -1
+	}
+	var cfg HooksConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return -1
+	}
+	return cfg.GTSettingsVersion
+}
+
+// MigrateHooksConfig upgrades a hooks.json to SettingsVersion, applying
+// each registered migration past its recorded version. Returns the upgraded
+// content and a description of each migration applied; current files are
+// returned unchanged. Files from a newer gt are an error rather than being
+// downgraded.
+func MigrateHooksConfig(data []byte) ([]byte, []string, error) {
+	var stamp struct {
+		GTSettingsVersion int `json:"gt_settings_version"`
+	}
+	if err := json.Unmarshal(data, &stamp); err != nil {
+		return nil, nil, fmt.Errorf("existing hooks.json is not valid JSON (fix or remove it): %w", err)
+	}
+	version := stamp.GTSettingsVersion
+	if version > SettingsVersion {
+		return nil, nil, fmt.Errorf("hooks.json settings version %d is newer than this gt supports (%d); upgrade gt", version, SettingsVersion)
+	}
+	if version == SettingsVersion {
+		return data, nil, nil
+	}
+
+	doc, err := parseHooksDoc(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("existing hooks.json: %w", err)
+	}
+	var applied []string
+	for _, m := range settingsMigrations {
+		if m.from < version {
+			continue
+		}
+		if err := m.apply(doc); err != nil {
+			return nil, nil, fmt.Errorf("migrating hooks.json from settings v%d: %w", m.from, err)
+		}
+		applied = append(applied, fmt.Sprintf("v%d→v%d: %s", m.from, m.from+1, m.description))
+	}
+	doc.top["gt_settings_version"] = json.RawMessage(fmt.Sprint(SettingsVersion))
+
+	out, err := doc.encode()
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, applied, nil
+}
+
+// claudeEventNames maps the Claude Code hook events early Gas Town wrote to
+// their Cursor equivalents.
+var claudeEventNames = map[string]string{
+	"SessionStart":     "sessionStart",
+	"UserPromptSubmit": "beforeSubmitPrompt",
+	"PreCompact":       "preCompact",
+	"Stop":             "stop",
+}
+
+// migrateClaudeEvents (v0→v1) renames Claude-style events and flattens
+// their {"matcher", "hooks": [{"type": "command", ...}]} groups into
+// Cursor's flat {"command"} entries. Entries join any existing Cursor event
+// of the same name.
+func migrateClaudeEvents(doc *hooksDoc) error {
+	var order []string
+	for _, event := range doc.order {
+		target, ok := claudeEventNames[event]
+		if !ok {
+			if !slices.Contains(order, event) {
+				order = append(order, event)
+			}
+			continue
+		}
+		var flat []json.RawMessage
+		for _, entry := range doc.events[event] {
+			var group struct {
+				Hooks []json.RawMessage `json:"hooks"`
+			}
+			if err := json.Unmarshal(entry, &group); err == nil && group.Hooks != nil {
+				for _, h := range group.Hooks {
+					if cmd := hookEntryCommand(h); cmd != "" {
+						flat = append(flat, commandEntry(cmd))
+					}
+				}
+				continue
+			}
+			flat = append(flat, entry)
+		}
+		delete(doc.events, event)
+		doc.events[target] = append(doc.events[target], flat...)
+		if !slices.Contains(order, target) {
+			order = append(order, target)
+		}
+	}
+	doc.order = order
+	return nil
+}
+
+// migrateLoginShell (v1→v2) wraps bare Gas Town script commands
+// (".cursor/hooks/gastown-*.sh [args]") in "bash -lc", matching what gt
+// generated at v2 so they are not reported as user-edited hooks.
+func migrateLoginShell(doc *hooksDoc) error {
+	for _, event := range doc.order {
+		for i, entry := range doc.events[event] {
+			cmd := strings.TrimPrefix(hookEntryCommand(entry), "./")
+			if !strings.HasPrefix(cmd, ".cursor/hooks/gastown-") || strings.Contains(cmd, "'") {
+				continue
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(entry, &fields); err != nil {
+				return err
+			}
+			quoted, err := json.Marshal("bash -lc '" + cmd + "'")
+			if err != nil {
+				return err
+			}
+			fields["command"] = quoted
+			rewritten, err := json.Marshal(fields)
+			if err != nil {
+				return err
+			}
+			doc.events[event][i] = rewritten
+		}
+	}
+	return nil
+}
+
+// migrateHookSubcommands (v2→v3) drops the entries running Gas Town hook
+// scripts, which gt no longer installs. The gt cursor-hook entries replacing
+// them come from the merge with the generated hooks; events left without
+// entries are removed.
+func migrateHookSubcommands(doc *hooksDoc) error {
+	var order []string
+	for _, event := range doc.order {
+		doc.events[event] = slices.DeleteFunc(doc.events[event], func(entry json.RawMessage) bool {
+			return legacyScriptPattern.MatchString(hookEntryCommand(entry))
+		})
+		if len(doc.events[event]) == 0 {
+			delete(doc.events, event)
+			continue
+		}
+		order = append(order, event)
+	}
+	doc.order = order
+	return nil
+}
+
+// commandEntry returns a flat {"command": cmd} hook entry.
+func commandEntry(cmd string) json.RawMessage {
+	data, _ := json.Marshal(HookEntry{Command: cmd})
+	return data
+}
+
+// parseHooksDoc splits a hooks.json into its top-level fields and its
+// events in file order.
+func parseHooksDoc(data []byte) (*hooksDoc, error) {
+	doc := &hooksDoc{events: make(map[string][]json.RawMessage)}
+	if err := json.Unmarshal(data, &doc.top); err != nil {
+		return nil, err
+	}
+	if _, ok := doc.top["hooks"]; !ok {
+		return doc, nil
+	}
+	order, raw, err := templateHookEvents(data)
+	if err != nil {
+		return nil, err
+	}
+	doc.order = order
+	for _, event := range order {
+		entries, err := hookEntries(raw[event])
+		if err != nil {
+			return nil, err
+		}
+		doc.events[event] = entries
+	}
+	return doc, nil
+}
+
+// encode writes the document back out in the layout gt generates: version
+// stamps first, then other fields sorted, then hooks in event order.
+func (d *hooksDoc) encode() ([]byte, error) {
+	var keys []string
+	for key := range d.top {
+		if key != "hooks" && !slices.Contains(generatedTopLevelKeys, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	keys = append(slices.DeleteFunc(slices.Clone(generatedTopLevelKeys), func(k string) bool {
+		_, ok := d.top[k]
+		return k == "hooks" || !ok
+	}), keys...)
+
+	var out bytes.Buffer
+	out.WriteString("{\n")
+	for _, key := range keys {
+		fmt.Fprintf(&out, "  %q: ", key)
+		if err := json.Indent(&out, d.top[key], "  ", "  "); err != nil {
+			return nil, err
+		}
+		out.WriteString(",\n")
+	}
+	out.WriteString("  \"hooks\": {\n")
+	for i, event := range d.order {
+		fmt.Fprintf(&out, "    %q: ", event)
+		list := append([]byte("["), bytes.Join(compactEntries(d.events[event]), []byte(","))...)
+		if err := json.Indent(&out, append(list, ']'), "    ", "  "); err != nil {
+			return nil, err
+		}
+		if i < len(d.order)-1 {
+			out.WriteString(",")
+		}
+		out.WriteString("\n")
+	}
+	out.WriteString("  }\n}\n")
+	return out.Bytes(), nil
+}
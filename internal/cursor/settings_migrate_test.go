@@ -0,0 +1,119 @@
+package cursor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSettingsMigrationsCoverEveryVersion(t *testing.T) {
+	for i, m := range settingsMigrations {
+		if m.from != i {
+			t.Errorf("migration %d upgrades from v%d; migrations must be contiguous from v0", i, m.from)
+		}
+	}
+	if len(settingsMigrations) != SettingsVersion {
+		t.Errorf("%d migrations registered, want one per version below SettingsVersion (%d)", len(settingsMigrations), SettingsVersion)
+	}
+}
+
+func TestMigrateHooksConfig(t *testing.T) {
+	legacy := `{
+  "version": 1,
+  "team": "infra",
+  "hooks": {
+    "SessionStart": [{"matcher": "", "hooks": [{"type": "command", "command": ".cursor/hooks/gastown-session-start.sh"}]}],
+    "sessionStart": [{"command": "./warm-cache.sh"}],
+    "UserPromptSubmit": [{"matcher": "", "hooks": [{"type": "command", "command": "./.cursor/hooks/gastown-prompt.sh"}]}],
+    "afterShellExecution": [{"command": "bash -lc '.cursor/hooks/gastown-shell.sh after'"}]
+  }
+}`
+	out, applied, err := MigrateHooksConfig([]byte(legacy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != SettingsVersion {
+		t.Errorf("applied = %v, want every migration", applied)
+	}
+
+	var cfg HooksConfig
+	if err := json.Unmarshal(out, &cfg); err != nil {
+		t.Fatalf("migrated hooks.json is invalid: %v\n%s", err, out)
+	}
+	if cfg.GTSettingsVersion != SettingsVersion {
+		t.Errorf("gt_settings_version = %d, want %d", cfg.GTSettingsVersion, SettingsVersion)
+	}
+	for _, old := range []string{"SessionStart", "UserPromptSubmit"} {
+		if _, ok := cfg.Hooks[old]; ok {
+			t.Errorf("Claude-style event %s not renamed", old)
+		}
+	}
+	// Gas Town script entries are dropped; the gt cursor-hook entries come from
+	// the merge with the generated hooks
+	if got := cfg.Hooks["sessionStart"]; len(got) != 1 || got[0].Command != "./warm-cache.sh" {
+		t.Errorf("sessionStart = %v, want only the user hook", got)
+	}
+	for _, event := range []string{"beforeSubmitPrompt", "afterShellExecution"} {
+		if got, ok := cfg.Hooks[event]; ok {
+			t.Errorf("%s = %v, want the event dropped with its script entries", event, got)
+		}
+	}
+	if !strings.Contains(string(out), `"team": "infra"`) {
+		t.Errorf("user field dropped:\n%s", out)
+	}
+
+	// Migrated files are current and left alone
+	again, applied, err := MigrateHooksConfig(out)
+	if err != nil || len(applied) != 0 || string(again) != string(out) {
+		t.Errorf("migrating a current file: applied %v, err %v", applied, err)
+	}
+
+	if _, _, err := MigrateHooksConfig([]byte(`{"gt_settings_version": 99, "hooks": {}}`)); err == nil {
+		t.Error("a file from a newer gt should not be migrated")
+	}
+	if _, _, err := MigrateHooksConfig([]byte(`{"hooks": `)); err == nil {
+		t.Error("invalid JSON should fail")
+	}
+}
+
+func TestEnsureHooksMigratesInPlace(t *testing.T) {
+	dir := t.TempDir()
+	hooksPath := filepath.Join(dir, ".cursor", "hooks.json")
+	if err := os.MkdirAll(filepath.Dir(hooksPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `{"version": 1, "hooks": {"Stop": [{"matcher": "", "hooks": [{"type": "command", "command": ".cursor/hooks/gastown-stop.sh"}]}, {"matcher": "", "hooks": [{"type": "command", "command": "./notify.sh"}]}]}}`
+	if err := os.WriteFile(hooksPath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if v := InstalledSettingsVersion(dir); v != 0 {
+		t.Errorf("legacy settings version = %d, want 0", v)
+	}
+
+	if err := EnsureHooksForRole(dir, "crew"); err != nil {
+		t.Fatal(err)
+	}
+	if v := InstalledSettingsVersion(dir); v != SettingsVersion {
+		t.Errorf("settings version after sync = %d, want %d", v, SettingsVersion)
+	}
+	if conflicts := HookConflicts(dir, "crew"); len(conflicts) > 0 {
+		t.Errorf("migrated hooks reported as user edits: %v", conflicts)
+	}
+	data, err := os.ReadFile(hooksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg HooksConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	stop := cfg.Hooks["stop"]
+	if len(stop) != 2 || stop[1].Command != "./notify.sh" {
+		t.Errorf("stop hooks = %v, want the Gas Town hook then the user's", stop)
+	}
+	if !HooksCurrentForRole(dir, "crew") {
+		t.Error("migrated hooks should be current")
+	}
+}
@@ -1,6 +1,7 @@
 package cursor
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -154,3 +155,105 @@ func TestEnsureSettingsForRole(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureSettings_TownOverride(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	overrideDir := filepath.Join(townRoot, "templates", "cursor")
+	if err := os.MkdirAll(overrideDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(overrideDir, "rules-autonomous.mdc"), []byte("team rules\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	workDir := filepath.Join(townRoot, "myrig", "polecats", "toast")
+	if err := EnsureSettings(workDir, Autonomous); err != nil {
+		t.Fatalf("EnsureSettings failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(workDir, ".cursor", "rules", "gastown.mdc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "team rules\n" {
+		t.Errorf("rules = %q, want town override", content)
+	}
+
+	// Roles without an override fall back to the embedded template
+	crewDir := filepath.Join(townRoot, "myrig", "crew", "max")
+	if err := EnsureSettings(crewDir, Interactive); err != nil {
+		t.Fatalf("EnsureSettings failed: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(crewDir, ".cursor", "rules", "gastown.mdc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "gt mail check") {
+		t.Error("interactive rules should fall back to the embedded template")
+	}
+}
+
+func TestEnsureSettings_RendersTownVars(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town","name":"ai"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	overrideDir := filepath.Join(townRoot, "templates", "cursor")
+	if err := os.MkdirAll(overrideDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	hooks := `{"version": 1, "hooks": {"stop": [{"command": {{printf "echo %s %s %s %s %s" .TownName .RigName .Role .Session (shellquote .TownRoot) | json}}}]}}` + "\n"
+	if err := os.WriteFile(filepath.Join(overrideDir, "hooks.json"), []byte(hooks), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rigSettings := `{"type":"rig-settings","version":1,"protected_paths":{"paths":["infra/","*.tf"]}}`
+	if err := os.MkdirAll(filepath.Join(townRoot, "myrig", "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "myrig", "settings", "config.json"), []byte(rigSettings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	workDir := filepath.Join(townRoot, "myrig", "witness")
+	if err := EnsureSettingsForRole(workDir, "witness"); err != nil {
+		t.Fatalf("EnsureSettingsForRole failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(workDir, ".cursor", "hooks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg HooksConfig
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := "echo ai myrig witness gt-myrig-witness '" + townRoot + "'"
+	if stop := cfg.Hooks["stop"]; len(stop) != 1 || stop[0].Command != want {
+		t.Errorf("stop hooks = %+v, want %q", stop, want)
+	}
+
+	rules, err := os.ReadFile(filepath.Join(workDir, ".cursor", "rules", "gastown.mdc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(rules), "Town `ai` at `"+townRoot+"`, rig `myrig`, role `witness`") {
+		t.Errorf("rules do not name the workspace:\n%s", rules)
+	}
+	if !strings.Contains(string(rules), "## Protected Paths") || !strings.Contains(string(rules), "- `infra/`\n- `*.tf`\n") {
+		t.Errorf("rules do not list the rig's protected paths:\n%s", rules)
+	}
+	if strings.Contains(string(rules), "{{") {
+		t.Error("rules contain unrendered template actions")
+	}
+	if !HooksCurrentForRole(workDir, "witness") {
+		t.Error("rendered hooks should be current")
+	}
+}
@@ -9,20 +9,26 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/cursorworkshop/cursor-gastown/internal/alerts"
 	"github.com/cursorworkshop/cursor-gastown/internal/beads"
 	"github.com/cursorworkshop/cursor-gastown/internal/boot"
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
 	"github.com/cursorworkshop/cursor-gastown/internal/deacon"
 	"github.com/cursorworkshop/cursor-gastown/internal/feed"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
 	"github.com/cursorworkshop/cursor-gastown/internal/polecat"
 	"github.com/cursorworkshop/cursor-gastown/internal/refinery"
 	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/secrets"
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
 	"github.com/cursorworkshop/cursor-gastown/internal/wisp"
@@ -40,6 +46,7 @@ type Daemon struct {
 	ctx     context.Context
 	cancel  context.CancelFunc
 	curator *feed.Curator
+	web     *webUI
 }
 
 // New creates a new daemon instance.
@@ -100,11 +107,20 @@ func (d *Daemon) Run() error {
 		Running:   true,
 		PID:       os.Getpid(),
 		StartedAt: time.Now(),
+		Version:   d.config.Version,
+	}
+	if err := d.startWeb(state); err != nil {
+		d.logger.Printf("Error: %v", err)
+		return err
 	}
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
+	// Resolve town settings env (including secret references) into the
+	// daemon's environment so agents it spawns inherit it.
+	d.applySettingsEnv()
+
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
 	baseSignals := []os.Signal{syscall.SIGINT, syscall.SIGTERM}
@@ -158,6 +174,10 @@ func (d *Daemon) Run() error {
 // 3 minutes is fast enough to detect stuck agents promptly while avoiding excessive overhead.
 const recoveryHeartbeatInterval = 3 * time.Minute
 
+// HeartbeatStaleAfter is how long the daemon may go without completing a
+// heartbeat before it is considered unresponsive (two missed intervals).
+const HeartbeatStaleAfter = 2 * recoveryHeartbeatInterval
+
 // heartbeat performs one heartbeat cycle.
 // The daemon is recovery-focused: it ensures agents are running and detects failures.
 // Normal wake is handled by feed subscription (bd activity --follow).
@@ -168,6 +188,17 @@ const recoveryHeartbeatInterval = 3 * time.Minute
 func (d *Daemon) heartbeat(state *State) {
 	d.logger.Println("Heartbeat starting (recovery-focused)")
 
+	// 0. Without tmux every agent looks dead, so skip everything that
+	// inspects or starts sessions. Pending spawns and lifecycle requests stay
+	// queued in the Deacon inbox until tmux is back.
+	if !d.checkTmuxHealth(state) {
+		d.checkCostAlerts()
+		d.syncEmailBridge()
+		d.updatePromptSummary()
+		d.finishHeartbeat(state)
+		return
+	}
+
 	// 1. Poke Boot (the Deacon's watchdog) instead of Deacon directly
 	// Boot handles the "when to wake Deacon" decision via triage logic
 	d.ensureBootRunning()
@@ -205,16 +236,58 @@ func (d *Daemon) heartbeat(state *State) {
 	// This validates tmux sessions are still alive for polecats with work-on-hook
 	d.checkPolecatSessionHealth()
 
-	// Update state
+	// 8b. Enforce polecat time boxes (wrap-up nudge at 80%, stop + handoff at 100%)
+	d.checkPolecatTimeboxes()
+
+	// 9. Evaluate cost alerts (hysteresis keeps repeat heartbeats quiet)
+	d.checkCostAlerts()
+
+	// 10. Refresh the cached prompt summary for `gt completion town-prompt`
+	d.updatePromptSummary()
+
+	// 11. Staged template re-sync after gt upgrades (opt-in via template_resync)
+	d.checkTemplateDrift()
+
+	// 12. Sync forked rigs with their upstream (rigs with "upstream" settings)
+	d.syncUpstreams()
+
+	// 13. Email escalations to the operator and ingest replies (opt-in via email_bridge)
+	d.syncEmailBridge()
+
+	d.finishHeartbeat(state)
+}
+
+// finishHeartbeat records a completed heartbeat in the daemon state.
+func (d *Daemon) finishHeartbeat(state *State) {
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
+	d.refreshWebSnapshot(state)
+
 	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
 }
 
+// checkTmuxHealth probes tmux and records the outcome in state, logging only
+// when availability changes. Returns false when tmux is unusable.
+func (d *Daemon) checkTmuxHealth(state *State) bool {
+	err := d.tmux.Probe()
+	if err == nil {
+		if state.TmuxError != "" {
+			d.logger.Printf("tmux is available again (was: %s), resuming session management", state.TmuxError)
+			state.TmuxError = ""
+		}
+		return true
+	}
+	if state.TmuxError == "" {
+		d.logger.Printf("tmux unavailable: %v; holding spawns and lifecycle requests until it returns", err)
+	}
+	state.TmuxError = err.Error()
+	return false
+}
+
 // DeaconRole is the role name for the Deacon's handoff bead.
 const DeaconRole = "deacon"
 
@@ -317,6 +390,15 @@ func (d *Daemon) ensureDeaconRunning() {
 	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead agent
 	deaconDir := filepath.Join(d.config.TownRoot, "deacon")
 	sessionName := d.getDeaconSessionName()
+	if err := preflight.BeforeSpawn(preflight.Spec{
+		Session:     sessionName,
+		Role:        session.RoleDeacon,
+		TownRoot:    d.config.TownRoot,
+		SettingsDir: deaconDir,
+	}); err != nil {
+		d.logger.Printf("Not starting Deacon: %v", err)
+		return
+	}
 	if err := d.tmux.EnsureSessionFresh(sessionName, deaconDir); err != nil {
 		d.logger.Printf("Error creating Deacon session: %v", err)
 		return
@@ -475,14 +557,19 @@ func (d *Daemon) getKnownRigs() []string {
 	}
 
 	var parsed struct {
-		Rigs map[string]interface{} `json:"rigs"`
+		Rigs map[string]struct {
+			Mirror bool `json:"mirror"`
+		} `json:"rigs"`
 	}
 	if err := json.Unmarshal(data, &parsed); err != nil {
 		return nil
 	}
 
 	var rigs []string
-	for name := range parsed.Rigs {
+	for name, entry := range parsed.Rigs {
+		if entry.Mirror {
+			continue // Reference-only rigs have no agents to supervise
+		}
 		rigs = append(rigs, name)
 	}
 	return rigs
@@ -590,6 +677,8 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Feed curator stopped")
 	}
 
+	d.stopWeb()
+
 	state.Running = false
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save final state: %v", err)
@@ -729,7 +818,13 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 		return
 	}
 
-	// Session is dead. Check if the polecat has work-on-hook.
+	// Session is dead. A polecat stopped for time box expiry stays down:
+	// its work was handed back to the witness.
+	if tb, _ := polecat.ReadTimebox(filepath.Join(d.config.TownRoot, rigName, "polecats", polecatName)); tb != nil && !tb.EnforcedAt.IsZero() {
+		return
+	}
+
+	// Check if the polecat has work-on-hook.
 	agentBeadID := beads.PolecatBeadID(rigName, polecatName)
 	info, err := d.getAgentBeadInfo(agentBeadID)
 	if err != nil {
@@ -772,6 +867,19 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 		return fmt.Errorf("polecat worktree does not exist: %s", workDir)
 	}
 
+	// No slot check: the crashed polecat still holds its slot
+	if err := preflight.BeforeSpawn(preflight.Spec{
+		Session:       sessionName,
+		Role:          session.RolePolecat,
+		TownRoot:      d.config.TownRoot,
+		RigPath:       filepath.Join(d.config.TownRoot, rigName),
+		WorkDir:       workDir,
+		CheckCheckout: true,
+		BranchPrefix:  constants.BranchPolecatPrefix + polecatName,
+	}); err != nil {
+		return err
+	}
+
 	// Pre-sync workspace (ensure beads are current)
 	d.syncWorkspace(workDir)
 
@@ -806,6 +914,7 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// Pass rigPath so rig agent settings are honored (not town-level defaults)
 	rigPath := filepath.Join(d.config.TownRoot, rigName)
 	startCmd := config.BuildPolecatStartupCommand(rigName, polecatName, rigPath, "")
+	startCmd = polecat.CleanroomCommand(filepath.Join(rigPath, "polecats", polecatName), startCmd)
 	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
@@ -820,6 +929,167 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	return nil
 }
 
+// checkPolecatTimeboxes enforces wall-clock time boxes on polecat sessions.
+// At TimeboxWrapUpFraction the polecat is nudged to wrap up; at expiry the
+// session is stopped and the witness is asked to hand the work off.
+func (d *Daemon) checkPolecatTimeboxes() {
+	now := time.Now()
+	for _, rigName := range d.getKnownRigs() {
+		polecatsDir := filepath.Join(d.config.TownRoot, rigName, "polecats")
+		entries, err := os.ReadDir(polecatsDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			polecatDir := filepath.Join(polecatsDir, entry.Name())
+			tb, err := polecat.ReadTimebox(polecatDir)
+			if err != nil || tb == nil || !tb.EnforcedAt.IsZero() {
+				continue
+			}
+			d.enforceTimebox(polecatDir, tb, now)
+		}
+	}
+}
+
+// enforceTimebox applies the time box phase actions for a single polecat.
+func (d *Daemon) enforceTimebox(polecatDir string, tb *polecat.Timebox, now time.Time) {
+	sessionName := fmt.Sprintf("gt-%s-%s", tb.Rig, tb.Polecat)
+
+	switch tb.Phase(now) {
+	case polecat.TimeboxWrapUp:
+		if !tb.NudgedAt.IsZero() {
+			return
+		}
+		msg := fmt.Sprintf("TIMEBOX: %s remaining of %s. Wrap up now: commit, push, and run 'gt done' (or 'gt handoff' if unfinished).",
+			tb.Remaining(now).Round(time.Minute), tb.Duration)
+		if err := d.tmux.NudgeSession(sessionName, msg); err != nil {
+			d.logger.Printf("Error nudging %s for timebox wrap-up: %v", sessionName, err)
+			return
+		}
+		tb.NudgedAt = now
+		d.logger.Printf("Timebox wrap-up nudge sent to %s/%s", tb.Rig, tb.Polecat)
+
+	case polecat.TimeboxExpired:
+		if running, _ := d.tmux.HasSession(sessionName); running {
+			if err := d.tmux.KillSession(sessionName); err != nil {
+				d.logger.Printf("Error stopping %s at timebox expiry: %v", sessionName, err)
+				return
+			}
+		}
+		tb.EnforcedAt = now
+		d.logger.Printf("Timebox expired for %s/%s after %s - session stopped", tb.Rig, tb.Polecat, tb.Duration)
+		d.notifyWitnessOfTimeboxExpiry(tb)
+
+	default:
+		return
+	}
+
+	if err := polecat.WriteTimebox(polecatDir, tb); err != nil {
+		d.logger.Printf("Warning: failed to update timebox for %s/%s: %v", tb.Rig, tb.Polecat, err)
+	}
+}
+
+// notifyWitnessOfTimeboxExpiry asks the witness to hand off an expired polecat's work.
+func (d *Daemon) notifyWitnessOfTimeboxExpiry(tb *polecat.Timebox) {
+	witnessAddr := tb.Rig + "/witness"
+	subject := fmt.Sprintf("TIMEBOX_EXPIRED: %s/%s", tb.Rig, tb.Polecat)
+	body := fmt.Sprintf(`Polecat %s exceeded its %s time box and was stopped.
+
+hook_bead: %s
+started_at: %s
+
+Review the polecat's branch and re-sling or hand off the remaining work.`,
+		tb.Polecat, tb.Duration, tb.HookBead, tb.StartedAt.Format(time.RFC3339))
+
+	cmd := exec.Command("gt", "mail", "send", witnessAddr, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: failed to notify witness of timebox expiry: %v", err)
+	}
+}
+
+// checkCostAlerts evaluates cost budgets via `gt alerts check`.
+// Alert state and notification are owned by the gt command; the daemon only
+// provides the periodic trigger.
+func (d *Daemon) checkCostAlerts() {
+	cmd := exec.Command("gt", "alerts", "check", "--quiet") //nolint:gosec // G204: args are constant
+	cmd.Dir = d.config.TownRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Warning: cost alert check failed: %v: %s", err, string(output))
+	}
+}
+
+// syncUpstreams runs scheduled upstream syncs via `gt rig upstream sync`.
+// Scheduling, fast-forwards, sync tasks, and witness notification are owned
+// by the gt command; the daemon only provides the periodic trigger.
+func (d *Daemon) syncUpstreams() {
+	cmd := exec.Command("gt", "rig", "upstream", "sync", "--due", "--quiet") //nolint:gosec // G204: args are constant
+	cmd.Dir = d.config.TownRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Warning: upstream sync failed: %v: %s", err, string(output))
+	}
+}
+
+// syncEmailBridge runs the operator email bridge via `gt mail bridge sync`
+// when email_bridge is configured. Forwarding, reply ingestion, and bridge
+// state are owned by the gt command; the daemon only provides the periodic
+// trigger.
+func (d *Daemon) syncEmailBridge() {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil || settings.EmailBridge == nil {
+		return
+	}
+	cmd := exec.Command("gt", "mail", "bridge", "sync", "--quiet") //nolint:gosec // G204: args are constant
+	cmd.Dir = d.config.TownRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Warning: email bridge sync failed: %v: %s", err, string(output))
+	}
+}
+
+// updatePromptSummary caches a compact town snapshot for shell prompts.
+// Prompt rendering must never block on tmux or beads, so the daemon does
+// the expensive queries once per heartbeat and prompts read the result.
+func (d *Daemon) updatePromptSummary() {
+	summary := &PromptSummary{
+		UpdatedAt: time.Now(),
+		Health:    HealthOK,
+	}
+
+	if sessions, err := d.tmux.ListSessions(); err == nil {
+		for _, s := range sessions {
+			if strings.HasPrefix(s, constants.SessionPrefix) || strings.HasPrefix(s, constants.HQSessionPrefix) {
+				summary.Sessions++
+			}
+		}
+	}
+
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	if mailbox, err := router.GetMailbox("overseer"); err == nil {
+		if _, unread, err := mailbox.Count(); err == nil {
+			summary.UnreadMail = unread
+		}
+	}
+
+	if state, err := alerts.Load(d.config.TownRoot); err == nil {
+		for _, a := range state.Alerts {
+			if a.Active() {
+				summary.ActiveAlerts++
+			}
+		}
+	}
+
+	if running, _ := d.tmux.HasSession(d.getDeaconSessionName()); !running || summary.ActiveAlerts > 0 {
+		summary.Health = HealthDegraded
+	}
+
+	if err := SavePromptSummary(d.config.TownRoot, summary); err != nil {
+		d.logger.Printf("Warning: failed to save prompt summary: %v", err)
+	}
+}
+
 // notifyWitnessOfCrashedPolecat notifies the witness when a polecat restart fails.
 func (d *Daemon) notifyWitnessOfCrashedPolecat(rigName, polecatName, hookBead string, restartErr error) {
 	witnessAddr := rigName + "/witness"
@@ -838,3 +1108,28 @@ Manual intervention may be required.`,
 		d.logger.Printf("Warning: failed to notify witness of crashed polecat: %v", err)
 	}
 }
+
+// applySettingsEnv resolves the town settings env and sets it in the daemon
+// process. Only variable names are logged; secret values never are.
+func (d *Daemon) applySettingsEnv() {
+	env := config.ResolveSessionEnv(d.config.TownRoot, "")
+	if len(env) == 0 {
+		return
+	}
+	resolved, err := secrets.ResolveEnv(env)
+	if err != nil {
+		d.logger.Printf("Warning: resolving settings env: %v", err)
+	}
+	names := make([]string, 0, len(resolved))
+	for k, v := range resolved {
+		if err := os.Setenv(k, v); err != nil {
+			d.logger.Printf("Warning: setting %s: %v", k, err)
+			continue
+		}
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		d.logger.Printf("Settings env applied: %s", strings.Join(names, ", "))
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -209,6 +211,28 @@ func TestSaveLoadState_Roundtrip(t *testing.T) {
 // NOTE: TestIsWitnessSession removed - isWitnessSession function was deleted
 // as part of ZFC cleanup. Witness poking is now Deacon's responsibility.
 
+func TestState_HeartbeatStale(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		state State
+		want  bool
+	}{
+		{"never started", State{}, false},
+		{"recent heartbeat", State{StartedAt: now.Add(-time.Hour), LastHeartbeat: now.Add(-time.Minute)}, false},
+		{"old heartbeat", State{StartedAt: now.Add(-time.Hour), LastHeartbeat: now.Add(-HeartbeatStaleAfter - time.Second)}, true},
+		{"starting up", State{StartedAt: now.Add(-time.Minute)}, false},
+		{"stuck before first heartbeat", State{StartedAt: now.Add(-HeartbeatStaleAfter - time.Second)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.HeartbeatStale(now); got != tt.want {
+				t.Errorf("HeartbeatStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestLifecycleAction_Constants(t *testing.T) {
 	// Verify constants have expected string values
 	if ActionCycle != "cycle" {
@@ -246,3 +270,17 @@ func TestLifecycleRequest_Serialization(t *testing.T) {
 		t.Errorf("Action mismatch: got %q, want %q", loaded.Action, request.Action)
 	}
 }
+
+func TestCheckTmuxHealth_NotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	d := testDaemon()
+	d.tmux = tmux.NewTmux()
+
+	state := &State{}
+	if d.checkTmuxHealth(state) {
+		t.Fatal("checkTmuxHealth = true without tmux, want false")
+	}
+	if state.TmuxError != tmux.ErrNotInstalled.Error() {
+		t.Errorf("TmuxError = %q, want %q", state.TmuxError, tmux.ErrNotInstalled.Error())
+	}
+}
@@ -11,6 +11,9 @@ import (
 	"github.com/cursorworkshop/cursor-gastown/internal/beads"
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/polecat"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
+	"github.com/cursorworkshop/cursor-gastown/internal/progress"
 	"github.com/cursorworkshop/cursor-gastown/internal/rig"
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
@@ -354,6 +357,23 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 		return fmt.Errorf("cannot determine working directory for %s", identity)
 	}
 
+	spec := preflight.Spec{
+		Session:  sessionName,
+		Role:     session.Role(parsed.RoleType),
+		TownRoot: d.config.TownRoot,
+	}
+	if parsed.RigName != "" {
+		spec.RigPath = filepath.Join(d.config.TownRoot, parsed.RigName)
+	}
+	if parsed.RoleType == "polecat" {
+		spec.WorkDir = workDir
+		spec.CheckCheckout = true
+		spec.BranchPrefix = constants.BranchPolecatPrefix + parsed.AgentName
+	}
+	if err := preflight.BeforeSpawn(spec); err != nil {
+		return err
+	}
+
 	// Determine if pre-sync is needed
 	needsPreSync := d.getNeedsPreSync(config, parsed)
 
@@ -469,7 +489,8 @@ func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIde
 
 	// Polecats need environment variables set in the command
 	if parsed.RoleType == "polecat" {
-		return config.BuildPolecatStartupCommand(parsed.RigName, parsed.AgentName, rigPath, "")
+		startCmd := config.BuildPolecatStartupCommand(parsed.RigName, parsed.AgentName, rigPath, "")
+		return polecat.CleanroomCommand(filepath.Join(rigPath, "polecats", parsed.AgentName), startCmd)
 	}
 
 	return defaultCmd
@@ -766,6 +787,14 @@ func (d *Daemon) checkRigGUPPViolations(rigName string) {
 
 		// Check if tmux session exists and agent is running
 		if d.tmux.IsCursorRunning(sessionName) {
+			// A progress report on the hooked work says more than the
+			// agent bead's update time; use it when the polecat has one.
+			polecatDir := filepath.Join(d.config.TownRoot, rigName, "polecats", polecatName)
+			if report, _ := progress.Read(polecatDir); report != nil && report.Bead == agent.HookBead {
+				d.checkStalledProgress(rigName, polecatName, polecatDir, report)
+				continue
+			}
+
 			// Session is alive - check if it's been stuck too long
 			updatedAt, err := time.Parse(time.RFC3339, agent.UpdatedAt)
 			if err != nil {
@@ -806,6 +835,47 @@ Action needed: Check if agent is alive and responsive. Consider restarting if st
 	}
 }
 
+// checkStalledProgress tells the witness, once per report, that a running
+// polecat has not reported progress on unfinished work within
+// progress.DefaultStallAfter.
+func (d *Daemon) checkStalledProgress(rigName, polecatName, polecatDir string, report *progress.Report) {
+	now := time.Now()
+	if !report.Stalled(now, progress.DefaultStallAfter) || report.StallNotifiedAt.After(report.ReportedAt) {
+		return
+	}
+
+	age := report.Age(now).Round(time.Minute)
+	d.logger.Printf("Stalled progress: %s/%s last reported %d%% on %s %v ago", rigName, polecatName, report.Percent, report.Bead, age)
+
+	witnessAddr := rigName + "/witness"
+	subject := fmt.Sprintf("STALLED: %s/%s no progress for %v", rigName, polecatName, age)
+	body := fmt.Sprintf(`Polecat %s has not reported progress on its hooked work.
+
+hook_bead: %s
+last_percent: %d
+last_note: %s
+reported_at: %s
+
+Action needed: Check the session (gt peek %s/%s) and nudge or restart it.`,
+		polecatName, report.Bead, report.Percent, report.Note, report.ReportedAt.Format(time.RFC3339), rigName, polecatName)
+
+	cmd := exec.Command("gt", "mail", "send", witnessAddr, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: failed to notify witness of stalled progress: %v", err)
+		return
+	}
+
+	// Skip the write if the polecat reported while we were mailing
+	if latest, _ := progress.Read(polecatDir); latest == nil || !latest.ReportedAt.Equal(report.ReportedAt) {
+		return
+	}
+	report.StallNotifiedAt = now
+	if err := progress.Write(polecatDir, report); err != nil {
+		d.logger.Printf("Warning: failed to update progress for %s/%s: %v", rigName, polecatName, err)
+	}
+}
+
 // checkOrphanedWork looks for work assigned to dead agents.
 // Orphaned work needs to be reassigned or the agent needs to be restarted.
 // Per gt-zecmc: derive agent liveness from tmux, not agent_state.
@@ -0,0 +1,248 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/lock"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// TemplateTarget is an agent workspace whose Cursor hook templates are
+// managed by gt.
+type TemplateTarget struct {
+	// Agent is the agent address (e.g., "mayor", "gastown/witness", "gastown/polecats").
+	Agent string
+
+	// WorkDir is the directory containing the agent's .cursor/ config.
+	WorkDir string
+
+	// Role is the role the hooks are generated for (see cursor.HookEvents).
+	Role string
+
+	// Session is the tmux session to cycle after re-sync (empty for
+	// shared crew/polecat config, which is picked up by new sessions).
+	Session string
+
+	// Patrol is true for long-running patrol roles that need a session
+	// cycle, which is only done during the maintenance window.
+	Patrol bool
+}
+
+// TemplateTargets lists template-managed agent workspaces ordered least
+// disruptive first: shared polecat and crew config, then the mayor (never
+// cycled, it is interactive), then patrol roles.
+func TemplateTargets(townRoot string, rigs []string) []TemplateTarget {
+	var shared, patrol []TemplateTarget
+	for _, rigName := range rigs {
+		rigPath := filepath.Join(townRoot, rigName)
+		shared = append(shared,
+			TemplateTarget{Agent: rigName + "/polecats", WorkDir: filepath.Join(rigPath, "polecats"), Role: "polecat"},
+			TemplateTarget{Agent: rigName + "/crew", WorkDir: filepath.Join(rigPath, "crew"), Role: "crew"},
+		)
+		patrol = append(patrol,
+			TemplateTarget{Agent: rigName + "/refinery", WorkDir: filepath.Join(rigPath, "refinery"), Role: "refinery",
+				Session: session.RefinerySessionName(rigName), Patrol: true},
+			TemplateTarget{Agent: rigName + "/witness", WorkDir: filepath.Join(rigPath, "witness"), Role: "witness",
+				Session: session.WitnessSessionName(rigName), Patrol: true},
+		)
+	}
+
+	targets := append(shared, TemplateTarget{Agent: "mayor", WorkDir: filepath.Join(townRoot, "mayor"), Role: "mayor"})
+	targets = append(targets, patrol...)
+	return append(targets, TemplateTarget{Agent: "deacon", WorkDir: filepath.Join(townRoot, "deacon"), Role: "deacon",
+		Session: session.DeaconSessionName(), Patrol: true})
+}
+
+// FindTemplateDrift returns targets whose installed hooks differ from the
+// templates embedded in this gt binary for their role. Workspaces without
+// hooks installed are ignored; they get current templates when first
+// provisioned.
+func FindTemplateDrift(townRoot string, rigs []string) []TemplateTarget {
+	var drifted []TemplateTarget
+	for _, t := range TemplateTargets(townRoot, rigs) {
+		if cursor.HooksInstalled(t.WorkDir) && !cursor.HooksCurrentForRole(t.WorkDir, t.Role) {
+			drifted = append(drifted, t)
+		}
+	}
+	return drifted
+}
+
+// TemplateResyncState tracks an in-progress staged template re-sync.
+type TemplateResyncState struct {
+	// DetectedAt is when drift was first seen (zero when no re-sync is active).
+	DetectedAt time.Time `json:"detected_at,omitempty"`
+
+	// Resynced lists agents already re-synced during the current run.
+	Resynced []string `json:"resynced,omitempty"`
+
+	// Deferred lists patrol agents waiting for the maintenance window.
+	Deferred []string `json:"deferred,omitempty"`
+}
+
+// TemplateResyncStateFile returns the path to the template re-sync state.
+func TemplateResyncStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "template-resync.json")
+}
+
+// LoadTemplateResyncState loads the re-sync state, returning an empty state
+// if none has been written.
+func LoadTemplateResyncState(townRoot string) (*TemplateResyncState, error) {
+	data, err := os.ReadFile(TemplateResyncStateFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &TemplateResyncState{}, nil
+		}
+		return nil, err
+	}
+	var state TemplateResyncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SaveTemplateResyncState saves the re-sync state using atomic write.
+func SaveTemplateResyncState(townRoot string, state *TemplateResyncState) error {
+	path := TemplateResyncStateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, state)
+}
+
+// checkTemplateDrift re-syncs agent hook templates after a gt upgrade when
+// template_resync.auto_resync is enabled. Idle agents and shared config are
+// re-synced immediately; running patrol agents are cycled one per heartbeat
+// and only inside the maintenance window. Every step is logged as an event.
+func (d *Daemon) checkTemplateDrift() {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil || settings.TemplateResync == nil || !settings.TemplateResync.AutoResync {
+		return
+	}
+
+	state, err := LoadTemplateResyncState(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: failed to load template resync state: %v", err)
+		state = &TemplateResyncState{}
+	}
+
+	drifted := FindTemplateDrift(d.config.TownRoot, d.getKnownRigs())
+	now := time.Now()
+
+	if len(drifted) == 0 {
+		if !state.DetectedAt.IsZero() {
+			// Keyed by the resync run: a daemon restarted before the state
+			// reset below is saved must not report completion twice.
+			_, _ = events.LogOnce(resyncEventKey(events.TypeTemplateResyncComplete, state, ""), events.TypeTemplateResyncComplete, "daemon", map[string]interface{}{
+				"agents":   state.Resynced,
+				"duration": now.Sub(state.DetectedAt).Round(time.Second).String(),
+			}, events.VisibilityAudit)
+			d.logger.Printf("Template resync complete (%d agent(s))", len(state.Resynced))
+			d.saveTemplateResyncState(&TemplateResyncState{})
+		}
+		return
+	}
+
+	// Rewriting hooks and cycling sessions must not overlap a gt doctor
+	// --fix or gt settings sync run; if one holds the town operation lock,
+	// try again next heartbeat.
+	opLock, err := lock.AcquireOperation(d.config.TownRoot, "template resync", "daemon")
+	if err != nil {
+		var busy *lock.OperationBusyError
+		if errors.As(err, &busy) {
+			d.logger.Printf("Template resync deferred: %v", err)
+		} else {
+			d.logger.Printf("Warning: template resync could not take the operation lock: %v", err)
+		}
+		return
+	}
+	defer func() { _ = opLock.Release() }()
+
+	if state.DetectedAt.IsZero() {
+		agents := make([]string, 0, len(drifted))
+		for _, t := range drifted {
+			agents = append(agents, t.Agent)
+		}
+		state.DetectedAt = now
+		_ = events.LogAudit(events.TypeTemplateDrift, "daemon", map[string]interface{}{
+			"agents": agents,
+		})
+		d.logger.Printf("Template drift detected in %d agent(s), starting staged resync", len(drifted))
+		// Persist the run start now so a restart mid-run keeps the same
+		// event keys instead of starting (and reporting) a new run.
+		d.saveTemplateResyncState(state)
+	}
+
+	inWindow := settings.TemplateResync.InMaintenanceWindow(now)
+	cycledThisBeat := false
+	prevDeferred := state.Deferred
+	state.Deferred = nil
+
+	for _, t := range drifted {
+		running := false
+		if t.Session != "" {
+			running, _ = d.tmux.HasSession(t.Session)
+		}
+
+		// Running patrol agents are cycled one per heartbeat, in the window only.
+		if t.Patrol && running && (!inWindow || cycledThisBeat) {
+			state.Deferred = append(state.Deferred, t.Agent)
+			continue
+		}
+
+		if err := cursor.EnsureHooksForRole(t.WorkDir, t.Role); err != nil {
+			d.logger.Printf("Warning: template resync failed for %s: %v", t.Agent, err)
+			continue
+		}
+
+		cycled := false
+		if t.Patrol && running {
+			if err := d.tmux.KillSession(t.Session); err != nil {
+				d.logger.Printf("Warning: failed to cycle %s after template resync: %v", t.Session, err)
+			} else {
+				cycled = true
+				cycledThisBeat = true
+			}
+		}
+
+		state.Resynced = append(state.Resynced, t.Agent)
+		_, _ = events.LogOnce(resyncEventKey(events.TypeTemplateResync, state, t.Agent), events.TypeTemplateResync, "daemon",
+			events.TemplateResyncPayload(t.Agent, cycled, ""), events.VisibilityAudit)
+		d.logger.Printf("Template resync: %s (cycled=%v)", t.Agent, cycled)
+	}
+
+	// Only record deferrals when the waiting set changes, not every heartbeat
+	if len(state.Deferred) > 0 && !slices.Equal(state.Deferred, prevDeferred) {
+		_ = events.LogAudit(events.TypeTemplateResyncDeferred, "daemon", map[string]interface{}{
+			"agents": state.Deferred,
+			"window": settings.TemplateResync.Window(),
+		})
+	}
+
+	d.saveTemplateResyncState(state)
+}
+
+// resyncEventKey is the idempotency key for a template resync event within
+// the resync run that started at state.DetectedAt.
+func resyncEventKey(eventType string, state *TemplateResyncState, agent string) string {
+	key := eventType + ":" + state.DetectedAt.UTC().Format(time.RFC3339Nano)
+	if agent != "" {
+		key += ":" + agent
+	}
+	return key
+}
+
+func (d *Daemon) saveTemplateResyncState(state *TemplateResyncState) {
+	if err := SaveTemplateResyncState(d.config.TownRoot, state); err != nil {
+		d.logger.Printf("Warning: failed to save template resync state: %v", err)
+	}
+}
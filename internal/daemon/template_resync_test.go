@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/lock"
+)
+
+func TestTemplateTargetsOrder(t *testing.T) {
+	targets := TemplateTargets("/town", []string{"gastown"})
+
+	var agents []string
+	for _, tt := range targets {
+		agents = append(agents, tt.Agent)
+	}
+	want := []string{"gastown/polecats", "gastown/crew", "mayor", "gastown/refinery", "gastown/witness", "deacon"}
+	if len(agents) != len(want) {
+		t.Fatalf("agents = %v, want %v", agents, want)
+	}
+	for i := range want {
+		if agents[i] != want[i] {
+			t.Fatalf("agents = %v, want %v (least disruptive first)", agents, want)
+		}
+	}
+
+	for _, tt := range targets {
+		if tt.Patrol != (tt.Session != "") {
+			t.Errorf("%s: Patrol=%v but Session=%q", tt.Agent, tt.Patrol, tt.Session)
+		}
+	}
+}
+
+func TestCheckTemplateDriftWaitsForOperationLock(t *testing.T) {
+	d, _ := testDaemonWithTown(t, "test-town")
+	townRoot := d.config.TownRoot
+	// The daemon runs from its town root and logs events there.
+	t.Chdir(townRoot)
+	settingsPath := config.TownSettingsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"template_resync":{"auto_resync":true}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hooksPath := filepath.Join(townRoot, "mayor", ".cursor", "hooks.json")
+	if err := os.MkdirAll(filepath.Dir(hooksPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hooksPath, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fix run holds the lock: the resync waits for a later heartbeat.
+	opLock, err := lock.AcquireOperation(townRoot, "doctor --fix", "overseer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.checkTemplateDrift()
+	if data, _ := os.ReadFile(hooksPath); string(data) != "{}\n" {
+		t.Errorf("hooks rewritten while the operation lock was held: %q", data)
+	}
+
+	if err := opLock.Release(); err != nil {
+		t.Fatal(err)
+	}
+	d.checkTemplateDrift()
+	if data, _ := os.ReadFile(hooksPath); string(data) == "{}\n" {
+		t.Error("hooks not resynced once the lock was free")
+	}
+	if _, err := lock.ReadOperation(townRoot); !errors.Is(err, lock.ErrNotLocked) {
+		t.Errorf("operation lock not released after resync: %v", err)
+	}
+}
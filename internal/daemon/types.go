@@ -30,6 +30,17 @@ type Config struct {
 
 	// PidFile is the path to the PID file.
 	PidFile string `json:"pid_file"`
+
+	// Version is the gt version running the daemon (recorded in state).
+	Version string `json:"version,omitempty"`
+
+	// WebAddr is the listen address of the read-only web UI (e.g. ":8080").
+	// Empty disables it.
+	WebAddr string `json:"web_addr,omitempty"`
+
+	// WebToken is the web UI access token. Empty uses the token stored in
+	// daemon/web.token, generating one on first use.
+	WebToken string `json:"-"`
 }
 
 // DefaultConfig returns the default daemon configuration.
@@ -59,6 +70,27 @@ type State struct {
 
 	// HeartbeatCount is how many heartbeats have completed.
 	HeartbeatCount int64 `json:"heartbeat_count"`
+
+	// Version is the gt version the daemon was started with.
+	Version string `json:"version,omitempty"`
+
+	// WebAddr is where the read-only web UI listens, empty when disabled.
+	WebAddr string `json:"web_addr,omitempty"`
+
+	// TmuxError is why tmux was unusable at the last heartbeat, empty when
+	// it was healthy. Session-dependent work is held while it is set.
+	TmuxError string `json:"tmux_error,omitempty"`
+}
+
+// HeartbeatStale reports whether the daemon has gone longer than
+// HeartbeatStaleAfter without completing a heartbeat. A daemon that has not
+// finished its first heartbeat is measured from StartedAt.
+func (s *State) HeartbeatStale(now time.Time) bool {
+	last := s.LastHeartbeat
+	if last.IsZero() {
+		last = s.StartedAt
+	}
+	return !last.IsZero() && now.Sub(last) > HeartbeatStaleAfter
 }
 
 // StateFile returns the path to the state file.
@@ -121,3 +153,61 @@ type LifecycleRequest struct {
 	// Timestamp is when the request was made.
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// PromptSummary is a compact snapshot of town state cached by the daemon so
+// shell prompt integrations can render it without querying tmux or beads.
+type PromptSummary struct {
+	// UpdatedAt is when the daemon last refreshed the summary.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Sessions is the number of running Gas Town tmux sessions.
+	Sessions int `json:"sessions"`
+
+	// UnreadMail is the number of unread messages in the overseer inbox.
+	UnreadMail int `json:"unread_mail"`
+
+	// Health is "ok" when the deacon is running and no alerts are active,
+	// otherwise "degraded".
+	Health string `json:"health"`
+
+	// ActiveAlerts is the number of firing or acknowledged alerts.
+	ActiveAlerts int `json:"active_alerts"`
+}
+
+// Prompt summary health values.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+)
+
+// PromptSummaryFile returns the path to the cached prompt summary.
+func PromptSummaryFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "prompt.json")
+}
+
+// LoadPromptSummary loads the cached prompt summary.
+// Returns nil without error if the daemon has not written one yet.
+func LoadPromptSummary(townRoot string) (*PromptSummary, error) {
+	data, err := os.ReadFile(PromptSummaryFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var summary PromptSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// SavePromptSummary saves the prompt summary using atomic write.
+func SavePromptSummary(townRoot string, summary *PromptSummary) error {
+	path := PromptSummaryFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, summary)
+}
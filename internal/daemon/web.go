@@ -0,0 +1,311 @@
+package daemon
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/web"
+)
+
+// webRecentEvents is how many feed events the web UI shows.
+const webRecentEvents = 50
+
+// webActivityWindow is how far back the web UI's activity chart counts.
+const webActivityWindow = 24 * time.Hour
+
+// WebTokenFile returns the path to the web UI access token.
+func WebTokenFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "web.token")
+}
+
+// LoadWebToken returns the town's web UI access token, or "" if none has
+// been stored.
+func LoadWebToken(townRoot string) string {
+	data, err := os.ReadFile(WebTokenFile(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// resolveWebToken returns the token the web UI should accept: the configured
+// one, else the stored one, else a newly generated random token. The result
+// is stored (readable only by the owner) so gt daemon status can print it.
+func resolveWebToken(townRoot, configured string) (string, error) {
+	token := configured
+	if token == "" {
+		token = LoadWebToken(townRoot)
+	}
+	if token == "" {
+		b := make([]byte, 24)
+		if _, err := rand.Read(b); err != nil {
+			return "", fmt.Errorf("generating web token: %w", err)
+		}
+		token = hex.EncodeToString(b)
+	}
+
+	path := WebTokenFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("writing web token: %w", err)
+	}
+	return token, nil
+}
+
+// webUI serves the read-only town web UI from a snapshot the daemon
+// refreshes each heartbeat.
+type webUI struct {
+	mu       sync.RWMutex
+	snapshot *web.TownSnapshot
+	server   *http.Server
+}
+
+// Snapshot returns the latest town snapshot.
+func (w *webUI) Snapshot() *web.TownSnapshot {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.snapshot
+}
+
+func (w *webUI) setSnapshot(snapshot *web.TownSnapshot) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.snapshot = snapshot
+}
+
+// startWeb starts the web UI if an address is configured. Binding errors
+// are returned so a daemon asked for a UI does not silently run without one.
+func (d *Daemon) startWeb(state *State) error {
+	if d.config.WebAddr == "" {
+		return nil
+	}
+
+	token, err := resolveWebToken(d.config.TownRoot, d.config.WebToken)
+	if err != nil {
+		return err
+	}
+
+	ui := &webUI{}
+	handler, err := web.NewTownHandler(ui, token)
+	if err != nil {
+		return fmt.Errorf("creating web handler: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", d.config.WebAddr)
+	if err != nil {
+		return fmt.Errorf("web UI listen on %s: %w", d.config.WebAddr, err)
+	}
+	ui.server = &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+	go func() {
+		if err := ui.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			d.logger.Printf("Warning: web UI stopped: %v", err)
+		}
+	}()
+
+	d.web = ui
+	state.WebAddr = listener.Addr().String()
+	d.logger.Printf("Web UI listening on %s", state.WebAddr)
+	return nil
+}
+
+// stopWeb shuts down the web UI, if running.
+func (d *Daemon) stopWeb() {
+	if d.web == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = d.web.server.Shutdown(ctx)
+	d.logger.Println("Web UI stopped")
+}
+
+// refreshWebSnapshot rebuilds the web UI snapshot from daemon state, the
+// cached prompt summary, tmux, the events log, and the cost ledger.
+func (d *Daemon) refreshWebSnapshot(state *State) {
+	if d.web == nil {
+		return
+	}
+
+	snapshot := &web.TownSnapshot{
+		UpdatedAt: time.Now(),
+		Daemon: web.DaemonStatus{
+			PID:            state.PID,
+			Version:        state.Version,
+			StartedAt:      state.StartedAt,
+			LastHeartbeat:  state.LastHeartbeat,
+			HeartbeatCount: state.HeartbeatCount,
+			Health:         HealthOK,
+			TmuxError:      state.TmuxError,
+		},
+		Sessions: []string{},
+	}
+
+	if summary, err := LoadPromptSummary(d.config.TownRoot); err == nil && summary != nil {
+		snapshot.Daemon.Health = summary.Health
+		snapshot.Daemon.UnreadMail = summary.UnreadMail
+		snapshot.Daemon.ActiveAlerts = summary.ActiveAlerts
+	}
+	if state.TmuxError != "" {
+		snapshot.Daemon.Health = HealthDegraded
+	}
+
+	if sessions, err := d.tmux.ListSessions(); err == nil {
+		for _, s := range sessions {
+			if strings.HasPrefix(s, constants.SessionPrefix) || strings.HasPrefix(s, constants.HQSessionPrefix) {
+				snapshot.Sessions = append(snapshot.Sessions, s)
+			}
+		}
+		sort.Strings(snapshot.Sessions)
+	}
+
+	snapshot.Events, snapshot.Doctor, snapshot.Activity = scanWebEvents(d.config.TownRoot, webRecentEvents, time.Now())
+	snapshot.Costs = webCosts(d.config.TownRoot)
+
+	d.web.setSnapshot(snapshot)
+}
+
+// webCosts returns this week's cost ledger from `gt costs`, which owns
+// ledger queries. Returns nil if costs are unavailable. A seam for tests.
+var webCosts = func(townRoot string) *web.CostSummary {
+	cmd := exec.Command("gt", "costs", "--json", "--week", "--by-role", "--by-rig") //nolint:gosec // G204: args are constant
+	cmd.Dir = townRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var ledger struct {
+		Total  float64            `json:"total_usd"`
+		ByRole map[string]float64 `json:"by_role"`
+		ByRig  map[string]float64 `json:"by_rig"`
+		Period string             `json:"period"`
+	}
+	if err := json.Unmarshal(out, &ledger); err != nil {
+		return nil
+	}
+	return &web.CostSummary{
+		Period: ledger.Period,
+		Total:  ledger.Total,
+		ByRole: web.NewCostBars(ledger.ByRole),
+		ByRig:  web.NewCostBars(ledger.ByRig),
+	}
+}
+
+// scanWebEvents reads the town's events log and returns the most recent
+// feed events, newest first, with their stored bodies loaded; the outcome
+// of the latest doctor run (nil if there has been none); and feed activity
+// over webActivityWindow before now. Doctor findings are logged before
+// their run event.
+func scanWebEvents(townRoot string, limit int, now time.Time) ([]web.EventRow, *web.DoctorSummary, *web.Activity) {
+	rows := []web.EventRow{}
+	evs, err := events.ReadEvents(filepath.Join(townRoot, events.EventsFile), nil)
+	if err != nil {
+		return rows, nil, nil
+	}
+
+	var doctor *web.DoctorSummary
+	pending := make(map[string][]web.DoctorFinding) // run ID -> findings
+	for _, event := range evs {
+		ts, _ := event.Time()
+		run := payloadString(event.Payload, "run")
+
+		switch event.Type {
+		case events.TypeDoctorFinding:
+			pending[run] = append(pending[run], web.DoctorFinding{
+				Check:   payloadString(event.Payload, "check"),
+				Status:  payloadString(event.Payload, "status"),
+				Message: payloadString(event.Payload, "message"),
+			})
+		case events.TypeDoctorRun:
+			fix, _ := event.Payload["fix"].(bool)
+			doctor = &web.DoctorSummary{
+				RanAt:    ts,
+				Rig:      payloadString(event.Payload, "rig"),
+				Fix:      fix,
+				Counts:   make(map[string]int),
+				Findings: pending[run],
+			}
+			for k, v := range event.Payload {
+				if n, ok := v.(float64); ok {
+					doctor.Counts[k] = int(n)
+				}
+			}
+			pending = make(map[string][]web.DoctorFinding)
+		}
+
+		if event.Visibility == events.VisibilityAudit {
+			continue
+		}
+		payload := events.WithBodies(townRoot, event).Payload
+		rows = append(rows, web.EventRow{Time: ts, Type: event.Type, Actor: event.Actor, Summary: payloadSummary(payload)})
+		if len(rows) > limit {
+			rows = rows[1:]
+		}
+	}
+
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+
+	byType := events.Totals(events.Aggregate(evs, events.Query{
+		Since: now.Add(-webActivityWindow),
+		By:    []string{"type"},
+		Match: func(e events.Event) bool { return e.Visibility != events.VisibilityAudit },
+	}), "type")
+	activity := &web.Activity{Window: fmt.Sprintf("%dh", int(webActivityWindow.Hours())), ByType: web.NewActivityBars(byType)}
+	for _, n := range byType {
+		activity.Total += n
+	}
+	return rows, doctor, activity
+}
+
+// payloadString returns a string payload field, or "".
+func payloadString(payload map[string]interface{}, key string) string {
+	s, _ := payload[key].(string)
+	return s
+}
+
+// payloadSummary renders an event payload as "key=value" pairs in key order.
+func payloadSummary(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		switch v := payload[k].(type) {
+		case map[string]interface{}, []interface{}:
+			continue
+		default:
+			parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+		}
+	}
+	summary := strings.Join(parts, " ")
+	if len(summary) > 160 {
+		summary = summary[:157] + "..."
+	}
+	return summary
+}
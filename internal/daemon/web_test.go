@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestScanWebEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, ".events.jsonl")
+	lines := []string{
+		`{"ts":"2026-01-01T10:00:00Z","type":"doctor_finding","actor":"overseer","visibility":"audit","payload":{"run":"r1","check":"tmux","status":"error","message":"old"}}`,
+		`{"ts":"2026-01-01T10:00:01Z","type":"doctor_run","actor":"overseer","visibility":"audit","payload":{"run":"r1","ok":3,"errors":1}}`,
+		`{"ts":"2026-01-01T11:00:00Z","type":"sling","actor":"mayor","visibility":"feed","payload":{"bead":"gt-1","target":"gastown"}}`,
+		`not json`,
+		`{"ts":"2026-01-01T12:00:00Z","type":"doctor_finding","actor":"overseer","visibility":"audit","payload":{"run":"r2","check":"stale-git-locks","status":"warning","message":"1 stale lock"}}`,
+		`{"ts":"2026-01-01T12:00:01Z","type":"doctor_run","actor":"overseer","visibility":"audit","payload":{"run":"r2","rig":"gastown","fix":true,"ok":4,"warnings":1}}`,
+		`{"ts":"2026-01-01T13:00:00Z","type":"done","actor":"gastown/polecats/toast","visibility":"feed","payload":{"bead":"gt-1"}}`,
+		`{"ts":"2026-01-01T14:00:00Z","type":"hook","actor":"gastown/polecats/nux","visibility":"feed","payload":{"bead":"gt-2"}}`,
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	rows, doctor, activity := scanWebEvents(townRoot, 2, now)
+
+	if len(rows) != 2 || rows[0].Type != "hook" || rows[1].Type != "done" {
+		t.Errorf("rows = %+v, want the 2 newest feed events, newest first", rows)
+	}
+	if rows[0].Summary != "bead=gt-2" {
+		t.Errorf("summary = %q, want %q", rows[0].Summary, "bead=gt-2")
+	}
+
+	if doctor == nil {
+		t.Fatal("doctor = nil, want the latest run")
+	}
+	if doctor.Rig != "gastown" || !doctor.Fix || doctor.Counts["ok"] != 4 || doctor.Counts["warnings"] != 1 {
+		t.Errorf("doctor = %+v", doctor)
+	}
+	if len(doctor.Findings) != 1 || doctor.Findings[0].Check != "stale-git-locks" {
+		t.Errorf("findings = %+v, want only the latest run's", doctor.Findings)
+	}
+
+	// The sling is over 24h old; audit events are not activity
+	if activity == nil || activity.Total != 2 || len(activity.ByType) != 2 {
+		t.Fatalf("activity = %+v, want the done and hook events", activity)
+	}
+	if activity.ByType[0].Type != "done" || activity.ByType[0].Percent != 100 {
+		t.Errorf("activity bars = %+v", activity.ByType)
+	}
+}
+
+func TestScanWebEvents_MissingLog(t *testing.T) {
+	rows, doctor, _ := scanWebEvents(filepath.Join(t.TempDir(), "missing"), 10, time.Now())
+	if rows == nil || len(rows) != 0 || doctor != nil {
+		t.Errorf("rows = %v, doctor = %v; want empty rows and no doctor run", rows, doctor)
+	}
+}
+
+func TestScanWebEvents_LoadsBodies(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := events.LogIn(townRoot, events.TypeNudge, "deacon", events.NudgePayload("gastown", "toast", "check your mail"), events.VisibilityFeed); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, _, _ := scanWebEvents(townRoot, 10, time.Now())
+	if len(rows) != 1 || !strings.Contains(rows[0].Summary, "reason=check your mail") {
+		t.Errorf("rows = %+v, want the nudge with its reason", rows)
+	}
+}
+
+func TestResolveWebToken(t *testing.T) {
+	townRoot := t.TempDir()
+
+	generated, err := resolveWebToken(townRoot, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(generated) < 32 {
+		t.Errorf("generated token %q is too short", generated)
+	}
+	info, err := os.Stat(WebTokenFile(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("token file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	again, err := resolveWebToken(townRoot, "")
+	if err != nil || again != generated {
+		t.Errorf("second resolve = %q, %v; want the stored token", again, err)
+	}
+
+	configured, err := resolveWebToken(townRoot, "team-token")
+	if err != nil || configured != "team-token" {
+		t.Errorf("configured resolve = %q, %v", configured, err)
+	}
+	if got := LoadWebToken(townRoot); got != "team-token" {
+		t.Errorf("stored token = %q, want the configured one", got)
+	}
+}
@@ -7,9 +7,10 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/cursorworkshop/cursor-gastown/internal/agent"
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
-	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
 )
@@ -73,9 +74,18 @@ func (m *Manager) Start() error {
 		return fmt.Errorf("creating deacon directory: %w", err)
 	}
 
-	// Ensure Cursor settings exist
-	if err := cursor.EnsureSettingsForRole(deaconDir, "deacon"); err != nil {
-		return fmt.Errorf("ensuring Cursor settings: %w", err)
+	// Ensure settings exist for every agent the town runs
+	if err := agent.EnsureSettingsForAllAgents(m.townRoot, deaconDir, "deacon"); err != nil {
+		return fmt.Errorf("ensuring agent settings: %w", err)
+	}
+
+	if err := preflight.BeforeSpawn(preflight.Spec{
+		Session:     sessionID,
+		Role:        session.RoleDeacon,
+		TownRoot:    m.townRoot,
+		SettingsDir: deaconDir,
+	}); err != nil {
+		return err
 	}
 
 	// Create new tmux session
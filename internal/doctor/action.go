@@ -0,0 +1,90 @@
+package doctor
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// FixAction is a suggested command that resolves a check's problem. Unlike
+// FixHint prose, actions can be applied programmatically by the daemon, a
+// TUI, or an agent.
+type FixAction struct {
+	Command     string   `json:"command"`               // Executable, e.g. "gt", "bd", "git"
+	Args        []string `json:"args,omitempty"`        // Arguments, unquoted
+	Dir         string   `json:"dir,omitempty"`         // Working directory, absolute or relative to the town root (default: town root)
+	Description string   `json:"description,omitempty"` // What the action does, e.g. "kill orphaned sessions"
+	Destructive bool     `json:"destructive,omitempty"` // Deletes data or kills processes: confirm before applying
+}
+
+// doctorFix suggests 'gt doctor --fix', described by what the check's fix does.
+func doctorFix(description string, destructive bool) FixAction {
+	return FixAction{Command: "gt", Args: []string{"doctor", "--fix"}, Description: description, Destructive: destructive}
+}
+
+// rigDoctorFix suggests 'gt doctor --fix --rig <rig>'.
+func rigDoctorFix(rigName, description string) FixAction {
+	return FixAction{Command: "gt", Args: []string{"doctor", "--fix", "--rig", rigName}, Description: description}
+}
+
+// String returns the action as a shell command line.
+func (a FixAction) String() string {
+	parts := []string{a.Command}
+	for _, arg := range a.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+	line := strings.Join(parts, " ")
+	if a.Dir != "" && a.Dir != "." {
+		line = "cd " + shellQuote(a.Dir) + " && " + line
+	}
+	return line
+}
+
+// Cmd builds the command for the action, run from townRoot (or Dir within it).
+func (a FixAction) Cmd(ctx context.Context, townRoot string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, a.Command, a.Args...) //nolint:gosec // G204: actions come from doctor checks
+	cmd.Dir = townRoot
+	if filepath.IsAbs(a.Dir) {
+		cmd.Dir = a.Dir
+	} else if a.Dir != "" {
+		cmd.Dir = filepath.Join(townRoot, a.Dir)
+	}
+	return cmd
+}
+
+// Hint returns the human-readable fix suggestion: each action as
+// "Run '<command>' to <description>", then the FixHint prose.
+func (r *CheckResult) Hint() string {
+	var parts, actions []string
+	for i, a := range r.Actions {
+		s := "'" + a.String() + "'"
+		if a.Description != "" {
+			s += " to " + a.Description
+		}
+		if a.Destructive {
+			s += " (destructive)"
+		}
+		if i == 0 {
+			s = "Run " + s
+		} else {
+			s = "or " + s
+		}
+		actions = append(actions, s)
+	}
+	if len(actions) > 0 {
+		parts = append(parts, strings.Join(actions, ", "))
+	}
+	if r.FixHint != "" {
+		parts = append(parts, r.FixHint)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// shellQuote quotes s for a POSIX shell when it contains special characters.
+func shellQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
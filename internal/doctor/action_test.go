@@ -0,0 +1,90 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestFixActionString(t *testing.T) {
+	tests := []struct {
+		action FixAction
+		want   string
+	}{
+		{doctorFix("", false), "gt doctor --fix"},
+		{FixAction{Command: "bd", Args: []string{"migrate", "--update-repo-id"}, Dir: "gp"}, "cd gp && bd migrate --update-repo-id"},
+		{FixAction{Command: "git", Args: []string{"commit", "-m", "it's done"}}, `git commit -m 'it'\''s done'`},
+		{FixAction{Command: "echo", Args: []string{""}, Dir: "."}, "echo ''"},
+	}
+	for _, tt := range tests {
+		if got := tt.action.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCheckResultHint(t *testing.T) {
+	r := &CheckResult{
+		Actions: []FixAction{
+			doctorFix("kill orphans", true),
+			{Command: "gt", Args: []string{"daemon", "start"}},
+		},
+		FixHint: "then re-run gt doctor",
+	}
+	want := "Run 'gt doctor --fix' to kill orphans (destructive), or 'gt daemon start'; then re-run gt doctor"
+	if got := r.Hint(); got != want {
+		t.Errorf("Hint() = %q, want %q", got, want)
+	}
+
+	prose := &CheckResult{FixHint: "Fix JSON syntax in mayor/town.json"}
+	if got := prose.Hint(); got != prose.FixHint {
+		t.Errorf("Hint() without actions = %q", got)
+	}
+}
+
+func TestFixActionCmdDir(t *testing.T) {
+	townRoot := t.TempDir()
+	ctx := context.Background()
+
+	if cmd := (FixAction{Command: "gt"}).Cmd(ctx, townRoot); cmd.Dir != townRoot {
+		t.Errorf("default dir = %q, want town root", cmd.Dir)
+	}
+	if cmd := (FixAction{Command: "bd", Dir: "gp"}).Cmd(ctx, townRoot); cmd.Dir != filepath.Join(townRoot, "gp") {
+		t.Errorf("relative dir = %q", cmd.Dir)
+	}
+	if cmd := (FixAction{Command: "bd", Dir: "/elsewhere"}).Cmd(ctx, townRoot); cmd.Dir != "/elsewhere" {
+		t.Errorf("absolute dir = %q", cmd.Dir)
+	}
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	report := NewReport()
+	report.Add(&CheckResult{
+		Name:    "orphan-sessions",
+		Status:  StatusWarning,
+		Message: "2 orphaned sessions",
+		Actions: []FixAction{doctorFix("kill them", true)},
+	})
+	report.Add(&CheckResult{Name: "daemon", Status: StatusOK, Message: "ok"})
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf, "/town"); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	var got jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.TownRoot != "/town" || got.Summary.Warnings != 1 || len(got.Checks) != 2 {
+		t.Fatalf("unexpected report: %+v", got)
+	}
+	orphans := got.Checks[0]
+	if orphans.Status != "warning" || len(orphans.Actions) != 1 {
+		t.Fatalf("unexpected check: %+v", orphans)
+	}
+	if a := orphans.Actions[0]; a.Command != "gt" || !a.Destructive || len(a.Args) != 2 || a.Args[1] != "--fix" {
+		t.Errorf("action did not round-trip: %+v", a)
+	}
+}
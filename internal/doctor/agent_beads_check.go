@@ -118,7 +118,7 @@ func (c *AgentBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 			Status:  StatusError,
 			Message: fmt.Sprintf("%d agent bead(s) missing", len(missing)),
 			Details: missing,
-			FixHint: "Run 'gt doctor --fix' to create missing agent beads",
+			Actions: []FixAction{doctorFix("create missing agent beads", false)},
 		}
 	}
 
@@ -167,7 +167,7 @@ func (c *AgentBeadsCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusError,
 		Message: fmt.Sprintf("%d agent bead(s) missing", len(missing)),
 		Details: missing,
-		FixHint: "Run 'gt doctor --fix' to create missing agent beads",
+		Actions: []FixAction{doctorFix("create missing agent beads", false)},
 	}
 }
 
@@ -0,0 +1,263 @@
+package doctor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// BackupsDir is where fix-run backups are stored, relative to the town root.
+const BackupsDir = ".runtime/doctor-backups"
+
+// backupManifestFile is the manifest written into each run's backup directory.
+const backupManifestFile = "manifest.json"
+
+// ErrNoBackups is returned when rollback is requested but no fix run has
+// recorded a backup.
+var ErrNoBackups = errors.New("no doctor fix backups found")
+
+// BackupEntry records one path captured before a fixer changed it.
+type BackupEntry struct {
+	Path   string `json:"path"`             // Absolute original path
+	Stored string `json:"stored,omitempty"` // Copy inside the run dir; empty if the path did not exist
+}
+
+// BackupManifest describes a single fix run's backup.
+type BackupManifest struct {
+	RunID     string        `json:"run_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Entries   []BackupEntry `json:"entries"`
+
+	// RolledBackAt is set once the run has been rolled back.
+	RolledBackAt *time.Time `json:"rolled_back_at,omitempty"`
+}
+
+// FixBackup captures files and directories before fixers delete or
+// overwrite them, so the whole fix run can be undone with
+// 'gt doctor rollback'. A nil *FixBackup is valid and records nothing.
+type FixBackup struct {
+	manifest BackupManifest
+	dir      string
+	saved    map[string]bool
+}
+
+// NewFixBackup creates a backup for a fix run starting at now. Nothing is
+// written to disk until the first path is saved.
+func NewFixBackup(townRoot string, now time.Time) *FixBackup {
+	root := filepath.Join(townRoot, BackupsDir)
+	runID := now.UTC().Format("20060102-150405")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(root, runID)); os.IsNotExist(err) {
+			break
+		}
+		runID = now.UTC().Format("20060102-150405") + "-" + strconv.Itoa(i)
+	}
+	return &FixBackup{
+		manifest: BackupManifest{RunID: runID, CreatedAt: now},
+		dir:      filepath.Join(root, runID),
+		saved:    make(map[string]bool),
+	}
+}
+
+// RunID returns the identifier used with 'gt doctor rollback'.
+func (b *FixBackup) RunID() string {
+	if b == nil {
+		return ""
+	}
+	return b.manifest.RunID
+}
+
+// Len returns the number of paths captured so far.
+func (b *FixBackup) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.manifest.Entries)
+}
+
+// Save captures path before it is modified. Paths that do not exist yet are
+// recorded so rollback removes whatever the fixer created. Only the first
+// capture of a path is kept. Fixers must not modify path if Save fails.
+func (b *FixBackup) Save(path string) error {
+	if b == nil {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", path, err)
+	}
+	if b.saved[abs] {
+		return nil
+	}
+
+	entry := BackupEntry{Path: abs}
+	if _, err := os.Lstat(abs); err == nil {
+		entry.Stored = filepath.Join("files", strconv.Itoa(len(b.manifest.Entries)), filepath.Base(abs))
+		if err := copyTree(abs, filepath.Join(b.dir, entry.Stored)); err != nil {
+			return fmt.Errorf("backing up %s: %w", abs, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("backing up %s: %w", abs, err)
+	}
+
+	b.manifest.Entries = append(b.manifest.Entries, entry)
+	b.saved[abs] = true
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return fmt.Errorf("creating backup dir: %w", err)
+	}
+	return util.AtomicWriteJSON(filepath.Join(b.dir, backupManifestFile), b.manifest)
+}
+
+// ListBackups returns the manifests of all recorded fix runs, newest first.
+func ListBackups(townRoot string) ([]BackupManifest, error) {
+	root := filepath.Join(townRoot, BackupsDir)
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var manifests []BackupManifest
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		m, err := loadBackupManifest(filepath.Join(root, e.Name()))
+		if err != nil {
+			continue // Incomplete or foreign directory
+		}
+		manifests = append(manifests, *m)
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].CreatedAt.After(manifests[j].CreatedAt)
+	})
+	return manifests, nil
+}
+
+func loadBackupManifest(dir string) (*BackupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, backupManifestFile)) //nolint:gosec // G304: path is under the town backups dir
+	if err != nil {
+		return nil, err
+	}
+	var m BackupManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Rollback restores every path captured by a fix run. An empty runID selects
+// the most recent run that has not already been rolled back. Paths are
+// restored in reverse capture order, and paths that did not exist before the
+// run are removed. Returns the manifest used.
+func Rollback(townRoot, runID string) (*BackupManifest, error) {
+	if runID == "" {
+		manifests, err := ListBackups(townRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range manifests {
+			if m.RolledBackAt == nil {
+				runID = m.RunID
+				break
+			}
+		}
+		if runID == "" {
+			return nil, ErrNoBackups
+		}
+	}
+
+	dir := filepath.Join(townRoot, BackupsDir, runID)
+	m, err := loadBackupManifest(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("fix run %q not found", runID)
+		}
+		return nil, fmt.Errorf("reading backup manifest: %w", err)
+	}
+
+	var errs []error
+	for i := len(m.Entries) - 1; i >= 0; i-- {
+		entry := m.Entries[i]
+		if err := os.RemoveAll(entry.Path); err != nil {
+			errs = append(errs, fmt.Errorf("removing %s: %w", entry.Path, err))
+			continue
+		}
+		if entry.Stored == "" {
+			continue
+		}
+		if err := copyTree(filepath.Join(dir, entry.Stored), entry.Path); err != nil {
+			errs = append(errs, fmt.Errorf("restoring %s: %w", entry.Path, err))
+		}
+	}
+	if len(errs) == 0 {
+		now := time.Now()
+		m.RolledBackAt = &now
+		if err := util.AtomicWriteJSON(filepath.Join(dir, backupManifestFile), m); err != nil {
+			errs = append(errs, fmt.Errorf("updating backup manifest: %w", err))
+		}
+	}
+	return m, errors.Join(errs...)
+}
+
+// copyTree copies a file, symlink, or directory tree from src to dst,
+// preserving permissions.
+func copyTree(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+
+	case info.IsDir():
+		if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := copyTree(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		in, err := os.Open(src) //nolint:gosec // G304: src is a path being backed up
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm()) //nolint:gosec // G304: dst is inside the backup dir or the original location
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			_ = out.Close()
+			return err
+		}
+		return out.Close()
+	}
+}
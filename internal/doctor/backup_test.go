@@ -0,0 +1,97 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFixBackupRollback(t *testing.T) {
+	townRoot := t.TempDir()
+
+	overwritten := filepath.Join(townRoot, "mayor", "rigs.json")
+	deletedDir := filepath.Join(townRoot, "gastown", ".gastown")
+	created := filepath.Join(townRoot, "gastown", "witness", "mail", "inbox.jsonl")
+
+	mustWrite(t, overwritten, `{"version":1,"rigs":{"gastown":{}}}`)
+	mustWrite(t, filepath.Join(deletedDir, "state.json"), `{"legacy":true}`)
+
+	b := NewFixBackup(townRoot, time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	ctx := &CheckContext{TownRoot: townRoot, Backup: b}
+
+	// Simulate fixers: overwrite, delete, create.
+	for _, p := range []string{overwritten, deletedDir, created, overwritten} {
+		if err := ctx.Backup.Save(p); err != nil {
+			t.Fatalf("Save(%s): %v", p, err)
+		}
+	}
+	mustWrite(t, overwritten, `{"version":1,"rigs":{}}`)
+	if err := os.RemoveAll(deletedDir); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, created, "")
+
+	if b.Len() != 3 {
+		t.Errorf("Len = %d, want 3 (duplicate saves ignored)", b.Len())
+	}
+
+	m, err := Rollback(townRoot, "")
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if m.RunID != "20260101-120000" {
+		t.Errorf("RunID = %q", m.RunID)
+	}
+
+	if got := mustRead(t, overwritten); got != `{"version":1,"rigs":{"gastown":{}}}` {
+		t.Errorf("rigs.json not restored: %s", got)
+	}
+	if got := mustRead(t, filepath.Join(deletedDir, "state.json")); got != `{"legacy":true}` {
+		t.Errorf("deleted dir not restored: %s", got)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("file created by fix should be removed, stat err = %v", err)
+	}
+
+	// The run is now marked rolled back and not picked again by default.
+	if _, err := Rollback(townRoot, ""); !errors.Is(err, ErrNoBackups) {
+		t.Errorf("second Rollback err = %v, want ErrNoBackups", err)
+	}
+}
+
+func TestFixBackupNil(t *testing.T) {
+	var b *FixBackup
+	if err := b.Save("/nonexistent"); err != nil {
+		t.Errorf("nil Save: %v", err)
+	}
+	if b.Len() != 0 || b.RunID() != "" {
+		t.Error("nil backup should be empty")
+	}
+}
+
+func TestRollbackUnknownRun(t *testing.T) {
+	if _, err := Rollback(t.TempDir(), "nope"); err == nil {
+		t.Error("expected error for unknown run")
+	}
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustRead(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
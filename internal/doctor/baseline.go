@@ -0,0 +1,148 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// BaselineFile is the town's acknowledged doctor findings, relative to the
+// town root. It lives with the town settings so it can be reviewed and
+// shared like them.
+const BaselineFile = "settings/doctor-baseline.json"
+
+// Baseline records doctor findings acknowledged with 'gt doctor baseline
+// save'. Later runs report a check whose findings are all baselined as
+// passing, so only new findings fail the run.
+type Baseline struct {
+	SavedAt   time.Time         `json:"saved_at"`
+	GTVersion string            `json:"gt_version,omitempty"`
+	Findings  []BaselineFinding `json:"findings"`
+
+	known map[string]CheckStatus // finding key -> baselined severity
+}
+
+// BaselineFinding is one acknowledged finding: a detail line of a failing
+// check, or its message when it has no details.
+type BaselineFinding struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"` // warning or error
+	Finding string `json:"finding"`
+}
+
+// BaselinePath returns the path to a town's doctor baseline.
+func BaselinePath(townRoot string) string {
+	return filepath.Join(townRoot, BaselineFile)
+}
+
+// LoadBaseline loads a town's doctor baseline, returning nil if none has
+// been saved.
+func LoadBaseline(townRoot string) (*Baseline, error) {
+	data, err := os.ReadFile(BaselinePath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", BaselineFile, err)
+	}
+	b.index()
+	return &b, nil
+}
+
+// SaveBaseline writes a town's doctor baseline.
+func SaveBaseline(townRoot string, b *Baseline) error {
+	path := BaselinePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, b)
+}
+
+// NewBaseline records every warning and error finding in report.
+func NewBaseline(report *Report, version string) *Baseline {
+	b := &Baseline{SavedAt: time.Now().UTC(), GTVersion: version, Findings: []BaselineFinding{}}
+	for _, check := range report.Checks {
+		if check.Status != StatusWarning && check.Status != StatusError {
+			continue
+		}
+		for _, finding := range findings(check) {
+			b.Findings = append(b.Findings, BaselineFinding{
+				Check:   check.Name,
+				Status:  strings.ToLower(check.Status.String()),
+				Finding: finding,
+			})
+		}
+	}
+	sort.SliceStable(b.Findings, func(i, j int) bool { return b.Findings[i].Check < b.Findings[j].Check })
+	b.index()
+	return b
+}
+
+// Apply marks result as baselined when every one of its findings is in
+// the baseline at the same or a higher severity. When only some are, the
+// known details are dropped so the result shows just the new ones.
+// Passing, timed-out, and blocked results are left alone. Safe on nil.
+func (b *Baseline) Apply(result *CheckResult) {
+	if b == nil || (result.Status != StatusWarning && result.Status != StatusError) {
+		return
+	}
+	known := func(finding string) bool {
+		status, ok := b.known[baselineKey(result.Name, finding)]
+		return ok && status >= result.Status
+	}
+
+	if len(result.Details) == 0 {
+		result.Baselined = known(result.Message)
+		return
+	}
+	var fresh []string
+	for _, detail := range result.Details {
+		if !known(detail) {
+			fresh = append(fresh, detail)
+		}
+	}
+	switch n := len(result.Details) - len(fresh); {
+	case len(fresh) == 0:
+		result.Baselined = true
+	case n > 0:
+		result.Details = append(fresh, fmt.Sprintf("(%d baselined finding(s) not shown)", n))
+	}
+}
+
+// index builds the lookup used by Apply.
+func (b *Baseline) index() {
+	b.known = make(map[string]CheckStatus, len(b.Findings))
+	for _, f := range b.Findings {
+		status := StatusWarning
+		if f.Status == "error" {
+			status = StatusError
+		}
+		key := baselineKey(f.Check, f.Finding)
+		if status > b.known[key] {
+			b.known[key] = status
+		}
+	}
+}
+
+// findings returns the individual findings of a failing result: its
+// details, or its message when it has none.
+func findings(result *CheckResult) []string {
+	if len(result.Details) == 0 {
+		return []string{result.Message}
+	}
+	return result.Details
+}
+
+func baselineKey(check, finding string) string {
+	return check + "\x00" + strings.TrimSpace(finding)
+}
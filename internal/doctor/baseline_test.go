@@ -0,0 +1,116 @@
+package doctor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBaseline_Apply(t *testing.T) {
+	saved := NewReport()
+	saved.Add(&CheckResult{Name: "stale-locks", Status: StatusWarning, Message: "2 stale locks", Details: []string{"a.lock", "b.lock"}})
+	saved.Add(&CheckResult{Name: "daemon", Status: StatusWarning, Message: "daemon not running"})
+	saved.Add(&CheckResult{Name: "town-config", Status: StatusOK, Message: "ok"})
+	b := NewBaseline(saved, "test")
+	if len(b.Findings) != 3 {
+		t.Fatalf("Findings = %+v, want the two lock details and the daemon message", b.Findings)
+	}
+
+	tests := []struct {
+		name        string
+		result      CheckResult
+		baselined   bool
+		wantDetails []string
+	}{
+		{"same details", CheckResult{Name: "stale-locks", Status: StatusWarning, Details: []string{"b.lock", "a.lock"}}, true, nil},
+		{"subset of details", CheckResult{Name: "stale-locks", Status: StatusWarning, Details: []string{"a.lock"}}, true, nil},
+		{"new detail", CheckResult{Name: "stale-locks", Status: StatusWarning, Details: []string{"a.lock", "c.lock"}}, false,
+			[]string{"c.lock", "(1 baselined finding(s) not shown)"}},
+		{"message only", CheckResult{Name: "daemon", Status: StatusWarning, Message: "daemon not running"}, true, nil},
+		{"changed message", CheckResult{Name: "daemon", Status: StatusWarning, Message: "daemon crashed"}, false, nil},
+		{"escalated to error", CheckResult{Name: "daemon", Status: StatusError, Message: "daemon not running"}, false, nil},
+		{"other check", CheckResult{Name: "hooks", Status: StatusWarning, Message: "daemon not running"}, false, nil},
+		{"timeout", CheckResult{Name: "daemon", Status: StatusTimeout, Message: "daemon not running"}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.result
+			b.Apply(&result)
+			if result.Baselined != tt.baselined {
+				t.Errorf("Baselined = %v, want %v", result.Baselined, tt.baselined)
+			}
+			if tt.wantDetails != nil && strings.Join(result.Details, "|") != strings.Join(tt.wantDetails, "|") {
+				t.Errorf("Details = %q, want %q", result.Details, tt.wantDetails)
+			}
+		})
+	}
+
+	// A nil baseline changes nothing
+	var none *Baseline
+	result := &CheckResult{Name: "daemon", Status: StatusWarning, Message: "daemon not running"}
+	none.Apply(result)
+	if result.Baselined {
+		t.Error("nil baseline marked a result as baselined")
+	}
+}
+
+func TestBaseline_RoundTripAndRun(t *testing.T) {
+	townRoot := t.TempDir()
+	if b, err := LoadBaseline(townRoot); err != nil || b != nil {
+		t.Fatalf("LoadBaseline with no file = %v, %v; want nil, nil", b, err)
+	}
+
+	run := func(b *Baseline) *Report {
+		d := NewDoctor()
+		d.Register(newMockCheck("known", StatusError))
+		d.Register(newMockCheck("healthy", StatusOK))
+		d.SetBaseline(b)
+		return d.Run(&CheckContext{TownRoot: townRoot})
+	}
+
+	first := run(nil)
+	if first.ExitCode() != ExitErrors {
+		t.Fatalf("ExitCode() without baseline = %d, want %d", first.ExitCode(), ExitErrors)
+	}
+	if err := SaveBaseline(townRoot, NewBaseline(first, "test")); err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadBaseline(townRoot)
+	if err != nil || b == nil {
+		t.Fatalf("LoadBaseline = %v, %v", b, err)
+	}
+
+	report := run(b)
+	if report.ExitCode() != ExitOK {
+		t.Errorf("ExitCode() with baseline = %d, want %d", report.ExitCode(), ExitOK)
+	}
+	if report.Summary.Baselined != 1 || report.Summary.Errors != 0 || report.Summary.OK != 1 {
+		t.Errorf("Summary = %+v, want 1 baselined, 0 errors, 1 OK", report.Summary)
+	}
+}
+
+func TestBaseline_DoesNotBlockDependents(t *testing.T) {
+	layout := newMockCheck("layout", StatusError)
+	settings := newMockCheck("settings", StatusError)
+	settings.CheckDependsOn = []string{"layout"}
+	run := func(b *Baseline) *Report {
+		d := NewDoctor()
+		d.RegisterAll(layout, settings)
+		d.SetBaseline(b)
+		return d.Run(&CheckContext{TownRoot: t.TempDir()})
+	}
+
+	// Baseline only the prerequisite: its dependent must still run and
+	// its own error must still fail the run.
+	first := run(nil)
+	b := NewBaseline(&Report{Checks: first.Checks[:1]}, "test")
+	report := run(b)
+	if got := report.Checks[1].Status; got != StatusError {
+		t.Errorf("dependent of a baselined check: status = %v, want Error", got)
+	}
+	if report.Summary.Blocked != 0 || report.Summary.Baselined != 1 || report.Summary.Errors != 1 {
+		t.Errorf("Summary = %+v, want 0 blocked, 1 baselined, 1 error", report.Summary)
+	}
+	if report.ExitCode() != ExitErrors {
+		t.Errorf("ExitCode() = %d, want %d", report.ExitCode(), ExitErrors)
+	}
+}
@@ -28,7 +28,7 @@ func NewBdDaemonCheck() *BdDaemonCheck {
 // Run checks if the bd daemon is running and healthy.
 func (c *BdDaemonCheck) Run(ctx *CheckContext) *CheckResult {
 	// Check daemon status
-	cmd := exec.Command("bd", "daemon", "--status")
+	cmd := exec.CommandContext(ctx, "bd", "daemon", "--status")
 	cmd.Dir = ctx.TownRoot
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -40,7 +40,7 @@ func (c *BdDaemonCheck) Run(ctx *CheckContext) *CheckResult {
 	// Check if daemon is running
 	if err == nil && strings.Contains(output, "Daemon is running") {
 		// Daemon is running, now check health
-		healthCmd := exec.Command("bd", "daemon", "--health")
+		healthCmd := exec.CommandContext(ctx, "bd", "daemon", "--health")
 		healthCmd.Dir = ctx.TownRoot
 		var healthOut bytes.Buffer
 		healthCmd.Stdout = &healthOut
@@ -81,7 +81,7 @@ func (c *BdDaemonCheck) Run(ctx *CheckContext) *CheckResult {
 
 // tryStartDaemon attempts to start the bd daemon and returns any error output.
 func (c *BdDaemonCheck) tryStartDaemon(ctx *CheckContext) *startError {
-	cmd := exec.Command("bd", "daemon", "--start")
+	cmd := exec.CommandContext(ctx, "bd", "daemon", "--start")
 	cmd.Dir = ctx.TownRoot
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -117,7 +117,7 @@ func (c *BdDaemonCheck) parseStartError(err *startError) *CheckResult {
 				"Database was created before bd version 0.17.5",
 				"Missing repository fingerprint prevents daemon from starting",
 			},
-			FixHint: "Run 'bd migrate --update-repo-id' to add fingerprint",
+			Actions: []FixAction{{Command: "bd", Args: []string{"migrate", "--update-repo-id"}, Description: "add fingerprint"}},
 		}
 	}
 
@@ -131,7 +131,8 @@ func (c *BdDaemonCheck) parseStartError(err *startError) *CheckResult {
 				"The .beads database was created for a different git repository",
 				"This can happen if .beads was copied or if the git remote URL changed",
 			},
-			FixHint: "Run 'bd migrate --update-repo-id' if URL changed, or 'rm -rf .beads && bd init' for fresh start",
+			Actions: []FixAction{{Command: "bd", Args: []string{"migrate", "--update-repo-id"}, Description: "update the fingerprint if the URL changed"}},
+			FixHint: "or start fresh with 'rm -rf .beads && bd init'",
 		}
 	}
 
@@ -162,7 +163,8 @@ func (c *BdDaemonCheck) parseStartError(err *startError) *CheckResult {
 			Status:  StatusError,
 			Message: "bd daemon failed: database may be corrupted",
 			Details: []string{output},
-			FixHint: "Run 'bd repair' or 'rm .beads/issues.db && bd sync --from-main'",
+			Actions: []FixAction{{Command: "bd", Args: []string{"repair"}, Description: "repair the database"}},
+			FixHint: "or rebuild it with 'rm .beads/issues.db && bd sync --from-main'",
 		}
 	}
 
@@ -193,20 +195,20 @@ func (c *BdDaemonCheck) Fix(ctx *CheckContext) error {
 	if strings.Contains(startErr.output, "LEGACY DATABASE") ||
 		strings.Contains(startErr.output, "DATABASE MISMATCH") {
 
-		migrateCmd := exec.Command("bd", "migrate", "--update-repo-id", "--yes")
+		migrateCmd := exec.CommandContext(ctx, "bd", "migrate", "--update-repo-id", "--yes")
 		migrateCmd.Dir = ctx.TownRoot
 		if err := migrateCmd.Run(); err != nil {
 			return err
 		}
 
 		// Try starting again
-		startCmd := exec.Command("bd", "daemon", "--start")
+		startCmd := exec.CommandContext(ctx, "bd", "daemon", "--start")
 		startCmd.Dir = ctx.TownRoot
 		return startCmd.Run()
 	}
 
 	// For other errors, just try to start
-	startCmd := exec.Command("bd", "daemon", "--start")
+	startCmd := exec.CommandContext(ctx, "bd", "daemon", "--start")
 	startCmd.Dir = ctx.TownRoot
 	return startCmd.Run()
 }
@@ -40,7 +40,7 @@ func (c *BeadsDatabaseCheck) Run(ctx *CheckContext) *CheckResult {
 			Name:    c.Name(),
 			Status:  StatusWarning,
 			Message: "No .beads directory found at town root",
-			FixHint: "Run 'bd init' to initialize beads",
+			Actions: []FixAction{{Command: "bd", Args: []string{"init"}, Description: "initialize beads"}},
 		}
 	}
 
@@ -71,7 +71,8 @@ func (c *BeadsDatabaseCheck) Run(ctx *CheckContext) *CheckResult {
 					"This can cause 'table issues has no column named pinned' errors",
 					"The database needs to be rebuilt from the JSONL file",
 				},
-				FixHint: "Run 'gt doctor --fix' or delete issues.db and run 'bd sync --from-main'",
+				Actions: []FixAction{doctorFix("rebuild issues.db from issues.jsonl", true)},
+				FixHint: "or delete issues.db and run 'bd sync --from-main'",
 			}
 		}
 	}
@@ -97,7 +98,8 @@ func (c *BeadsDatabaseCheck) Run(ctx *CheckContext) *CheckResult {
 							"Rig: " + ctx.RigName,
 							"This can cause 'table issues has no column named pinned' errors",
 						},
-						FixHint: "Run 'gt doctor --fix' or delete the rig's issues.db",
+						Actions: []FixAction{doctorFix("rebuild the rig's issues.db", true)},
+						FixHint: "or delete the rig's issues.db",
 					}
 				}
 			}
@@ -123,12 +125,15 @@ func (c *BeadsDatabaseCheck) Fix(ctx *CheckContext) error {
 
 	if dbErr == nil && dbInfo.Size() == 0 && jsonlErr == nil && jsonlInfo.Size() > 0 {
 		// Delete the empty database file
+		if err := ctx.Backup.Save(issuesDB); err != nil {
+			return err
+		}
 		if err := os.Remove(issuesDB); err != nil {
 			return err
 		}
 
 		// Run bd sync to rebuild from JSONL
-		cmd := exec.Command("bd", "sync", "--from-main")
+		cmd := exec.CommandContext(ctx, "bd", "sync", "--from-main")
 		cmd.Dir = ctx.TownRoot
 		var stderr bytes.Buffer
 		cmd.Stderr = &stderr
@@ -147,11 +152,14 @@ func (c *BeadsDatabaseCheck) Fix(ctx *CheckContext) error {
 		rigJSONLInfo, rigJSONLErr := os.Stat(rigJSONL)
 
 		if rigDBErr == nil && rigDBInfo.Size() == 0 && rigJSONLErr == nil && rigJSONLInfo.Size() > 0 {
+			if err := ctx.Backup.Save(rigDB); err != nil {
+				return err
+			}
 			if err := os.Remove(rigDB); err != nil {
 				return err
 			}
 
-			cmd := exec.Command("bd", "sync", "--from-main")
+			cmd := exec.CommandContext(ctx, "bd", "sync", "--from-main")
 			cmd.Dir = ctx.RigPath()
 			var stderr bytes.Buffer
 			cmd.Stderr = &stderr
@@ -336,7 +344,7 @@ func (c *PrefixMismatchCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("%d prefix mismatch(es) between rigs.json and routes.jsonl", len(mismatches)),
 		Details: details,
-		FixHint: "Run 'gt doctor --fix' to update rigs.json with correct prefixes",
+		Actions: []FixAction{doctorFix("update rigs.json with correct prefixes", false)},
 	}
 }
 
@@ -386,6 +394,9 @@ func (c *PrefixMismatchCheck) Fix(ctx *CheckContext) error {
 	}
 
 	if modified {
+		if err := ctx.Backup.Save(rigsPath); err != nil {
+			return err
+		}
 		return saveRigsConfig(rigsPath, rigsConfig)
 	}
 
@@ -1,6 +1,7 @@
 package doctor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -38,7 +39,7 @@ func (c *BranchCheck) Run(ctx *CheckContext) *CheckResult {
 	dirs := c.findPersistentRoleDirs(ctx.TownRoot)
 
 	for _, dir := range dirs {
-		branch, err := c.getCurrentBranch(dir)
+		branch, err := c.getCurrentBranch(ctx, dir)
 		if err != nil {
 			// Skip directories that aren't git repos
 			continue
@@ -55,7 +56,7 @@ func (c *BranchCheck) Run(ctx *CheckContext) *CheckResult {
 	// Cache for Fix
 	c.offMainDirs = nil
 	for _, dir := range dirs {
-		branch, err := c.getCurrentBranch(dir)
+		branch, err := c.getCurrentBranch(ctx, dir)
 		if err != nil {
 			continue
 		}
@@ -85,7 +86,7 @@ func (c *BranchCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("%d persistent role(s) not on expected branch", len(offExpected)),
 		Details: offExpected,
-		FixHint: "Run 'gt doctor --fix' to switch to expected branch",
+		Actions: []FixAction{doctorFix("switch to expected branch", false)},
 	}
 }
 
@@ -100,7 +101,7 @@ func (c *BranchCheck) Fix(ctx *CheckContext) error {
 		expectedBranch := c.getExpectedBranch(ctx.TownRoot, dir)
 
 		// git checkout <expected-branch>
-		cmd := exec.Command("git", "checkout", expectedBranch)
+		cmd := exec.CommandContext(ctx, "git", "checkout", expectedBranch)
 		cmd.Dir = dir
 		if err := cmd.Run(); err != nil {
 			lastErr = fmt.Errorf("%s: %w", dir, err)
@@ -108,7 +109,7 @@ func (c *BranchCheck) Fix(ctx *CheckContext) error {
 		}
 
 		// git pull --rebase
-		cmd = exec.Command("git", "pull", "--rebase")
+		cmd = exec.CommandContext(ctx, "git", "pull", "--rebase")
 		cmd.Dir = dir
 		if err := cmd.Run(); err != nil {
 			// Pull failure is not fatal, just warn
@@ -221,8 +222,8 @@ func (c *BranchCheck) isRig(path string) bool {
 }
 
 // getCurrentBranch returns the current git branch for a directory.
-func (c *BranchCheck) getCurrentBranch(dir string) (string, error) {
-	cmd := exec.Command("git", "branch", "--show-current")
+func (c *BranchCheck) getCurrentBranch(ctx context.Context, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "branch", "--show-current")
 	cmd.Dir = dir
 	out, err := cmd.Output()
 	if err != nil {
@@ -272,7 +273,7 @@ func (c *BeadsSyncOrphanCheck) Run(ctx *CheckContext) *CheckResult {
 	crewDir := crewDirs[0]
 
 	// Check if beads-sync branch exists
-	cmd := exec.Command("git", "rev-parse", "--verify", "beads-sync")
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "beads-sync")
 	cmd.Dir = crewDir
 	if err := cmd.Run(); err != nil {
 		return &CheckResult{
@@ -283,7 +284,7 @@ func (c *BeadsSyncOrphanCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Get diff between main and beads-sync, excluding .beads/
-	cmd = exec.Command("git", "diff", "--name-only", "main..beads-sync", "--", ".", ":(exclude).beads")
+	cmd = exec.CommandContext(ctx, "git", "diff", "--name-only", "main..beads-sync", "--", ".", ":(exclude).beads")
 	cmd.Dir = crewDir
 	out, err := cmd.Output()
 	if err != nil {
@@ -403,7 +404,7 @@ func (c *CloneDivergenceCheck) Run(ctx *CheckContext) *CheckResult {
 	// Gather info about each clone
 	var infos []cloneInfo
 	for _, path := range clones {
-		info, err := c.getCloneInfo(path)
+		info, err := c.getCloneInfo(ctx, path)
 		if err != nil {
 			continue // Skip problematic clones
 		}
@@ -421,6 +422,7 @@ func (c *CloneDivergenceCheck) Run(ctx *CheckContext) *CheckResult {
 	// Check for clones significantly behind origin/main
 	var warnings []string
 	var errors []string
+	var actions []FixAction
 
 	for _, info := range infos {
 		relPath := c.relativePath(ctx.TownRoot, info.path)
@@ -434,7 +436,10 @@ func (c *CloneDivergenceCheck) Run(ctx *CheckContext) *CheckResult {
 			errors = append(errors, fmt.Sprintf("%s: %d commits behind origin/main (EMERGENCY)", relPath, info.behindBy))
 		} else if info.behindBy > 10 {
 			warnings = append(warnings, fmt.Sprintf("%s: %d commits behind origin/main", relPath, info.behindBy))
+		} else {
+			continue
 		}
+		actions = append(actions, FixAction{Command: "git", Args: []string{"pull", "--rebase"}, Dir: relPath, Description: "update " + relPath})
 	}
 
 	if len(errors) > 0 {
@@ -443,7 +448,7 @@ func (c *CloneDivergenceCheck) Run(ctx *CheckContext) *CheckResult {
 			Status:  StatusError,
 			Message: fmt.Sprintf("%d clone(s) critically diverged", len(errors)),
 			Details: append(errors, warnings...),
-			FixHint: "Run 'git pull --rebase' in affected directories",
+			Actions: actions,
 		}
 	}
 
@@ -453,7 +458,7 @@ func (c *CloneDivergenceCheck) Run(ctx *CheckContext) *CheckResult {
 			Status:  StatusWarning,
 			Message: fmt.Sprintf("%d clone(s) behind origin/main", len(warnings)),
 			Details: warnings,
-			FixHint: "Run 'git pull --rebase' in affected directories",
+			Actions: actions,
 		}
 	}
 
@@ -534,11 +539,11 @@ func (c *CloneDivergenceCheck) isGitRepo(path string) bool {
 }
 
 // getCloneInfo gathers information about a clone.
-func (c *CloneDivergenceCheck) getCloneInfo(path string) (cloneInfo, error) {
+func (c *CloneDivergenceCheck) getCloneInfo(ctx context.Context, path string) (cloneInfo, error) {
 	info := cloneInfo{path: path}
 
 	// Get current branch
-	cmd := exec.Command("git", "branch", "--show-current")
+	cmd := exec.CommandContext(ctx, "git", "branch", "--show-current")
 	cmd.Dir = path
 	out, err := cmd.Output()
 	if err != nil {
@@ -547,7 +552,7 @@ func (c *CloneDivergenceCheck) getCloneInfo(path string) (cloneInfo, error) {
 	info.branch = strings.TrimSpace(string(out))
 
 	// Get HEAD SHA
-	cmd = exec.Command("git", "rev-parse", "HEAD")
+	cmd = exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
 	cmd.Dir = path
 	out, err = cmd.Output()
 	if err != nil {
@@ -556,12 +561,12 @@ func (c *CloneDivergenceCheck) getCloneInfo(path string) (cloneInfo, error) {
 	info.headSHA = strings.TrimSpace(string(out))
 
 	// Fetch to make sure we have latest refs (silent, ignore errors)
-	cmd = exec.Command("git", "fetch", "--quiet")
+	cmd = exec.CommandContext(ctx, "git", "fetch", "--quiet")
 	cmd.Dir = path
 	_ = cmd.Run()
 
 	// Count commits behind origin/main
-	cmd = exec.Command("git", "rev-list", "--count", "HEAD..origin/main")
+	cmd = exec.CommandContext(ctx, "git", "rev-list", "--count", "HEAD..origin/main")
 	cmd.Dir = path
 	out, err = cmd.Output()
 	if err != nil {
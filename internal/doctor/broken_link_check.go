@@ -0,0 +1,298 @@
+package doctor
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+// Kinds of broken link found by BrokenLinkCheck.
+const (
+	linkSymlink   = "symlink"    // Symlink whose target is gone
+	linkWorktree  = "worktree"   // Clone .git file pointing at a missing git dir
+	linkRegistry  = "registry"   // .repo.git/worktrees entry for a deleted worktree
+	linkLocalRepo = "local-repo" // rigs.json local_repo that no longer exists
+)
+
+// brokenLink is a link or config path whose target does not exist.
+type brokenLink struct {
+	kind   string
+	path   string // The link, registration, or config file
+	target string // What it points at
+	rig    string // Rig name for local-repo references
+	bare   string // Bare repo owning a registry entry
+}
+
+// brokenLinkSkipDirs are never walked: git internals are handled through
+// worktree links and registrations. Doctor backups, which hold copies of
+// removed links, are skipped too.
+var brokenLinkSkipDirs = map[string]bool{
+	".git":         true,
+	".repo.git":    true,
+	"node_modules": true,
+}
+
+// cloneManagedDirs are the gt-managed directories scanned inside a clone.
+// The rest of a clone is the project's source tree, whose symlinks are
+// the project's business.
+var cloneManagedDirs = []string{".cursor", ".beads", ".runtime"}
+
+// BrokenLinkCheck walks the town for symlinks whose targets are gone
+// (shared caches, hook script links), worktree links in both directions,
+// and config paths that no longer exist. Beads redirects are covered by
+// the rig beads checks.
+type BrokenLinkCheck struct {
+	FixableCheck
+	broken []brokenLink
+}
+
+// NewBrokenLinkCheck creates a new broken link check.
+func NewBrokenLinkCheck() *BrokenLinkCheck {
+	return &BrokenLinkCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "broken-links",
+				CheckDescription: "Detect broken symlinks and dangling paths",
+			},
+		},
+	}
+}
+
+// Run collects every broken link in the town.
+func (c *BrokenLinkCheck) Run(ctx *CheckContext) *CheckResult {
+	c.broken = findBrokenLinks(ctx.TownRoot)
+
+	if len(c.broken) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No broken symlinks or dangling paths",
+		}
+	}
+
+	var details []string
+	fixable, worktrees := 0, 0
+	for _, l := range c.broken {
+		path := c.relPath(ctx, l.path)
+		switch l.kind {
+		case linkSymlink:
+			details = append(details, fmt.Sprintf("%s -> %s (target missing)", path, l.target))
+		case linkWorktree:
+			details = append(details, fmt.Sprintf("%s: worktree git dir %s is missing", path, l.target))
+		case linkRegistry:
+			details = append(details, fmt.Sprintf("%s: registered worktree %s is missing", path, l.target))
+		case linkLocalRepo:
+			details = append(details, fmt.Sprintf("%s: rig %s local_repo %s is missing", path, l.rig, l.target))
+		}
+		if l.kind == linkWorktree {
+			worktrees++
+		} else {
+			fixable++
+		}
+	}
+
+	result := &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d broken link(s) or dangling path(s)", len(c.broken)),
+		Details: details,
+	}
+	if fixable > 0 {
+		result.Actions = []FixAction{doctorFix("remove broken symlinks, prune worktree registrations, clear missing local_repo paths", false)}
+	}
+	if worktrees > 0 {
+		result.FixHint = "Clones with a missing worktree git dir cannot be repaired in place; salvage their changes, then recreate them (e.g. gt polecat nuke)"
+	}
+	return result
+}
+
+// Fix removes what Run found, re-checking each target first so links
+// repaired in the meantime are left alone.
+func (c *BrokenLinkCheck) Fix(ctx *CheckContext) error {
+	pruned := make(map[string]bool)
+	clearRigs := make(map[string]bool)
+	var rigsPath string
+
+	for _, l := range c.broken {
+		switch l.kind {
+		case linkSymlink:
+			info, err := os.Lstat(l.path)
+			if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+				continue
+			}
+			if _, err := os.Stat(l.path); !os.IsNotExist(err) {
+				continue // Target came back
+			}
+			if err := ctx.Backup.Save(l.path); err != nil {
+				return err
+			}
+			if err := os.Remove(l.path); err != nil {
+				return fmt.Errorf("removing %s: %w", l.path, err)
+			}
+
+		case linkRegistry:
+			if pruned[l.bare] {
+				continue
+			}
+			pruned[l.bare] = true
+			if err := ctx.Backup.Save(filepath.Join(l.bare, "worktrees")); err != nil {
+				return err
+			}
+			cmd := exec.CommandContext(ctx, "git", "--git-dir", l.bare, "worktree", "prune")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("git worktree prune in %s: %s", l.bare, strings.TrimSpace(string(out)))
+			}
+
+		case linkLocalRepo:
+			rigsPath = l.path
+			clearRigs[l.rig] = true
+		}
+	}
+
+	if len(clearRigs) == 0 {
+		return nil
+	}
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for name := range clearRigs {
+		entry, ok := rigsConfig.Rigs[name]
+		if !ok || entry.LocalRepo == "" || pathExists(expandHome(entry.LocalRepo)) {
+			continue
+		}
+		entry.LocalRepo = ""
+		rigsConfig.Rigs[name] = entry
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if err := ctx.Backup.Save(rigsPath); err != nil {
+		return err
+	}
+	return config.SaveRigsConfig(rigsPath, rigsConfig)
+}
+
+func (c *BrokenLinkCheck) relPath(ctx *CheckContext, path string) string {
+	if rel, err := filepath.Rel(ctx.TownRoot, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// findBrokenLinks walks the town and its config for broken links.
+func findBrokenLinks(townRoot string) []brokenLink {
+	var broken []brokenLink
+	backups := filepath.Join(townRoot, BackupsDir)
+
+	var walk func(root string, isClone bool)
+	walk = func(root string, isClone bool) {
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.Type()&fs.ModeSymlink != 0 {
+				if _, err := os.Stat(path); os.IsNotExist(err) {
+					target, _ := os.Readlink(path)
+					broken = append(broken, brokenLink{kind: linkSymlink, path: path, target: target})
+				}
+				return nil
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if brokenLinkSkipDirs[d.Name()] || path == backups {
+				return filepath.SkipDir
+			}
+			if path == root || isClone {
+				return nil
+			}
+
+			// A clone: check its worktree link, then only gt's own dirs.
+			if info, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+				if !info.IsDir() {
+					if gitDir := resolveGitDir(path); gitDir != "" && !pathExists(gitDir) {
+						broken = append(broken, brokenLink{kind: linkWorktree, path: filepath.Join(path, ".git"), target: gitDir})
+					}
+				}
+				for _, sub := range cloneManagedDirs {
+					if pathExists(filepath.Join(path, sub)) {
+						walk(filepath.Join(path, sub), true)
+					}
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	}
+	walk(townRoot, false)
+
+	for _, rigPath := range findAllRigs(townRoot) {
+		broken = append(broken, findStaleWorktreeRegistrations(filepath.Join(rigPath, ".repo.git"))...)
+	}
+
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	if rigsConfig, err := config.LoadRigsConfig(rigsPath); err == nil {
+		names := make([]string, 0, len(rigsConfig.Rigs))
+		for name := range rigsConfig.Rigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			local := rigsConfig.Rigs[name].LocalRepo
+			if local != "" && !pathExists(expandHome(local)) {
+				broken = append(broken, brokenLink{kind: linkLocalRepo, path: rigsPath, target: local, rig: name})
+			}
+		}
+	}
+	return broken
+}
+
+// findStaleWorktreeRegistrations returns the worktree registrations in a
+// bare repo whose working trees were deleted without `git worktree remove`.
+// Locked registrations are skipped, as git worktree prune does.
+func findStaleWorktreeRegistrations(bare string) []brokenLink {
+	entries, err := os.ReadDir(filepath.Join(bare, "worktrees"))
+	if err != nil {
+		return nil
+	}
+	var broken []brokenLink
+	for _, e := range entries {
+		dir := filepath.Join(bare, "worktrees", e.Name())
+		if pathExists(filepath.Join(dir, "locked")) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "gitdir")) //nolint:gosec // G304: path is within the rig
+		if err != nil {
+			continue
+		}
+		dotGit := strings.TrimSpace(string(data))
+		if dotGit != "" && !pathExists(dotGit) {
+			broken = append(broken, brokenLink{kind: linkRegistry, path: dir, target: filepath.Dir(dotGit), bare: bare})
+		}
+	}
+	return broken
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// expandHome expands a leading ~/ to the user's home directory.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
@@ -0,0 +1,118 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+func TestBrokenLinkCheck(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	mkdir := func(dir string) {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	symlink := func(target, link string) {
+		t.Helper()
+		mkdir(filepath.Dir(link))
+		if err := os.Symlink(target, link); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Broken: a shared cache link in the town and a hook script link in a clone.
+	symlink(filepath.Join(townRoot, "cache", "gone"), filepath.Join(townRoot, "deacon", "cache"))
+	crew := filepath.Join(rigPath, "crew", "max")
+	mkdir(filepath.Join(crew, ".git"))
+	symlink("../scripts/missing.sh", filepath.Join(crew, ".cursor", "hooks", "session-start.sh"))
+	// Ignored: a project's own broken link and a working link.
+	symlink("nowhere", filepath.Join(crew, "src", "project-link"))
+	symlink(rigPath, filepath.Join(townRoot, "deacon", "rig"))
+
+	// A polecat whose worktree git dir is gone, and a registration for a
+	// deleted worktree.
+	bare := filepath.Join(rigPath, ".repo.git")
+	if out, err := exec.Command("git", "init", "--bare", "-q", bare).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %s", out)
+	}
+	polecat := filepath.Join(rigPath, "polecats", "Toast")
+	mkdir(polecat)
+	if err := os.WriteFile(filepath.Join(polecat, ".git"), []byte("gitdir: "+filepath.Join(bare, "worktrees", "Toast")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	registration := filepath.Join(bare, "worktrees", "Nux")
+	mkdir(registration)
+	if err := os.WriteFile(filepath.Join(registration, "gitdir"), []byte(filepath.Join(rigPath, "polecats", "Nux", ".git")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	mkdir(filepath.Dir(rigsPath))
+	if err := config.SaveRigsConfig(rigsPath, &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{
+		"gastown": {GitURL: "https://example.com/gastown.git", LocalRepo: filepath.Join(townRoot, "src", "gone")},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewBrokenLinkCheck()
+	ctx := &CheckContext{TownRoot: townRoot, Backup: NewFixBackup(townRoot, time.Now())}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("status = %v, want warning", result.Status)
+	}
+	want := []string{
+		"deacon/cache -> ",
+		"crew/max/.cursor/hooks/session-start.sh -> ../scripts/missing.sh",
+		"polecats/Toast/.git: worktree git dir",
+		"worktrees/Nux: registered worktree",
+		"rig gastown local_repo",
+	}
+	if len(result.Details) != len(want) {
+		t.Fatalf("details = %v, want %d entries", result.Details, len(want))
+	}
+	joined := strings.Join(result.Details, "\n")
+	for _, w := range want {
+		if !strings.Contains(joined, w) {
+			t.Errorf("details missing %q:\n%s", w, joined)
+		}
+	}
+	if result.FixHint == "" {
+		t.Error("want a hint for the unrepairable worktree")
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(townRoot, "deacon", "cache")); !os.IsNotExist(err) {
+		t.Error("broken cache link should be removed")
+	}
+	if _, err := os.Lstat(filepath.Join(crew, "src", "project-link")); err != nil {
+		t.Error("links in a project's source tree must be left alone")
+	}
+	if pathExists(registration) {
+		t.Error("stale worktree registration should be pruned")
+	}
+	rigs, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rigs.Rigs["gastown"].LocalRepo != "" || rigs.Rigs["gastown"].GitURL == "" {
+		t.Errorf("rig entry = %+v, want local_repo cleared and the rest kept", rigs.Rigs["gastown"])
+	}
+
+	result = check.Run(ctx)
+	if len(result.Details) != 1 || len(result.Actions) != 0 {
+		t.Errorf("after fix: details = %v, actions = %v; want only the polecat worktree", result.Details, result.Actions)
+	}
+}
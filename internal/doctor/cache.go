@@ -0,0 +1,216 @@
+package doctor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// CacheFile is the doctor result cache file name within the town .runtime directory.
+const CacheFile = "doctor-cache.json"
+
+// InputFingerprinter is implemented by checks whose result depends only on
+// files on disk. The returned paths (files or directories) are fingerprinted
+// by size and modification time; if none have changed since the last run,
+// --changed-only reuses the cached result instead of running the check.
+// Returning nil marks this run as not cacheable.
+//
+// Checks that inspect live state (tmux, processes, beads) must not implement
+// this interface - their results can change without any file changing.
+type InputFingerprinter interface {
+	Inputs(ctx *CheckContext) []string
+}
+
+// CachedResult is a check result stored with the fingerprint of its inputs.
+type CachedResult struct {
+	Fingerprint string      `json:"fingerprint"`
+	Status      CheckStatus `json:"status"`
+	Message     string      `json:"message"`
+	Details     []string    `json:"details,omitempty"`
+	FixHint     string      `json:"fix_hint,omitempty"`
+	Actions     []FixAction `json:"actions,omitempty"`
+	RanAt       time.Time   `json:"ran_at"`
+}
+
+// ResultCache stores the last result of each fingerprintable check.
+// The cache is discarded when the gt version changes, since check logic
+// may have changed even if inputs have not.
+type ResultCache struct {
+	Version string                   `json:"version"`
+	Results map[string]*CachedResult `json:"results"`
+}
+
+// CachePath returns the doctor cache path for a town.
+func CachePath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), CacheFile)
+}
+
+// LoadResultCache loads the doctor cache for a town. A missing, corrupt, or
+// version-mismatched cache yields an empty cache rather than an error.
+func LoadResultCache(townRoot, version string) *ResultCache {
+	empty := &ResultCache{Version: version, Results: make(map[string]*CachedResult)}
+
+	data, err := os.ReadFile(CachePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return empty
+	}
+
+	var cache ResultCache
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Version != version || cache.Results == nil {
+		return empty
+	}
+	return &cache
+}
+
+// Save writes the cache for a town.
+func (c *ResultCache) Save(townRoot string) error {
+	path := CachePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	return util.AtomicWriteJSON(path, c)
+}
+
+// lookup returns the cached result for key if its fingerprint matches.
+func (c *ResultCache) lookup(key, fingerprint string) *CachedResult {
+	if c == nil || fingerprint == "" {
+		return nil
+	}
+	if cached, ok := c.Results[key]; ok && cached.Fingerprint == fingerprint {
+		return cached
+	}
+	return nil
+}
+
+// store records a result under key with the given fingerprint.
+func (c *ResultCache) store(key, fingerprint string, result *CheckResult) {
+	if c == nil || fingerprint == "" || result.Status == StatusTimeout {
+		return
+	}
+	c.Results[key] = &CachedResult{
+		Fingerprint: fingerprint,
+		Status:      result.Status,
+		Message:     result.Message,
+		Details:     result.Details,
+		FixHint:     result.FixHint,
+		Actions:     result.Actions,
+		RanAt:       time.Now(),
+	}
+}
+
+// cacheKey scopes a check's cache entry to the rig it ran against.
+func cacheKey(check Check, ctx *CheckContext) string {
+	if ctx.RigName != "" {
+		return check.Name() + "@" + ctx.RigName
+	}
+	return check.Name()
+}
+
+// checkFingerprint returns the input fingerprint for a check, or "" if the
+// check does not declare its inputs or has none this run.
+func checkFingerprint(check Check, ctx *CheckContext) string {
+	fp, ok := check.(InputFingerprinter)
+	if !ok {
+		return ""
+	}
+	inputs := fp.Inputs(ctx)
+	if inputs == nil {
+		return ""
+	}
+	return fingerprintPaths(inputs)
+}
+
+// fingerprintPaths hashes the size and mtime of each path. Missing paths are
+// included as absent so that creating a file changes the fingerprint.
+func fingerprintPaths(paths []string) string {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	for _, p := range sorted {
+		info, err := os.Stat(p)
+		if err != nil {
+			_, _ = fmt.Fprintf(h, "%s\x00absent\n", p)
+			continue
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\n", p, info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// treeInputs returns root and every file and directory beneath it, so that
+// editing, adding, or removing any of them changes the fingerprint. A
+// missing root yields just root, fingerprinted as absent.
+func treeInputs(root string) []string {
+	paths := []string{root}
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && path != root {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths
+}
+
+// agentWorkspaceInputs returns the inputs of checks that compare agent
+// workspaces with their composed templates: the town's rig directories, town
+// and rig settings (with machine profiles), template overrides and rule
+// sources, and the generated files in each workspace.
+func agentWorkspaceInputs(townRoot string) []string {
+	inputs := []string{townRoot, config.TownSettingsPath(townRoot), filepath.Join(townRoot, workspace.PrimaryMarker)}
+	inputs = append(inputs, treeInputs(config.MachineProfilesDir(townRoot))...)
+	inputs = append(inputs, treeInputs(templates.OverrideDir(townRoot))...)
+
+	// A directory becomes a rig when its crew/, polecats/, witness/, or
+	// refinery/ is created, which changes the directory's mtime
+	if entries, err := os.ReadDir(townRoot); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+				inputs = append(inputs, filepath.Join(townRoot, entry.Name()))
+			}
+		}
+	}
+
+	var rigs []string
+	for _, rigPath := range findAllRigs(townRoot) {
+		rigs = append(rigs, filepath.Base(rigPath))
+		repo := filepath.Join(rigPath, "mayor", "rig")
+		inputs = append(inputs, config.RigSettingsPath(rigPath), filepath.Join(repo, "AGENTS.md"))
+		inputs = append(inputs, treeInputs(filepath.Join(rigPath, "settings", "rules"))...)
+		inputs = append(inputs, treeInputs(filepath.Join(repo, filepath.FromSlash(cursor.RepoRulesDir)))...)
+	}
+	for _, t := range daemon.TemplateTargets(townRoot, rigs) {
+		inputs = append(inputs, t.WorkDir, filepath.Join(t.WorkDir, "AGENTS.md"))
+		inputs = append(inputs, treeInputs(filepath.Join(t.WorkDir, ".cursor"))...)
+		inputs = append(inputs, treeInputs(filepath.Join(t.WorkDir, ".codex"))...)
+	}
+	return inputs
+}
+
+// resultFromCache rebuilds a CheckResult from a cached entry.
+func resultFromCache(name string, cached *CachedResult) *CheckResult {
+	return &CheckResult{
+		Name:    name,
+		Status:  cached.Status,
+		Message: cached.Message + " (cached)",
+		Details: cached.Details,
+		FixHint: cached.FixHint,
+		Actions: cached.Actions,
+		Cached:  true,
+	}
+}
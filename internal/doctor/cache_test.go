@@ -0,0 +1,193 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fileCheck is a fingerprintable mock that counts how often it runs.
+type fileCheck struct {
+	BaseCheck
+	path string
+	runs int
+}
+
+func (c *fileCheck) Run(ctx *CheckContext) *CheckResult {
+	c.runs++
+	status := StatusOK
+	if _, err := os.Stat(c.path); err != nil {
+		status = StatusWarning
+	}
+	return &CheckResult{Name: c.CheckName, Status: status, Message: "file check"}
+}
+
+func (c *fileCheck) Inputs(ctx *CheckContext) []string {
+	return []string{c.path}
+}
+
+func TestChangedOnlySkipsUnchangedChecks(t *testing.T) {
+	townRoot := t.TempDir()
+	input := filepath.Join(townRoot, "input.json")
+	if err := os.WriteFile(input, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := &fileCheck{BaseCheck: BaseCheck{CheckName: "file-check"}, path: input}
+	live := newMockCheck("live-check", StatusOK)
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	run := func(changedOnly bool) *Report {
+		cache := LoadResultCache(townRoot, "test")
+		d := NewDoctor()
+		d.Register(check)
+		d.Register(live)
+		d.SetCache(cache, changedOnly)
+		report := d.Run(ctx)
+		if err := cache.Save(townRoot); err != nil {
+			t.Fatalf("saving cache: %v", err)
+		}
+		return report
+	}
+
+	run(false)
+	if check.runs != 1 {
+		t.Fatalf("first run: runs = %d, want 1", check.runs)
+	}
+
+	report := run(true)
+	if check.runs != 1 {
+		t.Errorf("unchanged input was re-run (runs = %d)", check.runs)
+	}
+	if report.Summary.Cached != 1 {
+		t.Errorf("Summary.Cached = %d, want 1 (live checks are never cached)", report.Summary.Cached)
+	}
+
+	// Changing the input invalidates the cached result.
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(input, future, future); err != nil {
+		t.Fatal(err)
+	}
+	run(true)
+	if check.runs != 2 {
+		t.Errorf("changed input was not re-run (runs = %d)", check.runs)
+	}
+
+	// Removing the input also changes the fingerprint.
+	if err := os.Remove(input); err != nil {
+		t.Fatal(err)
+	}
+	report = run(true)
+	if check.runs != 3 || report.Summary.Warnings != 1 {
+		t.Errorf("removed input: runs = %d, warnings = %d", check.runs, report.Summary.Warnings)
+	}
+}
+
+func TestLoadResultCacheVersionMismatch(t *testing.T) {
+	townRoot := t.TempDir()
+
+	cache := LoadResultCache(townRoot, "v1")
+	cache.store("x", "fp", &CheckResult{Status: StatusOK})
+	if err := cache.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := LoadResultCache(townRoot, "v1").lookup("x", "fp"); got == nil {
+		t.Error("expected cached result for same version")
+	}
+	if got := LoadResultCache(townRoot, "v2").lookup("x", "fp"); got != nil {
+		t.Error("expected cache to be discarded after version change")
+	}
+}
+
+func TestFixIgnoresCachedFailures(t *testing.T) {
+	townRoot := t.TempDir()
+	check := &fileCheck{BaseCheck: BaseCheck{CheckName: "file-check"}, path: filepath.Join(townRoot, "missing")}
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	cache := LoadResultCache(townRoot, "test")
+	d := NewDoctor()
+	d.Register(check)
+	d.SetCache(cache, true)
+
+	d.Run(ctx)
+	d.Fix(ctx)
+	if check.runs != 2 {
+		t.Errorf("failing cached result should re-run under --fix (runs = %d)", check.runs)
+	}
+}
+
+func TestChangedOnlySkipsUnchangedFileChecks(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, dir := range []string{".runtime", "mayor/.cursor", "myrig/crew/alice", "myrig/polecats", "myrig/witness"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hooks := filepath.Join(townRoot, "mayor", ".cursor", "hooks.json")
+	if err := os.WriteFile(hooks, []byte(`{"version": 1, "hooks": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := []Check{
+		NewCursorSettingsCheck(),
+		NewTemplateDriftCheck(),
+		NewMCPConfigCheck(),
+		NewRulesCheck(),
+		NewCodexSettingsCheck(),
+		NewContextBudgetCheck(),
+		NewCommandsCheck(),
+	}
+	ctx := &CheckContext{TownRoot: townRoot}
+	run := func() *Report {
+		cache := LoadResultCache(townRoot, "test")
+		d := NewDoctor()
+		d.RegisterAll(checks...)
+		d.SetCache(cache, true)
+		report := d.Run(ctx)
+		if err := cache.Save(townRoot); err != nil {
+			t.Fatalf("saving cache: %v", err)
+		}
+		return report
+	}
+	cached := func(report *Report) map[string]bool {
+		m := make(map[string]bool)
+		for _, r := range report.Checks {
+			m[r.Name] = r.Cached
+		}
+		return m
+	}
+
+	run()
+	report := run()
+	if report.Summary.Cached != len(checks) {
+		t.Errorf("Summary.Cached = %d, want %d: %v", report.Summary.Cached, len(checks), cached(report))
+	}
+
+	// Editing an agent's hooks.json re-runs the checks that read it.
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(hooks, future, future); err != nil {
+		t.Fatal(err)
+	}
+	got := cached(run())
+	if got["cursor-settings"] || got["template-drift"] {
+		t.Errorf("checks reading hooks.json were not re-run: %v", got)
+	}
+	if !got["commands-provisioned"] {
+		t.Errorf("commands-provisioned does not read hooks.json and should stay cached: %v", got)
+	}
+
+	// A hooks.json in a wrong location is never cached: its git status is live.
+	wrong := filepath.Join(townRoot, "myrig", "crew", "alice", ".cursor", "hooks.json")
+	if err := os.MkdirAll(filepath.Dir(wrong), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(wrong, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run()
+	if cached(run())["cursor-settings"] {
+		t.Error("cursor-settings was cached with a wrong-location file present")
+	}
+}
@@ -0,0 +1,115 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/codex"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+)
+
+// CodexSettingsCheck verifies the Codex settings of agent workspaces set up
+// for Codex CLI: AGENTS.md holds the composed rules for the role, and
+// .codex/config.toml runs the current Gas Town notify program. Workspaces
+// without Codex settings are skipped.
+type CodexSettingsCheck struct {
+	FixableCheck
+	stale []daemon.TemplateTarget
+}
+
+// NewCodexSettingsCheck creates a new Codex settings check.
+func NewCodexSettingsCheck() *CodexSettingsCheck {
+	return &CodexSettingsCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "codex-settings",
+				CheckDescription: "Check Codex agents' AGENTS.md and notify program are current",
+			},
+		},
+	}
+}
+
+// Run compares each Codex workspace's settings with those generated for
+// its role.
+func (c *CodexSettingsCheck) Run(ctx *CheckContext) *CheckResult {
+	c.stale = nil
+
+	var rigs []string
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		rigs = append(rigs, filepath.Base(rigPath))
+	}
+
+	var details []string
+	checked := 0
+	for _, t := range daemon.TemplateTargets(ctx.TownRoot, rigs) {
+		if !codex.Installed(t.WorkDir) {
+			continue
+		}
+		checked++
+		problems, err := codex.CheckSettings(t.WorkDir, t.Role)
+		if err != nil {
+			details = append(details, fmt.Sprintf("%s: %v", t.Agent, err))
+			continue
+		}
+		if len(problems) > 0 {
+			c.stale = append(c.stale, t)
+			details = append(details, fmt.Sprintf("%s: %s", t.Agent, strings.Join(problems, ", ")))
+		}
+		if notify := codex.NotifyConflict(t.WorkDir); notify != "" {
+			details = append(details, fmt.Sprintf("%s: config.toml runs %q instead of the Gas Town notify program; costs and mail are not handled after turns", t.Agent, notify))
+		}
+	}
+
+	if len(details) == 0 {
+		msg := "No Codex workspaces"
+		if checked > 0 {
+			msg = fmt.Sprintf("%d Codex workspace(s) current", checked)
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: msg,
+		}
+	}
+	if len(c.stale) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Codex settings need manual attention",
+			Details: details,
+			FixHint: "Remove notify from .codex/config.toml, or run .codex/hooks/gastown-notify.sh from your own program",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d Codex workspace(s) have outdated settings", len(c.stale)),
+		Details: details,
+		Actions: []FixAction{doctorFix("regenerate Codex settings", false)},
+	}
+}
+
+// Inputs returns the paths this check depends on (see InputFingerprinter).
+func (c *CodexSettingsCheck) Inputs(ctx *CheckContext) []string {
+	return agentWorkspaceInputs(ctx.TownRoot)
+}
+
+// Fix regenerates the outdated workspaces' Codex settings. An edited
+// AGENTS.md and a notify program the user configured are kept.
+func (c *CodexSettingsCheck) Fix(ctx *CheckContext) error {
+	for i, target := range c.stale {
+		if err := ctx.Step(i, len(c.stale), target.Agent); err != nil {
+			return err
+		}
+		for _, path := range []string{filepath.Join(target.WorkDir, codex.InstructionsFile), filepath.Join(target.WorkDir, ".codex")} {
+			if err := ctx.Backup.Save(path); err != nil {
+				return err
+			}
+		}
+		if err := codex.EnsureSettingsForRole(target.WorkDir, target.Role); err != nil {
+			return fmt.Errorf("regenerating Codex settings for %s: %w", target.Agent, err)
+		}
+	}
+	return ctx.Step(len(c.stale), len(c.stale), "")
+}
@@ -0,0 +1,50 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/codex"
+)
+
+func TestCodexSettingsCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	check := NewCodexSettingsCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("no Codex workspaces: status = %v, details = %v", result.Status, result.Details)
+	}
+
+	mayorDir := filepath.Join(townRoot, "mayor")
+	if err := codex.EnsureSettingsForRole(mayorDir, "mayor"); err != nil {
+		t.Fatal(err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("fresh install: status = %v, details = %v", result.Status, result.Details)
+	}
+
+	if err := os.Remove(filepath.Join(mayorDir, codex.InstructionsFile)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mayorDir, ".codex", "config.toml"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || len(result.Details) != 1 {
+		t.Fatalf("missing AGENTS.md and notify: status = %v, details = %v; want one outdated workspace", result.Status, result.Details)
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after Fix: status = %v, details = %v", result.Status, result.Details)
+	}
+
+	if err := os.WriteFile(filepath.Join(mayorDir, ".codex", "config.toml"), []byte("notify = [\"say\", \"done\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := check.Run(ctx); result.Status != StatusWarning || len(result.Actions) != 0 {
+		t.Errorf("user notify program: status = %v, actions = %v; want a warning without a fix", result.Status, result.Actions)
+	}
+}
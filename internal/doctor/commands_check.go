@@ -2,6 +2,7 @@ package doctor
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/cursorworkshop/cursor-gastown/internal/templates"
@@ -61,10 +62,15 @@ func (c *CommandsCheck) Run(ctx *CheckContext) *CheckResult {
 			fmt.Sprintf("Expected at: %s/.cursor/commands/", ctx.TownRoot),
 			"All agents inherit town-level commands via directory traversal",
 		},
-		FixHint: "Run 'gt doctor --fix' to provision missing commands",
+		Actions: []FixAction{doctorFix("provision missing commands", false)},
 	}
 }
 
+// Inputs returns the paths this check depends on (see InputFingerprinter).
+func (c *CommandsCheck) Inputs(ctx *CheckContext) []string {
+	return treeInputs(filepath.Join(ctx.TownRoot, ".cursor", "commands"))
+}
+
 // Fix provisions missing slash commands at town level.
 func (c *CommandsCheck) Fix(ctx *CheckContext) error {
 	if len(c.missingCommands) == 0 {
@@ -79,10 +79,16 @@ func (c *SettingsCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("%d rig(s) missing settings/ directory", len(missing)),
 		Details: details,
-		FixHint: "Run 'gt doctor --fix' to create missing directories",
+		Actions: []FixAction{doctorFix("create missing directories", false)},
 	}
 }
 
+// Inputs returns the paths this check depends on (see InputFingerprinter).
+// Rig directory mtimes change when settings/ is created or removed.
+func (c *SettingsCheck) Inputs(ctx *CheckContext) []string {
+	return append([]string{ctx.TownRoot}, c.findRigs(ctx.TownRoot)...)
+}
+
 // Fix creates missing settings/ directories.
 func (c *SettingsCheck) Fix(ctx *CheckContext) error {
 	for _, path := range c.missingSettings {
@@ -153,6 +159,23 @@ func (c *RuntimeGitignoreCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// Inputs returns the paths this check depends on (see InputFingerprinter).
+func (c *RuntimeGitignoreCheck) Inputs(ctx *CheckContext) []string {
+	inputs := []string{ctx.TownRoot, filepath.Join(ctx.TownRoot, ".gitignore")}
+	for _, rig := range c.findRigs(ctx.TownRoot) {
+		crewPath := filepath.Join(rig, "crew")
+		inputs = append(inputs, rig, crewPath)
+		if crewEntries, err := os.ReadDir(crewPath); err == nil {
+			for _, crew := range crewEntries {
+				if crew.IsDir() && !strings.HasPrefix(crew.Name(), ".") {
+					inputs = append(inputs, filepath.Join(crewPath, crew.Name(), ".gitignore"))
+				}
+			}
+		}
+	}
+	return inputs
+}
+
 // containsPattern checks if a gitignore file contains a pattern.
 func (c *RuntimeGitignoreCheck) containsPattern(gitignorePath, pattern string) bool {
 	file, err := os.Open(gitignorePath)
@@ -243,13 +266,16 @@ func (c *LegacyGastownCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("%d legacy .gastown/ directory(ies) found", len(found)),
 		Details: found,
-		FixHint: "Run 'gt doctor --fix' to remove after verifying migration is complete",
+		Actions: []FixAction{doctorFix("remove after verifying migration is complete", true)},
 	}
 }
 
 // Fix removes legacy .gastown/ directories.
 func (c *LegacyGastownCheck) Fix(ctx *CheckContext) error {
 	for _, dir := range c.legacyDirs {
+		if err := ctx.Backup.Save(dir); err != nil {
+			return err
+		}
 		if err := os.RemoveAll(dir); err != nil {
 			return fmt.Errorf("failed to remove %s: %w", dir, err)
 		}
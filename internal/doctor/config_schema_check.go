@@ -0,0 +1,132 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+)
+
+// ConfigSchemaCheck validates the town and rig config files against schemas
+// generated from the config types. The loaders silently ignore unknown keys
+// (so a typo like "merge_qeue" just doesn't take effect) and report type
+// errors without a location; this check names the file, line, and key.
+type ConfigSchemaCheck struct {
+	BaseCheck
+}
+
+// NewConfigSchemaCheck creates a new config schema check.
+func NewConfigSchemaCheck() *ConfigSchemaCheck {
+	return &ConfigSchemaCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "config-schema",
+			CheckDescription: "Validate town and rig config files against their schemas",
+		},
+	}
+}
+
+// schemaFile is a config file and the schema it must match.
+type schemaFile struct {
+	path   string
+	schema *config.Schema
+}
+
+// files returns the config files to validate. Town identity is always
+// checked; settings and rig files only when present.
+func (c *ConfigSchemaCheck) files(townRoot string) []schemaFile {
+	files := []schemaFile{
+		{constants.MayorTownPath(townRoot), config.TownConfigSchema()},
+		{config.TownSettingsPath(townRoot), config.TownSettingsSchema()},
+	}
+	for _, rigPath := range findAllRigs(townRoot) {
+		files = append(files,
+			schemaFile{filepath.Join(rigPath, "config.json"), config.RigConfigSchema()},
+			schemaFile{config.RigSettingsPath(rigPath), config.RigSettingsSchema()},
+		)
+	}
+	return files
+}
+
+// Inputs returns the files this check depends on (see InputFingerprinter).
+func (c *ConfigSchemaCheck) Inputs(ctx *CheckContext) []string {
+	var paths []string
+	for _, f := range c.files(ctx.TownRoot) {
+		paths = append(paths, f.path)
+	}
+	return paths
+}
+
+// Run validates each config file.
+func (c *ConfigSchemaCheck) Run(ctx *CheckContext) *CheckResult {
+	var details []string
+	var errorFiles, warnFiles, checked int
+
+	for i, f := range c.files(ctx.TownRoot) {
+		rel, _ := filepath.Rel(ctx.TownRoot, f.path)
+		data, err := os.ReadFile(f.path) //nolint:gosec // G304: path is within the town root
+		if err != nil {
+			if os.IsNotExist(err) && i > 0 {
+				continue // optional file; town-config-exists covers mayor/town.json
+			}
+			details = append(details, fmt.Sprintf("%s: %v", rel, err))
+			errorFiles++
+			continue
+		}
+		checked++
+
+		violations, err := f.schema.Validate(data)
+		if err != nil {
+			details = append(details, fmt.Sprintf("%s: invalid JSON: %v", rel, err))
+			errorFiles++
+			continue
+		}
+		hasError := false
+		for _, v := range violations {
+			details = append(details, fmt.Sprintf("%s:%d:%d: %s", rel, v.Line, v.Column, violationText(v)))
+			if v.Kind != config.ViolationUnknownKey {
+				hasError = true
+			}
+		}
+		switch {
+		case hasError:
+			errorFiles++
+		case len(violations) > 0:
+			warnFiles++
+		}
+	}
+
+	if errorFiles == 0 && warnFiles == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%d config file(s) match their schemas", checked),
+		}
+	}
+
+	if errorFiles > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d config file(s) have schema errors", errorFiles),
+			Details: details,
+			FixHint: "Edit the listed keys; wrongly typed or missing fields stop config from loading",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d config file(s) have unknown keys", warnFiles),
+		Details: details,
+		FixHint: "Remove or correct the listed keys; unknown keys are ignored",
+	}
+}
+
+// violationText formats a violation without its position.
+func violationText(v config.SchemaViolation) string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
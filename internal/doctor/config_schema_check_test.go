@@ -0,0 +1,50 @@
+package doctor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigSchemaCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	ctx := &CheckContext{TownRoot: townRoot}
+	check := NewConfigSchemaCheck()
+
+	mustWrite(t, filepath.Join(townRoot, "mayor", "town.json"), `{"type":"town","version":2,"name":"hq"}`)
+	mustWrite(t, filepath.Join(townRoot, "gastown", "crew", ".keep"), "")
+	mustWrite(t, filepath.Join(townRoot, "gastown", "config.json"), `{"type":"rig","version":1,"name":"gastown","git_url":"x"}`)
+
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("valid configs: Status = %v, want OK (%v)", result.Status, result.Details)
+	}
+
+	// Unknown keys are warnings: loaders ignore them.
+	settingsPath := filepath.Join(townRoot, "gastown", "settings", "config.json")
+	mustWrite(t, settingsPath, "{\n  \"type\": \"rig-settings\",\n  \"version\": 1,\n  \"agnet\": \"codex\"\n}")
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("unknown key: Status = %v, want warning (%v)", result.Status, result.Details)
+	}
+	want := `gastown/settings/config.json:4:3: agnet: unknown key (did you mean "agent"?)`
+	if len(result.Details) != 1 || result.Details[0] != want {
+		t.Errorf("Details = %v, want [%s]", result.Details, want)
+	}
+
+	// Bad types are errors.
+	mustWrite(t, settingsPath, `{"type":"rig-settings","version":1,"env":{"FOO":1}}`)
+	result = check.Run(ctx)
+	if result.Status != StatusError {
+		t.Fatalf("bad type: Status = %v, want error (%v)", result.Status, result.Details)
+	}
+	if len(result.Details) != 1 || !strings.Contains(result.Details[0], "env.FOO: expected string, got integer") {
+		t.Errorf("Details = %v", result.Details)
+	}
+
+	// Invalid JSON is an error with the line of the syntax error.
+	mustWrite(t, settingsPath, "{\n  \"type\": \"rig-settings\",\n}")
+	result = check.Run(ctx)
+	if result.Status != StatusError || len(result.Details) != 1 || !strings.Contains(result.Details[0], "invalid JSON: line 2:") {
+		t.Errorf("invalid JSON: Status = %v, Details = %v", result.Status, result.Details)
+	}
+}
@@ -0,0 +1,181 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+)
+
+// contextSource is one file or generated block in an agent's instructions.
+type contextSource struct {
+	name   string
+	tokens int
+}
+
+// ContextBudgetCheck estimates how many tokens of instructions each agent
+// carries in every prompt (rules files, the repo's AGENTS.md, and the role
+// context from gt prime) and compares them with per-role budgets from the
+// town settings' context_budgets.
+type ContextBudgetCheck struct {
+	BaseCheck
+}
+
+// NewContextBudgetCheck creates a new context budget check.
+func NewContextBudgetCheck() *ContextBudgetCheck {
+	return &ContextBudgetCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "context-budget",
+			CheckDescription: "Check agent rules and context stay within per-role token budgets",
+		},
+	}
+}
+
+// Run estimates each agent's instruction size.
+func (c *ContextBudgetCheck) Run(ctx *CheckContext) *CheckResult {
+	settings, _ := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot))
+	var budgets *config.ContextBudgetsConfig
+	if settings != nil {
+		budgets = settings.ContextBudgets
+	}
+	window := budgets.Window()
+	tmpl, _ := templates.NewForTown(ctx.TownRoot) // Role context is skipped if templates fail to load
+
+	var rigs []string
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		rigs = append(rigs, filepath.Base(rigPath))
+	}
+
+	var details []string
+	checked, largest, largestAgent := 0, 0, ""
+	for _, t := range daemon.TemplateTargets(ctx.TownRoot, rigs) {
+		if _, err := os.Stat(t.WorkDir); err != nil {
+			continue
+		}
+		role, rig := contextRole(t.Agent)
+		sources := agentContextSources(ctx.TownRoot, t.WorkDir, role, rig, tmpl)
+		total := 0
+		for _, s := range sources {
+			total += s.tokens
+		}
+		checked++
+		if total > largest {
+			largest, largestAgent = total, t.Agent
+		}
+
+		budget := budgets.Budget(role)
+		if total <= budget {
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s: ~%s tokens, budget %s (%d%% of %s window): %s",
+			t.Agent, formatTokens(total), formatTokens(budget), total*100/window, formatTokens(window), describeContextSources(sources)))
+	}
+
+	if len(details) == 0 {
+		msg := "No agent workspaces to check"
+		if checked > 0 {
+			msg = fmt.Sprintf("%d agent(s) within instruction token budgets (largest: %s ~%s)", checked, largestAgent, formatTokens(largest))
+		}
+		return &CheckResult{Name: c.Name(), Status: StatusOK, Message: msg}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d agent(s) over their instruction token budget", len(details)),
+		Details: details,
+		FixHint: "Trim the largest sources (custom rules, AGENTS.md), or raise the role's budget in settings/config.json context_budgets",
+	}
+}
+
+// Inputs returns the paths this check depends on (see InputFingerprinter).
+func (c *ContextBudgetCheck) Inputs(ctx *CheckContext) []string {
+	return agentWorkspaceInputs(ctx.TownRoot)
+}
+
+// contextRole maps a template target's agent name to its role and rig.
+func contextRole(agent string) (role, rig string) {
+	rig, name, ok := strings.Cut(agent, "/")
+	if !ok {
+		return agent, "" // mayor, deacon
+	}
+	if name == "polecats" {
+		return "polecat", rig
+	}
+	return name, rig
+}
+
+// agentContextSources estimates the instructions an agent working in
+// workDir sees: every rules file in .cursor/rules, AGENTS.md (for rig
+// agents, the repo's copy in the mayor clone), and the rendered role
+// context.
+func agentContextSources(townRoot, workDir, role, rig string, tmpl *templates.Templates) []contextSource {
+	var sources []contextSource
+	add := func(name string, data []byte) {
+		if len(data) > 0 {
+			sources = append(sources, contextSource{name: name, tokens: estimateTokens(data)})
+		}
+	}
+
+	rulesDir := filepath.Join(workDir, ".cursor", "rules")
+	if entries, err := os.ReadDir(rulesDir); err == nil {
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".mdc") {
+				continue
+			}
+			data, _ := os.ReadFile(filepath.Join(rulesDir, e.Name())) //nolint:gosec // G304: path is within the agent workspace
+			add(e.Name(), data)
+		}
+	}
+
+	agentsMD := filepath.Join(workDir, "AGENTS.md")
+	if rig != "" {
+		agentsMD = filepath.Join(townRoot, rig, "mayor", "rig", "AGENTS.md")
+	}
+	data, _ := os.ReadFile(agentsMD) //nolint:gosec // G304: path is within the town
+	add("AGENTS.md", data)
+
+	if tmpl != nil {
+		rendered, err := tmpl.RenderRole(role, templates.RoleData{Role: role, RigName: rig, TownRoot: townRoot, WorkDir: workDir})
+		if err == nil {
+			add("role context", []byte(rendered))
+		}
+	}
+
+	sort.SliceStable(sources, func(i, j int) bool { return sources[i].tokens > sources[j].tokens })
+	return sources
+}
+
+// estimateTokens approximates the token count of text at four bytes per
+// token, which is close for English prose and markdown.
+func estimateTokens(data []byte) int {
+	return (len(data) + 3) / 4
+}
+
+// formatTokens renders a token count compactly, e.g. "950" or "12.4k".
+func formatTokens(n int) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d", n)
+	}
+	return fmt.Sprintf("%.1fk", float64(n)/1000)
+}
+
+// describeContextSources lists the largest sources, e.g.
+// "custom.mdc ~9.0k, role context ~3.2k, AGENTS.md ~1.1k".
+func describeContextSources(sources []contextSource) string {
+	const shown = 3
+	parts := make([]string, 0, shown+1)
+	for i, s := range sources {
+		if i == shown {
+			parts = append(parts, fmt.Sprintf("+%d more", len(sources)-shown))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s ~%s", s.name, formatTokens(s.tokens)))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,55 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+func TestContextBudgetCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	rulesDir := filepath.Join(townRoot, "gastown", "witness", ".cursor", "rules")
+	if err := os.MkdirAll(rulesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rulesDir, "custom.mdc"), []byte(strings.Repeat("word ", 8000)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewContextBudgetCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("default budget: status = %v, details = %v", result.Status, result.Details)
+	}
+
+	settings := config.NewTownSettings()
+	settings.ContextBudgets = &config.ContextBudgetsConfig{Roles: map[string]int{"witness": 5000}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("over witness budget: status = %v, want warning", result.Status)
+	}
+	if len(result.Details) != 1 || !strings.HasPrefix(result.Details[0], "gastown/witness: ~") ||
+		!strings.Contains(result.Details[0], "custom.mdc ~10.0k") {
+		t.Errorf("details = %v", result.Details)
+	}
+}
+
+func TestContextRole(t *testing.T) {
+	tests := map[string][2]string{
+		"mayor":            {"mayor", ""},
+		"gastown/polecats": {"polecat", "gastown"},
+		"gastown/crew":     {"crew", "gastown"},
+		"gastown/witness":  {"witness", "gastown"},
+	}
+	for agent, want := range tests {
+		if role, rig := contextRole(agent); role != want[0] || rig != want[1] {
+			t.Errorf("contextRole(%q) = %q, %q; want %q, %q", agent, role, rig, want[0], want[1])
+		}
+	}
+}
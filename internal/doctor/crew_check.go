@@ -136,7 +136,7 @@ func (c *CrewStateCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("%d crew workspace(s) with invalid state.json", len(c.invalidCrews)),
 		Details: details,
-		FixHint: "Run 'gt doctor --fix' to regenerate state files",
+		Actions: []FixAction{doctorFix("regenerate state files", false)},
 	}
 }
 
@@ -163,6 +163,10 @@ func (c *CrewStateCheck) Fix(ctx *CheckContext) error {
 			continue
 		}
 
+		if err := ctx.Backup.Save(ic.stateFile); err != nil {
+			lastErr = fmt.Errorf("%s/%s: %w", ic.rigName, ic.crewName, err)
+			continue
+		}
 		if err := os.WriteFile(ic.stateFile, data, 0644); err != nil {
 			lastErr = fmt.Errorf("%s/%s: %w", ic.rigName, ic.crewName, err)
 			continue
@@ -269,7 +273,8 @@ func (c *CrewWorktreeCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("%d cross-rig worktree(s) in crew directories", len(worktrees)),
 		Details: details,
-		FixHint: "Run 'gt doctor --fix' to remove, or use 'gt crew remove <name> --purge'",
+		FixHint: "or remove one with 'gt crew remove <name> --purge'",
+		Actions: []FixAction{doctorFix("remove them", true)},
 	}
 }
 
@@ -283,7 +288,7 @@ func (c *CrewWorktreeCheck) Fix(ctx *CheckContext) error {
 	for _, wt := range c.staleWorktrees {
 		// Use git worktree remove to properly clean up
 		mayorRigPath := filepath.Join(ctx.TownRoot, wt.rigName, "mayor", "rig")
-		removeCmd := exec.Command("git", "worktree", "remove", "--force", wt.path)
+		removeCmd := exec.CommandContext(ctx, "git", "worktree", "remove", "--force", wt.path)
 		removeCmd.Dir = mayorRigPath
 		if output, err := removeCmd.CombinedOutput(); err != nil {
 			lastErr = fmt.Errorf("%s/crew/%s: %v (%s)", wt.rigName, wt.name, err, strings.TrimSpace(string(output)))
@@ -0,0 +1,217 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+// MinCursorAgentVersion is the oldest cursor-agent release Gas Town supports
+// (hooks and --resume). cursor-agent uses date-based versions.
+const MinCursorAgentVersion = "2025.09.04"
+
+// cursorCLITimeout bounds each cursor-agent invocation so a hung CLI
+// cannot stall doctor.
+const cursorCLITimeout = 10 * time.Second
+
+// cursorInstallHint tells operators how to install the Cursor CLI.
+const cursorInstallHint = "Install the Cursor CLI: curl https://cursor.com/install -fsS | bash"
+
+// CursorCLICheck verifies the Cursor CLI that every agent session runs:
+// installed, on PATH inside tmux, logged in, and recent enough.
+type CursorCLICheck struct {
+	BaseCheck
+
+	// Seams for tests.
+	runCLI   func(command string, args ...string) (string, error)
+	tmuxPath func(ctx context.Context) (string, bool)
+}
+
+// NewCursorCLICheck creates a new Cursor CLI check.
+func NewCursorCLICheck() *CursorCLICheck {
+	return &CursorCLICheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "cursor-cli",
+			CheckDescription: "Check cursor-agent is installed, on tmux PATH, logged in, and supported",
+		},
+		runCLI:   runCursorCLI,
+		tmuxPath: tmuxGlobalPath,
+	}
+}
+
+// Run checks the Cursor CLI.
+func (c *CursorCLICheck) Run(ctx *CheckContext) *CheckResult {
+	rc := config.ResolveAgentConfig(ctx.TownRoot, ctx.RigPath())
+	command := rc.Command
+	if filepath.Base(command) != "cursor-agent" {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Default agent runs %q, not cursor-agent (skipped)", command),
+		}
+	}
+
+	// Installed?
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("%s not found on PATH; agent sessions cannot start", command),
+			FixHint: cursorInstallHint,
+		}
+	}
+	details := []string{"Binary: " + path}
+
+	// On PATH inside tmux? Sessions inherit the tmux server's environment,
+	// which may predate shell profile changes.
+	if !filepath.IsAbs(command) {
+		if tmuxPATH, ok := c.tmuxPath(ctx); ok && findInPath(command, tmuxPATH) == "" {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusError,
+				Message: fmt.Sprintf("%s is not on the tmux server's PATH; sessions will fail to start", command),
+				Details: append(details, "tmux PATH: "+tmuxPATH),
+				FixHint: fmt.Sprintf("Run: tmux set-environment -g PATH \"$PATH\" (or set the agent command to %s)", path),
+			}
+		}
+	}
+
+	// Version
+	var warnings []string
+	out, err := c.runCLI(path, "--version")
+	version := parseCursorAgentVersion(out)
+	switch {
+	case err != nil:
+		warnings = append(warnings, fmt.Sprintf("could not determine version: %v", err))
+	case version == "":
+		warnings = append(warnings, fmt.Sprintf("could not parse version from %q", strings.TrimSpace(out)))
+	case compareDottedVersions(version, MinCursorAgentVersion) < 0:
+		warnings = append(warnings, fmt.Sprintf("version %s is older than the minimum supported %s", version, MinCursorAgentVersion))
+	default:
+		details = append(details, "Version: "+version)
+	}
+
+	// Logged in? An API key in the session env also authenticates agents.
+	status, statusErr := c.runCLI(path, "status")
+	if !cursorLoggedIn(status) {
+		if _, ok := config.ResolveSessionEnv(ctx.TownRoot, ctx.RigPath())["CURSOR_API_KEY"]; ok {
+			details = append(details, "Auth: CURSOR_API_KEY set in settings env")
+		} else {
+			msg := "cursor-agent is not logged in; sessions will stall at the login prompt"
+			if statusErr != nil {
+				details = append(details, fmt.Sprintf("cursor-agent status: %v", statusErr))
+			}
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusError,
+				Message: msg,
+				Details: details,
+				FixHint: "or set CURSOR_API_KEY in settings env (see 'gt secret')",
+				Actions: []FixAction{{Command: "cursor-agent", Args: []string{"login"}}},
+			}
+		}
+	} else {
+		details = append(details, "Auth: "+firstLine(status))
+	}
+
+	if len(warnings) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "cursor-agent " + strings.Join(warnings, "; "),
+			Details: details,
+			Actions: []FixAction{{Command: "cursor-agent", Args: []string{"update"}, Description: "update"}},
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("cursor-agent %s installed and logged in", version),
+		Details: details,
+	}
+}
+
+// runCursorCLI runs the Cursor CLI with a timeout and returns combined output.
+func runCursorCLI(command string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cursorCLITimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, command, args...).CombinedOutput() //nolint:gosec // G204: command is the configured agent binary
+	if ctx.Err() == context.DeadlineExceeded {
+		return string(out), fmt.Errorf("timed out after %s", cursorCLITimeout)
+	}
+	return string(out), err
+}
+
+// tmuxGlobalPath returns PATH from the tmux server's global environment.
+// Returns false when no server is running or PATH is not set there (new
+// sessions then inherit the environment of whoever starts the server).
+func tmuxGlobalPath(ctx context.Context) (string, bool) {
+	out, err := exec.CommandContext(ctx, "tmux", "show-environment", "-g", "PATH").Output()
+	if err != nil {
+		return "", false
+	}
+	value, ok := strings.CutPrefix(strings.TrimSpace(string(out)), "PATH=")
+	return value, ok
+}
+
+// findInPath returns the path of an executable named command in a PATH
+// list, or "" if it is not there.
+func findInPath(command, pathList string) string {
+	for _, dir := range filepath.SplitList(pathList) {
+		candidate := filepath.Join(dir, command)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return candidate
+		}
+	}
+	return ""
+}
+
+var cursorVersionPattern = regexp.MustCompile(`\d+(\.\d+)+`)
+
+// parseCursorAgentVersion extracts a dotted version ("2025.09.18" from
+// "2025.09.18-7ae6800") from cursor-agent --version output.
+func parseCursorAgentVersion(out string) string {
+	return cursorVersionPattern.FindString(out)
+}
+
+// compareDottedVersions compares dotted numeric versions component-wise,
+// returning -1, 0, or 1. Missing components count as zero.
+func compareDottedVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// cursorLoggedIn reports whether cursor-agent status output shows a login.
+func cursorLoggedIn(status string) bool {
+	lower := strings.ToLower(status)
+	return strings.Contains(lower, "logged in") && !strings.Contains(lower, "not logged in")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
@@ -0,0 +1,94 @@
+package doctor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeCursorCLI puts an executable cursor-agent on PATH and returns a check
+// whose CLI calls return the given version and status output.
+func fakeCursorCLI(t *testing.T, version, status string, tmuxPATH *string) *CursorCLICheck {
+	t.Helper()
+	bin := t.TempDir()
+	mustWrite(t, filepath.Join(bin, "cursor-agent"), "#!/bin/sh\n")
+	if err := os.Chmod(filepath.Join(bin, "cursor-agent"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	c := NewCursorCLICheck()
+	c.runCLI = func(command string, args ...string) (string, error) {
+		if args[0] == "--version" {
+			return version, nil
+		}
+		return status, nil
+	}
+	c.tmuxPath = func(context.Context) (string, bool) {
+		if tmuxPATH == nil {
+			return "", false
+		}
+		return *tmuxPATH, true
+	}
+	return c
+}
+
+func TestCursorCLICheck(t *testing.T) {
+	townRoot := t.TempDir()
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	t.Run("healthy", func(t *testing.T) {
+		c := fakeCursorCLI(t, "2025.10.02-abc1234\n", "Logged in as dev@example.com\n", nil)
+		if r := c.Run(ctx); r.Status != StatusOK {
+			t.Errorf("status = %v, want OK: %s %v", r.Status, r.Message, r.Details)
+		}
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		if r := NewCursorCLICheck().Run(ctx); r.Status != StatusError || !strings.Contains(r.Message, "not found") {
+			t.Errorf("got %v %q, want missing binary error", r.Status, r.Message)
+		}
+	})
+
+	t.Run("missing from tmux PATH", func(t *testing.T) {
+		other := t.TempDir()
+		c := fakeCursorCLI(t, "2025.10.02", "Logged in", &other)
+		if r := c.Run(ctx); r.Status != StatusError || !strings.Contains(r.Message, "tmux") {
+			t.Errorf("got %v %q, want tmux PATH error", r.Status, r.Message)
+		}
+	})
+
+	t.Run("not logged in", func(t *testing.T) {
+		c := fakeCursorCLI(t, "2025.10.02", "Not logged in\n", nil)
+		if r := c.Run(ctx); r.Status != StatusError || !strings.Contains(r.Message, "not logged in") {
+			t.Errorf("got %v %q, want login error", r.Status, r.Message)
+		}
+	})
+
+	t.Run("old version", func(t *testing.T) {
+		c := fakeCursorCLI(t, "2025.07.01-deadbee", "Logged in as dev", nil)
+		if r := c.Run(ctx); r.Status != StatusWarning || !strings.Contains(r.Message, "older") {
+			t.Errorf("got %v %q, want old version warning", r.Status, r.Message)
+		}
+	})
+}
+
+func TestCompareDottedVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2025.09.04", "2025.09.04", 0},
+		{"2025.10.01", "2025.09.04", 1},
+		{"2025.9.4", "2025.09.04", 0},
+		{"1.2", "1.2.1", -1},
+	}
+	for _, tt := range tests {
+		if got := compareDottedVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareDottedVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
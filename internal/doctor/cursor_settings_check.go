@@ -1,13 +1,16 @@
 package doctor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
@@ -28,6 +31,7 @@ const (
 type CursorSettingsCheck struct {
 	FixableCheck
 	staleSettings []staleSettingsInfo
+	hooks         *config.CursorHooksConfig // town's optional hook settings
 }
 
 type staleSettingsInfo struct {
@@ -36,6 +40,7 @@ type staleSettingsInfo struct {
 	rigName       string        // Rig name (empty for town-level agents)
 	sessionName   string        // tmux session name for cycling
 	missing       []string      // What's missing from the settings
+	outdated      bool          // Older settings version, migrated in place by Fix
 	wrongLocation bool          // True if file is in wrong location (should be deleted)
 	gitStatus     gitFileStatus // Git status for wrong-location files (for safe deletion)
 }
@@ -47,6 +52,7 @@ func NewCursorSettingsCheck() *CursorSettingsCheck {
 			BaseCheck: BaseCheck{
 				CheckName:        "cursor-settings",
 				CheckDescription: "Verify Cursor settings files match expected templates",
+				CheckDependsOn:   []string{"town-config-exists"},
 			},
 		},
 	}
@@ -55,6 +61,10 @@ func NewCursorSettingsCheck() *CursorSettingsCheck {
 // Run checks all Cursor settings files for staleness.
 func (c *CursorSettingsCheck) Run(ctx *CheckContext) *CheckResult {
 	c.staleSettings = nil
+	c.hooks = nil
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot)); err == nil {
+		c.hooks = settings.CursorHooks
+	}
 
 	var details []string
 	var hasModifiedFiles bool
@@ -66,7 +76,7 @@ func (c *CursorSettingsCheck) Run(ctx *CheckContext) *CheckResult {
 		// Files in wrong locations are always stale (should be deleted)
 		if sf.wrongLocation {
 			// Check git status to determine safe deletion strategy
-			sf.gitStatus = c.getGitFileStatus(sf.path)
+			sf.gitStatus = c.getGitFileStatus(ctx, sf.path)
 			c.staleSettings = append(c.staleSettings, sf)
 
 			// Provide detailed message based on git status
@@ -88,14 +98,32 @@ func (c *CursorSettingsCheck) Run(ctx *CheckContext) *CheckResult {
 
 		// Check content of files in correct locations
 		missing := c.checkSettings(sf.path, sf.agentType)
+		version := cursor.InstalledSettingsVersion(filepath.Dir(filepath.Dir(sf.path)))
+		sf.outdated = version >= 0 && version < cursor.SettingsVersion
+		var problems []string
 		if len(missing) > 0 {
+			problems = append(problems, "missing "+strings.Join(missing, ", "))
+		}
+		if sf.outdated {
+			problems = append(problems, fmt.Sprintf("settings v%d (current v%d)", version, cursor.SettingsVersion))
+		}
+		if len(problems) > 0 {
 			sf.missing = missing
 			c.staleSettings = append(c.staleSettings, sf)
-			details = append(details, fmt.Sprintf("%s: missing %s", sf.path, strings.Join(missing, ", ")))
+			details = append(details, fmt.Sprintf("%s: %s", sf.path, strings.Join(problems, "; ")))
 		}
 	}
 
 	if len(c.staleSettings) == 0 {
+		if unknown := cursor.UnknownOptionalHooks(c.hooks); len(unknown) > 0 {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusWarning,
+				Message: fmt.Sprintf("%d unknown hook(s) in cursor_hooks are ignored", len(unknown)),
+				Details: unknown,
+				FixHint: fmt.Sprintf("Optional hooks are %s; fix cursor_hooks in settings/config.json", strings.Join(cursor.OptionalHooks, ", ")),
+			}
+		}
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusOK,
@@ -103,18 +131,69 @@ func (c *CursorSettingsCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	fixHint := "Run 'gt doctor --fix' to update settings and restart affected agents"
-	if hasModifiedFiles {
-		fixHint = "Run 'gt doctor --fix' to fix safe issues. Files with local modifications require manual review."
-	}
-
-	return &CheckResult{
+	result := &CheckResult{
 		Name:    c.Name(),
 		Status:  StatusError,
 		Message: fmt.Sprintf("Found %d stale Cursor config file(s) in wrong location", len(c.staleSettings)),
 		Details: details,
-		FixHint: fixHint,
+		Actions: []FixAction{doctorFix("update settings and restart affected agents", true)},
 	}
+	if hasModifiedFiles {
+		result.Actions = []FixAction{doctorFix("fix safe issues", true)}
+		result.FixHint = "files with local modifications require manual review"
+	}
+	return result
+}
+
+// Inputs returns the paths this check depends on (see InputFingerprinter):
+// every location a hooks.json is looked for, and the directories whose
+// entries decide those locations. Runs that find a file in a wrong location
+// are not cached, since its git status can change without the file changing.
+func (c *CursorSettingsCheck) Inputs(ctx *CheckContext) []string {
+	for _, sf := range c.findSettingsFiles(ctx.TownRoot) {
+		if sf.wrongLocation {
+			return nil
+		}
+	}
+
+	townRoot := ctx.TownRoot
+	inputs := []string{
+		townRoot,
+		config.TownSettingsPath(townRoot),
+		filepath.Join(townRoot, ".cursor", "hooks.json"),
+		filepath.Join(townRoot, "mayor", ".cursor", "hooks.json"),
+		filepath.Join(townRoot, "deacon", ".cursor", "hooks.json"),
+	}
+	inputs = append(inputs, treeInputs(config.MachineProfilesDir(townRoot))...)
+	entries, err := os.ReadDir(townRoot)
+	if err != nil {
+		return inputs
+	}
+	for _, entry := range entries {
+		rigName := entry.Name()
+		if !entry.IsDir() || rigName == "mayor" || rigName == "deacon" || rigName == "daemon" ||
+			rigName == "docs" || rigName[0] == '.' {
+			continue
+		}
+		rigPath := filepath.Join(townRoot, rigName)
+		inputs = append(inputs, rigPath)
+		for _, agent := range []string{"witness", "refinery"} {
+			inputs = append(inputs,
+				filepath.Join(rigPath, agent, ".cursor", "hooks.json"),
+				filepath.Join(rigPath, agent, "rig", ".cursor", "hooks.json"))
+		}
+		for _, shared := range []string{"crew", "polecats"} {
+			dir := filepath.Join(rigPath, shared)
+			inputs = append(inputs, dir, filepath.Join(dir, ".cursor", "hooks.json"))
+			members, _ := os.ReadDir(dir)
+			for _, member := range members {
+				if member.IsDir() && member.Name() != ".cursor" {
+					inputs = append(inputs, filepath.Join(dir, member.Name(), ".cursor", "hooks.json"))
+				}
+			}
+		}
+	}
+	return inputs
 }
 
 // findSettingsFiles locates all .cursor/ settings files and identifies their agent type.
@@ -131,7 +210,6 @@ func (c *CursorSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 			agentType:     "mayor",
 			sessionName:   "hq-mayor",
 			wrongLocation: true,
-			gitStatus:     c.getGitFileStatus(staleTownRootSettings),
 			missing:       []string{"should be at mayor/.cursor/, not town root"},
 		})
 	}
@@ -287,9 +365,10 @@ func (c *CursorSettingsCheck) findSettingsFiles(townRoot string) []staleSettings
 }
 
 // checkSettings compares a settings file against the expected template.
-// Returns a list of what's missing.
-// agentType is reserved for future role-specific validation.
-func (c *CursorSettingsCheck) checkSettings(path, _ string) []string {
+// Returns a list of what's missing: the version field, or any hook the
+// agent's role requires (cursor.RequiredHooks), including optional hooks the
+// town enabled for the role.
+func (c *CursorSettingsCheck) checkSettings(path, agentType string) []string {
 	var missing []string
 
 	// Read the actual settings
@@ -303,11 +382,6 @@ func (c *CursorSettingsCheck) checkSettings(path, _ string) []string {
 		return []string{"invalid JSON"}
 	}
 
-	// Check for required elements based on Cursor hooks.json template
-	// All templates should have:
-	// 1. version field
-	// 2. hooks object with beforeSubmitPrompt and stop hooks
-
 	// Check version
 	if _, ok := actual["version"]; !ok {
 		missing = append(missing, "version")
@@ -319,14 +393,13 @@ func (c *CursorSettingsCheck) checkSettings(path, _ string) []string {
 		return append(missing, "hooks")
 	}
 
-	// Check beforeSubmitPrompt hook exists (for mail check)
-	if !c.hookHasCommand(hooks, "beforeSubmitPrompt") {
-		missing = append(missing, "beforeSubmitPrompt hook")
-	}
-
-	// Check stop hook exists (for costs recording)
-	if !c.hookHasCommand(hooks, "stop") {
-		missing = append(missing, "stop hook")
+	// Every role needs beforeSubmitPrompt (mail) and stop (costs); the
+	// requirements table adds the hooks specific to this role, adjusted by
+	// the town's cursor_hooks settings.
+	for _, hook := range cursor.RequiredHooks(agentType, c.hooks) {
+		if !c.hookHasCommand(hooks, hook) {
+			missing = append(missing, hook+" hook")
+		}
 	}
 
 	return missing
@@ -334,18 +407,18 @@ func (c *CursorSettingsCheck) checkSettings(path, _ string) []string {
 
 // getGitFileStatus determines the git status of a file.
 // Returns untracked, tracked-clean, tracked-modified, or unknown.
-func (c *CursorSettingsCheck) getGitFileStatus(filePath string) gitFileStatus {
+func (c *CursorSettingsCheck) getGitFileStatus(ctx context.Context, filePath string) gitFileStatus {
 	dir := filepath.Dir(filePath)
 	fileName := filepath.Base(filePath)
 
 	// Check if we're in a git repo
-	cmd := exec.Command("git", "-C", dir, "rev-parse", "--git-dir")
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--git-dir")
 	if err := cmd.Run(); err != nil {
 		return gitStatusUnknown
 	}
 
 	// Check if file is tracked
-	cmd = exec.Command("git", "-C", dir, "ls-files", fileName)
+	cmd = exec.CommandContext(ctx, "git", "-C", dir, "ls-files", fileName)
 	output, err := cmd.Output()
 	if err != nil {
 		return gitStatusUnknown
@@ -357,14 +430,14 @@ func (c *CursorSettingsCheck) getGitFileStatus(filePath string) gitFileStatus {
 	}
 
 	// File is tracked - check if modified
-	cmd = exec.Command("git", "-C", dir, "diff", "--quiet", fileName)
+	cmd = exec.CommandContext(ctx, "git", "-C", dir, "diff", "--quiet", fileName)
 	if err := cmd.Run(); err != nil {
 		// Non-zero exit means file has changes
 		return gitStatusTrackedModified
 	}
 
 	// Also check for staged changes
-	cmd = exec.Command("git", "-C", dir, "diff", "--cached", "--quiet", fileName)
+	cmd = exec.CommandContext(ctx, "git", "-C", dir, "diff", "--cached", "--quiet", fileName)
 	if err := cmd.Run(); err != nil {
 		return gitStatusTrackedModified
 	}
@@ -392,29 +465,45 @@ func (c *CursorSettingsCheck) hookHasCommand(hooks map[string]any, hookName stri
 	return false
 }
 
-// Fix deletes stale settings files and restarts affected agents.
-// Files with local modifications are skipped to avoid losing user changes.
+// Fix moves settings files out of wrong locations and upgrades stale ones in
+// place: older settings versions are migrated and missing Gas Town hooks are
+// merged in, keeping hooks the user added. Only files that are not valid
+// JSON are deleted and recreated. Files with local modifications are skipped
+// to avoid losing user changes, and sessions whose agent is mid-task are
+// left running (see busyProbe).
 func (c *CursorSettingsCheck) Fix(ctx *CheckContext) error {
 	var errors []string
 	var skipped []string
 	t := tmux.NewTmux()
+	probe := newBusyProbe(ctx.TownRoot, time.Now())
+
+	for i, sf := range c.staleSettings {
+		if err := ctx.Step(i, len(c.staleSettings), sf.path); err != nil {
+			return err
+		}
 
-	for _, sf := range c.staleSettings {
 		// Skip files with local modifications - require manual review
 		if sf.wrongLocation && sf.gitStatus == gitStatusTrackedModified {
 			skipped = append(skipped, fmt.Sprintf("%s: has local modifications, skipping", sf.path))
 			continue
 		}
 
-		// Delete the stale settings file
-		if err := os.Remove(sf.path); err != nil {
-			errors = append(errors, fmt.Sprintf("failed to delete %s: %v", sf.path, err))
+		// Back up, then delete settings in the wrong location or beyond
+		// repair; everything else is upgraded in place below
+		if err := ctx.Backup.Save(sf.path); err != nil {
+			errors = append(errors, err.Error())
 			continue
 		}
-
-		// Also delete parent .cursor directory if empty
 		cursorDir := filepath.Dir(sf.path)
-		_ = os.Remove(cursorDir) // Best-effort, will fail if not empty
+		if sf.wrongLocation || cursor.InstalledSettingsVersion(filepath.Dir(cursorDir)) < 0 {
+			if err := os.Remove(sf.path); err != nil {
+				errors = append(errors, fmt.Sprintf("failed to delete %s: %v", sf.path, err))
+				continue
+			}
+
+			// Also delete parent .cursor directory if empty
+			_ = os.Remove(cursorDir) // Best-effort, will fail if not empty
+		}
 
 		// For files in wrong locations, delete and create at correct location
 		if sf.wrongLocation {
@@ -433,13 +522,16 @@ func (c *CursorSettingsCheck) Fix(ctx *CheckContext) error {
 			sessions, _ := t.ListSessions()
 			for _, sess := range sessions {
 				if strings.HasPrefix(sess, session.Prefix) || strings.HasPrefix(sess, session.HQPrefix) {
-					_ = t.KillSession(sess)
+					if probe.restartAllowed(ctx, sess) && t.KillSession(sess) == nil {
+						ctx.Record(ActionSessionKilled, sess)
+					}
 				}
 			}
 			continue
 		}
 
-		// Recreate settings using EnsureSettingsForRole
+		// Migrate and merge settings using EnsureSettingsForRole (recreates
+		// them if deleted above)
 		workDir := filepath.Dir(cursorDir) // agent work directory
 		if err := cursor.EnsureSettingsForRole(workDir, sf.agentType); err != nil {
 			errors = append(errors, fmt.Sprintf("failed to recreate settings for %s: %v", sf.path, err))
@@ -453,14 +545,18 @@ func (c *CursorSettingsCheck) Fix(ctx *CheckContext) error {
 			if sf.agentType == "witness" || sf.agentType == "refinery" ||
 				sf.agentType == "deacon" || sf.agentType == "mayor" {
 				running, _ := t.HasSession(sf.sessionName)
-				if running {
+				if running && probe.restartAllowed(ctx, sf.sessionName) {
 					// Cycle the agent by killing and letting gt up restart it
-					_ = t.KillSession(sf.sessionName)
+					if t.KillSession(sf.sessionName) == nil {
+						ctx.Record(ActionSessionKilled, sf.sessionName)
+					}
 				}
 			}
 		}
 	}
 
+	_ = ctx.Step(len(c.staleSettings), len(c.staleSettings), "")
+
 	// Report skipped files as warnings, not errors
 	if len(skipped) > 0 {
 		for _, s := range skipped {
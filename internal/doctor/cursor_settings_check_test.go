@@ -7,6 +7,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
 )
 
 func TestNewCursorSettingsCheck(t *testing.T) {
@@ -34,12 +37,13 @@ func TestCursorSettingsCheck_NoSettingsFiles(t *testing.T) {
 	}
 }
 
-// createValidSettings creates a valid hooks.json with all required elements.
+// createValidSettings creates a valid hooks.json with the hooks every role requires.
 func createValidSettings(t *testing.T, path string) {
 	t.Helper()
 
 	settings := map[string]any{
-		"version": 1,
+		"version":             1,
+		"gt_settings_version": cursor.SettingsVersion,
 		"hooks": map[string]any{
 			"beforeSubmitPrompt": []any{
 				map[string]any{
@@ -51,6 +55,21 @@ func createValidSettings(t *testing.T, path string) {
 					"command": ".cursor/hooks/gastown-stop.sh",
 				},
 			},
+			"sessionStart": []any{
+				map[string]any{
+					"command": ".cursor/hooks/gastown-session-start.sh",
+				},
+			},
+			"preCompact": []any{
+				map[string]any{
+					"command": ".cursor/hooks/gastown-precompact.sh",
+				},
+			},
+			"afterFileEdit": []any{
+				map[string]any{
+					"command": ".cursor/hooks/gastown-capture.sh edit",
+				},
+			},
 		},
 	}
 
@@ -73,7 +92,8 @@ func createStaleSettings(t *testing.T, path string, missingElements ...string) {
 	t.Helper()
 
 	settings := map[string]any{
-		"version": 1,
+		"version":             1,
+		"gt_settings_version": cursor.SettingsVersion,
 		"hooks": map[string]any{
 			"beforeSubmitPrompt": []any{
 				map[string]any{
@@ -85,6 +105,21 @@ func createStaleSettings(t *testing.T, path string, missingElements ...string) {
 					"command": ".cursor/hooks/gastown-stop.sh",
 				},
 			},
+			"sessionStart": []any{
+				map[string]any{
+					"command": ".cursor/hooks/gastown-session-start.sh",
+				},
+			},
+			"preCompact": []any{
+				map[string]any{
+					"command": ".cursor/hooks/gastown-precompact.sh",
+				},
+			},
+			"afterFileEdit": []any{
+				map[string]any{
+					"command": ".cursor/hooks/gastown-capture.sh edit",
+				},
+			},
 		},
 	}
 
@@ -94,12 +129,9 @@ func createStaleSettings(t *testing.T, path string, missingElements ...string) {
 			delete(settings, "version")
 		case "hooks":
 			delete(settings, "hooks")
-		case "beforeSubmitPrompt":
-			hooks := settings["hooks"].(map[string]any)
-			delete(hooks, "beforeSubmitPrompt")
-		case "stop":
+		default: // A hook event, e.g. "stop" or "afterFileEdit"
 			hooks := settings["hooks"].(map[string]any)
-			delete(hooks, "stop")
+			delete(hooks, missing)
 		}
 	}
 
@@ -315,6 +347,132 @@ func TestCursorSettingsCheck_MissingStopHook(t *testing.T) {
 	}
 }
 
+func TestCursorSettingsCheck_RoleSpecificHooks(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// afterFileEdit is required of the refinery but not the witness
+	refinerySettings := filepath.Join(tmpDir, "testrig", "refinery", ".cursor", "hooks.json")
+	witnessSettings := filepath.Join(tmpDir, "testrig", "witness", ".cursor", "hooks.json")
+	createStaleSettings(t, refinerySettings, "afterFileEdit")
+	createStaleSettings(t, witnessSettings, "afterFileEdit")
+
+	// sessionStart is required of the mayor but not the deacon
+	mayorSettings := filepath.Join(tmpDir, "mayor", ".cursor", "hooks.json")
+	deaconSettings := filepath.Join(tmpDir, "deacon", ".cursor", "hooks.json")
+	createStaleSettings(t, mayorSettings, "sessionStart")
+	createStaleSettings(t, deaconSettings, "sessionStart")
+
+	check := NewCursorSettingsCheck()
+	result := check.Run(&CheckContext{TownRoot: tmpDir})
+
+	want := []string{
+		mayorSettings + ": missing sessionStart hook",
+		refinerySettings + ": missing afterFileEdit hook",
+	}
+	if strings.Join(result.Details, "\n") != strings.Join(want, "\n") {
+		t.Errorf("details =\n%s\nwant\n%s", strings.Join(result.Details, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCursorSettingsCheck_OptionalHooksPerRole(t *testing.T) {
+	tmpDir := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.CursorHooks = &config.CursorHooksConfig{Roles: map[string]*config.RoleHooksConfig{
+		"witness":  {Enable: []string{"beforeShellExecution"}},
+		"refinery": {Disable: []string{"afterFileEdit"}},
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(tmpDir), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	// The witness now requires beforeShellExecution; the refinery no longer
+	// requires afterFileEdit
+	witnessSettings := filepath.Join(tmpDir, "testrig", "witness", ".cursor", "hooks.json")
+	refinerySettings := filepath.Join(tmpDir, "testrig", "refinery", ".cursor", "hooks.json")
+	createStaleSettings(t, witnessSettings)
+	createStaleSettings(t, refinerySettings, "afterFileEdit")
+
+	check := NewCursorSettingsCheck()
+	result := check.Run(&CheckContext{TownRoot: tmpDir})
+
+	want := []string{witnessSettings + ": missing beforeShellExecution hook"}
+	if strings.Join(result.Details, "\n") != strings.Join(want, "\n") {
+		t.Errorf("details =\n%s\nwant\n%s", strings.Join(result.Details, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCursorSettingsCheck_UnknownOptionalHook(t *testing.T) {
+	tmpDir := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.CursorHooks = &config.CursorHooksConfig{Roles: map[string]*config.RoleHooksConfig{
+		"crew": {Enable: []string{"afterFileEdits"}},
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(tmpDir), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewCursorSettingsCheck()
+	result := check.Run(&CheckContext{TownRoot: tmpDir})
+
+	if result.Status != StatusWarning {
+		t.Errorf("expected StatusWarning for an unknown hook, got %v", result.Status)
+	}
+	if len(result.Details) != 1 || result.Details[0] != "crew: afterFileEdits" {
+		t.Errorf("details = %v, want [crew: afterFileEdits]", result.Details)
+	}
+}
+
+func TestCursorSettingsCheck_MigratesOutdatedSettingsInPlace(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A pre-versioning witness hooks.json: Claude-style events, bare script
+	// commands, and a hook the user added
+	legacy := `{
+  "version": 1,
+  "hooks": {
+    "UserPromptSubmit": [{"matcher": "", "hooks": [{"type": "command", "command": ".cursor/hooks/gastown-prompt.sh"}]}],
+    "Stop": [{"matcher": "", "hooks": [{"type": "command", "command": ".cursor/hooks/gastown-stop.sh"}]}],
+    "preCompact": [{"command": ".cursor/hooks/gastown-precompact.sh"}],
+    "afterShellExecution": [{"command": "./audit.sh"}]
+  }
+}`
+	workDir := filepath.Join(tmpDir, "testrig", "witness")
+	settingsPath := filepath.Join(workDir, ".cursor", "hooks.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewCursorSettingsCheck()
+	result := check.Run(&CheckContext{TownRoot: tmpDir})
+	want := settingsPath + ": missing beforeSubmitPrompt hook, stop hook; settings v0 (current v3)"
+	if len(result.Details) != 1 || result.Details[0] != want {
+		t.Fatalf("details = %v, want [%s]", result.Details, want)
+	}
+
+	if err := check.Fix(&CheckContext{TownRoot: tmpDir}); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if v := cursor.InstalledSettingsVersion(workDir); v != cursor.SettingsVersion {
+		t.Errorf("settings version after fix = %d, want %d", v, cursor.SettingsVersion)
+	}
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "./audit.sh") {
+		t.Errorf("fix dropped the user's hook:\n%s", data)
+	}
+	if conflicts := cursor.HookConflicts(workDir, "witness"); len(conflicts) > 0 {
+		t.Errorf("migrated Gas Town hooks reported as user edits: %v", conflicts)
+	}
+	if result := check.Run(&CheckContext{TownRoot: tmpDir}); result.Status != StatusOK {
+		t.Errorf("after fix: %v %v", result.Message, result.Details)
+	}
+}
+
 func TestCursorSettingsCheck_WrongLocationWitness(t *testing.T) {
 	tmpDir := t.TempDir()
 	rigName := "testrig"
@@ -1,16 +1,20 @@
 package doctor
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"time"
 
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
 )
 
-// DaemonCheck verifies the daemon is running.
+// DaemonCheck verifies the daemon is running, responsive (heartbeats are
+// completing), and running the same gt version as this binary.
 type DaemonCheck struct {
 	FixableCheck
+	needsRestart bool // running but hung or on another version
 }
 
 // NewDaemonCheck creates a new daemon check.
@@ -19,14 +23,16 @@ func NewDaemonCheck() *DaemonCheck {
 		FixableCheck: FixableCheck{
 			BaseCheck: BaseCheck{
 				CheckName:        "daemon",
-				CheckDescription: "Check if Gas Town daemon is running",
+				CheckDescription: "Check Gas Town daemon is running, responsive, and up to date",
 			},
 		},
 	}
 }
 
-// Run checks if the daemon is running.
+// Run checks daemon liveness, heartbeat freshness, and version.
 func (c *DaemonCheck) Run(ctx *CheckContext) *CheckResult {
+	c.needsRestart = false
+
 	running, pid, err := daemon.IsRunning(ctx.TownRoot)
 	if err != nil {
 		return &CheckResult{
@@ -37,36 +43,99 @@ func (c *DaemonCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	if running {
-		// Get more info about daemon state
-		state, err := daemon.LoadState(ctx.TownRoot)
-		details := []string{}
-		if err == nil && !state.StartedAt.IsZero() {
-			uptime := time.Since(state.StartedAt).Round(time.Second)
-			details = append(details, "Uptime: "+uptime.String())
-			if state.HeartbeatCount > 0 {
-				details = append(details, "Heartbeats: "+string(rune(state.HeartbeatCount)))
-			}
+	// A stopped daemon is fine when mayor/daemon.json disables the heartbeat
+	if !running && heartbeatDisabled(ctx.TownRoot) {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "Daemon not configured (heartbeat disabled in mayor/daemon.json)",
 		}
+	}
 
+	if !running {
 		return &CheckResult{
 			Name:    c.Name(),
-			Status:  StatusOK,
-			Message: "Daemon is running (PID " + itoa(pid) + ")",
+			Status:  StatusWarning,
+			Message: "Daemon is not running",
+			Actions: []FixAction{{Command: "gt", Args: []string{"daemon", "start"}, Description: "start the daemon"}, doctorFix("start it", false)},
+		}
+	}
+
+	// Get more info about daemon state
+	state, err := daemon.LoadState(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Daemon is running (PID %d) but its state is unreadable", pid),
+			Details: []string{err.Error()},
+		}
+	}
+
+	now := time.Now()
+	var details []string
+	if !state.StartedAt.IsZero() {
+		details = append(details, "Uptime: "+now.Sub(state.StartedAt).Round(time.Second).String())
+	}
+	if state.LastHeartbeat.IsZero() {
+		details = append(details, "Last heartbeat: none yet")
+	} else {
+		details = append(details, fmt.Sprintf("Last heartbeat: %s ago (#%d)",
+			now.Sub(state.LastHeartbeat).Round(time.Second), state.HeartbeatCount))
+	}
+	if state.Version != "" {
+		details = append(details, "Version: "+state.Version)
+	}
+
+	if state.HeartbeatStale(now) {
+		c.needsRestart = true
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("Daemon (PID %d) is unresponsive: no heartbeat for over %s", pid, daemon.HeartbeatStaleAfter),
+			Details: details,
+			Actions: []FixAction{doctorFix("restart the daemon", false)},
+		}
+	}
+
+	if ctx.GTVersion != "" && state.Version != ctx.GTVersion {
+		c.needsRestart = true
+		daemonVersion := state.Version
+		if daemonVersion == "" {
+			daemonVersion = "unknown (older gt)"
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Daemon is running gt %s, but this is gt %s", daemonVersion, ctx.GTVersion),
 			Details: details,
+			Actions: []FixAction{doctorFix("restart the daemon on the current version", false)},
 		}
 	}
 
 	return &CheckResult{
 		Name:    c.Name(),
-		Status:  StatusWarning,
-		Message: "Daemon is not running",
-		FixHint: "Run 'gt daemon start' or 'gt doctor --fix'",
+		Status:  StatusOK,
+		Message: fmt.Sprintf("Daemon is running (PID %d)", pid),
+		Details: details,
 	}
 }
 
-// Fix starts the daemon.
+// heartbeatDisabled reports whether mayor/daemon.json explicitly disables
+// the daemon heartbeat.
+func heartbeatDisabled(townRoot string) bool {
+	cfg, err := config.LoadDaemonPatrolConfig(config.DaemonPatrolConfigPath(townRoot))
+	return err == nil && cfg.Heartbeat != nil && !cfg.Heartbeat.Enabled
+}
+
+// Fix starts the daemon, stopping it first if it is hung or outdated.
 func (c *DaemonCheck) Fix(ctx *CheckContext) error {
+	if c.needsRestart {
+		if err := daemon.StopDaemon(ctx.TownRoot); err != nil {
+			return fmt.Errorf("stopping daemon: %w", err)
+		}
+	}
+
 	// Find gt executable
 	gtPath, err := os.Executable()
 	if err != nil {
@@ -74,7 +143,7 @@ func (c *DaemonCheck) Fix(ctx *CheckContext) error {
 	}
 
 	// Start daemon in background (detach from parent I/O - daemon uses its own logging)
-	cmd := exec.Command(gtPath, "daemon", "run")
+	cmd := exec.CommandContext(ctx, gtPath, "daemon", "run")
 	cmd.Dir = ctx.TownRoot
 	cmd.Stdin = nil
 	cmd.Stdout = nil
@@ -89,23 +158,3 @@ func (c *DaemonCheck) Fix(ctx *CheckContext) error {
 
 	return nil
 }
-
-// itoa is a simple int to string helper
-func itoa(i int) string {
-	if i == 0 {
-		return "0"
-	}
-	s := ""
-	neg := i < 0
-	if neg {
-		i = -i
-	}
-	for i > 0 {
-		s = string(rune('0'+i%10)) + s
-		i /= 10
-	}
-	if neg {
-		s = "-" + s
-	}
-	return s
-}
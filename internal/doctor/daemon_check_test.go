@@ -0,0 +1,88 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+)
+
+// fakeDaemon records the test process as the running daemon with the given state.
+func fakeDaemon(t *testing.T, townRoot string, state *daemon.State) {
+	t.Helper()
+	mustWrite(t, filepath.Join(townRoot, "daemon", "daemon.pid"), strconv.Itoa(os.Getpid()))
+	if err := daemon.SaveState(townRoot, state); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDaemonCheck_NotRunning(t *testing.T) {
+	townRoot := t.TempDir()
+	result := NewDaemonCheck().Run(&CheckContext{TownRoot: townRoot, GTVersion: "1.0.0"})
+	if result.Status != StatusWarning {
+		t.Errorf("Status = %v, want warning: %s", result.Status, result.Message)
+	}
+}
+
+func TestDaemonCheck_HeartbeatDisabled(t *testing.T) {
+	townRoot := t.TempDir()
+	mustWrite(t, filepath.Join(townRoot, "mayor", "daemon.json"),
+		`{"type":"daemon-patrol-config","version":1,"heartbeat":{"enabled":false}}`)
+
+	result := NewDaemonCheck().Run(&CheckContext{TownRoot: townRoot, GTVersion: "1.0.0"})
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want ok: %s", result.Status, result.Message)
+	}
+}
+
+func TestDaemonCheck_Running(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		state        daemon.State
+		want         CheckStatus
+		needsRestart bool
+	}{
+		{
+			name:  "healthy",
+			state: daemon.State{StartedAt: now.Add(-time.Hour), LastHeartbeat: now.Add(-time.Minute), HeartbeatCount: 12, Version: "1.0.0"},
+			want:  StatusOK,
+		},
+		{
+			name:         "unresponsive",
+			state:        daemon.State{StartedAt: now.Add(-time.Hour), LastHeartbeat: now.Add(-daemon.HeartbeatStaleAfter - time.Minute), Version: "1.0.0"},
+			want:         StatusError,
+			needsRestart: true,
+		},
+		{
+			name:         "old version",
+			state:        daemon.State{StartedAt: now.Add(-time.Hour), LastHeartbeat: now.Add(-time.Minute), Version: "0.9.0"},
+			want:         StatusWarning,
+			needsRestart: true,
+		},
+		{
+			name:         "unversioned",
+			state:        daemon.State{StartedAt: now.Add(-time.Hour), LastHeartbeat: now.Add(-time.Minute)},
+			want:         StatusWarning,
+			needsRestart: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := t.TempDir()
+			fakeDaemon(t, townRoot, &tt.state)
+
+			check := NewDaemonCheck()
+			result := check.Run(&CheckContext{TownRoot: townRoot, GTVersion: "1.0.0"})
+			if result.Status != tt.want {
+				t.Errorf("Status = %v, want %v: %s", result.Status, tt.want, result.Message)
+			}
+			if check.needsRestart != tt.needsRestart {
+				t.Errorf("needsRestart = %v, want %v", check.needsRestart, tt.needsRestart)
+			}
+		})
+	}
+}
@@ -1,8 +1,25 @@
 package doctor
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultCheckTimeout is how long gt doctor lets a single check run before
+// reporting it as timed out and moving on.
+const DefaultCheckTimeout = 60 * time.Second
+
 // Doctor manages and executes health checks.
 type Doctor struct {
-	checks []Check
+	checks      []Check
+	cache       *ResultCache
+	changedOnly bool
+	timeout     time.Duration
+	onCheck     CheckProgressFunc
+	baseline    *Baseline
 }
 
 // NewDoctor creates a new Doctor with no registered checks.
@@ -27,17 +44,214 @@ func (d *Doctor) Checks() []Check {
 	return d.checks
 }
 
+// dependent is implemented by checks that declare prerequisites (see
+// BaseCheck.CheckDependsOn).
+type dependent interface {
+	DependsOn() []string
+}
+
+// dependsOn returns the names of the checks check depends on.
+func dependsOn(check Check) []string {
+	if dep, ok := check.(dependent); ok {
+		return dep.DependsOn()
+	}
+	return nil
+}
+
+// ordered returns the registered checks with every check after the checks
+// it depends on, otherwise keeping registration order. Dependencies that
+// are not registered (e.g. filtered out by a profile) are ignored, and a
+// dependency cycle is broken where it is found.
+func (d *Doctor) ordered() []Check {
+	byName := make(map[string]Check, len(d.checks))
+	for _, check := range d.checks {
+		byName[check.Name()] = check
+	}
+
+	order := make([]Check, 0, len(d.checks))
+	visited := make(map[string]bool, len(d.checks))
+	var visit func(check Check)
+	visit = func(check Check) {
+		if visited[check.Name()] {
+			return
+		}
+		visited[check.Name()] = true
+		for _, name := range dependsOn(check) {
+			if dep, ok := byName[name]; ok {
+				visit(dep)
+			}
+		}
+		order = append(order, check)
+	}
+	for _, check := range d.checks {
+		visit(check)
+	}
+	return order
+}
+
+// blocked returns a blocked result if a check that check depends on has
+// failed (error, timeout, or itself blocked), or nil if it may run.
+// Warnings do not block dependents, and neither do failures accepted in
+// the baseline: the report does not count those, so dependents must run
+// for their own findings to be seen.
+func blocked(check Check, results map[string]*CheckResult) *CheckResult {
+	var failed []string
+	for _, name := range dependsOn(check) {
+		if r, ok := results[name]; ok && !r.Baselined && r.Status != StatusOK && r.Status != StatusWarning {
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &CheckResult{
+		Name:    check.Name(),
+		Status:  StatusBlocked,
+		Message: "Skipped: depends on failing " + strings.Join(failed, ", "),
+	}
+}
+
+// SetCache attaches a result cache. Results of fingerprintable checks are
+// always recorded; when changedOnly is true, checks whose inputs are
+// unchanged since the cached run are skipped and their cached result reused.
+func (d *Doctor) SetCache(cache *ResultCache, changedOnly bool) {
+	d.cache = cache
+	d.changedOnly = changedOnly
+}
+
+// SetTimeout limits how long each check's Run, and each Fix, may take (0
+// disables). A check that overruns is reported with StatusTimeout and the
+// run moves on; a fix that overruns fails (see runFix).
+func (d *Doctor) SetTimeout(timeout time.Duration) {
+	d.timeout = timeout
+}
+
+// SetCheckProgress sets a function called as each check starts and
+// finishes, so callers can show live progress and stream results.
+func (d *Doctor) SetCheckProgress(fn CheckProgressFunc) {
+	d.onCheck = fn
+}
+
+// SetBaseline attaches the town's acknowledged findings (nil disables).
+// Results whose findings are all baselined do not count as failures.
+func (d *Doctor) SetBaseline(b *Baseline) {
+	d.baseline = b
+}
+
+// record adds the final result of the check at index i to the report,
+// after applying the baseline, and reports it as finished.
+func (d *Doctor) record(i int, result *CheckResult, report *Report, results map[string]*CheckResult) {
+	d.baseline.Apply(result)
+	results[result.Name] = result
+	report.Add(result)
+	d.finished(i, result)
+}
+
+// started reports that the check at index i is about to run.
+func (d *Doctor) started(i int, check Check) {
+	if d.onCheck != nil {
+		d.onCheck(CheckProgress{Check: check.Name(), Index: i, Total: len(d.checks)})
+	}
+}
+
+// finished reports the final result of the check at index i.
+func (d *Doctor) finished(i int, result *CheckResult) {
+	if d.onCheck != nil {
+		d.onCheck(CheckProgress{Check: result.Name, Index: i, Total: len(d.checks), Result: result})
+	}
+}
+
+// runCheck runs a check within the per-check timeout. The check sees a copy
+// of ctx whose context is cancelled at the deadline; if it has not returned
+// by then it is abandoned (its subprocesses are killed if it used
+// exec.CommandContext) and a timeout result is returned in its place. The
+// copy carries none of the fix state (backup, journal, progress), so an
+// abandoned check cannot touch it.
+func (d *Doctor) runCheck(check Check, ctx *CheckContext) *CheckResult {
+	if d.timeout <= 0 {
+		return named(check, check.Run(ctx))
+	}
+
+	runCtx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+	checkCtx := *ctx
+	checkCtx.runCtx = runCtx
+	checkCtx.Backup, checkCtx.Journal, checkCtx.Progress, checkCtx.ConfirmRestart = nil, nil, nil, nil
+
+	done := make(chan *CheckResult, 1)
+	go func() { done <- check.Run(&checkCtx) }()
+
+	select {
+	case result := <-done:
+		return named(check, result)
+	case <-runCtx.Done():
+		return &CheckResult{
+			Name:    check.Name(),
+			Status:  StatusTimeout,
+			Message: fmt.Sprintf("Timed out after %s", d.timeout),
+			FixHint: "Re-run with a longer --check-timeout, or look for a hung git or bd process",
+		}
+	}
+}
+
+// runFix runs check's Fix within the per-check timeout. Unlike Run, a fix
+// is never abandoned, since it holds the run's backup and journal: it runs
+// under ctx with its context cancelled at the deadline, so subprocesses
+// started with exec.CommandContext are killed and the fix can return.
+// A fix that returns only after the deadline is reported as timed out.
+func (d *Doctor) runFix(check Check, ctx *CheckContext) error {
+	if d.timeout <= 0 {
+		return check.Fix(ctx)
+	}
+
+	runCtx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+	prev := ctx.runCtx
+	ctx.runCtx = runCtx
+	defer func() { ctx.runCtx = prev }()
+
+	err := check.Fix(ctx)
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		if err == nil {
+			return fmt.Errorf("timed out after %s", d.timeout)
+		}
+		return fmt.Errorf("timed out after %s: %w", d.timeout, err)
+	}
+	return err
+}
+
+// named ensures the result's check name is populated.
+func named(check Check, result *CheckResult) *CheckResult {
+	if result.Name == "" {
+		result.Name = check.Name()
+	}
+	return result
+}
+
 // Run executes all registered checks and returns a report.
 func (d *Doctor) Run(ctx *CheckContext) *Report {
 	report := NewReport()
+	results := make(map[string]*CheckResult, len(d.checks))
 
-	for _, check := range d.checks {
-		result := check.Run(ctx)
-		// Ensure check name is populated
-		if result.Name == "" {
-			result.Name = check.Name()
+	for i, check := range d.ordered() {
+		if result := blocked(check, results); result != nil {
+			d.record(i, result, report, results)
+			continue
+		}
+
+		key, fp := cacheKey(check, ctx), checkFingerprint(check, ctx)
+		if cached := d.reusable(key, fp, false); cached != nil {
+			result := resultFromCache(check.Name(), cached)
+			d.record(i, result, report, results)
+			continue
 		}
-		report.Add(result)
+
+		d.started(i, check)
+		start := time.Now()
+		result := d.runCheck(check, ctx)
+		result.Elapsed = time.Since(start)
+		d.cache.store(key, fp, result)
+		d.record(i, result, report, results)
 	}
 
 	return report
@@ -45,45 +259,115 @@ func (d *Doctor) Run(ctx *CheckContext) *Report {
 
 // Fix runs all checks with auto-fix enabled where possible.
 // It first runs the check, then if it fails and can be fixed, attempts the fix.
+// Once a check times out no further fixes are attempted: the abandoned check
+// may still be running, and a fix could race with it. Later checks still run
+// so the report is complete.
 func (d *Doctor) Fix(ctx *CheckContext) *Report {
 	report := NewReport()
+	results := make(map[string]*CheckResult, len(d.checks))
+	var abandoned string // First check that timed out
 
-	for _, check := range d.checks {
-		result := check.Run(ctx)
-		if result.Name == "" {
-			result.Name = check.Name()
+	checks := d.ordered()
+	for i, check := range checks {
+		if ctx.Interrupted() {
+			report.Interrupted = true
+			for _, rest := range checks[i:] {
+				report.NotRun = append(report.NotRun, rest.Name())
+			}
+			break
+		}
+
+		// Dependencies have had their chance to be fixed by now
+		if result := blocked(check, results); result != nil {
+			d.record(i, result, report, results)
+			continue
+		}
+
+		key, fp := cacheKey(check, ctx), checkFingerprint(check, ctx)
+		if cached := d.reusable(key, fp, true); cached != nil {
+			result := resultFromCache(check.Name(), cached)
+			d.record(i, result, report, results)
+			continue
 		}
 
-		// Attempt fix if check failed and is fixable
-		if result.Status != StatusOK && check.CanFix() {
-			err := check.Fix(ctx)
-			if err == nil {
+		d.started(i, check)
+		start := time.Now()
+		result := d.runCheck(check, ctx)
+
+		// Attempt fix if check failed and is fixable. A timed-out check did
+		// not finish gathering the state its fix needs.
+		fixable := result.Status != StatusOK && result.Status != StatusTimeout && check.CanFix()
+		if fixable && abandoned != "" {
+			result.Details = append(result.Details, fmt.Sprintf(
+				"Fix skipped: %s timed out and may still be running; re-run 'gt doctor --fix'", abandoned))
+		} else if fixable {
+			ctx.fixing = check.Name()
+			mark := ctx.Backup.Len()
+			err := d.runFix(check, ctx)
+			ctx.journalBackups(mark)
+			ctx.fixing = ""
+
+			var partial *PartialFixError
+			if errors.As(err, &partial) {
+				// Stopped between items: report the state the partial fix left
+				report.Interrupted = true
+				result = d.runCheck(check, ctx)
+				result.Fixed = partial.Done > 0
+				result.Details = append(result.Details, fmt.Sprintf(
+					"Fix interrupted: completed %d of %d items", partial.Done, partial.Total))
+				fp = checkFingerprint(check, ctx)
+			} else if err == nil {
 				// Re-run check to verify fix worked
-				result = check.Run(ctx)
-				if result.Name == "" {
-					result.Name = check.Name()
-				}
+				result = d.runCheck(check, ctx)
+				result.Fixed = true
 				// Update message to indicate fix was applied
 				if result.Status == StatusOK {
 					result.Message = result.Message + " (fixed)"
 				}
+				// The fix may have changed the check's inputs
+				fp = checkFingerprint(check, ctx)
 			} else {
 				// Fix failed, add error to details
 				result.Details = append(result.Details, "Fix failed: "+err.Error())
 			}
 		}
 
-		report.Add(result)
+		if result.Status == StatusTimeout && abandoned == "" {
+			abandoned = check.Name()
+		}
+
+		result.Elapsed = time.Since(start)
+		d.cache.store(key, fp, result)
+		d.record(i, result, report, results)
 	}
 
 	return report
 }
 
+// reusable returns a cached result that may stand in for running the check.
+// When fixing, only passing results are reused: fixable checks gather the
+// state Fix needs during Run, so a failing check must run again.
+func (d *Doctor) reusable(key, fp string, fixing bool) *CachedResult {
+	if !d.changedOnly {
+		return nil
+	}
+	cached := d.cache.lookup(key, fp)
+	if cached == nil || (fixing && cached.Status != StatusOK) {
+		return nil
+	}
+	return cached
+}
+
 // BaseCheck provides a base implementation for checks that don't support auto-fix.
 // Embed this in custom checks to get default CanFix() and Fix() implementations.
 type BaseCheck struct {
 	CheckName        string
 	CheckDescription string
+
+	// CheckDependsOn names checks that must pass (or only warn) for this
+	// check to be meaningful. The runner orders them first and reports
+	// this check as blocked when one fails, instead of running it.
+	CheckDependsOn []string
 }
 
 // Name returns the check name.
@@ -96,6 +380,11 @@ func (b *BaseCheck) Description() string {
 	return b.CheckDescription
 }
 
+// DependsOn returns the names of the checks this check depends on.
+func (b *BaseCheck) DependsOn() []string {
+	return b.CheckDependsOn
+}
+
 // CanFix returns false by default.
 func (b *BaseCheck) CanFix() bool {
 	return false
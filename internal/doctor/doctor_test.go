@@ -2,7 +2,10 @@ package doctor
 
 import (
 	"bytes"
+	"os/exec"
+	"strings"
 	"testing"
+	"time"
 )
 
 // mockCheck is a test check that can be configured to return any status.
@@ -54,6 +57,8 @@ func TestCheckStatus_String(t *testing.T) {
 		{StatusOK, "OK"},
 		{StatusWarning, "Warning"},
 		{StatusError, "Error"},
+		{StatusTimeout, "Timeout"},
+		{StatusBlocked, "Blocked"},
 		{CheckStatus(99), "Unknown"},
 	}
 
@@ -194,6 +199,52 @@ func TestReport_IsHealthy(t *testing.T) {
 	}
 }
 
+func TestReport_ExitCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []*CheckResult
+		want    int
+	}{
+		{"empty", nil, ExitOK},
+		{"all OK", []*CheckResult{{Status: StatusOK}}, ExitOK},
+		{"fixed and healthy", []*CheckResult{{Status: StatusOK, Fixed: true}}, ExitOK},
+		{"warnings only", []*CheckResult{{Status: StatusOK}, {Status: StatusWarning}}, ExitWarnings},
+		{"errors", []*CheckResult{{Status: StatusWarning}, {Status: StatusError}}, ExitErrors},
+		{"fixed but error remains", []*CheckResult{{Status: StatusOK, Fixed: true}, {Status: StatusError}}, ExitFixedIssues},
+		{"fix did not clear warning", []*CheckResult{{Status: StatusWarning, Fixed: true}}, ExitFixedIssues},
+		{"timed out", []*CheckResult{{Status: StatusWarning}, {Status: StatusTimeout}}, ExitTimedOut},
+		{"error beats timeout", []*CheckResult{{Status: StatusTimeout}, {Status: StatusError}}, ExitErrors},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReport()
+			for _, res := range tt.results {
+				r.Add(res)
+			}
+			if got := r.ExitCode(); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoctor_FixMarksFixedResults(t *testing.T) {
+	check := newMockCheck("fixable", StatusError)
+	check.fixable = true
+
+	d := NewDoctor()
+	d.Register(check)
+	report := d.Fix(&CheckContext{TownRoot: t.TempDir()})
+
+	if report.Summary.Fixed != 1 {
+		t.Errorf("Summary.Fixed = %d, want 1", report.Summary.Fixed)
+	}
+	if report.ExitCode() != ExitOK {
+		t.Errorf("ExitCode() = %d, want %d after successful fix", report.ExitCode(), ExitOK)
+	}
+}
+
 func TestReport_Print(t *testing.T) {
 	r := NewReport()
 	r.Add(&CheckResult{
@@ -287,6 +338,168 @@ func TestDoctor_Run(t *testing.T) {
 	}
 }
 
+func TestDoctor_Dependencies(t *testing.T) {
+	layout := newMockCheck("layout", StatusError)
+	settings := newMockCheck("settings", StatusOK)
+	settings.CheckDependsOn = []string{"layout"}
+	hooks := newMockCheck("hooks", StatusOK)
+	hooks.CheckDependsOn = []string{"settings", "not-registered"}
+	warned := newMockCheck("warned", StatusWarning)
+	perms := newMockCheck("perms", StatusOK)
+	perms.CheckDependsOn = []string{"warned"}
+
+	d := NewDoctor()
+	d.RegisterAll(settings, hooks, layout, warned, perms) // settings before its dependency
+
+	report := d.Run(&CheckContext{TownRoot: "/test"})
+	var order []string
+	status := make(map[string]CheckStatus)
+	for _, r := range report.Checks {
+		order = append(order, r.Name)
+		status[r.Name] = r.Status
+	}
+	if got := strings.Join(order, ","); got != "layout,settings,hooks,warned,perms" {
+		t.Errorf("order = %s, want dependencies first", got)
+	}
+	if status["settings"] != StatusBlocked || status["hooks"] != StatusBlocked {
+		t.Errorf("dependents of a failed check should be blocked: %v", status)
+	}
+	if status["perms"] != StatusOK {
+		t.Errorf("a warning should not block dependents: %v", status)
+	}
+	if report.Summary.Blocked != 2 || report.Summary.Errors != 1 {
+		t.Errorf("summary = %+v", report.Summary)
+	}
+
+	// Once the prerequisite is fixed, dependents run
+	layout.fixable = true
+	report = d.Fix(&CheckContext{TownRoot: "/test"})
+	if report.Summary.Blocked != 0 || report.Summary.Fixed != 1 {
+		t.Errorf("after fixing prerequisite: summary = %+v", report.Summary)
+	}
+}
+
+// hangingCheck runs a subprocess that outlives any test timeout.
+type hangingCheck struct {
+	FixableCheck
+	err      chan error
+	fixCount int
+}
+
+func (h *hangingCheck) Run(ctx *CheckContext) *CheckResult {
+	h.err <- exec.CommandContext(ctx, "sleep", "60").Run()
+	return &CheckResult{Status: StatusWarning}
+}
+
+func (h *hangingCheck) Fix(ctx *CheckContext) error {
+	h.fixCount++
+	return nil
+}
+
+func TestDoctor_CheckTimeout(t *testing.T) {
+	hang := &hangingCheck{err: make(chan error, 1)}
+	hang.CheckName = "hang"
+
+	d := NewDoctor()
+	d.SetTimeout(100 * time.Millisecond)
+	d.Register(hang)
+	d.Register(newMockCheck("after", StatusOK))
+
+	start := time.Now()
+	report := d.Fix(&CheckContext{TownRoot: t.TempDir()})
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("run took %v; the hung check stalled it", elapsed)
+	}
+
+	if len(report.Checks) != 2 || report.Checks[1].Status != StatusOK {
+		t.Fatalf("checks after the hung one should still run: %+v", report.Checks)
+	}
+	result := report.Checks[0]
+	if result.Name != "hang" || result.Status != StatusTimeout || !strings.Contains(result.Message, "Timed out") {
+		t.Errorf("hung check result = %+v", result)
+	}
+	if hang.fixCount != 0 {
+		t.Error("a timed-out check must not be fixed")
+	}
+	if report.Summary.TimedOut != 1 || report.ExitCode() != ExitTimedOut {
+		t.Errorf("summary = %+v, exit %d", report.Summary, report.ExitCode())
+	}
+
+	// The subprocess was killed, not left running.
+	select {
+	case err := <-hang.err:
+		if err == nil {
+			t.Error("subprocess exited cleanly; expected it to be killed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("subprocess still running after timeout")
+	}
+}
+
+func TestDoctor_FixSkippedAfterTimeout(t *testing.T) {
+	hang := &hangingCheck{err: make(chan error, 1)}
+	hang.CheckName = "hang"
+	later := newMockCheck("later", StatusError)
+	later.fixable = true
+
+	d := NewDoctor()
+	d.SetTimeout(100 * time.Millisecond)
+	d.Register(hang)
+	d.Register(later)
+
+	report := d.Fix(&CheckContext{TownRoot: t.TempDir()})
+	<-hang.err
+
+	// The later check still runs, but is not fixed while the hung one may
+	// still be running.
+	if later.fixCount != 0 {
+		t.Error("fix ran after an earlier check timed out")
+	}
+	result := report.Checks[1]
+	if result.Status != StatusError || len(result.Details) == 0 || !strings.Contains(result.Details[0], "Fix skipped: hang timed out") {
+		t.Errorf("later check result = %+v", result)
+	}
+}
+
+// hangingFixCheck fails quickly but its fix runs a subprocess that outlives
+// any test timeout.
+type hangingFixCheck struct {
+	FixableCheck
+}
+
+func (h *hangingFixCheck) Run(ctx *CheckContext) *CheckResult {
+	return &CheckResult{Status: StatusError, Message: "broken"}
+}
+
+func (h *hangingFixCheck) Fix(ctx *CheckContext) error {
+	return exec.CommandContext(ctx, "sleep", "60").Run()
+}
+
+func TestDoctor_FixTimeout(t *testing.T) {
+	hang := &hangingFixCheck{}
+	hang.CheckName = "hang-fix"
+
+	d := NewDoctor()
+	d.SetTimeout(100 * time.Millisecond)
+	d.Register(hang)
+	d.Register(newMockCheck("after", StatusOK))
+
+	start := time.Now()
+	report := d.Fix(&CheckContext{TownRoot: t.TempDir()})
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("run took %v; the hung fix stalled it", elapsed)
+	}
+
+	result := report.Checks[0]
+	if result.Status != StatusError || result.Fixed || len(result.Details) == 0 ||
+		!strings.Contains(result.Details[0], "Fix failed: timed out after 100ms") {
+		t.Errorf("hung fix result = %+v", result)
+	}
+	if len(report.Checks) != 2 || report.Checks[1].Status != StatusOK {
+		t.Errorf("checks after the hung fix should still run: %+v", report.Checks)
+	}
+}
+
 func TestDoctor_Fix(t *testing.T) {
 	d := NewDoctor()
 
@@ -358,3 +571,39 @@ func TestFixableCheck(t *testing.T) {
 		t.Error("FixableCheck.CanFix() should return true")
 	}
 }
+
+func TestDoctor_CheckProgress(t *testing.T) {
+	d := NewDoctor()
+	d.Register(newMockCheck("first", StatusOK))
+	fixable := newMockCheck("second", StatusWarning)
+	fixable.fixable = true
+	d.Register(fixable)
+
+	var got []string
+	d.SetCheckProgress(func(p CheckProgress) {
+		if p.Total != 2 {
+			t.Errorf("Total = %d, want 2", p.Total)
+		}
+		if p.Result == nil {
+			got = append(got, "start "+p.Check)
+		} else {
+			got = append(got, "done "+p.Check+" "+p.Result.Status.String())
+		}
+	})
+
+	d.Fix(&CheckContext{TownRoot: t.TempDir()})
+	want := []string{"start first", "done first OK", "start second", "done second OK"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("progress = %v, want %v (the finished result includes the fix)", got, want)
+	}
+}
+
+func TestPrintCheck_Elapsed(t *testing.T) {
+	var buf bytes.Buffer
+	PrintCheck(&buf, &CheckResult{Name: "fast", Status: StatusOK, Message: "ok", Elapsed: 10 * time.Millisecond}, false)
+	PrintCheck(&buf, &CheckResult{Name: "slow", Status: StatusOK, Message: "ok", Elapsed: 2500 * time.Millisecond}, false)
+	out := buf.String()
+	if strings.Count(out, "s)") != 1 || !strings.Contains(out, "2.5s") {
+		t.Errorf("only the slow check should show its elapsed time:\n%s", out)
+	}
+}
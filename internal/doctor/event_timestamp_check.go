@@ -0,0 +1,194 @@
+package doctor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+// EventClockSkewTolerance is how far an event may run behind the one before
+// it, or ahead of now, before it counts as skewed. Writers take their
+// timestamp before the log lock, so small reorderings are normal.
+const EventClockSkewTolerance = time.Minute
+
+// EventTimestampCheck scans the town events log for timestamps that cannot
+// be right: in the future, well behind the events logged before them, or
+// unparseable. Seance sorts sessions and cost efficiency attributes work by
+// these timestamps, and both silently go wrong on a skewed clock or a hook
+// environment with a bad TZ. Results are grouped by actor so the offending
+// host or agent is easy to find.
+type EventTimestampCheck struct {
+	BaseCheck
+	now func() time.Time
+}
+
+// NewEventTimestampCheck creates a new event timestamp sanity check.
+func NewEventTimestampCheck() *EventTimestampCheck {
+	return &EventTimestampCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "event-timestamps",
+			CheckDescription: "Check events log for future-dated or out-of-order timestamps",
+		},
+		now: time.Now,
+	}
+}
+
+// timestampIssues counts one actor's bad timestamps.
+type timestampIssues struct {
+	future    int
+	backwards int
+	invalid   int
+	firstLine int
+	worstSkew time.Duration
+	behind    map[string]bool // Actors whose earlier events these trailed
+}
+
+// Run scans every event's timestamp.
+func (c *EventTimestampCheck) Run(ctx *CheckContext) *CheckResult {
+	file, err := os.Open(filepath.Join(ctx.TownRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &CheckResult{Name: c.Name(), Status: StatusOK, Message: "No events log yet"}
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Failed to read events log",
+			Details: []string{err.Error()},
+		}
+	}
+	defer file.Close()
+
+	byActor, checked, err := scanEventTimestamps(file, c.now())
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Failed to read events log",
+			Details: []string{err.Error()},
+		}
+	}
+
+	if len(byActor) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Event timestamps are in order (%d events)", checked),
+		}
+	}
+
+	actors := make([]string, 0, len(byActor))
+	total := 0
+	for actor, issues := range byActor {
+		actors = append(actors, actor)
+		total += issues.future + issues.backwards + issues.invalid
+	}
+	sort.Strings(actors)
+
+	var details []string
+	for _, actor := range actors {
+		issues := byActor[actor]
+		var parts []string
+		if issues.future > 0 {
+			parts = append(parts, fmt.Sprintf("%d future-dated", issues.future))
+		}
+		if issues.backwards > 0 {
+			parts = append(parts, fmt.Sprintf("%d out of order", issues.backwards))
+		}
+		if issues.invalid > 0 {
+			parts = append(parts, fmt.Sprintf("%d unparseable", issues.invalid))
+		}
+		detail := fmt.Sprintf("%s: %s (first at line %d", actor, strings.Join(parts, ", "), issues.firstLine)
+		if issues.worstSkew > 0 {
+			detail += fmt.Sprintf(", off by up to %s", issues.worstSkew.Round(time.Second))
+		}
+		if len(issues.behind) > 0 {
+			var behind []string
+			for other := range issues.behind {
+				behind = append(behind, other)
+			}
+			sort.Strings(behind)
+			detail += ", behind " + strings.Join(behind, ", ")
+		}
+		details = append(details, detail+")")
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d of %d event(s) have bad timestamps from %d actor(s)", total, checked, len(actors)),
+		Details: details,
+		FixHint: "Sync the clock (NTP) where these agents run and check their hook environment (TZ, faked time); seance order and cost attribution are unreliable until then",
+	}
+}
+
+// scanEventTimestamps checks each event against now and against the latest
+// trusted timestamp before it. An event well behind that mark is blamed on
+// its own actor, noting whose events it trailed in case that clock is the
+// fast one; future-dated events never raise the mark, so one fast clock
+// does not make every later event look out of order. Lines that are not
+// valid JSON are left to the events-integrity check.
+func scanEventTimestamps(file *os.File, now time.Time) (map[string]*timestampIssues, int, error) {
+	byActor := make(map[string]*timestampIssues)
+	record := func(actor string, line int) *timestampIssues {
+		if actor == "" {
+			actor = "(no actor)"
+		}
+		issues, ok := byActor[actor]
+		if !ok {
+			issues = &timestampIssues{firstLine: line}
+			byActor[actor] = issues
+		}
+		return issues
+	}
+
+	var latest time.Time
+	var latestActor string
+	checked, line := 0, 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line++
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		checked++
+
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			record(e.Actor, line).invalid++
+			continue
+		}
+		switch {
+		case at.After(now.Add(EventClockSkewTolerance)):
+			issues := record(e.Actor, line)
+			issues.future++
+			if skew := at.Sub(now); skew > issues.worstSkew {
+				issues.worstSkew = skew
+			}
+		case at.Before(latest.Add(-EventClockSkewTolerance)):
+			issues := record(e.Actor, line)
+			issues.backwards++
+			if skew := latest.Sub(at); skew > issues.worstSkew {
+				issues.worstSkew = skew
+			}
+			if latestActor != "" && latestActor != e.Actor {
+				if issues.behind == nil {
+					issues.behind = make(map[string]bool)
+				}
+				issues.behind[latestActor] = true
+			}
+		case at.After(latest):
+			latest, latestActor = at, e.Actor
+		}
+	}
+	return byActor, checked, scanner.Err()
+}
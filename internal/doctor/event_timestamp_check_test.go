@@ -0,0 +1,50 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestEventTimestampCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	check := NewEventTimestampCheck()
+	check.now = func() time.Time { return now }
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("missing log: Status = %v, want OK", result.Status)
+	}
+
+	line := func(at, actor string) string {
+		return fmt.Sprintf(`{"ts":%q,"type":"sling","actor":%q}`, at, actor) + "\n"
+	}
+	log := line("2025-03-01T10:00:00Z", "mayor") +
+		line("2025-03-01T09:59:30Z", "gastown/witness") + // Within tolerance
+		line("2025-03-01T13:30:00Z", "gastown/Toast") + // Future: does not raise the mark
+		line("2025-03-01T10:05:00Z", "gastown/refinery") +
+		line("2025-03-01T08:00:00Z", "gastown/Nux") + // Two hours behind the refinery
+		line("yesterday", "gastown/Nux") +
+		"{\"ts\":\n" // Corrupt: left to events-integrity
+	mustWrite(t, filepath.Join(townRoot, events.EventsFile), log)
+
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("Status = %v, want warning: %s", result.Status, result.Message)
+	}
+	if !strings.HasPrefix(result.Message, "3 of 6 event(s)") {
+		t.Errorf("Message = %q", result.Message)
+	}
+	want := []string{
+		"gastown/Nux: 1 out of order, 1 unparseable (first at line 5, off by up to 2h5m0s, behind gastown/refinery)",
+		"gastown/Toast: 1 future-dated (first at line 3, off by up to 1h30m0s)",
+	}
+	if strings.Join(result.Details, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Details =\n%s\nwant\n%s", strings.Join(result.Details, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,41 @@
+package doctor
+
+import (
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+// LogEvents records a doctor run in the town's events log so health can be
+// tracked over time: a doctor_run event with the summary, a doctor_finding
+// for each warning, error, or timeout, and a doctor_fix for each check fixed.
+// The events share a run ID (the fix run ID when fixing). Best-effort.
+func (r *Report) LogEvents(ctx *CheckContext, actor string) {
+	run := ctx.Backup.RunID()
+	if run == "" {
+		run = events.NewID()
+	}
+	log := func(eventType string, payload map[string]interface{}) {
+		_ = events.LogIn(ctx.TownRoot, eventType, actor, payload, events.VisibilityAudit)
+	}
+
+	for _, check := range r.Checks {
+		if check.Fixed {
+			log(events.TypeDoctorFix, events.DoctorFixPayload(run, check.Name, check.Message))
+		}
+		switch check.Status {
+		case StatusWarning, StatusError, StatusTimeout:
+			log(events.TypeDoctorFinding, events.DoctorFindingPayload(run, check.Name, strings.ToLower(check.Status.String()), check.Message))
+		}
+	}
+
+	log(events.TypeDoctorRun, events.DoctorRunPayload(run, ctx.RigName, ctx.Backup != nil, map[string]int{
+		"total":     r.Summary.Total,
+		"ok":        r.Summary.OK,
+		"warnings":  r.Summary.Warnings,
+		"errors":    r.Summary.Errors,
+		"timed_out": r.Summary.TimedOut,
+		"fixed":     r.Summary.Fixed,
+		"baselined": r.Summary.Baselined,
+	}))
+}
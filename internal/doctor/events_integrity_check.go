@@ -0,0 +1,89 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+// maxBadLineDetails caps how many corrupt lines are listed in the result.
+const maxBadLineDetails = 5
+
+// EventsIntegrityCheck scans the town events log (.events.jsonl) for lines
+// that are not valid JSON, such as partial writes from a crashed process.
+// Readers like seance and the feed skip these silently, so corruption hides
+// until someone wonders why events are missing. Fix moves the bad lines to
+// .events.quarantine.jsonl and rewrites the log with only valid lines.
+type EventsIntegrityCheck struct {
+	FixableCheck
+}
+
+// NewEventsIntegrityCheck creates a new events log integrity check.
+func NewEventsIntegrityCheck() *EventsIntegrityCheck {
+	return &EventsIntegrityCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "events-integrity",
+				CheckDescription: "Check events log for corrupt or truncated lines",
+			},
+		},
+	}
+}
+
+// Inputs returns the files this check depends on (see InputFingerprinter).
+func (c *EventsIntegrityCheck) Inputs(ctx *CheckContext) []string {
+	return []string{filepath.Join(ctx.TownRoot, events.EventsFile)}
+}
+
+// Run scans every line of the events log.
+func (c *EventsIntegrityCheck) Run(ctx *CheckContext) *CheckResult {
+	result, err := events.ScanLog(filepath.Join(ctx.TownRoot, events.EventsFile))
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Failed to read events log",
+			Details: []string{err.Error()},
+		}
+	}
+
+	if len(result.Bad) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Events log is clean (%d events)", result.Lines),
+		}
+	}
+
+	var details []string
+	for i, bad := range result.Bad {
+		if i == maxBadLineDetails {
+			details = append(details, fmt.Sprintf("... and %d more", len(result.Bad)-maxBadLineDetails))
+			break
+		}
+		kind := "unparseable"
+		if bad.Truncated {
+			kind = "truncated"
+		}
+		details = append(details, fmt.Sprintf("line %d: %s (%s)", bad.Number, kind, bad.Err))
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d of %d events log line(s) are corrupt", len(result.Bad), result.Lines),
+		Details: details,
+		Actions: []FixAction{doctorFix("move them to "+events.QuarantineFile, false)},
+	}
+}
+
+// Fix quarantines corrupt lines and rewrites the events log.
+func (c *EventsIntegrityCheck) Fix(ctx *CheckContext) error {
+	path := filepath.Join(ctx.TownRoot, events.EventsFile)
+	if err := ctx.Backup.Save(path); err != nil {
+		return err
+	}
+	_, err := events.QuarantineLog(path, filepath.Join(ctx.TownRoot, events.QuarantineFile))
+	return err
+}
@@ -0,0 +1,51 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestEventsIntegrityCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	path := filepath.Join(townRoot, events.EventsFile)
+	ctx := &CheckContext{TownRoot: townRoot}
+	check := NewEventsIntegrityCheck()
+
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("missing log: Status = %v, want OK", result.Status)
+	}
+
+	mustWrite(t, path, "{\"type\":\"sling\"}\n{\"type\":\"ho\n{\"type\":\"done\"}\n{\"type\":")
+	// Old enough that the unterminated last line is not an append in progress.
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("Status = %v, want warning", result.Status)
+	}
+	if len(result.Details) != 2 || !strings.HasPrefix(result.Details[0], "line 2: unparseable") ||
+		!strings.HasPrefix(result.Details[1], "line 4: truncated") {
+		t.Errorf("Details = %v", result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if got := mustRead(t, path); got != "{\"type\":\"sling\"}\n{\"type\":\"done\"}\n" {
+		t.Errorf("events log after fix = %q", got)
+	}
+	if got := mustRead(t, filepath.Join(townRoot, events.QuarantineFile)); got != "{\"type\":\"ho\n{\"type\":\n" {
+		t.Errorf("quarantine = %q", got)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after fix: Status = %v, want OK", result.Status)
+	}
+}
@@ -0,0 +1,57 @@
+package doctor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestReportLogEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	report := NewReport()
+	report.Add(&CheckResult{Name: "daemon", Status: StatusOK, Message: "running"})
+	report.Add(&CheckResult{Name: "orphan-sessions", Status: StatusOK, Message: "killed 2", Fixed: true})
+	report.Add(&CheckResult{Name: "routes", Status: StatusError, Message: "missing route"})
+
+	ctx := &CheckContext{TownRoot: townRoot, Backup: NewFixBackup(townRoot, time.Now())}
+	report.LogEvents(ctx, "overseer")
+
+	data, err := os.ReadFile(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		t.Fatalf("reading events: %v", err)
+	}
+	var got []events.Event
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e events.Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("bad event line %q: %v", line, err)
+		}
+		got = append(got, e)
+	}
+
+	var types []string
+	for _, e := range got {
+		types = append(types, e.Type)
+		if e.Payload["run"] != ctx.Backup.RunID() {
+			t.Errorf("%s run = %v, want fix run ID %s", e.Type, e.Payload["run"], ctx.Backup.RunID())
+		}
+	}
+	want := []string{events.TypeDoctorFix, events.TypeDoctorFinding, events.TypeDoctorRun}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("event types = %v, want %v", types, want)
+	}
+
+	finding := got[1].Payload
+	if finding["check"] != "routes" || finding["status"] != "error" {
+		t.Errorf("finding payload = %v", finding)
+	}
+	run := got[2].Payload
+	if run["fix"] != true || run["errors"] != float64(1) || run["fixed"] != float64(1) || run["total"] != float64(3) {
+		t.Errorf("run payload = %v", run)
+	}
+}
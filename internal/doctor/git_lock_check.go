@@ -0,0 +1,250 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// gitLockStaleAfter is how old a git lock file must be before it is
+// considered abandoned. Git holds locks for milliseconds to seconds; only a
+// large fetch or gc holds one for minutes.
+const gitLockStaleAfter = 10 * time.Minute
+
+// gitProcessDirs returns the working directories of running git processes.
+// unknown is true when processes exist but their directories cannot be
+// read (no /proc), in which case any lock may be live. A seam for tests.
+var gitProcessDirs = func(ctx context.Context) (dirs []string, unknown bool) {
+	out, err := exec.CommandContext(ctx, "ps", "-eo", "pid=,comm=").Output()
+	if err != nil {
+		return nil, true
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || filepath.Base(fields[1]) != "git" {
+			continue
+		}
+		if runtime.GOOS != "linux" {
+			return nil, true
+		}
+		cwd, err := os.Readlink(filepath.Join("/proc", fields[0], "cwd"))
+		if err != nil {
+			return nil, true
+		}
+		dirs = append(dirs, cwd)
+	}
+	return dirs, false
+}
+
+// gitLock is a git lock file found in a rig clone.
+type gitLock struct {
+	path    string
+	modTime time.Time
+}
+
+// GitLockCheck finds index.lock, HEAD.lock, ref locks, and other git lock
+// files abandoned in rig clones by agents killed mid-operation. Every git
+// command in the clone fails until they are removed.
+type GitLockCheck struct {
+	FixableCheck
+	stale []gitLock
+}
+
+// NewGitLockCheck creates a new stale git lock check.
+func NewGitLockCheck() *GitLockCheck {
+	return &GitLockCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "stale-git-locks",
+				CheckDescription: "Detect abandoned git lock files in rig clones",
+			},
+		},
+	}
+}
+
+// Run scans every rig's git directories for old lock files. A lock is
+// only stale if no git process is running anywhere in its rig.
+func (c *GitLockCheck) Run(ctx *CheckContext) *CheckResult {
+	c.stale = nil
+	now := time.Now()
+	procDirs, unknown := gitProcessDirs(ctx)
+
+	var busy []string
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		realRig := rigPath
+		if resolved, err := filepath.EvalSymlinks(rigPath); err == nil {
+			realRig = resolved // /proc reports resolved paths
+		}
+		for _, gitDir := range rigGitDirs(rigPath) {
+			for _, lock := range findGitLocks(gitDir) {
+				if now.Sub(lock.modTime) < gitLockStaleAfter {
+					continue
+				}
+				if unknown || gitRunningIn(procDirs, realRig) {
+					busy = append(busy, c.relPath(ctx, lock.path))
+					continue
+				}
+				c.stale = append(c.stale, lock)
+			}
+		}
+	}
+
+	if len(c.stale) == 0 {
+		msg := "No stale git lock files"
+		if len(busy) > 0 {
+			msg = fmt.Sprintf("%d old git lock file(s) in rigs with running git processes", len(busy))
+		}
+		return &CheckResult{Name: c.Name(), Status: StatusOK, Message: msg, Details: busy}
+	}
+
+	details := make([]string, 0, len(c.stale))
+	for _, lock := range c.stale {
+		details = append(details, fmt.Sprintf("%s (%s old)", c.relPath(ctx, lock.path), now.Sub(lock.modTime).Round(time.Minute)))
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusError,
+		Message: fmt.Sprintf("%d stale git lock file(s) blocking git in rig clones", len(c.stale)),
+		Details: details,
+		Actions: []FixAction{doctorFix("remove stale lock files", false)},
+	}
+}
+
+// Fix removes stale locks that are still present and unchanged since Run.
+func (c *GitLockCheck) Fix(ctx *CheckContext) error {
+	for _, lock := range c.stale {
+		info, err := os.Stat(lock.path)
+		if err != nil || !info.ModTime().Equal(lock.modTime) {
+			continue // Released or re-taken since Run
+		}
+		if err := ctx.Backup.Save(lock.path); err != nil {
+			return err
+		}
+		if err := os.Remove(lock.path); err != nil {
+			return fmt.Errorf("removing %s: %w", lock.path, err)
+		}
+	}
+	return nil
+}
+
+func (c *GitLockCheck) relPath(ctx *CheckContext, path string) string {
+	if rel, err := filepath.Rel(ctx.TownRoot, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// rigGitDirs returns the git directories of a rig's clones: the shared bare
+// repo and its worktrees, the mayor and refinery clones, and each crew and
+// polecat clone. Worktree .git files are followed to their git directory.
+func rigGitDirs(rigPath string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	add := func(dir string) {
+		if dir == "" || seen[dir] {
+			return
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	bare := filepath.Join(rigPath, ".repo.git")
+	add(bare)
+	if entries, err := os.ReadDir(filepath.Join(bare, "worktrees")); err == nil {
+		for _, e := range entries {
+			add(filepath.Join(bare, "worktrees", e.Name()))
+		}
+	}
+
+	clones := []string{filepath.Join(rigPath, "mayor", "rig"), filepath.Join(rigPath, "refinery", "rig")}
+	for _, parent := range []string{"crew", "polecats"} {
+		entries, err := os.ReadDir(filepath.Join(rigPath, parent))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				clones = append(clones, filepath.Join(rigPath, parent, e.Name()))
+			}
+		}
+	}
+	for _, clone := range clones {
+		add(resolveGitDir(clone))
+	}
+	return dirs
+}
+
+// resolveGitDir returns the git directory for a working tree, following a
+// "gitdir:" .git file for worktrees. Returns "" if there is none.
+func resolveGitDir(workTree string) string {
+	dotGit := filepath.Join(workTree, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return dotGit
+	}
+	data, err := os.ReadFile(dotGit) //nolint:gosec // G304: path is within the rig
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(workTree, gitDir)
+	}
+	return filepath.Clean(gitDir)
+}
+
+// findGitLocks returns the lock files directly in gitDir (index.lock,
+// HEAD.lock, config.lock, packed-refs.lock, ...) and under its refs.
+func findGitLocks(gitDir string) []gitLock {
+	var locks []gitLock
+	addLock := func(path string, info fs.FileInfo) {
+		if info.Mode().IsRegular() && strings.HasSuffix(path, ".lock") {
+			locks = append(locks, gitLock{path: path, modTime: info.ModTime()})
+		}
+	}
+
+	entries, _ := os.ReadDir(gitDir)
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil {
+			addLock(filepath.Join(gitDir, e.Name()), info)
+		}
+	}
+	_ = filepath.WalkDir(filepath.Join(gitDir, "refs"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			addLock(path, info)
+		}
+		return nil
+	})
+
+	sort.Slice(locks, func(i, j int) bool { return locks[i].path < locks[j].path })
+	return locks
+}
+
+// gitRunningIn reports whether any git process is working inside dir.
+func gitRunningIn(procDirs []string, dir string) bool {
+	for _, d := range procDirs {
+		if d == dir || strings.HasPrefix(d, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package doctor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGitLockCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	bare := filepath.Join(rigPath, ".repo.git")
+	worktreeGitDir := filepath.Join(bare, "worktrees", "Toast")
+	crewGitDir := filepath.Join(rigPath, "crew", "max", ".git")
+	polecatDir := filepath.Join(rigPath, "polecats", "Toast")
+	for _, dir := range []string{filepath.Join(bare, "refs", "heads", "polecat"), worktreeGitDir, crewGitDir, polecatDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(polecatDir, ".git"), []byte("gitdir: "+worktreeGitDir+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().Add(-time.Hour)
+	lock := func(path string, mtime time.Time) string {
+		t.Helper()
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	indexLock := lock(filepath.Join(worktreeGitDir, "index.lock"), old)
+	refLock := lock(filepath.Join(bare, "refs", "heads", "polecat", "Toast.lock"), old)
+	headLock := lock(filepath.Join(crewGitDir, "HEAD.lock"), old)
+	fresh := lock(filepath.Join(bare, "packed-refs.lock"), time.Now())
+
+	orig := gitProcessDirs
+	t.Cleanup(func() { gitProcessDirs = orig })
+	realRig, _ := filepath.EvalSymlinks(rigPath)
+	gitProcessDirs = func(context.Context) ([]string, bool) { return []string{filepath.Join(realRig, "crew", "max")}, false }
+
+	check := NewGitLockCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("git running in rig: status = %v, want OK", result.Status)
+	}
+
+	gitProcessDirs = func(context.Context) ([]string, bool) { return []string{"/elsewhere"}, false }
+	result := check.Run(ctx)
+	if result.Status != StatusError {
+		t.Fatalf("status = %v, want error", result.Status)
+	}
+	if len(result.Details) != 3 {
+		t.Errorf("details = %v, want 3 stale locks", result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	for _, path := range []string{indexLock, refLock, headLock} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s not removed", path)
+		}
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Errorf("fresh lock removed: %v", err)
+	}
+}
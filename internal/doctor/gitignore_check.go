@@ -0,0 +1,150 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// generatedIgnore is a gt-generated path that rig source repos must ignore.
+type generatedIgnore struct {
+	Probe string // representative generated file, relative to the repo root
+	Entry string // .gitignore entry appended by Fix
+}
+
+// generatedIgnores lists what gt writes into rig checkouts: Cursor rules and
+// hooks, runtime state (session IDs, locks), and the beads redirect.
+var generatedIgnores = []generatedIgnore{
+	{Probe: ".cursor/rules/gastown.mdc", Entry: ".cursor/"},
+	{Probe: ".runtime/session_id", Entry: ".runtime/"},
+	{Probe: ".beads/redirect", Entry: ".beads/redirect"},
+}
+
+// GeneratedGitignoreCheck verifies each rig's source repo .gitignore
+// excludes gt-generated files, so they are never committed and do not land
+// in every polecat worktree (the wrong-location files cursor-settings finds).
+type GeneratedGitignoreCheck struct {
+	FixableCheck
+	missing map[string][]string // .gitignore path -> entries to append, cached for Fix
+}
+
+// NewGeneratedGitignoreCheck creates a new generated-files gitignore check.
+func NewGeneratedGitignoreCheck() *GeneratedGitignoreCheck {
+	return &GeneratedGitignoreCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "generated-gitignore",
+				CheckDescription: "Check rig repos' .gitignore excludes gt-generated files",
+			},
+		},
+	}
+}
+
+// Run checks each rig's mayor/rig clone, the authoritative checkout.
+func (c *GeneratedGitignoreCheck) Run(ctx *CheckContext) *CheckResult {
+	c.missing = make(map[string][]string)
+
+	var details []string
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		repo := filepath.Join(rigPath, "mayor", "rig")
+		if _, err := os.Stat(filepath.Join(repo, ".git")); err != nil {
+			continue
+		}
+		missing := missingGeneratedIgnores(ctx, repo)
+		if len(missing) == 0 {
+			continue
+		}
+		c.missing[filepath.Join(repo, ".gitignore")] = missing
+		relPath, _ := filepath.Rel(ctx.TownRoot, repo)
+		details = append(details, fmt.Sprintf("%s/.gitignore missing: %s", relPath, strings.Join(missing, ", ")))
+	}
+
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "Rig repos ignore gt-generated files",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d rig repo(s) do not ignore gt-generated files", len(details)),
+		Details: details,
+		FixHint: "then commit .gitignore so every clone gets them",
+		Actions: []FixAction{doctorFix("append the entries", false)},
+	}
+}
+
+// Fix appends the missing entries to each .gitignore.
+func (c *GeneratedGitignoreCheck) Fix(ctx *CheckContext) error {
+	for path, entries := range c.missing {
+		if err := ctx.Backup.Save(path); err != nil {
+			return err
+		}
+		if err := appendGitignoreEntries(path, entries); err != nil {
+			return fmt.Errorf("updating %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// missingGeneratedIgnores returns the entries whose probe file is not
+// ignored by a .gitignore in repo. Ignores from .git/info/exclude or the
+// user's global excludes do not count: they do not travel with the repo.
+func missingGeneratedIgnores(ctx context.Context, repo string) []string {
+	var missing []string
+	for _, g := range generatedIgnores {
+		if !ignoredByGitignore(ctx, repo, g.Probe) {
+			missing = append(missing, g.Entry)
+		}
+	}
+	return missing
+}
+
+// ignoredByGitignore reports whether git ignores path because of a
+// .gitignore file (not a negated pattern or another exclude source).
+func ignoredByGitignore(ctx context.Context, repo, path string) bool {
+	// Output: <source>:<line>:<pattern>\t<path>; exit 1 when nothing matches.
+	out, err := exec.CommandContext(ctx, "git", "-C", repo, "check-ignore", "-v", "--no-index", path).Output() //nolint:gosec // G204: path is a fixed probe
+	if err != nil {
+		return false
+	}
+	match, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\t")
+	parts := strings.SplitN(match, ":", 3)
+	if len(parts) != 3 || strings.HasPrefix(parts[2], "!") {
+		return false
+	}
+	return filepath.Base(parts[0]) == ".gitignore"
+}
+
+// appendGitignoreEntries appends entries under a Gas Town header.
+func appendGitignoreEntries(path string, entries []string) error {
+	content, err := os.ReadFile(path) //nolint:gosec // G304: path is a rig .gitignore
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var b strings.Builder
+	if len(content) > 0 {
+		if content[len(content)-1] != '\n' {
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("# Gas Town generated files\n")
+	for _, e := range entries {
+		b.WriteString(e + "\n")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: .gitignore should be readable by git tools
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(b.String())
+	return err
+}
@@ -0,0 +1,52 @@
+package doctor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedGitignoreCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	repo := filepath.Join(townRoot, "gp", "mayor", "rig")
+	initGitRepo(t, repo)
+	if err := os.MkdirAll(filepath.Join(townRoot, "gp", "polecats"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitignore := filepath.Join(repo, ".gitignore")
+	mustWrite(t, gitignore, "node_modules/\n.cursor/")
+	// Ignored only locally: does not travel with the repo, so still missing.
+	mustWrite(t, filepath.Join(repo, ".git", "info", "exclude"), ".runtime/\n")
+
+	check := NewGeneratedGitignoreCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("status = %v, want warning", result.Status)
+	}
+	if len(result.Details) != 1 || !strings.Contains(result.Details[0], "missing: .runtime/, .beads/redirect") {
+		t.Errorf("details = %v", result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("fix: %v", err)
+	}
+	want := "node_modules/\n.cursor/\n\n# Gas Town generated files\n.runtime/\n.beads/redirect\n"
+	if got := mustRead(t, gitignore); got != want {
+		t.Errorf(".gitignore = %q, want %q", got, want)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after fix status = %v, details %v", result.Status, result.Details)
+	}
+}
+
+func TestIgnoredByGitignoreNegated(t *testing.T) {
+	repo := t.TempDir()
+	initGitRepo(t, repo)
+	mustWrite(t, filepath.Join(repo, ".gitignore"), ".cursor/*\n!.cursor/rules/\n")
+	if ignoredByGitignore(context.Background(), repo, ".cursor/rules/gastown.mdc") {
+		t.Error("negated pattern should not count as ignored")
+	}
+}
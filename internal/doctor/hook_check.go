@@ -1,6 +1,7 @@
 package doctor
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -52,7 +53,7 @@ func (c *HookAttachmentValidCheck) Run(ctx *CheckContext) *CheckResult {
 	c.invalidAttachments = append(c.invalidAttachments, townInvalid...)
 
 	// Check rig-level beads
-	rigDirs := c.findRigBeadsDirs(ctx.TownRoot)
+	rigDirs := c.findRigBeadsDirs(ctx, ctx.TownRoot)
 	for _, rigDir := range rigDirs {
 		rigName := filepath.Base(filepath.Dir(rigDir))
 		rigInvalid := c.checkBeadsDir(rigDir, rigName)
@@ -75,7 +76,8 @@ func (c *HookAttachmentValidCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusError,
 		Message: fmt.Sprintf("Found %d invalid hook attachment(s)", len(c.invalidAttachments)),
 		Details: details,
-		FixHint: "Run 'gt doctor --fix' to detach invalid molecules, or 'gt mol detach <pinned-bead-id>' manually",
+		FixHint: "or detach manually with 'gt mol detach <pinned-bead-id>'",
+		Actions: []FixAction{doctorFix("detach invalid molecules", false)},
 	}
 }
 
@@ -129,12 +131,12 @@ func (c *HookAttachmentValidCheck) checkBeadsDir(beadsDir, _ string) []invalidAt
 }
 
 // findRigBeadsDirs finds all rig-level .beads directories.
-func (c *HookAttachmentValidCheck) findRigBeadsDirs(townRoot string) []string {
+func (c *HookAttachmentValidCheck) findRigBeadsDirs(ctx context.Context, townRoot string) []string {
 	var dirs []string
 
 	// Look for .beads directories in rig subdirectories
 	// Pattern: <townRoot>/<rig>/.beads (but NOT <townRoot>/.beads which is town-level)
-	cmd := exec.Command("find", townRoot, "-maxdepth", "2", "-type", "d", "-name", ".beads")
+	cmd := exec.CommandContext(ctx, "find", townRoot, "-maxdepth", "2", "-type", "d", "-name", ".beads")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil
@@ -228,7 +230,7 @@ func (c *HookSingletonCheck) Run(ctx *CheckContext) *CheckResult {
 
 	// Check rig-level beads using the shared helper
 	attachCheck := &HookAttachmentValidCheck{}
-	rigDirs := attachCheck.findRigBeadsDirs(ctx.TownRoot)
+	rigDirs := attachCheck.findRigBeadsDirs(ctx, ctx.TownRoot)
 	for _, rigDir := range rigDirs {
 		rigDups := c.checkBeadsDir(rigDir)
 		for _, dup := range rigDups {
@@ -255,7 +257,8 @@ func (c *HookSingletonCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusError,
 		Message: fmt.Sprintf("Found %d duplicate handoff bead(s)", totalDups),
 		Details: details,
-		FixHint: "Run 'gt doctor --fix' to close duplicates, or 'bd close <id>' manually",
+		FixHint: "or close manually with 'bd close <id>'",
+		Actions: []FixAction{doctorFix("close duplicates", true)},
 	}
 }
 
@@ -366,7 +369,7 @@ func (c *OrphanedAttachmentsCheck) Run(ctx *CheckContext) *CheckResult {
 
 	// Check rig-level beads using the shared helper
 	attachCheck := &HookAttachmentValidCheck{}
-	rigDirs := attachCheck.findRigBeadsDirs(ctx.TownRoot)
+	rigDirs := attachCheck.findRigBeadsDirs(ctx, ctx.TownRoot)
 	for _, rigDir := range rigDirs {
 		rigOrphans := c.checkBeadsDir(rigDir, ctx.TownRoot)
 		for _, orph := range rigOrphans {
@@ -1,6 +1,7 @@
 package doctor
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -104,7 +105,7 @@ func TestHookAttachmentValidCheck_FindRigBeadsDirs(t *testing.T) {
 	}
 
 	check := NewHookAttachmentValidCheck()
-	dirs := check.findRigBeadsDirs(tmpDir)
+	dirs := check.findRigBeadsDirs(context.Background(), tmpDir)
 
 	// Should find the rig-level beads but not town-level
 	found := false
@@ -0,0 +1,125 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+)
+
+// HookVersionCheck flags agent workspaces whose hooks.json was generated by a different gt version than the running binary.
+type HookVersionCheck struct {
+	FixableCheck
+	stale []daemon.TemplateTarget
+}
+
+// NewHookVersionCheck creates a new hook version check.
+func NewHookVersionCheck() *HookVersionCheck {
+	return &HookVersionCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "hook-version",
+				CheckDescription: "Check agent hooks were generated by this gt version",
+			},
+		},
+	}
+}
+
+// Run compares version markers in installed hooks against this binary.
+func (c *HookVersionCheck) Run(ctx *CheckContext) *CheckResult {
+	c.stale = nil
+
+	var rigs []string
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		rigs = append(rigs, filepath.Base(rigPath))
+	}
+
+	var details []string
+	for _, t := range daemon.TemplateTargets(ctx.TownRoot, rigs) {
+		if !cursor.HooksInstalled(t.WorkDir) {
+			continue
+		}
+		stale := cursor.StaleHookVersions(t.WorkDir)
+		if len(stale) == 0 {
+			continue
+		}
+		c.stale = append(c.stale, t)
+		details = append(details, fmt.Sprintf("%s: %s", t.Agent, describeHookVersions(stale)))
+	}
+
+	if len(c.stale) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("All agent hooks generated by gt %s", cursor.GeneratorVersion),
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d agent workspace(s) have hooks from another gt version (current %s)", len(c.stale), cursor.GeneratorVersion),
+		Details: details,
+		Actions: []FixAction{doctorFix("regenerate hooks", false), {Command: "gt", Args: []string{"doctor", "--fix", "--restart-sessions"}, Description: "also cycle patrol agents onto them"}},
+	}
+}
+
+// Fix regenerates stale hooks. With --restart-sessions, running patrol
+// sessions are cycled so they load the new hooks; the daemon restarts them.
+func (c *HookVersionCheck) Fix(ctx *CheckContext) error {
+	t := tmux.NewTmux()
+	for i, target := range c.stale {
+		if err := ctx.Step(i, len(c.stale), target.Agent); err != nil {
+			return err
+		}
+		cursorDir := filepath.Join(target.WorkDir, ".cursor")
+		if err := ctx.Backup.Save(filepath.Join(cursorDir, "hooks.json")); err != nil {
+			return err
+		}
+		if err := ctx.Backup.Save(filepath.Join(cursorDir, "hooks")); err != nil {
+			return err
+		}
+		if err := cursor.EnsureHooksForRole(target.WorkDir, target.Role); err != nil {
+			return fmt.Errorf("regenerating hooks for %s: %w", target.Agent, err)
+		}
+
+		if ctx.RestartSessions && target.Session != "" {
+			if running, _ := t.HasSession(target.Session); running {
+				if t.KillSession(target.Session) == nil {
+					ctx.Record(ActionSessionKilled, target.Session)
+				}
+			}
+		}
+	}
+	return ctx.Step(len(c.stale), len(c.stale), "")
+}
+
+// describeHookVersions summarizes which versions generated the stale files,
+// e.g. "hooks.json from gt 0.1.0".
+func describeHookVersions(stale map[string]string) string {
+	byVersion := make(map[string][]string)
+	for name, v := range stale {
+		if v == "" {
+			v = "unknown (pre-marker)"
+		}
+		byVersion[v] = append(byVersion[v], name)
+	}
+
+	versions := make([]string, 0, len(byVersion))
+	for v := range byVersion {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+
+	parts := make([]string, 0, len(versions))
+	for _, v := range versions {
+		files := byVersion[v]
+		sort.Strings(files)
+		parts = append(parts, fmt.Sprintf("%s from gt %s", strings.Join(files, ", "), v))
+	}
+	return strings.Join(parts, "; ")
+}
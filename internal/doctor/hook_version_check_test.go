@@ -0,0 +1,43 @@
+package doctor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+)
+
+func TestHookVersionCheck(t *testing.T) {
+	orig := cursor.GeneratorVersion
+	t.Cleanup(func() { cursor.GeneratorVersion = orig })
+
+	townRoot := t.TempDir()
+	refineryDir := filepath.Join(townRoot, "gastown", "refinery")
+
+	cursor.GeneratorVersion = "0.1.0"
+	if err := cursor.EnsureHooks(refineryDir); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewHookVersionCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("same version: status = %v, want OK", result.Status)
+	}
+
+	cursor.GeneratorVersion = "0.2.0"
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("older hooks: status = %v, want warning", result.Status)
+	}
+	if len(result.Details) != 1 || result.Details[0] != "gastown/refinery: hooks.json from gt 0.1.0" {
+		t.Errorf("details = %v", result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if stale := cursor.StaleHookVersions(refineryDir); len(stale) != 0 {
+		t.Errorf("after Fix: stale = %v", stale)
+	}
+}
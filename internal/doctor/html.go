@@ -0,0 +1,151 @@
+package doctor
+
+import (
+	"html/template"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HTMLHistoryLimit is how many recent fix journal entries an HTML report shows.
+const HTMLHistoryLimit = 50
+
+// HTMLTown is one town's doctor results for an HTML report.
+type HTMLTown struct {
+	Report   *Report
+	TownRoot string
+	History  []JournalEntry // Fix journal entries, oldest first (see ReadJournal)
+}
+
+// htmlCheck is a check result prepared for the template.
+type htmlCheck struct {
+	*CheckResult
+	Class string // CSS class for the status: ok, warning, error, timeout, blocked
+}
+
+type htmlTown struct {
+	Root    string
+	Summary ReportSummary
+	Checks  []htmlCheck
+	NotRun  []string
+	History []htmlJournalEntry
+}
+
+type htmlJournalEntry struct {
+	JournalEntry
+	Target string // Town-relative when under the town root
+}
+
+type htmlPage struct {
+	Version   string
+	Generated time.Time
+	Towns     []htmlTown
+}
+
+// WriteHTML writes the report as a self-contained HTML page (inline styles,
+// no scripts or external assets) for sharing with people who cannot run
+// gt doctor themselves. history is the town's fix journal.
+func (r *Report) WriteHTML(w io.Writer, townRoot string, history []JournalEntry, version string) error {
+	return WriteHTMLTowns(w, []HTMLTown{{Report: r, TownRoot: townRoot, History: history}}, version)
+}
+
+// WriteHTMLTowns writes one HTML page with a section per town.
+func WriteHTMLTowns(w io.Writer, towns []HTMLTown, version string) error {
+	page := htmlPage{Version: version, Generated: time.Now()}
+	for _, town := range towns {
+		t := htmlTown{Root: town.TownRoot, Summary: town.Report.Summary, NotRun: town.Report.NotRun}
+		for _, c := range town.Report.Checks {
+			t.Checks = append(t.Checks, htmlCheck{CheckResult: c, Class: strings.ToLower(c.Status.String())})
+		}
+		// Newest first, limited
+		for i := len(town.History) - 1; i >= 0 && len(t.History) < HTMLHistoryLimit; i-- {
+			e := town.History[i]
+			target := e.Target
+			if rel, err := filepath.Rel(town.TownRoot, e.Target); err == nil && filepath.IsAbs(e.Target) && !strings.HasPrefix(rel, "..") {
+				target = rel
+			}
+			t.History = append(t.History, htmlJournalEntry{JournalEntry: e, Target: target})
+		}
+		page.Towns = append(page.Towns, t)
+	}
+	return htmlReportTemplate.Execute(w, page)
+}
+
+var htmlReportTemplate = template.Must(template.New("doctor").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>gt doctor report</title>
+<style>
+body { font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 60em; padding: 0 1em; color: #1f2328; }
+h1 { font-size: 1.5em; margin-bottom: 0; }
+h2 { font-size: 1.2em; margin-top: 2em; border-bottom: 1px solid #d0d7de; padding-bottom: .3em; }
+h3 { font-size: 1em; margin-top: 1.5em; }
+.meta, .dim { color: #656d76; }
+.summary span { margin-right: 1em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; vertical-align: top; padding: .35em .6em; border-bottom: 1px solid #eaeef2; }
+th { font-weight: 600; background: #f6f8fa; }
+.status { font-weight: 600; white-space: nowrap; }
+.ok .status { color: #1a7f37; }
+.warning .status { color: #9a6700; }
+.error .status { color: #cf222e; }
+.timeout .status { color: #8250df; }
+.blocked .status { color: #656d76; }
+ul { margin: .3em 0; padding-left: 1.2em; }
+code { font: 12px ui-monospace, SFMono-Regular, Menlo, monospace; background: #f6f8fa; padding: .1em .3em; border-radius: 4px; }
+.tag { font-size: 11px; border: 1px solid #d0d7de; border-radius: 1em; padding: 0 .5em; margin-left: .4em; color: #656d76; }
+.destructive { color: #cf222e; border-color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>gt doctor report</h1>
+<p class="meta">Generated {{time .Generated}}{{if .Version}} by gt {{.Version}}{{end}}</p>
+{{range .Towns}}
+<h2>{{.Root}}</h2>
+<p class="summary">
+<span>{{.Summary.Total}} checks</span>
+<span class="ok"><span class="status">{{.Summary.OK}} passed</span></span>
+{{- if .Summary.Warnings}}<span class="warning"><span class="status">{{.Summary.Warnings}} warnings</span></span>{{end}}
+{{- if .Summary.Errors}}<span class="error"><span class="status">{{.Summary.Errors}} errors</span></span>{{end}}
+{{- if .Summary.TimedOut}}<span class="timeout"><span class="status">{{.Summary.TimedOut}} timed out</span></span>{{end}}
+{{- if .Summary.Blocked}}<span class="blocked"><span class="status">{{.Summary.Blocked}} blocked</span></span>{{end}}
+{{- if .Summary.Fixed}}<span>{{.Summary.Fixed}} fixed</span>{{end}}
+{{- if .Summary.Baselined}}<span>{{.Summary.Baselined}} baselined</span>{{end}}
+</p>
+<table>
+<tr><th>Status</th><th>Check</th><th>Result</th></tr>
+{{- range .Checks}}
+<tr class="{{.Class}}">
+<td class="status">{{.Status}}</td>
+<td><code>{{.Name}}</code>{{if .Fixed}}<span class="tag">fixed</span>{{end}}{{if .Cached}}<span class="tag">cached</span>{{end}}{{if .Baselined}}<span class="tag">baselined</span>{{end}}</td>
+<td>{{.Message}}
+{{- if .Details}}<ul>{{range .Details}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{- if .Actions}}<div>Fix: {{range $i, $a := .Actions}}{{if $i}} or {{end}}<code>{{$a.String}}</code>{{if $a.Description}} to {{$a.Description}}{{end}}{{if $a.Destructive}}<span class="tag destructive">destructive</span>{{end}}{{end}}</div>{{end}}
+{{- if .FixHint}}<div class="dim">{{.FixHint}}</div>{{end}}
+</td>
+</tr>
+{{- end}}
+</table>
+{{- if .NotRun}}
+<p class="dim">Not run (interrupted): {{range $i, $n := .NotRun}}{{if $i}}, {{end}}{{$n}}{{end}}</p>
+{{- end}}
+<h3>Fix history</h3>
+{{- if .History}}
+<table>
+<tr><th>ID</th><th>Time</th><th>Action</th><th>Target</th><th>Check</th></tr>
+{{- range .History}}
+<tr><td>{{.ID}}</td><td class="dim">{{time .Time}}</td><td>{{.Action}}{{if .UndoneAt}}<span class="tag">undone</span>{{end}}</td><td><code>{{.Target}}</code></td><td>{{.Check}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p class="dim">No fix actions recorded.</p>
+{{- end}}
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,59 @@
+package doctor
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportWriteHTML(t *testing.T) {
+	townRoot := t.TempDir()
+	report := NewReport()
+	report.Add(&CheckResult{
+		Name:    "orphan-sessions",
+		Status:  StatusWarning,
+		Message: "1 orphaned session",
+		Details: []string{"gt-<script>alert(1)</script>"},
+		Actions: []FixAction{doctorFix("kill them", true)},
+	})
+	report.Add(&CheckResult{Name: "daemon", Status: StatusOK, Message: "daemon running", Fixed: true})
+
+	undone := time.Now()
+	history := []JournalEntry{
+		{ID: 1, Time: time.Now(), Check: "orphan-sessions", Action: ActionSessionKilled, Target: "gt-old"},
+		{ID: 2, Time: time.Now(), Check: "config", Action: ActionFileDeleted, Target: filepath.Join(townRoot, "mayor", "stale.json"), UndoneAt: &undone},
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteHTML(&buf, townRoot, history, "1.2.3"); err != nil {
+		t.Fatalf("WriteHTML: %v", err)
+	}
+	page := buf.String()
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"gt 1.2.3",
+		"orphan-sessions",
+		"<code>gt doctor --fix</code> to kill them",
+		">destructive<",
+		"mayor/stale.json", // journal targets are town-relative
+		">undone<",
+		"1 warnings",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+	if strings.Contains(page, "<script>") {
+		t.Error("details must be HTML-escaped")
+	}
+	if strings.Contains(page, "<link") || strings.Contains(page, "src=") {
+		t.Error("page should be self-contained")
+	}
+	// Newest history entry first
+	if strings.Index(page, "stale.json") > strings.Index(page, "gt-old") {
+		t.Error("history should be newest first")
+	}
+}
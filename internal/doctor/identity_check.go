@@ -2,7 +2,6 @@ package doctor
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/cursorworkshop/cursor-gastown/internal/lock"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
@@ -130,7 +129,7 @@ func (c *IdentityCollisionCheck) Run(ctx *CheckContext) *CheckResult {
 		for _, s := range staleLocks {
 			result.Details = append(result.Details, "  "+s)
 		}
-		result.FixHint = "Run 'gt doctor --fix' or 'gt agents fix' to clean up"
+		result.Actions = []FixAction{doctorFix("clean up", false), {Command: "gt", Args: []string{"agents", "fix"}, Description: "clean up"}}
 	}
 
 	if len(orphanedLocks) > 0 {
@@ -145,8 +144,8 @@ func (c *IdentityCollisionCheck) Run(ctx *CheckContext) *CheckResult {
 		for _, s := range orphanedLocks {
 			result.Details = append(result.Details, "  "+s)
 		}
-		if !strings.Contains(result.FixHint, "doctor") {
-			result.FixHint = "Run 'gt doctor --fix' to clean up stale locks"
+		if len(result.Actions) == 0 {
+			result.Actions = []FixAction{doctorFix("clean up stale locks", false)}
 		}
 	}
 
@@ -0,0 +1,296 @@
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// JournalFile is the town-level fix journal, relative to the town root.
+const JournalFile = ".runtime/doctor-journal.jsonl"
+
+// Fix journal actions.
+const (
+	ActionFileDeleted   = "file-deleted"
+	ActionFileCreated   = "file-created"
+	ActionFileModified  = "file-modified"
+	ActionSessionKilled = "session-killed"
+	ActionProcessKilled = "process-killed"
+	ActionDirRemoved    = "dir-removed"
+	ActionBranchCreated = "branch-created"
+
+	// actionUndo records that an earlier entry was undone.
+	actionUndo = "undo"
+)
+
+// ErrNotReversible is returned when undoing an action that cannot be undone,
+// such as a killed session.
+var ErrNotReversible = errors.New("action cannot be undone")
+
+// JournalEntry is one action taken by a 'gt doctor --fix' run.
+type JournalEntry struct {
+	ID     int       `json:"id"`
+	Time   time.Time `json:"ts"`
+	RunID  string    `json:"run_id,omitempty"` // Fix run (see 'gt doctor rollback')
+	Check  string    `json:"check,omitempty"`  // Check whose fix took the action
+	Action string    `json:"action"`
+	Target string    `json:"target"`           // Absolute path, session name, or PID
+	Stored string    `json:"stored,omitempty"` // Backup copy inside the run's backup dir
+
+	// Undoes is set on undo records: the ID of the entry undone.
+	Undoes int `json:"undoes,omitempty"`
+
+	// UndoneAt is filled in by ReadJournal for entries that were undone
+	// individually or by rolling back their run.
+	UndoneAt *time.Time `json:"undone_at,omitempty"`
+}
+
+// Reversible reports whether 'gt doctor undo' can reverse the entry.
+func (e *JournalEntry) Reversible() bool {
+	switch e.Action {
+	case ActionFileDeleted, ActionFileCreated, ActionFileModified:
+		return true
+	}
+	return false
+}
+
+// FixJournal appends fix actions to the town journal so destructive fixes
+// are auditable. A nil *FixJournal is valid and records nothing.
+type FixJournal struct {
+	path   string
+	runID  string
+	nextID int
+	err    error
+}
+
+// OpenFixJournal opens the town journal for a fix run. Nothing is written
+// until the first action is recorded.
+func OpenFixJournal(townRoot, runID string) *FixJournal {
+	j := &FixJournal{path: filepath.Join(townRoot, JournalFile), runID: runID, nextID: 1}
+	entries, err := readJournalFile(j.path)
+	if err != nil {
+		j.err = err
+	}
+	for _, e := range entries {
+		if e.ID >= j.nextID {
+			j.nextID = e.ID + 1
+		}
+	}
+	return j
+}
+
+// Err returns the first error hit while reading or writing the journal.
+func (j *FixJournal) Err() error {
+	if j == nil {
+		return nil
+	}
+	return j.err
+}
+
+// record appends an entry, assigning its ID and run.
+func (j *FixJournal) record(e JournalEntry) {
+	if j == nil {
+		return
+	}
+	e.ID = j.nextID
+	e.RunID = j.runID
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if err := appendJournal(j.path, e); err != nil {
+		if j.err == nil {
+			j.err = err
+		}
+		return
+	}
+	j.nextID++
+}
+
+// Record journals a non-file action (a killed session or process) taken by
+// the running fix. File changes are journaled automatically from the backup.
+func (ctx *CheckContext) Record(action, target string) {
+	if ctx == nil {
+		return
+	}
+	ctx.Journal.record(JournalEntry{Check: ctx.fixing, Action: action, Target: target})
+}
+
+// journalBackups journals the paths the running fix captured since the
+// backup held mark entries, classifying each by comparing the backup with
+// what is now on disk.
+func (ctx *CheckContext) journalBackups(mark int) {
+	if ctx.Journal == nil || ctx.Backup == nil {
+		return
+	}
+	for _, b := range ctx.Backup.manifest.Entries[mark:] {
+		_, statErr := os.Lstat(b.Path)
+		exists := statErr == nil
+
+		var action string
+		switch {
+		case b.Stored == "" && exists:
+			action = ActionFileCreated
+		case b.Stored != "" && !exists:
+			action = ActionFileDeleted
+		case b.Stored != "" && !sameFile(filepath.Join(ctx.Backup.dir, b.Stored), b.Path):
+			action = ActionFileModified
+		default:
+			continue // captured but left unchanged
+		}
+		ctx.Journal.record(JournalEntry{Check: ctx.fixing, Action: action, Target: b.Path, Stored: b.Stored})
+	}
+}
+
+// sameFile reports whether two regular files have identical contents.
+// Directories and symlinks are never considered the same.
+func sameFile(a, b string) bool {
+	ai, err := os.Lstat(a)
+	if err != nil || !ai.Mode().IsRegular() {
+		return false
+	}
+	bi, err := os.Lstat(b)
+	if err != nil || !bi.Mode().IsRegular() || ai.Size() != bi.Size() {
+		return false
+	}
+	ad, err := os.ReadFile(a) //nolint:gosec // G304: path is a doctor backup
+	if err != nil {
+		return false
+	}
+	bd, err := os.ReadFile(b) //nolint:gosec // G304: path was captured by a fixer
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ad, bd)
+}
+
+// ReadJournal returns the journaled fix actions, oldest first, with
+// UndoneAt set for entries that were undone or whose run was rolled back.
+func ReadJournal(townRoot string) ([]JournalEntry, error) {
+	raw, err := readJournalFile(filepath.Join(townRoot, JournalFile))
+	if err != nil {
+		return nil, err
+	}
+
+	rolledBack := make(map[string]*time.Time)
+	if manifests, err := ListBackups(townRoot); err == nil {
+		for _, m := range manifests {
+			rolledBack[m.RunID] = m.RolledBackAt
+		}
+	}
+
+	var entries []JournalEntry
+	index := make(map[int]int)
+	for _, e := range raw {
+		if e.Action == actionUndo {
+			if i, ok := index[e.Undoes]; ok {
+				at := e.Time
+				entries[i].UndoneAt = &at
+			}
+			continue
+		}
+		if at := rolledBack[e.RunID]; at != nil && e.Reversible() {
+			e.UndoneAt = at
+		}
+		index[e.ID] = len(entries)
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Undo reverses a single journaled file action using the backup taken by
+// its fix run: deleted and modified paths are restored, created paths are
+// removed. Killed sessions and processes return ErrNotReversible.
+func Undo(townRoot string, id int) (*JournalEntry, error) {
+	entries, err := ReadJournal(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("reading fix journal: %w", err)
+	}
+
+	var entry *JournalEntry
+	for i := range entries {
+		if entries[i].ID == id {
+			entry = &entries[i]
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("fix journal entry %d not found", id)
+	}
+	if !entry.Reversible() {
+		return entry, fmt.Errorf("%s %s: %w", entry.Action, entry.Target, ErrNotReversible)
+	}
+	if entry.UndoneAt != nil {
+		return entry, fmt.Errorf("entry %d was already undone at %s", id, entry.UndoneAt.Local().Format("2006-01-02 15:04:05"))
+	}
+	for _, later := range entries {
+		if later.ID > id && later.Target == entry.Target && later.Reversible() && later.UndoneAt == nil {
+			return entry, fmt.Errorf("entry %d also changed %s; undo it first", later.ID, entry.Target)
+		}
+	}
+
+	if err := os.RemoveAll(entry.Target); err != nil {
+		return entry, fmt.Errorf("removing %s: %w", entry.Target, err)
+	}
+	if entry.Action != ActionFileCreated {
+		stored := filepath.Join(townRoot, BackupsDir, entry.RunID, entry.Stored)
+		if err := copyTree(stored, entry.Target); err != nil {
+			return entry, fmt.Errorf("restoring %s: %w", entry.Target, err)
+		}
+	}
+
+	j := OpenFixJournal(townRoot, entry.RunID)
+	j.record(JournalEntry{Action: actionUndo, Target: entry.Target, Undoes: id})
+	if err := j.Err(); err != nil {
+		return entry, fmt.Errorf("recording undo: %w", err)
+	}
+	now := time.Now()
+	entry.UndoneAt = &now
+	return entry, nil
+}
+
+func readJournalFile(path string) ([]JournalEntry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the town journal
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e JournalEntry
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue // Skip torn or foreign lines
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+func appendJournal(path string, e JournalEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path is the town journal
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
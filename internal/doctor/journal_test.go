@@ -0,0 +1,196 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fileFixCheck is a fixable check whose fix deletes, creates, and
+// overwrites files and kills a session.
+type fileFixCheck struct {
+	FixableCheck
+	deleted, created, modified, untouched string
+	fixed                                 bool
+}
+
+func (c *fileFixCheck) Run(ctx *CheckContext) *CheckResult {
+	if c.fixed {
+		return &CheckResult{Name: c.CheckName, Status: StatusOK}
+	}
+	return &CheckResult{Name: c.CheckName, Status: StatusWarning}
+}
+
+func (c *fileFixCheck) Fix(ctx *CheckContext) error {
+	for _, p := range []string{c.deleted, c.created, c.modified, c.untouched} {
+		if err := ctx.Backup.Save(p); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(c.deleted); err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.created, []byte("new"), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.modified, []byte("after"), 0644); err != nil {
+		return err
+	}
+	ctx.Record(ActionSessionKilled, "gt-gastown-witness")
+	c.fixed = true
+	return nil
+}
+
+func TestFixJournalRecordsAndUndoes(t *testing.T) {
+	townRoot := t.TempDir()
+	check := &fileFixCheck{
+		FixableCheck: FixableCheck{BaseCheck{CheckName: "files"}},
+		deleted:      filepath.Join(townRoot, "deleted.json"),
+		created:      filepath.Join(townRoot, "created.json"),
+		modified:     filepath.Join(townRoot, "modified.json"),
+		untouched:    filepath.Join(townRoot, "untouched.json"),
+	}
+	mustWrite(t, check.deleted, "old")
+	mustWrite(t, check.modified, "before")
+	mustWrite(t, check.untouched, "same")
+
+	backup := NewFixBackup(townRoot, time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	ctx := &CheckContext{
+		TownRoot: townRoot,
+		Backup:   backup,
+		Journal:  OpenFixJournal(townRoot, backup.RunID()),
+	}
+	d := NewDoctor()
+	d.Register(check)
+	d.Fix(ctx)
+	if err := ctx.Journal.Err(); err != nil {
+		t.Fatalf("journal: %v", err)
+	}
+
+	entries, err := ReadJournal(townRoot)
+	if err != nil {
+		t.Fatalf("ReadJournal: %v", err)
+	}
+	want := []struct{ action, target string }{
+		{ActionSessionKilled, "gt-gastown-witness"},
+		{ActionFileDeleted, check.deleted},
+		{ActionFileCreated, check.created},
+		{ActionFileModified, check.modified},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.ID != i+1 || e.Action != w.action || e.Target != w.target || e.Check != "files" || e.RunID != "20260101-120000" {
+			t.Errorf("entry %d = %+v, want %s %s", i, e, w.action, w.target)
+		}
+	}
+
+	// Undo each file action individually.
+	for _, id := range []int{2, 3, 4} {
+		if _, err := Undo(townRoot, id); err != nil {
+			t.Fatalf("Undo(%d): %v", id, err)
+		}
+	}
+	if got := mustRead(t, check.deleted); got != "old" {
+		t.Errorf("deleted file not restored: %q", got)
+	}
+	if _, err := os.Stat(check.created); !os.IsNotExist(err) {
+		t.Errorf("created file should be removed, stat err = %v", err)
+	}
+	if got := mustRead(t, check.modified); got != "before" {
+		t.Errorf("modified file not restored: %q", got)
+	}
+
+	if _, err := Undo(townRoot, 1); !errors.Is(err, ErrNotReversible) {
+		t.Errorf("Undo(session) err = %v, want ErrNotReversible", err)
+	}
+	if _, err := Undo(townRoot, 2); err == nil {
+		t.Error("expected error undoing an entry twice")
+	}
+	if _, err := Undo(townRoot, 99); err == nil {
+		t.Error("expected error for unknown entry")
+	}
+
+	entries, _ = ReadJournal(townRoot)
+	for _, e := range entries {
+		if (e.UndoneAt != nil) != e.Reversible() {
+			t.Errorf("entry %d (%s) UndoneAt = %v", e.ID, e.Action, e.UndoneAt)
+		}
+	}
+
+	// New runs continue the ID sequence (undo records take IDs 5-7).
+	j := OpenFixJournal(townRoot, "later")
+	j.record(JournalEntry{Action: ActionProcessKilled, Target: "1234"})
+	entries, _ = ReadJournal(townRoot)
+	if last := entries[len(entries)-1]; last.ID != 8 || last.RunID != "later" {
+		t.Errorf("next entry = %+v, want ID 8 in run later", last)
+	}
+}
+
+func TestUndoRefusesWhenLaterEntryChangedPath(t *testing.T) {
+	townRoot := t.TempDir()
+	target := filepath.Join(townRoot, "settings.json")
+	mustWrite(t, target, "v1")
+
+	for i, content := range []string{"v2", "v3"} {
+		backup := NewFixBackup(townRoot, time.Date(2026, 1, 1, 12, i, 0, 0, time.UTC))
+		ctx := &CheckContext{TownRoot: townRoot, Backup: backup, Journal: OpenFixJournal(townRoot, backup.RunID())}
+		if err := backup.Save(target); err != nil {
+			t.Fatal(err)
+		}
+		mustWrite(t, target, content)
+		ctx.journalBackups(0)
+	}
+
+	if _, err := Undo(townRoot, 1); err == nil {
+		t.Fatal("expected Undo(1) to refuse while entry 2 is in effect")
+	}
+	if _, err := Undo(townRoot, 2); err != nil {
+		t.Fatalf("Undo(2): %v", err)
+	}
+	if _, err := Undo(townRoot, 1); err != nil {
+		t.Fatalf("Undo(1): %v", err)
+	}
+	if got := mustRead(t, target); got != "v1" {
+		t.Errorf("content = %q, want v1", got)
+	}
+}
+
+func TestRollbackMarksJournalEntriesUndone(t *testing.T) {
+	townRoot := t.TempDir()
+	target := filepath.Join(townRoot, "rigs.json")
+	mustWrite(t, target, "v1")
+
+	backup := NewFixBackup(townRoot, time.Now())
+	ctx := &CheckContext{TownRoot: townRoot, Backup: backup, Journal: OpenFixJournal(townRoot, backup.RunID())}
+	if err := backup.Save(target); err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, target, "v2")
+	ctx.journalBackups(0)
+
+	if _, err := Rollback(townRoot, backup.RunID()); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	entries, _ := ReadJournal(townRoot)
+	if len(entries) != 1 || entries[0].UndoneAt == nil {
+		t.Fatalf("entries = %+v, want one undone entry", entries)
+	}
+	if _, err := Undo(townRoot, 1); err == nil {
+		t.Error("expected Undo to refuse an entry from a rolled-back run")
+	}
+}
+
+func TestFixJournalNil(t *testing.T) {
+	var ctx *CheckContext
+	ctx.Record(ActionSessionKilled, "x")
+	(&CheckContext{}).Record(ActionSessionKilled, "x")
+	var j *FixJournal
+	if j.Err() != nil {
+		t.Error("nil journal should have no error")
+	}
+}
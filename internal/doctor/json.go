@@ -0,0 +1,82 @@
+package doctor
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// jsonReport is the --format json output for one town.
+type jsonReport struct {
+	TownRoot    string        `json:"town_root"`
+	Timestamp   time.Time     `json:"timestamp"`
+	Checks      []jsonCheck   `json:"checks"`
+	Summary     ReportSummary `json:"summary"`
+	Interrupted bool          `json:"interrupted,omitempty"`
+	NotRun      []string      `json:"not_run,omitempty"`
+}
+
+type jsonCheck struct {
+	Name    string      `json:"name"`
+	Status  string      `json:"status"` // ok, warning, error, timeout, blocked
+	Message string      `json:"message"`
+	Details []string    `json:"details,omitempty"`
+	FixHint string      `json:"fix_hint,omitempty"`
+	Actions []FixAction `json:"actions,omitempty"`
+	Cached  bool        `json:"cached,omitempty"`
+	Fixed   bool        `json:"fixed,omitempty"`
+
+	Baselined bool `json:"baselined,omitempty"`
+}
+
+// JSONTown is one town's doctor results for a combined JSON report.
+type JSONTown struct {
+	Report   *Report
+	TownRoot string
+}
+
+// WriteJSON writes the report as JSON. Each result carries its structured
+// Actions, so tools can apply suggested fixes without parsing hint prose.
+func (r *Report) WriteJSON(w io.Writer, townRoot string) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.jsonReport(townRoot))
+}
+
+// WriteJSONTowns writes a JSON array with one report per town.
+func WriteJSONTowns(w io.Writer, towns []JSONTown) error {
+	reports := make([]jsonReport, 0, len(towns))
+	for _, town := range towns {
+		reports = append(reports, town.Report.jsonReport(town.TownRoot))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
+
+func (r *Report) jsonReport(townRoot string) jsonReport {
+	out := jsonReport{
+		TownRoot:    townRoot,
+		Timestamp:   r.Timestamp,
+		Checks:      make([]jsonCheck, 0, len(r.Checks)),
+		Summary:     r.Summary,
+		Interrupted: r.Interrupted,
+		NotRun:      r.NotRun,
+	}
+	for _, c := range r.Checks {
+		out.Checks = append(out.Checks, jsonCheck{
+			Name:    c.Name,
+			Status:  strings.ToLower(c.Status.String()),
+			Message: c.Message,
+			Details: c.Details,
+			FixHint: c.FixHint,
+			Actions: c.Actions,
+			Cached:  c.Cached,
+			Fixed:   c.Fixed,
+
+			Baselined: c.Baselined,
+		})
+	}
+	return out
+}
@@ -0,0 +1,271 @@
+package doctor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+)
+
+// layoutMigration is one file from a historical town layout and where it
+// belongs in the current layout.
+type layoutMigration struct {
+	layout string // Historical layout the file comes from
+	from   string
+	to     string
+
+	// regenerate marks settings that cannot be translated (Claude hooks in
+	// .claude/settings.json). Instead of moving the file, current Cursor
+	// settings are installed for role in dir and the old file is retired.
+	regenerate bool
+	role       string
+	dir        string
+
+	conflict bool // to exists with different content; needs a manual merge
+}
+
+// Historical town layouts recognized by LayoutMigrationCheck.
+const (
+	// layoutConfigDir: gt before town.json moved into mayor/ kept town
+	// config in <town>/config/. Only messaging.json still lives there.
+	layoutConfigDir = "config-dir"
+
+	// layoutClaude: towns from before the Cursor port kept agent
+	// instructions in CLAUDE.md, hooks in .claude/settings.json, and slash
+	// commands in <town>/.claude/commands/.
+	layoutClaude = "claude"
+)
+
+// configDirFiles are the town config files that moved from config/ to mayor/.
+var configDirFiles = []string{"town.json", "rigs.json", "overseer.json", "daemon.json"}
+
+// LayoutMigrationCheck finds settings, instructions, and config left at
+// locations used by older gt versions and moves them to the current layout.
+// Unlike cursor-settings, which only knows wrong-location hooks.json files,
+// it recognizes each historical layout and migrates content rather than
+// deleting it.
+type LayoutMigrationCheck struct {
+	FixableCheck
+	migrations []layoutMigration
+}
+
+// NewLayoutMigrationCheck creates a new layout migration check.
+func NewLayoutMigrationCheck() *LayoutMigrationCheck {
+	return &LayoutMigrationCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "layout-migration",
+				CheckDescription: "Detect files left in layouts from older gt versions",
+			},
+		},
+	}
+}
+
+// Run scans the town for files at historical locations.
+func (c *LayoutMigrationCheck) Run(ctx *CheckContext) *CheckResult {
+	c.migrations = findLayoutMigrations(ctx.TownRoot)
+
+	if len(c.migrations) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "Town uses the current layout",
+		}
+	}
+
+	layouts := make(map[string]bool)
+	var details []string
+	conflicts := 0
+	for _, m := range c.migrations {
+		layouts[m.layout] = true
+		from, to := c.relPath(ctx, m.from), c.relPath(ctx, m.to)
+		switch {
+		case m.conflict:
+			conflicts++
+			details = append(details, fmt.Sprintf("%s: %s already exists with different content (merge by hand)", from, to))
+		case m.regenerate:
+			details = append(details, fmt.Sprintf("%s: replace with Cursor settings in %s", from, to))
+		default:
+			details = append(details, fmt.Sprintf("%s -> %s", from, to))
+		}
+	}
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d file(s) at deprecated locations (layouts: %s)", len(c.migrations), strings.Join(names, ", ")),
+		Details: details,
+	}
+	if conflicts < len(c.migrations) {
+		result.Actions = []FixAction{doctorFix("move files to the current layout", false)}
+	}
+	if conflicts > 0 {
+		result.FixHint = "Merge conflicting files into their new location, then delete the old copy"
+	}
+	return result
+}
+
+// Fix moves each file to its current location. Files identical to one
+// already there are retired; conflicting files are left for a manual merge.
+func (c *LayoutMigrationCheck) Fix(ctx *CheckContext) error {
+	for _, m := range c.migrations {
+		if m.conflict {
+			continue
+		}
+		if err := ctx.Backup.Save(m.from); err != nil {
+			return err
+		}
+
+		if m.regenerate {
+			if !fileExists(m.to) {
+				if err := ctx.Backup.Save(filepath.Join(m.dir, ".cursor")); err != nil {
+					return err
+				}
+				if err := cursor.EnsureSettingsForRole(m.dir, m.role); err != nil {
+					return fmt.Errorf("installing Cursor settings in %s: %w", m.dir, err)
+				}
+			}
+			if err := removeLegacy(m.from); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if fileExists(m.to) {
+			if !sameFileContent(m.from, m.to) {
+				continue // Another migration got there first; report next run
+			}
+			if err := removeLegacy(m.from); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := ctx.Backup.Save(m.to); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(m.to), 0755); err != nil {
+			return fmt.Errorf("creating %s: %w", filepath.Dir(m.to), err)
+		}
+		if err := os.Rename(m.from, m.to); err != nil {
+			return fmt.Errorf("moving %s to %s: %w", m.from, m.to, err)
+		}
+		removeEmptyParents(filepath.Dir(m.from), ctx.TownRoot)
+	}
+	return nil
+}
+
+func (c *LayoutMigrationCheck) relPath(ctx *CheckContext, path string) string {
+	if rel, err := filepath.Rel(ctx.TownRoot, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// findLayoutMigrations lists every file at a historical location.
+func findLayoutMigrations(townRoot string) []layoutMigration {
+	var migrations []layoutMigration
+	add := func(m layoutMigration) {
+		if !fileExists(m.from) {
+			return
+		}
+		if !m.regenerate && fileExists(m.to) {
+			m.conflict = !sameFileContent(m.from, m.to)
+		}
+		migrations = append(migrations, m)
+	}
+
+	// config-dir layout: <town>/config/{town,rigs,...}.json -> mayor/
+	for _, name := range configDirFiles {
+		add(layoutMigration{
+			layout: layoutConfigDir,
+			from:   filepath.Join(townRoot, "config", name),
+			to:     filepath.Join(townRoot, "mayor", name),
+		})
+	}
+
+	// claude layout: town-root instructions belong to the mayor; at the
+	// root they would reach every agent by directory traversal.
+	add(layoutMigration{
+		layout: layoutClaude,
+		from:   filepath.Join(townRoot, "CLAUDE.md"),
+		to:     filepath.Join(townRoot, "mayor", "AGENTS.md"),
+	})
+	commandsDir := filepath.Join(townRoot, ".claude", "commands")
+	if entries, err := os.ReadDir(commandsDir); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			add(layoutMigration{
+				layout: layoutClaude,
+				from:   filepath.Join(commandsDir, e.Name()),
+				to:     filepath.Join(townRoot, ".cursor", "commands", e.Name()),
+			})
+		}
+	}
+
+	var rigs []string
+	for _, rigPath := range findAllRigs(townRoot) {
+		rigs = append(rigs, filepath.Base(rigPath))
+	}
+	for _, t := range daemon.TemplateTargets(townRoot, rigs) {
+		add(layoutMigration{
+			layout: layoutClaude,
+			from:   filepath.Join(t.WorkDir, "CLAUDE.md"),
+			to:     filepath.Join(t.WorkDir, "AGENTS.md"),
+		})
+		role, _ := contextRole(t.Agent)
+		add(layoutMigration{
+			layout:     layoutClaude,
+			from:       filepath.Join(t.WorkDir, ".claude", "settings.json"),
+			to:         filepath.Join(t.WorkDir, ".cursor", "hooks.json"),
+			regenerate: true,
+			role:       role,
+			dir:        t.WorkDir,
+		})
+	}
+	return migrations
+}
+
+// sameFileContent reports whether two files have identical contents.
+func sameFileContent(a, b string) bool {
+	dataA, errA := os.ReadFile(a) //nolint:gosec // G304: paths are within the town
+	dataB, errB := os.ReadFile(b) //nolint:gosec // G304: paths are within the town
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+// removeLegacy deletes a migrated file and any directories it leaves empty
+// (e.g. .claude/ once its settings are gone).
+func removeLegacy(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing %s: %w", path, err)
+	}
+	removeEmptyParents(filepath.Dir(path), "")
+	return nil
+}
+
+// removeEmptyParents removes dir and its parents while they are empty,
+// stopping at stop and at the first directory that is not a legacy one.
+func removeEmptyParents(dir, stop string) {
+	for dir != stop && dir != filepath.Dir(dir) {
+		switch filepath.Base(dir) {
+		case ".claude", "commands", "config":
+		default:
+			return
+		}
+		if os.Remove(dir) != nil { // Fails unless empty
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
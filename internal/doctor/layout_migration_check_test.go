@@ -0,0 +1,141 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeLayoutFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readLayoutFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestLayoutMigrationCheck_CurrentLayout(t *testing.T) {
+	townRoot := t.TempDir()
+	writeLayoutFile(t, filepath.Join(townRoot, "mayor", "town.json"), `{"type":"town"}`)
+	writeLayoutFile(t, filepath.Join(townRoot, "mayor", "AGENTS.md"), "# Mayor")
+	writeLayoutFile(t, filepath.Join(townRoot, "config", "messaging.json"), `{}`)
+
+	result := NewLayoutMigrationCheck().Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusOK {
+		t.Errorf("status = %v, want OK: %s %v", result.Status, result.Message, result.Details)
+	}
+}
+
+func TestLayoutMigrationCheck_ConfigDirLayout(t *testing.T) {
+	townRoot := t.TempDir()
+	writeLayoutFile(t, filepath.Join(townRoot, "mayor", ".keep"), "")
+	writeLayoutFile(t, filepath.Join(townRoot, "config", "town.json"), `{"type":"town","name":"old"}`)
+	writeLayoutFile(t, filepath.Join(townRoot, "config", "rigs.json"), `{"rigs":{}}`)
+
+	check := NewLayoutMigrationCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || !strings.Contains(result.Message, "config-dir") {
+		t.Fatalf("result = %v %q, want a config-dir warning", result.Status, result.Message)
+	}
+	if len(result.Details) != 2 {
+		t.Errorf("details = %v, want 2 files", result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if got := readLayoutFile(t, filepath.Join(townRoot, "mayor", "town.json")); !strings.Contains(got, `"old"`) {
+		t.Errorf("mayor/town.json = %q, want the migrated content", got)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, "config")); !os.IsNotExist(err) {
+		t.Errorf("empty config/ should be removed after migration, stat err = %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after fix: %v %v", result.Message, result.Details)
+	}
+}
+
+func TestLayoutMigrationCheck_ClaudeLayout(t *testing.T) {
+	townRoot := t.TempDir()
+	writeLayoutFile(t, filepath.Join(townRoot, "mayor", "town.json"), `{"type":"town"}`)
+	writeLayoutFile(t, filepath.Join(townRoot, "CLAUDE.md"), "# Town instructions")
+	writeLayoutFile(t, filepath.Join(townRoot, ".claude", "commands", "handoff.md"), "custom handoff")
+	writeLayoutFile(t, filepath.Join(townRoot, "deacon", "CLAUDE.md"), "# Deacon")
+	writeLayoutFile(t, filepath.Join(townRoot, "deacon", ".claude", "settings.json"), `{"hooks":{}}`)
+
+	check := NewLayoutMigrationCheck()
+	ctx := &CheckContext{TownRoot: townRoot, Backup: NewFixBackup(townRoot, time.Now())}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || !strings.Contains(result.Message, "claude") {
+		t.Fatalf("result = %v %q, want a claude warning", result.Status, result.Message)
+	}
+	if len(result.Details) != 4 {
+		t.Errorf("details = %v, want 4 files", result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if got := readLayoutFile(t, filepath.Join(townRoot, "mayor", "AGENTS.md")); got != "# Town instructions" {
+		t.Errorf("mayor/AGENTS.md = %q", got)
+	}
+	if got := readLayoutFile(t, filepath.Join(townRoot, "deacon", "AGENTS.md")); got != "# Deacon" {
+		t.Errorf("deacon/AGENTS.md = %q", got)
+	}
+	if got := readLayoutFile(t, filepath.Join(townRoot, ".cursor", "commands", "handoff.md")); got != "custom handoff" {
+		t.Errorf("migrated command = %q", got)
+	}
+	if !fileExists(filepath.Join(townRoot, "deacon", ".cursor", "hooks.json")) {
+		t.Error("deacon should get Cursor hooks in place of .claude/settings.json")
+	}
+	for _, gone := range []string{"CLAUDE.md", ".claude", "deacon/CLAUDE.md", "deacon/.claude"} {
+		if _, err := os.Stat(filepath.Join(townRoot, gone)); !os.IsNotExist(err) {
+			t.Errorf("%s should be gone after migration", gone)
+		}
+	}
+	if ctx.Backup.Len() == 0 {
+		t.Error("migrated files should be backed up")
+	}
+}
+
+func TestLayoutMigrationCheck_Conflict(t *testing.T) {
+	townRoot := t.TempDir()
+	writeLayoutFile(t, filepath.Join(townRoot, "mayor", "AGENTS.md"), "# Current")
+	writeLayoutFile(t, filepath.Join(townRoot, "mayor", "CLAUDE.md"), "# Old, different")
+	writeLayoutFile(t, filepath.Join(townRoot, "deacon", "AGENTS.md"), "# Same")
+	writeLayoutFile(t, filepath.Join(townRoot, "deacon", "CLAUDE.md"), "# Same")
+
+	check := NewLayoutMigrationCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.FixHint == "" || len(result.Actions) == 0 {
+		t.Errorf("want a merge hint and a fix for the identical copy, got %+v", result)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if got := readLayoutFile(t, filepath.Join(townRoot, "mayor", "CLAUDE.md")); got != "# Old, different" {
+		t.Errorf("conflicting CLAUDE.md must be left alone, got %q", got)
+	}
+	if got := readLayoutFile(t, filepath.Join(townRoot, "mayor", "AGENTS.md")); got != "# Current" {
+		t.Errorf("AGENTS.md must not be overwritten, got %q", got)
+	}
+	if fileExists(filepath.Join(townRoot, "deacon", "CLAUDE.md")) {
+		t.Error("identical deacon/CLAUDE.md should be retired")
+	}
+}
@@ -50,14 +50,14 @@ func (c *LifecycleHygieneCheck) Run(ctx *CheckContext) *CheckResult {
 		Name:    c.Name(),
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("Found %d stale lifecycle message(s) in deacon inbox", staleCount),
-		FixHint: "Run 'gt doctor --fix' to clean up",
+		Actions: []FixAction{doctorFix("clean up", true)},
 	}
 }
 
 // checkDeaconInbox looks for stale lifecycle messages.
 func (c *LifecycleHygieneCheck) checkDeaconInbox(ctx *CheckContext) int {
 	// Get deacon inbox via gt mail
-	cmd := exec.Command("gt", "mail", "inbox", "--identity", "deacon/", "--json")
+	cmd := exec.CommandContext(ctx, "gt", "mail", "inbox", "--identity", "deacon/", "--json")
 	cmd.Dir = ctx.TownRoot
 
 	output, err := cmd.Output()
@@ -98,7 +98,7 @@ func (c *LifecycleHygieneCheck) Fix(ctx *CheckContext) error {
 
 	// Delete stale lifecycle messages
 	for _, msg := range c.staleMessages {
-		cmd := exec.Command("gt", "mail", "delete", msg.ID) //nolint:gosec // G204: msg.ID is from internal state, not user input
+		cmd := exec.CommandContext(ctx, "gt", "mail", "delete", msg.ID) //nolint:gosec // G204: msg.ID is from internal state, not user input
 		cmd.Dir = ctx.TownRoot
 		if err := cmd.Run(); err != nil {
 			errors = append(errors, fmt.Sprintf("failed to delete message %s: %v", msg.ID, err))
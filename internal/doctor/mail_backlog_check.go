@@ -0,0 +1,118 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+)
+
+// Mail backlog thresholds.
+const (
+	DefaultMailMaxAge     = 24 * time.Hour
+	DefaultMailMaxPending = 20
+)
+
+// MailBacklogCheck flags agent inboxes that are not being drained: unread
+// messages older than maxAge, or more than maxPending unread messages. Mail
+// hooks only inject a summary; an agent that never reads its inbox silently
+// drops instructions.
+type MailBacklogCheck struct {
+	BaseCheck
+	maxAge     time.Duration
+	maxPending int
+	listUnread func(townRoot string) ([]*mail.Message, error)
+}
+
+// NewMailBacklogCheck creates a new mail backlog check with default thresholds.
+func NewMailBacklogCheck() *MailBacklogCheck {
+	return &MailBacklogCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "mail-backlog",
+			CheckDescription: "Detect agent inboxes with stale or piled-up unread mail",
+		},
+		maxAge:     DefaultMailMaxAge,
+		maxPending: DefaultMailMaxPending,
+		listUnread: mail.ListTownUnread,
+	}
+}
+
+// inboxBacklog summarizes one recipient's unread mail.
+type inboxBacklog struct {
+	address string
+	unread  int
+	oldest  time.Time
+}
+
+// Run groups unread mail by recipient and reports inboxes over threshold.
+func (c *MailBacklogCheck) Run(ctx *CheckContext) *CheckResult {
+	if _, err := os.Stat(filepath.Join(ctx.TownRoot, ".beads")); os.IsNotExist(err) {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No town beads (no mail to check)",
+		}
+	}
+
+	messages, err := c.listUnread(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not query unread mail",
+			Details: []string{err.Error()},
+		}
+	}
+
+	inboxes := make(map[string]*inboxBacklog)
+	for _, msg := range messages {
+		if msg.To == "" {
+			continue // unclaimed queue/announce mail has no inbox
+		}
+		ib, ok := inboxes[msg.To]
+		if !ok {
+			ib = &inboxBacklog{address: msg.To, oldest: msg.Timestamp}
+			inboxes[msg.To] = ib
+		}
+		ib.unread++
+		if msg.Timestamp.Before(ib.oldest) {
+			ib.oldest = msg.Timestamp
+		}
+	}
+
+	now := time.Now()
+	var backlogged []*inboxBacklog
+	for _, ib := range inboxes {
+		if ib.unread > c.maxPending || now.Sub(ib.oldest) > c.maxAge {
+			backlogged = append(backlogged, ib)
+		}
+	}
+
+	if len(backlogged) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%d unread message(s) across %d inbox(es), none backlogged", len(messages), len(inboxes)),
+		}
+	}
+
+	sort.Slice(backlogged, func(i, j int) bool {
+		return backlogged[i].oldest.Before(backlogged[j].oldest)
+	})
+	var details []string
+	for _, ib := range backlogged {
+		details = append(details, fmt.Sprintf("%s: %d unread, oldest %s ago",
+			ib.address, ib.unread, now.Sub(ib.oldest).Round(time.Minute)))
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d inbox(es) not being drained (>%d unread or older than %s)", len(backlogged), c.maxPending, c.maxAge),
+		Details: details,
+		FixHint: "Inspect with 'gt mail inbox <address>' and nudge the agent with 'gt nudge <address>'",
+	}
+}
@@ -0,0 +1,65 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+)
+
+func TestMailBacklogCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	var messages []*mail.Message
+	// Witness has one stale message
+	messages = append(messages, &mail.Message{To: "gastown/witness", Timestamp: now.Add(-48 * time.Hour)})
+	// Nux has a pile of fresh messages
+	for i := 0; i < 5; i++ {
+		messages = append(messages, &mail.Message{To: "gastown/polecats/nux", Timestamp: now.Add(-time.Minute)})
+	}
+	// Mayor is fine
+	messages = append(messages, &mail.Message{To: "mayor/", Timestamp: now.Add(-time.Hour)})
+
+	check := NewMailBacklogCheck()
+	check.maxPending = 3
+	check.listUnread = func(string) ([]*mail.Message, error) { return messages, nil }
+
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning {
+		t.Fatalf("Status = %v, want warning: %s", result.Status, result.Message)
+	}
+	if len(result.Details) != 2 ||
+		!strings.HasPrefix(result.Details[0], "gastown/witness: 1 unread") ||
+		!strings.HasPrefix(result.Details[1], "gastown/polecats/nux: 5 unread") {
+		t.Errorf("Details = %v", result.Details)
+	}
+
+	check.listUnread = func(string) ([]*mail.Message, error) { return messages[len(messages)-1:], nil }
+	if result := check.Run(&CheckContext{TownRoot: townRoot}); result.Status != StatusOK {
+		t.Errorf("healthy inboxes: Status = %v, want OK: %s", result.Status, result.Message)
+	}
+
+	check.listUnread = func(string) ([]*mail.Message, error) { return nil, errors.New("bd not installed") }
+	if result := check.Run(&CheckContext{TownRoot: townRoot}); result.Status != StatusWarning {
+		t.Errorf("query error: Status = %v, want warning", result.Status)
+	}
+}
+
+func TestMailBacklogCheck_NoBeads(t *testing.T) {
+	check := NewMailBacklogCheck()
+	check.listUnread = func(string) ([]*mail.Message, error) {
+		t.Fatal("should not query mail without town beads")
+		return nil, nil
+	}
+	if result := check.Run(&CheckContext{TownRoot: t.TempDir()}); result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK", result.Status)
+	}
+}
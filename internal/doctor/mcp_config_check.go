@@ -0,0 +1,101 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+)
+
+// MCPConfigCheck verifies each agent workspace's .cursor/mcp.json has the
+// MCP servers the town and rig settings configure for its role.
+type MCPConfigCheck struct {
+	FixableCheck
+	stale []daemon.TemplateTarget
+}
+
+// NewMCPConfigCheck creates a new MCP config check.
+func NewMCPConfigCheck() *MCPConfigCheck {
+	return &MCPConfigCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "mcp-config",
+				CheckDescription: "Check agents' .cursor/mcp.json match the configured MCP servers",
+			},
+		},
+	}
+}
+
+// Run compares each provisioned workspace's mcp.json with what the
+// settings generate.
+func (c *MCPConfigCheck) Run(ctx *CheckContext) *CheckResult {
+	c.stale = nil
+
+	var rigs []string
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		rigs = append(rigs, filepath.Base(rigPath))
+	}
+
+	var details []string
+	invalid := 0
+	for _, t := range daemon.TemplateTargets(ctx.TownRoot, rigs) {
+		if !cursor.HooksInstalled(t.WorkDir) {
+			continue
+		}
+		current, err := cursor.MCPCurrentForRole(t.WorkDir, t.Role)
+		switch {
+		case err != nil:
+			invalid++
+			details = append(details, fmt.Sprintf("%s: %v", t.Agent, err))
+		case !current:
+			c.stale = append(c.stale, t)
+			details = append(details, fmt.Sprintf("%s: .cursor/mcp.json differs from the configured servers", t.Agent))
+		}
+	}
+
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "Agent MCP servers match settings",
+		}
+	}
+	if invalid > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d agent mcp.json file(s) cannot be read", invalid),
+			Details: details,
+			FixHint: "Fix or remove the listed .cursor/mcp.json files, then run 'gt doctor --fix'",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d agent workspace(s) have outdated MCP servers", len(c.stale)),
+		Details: details,
+		Actions: []FixAction{doctorFix("regenerate mcp.json", false), {Command: "gt", Args: []string{"settings", "diff"}, Description: "preview the changes"}},
+	}
+}
+
+// Inputs returns the paths this check depends on (see InputFingerprinter).
+func (c *MCPConfigCheck) Inputs(ctx *CheckContext) []string {
+	return agentWorkspaceInputs(ctx.TownRoot)
+}
+
+// Fix rewrites the outdated mcp.json files, keeping servers the user added.
+func (c *MCPConfigCheck) Fix(ctx *CheckContext) error {
+	for i, target := range c.stale {
+		if err := ctx.Step(i, len(c.stale), target.Agent); err != nil {
+			return err
+		}
+		if err := ctx.Backup.Save(cursor.MCPConfigPath(target.WorkDir)); err != nil {
+			return err
+		}
+		if err := cursor.EnsureMCPForRole(target.WorkDir, target.Role); err != nil {
+			return fmt.Errorf("regenerating mcp.json for %s: %w", target.Agent, err)
+		}
+	}
+	return ctx.Step(len(c.stale), len(c.stale), "")
+}
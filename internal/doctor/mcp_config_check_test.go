@@ -0,0 +1,48 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+)
+
+func TestMCPConfigCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	mayorDir := filepath.Join(townRoot, "mayor")
+	if err := cursor.EnsureHooks(mayorDir); err != nil {
+		t.Fatal(err)
+	}
+	check := NewMCPConfigCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("no servers configured: status = %v, want OK (%v)", result.Status, result.Details)
+	}
+
+	settings := `{"type":"town-settings","version":1,"mcp":{"servers":{"docs":{"url":"https://docs.example/mcp"}}}}`
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || len(result.Details) != 1 {
+		t.Fatalf("server added: status = %v, details = %v; want one stale workspace", result.Status, result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after Fix: status = %v, details = %v", result.Status, result.Details)
+	}
+
+	if err := os.WriteFile(cursor.MCPConfigPath(mayorDir), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := check.Run(ctx); result.Status != StatusError {
+		t.Errorf("invalid mcp.json: status = %v, want error", result.Status)
+	}
+}
@@ -1,11 +1,13 @@
 package doctor
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
@@ -26,6 +28,7 @@ func NewOrphanSessionCheck() *OrphanSessionCheck {
 			BaseCheck: BaseCheck{
 				CheckName:        "orphan-sessions",
 				CheckDescription: "Detect orphaned tmux sessions",
+				CheckDependsOn:   []string{"tmux"},
 			},
 		},
 	}
@@ -37,12 +40,7 @@ func (c *OrphanSessionCheck) Run(ctx *CheckContext) *CheckResult {
 
 	sessions, err := t.ListSessions()
 	if err != nil {
-		return &CheckResult{
-			Name:    c.Name(),
-			Status:  StatusWarning,
-			Message: "Could not list tmux sessions",
-			Details: []string{err.Error()},
-		}
+		return tmuxSkipped(c.Name())
 	}
 
 	if len(sessions) == 0 {
@@ -53,8 +51,12 @@ func (c *OrphanSessionCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	// Get list of valid rigs
+	// Get list of valid rigs. Rigs of other towns on this machine count
+	// too, so their sessions are never mistaken for orphans.
 	validRigs := c.getValidRigs(ctx.TownRoot)
+	for _, other := range ctx.OtherTowns {
+		validRigs = append(validRigs, c.getValidRigs(other)...)
+	}
 
 	// Get session names for mayor/deacon
 	mayorSession := session.MayorSessionName()
@@ -102,7 +104,7 @@ func (c *OrphanSessionCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("Found %d orphaned session(s)", len(orphans)),
 		Details: details,
-		FixHint: "Run 'gt doctor --fix' to kill orphaned sessions",
+		Actions: []FixAction{doctorFix("kill orphaned sessions", true)},
 	}
 }
 
@@ -115,7 +117,11 @@ func (c *OrphanSessionCheck) Fix(ctx *CheckContext) error {
 	t := tmux.NewTmux()
 	var lastErr error
 
-	for _, session := range c.orphanSessions {
+	for i, session := range c.orphanSessions {
+		if err := ctx.Step(i, len(c.orphanSessions), session); err != nil {
+			return err
+		}
+
 		// SAFEGUARD: Never auto-kill crew sessions.
 		// Crew workers are human-managed and require explicit action.
 		if isCrewSession(session) {
@@ -123,8 +129,11 @@ func (c *OrphanSessionCheck) Fix(ctx *CheckContext) error {
 		}
 		if err := t.KillSession(session); err != nil {
 			lastErr = err
+			continue
 		}
+		ctx.Record(ActionSessionKilled, session)
 	}
+	_ = ctx.Step(len(c.orphanSessions), len(c.orphanSessions), "")
 
 	return lastErr
 }
@@ -247,7 +256,7 @@ func NewOrphanProcessCheck() *OrphanProcessCheck {
 // Run checks for orphaned agent processes.
 func (c *OrphanProcessCheck) Run(ctx *CheckContext) *CheckResult {
 	// Get list of tmux session PIDs
-	tmuxPIDs, err := c.getTmuxSessionPIDs()
+	tmuxPIDs, err := c.getTmuxSessionPIDs(ctx)
 	if err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -258,7 +267,7 @@ func (c *OrphanProcessCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Find agent processes
-	agentProcs, err := c.findAgentProcesses()
+	agentProcs, err := c.findAgentProcesses(ctx)
 	if err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -281,7 +290,7 @@ func (c *OrphanProcessCheck) Run(ctx *CheckContext) *CheckResult {
 	var validCount int
 
 	for _, proc := range agentProcs {
-		if c.isOrphanProcess(proc, tmuxPIDs) {
+		if c.isOrphanProcess(ctx, proc, tmuxPIDs) {
 			orphans = append(orphans, proc)
 		} else {
 			validCount++
@@ -312,7 +321,7 @@ func (c *OrphanProcessCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("Found %d orphaned agent process(es)", len(orphans)),
 		Details: details,
-		FixHint: "Run 'gt doctor --fix' to kill orphaned processes",
+		Actions: []FixAction{doctorFix("kill orphaned processes", true)},
 	}
 }
 
@@ -325,12 +334,16 @@ func (c *OrphanProcessCheck) Fix(ctx *CheckContext) error {
 	// SAFEGUARD: Get crew session pane PIDs to avoid killing crew processes.
 	// Even if a process appears orphaned, if its parent is a crew session pane,
 	// we should not kill it (the detection might be wrong).
-	crewPanePIDs := c.getCrewSessionPanePIDs()
+	crewPanePIDs := c.getCrewSessionPanePIDs(ctx)
 
 	var lastErr error
-	for _, pid := range c.orphanPIDs {
+	for i, pid := range c.orphanPIDs {
+		if err := ctx.Step(i, len(c.orphanPIDs), fmt.Sprintf("PID %d", pid)); err != nil {
+			return err
+		}
+
 		// Check if this process has a crew session ancestor
-		if c.hasCrewAncestor(pid, crewPanePIDs) {
+		if c.hasCrewAncestor(ctx, pid, crewPanePIDs) {
 			// Skip - this process might belong to a crew session
 			continue
 		}
@@ -344,15 +357,18 @@ func (c *OrphanProcessCheck) Fix(ctx *CheckContext) error {
 			// Try SIGKILL if SIGINT fails
 			if killErr := proc.Kill(); killErr != nil {
 				lastErr = killErr
+				continue
 			}
 		}
+		ctx.Record(ActionProcessKilled, strconv.Itoa(pid))
 	}
+	_ = ctx.Step(len(c.orphanPIDs), len(c.orphanPIDs), "")
 
 	return lastErr
 }
 
 // getCrewSessionPanePIDs returns pane PIDs for all crew sessions.
-func (c *OrphanProcessCheck) getCrewSessionPanePIDs() map[int]bool {
+func (c *OrphanProcessCheck) getCrewSessionPanePIDs(ctx context.Context) map[int]bool {
 	pids := make(map[int]bool)
 
 	t := tmux.NewTmux()
@@ -366,7 +382,7 @@ func (c *OrphanProcessCheck) getCrewSessionPanePIDs() map[int]bool {
 			continue
 		}
 		// Get pane PIDs for this crew session
-		out, err := exec.Command("tmux", "list-panes", "-t", session, "-F", "#{pane_pid}").Output()
+		out, err := exec.CommandContext(ctx, "tmux", "list-panes", "-t", session, "-F", "#{pane_pid}").Output()
 		if err != nil {
 			continue
 		}
@@ -382,7 +398,7 @@ func (c *OrphanProcessCheck) getCrewSessionPanePIDs() map[int]bool {
 }
 
 // hasCrewAncestor checks if a process has a crew session pane as an ancestor.
-func (c *OrphanProcessCheck) hasCrewAncestor(pid int, crewPanePIDs map[int]bool) bool {
+func (c *OrphanProcessCheck) hasCrewAncestor(ctx context.Context, pid int, crewPanePIDs map[int]bool) bool {
 	if len(crewPanePIDs) == 0 {
 		return false
 	}
@@ -400,7 +416,7 @@ func (c *OrphanProcessCheck) hasCrewAncestor(pid int, crewPanePIDs map[int]bool)
 		}
 
 		// Get parent PID
-		out, err := exec.Command("ps", "-p", fmt.Sprintf("%d", currentPID), "-o", "ppid=").Output() //nolint:gosec // G204: PID is numeric from internal state
+		out, err := exec.CommandContext(ctx, "ps", "-p", fmt.Sprintf("%d", currentPID), "-o", "ppid=").Output() //nolint:gosec // G204: PID is numeric from internal state
 		if err != nil {
 			break
 		}
@@ -422,14 +438,14 @@ type processInfo struct {
 }
 
 // getTmuxSessionPIDs returns PIDs of all tmux server processes and pane shell PIDs.
-func (c *OrphanProcessCheck) getTmuxSessionPIDs() (map[int]bool, error) { //nolint:unparam // error return kept for future use
+func (c *OrphanProcessCheck) getTmuxSessionPIDs(ctx context.Context) (map[int]bool, error) { //nolint:unparam // error return kept for future use
 	// Get tmux server PID and all pane PIDs
 	pids := make(map[int]bool)
 
 	// Find tmux server processes using ps instead of pgrep.
 	// pgrep -x tmux is unreliable on macOS - it often misses the actual server.
 	// We use ps with awk to find processes where comm is exactly "tmux".
-	out, err := exec.Command("sh", "-c", `ps ax -o pid,comm | awk '$2 == "tmux" || $2 ~ /\/tmux$/ { print $1 }'`).Output()
+	out, err := exec.CommandContext(ctx, "sh", "-c", `ps ax -o pid,comm | awk '$2 == "tmux" || $2 ~ /\/tmux$/ { print $1 }'`).Output()
 	if err != nil {
 		// No tmux server running
 		return pids, nil
@@ -447,7 +463,7 @@ func (c *OrphanProcessCheck) getTmuxSessionPIDs() (map[int]bool, error) { //noli
 	sessions, _ := t.ListSessions()
 	for _, session := range sessions {
 		// Get pane PIDs for this session
-		out, err := exec.Command("tmux", "list-panes", "-t", session, "-F", "#{pane_pid}").Output()
+		out, err := exec.CommandContext(ctx, "tmux", "list-panes", "-t", session, "-F", "#{pane_pid}").Output()
 		if err != nil {
 			continue
 		}
@@ -463,11 +479,11 @@ func (c *OrphanProcessCheck) getTmuxSessionPIDs() (map[int]bool, error) { //noli
 }
 
 // findAgentProcesses finds all running cursor-agent processes.
-func (c *OrphanProcessCheck) findAgentProcesses() ([]processInfo, error) {
+func (c *OrphanProcessCheck) findAgentProcesses(ctx context.Context) ([]processInfo, error) {
 	var procs []processInfo
 
 	// Use ps to find agent processes (command includes args)
-	out, err := exec.Command("ps", "-eo", "pid,ppid,command").Output()
+	out, err := exec.CommandContext(ctx, "ps", "-eo", "pid,ppid,command").Output()
 	if err != nil {
 		return nil, err
 	}
@@ -507,7 +523,7 @@ func (c *OrphanProcessCheck) findAgentProcesses() ([]processInfo, error) {
 
 // isOrphanProcess checks if an agent process is orphaned.
 // A process is orphaned if its parent (or ancestor) is not a tmux session.
-func (c *OrphanProcessCheck) isOrphanProcess(proc processInfo, tmuxPIDs map[int]bool) bool {
+func (c *OrphanProcessCheck) isOrphanProcess(ctx context.Context, proc processInfo, tmuxPIDs map[int]bool) bool {
 	// Walk up the process tree looking for a tmux parent
 	currentPPID := proc.ppid
 	visited := make(map[int]bool)
@@ -521,7 +537,7 @@ func (c *OrphanProcessCheck) isOrphanProcess(proc processInfo, tmuxPIDs map[int]
 		}
 
 		// Get parent's parent
-		out, err := exec.Command("ps", "-p", fmt.Sprintf("%d", currentPPID), "-o", "ppid=").Output() //nolint:gosec // G204: PID is numeric from internal state
+		out, err := exec.CommandContext(ctx, "ps", "-p", fmt.Sprintf("%d", currentPPID), "-o", "ppid=").Output() //nolint:gosec // G204: PID is numeric from internal state
 		if err != nil {
 			break
 		}
@@ -2,6 +2,7 @@ package doctor
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -65,7 +66,7 @@ func (c *PatrolMoleculesExistCheck) Run(ctx *CheckContext) *CheckResult {
 	var details []string
 	for _, rigName := range rigs {
 		rigPath := filepath.Join(ctx.TownRoot, rigName)
-		missing := c.checkPatrolMolecules(rigPath)
+		missing := c.checkPatrolMolecules(ctx, rigPath)
 		if len(missing) > 0 {
 			c.missingMols[rigName] = missing
 			details = append(details, fmt.Sprintf("%s: missing %v", rigName, missing))
@@ -78,7 +79,7 @@ func (c *PatrolMoleculesExistCheck) Run(ctx *CheckContext) *CheckResult {
 			Status:  StatusWarning,
 			Message: fmt.Sprintf("%d rig(s) missing patrol molecules", len(c.missingMols)),
 			Details: details,
-			FixHint: "Run 'gt doctor --fix' to create missing patrol molecules",
+			Actions: []FixAction{doctorFix("create missing patrol molecules", false)},
 		}
 	}
 
@@ -90,9 +91,9 @@ func (c *PatrolMoleculesExistCheck) Run(ctx *CheckContext) *CheckResult {
 }
 
 // checkPatrolMolecules returns missing patrol molecule titles for a rig.
-func (c *PatrolMoleculesExistCheck) checkPatrolMolecules(rigPath string) []string {
+func (c *PatrolMoleculesExistCheck) checkPatrolMolecules(ctx context.Context, rigPath string) []string {
 	// List molecules using bd
-	cmd := exec.Command("bd", "list", "--type=molecule")
+	cmd := exec.CommandContext(ctx, "bd", "list", "--type=molecule")
 	cmd.Dir = rigPath
 	output, err := cmd.Output()
 	if err != nil {
@@ -115,7 +116,7 @@ func (c *PatrolMoleculesExistCheck) Fix(ctx *CheckContext) error {
 		rigPath := filepath.Join(ctx.TownRoot, rigName)
 		for _, mol := range missing {
 			desc := getPatrolMoleculeDesc(mol)
-			cmd := exec.Command("bd", "create", //nolint:gosec // G204: args are constructed internally
+			cmd := exec.CommandContext(ctx, "bd", "create", //nolint:gosec // G204: args are constructed internally
 				"--type=molecule",
 				"--title="+mol,
 				"--description="+desc,
@@ -170,7 +171,7 @@ func (c *PatrolHooksWiredCheck) Run(ctx *CheckContext) *CheckResult {
 			Name:    c.Name(),
 			Status:  StatusWarning,
 			Message: fmt.Sprintf("%s not found", relPath),
-			FixHint: "Run 'gt doctor --fix' to create default config, or 'gt daemon start' to start the daemon",
+			Actions: []FixAction{doctorFix("create default config", false), {Command: "gt", Args: []string{"daemon", "start"}, Description: "start the daemon"}},
 		}
 	}
 
@@ -204,7 +205,7 @@ func (c *PatrolHooksWiredCheck) Run(ctx *CheckContext) *CheckResult {
 		Name:    c.Name(),
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("Configure patrols in %s or run 'gt daemon start'", relPath),
-		FixHint: "Run 'gt doctor --fix' to create default config",
+		Actions: []FixAction{doctorFix("create default config", false)},
 	}
 }
 
@@ -361,7 +362,7 @@ func (c *PatrolPluginsAccessibleCheck) Run(ctx *CheckContext) *CheckResult {
 			Status:  StatusWarning,
 			Message: fmt.Sprintf("%d plugin directory(ies) missing", len(c.missingDirs)),
 			Details: c.missingDirs,
-			FixHint: "Run 'gt doctor --fix' to create missing directories",
+			Actions: []FixAction{doctorFix("create missing directories", false)},
 		}
 	}
 
@@ -455,7 +456,7 @@ func (c *PatrolRolesHavePromptsCheck) Run(ctx *CheckContext) *CheckResult {
 			Status:  StatusWarning,
 			Message: fmt.Sprintf("%d role prompt template(s) missing", len(missingPrompts)),
 			Details: missingPrompts,
-			FixHint: "Run 'gt doctor --fix' to copy embedded templates to rig repos",
+			Actions: []FixAction{doctorFix("copy embedded templates to rig repos", false)},
 		}
 	}
 
@@ -487,6 +488,9 @@ func (c *PatrolRolesHavePromptsCheck) Fix(ctx *CheckContext) error {
 			}
 
 			destPath := filepath.Join(templatesDir, roleFile)
+			if err := ctx.Backup.Save(destPath); err != nil {
+				return err
+			}
 			if err := os.WriteFile(destPath, content, 0644); err != nil {
 				return fmt.Errorf("writing %s in %s: %w", roleFile, rigName, err)
 			}
@@ -513,7 +517,10 @@ func discoverRigs(townRoot string) ([]string, error) {
 	}
 
 	var rigs []string
-	for name := range rigsConfig.Rigs {
+	for name, entry := range rigsConfig.Rigs {
+		if entry.Mirror {
+			continue // Mirror rigs have no patrol roles
+		}
 		rigs = append(rigs, name)
 	}
 	return rigs, nil
@@ -246,11 +246,11 @@ func TestPatrolRolesHavePromptsCheck_FixHint(t *testing.T) {
 
 	result := check.Run(ctx)
 
-	if result.FixHint == "" {
+	if result.Hint() == "" {
 		t.Error("FixHint should not be empty for warning status")
 	}
-	if result.FixHint != "Run 'gt doctor --fix' to copy embedded templates to rig repos" {
-		t.Errorf("FixHint = %q, unexpected value", result.FixHint)
+	if result.Hint() != "Run 'gt doctor --fix' to copy embedded templates to rig repos" {
+		t.Errorf("FixHint = %q, unexpected value", result.Hint())
 	}
 }
 
@@ -388,7 +388,7 @@ func TestPatrolHooksWiredCheck_NoDaemonConfig(t *testing.T) {
 	if result.Status != StatusWarning {
 		t.Errorf("Status = %v, want Warning", result.Status)
 	}
-	if result.FixHint == "" {
+	if result.Hint() == "" {
 		t.Error("FixHint should not be empty")
 	}
 }
@@ -0,0 +1,111 @@
+package doctor
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+// Built-in doctor profiles.
+const (
+	ProfileFull  = "full"
+	ProfileQuick = "quick"
+)
+
+// BuiltinProfiles returns the profiles available in every town.
+func BuiltinProfiles() map[string]*config.DoctorProfile {
+	return map[string]*config.DoctorProfile{
+		ProfileFull: {
+			Description: "Every check",
+		},
+		ProfileQuick: {
+			Description: "Skip checks that walk git history, shell out to slow tools, or use the network",
+			Skip: []string{
+				"persistent-role-branches",
+				"beads-sync-orphans",
+				"clone-divergence",
+				"crew-worktrees",
+				"cursor-cli",
+				"gt-update",
+			},
+		},
+	}
+}
+
+// Profiles returns the built-in profiles merged with those defined in the
+// town settings (town definitions win).
+func Profiles(townRoot string) (map[string]*config.DoctorProfile, error) {
+	profiles := BuiltinProfiles()
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	for name, p := range settings.DoctorProfiles {
+		if p != nil {
+			profiles[name] = p
+		}
+	}
+	return profiles, nil
+}
+
+// LookupProfile returns a named profile for a town.
+func LookupProfile(townRoot, name string) (*config.DoctorProfile, error) {
+	profiles, err := Profiles(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown doctor profile %q (available: %v)", name, names)
+	}
+	return p, nil
+}
+
+// ApplyProfile keeps only the registered checks the profile selects. It
+// returns the profile entries that matched no registered check, which are
+// usually typos or rig checks run without --rig.
+func (d *Doctor) ApplyProfile(p *config.DoctorProfile) ([]string, error) {
+	for _, pattern := range append(append([]string{}, p.Checks...), p.Skip...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid check pattern %q: %w", pattern, err)
+		}
+	}
+
+	used := make(map[string]bool)
+	matches := func(patterns []string, name string) bool {
+		found := false
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				used[pattern] = true
+				found = true
+			}
+		}
+		return found
+	}
+
+	kept := d.checks[:0:0]
+	for _, check := range d.checks {
+		name := check.Name()
+		include := len(p.Checks) == 0 || matches(p.Checks, name)
+		if matches(p.Skip, name) || !include {
+			continue
+		}
+		kept = append(kept, check)
+	}
+	d.checks = kept
+
+	var unmatched []string
+	for _, pattern := range append(append([]string{}, p.Checks...), p.Skip...) {
+		if !used[pattern] {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+	return unmatched, nil
+}
@@ -0,0 +1,122 @@
+package doctor
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+func checkNames(d *Doctor) []string {
+	var names []string
+	for _, c := range d.Checks() {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+func profileDoctor(names ...string) *Doctor {
+	d := NewDoctor()
+	for _, name := range names {
+		d.Register(newMockCheck(name, StatusOK))
+	}
+	return d
+}
+
+func TestApplyProfile(t *testing.T) {
+	all := []string{"daemon", "patrol-hooks-wired", "patrol-not-stuck", "clone-divergence", "cursor-cli"}
+
+	tests := []struct {
+		name      string
+		profile   *config.DoctorProfile
+		want      []string
+		unmatched []string
+	}{
+		{
+			name:    "empty profile runs everything",
+			profile: &config.DoctorProfile{},
+			want:    all,
+		},
+		{
+			name:    "checks with glob",
+			profile: &config.DoctorProfile{Checks: []string{"daemon", "patrol-*"}},
+			want:    []string{"daemon", "patrol-hooks-wired", "patrol-not-stuck"},
+		},
+		{
+			name:    "skip after checks",
+			profile: &config.DoctorProfile{Checks: []string{"patrol-*"}, Skip: []string{"patrol-not-stuck"}},
+			want:    []string{"patrol-hooks-wired"},
+		},
+		{
+			name:      "unmatched entries reported",
+			profile:   &config.DoctorProfile{Checks: []string{"daemon", "deamon"}, Skip: []string{"witness-exists"}},
+			want:      []string{"daemon"},
+			unmatched: []string{"deamon", "witness-exists"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := profileDoctor(all...)
+			unmatched, err := d.ApplyProfile(tt.profile)
+			if err != nil {
+				t.Fatalf("ApplyProfile: %v", err)
+			}
+			if got := checkNames(d); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checks = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(unmatched, tt.unmatched) {
+				t.Errorf("unmatched = %v, want %v", unmatched, tt.unmatched)
+			}
+		})
+	}
+
+	if _, err := profileDoctor(all...).ApplyProfile(&config.DoctorProfile{Checks: []string{"["}}); err == nil {
+		t.Error("expected error for malformed pattern")
+	}
+}
+
+func TestBuiltinQuickProfileSkipsGitChecks(t *testing.T) {
+	d := profileDoctor("daemon", "clone-divergence", "persistent-role-branches")
+	if _, err := d.ApplyProfile(BuiltinProfiles()[ProfileQuick]); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkNames(d); !reflect.DeepEqual(got, []string{"daemon"}) {
+		t.Errorf("quick profile checks = %v", got)
+	}
+}
+
+func TestLookupProfileFromTownSettings(t *testing.T) {
+	townRoot := t.TempDir()
+	mustWrite(t, filepath.Join(townRoot, "settings", "config.json"), `{
+  "type": "town-settings",
+  "version": 1,
+  "doctor_profiles": {
+    "pre-demo": {"description": "Before a demo", "checks": ["daemon"]},
+    "quick": {"skip": ["town-git"]}
+  }
+}`)
+
+	p, err := LookupProfile(townRoot, "pre-demo")
+	if err != nil {
+		t.Fatalf("LookupProfile(pre-demo): %v", err)
+	}
+	if !reflect.DeepEqual(p.Checks, []string{"daemon"}) {
+		t.Errorf("pre-demo checks = %v", p.Checks)
+	}
+
+	// Town definitions replace built-ins of the same name.
+	p, err = LookupProfile(townRoot, ProfileQuick)
+	if err != nil || !reflect.DeepEqual(p.Skip, []string{"town-git"}) {
+		t.Errorf("quick = %+v, %v; want town override", p, err)
+	}
+
+	if _, err := LookupProfile(townRoot, ProfileFull); err != nil {
+		t.Errorf("built-in full profile missing: %v", err)
+	}
+	_, err = LookupProfile(townRoot, "nope")
+	if err == nil || !strings.Contains(err.Error(), "pre-demo") {
+		t.Errorf("unknown profile err = %v, want list of available profiles", err)
+	}
+}
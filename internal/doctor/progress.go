@@ -0,0 +1,86 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFixInterrupted is returned (wrapped in a *PartialFixError) by fixers
+// that stop between items because the run was interrupted.
+var ErrFixInterrupted = errors.New("fix interrupted")
+
+// PartialFixError records how far a fix got before it was interrupted.
+type PartialFixError struct {
+	Done  int // Items completed before stopping
+	Total int // Items the fix set out to process
+}
+
+func (e *PartialFixError) Error() string {
+	return fmt.Sprintf("interrupted after %d of %d items", e.Done, e.Total)
+}
+
+// Unwrap lets errors.Is match ErrFixInterrupted.
+func (e *PartialFixError) Unwrap() error {
+	return ErrFixInterrupted
+}
+
+// FixProgress describes the item a running fix is working on.
+type FixProgress struct {
+	Check string // Name of the check being fixed
+	Done  int    // Items completed so far
+	Total int    // Total items to process
+	Item  string // Item being processed (empty when Done == Total)
+}
+
+// ProgressFunc receives progress updates from fixes.
+type ProgressFunc func(FixProgress)
+
+// CheckProgress reports a check starting (Result is nil) or finishing
+// during a doctor run.
+type CheckProgress struct {
+	Check  string       // Name of the check
+	Index  int          // Position in the run, from 0
+	Total  int          // Number of checks in the run
+	Result *CheckResult // Final result, including any fix; nil when starting
+}
+
+// CheckProgressFunc receives a CheckProgress as each check starts and
+// finishes.
+type CheckProgressFunc func(CheckProgress)
+
+// Interrupted reports whether the run has been cancelled (e.g. by ctrl-C).
+func (ctx *CheckContext) Interrupted() bool {
+	if ctx == nil || ctx.Interrupt == nil {
+		return false
+	}
+	select {
+	case <-ctx.Interrupt:
+		return true
+	default:
+		return false
+	}
+}
+
+// Step is called by fixes before processing each item. It reports progress
+// and returns a *PartialFixError if the run was interrupted, so the fix can
+// stop cleanly between items:
+//
+//	for i, item := range items {
+//		if err := ctx.Step(i, len(items), item.name); err != nil {
+//			return err
+//		}
+//		...
+//	}
+//	ctx.Step(len(items), len(items), "")
+func (ctx *CheckContext) Step(done, total int, item string) error {
+	if ctx == nil {
+		return nil
+	}
+	if done < total && ctx.Interrupted() {
+		return &PartialFixError{Done: done, Total: total}
+	}
+	if ctx.Progress != nil {
+		ctx.Progress(FixProgress{Check: ctx.fixing, Done: done, Total: total, Item: item})
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package doctor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// itemCheck is a fixable check that processes items one at a time and
+// closes interrupt after stopAfter items, simulating ctrl-C mid-fix.
+type itemCheck struct {
+	FixableCheck
+	items     []string
+	done      int
+	stopAfter int
+	interrupt chan struct{}
+}
+
+func (c *itemCheck) Run(ctx *CheckContext) *CheckResult {
+	if c.done == len(c.items) {
+		return &CheckResult{Name: c.CheckName, Status: StatusOK, Message: "all items fixed"}
+	}
+	return &CheckResult{Name: c.CheckName, Status: StatusWarning, Message: "items need fixing"}
+}
+
+func (c *itemCheck) Fix(ctx *CheckContext) error {
+	for i, item := range c.items {
+		if err := ctx.Step(i, len(c.items), item); err != nil {
+			return err
+		}
+		c.done++
+		if c.done == c.stopAfter {
+			close(c.interrupt)
+		}
+	}
+	return ctx.Step(len(c.items), len(c.items), "")
+}
+
+func TestDoctorFix_InterruptStopsBetweenItems(t *testing.T) {
+	interrupt := make(chan struct{})
+	check := &itemCheck{
+		FixableCheck: FixableCheck{BaseCheck{CheckName: "items"}},
+		items:        []string{"a", "b", "c", "d"},
+		stopAfter:    2,
+		interrupt:    interrupt,
+	}
+	later := newMockCheck("later", StatusWarning)
+	later.fixable = true
+
+	var progress []FixProgress
+	ctx := &CheckContext{
+		TownRoot:  t.TempDir(),
+		Interrupt: interrupt,
+		Progress:  func(p FixProgress) { progress = append(progress, p) },
+	}
+
+	d := NewDoctor()
+	d.Register(check)
+	d.Register(later)
+	report := d.Fix(ctx)
+
+	if check.done != 2 {
+		t.Errorf("items processed = %d, want 2 (stop between items)", check.done)
+	}
+	if len(progress) != 2 || progress[1].Check != "items" || progress[1].Item != "b" || progress[1].Total != 4 {
+		t.Errorf("unexpected progress updates: %+v", progress)
+	}
+	if !report.Interrupted || report.ExitCode() != ExitInterrupted {
+		t.Errorf("report should be interrupted: %+v", report)
+	}
+	if later.fixCount != 0 || len(report.NotRun) != 1 || report.NotRun[0] != "later" {
+		t.Errorf("checks after the interrupt should not run: fixCount=%d NotRun=%v", later.fixCount, report.NotRun)
+	}
+
+	result := report.Checks[0]
+	if !result.Fixed || result.Status != StatusWarning {
+		t.Errorf("partial fix should be reported as fixed but still warning: %+v", result)
+	}
+	if got := strings.Join(result.Details, "\n"); !strings.Contains(got, "completed 2 of 4 items") {
+		t.Errorf("details should report partial completion, got %q", got)
+	}
+}
+
+func TestCheckContext_Step(t *testing.T) {
+	var nilCtx *CheckContext
+	if err := nilCtx.Step(0, 1, "x"); err != nil {
+		t.Errorf("nil context Step() = %v", err)
+	}
+
+	interrupt := make(chan struct{})
+	close(interrupt)
+	ctx := &CheckContext{Interrupt: interrupt}
+	err := ctx.Step(3, 5, "x")
+	var partial *PartialFixError
+	if !errors.As(err, &partial) || partial.Done != 3 || !errors.Is(err, ErrFixInterrupted) {
+		t.Errorf("Step() after interrupt = %v, want PartialFixError{3, 5}", err)
+	}
+	if err := ctx.Step(5, 5, ""); err != nil {
+		t.Errorf("final Step() should not fail once all items are done: %v", err)
+	}
+}
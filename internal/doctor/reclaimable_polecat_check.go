@@ -0,0 +1,177 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/git"
+	"github.com/cursorworkshop/cursor-gastown/internal/polecat"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+)
+
+// polecatWorkClosed reports whether a hooked bead is closed; known is false
+// when the bead could not be read (seam for tests).
+var polecatWorkClosed = func(townRoot, rigName, id string) (closed, known bool) {
+	resolved := beads.ResolveBeadsDir(filepath.Join(townRoot, rigName))
+	issue, err := beads.NewWithBeadsDir(filepath.Dir(resolved), resolved).Show(id)
+	if err != nil {
+		return false, false
+	}
+	return issue.Status == "closed", true
+}
+
+// ReclaimablePolecatCheck detects polecat directories whose agent finished:
+// no session, and either the hooked work is closed or (with nothing hooked)
+// every commit on the branch has landed on the rig's default branch.
+// Directories that never started are the stale-polecat-dirs check's job.
+type ReclaimablePolecatCheck struct {
+	FixableCheck
+	reclaimable []reclaimablePolecatDir // cached for Fix
+}
+
+type reclaimablePolecatDir struct {
+	stalePolecatDir
+	reason string
+	dirty  bool // has uncommitted changes; Fix leaves it in place
+}
+
+// NewReclaimablePolecatCheck creates a new reclaimable polecat check.
+func NewReclaimablePolecatCheck() *ReclaimablePolecatCheck {
+	return &ReclaimablePolecatCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "reclaimable-polecats",
+				CheckDescription: "Detect polecat directories whose branch is merged or work is done",
+				CheckDependsOn:   []string{"tmux"},
+			},
+		},
+	}
+}
+
+// Run finds reclaimable polecat directories in every rig.
+func (c *ReclaimablePolecatCheck) Run(ctx *CheckContext) *CheckResult {
+	c.reclaimable = nil
+
+	if result := skipPolecatsWithoutTmux(c.Name()); result != nil {
+		return result
+	}
+
+	rigs, err := discoverRigs(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not read rigs registry",
+			Details: []string{err.Error()},
+		}
+	}
+	sort.Strings(rigs)
+
+	for _, rigName := range rigs {
+		c.reclaimable = append(c.reclaimable, findReclaimablePolecatDirs(ctx, ctx.TownRoot, rigName)...)
+	}
+
+	if len(c.reclaimable) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No reclaimable polecat directories",
+		}
+	}
+
+	var details []string
+	for _, d := range c.reclaimable {
+		detail := fmt.Sprintf("%s/polecats/%s (%s)", d.rigName, d.name, d.reason)
+		if d.dirty {
+			detail += " - has uncommitted changes, kept"
+		}
+		details = append(details, detail)
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d reclaimable polecat director(ies)", len(c.reclaimable)),
+		Details: details,
+		Actions: []FixAction{doctorFix("archive unmerged commits (salvage/*) and remove them", true)},
+	}
+}
+
+// Fix archives any commits that exist nowhere else onto a salvage/* branch
+// and removes each reclaimable directory. Directories with uncommitted
+// changes are left for a human to review.
+func (c *ReclaimablePolecatCheck) Fix(ctx *CheckContext) error {
+	var errs []string
+	for _, d := range c.reclaimable {
+		if d.dirty {
+			continue
+		}
+		branch, err := salvagePolecatBranch(ctx, d.stalePolecatDir)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: archive failed, left in place: %v", d.rigName, d.name, err))
+			continue
+		}
+		if branch != "" {
+			ctx.Record(ActionBranchCreated, branch)
+		}
+
+		r := &rig.Rig{Name: d.rigName, Path: filepath.Join(ctx.TownRoot, d.rigName)}
+		mgr := polecat.NewManager(r, git.NewGit(r.Path))
+		if err := mgr.RemoveWithOptions(d.name, true, true); err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %v", d.rigName, d.name, err))
+			continue
+		}
+		ctx.Record(ActionDirRemoved, d.path)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// findReclaimablePolecatDirs returns the finished polecat directories in a rig.
+func findReclaimablePolecatDirs(ctx context.Context, townRoot, rigName string) []reclaimablePolecatDir {
+	polecatsDir := filepath.Join(townRoot, rigName, "polecats")
+	defaultBranch := (&BranchCheck{}).getExpectedBranch(townRoot, polecatsDir)
+
+	var found []reclaimablePolecatDir
+	for _, name := range listAgentDirs(polecatsDir) {
+		dir := filepath.Join(polecatsDir, name)
+		if _, err := os.Stat(filepath.Join(dir, ".runtime", "session_id")); err != nil {
+			continue // never started
+		}
+		if polecatSessionRunning(session.PolecatSessionName(rigName, name)) {
+			continue
+		}
+		hook, known := polecatHookBead(townRoot, rigName, name)
+		if !known {
+			continue
+		}
+
+		var reason string
+		if hook != "" {
+			if closed, known := polecatWorkClosed(townRoot, rigName, hook); !known || !closed {
+				continue
+			}
+			reason = hook + " is closed"
+		} else {
+			if _, err := gitIn(ctx, dir, "merge-base", "--is-ancestor", "HEAD", "origin/"+defaultBranch); err != nil {
+				continue // unmerged, or not a checkout
+			}
+			reason = "branch merged into " + defaultBranch
+		}
+
+		status, _ := gitIn(ctx, dir, "status", "--porcelain", "--", ".", ":!.runtime")
+		found = append(found, reclaimablePolecatDir{
+			stalePolecatDir: stalePolecatDir{rigName: rigName, name: name, path: dir},
+			reason:          reason,
+			dirty:           status != "",
+		})
+	}
+	return found
+}
@@ -0,0 +1,56 @@
+package doctor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindReclaimablePolecatDirs(t *testing.T) {
+	townRoot := t.TempDir()
+	repo := filepath.Join(townRoot, "gp", "mayor", "rig")
+	initGitRepo(t, repo)
+
+	worktree := func(name string, commit, started bool) string {
+		dir := filepath.Join(townRoot, "gp", "polecats", name)
+		runGit(t, repo, "worktree", "add", "-q", "-b", "polecat/"+name, dir)
+		if commit {
+			mustWrite(t, filepath.Join(dir, name+".go"), "package x\n")
+			runGit(t, dir, "add", name+".go")
+			runGit(t, dir, "commit", "-q", "-m", name)
+		}
+		if started {
+			mustWrite(t, filepath.Join(dir, ".runtime", "session_id"), "abc\n")
+		}
+		return dir
+	}
+	merged := worktree("Merged", true, true)
+	runGit(t, repo, "update-ref", "refs/remotes/origin/main", runGit(t, merged, "rev-parse", "HEAD"))
+	worktree("Unmerged", true, true)
+	worktree("NeverStarted", false, false)
+	done := worktree("Done", true, true)
+	mustWrite(t, filepath.Join(done, "wip.go"), "package x // wip\n")
+	worktree("Open", true, true)
+	worktree("Running", false, true)
+
+	stubPolecatSeams(t,
+		map[string]bool{"gt-gp-Running": true},
+		map[string]string{"Done": "gp-1", "Open": "gp-2"})
+	origClosed := polecatWorkClosed
+	t.Cleanup(func() { polecatWorkClosed = origClosed })
+	polecatWorkClosed = func(_, _, id string) (bool, bool) { return id == "gp-1", true }
+
+	got := map[string]reclaimablePolecatDir{}
+	for _, d := range findReclaimablePolecatDirs(context.Background(), townRoot, "gp") {
+		got[d.name] = d
+	}
+	if len(got) != 2 {
+		t.Fatalf("reclaimable = %+v, want Merged and Done", got)
+	}
+	if d, ok := got["Merged"]; !ok || d.dirty {
+		t.Errorf("Merged = %+v, want clean reclaimable", d)
+	}
+	if d, ok := got["Done"]; !ok || !d.dirty {
+		t.Errorf("Done = %+v, want dirty reclaimable", d)
+	}
+}
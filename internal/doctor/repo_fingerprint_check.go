@@ -2,6 +2,7 @@ package doctor
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -56,7 +57,7 @@ func (c *RepoFingerprintCheck) Run(ctx *CheckContext) *CheckResult {
 	// Check town-level beads
 	townBeadsDir := filepath.Join(ctx.TownRoot, ".beads")
 	if _, err := os.Stat(townBeadsDir); err == nil {
-		result := c.checkBeadsDir(filepath.Dir(townBeadsDir), "town")
+		result := c.checkBeadsDir(ctx, filepath.Dir(townBeadsDir), "town")
 		if result.Status != StatusOK {
 			return result
 		}
@@ -66,7 +67,7 @@ func (c *RepoFingerprintCheck) Run(ctx *CheckContext) *CheckResult {
 	if ctx.RigName != "" {
 		rigBeadsDir := beads.ResolveBeadsDir(ctx.RigPath())
 		if _, err := os.Stat(rigBeadsDir); err == nil {
-			result := c.checkBeadsDir(filepath.Dir(rigBeadsDir), "rig "+ctx.RigName)
+			result := c.checkBeadsDir(ctx, filepath.Dir(rigBeadsDir), "rig "+ctx.RigName)
 			if result.Status != StatusOK {
 				return result
 			}
@@ -81,9 +82,9 @@ func (c *RepoFingerprintCheck) Run(ctx *CheckContext) *CheckResult {
 }
 
 // checkBeadsDir checks a single beads directory for repo fingerprint using bd doctor.
-func (c *RepoFingerprintCheck) checkBeadsDir(workDir, location string) *CheckResult {
+func (c *RepoFingerprintCheck) checkBeadsDir(ctx context.Context, workDir, location string) *CheckResult {
 	// Run bd doctor --json to get fingerprint status
-	cmd := exec.Command("bd", "doctor", "--json")
+	cmd := exec.CommandContext(ctx, "bd", "doctor", "--json")
 	cmd.Dir = workDir
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -126,7 +127,7 @@ func (c *RepoFingerprintCheck) checkBeadsDir(workDir, location string) *CheckRes
 						}
 						return nil
 					}(),
-					FixHint: "Run 'gt doctor --fix' or 'bd migrate --update-repo-id'",
+					Actions: []FixAction{doctorFix("add the fingerprint", false), {Command: "bd", Args: []string{"migrate", "--update-repo-id"}, Dir: workDir}},
 				}
 			case "error":
 				c.needsMigration = true
@@ -141,7 +142,7 @@ func (c *RepoFingerprintCheck) checkBeadsDir(workDir, location string) *CheckRes
 						}
 						return nil
 					}(),
-					FixHint: "Run 'gt doctor --fix' or 'bd migrate --update-repo-id'",
+					Actions: []FixAction{doctorFix("add the fingerprint", false), {Command: "bd", Args: []string{"migrate", "--update-repo-id"}, Dir: workDir}},
 				}
 			}
 		}
@@ -162,7 +163,7 @@ func (c *RepoFingerprintCheck) Fix(ctx *CheckContext) error {
 	}
 
 	// Run bd migrate --update-repo-id
-	cmd := exec.Command("bd", "migrate", "--update-repo-id")
+	cmd := exec.CommandContext(ctx, "bd", "migrate", "--update-repo-id")
 	cmd.Dir = filepath.Dir(c.beadsDir) // Parent of .beads directory
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
@@ -174,7 +175,7 @@ func (c *RepoFingerprintCheck) Fix(ctx *CheckContext) error {
 	running, _, err := daemon.IsRunning(ctx.TownRoot)
 	if err == nil && running {
 		// Stop daemon
-		stopCmd := exec.Command("gt", "daemon", "stop")
+		stopCmd := exec.CommandContext(ctx, "gt", "daemon", "stop")
 		stopCmd.Dir = ctx.TownRoot
 		_ = stopCmd.Run() // Ignore errors
 
@@ -182,7 +183,7 @@ func (c *RepoFingerprintCheck) Fix(ctx *CheckContext) error {
 		time.Sleep(500 * time.Millisecond)
 
 		// Start daemon
-		startCmd := exec.Command("gt", "daemon", "run")
+		startCmd := exec.CommandContext(ctx, "gt", "daemon", "run")
 		startCmd.Dir = ctx.TownRoot
 		startCmd.Stdin = nil
 		startCmd.Stdout = nil
@@ -60,7 +60,7 @@ func (c *RigIsGitRepoCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Verify git status works
-	cmd := exec.Command("git", "-C", mayorRigPath, "status", "--porcelain")
+	cmd := exec.CommandContext(ctx, "git", "-C", mayorRigPath, "status", "--porcelain")
 	if err := cmd.Run(); err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -97,6 +97,7 @@ func NewGitExcludeConfiguredCheck() *GitExcludeConfiguredCheck {
 			BaseCheck: BaseCheck{
 				CheckName:        "git-exclude-configured",
 				CheckDescription: "Check .git/info/exclude has Gas Town directories",
+				CheckDependsOn:   []string{"rig-is-git-repo"},
 			},
 		},
 	}
@@ -188,7 +189,7 @@ func (c *GitExcludeConfiguredCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("%d Gas Town directories not excluded", len(c.missingEntries)),
 		Details: []string{fmt.Sprintf("Missing: %s", strings.Join(c.missingEntries, ", "))},
-		FixHint: "Run 'gt doctor --fix' to add missing entries",
+		Actions: []FixAction{doctorFix("add missing entries", false)},
 	}
 }
 
@@ -303,7 +304,7 @@ func (c *HooksPathConfiguredCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 
 		// Check core.hooksPath
-		cmd := exec.Command("git", "-C", clonePath, "config", "--get", "core.hooksPath")
+		cmd := exec.CommandContext(ctx, "git", "-C", clonePath, "config", "--get", "core.hooksPath")
 		output, err := cmd.Output()
 		if err != nil || strings.TrimSpace(string(output)) != ".githooks" {
 			// Get relative path for cleaner output
@@ -338,14 +339,14 @@ func (c *HooksPathConfiguredCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: fmt.Sprintf("%d clone(s) missing hooks configuration", len(c.unconfiguredClones)),
 		Details: details,
-		FixHint: "Run 'gt doctor --fix' to configure hooks",
+		Actions: []FixAction{doctorFix("configure hooks", false)},
 	}
 }
 
 // Fix configures core.hooksPath for all unconfigured clones.
 func (c *HooksPathConfiguredCheck) Fix(ctx *CheckContext) error {
 	for _, clonePath := range c.unconfiguredClones {
-		cmd := exec.Command("git", "-C", clonePath, "config", "core.hooksPath", ".githooks")
+		cmd := exec.CommandContext(ctx, "git", "-C", clonePath, "config", "core.hooksPath", ".githooks")
 		if err := cmd.Run(); err != nil {
 			return fmt.Errorf("failed to configure hooks for %s: %w", clonePath, err)
 		}
@@ -426,7 +427,7 @@ func (c *WitnessExistsCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: "Witness structure incomplete",
 		Details: issues,
-		FixHint: "Run 'gt doctor --fix' to create missing structure",
+		Actions: []FixAction{doctorFix("create missing structure", false)},
 	}
 }
 
@@ -446,6 +447,9 @@ func (c *WitnessExistsCheck) Fix(ctx *CheckContext) error {
 			return fmt.Errorf("failed to create witness/mail/: %w", err)
 		}
 		inboxPath := filepath.Join(mailDir, "inbox.jsonl")
+		if err := ctx.Backup.Save(inboxPath); err != nil {
+			return err
+		}
 		if err := os.WriteFile(inboxPath, []byte{}, 0644); err != nil {
 			return fmt.Errorf("failed to create inbox.jsonl: %w", err)
 		}
@@ -532,7 +536,7 @@ func (c *RefineryExistsCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: "Refinery structure incomplete",
 		Details: issues,
-		FixHint: "Run 'gt doctor --fix' to create missing structure",
+		Actions: []FixAction{doctorFix("create missing structure", false)},
 	}
 }
 
@@ -552,6 +556,9 @@ func (c *RefineryExistsCheck) Fix(ctx *CheckContext) error {
 			return fmt.Errorf("failed to create refinery/mail/: %w", err)
 		}
 		inboxPath := filepath.Join(mailDir, "inbox.jsonl")
+		if err := ctx.Backup.Save(inboxPath); err != nil {
+			return err
+		}
 		if err := os.WriteFile(inboxPath, []byte{}, 0644); err != nil {
 			return fmt.Errorf("failed to create inbox.jsonl: %w", err)
 		}
@@ -629,7 +636,7 @@ func (c *MayorCloneExistsCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusWarning,
 		Message: "Mayor structure incomplete",
 		Details: issues,
-		FixHint: "Run 'gt doctor --fix' to create structure (clone requires repo URL)",
+		Actions: []FixAction{doctorFix("create structure (clone requires repo URL)", false)},
 	}
 }
 
@@ -714,7 +721,7 @@ func (c *PolecatClonesValidCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 
 		// Verify git status works and check for uncommitted changes
-		cmd := exec.Command("git", "-C", polecatPath, "status", "--porcelain")
+		cmd := exec.CommandContext(ctx, "git", "-C", polecatPath, "status", "--porcelain")
 		output, err := cmd.Output()
 		if err != nil {
 			issues = append(issues, fmt.Sprintf("%s: git status failed", polecatName))
@@ -726,7 +733,7 @@ func (c *PolecatClonesValidCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 
 		// Check if on a polecat branch
-		cmd = exec.Command("git", "-C", polecatPath, "branch", "--show-current")
+		cmd = exec.CommandContext(ctx, "git", "-C", polecatPath, "branch", "--show-current")
 		branchOutput, err := cmd.Output()
 		if err == nil {
 			branch := strings.TrimSpace(string(branchOutput))
@@ -812,7 +819,7 @@ func (c *BeadsConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Check if bd command works
-	cmd := exec.Command("bd", "stats", "--json")
+	cmd := exec.CommandContext(ctx, "bd", "stats", "--json")
 	cmd.Dir = c.rigPath
 	if err := cmd.Run(); err != nil {
 		return &CheckResult{
@@ -825,7 +832,7 @@ func (c *BeadsConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Check sync status
-	cmd = exec.Command("bd", "sync", "--status")
+	cmd = exec.CommandContext(ctx, "bd", "sync", "--status")
 	cmd.Dir = c.rigPath
 	output, err := cmd.CombinedOutput()
 	c.needsSync = false
@@ -839,7 +846,7 @@ func (c *BeadsConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 				Status:  StatusWarning,
 				Message: "Beads out of sync",
 				Details: []string{strings.TrimSpace(outputStr)},
-				FixHint: "Run 'gt doctor --fix' or 'bd sync' to synchronize",
+				Actions: []FixAction{doctorFix("synchronize", false), {Command: "bd", Args: []string{"sync"}, Dir: ctx.RigName, Description: "synchronize"}},
 			}
 		}
 	}
@@ -857,7 +864,7 @@ func (c *BeadsConfigValidCheck) Fix(ctx *CheckContext) error {
 		return nil
 	}
 
-	cmd := exec.Command("bd", "sync")
+	cmd := exec.CommandContext(ctx, "bd", "sync")
 	cmd.Dir = c.rigPath
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -914,7 +921,7 @@ func (c *BeadsRedirectCheck) Run(ctx *CheckContext) *CheckResult {
 					"Beads database not initialized for this rig",
 					"This prevents issue tracking for this rig",
 				},
-				FixHint: "Run 'gt doctor --fix --rig " + ctx.RigName + "' to initialize beads",
+				Actions: []FixAction{rigDoctorFix(ctx.RigName, "initialize beads")},
 			}
 		}
 		return &CheckResult{
@@ -942,7 +949,7 @@ func (c *BeadsRedirectCheck) Run(ctx *CheckContext) *CheckResult {
 				"Local beads with data exist at: .beads/",
 				"Fix will remove local beads and create redirect to tracked beads",
 			},
-			FixHint: "Run 'gt doctor --fix --rig " + ctx.RigName + "' to fix",
+			Actions: []FixAction{rigDoctorFix(ctx.RigName, "")},
 		}
 	}
 
@@ -957,7 +964,7 @@ func (c *BeadsRedirectCheck) Run(ctx *CheckContext) *CheckResult {
 				"Missing redirect at: .beads/redirect",
 				"Without this redirect, bd commands from rig root won't find beads",
 			},
-			FixHint: "Run 'gt doctor --fix' to create the redirect",
+			Actions: []FixAction{doctorFix("create the redirect", false)},
 		}
 	}
 
@@ -977,7 +984,7 @@ func (c *BeadsRedirectCheck) Run(ctx *CheckContext) *CheckResult {
 			Name:    c.Name(),
 			Status:  StatusError,
 			Message: fmt.Sprintf("Redirect points to %q, expected mayor/rig/.beads", target),
-			FixHint: "Run 'gt doctor --fix --rig " + ctx.RigName + "' to correct the redirect",
+			Actions: []FixAction{rigDoctorFix(ctx.RigName, "correct the redirect")},
 		}
 	}
 
@@ -1022,7 +1029,7 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 		}
 
 		// Run bd init with the configured prefix
-		cmd := exec.Command("bd", "init", "--prefix", prefix)
+		cmd := exec.CommandContext(ctx, "bd", "init", "--prefix", prefix)
 		cmd.Dir = rigPath
 		if output, err := cmd.CombinedOutput(); err != nil {
 			// bd might not be installed - create minimal config.yaml
@@ -1035,7 +1042,7 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 		} else {
 			_ = output // bd init succeeded
 			// Configure custom types for Gas Town (beads v0.46.0+)
-			configCmd := exec.Command("bd", "config", "set", "types.custom", "agent,role,rig,convoy,event")
+			configCmd := exec.CommandContext(ctx, "bd", "config", "set", "types.custom", "agent,role,rig,convoy,event")
 			configCmd.Dir = rigPath
 			_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
 		}
@@ -1047,6 +1054,9 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 		// Check if local beads have conflicting data
 		if hasLocalBeads && hasBeadsData(rigBeadsDir) {
 			// Remove conflicting local beads directory
+			if err := ctx.Backup.Save(rigBeadsDir); err != nil {
+				return err
+			}
 			if err := os.RemoveAll(rigBeadsDir); err != nil {
 				return fmt.Errorf("removing conflicting local beads: %w", err)
 			}
@@ -1058,6 +1068,9 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 		}
 
 		// Write redirect file
+		if err := ctx.Backup.Save(redirectPath); err != nil {
+			return err
+		}
 		if err := os.WriteFile(redirectPath, []byte("mayor/rig/.beads\n"), 0644); err != nil {
 			return fmt.Errorf("writing redirect file: %w", err)
 		}
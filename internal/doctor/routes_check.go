@@ -39,7 +39,7 @@ func (c *RoutesCheck) Run(ctx *CheckContext) *CheckResult {
 			Name:    c.Name(),
 			Status:  StatusWarning,
 			Message: "No .beads directory at town root",
-			FixHint: "Run 'bd init' to initialize beads",
+			Actions: []FixAction{{Command: "bd", Args: []string{"init"}, Description: "initialize beads"}},
 		}
 	}
 
@@ -49,7 +49,7 @@ func (c *RoutesCheck) Run(ctx *CheckContext) *CheckResult {
 			Name:    c.Name(),
 			Status:  StatusWarning,
 			Message: "No routes.jsonl file (prefix routing not configured)",
-			FixHint: "Run 'gt doctor --fix' to create routes.jsonl",
+			Actions: []FixAction{doctorFix("create routes.jsonl", false)},
 		}
 	}
 
@@ -153,7 +153,7 @@ func (c *RoutesCheck) Run(ctx *CheckContext) *CheckResult {
 			Status:  status,
 			Message: message,
 			Details: details,
-			FixHint: "Run 'gt doctor --fix' to add missing routes",
+			Actions: []FixAction{doctorFix("add missing routes", false)},
 		}
 	}
 
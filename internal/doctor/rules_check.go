@@ -0,0 +1,118 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+)
+
+// RulesCheck verifies each agent workspace's .cursor/rules holds the full
+// composed rule set for its role (base rules, packs, role and rig
+// fragments) and that every rules file has valid frontmatter.
+type RulesCheck struct {
+	FixableCheck
+	stale []daemon.TemplateTarget
+}
+
+// NewRulesCheck creates a new composed rules check.
+func NewRulesCheck() *RulesCheck {
+	return &RulesCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "cursor-rules",
+				CheckDescription: "Check agents' .cursor/rules match the composed rule set",
+			},
+		},
+	}
+}
+
+// Run composes each provisioned workspace's rules and compares them with
+// the installed files.
+func (c *RulesCheck) Run(ctx *CheckContext) *CheckResult {
+	c.stale = nil
+
+	var rigs []string
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		rigs = append(rigs, filepath.Base(rigPath))
+	}
+
+	var details []string
+	invalid := 0
+	for _, t := range daemon.TemplateTargets(ctx.TownRoot, rigs) {
+		if !cursor.HooksInstalled(t.WorkDir) {
+			continue
+		}
+		report, err := cursor.CheckRules(t.WorkDir, t.Role)
+		if err != nil {
+			invalid++
+			details = append(details, fmt.Sprintf("%s: %v", t.Agent, err))
+			continue
+		}
+		for _, problem := range report.Invalid {
+			invalid++
+			details = append(details, fmt.Sprintf("%s: %s", t.Agent, problem))
+		}
+		var drift []string
+		if len(report.Missing) > 0 {
+			drift = append(drift, "missing "+strings.Join(report.Missing, ", "))
+		}
+		for _, name := range report.Stale {
+			drift = append(drift, fmt.Sprintf("%s (%s)", name, report.Status[name]))
+		}
+		if len(report.Extra) > 0 {
+			drift = append(drift, "no longer composed "+strings.Join(report.Extra, ", "))
+		}
+		if len(drift) > 0 {
+			c.stale = append(c.stale, t)
+			details = append(details, fmt.Sprintf("%s: %s", t.Agent, strings.Join(drift, "; ")))
+		}
+	}
+
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "Agent rules match the composed rule set",
+		}
+	}
+	if invalid > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d rules problem(s) Cursor cannot load", invalid),
+			Details: details,
+			FixHint: "Fix the frontmatter of the listed rules or their sources (templates/cursor/, <rig>/settings/rules/, the rig repo's .gastown/rules.d/), then run 'gt doctor --fix'",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d agent workspace(s) have outdated rules", len(c.stale)),
+		Details: details,
+		Actions: []FixAction{doctorFix("recompose rules", false), {Command: "gt", Args: []string{"settings", "diff"}, Description: "preview the changes"}},
+	}
+}
+
+// Inputs returns the paths this check depends on (see InputFingerprinter).
+func (c *RulesCheck) Inputs(ctx *CheckContext) []string {
+	return agentWorkspaceInputs(ctx.TownRoot)
+}
+
+// Fix rewrites the outdated workspaces' rules. Edited base rules are kept.
+func (c *RulesCheck) Fix(ctx *CheckContext) error {
+	for i, target := range c.stale {
+		if err := ctx.Step(i, len(c.stale), target.Agent); err != nil {
+			return err
+		}
+		if err := ctx.Backup.Save(filepath.Join(target.WorkDir, ".cursor", "rules")); err != nil {
+			return err
+		}
+		if err := cursor.EnsureRulesForRole(target.WorkDir, target.Role); err != nil {
+			return fmt.Errorf("recomposing rules for %s: %w", target.Agent, err)
+		}
+	}
+	return ctx.Step(len(c.stale), len(c.stale), "")
+}
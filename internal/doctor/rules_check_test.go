@@ -0,0 +1,45 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+)
+
+func TestRulesCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	mayorDir := filepath.Join(townRoot, "mayor")
+	if err := cursor.EnsureSettingsForRole(mayorDir, "mayor"); err != nil {
+		t.Fatal(err)
+	}
+	check := NewRulesCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("fresh install: status = %v, details = %v", result.Status, result.Details)
+	}
+
+	roleRules := filepath.Join(mayorDir, ".cursor", "rules", "gastown-role-mayor.mdc")
+	if err := os.Remove(roleRules); err != nil {
+		t.Fatal(err)
+	}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || len(result.Details) != 1 {
+		t.Fatalf("missing role rules: status = %v, details = %v; want one outdated workspace", result.Status, result.Details)
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after Fix: status = %v, details = %v", result.Status, result.Details)
+	}
+
+	base := filepath.Join(mayorDir, ".cursor", "rules", "gastown.mdc")
+	if err := os.WriteFile(base, []byte("no frontmatter\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if result := check.Run(ctx); result.Status != StatusError {
+		t.Errorf("base rules without frontmatter: status = %v, want error", result.Status)
+	}
+}
@@ -0,0 +1,212 @@
+package doctor
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// SARIF 2.1.0 identifiers for code-scanning uploads.
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// sarifRootBase is the uriBaseId that artifact locations are relative to.
+	sarifRootBase = "TOWNROOT"
+)
+
+// SARIF log structure (the subset gt doctor emits).
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription sarifMessage  `json:"shortDescription"`
+	Help             *sarifMessage `json:"help,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// SARIFTown is one town's doctor results for a combined SARIF log.
+type SARIFTown struct {
+	Report   *Report
+	Checks   []Check
+	TownRoot string
+}
+
+// WriteSARIF writes the report as a SARIF 2.1.0 log. Each registered check
+// becomes a rule (ID = check name); each warning or error becomes a result.
+// File paths mentioned in a result's message or details that exist under
+// townRoot become artifact locations; results without one point at the
+// town config so code-scanning dashboards can still display them.
+func (r *Report) WriteSARIF(w io.Writer, checks []Check, townRoot, version string) error {
+	return WriteSARIFTowns(w, []SARIFTown{{Report: r, Checks: checks, TownRoot: townRoot}}, version)
+}
+
+// WriteSARIFTowns writes a SARIF 2.1.0 log with one run per town.
+func WriteSARIFTowns(w io.Writer, towns []SARIFTown, version string) error {
+	log := sarifLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs:    make([]sarifRun, 0, len(towns)),
+	}
+	for _, town := range towns {
+		log.Runs = append(log.Runs, town.Report.sarifRun(town.Checks, town.TownRoot, version))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// sarifRun converts the report into a SARIF run for one town.
+func (r *Report) sarifRun(checks []Check, townRoot, version string) sarifRun {
+	driver := sarifDriver{Name: "gt doctor", Version: version, Rules: []sarifRule{}}
+	ruleIndex := make(map[string]int)
+	addRule := func(id, description string) int {
+		if i, ok := ruleIndex[id]; ok {
+			return i
+		}
+		ruleIndex[id] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: description}})
+		return ruleIndex[id]
+	}
+	for _, check := range checks {
+		addRule(check.Name(), check.Description())
+	}
+
+	results := []sarifResult{}
+	for _, check := range r.Checks {
+		if check.Baselined {
+			continue
+		}
+		var level string
+		switch check.Status {
+		case StatusWarning, StatusTimeout:
+			level = "warning"
+		case StatusError:
+			level = "error"
+		default:
+			continue
+		}
+
+		i := addRule(check.Name, check.Name)
+		hint := check.Hint()
+		if hint != "" && driver.Rules[i].Help == nil {
+			driver.Rules[i].Help = &sarifMessage{Text: hint}
+		}
+
+		text := check.Message
+		if len(check.Details) > 0 {
+			text += "\n" + strings.Join(check.Details, "\n")
+		}
+		if hint != "" {
+			text += "\nFix: " + hint
+		}
+
+		paths := artifactPaths(townRoot, check)
+		if len(paths) == 0 {
+			paths = []string{workspace.PrimaryMarker}
+		}
+		locations := make([]sarifLocation, 0, len(paths))
+		for _, p := range paths {
+			locations = append(locations, sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: p, URIBaseID: sarifRootBase},
+			}})
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    check.Name,
+			RuleIndex: i,
+			Level:     level,
+			Message:   sarifMessage{Text: text},
+			Locations: locations,
+		})
+	}
+
+	root := (&url.URL{Scheme: "file", Path: filepath.ToSlash(townRoot) + "/"}).String()
+	return sarifRun{
+		Tool:               sarifTool{Driver: driver},
+		OriginalURIBaseIDs: map[string]sarifArtifactLocation{sarifRootBase: {URI: root}},
+		Results:            results,
+	}
+}
+
+// artifactPaths returns town-relative, slash-separated paths of existing
+// files mentioned in a result's message or details, in order of appearance.
+func artifactPaths(townRoot string, check *CheckResult) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, text := range append([]string{check.Message}, check.Details...) {
+		for _, field := range strings.Fields(text) {
+			candidate := strings.Trim(field, `"'()[],;:`)
+			if !strings.ContainsAny(candidate, "/.") || strings.Contains(candidate, "://") {
+				continue
+			}
+			abs := candidate
+			if !filepath.IsAbs(abs) {
+				abs = filepath.Join(townRoot, candidate)
+			}
+			rel, err := filepath.Rel(townRoot, abs)
+			if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			if info, err := os.Stat(abs); err != nil || info.IsDir() {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+			if !seen[rel] {
+				seen[rel] = true
+				paths = append(paths, rel)
+			}
+		}
+	}
+	return paths
+}
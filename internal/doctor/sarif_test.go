@@ -0,0 +1,62 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestReportWriteSARIF(t *testing.T) {
+	townRoot := t.TempDir()
+	mustWrite(t, filepath.Join(townRoot, ".cursor", "hooks.json"), "{}")
+
+	checks := []Check{
+		&mockCheck{BaseCheck: BaseCheck{CheckName: "hook-version", CheckDescription: "Check hook scripts are current"}},
+		&mockCheck{BaseCheck: BaseCheck{CheckName: "daemon", CheckDescription: "Check daemon health"}},
+		&mockCheck{BaseCheck: BaseCheck{CheckName: "routes", CheckDescription: "Check routes"}},
+	}
+	report := NewReport()
+	report.Add(&CheckResult{
+		Name:    "hook-version",
+		Status:  StatusWarning,
+		Message: "1 stale hook config",
+		Details: []string{"Stale: .cursor/hooks.json (missing.sh)"},
+		FixHint: "Run 'gt doctor --fix'",
+	})
+	report.Add(&CheckResult{Name: "daemon", Status: StatusError, Message: "daemon heartbeat is stale"})
+	report.Add(&CheckResult{Name: "routes", Status: StatusOK, Message: "ok"})
+
+	var buf bytes.Buffer
+	if err := report.WriteSARIF(&buf, checks, townRoot, "1.2.3"); err != nil {
+		t.Fatalf("WriteSARIF: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if log.Version != SARIFVersion || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log header: %+v", log)
+	}
+	run := log.Runs[0]
+	if got := len(run.Tool.Driver.Rules); got != 3 {
+		t.Errorf("rules = %d, want 3 (one per registered check)", got)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("results = %d, want 2 (OK results omitted)", len(run.Results))
+	}
+
+	stale := run.Results[0]
+	if stale.RuleID != "hook-version" || stale.Level != "warning" || run.Tool.Driver.Rules[stale.RuleIndex].ID != "hook-version" {
+		t.Errorf("unexpected result mapping: %+v", stale)
+	}
+	if len(stale.Locations) != 1 || stale.Locations[0].PhysicalLocation.ArtifactLocation.URI != ".cursor/hooks.json" {
+		t.Errorf("stale path not mapped to artifact location: %+v", stale.Locations)
+	}
+
+	daemon := run.Results[1]
+	if daemon.Level != "error" || daemon.Locations[0].PhysicalLocation.ArtifactLocation.URI != "mayor/town.json" {
+		t.Errorf("result without paths should fall back to town config: %+v", daemon)
+	}
+}
@@ -0,0 +1,127 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/selfupdate"
+)
+
+// updateCheckInterval is how long a release lookup is reused, so doctor
+// runs do not hit the releases API every time.
+const updateCheckInterval = 24 * time.Hour
+
+// updateCheckTimeout bounds the release lookup; offline towns just skip it.
+const updateCheckTimeout = 5 * time.Second
+
+// latestRelease looks up the newest release on a channel (seam for tests).
+var latestRelease = func(ctx context.Context, releasesURL, channel string) (*selfupdate.Release, error) {
+	return selfupdate.NewClient(releasesURL).Latest(ctx, channel)
+}
+
+// updateCheckCache is the last release lookup, in .runtime/update-check.json.
+type updateCheckCache struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Channel   string    `json:"channel"`
+	URL       string    `json:"url,omitempty"`
+	Latest    string    `json:"latest,omitempty"`
+	PageURL   string    `json:"page_url,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// SelfUpdateCheck compares the running gt against the latest release on
+// the town's configured channel (settings/config.json self_update).
+type SelfUpdateCheck struct {
+	BaseCheck
+	now func() time.Time
+}
+
+// NewSelfUpdateCheck creates a new gt release check.
+func NewSelfUpdateCheck() *SelfUpdateCheck {
+	return &SelfUpdateCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "gt-update",
+			CheckDescription: "Check whether a newer gt release is available",
+		},
+		now: time.Now,
+	}
+}
+
+// Run looks up (or reuses a recent lookup of) the latest release.
+func (c *SelfUpdateCheck) Run(ctx *CheckContext) *CheckResult {
+	if ctx.GTVersion == "" {
+		return &CheckResult{Name: c.Name(), Status: StatusOK, Message: "Running gt version unknown; update check skipped"}
+	}
+
+	var cfg *config.SelfUpdateConfig
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot)); err == nil {
+		cfg = settings.SelfUpdate
+	}
+	channel := cfg.UpdateChannel()
+	cache := c.lookup(ctx, cfg.URL(), channel)
+
+	if cache.Error != "" {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("gt %s; could not check for updates", ctx.GTVersion),
+			Details: []string{cache.Error},
+		}
+	}
+	if selfupdate.CompareVersions(cache.Latest, ctx.GTVersion) <= 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("gt %s is the latest %s release", ctx.GTVersion, channel),
+		}
+	}
+
+	details := []string{fmt.Sprintf("Checked %s", cache.CheckedAt.Local().Format("2006-01-02 15:04"))}
+	if cache.PageURL != "" {
+		details = append(details, "Release notes: "+cache.PageURL)
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("gt %s is available on the %s channel (running %s)", cache.Latest, channel, ctx.GTVersion),
+		Details: details,
+		FixHint: "Run 'gt self-update' to download, verify, and install it",
+		Actions: []FixAction{{Command: "gt", Args: []string{"self-update"}, Description: "upgrade gt to " + cache.Latest}},
+	}
+}
+
+// lookup returns the cached release lookup if it is recent and for the same
+// channel, otherwise queries the releases API and caches the outcome.
+// Failures are cached too so an offline town pays the timeout once a day.
+func (c *SelfUpdateCheck) lookup(ctx *CheckContext, url, channel string) *updateCheckCache {
+	path := filepath.Join(ctx.TownRoot, ".runtime", "update-check.json")
+	now := c.now()
+	if data, err := os.ReadFile(path); err == nil {
+		var cached updateCheckCache
+		if json.Unmarshal(data, &cached) == nil && cached.Channel == channel && cached.URL == url &&
+			now.Sub(cached.CheckedAt) < updateCheckInterval && !now.Before(cached.CheckedAt) {
+			return &cached
+		}
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+	defer cancel()
+	cache := &updateCheckCache{CheckedAt: now, Channel: channel, URL: url}
+	if rel, err := latestRelease(lookupCtx, url, channel); err != nil {
+		cache.Error = err.Error()
+	} else {
+		cache.Latest, cache.PageURL = rel.Version(), rel.PageURL
+	}
+
+	if data, err := json.MarshalIndent(cache, "", "  "); err == nil {
+		if os.MkdirAll(filepath.Dir(path), 0755) == nil {
+			_ = os.WriteFile(path, data, 0644)
+		}
+	}
+	return cache
+}
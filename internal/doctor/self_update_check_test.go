@@ -0,0 +1,56 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/selfupdate"
+)
+
+func TestSelfUpdateCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	check := NewSelfUpdateCheck()
+	check.now = func() time.Time { return now }
+
+	lookups := 0
+	latest := &selfupdate.Release{Tag: "v0.4.0", PageURL: "https://example.com/v0.4.0"}
+	var lookupErr error
+	orig := latestRelease
+	latestRelease = func(ctx context.Context, url, channel string) (*selfupdate.Release, error) {
+		lookups++
+		if channel != selfupdate.ChannelStable {
+			t.Errorf("channel = %q, want stable", channel)
+		}
+		return latest, lookupErr
+	}
+	t.Cleanup(func() { latestRelease = orig })
+
+	ctx := &CheckContext{TownRoot: townRoot, GTVersion: "0.3.2"}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || len(result.Actions) != 1 || result.Actions[0].String() != "gt self-update" {
+		t.Fatalf("behind: %v %q %v", result.Status, result.Message, result.Actions)
+	}
+
+	// A second run within a day reuses the lookup
+	ctx.GTVersion = "0.4.0"
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("current: Status = %v, %q", result.Status, result.Message)
+	}
+	if lookups != 1 {
+		t.Errorf("lookups = %d, want 1 (cached)", lookups)
+	}
+
+	// Offline: not a warning, and the failure is cached too
+	now = now.Add(25 * time.Hour)
+	lookupErr = errors.New("dial tcp: no route to host")
+	if result := check.Run(ctx); result.Status != StatusOK || len(result.Details) != 1 {
+		t.Errorf("offline: Status = %v, Details = %v", result.Status, result.Details)
+	}
+	check.Run(ctx)
+	if lookups != 2 {
+		t.Errorf("lookups = %d, want 2", lookups)
+	}
+}
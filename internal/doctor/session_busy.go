@@ -0,0 +1,111 @@
+package doctor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+)
+
+// SessionBusyWindow is how recently a session's pane must have produced
+// output, with no agent stop since, for the agent to count as mid-task.
+const SessionBusyWindow = 2 * time.Minute
+
+// stopSettle allows for the prompt redraw that follows an agent stop, so
+// the output printed after the Stop hook does not read as new work.
+const stopSettle = 15 * time.Second
+
+// paneActivity returns when a session's pane last produced output (seam
+// for tests).
+var paneActivity = func(sess string) (time.Time, error) {
+	return tmux.NewTmux().GetWindowActivity(sess)
+}
+
+// busyProbe tells whether agent sessions are mid-task, from pane output
+// and the agent_stop events the Stop hook logs.
+type busyProbe struct {
+	now   time.Time
+	stops map[string]time.Time // Session -> latest agent stop
+}
+
+// newBusyProbe reads the latest agent stop per session from the town's
+// events log. A missing or unreadable log just leaves pane output to decide.
+func newBusyProbe(townRoot string, now time.Time) *busyProbe {
+	p := &busyProbe{now: now, stops: make(map[string]time.Time)}
+	file, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		return p
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Type != events.TypeAgentStop {
+			continue
+		}
+		sess, _ := e.Payload["session"].(string)
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if sess == "" || err != nil {
+			continue
+		}
+		if at.After(p.stops[sess]) {
+			p.stops[sess] = at
+		}
+	}
+	return p
+}
+
+// SessionBusy reports whether an agent session looks mid-task, with the
+// reason, so commands that cycle sessions can leave working agents alone.
+func SessionBusy(townRoot, sess string) (bool, string) {
+	return newBusyProbe(townRoot, time.Now()).busy(sess)
+}
+
+// busy reports whether sess looks mid-task: its pane produced output within
+// SessionBusyWindow and its agent has not stopped since. The reason says
+// why, for the prompt or deferral notice. Sessions whose activity cannot
+// be read are treated as idle.
+func (p *busyProbe) busy(sess string) (bool, string) {
+	active, err := paneActivity(sess)
+	if err != nil || active.IsZero() {
+		return false, ""
+	}
+	idleFor := p.now.Sub(active)
+	if idleFor >= SessionBusyWindow {
+		return false, ""
+	}
+	stop, stopped := p.stops[sess]
+	if stopped && !stop.Before(active.Add(-stopSettle)) {
+		return false, ""
+	}
+
+	reason := fmt.Sprintf("output %s ago", idleFor.Round(time.Second))
+	if stopped {
+		reason += fmt.Sprintf(", last agent stop %s ago", p.now.Sub(stop).Round(time.Second))
+	} else {
+		reason += ", no agent stop recorded"
+	}
+	return true, reason
+}
+
+// restartAllowed decides whether a fix may kill sess. Idle sessions may go;
+// a busy one is put to ctx.ConfirmRestart when set, and otherwise deferred
+// with a notice so the next --fix run picks it up once the agent stops.
+func (p *busyProbe) restartAllowed(ctx *CheckContext, sess string) bool {
+	busy, reason := p.busy(sess)
+	if !busy {
+		return true
+	}
+	if ctx.ConfirmRestart != nil && ctx.ConfirmRestart(sess, reason) {
+		return true
+	}
+	fmt.Printf("  Deferred restart of %s: agent is busy (%s); rerun gt doctor --fix once it stops\n", sess, reason)
+	return false
+}
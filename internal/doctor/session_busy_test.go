@@ -0,0 +1,76 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestBusyProbe(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	stop := func(at time.Time, sess string) string {
+		return fmt.Sprintf(`{"ts":%q,"type":"agent_stop","actor":"x","payload":{"session":%q}}`, at.Format(time.RFC3339), sess) + "\n"
+	}
+	mustWrite(t, filepath.Join(townRoot, events.EventsFile),
+		stop(now.Add(-10*time.Minute), "gt-gastown-witness")+
+			stop(now.Add(-25*time.Second), "gt-gastown-refinery")+
+			stop(now.Add(-time.Hour), "gt-gastown-refinery")+ // Older stop logged later
+			"not json\n")
+
+	activity := map[string]time.Time{
+		"gt-gastown-witness":  now.Add(-20 * time.Second), // Working since its last stop
+		"gt-gastown-refinery": now.Add(-30 * time.Second), // Prompt redraw around its stop
+		"hq-mayor":            now.Add(-5 * time.Second),  // Never stopped
+		"hq-deacon":           now.Add(-10 * time.Minute), // Quiet
+	}
+	orig := paneActivity
+	paneActivity = func(sess string) (time.Time, error) {
+		if at, ok := activity[sess]; ok {
+			return at, nil
+		}
+		return time.Time{}, errors.New("no session")
+	}
+	t.Cleanup(func() { paneActivity = orig })
+
+	probe := newBusyProbe(townRoot, now)
+	tests := []struct {
+		sess   string
+		busy   bool
+		reason string
+	}{
+		{"gt-gastown-witness", true, "output 20s ago, last agent stop 10m0s ago"},
+		{"gt-gastown-refinery", false, ""},
+		{"hq-mayor", true, "output 5s ago, no agent stop recorded"},
+		{"hq-deacon", false, ""},
+		{"gt-gastown-gone", false, ""},
+	}
+	for _, tt := range tests {
+		busy, reason := probe.busy(tt.sess)
+		if busy != tt.busy || reason != tt.reason {
+			t.Errorf("busy(%s) = %v, %q; want %v, %q", tt.sess, busy, reason, tt.busy, tt.reason)
+		}
+	}
+
+	// Busy sessions are deferred without a prompt and follow the answer with one
+	ctx := &CheckContext{TownRoot: townRoot}
+	if probe.restartAllowed(ctx, "hq-mayor") {
+		t.Error("restartAllowed(busy) without ConfirmRestart = true, want deferred")
+	}
+	if !probe.restartAllowed(ctx, "hq-deacon") {
+		t.Error("restartAllowed(idle) = false, want true")
+	}
+	var asked string
+	ctx.ConfirmRestart = func(sess, reason string) bool {
+		asked = sess
+		return true
+	}
+	if !probe.restartAllowed(ctx, "hq-mayor") || asked != "hq-mayor" {
+		t.Errorf("restartAllowed with confirmation: asked %q", asked)
+	}
+}
@@ -0,0 +1,210 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/boot"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+)
+
+// SessionNameCheck detects tmux session names that collide with the agent
+// session naming scheme: two agents resolving to the same session name,
+// names that parse back to a different agent, and live sessions not started
+// by gt that shadow an agent's session.
+type SessionNameCheck struct {
+	BaseCheck
+}
+
+// NewSessionNameCheck creates a new session name check.
+func NewSessionNameCheck() *SessionNameCheck {
+	return &SessionNameCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "session-names",
+			CheckDescription: "Detect colliding or shadowed agent tmux session names",
+		},
+	}
+}
+
+// agentSession is the tmux session an agent is expected to run in.
+type agentSession struct {
+	Session string // session name as gt constructs it
+	Agent   string // mail-style address, e.g. "gastown/polecats/Toast"
+	Rig     string // empty for town-level agents
+	Role    string // GT_ROLE value for town-level agents
+}
+
+// Run checks the town's agents and live tmux sessions for name conflicts.
+func (c *SessionNameCheck) Run(ctx *CheckContext) *CheckResult {
+	agents, err := expectedAgentSessions(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not enumerate agents",
+			Details: []string{err.Error()},
+		}
+	}
+
+	collisions := sessionNameCollisions(agents)
+	ambiguous := ambiguousSessionNames(agents)
+
+	var shadowed []string
+	t := tmux.NewTmux()
+	if sessions, err := t.ListSessions(); err == nil {
+		shadowed = shadowedSessions(sessions, agents, func(sess, key string) string {
+			v, _ := t.GetEnvironment(sess, key)
+			return v
+		})
+	}
+
+	if len(collisions)+len(ambiguous)+len(shadowed) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%d agent session names are unique", len(agents)),
+		}
+	}
+
+	details := append(append(append([]string{}, collisions...), ambiguous...), shadowed...)
+	if len(collisions) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d agent session name collision(s)", len(collisions)),
+			Details: details,
+			FixHint: "Rename one of the colliding rigs, crew members, or polecats",
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d session name conflict(s)", len(ambiguous)+len(shadowed)),
+		Details: details,
+		FixHint: "Rename or kill shadowing sessions (tmux rename-session -t <name> <new>); rename ambiguous agents",
+	}
+}
+
+// expectedAgentSessions lists the session of every agent in the town.
+func expectedAgentSessions(townRoot string) ([]agentSession, error) {
+	agents := []agentSession{
+		{Session: session.MayorSessionName(), Agent: "mayor", Role: "mayor"},
+		{Session: session.DeaconSessionName(), Agent: "deacon", Role: "deacon"},
+		{Session: boot.SessionName, Agent: "boot", Role: "boot"},
+	}
+
+	rigs, err := discoverRigs(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(rigs)
+	for _, rigName := range rigs {
+		rigPath := filepath.Join(townRoot, rigName)
+		agents = append(agents,
+			agentSession{Session: session.WitnessSessionName(rigName), Agent: rigName + "/witness", Rig: rigName},
+			agentSession{Session: session.RefinerySessionName(rigName), Agent: rigName + "/refinery", Rig: rigName},
+		)
+		for _, name := range listAgentDirs(filepath.Join(rigPath, "crew")) {
+			agents = append(agents, agentSession{Session: session.CrewSessionName(rigName, name), Agent: rigName + "/crew/" + name, Rig: rigName})
+		}
+		for _, name := range listAgentDirs(filepath.Join(rigPath, "polecats")) {
+			agents = append(agents, agentSession{Session: session.PolecatSessionName(rigName, name), Agent: rigName + "/polecats/" + name, Rig: rigName})
+		}
+	}
+	return agents, nil
+}
+
+// listAgentDirs returns the non-hidden subdirectories of dir.
+func listAgentDirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// tmuxSessionName returns the name tmux actually gives a session: tmux
+// replaces '.' and ':' because they are target separators.
+func tmuxSessionName(name string) string {
+	return strings.NewReplacer(".", "_", ":", "_").Replace(name)
+}
+
+// sessionNameCollisions reports session names shared by more than one agent.
+func sessionNameCollisions(agents []agentSession) []string {
+	byName := make(map[string][]string)
+	var order []string
+	for _, a := range agents {
+		name := tmuxSessionName(a.Session)
+		if _, seen := byName[name]; !seen {
+			order = append(order, name)
+		}
+		byName[name] = append(byName[name], a.Agent)
+	}
+	var out []string
+	for _, name := range order {
+		if owners := byName[name]; len(owners) > 1 {
+			out = append(out, fmt.Sprintf("%s is claimed by %s: only one can run", name, strings.Join(owners, " and ")))
+		}
+	}
+	return out
+}
+
+// ambiguousSessionNames reports agent sessions that parse back to a
+// different agent, e.g. polecat "b" in rig "a-crew" (gt-a-crew-b) reads as
+// crew member "b" of rig "a". Lifecycle, nudges and status use the parsed
+// identity, so the agent would be misrouted.
+func ambiguousSessionNames(agents []agentSession) []string {
+	var out []string
+	for _, a := range agents {
+		id, err := session.ParseSessionName(a.Session)
+		if err != nil {
+			continue // boot and other fixed names
+		}
+		if parsed := id.Address(); parsed != a.Agent {
+			out = append(out, fmt.Sprintf("%s (%s) parses as %s: lifecycle and nudges would target the wrong agent", a.Session, a.Agent, parsed))
+		}
+	}
+	return out
+}
+
+// shadowedSessions reports live sessions that carry an agent's session name
+// but were not started by gt for that agent (their GT_RIG or GT_ROLE does not
+// match). gt treats such a session as the agent already running, so the real
+// agent will not start.
+func shadowedSessions(sessions []string, agents []agentSession, env func(session, key string) string) []string {
+	byName := make(map[string]agentSession, len(agents))
+	for _, a := range agents {
+		byName[tmuxSessionName(a.Session)] = a
+	}
+	var out []string
+	for _, sess := range sessions {
+		a, ok := byName[sess]
+		if !ok {
+			continue
+		}
+		key, want := "GT_RIG", a.Rig
+		if a.Rig == "" {
+			key, want = "GT_ROLE", a.Role
+		}
+		got := env(sess, key)
+		if got == want {
+			continue
+		}
+		found := key + " unset"
+		if got != "" {
+			found = fmt.Sprintf("%s=%s", key, got)
+		}
+		out = append(out, fmt.Sprintf("%s is not %s's session (%s): %s cannot start while it exists", sess, a.Agent, found, a.Agent))
+	}
+	return out
+}
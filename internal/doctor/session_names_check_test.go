@@ -0,0 +1,94 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpectedAgentSessions(t *testing.T) {
+	townRoot := t.TempDir()
+	mustWrite(t, filepath.Join(townRoot, "mayor", "rigs.json"), `{"version":1,"rigs":{"gp":{},"a-crew":{}}}`)
+	for _, dir := range []string{"gp/crew/max", "gp/polecats/Toast", "gp/polecats/.claude", "a-crew/polecats/b"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	agents, err := expectedAgentSessions(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, a := range agents {
+		got[a.Session] = a.Agent
+	}
+	want := map[string]string{
+		"hq-mayor":          "mayor",
+		"gt-boot":           "boot",
+		"gt-gp-witness":     "gp/witness",
+		"gt-gp-crew-max":    "gp/crew/max",
+		"gt-gp-Toast":       "gp/polecats/Toast",
+		"gt-a-crew-b":       "a-crew/polecats/b",
+		"gt-a-crew-witness": "a-crew/witness",
+	}
+	for sess, agent := range want {
+		if got[sess] != agent {
+			t.Errorf("session %s = %q, want %q", sess, got[sess], agent)
+		}
+	}
+	if _, ok := got["gt-gp-.claude"]; ok {
+		t.Error("hidden directories should not be agents")
+	}
+}
+
+func TestSessionNameCollisions(t *testing.T) {
+	agents := []agentSession{
+		{Session: "gt-my.app-witness", Agent: "my.app/witness", Rig: "my.app"},
+		{Session: "gt-my_app-witness", Agent: "my_app/witness", Rig: "my_app"},
+		{Session: "gt-gp-witness", Agent: "gp/witness", Rig: "gp"},
+		{Session: "gt-gp-witness", Agent: "gp/polecats/witness", Rig: "gp"},
+		{Session: "gt-gp-Toast", Agent: "gp/polecats/Toast", Rig: "gp"},
+	}
+	got := sessionNameCollisions(agents)
+	if len(got) != 2 {
+		t.Fatalf("collisions = %v, want 2", got)
+	}
+	if !strings.Contains(got[0], "gt-my_app-witness is claimed by my.app/witness and my_app/witness") {
+		t.Errorf("truncation collision = %q", got[0])
+	}
+	if !strings.Contains(got[1], "gp/witness and gp/polecats/witness") {
+		t.Errorf("role-name collision = %q", got[1])
+	}
+}
+
+func TestAmbiguousSessionNames(t *testing.T) {
+	agents := []agentSession{
+		{Session: "gt-a-crew-b", Agent: "a-crew/polecats/b", Rig: "a-crew"},
+		{Session: "gt-gp-Toast", Agent: "gp/polecats/Toast", Rig: "gp"},
+		{Session: "gt-boot", Agent: "boot", Role: "boot"},
+	}
+	got := ambiguousSessionNames(agents)
+	if len(got) != 1 || !strings.Contains(got[0], "parses as a/crew/b") {
+		t.Errorf("ambiguous = %v", got)
+	}
+}
+
+func TestShadowedSessions(t *testing.T) {
+	agents := []agentSession{
+		{Session: "hq-mayor", Agent: "mayor", Role: "mayor"},
+		{Session: "gt-gp-witness", Agent: "gp/witness", Rig: "gp"},
+		{Session: "gt-gp-Toast", Agent: "gp/polecats/Toast", Rig: "gp"},
+	}
+	env := map[string]string{
+		"hq-mayor/GT_ROLE":     "mayor",
+		"gt-gp-Toast/GT_RIG":   "gp",
+		"gt-gp-witness/GT_RIG": "",
+	}
+	got := shadowedSessions([]string{"hq-mayor", "gt-gp-witness", "gt-gp-Toast", "scratch"}, agents,
+		func(sess, key string) string { return env[sess+"/"+key] })
+	if len(got) != 1 || !strings.Contains(got[0], "gt-gp-witness is not gp/witness's session (GT_RIG unset)") {
+		t.Errorf("shadowed = %v", got)
+	}
+}
@@ -0,0 +1,156 @@
+package doctor
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+// permIssue is a settings, hook, or state path another user could modify.
+type permIssue struct {
+	path    string
+	mode    fs.FileMode
+	foreign bool // owned by another user; chmod cannot fix it
+}
+
+// SettingsPermsCheck flags hook configuration, hook scripts, and event and
+// state files that are group- or world-writable or owned by another user.
+// Agents execute whatever hooks.json points at, so a writable hook file
+// lets anyone on the machine run commands as the agent.
+type SettingsPermsCheck struct {
+	FixableCheck
+	issues []permIssue
+}
+
+// NewSettingsPermsCheck creates a new settings permission check.
+func NewSettingsPermsCheck() *SettingsPermsCheck {
+	return &SettingsPermsCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "settings-perms",
+				CheckDescription: "Check hooks and state files are not writable by other users",
+			},
+		},
+	}
+}
+
+// Run stats every hook, settings, and state path gt trusts.
+func (c *SettingsPermsCheck) Run(ctx *CheckContext) *CheckResult {
+	c.issues = nil
+	uid := os.Getuid()
+
+	for _, path := range settingsPermPaths(ctx.TownRoot) {
+		info, err := os.Lstat(path)
+		if err != nil || info.Mode()&fs.ModeSymlink != 0 {
+			continue
+		}
+		owner, known := fileOwner(info)
+		foreign := known && uid >= 0 && owner != uid
+		if info.Mode().Perm()&0o022 == 0 && !foreign {
+			continue
+		}
+		c.issues = append(c.issues, permIssue{path: path, mode: info.Mode(), foreign: foreign})
+	}
+
+	if len(c.issues) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "Hooks and state files are writable only by their owner",
+		}
+	}
+
+	status := StatusWarning
+	var details []string
+	var hint string
+	for _, issue := range c.issues {
+		rel, err := filepath.Rel(ctx.TownRoot, issue.path)
+		if err != nil {
+			rel = issue.path
+		}
+		switch {
+		case issue.foreign:
+			details = append(details, fmt.Sprintf("%s: owned by another user", rel))
+			hint = "Files owned by another user need 'chown' by that user or root"
+		case issue.mode.Perm()&0o002 != 0:
+			status = StatusError
+			details = append(details, fmt.Sprintf("%s: world-writable (%s)", rel, issue.mode.Perm()))
+		default:
+			details = append(details, fmt.Sprintf("%s: group-writable (%s)", rel, issue.mode.Perm()))
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  status,
+		Message: fmt.Sprintf("%d hook or state path(s) could be modified by other users", len(c.issues)),
+		Details: details,
+		FixHint: hint,
+		Actions: []FixAction{doctorFix("remove group and world write access", false)},
+	}
+}
+
+// Fix removes group and world write bits. Execute bits on hook scripts are
+// kept; files owned by other users are left for their owner.
+func (c *SettingsPermsCheck) Fix(ctx *CheckContext) error {
+	for _, issue := range c.issues {
+		if issue.foreign {
+			continue
+		}
+		if err := os.Chmod(issue.path, issue.mode.Perm()&^0o022); err != nil {
+			return fmt.Errorf("chmod %s: %w", issue.path, err)
+		}
+	}
+	return nil
+}
+
+// settingsPermPaths lists the paths whose contents gt or its agents act on:
+// each agent workspace's .cursor directory, hooks.json, and hook scripts;
+// the town events log; and daemon and runtime state.
+func settingsPermPaths(townRoot string) []string {
+	var rigs []string
+	for _, rigPath := range findAllRigs(townRoot) {
+		rigs = append(rigs, filepath.Base(rigPath))
+	}
+
+	var paths []string
+	for _, t := range daemon.TemplateTargets(townRoot, rigs) {
+		cursorDir := filepath.Join(t.WorkDir, ".cursor")
+		hooksDir := filepath.Join(cursorDir, "hooks")
+		paths = append(paths, cursorDir, filepath.Join(cursorDir, "hooks.json"))
+		paths = append(paths, dirAndEntries(hooksDir)...)
+	}
+
+	paths = append(paths, filepath.Join(townRoot, events.EventsFile))
+	paths = append(paths, dirAndEntries(filepath.Join(townRoot, "daemon"))...)
+	paths = append(paths, dirAndEntries(filepath.Join(townRoot, constants.DirRuntime))...)
+	for _, rig := range rigs {
+		paths = append(paths, dirAndEntries(filepath.Join(townRoot, rig, constants.DirRuntime))...)
+	}
+	return paths
+}
+
+// dirAndEntries returns dir followed by its immediate entries, sorted.
+// A missing directory yields just dir, which the caller skips.
+func dirAndEntries(dir string) []string {
+	paths := []string{dir}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return paths
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		paths = append(paths, filepath.Join(dir, name))
+	}
+	return paths
+}
@@ -0,0 +1,69 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+)
+
+func TestSettingsPermsCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix permissions")
+	}
+
+	townRoot := t.TempDir()
+	witnessDir := filepath.Join(townRoot, "gastown", "witness")
+	if err := cursor.EnsureHooks(witnessDir); err != nil {
+		t.Fatal(err)
+	}
+	// A script of the user's own that hooks.json runs
+	script := filepath.Join(witnessDir, ".cursor", "hooks", "notify.sh")
+	if err := os.MkdirAll(filepath.Dir(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/bash\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	eventsPath := filepath.Join(townRoot, ".events.jsonl")
+	if err := os.WriteFile(eventsPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewSettingsPermsCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("fresh install: status = %v, details = %v", result.Status, result.Details)
+	}
+
+	hooksJSON := filepath.Join(witnessDir, ".cursor", "hooks.json")
+	for path, mode := range map[string]os.FileMode{hooksJSON: 0666, script: 0775, eventsPath: 0664} {
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result := check.Run(ctx)
+	if result.Status != StatusError {
+		t.Fatalf("world-writable hooks.json: status = %v, want error", result.Status)
+	}
+	if len(result.Details) != 3 {
+		t.Errorf("details = %v, want 3", result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after fix: status = %v, details = %v", result.Status, result.Details)
+	}
+	info, err := os.Stat(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("script mode after fix = %v, want 0755", info.Mode().Perm())
+	}
+}
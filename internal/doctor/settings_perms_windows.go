@@ -0,0 +1,10 @@
+//go:build windows
+
+package doctor
+
+import "io/fs"
+
+// fileOwner is unavailable on Windows; ownership is not checked.
+func fileOwner(info fs.FileInfo) (uid int, ok bool) {
+	return 0, false
+}
@@ -105,7 +105,7 @@ func (c *SparseCheckoutCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusError,
 		Message: fmt.Sprintf("%d repo(s) missing sparse checkout configuration", len(c.affectedRepos)),
 		Details: details,
-		FixHint: "Run 'gt doctor --fix' to configure sparse checkout",
+		Actions: []FixAction{doctorFix("configure sparse checkout", false)},
 	}
 }
 
@@ -0,0 +1,243 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/git"
+	"github.com/cursorworkshop/cursor-gastown/internal/polecat"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+)
+
+// StalePolecatAge is how long a polecat directory may sit without a session
+// or session metadata before it is considered abandoned.
+const StalePolecatAge = 24 * time.Hour
+
+// Seams for tests.
+var (
+	polecatSessionRunning = func(sessionName string) bool {
+		running, _ := tmux.NewTmux().HasSession(sessionName)
+		return running
+	}
+	// polecatHookBead returns the polecat's hooked work; known is false when
+	// the agent bead could not be read.
+	polecatHookBead = func(townRoot, rigName, name string) (hook string, known bool) {
+		resolved := beads.ResolveBeadsDir(filepath.Join(townRoot, rigName))
+		b := beads.NewWithBeadsDir(filepath.Dir(resolved), resolved)
+		id := beads.PolecatBeadIDWithPrefix(beads.GetPrefixForRig(townRoot, rigName), rigName, name)
+		_, fields, err := b.GetAgentBead(id)
+		if err != nil {
+			return "", false
+		}
+		if fields == nil {
+			return "", true
+		}
+		return fields.HookBead, true
+	}
+)
+
+// StalePolecatCheck detects polecat directories left behind by warm pools
+// and crashed spawns: no tmux session, no hooked work, and no session
+// metadata (.runtime/session_id, written when an agent primes) for longer
+// than StalePolecatAge.
+type StalePolecatCheck struct {
+	FixableCheck
+	stale []stalePolecatDir // cached for Fix
+}
+
+type stalePolecatDir struct {
+	rigName string
+	name    string
+	path    string
+	age     time.Duration
+}
+
+// NewStalePolecatCheck creates a new stale polecat check.
+func NewStalePolecatCheck() *StalePolecatCheck {
+	return &StalePolecatCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "stale-polecat-dirs",
+				CheckDescription: "Detect abandoned polecat directories with no session or task",
+				CheckDependsOn:   []string{"tmux"},
+			},
+		},
+	}
+}
+
+// Run finds abandoned polecat directories in every rig.
+func (c *StalePolecatCheck) Run(ctx *CheckContext) *CheckResult {
+	c.stale = nil
+
+	if result := skipPolecatsWithoutTmux(c.Name()); result != nil {
+		return result
+	}
+
+	rigs, err := discoverRigs(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not read rigs registry",
+			Details: []string{err.Error()},
+		}
+	}
+	sort.Strings(rigs)
+
+	now := time.Now()
+	for _, rigName := range rigs {
+		c.stale = append(c.stale, findStalePolecatDirs(ctx.TownRoot, rigName, now)...)
+	}
+
+	if len(c.stale) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No abandoned polecat directories",
+		}
+	}
+
+	var details []string
+	for _, d := range c.stale {
+		details = append(details, fmt.Sprintf("%s/polecats/%s (idle %dh, never started)", d.rigName, d.name, int(d.age.Hours())))
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d abandoned polecat director(ies)", len(c.stale)),
+		Details: details,
+		Actions: []FixAction{doctorFix("salvage their branches (salvage/*) and remove them", true)},
+	}
+}
+
+// Fix salvages unmerged work from each abandoned directory onto a
+// salvage/* branch, then removes the directory.
+func (c *StalePolecatCheck) Fix(ctx *CheckContext) error {
+	var errs []string
+	for _, d := range c.stale {
+		branch, err := salvagePolecatBranch(ctx, d)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: salvage failed, left in place: %v", d.rigName, d.name, err))
+			continue
+		}
+		if branch != "" {
+			ctx.Record(ActionBranchCreated, branch)
+		}
+
+		r := &rig.Rig{Name: d.rigName, Path: filepath.Join(ctx.TownRoot, d.rigName)}
+		mgr := polecat.NewManager(r, git.NewGit(r.Path))
+		if err := mgr.RemoveWithOptions(d.name, true, true); err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %v", d.rigName, d.name, err))
+			continue
+		}
+		ctx.Record(ActionDirRemoved, d.path)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// findStalePolecatDirs returns the abandoned polecat directories in a rig.
+func findStalePolecatDirs(townRoot, rigName string, now time.Time) []stalePolecatDir {
+	polecatsDir := filepath.Join(townRoot, rigName, "polecats")
+	var stale []stalePolecatDir
+	for _, name := range listAgentDirs(polecatsDir) {
+		dir := filepath.Join(polecatsDir, name)
+		if _, err := os.Stat(filepath.Join(dir, ".runtime", "session_id")); err == nil {
+			continue // an agent ran here; stale-session cleanup is the witness's job
+		}
+		age := now.Sub(lastTouched(dir))
+		if age < StalePolecatAge {
+			continue // may be a spawn in progress
+		}
+		if polecatSessionRunning(session.PolecatSessionName(rigName, name)) {
+			continue
+		}
+		if hook, known := polecatHookBead(townRoot, rigName, name); !known || hook != "" {
+			continue // has work (or we cannot tell): leave it for the daemon
+		}
+		stale = append(stale, stalePolecatDir{rigName: rigName, name: name, path: dir, age: age})
+	}
+	return stale
+}
+
+// lastTouched returns the newest modification time of a polecat directory
+// and its top-level git and runtime entries.
+func lastTouched(dir string) time.Time {
+	var newest time.Time
+	for _, p := range []string{dir, filepath.Join(dir, ".git"), filepath.Join(dir, ".runtime")} {
+		if info, err := os.Stat(p); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	return newest
+}
+
+// salvagePolecatBranch preserves work in an abandoned polecat checkout that
+// exists nowhere else: uncommitted changes are committed, and if HEAD has
+// commits not on a remote or a non-polecat branch, a salvage/<branch>
+// branch is created at HEAD. Returns the salvage branch, or "" if there was
+// nothing to keep (including directories that are not git checkouts).
+func salvagePolecatBranch(ctx context.Context, d stalePolecatDir) (string, error) {
+	top, err := gitIn(ctx, d.path, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil // leftover from a spawn that never cloned
+	}
+	if resolved, err := filepath.EvalSymlinks(d.path); err == nil && filepath.Clean(top) != resolved {
+		return "", nil // not its own checkout (e.g. inside the rig repo)
+	}
+
+	if status, err := gitIn(ctx, d.path, "status", "--porcelain"); err != nil {
+		return "", err
+	} else if status != "" {
+		if _, err := gitIn(ctx, d.path, "add", "-A"); err != nil {
+			return "", err
+		}
+		if _, err := gitIn(ctx, d.path, "-c", "user.name=gt doctor", "-c", "user.email=doctor@gastown.local",
+			"commit", "--no-verify", "-q", "-m", "Salvage uncommitted work from abandoned polecat "+d.name); err != nil {
+			return "", err
+		}
+	}
+
+	unique, err := gitIn(ctx, d.path, "rev-list", "--count", "HEAD", "--not", "--remotes",
+		"--exclude=polecat/*", "--exclude=salvage/*", "--branches")
+	if err != nil {
+		return "", nil // no commits yet
+	}
+	if n, _ := strconv.Atoi(unique); n == 0 {
+		return "", nil
+	}
+
+	base, err := gitIn(ctx, d.path, "symbolic-ref", "--short", "-q", "HEAD")
+	if err != nil || base == "" {
+		base = "polecat/" + d.name
+	}
+	branch := "salvage/" + base
+	if _, err := gitIn(ctx, d.path, "rev-parse", "--verify", "-q", "refs/heads/"+branch); err == nil {
+		branch = fmt.Sprintf("%s-%d", branch, time.Now().Unix())
+	}
+	if _, err := gitIn(ctx, d.path, "branch", branch, "HEAD"); err != nil {
+		return "", err
+	}
+	return branch, nil
+}
+
+func gitIn(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...) //nolint:gosec // G204: args are fixed git subcommands
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
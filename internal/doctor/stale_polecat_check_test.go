@@ -0,0 +1,115 @@
+package doctor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubPolecatSeams fakes tmux and agent beads; a hook of "?" means the
+// agent bead could not be read.
+func stubPolecatSeams(t *testing.T, running map[string]bool, hooks map[string]string) {
+	t.Helper()
+	origRunning, origHook := polecatSessionRunning, polecatHookBead
+	t.Cleanup(func() { polecatSessionRunning, polecatHookBead = origRunning, origHook })
+	polecatSessionRunning = func(sess string) bool { return running[sess] }
+	polecatHookBead = func(_, _, name string) (string, bool) {
+		if hooks[name] == "?" {
+			return "", false
+		}
+		return hooks[name], true
+	}
+}
+
+func TestFindStalePolecatDirs(t *testing.T) {
+	townRoot := t.TempDir()
+	old := time.Now().Add(-2 * StalePolecatAge)
+	mk := func(name string, mtime time.Time, sessionID bool) {
+		dir := filepath.Join(townRoot, "gp", "polecats", name)
+		if sessionID {
+			mustWrite(t, filepath.Join(dir, ".runtime", "session_id"), "abc\n")
+			if err := os.Chtimes(filepath.Join(dir, ".runtime"), mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+		} else if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(dir, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mk("Abandoned", old, false)
+	mk("Fresh", time.Now(), false)
+	mk("Primed", old, true)
+	mk("Running", old, false)
+	mk("Hooked", old, false)
+	mk("Unknown", old, false)
+	mk(".cursor", old, false)
+
+	stubPolecatSeams(t,
+		map[string]bool{"gt-gp-Running": true},
+		map[string]string{"Hooked": "gp-123", "Unknown": "?"})
+
+	got := findStalePolecatDirs(townRoot, "gp", time.Now())
+	if len(got) != 1 || got[0].name != "Abandoned" {
+		t.Fatalf("stale = %+v, want only Abandoned", got)
+	}
+	if got[0].age < StalePolecatAge {
+		t.Errorf("age = %v", got[0].age)
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestSalvagePolecatBranch(t *testing.T) {
+	rigDir := t.TempDir()
+	repo := filepath.Join(rigDir, "mayor", "rig")
+	initGitRepo(t, repo)
+
+	// Worktree with an unmerged commit plus uncommitted changes.
+	work := filepath.Join(rigDir, "polecats", "Toast")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "polecat/Toast-abc", work)
+	mustWrite(t, filepath.Join(work, "feature.go"), "package x\n")
+	runGit(t, work, "add", "feature.go")
+	runGit(t, work, "commit", "-q", "-m", "feature")
+	mustWrite(t, filepath.Join(work, "wip.go"), "package x // wip\n")
+
+	branch, err := salvagePolecatBranch(context.Background(), stalePolecatDir{name: "Toast", path: work})
+	if err != nil {
+		t.Fatalf("salvage: %v", err)
+	}
+	if branch != "salvage/polecat/Toast-abc" {
+		t.Fatalf("branch = %q", branch)
+	}
+	files := runGit(t, repo, "ls-tree", "--name-only", branch)
+	if !strings.Contains(files, "feature.go") || !strings.Contains(files, "wip.go") {
+		t.Errorf("salvage branch files = %q, want committed and uncommitted work", files)
+	}
+
+	// A clean worktree at the base commit has nothing to salvage.
+	empty := filepath.Join(rigDir, "polecats", "Nux")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "polecat/Nux", empty)
+	if branch, err := salvagePolecatBranch(context.Background(), stalePolecatDir{name: "Nux", path: empty}); err != nil || branch != "" {
+		t.Errorf("clean worktree salvage = %q, %v; want nothing", branch, err)
+	}
+
+	// A leftover directory that is not a checkout has nothing to salvage.
+	bare := filepath.Join(t.TempDir(), "Slit")
+	if err := os.MkdirAll(bare, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if branch, err := salvagePolecatBranch(context.Background(), stalePolecatDir{name: "Slit", path: bare}); err != nil || branch != "" {
+		t.Errorf("non-git salvage = %q, %v", branch, err)
+	}
+}
@@ -0,0 +1,140 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/daemon"
+)
+
+// TemplateDriftCheck detects agent workspaces whose Cursor hooks were
+// generated from older templates than the ones embedded in this gt binary.
+// When template_resync.auto_resync is enabled the daemon remediates drift on
+// its own; this check reports progress and offers a manual re-sync.
+type TemplateDriftCheck struct {
+	FixableCheck
+	drifted []daemon.TemplateTarget
+}
+
+// NewTemplateDriftCheck creates a new template drift check.
+func NewTemplateDriftCheck() *TemplateDriftCheck {
+	return &TemplateDriftCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "template-drift",
+				CheckDescription: "Check agent hooks match this gt version's templates",
+			},
+		},
+	}
+}
+
+// Run compares installed hooks against embedded templates.
+func (c *TemplateDriftCheck) Run(ctx *CheckContext) *CheckResult {
+	var rigs []string
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		rigs = append(rigs, filepath.Base(rigPath))
+	}
+	c.drifted = daemon.FindTemplateDrift(ctx.TownRoot, rigs)
+
+	// Gas Town hooks edited by users are kept on re-sync; surface them
+	var conflicts []string
+	for _, t := range daemon.TemplateTargets(ctx.TownRoot, rigs) {
+		for _, conflict := range cursor.HookConflicts(t.WorkDir, t.Role) {
+			conflicts = append(conflicts, fmt.Sprintf("%s %s", t.Agent, conflict))
+		}
+	}
+
+	if len(c.drifted) == 0 {
+		if len(conflicts) > 0 {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusWarning,
+				Message: fmt.Sprintf("%d edited Gas Town hook(s) kept instead of the generated command", len(conflicts)),
+				Details: conflicts,
+				FixHint: "Restore the generated command in .cursor/hooks.json, or delete the entry and run 'gt hooks sync'",
+			}
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "All agent hooks match current templates",
+		}
+	}
+
+	var details []string
+	for _, t := range c.drifted {
+		details = append(details, t.Agent+describeHookStatuses(cursor.HookFileStatuses(t.WorkDir, t.Role)))
+	}
+	for _, conflict := range conflicts {
+		details = append(details, "Kept edited hook: "+conflict)
+	}
+
+	settings, _ := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot))
+	actions := []FixAction{doctorFix("re-sync now", false)}
+	fixHint := "or enable template_resync.auto_resync in settings/config.json"
+	if settings != nil && settings.TemplateResync != nil && settings.TemplateResync.AutoResync {
+		actions = nil
+		fixHint = fmt.Sprintf("Daemon auto-resync is enabled; patrol agents are cycled during %s", settings.TemplateResync.Window())
+		if state, err := daemon.LoadTemplateResyncState(ctx.TownRoot); err == nil && len(state.Deferred) > 0 {
+			details = append(details, fmt.Sprintf("Waiting for maintenance window: %d agent(s)", len(state.Deferred)))
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d agent workspace(s) have hooks from older templates", len(c.drifted)),
+		Details: details,
+		FixHint: fixHint,
+		Actions: actions,
+	}
+}
+
+// describeHookStatuses formats why an agent's hook files differ, e.g.
+// ": hooks.json (user-modified)".
+func describeHookStatuses(statuses map[string]cursor.FileStatus) string {
+	if len(statuses) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%s)", name, statuses[name])
+	}
+	return ": " + strings.Join(parts, ", ")
+}
+
+// Inputs returns the paths this check depends on (see InputFingerprinter).
+func (c *TemplateDriftCheck) Inputs(ctx *CheckContext) []string {
+	return agentWorkspaceInputs(ctx.TownRoot)
+}
+
+// Fix rewrites drifted hooks from the embedded templates, keeping hooks users
+// added. Sessions are not cycled; running agents pick up the new hooks on
+// their next start.
+func (c *TemplateDriftCheck) Fix(ctx *CheckContext) error {
+	for i, t := range c.drifted {
+		if err := ctx.Step(i, len(c.drifted), t.Agent); err != nil {
+			return err
+		}
+		cursorDir := filepath.Join(t.WorkDir, ".cursor")
+		if err := ctx.Backup.Save(filepath.Join(cursorDir, "hooks.json")); err != nil {
+			return err
+		}
+		if err := ctx.Backup.Save(filepath.Join(cursorDir, "hooks")); err != nil {
+			return err
+		}
+		if err := cursor.EnsureHooksForRole(t.WorkDir, t.Role); err != nil {
+			return fmt.Errorf("re-syncing %s: %w", t.Agent, err)
+		}
+	}
+	return ctx.Step(len(c.drifted), len(c.drifted), "")
+}
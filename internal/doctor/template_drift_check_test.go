@@ -0,0 +1,67 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+)
+
+func TestTemplateDriftCheck(t *testing.T) {
+	townRoot := t.TempDir()
+	witnessDir := filepath.Join(townRoot, "gastown", "witness")
+	polecatsDir := filepath.Join(townRoot, "gastown", "polecats")
+	if err := cursor.EnsureHooksForRole(witnessDir, "witness"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cursor.EnsureHooksForRole(polecatsDir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewTemplateDriftCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("fresh hooks: status = %v, want OK (%s)", result.Status, result.Message)
+	}
+
+	// Simulate hooks.json written by an older gt
+	stale := filepath.Join(witnessDir, ".cursor", "hooks.json")
+	if err := os.WriteFile(stale, []byte(`{"version":1,"hooks":{}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("drifted hooks: status = %v, want warning", result.Status)
+	}
+	want := "gastown/witness: hooks.json (no generation marker)"
+	if len(result.Details) != 1 || result.Details[0] != want {
+		t.Errorf("details = %v, want [%s]", result.Details, want)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if !cursor.HooksCurrent(witnessDir) {
+		t.Error("Fix should restore current hooks")
+	}
+
+	// A hand-edited Gas Town hook is told apart from a template change
+	hooksPath := filepath.Join(witnessDir, ".cursor", "hooks.json")
+	data, err := os.ReadFile(hooksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data), "hook stop\"", "hook stop --quiet\"", 1)
+	if err := os.WriteFile(hooksPath, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result = check.Run(ctx)
+	want = `gastown/witness stop: "gt cursor-hook stop --quiet" (generated: "gt cursor-hook stop")`
+	if len(result.Details) != 1 || result.Details[0] != want {
+		t.Errorf("details = %v, want [%s]", result.Details, want)
+	}
+}
@@ -1,6 +1,7 @@
 package doctor
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -59,7 +60,7 @@ func (c *ThemeCheck) Run(ctx *CheckContext) *CheckResult {
 	// Check if sessions have proper status-left format (no brackets = new format)
 	var needsUpdate []string
 	for _, session := range gtSessions {
-		statusLeft, err := getSessionStatusLeft(session)
+		statusLeft, err := getSessionStatusLeft(ctx, session)
 		if err != nil {
 			continue
 		}
@@ -79,7 +80,7 @@ func (c *ThemeCheck) Run(ctx *CheckContext) *CheckResult {
 			Status:  StatusWarning,
 			Message: fmt.Sprintf("%d session(s) have outdated theme format", len(needsUpdate)),
 			Details: details,
-			FixHint: "Run 'gt theme apply --all' or 'gt doctor --fix'",
+			Actions: []FixAction{{Command: "gt", Args: []string{"theme", "apply", "--all"}, Description: "apply themes"}, doctorFix("apply them", false)},
 		}
 	}
 
@@ -92,14 +93,14 @@ func (c *ThemeCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix applies themes to all sessions.
 func (c *ThemeCheck) Fix(ctx *CheckContext) error {
-	cmd := exec.Command("gt", "theme", "apply", "--all")
+	cmd := exec.CommandContext(ctx, "gt", "theme", "apply", "--all")
 	cmd.Dir = ctx.TownRoot
 	return cmd.Run()
 }
 
 // getSessionStatusLeft retrieves the status-left setting for a tmux session.
-func getSessionStatusLeft(session string) (string, error) {
-	cmd := exec.Command("tmux", "show-options", "-t", session, "status-left")
+func getSessionStatusLeft(ctx context.Context, session string) (string, error) {
+	cmd := exec.CommandContext(ctx, "tmux", "show-options", "-t", session, "status-left")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
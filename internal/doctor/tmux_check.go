@@ -1,6 +1,8 @@
 package doctor
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -9,6 +11,80 @@ import (
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
 )
 
+// tmuxProbe reports whether tmux is usable (seam for tests).
+var tmuxProbe = func() error { return tmux.NewTmux().Probe() }
+
+// TmuxCheck verifies the multiplexer every agent session runs in: installed
+// and answering. When it fails, session-based checks skip rather than report
+// misleading results of their own.
+type TmuxCheck struct {
+	BaseCheck
+}
+
+// NewTmuxCheck creates a new tmux availability check.
+func NewTmuxCheck() *TmuxCheck {
+	return &TmuxCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "tmux",
+			CheckDescription: "Check tmux is installed and its server responds",
+		},
+	}
+}
+
+// Run probes tmux.
+func (c *TmuxCheck) Run(ctx *CheckContext) *CheckResult {
+	err := tmuxProbe()
+	switch {
+	case err == nil:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "tmux is available",
+		}
+	case errors.Is(err, tmux.ErrNotInstalled):
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "tmux is not installed; agent sessions cannot start",
+			FixHint: "Install tmux (e.g. 'brew install tmux' or 'apt install tmux')",
+		}
+	default:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "tmux server is not responding; agent sessions cannot be managed",
+			Details: []string{err.Error()},
+			Actions: []FixAction{{
+				Command:     "tmux",
+				Args:        []string{"kill-server"},
+				Description: "restart it (the daemon recreates agent sessions)",
+				Destructive: true,
+			}},
+		}
+	}
+}
+
+// tmuxSkipped returns the result for a session-based check when tmux is
+// unusable; the tmux check reports the failure itself.
+func tmuxSkipped(name string) *CheckResult {
+	return &CheckResult{
+		Name:    name,
+		Status:  StatusOK,
+		Message: "tmux unavailable, skipped (see tmux check)",
+	}
+}
+
+// skipPolecatsWithoutTmux returns a skipped result for the polecat checks
+// (stale, reclaimable, unmerged) when tmux is unusable, or nil if they may
+// run. They treat a polecat without a session as abandoned, and without
+// tmux every polecat would look session-less.
+func skipPolecatsWithoutTmux(name string) *CheckResult {
+	if tmuxProbe() != nil {
+		return tmuxSkipped(name)
+	}
+	return nil
+}
+
 // LinkedPaneCheck detects tmux sessions that share panes,
 // which can cause crosstalk (messages sent to one session appearing in another).
 type LinkedPaneCheck struct {
@@ -23,6 +99,7 @@ func NewLinkedPaneCheck() *LinkedPaneCheck {
 			BaseCheck: BaseCheck{
 				CheckName:        "linked-panes",
 				CheckDescription: "Detect tmux sessions sharing panes (causes crosstalk)",
+				CheckDependsOn:   []string{"tmux"},
 			},
 		},
 	}
@@ -34,12 +111,7 @@ func (c *LinkedPaneCheck) Run(ctx *CheckContext) *CheckResult {
 
 	sessions, err := t.ListSessions()
 	if err != nil {
-		return &CheckResult{
-			Name:    c.Name(),
-			Status:  StatusWarning,
-			Message: "Could not list tmux sessions",
-			Details: []string{err.Error()},
-		}
+		return tmuxSkipped(c.Name())
 	}
 
 	// Filter to gt-* sessions only
@@ -62,7 +134,7 @@ func (c *LinkedPaneCheck) Run(ctx *CheckContext) *CheckResult {
 	paneToSessions := make(map[string][]string)
 
 	for _, session := range gtSessions {
-		panes, err := c.getSessionPanes(session)
+		panes, err := c.getSessionPanes(ctx, session)
 		if err != nil {
 			continue
 		}
@@ -107,7 +179,7 @@ func (c *LinkedPaneCheck) Run(ctx *CheckContext) *CheckResult {
 		Status:  StatusError,
 		Message: fmt.Sprintf("Found %d linked pane(s) causing crosstalk!", len(conflicts)),
 		Details: conflicts,
-		FixHint: "Run 'gt doctor --fix' to kill linked sessions (daemon will recreate)",
+		Actions: []FixAction{doctorFix("kill linked sessions (daemon will recreate)", true)},
 	}
 }
 
@@ -124,18 +196,20 @@ func (c *LinkedPaneCheck) Fix(ctx *CheckContext) error {
 	for _, session := range c.linkedSessions {
 		if err := t.KillSession(session); err != nil {
 			lastErr = err
+			continue
 		}
+		ctx.Record(ActionSessionKilled, session)
 	}
 
 	return lastErr
 }
 
 // getSessionPanes returns all pane IDs for a session.
-func (c *LinkedPaneCheck) getSessionPanes(session string) ([]string, error) {
+func (c *LinkedPaneCheck) getSessionPanes(ctx context.Context, session string) ([]string, error) {
 	// Get pane IDs using tmux list-panes with format
 	// Using #{pane_id} which gives us the unique pane identifier like %123
 	// Note: -s flag lists all panes in all windows of this session (not -a which is global)
-	out, err := exec.Command("tmux", "list-panes", "-t", session, "-s", "-F", "#{pane_id}").Output()
+	out, err := exec.CommandContext(ctx, "tmux", "list-panes", "-t", session, "-s", "-F", "#{pane_id}").Output()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,115 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+)
+
+func TestTmuxCheck(t *testing.T) {
+	orig := tmuxProbe
+	t.Cleanup(func() { tmuxProbe = orig })
+
+	tests := []struct {
+		name        string
+		probe       error
+		wantStatus  CheckStatus
+		wantActions int
+	}{
+		{"available", nil, StatusOK, 0},
+		{"not installed", tmux.ErrNotInstalled, StatusError, 0},
+		{"hung server", errors.New("tmux list-sessions: timeout"), StatusError, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmuxProbe = func() error { return tt.probe }
+			result := NewTmuxCheck().Run(&CheckContext{TownRoot: t.TempDir()})
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if len(result.Actions) != tt.wantActions {
+				t.Errorf("Actions = %v, want %d", result.Actions, tt.wantActions)
+			}
+		})
+	}
+}
+
+func TestStalePolecatCheck_SkipsWithoutTmux(t *testing.T) {
+	orig := tmuxProbe
+	t.Cleanup(func() { tmuxProbe = orig })
+	tmuxProbe = func() error { return tmux.ErrNotInstalled }
+
+	result := NewStalePolecatCheck().Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK || result.Message != tmuxSkipped("").Message {
+		t.Errorf("got %v %q, want tmux skip", result.Status, result.Message)
+	}
+}
+
+func TestTmuxEnvCheck(t *testing.T) {
+	origProbe, origVersion, origOption, origSocket := tmuxProbe, tmuxVersion, tmuxGlobalOption, tmuxSocketPath
+	t.Cleanup(func() {
+		tmuxProbe, tmuxVersion, tmuxGlobalOption, tmuxSocketPath = origProbe, origVersion, origOption, origSocket
+	})
+	tmuxProbe = func() error { return nil }
+	socketDir := filepath.Join(t.TempDir(), "tmux-1000")
+	if err := os.Mkdir(socketDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	tmuxSocketPath = func() string { return filepath.Join(socketDir, "default") }
+
+	tests := []struct {
+		name        string
+		version     string
+		options     map[string]string
+		noServer    bool
+		wantStatus  CheckStatus
+		wantActions int
+	}{
+		{"supported", "3.3a", nil, false, StatusOK, 0},
+		{"no server", "next-3.5", nil, true, StatusOK, 0},
+		{"too old", "2.9", nil, false, StatusError, 0},
+		{"no popup", "3.1c", nil, false, StatusWarning, 0},
+		{"remain-on-exit", "3.3a", map[string]string{"remain-on-exit": "on"}, false, StatusWarning, 1},
+		{"destroy-unattached", "3.4", map[string]string{"destroy-unattached": "keep-last", "exit-unattached": "on"}, false, StatusError, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmuxVersion = func() (string, error) { return tt.version, nil }
+			tmuxGlobalOption = func(name string) (string, error) {
+				if tt.noServer {
+					return "", tmux.ErrNoServer
+				}
+				if v, ok := tt.options[name]; ok {
+					return v, nil
+				}
+				return "off", nil
+			}
+			result := NewTmuxEnvCheck().Run(&CheckContext{TownRoot: t.TempDir()})
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v (%s: %v)", result.Status, tt.wantStatus, result.Message, result.Details)
+			}
+			if len(result.Actions) != tt.wantActions {
+				t.Errorf("Actions = %v, want %d", result.Actions, tt.wantActions)
+			}
+		})
+	}
+}
+
+func TestCheckTmuxSocket(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tmux-1000")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if detail, _ := checkTmuxSocket(filepath.Join(dir, "default")); detail != "" {
+		t.Errorf("missing socket: %s", detail)
+	}
+	if err := os.Chmod(dir, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if detail, _ := checkTmuxSocket(filepath.Join(dir, "default")); detail == "" {
+		t.Error("world-accessible socket directory should be reported")
+	}
+}
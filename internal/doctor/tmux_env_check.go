@@ -0,0 +1,174 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+)
+
+// Seams for tests.
+var (
+	tmuxVersion      = func() (string, error) { return tmux.NewTmux().Version() }
+	tmuxGlobalOption = func(name string) (string, error) { return tmux.NewTmux().GlobalOption(name) }
+	tmuxSocketPath   = tmux.SocketPath
+)
+
+// Minimum tmux versions. Session lookups filter with list-sessions -f
+// (3.1); the mail preview popup uses display-popup (3.2).
+const (
+	tmuxRequiredMajor, tmuxRequiredMinor       = 3, 1
+	tmuxRecommendedMajor, tmuxRecommendedMinor = 3, 2
+)
+
+// tmuxRequiredOption is a global tmux option that must have a given value
+// for agent sessions to be detected, restarted, and cycled.
+type tmuxRequiredOption struct {
+	name   string
+	want   string
+	fatal  bool   // error rather than warning
+	breaks string // what goes wrong otherwise
+}
+
+var tmuxRequiredOptions = []tmuxRequiredOption{
+	{"destroy-unattached", "off", true, "agent sessions run detached and are destroyed as soon as they start"},
+	{"exit-unattached", "off", true, "the server exits when the last client detaches, killing every agent"},
+	{"remain-on-exit", "off", false, "exited agents leave dead panes behind, so their sessions look alive and are not restarted"},
+}
+
+// TmuxEnvCheck verifies the tmux environment supports agent session
+// management: a recent enough version, a usable server socket, and the
+// global options gt relies on.
+type TmuxEnvCheck struct {
+	BaseCheck
+}
+
+// NewTmuxEnvCheck creates a new tmux environment check.
+func NewTmuxEnvCheck() *TmuxEnvCheck {
+	return &TmuxEnvCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "tmux-env",
+			CheckDescription: "Check tmux version, server socket, and options gt relies on",
+			CheckDependsOn:   []string{"tmux"},
+		},
+	}
+}
+
+// Run checks the version, socket, and options.
+func (c *TmuxEnvCheck) Run(ctx *CheckContext) *CheckResult {
+	if tmuxProbe() != nil {
+		return tmuxSkipped(c.Name())
+	}
+
+	status := StatusOK
+	var details, hints []string
+	var actions []FixAction
+	problem := func(fatal bool, detail, hint string) {
+		if fatal {
+			status = StatusError
+		} else if status == StatusOK {
+			status = StatusWarning
+		}
+		details = append(details, detail)
+		if hint != "" {
+			hints = append(hints, hint)
+		}
+	}
+
+	version, err := tmuxVersion()
+	if err != nil {
+		problem(false, "Could not read tmux version: "+err.Error(), "")
+	} else if major, minor, ok := tmux.ParseVersion(version); ok {
+		switch {
+		case !tmuxVersionAtLeast(major, minor, tmuxRequiredMajor, tmuxRequiredMinor):
+			problem(true, fmt.Sprintf("tmux %s is too old: session lookups need %d.%d or newer", version, tmuxRequiredMajor, tmuxRequiredMinor),
+				"Upgrade tmux (e.g. 'brew upgrade tmux' or your distribution's backports)")
+		case !tmuxVersionAtLeast(major, minor, tmuxRecommendedMajor, tmuxRecommendedMinor):
+			problem(false, fmt.Sprintf("tmux %s has no display-popup: the mail preview needs %d.%d or newer", version, tmuxRecommendedMajor, tmuxRecommendedMinor),
+				"Upgrade tmux for the mail preview popup")
+		}
+	}
+
+	if detail, hint := checkTmuxSocket(tmuxSocketPath()); detail != "" {
+		problem(true, detail, hint)
+	}
+
+	serverUp := true
+	for _, opt := range tmuxRequiredOptions {
+		if !serverUp {
+			break
+		}
+		value, err := tmuxGlobalOption(opt.name)
+		switch {
+		case errors.Is(err, tmux.ErrNoServer):
+			serverUp = false // Options are checked once a server is running
+		case err != nil:
+			problem(false, fmt.Sprintf("Could not read option %s: %v", opt.name, err), "")
+		case value != "" && value != opt.want:
+			problem(opt.fatal, fmt.Sprintf("%s is %s: %s", opt.name, value, opt.breaks),
+				fmt.Sprintf("Add 'set-option -g %s %s' to ~/.tmux.conf so it persists", opt.name, opt.want))
+			actions = append(actions, FixAction{
+				Command:     "tmux",
+				Args:        []string{"set-option", "-g", opt.name, opt.want},
+				Description: "fix the running server",
+			})
+		}
+	}
+
+	if status == StatusOK {
+		msg := "tmux environment supports agent sessions"
+		if version != "" {
+			msg = fmt.Sprintf("tmux %s supports agent sessions", version)
+		}
+		if !serverUp {
+			msg += " (no server running; options not checked)"
+		}
+		return &CheckResult{Name: c.Name(), Status: StatusOK, Message: msg}
+	}
+
+	msg := "tmux environment may break session cycling"
+	if status == StatusError {
+		msg = "tmux environment cannot support session cycling"
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  status,
+		Message: msg,
+		Details: details,
+		FixHint: strings.Join(hints, "; "),
+		Actions: actions,
+	}
+}
+
+// tmuxVersionAtLeast compares major.minor versions.
+func tmuxVersionAtLeast(major, minor, wantMajor, wantMinor int) bool {
+	return major > wantMajor || (major == wantMajor && minor >= wantMinor)
+}
+
+// checkTmuxSocket reports why tmux cannot use the socket at path, or ""
+// when it can. A missing socket is fine: the server creates it on start.
+func checkTmuxSocket(path string) (detail, hint string) {
+	dir := filepath.Dir(path)
+	if info, err := os.Stat(dir); err == nil && info.Mode().Perm()&0o007 != 0 {
+		return fmt.Sprintf("Socket directory %s is accessible to other users; tmux refuses to use it", dir),
+			fmt.Sprintf("Run 'chmod 700 %s'", dir)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", ""
+	}
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err == nil {
+		_ = conn.Close()
+		return "", ""
+	}
+	if errors.Is(err, os.ErrPermission) {
+		return fmt.Sprintf("Cannot connect to tmux socket %s: permission denied", path),
+			"The socket belongs to another user; run gt as its owner or point TMUX_TMPDIR at a directory you own"
+	}
+	return "", "" // Stale socket: tmux replaces it when the server starts
+}
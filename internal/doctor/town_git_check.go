@@ -38,7 +38,7 @@ func (c *TownGitCheck) Run(ctx *CheckContext) *CheckResult {
 				"Your town harness contains personal configuration and operating history",
 				"Version control makes it easier to backup and federate across machines",
 			},
-			FixHint: "Run 'git init' in your town root to initialize a repository",
+			Actions: []FixAction{{Command: "git", Args: []string{"init"}, Description: "initialize a repository in the town root"}},
 		}
 	}
 
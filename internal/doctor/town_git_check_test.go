@@ -38,7 +38,7 @@ func TestTownGitCheck_NoGitDir(t *testing.T) {
 		t.Errorf("expected StatusWarning, got %v", result.Status)
 	}
 
-	if result.FixHint == "" {
+	if result.Hint() == "" {
 		t.Error("expected non-empty FixHint")
 	}
 }
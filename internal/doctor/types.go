@@ -2,6 +2,7 @@
 package doctor
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
@@ -20,6 +21,12 @@ const (
 	StatusWarning
 	// StatusError indicates a critical problem.
 	StatusError
+	// StatusTimeout indicates the check did not finish within its timeout,
+	// so its outcome is unknown.
+	StatusTimeout
+	// StatusBlocked indicates the check was not run because a check it
+	// depends on failed.
+	StatusBlocked
 )
 
 // String returns a human-readable status.
@@ -31,6 +38,10 @@ func (s CheckStatus) String() string {
 		return "Warning"
 	case StatusError:
 		return "Error"
+	case StatusTimeout:
+		return "Timeout"
+	case StatusBlocked:
+		return "Blocked"
 	default:
 		return "Unknown"
 	}
@@ -42,6 +53,30 @@ type CheckContext struct {
 	RigName         string // Rig name (empty for town-level checks)
 	Verbose         bool   // Enable verbose output
 	RestartSessions bool   // Restart patrol sessions when fixing (requires explicit --restart-sessions flag)
+	GTVersion       string // Version of the running gt binary (for compatibility checks)
+
+	// OtherTowns lists the roots of other registered towns on this machine,
+	// whose sessions and processes must not be treated as orphans.
+	OtherTowns []string
+
+	// Backup captures files before fixers delete or overwrite them (nil disables).
+	Backup *FixBackup
+
+	// Journal records each fix action for 'gt doctor history' (nil disables).
+	Journal *FixJournal
+
+	// Progress receives per-item progress from fixes (nil disables).
+	Progress ProgressFunc
+
+	// Interrupt is closed to cancel the run; fixes stop between items.
+	Interrupt <-chan struct{}
+
+	// ConfirmRestart is asked before a fix kills a session whose agent is
+	// mid-task; nil defers such restarts to a later run.
+	ConfirmRestart func(session, reason string) bool
+
+	fixing string          // Name of the check whose fix is running
+	runCtx context.Context // Cancelled when the running check times out
 }
 
 // RigPath returns the full path to the rig directory.
@@ -53,13 +88,45 @@ func (ctx *CheckContext) RigPath() string {
 	return ctx.TownRoot + "/" + ctx.RigName
 }
 
+// CheckContext is a context.Context for the running check: it is cancelled
+// when the check's timeout expires. Checks pass it to exec.CommandContext so
+// a hung subprocess is killed rather than stalling the run.
+var _ context.Context = (*CheckContext)(nil)
+
+func (ctx *CheckContext) base() context.Context {
+	if ctx == nil || ctx.runCtx == nil {
+		return context.Background()
+	}
+	return ctx.runCtx
+}
+
+// Deadline returns when the running check times out, if it has a timeout.
+func (ctx *CheckContext) Deadline() (time.Time, bool) { return ctx.base().Deadline() }
+
+// Done is closed when the running check times out.
+func (ctx *CheckContext) Done() <-chan struct{} { return ctx.base().Done() }
+
+// Err returns context.DeadlineExceeded once the running check has timed out.
+func (ctx *CheckContext) Err() error { return ctx.base().Err() }
+
+// Value returns the value for key from the underlying context.
+func (ctx *CheckContext) Value(key any) any { return ctx.base().Value(key) }
+
 // CheckResult represents the outcome of a health check.
 type CheckResult struct {
-	Name    string      // Check name
-	Status  CheckStatus // Result status
-	Message string      // Primary result message
-	Details []string    // Additional information
-	FixHint string      // Suggestion if not auto-fixable
+	Name    string        // Check name
+	Status  CheckStatus   // Result status
+	Message string        // Primary result message
+	Details []string      // Additional information
+	FixHint string        // Manual guidance that has no concrete command
+	Actions []FixAction   // Commands that resolve the problem, in preferred order
+	Cached  bool          // Result reused from the doctor cache (--changed-only)
+	Fixed   bool          // A fix was applied successfully during this run
+	Elapsed time.Duration // How long the check (and any fix) took to run
+
+	// Baselined is set when every finding was acknowledged in the town's
+	// doctor baseline; the result then does not count as a failure.
+	Baselined bool
 }
 
 // Check defines the interface for a health check.
@@ -70,7 +137,9 @@ type Check interface {
 	// Description returns a human-readable description.
 	Description() string
 
-	// Run executes the check and returns a result.
+	// Run executes the check and returns a result. ctx is also a
+	// context.Context that is cancelled when the check times out; checks
+	// that run subprocesses should use exec.CommandContext(ctx, ...).
 	Run(ctx *CheckContext) *CheckResult
 
 	// Fix attempts to automatically fix the issue.
@@ -83,10 +152,18 @@ type Check interface {
 
 // ReportSummary summarizes the results of all checks.
 type ReportSummary struct {
-	Total    int
-	OK       int
-	Warnings int
-	Errors   int
+	Total    int `json:"total"`
+	OK       int `json:"ok"`
+	Warnings int `json:"warnings"`
+	Errors   int `json:"errors"`
+	TimedOut int `json:"timed_out"`
+	Blocked  int `json:"blocked"`
+	Cached   int `json:"cached"`
+	Fixed    int `json:"fixed"`
+
+	// Baselined counts warnings and errors acknowledged in the doctor
+	// baseline; they are not included in Warnings or Errors.
+	Baselined int `json:"baselined,omitempty"`
 }
 
 // Report contains all check results and a summary.
@@ -94,6 +171,11 @@ type Report struct {
 	Timestamp time.Time
 	Checks    []*CheckResult
 	Summary   ReportSummary
+
+	// Interrupted is set when the run was cancelled; NotRun lists the
+	// checks that were skipped as a result.
+	Interrupted bool
+	NotRun      []string
 }
 
 // NewReport creates an empty report with the current timestamp.
@@ -108,6 +190,17 @@ func NewReport() *Report {
 func (r *Report) Add(result *CheckResult) {
 	r.Checks = append(r.Checks, result)
 	r.Summary.Total++
+	if result.Cached {
+		r.Summary.Cached++
+	}
+	if result.Fixed {
+		r.Summary.Fixed++
+	}
+
+	if result.Baselined {
+		r.Summary.Baselined++
+		return
+	}
 
 	switch result.Status {
 	case StatusOK:
@@ -116,6 +209,10 @@ func (r *Report) Add(result *CheckResult) {
 		r.Summary.Warnings++
 	case StatusError:
 		r.Summary.Errors++
+	case StatusTimeout:
+		r.Summary.TimedOut++
+	case StatusBlocked:
+		r.Summary.Blocked++
 	}
 }
 
@@ -131,23 +228,58 @@ func (r *Report) HasWarnings() bool {
 
 // IsHealthy returns true if all checks passed without errors or warnings.
 func (r *Report) IsHealthy() bool {
-	return r.Summary.Errors == 0 && r.Summary.Warnings == 0
+	return r.Summary.Errors == 0 && r.Summary.Warnings == 0 && r.Summary.TimedOut == 0
+}
+
+// Exit codes returned by gt doctor so scripts and CI can branch on the
+// outcome without parsing output.
+const (
+	ExitOK          = 0 // All checks passed
+	ExitErrors      = 1 // At least one check reported an error
+	ExitWarnings    = 2 // Only warnings were reported
+	ExitFixedIssues = 3 // Fixes were applied but warnings or errors remain
+	ExitTimedOut    = 4 // No errors, but at least one check timed out
+
+	ExitInterrupted = 130 // The run was interrupted (ctrl-C) before finishing
+)
+
+// ExitCode returns the process exit code summarizing the report.
+func (r *Report) ExitCode() int {
+	switch {
+	case r.Interrupted:
+		return ExitInterrupted
+	case r.IsHealthy():
+		return ExitOK
+	case r.Summary.Fixed > 0:
+		return ExitFixedIssues
+	case r.HasErrors():
+		return ExitErrors
+	case r.Summary.TimedOut > 0:
+		return ExitTimedOut
+	default:
+		return ExitWarnings
+	}
 }
 
 // Print outputs the report to the given writer.
 func (r *Report) Print(w io.Writer, verbose bool) {
 	// Print individual check results
 	for _, check := range r.Checks {
-		r.printCheck(w, check, verbose)
+		PrintCheck(w, check, verbose)
 	}
 
 	// Print summary (output errors non-actionable)
 	_, _ = fmt.Fprintln(w)
-	r.printSummary(w)
+	r.PrintSummary(w)
 }
 
-// printCheck outputs a single check result (output errors non-actionable).
-func (r *Report) printCheck(w io.Writer, check *CheckResult, verbose bool) {
+// slowCheckThreshold is how long a check may take before its result line
+// shows the elapsed time.
+const slowCheckThreshold = time.Second
+
+// PrintCheck outputs a single check result, for streaming results as checks
+// finish (output errors non-actionable).
+func PrintCheck(w io.Writer, check *CheckResult, verbose bool) {
 	var prefix string
 	switch check.Status {
 	case StatusOK:
@@ -156,9 +288,21 @@ func (r *Report) printCheck(w io.Writer, check *CheckResult, verbose bool) {
 		prefix = style.WarningPrefix
 	case StatusError:
 		prefix = style.ErrorPrefix
+	case StatusTimeout:
+		prefix = style.Warning.Render("[?]")
+	case StatusBlocked:
+		prefix = style.Dim.Render("[-]")
+	}
+	if check.Baselined {
+		_, _ = fmt.Fprintf(w, "%s %s: %s %s\n", style.Dim.Render("[~]"), check.Name, check.Message, style.Dim.Render("(baselined)"))
+		return
 	}
 
-	_, _ = fmt.Fprintf(w, "%s %s: %s\n", prefix, check.Name, check.Message)
+	elapsed := ""
+	if check.Elapsed >= slowCheckThreshold {
+		elapsed = " " + style.Dim.Render(fmt.Sprintf("(%.1fs)", check.Elapsed.Seconds()))
+	}
+	_, _ = fmt.Fprintf(w, "%s %s: %s%s\n", prefix, check.Name, check.Message, elapsed)
 
 	// Print details in verbose mode or for non-OK results
 	if len(check.Details) > 0 && (verbose || check.Status != StatusOK) {
@@ -168,13 +312,13 @@ func (r *Report) printCheck(w io.Writer, check *CheckResult, verbose bool) {
 	}
 
 	// Print fix hint for errors/warnings
-	if check.FixHint != "" && check.Status != StatusOK {
-		_, _ = fmt.Fprintf(w, "    %s %s\n", style.ArrowPrefix, check.FixHint)
+	if hint := check.Hint(); hint != "" && check.Status != StatusOK {
+		_, _ = fmt.Fprintf(w, "    %s %s\n", style.ArrowPrefix, hint)
 	}
 }
 
-// printSummary outputs the summary line (output errors non-actionable).
-func (r *Report) printSummary(w io.Writer) {
+// PrintSummary outputs the summary line (output errors non-actionable).
+func (r *Report) PrintSummary(w io.Writer) {
 	parts := []string{
 		fmt.Sprintf("%d checks", r.Summary.Total),
 	}
@@ -188,6 +332,24 @@ func (r *Report) printSummary(w io.Writer) {
 	if r.Summary.Errors > 0 {
 		parts = append(parts, style.Error.Render(fmt.Sprintf("%d errors", r.Summary.Errors)))
 	}
+	if r.Summary.TimedOut > 0 {
+		parts = append(parts, style.Warning.Render(fmt.Sprintf("%d timed out", r.Summary.TimedOut)))
+	}
+	if r.Summary.Blocked > 0 {
+		parts = append(parts, style.Dim.Render(fmt.Sprintf("%d blocked", r.Summary.Blocked)))
+	}
+	if r.Summary.Fixed > 0 {
+		parts = append(parts, style.Info.Render(fmt.Sprintf("%d fixed", r.Summary.Fixed)))
+	}
+	if r.Summary.Cached > 0 {
+		parts = append(parts, style.Dim.Render(fmt.Sprintf("%d cached", r.Summary.Cached)))
+	}
+	if r.Summary.Baselined > 0 {
+		parts = append(parts, style.Dim.Render(fmt.Sprintf("%d baselined", r.Summary.Baselined)))
+	}
+	if r.Interrupted {
+		parts = append(parts, style.Warning.Render(fmt.Sprintf("interrupted (%d not run)", len(r.NotRun))))
+	}
 
 	_, _ = fmt.Fprintln(w, strings.Join(parts, ", "))
 }
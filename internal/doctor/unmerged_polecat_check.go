@@ -0,0 +1,190 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+)
+
+// polecatMergeRecord reports whether an open or in-progress merge request
+// names branch; known is false when the rig's beads could not be read
+// (seam for tests).
+var polecatMergeRecord = func(townRoot, rigName, branch string) (found, known bool) {
+	resolved := beads.ResolveBeadsDir(filepath.Join(townRoot, rigName))
+	b := beads.NewWithBeadsDir(filepath.Dir(resolved), resolved)
+	branchPrefix := "branch: " + branch + "\n"
+	for _, status := range []string{"open", "in_progress"} {
+		issues, err := b.List(beads.ListOptions{Status: status, Type: "merge-request", Priority: -1})
+		if err != nil {
+			return false, false
+		}
+		for _, issue := range issues {
+			if strings.HasPrefix(issue.Description, branchPrefix) {
+				return true, true
+			}
+		}
+	}
+	return false, true
+}
+
+// UnmergedPolecatCheck surfaces finished-but-unmerged polecat work: no
+// session, hooked work closed (or nothing hooked), and commits on the
+// branch that are not on the rig's default branch, with no open merge
+// request or unread handoff to carry them. Recycling such a directory
+// would strand the work on a salvage branch at best.
+type UnmergedPolecatCheck struct {
+	BaseCheck
+	listUnread func(townRoot string) ([]*mail.Message, error)
+}
+
+// unmergedPolecat is a polecat branch with work no one is carrying.
+type unmergedPolecat struct {
+	rigName string
+	name    string
+	branch  string
+	ahead   int
+	hook    string // Closed hooked bead, if any
+}
+
+// NewUnmergedPolecatCheck creates a new unmerged polecat branch check.
+func NewUnmergedPolecatCheck() *UnmergedPolecatCheck {
+	return &UnmergedPolecatCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "unmerged-polecat-branches",
+			CheckDescription: "Detect finished polecat branches with unmerged commits and no merge request",
+			CheckDependsOn:   []string{"tmux"},
+		},
+		listUnread: mail.ListTownUnread,
+	}
+}
+
+// Run scans every rig's polecat clones for unmerged, uncarried work.
+func (c *UnmergedPolecatCheck) Run(ctx *CheckContext) *CheckResult {
+	if result := skipPolecatsWithoutTmux(c.Name()); result != nil {
+		return result
+	}
+
+	rigs, err := discoverRigs(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not read rigs registry",
+			Details: []string{err.Error()},
+		}
+	}
+	sort.Strings(rigs)
+
+	handoffs := c.pendingHandoffs(ctx.TownRoot)
+	var unmerged []unmergedPolecat
+	for _, rigName := range rigs {
+		unmerged = append(unmerged, findUnmergedPolecats(ctx, ctx.TownRoot, rigName, handoffs)...)
+	}
+
+	if len(unmerged) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No finished polecat work waiting to be merged",
+		}
+	}
+
+	var details []string
+	for _, u := range unmerged {
+		detail := fmt.Sprintf("%s/polecats/%s: %s has %d commit(s) not on the default branch", u.rigName, u.name, u.branch, u.ahead)
+		if u.hook != "" {
+			detail += fmt.Sprintf(" (%s is closed)", u.hook)
+		}
+		details = append(details, detail)
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d polecat branch(es) with unmerged work and no merge request", len(unmerged)),
+		Details: details,
+		FixHint: "Submit each branch before its directory is recycled: gt mq submit --branch <branch> (from the rig), or review and discard it",
+	}
+}
+
+// pendingHandoffs returns the polecat identities (rig/name) with unread
+// handoff mail; their next session will pick the work up.
+func (c *UnmergedPolecatCheck) pendingHandoffs(townRoot string) map[string]bool {
+	handoffs := make(map[string]bool)
+	if _, err := os.Stat(filepath.Join(townRoot, ".beads")); err != nil {
+		return handoffs
+	}
+	messages, err := c.listUnread(townRoot)
+	if err != nil {
+		return handoffs
+	}
+	for _, msg := range messages {
+		if strings.Contains(msg.Subject, "HANDOFF") {
+			handoffs[polecatIdentity(msg.To)] = true
+		}
+	}
+	return handoffs
+}
+
+// polecatIdentity normalizes rig/polecats/name to rig/name.
+func polecatIdentity(address string) string {
+	parts := strings.Split(strings.TrimSuffix(address, "/"), "/")
+	if len(parts) == 3 && parts[1] == "polecats" {
+		return parts[0] + "/" + parts[2]
+	}
+	return strings.Join(parts, "/")
+}
+
+// findUnmergedPolecats returns the finished polecats in a rig whose branch
+// has commits no merge request or handoff carries.
+func findUnmergedPolecats(ctx context.Context, townRoot, rigName string, handoffs map[string]bool) []unmergedPolecat {
+	polecatsDir := filepath.Join(townRoot, rigName, "polecats")
+	defaultBranch := (&BranchCheck{}).getExpectedBranch(townRoot, polecatsDir)
+
+	var found []unmergedPolecat
+	for _, name := range listAgentDirs(polecatsDir) {
+		dir := filepath.Join(polecatsDir, name)
+		if polecatSessionRunning(session.PolecatSessionName(rigName, name)) {
+			continue
+		}
+		hook, known := polecatHookBead(townRoot, rigName, name)
+		if !known {
+			continue
+		}
+		if hook != "" {
+			if closed, known := polecatWorkClosed(townRoot, rigName, hook); !known || !closed {
+				continue // Work still in progress
+			}
+		}
+
+		branch, err := gitIn(ctx, dir, "symbolic-ref", "--short", "-q", "HEAD")
+		if err != nil || branch == "" {
+			continue // Detached or not a checkout
+		}
+		count, err := gitIn(ctx, dir, "rev-list", "--count", "origin/"+defaultBranch+"..HEAD")
+		if err != nil {
+			continue
+		}
+		ahead, _ := strconv.Atoi(count)
+		if ahead == 0 {
+			continue
+		}
+
+		if handoffs[rigName+"/"+name] {
+			continue
+		}
+		if found, known := polecatMergeRecord(townRoot, rigName, branch); found || !known {
+			continue
+		}
+
+		found = append(found, unmergedPolecat{rigName: rigName, name: name, branch: branch, ahead: ahead, hook: hook})
+	}
+	return found
+}
@@ -0,0 +1,72 @@
+package doctor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+)
+
+func TestFindUnmergedPolecats(t *testing.T) {
+	townRoot := t.TempDir()
+	repo := filepath.Join(townRoot, "gp", "mayor", "rig")
+	initGitRepo(t, repo)
+	runGit(t, repo, "update-ref", "refs/remotes/origin/main", "HEAD")
+
+	worktree := func(name string, commit bool) {
+		dir := filepath.Join(townRoot, "gp", "polecats", name)
+		runGit(t, repo, "worktree", "add", "-q", "-b", "polecat/"+name, dir)
+		if commit {
+			mustWrite(t, filepath.Join(dir, name+".go"), "package x\n")
+			runGit(t, dir, "add", name+".go")
+			runGit(t, dir, "commit", "-q", "-m", name)
+		}
+	}
+	worktree("Finished", true)
+	worktree("Idle", true)
+	worktree("Clean", false)
+	worktree("Submitted", true)
+	worktree("HandedOff", true)
+	worktree("Working", true)
+	worktree("Running", true)
+
+	stubPolecatSeams(t,
+		map[string]bool{"gt-gp-Running": true},
+		map[string]string{"Finished": "gp-1", "Working": "gp-2"})
+	origClosed, origMerge := polecatWorkClosed, polecatMergeRecord
+	t.Cleanup(func() { polecatWorkClosed, polecatMergeRecord = origClosed, origMerge })
+	polecatWorkClosed = func(_, _, id string) (bool, bool) { return id == "gp-1", true }
+	polecatMergeRecord = func(_, _, branch string) (bool, bool) { return branch == "polecat/Submitted", true }
+
+	got := map[string]unmergedPolecat{}
+	for _, u := range findUnmergedPolecats(context.Background(), townRoot, "gp", map[string]bool{"gp/HandedOff": true}) {
+		got[u.name] = u
+	}
+	if len(got) != 2 {
+		t.Fatalf("unmerged = %+v, want Finished and Idle", got)
+	}
+	if u := got["Finished"]; u.branch != "polecat/Finished" || u.ahead != 1 || u.hook != "gp-1" {
+		t.Errorf("Finished = %+v", u)
+	}
+	if _, ok := got["Idle"]; !ok {
+		t.Error("Idle polecat with unmerged commits should be reported")
+	}
+}
+
+func TestUnmergedPolecatPendingHandoffs(t *testing.T) {
+	townRoot := t.TempDir()
+	mustWrite(t, filepath.Join(townRoot, ".beads", "config.yaml"), "")
+	c := NewUnmergedPolecatCheck()
+	c.listUnread = func(string) ([]*mail.Message, error) {
+		return []*mail.Message{
+			{To: "gp/polecats/Toast", Subject: "🤝 HANDOFF: finish tests"},
+			{To: "gp/Nux", Subject: "🤝 HANDOFF: rebase"},
+			{To: "gp/Slit", Subject: "status?"},
+		}, nil
+	}
+	got := c.pendingHandoffs(townRoot)
+	if !got["gp/Toast"] || !got["gp/Nux"] || got["gp/Slit"] {
+		t.Errorf("handoffs = %v, want gp/Toast and gp/Nux", got)
+	}
+}
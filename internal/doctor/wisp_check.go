@@ -76,7 +76,7 @@ func (c *WispGCCheck) Run(ctx *CheckContext) *CheckResult {
 			Status:  StatusWarning,
 			Message: fmt.Sprintf("%d abandoned wisp(s) found (>1h old)", totalAbandoned),
 			Details: details,
-			FixHint: "Run 'gt doctor --fix' to garbage collect orphaned wisps",
+			Actions: []FixAction{doctorFix("garbage collect orphaned wisps", true)},
 		}
 	}
 
@@ -135,7 +135,7 @@ func (c *WispGCCheck) Fix(ctx *CheckContext) error {
 		rigPath := filepath.Join(ctx.TownRoot, rigName)
 
 		// Run bd --no-daemon mol wisp gc
-		cmd := exec.Command("bd", "--no-daemon", "mol", "wisp", "gc")
+		cmd := exec.CommandContext(ctx, "bd", "--no-daemon", "mol", "wisp", "gc")
 		cmd.Dir = rigPath
 		if output, err := cmd.CombinedOutput(); err != nil {
 			lastErr = fmt.Errorf("%s: %v (%s)", rigName, err, string(output))
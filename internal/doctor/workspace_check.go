@@ -31,7 +31,7 @@ func (c *TownConfigExistsCheck) Run(ctx *CheckContext) *CheckResult {
 			Name:    c.Name(),
 			Status:  StatusError,
 			Message: "mayor/town.json not found",
-			FixHint: "Run 'gt install' to initialize workspace",
+			Actions: []FixAction{{Command: "gt", Args: []string{"install"}, Description: "initialize workspace"}},
 		}
 	}
 
@@ -42,6 +42,11 @@ func (c *TownConfigExistsCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// Inputs returns the files this check depends on (see InputFingerprinter).
+func (c *TownConfigExistsCheck) Inputs(ctx *CheckContext) []string {
+	return []string{filepath.Join(ctx.TownRoot, "mayor", "town.json")}
+}
+
 // TownConfigValidCheck verifies mayor/town.json is valid JSON with required fields.
 type TownConfigValidCheck struct {
 	BaseCheck
@@ -53,6 +58,7 @@ func NewTownConfigValidCheck() *TownConfigValidCheck {
 		BaseCheck: BaseCheck{
 			CheckName:        "town-config-valid",
 			CheckDescription: "Check that mayor/town.json is valid with required fields",
+			CheckDependsOn:   []string{"town-config-exists"},
 		},
 	}
 }
@@ -118,6 +124,11 @@ func (c *TownConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// Inputs returns the files this check depends on (see InputFingerprinter).
+func (c *TownConfigValidCheck) Inputs(ctx *CheckContext) []string {
+	return []string{filepath.Join(ctx.TownRoot, "mayor", "town.json")}
+}
+
 // RigsRegistryExistsCheck verifies mayor/rigs.json exists.
 type RigsRegistryExistsCheck struct {
 	FixableCheck
@@ -144,7 +155,7 @@ func (c *RigsRegistryExistsCheck) Run(ctx *CheckContext) *CheckResult {
 			Name:    c.Name(),
 			Status:  StatusWarning,
 			Message: "mayor/rigs.json not found (no rigs registered)",
-			FixHint: "Run 'gt doctor --fix' to create empty rigs.json",
+			Actions: []FixAction{doctorFix("create empty rigs.json", false)},
 		}
 	}
 
@@ -155,6 +166,11 @@ func (c *RigsRegistryExistsCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// Inputs returns the files this check depends on (see InputFingerprinter).
+func (c *RigsRegistryExistsCheck) Inputs(ctx *CheckContext) []string {
+	return []string{filepath.Join(ctx.TownRoot, "mayor", "rigs.json")}
+}
+
 // Fix creates an empty rigs.json file.
 func (c *RigsRegistryExistsCheck) Fix(ctx *CheckContext) error {
 	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
@@ -172,6 +188,9 @@ func (c *RigsRegistryExistsCheck) Fix(ctx *CheckContext) error {
 		return fmt.Errorf("marshaling empty rigs.json: %w", err)
 	}
 
+	if err := ctx.Backup.Save(rigsPath); err != nil {
+		return err
+	}
 	return os.WriteFile(rigsPath, data, 0644)
 }
 
@@ -188,6 +207,7 @@ func NewRigsRegistryValidCheck() *RigsRegistryValidCheck {
 			BaseCheck: BaseCheck{
 				CheckName:        "rigs-registry-valid",
 				CheckDescription: "Check that registered rigs exist on disk",
+				CheckDependsOn:   []string{"rigs-registry-exists"},
 			},
 		},
 	}
@@ -266,7 +286,7 @@ func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 			Status:  StatusWarning,
 			Message: fmt.Sprintf("%d of %d registered rig(s) missing", len(missing), len(config.Rigs)),
 			Details: details,
-			FixHint: "Run 'gt doctor --fix' to remove missing rigs from registry",
+			Actions: []FixAction{doctorFix("remove missing rigs from registry", false)},
 		}
 	}
 
@@ -277,6 +297,16 @@ func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// Inputs returns the files this check depends on (see InputFingerprinter).
+// The town root is included because rig directories being added or removed
+// changes its modification time.
+func (c *RigsRegistryValidCheck) Inputs(ctx *CheckContext) []string {
+	return []string{
+		filepath.Join(ctx.TownRoot, "mayor", "rigs.json"),
+		ctx.TownRoot,
+	}
+}
+
 // Fix removes missing rigs from the registry.
 func (c *RigsRegistryValidCheck) Fix(ctx *CheckContext) error {
 	if len(c.missingRigs) == 0 {
@@ -306,6 +336,9 @@ func (c *RigsRegistryValidCheck) Fix(ctx *CheckContext) error {
 		return fmt.Errorf("marshaling rigs.json: %w", err)
 	}
 
+	if err := ctx.Backup.Save(rigsPath); err != nil {
+		return err
+	}
 	return os.WriteFile(rigsPath, newData, 0644)
 }
 
@@ -334,7 +367,7 @@ func (c *MayorExistsCheck) Run(ctx *CheckContext) *CheckResult {
 			Name:    c.Name(),
 			Status:  StatusError,
 			Message: "mayor/ directory not found",
-			FixHint: "Run 'gt install' to initialize workspace",
+			Actions: []FixAction{{Command: "gt", Args: []string{"install"}, Description: "initialize workspace"}},
 		}
 	}
 	if !info.IsDir() {
@@ -373,6 +406,14 @@ func (c *MayorExistsCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// Inputs returns the files this check depends on (see InputFingerprinter).
+func (c *MayorExistsCheck) Inputs(ctx *CheckContext) []string {
+	return []string{
+		filepath.Join(ctx.TownRoot, "mayor"),
+		filepath.Join(ctx.TownRoot, "mayor", "town.json"),
+	}
+}
+
 // WorkspaceChecks returns all workspace-level health checks.
 func WorkspaceChecks() []Check {
 	return []Check{
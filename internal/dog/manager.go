@@ -87,7 +87,10 @@ func (m *Manager) Add(name string) (*Dog, error) {
 
 	// Create worktrees into each rig
 	worktrees := make(map[string]string)
-	for rigName := range m.rigsConfig.Rigs {
+	for rigName, entry := range m.rigsConfig.Rigs {
+		if entry.Mirror {
+			continue // Mirror rigs are read-only references, no worktrees
+		}
 		worktreePath, err := m.createRigWorktree(dogPath, name, rigName)
 		if err != nil {
 			return nil, fmt.Errorf("creating worktree for rig %s: %w", rigName, err)
@@ -331,7 +334,10 @@ func (m *Manager) Refresh(name string) error {
 	newWorktrees := make(map[string]string)
 
 	// Recreate each worktree
-	for rigName := range m.rigsConfig.Rigs {
+	for rigName, entry := range m.rigsConfig.Rigs {
+		if entry.Mirror {
+			continue
+		}
 		rigPath := filepath.Join(m.townRoot, rigName)
 		oldWorktreePath := state.Worktrees[rigName]
 
@@ -421,7 +427,10 @@ func (m *Manager) RefreshRig(name, rigName string) error {
 func (m *Manager) CleanupStaleBranches() (int, error) {
 	totalDeleted := 0
 
-	for rigName := range m.rigsConfig.Rigs {
+	for rigName, entry := range m.rigsConfig.Rigs {
+		if entry.Mirror {
+			continue
+		}
 		rigPath := filepath.Join(m.townRoot, rigName)
 		repoGit, err := m.findRepoBase(rigPath)
 		if err != nil {
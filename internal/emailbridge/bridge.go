@@ -0,0 +1,469 @@
+// Package emailbridge connects the overseer inbox to the operator's real
+// email, so escalations can be handled from a phone.
+//
+// Each sync forwards new escalations in the overseer inbox to the operator
+// over SMTP, then reads replies from a dedicated IMAP mailbox (or Gmail
+// label) and delivers them to the escalating agent as mail from the
+// overseer. Forwarded emails carry a random token in their Message-ID and
+// subject; a reply is accepted only if it comes from the operator's address
+// and carries the token of a message that was forwarded.
+//
+// Bridge state is stored in <town>/.runtime/email-bridge.json.
+package emailbridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+	"github.com/cursorworkshop/cursor-gastown/internal/secrets"
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// StateFile is the bridge state file name within the town .runtime directory.
+const StateFile = "email-bridge.json"
+
+// ForwardWindow is how old overseer mail may be and still be forwarded, so
+// turning the bridge on does not email the whole inbox backlog.
+const ForwardWindow = 24 * time.Hour
+
+// forwardRetention is how long forwarded messages accept replies.
+const forwardRetention = 30 * 24 * time.Hour
+
+// maxRepliesPerSync bounds the emails read from the reply mailbox per sync.
+const maxRepliesPerSync = 50
+
+// ErrNotConfigured is returned when the town has no email_bridge settings.
+var ErrNotConfigured = errors.New("email bridge not configured")
+
+// escalationSeverities are the subject tags gt escalate puts on mail.
+var escalationSeverities = []string{"CRITICAL", "HIGH", "MEDIUM"}
+
+// IsEscalation reports whether a message is an escalation (sent by gt
+// escalate, with a "[SEVERITY] " subject tag).
+func IsEscalation(msg *mail.Message) bool {
+	for _, s := range escalationSeverities {
+		if strings.HasPrefix(msg.Subject, "["+s+"] ") {
+			return true
+		}
+	}
+	return false
+}
+
+// Sender sends email.
+type Sender interface {
+	Send(from string, to []string, msg []byte) error
+}
+
+// Email is a raw email read from the reply mailbox.
+type Email struct {
+	UID  uint32
+	Data []byte
+}
+
+// Inbox reads the reply mailbox.
+type Inbox interface {
+	// Since returns the emails with UIDs above after, and the mailbox's
+	// UIDVALIDITY. When validity differs from the mailbox's, UIDs were
+	// reassigned and every email is returned.
+	Since(ctx context.Context, validity, after uint32) ([]Email, uint32, error)
+
+	// Check logs in and opens the mailbox.
+	Check(ctx context.Context) error
+}
+
+// TownMail is the town mail system, as seen by the overseer.
+type TownMail interface {
+	// Unread returns the overseer's unread mail.
+	Unread() ([]*mail.Message, error)
+	// Send delivers a message.
+	Send(msg *mail.Message) error
+	// MarkRead marks an overseer message as read.
+	MarkRead(id string) error
+}
+
+// Forward records an overseer message that was emailed to the operator.
+type Forward struct {
+	MessageID string    `json:"message_id"`
+	From      string    `json:"from"`
+	Subject   string    `json:"subject"`
+	ThreadID  string    `json:"thread_id,omitempty"`
+	SentAt    time.Time `json:"sent_at"`
+	Replies   []string  `json:"replies,omitempty"` // Message-IDs of ingested reply emails
+}
+
+// State is the persisted bridge state.
+type State struct {
+	// Forwarded maps tokens to the messages they were sent for.
+	Forwarded map[string]*Forward `json:"forwarded"`
+
+	// UIDValidity and LastUID locate the last email read from the reply
+	// mailbox.
+	UIDValidity uint32 `json:"uid_validity,omitempty"`
+	LastUID     uint32 `json:"last_uid,omitempty"`
+
+	// LastSync is when the bridge last synced.
+	LastSync time.Time `json:"last_sync,omitempty"`
+}
+
+// StatePath returns the bridge state file path for a town.
+func StatePath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), StateFile)
+}
+
+// LoadState reads bridge state for a town. A missing file yields empty state.
+func LoadState(townRoot string) (*State, error) {
+	s := &State{}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading email bridge state: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("parsing email bridge state: %w", err)
+		}
+	}
+	if s.Forwarded == nil {
+		s.Forwarded = make(map[string]*Forward)
+	}
+	return s, nil
+}
+
+// Save writes bridge state for a town.
+func (s *State) Save(townRoot string) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	if err := util.AtomicWriteJSON(path, s); err != nil {
+		return fmt.Errorf("writing email bridge state: %w", err)
+	}
+	return nil
+}
+
+// forwarded reports whether a message has been forwarded.
+func (s *State) forwarded(messageID string) bool {
+	for _, f := range s.Forwarded {
+		if f.MessageID == messageID {
+			return true
+		}
+	}
+	return false
+}
+
+// prune drops forwards too old to accept replies.
+func (s *State) prune(now time.Time) {
+	for token, f := range s.Forwarded {
+		if now.Sub(f.SentAt) > forwardRetention {
+			delete(s.Forwarded, token)
+		}
+	}
+}
+
+// Bridge moves mail between the overseer inbox and the operator's email.
+type Bridge struct {
+	townRoot string
+	cfg      *config.EmailBridgeConfig
+	sender   Sender
+	inbox    Inbox
+	town     TownMail
+	now      func() time.Time
+}
+
+// New returns a bridge for a town's email_bridge settings, resolving the
+// server passwords.
+func New(townRoot string, cfg *config.EmailBridgeConfig) (*Bridge, error) {
+	smtpPassword, err := secrets.Resolve(cfg.SMTP.Password)
+	if err != nil {
+		return nil, fmt.Errorf("email_bridge smtp.password: %w", err)
+	}
+	imapPassword, err := secrets.Resolve(cfg.IMAP.Password)
+	if err != nil {
+		return nil, fmt.Errorf("email_bridge imap.password: %w", err)
+	}
+	smtpPort := cfg.SMTP.Port
+	if smtpPort == 0 {
+		smtpPort = config.DefaultSMTPPort
+	}
+	imapPort := cfg.IMAP.Port
+	if imapPort == 0 {
+		imapPort = config.DefaultIMAPPort
+	}
+	return &Bridge{
+		townRoot: townRoot,
+		cfg:      cfg,
+		sender:   &smtpSender{host: cfg.SMTP.Host, port: smtpPort, username: cfg.SMTP.Username, password: smtpPassword},
+		inbox:    &imapInbox{host: cfg.IMAP.Host, port: imapPort, username: cfg.IMAP.Username, password: imapPassword, mailbox: cfg.ReplyMailbox()},
+		town:     newRouterMail(townRoot),
+		now:      time.Now,
+	}, nil
+}
+
+// ForTown returns the bridge configured in a town's settings, or
+// ErrNotConfigured if it has none.
+func ForTown(townRoot string) (*Bridge, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	if settings.EmailBridge == nil {
+		return nil, ErrNotConfigured
+	}
+	return New(townRoot, settings.EmailBridge)
+}
+
+// Result is what one sync did.
+type Result struct {
+	// Forwarded lists the overseer messages emailed to the operator.
+	Forwarded []*mail.Message
+
+	// Delivered lists the replies delivered as town mail.
+	Delivered []*mail.Message
+
+	// Rejected lists emails in the reply mailbox that were not delivered,
+	// with the reason.
+	Rejected []string
+}
+
+// Sync forwards new overseer escalations and ingests operator replies.
+// Failures in one direction do not stop the other; both are returned.
+func (b *Bridge) Sync(ctx context.Context) (*Result, error) {
+	state, err := LoadState(b.townRoot)
+	if err != nil {
+		return nil, err
+	}
+	state.prune(b.now())
+
+	result := &Result{}
+	forwardErr := b.forward(state, result)
+	ingestErr := b.ingest(ctx, state, result)
+	state.LastSync = b.now()
+	if err := state.Save(b.townRoot); err != nil {
+		return result, err
+	}
+	return result, errors.Join(forwardErr, ingestErr)
+}
+
+// forward emails unread overseer mail that has not been forwarded yet.
+func (b *Bridge) forward(state *State, result *Result) error {
+	messages, err := b.town.Unread()
+	if err != nil {
+		return fmt.Errorf("listing overseer mail: %w", err)
+	}
+	now := b.now()
+	for _, msg := range messages {
+		if state.forwarded(msg.ID) || now.Sub(msg.Timestamp) > ForwardWindow {
+			continue
+		}
+		if !b.cfg.ForwardAll && !IsEscalation(msg) {
+			continue
+		}
+		token := newToken()
+		email := forwardEmail(b.cfg.From, b.cfg.Operator, b.cfg.ReplyAddress(), token, msg, now)
+		if err := b.sender.Send(b.cfg.From, []string{b.cfg.Operator}, email); err != nil {
+			return fmt.Errorf("forwarding %s: %w", msg.ID, err)
+		}
+		state.Forwarded[token] = &Forward{
+			MessageID: msg.ID,
+			From:      msg.From,
+			Subject:   msg.Subject,
+			ThreadID:  msg.ThreadID,
+			SentAt:    now,
+		}
+		result.Forwarded = append(result.Forwarded, msg)
+	}
+	return nil
+}
+
+// ingest delivers operator replies from the reply mailbox as town mail.
+func (b *Bridge) ingest(ctx context.Context, state *State, result *Result) error {
+	emails, validity, err := b.inbox.Since(ctx, state.UIDValidity, state.LastUID)
+	if err != nil {
+		return fmt.Errorf("reading replies: %w", err)
+	}
+	if validity != state.UIDValidity {
+		state.UIDValidity = validity
+		state.LastUID = 0
+	}
+	for _, email := range emails {
+		reason, err := b.deliverReply(state, email, result)
+		if err != nil {
+			// Leave the email to be retried on the next sync.
+			return err
+		}
+		if reason != "" {
+			result.Rejected = append(result.Rejected, fmt.Sprintf("email %d: %s", email.UID, reason))
+		}
+		state.LastUID = email.UID
+	}
+	return nil
+}
+
+// deliverReply delivers one reply email. It returns why the email was not
+// delivered, or an error if delivery failed and should be retried.
+func (b *Bridge) deliverReply(state *State, email Email, result *Result) (string, error) {
+	reply, err := parseReply(email.Data)
+	if err != nil {
+		return err.Error(), nil
+	}
+	if !strings.EqualFold(reply.From, b.cfg.Operator) {
+		return fmt.Sprintf("sent by %s, not the operator", reply.From), nil
+	}
+	fwd, ok := state.Forwarded[reply.Token]
+	if !ok {
+		return "not a reply to a forwarded message", nil
+	}
+	if reply.ID != "" {
+		for _, id := range fwd.Replies {
+			if id == reply.ID {
+				return "", nil
+			}
+		}
+	}
+	if reply.Body == "" {
+		return "reply is empty", nil
+	}
+
+	subject := fwd.Subject
+	if !strings.HasPrefix(subject, "Re: ") {
+		subject = "Re: " + subject
+	}
+	msg := &mail.Message{
+		From:     "overseer",
+		To:       fwd.From,
+		Subject:  subject,
+		Body:     reply.Body,
+		Type:     mail.TypeReply,
+		Priority: mail.PriorityNormal,
+		ReplyTo:  fwd.MessageID,
+		ThreadID: fwd.ThreadID,
+	}
+	if err := b.town.Send(msg); err != nil {
+		return "", fmt.Errorf("delivering reply to %s: %w", fwd.From, err)
+	}
+	// The operator has handled the escalation.
+	_ = b.town.MarkRead(fwd.MessageID)
+	fwd.Replies = append(fwd.Replies, reply.ID)
+	result.Delivered = append(result.Delivered, msg)
+	return "", nil
+}
+
+// Test sends a test email to the operator and logs in to the reply mailbox.
+func (b *Bridge) Test(ctx context.Context) error {
+	msg := &mail.Message{
+		ID:        "test",
+		From:      "gt mail bridge test",
+		Subject:   "Gas Town email bridge test",
+		Body:      "The email bridge can reach you. Escalations will arrive like this one.",
+		Timestamp: b.now(),
+	}
+	email := forwardEmail(b.cfg.From, b.cfg.Operator, b.cfg.ReplyAddress(), newToken(), msg, b.now())
+	if err := b.sender.Send(b.cfg.From, []string{b.cfg.Operator}, email); err != nil {
+		return fmt.Errorf("sending test email: %w", err)
+	}
+	if err := b.inbox.Check(ctx); err != nil {
+		return fmt.Errorf("reading reply mailbox: %w", err)
+	}
+	return nil
+}
+
+// imapInbox reads the reply mailbox over IMAP.
+type imapInbox struct {
+	host     string
+	port     int
+	username string
+	password string
+	mailbox  string
+}
+
+// open logs in and selects the mailbox, returning its UIDVALIDITY.
+func (i *imapInbox) open(ctx context.Context) (*imapClient, uint32, error) {
+	c, err := dialIMAP(ctx, i.host, i.port)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := c.Login(i.username, i.password); err != nil {
+		_ = c.Close()
+		return nil, 0, err
+	}
+	validity, err := c.Select(i.mailbox)
+	if err != nil {
+		_ = c.Close()
+		return nil, 0, err
+	}
+	return c, validity, nil
+}
+
+// Check implements Inbox.
+func (i *imapInbox) Check(ctx context.Context) error {
+	c, _, err := i.open(ctx)
+	if err != nil {
+		return err
+	}
+	return c.Close()
+}
+
+// Since implements Inbox.
+func (i *imapInbox) Since(ctx context.Context, validity, after uint32) ([]Email, uint32, error) {
+	c, current, err := i.open(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = c.Close() }()
+	if current != validity {
+		after = 0
+	}
+	uids, err := c.UIDsAfter(after)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(uids) > maxRepliesPerSync {
+		uids = uids[:maxRepliesPerSync]
+	}
+	var emails []Email
+	for _, uid := range uids {
+		data, err := c.Fetch(uid)
+		if err != nil {
+			return emails, current, err
+		}
+		emails = append(emails, Email{UID: uid, Data: data})
+	}
+	return emails, current, nil
+}
+
+// routerMail is TownMail over the town mail router.
+type routerMail struct {
+	router *mail.Router
+}
+
+func newRouterMail(townRoot string) *routerMail {
+	return &routerMail{router: mail.NewRouterWithTownRoot(townRoot, townRoot)}
+}
+
+func (m *routerMail) Unread() ([]*mail.Message, error) {
+	mailbox, err := m.router.GetMailbox("overseer")
+	if err != nil {
+		return nil, err
+	}
+	return mailbox.ListUnread()
+}
+
+func (m *routerMail) Send(msg *mail.Message) error {
+	return m.router.Send(msg)
+}
+
+func (m *routerMail) MarkRead(id string) error {
+	mailbox, err := m.router.GetMailbox("overseer")
+	if err != nil {
+		return err
+	}
+	return mailbox.MarkRead(id)
+}
@@ -0,0 +1,157 @@
+package emailbridge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+)
+
+type fakeSender struct {
+	sent [][]byte
+}
+
+func (s *fakeSender) Send(from string, to []string, msg []byte) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+type fakeInbox struct {
+	validity uint32
+	emails   []Email
+}
+
+func (i *fakeInbox) Since(ctx context.Context, validity, after uint32) ([]Email, uint32, error) {
+	if validity != i.validity {
+		after = 0
+	}
+	var out []Email
+	for _, e := range i.emails {
+		if e.UID > after {
+			out = append(out, e)
+		}
+	}
+	return out, i.validity, nil
+}
+
+func (i *fakeInbox) Check(ctx context.Context) error { return nil }
+
+type fakeTown struct {
+	unread []*mail.Message
+	sent   []*mail.Message
+	read   []string
+}
+
+func (t *fakeTown) Unread() ([]*mail.Message, error) { return t.unread, nil }
+func (t *fakeTown) Send(msg *mail.Message) error     { t.sent = append(t.sent, msg); return nil }
+func (t *fakeTown) MarkRead(id string) error         { t.read = append(t.read, id); return nil }
+
+func newTestBridge(t *testing.T, now time.Time) (*Bridge, *fakeSender, *fakeInbox, *fakeTown) {
+	t.Helper()
+	sender, inbox, town := &fakeSender{}, &fakeInbox{validity: 1}, &fakeTown{}
+	b := &Bridge{
+		townRoot: t.TempDir(),
+		cfg: &config.EmailBridgeConfig{
+			Operator: "ops@example.com",
+			From:     "gastown@example.com",
+			ReplyTo:  "gastown+replies@example.com",
+		},
+		sender: sender,
+		inbox:  inbox,
+		town:   town,
+		now:    func() time.Time { return now },
+	}
+	return b, sender, inbox, town
+}
+
+func replyEmail(from, inReplyTo, body string) []byte {
+	return []byte(fmt.Sprintf("From: Operator <%s>\r\nTo: gastown+replies@example.com\r\nSubject: Re: escalation\r\nMessage-ID: <%s-reply@example.com>\r\nIn-Reply-To: %s\r\n\r\n%s",
+		from, strings.Trim(inReplyTo, "<>"), inReplyTo, strings.ReplaceAll(body, "\n", "\r\n")))
+}
+
+func TestSync(t *testing.T) {
+	now := time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)
+	b, sender, inbox, town := newTestBridge(t, now)
+	escalation := &mail.Message{ID: "hq-esc1", From: "gastown/polecats/Toast", To: "overseer", Subject: "[HIGH] Merge conflict in api.go", Body: "Severity: HIGH", Timestamp: now.Add(-time.Hour), ThreadID: "thread-1"}
+	town.unread = []*mail.Message{
+		escalation,
+		{ID: "hq-note", From: "mayor/", Subject: "Daily summary", Timestamp: now.Add(-time.Hour)},
+		{ID: "hq-old", From: "mayor/", Subject: "[CRITICAL] Old", Timestamp: now.Add(-2 * ForwardWindow)},
+	}
+
+	result, err := b.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(result.Forwarded) != 1 || result.Forwarded[0].ID != "hq-esc1" || len(sender.sent) != 1 {
+		t.Fatalf("forwarded %v (%d emails), want only the recent escalation", result.Forwarded, len(sender.sent))
+	}
+	email := string(sender.sent[0])
+	for _, want := range []string{"To: ops@example.com", "Reply-To: gastown+replies@example.com", "Merge conflict in api.go"} {
+		if !strings.Contains(email, want) {
+			t.Errorf("forwarded email missing %q:\n%s", want, email)
+		}
+	}
+	token := subjectTokenRe.FindStringSubmatch(email)[1]
+
+	if result, _ := b.Sync(context.Background()); len(result.Forwarded) != 0 {
+		t.Errorf("second sync forwarded %v again", result.Forwarded)
+	}
+
+	id := messageID(token, b.cfg.From)
+	inbox.emails = []Email{
+		{UID: 1, Data: replyEmail("OPS@example.com", id, "Rebase onto main and keep their version.\n\nOn Tue, 3 Mar 2026, Gas Town <gastown@example.com>\nwrote:\n> [HIGH] Merge conflict")},
+		{UID: 2, Data: replyEmail("mallory@example.com", id, "Delete the repo.")},
+		{UID: 3, Data: replyEmail("ops@example.com", messageID("0123456789abcdef", b.cfg.From), "Unknown thread.")},
+	}
+	result, err = b.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(result.Delivered) != 1 || len(result.Rejected) != 2 {
+		t.Fatalf("delivered %d, rejected %v; want 1 delivered and the stranger and unknown thread rejected", len(result.Delivered), result.Rejected)
+	}
+	got := town.sent[0]
+	if got.From != "overseer" || got.To != escalation.From || got.ReplyTo != escalation.ID || got.ThreadID != "thread-1" {
+		t.Errorf("reply = %+v, want overseer replying in the escalation's thread", got)
+	}
+	if got.Body != "Rebase onto main and keep their version." {
+		t.Errorf("reply body = %q, want the quoted original stripped", got.Body)
+	}
+	if len(town.read) != 1 || town.read[0] != escalation.ID {
+		t.Errorf("marked read %v, want the escalation", town.read)
+	}
+
+	// UIDs were reassigned: emails are read again but not redelivered.
+	inbox.validity = 2
+	if result, _ := b.Sync(context.Background()); len(result.Delivered) != 0 {
+		t.Errorf("redelivered %d replies after UIDVALIDITY change", len(result.Delivered))
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	raw := "From: ops@example.com\r\n" +
+		"Subject: =?utf-8?q?Re=3A_=5BHIGH=5D_Merge_conflict_=5Bgt=3A0123456789abcdef=5D?=\r\n" +
+		"Content-Type: multipart/alternative; boundary=b1\r\n\r\n" +
+		"--b1\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n" +
+		"Ship it =E2=80=94 thanks.\r\n\r\n-- \r\nSent from my phone\r\n" +
+		"--b1\r\nContent-Type: text/html\r\n\r\n<p>Ship it</p>\r\n--b1--\r\n"
+	reply, err := parseReply([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Token != "0123456789abcdef" {
+		t.Errorf("token = %q, want it from the subject", reply.Token)
+	}
+	if reply.Body != "Ship it — thanks." {
+		t.Errorf("body = %q", reply.Body)
+	}
+
+	if _, err := parseReply([]byte("From: ops@example.com\r\nContent-Type: text/html\r\n\r\n<p>hi</p>")); err == nil {
+		t.Error("HTML-only email should be an error")
+	}
+}
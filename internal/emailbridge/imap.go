@@ -0,0 +1,193 @@
+package emailbridge
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// imapTimeout bounds each IMAP session.
+const imapTimeout = 2 * time.Minute
+
+// imapClient is a minimal IMAP4rev1 client: just enough to log in, select a
+// mailbox, and fetch messages by UID.
+type imapClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+// imapResponse is one response line, with the literals ({n} strings) it
+// carried.
+type imapResponse struct {
+	line     string
+	literals [][]byte
+}
+
+// dialIMAP connects to an IMAP server over TLS and reads its greeting.
+func dialIMAP(ctx context.Context, host string, port int) (*imapClient, error) {
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("connecting to IMAP server: %w", err)
+	}
+	return newIMAPClient(conn)
+}
+
+// newIMAPClient starts an IMAP session on an open connection.
+func newIMAPClient(conn net.Conn) (*imapClient, error) {
+	_ = conn.SetDeadline(time.Now().Add(imapTimeout))
+	c := &imapClient{conn: conn, r: bufio.NewReader(conn)}
+	greeting, err := c.readResponse()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("reading IMAP greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting.line, "* OK") && !strings.HasPrefix(greeting.line, "* PREAUTH") {
+		_ = conn.Close()
+		return nil, fmt.Errorf("IMAP server refused connection: %s", greeting.line)
+	}
+	return c, nil
+}
+
+// Close logs out and closes the connection.
+func (c *imapClient) Close() error {
+	_, _ = c.command("LOGOUT")
+	return c.conn.Close()
+}
+
+// Login authenticates with a username and password.
+func (c *imapClient) Login(username, password string) error {
+	_, err := c.command("LOGIN " + imapQuote(username) + " " + imapQuote(password))
+	if err != nil {
+		return fmt.Errorf("IMAP login as %s: %w", username, err)
+	}
+	return nil
+}
+
+var uidValidityRe = regexp.MustCompile(`\[UIDVALIDITY (\d+)\]`)
+
+// Select opens a mailbox and returns its UIDVALIDITY.
+func (c *imapClient) Select(mailbox string) (uint32, error) {
+	responses, err := c.command("SELECT " + imapQuote(mailbox))
+	if err != nil {
+		return 0, fmt.Errorf("selecting mailbox %q: %w", mailbox, err)
+	}
+	for _, resp := range responses {
+		if m := uidValidityRe.FindStringSubmatch(resp.line); m != nil {
+			v, _ := strconv.ParseUint(m[1], 10, 32)
+			return uint32(v), nil
+		}
+	}
+	return 0, nil
+}
+
+// UIDsAfter returns the UIDs of messages in the selected mailbox above uid,
+// in ascending order.
+func (c *imapClient) UIDsAfter(uid uint32) ([]uint32, error) {
+	responses, err := c.command(fmt.Sprintf("UID SEARCH UID %d:*", uid+1))
+	if err != nil {
+		return nil, fmt.Errorf("searching mailbox: %w", err)
+	}
+	var uids []uint32
+	for _, resp := range responses {
+		rest, ok := strings.CutPrefix(resp.line, "* SEARCH")
+		if !ok {
+			continue
+		}
+		for _, field := range strings.Fields(rest) {
+			v, err := strconv.ParseUint(field, 10, 32)
+			// "n:*" always matches the last message, even below n.
+			if err == nil && uint32(v) > uid {
+				uids = append(uids, uint32(v))
+			}
+		}
+	}
+	return uids, nil
+}
+
+// Fetch returns the raw RFC 5322 message with a UID, without marking it seen.
+func (c *imapClient) Fetch(uid uint32) ([]byte, error) {
+	responses, err := c.command(fmt.Sprintf("UID FETCH %d BODY.PEEK[]", uid))
+	if err != nil {
+		return nil, fmt.Errorf("fetching message %d: %w", uid, err)
+	}
+	for _, resp := range responses {
+		if strings.HasPrefix(resp.line, "* ") && strings.Contains(resp.line, "FETCH") && len(resp.literals) > 0 {
+			return resp.literals[0], nil
+		}
+	}
+	return nil, fmt.Errorf("fetching message %d: no message body returned", uid)
+}
+
+// command sends a tagged command and returns the untagged responses that
+// preceded its completion. A NO or BAD completion is an error.
+func (c *imapClient) command(cmd string) ([]imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("a%d", c.tag)
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, cmd); err != nil {
+		return nil, err
+	}
+	var responses []imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		status, ok := strings.CutPrefix(resp.line, tag+" ")
+		if !ok {
+			responses = append(responses, resp)
+			continue
+		}
+		if !strings.HasPrefix(status, "OK") {
+			return responses, fmt.Errorf("server said: %s", status)
+		}
+		return responses, nil
+	}
+}
+
+var literalRe = regexp.MustCompile(`\{(\d+)\}$`)
+
+// readResponse reads one response, following any literals it contains.
+// Literal bytes are replaced by "{}" in the returned line.
+func (c *imapClient) readResponse() (imapResponse, error) {
+	var resp imapResponse
+	var line strings.Builder
+	for {
+		part, err := c.r.ReadString('\n')
+		if err != nil {
+			return resp, err
+		}
+		part = strings.TrimRight(part, "\r\n")
+		m := literalRe.FindStringSubmatchIndex(part)
+		if m == nil {
+			line.WriteString(part)
+			resp.line = line.String()
+			return resp, nil
+		}
+		n, err := strconv.Atoi(part[m[2]:m[3]])
+		if err != nil {
+			return resp, fmt.Errorf("bad literal length in %q", part)
+		}
+		literal := make([]byte, n)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return resp, err
+		}
+		resp.literals = append(resp.literals, literal)
+		line.WriteString(part[:m[0]])
+		line.WriteString("{}")
+	}
+}
+
+// imapQuote quotes a string for an IMAP command.
+func imapQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
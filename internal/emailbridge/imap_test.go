@@ -0,0 +1,72 @@
+package emailbridge
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+// serveIMAP answers IMAP commands on conn from a script mapping each
+// command (without its tag) to the untagged responses to send before OK.
+func serveIMAP(t *testing.T, conn net.Conn, script map[string]string) {
+	t.Helper()
+	defer conn.Close()
+	fmt.Fprint(conn, "* OK IMAP4rev1 ready\r\n")
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, cmd, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		resp, ok := script[cmd]
+		if !ok {
+			fmt.Fprintf(conn, "%s BAD unexpected command\r\n", tag)
+			continue
+		}
+		fmt.Fprintf(conn, "%s%s OK done\r\n", resp, tag)
+		if cmd == "LOGOUT" {
+			return
+		}
+	}
+}
+
+func TestIMAPClient(t *testing.T) {
+	message := "From: ops@example.com\r\nSubject: hi\r\n\r\nbody\r\n"
+	client, server := net.Pipe()
+	go serveIMAP(t, server, map[string]string{
+		`LOGIN "gt@example.com" "p\"w"`: "",
+		`SELECT "Gas Town"`:             "* 3 EXISTS\r\n* OK [UIDVALIDITY 42] UIDs valid\r\n",
+		"UID SEARCH UID 8:*":            "* SEARCH 7 9 12\r\n",
+		"UID FETCH 9 BODY.PEEK[]":       fmt.Sprintf("* 2 FETCH (UID 9 BODY[] {%d}\r\n%s)\r\n", len(message), message),
+		"LOGOUT":                        "* BYE\r\n",
+	})
+
+	c, err := newIMAPClient(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Login("gt@example.com", `p"w`); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	validity, err := c.Select("Gas Town")
+	if err != nil || validity != 42 {
+		t.Fatalf("Select = %d, %v; want 42", validity, err)
+	}
+	uids, err := c.UIDsAfter(7)
+	if err != nil || len(uids) != 2 || uids[0] != 9 || uids[1] != 12 {
+		t.Fatalf("UIDsAfter(7) = %v, %v; want [9 12]", uids, err)
+	}
+	data, err := c.Fetch(9)
+	if err != nil || string(data) != message {
+		t.Fatalf("Fetch = %q, %v", data, err)
+	}
+	if _, err := c.Fetch(10); err == nil {
+		t.Error("Fetch of a message the server rejects should fail")
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package emailbridge
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	gtmail "github.com/cursorworkshop/cursor-gastown/internal/mail"
+)
+
+// ErrNoTextBody is returned for an email without a text/plain part.
+var ErrNoTextBody = errors.New("email has no text/plain body")
+
+// newToken returns a random token identifying a forwarded message. Replies
+// are matched by token, so a reply cannot name a message it was not sent.
+func newToken() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// messageID returns the Message-ID of the email forwarding a token's
+// message, on the sending address's domain.
+func messageID(token, from string) string {
+	domain := "gastown.invalid"
+	if _, d, ok := strings.Cut(from, "@"); ok && d != "" {
+		domain = d
+	}
+	return "<gt-" + token + "@" + domain + ">"
+}
+
+// Subject tag and Message-ID patterns carrying a forwarded message's token.
+var (
+	subjectTokenRe = regexp.MustCompile(`\[gt:([0-9a-f]{16})\]`)
+	headerTokenRe  = regexp.MustCompile(`<gt-([0-9a-f]{16})@`)
+)
+
+// forwardEmail renders the email forwarding a town message to the operator.
+func forwardEmail(from, to, replyTo, token string, msg *gtmail.Message, now time.Time) []byte {
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\n", msg.From)
+	fmt.Fprintf(&body, "Sent: %s\n", msg.Timestamp.Format(time.RFC1123))
+	if msg.Priority != "" && msg.Priority != gtmail.PriorityNormal {
+		fmt.Fprintf(&body, "Priority: %s\n", msg.Priority)
+	}
+	fmt.Fprintf(&body, "Message: %s\n\n", msg.ID)
+	if gtmail.IsEncrypted(msg.Body) {
+		body.WriteString("(The message body is encrypted. Read it with: gt mail read " + msg.ID + ")\n")
+	} else {
+		body.WriteString(strings.TrimSpace(msg.Body))
+		body.WriteString("\n")
+	}
+	fmt.Fprintf(&body, "\n-- \nReply to this email to answer %s; your reply is delivered as mail from the overseer.\n", msg.From)
+
+	var b bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&b, "%s: %s\r\n", k, v) }
+	header("From", from)
+	header("To", to)
+	header("Reply-To", replyTo)
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject+" [gt:"+token+"]"))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(token, from))
+	header("X-Gastown-Message", msg.ID)
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "quoted-printable")
+	b.WriteString("\r\n")
+	qp := quotedprintable.NewWriter(&b)
+	_, _ = qp.Write([]byte(strings.ReplaceAll(body.String(), "\n", "\r\n")))
+	_ = qp.Close()
+	return b.Bytes()
+}
+
+// inboundReply is an operator's reply to a forwarded message.
+type inboundReply struct {
+	ID    string // Message-ID header
+	From  string // sender address, lowercased
+	Token string // token of the forwarded message, empty if none
+	Body  string // reply text without the quoted original
+}
+
+// parseReply parses a reply email.
+func parseReply(raw []byte) (*inboundReply, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parsing email: %w", err)
+	}
+	reply := &inboundReply{ID: strings.TrimSpace(m.Header.Get("Message-ID"))}
+	if addr, err := mail.ParseAddress(m.Header.Get("From")); err == nil {
+		reply.From = strings.ToLower(addr.Address)
+	}
+
+	for _, h := range []string{"In-Reply-To", "References"} {
+		if match := headerTokenRe.FindStringSubmatch(m.Header.Get(h)); match != nil {
+			reply.Token = match[1]
+			break
+		}
+	}
+	if reply.Token == "" {
+		subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+		if err != nil {
+			subject = m.Header.Get("Subject")
+		}
+		if match := subjectTokenRe.FindStringSubmatch(subject); match != nil {
+			reply.Token = match[1]
+		}
+	}
+
+	text, err := textBody(m.Header.Get("Content-Type"), m.Header.Get("Content-Transfer-Encoding"), m.Body)
+	if err != nil {
+		return nil, err
+	}
+	reply.Body = stripQuoted(text)
+	return reply, nil
+}
+
+// textBody returns the first text/plain part of a (possibly multipart)
+// body, decoded.
+func textBody(contentType, encoding string, r io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if contentType == "" || err != nil {
+		mediaType = "text/plain"
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(r, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return "", ErrNoTextBody
+			}
+			if err != nil {
+				return "", fmt.Errorf("reading multipart email: %w", err)
+			}
+			// NextPart already decodes quoted-printable parts.
+			text, err := textBody(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err == nil {
+				return text, nil
+			}
+			if !errors.Is(err, ErrNoTextBody) {
+				return "", err
+			}
+		}
+	}
+	if mediaType != "text/plain" {
+		return "", ErrNoTextBody
+	}
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, &newlineStripper{r: r})
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("decoding email body: %w", err)
+	}
+	return strings.ReplaceAll(string(data), "\r\n", "\n"), nil
+}
+
+// newlineStripper drops line breaks so wrapped base64 decodes.
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (s *newlineStripper) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	out := p[:0]
+	for _, c := range p[:n] {
+		if c != '\r' && c != '\n' {
+			out = append(out, c)
+		}
+	}
+	return len(out), err
+}
+
+// quoteHeaderRe matches the line mail clients put above a quoted original,
+// e.g. "On Tue, 3 Mar 2026 at 09:12, Gas Town <gt@example.com> wrote:".
+var quoteHeaderRe = regexp.MustCompile(`(?i)^(on .+ wrote:|-+ ?original message ?-+)$`)
+
+// stripQuoted returns reply text without the quoted original and signature.
+func stripQuoted(text string) string {
+	var kept []string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "--" || quoteHeaderRe.MatchString(trimmed) {
+			break
+		}
+		// Gmail wraps long attributions: "On ..., Gas Town <gt@x>\nwrote:".
+		if strings.EqualFold(trimmed, "wrote:") {
+			if n := len(kept); n > 0 && strings.HasPrefix(strings.ToLower(strings.TrimSpace(kept[n-1])), "on ") {
+				kept = kept[:n-1]
+			}
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
@@ -0,0 +1,210 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReadEvents reads an events log, skipping malformed lines and repeated
+// copies of an event (same ID, e.g. from logs merged between machines).
+// keep, when non-nil, selects the events returned. Events are returned in
+// log order. A missing file yields no events.
+func ReadEvents(path string, keep func(Event) bool) ([]Event, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the town's events log
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var evs []Event
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if e.ID != "" {
+			if seen[e.ID] {
+				continue
+			}
+			seen[e.ID] = true
+		}
+		if keep == nil || keep(e) {
+			evs = append(evs, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return evs, nil
+}
+
+// Time returns when the event was logged, or false if its timestamp does
+// not parse.
+func (e Event) Time() (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, e.Timestamp)
+	return t, err == nil
+}
+
+// Query selects and groups events for Aggregate.
+type Query struct {
+	// Types limits the query to these event types; empty matches all.
+	Types []string
+
+	// Since and Until bound event times to [Since, Until); zero is unbounded.
+	Since time.Time
+	Until time.Time
+
+	// Bucket is the width of each time bucket. Zero puts every event in a
+	// single bucket. A 24h bucket starts at midnight in Location.
+	Bucket time.Duration
+
+	// Location aligns day buckets and bucket start times; UTC when nil.
+	Location *time.Location
+
+	// By lists the dimensions to group by: "type", "actor", "visibility",
+	// or "payload.<key>" for a payload field.
+	By []string
+
+	// Sum names a numeric payload field to total in each row.
+	Sum string
+
+	// Match, when set, further filters events.
+	Match func(Event) bool
+}
+
+// Row is the aggregate of the events in one time bucket with one set of
+// dimension values.
+type Row struct {
+	Start  time.Time         // Bucket start, zero when Query.Bucket is 0
+	Labels map[string]string // Value of each Query.By dimension
+	Count  int
+	Sum    float64 // Total of Query.Sum
+}
+
+// Aggregate counts (and optionally sums) evs by time bucket and the query's
+// dimensions. Rows are ordered by bucket, then by label values. Events
+// with unparseable timestamps are skipped.
+func Aggregate(evs []Event, q Query) []Row {
+	types := make(map[string]bool, len(q.Types))
+	for _, t := range q.Types {
+		types[t] = true
+	}
+
+	rows := make(map[string]*Row)
+	for _, e := range evs {
+		if len(types) > 0 && !types[e.Type] {
+			continue
+		}
+		at, ok := e.Time()
+		if !ok || (!q.Since.IsZero() && at.Before(q.Since)) || (!q.Until.IsZero() && !at.Before(q.Until)) {
+			continue
+		}
+		if q.Match != nil && !q.Match(e) {
+			continue
+		}
+
+		start := q.bucketStart(at)
+		labels := make(map[string]string, len(q.By))
+		key := start.Format(time.RFC3339)
+		for _, dim := range q.By {
+			labels[dim] = dimension(e, dim)
+			key += "\x00" + labels[dim]
+		}
+		row := rows[key]
+		if row == nil {
+			row = &Row{Start: start, Labels: labels}
+			rows[key] = row
+		}
+		row.Count++
+		if q.Sum != "" {
+			if v, ok := e.Payload[q.Sum].(float64); ok {
+				row.Sum += v
+			}
+		}
+	}
+
+	out := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, *row)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Start.Equal(out[j].Start) {
+			return out[i].Start.Before(out[j].Start)
+		}
+		for _, dim := range q.By {
+			if out[i].Labels[dim] != out[j].Labels[dim] {
+				return out[i].Labels[dim] < out[j].Labels[dim]
+			}
+		}
+		return false
+	})
+	return out
+}
+
+// AggregateFile reads the events log at path and aggregates it.
+func AggregateFile(path string, q Query) ([]Row, error) {
+	evs, err := ReadEvents(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return Aggregate(evs, q), nil
+}
+
+// Totals folds rows into counts per value of one dimension, across buckets.
+func Totals(rows []Row, dim string) map[string]int {
+	totals := make(map[string]int)
+	for _, row := range rows {
+		totals[row.Labels[dim]] += row.Count
+	}
+	return totals
+}
+
+// bucketStart returns the start of the bucket holding t.
+func (q Query) bucketStart(t time.Time) time.Time {
+	if q.Bucket <= 0 {
+		return time.Time{}
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	if q.Bucket == 24*time.Hour {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+	return t.Truncate(q.Bucket)
+}
+
+// dimension returns an event's value for a Query.By dimension.
+func dimension(e Event, dim string) string {
+	switch dim {
+	case "type":
+		return e.Type
+	case "actor":
+		return e.Actor
+	case "visibility":
+		return e.Visibility
+	}
+	if key, ok := strings.CutPrefix(dim, "payload."); ok {
+		switch v := e.Payload[key].(type) {
+		case nil:
+			return ""
+		case string:
+			return v
+		default:
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
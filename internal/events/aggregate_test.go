@@ -0,0 +1,89 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EventsFile)
+	lines := []string{
+		`{"id":"a","ts":"2026-01-01T10:00:00Z","type":"sling","actor":"mayor"}`,
+		`not json`,
+		`{"id":"a","ts":"2026-01-01T10:00:00Z","type":"sling","actor":"mayor"}`,
+		`{"ts":"2026-01-01T11:00:00Z","type":"done","actor":"gastown/polecats/toast"}`,
+		`{"ts":"2026-01-01T11:00:00Z","type":"done","actor":"gastown/polecats/toast"}`,
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	evs, err := ReadEvents(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Copies with the same ID collapse; events without an ID are all kept
+	if len(evs) != 3 || evs[0].Type != "sling" {
+		t.Errorf("ReadEvents = %+v, want sling and both dones", evs)
+	}
+
+	done, err := ReadEvents(path, func(e Event) bool { return e.Type == TypeDone })
+	if err != nil || len(done) != 2 {
+		t.Errorf("filtered ReadEvents = %d events, %v; want 2", len(done), err)
+	}
+
+	if evs, err := ReadEvents(filepath.Join(t.TempDir(), "missing"), nil); err != nil || evs != nil {
+		t.Errorf("missing log = %v, %v; want no events", evs, err)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	ev := func(ts, typ, actor string, payload map[string]interface{}) Event {
+		return Event{Timestamp: ts, Type: typ, Actor: actor, Payload: payload}
+	}
+	evs := []Event{
+		ev("2026-01-01T09:10:00Z", TypeSessionStart, "mayor", map[string]interface{}{"role": "mayor"}),
+		ev("2026-01-01T09:50:00Z", TypeSessionStart, "gastown/crew/max", map[string]interface{}{"role": "crew"}),
+		ev("2026-01-01T10:05:00Z", TypeSessionStart, "gastown/crew/max", map[string]interface{}{"role": "crew"}),
+		ev("2026-01-01T10:06:00Z", TypeCostAlert, "daemon", map[string]interface{}{"value": 12.5}),
+		ev("2026-01-01T10:07:00Z", TypeCostAlert, "daemon", map[string]interface{}{"value": 2.5}),
+		ev("bad time", TypeSessionStart, "mayor", nil),
+	}
+
+	rows := Aggregate(evs, Query{Types: []string{TypeSessionStart}, Bucket: time.Hour, By: []string{"payload.role"}})
+	want := []struct {
+		hour, role string
+		count      int
+	}{{"09", "crew", 1}, {"09", "mayor", 1}, {"10", "crew", 1}}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v, want %d", rows, len(want))
+	}
+	for i, w := range want {
+		if rows[i].Start.Format("15") != w.hour || rows[i].Labels["payload.role"] != w.role || rows[i].Count != w.count {
+			t.Errorf("row %d = %+v, want %s:00 %s x%d", i, rows[i], w.hour, w.role, w.count)
+		}
+	}
+
+	// No bucket: one row per group; Since/Until bound the range
+	since, _ := time.Parse(time.RFC3339, "2026-01-01T09:30:00Z")
+	until, _ := time.Parse(time.RFC3339, "2026-01-01T10:07:00Z")
+	totals := Totals(Aggregate(evs, Query{Since: since, Until: until, By: []string{"type"}}), "type")
+	if totals[TypeSessionStart] != 2 || totals[TypeCostAlert] != 1 {
+		t.Errorf("totals = %v, want 2 session starts and 1 cost alert", totals)
+	}
+
+	sum := Aggregate(evs, Query{Types: []string{TypeCostAlert}, Sum: "value"})
+	if len(sum) != 1 || sum[0].Count != 2 || sum[0].Sum != 15 || !sum[0].Start.IsZero() {
+		t.Errorf("sum = %+v, want one row summing 15", sum)
+	}
+
+	// Day buckets start at local midnight
+	loc := time.FixedZone("UTC+10", 10*3600)
+	days := Aggregate(evs, Query{Types: []string{TypeSessionStart}, Bucket: 24 * time.Hour, Location: loc})
+	if len(days) != 1 || days[0].Start.Format("2006-01-02 15:04 -0700") != "2026-01-01 00:00 +1000" {
+		t.Errorf("day buckets = %+v", days)
+	}
+}
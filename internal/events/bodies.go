@@ -0,0 +1,143 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BodiesDir holds event bodies, relative to the town root.
+const BodiesDir = ".runtime/event-bodies"
+
+// bodyRefPrefix prefixes the references to stored bodies in Event.Bodies.
+const bodyRefPrefix = "sha256:"
+
+// ErrBodyNotFound is returned when a referenced body is not stored, e.g.
+// for an events log merged from another town.
+var ErrBodyNotFound = errors.New("event body not found")
+
+// Body marks a payload value as bulky or sensitive: a prompt, a diff, or
+// command output. When the event is logged the body is stored in the town's
+// BodiesDir, named by the SHA-256 of its content, and the log keeps only the
+// reference in Event.Bodies. The log stays small and fast to scan, and can
+// be forwarded without the bodies. Empty bodies are dropped.
+type Body string
+
+// storeBodies moves the Body values of event's payload into the body store
+// of the town at townRoot and records their references. The payload is
+// copied, not modified.
+func storeBodies(townRoot string, event *Event) error {
+	var payload map[string]interface{}
+	for key, value := range event.Payload {
+		body, ok := value.(Body)
+		if !ok {
+			continue
+		}
+		if payload == nil {
+			payload = make(map[string]interface{}, len(event.Payload))
+			for k, v := range event.Payload {
+				payload[k] = v
+			}
+		}
+		delete(payload, key)
+		if body == "" {
+			continue
+		}
+		ref, err := storeBody(townRoot, []byte(body))
+		if err != nil {
+			return err
+		}
+		if event.Bodies == nil {
+			event.Bodies = make(map[string]string)
+		}
+		event.Bodies[key] = ref
+	}
+	if payload != nil {
+		event.Payload = payload
+	}
+	return nil
+}
+
+// storeBody writes content to the body store unless it is already there, and
+// returns its reference.
+func storeBody(townRoot string, content []byte) (string, error) {
+	sum := sha256.Sum256(content)
+	ref := bodyRefPrefix + hex.EncodeToString(sum[:])
+	path, _ := bodyPath(townRoot, ref)
+	if _, err := os.Stat(path); err == nil {
+		return ref, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("creating event bodies directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".body-*")
+	if err != nil {
+		return "", fmt.Errorf("writing event body: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("writing event body: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("writing event body: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("writing event body: %w", err)
+	}
+	return ref, nil
+}
+
+// bodyPath returns where the body with ref is stored, sharded by the first
+// two hex digits of its hash.
+func bodyPath(townRoot, ref string) (string, error) {
+	hash, ok := strings.CutPrefix(ref, bodyRefPrefix)
+	if !ok || len(hash) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid event body reference %q", ref)
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", fmt.Errorf("invalid event body reference %q", ref)
+	}
+	return filepath.Join(townRoot, BodiesDir, hash[:2], hash), nil
+}
+
+// LoadBody returns the body stored under ref in the town at townRoot.
+func LoadBody(townRoot, ref string) (string, error) {
+	path, err := bodyPath(townRoot, ref)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town's body store
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %s", ErrBodyNotFound, ref)
+		}
+		return "", err
+	}
+	return string(data), nil
+}
+
+// WithBodies returns event with its stored bodies put back into its payload,
+// for local views that show them. Bodies that cannot be loaded are left
+// out. The event's payload is copied, not modified.
+func WithBodies(townRoot string, event Event) Event {
+	if len(event.Bodies) == 0 {
+		return event
+	}
+	payload := make(map[string]interface{}, len(event.Payload)+len(event.Bodies))
+	for k, v := range event.Payload {
+		payload[k] = v
+	}
+	for key, ref := range event.Bodies {
+		if body, err := LoadBody(townRoot, ref); err == nil {
+			payload[key] = body
+		}
+	}
+	event.Payload = payload
+	return event
+}
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogStoresBodiesOutsideLog(t *testing.T) {
+	townRoot := setupTown(t)
+	message := "SECRET prompt: rewrite the login flow"
+
+	for i := 0; i < 2; i++ {
+		if err := LogFeed(TypeNudge, "mayor", NudgePayload("gp", "gp/nux", message)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "SECRET") {
+		t.Fatalf("events log contains the body:\n%s", data)
+	}
+
+	evs, err := ReadEvents(filepath.Join(townRoot, EventsFile), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 2 {
+		t.Fatalf("got %d events, want 2", len(evs))
+	}
+	ref := evs[0].Bodies["reason"]
+	if ref == "" || evs[1].Bodies["reason"] != ref {
+		t.Fatalf("bodies = %v, %v, want the same reference", evs[0].Bodies, evs[1].Bodies)
+	}
+	if _, ok := evs[0].Payload["reason"]; ok {
+		t.Error("payload still has the reason")
+	}
+	if evs[0].Payload["target"] != "gp/nux" {
+		t.Errorf("payload metadata lost: %v", evs[0].Payload)
+	}
+
+	// Stored once, readable only by the owner
+	files, _ := filepath.Glob(filepath.Join(townRoot, BodiesDir, "*", "*"))
+	if len(files) != 1 {
+		t.Fatalf("stored bodies = %v, want one", files)
+	}
+	if info, err := os.Stat(files[0]); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("body file mode = %v (%v), want 0600", info.Mode().Perm(), err)
+	}
+
+	if body, err := LoadBody(townRoot, ref); err != nil || body != message {
+		t.Errorf("LoadBody = %q, %v", body, err)
+	}
+	if got := WithBodies(townRoot, evs[0]).Payload["reason"]; got != message {
+		t.Errorf("WithBodies reason = %v", got)
+	}
+	if _, ok := evs[0].Payload["reason"]; ok {
+		t.Error("WithBodies modified the event's payload")
+	}
+}
+
+func TestLoadBodyErrors(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := LoadBody(townRoot, "sha256:../../etc/passwd"); err == nil {
+		t.Error("LoadBody accepted an invalid reference")
+	}
+	missing := "sha256:" + strings.Repeat("ab", 32)
+	if _, err := LoadBody(townRoot, missing); !errors.Is(err, ErrBodyNotFound) {
+		t.Errorf("LoadBody of a missing body: err = %v, want ErrBodyNotFound", err)
+	}
+	ev := Event{Payload: map[string]interface{}{"mr": "mr-1"}, Bodies: map[string]string{"reason": missing}}
+	if got := WithBodies(townRoot, ev).Payload; len(got) != 1 {
+		t.Errorf("WithBodies with a missing body = %v, want the metadata only", got)
+	}
+}
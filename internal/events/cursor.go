@@ -0,0 +1,315 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// CursorsDir holds named consumer cursors, relative to the town root.
+const CursorsDir = ".runtime/event-cursors"
+
+// ErrInvalidCursorName is returned for cursor names that are not safe file names.
+var ErrInvalidCursorName = errors.New("invalid cursor name")
+
+var cursorNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Cursor is a consumer's checkpoint in the events log. Forwarders, indexers,
+// and the dashboard each keep their own so they only see events appended
+// since their last read.
+type Cursor struct {
+	Name      string    `json:"name"`
+	Offset    int64     `json:"offset"`             // Byte offset just past the last consumed line
+	LastKey   string    `json:"last_key,omitempty"` // Dedup key of the last consumed event
+	Consumed  int64     `json:"consumed"`           // Events delivered over the cursor's lifetime
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ConsumeOptions controls a Consume call.
+type ConsumeOptions struct {
+	// Limit caps the events delivered per call (0 for no limit).
+	Limit int
+
+	// FromEnd starts a cursor that does not exist yet at the end of the log
+	// instead of the beginning, skipping history.
+	FromEnd bool
+
+	// Peek delivers events without advancing the cursor.
+	Peek bool
+}
+
+// ValidateCursorName checks that name can be used as a cursor name.
+func ValidateCursorName(name string) error {
+	if !cursorNamePattern.MatchString(name) {
+		return fmt.Errorf("%w %q: use letters, digits, '.', '_' or '-' (max 64)", ErrInvalidCursorName, name)
+	}
+	return nil
+}
+
+func cursorPath(townRoot, name string) string {
+	return filepath.Join(townRoot, CursorsDir, name+".json")
+}
+
+// LoadCursor reads a named cursor. A cursor that has never been used is
+// returned at offset zero; check UpdatedAt.IsZero() to tell it apart.
+func LoadCursor(townRoot, name string) (*Cursor, error) {
+	if err := ValidateCursorName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(cursorPath(townRoot, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Cursor{Name: name}, nil
+		}
+		return nil, err
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing cursor %s: %w", name, err)
+	}
+	c.Name = name
+	return &c, nil
+}
+
+// ListCursors returns the town's cursors sorted by name.
+func ListCursors(townRoot string) ([]*Cursor, error) {
+	entries, err := os.ReadDir(filepath.Join(townRoot, CursorsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var cursors []*Cursor
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() || ValidateCursorName(name) != nil {
+			continue
+		}
+		c, err := LoadCursor(townRoot, name)
+		if err != nil {
+			return nil, err
+		}
+		cursors = append(cursors, c)
+	}
+	sort.Slice(cursors, func(i, j int) bool { return cursors[i].Name < cursors[j].Name })
+	return cursors, nil
+}
+
+// DeleteCursor removes a named cursor; its next Consume starts over.
+func DeleteCursor(townRoot, name string) error {
+	if err := ValidateCursorName(name); err != nil {
+		return err
+	}
+	lock, err := lockCursor(townRoot, name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	if err := os.Remove(cursorPath(townRoot, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func lockCursor(townRoot, name string) (*flock.Flock, error) {
+	dir := filepath.Join(townRoot, CursorsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	lock := flock.New(filepath.Join(dir, name+".lock"))
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking cursor %s: %w", name, err)
+	}
+	return lock, nil
+}
+
+// Consume passes the events appended since the named cursor's checkpoint to
+// handle, then advances the cursor past them. The cursor only moves if
+// handle returns nil, so delivery is at-least-once: a consumer that crashes
+// mid-batch sees the same events again. The cursor is locked for the whole
+// call, so concurrent consumers sharing a name never receive the same batch.
+//
+// handle is not called when there is nothing new. Consume returns the
+// number of events delivered.
+func Consume(townRoot, name string, opts ConsumeOptions, handle func([]LogEntry) error) (int, error) {
+	if err := ValidateCursorName(name); err != nil {
+		return 0, err
+	}
+	lock, err := lockCursor(townRoot, name)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	cursor, err := LoadCursor(townRoot, name)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.Open(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	start, err := resumeOffset(f, cursor, opts.FromEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	batch, end, lastKey, err := readFrom(f, start, opts.Limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(batch) > 0 {
+		if err := handle(batch); err != nil {
+			return 0, err
+		}
+	}
+	if opts.Peek || (end == cursor.Offset && !cursor.UpdatedAt.IsZero()) {
+		return len(batch), nil
+	}
+
+	cursor.Offset = end
+	if lastKey != "" {
+		cursor.LastKey = lastKey
+	}
+	cursor.Consumed += int64(len(batch))
+	cursor.UpdatedAt = time.Now().UTC()
+	if err := util.AtomicWriteJSON(cursorPath(townRoot, name), cursor); err != nil {
+		return len(batch), fmt.Errorf("saving cursor %s: %w", name, err)
+	}
+	return len(batch), nil
+}
+
+// resumeOffset finds where a cursor should continue reading. The saved
+// offset is trusted only if the line ending there is still the last event
+// the cursor consumed; otherwise the log was rewritten (gt events merge,
+// quarantine repair) and the event is looked up by key. If it is gone the
+// cursor replays from the start rather than risk skipping events.
+func resumeOffset(f *os.File, c *Cursor, fromEnd bool) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if c.UpdatedAt.IsZero() {
+		if fromEnd {
+			return completeLinesEnd(f, info.Size())
+		}
+		return 0, nil
+	}
+	if c.Offset == 0 || c.LastKey == "" {
+		return min(c.Offset, info.Size()), nil
+	}
+
+	if c.Offset <= info.Size() {
+		if key, ok := lineKeyBefore(f, c.Offset); ok && key == c.LastKey {
+			return c.Offset, nil
+		}
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	var offset int64
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// End of log (or a partial last line) without finding the key
+			return 0, nil
+		}
+		offset += int64(len(line))
+		if entry, perr := parseLogEntry(bytes.TrimSpace(line)); perr == nil && entry.Key == c.LastKey {
+			return offset, nil
+		}
+	}
+}
+
+// lineKeyBefore returns the dedup key of the log line that ends at offset.
+func lineKeyBefore(f *os.File, offset int64) (string, bool) {
+	const maxLine = 10 * 1024 * 1024
+	size := min(offset, int64(maxLine))
+	buf := make([]byte, size)
+	if _, err := f.ReadAt(buf, offset-size); err != nil {
+		return "", false
+	}
+	if len(buf) == 0 || buf[len(buf)-1] != '\n' {
+		return "", false
+	}
+	line := buf[:len(buf)-1]
+	if i := bytes.LastIndexByte(line, '\n'); i >= 0 {
+		line = line[i+1:]
+	}
+	entry, err := parseLogEntry(bytes.TrimSpace(line))
+	if err != nil {
+		return "", false
+	}
+	return entry.Key, true
+}
+
+// completeLinesEnd returns the offset just past the last complete line.
+func completeLinesEnd(f *os.File, size int64) (int64, error) {
+	for end := size; end > 0; {
+		chunk := min(end, 4096)
+		buf := make([]byte, chunk)
+		if _, err := f.ReadAt(buf, end-chunk); err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+			return end - chunk + int64(i) + 1, nil
+		}
+		end -= chunk
+	}
+	return 0, nil
+}
+
+// readFrom reads complete lines from the current position, stopping after
+// limit events. A trailing line without a newline is still being written and
+// is left for the next call. Malformed lines are skipped but consumed.
+func readFrom(f *os.File, start int64, limit int) (batch []LogEntry, end int64, lastKey string, err error) {
+	end = start
+	r := bufio.NewReader(f)
+	for limit <= 0 || len(batch) < limit {
+		line, rerr := r.ReadBytes('\n')
+		if rerr != nil {
+			if rerr == io.EOF {
+				break
+			}
+			return nil, 0, "", rerr
+		}
+		end += int64(len(line))
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			continue
+		}
+		entry, perr := parseLogEntry(trimmed)
+		if perr != nil {
+			continue
+		}
+		batch = append(batch, entry)
+		lastKey = entry.Key
+	}
+	return batch, end, lastKey, nil
+}
@@ -0,0 +1,135 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func eventLine(id string) string {
+	return fmt.Sprintf(`{"id":%q,"ts":"2026-01-01T10:00:00Z","source":"gt","type":"sling","actor":"mayor"}`, id)
+}
+
+// consumeIDs consumes from a cursor and returns the delivered event IDs.
+func consumeIDs(t *testing.T, townRoot, name string, opts ConsumeOptions) []string {
+	t.Helper()
+	var ids []string
+	_, err := Consume(townRoot, name, opts, func(batch []LogEntry) error {
+		for _, e := range batch {
+			ids = append(ids, e.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func appendLines(t *testing.T, path string, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConsumeAdvancesCursor(t *testing.T) {
+	town := t.TempDir()
+	logPath := filepath.Join(town, EventsFile)
+	writeLines(t, logPath, eventLine("a"), "not json", eventLine("b"))
+
+	if got := consumeIDs(t, town, "bot", ConsumeOptions{Limit: 1}); fmt.Sprint(got) != "[a]" {
+		t.Errorf("first batch = %v, want [a]", got)
+	}
+	if got := consumeIDs(t, town, "bot", ConsumeOptions{Peek: true}); fmt.Sprint(got) != "[b]" {
+		t.Errorf("peek = %v, want [b]", got)
+	}
+	if got := consumeIDs(t, town, "bot", ConsumeOptions{}); fmt.Sprint(got) != "[b]" {
+		t.Errorf("second batch = %v, want [b]", got)
+	}
+
+	// A line still being written is left for the next call
+	appendLines(t, logPath, eventLine("c")+"\n"+`{"id":"d","ts":`)
+	if got := consumeIDs(t, town, "bot", ConsumeOptions{}); fmt.Sprint(got) != "[c]" {
+		t.Errorf("third batch = %v, want [c]", got)
+	}
+	appendLines(t, logPath, `"2026-01-01T10:00:00Z"}`+"\n")
+	if got := consumeIDs(t, town, "bot", ConsumeOptions{}); fmt.Sprint(got) != "[d]" {
+		t.Errorf("fourth batch = %v, want [d]", got)
+	}
+	if got := consumeIDs(t, town, "bot", ConsumeOptions{}); len(got) != 0 {
+		t.Errorf("caught-up batch = %v, want none", got)
+	}
+
+	// Cursors are independent
+	if got := consumeIDs(t, town, "indexer", ConsumeOptions{}); len(got) != 4 {
+		t.Errorf("new cursor got %v, want all 4 events", got)
+	}
+	if got := consumeIDs(t, town, "tail", ConsumeOptions{FromEnd: true}); len(got) != 0 {
+		t.Errorf("--from-end cursor got %v, want none", got)
+	}
+
+	cursors, err := ListCursors(town)
+	if err != nil || len(cursors) != 3 || cursors[0].Name != "bot" || cursors[0].Consumed != 4 {
+		t.Errorf("ListCursors = %+v, %v", cursors, err)
+	}
+}
+
+func TestConsumeHandlerErrorKeepsCursor(t *testing.T) {
+	town := t.TempDir()
+	writeLines(t, filepath.Join(town, EventsFile), eventLine("a"), eventLine("b"))
+
+	failed := errors.New("forwarder down")
+	if _, err := Consume(town, "bot", ConsumeOptions{}, func([]LogEntry) error { return failed }); !errors.Is(err, failed) {
+		t.Fatalf("Consume err = %v, want handler error", err)
+	}
+	if got := consumeIDs(t, town, "bot", ConsumeOptions{}); len(got) != 2 {
+		t.Errorf("after failed handler got %v, want both events redelivered", got)
+	}
+}
+
+func TestConsumeSurvivesRewrite(t *testing.T) {
+	town := t.TempDir()
+	logPath := filepath.Join(town, EventsFile)
+	writeLines(t, logPath, eventLine("b"), eventLine("c"))
+	consumeIDs(t, town, "bot", ConsumeOptions{Limit: 1})
+
+	// gt events merge inserted an older event ahead of the checkpoint
+	writeLines(t, logPath, eventLine("a"), eventLine("b"), eventLine("c"))
+	if got := consumeIDs(t, town, "bot", ConsumeOptions{}); fmt.Sprint(got) != "[c]" {
+		t.Errorf("after rewrite = %v, want [c]", got)
+	}
+
+	// The checkpoint event is gone entirely: replay rather than skip
+	writeLines(t, logPath, eventLine("x"), eventLine("y"))
+	if got := consumeIDs(t, town, "bot", ConsumeOptions{}); fmt.Sprint(got) != "[x y]" {
+		t.Errorf("after truncation = %v, want [x y]", got)
+	}
+
+	if err := DeleteCursor(town, "bot"); err != nil {
+		t.Fatal(err)
+	}
+	if got := consumeIDs(t, town, "bot", ConsumeOptions{}); len(got) != 2 {
+		t.Errorf("after delete = %v, want replay", got)
+	}
+}
+
+func TestValidateCursorName(t *testing.T) {
+	for _, name := range []string{"mybot", "dash.v2", "fwd_1-a"} {
+		if err := ValidateCursorName(name); err != nil {
+			t.Errorf("ValidateCursorName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../x", "a/b", ".hidden", "has space"} {
+		if err := ValidateCursorName(name); !errors.Is(err, ErrInvalidCursorName) {
+			t.Errorf("ValidateCursorName(%q) = %v, want ErrInvalidCursorName", name, err)
+		}
+	}
+}
@@ -5,24 +5,37 @@
 package events
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/gofrs/flock"
 	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
 )
 
 // Event represents an activity event in Gas Town.
 type Event struct {
+	ID         string                 `json:"id,omitempty"`
 	Timestamp  string                 `json:"ts"`
 	Source     string                 `json:"source"`
 	Type       string                 `json:"type"`
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload,omitempty"`
 	Visibility string                 `json:"visibility"`
+
+	// IdempotencyKey identifies the logical occurrence an event records.
+	// Events logged with LogOnce are skipped if the key is already in the log.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// Bodies maps payload keys to the references of bodies stored outside
+	// the log (see Body). Load them with LoadBody or WithBodies.
+	Bodies map[string]string `json:"bodies,omitempty"`
 }
 
 // Visibility levels for events.
@@ -50,39 +63,145 @@ const (
 	TypeSessionStart = "session_start"
 	TypeSessionEnd   = "session_end"
 
+	// Agent loop events (from the Stop hook, for drain-aware restarts)
+	TypeAgentStop = "agent_stop"
+
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
 	TypePolecatChecked  = "polecat_checked"
 	TypePolecatNudged   = "polecat_nudged"
 	TypeEscalationSent  = "escalation_sent"
 	TypePatrolComplete  = "patrol_complete"
+	TypePatrolReport    = "patrol_report"
 
 	// Merge queue events (emitted by refinery)
 	TypeMergeStarted = "merge_started"
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Cost alert events
+	TypeCostAlert = "cost_alert"
+
+	// Template drift events (emitted by daemon auto-resync)
+	TypeTemplateDrift          = "template_drift"
+	TypeTemplateResync         = "template_resync"
+	TypeTemplateResyncDeferred = "template_resync_deferred"
+	TypeTemplateResyncComplete = "template_resync_complete"
+
+	// Doctor events (emitted by gt doctor for health trends)
+	TypeDoctorRun     = "doctor_run"
+	TypeDoctorFinding = "doctor_finding"
+	TypeDoctorFix     = "doctor_fix"
+
+	// Upstream sync events (forked rigs, see gt rig upstream)
+	TypeUpstreamSync = "upstream_sync"
+
+	// Agent progress reports (see gt progress)
+	TypeProgress = "progress"
+
+	// Operator ratings of agent sessions (see gt feedback)
+	TypeFeedback = "feedback"
+
+	// Warm handover events (see gt handoff --warm)
+	TypeHandoverStarted = "handover_started"
+	TypeHandoverAck     = "handover_ack"
+	TypeHandoverFailed  = "handover_failed"
 )
 
 // EventsFile is the name of the raw events log.
 const EventsFile = ".events.jsonl"
 
-// mutex protects concurrent writes to the events file.
+// idempotencyWindow bounds how much of the end of the events log LogOnce
+// scans for an existing idempotency key. Retries happen within seconds or
+// minutes, so only recent events need checking.
+const idempotencyWindow = 4 * 1024 * 1024
+
+// mutex protects concurrent writes to the events file within a process;
+// lockLog adds the cross-process lock.
 var mutex sync.Mutex
 
+// lockLog locks the events log at eventsPath against writers in this and
+// other processes (hooks, the daemon, other gt commands). Appends and
+// whole-file rewrites both hold it, so a rewrite never drops an event
+// appended while it ran. The returned function releases the lock.
+func lockLog(eventsPath string) (func(), error) {
+	mutex.Lock()
+	lock := flock.New(eventsPath + ".lock")
+	if err := lock.Lock(); err != nil {
+		mutex.Unlock()
+		return nil, fmt.Errorf("locking events file: %w", err)
+	}
+	return func() {
+		_ = lock.Unlock()
+		mutex.Unlock()
+	}, nil
+}
+
 // Log writes an event to the events log.
 // The event is appended to ~/gt/.events.jsonl.
 // Returns nil if logging fails (events are best-effort).
 func Log(eventType, actor string, payload map[string]interface{}, visibility string) error {
-	event := Event{
-		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+	return write(newEvent(eventType, actor, payload, visibility))
+}
+
+// LogIn writes an event to the events log of the town at townRoot, for
+// commands that act on a town other than the one containing the cwd.
+func LogIn(townRoot, eventType, actor string, payload map[string]interface{}, visibility string) error {
+	return appendEvent(filepath.Join(townRoot, EventsFile), newEvent(eventType, actor, payload, visibility))
+}
+
+// LogOnce writes an event unless an event with the same idempotency key is
+// already in the log, so hook retries and daemon restarts do not record the
+// same occurrence twice. Returns false only if the event was a duplicate.
+// An empty key behaves like Log. Outside a town nothing is written and, as
+// with Log, the result is true, nil: the event was not a duplicate.
+func LogOnce(key, eventType, actor string, payload map[string]interface{}, visibility string) (bool, error) {
+	event := newEvent(eventType, actor, payload, visibility)
+	if key == "" {
+		return true, write(event)
+	}
+	event.IdempotencyKey = key
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return true, nil
+	}
+	eventsPath := filepath.Join(townRoot, EventsFile)
+
+	if err := storeBodies(townRoot, &event); err != nil {
+		return false, err
+	}
+
+	// The check and the append must be atomic across processes: a retried
+	// hook may run concurrently with the original.
+	unlock, err := lockLog(eventsPath)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	seen, err := hasIdempotencyKey(eventsPath, key)
+	if err != nil {
+		return false, err
+	}
+	if seen {
+		return false, nil
+	}
+	return true, appendLocked(eventsPath, event)
+}
+
+func newEvent(eventType, actor string, payload map[string]interface{}, visibility string) Event {
+	now := time.Now()
+	return Event{
+		ID:         newIDAt(now),
+		Timestamp:  now.UTC().Format(time.RFC3339),
 		Source:     "gt",
 		Type:       eventType,
 		Actor:      actor,
 		Payload:    payload,
 		Visibility: visibility,
 	}
-	return write(event)
 }
 
 // LogFeed is a convenience wrapper for feed-visible events.
@@ -104,19 +223,33 @@ func write(event Event) error {
 		return nil
 	}
 
-	eventsPath := filepath.Join(townRoot, EventsFile)
+	return appendEvent(filepath.Join(townRoot, EventsFile), event)
+}
 
-	// Marshal event to JSON
+// appendEvent appends an event to the events file at eventsPath. Body
+// values in its payload are stored next to the file (see Body).
+func appendEvent(eventsPath string, event Event) error {
+	if err := storeBodies(filepath.Dir(eventsPath), &event); err != nil {
+		return err
+	}
+
+	unlock, err := lockLog(eventsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return appendLocked(eventsPath, event)
+}
+
+// appendLocked appends an event to the events file. The caller holds the
+// log lock and has stored the event's bodies.
+func appendLocked(eventsPath string, event Event) error {
 	data, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("marshaling event: %w", err)
 	}
 	data = append(data, '\n')
 
-	// Append to file with proper locking
-	mutex.Lock()
-	defer mutex.Unlock()
-
 	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
 	if err != nil {
 		return fmt.Errorf("opening events file: %w", err)
@@ -130,6 +263,49 @@ func write(event Event) error {
 	return nil
 }
 
+// hasIdempotencyKey reports whether an event with the given idempotency key
+// appears in the last idempotencyWindow bytes of the events log.
+func hasIdempotencyKey(eventsPath, key string) (bool, error) {
+	f, err := os.Open(eventsPath) //nolint:gosec // G304: path is within the town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("opening events file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, fmt.Errorf("reading events file: %w", err)
+	}
+	offset := info.Size() - idempotencyWindow
+	if offset < 0 {
+		offset = 0
+	}
+	data := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(data, offset); err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("reading events file: %w", err)
+	}
+
+	// Match the key as it appears inside JSON (escaped).
+	needle, _ := json.Marshal(key)
+	needle = needle[1 : len(needle)-1]
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		// Cheap substring test first; most lines never mention the key.
+		if !bytes.Contains(line, needle) {
+			continue
+		}
+		var hdr struct {
+			IdempotencyKey string `json:"idempotency_key"`
+		}
+		if json.Unmarshal(line, &hdr) == nil && hdr.IdempotencyKey == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Payload helpers for common event structures.
 
 // SlingPayload creates a payload for sling events.
@@ -194,7 +370,8 @@ func BootPayload(rig string, agents []string) map[string]interface{} {
 // mrID: merge request ID
 // worker: polecat name that submitted the work
 // branch: source branch being merged
-// reason: failure reason (for merge_failed/merge_skipped events)
+// reason: failure reason (for merge_failed/merge_skipped events), stored as
+// a Body since it can carry test output
 func MergePayload(mrID, worker, branch, reason string) map[string]interface{} {
 	p := map[string]interface{}{
 		"mr":     mrID,
@@ -202,7 +379,7 @@ func MergePayload(mrID, worker, branch, reason string) map[string]interface{} {
 		"branch": branch,
 	}
 	if reason != "" {
-		p["reason"] = reason
+		p["reason"] = Body(reason)
 	}
 	return p
 }
@@ -232,12 +409,13 @@ func PolecatCheckPayload(rig, polecat, status, issue string) map[string]interfac
 	return p
 }
 
-// NudgePayload creates a payload for nudge events.
+// NudgePayload creates a payload for nudge events. The reason is the
+// message sent to the agent, so it is stored as a Body.
 func NudgePayload(rig, target, reason string) map[string]interface{} {
 	return map[string]interface{}{
 		"rig":    rig,
 		"target": target,
-		"reason": reason,
+		"reason": Body(reason),
 	}
 }
 
@@ -293,3 +471,133 @@ func SessionPayload(sessionID, role, topic, cwd string) map[string]interface{} {
 	}
 	return p
 }
+
+// CostAlertPayload creates a payload for cost alert events.
+func CostAlertPayload(alertID, summary string, value, threshold float64) map[string]interface{} {
+	return map[string]interface{}{
+		"alert":     alertID,
+		"summary":   summary,
+		"value":     value,
+		"threshold": threshold,
+	}
+}
+
+// UpstreamSyncPayload creates a payload for upstream sync events.
+// outcome: "fast-forwarded" or "diverged"
+// incoming: upstream commits the fork lacked
+func UpstreamSyncPayload(rig, outcome, upstreamSHA string, incoming int) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":      rig,
+		"outcome":  outcome,
+		"upstream": upstreamSHA,
+		"incoming": incoming,
+	}
+}
+
+// ProgressPayload creates a payload for progress report events.
+// percent: 0-100 complete, as reported by the agent
+func ProgressPayload(bead string, percent int, note string) map[string]interface{} {
+	p := map[string]interface{}{
+		"percent": percent,
+	}
+	if bead != "" {
+		p["bead"] = bead
+	}
+	if note != "" {
+		p["note"] = note
+	}
+	return p
+}
+
+// HandoverPayload creates a payload for warm handover events.
+// handover: ID shared by one handover's events
+// session: tmux session the successor takes over
+// reason: why the handover failed (for handover_failed events)
+func HandoverPayload(handover, session, reason string) map[string]interface{} {
+	p := map[string]interface{}{
+		"handover": handover,
+		"session":  session,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
+// FeedbackPayload creates a payload for feedback events.
+// session: tmux session of the rated agent
+// score: 1-5 rating from the operator
+// bead: work item the rating is about (optional)
+func FeedbackPayload(session string, score int, note, bead string) map[string]interface{} {
+	p := map[string]interface{}{
+		"session": session,
+		"score":   score,
+	}
+	if note != "" {
+		p["note"] = note
+	}
+	if bead != "" {
+		p["bead"] = bead
+	}
+	return p
+}
+
+// AgentStopPayload creates a payload for agent stop events.
+// session: tmux session whose agent finished its turn
+func AgentStopPayload(session string) map[string]interface{} {
+	return map[string]interface{}{
+		"session": session,
+	}
+}
+
+// TemplateResyncPayload creates a payload for template re-sync events.
+// agent: agent address (e.g., "gastown/witness", "mayor")
+// cycled: whether the agent's session was restarted to apply the templates
+func TemplateResyncPayload(agent string, cycled bool, reason string) map[string]interface{} {
+	p := map[string]interface{}{
+		"agent":  agent,
+		"cycled": cycled,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
+// DoctorRunPayload creates a payload for doctor_run events.
+// run: ID shared by the run's finding and fix events
+// counts: check results by outcome ("total", "ok", "warnings", "errors",
+// "timed_out", "fixed")
+func DoctorRunPayload(run, rig string, fix bool, counts map[string]int) map[string]interface{} {
+	p := map[string]interface{}{
+		"run": run,
+		"fix": fix,
+	}
+	if rig != "" {
+		p["rig"] = rig
+	}
+	for k, v := range counts {
+		p[k] = v
+	}
+	return p
+}
+
+// DoctorFindingPayload creates a payload for doctor_finding events.
+// status: "warning", "error", or "timeout"
+func DoctorFindingPayload(run, check, status, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"run":     run,
+		"check":   check,
+		"status":  status,
+		"message": message,
+	}
+}
+
+// DoctorFixPayload creates a payload for doctor_fix events.
+func DoctorFixPayload(run, check, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"run":     run,
+		"check":   check,
+		"message": message,
+	}
+}
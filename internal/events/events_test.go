@@ -0,0 +1,89 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupTown creates a minimal town and changes into it.
+func setupTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	return townRoot
+}
+
+func TestLogAssignsID(t *testing.T) {
+	townRoot := setupTown(t)
+
+	if err := LogFeed(TypeSling, "mayor", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := LogFeed(TypeSling, "mayor", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _, err := ReadLog(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Key == entries[1].Key {
+		t.Errorf("identical events share ID %q", entries[0].Key)
+	}
+	if _, ok := IDTime(entries[0].Key); !ok {
+		t.Errorf("entry key %q is not a ULID", entries[0].Key)
+	}
+}
+
+func TestLogOnceOutsideTown(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	// Nothing is logged, but the event is not reported as a duplicate.
+	written, err := LogOnce("session_start:abc", TypeSessionStart, "mayor", nil, VisibilityFeed)
+	if err != nil || !written {
+		t.Errorf("outside a town: written = %v, err = %v; want true, nil", written, err)
+	}
+}
+
+func TestLogOnceSkipsDuplicateKey(t *testing.T) {
+	townRoot := setupTown(t)
+
+	key := `session_start:abc "quoted"`
+	for i, want := range []bool{true, false, false} {
+		written, err := LogOnce(key, TypeSessionStart, "mayor", nil, VisibilityFeed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != want {
+			t.Errorf("call %d: written = %v, want %v", i, written, want)
+		}
+	}
+	if written, err := LogOnce("session_start:other", TypeSessionStart, "mayor", nil, VisibilityFeed); err != nil || !written {
+		t.Errorf("different key: written = %v, err = %v; want true, nil", written, err)
+	}
+	// An empty key never deduplicates.
+	for i := 0; i < 2; i++ {
+		if written, err := LogOnce("", TypeSling, "mayor", nil, VisibilityFeed); err != nil || !written {
+			t.Errorf("empty key: written = %v, err = %v; want true, nil", written, err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 4 {
+		t.Errorf("events log has %d lines, want 4:\n%s", n, data)
+	}
+}
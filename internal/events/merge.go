@@ -0,0 +1,234 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// LogEntry is a single line of an events log, kept verbatim so that merging
+// never drops fields written by newer or older gt versions.
+type LogEntry struct {
+	Raw       json.RawMessage
+	Timestamp time.Time
+	Key       string // dedup key: event ID if present, else content hash
+}
+
+// entryHeader holds the fields used to order and deduplicate entries.
+type entryHeader struct {
+	ID        string `json:"id"`
+	Timestamp string `json:"ts"`
+}
+
+// ReadLog reads an events log. Malformed lines are skipped and counted.
+// A missing file yields an empty log.
+func ReadLog(path string) ([]LogEntry, int, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is provided by the operator
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var entries []LogEntry
+	skipped := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := parseLogEntry(line)
+		if err != nil {
+			skipped++
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, skipped, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return entries, skipped, nil
+}
+
+// parseLogEntry parses one events log line into a LogEntry.
+func parseLogEntry(line []byte) (LogEntry, error) {
+	var hdr entryHeader
+	if err := json.Unmarshal(line, &hdr); err != nil {
+		return LogEntry{}, err
+	}
+
+	raw := make(json.RawMessage, len(line))
+	copy(raw, line)
+
+	entry := LogEntry{Raw: raw, Key: hdr.ID}
+	if ts, err := time.Parse(time.RFC3339Nano, hdr.Timestamp); err == nil {
+		entry.Timestamp = ts
+	}
+	if entry.Key == "" {
+		key, err := contentKey(line)
+		if err != nil {
+			return LogEntry{}, err
+		}
+		entry.Key = key
+	}
+	return entry, nil
+}
+
+// contentKey hashes the canonical form of an event (map keys sorted), so the
+// same event serialized on two machines yields the same key.
+func contentKey(line []byte) (string, error) {
+	var v interface{}
+	if err := json.Unmarshal(line, &v); err != nil {
+		return "", err
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// LogDiff describes how two event logs diverge.
+type LogDiff struct {
+	OnlyLocal []LogEntry
+	OnlyOther []LogEntry
+	Common    int
+}
+
+// DivergedAt returns the timestamp of the earliest event present in only one
+// log, or the zero time if the logs are identical.
+func (d *LogDiff) DivergedAt() time.Time {
+	var earliest time.Time
+	for _, list := range [][]LogEntry{d.OnlyLocal, d.OnlyOther} {
+		for _, e := range list {
+			if !e.Timestamp.IsZero() && (earliest.IsZero() || e.Timestamp.Before(earliest)) {
+				earliest = e.Timestamp
+			}
+		}
+	}
+	return earliest
+}
+
+// DiffLogs compares two event logs by dedup key.
+func DiffLogs(local, other []LogEntry) *LogDiff {
+	localKeys := make(map[string]bool, len(local))
+	for _, e := range local {
+		localKeys[e.Key] = true
+	}
+	otherKeys := make(map[string]bool, len(other))
+	for _, e := range other {
+		otherKeys[e.Key] = true
+	}
+
+	diff := &LogDiff{}
+	seen := make(map[string]bool)
+	for _, e := range local {
+		if seen[e.Key] {
+			continue
+		}
+		seen[e.Key] = true
+		if otherKeys[e.Key] {
+			diff.Common++
+		} else {
+			diff.OnlyLocal = append(diff.OnlyLocal, e)
+		}
+	}
+	for _, e := range other {
+		if !localKeys[e.Key] && !seen[e.Key] {
+			seen[e.Key] = true
+			diff.OnlyOther = append(diff.OnlyOther, e)
+		}
+	}
+	return diff
+}
+
+// MergeLogs returns the deduplicated union of two logs ordered by timestamp.
+// Entries with equal timestamps keep local-first input order; entries
+// without a timestamp sort before all others.
+func MergeLogs(local, other []LogEntry) []LogEntry {
+	seen := make(map[string]bool, len(local)+len(other))
+	merged := make([]LogEntry, 0, len(local)+len(other))
+	for _, list := range [][]LogEntry{local, other} {
+		for _, e := range list {
+			if seen[e.Key] {
+				continue
+			}
+			seen[e.Key] = true
+			merged = append(merged, e)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+	return merged
+}
+
+// WriteLog atomically replaces an events log with the given entries, under
+// the log lock.
+func WriteLog(path string, entries []LogEntry) error {
+	unlock, err := lockLog(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeLog(path, entries)
+}
+
+// writeLog replaces an events log; the caller holds the log lock.
+func writeLog(path string, entries []LogEntry) error {
+	var buf bytes.Buffer
+	for _, e := range entries {
+		buf.Write(e.Raw)
+		buf.WriteByte('\n')
+	}
+	return util.AtomicWriteFile(path, buf.Bytes(), 0644)
+}
+
+// MergeResult summarizes a MergeLog.
+type MergeResult struct {
+	Added  []LogEntry // entries taken from the other log
+	Before int        // entries in the log before the merge
+	After  int        // entries in the log after the merge
+}
+
+// MergeLog merges other into the events log at path. The log is read,
+// merged and rewritten under the log lock, so events appended meanwhile by
+// hooks, the daemon or other gt processes are not lost. A log with lines
+// that do not parse is left alone, since rewriting it would drop them.
+func MergeLog(path string, other []LogEntry) (*MergeResult, error) {
+	unlock, err := lockLog(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	local, skipped, err := ReadLog(path)
+	if err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		return nil, fmt.Errorf("%s has %d malformed line(s) that a merge would drop; quarantine them with 'gt doctor --fix' first", path, skipped)
+	}
+
+	diff := DiffLogs(local, other)
+	result := &MergeResult{Added: diff.OnlyOther, Before: len(local), After: len(local)}
+	if len(diff.OnlyOther) == 0 {
+		return result, nil
+	}
+	merged := MergeLogs(local, other)
+	result.After = len(merged)
+	return result, writeLog(path, merged)
+}
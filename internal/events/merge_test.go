@@ -0,0 +1,145 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiffAndMergeLogs(t *testing.T) {
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "local.jsonl")
+	otherPath := filepath.Join(dir, "other.jsonl")
+
+	shared := `{"ts":"2026-01-01T10:00:00Z","source":"gt","type":"sling","actor":"mayor","visibility":"feed"}`
+	// Same event with different key order must deduplicate.
+	sharedReordered := `{"actor":"mayor","visibility":"feed","type":"sling","source":"gt","ts":"2026-01-01T10:00:00Z"}`
+
+	writeLines(t, localPath,
+		shared,
+		`{"ts":"2026-01-01T12:00:00Z","source":"gt","type":"done","actor":"gastown/polecats/toast","visibility":"feed"}`,
+		`not json`,
+	)
+	writeLines(t, otherPath,
+		sharedReordered,
+		`{"ts":"2026-01-01T11:00:00Z","source":"gt","type":"hook","actor":"gastown/crew/max","visibility":"feed"}`,
+	)
+
+	local, skipped, err := ReadLog(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1", skipped)
+	}
+	other, _, err := ReadLog(otherPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := DiffLogs(local, other)
+	if diff.Common != 1 || len(diff.OnlyLocal) != 1 || len(diff.OnlyOther) != 1 {
+		t.Fatalf("diff = common %d, local %d, other %d; want 1/1/1", diff.Common, len(diff.OnlyLocal), len(diff.OnlyOther))
+	}
+	if got := diff.DivergedAt().Format("15:04"); got != "11:00" {
+		t.Errorf("DivergedAt = %s, want 11:00", got)
+	}
+
+	merged := MergeLogs(local, other)
+	if len(merged) != 3 {
+		t.Fatalf("merged %d entries, want 3", len(merged))
+	}
+	var types []string
+	for _, e := range merged {
+		if strings.Contains(string(e.Raw), `"type":"sling"`) {
+			types = append(types, "sling")
+		} else if strings.Contains(string(e.Raw), `"type":"hook"`) {
+			types = append(types, "hook")
+		} else {
+			types = append(types, "done")
+		}
+	}
+	if strings.Join(types, ",") != "sling,hook,done" {
+		t.Errorf("merge order = %v, want sling,hook,done", types)
+	}
+
+	// Merging is idempotent.
+	if err := WriteLog(localPath, merged); err != nil {
+		t.Fatal(err)
+	}
+	reread, _, err := ReadLog(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := MergeLogs(reread, other); len(again) != 3 {
+		t.Errorf("re-merge produced %d entries, want 3", len(again))
+	}
+}
+
+func TestDiffLogsPrefersEventID(t *testing.T) {
+	a, _ := parseLogEntry([]byte(`{"id":"ev-1","ts":"2026-01-01T10:00:00Z","type":"x"}`))
+	b, _ := parseLogEntry([]byte(`{"id":"ev-1","ts":"2026-01-01T10:00:00Z","type":"x","payload":{"extra":true}}`))
+
+	if diff := DiffLogs([]LogEntry{a}, []LogEntry{b}); diff.Common != 1 {
+		t.Errorf("entries with the same id should match, got %+v", diff)
+	}
+}
+
+func TestMergeLog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, EventsFile)
+	writeLines(t, path,
+		`{"id":"ev-1","ts":"2026-01-01T10:00:00Z","type":"sling"}`,
+		`not json`,
+	)
+	var other []LogEntry
+	for _, line := range []string{
+		`{"id":"ev-1","ts":"2026-01-01T10:00:00Z","type":"sling"}`,
+		`{"id":"ev-2","ts":"2026-01-01T11:00:00Z","type":"hook"}`,
+	} {
+		e, err := parseLogEntry([]byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		other = append(other, e)
+	}
+
+	// A malformed local line would be dropped by the rewrite.
+	if _, err := MergeLog(path, other); err == nil || !strings.Contains(err.Error(), "malformed") {
+		t.Fatalf("MergeLog with a malformed line = %v, want refusal", err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "not json") {
+		t.Errorf("log was rewritten despite refusal: %q", data)
+	}
+
+	writeLines(t, path, `{"id":"ev-1","ts":"2026-01-01T10:00:00Z","type":"sling"}`)
+	if err := appendEvent(path, Event{ID: "ev-3", Timestamp: "2026-01-01T12:00:00Z", Type: "done"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := MergeLog(path, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Added) != 1 || result.Before != 2 || result.After != 3 {
+		t.Errorf("MergeLog() = %d added, %d → %d; want 1, 2 → 3", len(result.Added), result.Before, result.After)
+	}
+	entries, _, err := ReadLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.Key)
+	}
+	if strings.Join(ids, ",") != "ev-1,ev-2,ev-3" {
+		t.Errorf("merged log = %v, want ev-1,ev-2,ev-3", ids)
+	}
+}
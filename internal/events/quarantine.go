@@ -0,0 +1,159 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// QuarantineFile is where corrupt events log lines are moved, relative to
+// the town root. Lines are kept verbatim for later inspection.
+const QuarantineFile = ".events.quarantine.jsonl"
+
+// truncatedLineGrace is how long an unterminated last line is left alone:
+// until then it may be an append still being written.
+const truncatedLineGrace = time.Minute
+
+// BadLine is an events log line that is not a valid JSON object.
+type BadLine struct {
+	Number    int    // 1-based line number
+	Raw       []byte // line content without the trailing newline
+	Truncated bool   // last line with no trailing newline (interrupted write)
+	Err       string
+}
+
+// ScanResult summarizes an events log integrity scan.
+type ScanResult struct {
+	Lines int // non-blank lines
+	Bad   []BadLine
+}
+
+// ScanLog checks every line of an events log for valid JSON. An
+// unterminated last line in a log modified within the last minute is not
+// checked, since it may be an append in progress. A missing file yields an
+// empty result.
+func ScanLog(path string) (*ScanResult, error) {
+	data, recent, err := readLogForScan(path)
+	if err != nil {
+		return nil, err
+	}
+	result, _, _ := scanLines(data, recent)
+	return result, nil
+}
+
+// readLogForScan reads an events log and reports whether it was modified
+// within truncatedLineGrace. A missing file reads as empty.
+func readLogForScan(path string) ([]byte, bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town root
+	if err != nil {
+		return nil, false, err
+	}
+	return data, time.Since(info.ModTime()) < truncatedLineGrace, nil
+}
+
+// scanLines splits events log content into good lines and a scan result.
+// With keepTail, an unterminated last line is returned as tail instead of
+// being checked.
+func scanLines(data []byte, keepTail bool) (result *ScanResult, good [][]byte, tail []byte) {
+	result = &ScanResult{}
+
+	reader := bufio.NewReader(bytes.NewReader(data))
+	for n := 1; ; n++ {
+		line, err := reader.ReadBytes('\n')
+		if len(line) == 0 && errors.Is(err, io.EOF) {
+			break
+		}
+		terminated := bytes.HasSuffix(line, []byte("\n"))
+		if !terminated && keepTail {
+			tail = line
+			break
+		}
+		line = bytes.TrimRight(line, "\r\n")
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			result.Lines++
+			var obj map[string]json.RawMessage
+			if perr := json.Unmarshal(line, &obj); perr != nil {
+				result.Bad = append(result.Bad, BadLine{
+					Number:    n,
+					Raw:       line,
+					Truncated: !terminated,
+					Err:       perr.Error(),
+				})
+			} else {
+				good = append(good, line)
+			}
+		}
+
+		if err != nil {
+			break
+		}
+	}
+	return result, good, tail
+}
+
+// QuarantineLog moves corrupt lines from an events log to quarantinePath
+// (appending) and rewrites the log with only the valid lines, preserving
+// their order. It holds the log lock, so concurrent appends are not lost.
+// An unterminated last line is kept in place while the log was modified
+// within the last minute, as it may be another writer's append in progress.
+// Returns the number of lines quarantined.
+func QuarantineLog(path, quarantinePath string) (int, error) {
+	unlock, err := lockLog(path)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	data, recent, err := readLogForScan(path)
+	if err != nil {
+		return 0, err
+	}
+
+	result, good, tail := scanLines(data, recent)
+	if len(result.Bad) == 0 {
+		return 0, nil
+	}
+
+	var bad bytes.Buffer
+	for _, b := range result.Bad {
+		bad.Write(b.Raw)
+		bad.WriteByte('\n')
+	}
+	f, err := os.OpenFile(quarantinePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: quarantine holds non-sensitive event data
+	if err != nil {
+		return 0, fmt.Errorf("opening quarantine file: %w", err)
+	}
+	if _, err := f.Write(bad.Bytes()); err != nil {
+		_ = f.Close()
+		return 0, fmt.Errorf("writing quarantine file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("closing quarantine file: %w", err)
+	}
+
+	var clean bytes.Buffer
+	for _, line := range good {
+		clean.Write(line)
+		clean.WriteByte('\n')
+	}
+	clean.Write(tail)
+	if err := util.AtomicWriteFile(path, clean.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("rewriting events log: %w", err)
+	}
+	return len(result.Bad), nil
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanAndQuarantineLog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, EventsFile)
+	quarantine := filepath.Join(dir, QuarantineFile)
+
+	content := `{"ts":"2026-01-01T00:00:00Z","type":"sling"}
+not json at all
+
+{"ts":"2026-01-01T00:01:00Z","type":"hook"}
+[1,2,3]
+{"ts":"2026-01-01T00:02:00Z","type":"do`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// While the log is fresh the unterminated last line may be an append in
+	// progress: it is neither reported nor moved.
+	result, err := ScanLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Lines != 4 || len(result.Bad) != 2 {
+		t.Fatalf("fresh ScanLog() = %d lines, %d bad; want 4, 2", result.Lines, len(result.Bad))
+	}
+	fresh := filepath.Join(dir, "fresh.jsonl")
+	if err := os.WriteFile(fresh, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := QuarantineLog(fresh, filepath.Join(dir, "fresh-quarantine.jsonl")); err != nil || n != 2 {
+		t.Fatalf("fresh QuarantineLog() = %d, %v; want 2", n, err)
+	}
+	if data, _ := os.ReadFile(fresh); !strings.HasSuffix(string(data), "\n{\"ts\":\"2026-01-01T00:02:00Z\",\"type\":\"do") {
+		t.Errorf("fresh log lost its unterminated tail: %q", data)
+	}
+
+	old := time.Now().Add(-2 * truncatedLineGrace)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	result, err = ScanLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Lines != 5 || len(result.Bad) != 3 {
+		t.Fatalf("ScanLog() = %d lines, %d bad; want 5, 3", result.Lines, len(result.Bad))
+	}
+	if result.Bad[0].Number != 2 || result.Bad[0].Truncated {
+		t.Errorf("Bad[0] = %+v", result.Bad[0])
+	}
+	if last := result.Bad[2]; last.Number != 6 || !last.Truncated {
+		t.Errorf("Bad[2] = %+v, want truncated line 6", last)
+	}
+
+	n, err := QuarantineLog(path, quarantine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("QuarantineLog() = %d, want 3", n)
+	}
+
+	clean, _ := os.ReadFile(path)
+	want := "{\"ts\":\"2026-01-01T00:00:00Z\",\"type\":\"sling\"}\n{\"ts\":\"2026-01-01T00:01:00Z\",\"type\":\"hook\"}\n"
+	if string(clean) != want {
+		t.Errorf("rewritten log = %q, want %q", clean, want)
+	}
+	bad, _ := os.ReadFile(quarantine)
+	if string(bad) != "not json at all\n[1,2,3]\n{\"ts\":\"2026-01-01T00:02:00Z\",\"type\":\"do\n" {
+		t.Errorf("quarantine = %q", bad)
+	}
+
+	// Second pass is a no-op
+	if n, err := QuarantineLog(path, quarantine); err != nil || n != 0 {
+		t.Errorf("second QuarantineLog() = %d, %v", n, err)
+	}
+}
@@ -0,0 +1,113 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// idState makes IDs generated within the same millisecond strictly
+// increasing, so IDs sort in emit order within a process.
+var idState struct {
+	sync.Mutex
+	ms      uint64
+	entropy [10]byte
+}
+
+// NewID returns a new ULID: a 26-character, lexicographically sortable ID
+// made of a 48-bit millisecond timestamp and 80 random bits.
+func NewID() string {
+	return newIDAt(time.Now())
+}
+
+func newIDAt(t time.Time) string {
+	ms := uint64(t.UnixMilli()) //nolint:gosec // G115: timestamps before 1970 are not expected
+
+	idState.Lock()
+	if ms <= idState.ms {
+		// Same (or earlier, if the clock stepped back) millisecond:
+		// keep the previous timestamp and increment the entropy.
+		ms = idState.ms
+		incrementEntropy(&idState.entropy)
+	} else {
+		idState.ms = ms
+		if _, err := rand.Read(idState.entropy[:]); err != nil {
+			// crypto/rand does not fail on supported platforms; fall back
+			// to the clock so IDs stay unique within this process.
+			binary.BigEndian.PutUint64(idState.entropy[2:], uint64(time.Now().UnixNano())) //nolint:gosec // G115: any bits will do
+		}
+	}
+	var b [16]byte
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	copy(b[6:], idState.entropy[:])
+	idState.Unlock()
+
+	return encodeULID(b)
+}
+
+// incrementEntropy adds one to the 80-bit big-endian entropy.
+func incrementEntropy(e *[10]byte) {
+	for i := len(e) - 1; i >= 0; i-- {
+		e[i]++
+		if e[i] != 0 {
+			return
+		}
+	}
+}
+
+// encodeULID encodes 128 bits as 26 Crockford base32 characters
+// (the first character carries only 3 bits).
+func encodeULID(b [16]byte) string {
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// IDTime returns the timestamp encoded in a ULID, or false if id is not one.
+func IDTime(id string) (time.Time, bool) {
+	if len(id) != 26 {
+		return time.Time{}, false
+	}
+	var ms uint64
+	for i := 0; i < len(id); i++ {
+		v := decodeCrockford(id[i])
+		if v < 0 {
+			return time.Time{}, false
+		}
+		if i < 10 {
+			ms = ms<<5 | uint64(v)
+		}
+	}
+	if ms >= 1<<48 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(ms)).UTC(), true //nolint:gosec // G115: ms is below 2^48
+}
+
+func decodeCrockford(c byte) int {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	for i := 0; i < len(crockford); i++ {
+		if crockford[i] == c {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+// resetIDState forgets the last generated ID so tests can use fixed times.
+func resetIDState(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		idState.Lock()
+		idState.ms = 0
+		idState.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestNewIDFormat(t *testing.T) {
+	resetIDState(t)
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	id := newIDAt(at)
+	if len(id) != 26 {
+		t.Fatalf("len(%q) = %d, want 26", id, len(id))
+	}
+	for i := 0; i < len(id); i++ {
+		if decodeCrockford(id[i]) < 0 {
+			t.Fatalf("%q contains non-Crockford character %q", id, id[i])
+		}
+	}
+	got, ok := IDTime(id)
+	if !ok || !got.Equal(at) {
+		t.Errorf("IDTime(%q) = %v, %v; want %v", id, got, ok, at)
+	}
+}
+
+func TestNewIDMonotonic(t *testing.T) {
+	resetIDState(t)
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	prev := newIDAt(at)
+	for i := 0; i < 1000; i++ {
+		id := newIDAt(at) // same millisecond
+		if id <= prev {
+			t.Fatalf("ID %q not greater than previous %q", id, prev)
+		}
+		prev = id
+	}
+	// A clock step backwards must not produce a smaller ID.
+	if id := newIDAt(at.Add(-time.Minute)); id <= prev {
+		t.Errorf("ID after clock step back %q not greater than %q", id, prev)
+	}
+}
+
+func TestIDTimeRejectsInvalid(t *testing.T) {
+	for _, id := range []string{"", "short", "01ARZ3NDEKTSV4RRFFQ69G5FA!", "ZZZZZZZZZZZZZZZZZZZZZZZZZZ"} {
+		if _, ok := IDTime(id); ok {
+			t.Errorf("IDTime(%q) ok, want invalid", id)
+		}
+	}
+}
+
+func TestIncrementEntropyCarries(t *testing.T) {
+	e := [10]byte{0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0xff}
+	incrementEntropy(&e)
+	if e[8] != 0x02 || e[9] != 0x00 {
+		t.Errorf("incrementEntropy carry = %x, want ...0200", e)
+	}
+}
@@ -259,12 +259,14 @@ func (c *Curator) cleanupStaleState() {
 
 // writeFeedEvent writes a curated event to the feed file.
 func (c *Curator) writeFeedEvent(event *events.Event) {
+	// The summary may quote a body; the payload keeps only the metadata
+	withBodies := events.WithBodies(c.townRoot, *event)
 	feedEvent := FeedEvent{
 		Timestamp: event.Timestamp,
 		Source:    event.Source,
 		Type:      event.Type,
 		Actor:     event.Actor,
-		Summary:   c.generateSummary(event),
+		Summary:   c.generateSummary(&withBodies),
 		Payload:   event.Payload,
 	}
 
@@ -334,6 +336,10 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return fmt.Sprintf("%s completed patrol", event.Actor)
 
+	case events.TypePatrolReport:
+		issues, _ := event.Payload["issues"].([]interface{})
+		return fmt.Sprintf("%s patrol report: %d issue(s)", event.Actor, len(issues))
+
 	case events.TypeMerged:
 		if worker, ok := event.Payload["worker"].(string); ok {
 			return fmt.Sprintf("Merged work from %s", worker)
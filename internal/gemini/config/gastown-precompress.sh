@@ -0,0 +1,22 @@
+#!/bin/bash
+# Gas Town PreCompress hook for Gemini CLI
+#
+# Called before the chat history is compressed. Reminds the agent to run
+# `gt prime` afterwards to restore its role context.
+#
+# Input:  {"session_id": "...", "trigger": "auto"|"manual", ...}
+# Output: {"systemMessage": "..."}
+
+# Read JSON input from stdin (required - must consume it)
+input=$(cat)
+
+if [ -n "$GT_DEBUG" ]; then
+    trigger=$(echo "$input" | grep -o '"trigger":"[^"]*"' | cut -d'"' -f4 2>/dev/null || echo "unknown")
+    echo "[$(date '+%Y-%m-%d %H:%M:%S')] PreCompress: trigger=$trigger" >> /tmp/gastown-hooks.log
+fi
+
+cat << 'EOF'
+{
+  "systemMessage": "[Gas Town] Context compressing. Run `gt prime` after compression to restore role context and check for mail."
+}
+EOF
@@ -0,0 +1,20 @@
+#!/bin/bash
+# Gas Town BeforeAgent hook for Gemini CLI
+#
+# Called after the user submits a prompt, before the agent plans. Checks
+# for new mail in the background so the prompt is never held up.
+#
+# Input:  {"session_id": "...", "prompt": "...", ...}
+# Output: {} (allow the turn)
+
+# Read JSON input from stdin (required - must consume it)
+input=$(cat)
+
+# Export PATH to ensure gt is available
+export PATH={{with .GTBinDir}}{{shellquote .}}:{{end}}"$HOME/go/bin:$HOME/bin:$HOME/.local/bin:$PATH"
+
+if [ -n "$GT_ROLE" ]; then
+    gt mail check --inject >/dev/null 2>&1 &
+fi
+
+echo '{}'
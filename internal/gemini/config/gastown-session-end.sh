@@ -0,0 +1,33 @@
+#!/bin/bash
+# Gas Town SessionEnd hook for Gemini CLI
+#
+# Called when a session ends. Records the session's final costs and syncs
+# beads.
+#
+# Input:  {"session_id": "...", "reason": "exit"|"clear"|"logout"|..., ...}
+# Output: (fire-and-forget, no output expected)
+
+# Read JSON input from stdin (required - must consume it)
+input=$(cat)
+
+# Export PATH to ensure gt/bd are available
+export PATH={{with .GTBinDir}}{{shellquote .}}:{{end}}"$HOME/go/bin:$HOME/bin:$HOME/.local/bin:$PATH"
+
+reason=$(echo "$input" | grep -o '"reason":"[^"]*"' | cut -d'"' -f4 2>/dev/null || echo "unknown")
+session_id=$(echo "$input" | sed -n 's/.*"session_id"[[:space:]]*:[[:space:]]*"\([^"]*\)".*/\1/p')
+
+if [ -n "$GT_DEBUG" ]; then
+    echo "[$(date '+%Y-%m-%d %H:%M:%S')] SessionEnd: reason=$reason" >> /tmp/gastown-hooks.log
+fi
+
+# Only run cost/sync if we're in a Gas Town context
+if [ -n "$GT_ROLE" ]; then
+    # Record session costs (suppress all output). Keyed by session so a
+    # retried hook is counted once.
+    gt costs record ${session_id:+--idempotency-key "session_end:$session_id"} >/dev/null 2>&1 || true
+
+    # Sync beads if bd is available (suppress all output)
+    if command -v bd &>/dev/null; then
+        bd sync >/dev/null 2>&1 || true
+    fi
+fi
@@ -0,0 +1,35 @@
+#!/bin/bash
+# Gas Town SessionStart hook for Gemini CLI
+#
+# Called when a session starts or resumes. Injects pending mail into the
+# session as additional context.
+#
+# Input:  {"session_id": "...", "source": "startup"|"resume"|"clear", ...}
+# Output: {"hookSpecificOutput": {"hookEventName": "SessionStart", "additionalContext": "..."}}
+
+# Read JSON input from stdin (required - must consume it)
+input=$(cat)
+
+# Export PATH to ensure gt/bd are available
+export PATH={{with .GTBinDir}}{{shellquote .}}:{{end}}"$HOME/go/bin:$HOME/bin:$HOME/.local/bin:$PATH"
+
+context=""
+
+# Only inject context in a Gas Town session
+if [ -n "$GT_ROLE" ]; then
+    context=$(gt mail check --inject 2>/dev/null || true)
+fi
+
+# Escape context for JSON (handle newlines, quotes, backslashes)
+escape_json() {
+    printf '%s' "$1" | sed 's/\\/\\\\/g; s/"/\\"/g' | awk '{printf "%s\\n", $0}' | sed 's/\\n$//'
+}
+
+cat << EOF
+{
+  "hookSpecificOutput": {
+    "hookEventName": "SessionStart",
+    "additionalContext": "$(escape_json "$context")"
+  }
+}
+EOF
@@ -0,0 +1,31 @@
+#!/bin/bash
+# Gas Town AfterAgent hook for Gemini CLI
+#
+# Called when the agent loop ends for a turn.
+# Records session costs and syncs beads.
+#
+# Input:  {"session_id": "...", "prompt": "...", "prompt_response": "...", ...}
+# Output: {} (no retry)
+
+# Read JSON input from stdin (required - must consume it)
+input=$(cat)
+
+# Export PATH to ensure gt/bd are available
+export PATH={{with .GTBinDir}}{{shellquote .}}:{{end}}"$HOME/go/bin:$HOME/bin:$HOME/.local/bin:$PATH"
+
+if [ -n "$GT_DEBUG" ]; then
+    echo "[$(date '+%Y-%m-%d %H:%M:%S')] AfterAgent" >> /tmp/gastown-hooks.log
+fi
+
+# Only run cost/sync if we're in a Gas Town context
+if [ -n "$GT_ROLE" ]; then
+    # Record session costs (suppress all output)
+    gt costs record >/dev/null 2>&1 || true
+
+    # Sync beads if bd is available (suppress all output)
+    if command -v bd &>/dev/null; then
+        bd sync >/dev/null 2>&1 || true
+    fi
+fi
+
+echo '{}'
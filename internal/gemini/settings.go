@@ -0,0 +1,254 @@
+// Package gemini provides Gemini CLI configuration management: the
+// GEMINI.md instructions and the .gemini/settings.json hooks equivalent to
+// the Cursor rules and hooks.
+package gemini
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/settingsbackup"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+)
+
+//go:embed config/settings.json config/*.sh
+var configFS embed.FS
+
+// InstructionsFile is the instructions file Gemini CLI loads from the
+// workspace and its parents.
+const InstructionsFile = "GEMINI.md"
+
+// hookScriptPrefix marks the hook commands gt owns in settings.json. Hooks
+// running anything else belong to the user and survive regeneration.
+const hookScriptPrefix = ".gemini/hooks/gastown-"
+
+// hookScripts are the Gas Town hook scripts installed into .gemini/hooks/.
+var hookScripts = []string{
+	"gastown-session-start.sh",
+	"gastown-prompt.sh",
+	"gastown-precompress.sh",
+	"gastown-stop.sh",
+	"gastown-session-end.sh",
+}
+
+// EnsureSettingsForRole installs Gas Town settings for Gemini CLI in
+// workDir: GEMINI.md with the rules composed for role (see
+// cursor.ComposeRules), and .gemini/settings.json with hooks that inject
+// mail at session start, check mail before each turn, and record costs when
+// a turn or session ends.
+func EnsureSettingsForRole(workDir, role string) error {
+	if err := cursor.EnsureInstructionsFile(workDir, role, InstructionsFile); err != nil {
+		return err
+	}
+	if err := ensureHooks(workDir, role); err != nil {
+		return fmt.Errorf("installing hooks: %w", err)
+	}
+	return nil
+}
+
+// GeneratedSettings returns the Gemini CLI settings files in workDir for
+// role with the content EnsureSettingsForRole would write: GEMINI.md (kept
+// if edited), each hook script, and settings.json merged with the user's
+// settings.
+func GeneratedSettings(workDir, role string) ([]cursor.SettingsFile, error) {
+	instructions, err := cursor.InstructionsSettingsFile(workDir, role, InstructionsFile)
+	if err != nil {
+		return nil, err
+	}
+	files := []cursor.SettingsFile{instructions}
+
+	vars := templates.ConfigVars{WorkDir: workDir, Role: role, GTBin: templates.GTBinary()}
+	for _, script := range hookScripts {
+		path := filepath.Join(workDir, ".gemini", "hooks", script)
+		installed, err := readIfExists(path)
+		if err != nil {
+			return nil, err
+		}
+		content, err := renderScript(script, vars)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, cursor.SettingsFile{Path: path, Installed: installed, Generated: content})
+	}
+
+	path := filepath.Join(workDir, ".gemini", "settings.json")
+	installed, err := readIfExists(path)
+	if err != nil {
+		return nil, err
+	}
+	generated, err := configFS.ReadFile("config/settings.json")
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergeSettings(installed, generated)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, cursor.SettingsFile{Path: path, Installed: installed, Generated: merged}), nil
+}
+
+// readIfExists reads path, returning nil content if it does not exist.
+func readIfExists(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the agent workspace
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// renderScript renders a hook script template with vars.
+func renderScript(name string, vars templates.ConfigVars) ([]byte, error) {
+	raw, err := configFS.ReadFile("config/" + name)
+	if err != nil {
+		return nil, err
+	}
+	return templates.RenderConfig(name, raw, vars)
+}
+
+// RenderTemplates renders the Gemini CLI config for role with vars, without
+// reading a workspace or town: GEMINI.md from the base and role rules,
+// settings.json and the hook scripts. Used to test templates against
+// synthetic workspaces (gt templates selftest).
+func RenderTemplates(vars templates.ConfigVars, role string) ([]cursor.RenderedFile, error) {
+	rules, err := cursor.ComposeRulesWith(vars, role, nil)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := configFS.ReadFile("config/settings.json")
+	if err != nil {
+		return nil, err
+	}
+	files := []cursor.RenderedFile{
+		{Name: InstructionsFile, Content: cursor.Instructions(rules)},
+		{Name: ".gemini/settings.json", Content: settings},
+	}
+	for _, script := range hookScripts {
+		content, err := renderScript(script, vars)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, cursor.RenderedFile{Name: ".gemini/hooks/" + script, Content: content})
+	}
+	return files, nil
+}
+
+// ensureHooks installs the hook scripts and merges their hooks into
+// .gemini/settings.json, keeping the user's settings and hooks.
+func ensureHooks(workDir, role string) error {
+	geminiDir := filepath.Join(workDir, ".gemini")
+	hooksDir := filepath.Join(geminiDir, "hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return fmt.Errorf("creating hooks directory: %w", err)
+	}
+
+	vars := templates.ConfigVars{WorkDir: workDir, Role: role, GTBin: templates.GTBinary()}
+	for _, script := range hookScripts {
+		content, err := renderScript(script, vars)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(hooksDir, script), content, 0755); err != nil { //nolint:gosec // G306: hook scripts must be executable
+			return fmt.Errorf("writing %s: %w", script, err)
+		}
+	}
+
+	path := filepath.Join(geminiDir, "settings.json")
+	installed, err := os.ReadFile(path) //nolint:gosec // G304: path is within the agent workspace
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading settings.json: %w", err)
+	}
+	generated, err := configFS.ReadFile("config/settings.json")
+	if err != nil {
+		return err
+	}
+	content, err := mergeSettings(installed, generated)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(content, installed) {
+		return nil
+	}
+	if err := settingsbackup.WriteFile(path, content, 0644); err != nil { //nolint:gosec // G306: settings are not sensitive
+		return fmt.Errorf("writing settings.json: %w", err)
+	}
+	return nil
+}
+
+// mergeSettings merges the generated Gas Town hooks into an installed
+// settings.json. Other settings, and hook groups that do not run a Gas Town
+// script, are kept; Gas Town groups are replaced by the generated ones. An
+// installed file that is not valid JSON is an error rather than being
+// overwritten.
+func mergeSettings(installed, generated []byte) ([]byte, error) {
+	settings := map[string]json.RawMessage{}
+	if installed != nil {
+		if err := json.Unmarshal(installed, &settings); err != nil {
+			return nil, fmt.Errorf("existing settings.json is not valid JSON (fix or remove it): %w", err)
+		}
+	}
+	hooks := map[string][]json.RawMessage{}
+	if raw, ok := settings["hooks"]; ok {
+		if err := json.Unmarshal(raw, &hooks); err != nil {
+			return nil, fmt.Errorf("existing settings.json: parsing hooks: %w", err)
+		}
+	}
+
+	var gen struct {
+		Hooks map[string][]json.RawMessage `json:"hooks"`
+	}
+	if err := json.Unmarshal(generated, &gen); err != nil {
+		return nil, err
+	}
+
+	for event, groups := range hooks {
+		var kept []json.RawMessage
+		for _, group := range groups {
+			if !gastownGroup(group) {
+				kept = append(kept, group)
+			}
+		}
+		if len(kept) == 0 {
+			delete(hooks, event)
+		} else {
+			hooks[event] = kept
+		}
+	}
+	for event, groups := range gen.Hooks {
+		hooks[event] = append(groups, hooks[event]...)
+	}
+
+	raw, err := json.Marshal(hooks)
+	if err != nil {
+		return nil, err
+	}
+	settings["hooks"] = raw
+	out, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// gastownGroup reports whether a hook group runs a Gas Town hook script.
+func gastownGroup(group json.RawMessage) bool {
+	var g struct {
+		Hooks []struct {
+			Command string `json:"command"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal(group, &g); err != nil {
+		return false
+	}
+	for _, h := range g.Hooks {
+		if strings.Contains(h.Command, hookScriptPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package gemini
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnsureSettingsForRole(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureSettingsForRole(dir, "crew"); err != nil {
+		t.Fatalf("EnsureSettingsForRole: %v", err)
+	}
+
+	instructions, err := os.ReadFile(filepath.Join(dir, InstructionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(instructions)
+	if !strings.HasPrefix(text, "<!-- generated by gt ") {
+		t.Errorf("GEMINI.md should start with the generation marker:\n%s", text)
+	}
+	if strings.Contains(text, "alwaysApply") {
+		t.Error("GEMINI.md should not carry Cursor rule frontmatter")
+	}
+	if !strings.Contains(text, "gt mail check --inject") {
+		t.Error("GEMINI.md should carry the Gas Town rules")
+	}
+
+	for _, script := range hookScripts {
+		info, err := os.Stat(filepath.Join(dir, ".gemini", "hooks", script))
+		if err != nil {
+			t.Errorf("%s not installed: %v", script, err)
+		} else if info.Mode()&0100 == 0 {
+			t.Errorf("%s is not executable", script)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".gemini", "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings struct {
+		Hooks map[string]json.RawMessage `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("settings.json: %v", err)
+	}
+	for _, event := range []string{"SessionStart", "BeforeAgent", "AfterAgent", "SessionEnd"} {
+		if !strings.Contains(string(settings.Hooks[event]), hookScriptPrefix) {
+			t.Errorf("settings.json has no Gas Town %s hook", event)
+		}
+	}
+}
+
+func TestEnsureSettingsKeepsUserFiles(t *testing.T) {
+	dir := t.TempDir()
+	user := "# Project notes\n"
+	if err := os.WriteFile(filepath.Join(dir, InstructionsFile), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureSettingsForRole(dir, "polecat"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, InstructionsFile)); string(got) != user {
+		t.Errorf("user GEMINI.md was overwritten:\n%s", got)
+	}
+}
+
+func TestMergeSettings(t *testing.T) {
+	generated, err := configFS.ReadFile("config/settings.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed := []byte(`{
+  "theme": "Dracula",
+  "hooks": {
+    "AfterAgent": [
+      {"hooks": [{"type": "command", "command": "bash -lc '.gemini/hooks/gastown-old.sh'"}]},
+      {"hooks": [{"type": "command", "command": "./notify.sh"}]}
+    ],
+    "BeforeTool": [
+      {"matcher": "write_file", "hooks": [{"type": "command", "command": "./lint.sh"}]}
+    ]
+  }
+}`)
+	merged, err := mergeSettings(installed, generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Theme string                       `json:"theme"`
+		Hooks map[string][]json.RawMessage `json:"hooks"`
+	}
+	if err := json.Unmarshal(merged, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Theme != "Dracula" {
+		t.Errorf("user setting lost: theme = %q", got.Theme)
+	}
+	if len(got.Hooks["BeforeTool"]) != 1 {
+		t.Errorf("user-only event lost: %s", got.Hooks["BeforeTool"])
+	}
+	after := got.Hooks["AfterAgent"]
+	if len(after) != 2 || !strings.Contains(string(after[0]), "gastown-stop.sh") || !strings.Contains(string(after[1]), "notify.sh") {
+		t.Errorf("AfterAgent = %s, want the generated hook then the user's", after)
+	}
+	if strings.Contains(string(merged), "gastown-old.sh") {
+		t.Error("stale Gas Town hook was kept")
+	}
+
+	again, err := mergeSettings(merged, generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(merged) {
+		t.Errorf("merge is not idempotent:\n%s\n---\n%s", merged, again)
+	}
+
+	if _, err := mergeSettings([]byte("{not json"), generated); err == nil {
+		t.Error("invalid settings.json should be an error")
+	}
+}
@@ -330,6 +330,19 @@ func (g *Git) RemoteURL(remote string) (string, error) {
 	return g.run("remote", "get-url", remote)
 }
 
+// SetRemote points the named remote at url, adding it if it does not exist.
+func (g *Git) SetRemote(name, url string) error {
+	if current, err := g.RemoteURL(name); err == nil {
+		if current == url {
+			return nil
+		}
+		_, err = g.run("remote", "set-url", name, url)
+		return err
+	}
+	_, err := g.run("remote", "add", name, url)
+	return err
+}
+
 // Remotes returns the list of configured remote names.
 func (g *Git) Remotes() ([]string, error) {
 	out, err := g.run("remote")
@@ -849,6 +862,19 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// ChangedFiles returns the files branch changed since it diverged from base
+// (git diff --name-only base...branch).
+func (g *Git) ChangedFiles(base, branch string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
@@ -0,0 +1,285 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+	"github.com/gofrs/flock"
+)
+
+// ErrOperationInProgress is returned (wrapped in an *OperationBusyError) when
+// another process holds the town operation lock.
+var ErrOperationInProgress = errors.New("another town operation is in progress")
+
+const (
+	// OperationHeartbeat is how often a held operation lock is refreshed.
+	OperationHeartbeat = 30 * time.Second
+
+	// OperationStaleAfter is how long a lock may go without a heartbeat
+	// before it is considered abandoned. It is the only staleness signal for
+	// locks held on another host (e.g. a town on a shared filesystem).
+	OperationStaleAfter = 2 * time.Minute
+)
+
+// operationPollInterval is how often AcquireOperationWait retries.
+var operationPollInterval = time.Second
+
+// OperationInfo describes who holds the town operation lock.
+type OperationInfo struct {
+	Operation string    `json:"operation"`
+	Owner     string    `json:"owner,omitempty"`
+	User      string    `json:"user,omitempty"`
+	PID       int       `json:"pid"`
+	Hostname  string    `json:"hostname,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	Heartbeat time.Time `json:"heartbeat"`
+}
+
+// IsStale reports whether the lock was abandoned: its heartbeat is too old,
+// or it was taken on this host by a process that has exited.
+func (o *OperationInfo) IsStale(now time.Time) bool {
+	if now.Sub(o.Heartbeat) > OperationStaleAfter {
+		return true
+	}
+	hostname, _ := os.Hostname()
+	if o.Hostname == "" || o.Hostname == hostname {
+		return !processExists(o.PID)
+	}
+	return false
+}
+
+// String describes the holder, e.g.
+// "doctor --fix by mayor/ (PID 4242 on box, started 3m0s ago)".
+func (o *OperationInfo) String() string {
+	who := o.Owner
+	if who == "" {
+		who = o.User
+	} else if o.User != "" && o.User != o.Owner {
+		who = fmt.Sprintf("%s [%s]", o.Owner, o.User)
+	}
+	s := o.Operation
+	if who != "" {
+		s += " by " + who
+	}
+	if o.PID == 0 {
+		return s
+	}
+	where := fmt.Sprintf("PID %d", o.PID)
+	if o.Hostname != "" {
+		where += " on " + o.Hostname
+	}
+	return fmt.Sprintf("%s (%s, started %s ago)", s, where, time.Since(o.StartedAt).Round(time.Second))
+}
+
+// OperationBusyError reports the holder of the town operation lock.
+type OperationBusyError struct {
+	Holder *OperationInfo
+}
+
+func (e *OperationBusyError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrOperationInProgress, e.Holder)
+}
+
+func (e *OperationBusyError) Unwrap() error { return ErrOperationInProgress }
+
+// OperationLock is a held town operation lock. Only one town-mutating
+// operation (such as 'gt doctor --fix') runs at a time; the lock is kept
+// alive by a heartbeat until Release.
+type OperationLock struct {
+	path string
+	info OperationInfo
+
+	// Recovered is the stale lock this one replaced, if any.
+	Recovered *OperationInfo
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// OperationLockPath returns the path of a town's operation lock file.
+func OperationLockPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "operation.lock")
+}
+
+// AcquireOperation takes the town operation lock for operation on behalf of
+// owner (an agent address or "overseer"). It returns an *OperationBusyError
+// if a live process holds the lock. Stale locks are replaced and reported in
+// the returned lock's Recovered field.
+func AcquireOperation(townRoot, operation, owner string) (*OperationLock, error) {
+	path := OperationLockPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating lock directory: %w", err)
+	}
+
+	guard, err := lockGuard(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = guard.Unlock() }()
+
+	now := time.Now()
+	l := &OperationLock{path: path}
+	holder, err := readOperation(path)
+	switch {
+	case err == nil && holder.IsStale(now):
+		l.Recovered = holder
+	case err == nil:
+		return nil, &OperationBusyError{Holder: holder}
+	case errors.Is(err, ErrInvalidLock):
+		// A torn write from a crashed process: nobody can be holding it.
+		l.Recovered = &OperationInfo{Operation: "unreadable lock"}
+	case !errors.Is(err, ErrNotLocked):
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	l.info = OperationInfo{
+		Operation: operation,
+		Owner:     owner,
+		User:      currentUser(),
+		PID:       os.Getpid(),
+		Hostname:  hostname,
+		StartedAt: now,
+		Heartbeat: now,
+	}
+	if err := util.AtomicWriteJSON(path, l.info); err != nil {
+		return nil, fmt.Errorf("writing operation lock: %w", err)
+	}
+
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	go l.heartbeat()
+	return l, nil
+}
+
+// AcquireOperationWait is AcquireOperation, but while the lock is held by
+// someone else it retries for up to timeout, calling waiting with the
+// holder whenever the holder changes. After timeout it returns the last
+// *OperationBusyError.
+func AcquireOperationWait(townRoot, operation, owner string, timeout time.Duration, waiting func(*OperationInfo)) (*OperationLock, error) {
+	deadline := time.Now().Add(timeout)
+	var last *OperationInfo
+	for {
+		l, err := AcquireOperation(townRoot, operation, owner)
+		var busy *OperationBusyError
+		if !errors.As(err, &busy) {
+			return l, err
+		}
+		if !time.Now().Before(deadline) {
+			return nil, err
+		}
+		if waiting != nil && (last == nil || !last.StartedAt.Equal(busy.Holder.StartedAt) || last.PID != busy.Holder.PID) {
+			waiting(busy.Holder)
+		}
+		last = busy.Holder
+		time.Sleep(min(operationPollInterval, time.Until(deadline)))
+	}
+}
+
+// ReadOperation returns the current holder of a town's operation lock, or
+// ErrNotLocked. The holder may be stale; see OperationInfo.IsStale.
+func ReadOperation(townRoot string) (*OperationInfo, error) {
+	return readOperation(OperationLockPath(townRoot))
+}
+
+// Release stops the heartbeat and removes the lock file, unless another
+// process has since taken it over.
+func (l *OperationLock) Release() error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+
+	guard, err := lockGuard(l.path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = guard.Unlock() }()
+
+	if !l.stillOurs() {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing operation lock: %w", err)
+	}
+	return nil
+}
+
+// heartbeat refreshes the lock until Release, so other processes can tell
+// a long-running operation from an abandoned one.
+func (l *OperationLock) heartbeat() {
+	defer close(l.done)
+	ticker := time.NewTicker(OperationHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if !l.refresh() {
+				return
+			}
+		}
+	}
+}
+
+// refresh updates the heartbeat. It returns false if the lock was lost.
+func (l *OperationLock) refresh() bool {
+	guard, err := lockGuard(l.path)
+	if err != nil {
+		return true // try again next tick
+	}
+	defer func() { _ = guard.Unlock() }()
+
+	if !l.stillOurs() {
+		return false
+	}
+	l.info.Heartbeat = time.Now()
+	_ = util.AtomicWriteJSON(l.path, l.info)
+	return true
+}
+
+// stillOurs reports whether the lock file still records this lock.
+// Callers hold the guard.
+func (l *OperationLock) stillOurs() bool {
+	current, err := readOperation(l.path)
+	return err == nil && current.PID == l.info.PID && current.Hostname == l.info.Hostname &&
+		current.StartedAt.Equal(l.info.StartedAt)
+}
+
+// lockGuard serializes check-and-replace of the lock file across processes.
+func lockGuard(path string) (*flock.Flock, error) {
+	guard := flock.New(path + ".guard")
+	if err := guard.Lock(); err != nil {
+		return nil, fmt.Errorf("locking %s: %w", guard.Path(), err)
+	}
+	return guard, nil
+}
+
+func readOperation(path string) (*OperationInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotLocked
+		}
+		return nil, fmt.Errorf("reading operation lock: %w", err)
+	}
+	var info OperationInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLock, err)
+	}
+	return &info, nil
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
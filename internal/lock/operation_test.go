@@ -0,0 +1,117 @@
+package lock
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+func TestAcquireOperation(t *testing.T) {
+	town := t.TempDir()
+
+	l, err := AcquireOperation(town, "doctor --fix", "overseer")
+	if err != nil {
+		t.Fatalf("AcquireOperation: %v", err)
+	}
+	if l.Recovered != nil {
+		t.Errorf("Recovered = %+v on a fresh town", l.Recovered)
+	}
+
+	_, err = AcquireOperation(town, "doctor rollback", "mayor/")
+	var busy *OperationBusyError
+	if !errors.As(err, &busy) || !errors.Is(err, ErrOperationInProgress) {
+		t.Fatalf("second AcquireOperation = %v, want an OperationBusyError", err)
+	}
+	if busy.Holder.Operation != "doctor --fix" || busy.Holder.Owner != "overseer" || busy.Holder.PID != os.Getpid() {
+		t.Errorf("holder = %+v, want the first lock", busy.Holder)
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := ReadOperation(town); !errors.Is(err, ErrNotLocked) {
+		t.Errorf("ReadOperation after Release = %v, want ErrNotLocked", err)
+	}
+}
+
+func TestAcquireOperationRecoversStaleLock(t *testing.T) {
+	hostname, _ := os.Hostname()
+	tests := []struct {
+		name string
+		info OperationInfo
+	}{
+		{"dead process", OperationInfo{Operation: "doctor --fix", PID: 999999999, Hostname: hostname, Heartbeat: time.Now()}},
+		{"no heartbeat", OperationInfo{Operation: "doctor --fix", PID: os.Getpid(), Hostname: "other-host", Heartbeat: time.Now().Add(-2 * OperationStaleAfter)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			town := t.TempDir()
+			if err := os.MkdirAll(town+"/.runtime", 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := util.AtomicWriteJSON(OperationLockPath(town), tt.info); err != nil {
+				t.Fatal(err)
+			}
+
+			l, err := AcquireOperation(town, "doctor undo", "overseer")
+			if err != nil {
+				t.Fatalf("AcquireOperation over a stale lock: %v", err)
+			}
+			defer func() { _ = l.Release() }()
+			if l.Recovered == nil || l.Recovered.PID != tt.info.PID {
+				t.Errorf("Recovered = %+v, want the stale lock", l.Recovered)
+			}
+		})
+	}
+}
+
+func TestAcquireOperationLiveLockOnOtherHost(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(town+"/.runtime", 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The PID cannot be checked on another host; a fresh heartbeat wins.
+	info := OperationInfo{Operation: "doctor --fix", PID: 999999999, Hostname: "other-host", Heartbeat: time.Now()}
+	if err := util.AtomicWriteJSON(OperationLockPath(town), info); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AcquireOperation(town, "doctor --fix", "overseer"); !errors.Is(err, ErrOperationInProgress) {
+		t.Errorf("AcquireOperation = %v, want ErrOperationInProgress", err)
+	}
+}
+
+func TestAcquireOperationWait(t *testing.T) {
+	old := operationPollInterval
+	operationPollInterval = 10 * time.Millisecond
+	defer func() { operationPollInterval = old }()
+
+	town := t.TempDir()
+	first, err := AcquireOperation(town, "doctor --fix", "overseer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := AcquireOperationWait(town, "doctor --fix", "deacon/", 30*time.Millisecond, nil); !errors.Is(err, ErrOperationInProgress) {
+		t.Fatalf("AcquireOperationWait past timeout = %v, want ErrOperationInProgress", err)
+	}
+
+	waited := 0
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = first.Release()
+	}()
+	l, err := AcquireOperationWait(town, "doctor --fix", "deacon/", 5*time.Second, func(*OperationInfo) { waited++ })
+	if err != nil {
+		t.Fatalf("AcquireOperationWait: %v", err)
+	}
+	defer func() { _ = l.Release() }()
+	if waited != 1 {
+		t.Errorf("waiting called %d times, want once per holder", waited)
+	}
+	if got, _ := ReadOperation(town); got == nil || got.Owner != "deacon/" {
+		t.Errorf("holder after wait = %+v, want deacon/", got)
+	}
+}
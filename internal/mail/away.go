@@ -0,0 +1,203 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+	"github.com/cursorworkshop/cursor-gastown/internal/wisp"
+)
+
+// AutoReplyPrefix marks auto-reply subjects. Messages with this prefix never
+// trigger another auto-reply, so two away inboxes cannot ping-pong.
+const AutoReplyPrefix = "Auto-reply: "
+
+// AutoReplyInterval is the minimum time between auto-replies from one away
+// inbox to the same sender.
+const AutoReplyInterval = 24 * time.Hour
+
+// AwayStatus describes why a recipient's inbox is unattended.
+type AwayStatus struct {
+	// Address is the away recipient.
+	Address string
+
+	// Reason is the status shown to senders.
+	Reason string
+
+	// Until is the expected return time, if known.
+	Until *time.Time
+
+	// Fallback receives urgent mail in place of the recipient.
+	Fallback string
+}
+
+// Away returns the away status for a recipient address, or nil if the inbox
+// is attended. An explicit entry in the messaging config (by address, then by
+// rig name) takes precedence; otherwise agents in a parked or docked rig are
+// away.
+func (r *Router) Away(address string) *AwayStatus {
+	if r.townRoot == "" || address == "overseer" {
+		return nil
+	}
+
+	rigName := addressRig(address)
+	var entry *config.AwayConfig
+	if cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot)); err == nil {
+		identity := addressToIdentity(address)
+		for key, away := range cfg.Away {
+			if addressToIdentity(key) == identity {
+				entry = &away
+				break
+			}
+		}
+		if entry == nil && rigName != "" {
+			if away, ok := cfg.Away[rigName]; ok {
+				entry = &away
+			}
+		}
+	}
+
+	// An entry past its expected return time has lapsed
+	if entry != nil && entry.Until != nil && timeNow().After(*entry.Until) {
+		entry = nil
+	}
+
+	var rigStatus string
+	if rigName != "" {
+		rigStatus = wisp.NewConfig(r.townRoot, rigName).GetString("status")
+	}
+
+	if entry == nil {
+		if rigStatus != "parked" && rigStatus != "docked" {
+			return nil
+		}
+		entry = &config.AwayConfig{}
+	}
+
+	status := &AwayStatus{
+		Address:  address,
+		Reason:   entry.Message,
+		Until:    entry.Until,
+		Fallback: entry.Fallback,
+	}
+	if status.Reason == "" {
+		if rigStatus == "parked" || rigStatus == "docked" {
+			status.Reason = fmt.Sprintf("rig %s is %s", rigName, rigStatus)
+		} else {
+			status.Reason = "away"
+		}
+	}
+	return status
+}
+
+// addressRig returns the rig component of a rig-level address, or "" for
+// town-level addresses.
+func addressRig(address string) string {
+	rigName, _, ok := strings.Cut(address, "/")
+	if !ok || rigName == "mayor" || rigName == "deacon" {
+		return ""
+	}
+	return rigName
+}
+
+// divert forwards an urgent message to the away recipient's fallback.
+// Returns false without sending if the message should not be diverted.
+func (r *Router) divert(msg *Message, away *AwayStatus) (bool, error) {
+	if msg.Priority != PriorityUrgent || away.Fallback == "" {
+		return false, nil
+	}
+
+	fwd := *msg
+	fwd.To = away.Fallback
+	fwd.Subject = fmt.Sprintf("[for %s] %s", msg.To, msg.Subject)
+	fwd.Body = fmt.Sprintf("Diverted from %s (%s).\n\n%s", msg.To, away.Reason, msg.Body)
+	fwd.CC = nil
+	if err := r.deliver(&fwd); err != nil {
+		return false, fmt.Errorf("diverting urgent mail to %s: %w", away.Fallback, err)
+	}
+	return true, nil
+}
+
+// autoReply tells the sender that the recipient is away, unless the message
+// is automated or the sender was already answered recently. Best-effort.
+func (r *Router) autoReply(msg *Message, away *AwayStatus, diverted bool) {
+	if !r.shouldAutoReply(msg) {
+		return
+	}
+	reply := NewReplyMessage(msg.To, msg.From, AutoReplyPrefix+msg.Subject, away.replyBody(diverted), msg)
+	reply.Wisp = true
+	if err := r.deliver(reply); err == nil {
+		r.recordAutoReply(msg.To, msg.From)
+	}
+}
+
+// replyBody renders the auto-reply text.
+func (a *AwayStatus) replyBody(diverted bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s is away: %s.\n", a.Address, a.Reason)
+	if a.Until != nil {
+		fmt.Fprintf(&b, "Expected back: %s.\n", a.Until.Local().Format("2006-01-02 15:04"))
+	}
+	switch {
+	case diverted:
+		fmt.Fprintf(&b, "Your urgent message was forwarded to %s.\n", a.Fallback)
+	case a.Fallback != "":
+		fmt.Fprintf(&b, "Your message is queued. For anything urgent, contact %s.\n", a.Fallback)
+	default:
+		b.WriteString("Your message is queued until they return.\n")
+	}
+	return b.String()
+}
+
+// shouldAutoReply reports whether the sender of msg should get an auto-reply.
+func (r *Router) shouldAutoReply(msg *Message) bool {
+	if msg.From == "" || isSelfMail(msg.From, msg.To) || isLifecycleMessage(msg) {
+		return false
+	}
+	if strings.HasPrefix(msg.Subject, AutoReplyPrefix) {
+		return false
+	}
+	last, ok := r.loadAutoReplies()[autoReplyKey(msg.To, msg.From)]
+	return !ok || timeNow().Sub(last) >= AutoReplyInterval
+}
+
+// autoReplyStatePath is where auto-reply times are recorded for rate limiting.
+func (r *Router) autoReplyStatePath() string {
+	return filepath.Join(r.townRoot, ".runtime", "mail-autoreplies.json")
+}
+
+func autoReplyKey(to, from string) string {
+	return addressToIdentity(to) + "<-" + addressToIdentity(from)
+}
+
+func (r *Router) loadAutoReplies() map[string]time.Time {
+	replies := make(map[string]time.Time)
+	data, err := os.ReadFile(r.autoReplyStatePath())
+	if err == nil {
+		_ = json.Unmarshal(data, &replies)
+	}
+	return replies
+}
+
+// recordAutoReply notes that to auto-replied to from, dropping expired entries.
+func (r *Router) recordAutoReply(to, from string) {
+	replies := r.loadAutoReplies()
+	now := timeNow()
+	for key, t := range replies {
+		if now.Sub(t) >= AutoReplyInterval {
+			delete(replies, key)
+		}
+	}
+	replies[autoReplyKey(to, from)] = now
+
+	path := r.autoReplyStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = util.AtomicWriteJSON(path, replies)
+}
@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/wisp"
+)
+
+func TestRouterAway(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	if away := r.Away("gastown/crew/joe"); away != nil {
+		t.Fatalf("Away() with no config = %+v, want nil", away)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	cfg := config.NewMessagingConfig()
+	cfg.Away["gastown/crew/joe"] = config.AwayConfig{Message: "on leave", Fallback: "gastown/crew/max"}
+	cfg.Away["gastown/crew/ann"] = config.AwayConfig{Message: "back already", Until: &past}
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(townRoot), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	away := r.Away("gastown/joe") // normalized address form
+	if away == nil || away.Reason != "on leave" || away.Fallback != "gastown/crew/max" {
+		t.Errorf("Away(joe) = %+v", away)
+	}
+	if away := r.Away("gastown/crew/ann"); away != nil {
+		t.Errorf("Away(ann) past Until = %+v, want nil", away)
+	}
+	if away := r.Away("mayor/"); away != nil {
+		t.Errorf("Away(mayor/) = %+v, want nil", away)
+	}
+
+	// Parked rigs are away without explicit config
+	if err := wisp.NewConfig(townRoot, "beads").Set("status", "parked"); err != nil {
+		t.Fatal(err)
+	}
+	away = r.Away("beads/witness")
+	if away == nil || away.Reason != "rig beads is parked" || away.Fallback != "" {
+		t.Errorf("Away(beads/witness) = %+v", away)
+	}
+}
+
+func TestShouldAutoReply(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	msg := &Message{From: "mayor/", To: "gastown/crew/joe", Subject: "Review PR"}
+
+	if !r.shouldAutoReply(msg) {
+		t.Fatal("expected auto-reply for first message")
+	}
+	r.recordAutoReply(msg.To, msg.From)
+	if r.shouldAutoReply(msg) {
+		t.Error("expected no auto-reply within interval")
+	}
+
+	orig := timeNow
+	timeNow = func() time.Time { return orig().Add(AutoReplyInterval + time.Minute) }
+	defer func() { timeNow = orig }()
+	if !r.shouldAutoReply(msg) {
+		t.Error("expected auto-reply after interval")
+	}
+
+	for _, m := range []*Message{
+		{From: "gastown/crew/max", To: "gastown/crew/joe", Subject: AutoReplyPrefix + "hi"},
+		{From: "gastown/witness", To: "gastown/crew/joe", Subject: "POLECAT_DONE nux"},
+		{From: "gastown/crew/joe", To: "gastown/crew/joe", Subject: "handoff"},
+	} {
+		if r.shouldAutoReply(m) {
+			t.Errorf("shouldAutoReply(%q from %s) = true, want false", m.Subject, m.From)
+		}
+	}
+}
+
+func TestAwayReplyBody(t *testing.T) {
+	until := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	away := &AwayStatus{Address: "gastown/crew/joe", Reason: "on leave", Until: &until, Fallback: "gastown/crew/max"}
+
+	body := away.replyBody(true)
+	for _, want := range []string{"gastown/crew/joe is away: on leave.", "Expected back: 2026-03-01 09:00.", "forwarded to gastown/crew/max"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("replyBody(true) missing %q:\n%s", want, body)
+		}
+	}
+	if body := away.replyBody(false); !strings.Contains(body, "For anything urgent, contact gastown/crew/max") {
+		t.Errorf("replyBody(false) = %s", body)
+	}
+}
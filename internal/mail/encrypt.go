@@ -0,0 +1,152 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/secrets"
+)
+
+// ageArmorHeader begins an ASCII-armored age ciphertext.
+const ageArmorHeader = "-----BEGIN AGE ENCRYPTED FILE-----"
+
+// ErrNoMailIdentity is returned when a message body is encrypted but no age
+// identity is configured to decrypt it.
+var ErrNoMailIdentity = errors.New("message is encrypted and no mail_encryption.identity is configured")
+
+// runAge runs the age CLI with stdin and returns its stdout (seam for tests).
+var runAge = func(stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command("age", args...) //nolint:gosec // G204: args are fixed flags plus operator-configured keys
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("age: %s", msg)
+		}
+		return nil, fmt.Errorf("age: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// IsEncrypted reports whether a message body is age ciphertext.
+func IsEncrypted(body string) bool {
+	return strings.HasPrefix(strings.TrimSpace(body), ageArmorHeader)
+}
+
+// encryptBody encrypts body to an age recipient, armored so it can be
+// stored as a bead description.
+func encryptBody(recipient, body string) (string, error) {
+	out, err := runAge([]byte(body), "--encrypt", "--armor", "--recipient", recipient)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// decryptBody decrypts an armored body with an age identity: a path to an
+// identity file, or a secret reference resolving to the identity itself.
+func decryptBody(body, identity string) (string, error) {
+	if !secrets.IsRef(identity) {
+		if rest, ok := strings.CutPrefix(identity, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				identity = filepath.Join(home, rest)
+			}
+		}
+		out, err := runAge([]byte(body), "--decrypt", "--identity", identity)
+		return string(out), err
+	}
+
+	key, err := secrets.Resolve(identity)
+	if err != nil {
+		return "", err
+	}
+	// The identity goes on stdin so it never touches disk; the ciphertext
+	// (not secret) goes through a temp file.
+	f, err := os.CreateTemp("", "gt-mail-*.age")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.WriteString(body); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	out, err := runAge([]byte(key), "--decrypt", "--identity", "-", f.Name())
+	return string(out), err
+}
+
+// mailEncryption returns the town's overseer mail encryption settings, or
+// nil when encryption is not configured.
+func (r *Router) mailEncryption() *config.MailEncryptionConfig {
+	if r.townRoot == "" {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(r.townRoot))
+	if err != nil || settings.MailEncryption == nil || settings.MailEncryption.Recipient == "" {
+		return nil
+	}
+	return settings.MailEncryption
+}
+
+// onlyToOverseer reports whether the overseer is a message's sole
+// addressee: it is in To and any CCs are the overseer too.
+func onlyToOverseer(msg *Message) bool {
+	if addressToIdentity(msg.To) != "overseer" {
+		return false
+	}
+	for _, cc := range msg.CC {
+		if addressToIdentity(cc) != "overseer" {
+			return false
+		}
+	}
+	return true
+}
+
+// encryptForRecipient returns the body to store for a message: encrypted
+// when the overseer is its sole addressee and encryption is configured.
+// Every recipient reads the same stored body, so mail that also goes to
+// agents (as To or CC) stays plaintext for them to read.
+func (r *Router) encryptForRecipient(msg *Message) (string, error) {
+	if !onlyToOverseer(msg) || IsEncrypted(msg.Body) {
+		return msg.Body, nil
+	}
+	enc := r.mailEncryption()
+	if enc == nil {
+		return msg.Body, nil
+	}
+	body, err := encryptBody(enc.Recipient, msg.Body)
+	if err != nil {
+		return "", fmt.Errorf("encrypting overseer mail: %w", err)
+	}
+	return body, nil
+}
+
+// Decrypt replaces an encrypted message body with its plaintext. Bodies that
+// are not encrypted are left alone. Returns ErrNoMailIdentity if the body is
+// encrypted but no identity is configured.
+func (r *Router) Decrypt(msg *Message) error {
+	if !IsEncrypted(msg.Body) {
+		return nil
+	}
+	enc := r.mailEncryption()
+	if enc == nil || enc.Identity == "" {
+		return ErrNoMailIdentity
+	}
+	body, err := decryptBody(msg.Body, enc.Identity)
+	if err != nil {
+		return fmt.Errorf("decrypting message %s: %w", msg.ID, err)
+	}
+	msg.Body = body
+	return nil
+}
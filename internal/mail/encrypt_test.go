@@ -0,0 +1,148 @@
+package mail
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const ageArmorFooter = "-----END AGE ENCRYPTED FILE-----"
+
+// stubAge fakes the age CLI: "ciphertext" is the plaintext wrapped in armor
+// lines, and decryption requires identity "AGE-SECRET-KEY-TEST".
+func stubAge(t *testing.T) *[][]string {
+	t.Helper()
+	var calls [][]string
+	orig := runAge
+	t.Cleanup(func() { runAge = orig })
+	runAge = func(stdin []byte, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		switch args[0] {
+		case "--encrypt":
+			return []byte(ageArmorHeader + "\n" + string(stdin) + "\n" + ageArmorFooter + "\n"), nil
+		case "--decrypt":
+			identity, input := args[2], string(stdin)
+			if identity == "-" {
+				identity = string(stdin)
+				data, err := os.ReadFile(args[3])
+				if err != nil {
+					return nil, err
+				}
+				input = string(data)
+			} else if data, err := os.ReadFile(identity); err == nil {
+				identity = string(data)
+			}
+			if identity != "AGE-SECRET-KEY-TEST" {
+				return nil, errors.New("no identity matched any of the recipients")
+			}
+			input = strings.TrimPrefix(input, ageArmorHeader+"\n")
+			return []byte(strings.TrimSuffix(input, "\n"+ageArmorFooter+"\n")), nil
+		}
+		return nil, errors.New("unexpected age args")
+	}
+	return &calls
+}
+
+func writeMailEncryption(t *testing.T, townRoot, identity string) {
+	t.Helper()
+	dir := filepath.Join(townRoot, "settings")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"type":"town-settings","version":1,"mail_encryption":{"recipient":"age1test","identity":"` + identity + `"}}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncryptForRecipient(t *testing.T) {
+	calls := stubAge(t)
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	// Not configured: overseer mail stays plaintext.
+	body, err := r.encryptForRecipient(&Message{To: "overseer", Body: "diff --git a/x b/x"})
+	if err != nil || body != "diff --git a/x b/x" {
+		t.Fatalf("unconfigured = %q, %v", body, err)
+	}
+
+	writeMailEncryption(t, townRoot, "")
+	body, err = r.encryptForRecipient(&Message{To: "overseer", Body: "diff --git a/x b/x"})
+	if err != nil || !IsEncrypted(body) {
+		t.Fatalf("overseer body = %q, %v; want ciphertext", body, err)
+	}
+	if got := (*calls)[0]; got[len(got)-1] != "age1test" {
+		t.Errorf("age args = %v, want recipient age1test", got)
+	}
+
+	// Mail shared with an agent stays readable by that agent, whichever
+	// side the overseer is on.
+	for _, msg := range []*Message{
+		{To: "mayor/", CC: []string{"overseer"}, Body: "diff --git a/x b/x"},
+		{To: "overseer", CC: []string{"mayor/"}, Body: "diff --git a/x b/x"},
+	} {
+		body, err = r.encryptForRecipient(msg)
+		if err != nil || body != "diff --git a/x b/x" {
+			t.Errorf("To %s CC %v body = %q, %v; want plaintext", msg.To, msg.CC, body, err)
+		}
+	}
+
+	// Agent mail is never encrypted.
+	body, err = r.encryptForRecipient(&Message{To: "gastown/Toast", Body: "hello"})
+	if err != nil || body != "hello" {
+		t.Errorf("agent body = %q, %v", body, err)
+	}
+}
+
+func TestEncryptForRecipientFailsClosed(t *testing.T) {
+	orig := runAge
+	t.Cleanup(func() { runAge = orig })
+	runAge = func([]byte, ...string) ([]byte, error) { return nil, errors.New(`exec: "age": executable file not found in $PATH`) }
+
+	townRoot := t.TempDir()
+	writeMailEncryption(t, townRoot, "")
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	if _, err := r.encryptForRecipient(&Message{To: "overseer", Body: "secret"}); err == nil {
+		t.Error("expected error instead of storing plaintext when age is unavailable")
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	stubAge(t)
+	townRoot := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "age.key")
+	if err := os.WriteFile(keyFile, []byte("AGE-SECRET-KEY-TEST"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := encryptBody("age1test", "the diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	msg := &Message{ID: "hq-1", Body: ciphertext}
+	if err := r.Decrypt(msg); !errors.Is(err, ErrNoMailIdentity) {
+		t.Fatalf("no identity: err = %v", err)
+	}
+	if msg.Body != ciphertext {
+		t.Error("body should be left as ciphertext when it cannot be decrypted")
+	}
+
+	for _, identity := range []string{keyFile, "secretRef:file:" + keyFile} {
+		writeMailEncryption(t, townRoot, identity)
+		msg := &Message{ID: "hq-1", Body: ciphertext}
+		if err := r.Decrypt(msg); err != nil {
+			t.Fatalf("identity %s: %v", identity, err)
+		}
+		if msg.Body != "the diff" {
+			t.Errorf("identity %s: body = %q", identity, msg.Body)
+		}
+	}
+
+	plain := &Message{Body: "not encrypted"}
+	if err := r.Decrypt(plain); err != nil || plain.Body != "not encrypted" {
+		t.Errorf("plaintext message = %q, %v", plain.Body, err)
+	}
+}
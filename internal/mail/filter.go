@@ -0,0 +1,129 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+// BouncePrefix marks bounce subjects. Bounces are never filtered or bounced
+// again, so two filtered inboxes cannot loop.
+const BouncePrefix = "Bounced: "
+
+// ErrRejected indicates a recipient's mail filter refused a message.
+var ErrRejected = errors.New("rejected by mail filter")
+
+// filterMail checks msg against the mail filter for the recipient's role.
+// It returns "" if the message may be delivered, else the rejection reason.
+func (r *Router) filterMail(msg *Message) string {
+	if r.townRoot == "" || msg.From == "overseer" || isSelfMail(msg.From, msg.To) || isLifecycleMessage(msg) {
+		return ""
+	}
+	if strings.HasPrefix(msg.Subject, BouncePrefix) || strings.HasPrefix(msg.Subject, AutoReplyPrefix) {
+		return ""
+	}
+
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(r.townRoot))
+	if err != nil {
+		return ""
+	}
+	role := r.addressRole(msg.To)
+	filter, ok := cfg.Filters[role]
+	if !ok || filterAccepts(filter, msg) {
+		return ""
+	}
+
+	var accepts []string
+	if len(filter.Types) > 0 {
+		accepts = append(accepts, "types "+strings.Join(filter.Types, ", "))
+	}
+	if len(filter.Subjects) > 0 {
+		accepts = append(accepts, "subjects about "+strings.Join(filter.Subjects, ", "))
+	}
+	if len(filter.From) > 0 {
+		accepts = append(accepts, "mail from "+strings.Join(filter.From, ", "))
+	}
+	msgType := msg.Type
+	if msgType == "" {
+		msgType = TypeNotification
+	}
+	return fmt.Sprintf("%s inboxes accept only %s (got a %s)", role, strings.Join(accepts, "; "), msgType)
+}
+
+// filterAccepts reports whether msg matches any rule of filter.
+func filterAccepts(filter config.MailFilterConfig, msg *Message) bool {
+	if len(filter.Types) == 0 && len(filter.Subjects) == 0 && len(filter.From) == 0 {
+		return true
+	}
+	msgType := msg.Type
+	if msgType == "" {
+		msgType = TypeNotification
+	}
+	for _, t := range filter.Types {
+		if MessageType(t) == msgType {
+			return true
+		}
+	}
+	subject := strings.ToLower(msg.Subject)
+	for _, keyword := range filter.Subjects {
+		if strings.Contains(subject, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	from := addressToIdentity(msg.From)
+	for _, pattern := range filter.From {
+		pattern = addressToIdentity(pattern)
+		if ok, _ := path.Match(pattern, from); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// addressRole returns the role of the agent at address (polecat, crew,
+// witness, refinery, mayor, deacon), or "" if it cannot be determined.
+// Normalized rig/name addresses are resolved by looking for the worker's
+// directory in the town.
+func (r *Router) addressRole(address string) string {
+	identity := addressToIdentity(address)
+	switch identity {
+	case "mayor/":
+		return "mayor"
+	case "deacon/":
+		return "deacon"
+	}
+
+	parts := strings.Split(strings.TrimSuffix(address, "/"), "/")
+	switch {
+	case len(parts) == 3 && parts[1] == "polecats":
+		return "polecat"
+	case len(parts) == 3 && parts[1] == "crew":
+		return "crew"
+	case len(parts) == 2 && (parts[1] == "witness" || parts[1] == "refinery"):
+		return parts[1]
+	case len(parts) == 2:
+		for _, dir := range []string{"polecats", "crew"} {
+			if info, err := os.Stat(filepath.Join(r.townRoot, parts[0], dir, parts[1])); err == nil && info.IsDir() {
+				return strings.TrimSuffix(dir, "s")
+			}
+		}
+	}
+	return ""
+}
+
+// bounce returns a rejected message to its sender with the reason.
+// Best-effort: a failed bounce does not change the rejection.
+func (r *Router) bounce(msg *Message, reason string) {
+	if msg.From == "" {
+		return
+	}
+	body := fmt.Sprintf("Your message to %s was not delivered: %s.\n\n--- Original message ---\n%s", msg.To, reason, msg.Body)
+	reply := NewReplyMessage(msg.To, msg.From, BouncePrefix+msg.Subject, body, msg)
+	reply.Wisp = true
+	_ = r.deliver(reply)
+}
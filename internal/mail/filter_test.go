@@ -0,0 +1,88 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+func TestRouterFilterMail(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "polecats", "Toast"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &Message{From: "mayor/", To: "gastown/Toast", Subject: "Fix the bug", Type: TypeTask}
+	chatter := &Message{From: "gastown/crew/max", To: "gastown/Toast", Subject: "lunch?", Type: TypeNotification}
+	if reason := r.filterMail(chatter); reason != "" {
+		t.Fatalf("no filters configured: rejected with %q", reason)
+	}
+
+	cfg := config.NewMessagingConfig()
+	cfg.Filters["polecat"] = config.MailFilterConfig{Types: []string{"task", "reply"}, From: []string{"*/witness"}}
+	cfg.Filters["witness"] = config.MailFilterConfig{Subjects: []string{"escalation"}}
+	if err := config.SaveMessagingConfig(config.MessagingConfigPath(townRoot), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	accepted := []*Message{
+		task,
+		{From: "gastown/witness", To: "gastown/polecats/Toast", Subject: "status?"},
+		{From: "gastown/Toast", To: "gastown/witness", Subject: "Escalation: stuck on tests"},
+		{From: "overseer", To: "gastown/Toast", Subject: "hi"},
+		{From: "gastown/Toast", To: "gastown/Toast", Subject: "handoff"},
+		{From: "gastown/witness", To: "gastown/Toast", Subject: "NUDGE check mail"},
+		{From: "gastown/witness", To: "gastown/refinery", Subject: "no filter for refinery"},
+		{From: "gastown/Toast", To: "gastown/crew/max", Subject: BouncePrefix + "lunch?"},
+	}
+	for _, msg := range accepted {
+		if reason := r.filterMail(msg); reason != "" {
+			t.Errorf("%q from %s to %s rejected: %s", msg.Subject, msg.From, msg.To, reason)
+		}
+	}
+
+	reason := r.filterMail(chatter)
+	if reason == "" {
+		t.Fatal("notification from crew to a polecat should be rejected")
+	}
+	for _, want := range []string{"polecat", "task, reply", "*/witness", "notification"} {
+		if !strings.Contains(reason, want) {
+			t.Errorf("reason %q should mention %q", reason, want)
+		}
+	}
+	if reason := r.filterMail(&Message{From: "mayor/", To: "gastown/witness", Subject: "Weekly report"}); reason == "" {
+		t.Error("witness should reject mail without an escalation subject")
+	}
+}
+
+func TestRouterAddressRole(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	for _, dir := range []string{"gastown/polecats/Toast", "gastown/crew/max"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]string{
+		"mayor/":               "mayor",
+		"deacon":               "deacon",
+		"gastown/witness":      "witness",
+		"gastown/refinery":     "refinery",
+		"gastown/polecats/Nux": "polecat",
+		"gastown/crew/joe":     "crew",
+		"gastown/Toast":        "polecat",
+		"gastown/max":          "crew",
+		"gastown/unknown":      "",
+		"overseer":             "",
+	}
+	for address, want := range tests {
+		if got := r.addressRole(address); got != want {
+			t.Errorf("addressRole(%q) = %q, want %q", address, got, want)
+		}
+	}
+}
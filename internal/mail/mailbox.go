@@ -677,6 +677,48 @@ func (m *Mailbox) Search(opts SearchOptions) ([]*Message, error) {
 	return matches, nil
 }
 
+// ListTownUnread returns every unread message in the town's beads across
+// all recipients, oldest first. Used for town-wide inbox health checks.
+func ListTownUnread(townRoot string) ([]*Message, error) {
+	m := &Mailbox{workDir: townRoot}
+	beadsDir := filepath.Join(townRoot, ".beads")
+
+	var messages []*Message
+	for _, status := range []string{"open", "hooked"} {
+		msgs, err := m.queryMessages(beadsDir, "--limit", "0", status)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msgs...)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].Timestamp.Before(messages[j].Timestamp)
+	})
+	return messages, nil
+}
+
+// ListTown returns every message in the town's beads across all recipients,
+// read or not, newest first. Used for town-wide search (gt grep).
+func ListTown(townRoot string) ([]*Message, error) {
+	m := &Mailbox{workDir: townRoot}
+	beadsDir := filepath.Join(townRoot, ".beads")
+
+	var messages []*Message
+	for _, status := range []string{"open", "hooked", "closed"} {
+		msgs, err := m.queryMessages(beadsDir, "--limit", "0", status)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msgs...)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].Timestamp.After(messages[j].Timestamp)
+	})
+	return messages, nil
+}
+
 // Count returns the total and unread message counts.
 func (m *Mailbox) Count() (total, unread int, err error) {
 	messages, err := m.List()
@@ -475,7 +475,11 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 	if msg.Wisp {
 		return true
 	}
-	// Auto-detect lifecycle messages by subject prefix
+	return isLifecycleMessage(msg)
+}
+
+// isLifecycleMessage detects automated lifecycle messages by subject prefix.
+func isLifecycleMessage(msg *Message) bool {
 	subjectLower := strings.ToLower(msg.Subject)
 	wispPrefixes := []string{
 		"polecat_started",
@@ -559,8 +563,38 @@ func (r *Router) sendToGroup(msg *Message) error {
 	return nil
 }
 
-// sendToSingle sends a message to a single recipient.
+// sendToSingle sends a message to a single recipient, applying the
+// recipient role's mail filter and vacation mode when the recipient is away.
 func (r *Router) sendToSingle(msg *Message) error {
+	if reason := r.filterMail(msg); reason != "" {
+		r.bounce(msg, reason)
+		return fmt.Errorf("%w: %s: %s", ErrRejected, msg.To, reason)
+	}
+
+	away := r.Away(msg.To)
+	if away != nil {
+		diverted, err := r.divert(msg, away)
+		if err != nil {
+			return err
+		}
+		if diverted {
+			r.autoReply(msg, away, true)
+			return nil
+		}
+	}
+
+	if err := r.deliver(msg); err != nil {
+		return err
+	}
+
+	if away != nil {
+		r.autoReply(msg, away, false)
+	}
+	return nil
+}
+
+// deliver writes a message to the recipient's inbox and notifies them.
+func (r *Router) deliver(msg *Message) error {
 	// Convert addresses to beads identities
 	toIdentity := addressToIdentity(msg.To)
 
@@ -579,11 +613,17 @@ func (r *Router) sendToSingle(msg *Message) error {
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
+	// Overseer mail may be encrypted at rest
+	body, err := r.encryptForRecipient(msg)
+	if err != nil {
+		return err
+	}
+
 	// Build command: bd create <subject> --type=message --assignee=<recipient> -d <body>
 	args := []string{"create", msg.Subject,
 		"--type", "message",
 		"--assignee", toIdentity,
-		"-d", msg.Body,
+		"-d", body,
 	}
 
 	// Add priority flag
@@ -604,8 +644,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 	}
 
 	beadsDir := r.resolveBeadsDir(msg.To)
-	_, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
-	if err != nil {
+	if _, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir); err != nil {
 		return fmt.Errorf("sending message: %w", err)
 	}
 
@@ -689,12 +728,18 @@ func (r *Router) sendToQueue(msg *Message) error {
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
+	// Overseer mail may be encrypted at rest
+	body, err := r.encryptForRecipient(msg)
+	if err != nil {
+		return err
+	}
+
 	// Build command: bd create <subject> --type=message --assignee=queue:<name> -d <body>
 	// Use queue:<name> as assignee so inbox queries can filter by queue
 	args := []string{"create", msg.Subject,
 		"--type", "message",
 		"--assignee", msg.To, // queue:name
-		"-d", msg.Body,
+		"-d", body,
 	}
 
 	// Add priority flag
@@ -760,12 +805,18 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
+	// Overseer mail may be encrypted at rest
+	body, err := r.encryptForRecipient(msg)
+	if err != nil {
+		return err
+	}
+
 	// Build command: bd create <subject> --type=message --assignee=announce:<name> -d <body>
 	// Use announce:<name> as assignee so queries can filter by channel
 	args := []string{"create", msg.Subject,
 		"--type", "message",
 		"--assignee", msg.To, // announce:name
-		"-d", msg.Body,
+		"-d", body,
 	}
 
 	// Add priority flag
@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	s := NewServer("gastown", "0.1.0")
+	s.AddTool(Tool{
+		Name:        "echo",
+		Description: "Echo the text argument",
+		InputSchema: map[string]any{"type": "object"},
+		Handler: func(args json.RawMessage) (string, error) {
+			var in struct {
+				Text string `json:"text"`
+			}
+			if err := json.Unmarshal(args, &in); err != nil {
+				return "", err
+			}
+			if in.Text == "" {
+				return "", errors.New("text is required")
+			}
+			return in.Text, nil
+		},
+	})
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"nope"}}`,
+		`{"jsonrpc":"2.0","id":"six","method":"resources/list"}`,
+		`not json`,
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	if err := s.Serve(strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		`{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"tools":{}},"protocolVersion":"2025-03-26","serverInfo":{"name":"gastown","version":"0.1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"result":{"tools":[{"description":"Echo the text argument","inputSchema":{"type":"object"},"name":"echo"}]}}`,
+		`{"jsonrpc":"2.0","id":3,"result":{"content":[{"type":"text","text":"hi"}]}}`,
+		`{"jsonrpc":"2.0","id":4,"result":{"content":[{"type":"text","text":"text is required"}],"isError":true}}`,
+		`{"jsonrpc":"2.0","id":5,"error":{"code":-32602,"message":"unknown tool \"nope\""}}`,
+		`{"jsonrpc":"2.0","id":"six","error":{"code":-32601,"message":"method \"resources/list\" not found"}}`,
+	}
+	if len(lines) != len(want)+1 {
+		t.Fatalf("got %d responses, want %d:\n%s", len(lines), len(want)+1, out.String())
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("response %d = %s\nwant %s", i+1, lines[i], w)
+		}
+	}
+	if !strings.Contains(lines[len(want)], `"code":-32700`) {
+		t.Errorf("invalid JSON: %s, want a parse error", lines[len(want)])
+	}
+}
+
+func TestServe_OffersNewestProtocol(t *testing.T) {
+	var out bytes.Buffer
+	input := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}` + "\n"
+	if err := NewServer("gastown", "dev").Serve(strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"protocolVersion":"`+protocolVersions[0]+`"`) {
+		t.Errorf("initialize = %s, want protocol %s", out.String(), protocolVersions[0])
+	}
+}
@@ -2,6 +2,7 @@
 package mrqueue
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -150,3 +151,34 @@ func (l *EventLogger) LogMergeSkipped(mr *MR, reason string) error {
 func (l *EventLogger) LogPath() string {
 	return l.logPath
 }
+
+// ReadEvents returns the logged events at or after since, oldest first.
+// A missing log has no events; malformed lines are skipped.
+func (l *EventLogger) ReadEvents(since time.Time) ([]Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening event log: %w", err)
+	}
+	defer f.Close()
+
+	var evs []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev Event
+		if json.Unmarshal(scanner.Bytes(), &ev) != nil || ev.Timestamp.Before(since) {
+			continue
+		}
+		evs = append(evs, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading event log: %w", err)
+	}
+	return evs, nil
+}
@@ -112,3 +112,37 @@ func splitLines(s string) []string {
 	}
 	return lines
 }
+
+func TestEventLoggerReadEvents(t *testing.T) {
+	logger := NewEventLogger(t.TempDir())
+	mr := &MR{ID: "mr-1", Branch: "polecat/nux", Target: "main", Rig: "gastown"}
+
+	if evs, err := logger.ReadEvents(time.Time{}); err != nil || evs != nil {
+		t.Fatalf("ReadEvents without a log = %v, %v", evs, err)
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	if err := logger.LogEvent(Event{Timestamp: old, Type: EventMergeFailed, MRID: "mr-0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.LogMergeFailed(mr, "tests failed"); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(logger.LogPath(), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("not json\n")
+	_ = f.Close()
+	if err := logger.LogMerged(mr, "abc123"); err != nil {
+		t.Fatal(err)
+	}
+
+	evs, err := logger.ReadEvents(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(evs) != 2 || evs[0].Type != EventMergeFailed || evs[0].Reason != "tests failed" || evs[1].Type != EventMerged {
+		t.Errorf("ReadEvents = %+v, want the recent merge_failed and merged events", evs)
+	}
+}
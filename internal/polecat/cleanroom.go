@@ -0,0 +1,410 @@
+package polecat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// CleanroomFile is the cleanroom state file within a polecat's .runtime directory.
+const CleanroomFile = "cleanroom.json"
+
+// CleanroomImageKey is the rig config key naming the container image that
+// cleanroom polecats run in (e.g. "ghcr.io/acme/gastown-agent:latest").
+const CleanroomImageKey = "cleanroom_image"
+
+// CleanroomRuntimeKey is the rig config key selecting the container runtime
+// ("docker" or "podman"). When unset, the first one found on PATH is used.
+const CleanroomRuntimeKey = "cleanroom_runtime"
+
+// Cleanroom describes a polecat whose agent runs inside a container.
+// Stored in <polecat>/.runtime/cleanroom.json so every (re)start of the
+// session uses the same container setup.
+//
+// Only the worktree, the rig's git directory, and the beads, mail, and
+// events files that gt and bd in the container write are mounted
+// read-write, at their host paths; the town marker and the settings the
+// agent reads are mounted read-only. Other agents' workspaces, other rigs,
+// and the rest of the host filesystem are invisible to the agent. The
+// host's gt binary is mounted read-only, both at its host path (hook
+// commands use it) and as /usr/local/bin/gt. The startup command's
+// environment exports, including secret references, are evaluated on the
+// host and passed into the container by name, so agent credentials come
+// from settings env. The image must provide bd, the agent CLI, and any
+// tooling the task needs (see CheckImage).
+type Cleanroom struct {
+	Runtime   string   `json:"runtime"`
+	Image     string   `json:"image"`
+	Container string   `json:"container"`
+	WorkDir   string   `json:"work_dir"`
+	Mounts    []string `json:"mounts"`
+
+	// ReadOnlyMounts are bind-mounted without write access.
+	ReadOnlyMounts []string `json:"read_only_mounts,omitempty"`
+
+	// GTBinary is the host gt binary mounted into the container.
+	GTBinary string `json:"gt_binary,omitempty"`
+
+	// User is the uid:gid the agent runs as under docker, so files it
+	// writes in the worktree stay owned by the host user.
+	User string `json:"user,omitempty"`
+}
+
+// containerGTPath is where the host's gt binary is mounted so agents find
+// it on PATH.
+const containerGTPath = "/usr/local/bin/gt"
+
+// NewCleanroom builds the cleanroom setup for a polecat worktree.
+// containerRuntime may be empty to auto-detect docker or podman.
+func NewCleanroom(containerRuntime, image, container, workDir string) (*Cleanroom, error) {
+	if image == "" {
+		return nil, fmt.Errorf("no cleanroom image configured (set rig config %s)", CleanroomImageKey)
+	}
+	if containerRuntime == "" {
+		containerRuntime = DetectContainerRuntime()
+		if containerRuntime == "" {
+			return nil, fmt.Errorf("cleanroom mode needs docker or podman on PATH")
+		}
+	}
+	if containerRuntime != "docker" && containerRuntime != "podman" {
+		return nil, fmt.Errorf("unsupported cleanroom runtime %q (want docker or podman)", containerRuntime)
+	}
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("cleanroom mode needs a Linux host: the host's gt binary runs inside the container")
+	}
+
+	mounts, readOnly, err := cleanroomMounts(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cleanroom{
+		Runtime:        containerRuntime,
+		Image:          image,
+		Container:      container,
+		WorkDir:        workDir,
+		Mounts:         mounts,
+		ReadOnlyMounts: readOnly,
+		GTBinary:       templates.GTBinary(),
+	}
+	if containerRuntime == "docker" {
+		c.User = fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+	}
+	return c, nil
+}
+
+// cleanroomMounts returns the read-write and read-only mounts for a
+// polecat worktree. Read-write: the worktree, its git common dir, the rig
+// beads it resolves to, the town beads (mail), and the town events log,
+// its lock, and its bodies dir, which are created if missing so the
+// container does not write to its own filesystem instead. Read-only: the
+// town marker, town and rig settings, the messaging config, and the
+// polecats' shared .cursor settings, when they exist.
+func cleanroomMounts(workDir string) (mounts, readOnly []string, err error) {
+	mounts = []string{workDir}
+	if gitDir := worktreeCommonDir(workDir); gitDir != "" {
+		mounts = append(mounts, gitDir)
+	}
+	optional := []string{filepath.Join(filepath.Dir(workDir), ".cursor")}
+
+	townRoot, _ := workspace.Find(workDir)
+	if townRoot != "" {
+		eventsPath := filepath.Join(townRoot, events.EventsFile)
+		for _, file := range []string{eventsPath, eventsPath + ".lock"} {
+			if err := touchFile(file); err != nil {
+				return nil, nil, fmt.Errorf("preparing %s: %w", file, err)
+			}
+		}
+		bodiesDir := filepath.Join(townRoot, events.BodiesDir)
+		if err := os.MkdirAll(bodiesDir, 0755); err != nil {
+			return nil, nil, fmt.Errorf("preparing %s: %w", bodiesDir, err)
+		}
+		mounts = append(mounts, eventsPath, eventsPath+".lock", bodiesDir)
+
+		for _, dir := range []string{beads.ResolveBeadsDir(workDir), filepath.Join(townRoot, ".beads")} {
+			if dirExists(dir) && !withinDir(dir, workDir) && !slices.Contains(mounts, dir) {
+				mounts = append(mounts, dir)
+			}
+		}
+
+		optional = append(optional,
+			filepath.Join(townRoot, workspace.PrimaryMarker),
+			config.TownSettingsPath(townRoot),
+			config.MessagingConfigPath(townRoot))
+		if rel, err := filepath.Rel(townRoot, workDir); err == nil {
+			if rigName, _, ok := strings.Cut(filepath.ToSlash(rel), "/"); ok && rigName != ".." {
+				optional = append(optional, config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+			}
+		}
+	}
+	for _, path := range optional {
+		if _, err := os.Stat(path); err == nil {
+			readOnly = append(readOnly, path)
+		}
+	}
+	return mounts, readOnly, nil
+}
+
+// withinDir reports whether path is dir or inside it.
+func withinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// touchFile creates path if it does not exist, leaving existing content
+// alone.
+func touchFile(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// DetectContainerRuntime returns "docker" or "podman", whichever is found
+// first on PATH, or "" if neither is installed.
+func DetectContainerRuntime() string {
+	for _, runtime := range []string{"docker", "podman"} {
+		if _, err := exec.LookPath(runtime); err == nil {
+			return runtime
+		}
+	}
+	return ""
+}
+
+// Command wraps a shell command so it runs inside the cleanroom container.
+// A leading "export ... &&" (as in agent startup commands) runs on the
+// host, where secret references can be resolved, and the exported
+// variables are passed into the container by name. A container of the same
+// name left over from a crashed session is removed first, so restarts do
+// not fail on the name. The container is removed when the command exits.
+func (c *Cleanroom) Command(command string) string {
+	exports, inner := splitExports(command)
+
+	args := []string{c.Runtime, "run", "--rm", "-it"}
+	if c.Container != "" {
+		args = append(args, "--name", shellQuote(c.Container))
+	}
+	switch {
+	case c.User != "":
+		args = append(args, "--user", c.User)
+	case c.Runtime == "podman":
+		args = append(args, "--userns=keep-id")
+	}
+	for _, name := range exportNames(exports) {
+		args = append(args, "-e", name)
+	}
+	for _, mount := range c.Mounts {
+		args = append(args, "-v", shellQuote(mount+":"+mount))
+	}
+	for _, mount := range c.ReadOnlyMounts {
+		args = append(args, "-v", shellQuote(mount+":"+mount+":ro"))
+	}
+	if c.GTBinary != "" {
+		args = append(args, "-v", shellQuote(c.GTBinary+":"+c.GTBinary+":ro"))
+		if c.GTBinary != containerGTPath {
+			args = append(args, "-v", shellQuote(c.GTBinary+":"+containerGTPath+":ro"))
+		}
+	}
+	args = append(args, "-w", shellQuote(c.WorkDir), shellQuote(c.Image), "sh", "-c", shellQuote(inner))
+
+	var b strings.Builder
+	if exports != "" {
+		b.WriteString(exports + " && ")
+	}
+	if c.Container != "" {
+		fmt.Fprintf(&b, "%s rm -f %s >/dev/null 2>&1; ", c.Runtime, shellQuote(c.Container))
+	}
+	b.WriteString("exec " + strings.Join(args, " "))
+	return b.String()
+}
+
+// splitExports splits a command of the form "export A=1 B=2 && rest" into
+// its export statement and the rest. Commands without one are returned as
+// rest.
+func splitExports(command string) (exports, rest string) {
+	if !strings.HasPrefix(command, "export ") {
+		return "", command
+	}
+	words := shellWords(command)
+	offset := 0
+	for _, w := range words {
+		offset = strings.Index(command[offset:], w) + offset + len(w)
+		if w == "&&" {
+			return strings.TrimSpace(command[:offset-len(w)]), strings.TrimSpace(command[offset:])
+		}
+	}
+	return "", command
+}
+
+// exportNames returns the variable names assigned by an export statement.
+func exportNames(exports string) []string {
+	var names []string
+	for _, w := range shellWords(exports) {
+		name, _, ok := strings.Cut(w, "=")
+		if ok && envNamePattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// envNamePattern matches a valid environment variable name.
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// shellWords splits a command into words on unquoted whitespace, keeping
+// quotes in the words. It understands enough sh quoting for generated
+// startup commands: single quotes, and double quotes that may contain a
+// $(...) substitution.
+func shellWords(command string) []string {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ' ' || r == '\t':
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			continue
+		}
+		word.WriteRune(r)
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// StopCommand returns the command that force-removes the container.
+func (c *Cleanroom) StopCommand() *exec.Cmd {
+	return exec.Command(c.Runtime, "rm", "-f", c.Container) //nolint:gosec // G204: runtime is docker or podman
+}
+
+// CheckImage verifies that the image provides the given tools (bd and the
+// agent CLI), which are not mounted from the host. It runs the image once.
+func (c *Cleanroom) CheckImage(tools ...string) error {
+	var script strings.Builder
+	for _, tool := range tools {
+		fmt.Fprintf(&script, "command -v %s >/dev/null 2>&1 || echo %s; ", shellQuote(tool), shellQuote(tool))
+	}
+	out, err := exec.Command(c.Runtime, "run", "--rm", c.Image, "sh", "-c", script.String()).Output() //nolint:gosec // G204: runtime is docker or podman
+	if err != nil {
+		return fmt.Errorf("running image %s: %w", c.Image, err)
+	}
+	if missing := strings.Fields(string(out)); len(missing) > 0 {
+		return fmt.Errorf("image %s does not provide %s (cleanroom images need bd and the agent CLI)", c.Image, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// worktreeCommonDir returns the shared git directory of a linked worktree
+// (e.g. <rig>/.repo.git), or "" if workDir is not a linked worktree.
+func worktreeCommonDir(workDir string) string {
+	data, err := os.ReadFile(filepath.Join(workDir, ".git")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(workDir, gitDir)
+	}
+	// Linked worktrees point at <common>/worktrees/<name>
+	if filepath.Base(filepath.Dir(gitDir)) != "worktrees" {
+		return ""
+	}
+	return filepath.Dir(filepath.Dir(gitDir))
+}
+
+// dirExists reports whether path is an existing directory.
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// shellQuote single-quotes s for safe use in a POSIX shell command.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// CleanroomPath returns the cleanroom file path for a polecat directory.
+func CleanroomPath(polecatDir string) string {
+	return filepath.Join(polecatDir, ".runtime", CleanroomFile)
+}
+
+// WriteCleanroom saves a cleanroom setup into a polecat directory.
+func WriteCleanroom(polecatDir string, c *Cleanroom) error {
+	path := CleanroomPath(polecatDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	return util.AtomicWriteJSON(path, c)
+}
+
+// ReadCleanroom loads a polecat's cleanroom setup. Returns nil if the
+// polecat runs on the host.
+func ReadCleanroom(polecatDir string) (*Cleanroom, error) {
+	data, err := os.ReadFile(CleanroomPath(polecatDir)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var c Cleanroom
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing cleanroom: %w", err)
+	}
+	return &c, nil
+}
+
+// CleanroomCommand wraps command for the polecat in polecatDir if it runs
+// in a cleanroom, and returns it unchanged otherwise.
+func CleanroomCommand(polecatDir, command string) string {
+	c, err := ReadCleanroom(polecatDir)
+	if err != nil || c == nil {
+		return command
+	}
+	return c.Command(command)
+}
+
+// ParseCleanroomField reports whether a task description asks for cleanroom
+// mode with a "cleanroom: true" line (also accepts yes/1/on).
+func ParseCleanroomField(description string) bool {
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "cleanroom") {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "true", "yes", "1", "on":
+			return true
+		}
+		return false
+	}
+	return false
+}
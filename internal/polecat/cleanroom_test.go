@@ -0,0 +1,190 @@
+package polecat
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewCleanroom_Mounts(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("cleanroom mode needs a Linux host")
+	}
+	rigPath := t.TempDir()
+	commonDir := filepath.Join(rigPath, ".repo.git")
+	workDir := filepath.Join(rigPath, "polecats", "toast")
+	if err := os.MkdirAll(filepath.Join(commonDir, "worktrees", "toast"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(rigPath, "polecats", ".cursor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitFile := "gitdir: " + filepath.Join(commonDir, "worktrees", "toast") + "\n"
+	if err := os.WriteFile(filepath.Join(workDir, ".git"), []byte(gitFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewCleanroom("podman", "agent:latest", "gt-rig-toast", workDir)
+	if err != nil {
+		t.Fatalf("NewCleanroom() error = %v", err)
+	}
+	if len(c.Mounts) != 2 || c.Mounts[0] != workDir || c.Mounts[1] != commonDir {
+		t.Errorf("Mounts = %v, want [%s %s]", c.Mounts, workDir, commonDir)
+	}
+	if len(c.ReadOnlyMounts) != 1 || c.ReadOnlyMounts[0] != filepath.Join(rigPath, "polecats", ".cursor") {
+		t.Errorf("ReadOnlyMounts = %v, want the polecats settings dir", c.ReadOnlyMounts)
+	}
+
+	cmd := c.Command("export GT_ROLE=polecat && cursor-agent 'it''s'")
+	for _, want := range []string{
+		"exec podman run --rm -it --name 'gt-rig-toast' --userns=keep-id",
+		"-v '" + workDir + ":" + workDir + "'",
+		"-v '" + commonDir + ":" + commonDir + "'",
+		":ro'",
+		"-w '" + workDir + "' 'agent:latest' sh -c",
+		`cursor-agent '\''it'\'''\''s'\''`,
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("Command() = %q, missing %q", cmd, want)
+		}
+	}
+}
+
+func TestNewCleanroom_MountsTown(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("cleanroom mode needs a Linux host")
+	}
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	workDir := filepath.Join(rigPath, "polecats", "toast")
+	commonDir := filepath.Join(rigPath, ".repo.git")
+	rigBeads := filepath.Join(rigPath, "mayor", "rig", ".beads")
+	for _, dir := range []string{
+		workDir,
+		filepath.Join(commonDir, "worktrees", "toast"),
+		filepath.Join(workDir, ".beads"),
+		rigBeads,
+		filepath.Join(townRoot, ".beads"),
+		filepath.Join(townRoot, "mayor", ".cursor"),
+		filepath.Join(townRoot, "settings"),
+		filepath.Join(rigPath, "settings"),
+		filepath.Join(rigPath, "crew", "max"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range map[string]string{
+		filepath.Join(townRoot, "mayor", "town.json"):      `{"name":"test"}`,
+		filepath.Join(townRoot, "settings", "config.json"): `{}`,
+		filepath.Join(rigPath, "settings", "config.json"):  `{}`,
+		filepath.Join(workDir, ".git"):                     "gitdir: " + filepath.Join(commonDir, "worktrees", "toast") + "\n",
+		filepath.Join(workDir, ".beads", "redirect"):       "../../mayor/rig/.beads\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := NewCleanroom("docker", "agent:latest", "gt-gastown-toast", workDir)
+	if err != nil {
+		t.Fatalf("NewCleanroom() error = %v", err)
+	}
+
+	// Read-write: the worktree, git, beads, mail, and events only; never
+	// the town root, other agents' workspaces, or other rigs' settings.
+	eventsPath := filepath.Join(townRoot, ".events.jsonl")
+	wantMounts := []string{
+		workDir,
+		commonDir,
+		eventsPath,
+		eventsPath + ".lock",
+		filepath.Join(townRoot, ".runtime", "event-bodies"),
+		rigBeads,
+		filepath.Join(townRoot, ".beads"),
+	}
+	if !slices.Equal(c.Mounts, wantMounts) {
+		t.Errorf("Mounts =\n%v\nwant\n%v", c.Mounts, wantMounts)
+	}
+	wantReadOnly := []string{
+		filepath.Join(townRoot, "mayor", "town.json"),
+		filepath.Join(townRoot, "settings", "config.json"),
+		filepath.Join(rigPath, "settings", "config.json"),
+	}
+	if !slices.Equal(c.ReadOnlyMounts, wantReadOnly) {
+		t.Errorf("ReadOnlyMounts =\n%v\nwant\n%v", c.ReadOnlyMounts, wantReadOnly)
+	}
+
+	// The events log is created so the container appends to the host's.
+	if _, err := os.Stat(eventsPath); err != nil {
+		t.Errorf("events log not created: %v", err)
+	}
+}
+
+func TestCleanroomCommand_HostExports(t *testing.T) {
+	c := &Cleanroom{Runtime: "podman", Image: "agent:latest", Container: "gt-rig-toast", WorkDir: "/town/rig/polecats/toast",
+		Mounts: []string{"/town"}, GTBinary: "/opt/gt/bin/gt"}
+	command := `export API_KEY="$(gt secret resolve 'secretRef:env:A && B')" GT_ROLE=polecat NOTE='a b' && cursor-agent --force`
+
+	got := c.Command(command)
+	want := `export API_KEY="$(gt secret resolve 'secretRef:env:A && B')" GT_ROLE=polecat NOTE='a b' && ` +
+		`podman rm -f 'gt-rig-toast' >/dev/null 2>&1; ` +
+		`exec podman run --rm -it --name 'gt-rig-toast' --userns=keep-id -e API_KEY -e GT_ROLE -e NOTE ` +
+		`-v '/town:/town' -v '/opt/gt/bin/gt:/opt/gt/bin/gt:ro' -v '/opt/gt/bin/gt:/usr/local/bin/gt:ro' ` +
+		`-w '/town/rig/polecats/toast' 'agent:latest' sh -c 'cursor-agent --force'`
+	if got != want {
+		t.Errorf("Command() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNewCleanroom_Errors(t *testing.T) {
+	if _, err := NewCleanroom("docker", "", "c", t.TempDir()); err == nil {
+		t.Error("NewCleanroom without an image should fail")
+	}
+	if _, err := NewCleanroom("lxc", "agent:latest", "c", t.TempDir()); err == nil {
+		t.Error("NewCleanroom with an unsupported runtime should fail")
+	}
+}
+
+func TestCleanroomCommand(t *testing.T) {
+	polecatDir := t.TempDir()
+	if got := CleanroomCommand(polecatDir, "cursor-agent"); got != "cursor-agent" {
+		t.Errorf("CleanroomCommand without cleanroom = %q, want unchanged", got)
+	}
+
+	c := &Cleanroom{Runtime: "docker", Image: "agent:latest", Container: "gt-rig-toast", WorkDir: polecatDir, Mounts: []string{polecatDir}, User: "1000:1000"}
+	if err := WriteCleanroom(polecatDir, c); err != nil {
+		t.Fatal(err)
+	}
+	got := CleanroomCommand(polecatDir, "cursor-agent")
+	// A container left over from a crashed session is removed first.
+	if !strings.HasPrefix(got, "docker rm -f 'gt-rig-toast' >/dev/null 2>&1; exec docker run --rm -it --name 'gt-rig-toast' --user 1000:1000 ") {
+		t.Errorf("CleanroomCommand = %q, want stale container removal, then docker run wrapper", got)
+	}
+	if !strings.HasSuffix(got, "sh -c 'cursor-agent'") {
+		t.Errorf("CleanroomCommand = %q, want wrapped agent command", got)
+	}
+}
+
+func TestParseCleanroomField(t *testing.T) {
+	tests := []struct {
+		desc string
+		want bool
+	}{
+		{"Untrusted migration\n\ncleanroom: true", true},
+		{"Cleanroom: yes\nmore text", true},
+		{"cleanroom: false", false},
+		{"no fields here", false},
+	}
+	for _, tt := range tests {
+		if got := ParseCleanroomField(tt.desc); got != tt.want {
+			t.Errorf("ParseCleanroomField(%q) = %v, want %v", tt.desc, got, tt.want)
+		}
+	}
+}
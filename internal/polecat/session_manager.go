@@ -13,6 +13,7 @@ import (
 	"github.com/cursorworkshop/cursor-gastown/internal/agent"
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
 	"github.com/cursorworkshop/cursor-gastown/internal/rig"
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
@@ -147,17 +148,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		townRoot := filepath.Dir(m.rig.Path)
 		rc := config.ResolveAgentConfig(townRoot, m.rig.Path)
 		if rc != nil && rc.Command != "" {
-			// Map command to agent name
-			switch {
-			case strings.Contains(rc.Command, "cursor"):
-				agentName = "cursor"
-			case strings.Contains(rc.Command, "gemini"):
-				agentName = "gemini"
-			case strings.Contains(rc.Command, "codex"):
-				agentName = "codex"
-			default:
-				agentName = "cursor"
-			}
+			agentName = agent.AgentForCommand(rc.Command)
 		} else {
 			agentName = "cursor" // default
 		}
@@ -166,6 +157,20 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		return fmt.Errorf("ensuring agent settings: %w", err)
 	}
 
+	if err := preflight.BeforeSpawn(preflight.Spec{
+		Session:       sessionID,
+		Role:          session.RolePolecat,
+		RigPath:       m.rig.Path,
+		Agent:         opts.Agent,
+		SettingsDir:   polecatsDir,
+		WorkDir:       workDir,
+		CheckCheckout: true,
+		BranchPrefix:  constants.BranchPolecatPrefix + polecat,
+		MaxPolecats:   m.rig.GetIntConfig("max_polecats"),
+	}); err != nil {
+		return err
+	}
+
 	// Create session
 	if err := m.tmux.NewSession(sessionID, workDir); err != nil {
 		return fmt.Errorf("creating session: %w", err)
@@ -211,6 +216,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	if command == "" {
 		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, "")
 	}
+	command = CleanroomCommand(m.polecatDir(polecat), command)
 	if err := m.tmux.SendKeys(sessionID, command); err != nil {
 		return fmt.Errorf("sending command: %w", err)
 	}
@@ -270,6 +276,11 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 		return fmt.Errorf("killing session: %w", err)
 	}
 
+	// Remove the cleanroom container; killing the pane may leave it running
+	if c, _ := ReadCleanroom(m.polecatDir(polecat)); c != nil {
+		debugSession("remove cleanroom container", c.StopCommand().Run())
+	}
+
 	return nil
 }
 
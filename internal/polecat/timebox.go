@@ -0,0 +1,121 @@
+package polecat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// TimeboxFile is the timebox state file within a polecat's .runtime directory.
+const TimeboxFile = "timebox.json"
+
+// TimeboxWrapUpFraction is the elapsed fraction at which the daemon nudges
+// the polecat to start wrapping up.
+const TimeboxWrapUpFraction = 0.8
+
+// TimeboxPhase describes where a polecat is within its time box.
+type TimeboxPhase string
+
+const (
+	// TimeboxRunning means less than TimeboxWrapUpFraction has elapsed.
+	TimeboxRunning TimeboxPhase = "running"
+	// TimeboxWrapUp means the polecat should commit and hand off soon.
+	TimeboxWrapUp TimeboxPhase = "wrap-up"
+	// TimeboxExpired means the wall-clock budget is spent.
+	TimeboxExpired TimeboxPhase = "expired"
+)
+
+// Timebox is a wall-clock limit on a polecat session.
+// Stored in <polecat>/.runtime/timebox.json and enforced by the daemon.
+type Timebox struct {
+	Rig       string        `json:"rig"`
+	Polecat   string        `json:"polecat"`
+	HookBead  string        `json:"hook_bead,omitempty"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+
+	// NudgedAt is set once the wrap-up nudge has been delivered.
+	NudgedAt time.Time `json:"nudged_at,omitempty"`
+
+	// EnforcedAt is set once the session has been stopped for expiry.
+	// The daemon does not auto-restart polecats with an enforced time box.
+	EnforcedAt time.Time `json:"enforced_at,omitempty"`
+}
+
+// Deadline returns when the time box expires.
+func (t *Timebox) Deadline() time.Time {
+	return t.StartedAt.Add(t.Duration)
+}
+
+// Phase returns the time box phase at now.
+func (t *Timebox) Phase(now time.Time) TimeboxPhase {
+	elapsed := now.Sub(t.StartedAt)
+	switch {
+	case elapsed >= t.Duration:
+		return TimeboxExpired
+	case float64(elapsed) >= float64(t.Duration)*TimeboxWrapUpFraction:
+		return TimeboxWrapUp
+	default:
+		return TimeboxRunning
+	}
+}
+
+// Remaining returns the time left before expiry (never negative).
+func (t *Timebox) Remaining(now time.Time) time.Duration {
+	if r := t.Deadline().Sub(now); r > 0 {
+		return r
+	}
+	return 0
+}
+
+// TimeboxPath returns the timebox file path for a polecat directory.
+func TimeboxPath(polecatDir string) string {
+	return filepath.Join(polecatDir, ".runtime", TimeboxFile)
+}
+
+// WriteTimebox saves a time box into a polecat directory.
+func WriteTimebox(polecatDir string, tb *Timebox) error {
+	path := TimeboxPath(polecatDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	return util.AtomicWriteJSON(path, tb)
+}
+
+// ReadTimebox loads a polecat's time box. Returns nil if none is set.
+func ReadTimebox(polecatDir string) (*Timebox, error) {
+	data, err := os.ReadFile(TimeboxPath(polecatDir)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var tb Timebox
+	if err := json.Unmarshal(data, &tb); err != nil {
+		return nil, fmt.Errorf("parsing timebox: %w", err)
+	}
+	return &tb, nil
+}
+
+// ParseTimeboxField extracts a "timebox: <duration>" line from a task
+// description, e.g. "timebox: 2h30m". Returns 0 if absent or invalid.
+func ParseTimeboxField(description string) time.Duration {
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "timebox") {
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return 0
+		}
+		return d
+	}
+	return 0
+}
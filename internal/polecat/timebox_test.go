@@ -0,0 +1,71 @@
+package polecat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeboxPhase(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	tb := &Timebox{StartedAt: start, Duration: 10 * time.Hour}
+
+	tests := []struct {
+		elapsed time.Duration
+		want    TimeboxPhase
+	}{
+		{0, TimeboxRunning},
+		{7*time.Hour + 59*time.Minute, TimeboxRunning},
+		{8 * time.Hour, TimeboxWrapUp},
+		{9*time.Hour + 59*time.Minute, TimeboxWrapUp},
+		{10 * time.Hour, TimeboxExpired},
+		{60 * time.Hour, TimeboxExpired},
+	}
+	for _, tt := range tests {
+		if got := tb.Phase(start.Add(tt.elapsed)); got != tt.want {
+			t.Errorf("Phase(+%s) = %s, want %s", tt.elapsed, got, tt.want)
+		}
+	}
+
+	if got := tb.Remaining(start.Add(11 * time.Hour)); got != 0 {
+		t.Errorf("Remaining after expiry = %s, want 0", got)
+	}
+}
+
+func TestParseTimeboxField(t *testing.T) {
+	tests := []struct {
+		desc string
+		want time.Duration
+	}{
+		{"Fix the parser\n\ntimebox: 2h", 2 * time.Hour},
+		{"Timebox: 45m\nmore text", 45 * time.Minute},
+		{"timebox: soon", 0},
+		{"timebox: -1h", 0},
+		{"no field here", 0},
+	}
+	for _, tt := range tests {
+		if got := ParseTimeboxField(tt.desc); got != tt.want {
+			t.Errorf("ParseTimeboxField(%q) = %s, want %s", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestTimeboxReadWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	tb, err := ReadTimebox(dir)
+	if err != nil || tb != nil {
+		t.Fatalf("ReadTimebox(empty) = %v, %v; want nil, nil", tb, err)
+	}
+
+	want := &Timebox{Rig: "gastown", Polecat: "toast", StartedAt: time.Now().UTC().Truncate(time.Second), Duration: time.Hour}
+	if err := WriteTimebox(dir, want); err != nil {
+		t.Fatalf("WriteTimebox: %v", err)
+	}
+	got, err := ReadTimebox(dir)
+	if err != nil {
+		t.Fatalf("ReadTimebox: %v", err)
+	}
+	if got.Polecat != want.Polecat || got.Duration != want.Duration || !got.StartedAt.Equal(want.StartedAt) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,365 @@
+// Package preflight checks that an agent session can do useful work before
+// it is spawned: the agent binary is installed, agent settings are in place,
+// the checkout is usable, the cost budget has room, and a polecat slot is
+// free. A failed preflight refuses the spawn with an error naming each
+// problem and how to fix it, instead of starting a session that flails.
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/alerts"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
+)
+
+// SkipEnv disables preflight when set to "1" (for emergencies, e.g. starting
+// the mayor to raise an exhausted budget).
+const SkipEnv = "GT_SKIP_PREFLIGHT"
+
+// Spec describes the session about to be spawned.
+type Spec struct {
+	Session  string       // tmux session name
+	Role     session.Role // role of the agent
+	TownRoot string
+	RigPath  string // empty for town-level agents (mayor, deacon)
+	Agent    string // agent override ("" uses rig/town settings)
+
+	// SettingsDir is where agent settings were ensured ("" skips the check).
+	SettingsDir string
+
+	// WorkDir is the agent's checkout; it is checked if it is a git repo
+	// and CheckCheckout is set.
+	WorkDir       string
+	CheckCheckout bool
+
+	// BranchPrefix, if set, is required of the checked-out branch
+	// (e.g. "polecat/Toast" for a polecat's work branch).
+	BranchPrefix string
+
+	// MaxPolecats caps running polecat sessions in the rig (0 = no cap).
+	// Only checked for polecats.
+	MaxPolecats int
+}
+
+// Problem is one failed preflight check.
+type Problem struct {
+	Check   string // "agent", "settings", "checkout", "budget", "slot"
+	Message string
+	Fix     string
+}
+
+// Error is returned when preflight fails.
+type Error struct {
+	Session  string
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "preflight failed for %s; not spawning:", e.Session)
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s: %s", p.Check, p.Message)
+		if p.Fix != "" {
+			fmt.Fprintf(&b, "\n    fix: %s", p.Fix)
+		}
+	}
+	fmt.Fprintf(&b, "\n(set %s=1 to spawn anyway; 'gt why spawn' shows every check)", SkipEnv)
+	return b.String()
+}
+
+// IsError reports whether err is a preflight failure.
+func IsError(err error) bool {
+	var pe *Error
+	return errors.As(err, &pe)
+}
+
+// Seams for tests.
+var (
+	lookPath     = exec.LookPath
+	listSessions = func() ([]string, error) { return tmux.NewTmux().ListSessions() }
+	now          = time.Now
+)
+
+// Run checks spec and returns an *Error listing every problem found, or nil.
+func Run(spec Spec) error {
+	if os.Getenv(SkipEnv) == "1" {
+		return nil
+	}
+
+	var problems []Problem
+	for _, d := range Explain(spec) {
+		if d.Problem != nil {
+			problems = append(problems, *d.Problem)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &Error{Session: spec.Session, Problems: problems}
+}
+
+// BeforeSpawn is called by every path that starts an agent session (the
+// role managers, gt start, and the daemon's restarts) just before it creates
+// the tmux session, so a session that cannot do useful work is refused
+// instead of left to flail. Callers return the *Error unchanged. TownRoot
+// defaults to the parent of RigPath, where every rig lives.
+func BeforeSpawn(spec Spec) error {
+	if spec.TownRoot == "" && spec.RigPath != "" {
+		spec.TownRoot = filepath.Dir(spec.RigPath)
+	}
+	return Run(spec)
+}
+
+// Decision is the outcome of one preflight check.
+type Decision struct {
+	Check   string
+	Skipped string   // why the check does not apply; empty when it ran
+	Problem *Problem // nil when the check passed or was skipped
+}
+
+// Explain runs every preflight check for spec and reports each outcome,
+// including checks that passed or did not apply (for 'gt why spawn').
+// It ignores SkipEnv.
+func Explain(spec Spec) []Decision {
+	var decisions []Decision
+	add := func(check, skipped string, p *Problem) {
+		decisions = append(decisions, Decision{Check: check, Skipped: skipped, Problem: p})
+	}
+
+	command, agentProblem := checkAgent(spec)
+	add("agent", "", agentProblem)
+	switch {
+	case agentProblem != nil:
+		add("settings", "agent check failed", nil)
+	case filepath.Base(command) != "cursor-agent":
+		add("settings", fmt.Sprintf("agent runs %s, not cursor-agent", command), nil)
+	case spec.SettingsDir == "":
+		add("settings", "no settings directory for this spawn", nil)
+	default:
+		add("settings", "", checkSettings(spec))
+	}
+	if spec.CheckCheckout {
+		add("checkout", "", checkCheckout(spec))
+	} else {
+		add("checkout", "not checked for this role", nil)
+	}
+	if isAutonomous(spec.Role) {
+		add("budget", "", checkBudget(spec))
+	} else {
+		add("budget", fmt.Sprintf("%s is interactive, not held to the budget", spec.Role), nil)
+	}
+	switch {
+	case spec.Role != session.RolePolecat:
+		add("slot", "only polecats take slots", nil)
+	case spec.MaxPolecats <= 0:
+		add("slot", "max_polecats is not set", nil)
+	default:
+		add("slot", "", checkSlot(spec))
+	}
+	return decisions
+}
+
+// isAutonomous reports whether a role runs unattended; interactive roles
+// (mayor, crew) are human-driven and not held to the budget.
+func isAutonomous(role session.Role) bool {
+	switch role {
+	case session.RolePolecat, session.RoleWitness, session.RoleRefinery, session.RoleDeacon:
+		return true
+	}
+	return false
+}
+
+// checkAgent resolves the agent command and probes for its binary.
+func checkAgent(spec Spec) (string, *Problem) {
+	rc, _, err := config.ResolveAgentConfigWithOverride(spec.TownRoot, spec.RigPath, spec.Agent)
+	if err != nil {
+		return "", &Problem{Check: "agent", Message: err.Error(), Fix: "Use an agent defined in settings/config.json or a built-in preset"}
+	}
+	if rc == nil || rc.Command == "" {
+		return "", &Problem{Check: "agent", Message: "no agent command configured", Fix: "Set default_agent in settings/config.json"}
+	}
+	if _, err := lookPath(rc.Command); err != nil {
+		fix := fmt.Sprintf("Install %s or change the agent in settings/config.json", rc.Command)
+		if filepath.Base(rc.Command) == "cursor-agent" {
+			fix = "Install the Cursor CLI: curl https://cursor.com/install -fsS | bash (then run 'gt doctor')"
+		}
+		return rc.Command, &Problem{Check: "agent", Message: fmt.Sprintf("%s not found in PATH", rc.Command), Fix: fix}
+	}
+	return rc.Command, nil
+}
+
+// checkSettings verifies Cursor hooks and rules were installed.
+func checkSettings(spec Spec) *Problem {
+	if spec.SettingsDir == "" {
+		return nil
+	}
+	var missing []string
+	if !cursor.HooksInstalled(spec.SettingsDir) {
+		missing = append(missing, ".cursor/hooks.json")
+	}
+	if _, err := os.Stat(filepath.Join(spec.SettingsDir, ".cursor", "rules", "gastown.mdc")); err != nil {
+		missing = append(missing, ".cursor/rules/gastown.mdc")
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &Problem{
+		Check:   "settings",
+		Message: fmt.Sprintf("%s missing in %s", strings.Join(missing, ", "), spec.SettingsDir),
+		Fix:     "Run 'gt doctor --fix' to reinstall agent settings",
+	}
+}
+
+// checkCheckout verifies the work dir is not mid-merge or mid-rebase, has
+// no unresolved conflicts, and is on the expected branch. Uncommitted
+// changes are allowed: a restarted agent resumes its own work.
+func checkCheckout(spec Spec) *Problem {
+	dir := spec.WorkDir
+	if dir == "" {
+		return nil
+	}
+	if _, err := gitOutput(dir, "rev-parse", "--git-dir"); err != nil {
+		return nil // not a git checkout
+	}
+
+	for _, op := range []struct{ path, name, abort string }{
+		{"rebase-merge", "rebase", "git rebase --abort"},
+		{"rebase-apply", "rebase", "git rebase --abort"},
+		{"MERGE_HEAD", "merge", "git merge --abort"},
+		{"CHERRY_PICK_HEAD", "cherry-pick", "git cherry-pick --abort"},
+	} {
+		p, err := gitOutput(dir, "rev-parse", "--git-path", op.path)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		if _, err := os.Stat(p); err == nil {
+			return &Problem{
+				Check:   "checkout",
+				Message: fmt.Sprintf("%s has a %s in progress", dir, op.name),
+				Fix:     fmt.Sprintf("Finish it or run: git -C %s %s", dir, strings.TrimPrefix(op.abort, "git ")),
+			}
+		}
+	}
+
+	if out, err := gitOutput(dir, "diff", "--name-only", "--diff-filter=U"); err == nil && out != "" {
+		files := strings.Split(out, "\n")
+		return &Problem{
+			Check:   "checkout",
+			Message: fmt.Sprintf("%s has %d unresolved conflict(s): %s", dir, len(files), strings.Join(files, ", ")),
+			Fix:     "Resolve the conflicts and commit, or reset the checkout",
+		}
+	}
+
+	if spec.BranchPrefix != "" {
+		branch, err := gitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return &Problem{Check: "checkout", Message: fmt.Sprintf("cannot read branch in %s: %v", dir, err)}
+		}
+		if branch == "HEAD" {
+			return &Problem{
+				Check:   "checkout",
+				Message: fmt.Sprintf("%s is on a detached HEAD", dir),
+				Fix:     fmt.Sprintf("Check out the work branch (%s*)", spec.BranchPrefix),
+			}
+		}
+		if branch != spec.BranchPrefix && !strings.HasPrefix(branch, spec.BranchPrefix+"-") && !strings.HasPrefix(branch, spec.BranchPrefix+"/") {
+			return &Problem{
+				Check:   "checkout",
+				Message: fmt.Sprintf("%s is on branch %s, expected %s*", dir, branch, spec.BranchPrefix),
+				Fix:     "Check out the work branch, or recreate the worktree with 'gt polecat nuke' and re-sling",
+			}
+		}
+	}
+	return nil
+}
+
+// checkBudget refuses autonomous work while a cost budget alert shows the
+// budget exhausted (alerts are evaluated by 'gt alerts check').
+func checkBudget(spec Spec) *Problem {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(spec.TownRoot))
+	if err != nil || settings.CostAlerts == nil {
+		return nil
+	}
+	cfg := settings.CostAlerts
+	state, err := alerts.Load(spec.TownRoot)
+	if err != nil {
+		return nil
+	}
+
+	t := now()
+	budgets := []struct {
+		id, name, key string
+		limit         float64
+	}{
+		{"budget-daily", "daily", "daily_budget_usd", cfg.DailyBudgetUSD},
+		{"budget-weekly", "weekly", "weekly_budget_usd", cfg.WeeklyBudgetUSD},
+	}
+	for _, b := range budgets {
+		a := state.Alerts[b.id]
+		if b.limit <= 0 || a == nil || !a.Active() || a.Value < b.limit {
+			continue
+		}
+		if b.id == "budget-daily" && (a.UpdatedAt.Year() != t.Year() || a.UpdatedAt.YearDay() != t.YearDay()) {
+			continue // yesterday's spend
+		}
+		if t.Sub(a.UpdatedAt) > 7*24*time.Hour {
+			continue // outside the trailing week
+		}
+		return &Problem{
+			Check:   "budget",
+			Message: fmt.Sprintf("%s budget exhausted ($%.2f of $%.2f)", b.name, a.Value, b.limit),
+			Fix:     fmt.Sprintf("Raise cost_alerts.%s in settings/config.json or wait for the budget window to reset", b.key),
+		}
+	}
+	return nil
+}
+
+// checkSlot refuses a polecat when the rig already runs MaxPolecats.
+func checkSlot(spec Spec) *Problem {
+	if spec.MaxPolecats <= 0 || spec.RigPath == "" {
+		return nil
+	}
+	sessions, err := listSessions()
+	if err != nil {
+		return nil // no tmux server yet: nothing running
+	}
+	rigName := filepath.Base(spec.RigPath)
+	running := 0
+	for _, s := range sessions {
+		if s == spec.Session {
+			continue
+		}
+		id, err := session.ParseSessionName(s)
+		if err == nil && id.Role == session.RolePolecat && id.Rig == rigName {
+			running++
+		}
+	}
+	if running < spec.MaxPolecats {
+		return nil
+	}
+	return &Problem{
+		Check:   "slot",
+		Message: fmt.Sprintf("rig %s already runs %d of %d polecats", rigName, running, spec.MaxPolecats),
+		Fix:     fmt.Sprintf("Wait for a polecat to finish, or raise the cap: gt rig config set %s max_polecats <n>", rigName),
+	}
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output() //nolint:gosec // G204: args are fixed git subcommands
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
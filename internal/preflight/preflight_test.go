@@ -0,0 +1,278 @@
+package preflight
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/alerts"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+)
+
+// stubEnv replaces the binary probe and session listing for a test.
+func stubEnv(t *testing.T, installed bool, sessions ...string) {
+	t.Helper()
+	origLook, origList := lookPath, listSessions
+	t.Cleanup(func() { lookPath, listSessions = origLook, origList })
+	lookPath = func(file string) (string, error) {
+		if installed {
+			return "/usr/bin/" + file, nil
+		}
+		return "", exec.ErrNotFound
+	}
+	listSessions = func() ([]string, error) { return sessions, nil }
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func installSettings(t *testing.T, dir string) {
+	t.Helper()
+	writeFile(t, filepath.Join(dir, ".cursor", "hooks.json"), "{}")
+	writeFile(t, filepath.Join(dir, ".cursor", "rules", "gastown.mdc"), "rules")
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func newRepo(t *testing.T, branch string) string {
+	t.Helper()
+	dir := t.TempDir()
+	git(t, dir, "init", "-q", "-b", "main")
+	git(t, dir, "config", "user.name", "Test")
+	git(t, dir, "config", "user.email", "test@test")
+	writeFile(t, filepath.Join(dir, "a.txt"), "one\n")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "init")
+	if branch != "" {
+		git(t, dir, "checkout", "-q", "-b", branch)
+	}
+	return dir
+}
+
+func problems(t *testing.T, err error) []Problem {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var pe *Error
+	if !errors.As(err, &pe) {
+		t.Fatalf("Run returned %T, want *Error: %v", err, err)
+	}
+	return pe.Problems
+}
+
+func TestRunPassesHealthyPolecat(t *testing.T) {
+	stubEnv(t, true, "gt-gp-Nux", "gt-gp-witness")
+	town := t.TempDir()
+	settings := filepath.Join(town, "gp", "polecats")
+	installSettings(t, settings)
+
+	err := Run(Spec{
+		Session:       "gt-gp-Toast",
+		Role:          session.RolePolecat,
+		TownRoot:      town,
+		RigPath:       filepath.Join(town, "gp"),
+		SettingsDir:   settings,
+		WorkDir:       newRepo(t, "polecat/Toast-mk1"),
+		CheckCheckout: true,
+		BranchPrefix:  "polecat/Toast",
+		MaxPolecats:   2,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+func TestRunMissingAgent(t *testing.T) {
+	stubEnv(t, false)
+	town := t.TempDir()
+	err := Run(Spec{Session: "gt-gp-witness", Role: session.RoleWitness, TownRoot: town, SettingsDir: town})
+	got := problems(t, err)
+	if len(got) != 1 || got[0].Check != "agent" || !strings.Contains(got[0].Fix, "cursor.com/install") {
+		t.Fatalf("problems = %+v, want missing cursor-agent with install hint", got)
+	}
+	if !IsError(err) || !strings.Contains(err.Error(), SkipEnv) {
+		t.Errorf("error %q should be a preflight error naming %s", err, SkipEnv)
+	}
+
+	t.Setenv(SkipEnv, "1")
+	if err := Run(Spec{Session: "gt-gp-witness", Role: session.RoleWitness, TownRoot: town}); err != nil {
+		t.Errorf("Run with %s=1: %v", SkipEnv, err)
+	}
+}
+
+func TestBeforeSpawnDefaultsTownRoot(t *testing.T) {
+	stubEnv(t, true)
+	town := t.TempDir()
+	rig := filepath.Join(town, "gp")
+	writeFile(t, filepath.Join(town, "settings", "config.json"),
+		`{"type":"town-settings","version":1,"cost_alerts":{"daily_budget_usd":50}}`)
+	if err := (&alerts.State{Alerts: map[string]*alerts.Alert{
+		"budget-daily": {ID: "budget-daily", Status: alerts.StatusFiring, Value: 60, UpdatedAt: time.Now()},
+	}}).Save(town); err != nil {
+		t.Fatal(err)
+	}
+
+	// The town's exhausted budget is found from the rig path alone.
+	got := problems(t, BeforeSpawn(Spec{Session: "gt-gp-witness", Role: session.RoleWitness, RigPath: rig}))
+	if len(got) != 1 || got[0].Check != "budget" {
+		t.Errorf("problems = %+v, want the town's budget", got)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	stubEnv(t, true)
+	town := t.TempDir()
+	settings := filepath.Join(town, "mayor")
+	installSettings(t, settings)
+
+	decisions := Explain(Spec{Session: "hq-mayor", Role: session.RoleMayor, TownRoot: town, SettingsDir: settings})
+	want := map[string]string{ // check -> "" for pass, else skip reason fragment
+		"agent":    "",
+		"settings": "",
+		"checkout": "not checked",
+		"budget":   "interactive",
+		"slot":     "only polecats",
+	}
+	if len(decisions) != len(want) {
+		t.Fatalf("decisions = %+v, want %d", decisions, len(want))
+	}
+	for _, d := range decisions {
+		if d.Problem != nil {
+			t.Errorf("%s: unexpected problem %+v", d.Check, d.Problem)
+		}
+		if frag := want[d.Check]; (frag == "") != (d.Skipped == "") || !strings.Contains(d.Skipped, frag) {
+			t.Errorf("%s: skipped = %q, want %q", d.Check, d.Skipped, frag)
+		}
+	}
+}
+
+func TestRunMissingSettings(t *testing.T) {
+	stubEnv(t, true)
+	town := t.TempDir()
+	dir := filepath.Join(town, "crew")
+	writeFile(t, filepath.Join(dir, ".cursor", "hooks.json"), "{}")
+
+	got := problems(t, Run(Spec{Session: "gt-gp-crew-max", Role: session.RoleCrew, TownRoot: town, SettingsDir: dir}))
+	if len(got) != 1 || got[0].Check != "settings" || !strings.Contains(got[0].Message, "gastown.mdc") {
+		t.Errorf("problems = %+v, want missing gastown.mdc", got)
+	}
+}
+
+func TestCheckCheckout(t *testing.T) {
+	wrongBranch := newRepo(t, "polecat/Nux-abc")
+
+	detached := newRepo(t, "")
+	git(t, detached, "checkout", "-q", "--detach")
+
+	conflicted := newRepo(t, "polecat/Toast")
+	git(t, conflicted, "checkout", "-q", "-b", "other")
+	writeFile(t, filepath.Join(conflicted, "a.txt"), "other\n")
+	git(t, conflicted, "commit", "-q", "-am", "other")
+	git(t, conflicted, "checkout", "-q", "polecat/Toast")
+	writeFile(t, filepath.Join(conflicted, "a.txt"), "toast\n")
+	git(t, conflicted, "commit", "-q", "-am", "toast")
+	_ = exec.Command("git", "-C", conflicted, "merge", "other").Run() // conflicts
+
+	dirty := newRepo(t, "polecat/Toast-x")
+	writeFile(t, filepath.Join(dirty, "a.txt"), "wip\n")
+
+	tests := []struct {
+		name, dir, want string
+	}{
+		{"wrong branch", wrongBranch, "expected polecat/Toast*"},
+		{"detached", detached, "detached HEAD"},
+		{"merge in progress", conflicted, "merge in progress"},
+		{"uncommitted changes allowed", dirty, ""},
+		{"not a repo", t.TempDir(), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := checkCheckout(Spec{WorkDir: tt.dir, BranchPrefix: "polecat/Toast"})
+			if tt.want == "" {
+				if p != nil {
+					t.Errorf("unexpected problem: %+v", p)
+				}
+				return
+			}
+			if p == nil || !strings.Contains(p.Message, tt.want) {
+				t.Errorf("problem = %+v, want message containing %q", p, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckBudget(t *testing.T) {
+	town := t.TempDir()
+	writeFile(t, filepath.Join(town, "settings", "config.json"),
+		`{"type":"town-settings","version":1,"cost_alerts":{"daily_budget_usd":50,"weekly_budget_usd":200}}`)
+	fixed := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+	origNow := now
+	t.Cleanup(func() { now = origNow })
+	now = func() time.Time { return fixed }
+
+	save := func(a map[string]*alerts.Alert) {
+		t.Helper()
+		if err := (&alerts.State{Alerts: a}).Save(town); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spec := Spec{Role: session.RolePolecat, TownRoot: town}
+
+	save(map[string]*alerts.Alert{
+		"budget-daily": {ID: "budget-daily", Status: alerts.StatusFiring, Value: 52.5, UpdatedAt: fixed.Add(-time.Hour)},
+	})
+	if p := checkBudget(spec); p == nil || !strings.Contains(p.Message, "daily budget exhausted ($52.50 of $50.00)") {
+		t.Errorf("exhausted daily: %+v", p)
+	}
+
+	save(map[string]*alerts.Alert{
+		"budget-daily":  {ID: "budget-daily", Status: alerts.StatusFiring, Value: 60, UpdatedAt: fixed.AddDate(0, 0, -1)},
+		"budget-weekly": {ID: "budget-weekly", Status: alerts.StatusFiring, Value: 170, UpdatedAt: fixed},
+	})
+	if p := checkBudget(spec); p != nil {
+		t.Errorf("stale daily alert and weekly under budget should pass: %+v", p)
+	}
+
+	save(map[string]*alerts.Alert{
+		"budget-weekly": {ID: "budget-weekly", Status: alerts.StatusAcknowledged, Value: 210, UpdatedAt: fixed},
+	})
+	if p := checkBudget(spec); p == nil || !strings.Contains(p.Fix, "weekly_budget_usd") {
+		t.Errorf("exhausted weekly: %+v", p)
+	}
+}
+
+func TestCheckSlot(t *testing.T) {
+	stubEnv(t, true, "gt-gp-Nux", "gt-gp-Slit", "gt-gp-Toast", "gt-gp-witness", "gt-gp-crew-max", "gt-other-Rictus")
+	spec := Spec{Session: "gt-gp-Toast", Role: session.RolePolecat, RigPath: "/town/gp", MaxPolecats: 2}
+
+	// Toast itself is excluded (restart); witness, crew and other rigs do not count.
+	if p := checkSlot(spec); p == nil || !strings.Contains(p.Message, "2 of 2") {
+		t.Errorf("full rig: %+v", p)
+	}
+	spec.MaxPolecats = 3
+	if p := checkSlot(spec); p != nil {
+		t.Errorf("slot free: %+v", p)
+	}
+	spec.MaxPolecats = 0
+	if p := checkSlot(spec); p != nil {
+		t.Errorf("no cap: %+v", p)
+	}
+}
@@ -0,0 +1,104 @@
+// Package progress implements the agent progress reporting protocol.
+//
+// Agents call 'gt progress report --percent N --note "..."' as they work.
+// The latest report is stored in <agent home>/.runtime/progress.json and
+// each report is logged as a progress event, so status displays and stall
+// detection read what the agent said instead of guessing from pane output.
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// File is the progress file within an agent's .runtime directory.
+const File = "progress.json"
+
+// DefaultStallAfter is how long an agent may go without reporting progress
+// on unfinished work before it is considered stalled.
+const DefaultStallAfter = 30 * time.Minute
+
+// Report is an agent's latest progress report on its hooked work.
+type Report struct {
+	Agent      string    `json:"agent"`
+	Bead       string    `json:"bead,omitempty"`
+	Percent    int       `json:"percent"`
+	Note       string    `json:"note,omitempty"`
+	StartedAt  time.Time `json:"started_at"` // First report on this bead
+	ReportedAt time.Time `json:"reported_at"`
+
+	// StallNotifiedAt is set when the witness has been told this report
+	// went stale; a newer report clears the stall.
+	StallNotifiedAt time.Time `json:"stall_notified_at,omitempty"`
+}
+
+// Done reports whether the agent reported the work complete.
+func (r *Report) Done() bool {
+	return r.Percent >= 100
+}
+
+// Age returns how long ago the report was made.
+func (r *Report) Age(now time.Time) time.Duration {
+	return now.Sub(r.ReportedAt)
+}
+
+// Stalled reports whether unfinished work has gone without a report for
+// longer than after.
+func (r *Report) Stalled(now time.Time, after time.Duration) bool {
+	return !r.Done() && r.Age(now) > after
+}
+
+// Next returns the report that follows r (which may be nil) when the agent
+// reports on bead. Reports on a new bead start over; otherwise a percent
+// below zero keeps the previous value.
+func Next(prev *Report, agent, bead string, percent int, note string, now time.Time) (*Report, error) {
+	if percent > 100 {
+		return nil, fmt.Errorf("percent must be between 0 and 100, got %d", percent)
+	}
+	r := &Report{Agent: agent, Bead: bead, Percent: percent, Note: note, StartedAt: now, ReportedAt: now}
+	if prev != nil && prev.Bead == bead {
+		r.StartedAt = prev.StartedAt
+		if percent < 0 {
+			r.Percent = prev.Percent
+		}
+	}
+	if r.Percent < 0 {
+		r.Percent = 0
+	}
+	return r, nil
+}
+
+// Path returns the progress file path for an agent home directory.
+func Path(homeDir string) string {
+	return filepath.Join(homeDir, ".runtime", File)
+}
+
+// Write saves a report into an agent home directory.
+func Write(homeDir string, r *Report) error {
+	path := Path(homeDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	return util.AtomicWriteJSON(path, r)
+}
+
+// Read loads an agent's latest report. Returns nil if it has not reported.
+func Read(homeDir string) (*Report, error) {
+	data, err := os.ReadFile(Path(homeDir)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing progress: %w", err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,75 @@
+package progress
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	later := start.Add(20 * time.Minute)
+
+	first, err := Next(nil, "gp/polecats/Toast", "gp-1", -1, "starting", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Percent != 0 || !first.StartedAt.Equal(start) {
+		t.Errorf("first report = %+v", first)
+	}
+
+	first.Percent = 40
+	note, err := Next(first, "gp/polecats/Toast", "gp-1", -1, "still testing", later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Percent != 40 || !note.StartedAt.Equal(start) || !note.ReportedAt.Equal(later) {
+		t.Errorf("note-only report should keep percent and start: %+v", note)
+	}
+
+	other, err := Next(note, "gp/polecats/Toast", "gp-2", 10, "", later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Percent != 10 || !other.StartedAt.Equal(later) {
+		t.Errorf("new bead should start over: %+v", other)
+	}
+
+	if _, err := Next(nil, "a", "b", 101, "", start); err == nil {
+		t.Error("percent over 100 should be rejected")
+	}
+}
+
+func TestStalled(t *testing.T) {
+	now := time.Now()
+	r := &Report{Percent: 50, ReportedAt: now.Add(-45 * time.Minute)}
+	if !r.Stalled(now, DefaultStallAfter) {
+		t.Error("45m-old report on unfinished work should be stalled")
+	}
+	r.Percent = 100
+	if r.Stalled(now, DefaultStallAfter) {
+		t.Error("finished work is never stalled")
+	}
+	r = &Report{Percent: 50, ReportedAt: now.Add(-5 * time.Minute)}
+	if r.Stalled(now, DefaultStallAfter) {
+		t.Error("recent report should not be stalled")
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	dir := t.TempDir()
+	if r, err := Read(dir); err != nil || r != nil {
+		t.Fatalf("Read before any report = %v, %v", r, err)
+	}
+
+	want := &Report{Agent: "gp/polecats/Toast", Bead: "gp-1", Percent: 60, Note: "tests", ReportedAt: time.Now().UTC().Truncate(time.Second)}
+	if err := Write(dir, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Bead != want.Bead || got.Percent != want.Percent || !got.ReportedAt.Equal(want.ReportedAt) {
+		t.Errorf("Read = %+v, want %+v", got, want)
+	}
+}
@@ -15,11 +15,14 @@ import (
 	"time"
 
 	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/git"
 	"github.com/cursorworkshop/cursor-gastown/internal/mail"
 	"github.com/cursorworkshop/cursor-gastown/internal/mrqueue"
 	"github.com/cursorworkshop/cursor-gastown/internal/protocol"
 	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+	"github.com/cursorworkshop/cursor-gastown/internal/testledger"
+	"github.com/cursorworkshop/cursor-gastown/internal/tracker"
 )
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -48,6 +51,11 @@ type MergeQueueConfig struct {
 	// RetryFlakyTests is the number of times to retry flaky tests.
 	RetryFlakyTests int `json:"retry_flaky_tests"`
 
+	// RequireGreenRun gates merges on the rig's test ledger: a branch merges
+	// only if its latest recorded run passed within this window. Zero
+	// disables the gate.
+	RequireGreenRun time.Duration `json:"require_green_run"`
+
 	// PollInterval is how often to check for new MRs.
 	PollInterval time.Duration `json:"poll_interval"`
 
@@ -150,6 +158,7 @@ func (e *Engineer) LoadConfig() error {
 		TestCommand          *string `json:"test_command"`
 		DeleteMergedBranches *bool   `json:"delete_merged_branches"`
 		RetryFlakyTests      *int    `json:"retry_flaky_tests"`
+		RequireGreenRun      *string `json:"require_green_run"`
 		PollInterval         *string `json:"poll_interval"`
 		MaxConcurrent        *int    `json:"max_concurrent"`
 	}
@@ -193,6 +202,13 @@ func (e *Engineer) LoadConfig() error {
 		}
 		e.config.PollInterval = dur
 	}
+	if mqRaw.RequireGreenRun != nil && *mqRaw.RequireGreenRun != "" {
+		dur, err := time.ParseDuration(*mqRaw.RequireGreenRun)
+		if err != nil {
+			return fmt.Errorf("invalid require_green_run %q: %w", *mqRaw.RequireGreenRun, err)
+		}
+		e.config.RequireGreenRun = dur
+	}
 
 	return nil
 }
@@ -209,6 +225,7 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
+	Protected   []string // Changed protected paths awaiting approval
 }
 
 // ProcessMR processes a single merge request from a beads issue.
@@ -283,10 +300,26 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Step 3.5: Hold changes to the rig's protected paths until approved
+	protected, err := e.unapprovedProtectedFiles(branch, target)
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("protected path check failed: %v", err),
+		}
+	}
+	if len(protected) > 0 {
+		return ProcessResult{
+			Success:   false,
+			Protected: protected,
+			Error:     fmt.Sprintf("unapproved changes to protected paths: %s", strings.Join(protected, ", ")),
+		}
+	}
+
 	// Step 4: Run tests if configured
 	if e.config.RunTests && e.config.TestCommand != "" {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
+		result := e.runTests(ctx, branch)
 		if !result.Success {
 			return ProcessResult{
 				Success:     false,
@@ -297,6 +330,24 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
 	}
 
+	// Step 4.5: Require a recent green run in the rig's test ledger
+	if e.config.RequireGreenRun > 0 {
+		runs, err := testledger.Load(e.rig.Path)
+		if err != nil {
+			return ProcessResult{
+				Success: false,
+				Error:   fmt.Sprintf("reading test ledger: %v", err),
+			}
+		}
+		if err := testledger.RequireGreen(runs, branch, e.config.RequireGreenRun, time.Now()); err != nil {
+			return ProcessResult{
+				Success:     false,
+				TestsFailed: true,
+				Error:       err.Error(),
+			}
+		}
+	}
+
 	// Step 5: Perform the actual merge
 	mergeMsg := fmt.Sprintf("Merge %s into %s", branch, target)
 	if sourceIssue != "" {
@@ -343,8 +394,23 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 }
 
-// runTests runs the configured test command and returns the result.
-func (e *Engineer) runTests(ctx context.Context) ProcessResult {
+// unapprovedProtectedFiles returns the files under the rig's protected
+// paths that branch changes without an approval (see gt rig protected).
+func (e *Engineer) unapprovedProtectedFiles(branch, target string) ([]string, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path))
+	if err != nil || settings.ProtectedPaths == nil {
+		return nil, nil // No rig settings: nothing is protected
+	}
+	scans, err := rig.ScanProtected(e.rig, e.git, target, settings.ProtectedPaths, []string{branch})
+	if err != nil || len(scans) == 0 {
+		return nil, err
+	}
+	return scans[0].Unapproved(), nil
+}
+
+// runTests runs the configured test command and returns the result. The run
+// is recorded in the rig's test ledger against branch.
+func (e *Engineer) runTests(ctx context.Context, branch string) ProcessResult {
 	if e.config.TestCommand == "" {
 		return ProcessResult{Success: true}
 	}
@@ -355,6 +421,7 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		maxRetries = 1
 	}
 
+	start := time.Now()
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
@@ -371,6 +438,7 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 
 		err := cmd.Run()
 		if err == nil {
+			e.recordTestRun(branch, true, attempt-1, time.Since(start))
 			return ProcessResult{Success: true}
 		}
 		lastErr = err
@@ -384,6 +452,7 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		}
 	}
 
+	e.recordTestRun(branch, false, maxRetries-1, time.Since(start))
 	return ProcessResult{
 		Success:     false,
 		TestsFailed: true,
@@ -391,6 +460,25 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 }
 
+// recordTestRun adds a merge-queue test run to the rig's test ledger.
+// Ledger errors are warnings and never fail the merge.
+func (e *Engineer) recordTestRun(branch string, passed bool, retries int, duration time.Duration) {
+	run := testledger.Run{
+		Suite:      "merge-queue",
+		Passed:     passed,
+		DurationMS: duration.Milliseconds(),
+		Retries:    retries,
+		Branch:     branch,
+		Actor:      e.rig.Name + "/refinery",
+	}
+	if commit, err := e.git.Rev(branch); err == nil {
+		run.Commit = commit
+	}
+	if err := testledger.Record(e.rig.Path, run); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record test run: %v\n", err)
+	}
+}
+
 // handleSuccess handles a successful merge completion.
 // Steps:
 // 1. Update MR with merge_commit SHA
@@ -426,6 +514,7 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 		} else {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Closed source issue: %s\n", mrFields.SourceIssue)
 		}
+		e.closeTrackerIssue(mrFields.SourceIssue, mr.ID, result.MergeCommit)
 	}
 
 	// 3.5. Clear agent bead's active_mr reference (traceability cleanup)
@@ -448,6 +537,37 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 	_, _ = fmt.Fprintf(e.output, "[Engineer] [OK] Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
+// closeTrackerIssue closes the external tracker issue a merged source issue
+// was imported from (see gt tracker import). Rigs without a tracker are
+// skipped; tracker errors are warnings and never fail the merge.
+func (e *Engineer) closeTrackerIssue(sourceIssue, mrID, mergeCommit string) {
+	t, err := tracker.ForRig(e.workDir)
+	if err != nil {
+		if !errors.Is(err, tracker.ErrNotConfigured) {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: issue tracker unavailable: %v\n", err)
+		}
+		return
+	}
+	issue, err := e.beads.Show(sourceIssue)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to read source issue %s: %v\n", sourceIssue, err)
+		return
+	}
+	key, ok := tracker.LinkedKey(t, issue.Labels)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	msg := fmt.Sprintf("Merged in %s (commit %s).", mrID, mergeCommit)
+	if err := t.Close(ctx, key, msg); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close %s issue %s: %v\n", t.Name(), key, err)
+		return
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Closed %s issue: %s\n", t.Name(), key)
+}
+
 // handleFailure handles a failed merge request.
 // Reopens the MR for rework and logs the failure.
 func (e *Engineer) handleFailure(mr *beads.Issue, result ProcessResult) {
@@ -536,6 +656,7 @@ func (e *Engineer) handleSuccessFromQueue(mr *mrqueue.MR, result ProcessResult)
 		} else {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Closed source issue: %s\n", mr.SourceIssue)
 		}
+		e.closeTrackerIssue(mr.SourceIssue, mr.ID, result.MergeCommit)
 	}
 
 	// 1.5. Clear agent bead's active_mr reference (traceability cleanup)
@@ -579,6 +700,8 @@ func (e *Engineer) handleFailureFromQueue(mr *mrqueue.MR, result ProcessResult)
 		failureType = "conflict"
 	} else if result.TestsFailed {
 		failureType = "tests"
+	} else if len(result.Protected) > 0 {
+		failureType = "protected"
 	}
 	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
 	if err := e.router.Send(msg); err != nil {
@@ -604,15 +727,93 @@ func (e *Engineer) handleFailureFromQueue(mr *mrqueue.MR, result ProcessResult)
 		}
 	}
 
+	// Changes to protected paths wait for an approver; block the MR on an
+	// approval task so the queue moves on
+	if len(result.Protected) > 0 {
+		taskID, err := e.requestProtectedApproval(mr, result.Protected)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to request protected path approval: %v\n", err)
+		} else if err := e.mrQueue.SetBlockedBy(mr.ID, taskID); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to block MR on approval task: %v\n", err)
+		} else {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] MR %s blocked on approval task %s\n", mr.ID, taskID)
+		}
+	}
+
 	// Log the failure - MR stays in queue but may be blocked
 	_, _ = fmt.Fprintf(e.output, "[Engineer] [X] Failed: %s - %s\n", mr.ID, result.Error)
-	if mr.BlockedBy != "" {
+	if len(result.Protected) > 0 {
+		_, _ = fmt.Fprintln(e.output, "[Engineer] MR held for protected path approval - queue continues to next MR")
+	} else if mr.BlockedBy != "" {
 		_, _ = fmt.Fprintln(e.output, "[Engineer] MR blocked pending conflict resolution - queue continues to next MR")
 	} else {
 		_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue for retry")
 	}
 }
 
+// requestProtectedApproval opens a task asking the rig's approver to
+// approve a branch's changes to protected paths, and mails the approver.
+// Returns the task's ID for blocking the MR until it is approved.
+func (e *Engineer) requestProtectedApproval(mr *mrqueue.MR, files []string) (string, error) {
+	approver := config.DefaultProtectedPathsApprover
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path)); err == nil {
+		approver = settings.ProtectedPaths.ApproverAddress()
+	}
+
+	var list strings.Builder
+	for _, f := range files {
+		list.WriteString("- " + f + "\n")
+	}
+	description := fmt.Sprintf(`Branch %s changes protected paths of rig %s.
+
+## Metadata
+- Original MR: %s
+- Branch: %s
+- Worker: %s
+- Original issue: %s
+
+## Protected files changed
+%s
+## Instructions
+Review the changes: git diff %s...%s -- <file>
+
+- Approve: gt rig protected approve %s %s --reason "<why>"
+  (records the approval and closes this task; the MR re-enters the queue)
+- Reject: gt mq reject %s %s --reason "<why>"`,
+		mr.Branch, e.rig.Name,
+		mr.ID, mr.Branch, mr.Worker, mr.SourceIssue,
+		list.String(),
+		mr.Target, mr.Branch,
+		e.rig.Name, mr.Branch,
+		e.rig.Name, mr.Branch,
+	)
+
+	task, err := e.beads.Create(beads.CreateOptions{
+		Title:       rig.ProtectedApprovalTitlePrefix + mr.Branch,
+		Type:        "task",
+		Priority:    mr.Priority,
+		Description: description,
+		Actor:       e.rig.Name + "/refinery",
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating approval task: %w", err)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Created protected path approval task: %s\n", task.ID)
+
+	msg := mail.NewMessage(
+		e.rig.Name+"/refinery",
+		approver,
+		fmt.Sprintf("APPROVAL_NEEDED %s", mr.Branch),
+		description+"\n\nTask: "+task.ID,
+	)
+	msg.Priority = mail.PriorityHigh
+	msg.Type = mail.TypeTask
+	if err := e.router.Send(msg); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to mail %s for approval: %v\n", approver, err)
+	}
+	return task.ID, nil
+}
+
 // createConflictResolutionTask creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be dispatched to an available polecat.
 // Returns the created task's ID for blocking the MR until resolution.
@@ -70,12 +70,13 @@ func TestEngineer_LoadConfig_WithMergeQueue(t *testing.T) {
 		"version": 1,
 		"name":    "test-rig",
 		"merge_queue": map[string]interface{}{
-			"enabled":        true,
-			"target_branch":  "develop",
-			"poll_interval":  "10s",
-			"max_concurrent": 2,
-			"run_tests":      false,
-			"test_command":   "make test",
+			"enabled":           true,
+			"target_branch":     "develop",
+			"poll_interval":     "10s",
+			"max_concurrent":    2,
+			"run_tests":         false,
+			"test_command":      "make test",
+			"require_green_run": "24h",
 		},
 	}
 
@@ -111,6 +112,9 @@ func TestEngineer_LoadConfig_WithMergeQueue(t *testing.T) {
 	if e.config.TestCommand != "make test" {
 		t.Errorf("expected TestCommand 'make test', got %q", e.config.TestCommand)
 	}
+	if e.config.RequireGreenRun != 24*time.Hour {
+		t.Errorf("expected RequireGreenRun 24h, got %v", e.config.RequireGreenRun)
+	}
 
 	// Check that defaults are preserved for unspecified fields
 	if e.config.OnConflict != "assign_back" {
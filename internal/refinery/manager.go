@@ -18,6 +18,7 @@ import (
 	"github.com/cursorworkshop/cursor-gastown/internal/events"
 	"github.com/cursorworkshop/cursor-gastown/internal/mail"
 	"github.com/cursorworkshop/cursor-gastown/internal/mrqueue"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
 	"github.com/cursorworkshop/cursor-gastown/internal/rig"
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
@@ -173,6 +174,15 @@ func (m *Manager) Start(foreground bool) error {
 		return fmt.Errorf("ensuring Cursor settings: %w", err)
 	}
 
+	if err := preflight.BeforeSpawn(preflight.Spec{
+		Session:     sessionID,
+		Role:        session.RoleRefinery,
+		RigPath:     m.rig.Path,
+		SettingsDir: refineryParentDir,
+	}); err != nil {
+		return err
+	}
+
 	if err := t.NewSession(sessionID, refineryRigDir); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
@@ -239,7 +249,7 @@ func (m *Manager) Start(foreground bool) error {
 	// Send the propulsion nudge to trigger autonomous patrol execution.
 	// Wait for beacon to be fully processed (needs to be separate prompt)
 	time.Sleep(2 * time.Second)
-	_ = t.NudgeSession(sessionID, session.PropulsionNudgeForRole("refinery", refineryRigDir)) // Non-fatal
+	_ = t.NudgeSession(sessionID, session.PropulsionNudgeWithState("refinery", refineryRigDir, m.StartupState())) // Non-fatal
 
 	return nil
 }
@@ -496,6 +506,7 @@ func (m *Manager) completeMR(mr *MergeRequest, closeReason CloseReason, errMsg s
 		switch closeReason {
 		case CloseReasonMerged:
 			ref.LastMergeAt = &now
+			_ = events.LogFeed(events.TypeMerged, actor, events.MergePayload(mr.ID, mr.Worker, mr.Branch, ""))
 		case CloseReasonSuperseded:
 			// Emit merge_skipped event
 			_ = events.LogFeed(events.TypeMergeSkipped, actor, events.MergePayload(mr.ID, mr.Worker, mr.Branch, "superseded"))
@@ -0,0 +1,110 @@
+package refinery
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/mrqueue"
+)
+
+// failureWindow is how far back the startup state looks for merge failures.
+const failureWindow = 24 * time.Hour
+
+// maxListedFailures caps how many failed MRs the startup state names.
+const maxListedFailures = 3
+
+// StartupState summarizes the merge queue for the refinery's opening
+// prompt (see session.PropulsionNudgeWithState): queue depth, an MR left
+// in progress by the previous session, recent merge failures, and the last
+// merge. Each item is a short clause; unreadable sources are left out.
+func (m *Manager) StartupState() []string {
+	var state []string
+
+	if mrs, err := mrqueue.New(m.rig.Path).List(); err == nil {
+		state = append(state, queueState(mrs))
+	}
+
+	ref, _ := m.loadState()
+	if ref != nil && ref.CurrentMR != nil {
+		state = append(state, fmt.Sprintf("%s (%s) was in progress when the last session stopped",
+			ref.CurrentMR.ID, ref.CurrentMR.Branch))
+	}
+
+	logger := mrqueue.NewEventLoggerFromRig(m.rig.Path)
+	if evs, err := logger.ReadEvents(time.Now().Add(-failureWindow)); err == nil {
+		if s := failureState(evs); s != "" {
+			state = append(state, s)
+		}
+	}
+
+	if ref != nil && ref.LastMergeAt != nil {
+		state = append(state, "last merge "+formatAge(*ref.LastMergeAt))
+	}
+	return state
+}
+
+// queueState describes the queue depth and its oldest MR.
+func queueState(mrs []*mrqueue.MR) string {
+	if len(mrs) == 0 {
+		return "merge queue empty"
+	}
+	var oldest *mrqueue.MR
+	blocked := 0
+	for _, mr := range mrs {
+		if mr.BlockedBy != "" {
+			blocked++
+		}
+		if oldest == nil || mr.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = mr
+		}
+	}
+	s := fmt.Sprintf("%d MR(s) queued", len(mrs))
+	if blocked > 0 {
+		s += fmt.Sprintf(", %d blocked on conflict resolution", blocked)
+	}
+	s += fmt.Sprintf(", oldest %s queued %s", oldest.ID, formatAge(oldest.CreatedAt))
+	if oldest.Worker != "" {
+		s += " by " + oldest.Worker
+	}
+	return s
+}
+
+// failureState counts merge_failed events and names the latest failure of
+// the most recently failed MRs.
+func failureState(evs []mrqueue.Event) string {
+	var failed []mrqueue.Event
+	for _, ev := range evs {
+		if ev.Type == mrqueue.EventMergeFailed {
+			failed = append(failed, ev)
+		}
+	}
+	if len(failed) == 0 {
+		return ""
+	}
+
+	var named []string
+	seen := make(map[string]bool)
+	for i := len(failed) - 1; i >= 0 && len(named) < maxListedFailures; i-- {
+		ev := failed[i]
+		if seen[ev.MRID] {
+			continue
+		}
+		seen[ev.MRID] = true
+		if reason := truncate(strings.Join(strings.Fields(ev.Reason), " "), 60); reason != "" {
+			named = append(named, fmt.Sprintf("%s: %s", ev.MRID, reason))
+		} else {
+			named = append(named, ev.MRID)
+		}
+	}
+	return fmt.Sprintf("%d merge failure(s) in the last 24h (%s)", len(failed), strings.Join(named, ", "))
+}
+
+// truncate shortens s to at most maxLen runes, marking the cut with "…".
+func truncate(s string, maxLen int) string {
+	r := []rune(s)
+	if len(r) <= maxLen {
+		return s
+	}
+	return string(r[:maxLen-1]) + "…"
+}
@@ -0,0 +1,65 @@
+package refinery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/mrqueue"
+)
+
+func TestStartupState(t *testing.T) {
+	mgr, rigPath := setupTestManager(t)
+
+	if got := mgr.StartupState(); len(got) != 1 || got[0] != "merge queue empty" {
+		t.Errorf("StartupState on an idle rig = %q, want just an empty queue", got)
+	}
+
+	q := mrqueue.New(rigPath)
+	now := time.Now()
+	for _, mr := range []*mrqueue.MR{
+		{ID: "mr-new", Branch: "polecat/nux", Worker: "nux", CreatedAt: now.Add(-10 * time.Minute)},
+		{ID: "mr-old", Branch: "polecat/toast", Worker: "toast", CreatedAt: now.Add(-3 * time.Hour), BlockedBy: "gt-task"},
+	} {
+		if err := q.Submit(mr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logger := mrqueue.NewEventLoggerFromRig(rigPath)
+	for _, ev := range []mrqueue.Event{
+		{Timestamp: now.Add(-48 * time.Hour), Type: mrqueue.EventMergeFailed, MRID: "mr-ancient", Reason: "too old to report"},
+		{Timestamp: now.Add(-2 * time.Hour), Type: mrqueue.EventMergeFailed, MRID: "mr-old", Reason: "conflict"},
+		{Timestamp: now.Add(-1 * time.Hour), Type: mrqueue.EventMergeFailed, MRID: "mr-old", Reason: "tests failed:\n  TestLogin"},
+		{Timestamp: now.Add(-30 * time.Minute), Type: mrqueue.EventMerged, MRID: "mr-done"},
+	} {
+		if err := logger.LogEvent(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lastMerge := now.Add(-30 * time.Minute)
+	ref := &Refinery{
+		RigName:     "testrig",
+		CurrentMR:   &MergeRequest{ID: "mr-half", Branch: "polecat/ace"},
+		LastMergeAt: &lastMerge,
+	}
+	data, _ := json.Marshal(ref)
+	if err := os.WriteFile(filepath.Join(rigPath, ".runtime", "refinery.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := mgr.StartupState()
+	want := []string{
+		"2 MR(s) queued, 1 blocked on conflict resolution, oldest mr-old queued 3h ago by toast",
+		"mr-half (polecat/ace) was in progress when the last session stopped",
+		"2 merge failure(s) in the last 24h (mr-old: tests failed: TestLogin)",
+		"last merge 30m ago",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("StartupState =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
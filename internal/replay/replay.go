@@ -0,0 +1,322 @@
+// Package replay records the tool calls an agent makes during a session and
+// reads them back for step-through review.
+//
+// Cursor hooks (afterShellExecution, afterFileEdit, afterMCPExecution) pipe
+// their input to `gt replay capture`, which appends one Step per call to
+// <town>/.runtime/replay/<session-id>.jsonl.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dir is the replay log directory, relative to the town root.
+const Dir = ".runtime/replay"
+
+// Step kinds, one per captured hook.
+const (
+	KindShell = "shell" // afterShellExecution
+	KindEdit  = "edit"  // afterFileEdit
+	KindMCP   = "mcp"   // afterMCPExecution
+)
+
+// ErrNoSession is returned when no replay log matches a session ID.
+var ErrNoSession = errors.New("no captured steps for session")
+
+// Edit is a single string replacement applied to a file.
+type Edit struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Step is one captured tool call.
+type Step struct {
+	Time  time.Time `json:"ts"`
+	Kind  string    `json:"kind"`
+	Actor string    `json:"actor,omitempty"`
+	Cwd   string    `json:"cwd,omitempty"`
+
+	// Shell steps
+	Command string `json:"command,omitempty"`
+	Output  string `json:"output,omitempty"`
+
+	// Edit steps
+	File  string `json:"file,omitempty"`
+	Edits []Edit `json:"edits,omitempty"`
+
+	// MCP steps
+	Tool   string `json:"tool,omitempty"`
+	Input  string `json:"input,omitempty"`
+	Result string `json:"result,omitempty"`
+
+	// DurationMS is how long the call took, when the hook reports it.
+	DurationMS int64 `json:"duration_ms,omitempty"`
+}
+
+// Summary describes one captured session.
+type Summary struct {
+	SessionID string    `json:"session_id"`
+	Actor     string    `json:"actor,omitempty"`
+	Steps     int       `json:"steps"`
+	First     time.Time `json:"first"`
+	Last      time.Time `json:"last"`
+}
+
+// hookInput is the union of the Cursor hook payload fields we capture.
+type hookInput struct {
+	SessionID      string          `json:"session_id"`
+	ConversationID string          `json:"conversation_id"`
+	Cwd            string          `json:"cwd"`
+	Command        string          `json:"command"`
+	Output         string          `json:"output"`
+	Duration       int64           `json:"duration"`
+	FilePath       string          `json:"file_path"`
+	Edits          []hookEdit      `json:"edits"`
+	ToolName       string          `json:"tool_name"`
+	ToolInput      json.RawMessage `json:"tool_input"`
+	ResultJSON     json.RawMessage `json:"result_json"`
+}
+
+type hookEdit struct {
+	OldString string `json:"old_string"`
+	NewString string `json:"new_string"`
+}
+
+// ParseHookInput converts a Cursor hook payload into a Step. The session ID
+// is taken from the payload (session_id, then conversation_id); it is empty
+// when the payload carries neither.
+func ParseHookInput(kind string, data []byte, now time.Time) (Step, string, error) {
+	var in hookInput
+	if err := json.Unmarshal(data, &in); err != nil {
+		return Step{}, "", fmt.Errorf("parsing hook input: %w", err)
+	}
+
+	step := Step{Time: now.UTC(), Kind: kind, Cwd: in.Cwd, DurationMS: in.Duration}
+	switch kind {
+	case KindShell:
+		step.Command = in.Command
+		step.Output = in.Output
+	case KindEdit:
+		step.File = in.FilePath
+		for _, e := range in.Edits {
+			step.Edits = append(step.Edits, Edit{Old: e.OldString, New: e.NewString})
+		}
+	case KindMCP:
+		step.Tool = in.ToolName
+		step.Input = rawString(in.ToolInput)
+		step.Result = rawString(in.ResultJSON)
+	default:
+		return Step{}, "", fmt.Errorf("unknown step kind %q", kind)
+	}
+
+	sessionID := in.SessionID
+	if sessionID == "" {
+		sessionID = in.ConversationID
+	}
+	return step, sessionID, nil
+}
+
+// rawString returns a JSON value as a string, unquoting JSON strings.
+func rawString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// LogPath returns the replay log path for a session.
+func LogPath(townRoot, sessionID string) string {
+	return filepath.Join(townRoot, Dir, sessionID+".jsonl")
+}
+
+// mutex serializes appends from within one process.
+var mutex sync.Mutex
+
+// Record appends a step to the session's replay log.
+func Record(townRoot, sessionID string, step Step) error {
+	if sessionID == "" || strings.ContainsAny(sessionID, `/\`) || strings.HasPrefix(sessionID, ".") {
+		return fmt.Errorf("invalid session ID %q", sessionID)
+	}
+
+	data, err := json.Marshal(step)
+	if err != nil {
+		return fmt.Errorf("marshaling step: %w", err)
+	}
+	data = append(data, '\n')
+
+	path := LogPath(townRoot, sessionID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating replay directory: %w", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: replay log is operational data
+	if err != nil {
+		return fmt.Errorf("opening replay log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing step: %w", err)
+	}
+	return nil
+}
+
+// Resolve finds the captured session matching an ID or unique ID prefix.
+func Resolve(townRoot, sessionID string) (string, error) {
+	if _, err := os.Stat(LogPath(townRoot, sessionID)); err == nil {
+		return sessionID, nil
+	}
+
+	ids, err := sessionIDs(townRoot)
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	for _, id := range ids {
+		if strings.HasPrefix(id, sessionID) {
+			matches = append(matches, id)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w %s", ErrNoSession, sessionID)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("session prefix %q is ambiguous (%d matches)", sessionID, len(matches))
+	}
+}
+
+// Load reads a session's captured steps in timestamp order.
+// Lines that fail to parse are skipped.
+func Load(townRoot, sessionID string) ([]Step, error) {
+	f, err := os.Open(LogPath(townRoot, sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w %s", ErrNoSession, sessionID)
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var steps []Step
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var step Step
+		if err := json.Unmarshal(scanner.Bytes(), &step); err != nil {
+			continue
+		}
+		steps = append(steps, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return steps, err
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].Time.Before(steps[j].Time)
+	})
+	return steps, nil
+}
+
+// List summarizes all captured sessions, most recently active first.
+func List(townRoot string) ([]Summary, error) {
+	ids, err := sessionIDs(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []Summary
+	for _, id := range ids {
+		steps, err := Load(townRoot, id)
+		if err != nil || len(steps) == 0 {
+			continue
+		}
+		summaries = append(summaries, Summary{
+			SessionID: id,
+			Actor:     steps[0].Actor,
+			Steps:     len(steps),
+			First:     steps[0].Time,
+			Last:      steps[len(steps)-1].Time,
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Last.After(summaries[j].Last)
+	})
+	return summaries, nil
+}
+
+// sessionIDs returns the IDs of all sessions with a replay log.
+func sessionIDs(townRoot string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(townRoot, Dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ids []string
+	for _, e := range entries {
+		if id, ok := strings.CutSuffix(e.Name(), ".jsonl"); ok && !e.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// DiffLines renders an edit as removed ("-") and added ("+") lines, with
+// the lines common to the start and end of both sides shown as context.
+func DiffLines(e Edit) []string {
+	oldLines := splitLines(e.Old)
+	newLines := splitLines(e.New)
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	var out []string
+	for _, l := range oldLines[:prefix] {
+		out = append(out, "  "+l)
+	}
+	for _, l := range oldLines[prefix : len(oldLines)-suffix] {
+		out = append(out, "- "+l)
+	}
+	for _, l := range newLines[prefix : len(newLines)-suffix] {
+		out = append(out, "+ "+l)
+	}
+	for _, l := range oldLines[len(oldLines)-suffix:] {
+		out = append(out, "  "+l)
+	}
+	return out
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
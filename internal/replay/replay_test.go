@@ -0,0 +1,100 @@
+package replay
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseHookInput(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	step, sid, err := ParseHookInput(KindShell, []byte(`{"conversation_id":"c1","command":"go test ./...","output":"ok","duration":1200}`), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sid != "c1" || step.Command != "go test ./..." || step.Output != "ok" || step.DurationMS != 1200 {
+		t.Errorf("shell step = %+v, session %q", step, sid)
+	}
+
+	step, sid, err = ParseHookInput(KindEdit, []byte(`{"session_id":"s1","conversation_id":"c1","file_path":"main.go","edits":[{"old_string":"a","new_string":"b"}]}`), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sid != "s1" || step.File != "main.go" || !reflect.DeepEqual(step.Edits, []Edit{{Old: "a", New: "b"}}) {
+		t.Errorf("edit step = %+v, session %q", step, sid)
+	}
+
+	step, _, err = ParseHookInput(KindMCP, []byte(`{"tool_name":"search","tool_input":{"q":"x"},"result_json":"found"}`), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step.Tool != "search" || step.Input != `{"q":"x"}` || step.Result != "found" {
+		t.Errorf("mcp step = %+v", step)
+	}
+
+	if _, _, err := ParseHookInput("bogus", []byte(`{}`), now); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
+
+func TestRecordLoadList(t *testing.T) {
+	townRoot := t.TempDir()
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Recorded out of order; Load sorts by time
+	for _, step := range []Step{
+		{Time: t0.Add(time.Minute), Kind: KindEdit, File: "b.go"},
+		{Time: t0, Kind: KindShell, Command: "ls", Actor: "gastown/polecats/nux"},
+	} {
+		if err := Record(townRoot, "abc-123", step); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Record(townRoot, "def-456", Step{Time: t0.Add(time.Hour), Kind: KindShell}); err != nil {
+		t.Fatal(err)
+	}
+
+	steps, err := Load(townRoot, "abc-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || steps[0].Command != "ls" || steps[1].File != "b.go" {
+		t.Errorf("Load() = %+v", steps)
+	}
+
+	summaries, err := List(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 2 || summaries[0].SessionID != "def-456" || summaries[1].Steps != 2 {
+		t.Errorf("List() = %+v", summaries)
+	}
+
+	if id, err := Resolve(townRoot, "abc"); err != nil || id != "abc-123" {
+		t.Errorf("Resolve(abc) = %q, %v", id, err)
+	}
+	if _, err := Resolve(townRoot, "zzz"); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Resolve(zzz) error = %v, want ErrNoSession", err)
+	}
+	if err := Record(townRoot, "../escape", Step{}); err == nil {
+		t.Error("expected error for session ID with path separator")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := DiffLines(Edit{
+		Old: "func a() {\n\treturn 1\n}\n",
+		New: "func a() {\n\treturn 2\n}\n",
+	})
+	want := []string{"  func a() {", "- \treturn 1", "+ \treturn 2", "  }"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffLines() = %q, want %q", got, want)
+	}
+
+	// New file content: everything added
+	if got := DiffLines(Edit{New: "x\ny"}); !reflect.DeepEqual(got, []string{"+ x", "+ y"}) {
+		t.Errorf("DiffLines(new) = %q", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/wisp"
 )
 
@@ -70,10 +71,11 @@ func (r *Rig) GetConfigWithSource(key string) ConfigResult {
 		return ConfigResult{Value: val, Source: SourceBead}
 	}
 
-	// Layer 3: Town defaults
-	// Note: Town defaults for operational state would typically be in
-	// ~/gt/settings/config.json. For now, we skip directly to system defaults.
-	// Future: load from config.TownSettings
+	// Layer 3: Town defaults (settings/config.json rig_defaults, with the
+	// machine profile applied)
+	if val := townDefault(townRoot, key); val != nil {
+		return ConfigResult{Value: val, Source: SourceTown}
+	}
 
 	// Layer 4: System defaults
 	if val, ok := SystemDefaults[key]; ok {
@@ -119,7 +121,9 @@ func (r *Rig) GetIntConfig(key string) int {
 
 	// Get base value (town or system default)
 	base := 0
-	if val, ok := SystemDefaults[key]; ok {
+	if val := townDefault(townRoot, key); val != nil {
+		base = toInt(val)
+	} else if val, ok := SystemDefaults[key]; ok {
 		base = toInt(val)
 	}
 
@@ -160,6 +164,16 @@ func (r *Rig) GetStringConfig(key string) string {
 	}
 }
 
+// townDefault reads a rig config default from the town settings.
+// Returns nil if the town does not set one.
+func townDefault(townRoot, key string) interface{} {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return settings.RigDefaults[key]
+}
+
 // getBeadLabel reads a label value from the rig identity bead.
 // Returns nil if the rig bead doesn't exist or the label is not set.
 func (r *Rig) getBeadLabel(key string) interface{} {
@@ -274,3 +274,32 @@ func TestGetConfig_BeadLabel(t *testing.T) {
 		t.Logf("source is %s (expected SourceBead or SourceSystem)", result.Source)
 	}
 }
+
+func TestGetConfig_TownDefault(t *testing.T) {
+	tmpDir := t.TempDir()
+	rigPath := filepath.Join(tmpDir, "testrig")
+	settingsPath := filepath.Join(tmpDir, "settings", "config.json")
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"type": "town-settings", "version": 1, "rig_defaults": {"max_polecats": 3}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GASTOWN_PROFILE", "")
+
+	rig := &Rig{
+		Name: "testrig",
+		Path: rigPath,
+	}
+
+	result := rig.GetConfigWithSource("max_polecats")
+	if result.Source != SourceTown {
+		t.Errorf("expected source SourceTown, got %s", result.Source)
+	}
+	if got := rig.GetIntConfig("max_polecats"); got != 3 {
+		t.Errorf("expected max_polecats=3, got %d", got)
+	}
+	if got := rig.GetConfigWithSource("status"); got.Source != SourceSystem {
+		t.Errorf("keys the town does not set fall through to system defaults, got %s", got.Source)
+	}
+}
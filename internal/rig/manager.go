@@ -33,6 +33,7 @@ type RigConfig struct {
 	DefaultBranch string       `json:"default_branch,omitempty"` // main, master, etc.
 	CreatedAt     time.Time    `json:"created_at"`               // when rig was created
 	Beads         *BeadsConfig `json:"beads,omitempty"`
+	RulePacks     []string     `json:"rule_packs,omitempty"` // language rule packs detected in the repo (refreshed by gt rig sync)
 }
 
 // BeadsConfig represents beads configuration for the rig.
@@ -60,12 +61,16 @@ func NewManager(townRoot string, rigsConfig *config.RigsConfig, g *git.Git) *Man
 	}
 }
 
-// DiscoverRigs returns all rigs registered in the workspace.
+// DiscoverRigs returns all agent rigs registered in the workspace.
+// Mirror rigs are excluded; use DiscoverMirrors for those.
 // Rigs that fail to load are logged to stderr and skipped; partial results are returned.
 func (m *Manager) DiscoverRigs() ([]*Rig, error) {
 	var rigs []*Rig
 
 	for name, entry := range m.config.Rigs {
+		if entry.Mirror {
+			continue
+		}
 		rig, err := m.loadRig(name, entry)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to load rig %q: %v\n", name, err)
@@ -112,6 +117,12 @@ func (m *Manager) loadRig(name string, entry config.RigEntry) (*Rig, error) {
 		GitURL:    entry.GitURL,
 		LocalRepo: entry.LocalRepo,
 		Config:    entry.BeadsConfig,
+		Mirror:    entry.Mirror,
+	}
+
+	// Mirror rigs have only a read-only checkout, no agent directories
+	if entry.Mirror {
+		return rig, nil
 	}
 
 	// Scan for polecats
@@ -333,6 +344,15 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 	}
 	fmt.Printf("   [OK] Created mayor clone\n")
 
+	// Detect languages so agent rules include the matching rule packs.
+	if packs := cursor.DetectRulePacks(mayorRigPath); len(packs) > 0 {
+		rigConfig.RulePacks = packs
+		if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
+			return nil, fmt.Errorf("updating rig config with rule packs: %w", err)
+		}
+		fmt.Printf("   [OK] Detected rule packs: %s\n", strings.Join(packs, ", "))
+	}
+
 	// Check if source repo has .beads/ with its own prefix - if so, use that prefix.
 	// This ensures we use the project's existing beads database instead of creating a new one.
 	// Without this, routing would fail when trying to access existing issues because the
@@ -0,0 +1,181 @@
+package rig
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/git"
+)
+
+// MirrorCheckoutDir is the checkout directory inside a mirror rig.
+const MirrorCheckoutDir = "repo"
+
+// RigTypeMirror is the config.json type for read-only mirror rigs.
+const RigTypeMirror = "mirror"
+
+// AddMirrorOptions configures mirror rig creation.
+type AddMirrorOptions struct {
+	Name   string // Rig name (directory name)
+	GitURL string // Repository URL
+	Branch string // Branch to check out (defaults to the remote's default)
+}
+
+// MirrorCheckoutPath returns the read-only checkout inside a mirror rig.
+func MirrorCheckoutPath(rigPath string) string {
+	return filepath.Join(rigPath, MirrorCheckoutDir)
+}
+
+// AddMirror creates a reference-only rig: a single read-only checkout that
+// agents may browse for context. Mirror rigs have no beads, no patrol roles,
+// no agent settings, and are skipped by the daemon and agent-oriented checks.
+//
+// Structure:
+//
+//	<name>/
+//	├── config.json    # type: "mirror"
+//	└── repo/          # Read-only checkout
+func (m *Manager) AddMirror(opts AddMirrorOptions) (*Rig, error) {
+	if m.RigExists(opts.Name) {
+		return nil, ErrRigExists
+	}
+	if strings.ContainsAny(opts.Name, "-. ") {
+		return nil, fmt.Errorf("rig name %q contains invalid characters; hyphens, dots, and spaces are reserved for agent ID parsing", opts.Name)
+	}
+
+	rigPath := filepath.Join(m.townRoot, opts.Name)
+	if _, err := os.Stat(rigPath); err == nil {
+		return nil, fmt.Errorf("directory already exists: %s", rigPath)
+	}
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		return nil, fmt.Errorf("creating rig directory: %w", err)
+	}
+
+	success := false
+	defer func() {
+		if !success {
+			_ = os.RemoveAll(rigPath)
+		}
+	}()
+
+	checkout := MirrorCheckoutPath(rigPath)
+	fmt.Printf("  Cloning repository (this may take a moment)...\n")
+	if err := m.git.Clone(opts.GitURL, checkout); err != nil {
+		return nil, fmt.Errorf("cloning mirror: %w", err)
+	}
+	checkoutGit := git.NewGit(checkout)
+	if opts.Branch != "" {
+		if err := checkoutGit.Checkout(opts.Branch); err != nil {
+			return nil, fmt.Errorf("checking out %s: %w", opts.Branch, err)
+		}
+	}
+	branch, _ := checkoutGit.CurrentBranch()
+
+	if err := setTreeReadOnly(checkout); err != nil {
+		return nil, fmt.Errorf("marking checkout read-only: %w", err)
+	}
+	fmt.Printf("   [OK] Created read-only checkout (%s)\n", branch)
+
+	rigConfig := &RigConfig{
+		Type:          RigTypeMirror,
+		Version:       CurrentRigConfigVersion,
+		Name:          opts.Name,
+		GitURL:        opts.GitURL,
+		DefaultBranch: branch,
+		CreatedAt:     time.Now(),
+	}
+	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
+		return nil, fmt.Errorf("saving rig config: %w", err)
+	}
+
+	m.config.Rigs[opts.Name] = config.RigEntry{
+		GitURL:  opts.GitURL,
+		AddedAt: time.Now(),
+		Mirror:  true,
+	}
+
+	success = true
+	return m.loadRig(opts.Name, m.config.Rigs[opts.Name])
+}
+
+// SyncMirror fast-forwards a mirror rig's checkout to its upstream branch.
+func (m *Manager) SyncMirror(name string) error {
+	entry, ok := m.config.Rigs[name]
+	if !ok {
+		return ErrRigNotFound
+	}
+	if !entry.Mirror {
+		return fmt.Errorf("rig %q is not a mirror", name)
+	}
+
+	checkout := MirrorCheckoutPath(filepath.Join(m.townRoot, name))
+	g := git.NewGit(checkout)
+	branch, err := g.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("reading mirror branch: %w", err)
+	}
+
+	// Restore write access so git can update files, then lock again.
+	if err := setTreeWritable(checkout); err != nil {
+		return fmt.Errorf("unlocking checkout: %w", err)
+	}
+	pullErr := g.Pull("origin", branch)
+	if err := setTreeReadOnly(checkout); err != nil && pullErr == nil {
+		return fmt.Errorf("marking checkout read-only: %w", err)
+	}
+	return pullErr
+}
+
+// DiscoverMirrors returns all registered mirror rigs.
+func (m *Manager) DiscoverMirrors() ([]*Rig, error) {
+	var rigs []*Rig
+	for name, entry := range m.config.Rigs {
+		if !entry.Mirror {
+			continue
+		}
+		r, err := m.loadRig(name, entry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load mirror %q: %v\n", name, err)
+			continue
+		}
+		rigs = append(rigs, r)
+	}
+	return rigs, nil
+}
+
+// setTreeReadOnly removes write permission from every file in the working
+// tree (not .git). Directories stay writable so 'gt rig sync' can update.
+func setTreeReadOnly(root string) error {
+	return chmodTree(root, func(mode fs.FileMode) fs.FileMode { return mode &^ 0222 })
+}
+
+// setTreeWritable restores owner write permission on working tree files.
+func setTreeWritable(root string) error {
+	return chmodTree(root, func(mode fs.FileMode) fs.FileMode { return mode | 0200 })
+}
+
+func chmodTree(root string, change func(fs.FileMode) fs.FileMode) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return os.Chmod(path, change(info.Mode().Perm()))
+	})
+}
@@ -0,0 +1,94 @@
+package rig
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/git"
+)
+
+// createUpstreamRepo creates a local git repository with one commit.
+func createUpstreamRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# upstream\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+	return dir
+}
+
+func TestAddMirror(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root, rigsConfig := setupTestTown(t)
+	upstream := createUpstreamRepo(t)
+
+	// An agent rig alongside the mirror
+	createTestRig(t, root, "gastown")
+	rigsConfig.Rigs["gastown"] = config.RigEntry{GitURL: "git@github.com:test/gastown.git"}
+
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+	r, err := manager.AddMirror(AddMirrorOptions{Name: "upstream_docs", GitURL: upstream})
+	if err != nil {
+		t.Fatalf("AddMirror: %v", err)
+	}
+	if !r.Mirror || r.HasWitness || r.HasRefinery {
+		t.Errorf("mirror rig = %+v, want Mirror without agents", r)
+	}
+	if !rigsConfig.Rigs["upstream_docs"].Mirror {
+		t.Error("registry entry should be marked mirror")
+	}
+
+	readme := filepath.Join(MirrorCheckoutPath(r.Path), "README.md")
+	info, err := os.Stat(readme)
+	if err != nil {
+		t.Fatalf("checkout missing README: %v", err)
+	}
+	if info.Mode().Perm()&0222 != 0 {
+		t.Errorf("README mode = %v, want read-only", info.Mode().Perm())
+	}
+
+	cfg, err := LoadRigConfig(r.Path)
+	if err != nil || cfg.Type != RigTypeMirror {
+		t.Errorf("config.json type = %v (err %v), want %q", cfg, err, RigTypeMirror)
+	}
+
+	// Mirrors are excluded from agent rig discovery
+	rigs, _ := manager.DiscoverRigs()
+	for _, dr := range rigs {
+		if dr.Name == "upstream_docs" {
+			t.Error("DiscoverRigs should skip mirrors")
+		}
+	}
+	mirrors, _ := manager.DiscoverMirrors()
+	if len(mirrors) != 1 || mirrors[0].Name != "upstream_docs" {
+		t.Errorf("DiscoverMirrors = %v, want [upstream_docs]", mirrors)
+	}
+
+	if err := manager.SyncMirror("upstream_docs"); err != nil {
+		t.Errorf("SyncMirror: %v", err)
+	}
+	if info, _ := os.Stat(readme); info.Mode().Perm()&0222 != 0 {
+		t.Error("checkout should be read-only again after sync")
+	}
+	if err := manager.SyncMirror("gastown"); err == nil {
+		t.Error("SyncMirror on an agent rig should fail")
+	}
+}
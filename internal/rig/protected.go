@@ -0,0 +1,194 @@
+package rig
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/git"
+)
+
+// ProtectedApprovalTitlePrefix starts the title of the task the refinery
+// opens when a merge request changes protected paths. The MR is blocked on
+// the task until the changes are approved.
+const ProtectedApprovalTitlePrefix = "Approve protected path changes: "
+
+// ProtectedChange is a file a branch changes under a protected path.
+type ProtectedChange struct {
+	File     string `json:"file"`
+	Pattern  string `json:"pattern"` // The protected_paths pattern it matches
+	Approved bool   `json:"approved"`
+}
+
+// ProtectedScan lists the protected files one branch changes.
+type ProtectedScan struct {
+	Branch  string            `json:"branch"`
+	Changes []ProtectedChange `json:"changes"`
+}
+
+// Unapproved returns the changed protected files that have no approval.
+func (s ProtectedScan) Unapproved() []string {
+	var files []string
+	for _, c := range s.Changes {
+		if !c.Approved {
+			files = append(files, c.File)
+		}
+	}
+	return files
+}
+
+// ProtectedApproval records that a branch's changes to protected files
+// were approved. Stored one per line in <rig>/.runtime/protected-approvals.jsonl.
+type ProtectedApproval struct {
+	Time     time.Time `json:"ts"`
+	Branch   string    `json:"branch"`
+	Commit   string    `json:"commit"` // Branch head when approved
+	Files    []string  `json:"files"`
+	Approver string    `json:"approver,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// covers reports whether the approval applies to file on a branch whose
+// head is head. The approved commit must still be part of the branch, so an
+// approval does not carry over to a branch name reused for other work.
+func (a ProtectedApproval) covers(g *git.Git, branch, head, file string) bool {
+	if a.Branch != branch {
+		return false
+	}
+	found := false
+	for _, f := range a.Files {
+		if f == file {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+	if a.Commit == head {
+		return true
+	}
+	ok, err := g.IsAncestor(a.Commit, head)
+	return err == nil && ok
+}
+
+func protectedApprovalsPath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "protected-approvals.jsonl")
+}
+
+// RecordProtectedApproval appends an approval to the rig's approval log.
+// A zero Time is set to now.
+func RecordProtectedApproval(rigPath string, a ProtectedApproval) error {
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+	a.Time = a.Time.UTC()
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	path := protectedApprovalsPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: approval log is operational data
+	if err != nil {
+		return fmt.Errorf("opening approval log: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// LoadProtectedApprovals reads a rig's approvals, oldest first. A missing log
+// has no approvals; lines that fail to parse are skipped.
+func LoadProtectedApprovals(rigPath string) ([]ProtectedApproval, error) {
+	f, err := os.Open(protectedApprovalsPath(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var approvals []ProtectedApproval
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var a ProtectedApproval
+		if err := json.Unmarshal(scanner.Bytes(), &a); err != nil || a.Branch == "" {
+			continue
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, scanner.Err()
+}
+
+// ProtectedRepo returns the rig repo agent branches live in (the shared
+// .repo.git, or mayor/rig for older rigs) and the ref branches are compared
+// against: origin's default branch when fetched, else the local one.
+func ProtectedRepo(r *Rig) (*git.Git, string, error) {
+	var g *git.Git
+	if info, err := os.Stat(filepath.Join(r.Path, ".repo.git")); err == nil && info.IsDir() {
+		g = git.NewGitWithDir(filepath.Join(r.Path, ".repo.git"), "")
+	} else if _, err := os.Stat(filepath.Join(r.Path, "mayor", "rig")); err == nil {
+		g = git.NewGit(filepath.Join(r.Path, "mayor", "rig"))
+	} else {
+		return nil, "", fmt.Errorf("rig %s has no repo (neither .repo.git nor mayor/rig exists)", r.Name)
+	}
+	base := r.DefaultBranch()
+	if _, err := g.Rev("origin/" + base); err == nil {
+		base = "origin/" + base
+	}
+	return g, base, nil
+}
+
+// ScanProtected lists the protected files each branch changes since it left
+// base, marking the ones approved in the rig's approval log. Branches that
+// change no protected files are left out.
+func ScanProtected(r *Rig, g *git.Git, base string, cfg *config.ProtectedPathsConfig, branches []string) ([]ProtectedScan, error) {
+	if cfg == nil || len(cfg.Paths) == 0 {
+		return nil, nil
+	}
+	approvals, err := LoadProtectedApprovals(r.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading approvals: %w", err)
+	}
+
+	var scans []ProtectedScan
+	for _, branch := range branches {
+		changed, err := g.ChangedFiles(base, branch)
+		if err != nil {
+			return nil, fmt.Errorf("diffing %s: %w", branch, err)
+		}
+		head, err := g.Rev(branch)
+		if err != nil {
+			return nil, err
+		}
+		scan := ProtectedScan{Branch: branch}
+		for _, file := range changed {
+			pattern := cfg.Match(file)
+			if pattern == "" {
+				continue
+			}
+			change := ProtectedChange{File: file, Pattern: pattern}
+			for _, a := range approvals {
+				if a.covers(g, branch, head, file) {
+					change.Approved = true
+					break
+				}
+			}
+			scan.Changes = append(scan.Changes, change)
+		}
+		if len(scan.Changes) > 0 {
+			scans = append(scans, scan)
+		}
+	}
+	sort.Slice(scans, func(i, j int) bool { return scans[i].Branch < scans[j].Branch })
+	return scans, nil
+}
@@ -0,0 +1,74 @@
+package rig
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+func TestScanProtected(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	r := &Rig{Name: "gp", Path: t.TempDir()}
+	repo := filepath.Join(r.Path, "mayor", "rig")
+	if err := os.MkdirAll(filepath.Join(repo, "infra"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, repo, "init", "-q", "-b", "main")
+	commitFile(t, repo, "README.md", "hello\n")
+	gitIn(t, repo, "checkout", "-q", "-b", "polecat/Toast")
+	commitFile(t, repo, "infra/main.tf", "resource {}\n")
+	commitFile(t, repo, "app.go", "package app\n")
+	gitIn(t, repo, "checkout", "-q", "-b", "polecat/Nux", "main")
+	commitFile(t, repo, "docs.md", "docs\n")
+	gitIn(t, repo, "checkout", "-q", "main")
+
+	g, base, err := ProtectedRepo(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.ProtectedPathsConfig{Paths: []string{"infra/"}}
+	scan := func() []ProtectedScan {
+		t.Helper()
+		scans, err := ScanProtected(r, g, base, cfg, []string{"polecat/Nux", "polecat/Toast"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return scans
+	}
+
+	scans := scan()
+	if len(scans) != 1 || scans[0].Branch != "polecat/Toast" || !slices.Equal(scans[0].Unapproved(), []string{"infra/main.tf"}) {
+		t.Fatalf("scans = %+v, want polecat/Toast with infra/main.tf unapproved", scans)
+	}
+
+	head := gitIn(t, repo, "rev-parse", "polecat/Toast")
+	if err := RecordProtectedApproval(r.Path, ProtectedApproval{Branch: "polecat/Toast", Commit: head, Files: []string{"infra/main.tf"}}); err != nil {
+		t.Fatal(err)
+	}
+	if scans := scan(); len(scans) != 1 || len(scans[0].Unapproved()) != 0 {
+		t.Fatalf("after approval: scans = %+v, want nothing unapproved", scans)
+	}
+
+	// A later commit keeps the approval but its new protected file needs one
+	gitIn(t, repo, "checkout", "-q", "polecat/Toast")
+	commitFile(t, repo, "infra/vars.tf", "variable {}\n")
+	if scans := scan(); len(scans) != 1 || !slices.Equal(scans[0].Unapproved(), []string{"infra/vars.tf"}) {
+		t.Fatalf("after new commit: scans = %+v, want only infra/vars.tf unapproved", scans)
+	}
+
+	// A reused branch name does not inherit the approval
+	gitIn(t, repo, "checkout", "-q", "-B", "polecat/Toast", "main")
+	if err := os.MkdirAll(filepath.Join(repo, "infra"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, repo, "infra/main.tf", "resource { other }\n")
+	if scans := scan(); len(scans) != 1 || !slices.Equal(scans[0].Unapproved(), []string{"infra/main.tf"}) {
+		t.Fatalf("reused branch: scans = %+v, want infra/main.tf unapproved", scans)
+	}
+}
@@ -0,0 +1,53 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+)
+
+// settingsDirs are the rig directories that hold agent settings (.cursor/).
+var settingsDirs = []string{"witness", "refinery", "crew", "polecats"}
+
+// RefreshRulePacks re-detects the languages of a rig's repo (from the
+// mayor's clone), records the matching rule packs in config.json, and
+// installs them into every agent settings directory of the rig.
+// Returns the selected packs and whether they changed.
+func (m *Manager) RefreshRulePacks(name string) ([]string, bool, error) {
+	entry, ok := m.config.Rigs[name]
+	if !ok {
+		return nil, false, ErrRigNotFound
+	}
+	if entry.Mirror {
+		return nil, false, fmt.Errorf("rig %q is a mirror and has no agent rules", name)
+	}
+
+	rigPath := filepath.Join(m.townRoot, name)
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("loading rig config: %w", err)
+	}
+
+	packs := cursor.DetectRulePacks(filepath.Join(rigPath, "mayor", "rig"))
+	changed := !slices.Equal(packs, cfg.RulePacks)
+	if changed {
+		cfg.RulePacks = packs
+		if err := m.saveRigConfig(rigPath, cfg); err != nil {
+			return nil, false, fmt.Errorf("saving rig config: %w", err)
+		}
+	}
+
+	for _, dir := range settingsDirs {
+		settingsDir := filepath.Join(rigPath, dir)
+		if _, err := os.Stat(settingsDir); err != nil {
+			continue
+		}
+		if err := cursor.EnsureRulePacks(settingsDir, packs); err != nil {
+			return nil, false, fmt.Errorf("updating %s rules: %w", dir, err)
+		}
+	}
+	return packs, changed, nil
+}
@@ -0,0 +1,52 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/git"
+)
+
+func TestRefreshRulePacks(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	createTestRig(t, root, "gastown")
+	rigsConfig.Rigs["gastown"] = config.RigEntry{GitURL: "git@github.com:test/gastown.git"}
+	rigPath := filepath.Join(root, "gastown")
+
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+	if err := manager.saveRigConfig(rigPath, &RigConfig{Type: "rig", Version: 1, Name: "gastown", RulePacks: []string{"node"}}); err != nil {
+		t.Fatal(err)
+	}
+	mayorRig := filepath.Join(rigPath, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mayorRig, "go.mod"), []byte("module x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	packs, changed, err := manager.RefreshRulePacks("gastown")
+	if err != nil {
+		t.Fatalf("RefreshRulePacks: %v", err)
+	}
+	if !changed || !reflect.DeepEqual(packs, []string{"go"}) {
+		t.Errorf("packs = %v (changed %v), want [go] changed", packs, changed)
+	}
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil || !reflect.DeepEqual(cfg.RulePacks, []string{"go"}) {
+		t.Errorf("config rule_packs = %v, %v", cfg, err)
+	}
+	if _, err := os.Stat(filepath.Join(rigPath, "polecats", ".cursor", "rules", "gastown-go.mdc")); err != nil {
+		t.Errorf("go pack not installed for polecats: %v", err)
+	}
+
+	if _, changed, _ := manager.RefreshRulePacks("gastown"); changed {
+		t.Error("second refresh should report no change")
+	}
+	if _, _, err := manager.RefreshRulePacks("nope"); err != ErrRigNotFound {
+		t.Errorf("unknown rig err = %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package rig
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DefaultSearchLimit caps the matches a search returns when no limit is
+// given, so a common symbol does not flood an agent's context.
+const DefaultSearchLimit = 100
+
+// maxSearchFileSize is the largest file the built-in searcher reads.
+// ripgrep has no such limit but skips binary files the same way.
+const maxSearchFileSize = 1 << 20
+
+// maxSearchLineLength truncates long matched lines (minified code).
+const maxSearchLineLength = 300
+
+// SearchOptions configures a cross-rig code search.
+type SearchOptions struct {
+	Pattern    string   // Regular expression (or literal text with Fixed)
+	Rigs       []string // Rigs to search; empty searches every rig
+	Glob       string   // Only files matching this glob, e.g. "*.go"
+	IgnoreCase bool     // Case-insensitive matching
+	Fixed      bool     // Treat Pattern as literal text
+	Limit      int      // Maximum matches; 0 uses DefaultSearchLimit
+}
+
+// SearchMatch is a line matching a search.
+type SearchMatch struct {
+	Rig  string `json:"rig"`
+	Path string `json:"path"` // Relative to the rig's checkout
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// SearchResult holds the matches of a search, in rig then file order.
+type SearchResult struct {
+	Matches []SearchMatch `json:"matches"`
+
+	// Truncated is set when the limit was reached and more matches exist.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Skipped lists rigs that have no checkout to search.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// CheckoutPath returns the checkout searched for the rig's code: the
+// read-only checkout of a mirror rig, otherwise the mayor's clone (or the
+// refinery's for rigs without one). Returns "" if the rig has none.
+func (r *Rig) CheckoutPath() string {
+	candidates := []string{filepath.Join(r.Path, "mayor", "rig"), filepath.Join(r.Path, "refinery", "rig")}
+	if r.Mirror {
+		candidates = []string{MirrorCheckoutPath(r.Path)}
+	}
+	for _, dir := range candidates {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
+
+// Search finds lines matching opts.Pattern in the checkouts of rigs, so an
+// agent in one rig can find where a shared symbol is defined in another.
+// It runs ripgrep when installed, which honors .gitignore, and otherwise
+// walks the checkouts itself, skipping hidden directories and binary files.
+// Rigs are searched in name order, and files in path order.
+func Search(rigs []*Rig, opts SearchOptions) (*SearchResult, error) {
+	if opts.Pattern == "" {
+		return nil, errors.New("search pattern is empty")
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultSearchLimit
+	}
+	pattern := opts.Pattern
+	if opts.Fixed {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if opts.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search pattern: %w", err)
+	}
+
+	sorted := make([]*Rig, 0, len(rigs))
+	for _, r := range rigs {
+		if len(opts.Rigs) == 0 || slices.Contains(opts.Rigs, r.Name) {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	rg, _ := exec.LookPath("rg")
+	result := &SearchResult{}
+	for _, r := range sorted {
+		dir := r.CheckoutPath()
+		if dir == "" {
+			result.Skipped = append(result.Skipped, r.Name)
+			continue
+		}
+		remaining := opts.Limit - len(result.Matches)
+		var matches []SearchMatch
+		var more bool
+		if rg != "" {
+			matches, more, err = searchRipgrep(rg, dir, opts, remaining)
+		} else {
+			matches, more, err = searchWalk(dir, re, opts.Glob, remaining)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("searching rig %s: %w", r.Name, err)
+		}
+		for i := range matches {
+			matches[i].Rig = r.Name
+		}
+		result.Matches = append(result.Matches, matches...)
+		if more {
+			result.Truncated = true
+			break
+		}
+	}
+	return result, nil
+}
+
+// searchRipgrep searches dir with ripgrep, returning at most limit matches
+// and whether more exist.
+func searchRipgrep(rg, dir string, opts SearchOptions, limit int) ([]SearchMatch, bool, error) {
+	args := []string{"--json", "--no-config", "--sort", "path"}
+	if opts.IgnoreCase {
+		args = append(args, "--ignore-case")
+	}
+	if opts.Fixed {
+		args = append(args, "--fixed-strings")
+	}
+	if opts.Glob != "" {
+		args = append(args, "--glob", opts.Glob)
+	}
+	args = append(args, "--", opts.Pattern, ".")
+
+	cmd := exec.Command(rg, args...) //nolint:gosec // G204: rg from PATH with a fixed argument layout
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, false, err
+	}
+
+	var matches []SearchMatch
+	more := false
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var msg struct {
+			Type string `json:"type"`
+			Data struct {
+				Path struct {
+					Text string `json:"text"`
+				} `json:"path"`
+				Lines struct {
+					Text string `json:"text"`
+				} `json:"lines"`
+				LineNumber int `json:"line_number"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil || msg.Type != "match" {
+			continue
+		}
+		if len(matches) == limit {
+			more = true
+			break
+		}
+		matches = append(matches, SearchMatch{
+			Path: filepath.ToSlash(strings.TrimPrefix(msg.Data.Path.Text, "./")),
+			Line: msg.Data.LineNumber,
+			Text: searchLineText(msg.Data.Lines.Text),
+		})
+	}
+	if more {
+		_ = cmd.Process.Kill()
+		_, _ = io.Copy(io.Discard, stdout)
+		_ = cmd.Wait()
+		return matches, true, nil
+	}
+
+	if err := cmd.Wait(); err != nil {
+		// Exit status 1 means no matches
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return matches, false, nil
+		}
+		return nil, false, fmt.Errorf("rg: %s", strings.TrimSpace(stderr.String()))
+	}
+	return matches, false, nil
+}
+
+// searchWalk searches dir without ripgrep, returning at most limit matches
+// and whether more exist.
+func searchWalk(dir string, re *regexp.Regexp, glob string, limit int) ([]SearchMatch, bool, error) {
+	var matches []SearchMatch
+	errLimit := errors.New("limit reached")
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Unreadable entries are skipped, as ripgrep does
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if glob != "" {
+			if ok, _ := filepath.Match(glob, d.Name()); !ok {
+				return nil
+			}
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxSearchFileSize {
+			return nil
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the rig checkout
+		if err != nil || bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		for i, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			if !re.MatchString(line) {
+				continue
+			}
+			if len(matches) == limit {
+				return errLimit
+			}
+			matches = append(matches, SearchMatch{Path: filepath.ToSlash(rel), Line: i + 1, Text: searchLineText(line)})
+		}
+		return nil
+	})
+	if errors.Is(err, errLimit) {
+		return matches, true, nil
+	}
+	return matches, false, err
+}
+
+// searchLineText trims a matched line for display.
+func searchLineText(line string) string {
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) > maxSearchLineLength {
+		line = strings.ToValidUTF8(line[:maxSearchLineLength], "") + "…"
+	}
+	return line
+}
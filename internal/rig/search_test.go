@@ -0,0 +1,118 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// writeSearchFiles creates files (relative path to content) under dir.
+func writeSearchFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCheckoutPath(t *testing.T) {
+	root := t.TempDir()
+	writeSearchFiles(t, root, map[string]string{
+		"app/mayor/rig/go.mod":    "module app\n",
+		"lib/refinery/rig/go.mod": "module lib\n",
+		"docs/repo/README.md":     "# docs\n",
+		"empty/config.json":       "{}\n",
+	})
+
+	tests := []struct {
+		rig  *Rig
+		want string
+	}{
+		{&Rig{Name: "app", Path: filepath.Join(root, "app")}, filepath.Join(root, "app", "mayor", "rig")},
+		{&Rig{Name: "lib", Path: filepath.Join(root, "lib")}, filepath.Join(root, "lib", "refinery", "rig")},
+		{&Rig{Name: "docs", Path: filepath.Join(root, "docs"), Mirror: true}, filepath.Join(root, "docs", "repo")},
+		{&Rig{Name: "empty", Path: filepath.Join(root, "empty")}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.rig.CheckoutPath(); got != tt.want {
+			t.Errorf("%s: CheckoutPath() = %q, want %q", tt.rig.Name, got, tt.want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	root := t.TempDir()
+	writeSearchFiles(t, root, map[string]string{
+		"app/mayor/rig/main.go":         "package main\n\nfunc main() { shared.ParseConfig() }\n",
+		"app/mayor/rig/.git/HEAD":       "ParseConfig\n",
+		"lib/mayor/rig/config/parse.go": "package config\n\n// ParseConfig reads the config.\nfunc ParseConfig() {}\n",
+		"lib/mayor/rig/README.md":       "Call ParseConfig first.\n",
+	})
+	rigs := []*Rig{
+		{Name: "lib", Path: filepath.Join(root, "lib")},
+		{Name: "app", Path: filepath.Join(root, "app")},
+		{Name: "empty", Path: filepath.Join(root, "empty")},
+	}
+
+	result, err := Search(rigs, SearchOptions{Pattern: `func ParseConfig\(`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SearchMatch{Rig: "lib", Path: "config/parse.go", Line: 4, Text: "func ParseConfig() {}"}
+	if len(result.Matches) != 1 || result.Matches[0] != want {
+		t.Errorf("matches = %+v, want [%+v]", result.Matches, want)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "empty" {
+		t.Errorf("skipped = %v, want [empty]", result.Skipped)
+	}
+
+	// Rigs are searched in name order; .git is never searched
+	result, err = Search(rigs, SearchOptions{Pattern: "parseconfig", IgnoreCase: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Matches) != 4 || result.Matches[0].Rig != "app" || result.Truncated {
+		t.Errorf("matches = %+v, want 4 starting in app", result.Matches)
+	}
+
+	result, err = Search(rigs, SearchOptions{Pattern: "ParseConfig", Rigs: []string{"lib"}, Glob: "*.go", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Matches) != 1 || result.Matches[0].Rig != "lib" || !result.Truncated {
+		t.Errorf("filtered result = %+v, want 1 lib match, truncated", result)
+	}
+
+	result, err = Search(rigs, SearchOptions{Pattern: "ParseConfig()", Fixed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Matches) != 2 {
+		t.Errorf("fixed-string matches = %+v, want 2", result.Matches)
+	}
+
+	if _, err := Search(rigs, SearchOptions{Pattern: "("}); err == nil {
+		t.Error("invalid pattern should fail")
+	}
+}
+
+func TestSearchWalkSkipsBinaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeSearchFiles(t, dir, map[string]string{
+		"bin/tool":  "ParseConfig\x00\x01",
+		"src/a.txt": "ParseConfig\n",
+	})
+	matches, more, err := searchWalk(dir, regexp.MustCompile("ParseConfig"), "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if more || len(matches) != 1 || matches[0].Path != "src/a.txt" {
+		t.Errorf("matches = %+v, more %v; want only src/a.txt", matches, more)
+	}
+}
@@ -37,6 +37,9 @@ type Rig struct {
 
 	// HasMayor indicates if the rig has a mayor clone.
 	HasMayor bool `json:"has_mayor"`
+
+	// Mirror indicates a reference-only rig with no agents (see AddMirror).
+	Mirror bool `json:"mirror,omitempty"`
 }
 
 // AgentDirs are the standard agent directories in a rig.
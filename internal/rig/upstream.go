@@ -0,0 +1,198 @@
+package rig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/git"
+)
+
+// UpstreamRemote is the remote added to a rig's shared repo for its upstream.
+const UpstreamRemote = "upstream"
+
+// Upstream sync outcomes.
+const (
+	UpstreamUpToDate      = "up-to-date"     // Fork already contains upstream
+	UpstreamFastForwarded = "fast-forwarded" // Fork's default branch moved to upstream
+	UpstreamBehind        = "behind"         // Would fast-forward (dry run)
+	UpstreamDiverged      = "diverged"       // Both sides have commits; needs a merge
+)
+
+// UpstreamOverlap is an in-flight polecat branch that touches files the
+// incoming upstream commits also change.
+type UpstreamOverlap struct {
+	Polecat string   `json:"polecat"`
+	Branch  string   `json:"branch"`
+	Files   []string `json:"files"`
+}
+
+// UpstreamSyncResult describes one upstream sync.
+type UpstreamSyncResult struct {
+	Outcome        string            `json:"outcome"`
+	DefaultBranch  string            `json:"default_branch"`
+	UpstreamBranch string            `json:"upstream_branch"`
+	UpstreamSHA    string            `json:"upstream_sha"`
+	Incoming       int               `json:"incoming"`        // Upstream commits the fork lacked
+	Local          int               `json:"local,omitempty"` // Fork commits upstream lacks
+	Files          []string          `json:"files,omitempty"` // Files the incoming commits change
+	Overlaps       []UpstreamOverlap `json:"overlaps,omitempty"`
+}
+
+// SyncUpstream fetches a forked rig's upstream into the rig's shared repo
+// (.repo.git) and compares it with origin's default branch. When the fork
+// has no commits of its own, the default branch is fast-forwarded on origin
+// (unless dryRun); when both sides have commits the result is Diverged and
+// the caller decides how to follow up. Incoming changes are matched against
+// in-flight polecat branches.
+func SyncUpstream(r *Rig, cfg *config.UpstreamConfig, dryRun bool) (*UpstreamSyncResult, error) {
+	bare := filepath.Join(r.Path, ".repo.git")
+	if _, err := os.Stat(bare); err != nil {
+		return nil, fmt.Errorf("rig %s has no shared repo (.repo.git)", r.Name)
+	}
+	g := git.NewGitWithDir(bare, "")
+
+	defaultBranch := r.DefaultBranch()
+	upstreamBranch := cfg.Branch
+	if upstreamBranch == "" {
+		upstreamBranch = defaultBranch
+	}
+	originRef := "refs/remotes/origin/" + defaultBranch
+	upstreamRef := "refs/remotes/" + UpstreamRemote + "/" + upstreamBranch
+
+	if err := g.SetRemote(UpstreamRemote, cfg.URL); err != nil {
+		return nil, fmt.Errorf("configuring upstream remote: %w", err)
+	}
+	if err := g.FetchBranch("origin", "+refs/heads/"+defaultBranch+":"+originRef); err != nil {
+		return nil, fmt.Errorf("fetching origin: %w", err)
+	}
+	if err := g.FetchBranch(UpstreamRemote, "+refs/heads/"+upstreamBranch+":"+upstreamRef); err != nil {
+		return nil, fmt.Errorf("fetching upstream: %w", err)
+	}
+
+	result := &UpstreamSyncResult{DefaultBranch: defaultBranch, UpstreamBranch: upstreamBranch}
+	var err error
+	if result.UpstreamSHA, err = g.Rev(upstreamRef); err != nil {
+		return nil, err
+	}
+	originSHA, err := g.Rev(originRef)
+	if err != nil {
+		return nil, err
+	}
+	if result.Incoming, err = g.CommitsAhead(originSHA, upstreamRef); err != nil {
+		return nil, err
+	}
+	if result.Local, err = g.CommitsAhead(upstreamRef, originSHA); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case result.Incoming == 0:
+		result.Outcome = UpstreamUpToDate
+		return result, nil
+	case result.Local > 0:
+		result.Outcome = UpstreamDiverged
+	case dryRun:
+		result.Outcome = UpstreamBehind
+	default:
+		if err := g.Push("origin", upstreamRef+":refs/heads/"+defaultBranch, false); err != nil {
+			return nil, fmt.Errorf("fast-forwarding origin/%s: %w", defaultBranch, err)
+		}
+		_ = g.FetchBranch("origin", "+refs/heads/"+defaultBranch+":"+originRef)
+		result.Outcome = UpstreamFastForwarded
+	}
+
+	// Changes are relative to the fork as it was before this sync
+	if result.Files, err = g.ChangedFiles(originSHA, upstreamRef); err != nil {
+		return nil, err
+	}
+	result.Overlaps = upstreamOverlaps(r, originSHA, result.Files)
+	return result, nil
+}
+
+// upstreamOverlaps finds polecat branches that change any of files since
+// they left base.
+func upstreamOverlaps(r *Rig, base string, files []string) []UpstreamOverlap {
+	incoming := make(map[string]bool, len(files))
+	for _, f := range files {
+		incoming[f] = true
+	}
+
+	var overlaps []UpstreamOverlap
+	for _, name := range r.Polecats {
+		wg := git.NewGit(filepath.Join(r.Path, "polecats", name))
+		branch, err := wg.CurrentBranch()
+		if err != nil {
+			continue
+		}
+		changed, err := wg.ChangedFiles(base, "HEAD")
+		if err != nil {
+			continue
+		}
+		var shared []string
+		for _, f := range changed {
+			if incoming[f] {
+				shared = append(shared, f)
+			}
+		}
+		if len(shared) > 0 {
+			overlaps = append(overlaps, UpstreamOverlap{Polecat: name, Branch: branch, Files: shared})
+		}
+	}
+	sort.Slice(overlaps, func(i, j int) bool { return overlaps[i].Polecat < overlaps[j].Polecat })
+	return overlaps
+}
+
+// UpstreamState records upstream syncs for scheduling and deduplication.
+// Stored in <rig>/.runtime/upstream-sync.json.
+type UpstreamState struct {
+	LastSync     time.Time `json:"last_sync,omitempty"`
+	LastOutcome  string    `json:"last_outcome,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	NotifiedSHA  string    `json:"notified_sha,omitempty"`  // Upstream commit the witness was last told about
+	SyncTaskBead string    `json:"sync_task_bead,omitempty"` // Open task for a diverged fork
+}
+
+func upstreamStatePath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "upstream-sync.json")
+}
+
+// LoadUpstreamState reads a rig's upstream sync state; missing state is empty.
+func LoadUpstreamState(rigPath string) (*UpstreamState, error) {
+	data, err := os.ReadFile(upstreamStatePath(rigPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return &UpstreamState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state UpstreamState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing upstream sync state: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveUpstreamState writes a rig's upstream sync state.
+func SaveUpstreamState(rigPath string, state *UpstreamState) error {
+	path := upstreamStatePath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Due reports whether a scheduled sync should run at now.
+func (s *UpstreamState) Due(cfg *config.UpstreamConfig, now time.Time) bool {
+	interval := cfg.SyncInterval()
+	return interval > 0 && now.Sub(s.LastSync) >= interval
+}
@@ -0,0 +1,156 @@
+package rig
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+// gitIn runs git in dir with a fixed identity and returns trimmed output.
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commitFile writes path in the checkout at dir and commits it.
+func commitFile(t *testing.T, dir, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, path), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, dir, "add", path)
+	gitIn(t, dir, "commit", "-q", "-m", "change "+path)
+}
+
+// setupForkedRig creates an upstream repo, a bare fork of it, and a rig
+// whose shared repo is cloned from the fork.
+func setupForkedRig(t *testing.T) (upstream, fork string, r *Rig) {
+	t.Helper()
+	upstream = createUpstreamRepo(t)
+	fork = filepath.Join(t.TempDir(), "fork.git")
+	gitIn(t, upstream, "clone", "-q", "--bare", upstream, fork)
+	rigPath := t.TempDir()
+	gitIn(t, rigPath, "clone", "-q", "--bare", fork, filepath.Join(rigPath, ".repo.git"))
+	return upstream, fork, &Rig{Name: "gp", Path: rigPath}
+}
+
+func TestSyncUpstream_FastForward(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	upstream, fork, r := setupForkedRig(t)
+	cfg := &config.UpstreamConfig{URL: upstream}
+
+	result, err := SyncUpstream(r, cfg, false)
+	if err != nil {
+		t.Fatalf("SyncUpstream: %v", err)
+	}
+	if result.Outcome != UpstreamUpToDate {
+		t.Errorf("Outcome = %s, want %s", result.Outcome, UpstreamUpToDate)
+	}
+
+	// A polecat editing the file upstream is about to change
+	bare := filepath.Join(r.Path, ".repo.git")
+	polecat := filepath.Join(r.Path, "polecats", "Toast")
+	gitIn(t, bare, "worktree", "add", "-q", "-b", "polecat/Toast-1", polecat, "main")
+	commitFile(t, polecat, "README.md", "# polecat edit\n")
+	r.Polecats = []string{"Toast"}
+
+	commitFile(t, upstream, "README.md", "# upstream edit\n")
+	commitFile(t, upstream, "NEW.md", "new\n")
+
+	result, err = SyncUpstream(r, cfg, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if result.Outcome != UpstreamBehind || result.Incoming != 2 {
+		t.Errorf("dry run = %+v, want behind by 2", result)
+	}
+	if gitIn(t, fork, "rev-parse", "main") == gitIn(t, upstream, "rev-parse", "main") {
+		t.Error("dry run must not push")
+	}
+
+	result, err = SyncUpstream(r, cfg, false)
+	if err != nil {
+		t.Fatalf("SyncUpstream: %v", err)
+	}
+	if result.Outcome != UpstreamFastForwarded {
+		t.Errorf("Outcome = %s, want %s", result.Outcome, UpstreamFastForwarded)
+	}
+	if got, want := gitIn(t, fork, "rev-parse", "main"), gitIn(t, upstream, "rev-parse", "main"); got != want {
+		t.Errorf("fork main = %s, want upstream %s", got, want)
+	}
+	if len(result.Overlaps) != 1 || result.Overlaps[0].Polecat != "Toast" || strings.Join(result.Overlaps[0].Files, ",") != "README.md" {
+		t.Errorf("Overlaps = %+v, want Toast on README.md", result.Overlaps)
+	}
+}
+
+func TestSyncUpstream_Diverged(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	upstream, fork, r := setupForkedRig(t)
+
+	// A commit only the fork has
+	work := filepath.Join(t.TempDir(), "work")
+	gitIn(t, upstream, "clone", "-q", fork, work)
+	commitFile(t, work, "FORK.md", "fork only\n")
+	gitIn(t, work, "push", "-q", "origin", "main")
+	forkMain := gitIn(t, fork, "rev-parse", "main")
+
+	commitFile(t, upstream, "UPSTREAM.md", "upstream only\n")
+
+	result, err := SyncUpstream(r, &config.UpstreamConfig{URL: upstream}, false)
+	if err != nil {
+		t.Fatalf("SyncUpstream: %v", err)
+	}
+	if result.Outcome != UpstreamDiverged || result.Incoming != 1 || result.Local != 1 {
+		t.Errorf("result = %+v, want diverged 1/1", result)
+	}
+	if got := gitIn(t, fork, "rev-parse", "main"); got != forkMain {
+		t.Error("a diverged fork must not be pushed")
+	}
+}
+
+func TestUpstreamState(t *testing.T) {
+	rigPath := t.TempDir()
+	state, err := LoadUpstreamState(rigPath)
+	if err != nil {
+		t.Fatalf("missing state: %v", err)
+	}
+	cfg := &config.UpstreamConfig{URL: "u", Interval: "1h"}
+	now := time.Now()
+	if !state.Due(cfg, now) {
+		t.Error("never-synced rig should be due")
+	}
+
+	state.LastSync = now.Add(-30 * time.Minute)
+	state.SyncTaskBead = "gp-sync"
+	if err := SaveUpstreamState(rigPath, state); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadUpstreamState(rigPath)
+	if err != nil || loaded.SyncTaskBead != "gp-sync" {
+		t.Fatalf("round trip = %+v, %v", loaded, err)
+	}
+	if loaded.Due(cfg, now) {
+		t.Error("synced 30m ago with a 1h interval should not be due")
+	}
+	if loaded.Due(&config.UpstreamConfig{URL: "u", Interval: "0"}, now.Add(24*time.Hour)) {
+		t.Error("interval 0 is manual only")
+	}
+}
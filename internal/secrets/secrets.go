@@ -0,0 +1,188 @@
+// Package secrets resolves secret references used in Gas Town config.
+//
+// Config values of the form "secretRef:<provider>:<reference>" are looked up
+// at agent spawn and daemon start instead of being stored in plaintext:
+//
+//	secretRef:env:ANTHROPIC_API_KEY            environment variable
+//	secretRef:file:~/.config/gt/api-key        file contents (trailing newline trimmed)
+//	secretRef:keychain:gastown/api-key         OS keychain (service/account)
+//	secretRef:op:op://Dev/Anthropic/credential 1Password CLI (op read)
+//	secretRef:vault:secret/gastown#api_key     Vault CLI (vault kv get -field)
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// RefPrefix marks a config value as a secret reference.
+const RefPrefix = "secretRef:"
+
+// ErrUnknownProvider is returned for references naming an unregistered provider.
+var ErrUnknownProvider = errors.New("unknown secret provider")
+
+// Provider looks up secrets in one backend.
+type Provider interface {
+	// Lookup returns the secret for a provider-specific reference.
+	Lookup(ref string) (string, error)
+}
+
+// ProviderFunc adapts a function to the Provider interface.
+type ProviderFunc func(ref string) (string, error)
+
+// Lookup calls f(ref).
+func (f ProviderFunc) Lookup(ref string) (string, error) { return f(ref) }
+
+// providers maps provider names to implementations.
+var providers = map[string]Provider{
+	"env":      ProviderFunc(lookupEnv),
+	"file":     ProviderFunc(lookupFile),
+	"keychain": ProviderFunc(lookupKeychain),
+	"op":       ProviderFunc(lookupOnePassword),
+	"vault":    ProviderFunc(lookupVault),
+}
+
+// Register adds or replaces a provider.
+func Register(name string, p Provider) {
+	providers[name] = p
+}
+
+// Providers returns the registered provider names, sorted.
+func Providers() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsRef reports whether a config value is a secret reference.
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, RefPrefix)
+}
+
+// ParseRef splits a secret reference into provider name and reference.
+func ParseRef(value string) (provider, ref string, err error) {
+	rest, ok := strings.CutPrefix(value, RefPrefix)
+	if !ok {
+		return "", "", fmt.Errorf("not a secret reference: missing %q prefix", RefPrefix)
+	}
+	provider, ref, ok = strings.Cut(rest, ":")
+	if !ok || provider == "" || ref == "" {
+		return "", "", fmt.Errorf("invalid secret reference %q: want %s<provider>:<reference>", value, RefPrefix)
+	}
+	if _, known := providers[provider]; !known {
+		return "", "", fmt.Errorf("%w %q (have: %s)", ErrUnknownProvider, provider, strings.Join(Providers(), ", "))
+	}
+	return provider, ref, nil
+}
+
+// Resolve returns the secret a value refers to, or the value itself if it
+// is not a secret reference.
+func Resolve(value string) (string, error) {
+	if !IsRef(value) {
+		return value, nil
+	}
+	provider, ref, err := ParseRef(value)
+	if err != nil {
+		return "", err
+	}
+	secret, err := providers[provider].Lookup(ref)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s secret %q: %w", provider, ref, err)
+	}
+	return secret, nil
+}
+
+// ResolveEnv resolves every value in an environment map. Errors name the
+// variable but never include secret values.
+func ResolveEnv(env map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(env))
+	var errs []error
+	for k, v := range env {
+		secret, err := Resolve(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", k, err))
+			continue
+		}
+		resolved[k] = secret
+	}
+	return resolved, errors.Join(errs...)
+}
+
+// runCommand runs a provider CLI and returns its trimmed stdout.
+// Overridden in tests.
+var runCommand = func(name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...) //nolint:gosec // G204: provider CLIs are fixed; args come from operator config
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %s", name, msg)
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+func lookupEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+func lookupFile(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path comes from operator config
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// lookupKeychain reads a generic password stored as "service/account".
+func lookupKeychain(ref string) (string, error) {
+	service, account, ok := strings.Cut(ref, "/")
+	if !ok || service == "" || account == "" {
+		return "", fmt.Errorf("keychain reference must be service/account")
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return runCommand("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux":
+		return runCommand("secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return "", fmt.Errorf("keychain provider not supported on %s", runtime.GOOS)
+	}
+}
+
+// lookupOnePassword reads an op:// secret reference with the 1Password CLI.
+func lookupOnePassword(ref string) (string, error) {
+	return runCommand("op", "read", "--no-newline", ref)
+}
+
+// lookupVault reads "path#field" from Vault's KV store.
+func lookupVault(ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("vault reference must be path#field")
+	}
+	return runCommand("vault", "kv", "get", "-field="+field, path)
+}
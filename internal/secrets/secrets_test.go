@@ -0,0 +1,90 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("GT_TEST_SECRET", "s3cret")
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain-value", "plain-value"},
+		{"secretRef:env:GT_TEST_SECRET", "s3cret"},
+		{"secretRef:file:" + keyFile, "from-file"},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"secretRef:env", "secretRef::x", "secretRef:nope:x", "secretRef:env:GT_TEST_UNSET_VAR"} {
+		if _, err := Resolve(bad); err == nil {
+			t.Errorf("Resolve(%q) succeeded, want error", bad)
+		}
+	}
+	if _, err := Resolve("secretRef:nope:x"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("unknown provider error = %v, want ErrUnknownProvider", err)
+	}
+}
+
+func TestCLIProviders(t *testing.T) {
+	var calls [][]string
+	orig := runCommand
+	runCommand = func(name string, args ...string) (string, error) {
+		calls = append(calls, append([]string{name}, args...))
+		return "cli-secret", nil
+	}
+	defer func() { runCommand = orig }()
+
+	for _, ref := range []string{"secretRef:op:op://Dev/Anthropic/credential", "secretRef:vault:secret/gastown#api_key"} {
+		if got, err := Resolve(ref); err != nil || got != "cli-secret" {
+			t.Errorf("Resolve(%q) = %q, %v", ref, got, err)
+		}
+	}
+	want := [][]string{
+		{"op", "read", "--no-newline", "op://Dev/Anthropic/credential"},
+		{"vault", "kv", "get", "-field=api_key", "secret/gastown"},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	if _, err := Resolve("secretRef:vault:secret/gastown"); err == nil {
+		t.Error("expected error for vault reference without #field")
+	}
+}
+
+func TestResolveEnv(t *testing.T) {
+	t.Setenv("GT_TEST_SECRET", "s3cret")
+	env := map[string]string{
+		"API_KEY": "secretRef:env:GT_TEST_SECRET",
+		"REGION":  "us-east-1",
+		"BROKEN":  "secretRef:env:GT_TEST_UNSET_VAR",
+	}
+
+	resolved, err := ResolveEnv(env)
+	if err == nil || !strings.Contains(err.Error(), "BROKEN") {
+		t.Errorf("ResolveEnv() error = %v, want error naming BROKEN", err)
+	}
+	if resolved["API_KEY"] != "s3cret" || resolved["REGION"] != "us-east-1" {
+		t.Errorf("ResolveEnv() = %v", resolved)
+	}
+	if _, ok := resolved["BROKEN"]; ok {
+		t.Error("unresolved variable should be omitted")
+	}
+}
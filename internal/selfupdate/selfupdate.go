@@ -0,0 +1,408 @@
+// Package selfupdate finds, verifies, and installs gt release binaries.
+//
+// Releases are published by goreleaser: one archive per platform plus a
+// checksums.txt (sha256) and, for signed releases, checksums.txt.sig, a raw
+// ed25519 signature over checksums.txt. Binaries built with a SigningKey
+// refuse releases whose checksums are not signed by it.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultReleasesURL is the GitHub API listing of gt releases.
+const DefaultReleasesURL = "https://api.github.com/repos/cursorworkshop/cursor-gastown/releases"
+
+// Release channels.
+const (
+	ChannelStable     = "stable"     // Full releases only
+	ChannelPrerelease = "prerelease" // Release candidates and betas too
+)
+
+// ChecksumsFile is the release asset listing each archive's sha256.
+const ChecksumsFile = "checksums.txt"
+
+// maxDownload bounds a single asset download.
+const maxDownload = 256 << 20
+
+// SigningKey is the hex ed25519 public key releases are signed with, set
+// at build time via ldflags. Empty (dev builds) verifies checksums only.
+var SigningKey = ""
+
+// Errors returned while fetching a release.
+var (
+	ErrNoAsset          = errors.New("release has no asset for this platform")
+	ErrChecksumMismatch = errors.New("archive checksum does not match checksums.txt")
+	ErrBadSignature     = errors.New("checksums.txt signature does not verify")
+	ErrUnverifiedMirror = errors.New("this gt has no release signing key; refusing to install from a custom releases_url")
+)
+
+// Asset is a downloadable file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Release is one published gt release.
+type Release struct {
+	Tag        string  `json:"tag_name"`
+	Prerelease bool    `json:"prerelease"`
+	Draft      bool    `json:"draft"`
+	PageURL    string  `json:"html_url"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Version returns the release version without the leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+// Asset returns the named asset.
+func (r *Release) Asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Client talks to a releases endpoint.
+type Client struct {
+	ReleasesURL string
+	HTTP        *http.Client
+}
+
+// NewClient returns a client for releasesURL (DefaultReleasesURL if empty).
+func NewClient(releasesURL string) *Client {
+	if releasesURL == "" {
+		releasesURL = DefaultReleasesURL
+	}
+	return &Client{ReleasesURL: releasesURL, HTTP: &http.Client{Timeout: 5 * time.Minute}}
+}
+
+// Latest returns the newest release on channel. Drafts are never chosen;
+// prereleases only on ChannelPrerelease.
+func (c *Client) Latest(ctx context.Context, channel string) (*Release, error) {
+	data, err := c.get(ctx, c.ReleasesURL+"?per_page=30")
+	if err != nil {
+		return nil, err
+	}
+	var releases []*Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("parsing release list: %w", err)
+	}
+
+	var candidates []*Release
+	for _, r := range releases {
+		if r.Draft || (r.Prerelease && channel != ChannelPrerelease) {
+			continue
+		}
+		candidates = append(candidates, r)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no %s releases found at %s", channel, c.ReleasesURL)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return CompareVersions(candidates[i].Version(), candidates[j].Version()) > 0
+	})
+	return candidates[0], nil
+}
+
+// Tagged returns the release for a specific version ("1.2.3" or "v1.2.3").
+func (c *Client) Tagged(ctx context.Context, version string) (*Release, error) {
+	data, err := c.get(ctx, c.ReleasesURL+"/tags/v"+strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return nil, err
+	}
+	var r Release
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing release: %w", err)
+	}
+	return &r, nil
+}
+
+// get fetches url, failing on non-200 responses.
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(url, "api.github.com") {
+		req.Header.Set("Accept", "application/vnd.github+json")
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload+1))
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", url, err)
+	}
+	if len(data) > maxDownload {
+		return nil, fmt.Errorf("GET %s: larger than %d bytes", url, maxDownload)
+	}
+	return data, nil
+}
+
+// ArchiveName returns the goreleaser archive name for a platform.
+func ArchiveName(version, goos, goarch string) string {
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("cursor-gastown_%s_%s_%s.%s", strings.TrimPrefix(version, "v"), goos, goarch, ext)
+}
+
+// Fetch downloads the release archive for goos/goarch, verifies it against
+// the (signed, if SigningKey is set) checksums, and returns the gt binary
+// inside it. Without a SigningKey only DefaultReleasesURL is trusted: a
+// mirror serves its own checksums, so they prove nothing.
+func (c *Client) Fetch(ctx context.Context, r *Release, goos, goarch string) ([]byte, error) {
+	if SigningKey == "" && c.ReleasesURL != DefaultReleasesURL {
+		return nil, fmt.Errorf("%w (%s)", ErrUnverifiedMirror, c.ReleasesURL)
+	}
+	name := ArchiveName(r.Version(), goos, goarch)
+	archiveAsset, ok := r.Asset(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s not in %s", ErrNoAsset, name, r.Tag)
+	}
+	sumsAsset, ok := r.Asset(ChecksumsFile)
+	if !ok {
+		return nil, fmt.Errorf("%s has no %s; refusing an unverifiable download", r.Tag, ChecksumsFile)
+	}
+
+	sums, err := c.get(ctx, sumsAsset.URL)
+	if err != nil {
+		return nil, err
+	}
+	if SigningKey != "" {
+		sigAsset, ok := r.Asset(ChecksumsFile + ".sig")
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not signed", ErrBadSignature, r.Tag)
+		}
+		sig, err := c.get(ctx, sigAsset.URL)
+		if err != nil {
+			return nil, err
+		}
+		if err := VerifySignature(sums, sig, SigningKey); err != nil {
+			return nil, err
+		}
+	}
+
+	archive, err := c.get(ctx, archiveAsset.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyChecksum(sums, name, archive); err != nil {
+		return nil, err
+	}
+
+	binary := "gt"
+	if goos == "windows" {
+		binary = "gt.exe"
+	}
+	return ExtractBinary(name, archive, binary)
+}
+
+// VerifySignature checks a raw ed25519 signature over checksums against a
+// hex public key.
+func VerifySignature(checksums, sig []byte, publicKeyHex string) error {
+	key, err := hex.DecodeString(strings.TrimSpace(publicKeyHex))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release signing key %q", publicKeyHex)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), checksums, sig) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// VerifyChecksum checks data against name's line in a checksums.txt.
+func VerifyChecksum(checksums []byte, name string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[1] != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, name)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s is not listed", ErrChecksumMismatch, name)
+}
+
+// ExtractBinary returns the file named binary from a .tar.gz or .zip archive.
+func ExtractBinary(archiveName string, archive []byte, binary string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", archiveName, err)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) != binary {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(io.LimitReader(rc, maxDownload))
+		}
+		return nil, fmt.Errorf("%s has no %s", archiveName, binary)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", archiveName, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s has no %s", archiveName, binary)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", archiveName, err)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == binary {
+			return io.ReadAll(io.LimitReader(tr, maxDownload))
+		}
+	}
+}
+
+// Install replaces the executable at exePath with binary. The new binary is
+// written beside it and renamed into place, so a failed update leaves the
+// old one intact. On Windows, where a running executable cannot be
+// replaced, the old one is first moved to exePath.old.
+func Install(exePath string, binary []byte) error {
+	info, err := os.Stat(exePath)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(exePath), "."+filepath.Base(exePath)+".new-*")
+	if err != nil {
+		return fmt.Errorf("staging update next to %s: %w", exePath, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()|0o111); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := exePath + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(exePath, old); err != nil {
+			return err
+		}
+		if err := os.Rename(tmpPath, exePath); err != nil {
+			_ = os.Rename(old, exePath)
+			return err
+		}
+		return nil
+	}
+	return os.Rename(tmpPath, exePath)
+}
+
+// CompareVersions compares two semver strings ("1.2.3", "v1.3.0-rc.1"),
+// returning -1, 0, or 1. A prerelease sorts before its release.
+func CompareVersions(a, b string) int {
+	aCore, aPre, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	bCore, bPre, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+	if c := compareDotted(aCore, bCore); c != 0 {
+		return c
+	}
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return compareDotted(aPre, bPre)
+}
+
+// compareDotted compares dot-separated identifiers, numerically where both
+// are numbers.
+func compareDotted(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xErr := strconv.Atoi(x)
+		yn, yErr := strconv.Atoi(y)
+		if x == "" && yErr == nil {
+			xErr = nil // "1.2" == "1.2.0"
+		}
+		if y == "" && xErr == nil {
+			yErr = nil
+		}
+		switch {
+		case xErr == nil && yErr == nil:
+			if xn != yn {
+				return cmpInt(xn, yn)
+			}
+		case x != y:
+			if x == "" {
+				return -1
+			}
+			if y == "" {
+				return 1
+			}
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+func cmpInt(a, b int) int {
+	if a < b {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,208 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testRelease serves a release list with one archive for linux/amd64.
+type testRelease struct {
+	archive  []byte
+	sums     []byte
+	sig      []byte
+	releases []*Release
+}
+
+func newTestServer(t *testing.T, rel *testRelease) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases":
+			_ = json.NewEncoder(w).Encode(rel.releases)
+		case "/releases/tags/v1.2.0":
+			_ = json.NewEncoder(w).Encode(rel.releases[1])
+		case "/dl/archive":
+			_, _ = w.Write(rel.archive)
+		case "/dl/checksums":
+			_, _ = w.Write(rel.sums)
+		case "/dl/sig":
+			_, _ = w.Write(rel.sig)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func tarGz(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{{"README.md", []byte("readme")}, {name, content}} {
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0755, Size: int64(len(f.data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func setup(t *testing.T) (*Client, *testRelease, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := SigningKey
+	SigningKey = hex.EncodeToString(pub)
+	t.Cleanup(func() { SigningKey = orig })
+
+	rel := &testRelease{archive: tarGz(t, "gt", []byte("new gt binary"))}
+	sum := sha256.Sum256(rel.archive)
+	rel.sums = []byte(fmt.Sprintf("%s  %s\n%s  other.zip\n", hex.EncodeToString(sum[:]), ArchiveName("1.3.0-rc.1", "linux", "amd64"), hex.EncodeToString(sum[:])))
+	rel.sig = ed25519.Sign(priv, rel.sums)
+
+	srv := newTestServer(t, rel)
+	assets := []Asset{
+		{Name: ArchiveName("1.3.0-rc.1", "linux", "amd64"), URL: srv.URL + "/dl/archive"},
+		{Name: ChecksumsFile, URL: srv.URL + "/dl/checksums"},
+		{Name: ChecksumsFile + ".sig", URL: srv.URL + "/dl/sig"},
+	}
+	rel.releases = []*Release{
+		{Tag: "v1.3.0-rc.1", Prerelease: true, Assets: assets},
+		{Tag: "v1.2.0"},
+		{Tag: "v1.10.0", Draft: true},
+		{Tag: "v1.1.9"},
+	}
+	return NewClient(srv.URL + "/releases"), rel, priv
+}
+
+func TestLatestByChannel(t *testing.T) {
+	client, _, _ := setup(t)
+	ctx := context.Background()
+
+	stable, err := client.Latest(ctx, ChannelStable)
+	if err != nil || stable.Tag != "v1.2.0" {
+		t.Errorf("Latest(stable) = %v, %v; want v1.2.0", stable, err)
+	}
+	pre, err := client.Latest(ctx, ChannelPrerelease)
+	if err != nil || pre.Tag != "v1.3.0-rc.1" {
+		t.Errorf("Latest(prerelease) = %v, %v; want v1.3.0-rc.1", pre, err)
+	}
+	tagged, err := client.Tagged(ctx, "1.2.0")
+	if err != nil || tagged.Version() != "1.2.0" {
+		t.Errorf("Tagged(1.2.0) = %v, %v", tagged, err)
+	}
+}
+
+func TestFetchVerifies(t *testing.T) {
+	client, rel, priv := setup(t)
+	ctx := context.Background()
+	release := rel.releases[0]
+
+	binary, err := client.Fetch(ctx, release, "linux", "amd64")
+	if err != nil || string(binary) != "new gt binary" {
+		t.Fatalf("Fetch = %q, %v", binary, err)
+	}
+
+	if _, err := client.Fetch(ctx, release, "darwin", "arm64"); !errors.Is(err, ErrNoAsset) {
+		t.Errorf("Fetch(missing platform) err = %v, want ErrNoAsset", err)
+	}
+
+	good := rel.archive
+	rel.archive = append(append([]byte{}, good...), 0)
+	if _, err := client.Fetch(ctx, release, "linux", "amd64"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Fetch(tampered archive) err = %v, want ErrChecksumMismatch", err)
+	}
+	rel.archive = good
+
+	rel.sig = ed25519.Sign(priv, []byte("something else"))
+	if _, err := client.Fetch(ctx, release, "linux", "amd64"); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("Fetch(bad signature) err = %v, want ErrBadSignature", err)
+	}
+
+	unsigned := &Release{Tag: release.Tag, Assets: release.Assets[:2]}
+	if _, err := client.Fetch(ctx, unsigned, "linux", "amd64"); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("Fetch(unsigned) err = %v, want ErrBadSignature", err)
+	}
+
+	// Dev builds without a key refuse mirrors, and check the checksum only
+	// of releases from the default endpoint
+	SigningKey = ""
+	if _, err := client.Fetch(ctx, unsigned, "linux", "amd64"); !errors.Is(err, ErrUnverifiedMirror) {
+		t.Errorf("Fetch(mirror, no key) err = %v, want ErrUnverifiedMirror", err)
+	}
+	client.ReleasesURL = DefaultReleasesURL
+	if _, err := client.Fetch(ctx, unsigned, "linux", "amd64"); err != nil {
+		t.Errorf("Fetch(unsigned, no key) err = %v", err)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "gt")
+	if err := os.WriteFile(exe, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Install(exe, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(exe)
+	if err != nil || string(data) != "new" {
+		t.Errorf("after Install: %q, %v", data, err)
+	}
+	info, _ := os.Stat(exe)
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("installed binary mode = %v, want executable", info.Mode())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 1 {
+		t.Errorf("staging file left behind: %v", entries)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.10.0", "1.9.9", 1},
+		{"1.2", "1.2.0", 0},
+		{"1.3.0-rc.1", "1.3.0", -1},
+		{"1.3.0-rc.2", "1.3.0-rc.10", -1},
+		{"1.3.0-rc.1", "1.2.9", 1},
+		{"0.1.1", "0.2.0", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
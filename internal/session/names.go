@@ -64,6 +64,15 @@ func PropulsionNudge() string {
 // The workDir parameter is used to locate .runtime/session_id for including
 // session ID in the message (for Cursor /resume picker discovery).
 func PropulsionNudgeForRole(role, workDir string) string {
+	return PropulsionNudgeWithState(role, workDir, nil)
+}
+
+// PropulsionNudgeWithState is PropulsionNudgeForRole followed by a summary
+// of the live work state the agent starts from (e.g. merge queue depth and
+// recent failures for the refinery), so patrol agents start with
+// actionable context. The nudge is one line: each state item is a short
+// clause, joined with "; ".
+func PropulsionNudgeWithState(role, workDir string, state []string) string {
 	var msg string
 	switch role {
 	case "polecat", "crew":
@@ -80,6 +89,10 @@ func PropulsionNudgeForRole(role, workDir string) string {
 		msg = PropulsionNudge()
 	}
 
+	if len(state) > 0 {
+		msg = fmt.Sprintf("%s Current state: %s.", msg, strings.Join(state, "; "))
+	}
+
 	// Append session ID if available (for /resume picker visibility)
 	if sessionID := readSessionID(workDir); sessionID != "" {
 		msg = fmt.Sprintf("%s [session:%s]", msg, sessionID)
@@ -169,3 +169,14 @@ func TestPropulsionNudgeForRole_AllRoles(t *testing.T) {
 		})
 	}
 }
+
+func TestPropulsionNudgeWithState(t *testing.T) {
+	msg := PropulsionNudgeWithState("refinery", "", []string{"3 MRs queued", "1 merge failure in the last 24h"})
+	want := "Run `gt prime` to check MQ status and begin patrol. Current state: 3 MRs queued; 1 merge failure in the last 24h."
+	if msg != want {
+		t.Errorf("PropulsionNudgeWithState = %q, want %q", msg, want)
+	}
+	if msg := PropulsionNudgeWithState("witness", "", nil); msg != PropulsionNudgeForRole("witness", "") {
+		t.Errorf("PropulsionNudgeWithState without state = %q, want the plain nudge", msg)
+	}
+}
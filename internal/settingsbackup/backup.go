@@ -0,0 +1,249 @@
+// Package settingsbackup keeps the previous version of agent settings files
+// (hooks.json, rules, instructions files such as AGENTS.md, mcp.json) that
+// a settings sync or 'gt doctor --fix' replaces or removes, so local edits
+// can be listed and restored with 'gt settings backups' and
+// 'gt settings restore'.
+//
+// Backups of files inside a town go to the town's BackupsDir. Files outside
+// any town are copied next to themselves as "<name>.<timestamp>.bak".
+package settingsbackup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// BackupsDir is where settings backups are stored, relative to the town root.
+const BackupsDir = ".runtime/settings-backups"
+
+// KeepPerPath is how many backups are kept for each file; older ones are
+// pruned when a new one is taken.
+const KeepPerPath = 10
+
+const (
+	metaFile    = "backup.json"
+	contentFile = "content"
+	idFormat    = "20060102-150405"
+)
+
+// ErrNotFound is returned when a backup ID does not exist.
+var ErrNotFound = errors.New("settings backup not found")
+
+// Backup is one saved version of a settings file.
+type Backup struct {
+	ID        string      `json:"id"`
+	Path      string      `json:"path"` // Absolute original path
+	CreatedAt time.Time   `json:"created_at"`
+	Mode      os.FileMode `json:"mode"`
+	Size      int64       `json:"size"`
+}
+
+// Save backs up the file at path before it is overwritten with next, or
+// removed when next is nil. Nothing is saved when the file does not exist,
+// is not a regular file, or already has content next. Callers must not
+// modify path if Save fails. Returns the backup taken, or nil.
+func Save(path string, next []byte) (*Backup, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", path, err)
+	}
+	info, err := os.Lstat(abs)
+	if os.IsNotExist(err) || (err == nil && !info.Mode().IsRegular()) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("backing up %s: %w", abs, err)
+	}
+	content, err := os.ReadFile(abs) //nolint:gosec // G304: path is a settings file being replaced
+	if err != nil {
+		return nil, fmt.Errorf("backing up %s: %w", abs, err)
+	}
+	if next != nil && bytes.Equal(content, next) {
+		return nil, nil
+	}
+
+	now := time.Now()
+	townRoot, _ := workspace.Find(filepath.Dir(abs))
+	if townRoot == "" {
+		bak := fmt.Sprintf("%s.%s.bak", abs, now.UTC().Format(idFormat))
+		if err := os.WriteFile(bak, content, info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("backing up %s: %w", abs, err)
+		}
+		return &Backup{Path: abs, CreatedAt: now, Mode: info.Mode().Perm(), Size: info.Size()}, nil
+	}
+
+	root := filepath.Join(townRoot, BackupsDir)
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("creating backup dir: %w", err)
+	}
+	b := &Backup{Path: abs, CreatedAt: now, Mode: info.Mode().Perm(), Size: info.Size()}
+	dir, err := newBackupDir(root, now)
+	if err != nil {
+		return nil, err
+	}
+	b.ID = filepath.Base(dir)
+	if err := os.WriteFile(filepath.Join(dir, contentFile), content, 0600); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("backing up %s: %w", abs, err)
+	}
+	if err := util.AtomicWriteJSON(filepath.Join(dir, metaFile), b); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("backing up %s: %w", abs, err)
+	}
+	prune(townRoot, abs)
+	return b, nil
+}
+
+// newBackupDir creates a fresh backup directory named for now, suffixed
+// when several files are backed up in the same second.
+func newBackupDir(root string, now time.Time) (string, error) {
+	base := now.UTC().Format(idFormat)
+	id := base
+	for i := 2; ; i++ {
+		dir := filepath.Join(root, id)
+		err := os.Mkdir(dir, 0755)
+		if err == nil {
+			return dir, nil
+		}
+		if !os.IsExist(err) {
+			return "", fmt.Errorf("creating backup dir: %w", err)
+		}
+		id = base + "-" + strconv.Itoa(i)
+	}
+}
+
+// prune removes all but the newest KeepPerPath backups of path.
+func prune(townRoot, path string) {
+	backups, err := List(townRoot)
+	if err != nil {
+		return
+	}
+	kept := 0
+	for _, b := range backups {
+		if b.Path != path {
+			continue
+		}
+		if kept++; kept > KeepPerPath {
+			_ = os.RemoveAll(filepath.Join(townRoot, BackupsDir, b.ID))
+		}
+	}
+}
+
+// List returns the town's settings backups, newest first.
+func List(townRoot string) ([]Backup, error) {
+	root := filepath.Join(townRoot, BackupsDir)
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var backups []Backup
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		b, err := load(root, e.Name())
+		if err != nil {
+			continue // Incomplete or foreign directory
+		}
+		backups = append(backups, *b)
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		if !backups[i].CreatedAt.Equal(backups[j].CreatedAt) {
+			return backups[i].CreatedAt.After(backups[j].CreatedAt)
+		}
+		return backups[i].ID > backups[j].ID
+	})
+	return backups, nil
+}
+
+func load(root, id string) (*Backup, error) {
+	data, err := os.ReadFile(filepath.Join(root, id, metaFile)) //nolint:gosec // G304: path is under the town backups dir
+	if err != nil {
+		return nil, err
+	}
+	var b Backup
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+	b.ID = id
+	return &b, nil
+}
+
+// Content returns the saved content of a backup.
+func Content(townRoot, id string) ([]byte, *Backup, error) {
+	root := filepath.Join(townRoot, BackupsDir)
+	if id == "" || filepath.Base(id) != id {
+		return nil, nil, fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	b, err := load(root, id)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("%w: %q", ErrNotFound, id)
+		}
+		return nil, nil, fmt.Errorf("reading backup %s: %w", id, err)
+	}
+	content, err := os.ReadFile(filepath.Join(root, id, contentFile)) //nolint:gosec // G304: path is under the town backups dir
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading backup %s: %w", id, err)
+	}
+	return content, b, nil
+}
+
+// Restore writes a backup back to its original path. The file it replaces
+// is backed up first, so a restore can itself be undone.
+func Restore(townRoot, id string) (*Backup, error) {
+	content, b, err := Content(townRoot, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Save(b.Path, content); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(b.Path), 0755); err != nil {
+		return nil, fmt.Errorf("restoring %s: %w", b.Path, err)
+	}
+	mode := b.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+	if err := os.WriteFile(b.Path, content, mode); err != nil {
+		return nil, fmt.Errorf("restoring %s: %w", b.Path, err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(b.Path, mode); err != nil {
+		return nil, fmt.Errorf("restoring %s: %w", b.Path, err)
+	}
+	return b, nil
+}
+
+// WriteFile backs up path (see Save) and writes data to it.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if _, err := Save(path, data); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// Remove backs up path (see Save) and removes it. A missing file is not an error.
+func Remove(path string) error {
+	if _, err := Save(path, nil); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
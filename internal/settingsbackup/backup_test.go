@@ -0,0 +1,153 @@
+package settingsbackup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTown(t *testing.T) string {
+	t.Helper()
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"type":"town","name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return town
+}
+
+func TestWriteFileBacksUpAndRestores(t *testing.T) {
+	town := newTown(t)
+	path := filepath.Join(town, "gastown", "crew", "max", ".cursor", "hooks.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Creating a file and rewriting it unchanged take no backup
+	if err := WriteFile(path, []byte("edited\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("edited\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := List(town); len(backups) != 0 {
+		t.Fatalf("backups = %+v, want none", backups)
+	}
+
+	if err := WriteFile(path, []byte("generated\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := List(town)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("List = %+v, %v, want one backup", backups, err)
+	}
+	if backups[0].Path != path || backups[0].Mode != 0600 {
+		t.Errorf("backup = %+v, want %s with mode 0600", backups[0], path)
+	}
+
+	b, err := Restore(town, backups[0].ID)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "edited\n" {
+		t.Errorf("restored content = %q, want the edited version", got)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("restored mode = %v, want 0600", info.Mode().Perm())
+	}
+	if b.Path != path {
+		t.Errorf("Restore returned %+v", b)
+	}
+
+	// The generated version the restore replaced was backed up in turn
+	backups, _ = List(town)
+	if len(backups) != 2 {
+		t.Fatalf("backups after restore = %d, want 2", len(backups))
+	}
+	if content, _, _ := Content(town, backups[0].ID); string(content) != "generated\n" {
+		t.Errorf("newest backup = %q, want the replaced generated version", content)
+	}
+
+	if _, err := Restore(town, "../mayor"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Restore outside the backups dir = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRemoveBacksUp(t *testing.T) {
+	town := newTown(t)
+	path := filepath.Join(town, "mayor", ".cursor", "rules", "gastown-rig-old.mdc")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("old rig rules\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(path); err != nil {
+		t.Errorf("Remove of a missing file = %v", err)
+	}
+	backups, _ := List(town)
+	if len(backups) != 1 {
+		t.Fatalf("backups = %d, want 1", len(backups))
+	}
+	if _, err := Restore(town, backups[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "old rig rules\n" {
+		t.Errorf("restored content = %q", got)
+	}
+}
+
+func TestSavePrunesOldBackups(t *testing.T) {
+	town := newTown(t)
+	path := filepath.Join(town, "mayor", "AGENTS.md")
+	other := filepath.Join(town, "deacon", "AGENTS.md")
+	for _, p := range []string{path, other} {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("v0"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := WriteFile(other, []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= KeepPerPath+3; i++ {
+		if err := WriteFile(path, []byte{'v', byte('0' + i)}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, _ := List(town)
+	counts := make(map[string]int)
+	for _, b := range backups {
+		counts[b.Path]++
+	}
+	if counts[path] != KeepPerPath || counts[other] != 1 {
+		t.Errorf("backups per path = %v, want %d for %s and 1 for %s", counts, KeepPerPath, path, other)
+	}
+}
+
+func TestSaveOutsideTown(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "GEMINI.md")
+	if err := os.WriteFile(path, []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("generated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	baks, _ := filepath.Glob(path + ".*.bak")
+	if len(baks) != 1 {
+		t.Fatalf(".bak files = %v, want one", baks)
+	}
+	if got, _ := os.ReadFile(baks[0]); string(got) != "mine" {
+		t.Errorf(".bak content = %q, want the previous version", got)
+	}
+}
@@ -0,0 +1,119 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// ConfigVars are the environment values generated agent config (Cursor
+// rules, hooks.json) can reference, e.g.
+// {{.TownName}} or {{shellquote .GTBin}}. Fields that do not apply to a
+// workspace are empty: RigName for the mayor and deacon, Session for the
+// shared crew and polecat config.
+type ConfigVars struct {
+	TownName string // e.g., "ai"
+	TownRoot string // e.g., "/Users/steve/ai"
+	RigName  string // e.g., "greenplace"
+	Role     string // mayor, deacon, witness, refinery, crew, polecat
+	Session  string // tmux session, e.g., "gt-greenplace-witness"
+	WorkDir  string // directory holding the .cursor/ config
+	GTBin    string // absolute path of the gt binary, "" if unknown
+
+	// ProtectedPaths are the owning rig's protected path patterns, which
+	// agents must not change without an approval.
+	ProtectedPaths []string
+}
+
+// GTBinDir returns the directory holding the gt binary, or "" when its
+// path is unknown.
+func (v ConfigVars) GTBinDir() string {
+	if !filepath.IsAbs(v.GTBin) {
+		return ""
+	}
+	return filepath.Dir(v.GTBin)
+}
+
+// GTCommand returns the command that runs the gt binary in a hook: its
+// absolute path, double-quoted when it holds spaces, or plain "gt" (found
+// on PATH) when its path is unknown.
+func (v ConfigVars) GTCommand() string {
+	if !filepath.IsAbs(v.GTBin) {
+		return "gt"
+	}
+	if strings.ContainsAny(v.GTBin, " \t") {
+		return `"` + v.GTBin + `"`
+	}
+	return v.GTBin
+}
+
+// Funcs are the custom functions available to every template gt renders:
+//
+//	shellquote  single-quotes a string for sh
+//	json        encodes a value as JSON (a quoted string for strings)
+//	default     returns its first argument when the second is empty
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"shellquote": shellQuote,
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"default": func(fallback, v string) string {
+			if v == "" {
+				return fallback
+			}
+			return v
+		},
+	}
+}
+
+// RenderConfig renders a config template with vars. name is used in error
+// messages. Content without template actions is returned unchanged.
+func RenderConfig(name string, content []byte, vars ConfigVars) ([]byte, error) {
+	if !bytes.Contains(content, []byte("{{")) {
+		return content, nil
+	}
+	tmpl, err := template.New(name).Funcs(Funcs()).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, fmt.Errorf("rendering template %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// GTBinary returns the absolute path of the gt binary: the running
+// executable when it is gt, otherwise gt on PATH, or "" when neither is
+// found (e.g. in tests).
+func GTBinary() string {
+	if exe, err := os.Executable(); err == nil {
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+		if filepath.Base(exe) == "gt" {
+			return exe
+		}
+	}
+	if path, err := exec.LookPath("gt"); err == nil {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+	}
+	return ""
+}
+
+// shellQuote single-quotes s for sh, escaping embedded single quotes.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderConfig(t *testing.T) {
+	vars := ConfigVars{TownName: "ai", RigName: "gp", Role: "witness", GTBin: "/opt/it's/gt"}
+	tests := []struct {
+		name, content, want string
+	}{
+		{"plain", "no actions, $PATH kept\n", "no actions, $PATH kept\n"},
+		{"vars", "{{.TownName}}/{{.RigName}}/{{.Role}}", "ai/gp/witness"},
+		{"shellquote", "export PATH={{shellquote .GTBinDir}}", `export PATH='/opt/it'\''s'`},
+		{"json", `{"rig": {{json .RigName}}}`, `{"rig": "gp"}`},
+		{"default", `{{default "none" .Session}}`, "none"},
+	}
+	for _, tt := range tests {
+		got, err := RenderConfig(tt.name, []byte(tt.content), vars)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := RenderConfig("bad.sh", []byte("{{.Nope}}"), vars); err == nil || !strings.Contains(err.Error(), "bad.sh") {
+		t.Errorf("unknown field: err = %v, want an error naming the template", err)
+	}
+}
+
+func TestConfigVars_GTBinDir(t *testing.T) {
+	if got := (ConfigVars{GTBin: "/usr/local/bin/gt"}).GTBinDir(); got != "/usr/local/bin" {
+		t.Errorf("GTBinDir() = %q", got)
+	}
+	if got := (ConfigVars{GTBin: "gt"}).GTBinDir(); got != "" {
+		t.Errorf("GTBinDir() for a relative path = %q, want empty", got)
+	}
+}
+
+func TestConfigVars_GTCommand(t *testing.T) {
+	tests := []struct{ bin, want string }{
+		{"/usr/local/bin/gt", "/usr/local/bin/gt"},
+		{"/opt/my tools/gt", `"/opt/my tools/gt"`},
+		{"", "gt"},
+	}
+	for _, tt := range tests {
+		if got := (ConfigVars{GTBin: tt.bin}).GTCommand(); got != tt.want {
+			t.Errorf("GTCommand() for %q = %q, want %q", tt.bin, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// Package selftest renders every agent config and role context template for
+// every role, agent, and platform combination with synthetic workspaces,
+// and validates the output, so template regressions are caught before they
+// reach live agents.
+package selftest
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cursorworkshop/cursor-gastown/internal/amp"
+	"github.com/cursorworkshop/cursor-gastown/internal/auggie"
+	"github.com/cursorworkshop/cursor-gastown/internal/codex"
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+	"github.com/cursorworkshop/cursor-gastown/internal/gemini"
+	"github.com/cursorworkshop/cursor-gastown/internal/session"
+	"github.com/cursorworkshop/cursor-gastown/internal/templates"
+)
+
+//go:embed fixtures.json
+var fixturesJSON []byte
+
+// Fixtures are the synthetic workspaces templates are rendered against.
+type Fixtures struct {
+	TownName       string     `json:"town_name"`
+	Rig            string     `json:"rig"`
+	ProtectedPaths []string   `json:"protected_paths"`
+	Roles          []string   `json:"roles"`
+	Agents         []string   `json:"agents"`
+	Platforms      []Platform `json:"platforms"`
+}
+
+// Platform is a synthetic town layout for one OS. Paths deliberately
+// include spaces and quotes to exercise shell quoting.
+type Platform struct {
+	OS       string `json:"os"`
+	TownRoot string `json:"town_root"`
+	GTBin    string `json:"gt_bin"`
+}
+
+// Combination is one role, agent, and platform to render templates for.
+type Combination struct {
+	Role     string
+	Agent    string
+	Platform Platform
+}
+
+// String names the combination, e.g. "linux/cursor/witness".
+func (c Combination) String() string {
+	return c.Platform.OS + "/" + c.Agent + "/" + c.Role
+}
+
+// Result is the rendered output of one combination and what is wrong
+// with it.
+type Result struct {
+	Combination
+	Files  []cursor.RenderedFile
+	Errors []string // "<file>: <problem>"
+}
+
+// OK reports whether every file rendered and validated.
+func (r Result) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// LoadFixtures returns the embedded fixtures.
+func LoadFixtures() (*Fixtures, error) {
+	var f Fixtures
+	if err := json.Unmarshal(fixturesJSON, &f); err != nil {
+		return nil, fmt.Errorf("parsing fixtures: %w", err)
+	}
+	return &f, nil
+}
+
+// Combinations returns every role, agent, and platform combination.
+func (f *Fixtures) Combinations() []Combination {
+	var combos []Combination
+	for _, p := range f.Platforms {
+		for _, agent := range f.Agents {
+			for _, role := range f.Roles {
+				combos = append(combos, Combination{Role: role, Agent: agent, Platform: p})
+			}
+		}
+	}
+	return combos
+}
+
+// workDir returns where a role's agent config lives in the synthetic town,
+// mirroring the directories the role managers install settings into.
+func (f *Fixtures) workDir(c Combination) string {
+	root := c.Platform.TownRoot
+	switch c.Role {
+	case "mayor", "deacon":
+		return filepath.Join(root, c.Role)
+	case "polecat":
+		return filepath.Join(root, f.Rig, "polecats")
+	default:
+		return filepath.Join(root, f.Rig, c.Role)
+	}
+}
+
+// Vars returns the config template variables for a combination.
+func (f *Fixtures) Vars(c Combination) templates.ConfigVars {
+	vars := templates.ConfigVars{
+		TownName: f.TownName,
+		TownRoot: c.Platform.TownRoot,
+		Role:     c.Role,
+		WorkDir:  f.workDir(c),
+		GTBin:    c.Platform.GTBin,
+	}
+	if c.Role != "mayor" && c.Role != "deacon" {
+		vars.RigName = f.Rig
+		vars.ProtectedPaths = f.ProtectedPaths
+	}
+	return vars
+}
+
+// roleData returns the role context template data for a combination.
+func (f *Fixtures) roleData(c Combination) templates.RoleData {
+	vars := f.Vars(c)
+	return templates.RoleData{
+		Role:          c.Role,
+		RigName:       vars.RigName,
+		TownRoot:      vars.TownRoot,
+		TownName:      vars.TownName,
+		WorkDir:       vars.WorkDir,
+		DefaultBranch: "main",
+		Polecat:       "Toast",
+		Polecats:      []string{"Toast", "Nux"},
+		BeadsDir:      filepath.Join(vars.TownRoot, ".beads"),
+		IssuePrefix:   "gt",
+		MayorSession:  session.MayorSessionName(),
+		DeaconSession: session.DeaconSessionName(),
+		GTBin:         vars.GTBin,
+	}
+}
+
+// Render renders every template for a combination: the agent's config
+// files and the role's context, including provider-specific variants.
+func (f *Fixtures) Render(c Combination) ([]cursor.RenderedFile, error) {
+	var files []cursor.RenderedFile
+	var err error
+	switch c.Agent {
+	case "cursor":
+		files, err = cursor.RenderTemplates(f.Vars(c), c.Role)
+	case "gemini":
+		files, err = gemini.RenderTemplates(f.Vars(c), c.Role)
+	case "codex":
+		files, err = codex.RenderTemplates(f.Vars(c), c.Role)
+	case "amp":
+		files, err = amp.RenderTemplates(f.Vars(c), c.Role)
+	case "auggie":
+		files, err = auggie.RenderTemplates(f.Vars(c), c.Role)
+	default:
+		return nil, fmt.Errorf("no config templates for agent %q", c.Agent)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := templates.New()
+	if err != nil {
+		return nil, err
+	}
+	data := f.roleData(c)
+	context, err := tmpl.RenderRole(c.Role, data)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, cursor.RenderedFile{Name: "context/" + c.Role + ".md", Content: []byte(context)})
+	for _, provider := range tmpl.ProviderTemplateNames()[c.Role] {
+		context, err := tmpl.RenderRoleForProvider(c.Role, provider, data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, cursor.RenderedFile{Name: "context/" + c.Role + "-" + provider + ".md", Content: []byte(context)})
+	}
+	return files, nil
+}
+
+// Run renders and validates every combination.
+func (f *Fixtures) Run() []Result {
+	var results []Result
+	for _, c := range f.Combinations() {
+		r := Result{Combination: c}
+		files, err := f.Render(c)
+		if err != nil {
+			r.Errors = append(r.Errors, err.Error())
+		}
+		r.Files = files
+		r.Errors = append(r.Errors, Validate(files)...)
+		results = append(results, r)
+	}
+	return results
+}
+
+// hookScriptRef matches a hook script referenced from a hooks config.
+var hookScriptRef = regexp.MustCompile(`\.(?:cursor|gemini|codex)/hooks/gastown-[A-Za-z0-9_-]+\.sh`)
+
+// Validate checks rendered files and returns their problems:
+//
+//	every file   rendered (no "<no value>") and not empty
+//	.json        valid JSON, and every hook script it runs is rendered too
+//	.toml        valid TOML, and every hook script it runs is rendered too
+//	.mdc         frontmatter Cursor can read
+//	.sh          a bash shebang, and valid syntax (bash -n) when bash is installed
+func Validate(files []cursor.RenderedFile) []string {
+	rendered := make(map[string]bool, len(files))
+	for _, f := range files {
+		rendered[f.Name] = true
+	}
+	bash, _ := exec.LookPath("bash")
+
+	var problems []string
+	for _, f := range files {
+		for _, err := range validateFile(f, rendered, bash) {
+			problems = append(problems, f.Name+": "+err)
+		}
+	}
+	return problems
+}
+
+func validateFile(f cursor.RenderedFile, rendered map[string]bool, bash string) []string {
+	if len(bytes.TrimSpace(f.Content)) == 0 {
+		return []string{"empty"}
+	}
+	var problems []string
+	if bytes.Contains(f.Content, []byte("<no value>")) {
+		problems = append(problems, "template references a missing value (<no value>)")
+	}
+	switch path.Ext(f.Name) {
+	case ".json", ".toml":
+		if path.Ext(f.Name) == ".json" {
+			var v any
+			if err := json.Unmarshal(f.Content, &v); err != nil {
+				problems = append(problems, "invalid JSON: "+err.Error())
+			}
+		} else {
+			var v map[string]any
+			if _, err := toml.Decode(string(f.Content), &v); err != nil {
+				problems = append(problems, "invalid TOML: "+err.Error())
+			}
+		}
+		for _, script := range hookScriptRef.FindAllString(string(f.Content), -1) {
+			if !rendered[script] {
+				problems = append(problems, "runs "+script+", which is not generated")
+			}
+		}
+	case ".mdc":
+		if err := cursor.ValidateRuleFrontmatter(f.Content); err != nil {
+			problems = append(problems, err.Error())
+		}
+	case ".sh":
+		if !bytes.HasPrefix(f.Content, []byte("#!/bin/bash\n")) {
+			problems = append(problems, "missing #!/bin/bash shebang")
+		}
+		if bash != "" {
+			cmd := exec.Command(bash, "-n") //nolint:gosec // G204: bash from PATH checks syntax only
+			cmd.Stdin = bytes.NewReader(f.Content)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				problems = append(problems, "bash syntax: "+strings.TrimSpace(string(out)))
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,108 @@
+package selftest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/")
+
+func TestAllCombinationsValid(t *testing.T) {
+	f, err := LoadFixtures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := f.Run()
+	if want := len(f.Roles) * len(f.Agents) * len(f.Platforms); len(results) != want {
+		t.Fatalf("got %d results, want %d", len(results), want)
+	}
+	for _, r := range results {
+		if len(r.Files) == 0 {
+			t.Errorf("%s rendered no files", r)
+		}
+		for _, problem := range r.Errors {
+			t.Errorf("%s: %s", r, problem)
+		}
+	}
+}
+
+// TestGolden compares the linux renders with testdata/<agent>-<role>.golden.
+// Run with -update after an intended template change and review the diff.
+func TestGolden(t *testing.T) {
+	f, err := LoadFixtures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range f.Combinations() {
+		if c.Platform.OS != "linux" {
+			continue
+		}
+		files, err := f.Render(c)
+		if err != nil {
+			t.Fatalf("%s: %v", c, err)
+		}
+		var got bytes.Buffer
+		for _, file := range files {
+			got.WriteString("==> " + file.Name + " <==\n")
+			got.Write(file.Content)
+			if !bytes.HasSuffix(file.Content, []byte("\n")) {
+				got.WriteString("\n")
+			}
+		}
+
+		path := filepath.Join("testdata", c.Agent+"-"+c.Role+".golden")
+		if *update {
+			if err := os.MkdirAll("testdata", 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, got.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v (run go test -update to create it)", c, err)
+		}
+		if !bytes.Equal(got.Bytes(), want) {
+			t.Errorf("%s differs from %s; run go test ./internal/templates/selftest -update and review the diff", c, path)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    cursor.RenderedFile
+		problem string
+	}{
+		{"empty", cursor.RenderedFile{Name: "GEMINI.md", Content: []byte("\n")}, "empty"},
+		{"missing value", cursor.RenderedFile{Name: "context/crew.md", Content: []byte("rig <no value>\n")}, "<no value>"},
+		{"bad json", cursor.RenderedFile{Name: ".cursor/hooks.json", Content: []byte(`{"hooks": `)}, "invalid JSON"},
+		{"missing script", cursor.RenderedFile{Name: ".cursor/hooks.json", Content: []byte(`{"command": ".cursor/hooks/gastown-gone.sh"}`)}, "not generated"},
+		{"bad frontmatter", cursor.RenderedFile{Name: ".cursor/rules/gastown.mdc", Content: []byte("# no frontmatter\n")}, "frontmatter"},
+		{"no shebang", cursor.RenderedFile{Name: ".cursor/hooks/gastown-stop.sh", Content: []byte("echo hi\n")}, "shebang"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := Validate([]cursor.RenderedFile{tt.file})
+			if len(problems) == 0 || !strings.Contains(strings.Join(problems, "\n"), tt.problem) {
+				t.Errorf("Validate() = %v, want a problem mentioning %q", problems, tt.problem)
+			}
+		})
+	}
+
+	valid := []cursor.RenderedFile{
+		{Name: ".gemini/settings.json", Content: []byte(`{"command": "bash -lc '.gemini/hooks/gastown-stop.sh'"}`)},
+		{Name: ".gemini/hooks/gastown-stop.sh", Content: []byte("#!/bin/bash\necho '{}'\n")},
+	}
+	if problems := Validate(valid); len(problems) > 0 {
+		t.Errorf("Validate(valid) = %v", problems)
+	}
+}
@@ -16,6 +16,17 @@ var templateFS embed.FS
 //go:embed commands/*.md
 var commandsFS embed.FS
 
+// OverrideDirName is the town-level directory holding template overrides.
+// roles/*.md.tmpl and messages/*.md.tmpl there replace (or add to) the
+// embedded templates of the same name; cursor/ overrides the Cursor rules
+// and hooks templates (see the cursor package).
+const OverrideDirName = "templates"
+
+// OverrideDir returns the template override directory for a town.
+func OverrideDir(townRoot string) string {
+	return filepath.Join(townRoot, OverrideDirName)
+}
+
 // Templates manages role and message templates.
 type Templates struct {
 	roleTemplates    *template.Template
@@ -38,6 +49,7 @@ type RoleData struct {
 	DeaconSession  string   // e.g., "gt-ai-deacon" - dynamic deacon session name
 	Provider       string   // model provider: "anthropic", "openai", "google" (for template selection)
 	Model          string   // specific model being used
+	GTBin          string   // absolute path of the gt binary (see GTBinary)
 }
 
 // SpawnData contains information for spawn assignment messages.
@@ -88,14 +100,14 @@ func New() (*Templates, error) {
 	t := &Templates{}
 
 	// Parse role templates
-	roleTempl, err := template.ParseFS(templateFS, "roles/*.md.tmpl")
+	roleTempl, err := template.New("roles").Funcs(Funcs()).ParseFS(templateFS, "roles/*.md.tmpl")
 	if err != nil {
 		return nil, fmt.Errorf("parsing role templates: %w", err)
 	}
 	t.roleTemplates = roleTempl
 
 	// Parse message templates
-	msgTempl, err := template.ParseFS(templateFS, "messages/*.md.tmpl")
+	msgTempl, err := template.New("messages").Funcs(Funcs()).ParseFS(templateFS, "messages/*.md.tmpl")
 	if err != nil {
 		return nil, fmt.Errorf("parsing message templates: %w", err)
 	}
@@ -104,6 +116,42 @@ func New() (*Templates, error) {
 	return t, nil
 }
 
+// NewForTown creates a Templates instance that prefers the town's overrides
+// in <townRoot>/templates/{roles,messages}/ over the embedded templates.
+// An empty townRoot or missing override directory yields the defaults.
+func NewForTown(townRoot string) (*Templates, error) {
+	t, err := New()
+	if err != nil || townRoot == "" {
+		return t, err
+	}
+	if err := applyOverrides(t.roleTemplates, filepath.Join(OverrideDir(townRoot), "roles")); err != nil {
+		return nil, err
+	}
+	if err := applyOverrides(t.messageTemplates, filepath.Join(OverrideDir(townRoot), "messages")); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// applyOverrides parses each *.md.tmpl in dir into set, replacing any
+// embedded template with the same file name.
+func applyOverrides(set *template.Template, dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.md.tmpl"))
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	for _, path := range paths {
+		content, err := os.ReadFile(path) //nolint:gosec // G304: path is within the town's templates directory
+		if err != nil {
+			return fmt.Errorf("reading template override: %w", err)
+		}
+		if _, err := set.New(filepath.Base(path)).Parse(string(content)); err != nil {
+			return fmt.Errorf("parsing template override %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
 // RenderRole renders a role context template.
 // If data.Provider is set and a provider-specific template exists, it uses that.
 // Otherwise, it falls back to the default template.
@@ -256,6 +304,13 @@ func CommandNames() ([]string, error) {
 	return names, nil
 }
 
+// IsBuiltinCommand reports whether content is the embedded version of the
+// named slash command, i.e. the command has not been customized.
+func IsBuiltinCommand(name string, content []byte) bool {
+	builtin, err := commandsFS.ReadFile("commands/" + name)
+	return err == nil && bytes.Equal(builtin, content)
+}
+
 // HasCommands checks if a workspace has the .cursor/commands/ directory provisioned.
 func HasCommands(workspacePath string) bool {
 	commandsDir := filepath.Join(workspacePath, ".cursor", "commands")
@@ -1,6 +1,8 @@
 package templates
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -15,6 +17,50 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewForTown_Overrides(t *testing.T) {
+	townRoot := t.TempDir()
+	rolesDir := filepath.Join(OverrideDir(townRoot), "roles")
+	if err := os.MkdirAll(rolesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	override := "# Team Mayor for {{ .TownName }}\n"
+	if err := os.WriteFile(filepath.Join(rolesDir, "mayor.md.tmpl"), []byte(override), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := NewForTown(townRoot)
+	if err != nil {
+		t.Fatalf("NewForTown() error = %v", err)
+	}
+
+	output, err := tmpl.RenderRole("mayor", RoleData{Role: "mayor", TownName: "town"})
+	if err != nil {
+		t.Fatalf("RenderRole() error = %v", err)
+	}
+	if output != "# Team Mayor for town\n" {
+		t.Errorf("RenderRole(mayor) = %q, want town override", output)
+	}
+
+	// Roles without an override use the embedded template
+	output, err = tmpl.RenderRole("crew", RoleData{Role: "crew", RigName: "myrig", Polecat: "max"})
+	if err != nil {
+		t.Fatalf("RenderRole() error = %v", err)
+	}
+	if strings.Contains(output, "Team Mayor") {
+		t.Error("crew context should not use the mayor override")
+	}
+}
+
+func TestNewForTown_NoOverrides(t *testing.T) {
+	tmpl, err := NewForTown(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewForTown() error = %v", err)
+	}
+	if len(tmpl.RoleNames()) == 0 {
+		t.Error("NewForTown() without overrides should keep embedded role templates")
+	}
+}
+
 func TestRenderRole_Mayor(t *testing.T) {
 	tmpl, err := New()
 	if err != nil {
@@ -0,0 +1,243 @@
+// Package testledger records test run outcomes in a ledger shared by every
+// agent in a rig.
+//
+// Polecats and crew record their runs with `gt tests record`; the refinery
+// records the runs it makes before merging. Each run is one JSON line in
+// <rig>/.runtime/test-runs.jsonl. The witness reads flaky runs from the
+// ledger into its patrol reports, and the refinery can require a recent
+// green run for a branch before merging it.
+package testledger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// File is the ledger path, relative to the rig root.
+const File = ".runtime/test-runs.jsonl"
+
+// ErrNoGreenRun is returned by RequireGreen when a branch has no recent
+// passing run.
+var ErrNoGreenRun = errors.New("no recent green test run")
+
+// Run is one recorded test run.
+type Run struct {
+	Time       time.Time `json:"ts"`
+	Suite      string    `json:"suite"` // e.g. "unit", "integration", "merge-queue"
+	Passed     bool      `json:"passed"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+
+	// Retries is how many failed attempts were retried before the final
+	// result. A passing run with retries is flaky.
+	Retries int `json:"retries,omitempty"`
+
+	// FailedTests names the tests that failed on any attempt, when known.
+	FailedTests []string `json:"failed_tests,omitempty"`
+
+	Branch string `json:"branch,omitempty"`
+	Commit string `json:"commit,omitempty"`
+	Actor  string `json:"actor,omitempty"` // e.g. "greenplace/Toast", "greenplace/refinery"
+}
+
+// Flaky reports whether the run passed only after retrying failures.
+func (r Run) Flaky() bool {
+	return r.Passed && r.Retries > 0
+}
+
+// Path returns the ledger path for a rig.
+func Path(rigPath string) string {
+	return filepath.Join(rigPath, File)
+}
+
+// mutex serializes appends from within one process.
+var mutex sync.Mutex
+
+// Record appends a run to the rig's ledger. A zero Time is set to now.
+func Record(rigPath string, run Run) error {
+	if run.Suite == "" {
+		return fmt.Errorf("test run has no suite")
+	}
+	if run.Time.IsZero() {
+		run.Time = time.Now()
+	}
+	run.Time = run.Time.UTC()
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("marshaling test run: %w", err)
+	}
+	data = append(data, '\n')
+
+	path := Path(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating ledger directory: %w", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: ledger is operational data
+	if err != nil {
+		return fmt.Errorf("opening test ledger: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("writing test run: %w", err)
+	}
+	return nil
+}
+
+// Load reads a rig's recorded runs, oldest first. A missing ledger has no
+// runs; lines that fail to parse are skipped.
+func Load(rigPath string) ([]Run, error) {
+	f, err := os.Open(Path(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil || run.Suite == "" {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return runs, err
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].Time.Before(runs[j].Time)
+	})
+	return runs, nil
+}
+
+// Since returns the runs at or after t. Runs must be oldest first.
+func Since(runs []Run, t time.Time) []Run {
+	i := sort.Search(len(runs), func(i int) bool {
+		return !runs[i].Time.Before(t)
+	})
+	return runs[i:]
+}
+
+// SuiteStats aggregates the runs of one suite.
+type SuiteStats struct {
+	Suite         string `json:"suite"`
+	Runs          int    `json:"runs"`
+	Passed        int    `json:"passed"`
+	Failed        int    `json:"failed"`
+	Flaky         int    `json:"flaky"` // passed after retries
+	AvgDurationMS int64  `json:"avg_duration_ms"`
+	Last          Run    `json:"last"`
+}
+
+// PassRate is the fraction of runs that passed, or 0 with no runs.
+func (s SuiteStats) PassRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Passed) / float64(s.Runs)
+}
+
+// History aggregates runs per suite, sorted by suite name. Runs must be
+// oldest first.
+func History(runs []Run) []SuiteStats {
+	bySuite := make(map[string]*SuiteStats)
+	durations := make(map[string][2]int64) // total ms, runs with a duration
+	for _, run := range runs {
+		s := bySuite[run.Suite]
+		if s == nil {
+			s = &SuiteStats{Suite: run.Suite}
+			bySuite[run.Suite] = s
+		}
+		s.Runs++
+		if run.Passed {
+			s.Passed++
+		} else {
+			s.Failed++
+		}
+		if run.Flaky() {
+			s.Flaky++
+		}
+		if run.DurationMS > 0 {
+			d := durations[run.Suite]
+			durations[run.Suite] = [2]int64{d[0] + run.DurationMS, d[1] + 1}
+		}
+		s.Last = run
+	}
+
+	stats := make([]SuiteStats, 0, len(bySuite))
+	for suite, s := range bySuite {
+		if d := durations[suite]; d[1] > 0 {
+			s.AvgDurationMS = d[0] / d[1]
+		}
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Suite < stats[j].Suite
+	})
+	return stats
+}
+
+// FlakyTests returns the tests that failed in runs that then passed on
+// retry, sorted. A flaky run that does not name its failed tests counts its
+// suite instead.
+func FlakyTests(runs []Run) []string {
+	seen := make(map[string]bool)
+	var tests []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			tests = append(tests, name)
+		}
+	}
+	for _, run := range runs {
+		if !run.Flaky() {
+			continue
+		}
+		if len(run.FailedTests) == 0 {
+			add(run.Suite)
+			continue
+		}
+		for _, test := range run.FailedTests {
+			add(test)
+		}
+	}
+	sort.Strings(tests)
+	return tests
+}
+
+// RequireGreen checks that branch's latest recorded run is green and no
+// older than within. Runs must be oldest first. The returned error wraps
+// ErrNoGreenRun.
+func RequireGreen(runs []Run, branch string, within time.Duration, now time.Time) error {
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		if run.Branch != branch {
+			continue
+		}
+		if now.Sub(run.Time) > within {
+			break
+		}
+		if !run.Passed {
+			return fmt.Errorf("%w: latest run of %s for %s failed at %s", ErrNoGreenRun, run.Suite, branch, run.Time.Local().Format("2006-01-02 15:04"))
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: no run recorded for %s in the last %s", ErrNoGreenRun, branch, within)
+}
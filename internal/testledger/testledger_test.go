@@ -0,0 +1,101 @@
+package testledger
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRecordAndLoad(t *testing.T) {
+	rigPath := t.TempDir()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if runs, err := Load(rigPath); err != nil || runs != nil {
+		t.Fatalf("Load on empty rig = %v, %v; want nil, nil", runs, err)
+	}
+
+	// Recorded out of order; Load sorts by time
+	for _, run := range []Run{
+		{Time: base.Add(time.Hour), Suite: "unit", Passed: true, Branch: "polecat/Toast"},
+		{Time: base, Suite: "unit", Passed: false, FailedTests: []string{"TestSync"}},
+	} {
+		if err := Record(rigPath, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Record(rigPath, Run{Passed: true}); err == nil {
+		t.Error("Record without a suite should fail")
+	}
+
+	// Garbage lines are skipped
+	f, err := os.OpenFile(Path(rigPath), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("not json\n")
+	_ = f.Close()
+
+	runs, err := Load(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || !runs[0].Time.Equal(base) || runs[1].Branch != "polecat/Toast" {
+		t.Fatalf("runs = %+v", runs)
+	}
+	if got := Since(runs, base.Add(time.Minute)); len(got) != 1 || !got[0].Passed {
+		t.Errorf("Since = %+v, want the passing run", got)
+	}
+}
+
+func TestHistoryAndFlakyTests(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	runs := []Run{
+		{Time: base, Suite: "unit", Passed: true, DurationMS: 1000},
+		{Time: base.Add(1 * time.Minute), Suite: "unit", Passed: true, Retries: 1, DurationMS: 3000, FailedTests: []string{"TestSync"}},
+		{Time: base.Add(2 * time.Minute), Suite: "integration", Passed: true, Retries: 2},
+		{Time: base.Add(3 * time.Minute), Suite: "unit", Passed: false, FailedTests: []string{"TestAuth"}},
+	}
+
+	stats := History(runs)
+	if len(stats) != 2 || stats[0].Suite != "integration" || stats[1].Suite != "unit" {
+		t.Fatalf("stats = %+v", stats)
+	}
+	unit := stats[1]
+	if unit.Runs != 3 || unit.Passed != 2 || unit.Failed != 1 || unit.Flaky != 1 {
+		t.Errorf("unit stats = %+v", unit)
+	}
+	if unit.AvgDurationMS != 2000 {
+		t.Errorf("unit avg duration = %d, want 2000 (runs without a duration are not averaged)", unit.AvgDurationMS)
+	}
+	if unit.Last.Passed || unit.PassRate() < 0.66 || unit.PassRate() > 0.67 {
+		t.Errorf("unit last = %+v, pass rate %.2f", unit.Last, unit.PassRate())
+	}
+
+	// TestAuth failed outright, so it is broken rather than flaky
+	want := []string{"TestSync", "integration"}
+	if got := FlakyTests(runs); !slices.Equal(got, want) {
+		t.Errorf("FlakyTests = %v, want %v", got, want)
+	}
+}
+
+func TestRequireGreen(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	runs := []Run{
+		{Time: now.Add(-30 * time.Hour), Suite: "unit", Passed: true, Branch: "polecat/Old"},
+		{Time: now.Add(-2 * time.Hour), Suite: "unit", Passed: true, Branch: "polecat/Toast"},
+		{Time: now.Add(-1 * time.Hour), Suite: "unit", Passed: false, Branch: "polecat/Nux"},
+		{Time: now.Add(-90 * time.Minute), Suite: "unit", Passed: true, Branch: "polecat/Nux"},
+	}
+	runs = append(runs[:2], runs[3], runs[2]) // oldest first
+
+	if err := RequireGreen(runs, "polecat/Toast", 24*time.Hour, now); err != nil {
+		t.Errorf("Toast: %v", err)
+	}
+	for _, branch := range []string{"polecat/Nux", "polecat/Old", "polecat/None"} {
+		if err := RequireGreen(runs, branch, 24*time.Hour, now); !errors.Is(err, ErrNoGreenRun) {
+			t.Errorf("%s: err = %v, want ErrNoGreenRun", branch, err)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 // Common errors
 var (
 	ErrNoServer        = errors.New("no tmux server running")
+	ErrNotInstalled    = errors.New("tmux is not installed")
 	ErrSessionExists   = errors.New("session already exists")
 	ErrSessionNotFound = errors.New("session not found")
 )
@@ -40,6 +42,9 @@ func (t *Tmux) run(args ...string) (string, error) {
 
 	err := cmd.Run()
 	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", ErrNotInstalled
+		}
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return "", fmt.Errorf("tmux %s: timeout", strings.Join(args, " "))
 		}
@@ -137,6 +142,60 @@ func (t *Tmux) IsAvailable() bool {
 	return cmd.Run() == nil
 }
 
+// Probe reports whether tmux is usable. It returns nil when the server is
+// reachable or simply not started (creating a session starts it),
+// ErrNotInstalled when the binary is missing, and otherwise the error from
+// querying the server (e.g. a timeout from a hung server).
+func (t *Tmux) Probe() error {
+	_, err := t.run("list-sessions", "-F", "#{session_name}")
+	if errors.Is(err, ErrNoServer) {
+		return nil
+	}
+	return err
+}
+
+// Version returns the installed tmux version, e.g. "3.3a" or "next-3.5".
+func (t *Tmux) Version() (string, error) {
+	out, err := t.run("-V")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(out, "tmux "), nil
+}
+
+// ParseVersion extracts the major and minor numbers from a Version string
+// ("3.3a" is 3, 3; "next-3.5" is 3, 5). ok is false for unnumbered builds
+// such as "master".
+func ParseVersion(v string) (major, minor int, ok bool) {
+	if i := strings.IndexAny(v, "0123456789"); i >= 0 {
+		v = v[i:]
+	}
+	if n, _ := fmt.Sscanf(v, "%d.%d", &major, &minor); n < 2 {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// GlobalOption returns the global value of a tmux option, or "" when it is
+// unset. It needs a running server.
+func (t *Tmux) GlobalOption(name string) (string, error) {
+	return t.run("show-options", "-gqv", name)
+}
+
+// SocketPath returns the server socket tmux commands connect to: the one
+// named in $TMUX inside a session, otherwise the default socket in
+// $TMUX_TMPDIR (or /tmp).
+func SocketPath() string {
+	if env := os.Getenv("TMUX"); env != "" {
+		return strings.SplitN(env, ",", 2)[0]
+	}
+	dir := os.Getenv("TMUX_TMPDIR")
+	if dir == "" {
+		dir = "/tmp"
+	}
+	return filepath.Join(dir, fmt.Sprintf("tmux-%d", os.Getuid()), "default")
+}
+
 // HasSession checks if a session exists (exact match).
 // Uses "=" prefix for exact matching, preventing prefix matches
 // (e.g., "gt-deacon-boot" won't match when checking for "gt-deacon").
@@ -394,6 +453,21 @@ func (t *Tmux) GetPaneWorkDir(session string) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// GetWindowActivity returns when a session's window last produced output.
+// Unlike session_activity, which tracks client input, this moves while an
+// agent is streaming output or running tools.
+func (t *Tmux) GetWindowActivity(session string) (time.Time, error) {
+	out, err := t.run("display-message", "-p", "-t", session, "#{window_activity}")
+	if err != nil {
+		return time.Time{}, err
+	}
+	var secs int64
+	if _, err := fmt.Sscanf(strings.TrimSpace(out), "%d", &secs); err != nil {
+		return time.Time{}, fmt.Errorf("parsing window activity %q: %w", out, err)
+	}
+	return time.Unix(secs, 0), nil
+}
+
 // FindSessionByWorkDir finds tmux sessions where the pane's current working directory
 // matches or is under the target directory. Returns session names that match.
 // If requireAgentRunning is true, only returns sessions that have some non-shell command running.
@@ -465,6 +539,46 @@ func (t *Tmux) SelectWindow(session string, index int) error {
 	return err
 }
 
+// NewWindow creates a background window in session running command in
+// workDir, and returns its window ID (e.g. "@3"). The session's active
+// window is left as it is.
+func (t *Tmux) NewWindow(session, name, workDir, command string) (string, error) {
+	args := []string{"new-window", "-d", "-P", "-F", "#{window_id}", "-t", session + ":", "-n", name}
+	if workDir != "" {
+		args = append(args, "-c", workDir)
+	}
+	return t.run(append(args, command)...)
+}
+
+// KillWindow kills a window (by window ID or session:window target) and
+// the processes in it.
+func (t *Tmux) KillWindow(target string) error {
+	_, err := t.run("kill-window", "-t", target)
+	return err
+}
+
+// WindowID returns the ID of the window containing pane.
+func (t *Tmux) WindowID(pane string) (string, error) {
+	return t.run("display-message", "-p", "-t", pane, "#{window_id}")
+}
+
+// ListWindowIDs returns the IDs of a session's windows.
+func (t *Tmux) ListWindowIDs(session string) ([]string, error) {
+	out, err := t.run("list-windows", "-t", session, "-F", "#{window_id}")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// RunShellBackground runs a shell command in the background of the tmux
+// server, so it outlives the process that started it and the pane it ran
+// from.
+func (t *Tmux) RunShellBackground(command string) error {
+	_, err := t.run("run-shell", "-b", command)
+	return err
+}
+
 // SetEnvironment sets an environment variable in the session.
 func (t *Tmux) SetEnvironment(session, key, value string) error {
 	_, err := t.run("set-environment", "-t", session, key, value)
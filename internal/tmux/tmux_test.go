@@ -1,6 +1,9 @@
 package tmux
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"testing"
@@ -41,6 +44,18 @@ func TestHasSessionNoServer(t *testing.T) {
 	}
 }
 
+func TestProbeNotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	tm := NewTmux()
+	if err := tm.Probe(); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Probe = %v, want ErrNotInstalled", err)
+	}
+	if _, err := tm.ListSessions(); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("ListSessions = %v, want ErrNotInstalled", err)
+	}
+}
+
 func TestSessionLifecycle(t *testing.T) {
 	if !hasTmux() {
 		t.Skip("tmux not installed")
@@ -425,3 +440,35 @@ func TestIsCursorRunning(t *testing.T) {
 		t.Errorf("IsCursorRunning() = %v, want %v (pane cmd: %q)", got, wantRunning, cmd)
 	}
 }
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in           string
+		major, minor int
+		ok           bool
+	}{
+		{"3.3a", 3, 3, true},
+		{"3.0", 3, 0, true},
+		{"next-3.5", 3, 5, true},
+		{"openbsd-7.4", 7, 4, true},
+		{"master", 0, 0, false},
+	}
+	for _, tt := range tests {
+		major, minor, ok := ParseVersion(tt.in)
+		if major != tt.major || minor != tt.minor || ok != tt.ok {
+			t.Errorf("ParseVersion(%q) = %d, %d, %v; want %d, %d, %v", tt.in, major, minor, ok, tt.major, tt.minor, tt.ok)
+		}
+	}
+}
+
+func TestSocketPath(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-501/work,1234,0")
+	if got := SocketPath(); got != "/tmp/tmux-501/work" {
+		t.Errorf("inside tmux: SocketPath = %q", got)
+	}
+	t.Setenv("TMUX", "")
+	t.Setenv("TMUX_TMPDIR", "/var/run")
+	if got, want := SocketPath(), fmt.Sprintf("/var/run/tmux-%d/default", os.Getuid()); got != want {
+		t.Errorf("SocketPath = %q, want %q", got, want)
+	}
+}
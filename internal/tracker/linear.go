@@ -0,0 +1,313 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+// LinearEndpoint is the Linear GraphQL API.
+const LinearEndpoint = "https://api.linear.app/graphql"
+
+// Linear is a Tracker backed by the Linear GraphQL API.
+type Linear struct {
+	apiKey     string
+	team       string
+	label      string
+	closeState string
+
+	// Endpoint is the GraphQL URL (overridden in tests).
+	Endpoint string
+	client   *http.Client
+}
+
+// NewLinear creates a Linear tracker with a resolved API key.
+func NewLinear(cfg *config.TrackerConfig, apiKey string) *Linear {
+	return &Linear{
+		apiKey:     apiKey,
+		team:       cfg.Team,
+		label:      cfg.Label,
+		closeState: cfg.CloseState,
+		Endpoint:   LinearEndpoint,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns "linear".
+func (l *Linear) Name() string { return config.TrackerLinear }
+
+type linearIssue struct {
+	ID          string  `json:"id"`
+	Identifier  string  `json:"identifier"`
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	URL         string  `json:"url"`
+	Priority    float64 `json:"priority"`
+	State       struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"state"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+const linearIssuesQuery = `query Issues($filter: IssueFilter, $after: String) {
+  issues(first: 100, after: $after, filter: $filter) {
+    nodes { id identifier title description url priority state { name type } labels { nodes { name } } }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+// OpenIssues returns the team's issues that are not completed or canceled,
+// limited to the configured label if any.
+func (l *Linear) OpenIssues(ctx context.Context) ([]Issue, error) {
+	filter := map[string]interface{}{
+		"team":  map[string]interface{}{"key": map[string]interface{}{"eq": l.team}},
+		"state": map[string]interface{}{"type": map[string]interface{}{"nin": []string{"completed", "canceled"}}},
+	}
+	if l.label != "" {
+		filter["labels"] = map[string]interface{}{"name": map[string]interface{}{"eq": l.label}}
+	}
+
+	var issues []Issue
+	var after interface{}
+	for {
+		var resp struct {
+			Issues struct {
+				Nodes    []linearIssue `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"issues"`
+		}
+		if err := l.do(ctx, linearIssuesQuery, map[string]interface{}{"filter": filter, "after": after}, &resp); err != nil {
+			return nil, err
+		}
+		for _, n := range resp.Issues.Nodes {
+			issues = append(issues, n.toIssue())
+		}
+		if !resp.Issues.PageInfo.HasNextPage {
+			return issues, nil
+		}
+		after = resp.Issues.PageInfo.EndCursor
+	}
+}
+
+func (n linearIssue) toIssue() Issue {
+	issue := Issue{
+		Key:      n.Identifier,
+		Title:    n.Title,
+		URL:      n.URL,
+		State:    n.State.Name,
+		Priority: linearPriority(int(n.Priority)),
+	}
+	if n.Description != nil {
+		issue.Description = *n.Description
+	}
+	for _, label := range n.Labels.Nodes {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	return issue
+}
+
+// linearPriority maps Linear priority (0 none, 1 urgent .. 4 low) to beads
+// priority (0 critical .. 4 backlog). Unprioritized issues get the default.
+func linearPriority(p int) int {
+	switch p {
+	case 1:
+		return 0
+	case 2:
+		return 1
+	case 3:
+		return 2
+	case 4:
+		return 3
+	default:
+		return 2
+	}
+}
+
+const linearIssueQuery = `query Issue($id: String!) {
+  issue(id: $id) {
+    id
+    state { type }
+    team { states(filter: { type: { eq: "completed" } }) { nodes { id name position } } }
+  }
+}`
+
+type linearIssueRef struct {
+	ID    string `json:"id"`
+	State struct {
+		Type string `json:"type"`
+	} `json:"state"`
+	Team struct {
+		States struct {
+			Nodes []struct {
+				ID       string  `json:"id"`
+				Name     string  `json:"name"`
+				Position float64 `json:"position"`
+			} `json:"nodes"`
+		} `json:"states"`
+	} `json:"team"`
+}
+
+// lookup resolves an issue identifier (e.g. "ENG-123").
+func (l *Linear) lookup(ctx context.Context, key string) (*linearIssueRef, error) {
+	var resp struct {
+		Issue *linearIssueRef `json:"issue"`
+	}
+	if err := l.do(ctx, linearIssueQuery, map[string]interface{}{"id": key}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Issue == nil {
+		return nil, fmt.Errorf("linear issue %s not found", key)
+	}
+	return resp.Issue, nil
+}
+
+const linearCommentMutation = `mutation Comment($input: CommentCreateInput!) {
+  commentCreate(input: $input) { success }
+}`
+
+// PostStatus comments on an issue.
+func (l *Linear) PostStatus(ctx context.Context, key, message string) error {
+	ref, err := l.lookup(ctx, key)
+	if err != nil {
+		return err
+	}
+	return l.comment(ctx, ref.ID, message)
+}
+
+func (l *Linear) comment(ctx context.Context, issueID, body string) error {
+	var resp struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+	input := map[string]interface{}{"issueId": issueID, "body": body}
+	if err := l.do(ctx, linearCommentMutation, map[string]interface{}{"input": input}, &resp); err != nil {
+		return err
+	}
+	if !resp.CommentCreate.Success {
+		return errors.New("linear did not create the comment")
+	}
+	return nil
+}
+
+const linearUpdateMutation = `mutation Update($id: String!, $input: IssueUpdateInput!) {
+  issueUpdate(id: $id, input: $input) { success }
+}`
+
+// Close comments on an issue and moves it to the configured close state
+// (default: the team's first completed state). Issues already completed or
+// canceled are only commented on.
+func (l *Linear) Close(ctx context.Context, key, message string) error {
+	ref, err := l.lookup(ctx, key)
+	if err != nil {
+		return err
+	}
+	if message != "" {
+		if err := l.comment(ctx, ref.ID, message); err != nil {
+			return err
+		}
+	}
+	if ref.State.Type == "completed" || ref.State.Type == "canceled" {
+		return nil
+	}
+
+	stateID := ""
+	bestPos := 0.0
+	for _, s := range ref.Team.States.Nodes {
+		if l.closeState != "" {
+			if strings.EqualFold(s.Name, l.closeState) {
+				stateID = s.ID
+				break
+			}
+			continue
+		}
+		if stateID == "" || s.Position < bestPos {
+			stateID, bestPos = s.ID, s.Position
+		}
+	}
+	if stateID == "" {
+		if l.closeState != "" {
+			return fmt.Errorf("linear team has no completed state named %q", l.closeState)
+		}
+		return errors.New("linear team has no completed workflow state")
+	}
+
+	var resp struct {
+		IssueUpdate struct {
+			Success bool `json:"success"`
+		} `json:"issueUpdate"`
+	}
+	vars := map[string]interface{}{"id": ref.ID, "input": map[string]interface{}{"stateId": stateID}}
+	if err := l.do(ctx, linearUpdateMutation, vars, &resp); err != nil {
+		return err
+	}
+	if !resp.IssueUpdate.Success {
+		return fmt.Errorf("linear did not close %s", key)
+	}
+	return nil
+}
+
+// do runs a GraphQL request and decodes its data into out.
+func (l *Linear) do(ctx context.Context, query string, vars map[string]interface{}, out interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", l.apiKey)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("linear request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return fmt.Errorf("reading linear response: %w", err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.New("linear rejected the API key (check tracker.api_key)")
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("linear: %s", resp.Status)
+	}
+	if len(result.Errors) > 0 {
+		msgs := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			msgs[i] = e.Message
+		}
+		return fmt.Errorf("linear: %s", strings.Join(msgs, "; "))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("linear: %s", resp.Status)
+	}
+	return json.Unmarshal(result.Data, out)
+}
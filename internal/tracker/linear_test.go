@@ -0,0 +1,177 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+)
+
+// fakeLinear serves canned GraphQL responses keyed by operation name and
+// records the variables of each request.
+type fakeLinear struct {
+	t         *testing.T
+	responses map[string]string
+	calls     map[string][]map[string]interface{}
+}
+
+func (f *fakeLinear) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if got := r.Header.Get("Authorization"); got != "lin_api_test" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var req struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		f.t.Fatalf("decoding request: %v", err)
+	}
+	for op, resp := range f.responses {
+		if strings.Contains(req.Query, op+"(") {
+			f.calls[op] = append(f.calls[op], req.Variables)
+			_, _ = w.Write([]byte(resp))
+			return
+		}
+	}
+	f.t.Fatalf("unexpected query: %s", req.Query)
+}
+
+func newFakeLinear(t *testing.T, cfg *config.TrackerConfig, responses map[string]string) (*Linear, *fakeLinear) {
+	t.Helper()
+	fake := &fakeLinear{t: t, responses: responses, calls: make(map[string][]map[string]interface{})}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	l := NewLinear(cfg, "lin_api_test")
+	l.Endpoint = srv.URL
+	return l, fake
+}
+
+func TestLinearOpenIssuesPaginates(t *testing.T) {
+	pages := []string{
+		`{"data":{"issues":{"nodes":[{"id":"u1","identifier":"ENG-1","title":"Fix login","description":"Steps...","url":"https://linear.app/x/issue/ENG-1","priority":1,"state":{"name":"Todo","type":"unstarted"},"labels":{"nodes":[{"name":"gastown"}]}}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}`,
+		`{"data":{"issues":{"nodes":[{"id":"u2","identifier":"ENG-2","title":"Docs","description":null,"url":"","priority":0,"state":{"name":"Backlog","type":"backlog"},"labels":{"nodes":[]}}],"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}`,
+	}
+	var calls []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_, _ = w.Write([]byte(pages[len(calls)]))
+		calls = append(calls, req.Variables)
+	}))
+	defer srv.Close()
+	l := NewLinear(&config.TrackerConfig{Type: config.TrackerLinear, Team: "ENG", Label: "gastown"}, "lin_api_test")
+	l.Endpoint = srv.URL
+
+	issues, err := l.OpenIssues(context.Background())
+	if err != nil {
+		t.Fatalf("OpenIssues: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+	if got := issues[0]; got.Key != "ENG-1" || got.Priority != 0 || got.State != "Todo" || got.Description != "Steps..." || len(got.Labels) != 1 {
+		t.Errorf("issue 0 = %+v", got)
+	}
+	if got := issues[1]; got.Key != "ENG-2" || got.Priority != 2 || got.Description != "" {
+		t.Errorf("issue 1 = %+v", got)
+	}
+
+	if len(calls) != 2 || calls[0]["after"] != nil || calls[1]["after"] != "c1" {
+		t.Errorf("pagination variables = %v", calls)
+	}
+	filter, _ := json.Marshal(calls[0]["filter"])
+	for _, want := range []string{`"key":{"eq":"ENG"}`, `"labels":{"name":{"eq":"gastown"}}`, `"nin":["completed","canceled"]`} {
+		if !strings.Contains(string(filter), want) {
+			t.Errorf("filter %s missing %s", filter, want)
+		}
+	}
+}
+
+const linearIssueRefResponse = `{"data":{"issue":{"id":"uuid-7","state":{"type":"started"},"team":{"states":{"nodes":[
+  {"id":"s-shipped","name":"Shipped","position":2},
+  {"id":"s-done","name":"Done","position":1}]}}}}}`
+
+func TestLinearCloseCommentsAndMovesToCompletedState(t *testing.T) {
+	cfg := &config.TrackerConfig{Type: config.TrackerLinear, Team: "ENG"}
+	l, fake := newFakeLinear(t, cfg, map[string]string{
+		"Issue":   linearIssueRefResponse,
+		"Comment": `{"data":{"commentCreate":{"success":true}}}`,
+		"Update":  `{"data":{"issueUpdate":{"success":true}}}`,
+	})
+
+	if err := l.Close(context.Background(), "ENG-7", "Merged in gt-mr-1"); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := fake.calls["Issue"][0]["id"]; got != "ENG-7" {
+		t.Errorf("lookup id = %v", got)
+	}
+	comment := fake.calls["Comment"][0]["input"].(map[string]interface{})
+	if comment["issueId"] != "uuid-7" || comment["body"] != "Merged in gt-mr-1" {
+		t.Errorf("comment input = %v", comment)
+	}
+	update := fake.calls["Update"][0]
+	if update["id"] != "uuid-7" || update["input"].(map[string]interface{})["stateId"] != "s-done" {
+		t.Errorf("update = %v, want lowest-position completed state", update)
+	}
+}
+
+func TestLinearCloseUsesConfiguredState(t *testing.T) {
+	cfg := &config.TrackerConfig{Type: config.TrackerLinear, Team: "ENG", CloseState: "shipped"}
+	l, fake := newFakeLinear(t, cfg, map[string]string{
+		"Issue":  linearIssueRefResponse,
+		"Update": `{"data":{"issueUpdate":{"success":true}}}`,
+	})
+	if err := l.Close(context.Background(), "ENG-7", ""); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := fake.calls["Update"][0]["input"].(map[string]interface{})["stateId"]; got != "s-shipped" {
+		t.Errorf("stateId = %v, want s-shipped", got)
+	}
+	if len(fake.calls["Comment"]) != 0 {
+		t.Error("empty message should not comment")
+	}
+}
+
+func TestLinearErrors(t *testing.T) {
+	cfg := &config.TrackerConfig{Type: config.TrackerLinear, Team: "ENG"}
+	l, _ := newFakeLinear(t, cfg, map[string]string{
+		"Issue": `{"data":{"issue":null},"errors":[{"message":"Entity not found: Issue"}]}`,
+	})
+	err := l.PostStatus(context.Background(), "ENG-404", "hi")
+	if err == nil || !strings.Contains(err.Error(), "Entity not found") {
+		t.Errorf("PostStatus err = %v, want GraphQL error", err)
+	}
+
+	l.apiKey = "wrong"
+	if err := l.PostStatus(context.Background(), "ENG-1", "hi"); err == nil || !strings.Contains(err.Error(), "API key") {
+		t.Errorf("bad key err = %v", err)
+	}
+}
+
+func TestUnimportedSkipsLinkedIssues(t *testing.T) {
+	l := NewLinear(&config.TrackerConfig{Type: config.TrackerLinear, Team: "ENG"}, "")
+	issues := []Issue{{Key: "ENG-1"}, {Key: "ENG-2"}, {Key: "ENG-3"}}
+	existing := []*beads.Issue{
+		{ID: "gt-a", Labels: []string{"linear:ENG-1"}},
+		{ID: "gt-b", Labels: []string{"bug", "linear:ENG-3"}},
+	}
+	got := Unimported(l, issues, existing)
+	if len(got) != 1 || got[0].Key != "ENG-2" {
+		t.Errorf("Unimported = %+v, want only ENG-2", got)
+	}
+
+	if key, ok := LinkedKey(l, existing[1].Labels); !ok || key != "ENG-3" {
+		t.Errorf("LinkedKey = %q, %v", key, ok)
+	}
+	if Label(l, "ENG-9") != "linear:ENG-9" {
+		t.Errorf("Label = %q", Label(l, "ENG-9"))
+	}
+}
@@ -0,0 +1,124 @@
+// Package tracker connects rigs to external issue trackers.
+//
+// A rig opts in with a "tracker" block in its settings/config.json. Issues
+// from the tracker are imported as beads labeled "<tracker>:<key>" (for
+// example "linear:ENG-123"); that label is how status updates and the
+// refinery's close-on-merge find the external issue again.
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/config"
+	"github.com/cursorworkshop/cursor-gastown/internal/secrets"
+)
+
+// ErrNotConfigured is returned when a rig has no tracker configured.
+var ErrNotConfigured = errors.New("no issue tracker configured")
+
+// Issue is an issue in an external tracker.
+type Issue struct {
+	Key         string   `json:"key"` // Human-readable identifier, e.g. "ENG-123"
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	State       string   `json:"state,omitempty"`
+	Priority    int      `json:"priority"` // Beads priority (0 = critical, 4 = backlog)
+	Labels      []string `json:"labels,omitempty"`
+}
+
+// Tracker is an external issue tracker.
+type Tracker interface {
+	// Name is the tracker type, used as the bead label prefix.
+	Name() string
+
+	// OpenIssues returns the issues to import as work items.
+	OpenIssues(ctx context.Context) ([]Issue, error)
+
+	// PostStatus adds a status comment to an issue.
+	PostStatus(ctx context.Context, key, message string) error
+
+	// Close marks an issue done, commenting with message if non-empty.
+	Close(ctx context.Context, key, message string) error
+}
+
+// New returns the tracker for a config, resolving its API key.
+func New(cfg *config.TrackerConfig) (Tracker, error) {
+	apiKey, err := secrets.Resolve(cfg.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("tracker api_key: %w", err)
+	}
+	switch cfg.Type {
+	case config.TrackerLinear:
+		return NewLinear(cfg, apiKey), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %q", config.ErrInvalidTracker, cfg.Type)
+	}
+}
+
+// ForRig returns the tracker configured in a rig's settings, or
+// ErrNotConfigured if the rig has none.
+func ForRig(rigPath string) (Tracker, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, ErrNotConfigured
+		}
+		return nil, err
+	}
+	if settings.Tracker == nil {
+		return nil, ErrNotConfigured
+	}
+	return New(settings.Tracker)
+}
+
+// Label returns the bead label linking a bead to a tracker issue.
+func Label(t Tracker, key string) string {
+	return t.Name() + ":" + key
+}
+
+// LinkedKey returns the key of the tracker issue a bead is linked to.
+func LinkedKey(t Tracker, labels []string) (string, bool) {
+	prefix := t.Name() + ":"
+	for _, l := range labels {
+		if key, ok := strings.CutPrefix(l, prefix); ok && key != "" {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// Unimported returns the issues no existing bead is linked to.
+func Unimported(t Tracker, issues []Issue, existing []*beads.Issue) []Issue {
+	linked := make(map[string]bool)
+	for _, b := range existing {
+		if key, ok := LinkedKey(t, b.Labels); ok {
+			linked[key] = true
+		}
+	}
+	var out []Issue
+	for _, issue := range issues {
+		if !linked[issue.Key] {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+// BeadDescription renders an imported issue's bead description.
+func BeadDescription(t Tracker, issue Issue) string {
+	var b strings.Builder
+	if issue.Description != "" {
+		b.WriteString(strings.TrimSpace(issue.Description))
+		b.WriteString("\n\n")
+	}
+	fmt.Fprintf(&b, "Imported from %s issue %s", t.Name(), issue.Key)
+	if issue.URL != "" {
+		fmt.Fprintf(&b, ": %s", issue.URL)
+	}
+	return b.String()
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/cursorworkshop/cursor-gastown/internal/beads"
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
 )
 
 // EventSource represents a source of events
@@ -214,9 +215,10 @@ func parseBeadContext(beadID string) (actor, rig, role string) {
 
 // GtEventsSource reads events from ~/gt/.events.jsonl (gt activity log)
 type GtEventsSource struct {
-	file   *os.File
-	events chan Event
-	cancel context.CancelFunc
+	townRoot string
+	file     *os.File
+	events   chan Event
+	cancel   context.CancelFunc
 }
 
 // GtEvent is the structure of events in .events.jsonl
@@ -227,6 +229,7 @@ type GtEvent struct {
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload"`
 	Visibility string                 `json:"visibility"`
+	Bodies     map[string]string      `json:"bodies"`
 }
 
 // NewGtEventsSource creates a source that tails ~/gt/.events.jsonl
@@ -240,9 +243,10 @@ func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	source := &GtEventsSource{
-		file:   file,
-		events: make(chan Event, 100),
-		cancel: cancel,
+		townRoot: townRoot,
+		file:     file,
+		events:   make(chan Event, 100),
+		cancel:   cancel,
 	}
 
 	go source.tail(ctx)
@@ -268,7 +272,7 @@ func (s *GtEventsSource) tail(ctx context.Context) {
 		case <-ticker.C:
 			for scanner.Scan() {
 				line := scanner.Text()
-				if event := parseGtEventLine(line); event != nil {
+				if event := parseGtEventLine(s.townRoot, line); event != nil {
 					select {
 					case s.events <- *event:
 					default:
@@ -290,8 +294,9 @@ func (s *GtEventsSource) Close() error {
 	return s.file.Close()
 }
 
-// parseGtEventLine parses a line from .events.jsonl
-func parseGtEventLine(line string) *Event {
+// parseGtEventLine parses a line from .events.jsonl, loading the bodies
+// stored outside the log (e.g. a nudge's reason) back into its payload.
+func parseGtEventLine(townRoot, line string) *Event {
 	if strings.TrimSpace(line) == "" {
 		return nil
 	}
@@ -305,6 +310,7 @@ func parseGtEventLine(line string) *Event {
 	if ge.Visibility != "feed" && ge.Visibility != "both" {
 		return nil
 	}
+	ge.Payload = events.WithBodies(townRoot, events.Event{Payload: ge.Payload, Bodies: ge.Bodies}).Payload
 
 	t, err := time.Parse(time.RFC3339, ge.Timestamp)
 	if err != nil {
@@ -390,6 +396,12 @@ func buildEventMessage(eventType string, payload map[string]interface{}) string
 		}
 		return "patrol complete"
 
+	case "patrol_report":
+		commits := getPayloadInt(payload, "commits_reviewed")
+		issues, _ := payload["issues"].([]interface{})
+		flaky, _ := payload["flaky_tests"].([]interface{})
+		return fmt.Sprintf("patrol report: %d commits, %d issues, %d flaky", commits, len(issues), len(flaky))
+
 	case "polecat_checked":
 		polecat := getPayloadString(payload, "polecat")
 		status := getPayloadString(payload, "status")
@@ -453,6 +465,13 @@ func buildEventMessage(eventType string, payload map[string]interface{}) string
 		}
 		return "work done"
 
+	case "progress":
+		percent := getPayloadInt(payload, "percent")
+		if note := getPayloadString(payload, "note"); note != "" {
+			return fmt.Sprintf("progress %d%%: %s", percent, note)
+		}
+		return fmt.Sprintf("progress %d%%", percent)
+
 	case "mail":
 		subject := getPayloadString(payload, "subject")
 		to := getPayloadString(payload, "to")
@@ -0,0 +1,38 @@
+package feed
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestParseGtEventLineLoadsBodies(t *testing.T) {
+	townRoot := t.TempDir()
+	payload := map[string]interface{}{"rig": "gastown", "polecat": "toast", "reason": events.Body("idle for 30m")}
+	if err := events.LogIn(townRoot, events.TypePolecatNudged, "gastown/witness", payload, events.VisibilityFeed); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatal("events log is empty")
+	}
+	line := scanner.Text()
+	if strings.Contains(line, "idle for 30m") {
+		t.Fatal("reason was logged inline, not as a body")
+	}
+
+	event := parseGtEventLine(townRoot, line)
+	if event == nil || event.Message != "nudged toast: idle for 30m" {
+		t.Errorf("event = %+v, want the nudge with its reason", event)
+	}
+}
@@ -135,6 +135,7 @@ var (
 		// Witness patrol events
 		"patrol_started":  constants.IconWitness,
 		"patrol_complete": "*",
+		"patrol_report":   "=",
 		"polecat_checked": ".",
 		"polecat_nudged":  "!",
 		"escalation_sent": "^",
@@ -155,5 +156,8 @@ var (
 		"nudge":   "!",
 		"boot":    "[boot]",
 		"halt":    "[halt]",
+
+		// Agent progress reports
+		"progress": "%",
 	}
 )
@@ -0,0 +1,36 @@
+package util
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	a := []byte("one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n")
+	b := []byte("one\n2\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n")
+
+	// One line of context keeps the two changes in separate hunks
+	want := `--- old
++++ new
+@@ -1,3 +1,3 @@
+ one
+-two
++2
+ three
+@@ -10 +10,2 @@
+ ten
++eleven
+`
+	if got := UnifiedDiff("old", "new", a, b, 1); got != want {
+		t.Errorf("context 1:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Four lines of context merge them into one hunk
+	if got := UnifiedDiff("old", "new", a, b, 4); got[len("--- old\n+++ new\n"):][:16] != "@@ -1,10 +1,11 @" {
+		t.Errorf("context 4 should produce one hunk:\n%s", got)
+	}
+
+	if got := UnifiedDiff("old", "new", a, a, 3); got != "" {
+		t.Errorf("equal inputs: %q, want no diff", got)
+	}
+	if got := UnifiedDiff("/dev/null", "new", nil, []byte("x\n"), 3); got != "--- /dev/null\n+++ new\n@@ -0,0 +1 @@\n+x\n" {
+		t.Errorf("new file diff = %q", got)
+	}
+}
@@ -3,8 +3,10 @@ package web
 
 import (
 	"embed"
+	"fmt"
 	"html/template"
 	"io/fs"
+	"time"
 
 	"github.com/cursorworkshop/cursor-gastown/internal/activity"
 )
@@ -68,6 +70,8 @@ func LoadTemplates() (*template.Template, error) {
 		"statusClass":     statusClass,
 		"workStatusClass": workStatusClass,
 		"progressPercent": progressPercent,
+		"timeAgo":         timeAgo,
+		"usd":             usd,
 	}
 
 	// Get the templates subdirectory
@@ -136,3 +140,26 @@ func progressPercent(completed, total int) int {
 	}
 	return (completed * 100) / total
 }
+
+// timeAgo renders how long ago t was, e.g. "5m ago".
+func timeAgo(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// usd formats a dollar amount, e.g. "$12.34".
+func usd(v float64) string {
+	return fmt.Sprintf("$%.2f", v)
+}
@@ -0,0 +1,250 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TokenCookie is the cookie that carries the access token after a browser
+// has authenticated once with ?token=.
+const TokenCookie = "gt_token"
+
+// TownSnapshot is the town state shown by the daemon's read-only web UI.
+// The daemon refreshes it once per heartbeat; requests never query tmux,
+// beads, or the filesystem themselves.
+type TownSnapshot struct {
+	UpdatedAt time.Time      `json:"updated_at"`
+	Daemon    DaemonStatus   `json:"daemon"`
+	Sessions  []string       `json:"sessions"`
+	Events    []EventRow     `json:"events"`
+	Costs     *CostSummary   `json:"costs,omitempty"`
+	Activity  *Activity      `json:"activity,omitempty"`
+	Doctor    *DoctorSummary `json:"doctor,omitempty"`
+}
+
+// DaemonStatus summarizes the daemon and overall town health.
+type DaemonStatus struct {
+	PID            int       `json:"pid"`
+	Version        string    `json:"version,omitempty"`
+	StartedAt      time.Time `json:"started_at"`
+	LastHeartbeat  time.Time `json:"last_heartbeat"`
+	HeartbeatCount int64     `json:"heartbeat_count"`
+	Health         string    `json:"health"` // "ok" or "degraded"
+	TmuxError      string    `json:"tmux_error,omitempty"`
+	UnreadMail     int       `json:"unread_mail"`
+	ActiveAlerts   int       `json:"active_alerts"`
+}
+
+// EventRow is one entry from the town events log.
+type EventRow struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Actor   string    `json:"actor"`
+	Summary string    `json:"summary,omitempty"`
+}
+
+// CostSummary is the cost ledger for a period, broken down for charting.
+type CostSummary struct {
+	Period string    `json:"period"`
+	Total  float64   `json:"total_usd"`
+	ByRole []CostBar `json:"by_role,omitempty"`
+	ByRig  []CostBar `json:"by_rig,omitempty"`
+}
+
+// CostBar is one bar of a cost chart.
+type CostBar struct {
+	Name    string  `json:"name"`
+	USD     float64 `json:"usd"`
+	Percent int     `json:"percent"` // Bar width relative to the largest bar
+}
+
+// NewCostBars converts a cost breakdown into bars sorted by cost, largest
+// first, scaled so the largest bar is 100%.
+func NewCostBars(costs map[string]float64) []CostBar {
+	bars := make([]CostBar, 0, len(costs))
+	largest := 0.0
+	for name, usd := range costs {
+		bars = append(bars, CostBar{Name: name, USD: usd})
+		if usd > largest {
+			largest = usd
+		}
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].USD != bars[j].USD {
+			return bars[i].USD > bars[j].USD
+		}
+		return bars[i].Name < bars[j].Name
+	})
+	for i := range bars {
+		if largest > 0 {
+			bars[i].Percent = int(bars[i].USD * 100 / largest)
+		}
+	}
+	return bars
+}
+
+// Activity counts recent feed events by type.
+type Activity struct {
+	Window string        `json:"window"` // e.g. "24h"
+	Total  int           `json:"total"`
+	ByType []ActivityBar `json:"by_type,omitempty"`
+}
+
+// ActivityBar is one bar of the activity chart.
+type ActivityBar struct {
+	Type    string `json:"type"`
+	Count   int    `json:"count"`
+	Percent int    `json:"percent"` // Bar width relative to the largest bar
+}
+
+// NewActivityBars converts event counts into bars sorted by count, largest
+// first, scaled so the largest bar is 100%.
+func NewActivityBars(counts map[string]int) []ActivityBar {
+	bars := make([]ActivityBar, 0, len(counts))
+	largest := 0
+	for typ, n := range counts {
+		bars = append(bars, ActivityBar{Type: typ, Count: n})
+		if n > largest {
+			largest = n
+		}
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Count != bars[j].Count {
+			return bars[i].Count > bars[j].Count
+		}
+		return bars[i].Type < bars[j].Type
+	})
+	for i := range bars {
+		if largest > 0 {
+			bars[i].Percent = bars[i].Count * 100 / largest
+		}
+	}
+	return bars
+}
+
+// DoctorSummary is the outcome of the most recent gt doctor run.
+type DoctorSummary struct {
+	RanAt    time.Time       `json:"ran_at"`
+	Rig      string          `json:"rig,omitempty"`
+	Fix      bool            `json:"fix"`
+	Counts   map[string]int  `json:"counts"`
+	Findings []DoctorFinding `json:"findings,omitempty"`
+}
+
+// DoctorFinding is a warning, error, or timeout from a doctor run.
+type DoctorFinding struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// SnapshotSource provides the latest town snapshot, or nil if none has been
+// taken yet.
+type SnapshotSource interface {
+	Snapshot() *TownSnapshot
+}
+
+// TownHandler serves the read-only town web UI: an HTML overview at / and
+// the raw snapshot at /api/snapshot. Every request must carry the access
+// token as a bearer token, a ?token= parameter, or the gt_token cookie.
+type TownHandler struct {
+	source   SnapshotSource
+	token    string
+	template *template.Template
+	mux      *http.ServeMux
+}
+
+// NewTownHandler creates a town UI handler. token must not be empty.
+func NewTownHandler(source SnapshotSource, token string) (*TownHandler, error) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &TownHandler{source: source, token: token, template: tmpl, mux: http.NewServeMux()}
+	h.mux.HandleFunc("/", h.serveIndex)
+	h.mux.HandleFunc("/api/snapshot", h.serveSnapshot)
+	return h, nil
+}
+
+// ServeHTTP authenticates the request and dispatches it.
+func (h *TownHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Read-only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if query := r.URL.Query().Get("token"); query != "" && h.validToken(query) {
+		// Remember the browser and drop the token from the address bar.
+		http.SetCookie(w, &http.Cookie{
+			Name:     TokenCookie,
+			Value:    query,
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteStrictMode,
+		})
+		clean := *r.URL
+		q := clean.Query()
+		q.Del("token")
+		clean.RawQuery = q.Encode()
+		http.Redirect(w, r, clean.RequestURI(), http.StatusSeeOther)
+		return
+	}
+
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="gastown"`)
+		http.Error(w, "Unauthorized: open the URL printed by 'gt daemon status' or pass the token from daemon/web.token", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Frame-Options", "DENY")
+	h.mux.ServeHTTP(w, r)
+}
+
+// authorized reports whether the request carries the access token.
+func (h *TownHandler) authorized(r *http.Request) bool {
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && h.validToken(bearer) {
+		return true
+	}
+	if c, err := r.Cookie(TokenCookie); err == nil && h.validToken(c.Value) {
+		return true
+	}
+	return false
+}
+
+func (h *TownHandler) validToken(candidate string) bool {
+	return h.token != "" && subtle.ConstantTimeCompare([]byte(candidate), []byte(h.token)) == 1
+}
+
+func (h *TownHandler) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := h.template.ExecuteTemplate(w, "town.html", h.source.Snapshot()); err != nil {
+		http.Error(w, "Failed to render template", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TownHandler) serveSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot := h.source.Snapshot()
+	if snapshot == nil {
+		http.Error(w, "No snapshot yet: waiting for the first daemon heartbeat", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(snapshot)
+}
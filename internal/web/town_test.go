@@ -0,0 +1,187 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type staticSource struct {
+	snapshot *TownSnapshot
+}
+
+func (s *staticSource) Snapshot() *TownSnapshot { return s.snapshot }
+
+func testSnapshot() *TownSnapshot {
+	return &TownSnapshot{
+		UpdatedAt: time.Now(),
+		Daemon: DaemonStatus{
+			PID:            42,
+			StartedAt:      time.Now().Add(-time.Hour),
+			LastHeartbeat:  time.Now(),
+			HeartbeatCount: 7,
+			Health:         "degraded",
+			ActiveAlerts:   1,
+		},
+		Sessions: []string{"gt-gastown-witness", "hq-mayor"},
+		Events:   []EventRow{{Time: time.Now(), Type: "sling", Actor: "mayor", Summary: "bead=gt-abc"}},
+		Costs: &CostSummary{
+			Period: "this week",
+			Total:  12.5,
+			ByRole: NewCostBars(map[string]float64{"polecat": 10, "mayor": 2.5}),
+		},
+		Doctor: &DoctorSummary{
+			RanAt:    time.Now(),
+			Counts:   map[string]int{"ok": 40, "warnings": 1, "errors": 0},
+			Findings: []DoctorFinding{{Check: "stale-git-locks", Status: "warning", Message: "1 stale lock"}},
+		},
+	}
+}
+
+func TestTownHandler_RequiresToken(t *testing.T) {
+	h, err := NewTownHandler(&staticSource{testSnapshot()}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(r *http.Request)
+		status int
+	}{
+		{"no token", func(r *http.Request) {}, http.StatusUnauthorized},
+		{"wrong bearer", func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, http.StatusUnauthorized},
+		{"bearer", func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") }, http.StatusOK},
+		{"cookie", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: TokenCookie, Value: "secret"}) }, http.StatusOK},
+		{"wrong cookie", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: TokenCookie, Value: "x"}) }, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			tt.setup(req)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestTownHandler_QueryTokenSetsCookie(t *testing.T) {
+	h, err := NewTownHandler(&staticSource{testSnapshot()}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/?token=secret", nil))
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusSeeOther)
+	}
+	if loc := w.Header().Get("Location"); strings.Contains(loc, "secret") {
+		t.Errorf("redirect %q still carries the token", loc)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != TokenCookie || cookies[0].Value != "secret" || !cookies[0].HttpOnly {
+		t.Errorf("cookies = %+v, want HttpOnly %s", cookies, TokenCookie)
+	}
+}
+
+func TestTownHandler_ReadOnly(t *testing.T) {
+	h, err := NewTownHandler(&staticSource{testSnapshot()}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestTownHandler_RendersSnapshot(t *testing.T) {
+	h, err := NewTownHandler(&staticSource{testSnapshot()}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body: %s", w.Code, w.Body.String())
+	}
+	body := w.Body.String()
+	for _, want := range []string{"degraded", "gt-gastown-witness", "hq-mayor", "sling", "bead=gt-abc", "$12.50", "polecat", "stale-git-locks", "1 stale lock"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+}
+
+func TestTownHandler_NoSnapshotYet(t *testing.T) {
+	h, err := NewTownHandler(&staticSource{}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, status := range map[string]int{"/": http.StatusOK, "/api/snapshot": http.StatusServiceUnavailable} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != status {
+			t.Errorf("%s status = %d, want %d", path, w.Code, status)
+		}
+		if path == "/" && !strings.Contains(w.Body.String(), "Waiting for the first daemon heartbeat") {
+			t.Errorf("page does not explain the missing snapshot")
+		}
+	}
+}
+
+func TestTownHandler_SnapshotJSON(t *testing.T) {
+	h, err := NewTownHandler(&staticSource{testSnapshot()}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/snapshot", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	var got TownSnapshot
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding snapshot: %v", err)
+	}
+	if got.Daemon.PID != 42 || len(got.Sessions) != 2 || got.Costs.Total != 12.5 {
+		t.Errorf("snapshot = %+v", got)
+	}
+}
+
+func TestNewCostBars(t *testing.T) {
+	bars := NewCostBars(map[string]float64{"mayor": 2, "polecat": 8, "crew": 2})
+	want := []CostBar{
+		{Name: "polecat", USD: 8, Percent: 100},
+		{Name: "crew", USD: 2, Percent: 25},
+		{Name: "mayor", USD: 2, Percent: 25},
+	}
+	if len(bars) != len(want) {
+		t.Fatalf("bars = %+v", bars)
+	}
+	for i := range want {
+		if bars[i] != want[i] {
+			t.Errorf("bars[%d] = %+v, want %+v", i, bars[i], want[i])
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/cursorworkshop/cursor-gastown/internal/config"
 	"github.com/cursorworkshop/cursor-gastown/internal/cursor"
 	"github.com/cursorworkshop/cursor-gastown/internal/constants"
+	"github.com/cursorworkshop/cursor-gastown/internal/preflight"
 	"github.com/cursorworkshop/cursor-gastown/internal/rig"
 	"github.com/cursorworkshop/cursor-gastown/internal/session"
 	"github.com/cursorworkshop/cursor-gastown/internal/tmux"
@@ -151,6 +152,15 @@ func (m *Manager) Start(foreground bool) error {
 		return fmt.Errorf("ensuring Cursor settings: %w", err)
 	}
 
+	if err := preflight.BeforeSpawn(preflight.Spec{
+		Session:     sessionID,
+		Role:        session.RoleWitness,
+		RigPath:     m.rig.Path,
+		SettingsDir: witnessParentDir,
+	}); err != nil {
+		return err
+	}
+
 	// Create new tmux session
 	if err := t.NewSession(sessionID, witnessDir); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
@@ -211,7 +221,7 @@ func (m *Manager) Start(foreground bool) error {
 	// Send the propulsion nudge to trigger autonomous patrol execution.
 	// Wait for beacon to be fully processed (needs to be separate prompt)
 	time.Sleep(2 * time.Second)
-	_ = t.NudgeSession(sessionID, session.PropulsionNudgeForRole("witness", witnessDir)) // Non-fatal
+	_ = t.NudgeSession(sessionID, session.PropulsionNudgeWithState("witness", witnessDir, m.StartupState())) // Non-fatal
 
 	return nil
 }
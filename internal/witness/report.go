@@ -0,0 +1,193 @@
+package witness
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+// PatrolReport is the structured outcome of one witness patrol cycle.
+// Reports are recorded as patrol_report events so patrol output is
+// accountable and can be compared across cycles.
+type PatrolReport struct {
+	Rig       string    `json:"rig"`
+	Cycle     string    `json:"cycle,omitempty"` // patrol wisp/molecule ID
+	Timestamp time.Time `json:"ts"`
+
+	CommitsReviewed int      `json:"commits_reviewed"`
+	Issues          []string `json:"issues,omitempty"`      // issues found (bead IDs or short descriptions)
+	FlakyTests      []string `json:"flaky_tests,omitempty"` // tests observed failing intermittently
+	PolecatsChecked int      `json:"polecats_checked"`
+	Nudges          int      `json:"nudges"`
+	Escalations     int      `json:"escalations"`
+	Notes           string   `json:"notes,omitempty"`
+}
+
+// Payload returns the report as an event payload.
+func (r *PatrolReport) Payload() map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":              r.Rig,
+		"commits_reviewed": r.CommitsReviewed,
+		"polecats_checked": r.PolecatsChecked,
+		"nudges":           r.Nudges,
+		"escalations":      r.Escalations,
+	}
+	if r.Cycle != "" {
+		p["cycle"] = r.Cycle
+	}
+	if len(r.Issues) > 0 {
+		p["issues"] = r.Issues
+	}
+	if len(r.FlakyTests) > 0 {
+		p["flaky_tests"] = r.FlakyTests
+	}
+	if r.Notes != "" {
+		p["notes"] = r.Notes
+	}
+	return p
+}
+
+// Summary is a one-line description of the report, used as a mail subject.
+func (r *PatrolReport) Summary() string {
+	return fmt.Sprintf("%d commit(s) reviewed, %d issue(s), %d flaky test(s)",
+		r.CommitsReviewed, len(r.Issues), len(r.FlakyTests))
+}
+
+// Body renders the report for mail.
+func (r *PatrolReport) Body() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rig: %s\n", r.Rig)
+	if r.Cycle != "" {
+		fmt.Fprintf(&b, "Cycle: %s\n", r.Cycle)
+	}
+	fmt.Fprintf(&b, "Commits reviewed: %d\n", r.CommitsReviewed)
+	fmt.Fprintf(&b, "Polecats checked: %d (nudged %d, escalated %d)\n", r.PolecatsChecked, r.Nudges, r.Escalations)
+	writeList(&b, "Issues found", r.Issues)
+	writeList(&b, "Flaky tests", r.FlakyTests)
+	if r.Notes != "" {
+		fmt.Fprintf(&b, "\n%s\n", r.Notes)
+	}
+	fmt.Fprintf(&b, "\nHistory: gt witness reports --rig %s\n", r.Rig)
+	return b.String()
+}
+
+func writeList(b *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		fmt.Fprintf(b, "%s: none\n", title)
+		return
+	}
+	fmt.Fprintf(b, "%s:\n", title)
+	for _, item := range items {
+		fmt.Fprintf(b, "  - %s\n", item)
+	}
+}
+
+// LoadReports reads a rig's patrol reports from the town events log,
+// oldest first. Copies of the same event (same ID) are counted once.
+func LoadReports(townRoot, rigName string) ([]PatrolReport, error) {
+	entries, _, err := events.ReadLog(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []PatrolReport
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		var ev struct {
+			Type    string          `json:"type"`
+			Payload json.RawMessage `json:"payload"`
+		}
+		if json.Unmarshal(entry.Raw, &ev) != nil || ev.Type != events.TypePatrolReport {
+			continue
+		}
+		var r PatrolReport
+		if json.Unmarshal(ev.Payload, &r) != nil || r.Rig != rigName || seen[entry.Key] {
+			continue
+		}
+		seen[entry.Key] = true
+		r.Timestamp = entry.Timestamp
+		reports = append(reports, r)
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Timestamp.Before(reports[j].Timestamp)
+	})
+	return reports, nil
+}
+
+// ReportTrend compares the latest report with the average of the reports
+// before it.
+type ReportTrend struct {
+	Latest   PatrolReport `json:"latest"`
+	Baseline int          `json:"baseline_reports"` // number of earlier reports averaged
+
+	AvgCommitsReviewed float64 `json:"avg_commits_reviewed"`
+	AvgIssues          float64 `json:"avg_issues"`
+	AvgFlakyTests      float64 `json:"avg_flaky_tests"`
+	AvgEscalations     float64 `json:"avg_escalations"`
+
+	// RecurringFlaky lists tests reported flaky in more than one report,
+	// most frequent first.
+	RecurringFlaky []FlakyTestCount `json:"recurring_flaky,omitempty"`
+}
+
+// FlakyTestCount is how many reports named a flaky test.
+type FlakyTestCount struct {
+	Test    string `json:"test"`
+	Reports int    `json:"reports"`
+}
+
+// Trend compares the newest report with up to window earlier reports.
+// Reports must be oldest first. Returns nil if there are no reports.
+func Trend(reports []PatrolReport, window int) *ReportTrend {
+	if len(reports) == 0 {
+		return nil
+	}
+	latest := reports[len(reports)-1]
+	earlier := reports[:len(reports)-1]
+	if window > 0 && len(earlier) > window {
+		earlier = earlier[len(earlier)-window:]
+	}
+
+	t := &ReportTrend{Latest: latest, Baseline: len(earlier)}
+	if n := float64(len(earlier)); n > 0 {
+		for _, r := range earlier {
+			t.AvgCommitsReviewed += float64(r.CommitsReviewed)
+			t.AvgIssues += float64(len(r.Issues))
+			t.AvgFlakyTests += float64(len(r.FlakyTests))
+			t.AvgEscalations += float64(r.Escalations)
+		}
+		t.AvgCommitsReviewed /= n
+		t.AvgIssues /= n
+		t.AvgFlakyTests /= n
+		t.AvgEscalations /= n
+	}
+
+	counts := make(map[string]int)
+	for _, r := range reports[len(reports)-1-len(earlier):] { // earlier + latest
+		seen := make(map[string]bool)
+		for _, test := range r.FlakyTests {
+			if !seen[test] {
+				seen[test] = true
+				counts[test]++
+			}
+		}
+	}
+	for test, n := range counts {
+		if n > 1 {
+			t.RecurringFlaky = append(t.RecurringFlaky, FlakyTestCount{Test: test, Reports: n})
+		}
+	}
+	sort.Slice(t.RecurringFlaky, func(i, j int) bool {
+		a, b := t.RecurringFlaky[i], t.RecurringFlaky[j]
+		if a.Reports != b.Reports {
+			return a.Reports > b.Reports
+		}
+		return a.Test < b.Test
+	})
+	return t
+}
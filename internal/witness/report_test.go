@@ -0,0 +1,68 @@
+package witness
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+)
+
+func TestLoadReports(t *testing.T) {
+	townRoot := t.TempDir()
+	lines := []string{
+		`{"id":"01B","ts":"2026-01-01T11:00:00Z","type":"patrol_report","actor":"gp/witness","payload":{"rig":"gp","commits_reviewed":2,"issues":["gp-1"]}}`,
+		`{"id":"01A","ts":"2026-01-01T10:00:00Z","type":"patrol_report","actor":"gp/witness","payload":{"rig":"gp","commits_reviewed":5}}`,
+		// Duplicate copy of the same event, another rig, another type.
+		`{"id":"01B","ts":"2026-01-01T11:00:00Z","type":"patrol_report","actor":"gp/witness","payload":{"rig":"gp","commits_reviewed":2,"issues":["gp-1"]}}`,
+		`{"id":"01C","ts":"2026-01-01T12:00:00Z","type":"patrol_report","actor":"other/witness","payload":{"rig":"other"}}`,
+		`{"id":"01D","ts":"2026-01-01T12:00:00Z","type":"patrol_complete","actor":"gp/witness","payload":{"rig":"gp"}}`,
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := LoadReports(townRoot, "gp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("got %d reports, want 2", len(reports))
+	}
+	if reports[0].CommitsReviewed != 5 || reports[1].CommitsReviewed != 2 {
+		t.Errorf("reports not oldest first: %+v", reports)
+	}
+	if reports[1].Timestamp.Hour() != 11 || len(reports[1].Issues) != 1 {
+		t.Errorf("report[1] = %+v", reports[1])
+	}
+}
+
+func TestTrend(t *testing.T) {
+	if Trend(nil, 10) != nil {
+		t.Error("Trend(nil) should be nil")
+	}
+
+	reports := []PatrolReport{
+		{CommitsReviewed: 100, FlakyTests: []string{"TestOld"}}, // outside window
+		{CommitsReviewed: 2, FlakyTests: []string{"TestA"}},
+		{CommitsReviewed: 4, Issues: []string{"x", "y"}, FlakyTests: []string{"TestA", "TestB"}},
+		{CommitsReviewed: 1, Issues: []string{"z"}, FlakyTests: []string{"TestB", "TestA", "TestA"}},
+	}
+	trend := Trend(reports, 2)
+	if trend.Baseline != 2 || trend.Latest.CommitsReviewed != 1 {
+		t.Fatalf("trend = %+v", trend)
+	}
+	if trend.AvgCommitsReviewed != 3 || trend.AvgIssues != 1 || trend.AvgFlakyTests != 1.5 {
+		t.Errorf("averages = %v/%v/%v, want 3/1/1.5", trend.AvgCommitsReviewed, trend.AvgIssues, trend.AvgFlakyTests)
+	}
+	want := []FlakyTestCount{{"TestA", 3}, {"TestB", 2}}
+	if len(trend.RecurringFlaky) != len(want) {
+		t.Fatalf("RecurringFlaky = %v, want %v", trend.RecurringFlaky, want)
+	}
+	for i := range want {
+		if trend.RecurringFlaky[i] != want[i] {
+			t.Errorf("RecurringFlaky[%d] = %v, want %v", i, trend.RecurringFlaky[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package witness
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+	"github.com/cursorworkshop/cursor-gastown/internal/mrqueue"
+	"github.com/cursorworkshop/cursor-gastown/internal/workspace"
+)
+
+// failureWindow is how far back the startup state looks for merge failures.
+const failureWindow = 24 * time.Hour
+
+// maxListedPolecats caps how many polecats the startup state names.
+const maxListedPolecats = 5
+
+// StartupState summarizes the rig for the witness's opening prompt (see
+// session.PropulsionNudgeWithState): unread protocol mail waiting for
+// review, polecats to check, recent merge failures, and the last patrol
+// report. Each item is a short clause; unreadable sources are left out.
+func (m *Manager) StartupState() []string {
+	var state []string
+	townRoot, _ := workspace.Find(m.rig.Path)
+
+	if townRoot != "" {
+		mailbox := mail.NewMailboxFromAddress(m.rig.Name+"/witness", townRoot)
+		if unread, err := mailbox.ListUnread(); err == nil {
+			state = append(state, inboxState(unread))
+		}
+	}
+
+	if s := polecatState(m.rig.Polecats); s != "" {
+		state = append(state, s)
+	}
+
+	logger := mrqueue.NewEventLoggerFromRig(m.rig.Path)
+	if evs, err := logger.ReadEvents(time.Now().Add(-failureWindow)); err == nil {
+		if s := mergeFailureState(evs); s != "" {
+			state = append(state, s)
+		}
+	}
+
+	if townRoot != "" {
+		if reports, err := LoadReports(townRoot, m.rig.Name); err == nil && len(reports) > 0 {
+			state = append(state, reportState(reports[len(reports)-1]))
+		}
+	}
+	return state
+}
+
+// inboxState counts unread witness mail by protocol message type.
+func inboxState(unread []*mail.Message) string {
+	if len(unread) == 0 {
+		return "inbox empty"
+	}
+	counts := make(map[ProtocolType]int)
+	for _, msg := range unread {
+		counts[ClassifyMessage(msg.Subject)]++
+	}
+	var parts []string
+	for _, c := range []struct {
+		proto ProtocolType
+		label string
+	}{
+		{ProtoPolecatDone, "POLECAT_DONE awaiting review"},
+		{ProtoHelp, "HELP request(s)"},
+		{ProtoMergeFailed, "MERGE_FAILED notice(s)"},
+		{ProtoMerged, "MERGED notice(s) to clean up"},
+	} {
+		if n := counts[c.proto]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, c.label))
+			delete(counts, c.proto)
+		}
+	}
+	other := 0
+	for _, n := range counts {
+		other += n
+	}
+	if other > 0 {
+		parts = append(parts, fmt.Sprintf("%d other message(s)", other))
+	}
+	return "unread: " + strings.Join(parts, ", ")
+}
+
+// polecatState names the rig's polecats.
+func polecatState(polecats []string) string {
+	if len(polecats) == 0 {
+		return "no polecats"
+	}
+	names := polecats
+	if len(names) > maxListedPolecats {
+		names = append(names[:maxListedPolecats:maxListedPolecats], "…")
+	}
+	return fmt.Sprintf("%d polecat(s) to check: %s", len(polecats), strings.Join(names, ", "))
+}
+
+// mergeFailureState names the polecats whose merges failed recently, who
+// may need rework.
+func mergeFailureState(evs []mrqueue.Event) string {
+	failures := 0
+	workers := make(map[string]bool)
+	for _, ev := range evs {
+		if ev.Type != mrqueue.EventMergeFailed {
+			continue
+		}
+		failures++
+		if ev.Worker != "" {
+			workers[ev.Worker] = true
+		}
+	}
+	if failures == 0 {
+		return ""
+	}
+	s := fmt.Sprintf("%d merge failure(s) in the last 24h", failures)
+	if len(workers) > 0 {
+		names := make([]string, 0, len(workers))
+		for w := range workers {
+			names = append(names, w)
+		}
+		sort.Strings(names)
+		s += " for " + strings.Join(names, ", ")
+	}
+	return s
+}
+
+// reportState summarizes the last patrol report.
+func reportState(r PatrolReport) string {
+	s := fmt.Sprintf("last patrol %s ago found %d issue(s)", formatSince(r.Timestamp), len(r.Issues))
+	if len(r.FlakyTests) > 0 {
+		s += fmt.Sprintf(", %d flaky test(s)", len(r.FlakyTests))
+	}
+	if r.Escalations > 0 {
+		s += fmt.Sprintf(", %d escalation(s)", r.Escalations)
+	}
+	return s
+}
+
+// formatSince formats the time since t compactly, e.g. "45m" or "3h".
+func formatSince(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,86 @@
+package witness
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/events"
+	"github.com/cursorworkshop/cursor-gastown/internal/mail"
+	"github.com/cursorworkshop/cursor-gastown/internal/mrqueue"
+	"github.com/cursorworkshop/cursor-gastown/internal/rig"
+)
+
+func TestStartupState(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gp")
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town","name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	report := fmt.Sprintf(`{"id":"01A","ts":%q,"type":"patrol_report","actor":"gp/witness","payload":{"rig":"gp","issues":["gp-1","gp-2"],"flaky_tests":["TestA"],"escalations":1}}`+"\n",
+		now.Add(-2*time.Hour).UTC().Format(time.RFC3339))
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(report), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := mrqueue.NewEventLoggerFromRig(rigPath)
+	for _, ev := range []mrqueue.Event{
+		{Type: mrqueue.EventMergeFailed, MRID: "mr-1", Worker: "toast"},
+		{Type: mrqueue.EventMergeFailed, MRID: "mr-2", Worker: "nux"},
+		{Type: mrqueue.EventMergeFailed, MRID: "mr-1", Worker: "toast"},
+	} {
+		if err := logger.LogEvent(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mgr := NewManager(&rig.Rig{Name: "gp", Path: rigPath, Polecats: []string{"toast", "nux"}})
+	got := mgr.StartupState()
+	// The inbox needs bd, so only the other items are checked
+	for _, want := range []string{
+		"2 polecat(s) to check: toast, nux",
+		"3 merge failure(s) in the last 24h for nux, toast",
+		"last patrol 2h ago found 2 issue(s), 1 flaky test(s), 1 escalation(s)",
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("StartupState = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestInboxState(t *testing.T) {
+	if got := inboxState(nil); got != "inbox empty" {
+		t.Errorf("inboxState(nil) = %q", got)
+	}
+	unread := []*mail.Message{
+		{Subject: "POLECAT_DONE toast"},
+		{Subject: "POLECAT_DONE nux"},
+		{Subject: "HELP: stuck on tests"},
+		{Subject: "Lunch?"},
+	}
+	want := "unread: 2 POLECAT_DONE awaiting review, 1 HELP request(s), 1 other message(s)"
+	if got := inboxState(unread); got != want {
+		t.Errorf("inboxState = %q, want %q", got, want)
+	}
+}
+
+func TestPolecatState(t *testing.T) {
+	if got := polecatState(nil); got != "no polecats" {
+		t.Errorf("polecatState(nil) = %q", got)
+	}
+	many := []string{"a", "b", "c", "d", "e", "f", "g"}
+	if got := polecatState(many); got != "7 polecat(s) to check: a, b, c, d, e, …" {
+		t.Errorf("polecatState = %q", got)
+	}
+	if len(many) != 7 || many[5] != "f" {
+		t.Errorf("polecatState modified its argument: %q", many)
+	}
+}
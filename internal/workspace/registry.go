@@ -0,0 +1,138 @@
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cursorworkshop/cursor-gastown/internal/util"
+)
+
+// RegistryEnv overrides the location of the town registry file.
+const RegistryEnv = "GT_TOWN_REGISTRY"
+
+// RegisteredTown is a town root recorded in the machine-wide registry.
+type RegisteredTown struct {
+	Root     string    `json:"root"`
+	Name     string    `json:"name,omitempty"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// townRegistry is the on-disk registry of known town roots.
+type townRegistry struct {
+	Towns []RegisteredTown `json:"towns"`
+}
+
+// RegistryPath returns the path of the machine-wide town registry:
+// $GT_TOWN_REGISTRY, or towns.json under the user config dir
+// (e.g. ~/.config/gastown/towns.json).
+func RegistryPath() (string, error) {
+	if p := os.Getenv(RegistryEnv); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config dir: %w", err)
+	}
+	return filepath.Join(dir, "gastown", "towns.json"), nil
+}
+
+func loadRegistry(path string) (*townRegistry, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the registry location
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &townRegistry{}, nil
+		}
+		return nil, err
+	}
+	var reg townRegistry
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &reg, nil
+}
+
+// RegisterTown records a town root in the registry, refreshing its name
+// and last-seen time if it is already known.
+func RegisterTown(townRoot string) error {
+	path, err := RegistryPath()
+	if err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(townRoot)
+	if err != nil {
+		return fmt.Errorf("resolving path: %w", err)
+	}
+	reg, err := loadRegistry(path)
+	if err != nil {
+		return err
+	}
+
+	entry := RegisteredTown{Root: abs, LastSeen: time.Now().UTC()}
+	if name, err := GetTownName(abs); err == nil {
+		entry.Name = name
+	}
+
+	found := false
+	for i := range reg.Towns {
+		if reg.Towns[i].Root == abs {
+			reg.Towns[i] = entry
+			found = true
+			break
+		}
+	}
+	if !found {
+		reg.Towns = append(reg.Towns, entry)
+	}
+	sort.Slice(reg.Towns, func(i, j int) bool { return reg.Towns[i].Root < reg.Towns[j].Root })
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating registry dir: %w", err)
+	}
+	return util.AtomicWriteJSON(path, reg)
+}
+
+// UnregisterTown removes a town root from the registry.
+// Returns false if it was not registered.
+func UnregisterTown(townRoot string) (bool, error) {
+	path, err := RegistryPath()
+	if err != nil {
+		return false, err
+	}
+	abs, err := filepath.Abs(townRoot)
+	if err != nil {
+		return false, fmt.Errorf("resolving path: %w", err)
+	}
+	reg, err := loadRegistry(path)
+	if err != nil {
+		return false, err
+	}
+
+	kept := reg.Towns[:0]
+	for _, t := range reg.Towns {
+		if t.Root != abs {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == len(reg.Towns) {
+		return false, nil
+	}
+	reg.Towns = kept
+	return true, util.AtomicWriteJSON(path, reg)
+}
+
+// KnownTowns returns every registered town, sorted by root.
+func KnownTowns() ([]RegisteredTown, error) {
+	path, err := RegistryPath()
+	if err != nil {
+		return nil, err
+	}
+	reg, err := loadRegistry(path)
+	if err != nil {
+		return nil, err
+	}
+	return reg.Towns, nil
+}
@@ -0,0 +1,55 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTownRegistry(t *testing.T) {
+	t.Setenv(RegistryEnv, filepath.Join(t.TempDir(), "towns.json"))
+
+	townA := t.TempDir()
+	townB := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townA, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townA, PrimaryMarker), []byte(`{"type":"town","version":2,"name":"alpha"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if towns, err := KnownTowns(); err != nil || len(towns) != 0 {
+		t.Fatalf("KnownTowns() on empty registry = %v, %v", towns, err)
+	}
+
+	for _, root := range []string{townA, townB, townA} {
+		if err := RegisterTown(root); err != nil {
+			t.Fatalf("RegisterTown(%s): %v", root, err)
+		}
+	}
+	towns, err := KnownTowns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(towns) != 2 {
+		t.Fatalf("KnownTowns() = %d towns, want 2 (re-registering is idempotent)", len(towns))
+	}
+	for _, town := range towns {
+		if town.Root == townA && town.Name != "alpha" {
+			t.Errorf("town name = %q, want alpha", town.Name)
+		}
+		if town.LastSeen.IsZero() {
+			t.Errorf("LastSeen not recorded for %s", town.Root)
+		}
+	}
+
+	if removed, err := UnregisterTown(townB); err != nil || !removed {
+		t.Fatalf("UnregisterTown() = %v, %v", removed, err)
+	}
+	if removed, _ := UnregisterTown(townB); removed {
+		t.Error("UnregisterTown() of unknown town should report false")
+	}
+	if towns, _ := KnownTowns(); len(towns) != 1 || towns[0].Root != townA {
+		t.Errorf("after unregister, KnownTowns() = %v", towns)
+	}
+}